@@ -0,0 +1,27 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// OSHardeningProfileFile is the name of the file inside the data
+	// dir that cloud-init writes once it has applied an OS hardening
+	// profile to the machine. It holds the name of the profile.
+	OSHardeningProfileFile = "os-hardening-profile"
+)
+
+// AppliedOSHardeningProfile returns the name of the OS hardening profile
+// applied to the machine when it was provisioned, or "" if none was.
+func AppliedOSHardeningProfile(dataDir string) string {
+	data, err := ioutil.ReadFile(filepath.Join(dataDir, OSHardeningProfileFile))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
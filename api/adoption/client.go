@@ -0,0 +1,57 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package adoption
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the adoption API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the adoption api.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Adoption")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Candidates returns the instances tagged as belonging to the model
+// that no machine in the model records.
+func (c *Client) Candidates() ([]params.AdoptionCandidate, error) {
+	var result params.AdoptionCandidatesResult
+	if err := c.facade.FacadeCall("Candidates", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Candidates, nil
+}
+
+// Verify checks that the specified instances can be adopted, without
+// changing anything. It returns one error result per machine.
+func (c *Client) Verify(machines ...params.AdoptMachine) ([]params.ErrorResult, error) {
+	return c.call("Verify", machines)
+}
+
+// Adopt recreates the machines that the specified instances were
+// started for. It returns one error result per machine.
+func (c *Client) Adopt(machines ...params.AdoptMachine) ([]params.ErrorResult, error) {
+	return c.call("Adopt", machines)
+}
+
+func (c *Client) call(method string, machines []params.AdoptMachine) ([]params.ErrorResult, error) {
+	args := params.AdoptMachines{Machines: machines}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall(method, args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if n := len(results.Results); n != len(machines) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(machines), n)
+	}
+	return results.Results, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package adoption_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/adoption"
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type AdoptionSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&AdoptionSuite{})
+
+var adoptMachine = params.AdoptMachine{
+	InstanceId: "i-3",
+	MachineTag: "machine-3",
+	Series:     "xenial",
+	Nonce:      "nonce",
+	Password:   "password",
+}
+
+func (s *AdoptionSuite) TestCandidates(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Adoption")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Candidates")
+			c.Check(a, gc.IsNil)
+			called = true
+
+			if results, ok := result.(*params.AdoptionCandidatesResult); ok {
+				results.Candidates = []params.AdoptionCandidate{{
+					InstanceId: "i-3",
+					MachineTag: "machine-3",
+				}}
+			}
+			return nil
+		})
+
+	client := adoption.NewClient(apiCaller)
+	candidates, err := client.Candidates()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(candidates, jc.DeepEquals, []params.AdoptionCandidate{{
+		InstanceId: "i-3",
+		MachineTag: "machine-3",
+	}})
+}
+
+func (s *AdoptionSuite) TestVerify(c *gc.C) {
+	s.testCall(c, "Verify", (*adoption.Client).Verify)
+}
+
+func (s *AdoptionSuite) TestAdopt(c *gc.C) {
+	s.testCall(c, "Adopt", (*adoption.Client).Adopt)
+}
+
+func (s *AdoptionSuite) testCall(
+	c *gc.C,
+	method string,
+	call func(*adoption.Client, ...params.AdoptMachine) ([]params.ErrorResult, error),
+) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Adoption")
+			c.Check(request, gc.Equals, method)
+			c.Check(a, jc.DeepEquals, params.AdoptMachines{
+				Machines: []params.AdoptMachine{adoptMachine},
+			})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{Error: &params.Error{Message: "bad"}}},
+			}
+			return nil
+		})
+	results, err := call(adoption.NewClient(apiCaller), adoptMachine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.ErrorResult{{Error: &params.Error{Message: "bad"}}})
+}
+
+func (s *AdoptionSuite) TestAdoptResultCountMismatch(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			return nil
+		})
+	client := adoption.NewClient(apiCaller)
+	_, err := client.Adopt(adoptMachine)
+	c.Assert(err, gc.ErrorMatches, `expected 1 result\(s\), got 0`)
+}
+
+func (s *AdoptionSuite) TestCandidatesError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			return errors.New("boom")
+		})
+	client := adoption.NewClient(apiCaller)
+	_, err := client.Candidates()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,46 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agentversionpins
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the agent version pins API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the agent version
+// pins api.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "AgentVersionPins")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// SetAgentVersionPins pins the agents of the machines, or of the
+// machines hosting the applications' units, with the supplied tags to
+// the agent version range, in the form "MIN..MAX". An empty range
+// removes the pins.
+func (c *Client) SetAgentVersionPins(versionRange string, tags ...names.Tag) error {
+	args := params.AgentVersionPins{
+		Pins: make([]params.AgentVersionPin, len(tags)),
+	}
+	for i, tag := range tags {
+		args.Pins[i] = params.AgentVersionPin{
+			Tag:   tag.String(),
+			Range: versionRange,
+		}
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetAgentVersionPins", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.Combine()
+}
@@ -0,0 +1,55 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agentversionpins_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/agentversionpins"
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type AgentVersionPinsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&AgentVersionPinsSuite{})
+
+func (s *AgentVersionPinsSuite) TestSetAgentVersionPins(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "AgentVersionPins")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "SetAgentVersionPins")
+			c.Check(a, jc.DeepEquals, params.AgentVersionPins{
+				Pins: []params.AgentVersionPin{
+					{Tag: "machine-0", Range: "2.3.1..2.3.4"},
+					{Tag: "application-mysql", Range: "2.3.1..2.3.4"},
+				},
+			})
+			called = true
+
+			if results, ok := result.(*params.ErrorResults); ok {
+				results.Results = []params.ErrorResult{
+					{},
+					{Error: &params.Error{Message: `application "mysql" not found`}},
+				}
+			}
+			return nil
+		})
+
+	client := agentversionpins.NewClient(apiCaller)
+	err := client.SetAgentVersionPins("2.3.1..2.3.4", names.NewMachineTag("0"), names.NewApplicationTag("mysql"))
+	c.Assert(called, jc.IsTrue)
+	c.Assert(err, gc.ErrorMatches, `application "mysql" not found`)
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/observer"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/correlation"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/jsoncodec"
@@ -63,6 +64,12 @@ type rpcConnection interface {
 	Close() error
 }
 
+// correlatedRPCConnection is implemented by rpc connections that can
+// send a correlation ID with a request.
+type correlatedRPCConnection interface {
+	CallCorrelated(req rpc.Request, correlationId string, params, response interface{}) error
+}
+
 // state is the internal implementation of the Connection interface.
 type state struct {
 	client rpcConnection
@@ -914,13 +921,24 @@ var apiCallRetryStrategy = retry.LimitTime(10*time.Second,
 // object id, and the specific RPC method. It marshalls the Arguments, and will
 // unmarshall the result into the response object that is supplied.
 func (s *state) APICall(facade string, version int, id, method string, args, response interface{}) error {
+	req := rpc.Request{
+		Type:    facade,
+		Version: version,
+		Id:      id,
+		Action:  method,
+	}
+	// Each call is given a correlation ID, shared by its retries, so
+	// that the controller's log lines and spans for it can be grouped
+	// with the actions and hooks it causes.
+	call := s.client.Call
+	if client, ok := s.client.(correlatedRPCConnection); ok {
+		correlationId := correlation.NewID()
+		call = func(req rpc.Request, params, response interface{}) error {
+			return client.CallCorrelated(req, correlationId, params, response)
+		}
+	}
 	for a := retry.Start(apiCallRetryStrategy, s.clock); a.Next(); {
-		err := s.client.Call(rpc.Request{
-			Type:    facade,
-			Version: version,
-			Id:      id,
-			Action:  method,
-		}, args, response)
+		err := call(req, args, response)
 		if params.ErrCode(err) != params.CodeRetry {
 			return errors.Trace(err)
 		}
@@ -31,6 +31,7 @@ import (
 	"github.com/juju/juju/apiserver/observer/fakeobserver"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/correlation"
 	jjtesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/pubsub/centralhub"
@@ -1037,6 +1038,31 @@ func (s *apiclientSuite) TestAPICallRetriesLimit(c *gc.C) {
 	})
 }
 
+func (s *apiclientSuite) TestAPICallSendsCorrelationId(c *gc.C) {
+	clock := &fakeClock{}
+	rpcConn := &fakeCorrelatedRPCConnection{
+		fakeRPCConnection: newRPCConnection(
+			&rpc.RequestError{Message: "hmm...", Code: params.CodeRetry},
+		),
+	}
+	conn := api.NewTestingState(api.TestingStateParams{
+		RPCConnection: rpcConn,
+		Clock:         clock,
+	})
+
+	err := conn.APICall("facade", 1, "id", "method", nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rpcConn.correlationIds, gc.HasLen, 2)
+	c.Assert(correlation.IsValidID(rpcConn.correlationIds[0]), jc.IsTrue)
+	// Retries are made for the same operation, so they share its ID.
+	c.Assert(rpcConn.correlationIds[1], gc.Equals, rpcConn.correlationIds[0])
+
+	err = conn.APICall("facade", 1, "id", "method", nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rpcConn.correlationIds, gc.HasLen, 3)
+	c.Assert(rpcConn.correlationIds[2], gc.Not(gc.Equals), rpcConn.correlationIds[0])
+}
+
 func (s *apiclientSuite) TestPing(c *gc.C) {
 	clock := &fakeClock{}
 	rpcConn := newRPCConnection()
@@ -1139,6 +1165,16 @@ func (f *fakeRPCConnection) Call(req rpc.Request, params, response interface{})
 	return f.stub.NextErr()
 }
 
+type fakeCorrelatedRPCConnection struct {
+	*fakeRPCConnection
+	correlationIds []string
+}
+
+func (f *fakeCorrelatedRPCConnection) CallCorrelated(req rpc.Request, correlationId string, params, response interface{}) error {
+	f.correlationIds = append(f.correlationIds, correlationId)
+	return f.Call(req, params, response)
+}
+
 type redirectAPI struct {
 	redirected       bool
 	modelUUID        string
@@ -473,6 +473,11 @@ type DestroyApplicationsParams struct {
 	// DestroyStorage controls whether or not storage attached
 	// to units of the applications will be destroyed.
 	DestroyStorage bool
+
+	// RetainStorage controls whether or not storage attached to
+	// units of the applications must be retained. Applications whose
+	// storage cannot all be detached are not destroyed.
+	RetainStorage bool
 }
 
 // DestroyApplications destroys the given applications.
@@ -493,11 +498,15 @@ func (c *Client) DestroyApplications(in DestroyApplicationsParams) ([]params.Des
 		argsV5.Applications = append(argsV5.Applications, params.DestroyApplicationParams{
 			ApplicationTag: names.NewApplicationTag(name).String(),
 			DestroyStorage: in.DestroyStorage,
+			RetainStorage:  in.RetainStorage,
 		})
 	}
 	if len(argsV5.Applications) == 0 {
 		return allResults, nil
 	}
+	if in.RetainStorage && c.BestAPIVersion() < 8 {
+		return nil, errors.New("this controller does not support --retain-storage")
+	}
 
 	args := interface{}(argsV5)
 	if c.BestAPIVersion() < 5 {
@@ -603,6 +612,66 @@ func (c *Client) SetConstraints(application string, constraints constraints.Valu
 	return c.facade.FacadeCall("SetConstraints", params, nil)
 }
 
+// Affinity holds the affinity policy of an application.
+type Affinity struct {
+	// NotWith holds the names of the applications whose units must
+	// never share a host machine with the application's units.
+	NotWith []string
+
+	// SameZoneAs holds the names of the applications whose
+	// availability zones the application's units are placed in,
+	// where possible.
+	SameZoneAs []string
+
+	// Violations holds, by unit name, the ways in which the placement
+	// of the application's units breaks affinity policy. It is not
+	// set by SetAffinity.
+	Violations map[string][]string
+}
+
+// GetAffinity returns the affinity policy of the given application.
+func (c *Client) GetAffinity(application string) (Affinity, error) {
+	if c.BestAPIVersion() < 6 {
+		return Affinity{}, errors.NotSupportedf("application affinity")
+	}
+	args := params.Entities{Entities: []params.Entity{{
+		Tag: names.NewApplicationTag(application).String(),
+	}}}
+	var results params.ApplicationAffinityResults
+	if err := c.facade.FacadeCall("GetAffinity", args, &results); err != nil {
+		return Affinity{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return Affinity{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return Affinity{}, result.Error
+	}
+	return Affinity{
+		NotWith:    result.Result.NotWith,
+		SameZoneAs: result.Result.SameZoneAs,
+		Violations: result.Result.Violations,
+	}, nil
+}
+
+// SetAffinity sets the affinity policy of the given application.
+func (c *Client) SetAffinity(application string, affinity Affinity) error {
+	if c.BestAPIVersion() < 6 {
+		return errors.NotSupportedf("application affinity")
+	}
+	args := params.SetApplicationsAffinity{Args: []params.ApplicationAffinity{{
+		ApplicationTag: names.NewApplicationTag(application).String(),
+		NotWith:        affinity.NotWith,
+		SameZoneAs:     affinity.SameZoneAs,
+	}}}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetAffinity", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
 // Expose changes the juju-managed firewall to expose any ports that
 // were also explicitly marked by units as open.
 func (c *Client) Expose(application string) error {
@@ -617,6 +686,36 @@ func (c *Client) Unexpose(application string) error {
 	return c.facade.FacadeCall("Unexpose", params, nil)
 }
 
+// ExposeEndpoints exposes the ports opened for the application's
+// endpoints to the spaces and CIDRs in the supplied expose settings,
+// keyed on endpoint name. The settings are merged into the
+// application's existing settings; the settings for the empty endpoint
+// name apply to all endpoints without settings of their own.
+func (c *Client) ExposeEndpoints(application string, exposedEndpoints map[string]params.ExposedEndpoint) error {
+	if c.BestAPIVersion() < 7 {
+		return errors.NotSupportedf("exposing application endpoints")
+	}
+	args := params.ApplicationExpose{
+		ApplicationName:  application,
+		ExposedEndpoints: exposedEndpoints,
+	}
+	return c.facade.FacadeCall("Expose", args, nil)
+}
+
+// UnexposeEndpoints removes the expose settings of the application's
+// named endpoints. The application is unexposed once no expose
+// settings remain.
+func (c *Client) UnexposeEndpoints(application string, endpoints []string) error {
+	if c.BestAPIVersion() < 7 {
+		return errors.NotSupportedf("unexposing application endpoints")
+	}
+	args := params.ApplicationUnexpose{
+		ApplicationName:  application,
+		ExposedEndpoints: endpoints,
+	}
+	return c.facade.FacadeCall("Unexpose", args, nil)
+}
+
 // Get returns the configuration for the named application.
 func (c *Client) Get(application string) (*params.ApplicationGetResults, error) {
 	var results params.ApplicationGetResults
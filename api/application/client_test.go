@@ -354,6 +354,39 @@ func (s *applicationSuite) TestDestroyApplications(c *gc.C) {
 	c.Assert(results, jc.DeepEquals, expectedResults)
 }
 
+func (s *applicationSuite) TestDestroyApplicationsRetainStorage(c *gc.C) {
+	caller := func(objType string, version int, id, request string, a, response interface{}) error {
+		c.Assert(request, gc.Equals, "DestroyApplication")
+		c.Assert(a, jc.DeepEquals, params.DestroyApplicationsParams{
+			Applications: []params.DestroyApplicationParams{
+				{ApplicationTag: "application-foo", RetainStorage: true},
+			},
+		})
+		out := response.(*params.DestroyApplicationResults)
+		*out = params.DestroyApplicationResults{[]params.DestroyApplicationResult{{}}}
+		return nil
+	}
+	client := application.NewClient(basetesting.BestVersionCaller{caller, 8})
+	results, err := client.DestroyApplications(application.DestroyApplicationsParams{
+		Applications:  []string{"foo"},
+		RetainStorage: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+}
+
+func (s *applicationSuite) TestDestroyApplicationsRetainStorageNotSupported(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
+		c.Fatalf("unexpected call to %s", request)
+		return nil
+	})
+	_, err := client.DestroyApplications(application.DestroyApplicationsParams{
+		Applications:  []string{"foo"},
+		RetainStorage: true,
+	})
+	c.Assert(err, gc.ErrorMatches, "this controller does not support --retain-storage")
+}
+
 func (s *applicationSuite) TestDestroyApplicationsV4(c *gc.C) {
 	expectedResults := []params.DestroyApplicationResult{{
 		Error: &params.Error{Message: "boo"},
@@ -944,3 +977,129 @@ func (s *applicationSuite) TestGetConstraintsAPIv4(c *gc.C) {
 		fooConstraints, barConstraints,
 	})
 }
+
+func (s *applicationSuite) TestGetAffinity(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, "GetAffinity")
+				c.Assert(a, jc.DeepEquals, params.Entities{
+					Entities: []params.Entity{{"application-foo"}},
+				})
+				result, ok := response.(*params.ApplicationAffinityResults)
+				c.Assert(ok, jc.IsTrue)
+				result.Results = []params.ApplicationAffinityResult{{
+					Result: &params.ApplicationAffinity{
+						ApplicationTag: "application-foo",
+						NotWith:        []string{"bar"},
+						SameZoneAs:     []string{"baz"},
+						Violations:     map[string][]string{"foo/0": {"zone a is not a zone of baz"}},
+					},
+				}}
+				return nil
+			},
+		),
+		BestVersion: 6,
+	})
+	affinity, err := client.GetAffinity("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(affinity, jc.DeepEquals, application.Affinity{
+		NotWith:    []string{"bar"},
+		SameZoneAs: []string{"baz"},
+		Violations: map[string][]string{"foo/0": {"zone a is not a zone of baz"}},
+	})
+}
+
+func (s *applicationSuite) TestSetAffinity(c *gc.C) {
+	var called bool
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				c.Assert(request, gc.Equals, "SetAffinity")
+				c.Assert(a, jc.DeepEquals, params.SetApplicationsAffinity{
+					Args: []params.ApplicationAffinity{{
+						ApplicationTag: "application-foo",
+						NotWith:        []string{"bar"},
+					}},
+				})
+				result, ok := response.(*params.ErrorResults)
+				c.Assert(ok, jc.IsTrue)
+				result.Results = []params.ErrorResult{{Error: &params.Error{Message: "boom"}}}
+				return nil
+			},
+		),
+		BestVersion: 6,
+	})
+	err := client.SetAffinity("foo", application.Affinity{NotWith: []string{"bar"}})
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *applicationSuite) TestAffinityNotSupported(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
+		c.Fatalf("unexpected call to %s", request)
+		return nil
+	})
+	_, err := client.GetAffinity("foo")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = client.SetAffinity("foo", application.Affinity{})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *applicationSuite) TestExposeEndpoints(c *gc.C) {
+	var called bool
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				c.Assert(request, gc.Equals, "Expose")
+				c.Assert(a, jc.DeepEquals, params.ApplicationExpose{
+					ApplicationName: "foo",
+					ExposedEndpoints: map[string]params.ExposedEndpoint{
+						"admin": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+					},
+				})
+				return nil
+			},
+		),
+		BestVersion: 7,
+	})
+	err := client.ExposeEndpoints("foo", map[string]params.ExposedEndpoint{
+		"admin": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *applicationSuite) TestUnexposeEndpoints(c *gc.C) {
+	var called bool
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				c.Assert(request, gc.Equals, "Unexpose")
+				c.Assert(a, jc.DeepEquals, params.ApplicationUnexpose{
+					ApplicationName:  "foo",
+					ExposedEndpoints: []string{"admin"},
+				})
+				return nil
+			},
+		),
+		BestVersion: 7,
+	})
+	err := client.UnexposeEndpoints("foo", []string{"admin"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *applicationSuite) TestExposeEndpointsNotSupported(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
+		c.Fatalf("unexpected call to %s", request)
+		return nil
+	})
+	err := client.ExposeEndpoints("foo", map[string]params.ExposedEndpoint{"admin": {}})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = client.UnexposeEndpoints("foo", []string{"admin"})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
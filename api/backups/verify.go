@@ -0,0 +1,23 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Verify implements the API method.
+func (c *Client) Verify(id string) (*params.BackupsVerifyResult, error) {
+	if c.BestAPIVersion() < 2 {
+		return nil, errors.NotSupportedf("verifying backups")
+	}
+	var result params.BackupsVerifyResult
+	args := params.BackupsVerifyArgs{ID: id}
+	if err := c.facade.FacadeCall("Verify", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,41 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package chatsummary
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the chat summary API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the chat summary api.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "ChatSummary")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Summary returns a compact summary of the model, suitable for posting
+// as a chat message.
+func (c *Client) Summary() (*params.ChatSummary, error) {
+	var result params.ChatSummary
+	if err := c.facade.FacadeCall("Summary", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &result, nil
+}
+
+// PushSummary asks the controller to post the summary of the model to
+// the given chat webhook, which must be one of those configured in the
+// controller's chat-webhook-urls. It requires admin access to the model.
+func (c *Client) PushSummary(webhookURL string) error {
+	args := params.ChatSummaryPushArgs{WebhookURL: webhookURL}
+	return errors.Trace(c.facade.FacadeCall("PushSummary", args, nil))
+}
@@ -0,0 +1,81 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package chatsummary_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/chatsummary"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type ChatSummarySuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&ChatSummarySuite{})
+
+func (s *ChatSummarySuite) TestSummary(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "ChatSummary")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Summary")
+			c.Check(a, gc.IsNil)
+			called = true
+
+			if summary, ok := result.(*params.ChatSummary); ok {
+				*summary = params.ChatSummary{
+					Model:  "prod",
+					Health: "healthy",
+					Units:  3,
+					Text:   "prod (healthy): 0 machines, 0 applications, 3 units",
+				}
+			}
+			return nil
+		})
+
+	client := chatsummary.NewClient(apiCaller)
+	summary, err := client.Summary()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(summary, jc.DeepEquals, &params.ChatSummary{
+		Model:  "prod",
+		Health: "healthy",
+		Units:  3,
+		Text:   "prod (healthy): 0 machines, 0 applications, 3 units",
+	})
+}
+
+func (s *ChatSummarySuite) TestPushSummary(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "ChatSummary")
+			c.Check(request, gc.Equals, "PushSummary")
+			c.Check(a, jc.DeepEquals, params.ChatSummaryPushArgs{
+				WebhookURL: "https://chat.example.com/hooks/abc",
+			})
+			called = true
+			return errors.New("boom")
+		})
+
+	client := chatsummary.NewClient(apiCaller)
+	err := client.PushSummary("https://chat.example.com/hooks/abc")
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(called, jc.IsTrue)
+}
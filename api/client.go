@@ -58,7 +58,24 @@ func (c *Client) CACert() (string, error) {
 // <kind:combined|agent|workload|machine|machineinstance|container|containerinstance> status
 // for <name> unit
 func (c *Client) StatusHistory(kind status.HistoryKind, tag names.Tag, filter status.StatusHistoryFilter) (status.History, error) {
-	var results params.StatusHistoryResults
+	history, _, err := c.statusHistory(statusHistoryRequest(kind, tag, filter))
+	return history, err
+}
+
+// StatusHistoryPage retrieves a page of at most pageSize results of
+// the status history StatusHistory would, starting with the page
+// identified by token, or the most recent if token is empty. It
+// returns the token of the following page, which is empty if there
+// are no more results. Controllers that cannot page status history
+// return it all at once.
+func (c *Client) StatusHistoryPage(kind status.HistoryKind, tag names.Tag, filter status.StatusHistoryFilter, pageSize int, token string) (status.History, string, error) {
+	args := statusHistoryRequest(kind, tag, filter)
+	args.PageSize = pageSize
+	args.Token = token
+	return c.statusHistory(args)
+}
+
+func statusHistoryRequest(kind status.HistoryKind, tag names.Tag, filter status.StatusHistoryFilter) params.StatusHistoryRequest {
 	args := params.StatusHistoryRequest{
 		Kind: string(kind),
 		Filter: params.StatusHistoryFilter{
@@ -66,23 +83,39 @@ func (c *Client) StatusHistory(kind status.HistoryKind, tag names.Tag, filter st
 			Date:    filter.FromDate,
 			Delta:   filter.Delta,
 			Exclude: filter.Exclude.Values(),
+
+			IncludeStatus: filter.IncludeStatus.Values(),
+			ExcludeStatus: filter.ExcludeStatus.Values(),
+			MessageRegex:  filter.MessageRegex,
 		},
 		Tag: tag.String(),
 	}
+	if filter.Squash != nil {
+		args.Filter.Squash = &params.StatusHistorySquash{
+			Enabled:    true,
+			CycleSizes: filter.Squash.CycleSizes,
+			MaxLength:  filter.Squash.MaxLength,
+		}
+	}
+	return args
+}
+
+func (c *Client) statusHistory(args params.StatusHistoryRequest) (status.History, string, error) {
+	var results params.StatusHistoryResults
 	bulkArgs := params.StatusHistoryRequests{Requests: []params.StatusHistoryRequest{args}}
 	err := c.facade.FacadeCall("StatusHistory", bulkArgs, &results)
 	if err != nil {
-		return status.History{}, errors.Trace(err)
+		return status.History{}, "", errors.Trace(err)
 	}
 	if len(results.Results) != 1 {
-		return status.History{}, errors.Errorf("expected 1 result got %d", len(results.Results))
+		return status.History{}, "", errors.Errorf("expected 1 result got %d", len(results.Results))
 	}
 	if results.Results[0].Error != nil {
-		return status.History{}, errors.Annotatef(results.Results[0].Error, "while processing the request")
+		return status.History{}, "", errors.Annotatef(results.Results[0].Error, "while processing the request")
 	}
 	history := make(status.History, len(results.Results[0].History.Statuses))
 	if results.Results[0].History.Error != nil {
-		return status.History{}, results.Results[0].History.Error
+		return status.History{}, "", results.Results[0].History.Error
 	}
 	for i, h := range results.Results[0].History.Statuses {
 		history[i] = status.DetailedStatus{
@@ -101,7 +134,7 @@ func (c *Client) StatusHistory(kind status.HistoryKind, tag names.Tag, filter st
 			logger.Errorf("history returned an unknown status kind %q", h.Kind)
 		}
 	}
-	return history, nil
+	return history, results.Results[0].NextToken, nil
 }
 
 // Resolved clears errors on a unit.
@@ -258,6 +291,43 @@ func (c *Client) WatchAll() (*AllWatcher, error) {
 	return NewAllWatcher(c.st, &info.AllWatcherId), nil
 }
 
+// WatchStatus returns a StatusWatcher that reports the changes to the
+// model's full status, filtered by the supplied patterns, as diffs.
+func (c *Client) WatchStatus(patterns []string) (*StatusWatcher, error) {
+	if c.st.BestFacadeVersion("StatusWatcher") < 1 {
+		return nil, errors.NotSupportedf("watching status with this controller")
+	}
+	var result params.StatusWatcherId
+	p := params.StatusParams{Patterns: patterns}
+	if err := c.facade.FacadeCall("WatchStatus", p, &result); err != nil {
+		return nil, err
+	}
+	return newStatusWatcher(c.st, result.StatusWatcherId), nil
+}
+
+// WatchStatusHistory returns a StatusHistoryWatcher that reports the
+// entries added to the status history of the given kind for the given
+// entity, as they are recorded.
+func (c *Client) WatchStatusHistory(kind status.HistoryKind, tag names.Tag) (*StatusHistoryWatcher, error) {
+	if c.st.BestFacadeVersion("StatusHistoryWatcher") < 1 {
+		return nil, errors.NotSupportedf("watching status history with this controller")
+	}
+	var results params.StatusHistoryWatchResults
+	args := params.StatusHistoryRequests{
+		Requests: []params.StatusHistoryRequest{{Kind: string(kind), Tag: tag.String()}},
+	}
+	if err := c.facade.FacadeCall("WatchStatusHistory", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, err
+	}
+	return newStatusHistoryWatcher(c.st, results.Results[0].StatusHistoryWatcherId), nil
+}
+
 // Close closes the Client's underlying State connection
 // Client is unique among the api.State facades in closing its own State
 // connection, but it is conventional to use a Client object without any access
@@ -0,0 +1,79 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package configprofiles
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the config profiles API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the config profiles api.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "ConfigProfiles")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// List returns all the config profiles stored on the controller.
+func (c *Client) List() ([]params.ConfigProfile, error) {
+	var result params.ConfigProfiles
+	if err := c.facade.FacadeCall("List", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Profiles, nil
+}
+
+// Get returns the named config profile.
+func (c *Client) Get(name string) (params.ConfigProfile, error) {
+	args := params.ConfigProfileNames{Names: []string{name}}
+	var results params.ConfigProfileResults
+	if err := c.facade.FacadeCall("Get", args, &results); err != nil {
+		return params.ConfigProfile{}, errors.Trace(err)
+	}
+	if n := len(results.Results); n != 1 {
+		return params.ConfigProfile{}, errors.Errorf("expected 1 result, got %d", n)
+	}
+	if err := results.Results[0].Error; err != nil {
+		return params.ConfigProfile{}, err
+	}
+	return *results.Results[0].Result, nil
+}
+
+// Add stores a new config profile on the controller.
+func (c *Client) Add(name string, config map[string]interface{}) error {
+	return c.set("Add", name, config)
+}
+
+// Update replaces the values set by an existing config profile.
+func (c *Client) Update(name string, config map[string]interface{}) error {
+	return c.set("Update", name, config)
+}
+
+func (c *Client) set(method, name string, config map[string]interface{}) error {
+	args := params.ConfigProfiles{
+		Profiles: []params.ConfigProfile{{Name: name, Config: config}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall(method, args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// Remove removes the named config profile from the controller.
+func (c *Client) Remove(name string) error {
+	args := params.ConfigProfileNames{Names: []string{name}}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("Remove", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
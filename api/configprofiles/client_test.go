@@ -0,0 +1,139 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package configprofiles_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/configprofiles"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type ConfigProfilesSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&ConfigProfilesSuite{})
+
+var devProfile = params.ConfigProfile{
+	Name:   "dev",
+	Config: map[string]interface{}{"development": true},
+}
+
+func (s *ConfigProfilesSuite) TestList(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "ConfigProfiles")
+			c.Check(request, gc.Equals, "List")
+			c.Check(a, gc.IsNil)
+			*(result.(*params.ConfigProfiles)) = params.ConfigProfiles{
+				Profiles: []params.ConfigProfile{devProfile},
+			}
+			return nil
+		})
+	client := configprofiles.NewClient(apiCaller)
+	profiles, err := client.List()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profiles, jc.DeepEquals, []params.ConfigProfile{devProfile})
+}
+
+func (s *ConfigProfilesSuite) TestGet(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(request, gc.Equals, "Get")
+			c.Check(a, jc.DeepEquals, params.ConfigProfileNames{Names: []string{"dev"}})
+			*(result.(*params.ConfigProfileResults)) = params.ConfigProfileResults{
+				Results: []params.ConfigProfileResult{{Result: &devProfile}},
+			}
+			return nil
+		})
+	client := configprofiles.NewClient(apiCaller)
+	profile, err := client.Get("dev")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profile, jc.DeepEquals, devProfile)
+}
+
+func (s *ConfigProfilesSuite) TestGetError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			*(result.(*params.ConfigProfileResults)) = params.ConfigProfileResults{
+				Results: []params.ConfigProfileResult{{
+					Error: &params.Error{Message: "boom"},
+				}},
+			}
+			return nil
+		})
+	client := configprofiles.NewClient(apiCaller)
+	_, err := client.Get("dev")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ConfigProfilesSuite) TestAdd(c *gc.C) {
+	s.testSet(c, "Add", (*configprofiles.Client).Add)
+}
+
+func (s *ConfigProfilesSuite) TestUpdate(c *gc.C) {
+	s.testSet(c, "Update", (*configprofiles.Client).Update)
+}
+
+func (s *ConfigProfilesSuite) testSet(
+	c *gc.C,
+	method string,
+	setFunc func(*configprofiles.Client, string, map[string]interface{}) error,
+) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(request, gc.Equals, method)
+			c.Check(a, jc.DeepEquals, params.ConfigProfiles{
+				Profiles: []params.ConfigProfile{devProfile},
+			})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{
+					Error: &params.Error{Message: "boom"},
+				}},
+			}
+			return nil
+		})
+	client := configprofiles.NewClient(apiCaller)
+	err := setFunc(client, "dev", map[string]interface{}{"development": true})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ConfigProfilesSuite) TestRemove(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(request, gc.Equals, "Remove")
+			c.Check(a, jc.DeepEquals, params.ConfigProfileNames{Names: []string{"dev"}})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		})
+	client := configprofiles.NewClient(apiCaller)
+	err := client.Remove("dev")
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -0,0 +1,53 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controllerfirewaller
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/watcher"
+)
+
+// Client provides access to the ControllerFirewaller API facade.
+type Client struct {
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new ControllerFirewaller client.
+func NewClient(caller base.APICaller) *Client {
+	return &Client{
+		facade: base.NewFacadeCaller(caller, "ControllerFirewaller"),
+	}
+}
+
+// ControllerIngressRules returns the UUID of the controller, and the
+// rules governing ingress to its instances.
+func (c *Client) ControllerIngressRules() (string, []network.IngressRule, error) {
+	var result params.ControllerIngressRulesResult
+	if err := c.facade.FacadeCall("ControllerIngressRules", nil, &result); err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	rules := make([]network.IngressRule, len(result.Rules))
+	for i, rule := range result.Rules {
+		rules[i] = rule.NetworkIngressRule()
+	}
+	return result.ControllerUUID, rules, nil
+}
+
+// WatchControllerIngressRules returns a watcher that notifies of
+// changes that may affect the controller ingress rules.
+func (c *Client) WatchControllerIngressRules() (watcher.NotifyWatcher, error) {
+	var result params.NotifyWatchResult
+	if err := c.facade.FacadeCall("WatchControllerIngressRules", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewNotifyWatcher(c.facade.RawAPICaller(), result), nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controllerfirewaller_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/controllerfirewaller"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type ControllerFirewallerSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&ControllerFirewallerSuite{})
+
+func (s *ControllerFirewallerSuite) TestControllerIngressRules(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "ControllerFirewaller")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "ControllerIngressRules")
+			c.Check(a, gc.IsNil)
+			c.Assert(result, gc.FitsTypeOf, &params.ControllerIngressRulesResult{})
+			*(result.(*params.ControllerIngressRulesResult)) = params.ControllerIngressRulesResult{
+				ControllerUUID: "controller-uuid",
+				Rules: []params.IngressRule{{
+					PortRange: params.PortRange{FromPort: 17070, ToPort: 17070, Protocol: "tcp"},
+				}, {
+					PortRange:   params.PortRange{FromPort: 37017, ToPort: 37017, Protocol: "tcp"},
+					SourceCIDRs: []string{"10.0.0.1/32"},
+				}},
+			}
+			return nil
+		})
+	client := controllerfirewaller.NewClient(apiCaller)
+	controllerUUID, rules, err := client.ControllerIngressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(controllerUUID, gc.Equals, "controller-uuid")
+	c.Assert(rules, jc.DeepEquals, []network.IngressRule{
+		network.MustNewIngressRule("tcp", 17070, 17070),
+		network.MustNewIngressRule("tcp", 37017, 37017, "10.0.0.1/32"),
+	})
+}
+
+func (s *ControllerFirewallerSuite) TestControllerIngressRulesError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			return errors.New("boom")
+		})
+	client := controllerfirewaller.NewClient(apiCaller)
+	_, _, err := client.ControllerIngressRules()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ControllerFirewallerSuite) TestWatchControllerIngressRulesError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "ControllerFirewaller")
+			c.Check(request, gc.Equals, "WatchControllerIngressRules")
+			*(result.(*params.NotifyWatchResult)) = params.NotifyWatchResult{
+				Error: &params.Error{Code: params.CodeNotSupported, Message: "not supported"},
+			}
+			return nil
+		})
+	client := controllerfirewaller.NewClient(apiCaller)
+	_, err := client.WatchControllerIngressRules()
+	c.Assert(err, jc.Satisfies, params.IsCodeNotSupported)
+}
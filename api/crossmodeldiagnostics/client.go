@@ -0,0 +1,44 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package crossmodeldiagnostics
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the cross model diagnostics API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the cross model
+// diagnostics api.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "CrossModelDiagnostics")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// RelationDiagnostics returns the state of the cross model relation
+// with the given id in each of the models taking part in it, and the
+// result of connecting to the controller hosting the offer.
+func (c *Client) RelationDiagnostics(relationId int) (*params.CrossModelRelationDiagnostics, error) {
+	args := params.CrossModelRelationDiagnosticsArgs{
+		RelationIds: []int{relationId},
+	}
+	var results params.CrossModelRelationDiagnosticsResults
+	if err := c.facade.FacadeCall("RelationDiagnostics", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, err
+	}
+	return results.Results[0].Result, nil
+}
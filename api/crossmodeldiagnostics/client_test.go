@@ -0,0 +1,96 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package crossmodeldiagnostics_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/crossmodeldiagnostics"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type DiagnosticsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&DiagnosticsSuite{})
+
+func (s *DiagnosticsSuite) TestRelationDiagnostics(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "CrossModelDiagnostics")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "RelationDiagnostics")
+			c.Check(a, jc.DeepEquals, params.CrossModelRelationDiagnosticsArgs{
+				RelationIds: []int{3},
+			})
+			called = true
+
+			if results, ok := result.(*params.CrossModelRelationDiagnosticsResults); ok {
+				results.Results = []params.CrossModelRelationDiagnosticsResult{{
+					Result: &params.CrossModelRelationDiagnostics{
+						RelationId:        3,
+						RelationKey:       "wordpress:db mysql:server",
+						RemoteApplication: "mysql",
+						Role:              "consumer",
+					},
+				}}
+			}
+			return nil
+		})
+
+	client := crossmodeldiagnostics.NewClient(apiCaller)
+	result, err := client.RelationDiagnostics(3)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(result, jc.DeepEquals, &params.CrossModelRelationDiagnostics{
+		RelationId:        3,
+		RelationKey:       "wordpress:db mysql:server",
+		RemoteApplication: "mysql",
+		Role:              "consumer",
+	})
+}
+
+func (s *DiagnosticsSuite) TestRelationDiagnosticsResultError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			if results, ok := result.(*params.CrossModelRelationDiagnosticsResults); ok {
+				results.Results = []params.CrossModelRelationDiagnosticsResult{{
+					Error: &params.Error{Code: params.CodeNotFound, Message: "relation 3 not found"},
+				}}
+			}
+			return nil
+		})
+	client := crossmodeldiagnostics.NewClient(apiCaller)
+	_, err := client.RelationDiagnostics(3)
+	c.Assert(err, gc.ErrorMatches, "relation 3 not found")
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func (s *DiagnosticsSuite) TestRelationDiagnosticsError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			return errors.New("boom")
+		})
+	client := crossmodeldiagnostics.NewClient(apiCaller)
+	_, err := client.RelationDiagnostics(3)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,62 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ephemeralreaper
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/watcher"
+)
+
+const apiName = "EphemeralReaper"
+
+// Facade provides access to the EphemeralReaper API.
+type Facade struct {
+	caller base.FacadeCaller
+}
+
+// NewFacade returns a new EphemeralReaper Facade.
+func NewFacade(caller base.APICaller) *Facade {
+	return &Facade{caller: base.NewFacadeCaller(caller, apiName)}
+}
+
+// WatchModel returns a NotifyWatcher that notifies of changes to the
+// model, including changes to its expiry.
+func (f *Facade) WatchModel() (watcher.NotifyWatcher, error) {
+	var result params.NotifyWatchResult
+	if err := f.caller.FacadeCall("WatchModel", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewNotifyWatcher(f.caller.RawAPICaller(), result), nil
+}
+
+// ModelExpiry returns the time after which the model is destroyed, and
+// whether the model is ephemeral at all.
+func (f *Facade) ModelExpiry() (time.Time, bool, error) {
+	var result params.ModelExpiryResult
+	if err := f.caller.FacadeCall("ModelExpiry", nil, &result); err != nil {
+		return time.Time{}, false, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return time.Time{}, false, result.Error
+	}
+	if result.Expiry == nil {
+		return time.Time{}, false, nil
+	}
+	return *result.Expiry, true, nil
+}
+
+// DestroyModel destroys the expired ephemeral model, along with its
+// storage.
+func (f *Facade) DestroyModel() error {
+	return errors.Trace(f.caller.FacadeCall("DestroyModel", nil, nil))
+}
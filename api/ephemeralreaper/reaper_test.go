@@ -0,0 +1,76 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ephemeralreaper_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/ephemeralreaper"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type ReaperSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&ReaperSuite{})
+
+func (s *ReaperSuite) TestDestroyModel(c *gc.C) {
+	var stub testing.Stub
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		stub.AddCall(objType+"."+request, arg)
+		return stub.NextErr()
+	})
+	err := ephemeralreaper.NewFacade(apiCaller).DestroyModel()
+	c.Assert(err, jc.ErrorIsNil)
+	stub.CheckCalls(c, []testing.StubCall{{"EphemeralReaper.DestroyModel", []interface{}{nil}}})
+}
+
+func (s *ReaperSuite) TestDestroyModelError(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("boom")
+	})
+	err := ephemeralreaper.NewFacade(apiCaller).DestroyModel()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ReaperSuite) TestModelExpiry(c *gc.C) {
+	expiry := time.Date(2018, 4, 1, 12, 0, 0, 0, time.UTC)
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "EphemeralReaper")
+		c.Check(request, gc.Equals, "ModelExpiry")
+		*result.(*params.ModelExpiryResult) = params.ModelExpiryResult{Expiry: &expiry}
+		return nil
+	})
+	got, ok, err := ephemeralreaper.NewFacade(apiCaller).ModelExpiry()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(got, gc.Equals, expiry)
+}
+
+func (s *ReaperSuite) TestModelExpiryNotEphemeral(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return nil
+	})
+	_, ok, err := ephemeralreaper.NewFacade(apiCaller).ModelExpiry()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *ReaperSuite) TestModelExpiryError(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*result.(*params.ModelExpiryResult) = params.ModelExpiryResult{
+			Error: &params.Error{Message: "boom"},
+		}
+		return nil
+	})
+	_, _, err := ephemeralreaper.NewFacade(apiCaller).ModelExpiry()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -13,32 +13,40 @@ package api
 var facadeVersions = map[string]int{
 	"Action":                       2,
 	"ActionPruner":                 1,
+	"Adoption":                     1,
 	"Agent":                        2,
 	"AgentTools":                   1,
+	"AgentVersionPins":             1,
 	"AllModelWatcher":              2,
 	"AllWatcher":                   1,
 	"Annotations":                  2,
-	"Application":                  5,
+	"Application":                  8,
 	"ApplicationOffers":            1,
 	"ApplicationScaler":            1,
-	"Backups":                      1,
+	"Backups":                      2,
 	"Block":                        2,
 	"Bundle":                       1,
 	"CharmRevisionUpdater":         2,
 	"Charms":                       2,
+	"ChatSummary":                  1,
 	"Cleaner":                      2,
 	"Client":                       1,
 	"Cloud":                        2,
+	"ConfigProfiles":               1,
 	"Controller":                   4,
+	"ControllerFirewaller":         1,
 	"CrossController":              1,
+	"CrossModelDiagnostics":        1,
 	"CrossModelRelations":          1,
 	"Deployer":                     1,
 	"DiskManager":                  2,
 	"EntityWatcher":                2,
+	"EphemeralReaper":              1,
 	"ExternalControllerUpdater":    1,
 	"FanConfigurer":                1,
+	"FaultInjection":               1,
 	"FilesystemAttachmentsWatcher": 2,
-	"Firewaller":                   4,
+	"Firewaller":                   6,
 	"FirewallRules":                1,
 	"HighAvailability":             2,
 	"HostKeyReporter":              1,
@@ -46,14 +54,17 @@ var facadeVersions = map[string]int{
 	"ImageMetadata":                3,
 	"ImageMetadataManager":         1,
 	"InstancePoller":               3,
-	"KeyManager":                   1,
+	"Inventory":                    1,
+	"KeyManager":                   2,
 	"KeyUpdater":                   1,
-	"LeadershipService":            2,
+	"LeadershipReport":             1,
+	"LeadershipService":            3,
 	"LifeFlag":                     1,
 	"LogForwarding":                1,
 	"Logger":                       1,
 	"MachineActions":               1,
-	"MachineManager":               4,
+	"MachineManager":               6,
+	"MachinePool":                  1,
 	"MachineUndertaker":            1,
 	"Machiner":                     1,
 	"MeterStatus":                  1,
@@ -65,17 +76,22 @@ var facadeVersions = map[string]int{
 	"MigrationMinion":              1,
 	"MigrationStatusWatcher":       1,
 	"MigrationTarget":              1,
-	"ModelConfig":                  1,
-	"ModelManager":                 4,
+	"ModelConfig":                  5,
+	"ModelManager":                 7,
 	"ModelUpgrader":                1,
 	"NotifyWatcher":                1,
+	"OfferCatalog":                 1,
 	"OfferStatusWatcher":           1,
+	"OperationsTimeline":           1,
+	"OrphanedResources":            1,
+	"PackageUpdateReporter":        1,
 	"Payloads":                     1,
 	"PayloadsHookContext":          1,
 	"Pinger":                       1,
-	"Provisioner":                  5,
+	"Provisioner":                  6,
 	"ProxyUpdater":                 1,
 	"Reboot":                       2,
+	"RelationData":                 1,
 	"RelationStatusWatcher":        1,
 	"RelationUnitsWatcher":         1,
 	"RemoteRelations":              1,
@@ -85,17 +101,24 @@ var facadeVersions = map[string]int{
 	"RetryStrategy":                1,
 	"Singular":                     2,
 	"Spaces":                       3,
-	"SSHClient":                    2,
+	"SpaceTopology":                1,
+	"SSHClient":                    4,
+	"SSHKeyImporter":               1,
 	"StatusHistory":                2,
+	"StatusHistoryWatcher":         1,
+	"StatusWatcher":                1,
 	"Storage":                      4,
 	"StorageProvisioner":           4,
 	"StringsWatcher":               1,
 	"Subnets":                      2,
+	"Tracing":                      1,
 	"Undertaker":                   1,
 	"UnitAssigner":                 1,
-	"Uniter":                       7,
-	"Upgrader":                     1,
-	"UserManager":                  2,
+	"UnitMover":                    1,
+	"UnitMoves":                    1,
+	"Uniter":                       9,
+	"Upgrader":                     2,
+	"UserManager":                  5,
 	"VolumeAttachmentsWatcher":     2,
 }
 
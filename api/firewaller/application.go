@@ -75,3 +75,63 @@ func (s *Application) IsExposed() (bool, error) {
 	}
 	return result.Result, nil
 }
+
+// ExposedEndpointCIDRs returns the CIDRs that may access the ports
+// opened for each exposed endpoint of the application, keyed on the
+// endpoint name. CIDRs keyed on the empty endpoint name apply to every
+// endpoint without CIDRs of its own. The result is empty if the
+// application is not exposed.
+func (s *Application) ExposedEndpointCIDRs() (map[string][]string, error) {
+	if s.st.BestAPIVersion() < 5 {
+		// Older controllers only expose applications to everyone.
+		exposed, err := s.IsExposed()
+		if err != nil || !exposed {
+			return nil, err
+		}
+		return map[string][]string{"": {"0.0.0.0/0"}}, nil
+	}
+	var results params.ExposeInfoResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.tag.String()}},
+	}
+	err := s.st.facade.FacadeCall("GetExposeInfo", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if !result.Exposed {
+		return nil, nil
+	}
+	return result.ExposedEndpointCIDRs, nil
+}
+
+// Hostname returns the hostname at which the application is published
+// in DNS while it is exposed, which is empty if it has none.
+func (s *Application) Hostname() (string, error) {
+	if s.st.BestAPIVersion() < 6 {
+		// Older controllers do not report hostnames.
+		return "", nil
+	}
+	var results params.StringResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.tag.String()}},
+	}
+	err := s.st.facade.FacadeCall("GetHostnames", args, &results)
+	if err != nil {
+		return "", err
+	}
+	if len(results.Results) != 1 {
+		return "", fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Result, nil
+}
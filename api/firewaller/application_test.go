@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/juju/api/firewaller"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/watcher/watchertest"
 )
 
@@ -91,3 +92,35 @@ func (s *applicationSuite) TestIsExposed(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(isExposed, jc.IsFalse)
 }
+
+func (s *applicationSuite) TestExposedEndpointCIDRs(c *gc.C) {
+	cidrs, err := s.apiApplication.ExposedEndpointCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, gc.HasLen, 0)
+
+	err = s.application.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"url": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	cidrs, err = s.apiApplication.ExposedEndpointCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, jc.DeepEquals, map[string][]string{
+		"url": {"10.0.0.0/8"},
+	})
+}
+
+func (s *applicationSuite) TestHostname(c *gc.C) {
+	hostname, err := s.apiApplication.Hostname()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hostname, gc.Equals, "")
+
+	err = s.IAASModel.SetAnnotations(s.application, map[string]string{
+		"hostname": "blog.example.com",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	hostname, err = s.apiApplication.Hostname()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hostname, gc.Equals, "blog.example.com")
+}
@@ -100,6 +100,26 @@ func (c *Client) WatchModelMachines() (watcher.StringsWatcher, error) {
 	return w, nil
 }
 
+// WatchAnnotations returns a NotifyWatcher that notifies of changes
+// to the annotations of the entities in the current model. The
+// returned error satisfies errors.IsNotSupported if the controller
+// does not support watching annotations.
+func (c *Client) WatchAnnotations() (watcher.NotifyWatcher, error) {
+	if c.BestAPIVersion() < 6 {
+		return nil, errors.NotSupportedf("watching annotations")
+	}
+	var result params.NotifyWatchResult
+	err := c.facade.FacadeCall("WatchAnnotations", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.Error; err != nil {
+		return nil, result.Error
+	}
+	w := apiwatcher.NewNotifyWatcher(c.facade.RawAPICaller(), result)
+	return w, nil
+}
+
 // WatchOpenedPorts returns a StringsWatcher that notifies of
 // changes to the opened ports for the current model.
 func (c *Client) WatchOpenedPorts() (watcher.StringsWatcher, error) {
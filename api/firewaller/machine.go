@@ -108,9 +108,31 @@ func (m *Machine) ActiveSubnets() ([]names.SubnetTag, error) {
 	return tags, nil
 }
 
+// PortRangeOwner identifies the unit that opened a port range, and the
+// endpoint the range was opened for, if any.
+type PortRangeOwner struct {
+	Unit     names.UnitTag
+	Endpoint string
+}
+
 // OpenedPorts returns a map of network.PortRange to unit tag for all opened
 // port ranges on the machine for the subnet matching given subnetTag.
 func (m *Machine) OpenedPorts(subnetTag names.SubnetTag) (map[network.PortRange]names.UnitTag, error) {
+	ranges, err := m.OpenedPortRanges(subnetTag)
+	if err != nil {
+		return nil, err
+	}
+	endResult := make(map[network.PortRange]names.UnitTag, len(ranges))
+	for portRange, owner := range ranges {
+		endResult[portRange] = owner.Unit
+	}
+	return endResult, nil
+}
+
+// OpenedPortRanges returns a map of network.PortRange to the unit and
+// endpoint owning each opened port range on the machine for the subnet
+// matching given subnetTag.
+func (m *Machine) OpenedPortRanges(subnetTag names.SubnetTag) (map[network.PortRange]PortRangeOwner, error) {
 	var results params.MachinePortsResults
 	var subnetTagAsString string
 	if subnetTag.Id() != "" {
@@ -133,13 +155,16 @@ func (m *Machine) OpenedPorts(subnetTag names.SubnetTag) (map[network.PortRange]
 		return nil, result.Error
 	}
 	// Convert string tags to names.UnitTag before returning.
-	endResult := make(map[network.PortRange]names.UnitTag)
+	endResult := make(map[network.PortRange]PortRangeOwner)
 	for _, ports := range result.Ports {
 		unitTag, err := names.ParseUnitTag(ports.UnitTag)
 		if err != nil {
 			return nil, err
 		}
-		endResult[ports.PortRange.NetworkPortRange()] = unitTag
+		endResult[ports.PortRange.NetworkPortRange()] = PortRangeOwner{
+			Unit:     unitTag,
+			Endpoint: ports.Endpoint,
+		}
 	}
 	return endResult, nil
 }
@@ -0,0 +1,36 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package inventory
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the inventory API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the inventory api.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Inventory")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Export returns the inventory of the controller. If sinceGeneration
+// is non-zero, only the entities added, changed or removed after that
+// generation are included; the generation of the returned inventory
+// may be passed to the next call.
+func (c *Client) Export(sinceGeneration int64) (*params.Inventory, error) {
+	args := params.InventoryArgs{SinceGeneration: sinceGeneration}
+	var result params.Inventory
+	if err := c.facade.FacadeCall("Export", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &result, nil
+}
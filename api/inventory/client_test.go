@@ -0,0 +1,83 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package inventory_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/inventory"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type InventorySuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&InventorySuite{})
+
+func (s *InventorySuite) TestExport(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Inventory")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Export")
+			c.Check(a, jc.DeepEquals, params.InventoryArgs{SinceGeneration: 2})
+			called = true
+
+			if inventory, ok := result.(*params.Inventory); ok {
+				*inventory = params.Inventory{
+					SchemaVersion:   params.InventorySchemaVersion,
+					ControllerUUID:  testing.ControllerTag.Id(),
+					Generation:      3,
+					SinceGeneration: 2,
+					Models: []params.InventoryModel{{
+						UUID:       testing.ModelTag.Id(),
+						Name:       "testmodel",
+						Generation: 3,
+					}},
+				}
+			}
+			return nil
+		})
+
+	client := inventory.NewClient(apiCaller)
+	result, err := client.Export(2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(result, jc.DeepEquals, &params.Inventory{
+		SchemaVersion:   params.InventorySchemaVersion,
+		ControllerUUID:  testing.ControllerTag.Id(),
+		Generation:      3,
+		SinceGeneration: 2,
+		Models: []params.InventoryModel{{
+			UUID:       testing.ModelTag.Id(),
+			Name:       "testmodel",
+			Generation: 3,
+		}},
+	})
+}
+
+func (s *InventorySuite) TestExportError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			return errors.New("boom")
+		})
+
+	client := inventory.NewClient(apiCaller)
+	_, err := client.Export(0)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -18,15 +18,40 @@ import (
 	"github.com/juju/juju/core/leadership"
 )
 
+// Client extends leadership.Claimer with access to the controller's
+// leadership lease settings.
+type Client interface {
+	leadership.Claimer
+
+	// LeaseSettings returns the duration for which units should claim
+	// leadership, and how often leaders should renew their claims.
+	LeaseSettings() (duration, renewal time.Duration, err error)
+}
+
 type client struct {
 	base.FacadeCaller
 }
 
-// NewClient returns a new leadership.Claimer backed by the supplied api caller.
-func NewClient(caller base.APICaller) leadership.Claimer {
+// NewClient returns a new Client backed by the supplied api caller.
+func NewClient(caller base.APICaller) Client {
 	return &client{base.NewFacadeCaller(caller, "LeadershipService")}
 }
 
+// LeaseSettings is part of the Client interface. It returns a
+// NotSupported error if the controller does not report its settings.
+func (c *client) LeaseSettings() (time.Duration, time.Duration, error) {
+	if c.BestAPIVersion() < 3 {
+		return 0, 0, errors.NotSupportedf("leadership lease settings")
+	}
+	var result params.LeadershipLeaseSettings
+	if err := c.FacadeCall("LeaseSettings", nil, &result); err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	duration := time.Duration(result.LeaseDurationSeconds * float64(time.Second))
+	renewal := time.Duration(result.RenewalIntervalSeconds * float64(time.Second))
+	return duration, renewal, nil
+}
+
 // ClaimLeadership is part of the leadership.Claimer interface.
 func (c *client) ClaimLeadership(serviceId, unitId string, duration time.Duration) error {
 
@@ -191,3 +191,43 @@ func (s *ClientSuite) TestBlockUntilLeadershipReleasedFacadeCallError(c *gc.C) {
 	c.Check(numStubCalls, gc.Equals, 1)
 	c.Check(err, gc.ErrorMatches, "error blocking on leadership release: "+errMsg)
 }
+
+func (s *ClientSuite) TestLeaseSettings(c *gc.C) {
+	numStubCalls := 0
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 3,
+		APICallerFunc: func(facade string, version int, id, request string, arg, result interface{}) error {
+			numStubCalls++
+			c.Check(facade, gc.Equals, "LeadershipService")
+			c.Check(version, gc.Equals, 3)
+			c.Check(request, gc.Equals, "LeaseSettings")
+			c.Check(arg, gc.IsNil)
+			*(result.(*params.LeadershipLeaseSettings)) = params.LeadershipLeaseSettings{
+				LeaseDurationSeconds:   90,
+				RenewalIntervalSeconds: 20,
+			}
+			return nil
+		},
+	}
+
+	client := leadership.NewClient(apiCaller)
+	duration, renewal, err := client.LeaseSettings()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(duration, gc.Equals, 90*time.Second)
+	c.Check(renewal, gc.Equals, 20*time.Second)
+	c.Check(numStubCalls, gc.Equals, 1)
+}
+
+func (s *ClientSuite) TestLeaseSettingsNotSupported(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 2,
+		APICallerFunc: func(facade string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		},
+	}
+
+	client := leadership.NewClient(apiCaller)
+	_, _, err := client.LeaseSettings()
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -135,3 +135,54 @@ func (client *Client) UpdateMachineSeries(machineName, series string, force bool
 	}
 	return results.OneError()
 }
+
+// InstanceMetadata returns the provider-specific metadata of the
+// instances of the given machines.
+func (client *Client) InstanceMetadata(machines ...string) ([]params.InstanceMetadataResult, error) {
+	if client.BestAPIVersion() < 5 {
+		return nil, errors.NotSupportedf("instance metadata")
+	}
+	args := params.Entities{
+		Entities: make([]params.Entity, 0, len(machines)),
+	}
+	allResults := make([]params.InstanceMetadataResult, len(machines))
+	index := make([]int, 0, len(machines))
+	for i, machineId := range machines {
+		if !names.IsValidMachine(machineId) {
+			allResults[i].Error = &params.Error{
+				Message: errors.NotValidf("machine ID %q", machineId).Error(),
+			}
+			continue
+		}
+		index = append(index, i)
+		args.Entities = append(args.Entities, params.Entity{
+			Tag: names.NewMachineTag(machineId).String(),
+		})
+	}
+	if len(args.Entities) > 0 {
+		var result params.InstanceMetadataResults
+		if err := client.facade.FacadeCall("InstanceMetadata", args, &result); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if n := len(result.Results); n != len(args.Entities) {
+			return nil, errors.Errorf("expected %d result(s), got %d", len(args.Entities), n)
+		}
+		for i, result := range result.Results {
+			allResults[index[i]] = result
+		}
+	}
+	return allResults, nil
+}
+
+// PackageUpdates returns the package updates last reported by the
+// agents of the model's machines.
+func (client *Client) PackageUpdates() ([]params.PackageUpdates, error) {
+	if client.BestAPIVersion() < 6 {
+		return nil, errors.NotSupportedf("package updates")
+	}
+	var result params.PackageUpdatesResults
+	if err := client.facade.FacadeCall("PackageUpdates", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Results, nil
+}
@@ -6,6 +6,7 @@ package machinemanager_test
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -210,3 +211,83 @@ func (s *MachinemanagerSuite) TestDestroyMachinesWithParams(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, jc.DeepEquals, expectedResults)
 }
+
+func (s *MachinemanagerSuite) TestInstanceMetadata(c *gc.C) {
+	expectedResults := []params.InstanceMetadataResult{{
+		Result: &params.InstanceMetadata{InstanceId: "inst-0", ImageId: "ami-0"},
+	}, {
+		Error: &params.Error{Message: `machine ID "!" not valid`},
+	}, {
+		Error: &params.Error{Message: "machine 1 not provisioned"},
+	}}
+	var callCount int
+	client := machinemanager.NewClient(basetesting.BestVersionCaller{
+		BestVersion: 5,
+		APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+			c.Check(objType, gc.Equals, "MachineManager")
+			c.Check(request, gc.Equals, "InstanceMetadata")
+			c.Check(a, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "machine-0"}, {Tag: "machine-1"}},
+			})
+			c.Assert(response, gc.FitsTypeOf, &params.InstanceMetadataResults{})
+			out := response.(*params.InstanceMetadataResults)
+			*out = params.InstanceMetadataResults{
+				Results: []params.InstanceMetadataResult{expectedResults[0], expectedResults[2]},
+			}
+			callCount++
+			return nil
+		},
+	})
+	results, err := client.InstanceMetadata("0", "!", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, expectedResults)
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *MachinemanagerSuite) TestInstanceMetadataNotSupported(c *gc.C) {
+	client := machinemanager.NewClient(basetesting.BestVersionCaller{
+		BestVersion: 4,
+		APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+			c.Fatalf("unexpected API call %q", request)
+			return nil
+		},
+	})
+	_, err := client.InstanceMetadata("0")
+	c.Assert(err, gc.ErrorMatches, "instance metadata not supported")
+}
+
+func (s *MachinemanagerSuite) TestPackageUpdates(c *gc.C) {
+	expected := []params.PackageUpdates{{
+		Tag:            "machine-0",
+		Pending:        4,
+		Security:       1,
+		RebootRequired: true,
+		Updated:        time.Date(2018, 4, 3, 12, 0, 0, 0, time.UTC),
+	}}
+	client := machinemanager.NewClient(basetesting.BestVersionCaller{
+		BestVersion: 6,
+		APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+			c.Check(objType, gc.Equals, "MachineManager")
+			c.Check(request, gc.Equals, "PackageUpdates")
+			c.Check(a, gc.IsNil)
+			c.Assert(response, gc.FitsTypeOf, &params.PackageUpdatesResults{})
+			*(response.(*params.PackageUpdatesResults)) = params.PackageUpdatesResults{Results: expected}
+			return nil
+		},
+	})
+	results, err := client.PackageUpdates()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, expected)
+}
+
+func (s *MachinemanagerSuite) TestPackageUpdatesNotSupported(c *gc.C) {
+	client := machinemanager.NewClient(basetesting.BestVersionCaller{
+		BestVersion: 5,
+		APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+			c.Fatalf("unexpected API call %q", request)
+			return nil
+		},
+	})
+	_, err := client.PackageUpdates()
+	c.Assert(err, gc.ErrorMatches, "package updates not supported")
+}
@@ -0,0 +1,63 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinepool
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the machine pool API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the machine pool api.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "MachinePool")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Add adds the machines with the specified ids to the model's machine
+// pool. It returns one error result per machine.
+func (c *Client) Add(machineIds ...string) ([]params.ErrorResult, error) {
+	return c.call("Add", machineIds)
+}
+
+// Remove removes the machines with the specified ids from the model's
+// machine pool. It returns one error result per machine.
+func (c *Client) Remove(machineIds ...string) ([]params.ErrorResult, error) {
+	return c.call("Remove", machineIds)
+}
+
+func (c *Client) call(method string, machineIds []string) ([]params.ErrorResult, error) {
+	args := params.Entities{Entities: make([]params.Entity, len(machineIds))}
+	for i, id := range machineIds {
+		if !names.IsValidMachine(id) {
+			return nil, errors.NotValidf("machine ID %q", id)
+		}
+		args.Entities[i].Tag = names.NewMachineTag(id).String()
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall(method, args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if n := len(results.Results); n != len(machineIds) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(machineIds), n)
+	}
+	return results.Results, nil
+}
+
+// List returns the machines in the model's machine pool.
+func (c *Client) List() ([]params.PoolMachine, error) {
+	var result params.MachinePoolResult
+	if err := c.facade.FacadeCall("List", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Machines, nil
+}
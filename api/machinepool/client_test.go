@@ -0,0 +1,107 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinepool_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/machinepool"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type MachinePoolSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&MachinePoolSuite{})
+
+func (s *MachinePoolSuite) TestList(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "MachinePool")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "List")
+			c.Check(a, gc.IsNil)
+			called = true
+
+			if results, ok := result.(*params.MachinePoolResult); ok {
+				results.Machines = []params.PoolMachine{{
+					MachineTag: "machine-0",
+					Status:     "available",
+				}}
+			}
+			return nil
+		})
+
+	client := machinepool.NewClient(apiCaller)
+	machines, err := client.List()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(machines, jc.DeepEquals, []params.PoolMachine{{
+		MachineTag: "machine-0",
+		Status:     "available",
+	}})
+}
+
+func (s *MachinePoolSuite) TestAdd(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "MachinePool")
+			c.Check(request, gc.Equals, "Add")
+			c.Check(a, jc.DeepEquals, params.Entities{Entities: []params.Entity{
+				{Tag: "machine-0"}, {Tag: "machine-1"},
+			}})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}, {Error: &params.Error{Message: "boom"}}},
+			}
+			return nil
+		})
+
+	client := machinepool.NewClient(apiCaller)
+	results, err := client.Add("0", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.ErrorResult{{}, {Error: &params.Error{Message: "boom"}}})
+}
+
+func (s *MachinePoolSuite) TestRemoveInvalidMachine(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Fatalf("unexpected call")
+			return nil
+		})
+	client := machinepool.NewClient(apiCaller)
+	_, err := client.Remove("foo")
+	c.Assert(err, gc.ErrorMatches, `machine ID "foo" not valid`)
+}
+
+func (s *MachinePoolSuite) TestListError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			return errors.New("boom")
+		})
+	client := machinepool.NewClient(apiCaller)
+	_, err := client.List()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
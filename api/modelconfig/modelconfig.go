@@ -5,12 +5,16 @@ package modelconfig
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs/config"
 )
 
+var logger = loggo.GetLogger("juju.api.modelconfig")
+
 // Client provides methods that the Juju client command uses to interact
 // with models stored in the Juju Server.
 type Client struct {
@@ -57,10 +61,36 @@ func (c *Client) ModelGetWithMetadata() (config.ConfigValues, error) {
 	return values, nil
 }
 
-// ModelSet sets the given key-value pairs in the model.
+// ModelSet sets the given key-value pairs in the model. If the
+// resulting config is not valid, the error's cause is a *params.Error
+// whose Info lists the reasons. Warnings about deprecated attributes
+// reported by the controller are logged.
 func (c *Client) ModelSet(config map[string]interface{}) error {
 	args := params.ModelSet{Config: config}
-	return c.facade.FacadeCall("ModelSet", args, nil)
+	switch c.BestAPIVersion() {
+	case 0, 1, 2, 3:
+		return c.facade.FacadeCall("ModelSet", args, nil)
+	case 4:
+		var result params.ErrorResult
+		if err := c.facade.FacadeCall("ModelSet", args, &result); err != nil {
+			return errors.Trace(err)
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		return nil
+	}
+	var result params.ModelSetResult
+	if err := c.facade.FacadeCall("ModelSet", args, &result); err != nil {
+		return errors.Trace(err)
+	}
+	for _, warning := range result.Warnings {
+		logger.Warningf("%s", warning)
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
 }
 
 // ModelUnset sets the given key-value pairs in the model.
@@ -90,3 +120,46 @@ func (c *Client) SLALevel() (string, error) {
 	}
 	return result.Result, nil
 }
+
+// ModelConfigHistory returns the recorded changes to the model's
+// config, most recent first. If key is not empty, only the changes to
+// that attribute are returned.
+func (c *Client) ModelConfigHistory(key string) ([]params.ModelConfigChange, error) {
+	if c.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("model config history on this Juju controller")
+	}
+	args := params.ModelConfigHistoryArgs{Key: key}
+	var result params.ModelConfigHistoryResult
+	if err := c.facade.FacadeCall("ModelConfigHistory", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Changes, nil
+}
+
+// ConfigSchema returns documentation for the config fields of the
+// model's provider, including any guidance the provider attaches to
+// its own fields.
+func (c *Client) ConfigSchema() (config.Docs, error) {
+	if c.BestAPIVersion() < 2 {
+		return nil, errors.NotSupportedf("config schema on this Juju controller")
+	}
+	var result params.ConfigSchemaResult
+	if err := c.facade.FacadeCall("ConfigSchema", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	docs := make(config.Docs)
+	for name, field := range result.Fields {
+		docs[name] = config.FieldDoc{
+			Attr: environschema.Attr{
+				Description: field.Description,
+				Type:        environschema.FieldType(field.Type),
+				Group:       environschema.Group(field.Group),
+				Immutable:   field.Immutable,
+				Example:     field.Example,
+				Values:      field.Values,
+			},
+			Deprecated: field.Deprecated,
+		}
+	}
+	return docs, nil
+}
@@ -4,14 +4,17 @@
 package modelconfig_test
 
 import (
+	"github.com/juju/errors"
 	gitjujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/environschema.v1"
 
 	basetesting "github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/api/modelconfig"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs/config"
+	coretesting "github.com/juju/juju/testing"
 )
 
 type modelconfigSuite struct {
@@ -104,6 +107,62 @@ func (s *modelconfigSuite) TestModelSet(c *gc.C) {
 	c.Assert(called, jc.IsTrue)
 }
 
+func (s *modelconfigSuite) TestModelSetConfigNotValid(c *gc.C) {
+	configErr := &params.Error{
+		Message: "bad CIDR; bad TTL",
+		Code:    params.CodeConfigNotValid,
+		Info: &params.ErrorInfo{
+			ConfigErrors: []params.ConfigAttributeError{
+				{Attribute: "dns-ttl", Message: "bad TTL"},
+				{Attribute: "ssh-allow", Message: "bad CIDR"},
+			},
+		},
+	}
+	apiCaller := basetesting.BestVersionCaller{
+		BestVersion: 4,
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				c.Check(objType, gc.Equals, "ModelConfig")
+				c.Check(request, gc.Equals, "ModelSet")
+				c.Assert(result, gc.FitsTypeOf, &params.ErrorResult{})
+				result.(*params.ErrorResult).Error = configErr
+				return nil
+			},
+		),
+	}
+	client := modelconfig.NewClient(apiCaller)
+	err := client.ModelSet(map[string]interface{}{"dns-ttl": 0})
+	c.Assert(errors.Cause(err), gc.Equals, configErr)
+}
+
+func (s *modelconfigSuite) TestModelSetLogsWarnings(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		BestVersion: 5,
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				c.Check(request, gc.Equals, "ModelSet")
+				c.Assert(result, gc.FitsTypeOf, &params.ModelSetResult{})
+				result.(*params.ModelSetResult).Warnings = []string{
+					`config attribute "old-name" is deprecated`,
+				}
+				return nil
+			},
+		),
+	}
+	client := modelconfig.NewClient(apiCaller)
+	err := client.ModelSet(map[string]interface{}{"old-name": "value"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(c.GetTestLog(), jc.Contains, `WARNING juju.api.modelconfig config attribute "old-name" is deprecated`)
+}
+
 func (s *modelconfigSuite) TestModelUnset(c *gc.C) {
 	called := false
 	apiCaller := basetesting.APICallerFunc(
@@ -180,3 +239,92 @@ func (s *modelconfigSuite) TestGetSupport(c *gc.C) {
 	c.Assert(called, jc.IsTrue)
 	c.Assert(level, gc.Equals, "level")
 }
+
+func (s *modelconfigSuite) TestConfigSchema(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		BestVersion: 2,
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				c.Check(objType, gc.Equals, "ModelConfig")
+				c.Check(request, gc.Equals, "ConfigSchema")
+				c.Check(a, gc.IsNil)
+				c.Assert(result, gc.FitsTypeOf, &params.ConfigSchemaResult{})
+				results := result.(*params.ConfigSchemaResult)
+				results.Fields = map[string]params.ConfigSchemaField{
+					"network-mode": {
+						Description: "how to network",
+						Type:        "string",
+						Values:      []interface{}{"flat", "vlan"},
+						Deprecated:  "use fan-config instead",
+					},
+				}
+				return nil
+			},
+		),
+	}
+	client := modelconfig.NewClient(apiCaller)
+	docs, err := client.ConfigSchema()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(docs, jc.DeepEquals, config.Docs{
+		"network-mode": {
+			Attr: environschema.Attr{
+				Description: "how to network",
+				Type:        environschema.Tstring,
+				Values:      []interface{}{"flat", "vlan"},
+			},
+			Deprecated: "use fan-config instead",
+		},
+	})
+}
+
+func (s *modelconfigSuite) TestConfigSchemaNotSupported(c *gc.C) {
+	client := modelconfig.NewClient(basetesting.BestVersionCaller{BestVersion: 1})
+	_, err := client.ConfigSchema()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *modelconfigSuite) TestModelConfigHistory(c *gc.C) {
+	now := coretesting.NonZeroTime()
+	apiCaller := basetesting.BestVersionCaller{
+		BestVersion: 3,
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				c.Check(objType, gc.Equals, "ModelConfig")
+				c.Check(request, gc.Equals, "ModelConfigHistory")
+				c.Check(a, jc.DeepEquals, params.ModelConfigHistoryArgs{Key: "http-proxy"})
+				c.Assert(result, gc.FitsTypeOf, &params.ModelConfigHistoryResult{})
+				results := result.(*params.ModelConfigHistoryResult)
+				results.Changes = []params.ModelConfigChange{{
+					Time: now,
+					User: "bob",
+					Key:  "http-proxy",
+					New:  "http://proxy",
+				}}
+				return nil
+			},
+		),
+	}
+	client := modelconfig.NewClient(apiCaller)
+	changes, err := client.ModelConfigHistory("http-proxy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes, jc.DeepEquals, []params.ModelConfigChange{{
+		Time: now,
+		User: "bob",
+		Key:  "http-proxy",
+		New:  "http://proxy",
+	}})
+}
+
+func (s *modelconfigSuite) TestModelConfigHistoryNotSupported(c *gc.C) {
+	client := modelconfig.NewClient(basetesting.BestVersionCaller{BestVersion: 2})
+	_, err := client.ModelConfigHistory("")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
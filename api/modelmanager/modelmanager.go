@@ -4,6 +4,8 @@
 package modelmanager
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"gopkg.in/juju/names.v2"
@@ -39,16 +41,45 @@ func NewClient(st base.APICallCloser) *Client {
 }
 
 // CreateModel creates a new model using the model config,
-// cloud region and credential specified in the args.
+// cloud region and credential specified in the args. If a config
+// profile is named, its values are applied to the model unless
+// overridden by the config.
 func (c *Client) CreateModel(
-	name, owner, cloud, cloudRegion string,
+	name, owner, cloud, cloudRegion, profile string,
+	cloudCredential names.CloudCredentialTag,
+	config map[string]interface{},
+) (base.ModelInfo, error) {
+	return c.createModel(name, owner, cloud, cloudRegion, profile, cloudCredential, config, nil)
+}
+
+// CreateEphemeralModel creates a new model, as CreateModel does, which
+// the controller destroys along with its storage after the given
+// expiry time.
+func (c *Client) CreateEphemeralModel(
+	name, owner, cloud, cloudRegion, profile string,
+	cloudCredential names.CloudCredentialTag,
+	config map[string]interface{},
+	expiry time.Time,
+) (base.ModelInfo, error) {
+	if c.BestAPIVersion() < 7 {
+		return base.ModelInfo{}, errors.NotSupportedf("ephemeral models on this Juju controller")
+	}
+	return c.createModel(name, owner, cloud, cloudRegion, profile, cloudCredential, config, &expiry)
+}
+
+func (c *Client) createModel(
+	name, owner, cloud, cloudRegion, profile string,
 	cloudCredential names.CloudCredentialTag,
 	config map[string]interface{},
+	expiry *time.Time,
 ) (base.ModelInfo, error) {
 	var result base.ModelInfo
 	if !names.IsValidUser(owner) {
 		return result, errors.Errorf("invalid owner name %q", owner)
 	}
+	if profile != "" && c.BestAPIVersion() < 5 {
+		return result, errors.NotSupportedf("config profiles on this Juju controller")
+	}
 	var cloudTag string
 	if cloud != "" {
 		if !names.IsValidCloud(cloud) {
@@ -67,6 +98,8 @@ func (c *Client) CreateModel(
 		CloudTag:           cloudTag,
 		CloudRegion:        cloudRegion,
 		CloudCredentialTag: cloudCredentialTag,
+		Profile:            profile,
+		EphemeralExpiry:    expiry,
 	}
 	var modelInfo params.ModelInfo
 	err := c.facade.FacadeCall("CreateModel", createArgs, &modelInfo)
@@ -388,6 +421,58 @@ func (c *Client) DestroyModel(tag names.ModelTag, destroyStorage *bool) error {
 	return nil
 }
 
+// TransferModelOwnership makes the user the owner of the model.
+func (c *Client) TransferModelOwnership(model names.ModelTag, owner names.UserTag) error {
+	return c.transferModelOwnership(params.TransferModelOwnership{
+		ModelTag: model.String(),
+		OwnerTag: owner.String(),
+	})
+}
+
+// TransferModelOwnershipToTeam makes the named team the owner of the
+// model.
+func (c *Client) TransferModelOwnershipToTeam(model names.ModelTag, team string) error {
+	return c.transferModelOwnership(params.TransferModelOwnership{
+		ModelTag: model.String(),
+		Team:     team,
+	})
+}
+
+func (c *Client) transferModelOwnership(arg params.TransferModelOwnership) error {
+	if c.BestAPIVersion() < 6 {
+		return errors.NotSupportedf("transferring model ownership on this Juju controller")
+	}
+	args := params.TransferModelOwnershipRequest{
+		Transfers: []params.TransferModelOwnership{arg},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("TransferModelOwnership", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// SetModelExpiry sets the time after which the model is destroyed along
+// with its storage. A zero expiry makes the model persistent. Only
+// admins of the model, or of the controller, may do this.
+func (c *Client) SetModelExpiry(model names.ModelTag, expiry time.Time) error {
+	if c.BestAPIVersion() < 7 {
+		return errors.NotSupportedf("changing model expiry on this Juju controller")
+	}
+	arg := params.ModelExpiry{ModelTag: model.String()}
+	if !expiry.IsZero() {
+		arg.Expiry = &expiry
+	}
+	args := params.SetModelExpiryArgs{
+		Models: []params.ModelExpiry{arg},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetModelExpiry", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
 // GrantModel grants a user access to the specified models.
 func (c *Client) GrantModel(user, access string, modelUUIDs ...string) error {
 	return c.modifyModelUser(params.GrantModelAccess, user, access, modelUUIDs)
@@ -31,13 +31,13 @@ var _ = gc.Suite(&modelmanagerSuite{})
 
 func (s *modelmanagerSuite) TestCreateModelBadUser(c *gc.C) {
 	client := modelmanager.NewClient(basetesting.BestVersionCaller{})
-	_, err := client.CreateModel("mymodel", "not a user", "", "", names.CloudCredentialTag{}, nil)
+	_, err := client.CreateModel("mymodel", "not a user", "", "", "", names.CloudCredentialTag{}, nil)
 	c.Assert(err, gc.ErrorMatches, `invalid owner name "not a user"`)
 }
 
 func (s *modelmanagerSuite) TestCreateModelBadCloud(c *gc.C) {
 	client := modelmanager.NewClient(basetesting.BestVersionCaller{})
-	_, err := client.CreateModel("mymodel", "bob", "123!", "", names.CloudCredentialTag{}, nil)
+	_, err := client.CreateModel("mymodel", "bob", "123!", "", "", names.CloudCredentialTag{}, nil)
 	c.Assert(err, gc.ErrorMatches, `invalid cloud name "123!"`)
 }
 
@@ -74,6 +74,7 @@ func (s *modelmanagerSuite) TestCreateModel(c *gc.C) {
 		"bob",
 		"nimbus",
 		"catbus",
+		"",
 		names.CloudCredentialTag{},
 		map[string]interface{}{"abc": 123},
 	)
@@ -97,6 +98,59 @@ func (s *modelmanagerSuite) TestCreateModel(c *gc.C) {
 	})
 }
 
+func (s *modelmanagerSuite) TestCreateModelWithProfile(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		BestVersion: 5,
+		APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "ModelManager")
+			c.Check(request, gc.Equals, "CreateModel")
+			c.Check(arg, jc.DeepEquals, params.ModelCreateArgs{
+				Name:     "new-model",
+				OwnerTag: "user-bob",
+				Profile:  "hardened",
+			})
+			return errors.New("boom")
+		}),
+	}
+	client := modelmanager.NewClient(apiCaller)
+	_, err := client.CreateModel("new-model", "bob", "", "", "hardened", names.CloudCredentialTag{}, nil)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *modelmanagerSuite) TestCreateModelWithProfileNotSupported(c *gc.C) {
+	client := modelmanager.NewClient(basetesting.BestVersionCaller{BestVersion: 4})
+	_, err := client.CreateModel("new-model", "bob", "", "", "hardened", names.CloudCredentialTag{}, nil)
+	c.Assert(err, gc.ErrorMatches, "config profiles on this Juju controller not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *modelmanagerSuite) TestCreateEphemeralModel(c *gc.C) {
+	expiry := time.Date(2018, 4, 1, 12, 0, 0, 0, time.UTC)
+	apiCaller := basetesting.BestVersionCaller{
+		BestVersion: 7,
+		APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "ModelManager")
+			c.Check(request, gc.Equals, "CreateModel")
+			c.Check(arg, jc.DeepEquals, params.ModelCreateArgs{
+				Name:            "new-model",
+				OwnerTag:        "user-bob",
+				EphemeralExpiry: &expiry,
+			})
+			return errors.New("boom")
+		}),
+	}
+	client := modelmanager.NewClient(apiCaller)
+	_, err := client.CreateEphemeralModel("new-model", "bob", "", "", "", names.CloudCredentialTag{}, nil, expiry)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *modelmanagerSuite) TestCreateEphemeralModelNotSupported(c *gc.C) {
+	client := modelmanager.NewClient(basetesting.BestVersionCaller{BestVersion: 6})
+	_, err := client.CreateEphemeralModel("new-model", "bob", "", "", "", names.CloudCredentialTag{}, nil, time.Now())
+	c.Assert(err, gc.ErrorMatches, "ephemeral models on this Juju controller not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *modelmanagerSuite) TestListModelsBadUser(c *gc.C) {
 	client := modelmanager.NewClient(basetesting.BestVersionCaller{})
 	_, err := client.ListModels("not a user")
@@ -527,6 +581,57 @@ func (s *modelmanagerSuite) TestListModelSummariesServerError(c *gc.C) {
 	c.Assert(out, gc.IsNil)
 }
 
+func (s *modelmanagerSuite) TestTransferModelOwnership(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		BestVersion: 6,
+		APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "ModelManager")
+			c.Check(request, gc.Equals, "TransferModelOwnership")
+			c.Check(arg, jc.DeepEquals, params.TransferModelOwnershipRequest{
+				Transfers: []params.TransferModelOwnership{{
+					ModelTag: coretesting.ModelTag.String(),
+					OwnerTag: "user-bob",
+				}},
+			})
+			*result.(*params.ErrorResults) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		}),
+	}
+	client := modelmanager.NewClient(apiCaller)
+	err := client.TransferModelOwnership(coretesting.ModelTag, names.NewUserTag("bob"))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *modelmanagerSuite) TestTransferModelOwnershipToTeam(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		BestVersion: 6,
+		APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(arg, jc.DeepEquals, params.TransferModelOwnershipRequest{
+				Transfers: []params.TransferModelOwnership{{
+					ModelTag: coretesting.ModelTag.String(),
+					Team:     "ops",
+				}},
+			})
+			*result.(*params.ErrorResults) = params.ErrorResults{
+				Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+			}
+			return nil
+		}),
+	}
+	client := modelmanager.NewClient(apiCaller)
+	err := client.TransferModelOwnershipToTeam(coretesting.ModelTag, "ops")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *modelmanagerSuite) TestTransferModelOwnershipNotSupported(c *gc.C) {
+	client := modelmanager.NewClient(basetesting.BestVersionCaller{BestVersion: 5})
+	err := client.TransferModelOwnership(coretesting.ModelTag, names.NewUserTag("bob"))
+	c.Assert(err, gc.ErrorMatches, "transferring model ownership on this Juju controller not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 type dumpModelSuite struct {
 	coretesting.BaseSuite
 }
@@ -678,3 +783,54 @@ func (s *dumpModelSuite) TestDumpModelDBError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "fake error")
 	c.Assert(out, gc.IsNil)
 }
+
+func (s *modelmanagerSuite) TestSetModelExpiry(c *gc.C) {
+	expiry := time.Date(2018, 4, 1, 12, 0, 0, 0, time.UTC)
+	apiCaller := basetesting.BestVersionCaller{
+		BestVersion: 7,
+		APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "ModelManager")
+			c.Check(request, gc.Equals, "SetModelExpiry")
+			c.Check(arg, jc.DeepEquals, params.SetModelExpiryArgs{
+				Models: []params.ModelExpiry{{
+					ModelTag: coretesting.ModelTag.String(),
+					Expiry:   &expiry,
+				}},
+			})
+			*result.(*params.ErrorResults) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		}),
+	}
+	client := modelmanager.NewClient(apiCaller)
+	err := client.SetModelExpiry(coretesting.ModelTag, expiry)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *modelmanagerSuite) TestSetModelExpiryPersistent(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		BestVersion: 7,
+		APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(arg, jc.DeepEquals, params.SetModelExpiryArgs{
+				Models: []params.ModelExpiry{{
+					ModelTag: coretesting.ModelTag.String(),
+				}},
+			})
+			*result.(*params.ErrorResults) = params.ErrorResults{
+				Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+			}
+			return nil
+		}),
+	}
+	client := modelmanager.NewClient(apiCaller)
+	err := client.SetModelExpiry(coretesting.ModelTag, time.Time{})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *modelmanagerSuite) TestSetModelExpiryNotSupported(c *gc.C) {
+	client := modelmanager.NewClient(basetesting.BestVersionCaller{BestVersion: 6})
+	err := client.SetModelExpiry(coretesting.ModelTag, time.Now())
+	c.Assert(err, gc.ErrorMatches, "changing model expiry on this Juju controller not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -0,0 +1,70 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package offercatalog
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the offer catalog API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the offer catalog api.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "OfferCatalog")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Register records the offers of a controller in the offer catalog of
+// the controller the client is connected to, replacing any offers the
+// controller registered before.
+func (c *Client) Register(reg params.OfferCatalogRegistration) error {
+	args := params.OfferCatalogRegistrations{
+		Registrations: []params.OfferCatalogRegistration{reg},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("Register", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// Unregister removes the offers registered by the controller with the
+// given UUID from the offer catalog.
+func (c *Client) Unregister(controllerUUID string) error {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewControllerTag(controllerUUID).String()}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("Unregister", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// List returns the offers registered with the offer catalog by each
+// controller.
+func (c *Client) List() ([]params.OfferCatalogRegistration, error) {
+	var result params.OfferCatalogRegistrations
+	if err := c.facade.FacadeCall("List", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Registrations, nil
+}
+
+// Find returns the offers in the offer catalog that match the filter.
+func (c *Client) Find(filter params.OfferCatalogFilter) ([]params.OfferCatalogEntry, error) {
+	var result params.OfferCatalogEntries
+	if err := c.facade.FacadeCall("Find", filter, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Offers, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package offercatalog_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/offercatalog"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type OfferCatalogSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&OfferCatalogSuite{})
+
+var postgresqlEntry = params.OfferCatalogEntry{
+	ControllerTag: "controller-f47ac10b-58cc-4372-a567-0e02b2c3d479",
+	OfferURL:      "data:fred/prod.postgresql",
+	OfferName:     "postgresql",
+	Endpoints: []params.RemoteEndpoint{
+		{Name: "db", Interface: "pgsql", Role: charm.RoleProvider},
+	},
+}
+
+func (s *OfferCatalogSuite) TestFind(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "OfferCatalog")
+			c.Check(request, gc.Equals, "Find")
+			c.Check(a, jc.DeepEquals, params.OfferCatalogFilter{Interface: "pgsql"})
+			*(result.(*params.OfferCatalogEntries)) = params.OfferCatalogEntries{
+				Offers: []params.OfferCatalogEntry{postgresqlEntry},
+			}
+			return nil
+		})
+	client := offercatalog.NewClient(apiCaller)
+	offers, err := client.Find(params.OfferCatalogFilter{Interface: "pgsql"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offers, jc.DeepEquals, []params.OfferCatalogEntry{postgresqlEntry})
+}
+
+func (s *OfferCatalogSuite) TestRegister(c *gc.C) {
+	reg := params.OfferCatalogRegistration{
+		ControllerTag:  postgresqlEntry.ControllerTag,
+		ControllerName: "data",
+		Offers:         []params.OfferCatalogEntry{postgresqlEntry},
+	}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(request, gc.Equals, "Register")
+			c.Check(a, jc.DeepEquals, params.OfferCatalogRegistrations{
+				Registrations: []params.OfferCatalogRegistration{reg},
+			})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+			}
+			return nil
+		})
+	client := offercatalog.NewClient(apiCaller)
+	err := client.Register(reg)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *OfferCatalogSuite) TestUnregister(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(request, gc.Equals, "Unregister")
+			c.Check(a, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: postgresqlEntry.ControllerTag}},
+			})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		})
+	client := offercatalog.NewClient(apiCaller)
+	err := client.Unregister("f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *OfferCatalogSuite) TestList(c *gc.C) {
+	reg := params.OfferCatalogRegistration{
+		ControllerTag:  postgresqlEntry.ControllerTag,
+		ControllerName: "data",
+		Offers:         []params.OfferCatalogEntry{postgresqlEntry},
+	}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(request, gc.Equals, "List")
+			c.Check(a, gc.IsNil)
+			*(result.(*params.OfferCatalogRegistrations)) = params.OfferCatalogRegistrations{
+				Registrations: []params.OfferCatalogRegistration{reg},
+			}
+			return nil
+		})
+	client := offercatalog.NewClient(apiCaller)
+	regs, err := client.List()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(regs, jc.DeepEquals, []params.OfferCatalogRegistration{reg})
+}
@@ -0,0 +1,106 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operationstimeline_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/operationstimeline"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type OperationsTimelineSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&OperationsTimelineSuite{})
+
+func (s *OperationsTimelineSuite) TestDeployTimeline(c *gc.C) {
+	start := time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC)
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "OperationsTimeline")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "DeployTimeline")
+			c.Check(a, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "application-mysql"}},
+			})
+			called = true
+
+			if results, ok := result.(*params.DeployTimelineResults); ok {
+				results.Results = []params.DeployTimelineResult{{
+					Result: &params.DeployTimeline{
+						Application: "mysql",
+						Events: []params.TimelineEvent{{
+							Entity: "unit-mysql-0",
+							Kind:   params.TimelineHook,
+							Name:   "install",
+							Start:  start,
+						}},
+					},
+				}}
+			}
+			return nil
+		})
+
+	client := operationstimeline.NewClient(apiCaller)
+	timeline, err := client.DeployTimeline("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(timeline, jc.DeepEquals, &params.DeployTimeline{
+		Application: "mysql",
+		Events: []params.TimelineEvent{{
+			Entity: "unit-mysql-0",
+			Kind:   params.TimelineHook,
+			Name:   "install",
+			Start:  start,
+		}},
+	})
+}
+
+func (s *OperationsTimelineSuite) TestDeployTimelineError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			if results, ok := result.(*params.DeployTimelineResults); ok {
+				results.Results = []params.DeployTimelineResult{{
+					Error: &params.Error{Message: `application "mysql" not found`},
+				}}
+			}
+			return nil
+		})
+
+	client := operationstimeline.NewClient(apiCaller)
+	_, err := client.DeployTimeline("mysql")
+	c.Assert(err, gc.ErrorMatches, `application "mysql" not found`)
+}
+
+func (s *OperationsTimelineSuite) TestDeployTimelineInvalidApplication(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Fatalf("unexpected API call")
+			return nil
+		})
+
+	client := operationstimeline.NewClient(apiCaller)
+	_, err := client.DeployTimeline("mysql/0")
+	c.Assert(err, gc.ErrorMatches, `application name "mysql/0" not valid`)
+}
@@ -0,0 +1,33 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package orphanedresources
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the orphaned resources API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the orphaned resources api.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "OrphanedResources")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// OrphanedResources returns the cloud resources tagged as belonging to
+// the model that the model no longer tracks.
+func (c *Client) OrphanedResources() ([]params.OrphanedResource, error) {
+	var result params.OrphanedResourcesResult
+	if err := c.facade.FacadeCall("OrphanedResources", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Resources, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package orphanedresources_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/orphanedresources"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type OrphanedResourcesSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&OrphanedResourcesSuite{})
+
+func (s *OrphanedResourcesSuite) TestOrphanedResources(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "OrphanedResources")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "OrphanedResources")
+			c.Check(a, gc.IsNil)
+			called = true
+
+			if results, ok := result.(*params.OrphanedResourcesResult); ok {
+				results.Resources = []params.OrphanedResource{{
+					Kind: "instance",
+					ID:   "i-0",
+				}}
+			}
+			return nil
+		})
+
+	client := orphanedresources.NewClient(apiCaller)
+	resources, err := client.OrphanedResources()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(resources, jc.DeepEquals, []params.OrphanedResource{{
+		Kind: "instance",
+		ID:   "i-0",
+	}})
+}
+
+func (s *OrphanedResourcesSuite) TestOrphanedResourcesError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			return errors.New("boom")
+		})
+	client := orphanedresources.NewClient(apiCaller)
+	_, err := client.OrphanedResources()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,62 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package packageupdatereporter implements the client-side API facade
+// used by the packageupdatereporter worker.
+package packageupdatereporter
+
+import (
+	"time"
+
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Report describes the package updates pending on a machine.
+type Report struct {
+	// Pending is the number of package updates available.
+	Pending int
+
+	// Security is the number of the pending updates that are security
+	// updates.
+	Security int
+
+	// RebootRequired reports whether updates already installed need
+	// the machine to be rebooted to take effect.
+	RebootRequired bool
+
+	// Updated is when the updates were checked.
+	Updated time.Time
+}
+
+// Facade provides access to the PackageUpdateReporter API facade.
+type Facade struct {
+	caller base.FacadeCaller
+}
+
+// NewFacade creates a new client-side PackageUpdateReporter facade.
+func NewFacade(caller base.APICaller) *Facade {
+	return &Facade{
+		caller: base.NewFacadeCaller(caller, "PackageUpdateReporter"),
+	}
+}
+
+// ReportUpdates reports the package updates pending on a machine to
+// the controller.
+func (f *Facade) ReportUpdates(machineId string, report Report) error {
+	args := params.SetPackageUpdates{Updates: []params.PackageUpdates{{
+		Tag:            names.NewMachineTag(machineId).String(),
+		Pending:        report.Pending,
+		Security:       report.Security,
+		RebootRequired: report.RebootRequired,
+		Updated:        report.Updated,
+	}}}
+	var result params.ErrorResults
+	err := f.caller.FacadeCall("ReportUpdates", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
@@ -0,0 +1,99 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package packageupdatereporter_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/packageupdatereporter"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type facadeSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&facadeSuite{})
+
+func (s *facadeSuite) TestReportUpdates(c *gc.C) {
+	now := time.Date(2018, 4, 3, 12, 0, 0, 0, time.UTC)
+	stub := new(testing.Stub)
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		c.Check(objType, gc.Equals, "PackageUpdateReporter")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		stub.AddCall(request, args)
+		*response.(*params.ErrorResults) = params.ErrorResults{
+			Results: []params.ErrorResult{{
+				(*params.Error)(nil),
+			}},
+		}
+		return nil
+	})
+	facade := packageupdatereporter.NewFacade(apiCaller)
+
+	err := facade.ReportUpdates("42", packageupdatereporter.Report{
+		Pending:        3,
+		Security:       1,
+		RebootRequired: true,
+		Updated:        now,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	stub.CheckCalls(c, []testing.StubCall{{
+		"ReportUpdates", []interface{}{params.SetPackageUpdates{
+			Updates: []params.PackageUpdates{{
+				Tag:            names.NewMachineTag("42").String(),
+				Pending:        3,
+				Security:       1,
+				RebootRequired: true,
+				Updated:        now,
+			}},
+		}},
+	}})
+}
+
+func (s *facadeSuite) TestCallError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		return errors.New("blam")
+	})
+	facade := packageupdatereporter.NewFacade(apiCaller)
+
+	err := facade.ReportUpdates("42", packageupdatereporter.Report{})
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
+
+func (s *facadeSuite) TestInnerError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		*response.(*params.ErrorResults) = params.ErrorResults{
+			Results: []params.ErrorResult{{
+				&params.Error{Message: "blam"},
+			}},
+		}
+		return nil
+	})
+	facade := packageupdatereporter.NewFacade(apiCaller)
+
+	err := facade.ReportUpdates("42", packageupdatereporter.Report{})
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
@@ -321,6 +321,29 @@ func (st *State) HostChangesForContainer(containerTag names.MachineTag) ([]netwo
 	return res, result.Results[0].ReconfigureDelay, nil
 }
 
+// ApplicationZones returns the availability zones of the provisioned
+// machines hosting units of the named application. The provisioner
+// uses them to resolve machine-with placement expressions.
+func (st *State) ApplicationZones(appName string) ([]string, error) {
+	if st.facade.BestAPIVersion() < 6 {
+		return nil, errors.NotSupportedf("resolving application zones")
+	}
+	var results params.StringsResults
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: names.NewApplicationTag(appName).String()},
+	}}
+	if err := st.facade.FacadeCall("ApplicationZones", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, err
+	}
+	return results.Results[0].Result, nil
+}
+
 // DistributionGroupByMachineId returns a slice of machine.Ids
 // that belong to the same distribution group as the given
 // Machine. The provisioner may use this information
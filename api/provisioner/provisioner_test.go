@@ -484,6 +484,27 @@ func (s *provisionerSuite) TestDistributionGroupByMachineId(c *gc.C) {
 	})
 }
 
+func (s *provisionerSuite) TestApplicationZones(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	hc := instance.MustParseHardware("availability-zone=zone-a")
+	err = machine.SetProvisioned("i-0", "nonce", &hc)
+	c.Assert(err, jc.ErrorIsNil)
+	wordpress := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	zones, err := s.provisioner.ApplicationZones("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, jc.DeepEquals, []string{"zone-a"})
+
+	_, err = s.provisioner.ApplicationZones("haproxy")
+	c.Assert(err, gc.ErrorMatches, `application "haproxy" not found`)
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
+
 func (s *provisionerSuite) TestDistributionGroupByMachineIdNotFound(c *gc.C) {
 	stateMachine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -91,3 +91,23 @@ func (api *API) ProxyConfig() (proxySettings, APTProxySettings proxy.Settings, e
 	APTProxySettings = proxySettingsParamToProxySettings(result.APTProxySettings)
 	return proxySettings, APTProxySettings, nil
 }
+
+// CACertificates returns the custom CA certificates that the model's
+// machines add to their system trust store.
+func (api *API) CACertificates() ([]string, error) {
+	var results params.ProxyConfigResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: api.tag.String()}},
+	}
+	if err := api.facade.FacadeCall("ProxyConfig", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.CACertificates, nil
+}
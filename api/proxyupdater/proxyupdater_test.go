@@ -117,3 +117,38 @@ func (s *ProxyUpdaterSuite) TestProxyConfig(c *gc.C) {
 		NoProxy: "NoProxy-apt",
 	})
 }
+
+func (s *ProxyUpdaterSuite) TestCACertificates(c *gc.C) {
+	called, api := newAPI(c, apitesting.APICall{
+		Facade: "ProxyUpdater",
+		Method: "ProxyConfig",
+		Args: params.Entities{
+			Entities: []params.Entity{{Tag: "unit-u-0"}},
+		},
+		Results: params.ProxyConfigResults{
+			Results: []params.ProxyConfigResult{{
+				CACertificates: []string{coretesting.CACert},
+			}},
+		},
+	})
+
+	certs, err := api.CACertificates()
+	c.Assert(*called, gc.Equals, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(certs, jc.DeepEquals, []string{coretesting.CACert})
+}
+
+func (s *ProxyUpdaterSuite) TestCACertificatesError(c *gc.C) {
+	_, api := newAPI(c, apitesting.APICall{
+		Facade: "ProxyUpdater",
+		Method: "ProxyConfig",
+		Results: params.ProxyConfigResults{
+			Results: []params.ProxyConfigResult{{
+				Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized},
+			}},
+		},
+	})
+
+	_, err := api.CACertificates()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
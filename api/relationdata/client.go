@@ -0,0 +1,44 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package relationdata
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the relation data API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the relation data api.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "RelationData")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// RelationData returns the data bags of the units in scope in the
+// relation with the given id. Settings that look like secrets are
+// redacted unless reveal is true.
+func (c *Client) RelationData(relationId int, reveal bool) (*params.RelationData, error) {
+	args := params.RelationDataArgs{
+		RelationIds: []int{relationId},
+		Reveal:      reveal,
+	}
+	var results params.RelationDataResults
+	if err := c.facade.FacadeCall("RelationData", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, err
+	}
+	return results.Results[0].Result, nil
+}
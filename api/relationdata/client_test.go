@@ -0,0 +1,93 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package relationdata_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/relationdata"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type RelationDataSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&RelationDataSuite{})
+
+func (s *RelationDataSuite) TestRelationData(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "RelationData")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "RelationData")
+			c.Check(a, jc.DeepEquals, params.RelationDataArgs{
+				RelationIds: []int{3},
+				Reveal:      true,
+			})
+			called = true
+
+			if results, ok := result.(*params.RelationDataResults); ok {
+				results.Results = []params.RelationDataResult{{
+					Result: &params.RelationData{
+						Id:  3,
+						Key: "wordpress:db mysql:server",
+					},
+				}}
+			}
+			return nil
+		})
+
+	client := relationdata.NewClient(apiCaller)
+	data, err := client.RelationData(3, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(data, jc.DeepEquals, &params.RelationData{
+		Id:  3,
+		Key: "wordpress:db mysql:server",
+	})
+}
+
+func (s *RelationDataSuite) TestRelationDataResultError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			if results, ok := result.(*params.RelationDataResults); ok {
+				results.Results = []params.RelationDataResult{{
+					Error: &params.Error{Code: params.CodeNotFound, Message: "relation 3 not found"},
+				}}
+			}
+			return nil
+		})
+	client := relationdata.NewClient(apiCaller)
+	_, err := client.RelationData(3, false)
+	c.Assert(err, gc.ErrorMatches, "relation 3 not found")
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func (s *RelationDataSuite) TestRelationDataError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			return errors.New("boom")
+		})
+	client := relationdata.NewClient(apiCaller)
+	_, err := client.RelationData(3, false)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,33 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package spacetopology
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the space topology API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the space topology api.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "SpaceTopology")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Topology returns the model's spaces, subnets, machines and
+// applications, and the ways they are connected, as a graph.
+func (c *Client) Topology() (params.SpaceTopology, error) {
+	var result params.SpaceTopology
+	if err := c.facade.FacadeCall("Topology", nil, &result); err != nil {
+		return params.SpaceTopology{}, errors.Trace(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package spacetopology_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/spacetopology"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type TopologySuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&TopologySuite{})
+
+func (s *TopologySuite) TestTopology(c *gc.C) {
+	expected := params.SpaceTopology{
+		Nodes: []params.TopologyNode{{
+			Id:    "space:public",
+			Kind:  "space",
+			Label: "public",
+		}, {
+			Id:    "subnet:10.0.0.0/24",
+			Kind:  "subnet",
+			Label: "10.0.0.0/24",
+		}},
+		Edges: []params.TopologyEdge{{
+			From: "space:public",
+			To:   "subnet:10.0.0.0/24",
+			Kind: "contains",
+		}},
+	}
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "SpaceTopology")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Topology")
+			c.Check(a, gc.IsNil)
+			called = true
+
+			c.Assert(result, gc.FitsTypeOf, &params.SpaceTopology{})
+			*(result.(*params.SpaceTopology)) = expected
+			return nil
+		})
+
+	client := spacetopology.NewClient(apiCaller)
+	result, err := client.Topology()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(result, jc.DeepEquals, expected)
+}
+
+func (s *TopologySuite) TestTopologyError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			return errors.New("boom")
+		})
+
+	client := spacetopology.NewClient(apiCaller)
+	_, err := client.Topology()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -111,6 +111,18 @@ func (facade *Facade) Proxy() (bool, error) {
 	return out.UseProxy, nil
 }
 
+// Bastion returns the details needed to connect to the model's machines
+// through the ssh bastion running on the controller machines. The port
+// in the result is zero if the bastion is disabled.
+func (facade *Facade) Bastion() (params.SSHBastionResult, error) {
+	var out params.SSHBastionResult
+	err := facade.caller.FacadeCall("Bastion", nil, &out)
+	if err != nil {
+		return params.SSHBastionResult{}, errors.Trace(err)
+	}
+	return out, nil
+}
+
 func targetToEntities(target string) (params.Entities, error) {
 	tag, err := targetToTag(target)
 	if err != nil {
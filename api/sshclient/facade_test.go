@@ -280,3 +280,34 @@ func (s *FacadeSuite) TestProxyError(c *gc.C) {
 	_, err := facade.Proxy()
 	c.Check(err, gc.ErrorMatches, "boom")
 }
+
+func (s *FacadeSuite) TestBastion(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		stub.AddCall(objType+"."+request, arg)
+		*result.(*params.SSHBastionResult) = params.SSHBastionResult{
+			Port:      17022,
+			ModelUUID: "deadbeef-0bad-400d-8000-4b1d0d06f00d",
+			HostKeys:  []string{"rsa"},
+		}
+		return nil
+	})
+	facade := sshclient.NewFacade(apiCaller)
+	result, err := facade.Bastion()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(result, jc.DeepEquals, params.SSHBastionResult{
+		Port:      17022,
+		ModelUUID: "deadbeef-0bad-400d-8000-4b1d0d06f00d",
+		HostKeys:  []string{"rsa"},
+	})
+	stub.CheckCalls(c, []jujutesting.StubCall{{"SSHClient.Bastion", []interface{}{nil}}})
+}
+
+func (s *FacadeSuite) TestBastionError(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("boom")
+	})
+	facade := sshclient.NewFacade(apiCaller)
+	_, err := facade.Bastion()
+	c.Check(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,64 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sshclient
+
+import (
+	"io"
+	"net/url"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// OpenTunnel opens a connection to the ssh port of the given target, a
+// machine ID or unit name, relayed through the controller's API
+// server. The ssh protocol may be spoken directly over the returned
+// connection.
+func OpenTunnel(connector base.StreamConnector, target string) (io.ReadWriteCloser, error) {
+	attrs := url.Values{"target": {target}}
+	stream, err := connector.ConnectStream("/ssh-tunnel", attrs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "opening ssh tunnel to %q", target)
+	}
+	return &tunnelConn{stream: stream}, nil
+}
+
+// tunnelConn adapts a tunnel stream, which carries data in discrete
+// messages, to an io.ReadWriteCloser.
+type tunnelConn struct {
+	stream base.Stream
+	// pending holds data received but not yet read.
+	pending []byte
+}
+
+// Read is part of the io.Reader interface.
+func (c *tunnelConn) Read(p []byte) (int, error) {
+	for len(c.pending) == 0 {
+		var data params.SSHTunnelData
+		if err := c.stream.ReadJSON(&data); err != nil {
+			// The stream is closed when either end of the tunnel
+			// goes away, which is the end of the data.
+			return 0, io.EOF
+		}
+		c.pending = data.Data
+	}
+	n := copy(p, c.pending)
+	c.pending = c.pending[n:]
+	return n, nil
+}
+
+// Write is part of the io.Writer interface.
+func (c *tunnelConn) Write(p []byte) (int, error) {
+	if err := c.stream.WriteJSON(params.SSHTunnelData{Data: p}); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return len(p), nil
+}
+
+// Close is part of the io.Closer interface.
+func (c *tunnelConn) Close() error {
+	return c.stream.Close()
+}
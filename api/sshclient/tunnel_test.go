@@ -0,0 +1,102 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sshclient_test
+
+import (
+	"io"
+	"io/ioutil"
+	"net/url"
+
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/sshclient"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type TunnelSuite struct {
+	jujutesting.IsolationSuite
+}
+
+var _ = gc.Suite(&TunnelSuite{})
+
+func (s *TunnelSuite) TestOpenTunnel(c *gc.C) {
+	stream := &fakeTunnelStream{
+		received: []params.SSHTunnelData{
+			{Data: []byte("SSH-2.0-")},
+			{Data: []byte("OpenSSH\r\n")},
+		},
+	}
+	connector := &fakeTunnelConnector{stream: stream}
+
+	conn, err := sshclient.OpenTunnel(connector, "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(connector.path, gc.Equals, "/ssh-tunnel")
+	c.Assert(connector.attrs, jc.DeepEquals, url.Values{"target": {"mysql/0"}})
+
+	n, err := conn.Write([]byte("hello"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(n, gc.Equals, 5)
+	c.Assert(stream.sent, jc.DeepEquals, []interface{}{
+		params.SSHTunnelData{Data: []byte("hello")},
+	})
+
+	data, err := ioutil.ReadAll(conn)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "SSH-2.0-OpenSSH\r\n")
+
+	c.Assert(conn.Close(), jc.ErrorIsNil)
+	c.Assert(stream.closed, jc.IsTrue)
+}
+
+func (s *TunnelSuite) TestOpenTunnelError(c *gc.C) {
+	connector := &fakeTunnelConnector{err: errors.New("permission denied")}
+	_, err := sshclient.OpenTunnel(connector, "0")
+	c.Assert(err, gc.ErrorMatches, `opening ssh tunnel to "0": permission denied`)
+}
+
+type fakeTunnelConnector struct {
+	path   string
+	attrs  url.Values
+	stream base.Stream
+	err    error
+}
+
+func (c *fakeTunnelConnector) ConnectStream(path string, attrs url.Values) (base.Stream, error) {
+	c.path = path
+	c.attrs = attrs
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.stream, nil
+}
+
+type fakeTunnelStream struct {
+	base.Stream
+	received []params.SSHTunnelData
+	sent     []interface{}
+	closed   bool
+}
+
+func (s *fakeTunnelStream) ReadJSON(v interface{}) error {
+	if len(s.received) == 0 {
+		return io.EOF
+	}
+	*v.(*params.SSHTunnelData) = s.received[0]
+	s.received = s.received[1:]
+	return nil
+}
+
+func (s *fakeTunnelStream) WriteJSON(v interface{}) error {
+	s.sent = append(s.sent, v)
+	return nil
+}
+
+func (s *fakeTunnelStream) Close() error {
+	s.closed = true
+	return nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sshkeyimporter
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the SSHKeyImporter API facade.
+type Client struct {
+	*common.ModelWatcher
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new SSHKeyImporter client.
+func NewClient(caller base.APICaller) *Client {
+	facadeCaller := base.NewFacadeCaller(caller, "SSHKeyImporter")
+	return &Client{
+		ModelWatcher: common.NewModelWatcher(facadeCaller),
+		facade:       facadeCaller,
+	}
+}
+
+// RefreshKeys refreshes the model's authorized keys from their key
+// server sources and removes any expired keys. It returns how long to
+// wait before the keys should next be refreshed.
+func (c *Client) RefreshKeys() (time.Duration, error) {
+	var result params.SSHKeyRefreshResult
+	if err := c.facade.FacadeCall("RefreshKeys", nil, &result); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return result.RefreshAfter, nil
+}
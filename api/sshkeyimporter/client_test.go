@@ -0,0 +1,60 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sshkeyimporter_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/sshkeyimporter"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type SSHKeyImporterSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&SSHKeyImporterSuite{})
+
+func (s *SSHKeyImporterSuite) TestRefreshKeys(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "SSHKeyImporter")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "RefreshKeys")
+			c.Check(a, gc.IsNil)
+			c.Assert(result, gc.FitsTypeOf, &params.SSHKeyRefreshResult{})
+			*(result.(*params.SSHKeyRefreshResult)) = params.SSHKeyRefreshResult{
+				RefreshAfter: time.Hour,
+			}
+			return nil
+		})
+	client := sshkeyimporter.NewClient(apiCaller)
+	refreshAfter, err := client.RefreshKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(refreshAfter, gc.Equals, time.Hour)
+}
+
+func (s *SSHKeyImporterSuite) TestRefreshKeysError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			return errors.New("boom")
+		})
+	client := sshkeyimporter.NewClient(apiCaller)
+	_, err := client.RefreshKeys()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
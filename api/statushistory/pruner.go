@@ -27,9 +27,16 @@ func NewFacade(caller base.APICaller) *Facade {
 
 // Prune calls "StatusHistory.Prune"
 func (s *Facade) Prune(maxHistoryTime time.Duration, maxHistoryMB int) error {
+	return s.PruneEntities(maxHistoryTime, maxHistoryMB, 0)
+}
+
+// PruneEntities calls "StatusHistory.Prune", additionally limiting the
+// history of each entity to maxEntriesPerEntity entries unless it is 0.
+func (s *Facade) PruneEntities(maxHistoryTime time.Duration, maxHistoryMB, maxEntriesPerEntity int) error {
 	p := params.StatusHistoryPruneArgs{
-		MaxHistoryTime: maxHistoryTime,
-		MaxHistoryMB:   maxHistoryMB,
+		MaxHistoryTime:      maxHistoryTime,
+		MaxHistoryMB:        maxHistoryMB,
+		MaxEntriesPerEntity: maxEntriesPerEntity,
 	}
 	return s.facade.FacadeCall("Prune", p, nil)
 }
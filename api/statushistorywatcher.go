@@ -0,0 +1,63 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package api
+
+import (
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/status"
+)
+
+// StatusHistoryWatcher returns the entries added to an entity's status
+// history from a watcher created by the WatchStatusHistory API call.
+type StatusHistoryWatcher struct {
+	caller base.APICaller
+	id     string
+}
+
+func newStatusHistoryWatcher(caller base.APICaller, id string) *StatusHistoryWatcher {
+	return &StatusHistoryWatcher{
+		caller: caller,
+		id:     id,
+	}
+}
+
+// Next returns the entries added to the status history since the
+// previous call, or since the watcher was created, oldest first. It
+// blocks until there are entries to return.
+func (w *StatusHistoryWatcher) Next() (status.History, error) {
+	var result params.StatusHistoryWatchResult
+	err := w.caller.APICall(
+		"StatusHistoryWatcher",
+		w.caller.BestFacadeVersion("StatusHistoryWatcher"),
+		w.id,
+		"Next",
+		nil, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	history := make(status.History, len(result.Statuses))
+	for i, s := range result.Statuses {
+		history[i] = status.DetailedStatus{
+			Status: status.Status(s.Status),
+			Info:   s.Info,
+			Data:   s.Data,
+			Since:  s.Since,
+			Kind:   status.HistoryKind(s.Kind),
+		}
+	}
+	return history, nil
+}
+
+// Stop stops the watcher.
+func (w *StatusHistoryWatcher) Stop() error {
+	return w.caller.APICall(
+		"StatusHistoryWatcher",
+		w.caller.BestFacadeVersion("StatusHistoryWatcher"),
+		w.id,
+		"Stop",
+		nil, nil,
+	)
+}
@@ -0,0 +1,49 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package api
+
+import (
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// StatusWatcher returns the changes to a model's full status, as
+// diffs, from a watcher created by the WatchStatus API call.
+type StatusWatcher struct {
+	caller base.APICaller
+	id     string
+}
+
+func newStatusWatcher(caller base.APICaller, id string) *StatusWatcher {
+	return &StatusWatcher{
+		caller: caller,
+		id:     id,
+	}
+}
+
+// Next returns the diffs to the model's full status since the
+// previous call. The first call returns the whole status, as diffs
+// from an empty one. It blocks until there are diffs to return.
+func (w *StatusWatcher) Next() ([]params.StatusDiff, error) {
+	var result params.StatusWatchResult
+	err := w.caller.APICall(
+		"StatusWatcher",
+		w.caller.BestFacadeVersion("StatusWatcher"),
+		w.id,
+		"Next",
+		nil, &result,
+	)
+	return result.Diffs, err
+}
+
+// Stop stops the watcher.
+func (w *StatusWatcher) Stop() error {
+	return w.caller.APICall(
+		"StatusWatcher",
+		w.caller.BestFacadeVersion("StatusWatcher"),
+		w.id,
+		"Stop",
+		nil, nil,
+	)
+}
@@ -0,0 +1,75 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package tracing provides access to the Tracing facade, through which
+// agents record the spans of slow operations and clients retrieve them.
+package tracing
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	coretracing "github.com/juju/juju/core/tracing"
+)
+
+// Client provides access to the Tracing facade. It implements
+// core/tracing.Recorder.
+type Client struct {
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new Client that makes API calls through the
+// given caller.
+func NewClient(caller base.APICaller) *Client {
+	return &Client{facade: base.NewFacadeCaller(caller, "Tracing")}
+}
+
+// RecordSpans sends the spans to the controller, which records those
+// of slow operations.
+func (c *Client) RecordSpans(spans []coretracing.Span) error {
+	args := params.TraceSpans{
+		Spans: make([]params.TraceSpan, len(spans)),
+	}
+	for i, span := range spans {
+		args.Spans[i] = params.TraceSpan{
+			Kind:     string(span.Kind),
+			Entity:   span.Entity,
+			Name:     span.Name,
+			Start:    span.Start,
+			Duration: span.Duration,
+			Error:    span.Error,
+		}
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("RecordSpans", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.Combine()
+}
+
+// SlowSpans returns the recorded spans of slow operations in the model,
+// in the order they started. If kind or entity are not empty, only the
+// spans of that kind, or for the entity with that tag, are returned.
+func (c *Client) SlowSpans(kind coretracing.Kind, entity string) ([]coretracing.Span, error) {
+	args := params.SlowSpansArgs{
+		Kind:   string(kind),
+		Entity: entity,
+	}
+	var result params.TraceSpans
+	if err := c.facade.FacadeCall("SlowSpans", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	spans := make([]coretracing.Span, len(result.Spans))
+	for i, span := range result.Spans {
+		spans[i] = coretracing.Span{
+			Kind:     coretracing.Kind(span.Kind),
+			Entity:   span.Entity,
+			Name:     span.Name,
+			Start:    span.Start,
+			Duration: span.Duration,
+			Error:    span.Error,
+		}
+	}
+	return spans, nil
+}
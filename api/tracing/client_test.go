@@ -0,0 +1,103 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tracing_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/tracing"
+	"github.com/juju/juju/apiserver/params"
+	coretracing "github.com/juju/juju/core/tracing"
+	"github.com/juju/juju/testing"
+)
+
+type TracingSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&TracingSuite{})
+
+func (s *TracingSuite) TestRecordSpans(c *gc.C) {
+	start := time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC)
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Tracing")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "RecordSpans")
+			c.Check(a, jc.DeepEquals, params.TraceSpans{
+				Spans: []params.TraceSpan{{
+					Kind:     "hook",
+					Entity:   "unit-mysql-0",
+					Name:     "install",
+					Start:    start,
+					Duration: time.Minute,
+				}},
+			})
+			called = true
+
+			if results, ok := result.(*params.ErrorResults); ok {
+				results.Results = []params.ErrorResult{{
+					Error: &params.Error{Message: "permission denied"},
+				}}
+			}
+			return nil
+		})
+
+	client := tracing.NewClient(apiCaller)
+	err := client.RecordSpans([]coretracing.Span{{
+		Kind:     coretracing.KindHook,
+		Entity:   "unit-mysql-0",
+		Name:     "install",
+		Start:    start,
+		Duration: time.Minute,
+	}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *TracingSuite) TestSlowSpans(c *gc.C) {
+	start := time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC)
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Tracing")
+			c.Check(request, gc.Equals, "SlowSpans")
+			c.Check(a, jc.DeepEquals, params.SlowSpansArgs{Kind: "provider-call"})
+			if result, ok := result.(*params.TraceSpans); ok {
+				result.Spans = []params.TraceSpan{{
+					Kind:     "provider-call",
+					Entity:   "machine-3",
+					Name:     "StartInstance",
+					Start:    start,
+					Duration: time.Minute,
+					Error:    "no capacity",
+				}}
+			}
+			return nil
+		})
+
+	client := tracing.NewClient(apiCaller)
+	spans, err := client.SlowSpans(coretracing.KindProviderCall, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spans, jc.DeepEquals, []coretracing.Span{{
+		Kind:     coretracing.KindProviderCall,
+		Entity:   "machine-3",
+		Name:     "StartInstance",
+		Start:    start,
+		Duration: time.Minute,
+		Error:    "no capacity",
+	}})
+}
@@ -5,8 +5,9 @@ package uniter
 
 // Action represents a single instance of an Action call, by name and params.
 type Action struct {
-	name   string
-	params map[string]interface{}
+	name          string
+	params        map[string]interface{}
+	correlationID string
 }
 
 // NewAction makes a new Action with specified name and params map.
@@ -23,3 +24,9 @@ func (a *Action) Name() string {
 func (a *Action) Params() map[string]interface{} {
 	return a.params
 }
+
+// CorrelationID returns the ID correlating the Action with the
+// operator action that queued it, if any.
+func (a *Action) CorrelationID() string {
+	return a.correlationID
+}
@@ -5,6 +5,7 @@ package uniter
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"gopkg.in/juju/names.v2"
@@ -94,6 +95,78 @@ func (ru *RelationUnit) LeaveScope() error {
 	return result.OneError()
 }
 
+// SetDepartureBarrier sets a departure barrier on the relation, so
+// that its provider units wait up to the timeout for the requirer units
+// to confirm their departure before breaking it. Only provider units
+// may set the barrier.
+func (ru *RelationUnit) SetDepartureBarrier(timeout time.Duration) error {
+	if ru.st.facade.BestAPIVersion() < 9 {
+		return errors.NotSupportedf("relation departure barriers")
+	}
+	var result params.ErrorResults
+	args := params.RelationDepartureBarrierArgs{
+		Args: []params.RelationDepartureBarrierArg{{
+			Relation: ru.relation.tag.String(),
+			Unit:     ru.unit.tag.String(),
+			Timeout:  timeout,
+		}},
+	}
+	err := ru.st.facade.FacadeCall("SetRelationDepartureBarriers", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
+// ConfirmDeparture records that the unit has cleaned up after the
+// relation, so that the relation's departure barrier need not wait for
+// it. Only requirer units may confirm their departure.
+func (ru *RelationUnit) ConfirmDeparture() error {
+	if ru.st.facade.BestAPIVersion() < 9 {
+		return errors.NotSupportedf("relation departure barriers")
+	}
+	var result params.ErrorResults
+	args := params.RelationUnits{
+		RelationUnits: []params.RelationUnit{{
+			Relation: ru.relation.tag.String(),
+			Unit:     ru.unit.tag.String(),
+		}},
+	}
+	err := ru.st.facade.FacadeCall("ConfirmRelationDepartures", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
+// DepartureBarrierReleased reports whether the relation's departure
+// barrier allows the unit to break the relation. Controllers that do
+// not support departure barriers never hold the unit.
+func (ru *RelationUnit) DepartureBarrierReleased() (bool, error) {
+	if ru.st.facade.BestAPIVersion() < 9 {
+		return true, nil
+	}
+	var results params.BoolResults
+	args := params.RelationUnits{
+		RelationUnits: []params.RelationUnit{{
+			Relation: ru.relation.tag.String(),
+			Unit:     ru.unit.tag.String(),
+		}},
+	}
+	err := ru.st.facade.FacadeCall("RelationDepartureBarriersReleased", args, &results)
+	if err != nil {
+		return false, err
+	}
+	if len(results.Results) != 1 {
+		return false, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.Result, nil
+}
+
 // Settings returns a Settings which allows access to the unit's settings
 // within the relation.
 func (ru *RelationUnit) Settings() (*Settings, error) {
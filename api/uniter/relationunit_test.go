@@ -4,6 +4,8 @@
 package uniter_test
 
 import (
+	"time"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6"
@@ -173,6 +175,30 @@ func (s *relationUnitSuite) TestLeaveScope(c *gc.C) {
 	s.assertInScope(c, wpRelUnit, false)
 }
 
+func (s *relationUnitSuite) TestDepartureBarrier(c *gc.C) {
+	_, apiRelUnit := s.getRelationUnits(c)
+
+	// Only provider units may set the barrier.
+	err := apiRelUnit.SetDepartureBarrier(time.Minute)
+	c.Assert(err, gc.ErrorMatches, `unit "wordpress/0" is not a provider in relation "wordpress:db mysql:server"`)
+
+	err = s.stateRelation.SetDepartureBarrier(time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	err = apiRelUnit.ConfirmDeparture()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.stateRelation.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	barrier, err := s.stateRelation.DepartureBarrier()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(barrier.Confirmed, jc.DeepEquals, []string{"wordpress/0"})
+
+	// The barrier never holds requirer units.
+	released, err := apiRelUnit.DepartureBarrierReleased()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(released, jc.IsTrue)
+}
+
 func (s *relationUnitSuite) TestSettings(c *gc.C) {
 	wpRelUnit, apiRelUnit := s.getRelationUnits(c)
 	settings := map[string]interface{}{
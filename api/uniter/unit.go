@@ -139,6 +139,58 @@ func (u *Unit) SetAgentStatus(agentStatus status.Status, info string, data map[s
 	return result.OneError()
 }
 
+// SetAgentStatuses sets the unit agent's status to each of the given
+// statuses in turn, recording each as reached at its Since time. It
+// returns an error for each status the controller rejected, and an
+// error if the statuses could not be sent at all.
+func (u *Unit) SetAgentStatuses(statuses []status.StatusInfo) ([]error, error) {
+	setStatusFacadeCall := "SetAgentStatus"
+	if u.st.facade.BestAPIVersion() < 2 {
+		setStatusFacadeCall = "SetStatus"
+	}
+	return u.setStatuses(setStatusFacadeCall, statuses)
+}
+
+// SetUnitStatuses sets the unit's workload status to each of the given
+// statuses in turn, recording each as reached at its Since time. It
+// returns an error for each status the controller rejected, and an
+// error if the statuses could not be sent at all.
+func (u *Unit) SetUnitStatuses(statuses []status.StatusInfo) ([]error, error) {
+	if u.st.facade.BestAPIVersion() < 2 {
+		return nil, errors.NotImplementedf("SetUnitStatus")
+	}
+	return u.setStatuses("SetUnitStatus", statuses)
+}
+
+func (u *Unit) setStatuses(facadeCall string, statuses []status.StatusInfo) ([]error, error) {
+	args := params.SetStatus{
+		Entities: make([]params.EntityStatusArgs, len(statuses)),
+	}
+	for i, info := range statuses {
+		args.Entities[i] = params.EntityStatusArgs{
+			Tag:    u.tag.String(),
+			Status: info.Status.String(),
+			Info:   info.Message,
+			Data:   info.Data,
+			Since:  info.Since,
+		}
+	}
+	var result params.ErrorResults
+	if err := u.st.facade.FacadeCall(facadeCall, args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(result.Results) != len(statuses) {
+		return nil, errors.Errorf("expected %d results, got %d", len(statuses), len(result.Results))
+	}
+	errs := make([]error, len(statuses))
+	for i, res := range result.Results {
+		if res.Error != nil {
+			errs[i] = res.Error
+		}
+	}
+	return errs, nil
+}
+
 // AddMetrics adds the metrics for the unit.
 func (u *Unit) AddMetrics(metrics []params.Metric) error {
 	var result params.ErrorResults
@@ -457,25 +509,32 @@ func (u *Unit) AvailabilityZone() (string, error) {
 // OpenPorts sets the policy of the port range with protocol to be
 // opened.
 func (u *Unit) OpenPorts(protocol string, fromPort, toPort int) error {
-	var result params.ErrorResults
-	args := params.EntitiesPortRanges{
-		Entities: []params.EntityPortRange{{
-			Tag:      u.tag.String(),
-			Protocol: protocol,
-			FromPort: fromPort,
-			ToPort:   toPort,
-		}},
-	}
-	err := u.st.facade.FacadeCall("OpenPorts", args, &result)
-	if err != nil {
-		return err
-	}
-	return result.OneError()
+	return u.OpenPortsForEndpoint("", protocol, fromPort, toPort)
+}
+
+// OpenPortsForEndpoint sets the policy of the port range with protocol
+// to be opened for the named endpoint of the unit's application. An
+// empty endpoint opens the port range for all endpoints.
+func (u *Unit) OpenPortsForEndpoint(endpoint, protocol string, fromPort, toPort int) error {
+	return u.setPortsPolicy("OpenPorts", endpoint, protocol, fromPort, toPort)
 }
 
 // ClosePorts sets the policy of the port range with protocol to be
 // closed.
 func (u *Unit) ClosePorts(protocol string, fromPort, toPort int) error {
+	return u.ClosePortsForEndpoint("", protocol, fromPort, toPort)
+}
+
+// ClosePortsForEndpoint sets the policy of the port range with protocol
+// opened for the named endpoint of the unit's application to be closed.
+func (u *Unit) ClosePortsForEndpoint(endpoint, protocol string, fromPort, toPort int) error {
+	return u.setPortsPolicy("ClosePorts", endpoint, protocol, fromPort, toPort)
+}
+
+func (u *Unit) setPortsPolicy(method, endpoint, protocol string, fromPort, toPort int) error {
+	if endpoint != "" && u.st.facade.BestAPIVersion() < 8 {
+		return errors.NotSupportedf("opening or closing ports for endpoint %q", endpoint)
+	}
 	var result params.ErrorResults
 	args := params.EntitiesPortRanges{
 		Entities: []params.EntityPortRange{{
@@ -483,9 +542,10 @@ func (u *Unit) ClosePorts(protocol string, fromPort, toPort int) error {
 			Protocol: protocol,
 			FromPort: fromPort,
 			ToPort:   toPort,
+			Endpoint: endpoint,
 		}},
 	}
-	err := u.st.facade.FacadeCall("ClosePorts", args, &result)
+	err := u.st.facade.FacadeCall(method, args, &result)
 	if err != nil {
 		return err
 	}
@@ -91,6 +91,58 @@ func (s *unitSuite) TestSetAgentStatus(c *gc.C) {
 	c.Assert(unitStatusInfo.Data, gc.HasLen, 0)
 }
 
+func (s *unitSuite) TestSetAgentStatuses(c *gc.C) {
+	earlier := time.Now().Add(-time.Minute).Round(time.Millisecond)
+	later := earlier.Add(time.Second)
+	errs, err := s.apiUnit.SetAgentStatuses([]status.StatusInfo{{
+		Status:  status.Executing,
+		Message: "running config-changed hook",
+		Since:   &earlier,
+	}, {
+		Status: status.Status("bogus"),
+	}, {
+		Status: status.Idle,
+		Since:  &later,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, gc.HasLen, 3)
+	c.Assert(errs[0], jc.ErrorIsNil)
+	c.Assert(errs[1], gc.ErrorMatches, `cannot set status "bogus"`)
+	c.Assert(errs[2], jc.ErrorIsNil)
+
+	statusInfo, err := s.wordpressUnit.AgentStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Status, gc.Equals, status.Idle)
+	c.Assert(statusInfo.Since.Equal(later), jc.IsTrue)
+
+	history, err := s.wordpressUnit.AgentHistory().StatusHistory(status.StatusHistoryFilter{Size: 2})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[1].Message, gc.Equals, "running config-changed hook")
+}
+
+func (s *unitSuite) TestSetUnitStatuses(c *gc.C) {
+	earlier := time.Now().Add(-time.Minute).Round(time.Millisecond)
+	later := earlier.Add(time.Second)
+	errs, err := s.apiUnit.SetUnitStatuses([]status.StatusInfo{{
+		Status:  status.Maintenance,
+		Message: "installing",
+		Since:   &earlier,
+	}, {
+		Status:  status.Active,
+		Message: "ready",
+		Since:   &later,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil, nil})
+
+	statusInfo, err := s.wordpressUnit.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Status, gc.Equals, status.Active)
+	c.Assert(statusInfo.Message, gc.Equals, "ready")
+	c.Assert(statusInfo.Since.Equal(later), jc.IsTrue)
+}
+
 func (s *unitSuite) TestSetUnitStatus(c *gc.C) {
 	statusInfo, err := s.wordpressUnit.Status()
 	c.Assert(err, jc.ErrorIsNil)
@@ -447,6 +499,24 @@ func (s *unitSuite) TestOpenClosePortRanges(c *gc.C) {
 	c.Assert(ports, gc.HasLen, 0)
 }
 
+func (s *unitSuite) TestOpenClosePortsForEndpoint(c *gc.C) {
+	err := s.apiUnit.OpenPortsForEndpoint("url", "tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ports, err := s.wordpressUnit.OpenedPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports, gc.DeepEquals, []network.PortRange{
+		{Protocol: "tcp", FromPort: 80, ToPort: 80},
+	})
+
+	err = s.apiUnit.ClosePortsForEndpoint("url", "tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ports, err = s.wordpressUnit.OpenedPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports, gc.HasLen, 0)
+}
+
 func (s *unitSuite) TestGetSetCharmURL(c *gc.C) {
 	// No charm URL set yet.
 	curl, ok := s.wordpressUnit.CharmURL()
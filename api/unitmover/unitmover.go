@@ -0,0 +1,51 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package unitmover
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/watcher"
+)
+
+const apiName = "UnitMover"
+
+// Facade provides access to the UnitMover API.
+type Facade struct {
+	caller base.FacadeCaller
+}
+
+// NewFacade returns a new UnitMover Facade.
+func NewFacade(caller base.APICaller) *Facade {
+	return &Facade{caller: base.NewFacadeCaller(caller, apiName)}
+}
+
+// WatchUnitMoves returns a watcher that notifies when unit moves are
+// started or change phase.
+func (f *Facade) WatchUnitMoves() (watcher.NotifyWatcher, error) {
+	var result params.NotifyWatchResult
+	if err := f.caller.FacadeCall("WatchUnitMoves", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewNotifyWatcher(f.caller.RawAPICaller(), result), nil
+}
+
+// AdvanceUnitMoves moves each unfinished unit move on as far as it can
+// go, and returns the number of moves still unfinished.
+func (f *Facade) AdvanceUnitMoves() (int, error) {
+	var result params.IntResult
+	if err := f.caller.FacadeCall("AdvanceUnitMoves", nil, &result); err != nil {
+		return 0, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.Result, nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package unitmover_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/unitmover"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type UnitMoverSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&UnitMoverSuite{})
+
+func (s *UnitMoverSuite) TestAdvanceUnitMoves(c *gc.C) {
+	var stub testing.Stub
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		stub.AddCall(objType+"."+request, arg)
+		*(result.(*params.IntResult)) = params.IntResult{Result: 3}
+		return stub.NextErr()
+	})
+	active, err := unitmover.NewFacade(apiCaller).AdvanceUnitMoves()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(active, gc.Equals, 3)
+	stub.CheckCalls(c, []testing.StubCall{{"UnitMover.AdvanceUnitMoves", []interface{}{nil}}})
+}
+
+func (s *UnitMoverSuite) TestAdvanceUnitMovesError(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("boom")
+	})
+	_, err := unitmover.NewFacade(apiCaller).AdvanceUnitMoves()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *UnitMoverSuite) TestWatchUnitMovesError(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "WatchUnitMoves")
+		*(result.(*params.NotifyWatchResult)) = params.NotifyWatchResult{
+			Error: &params.Error{Message: "boom"},
+		}
+		return nil
+	})
+	_, err := unitmover.NewFacade(apiCaller).WatchUnitMoves()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,109 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package unitmoves
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+)
+
+// Client allows access to the unit moves API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the unit moves api.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "UnitMoves")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// MoveUnit starts moving the named unit to the machine chosen by the
+// placement directive, or to a new machine if placement is nil.
+func (c *Client) MoveUnit(unitName string, placement *instance.Placement) (params.UnitMove, error) {
+	if !names.IsValidUnit(unitName) {
+		return params.UnitMove{}, errors.NotValidf("unit name %q", unitName)
+	}
+	args := params.MoveUnitArgs{
+		Units: []params.MoveUnitArg{{
+			UnitTag:   names.NewUnitTag(unitName).String(),
+			Placement: placement,
+		}},
+	}
+	return c.call("MoveUnits", args)
+}
+
+// UnitMove returns the progress of the most recent move of the named
+// unit.
+func (c *Client) UnitMove(unitName string) (params.UnitMove, error) {
+	if !names.IsValidUnit(unitName) {
+		return params.UnitMove{}, errors.NotValidf("unit name %q", unitName)
+	}
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewUnitTag(unitName).String()}},
+	}
+	return c.call("UnitMoves", args)
+}
+
+func (c *Client) call(method string, args interface{}) (params.UnitMove, error) {
+	var results params.UnitMoveResults
+	if err := c.facade.FacadeCall(method, args, &results); err != nil {
+		return params.UnitMove{}, errors.Trace(err)
+	}
+	if n := len(results.Results); n != 1 {
+		return params.UnitMove{}, errors.Errorf("expected 1 result, got %d", n)
+	}
+	if err := results.Results[0].Error; err != nil {
+		return params.UnitMove{}, err
+	}
+	return *results.Results[0].Result, nil
+}
+
+// EvacuateMachine starts moving the units off the machine with the
+// given id, so that it can be removed. Units are moved to the machines
+// chosen by the placement directive, or to new machines if placement
+// is nil.
+func (c *Client) EvacuateMachine(machineId string, placement *instance.Placement) (params.MachineEvacuation, error) {
+	if !names.IsValidMachine(machineId) {
+		return params.MachineEvacuation{}, errors.NotValidf("machine ID %q", machineId)
+	}
+	args := params.EvacuateMachineArgs{
+		Machines: []params.EvacuateMachineArg{{
+			MachineTag: names.NewMachineTag(machineId).String(),
+			Placement:  placement,
+		}},
+	}
+	return c.callEvacuation("EvacuateMachines", args)
+}
+
+// MachineEvacuation returns the progress of the evacuation of the
+// machine with the given id.
+func (c *Client) MachineEvacuation(machineId string) (params.MachineEvacuation, error) {
+	if !names.IsValidMachine(machineId) {
+		return params.MachineEvacuation{}, errors.NotValidf("machine ID %q", machineId)
+	}
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewMachineTag(machineId).String()}},
+	}
+	return c.callEvacuation("MachineEvacuations", args)
+}
+
+func (c *Client) callEvacuation(method string, args interface{}) (params.MachineEvacuation, error) {
+	var results params.MachineEvacuationResults
+	if err := c.facade.FacadeCall(method, args, &results); err != nil {
+		return params.MachineEvacuation{}, errors.Trace(err)
+	}
+	if n := len(results.Results); n != 1 {
+		return params.MachineEvacuation{}, errors.Errorf("expected 1 result, got %d", n)
+	}
+	if err := results.Results[0].Error; err != nil {
+		return params.MachineEvacuation{}, err
+	}
+	return *results.Results[0].Result, nil
+}
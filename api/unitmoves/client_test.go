@@ -0,0 +1,125 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package unitmoves_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/unitmoves"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/testing"
+)
+
+type UnitMovesSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&UnitMovesSuite{})
+
+func (s *UnitMovesSuite) TestMoveUnit(c *gc.C) {
+	placement := &instance.Placement{Scope: instance.MachineScope, Directive: "3"}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "UnitMoves")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "MoveUnits")
+			c.Check(a, jc.DeepEquals, params.MoveUnitArgs{
+				Units: []params.MoveUnitArg{{UnitTag: "unit-mysql-0", Placement: placement}},
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.UnitMoveResults{})
+			*(result.(*params.UnitMoveResults)) = params.UnitMoveResults{
+				Results: []params.UnitMoveResult{{
+					Result: &params.UnitMove{
+						UnitTag:        "unit-mysql-0",
+						ReplacementTag: "unit-mysql-1",
+						Phase:          "provisioning",
+					},
+				}},
+			}
+			return nil
+		})
+
+	client := unitmoves.NewClient(apiCaller)
+	move, err := client.MoveUnit("mysql/0", placement)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(move, jc.DeepEquals, params.UnitMove{
+		UnitTag:        "unit-mysql-0",
+		ReplacementTag: "unit-mysql-1",
+		Phase:          "provisioning",
+	})
+}
+
+func (s *UnitMovesSuite) TestMoveUnitInvalidName(c *gc.C) {
+	client := unitmoves.NewClient(basetesting.APICallerFunc(
+		func(string, int, string, string, interface{}, interface{}) error {
+			c.Fatalf("unexpected API call")
+			return nil
+		}))
+	_, err := client.MoveUnit("mysql", nil)
+	c.Assert(err, gc.ErrorMatches, `unit name "mysql" not valid`)
+}
+
+func (s *UnitMovesSuite) TestUnitMoveError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(request, gc.Equals, "UnitMoves")
+			c.Check(a, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "unit-mysql-0"}},
+			})
+			*(result.(*params.UnitMoveResults)) = params.UnitMoveResults{
+				Results: []params.UnitMoveResult{{
+					Error: &params.Error{Code: params.CodeNotFound, Message: "move of unit \"mysql/0\" not found"},
+				}},
+			}
+			return nil
+		})
+
+	client := unitmoves.NewClient(apiCaller)
+	_, err := client.UnitMove("mysql/0")
+	c.Assert(err, gc.ErrorMatches, `move of unit "mysql/0" not found`)
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func (s *UnitMovesSuite) TestEvacuateMachine(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "UnitMoves")
+			c.Check(request, gc.Equals, "EvacuateMachines")
+			c.Check(a, jc.DeepEquals, params.EvacuateMachineArgs{
+				Machines: []params.EvacuateMachineArg{{MachineTag: "machine-2"}},
+			})
+			*(result.(*params.MachineEvacuationResults)) = params.MachineEvacuationResults{
+				Results: []params.MachineEvacuationResult{{
+					Result: &params.MachineEvacuation{
+						MachineTag: "machine-2",
+						MoveTags:   []string{"unit-wordpress-0"},
+					},
+				}},
+			}
+			return nil
+		})
+
+	client := unitmoves.NewClient(apiCaller)
+	evacuation, err := client.EvacuateMachine("2", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(evacuation, jc.DeepEquals, params.MachineEvacuation{
+		MachineTag: "machine-2",
+		MoveTags:   []string{"unit-wordpress-0"},
+	})
+}
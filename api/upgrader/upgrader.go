@@ -93,6 +93,24 @@ func (st *State) Tools(tag string) (tools.List, error) {
 	return result.ToolsList, nil
 }
 
+// AgentBinaryTrustedKeys returns the armored OpenPGP public keys that
+// downloaded agent binaries must be signed with. It returns an empty
+// string when the controller does not require signed agent binaries,
+// or is too old to say.
+func (st *State) AgentBinaryTrustedKeys() (string, error) {
+	if st.facade.BestAPIVersion() < 2 {
+		return "", nil
+	}
+	var result params.StringResult
+	if err := st.facade.FacadeCall("AgentBinaryTrustedKeys", nil, &result); err != nil {
+		return "", err
+	}
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Result, nil
+}
+
 func (st *State) WatchAPIVersion(agentTag string) (watcher.NotifyWatcher, error) {
 	var results params.NotifyWatchResults
 	args := params.Entities{
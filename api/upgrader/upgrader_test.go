@@ -105,6 +105,12 @@ func (s *machineUpgraderSuite) TestTools(c *gc.C) {
 	c.Assert(stateTools.URL, gc.Equals, url)
 }
 
+func (s *machineUpgraderSuite) TestAgentBinaryTrustedKeys(c *gc.C) {
+	keys, err := s.st.AgentBinaryTrustedKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, gc.Equals, "")
+}
+
 func (s *machineUpgraderSuite) TestWatchAPIVersion(c *gc.C) {
 	w, err := s.st.WatchAPIVersion(s.rawMachine.Tag().String())
 	c.Assert(err, jc.ErrorIsNil)
@@ -207,3 +207,146 @@ func (c *Client) ResetPassword(username string) ([]byte, error) {
 	}
 	return result.SecretKey, nil
 }
+
+// AddTeam adds a team, with no members, to the controller.
+func (c *Client) AddTeam(name string) error {
+	return c.teamCall("AddTeams", name)
+}
+
+// RemoveTeam removes a team from the controller.
+func (c *Client) RemoveTeam(name string) error {
+	return c.teamCall("RemoveTeams", name)
+}
+
+func (c *Client) teamCall(methodCall, name string) error {
+	if c.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("teams on this Juju controller")
+	}
+	var results params.ErrorResults
+	args := params.TeamNames{Names: []string{name}}
+	if err := c.facade.FacadeCall(methodCall, args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// AddTeamMember adds a local user to a team.
+func (c *Client) AddTeamMember(team, username string) error {
+	return c.modifyTeamMember(team, params.AddTeamMember, username)
+}
+
+// RemoveTeamMember removes a user from a team.
+func (c *Client) RemoveTeamMember(team, username string) error {
+	return c.modifyTeamMember(team, params.RemoveTeamMember, username)
+}
+
+func (c *Client) modifyTeamMember(team string, action params.TeamAction, username string) error {
+	if c.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("teams on this Juju controller")
+	}
+	if !names.IsValidUser(username) {
+		return errors.Errorf("%q is not a valid username", username)
+	}
+	args := params.ModifyTeamMembersRequest{
+		Changes: []params.ModifyTeamMember{{
+			Team:    team,
+			Action:  action,
+			UserTag: names.NewUserTag(username).String(),
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("ModifyTeamMembers", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// Teams returns information on all the teams on the controller.
+func (c *Client) Teams() ([]params.TeamInfo, error) {
+	if c.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("teams on this Juju controller")
+	}
+	var results params.TeamInfoResults
+	if err := c.facade.FacadeCall("Teams", nil, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
+// ListSessions returns the API sessions of the given users on every API
+// server of the controller, and the IDs of the API servers that did not
+// report their sessions in time. If no users are given, the sessions of
+// all users are returned; only controller superusers may see the
+// sessions of other users.
+func (c *Client) ListSessions(usernames ...string) ([]params.SessionInfo, []string, error) {
+	if c.BestAPIVersion() < 4 {
+		return nil, nil, errors.NotSupportedf("listing API sessions on this Juju controller")
+	}
+	args := params.ListSessionsRequest{}
+	for _, name := range usernames {
+		if !names.IsValidUser(name) {
+			return nil, nil, errors.Errorf("%q is not a valid username", name)
+		}
+		args.UserTags = append(args.UserTags, names.NewUserTag(name).String())
+	}
+	var results params.SessionInfoResults
+	if err := c.facade.FacadeCall("ListSessions", args, &results); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return results.Results, results.Unreachable, nil
+}
+
+// RevokeSessions ends the identified API sessions, and all the sessions
+// of the given users, on every API server of the controller.
+func (c *Client) RevokeSessions(sessionIDs []string, usernames []string) error {
+	if c.BestAPIVersion() < 4 {
+		return errors.NotSupportedf("revoking API sessions on this Juju controller")
+	}
+	args := params.RevokeSessionsRequest{SessionIDs: sessionIDs}
+	for _, name := range usernames {
+		if !names.IsValidUser(name) {
+			return errors.Errorf("%q is not a valid username", name)
+		}
+		args.UserTags = append(args.UserTags, names.NewUserTag(name).String())
+	}
+	return errors.Trace(c.facade.FacadeCall("RevokeSessions", args, nil))
+}
+
+// ListLoginLockouts returns the recent failed logins of the given users
+// seen by the API server the client is connected to, including those
+// for which the users are locked out. If no users are given, those of
+// all users are returned.
+func (c *Client) ListLoginLockouts(usernames ...string) ([]params.LoginLockoutInfo, error) {
+	if c.BestAPIVersion() < 5 {
+		return nil, errors.NotSupportedf("listing login lockouts on this Juju controller")
+	}
+	args := params.ListLoginLockoutsRequest{}
+	for _, name := range usernames {
+		if !names.IsValidUser(name) {
+			return nil, errors.Errorf("%q is not a valid username", name)
+		}
+		args.UserTags = append(args.UserTags, names.NewUserTag(name).String())
+	}
+	var results params.LoginLockoutResults
+	if err := c.facade.FacadeCall("ListLoginLockouts", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
+// ClearLoginLockouts forgets the failed logins of the given users from
+// any address, and of any user from the given addresses, so that they
+// may log in again immediately.
+func (c *Client) ClearLoginLockouts(usernames []string, addresses []string) error {
+	if c.BestAPIVersion() < 5 {
+		return errors.NotSupportedf("clearing login lockouts on this Juju controller")
+	}
+	args := params.ClearLoginLockoutsRequest{Addresses: addresses}
+	for _, name := range usernames {
+		if !names.IsValidUser(name) {
+			return errors.Errorf("%q is not a valid username", name)
+		}
+		args.UserTags = append(args.UserTags, names.NewUserTag(name).String())
+	}
+	return errors.Trace(c.facade.FacadeCall("ClearLoginLockouts", args, nil))
+}
@@ -270,3 +270,132 @@ func (s *usermanagerSuite) TestResetPasswordResultCount(c *gc.C) {
 	_, err := client.ResetPassword("foobar")
 	c.Assert(err, gc.ErrorMatches, "expected 1 result, got 2")
 }
+
+func (s *usermanagerSuite) TestTeams(c *gc.C) {
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "alice"})
+	err := s.usermanager.AddTeam("ops")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.usermanager.AddTeamMember("ops", "alice")
+	c.Assert(err, jc.ErrorIsNil)
+
+	teams, err := s.usermanager.Teams()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(teams, gc.HasLen, 1)
+	c.Assert(teams[0].Name, gc.Equals, "ops")
+	c.Assert(teams[0].Members, jc.DeepEquals, []string{"user-alice"})
+
+	err = s.usermanager.RemoveTeamMember("ops", "alice")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.usermanager.RemoveTeam("ops")
+	c.Assert(err, jc.ErrorIsNil)
+	teams, err = s.usermanager.Teams()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(teams, gc.HasLen, 0)
+}
+
+func (s *usermanagerSuite) TestAddTeamMemberInvalidUsername(c *gc.C) {
+	err := s.usermanager.AddTeamMember("ops", "not/valid")
+	c.Assert(err, gc.ErrorMatches, `"not/valid" is not a valid username`)
+}
+
+func (s *usermanagerSuite) TestTeamsNotSupported(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 2,
+		APICallerFunc: apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		}),
+	}
+	client := usermanager.NewClient(apiCaller)
+	err := client.AddTeam("ops")
+	c.Assert(err, gc.ErrorMatches, "teams on this Juju controller not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *usermanagerSuite) TestListSessions(c *gc.C) {
+	sessions, unreachable, err := s.usermanager.ListSessions("admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unreachable, gc.HasLen, 0)
+	c.Assert(sessions, gc.Not(gc.HasLen), 0)
+	for _, session := range sessions {
+		c.Check(session.UserTag, gc.Equals, s.AdminUserTag(c).String())
+	}
+}
+
+func (s *usermanagerSuite) TestRevokeSessions(c *gc.C) {
+	var called bool
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 4,
+		APICallerFunc: apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			called = true
+			c.Check(objType, gc.Equals, "UserManager")
+			c.Check(request, gc.Equals, "RevokeSessions")
+			c.Check(arg, jc.DeepEquals, params.RevokeSessionsRequest{
+				SessionIDs: []string{"0:1"},
+				UserTags:   []string{"user-bob"},
+			})
+			return nil
+		}),
+	}
+	client := usermanager.NewClient(apiCaller)
+	err := client.RevokeSessions([]string{"0:1"}, []string{"bob"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *usermanagerSuite) TestSessionsNotSupported(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 3,
+		APICallerFunc: apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		}),
+	}
+	client := usermanager.NewClient(apiCaller)
+	_, _, err := client.ListSessions()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = client.RevokeSessions(nil, []string{"bob"})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *usermanagerSuite) TestListLoginLockouts(c *gc.C) {
+	lockouts, err := s.usermanager.ListLoginLockouts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(lockouts, gc.HasLen, 0)
+}
+
+func (s *usermanagerSuite) TestClearLoginLockouts(c *gc.C) {
+	var called bool
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 5,
+		APICallerFunc: apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			called = true
+			c.Check(objType, gc.Equals, "UserManager")
+			c.Check(request, gc.Equals, "ClearLoginLockouts")
+			c.Check(arg, jc.DeepEquals, params.ClearLoginLockoutsRequest{
+				UserTags:  []string{"user-bob"},
+				Addresses: []string{"10.0.0.1"},
+			})
+			return nil
+		}),
+	}
+	client := usermanager.NewClient(apiCaller)
+	err := client.ClearLoginLockouts([]string{"bob"}, []string{"10.0.0.1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *usermanagerSuite) TestLoginLockoutsNotSupported(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 4,
+		APICallerFunc: apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		}),
+	}
+	client := usermanager.NewClient(apiCaller)
+	_, err := client.ListLoginLockouts()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = client.ClearLoginLockouts([]string{"bob"}, nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -11,6 +11,8 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/clock"
+	"github.com/juju/utils/featureflag"
+	"github.com/juju/utils/set"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api"
@@ -20,6 +22,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/agent/presence"
 	"github.com/juju/juju/apiserver/observer"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/rpcreflect"
@@ -109,6 +112,10 @@ func (a *admin) login(req params.LoginRequest, loginVersion int) (params.LoginRe
 	var apiRoot rpc.Root = newAPIRoot(
 		a.root.state,
 		a.srv.statePool,
+		a.srv.modelCache,
+		a.srv.spanExporter,
+		a.srv.sessions,
+		a.srv.lockouts,
 		a.srv.facades,
 		a.root.resources,
 		a.root,
@@ -125,6 +132,7 @@ func (a *admin) login(req params.LoginRequest, loginVersion int) (params.LoginRe
 
 	var facadeFilters []facadeFilterFunc
 	var modelTag string
+	modelFeatures := set.NewStrings()
 	if authResult.anonymousLogin {
 		facadeFilters = append(facadeFilters, IsAnonymousFacade)
 	}
@@ -133,6 +141,11 @@ func (a *admin) login(req params.LoginRequest, loginVersion int) (params.LoginRe
 	} else {
 		facadeFilters = append(facadeFilters, IsModelFacade)
 		modelTag = a.root.model.Tag().String()
+		cfg, err := a.root.state.ModelConfig()
+		if err != nil {
+			return fail, errors.Trace(err)
+		}
+		modelFeatures = cfg.Features()
 	}
 
 	a.root.rpcConn.ServeRoot(apiRoot, serverError)
@@ -143,7 +156,7 @@ func (a *admin) login(req params.LoginRequest, loginVersion int) (params.LoginRe
 		ServerVersion: jujuversion.Current.String(),
 		PublicDNSName: a.srv.publicDNSName(),
 		ModelTag:      modelTag,
-		Facades:       filterFacades(a.srv.facades, facadeFilters...),
+		Facades:       filterFacades(a.srv.facades, modelFeatures, facadeFilters...),
 	}, nil
 }
 
@@ -208,7 +221,17 @@ func (a *admin) authenticate(req params.LoginRequest) (*authResult, error) {
 		startPinger    = true
 	)
 	if !result.anonymousLogin {
+		userTag, isUser := result.tag.(names.UserTag)
+		if isUser {
+			if err := a.srv.lockouts.Check(userTag, a.root.remoteAddr); err != nil {
+				logger.Debugf("refusing login by %s from %s: %v", userTag.Id(), a.root.remoteAddr, err)
+				return nil, errors.Trace(err)
+			}
+		}
 		entity, lastConnection, err = a.checkCreds(req, result.tag, result.userLogin)
+		if isUser {
+			a.recordUserLogin(userTag, err)
+		}
 		if err != nil {
 			// If above login fails, we may still be a login to a controller
 			// machine in the controller model.
@@ -220,6 +243,11 @@ func (a *admin) authenticate(req params.LoginRequest) (*authResult, error) {
 			// agents when logging into the controller model.
 			startPinger = false
 		}
+		if !result.userLogin && startPinger {
+			if err := checkAgentDisconnectFault(a.root.state); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
 	}
 	a.loggedIn = true
 
@@ -251,6 +279,26 @@ func (a *admin) authenticate(req params.LoginRequest) (*authResult, error) {
 	return result, nil
 }
 
+// recordUserLogin records the outcome of a user's attempt to log in
+// with a password, so that users that repeatedly fail are throttled and
+// then locked out. Failures are held for a while before they are
+// reported, to slow down password guessing.
+func (a *admin) recordUserLogin(user names.UserTag, err error) {
+	switch {
+	case err == nil:
+		a.srv.lockouts.Succeeded(user, a.root.remoteAddr)
+	case errors.Cause(err) == common.ErrBadCreds:
+		pause := a.srv.lockouts.Failed(user, a.root.remoteAddr)
+		if pause <= 0 {
+			return
+		}
+		select {
+		case <-a.srv.clock.After(pause):
+		case <-a.srv.tomb.Dying():
+		}
+	}
+}
+
 func (a *admin) handleAuthError(
 	req params.LoginRequest,
 	authTag names.Tag,
@@ -386,8 +434,11 @@ func (a *admin) checkUserPermissions(userTag names.UserTag, controllerOnlyLogin
 
 type facadeFilterFunc func(name string) bool
 
-func filterFacades(registry *facade.Registry, allowFacadeAllMustMatch ...facadeFilterFunc) []params.FacadeVersions {
-	allFacades := DescribeFacades(registry)
+// filterFacades returns the facades in the registry that are allowed
+// by every filter, leaving out those that need a model feature flag
+// that is not in modelFeatures.
+func filterFacades(registry *facade.Registry, modelFeatures set.Strings, allowFacadeAllMustMatch ...facadeFilterFunc) []params.FacadeVersions {
+	allFacades := describeFacades(registry.ListForModel(modelFeatures))
 	out := make([]params.FacadeVersions, 0, len(allFacades))
 	for _, f := range allFacades {
 		allowed := false
@@ -403,6 +454,22 @@ func filterFacades(registry *facade.Registry, allowFacadeAllMustMatch ...facadeF
 	return out
 }
 
+// checkAgentDisconnectFault refuses logins by a model's agents while
+// an agent-disconnect fault is injected into the model.
+func checkAgentDisconnectFault(st *state.State) error {
+	if !featureflag.Enabled(feature.FaultInjection) {
+		return nil
+	}
+	fault, err := st.ActiveFault(state.FaultAgentDisconnect)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	logger.Debugf("refusing agent login: %s fault injected until %v", fault.Kind, fault.Expires)
+	return common.ErrTryAgain
+}
+
 func (a *admin) checkCreds(req params.LoginRequest, authTag names.Tag, userLogin bool) (state.Entity, *time.Time, error) {
 	return doCheckCreds(a.root.state, req, authTag, userLogin, a.authenticator())
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/juju/juju/apiserver"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facades/client/controller"
+	"github.com/juju/juju/apiserver/lockout"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
 	jujutesting "github.com/juju/juju/juju/testing"
@@ -139,6 +140,37 @@ func (s *loginSuite) TestBadLogin(c *gc.C) {
 	}
 }
 
+func (s *loginSuite) TestLoginLockout(c *gc.C) {
+	cfg := defaultServerConfig(c)
+	cfg.LoginLockout = lockout.Policy{
+		MaxFailures: 2,
+		Duration:    time.Hour,
+	}
+	info, srv := newServerWithConfig(c, s.pool, cfg)
+	defer assertStop(c, srv)
+	info.ModelTag = s.IAASModel.ModelTag()
+	u := s.Factory.MakeUser(c, &factory.UserParams{Password: "password"})
+
+	login := func(password string) error {
+		st := s.openAPIWithoutLogin(c, info)
+		return st.Login(u.Tag(), password, "", nil)
+	}
+	for i := 0; i < 2; i++ {
+		err := login("wrong password")
+		assertInvalidEntityPassword(c, err)
+	}
+
+	// Now the user is locked out, even the right password is refused.
+	err := login("password")
+	c.Assert(err, gc.ErrorMatches, "too many failed logins; try again in .*")
+	c.Assert(params.ErrCode(err), gc.Equals, params.CodeUnauthorized)
+
+	// Other users are not locked out.
+	st := s.openAPIWithoutLogin(c, info)
+	err = st.Login(s.AdminUserTag(c), "dummy-secret", "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *loginSuite) TestLoginAsDeactivatedUser(c *gc.C) {
 	info, srv := newServer(c, s.pool)
 	defer assertStop(c, srv)
@@ -631,6 +663,25 @@ func (s *loginSuite) TestControllerModel(c *gc.C) {
 	s.assertRemoteModel(c, st, s.IAASModel.ModelTag())
 }
 
+func (s *loginSuite) TestModelFeatureFacadesAdvertised(c *gc.C) {
+	info, srv := newServer(c, s.pool)
+	defer assertStop(c, srv)
+	info.ModelTag = s.IAASModel.ModelTag()
+	adminUser := s.AdminUserTag(c)
+
+	st := s.openAPIWithoutLogin(c, info)
+	err := st.Login(adminUser, "dummy-secret", "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(st.AllFacadeVersions(), gc.Not(jc.HasKey), "ImageMetadataManager")
+
+	err = s.IAASModel.UpdateModelConfig(map[string]interface{}{"features": "image-metadata"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	st = s.openAPIWithoutLogin(c, info)
+	err = st.Login(adminUser, "dummy-secret", "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(st.AllFacadeVersions()["ImageMetadataManager"], jc.DeepEquals, []int{1})
+}
+
 func (s *loginSuite) TestControllerModelBadCreds(c *gc.C) {
 	info, srv := newServer(c, s.pool)
 	defer assertStop(c, srv)
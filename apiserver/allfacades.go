@@ -26,6 +26,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/agent/metricsadder"
 	"github.com/juju/juju/apiserver/facades/agent/migrationflag"
 	"github.com/juju/juju/apiserver/facades/agent/migrationminion"
+	"github.com/juju/juju/apiserver/facades/agent/packageupdatereporter"
 	"github.com/juju/juju/apiserver/facades/agent/payloadshookcontext"
 	"github.com/juju/juju/apiserver/facades/agent/provisioner"
 	"github.com/juju/juju/apiserver/facades/agent/proxyupdater"
@@ -33,43 +34,61 @@ import (
 	"github.com/juju/juju/apiserver/facades/agent/resourceshookcontext"
 	"github.com/juju/juju/apiserver/facades/agent/retrystrategy"
 	"github.com/juju/juju/apiserver/facades/agent/storageprovisioner"
+	"github.com/juju/juju/apiserver/facades/agent/tracing"
 	"github.com/juju/juju/apiserver/facades/agent/unitassigner"
 	"github.com/juju/juju/apiserver/facades/agent/uniter"
 	"github.com/juju/juju/apiserver/facades/agent/upgrader"
 	"github.com/juju/juju/apiserver/facades/client/action"
+	"github.com/juju/juju/apiserver/facades/client/adoption"
+	"github.com/juju/juju/apiserver/facades/client/agentversionpins"
 	"github.com/juju/juju/apiserver/facades/client/annotations" // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/application" // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/applicationoffers"
 	"github.com/juju/juju/apiserver/facades/client/backups" // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/block"   // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/bundle"
-	"github.com/juju/juju/apiserver/facades/client/charms"     // ModelUser Write
-	"github.com/juju/juju/apiserver/facades/client/client"     // ModelUser Write
-	"github.com/juju/juju/apiserver/facades/client/cloud"      // ModelUser Read
+	"github.com/juju/juju/apiserver/facades/client/charms" // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/chatsummary"
+	"github.com/juju/juju/apiserver/facades/client/client" // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/cloud"  // ModelUser Read
+	"github.com/juju/juju/apiserver/facades/client/configprofiles"
 	"github.com/juju/juju/apiserver/facades/client/controller" // ModelUser Admin (although some methods check for read only)
+	"github.com/juju/juju/apiserver/facades/client/crossmodeldiagnostics"
+	"github.com/juju/juju/apiserver/facades/client/faultinjection"
 	"github.com/juju/juju/apiserver/facades/client/firewallrules"
 	"github.com/juju/juju/apiserver/facades/client/highavailability" // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/imagemanager"     // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/imagemetadatamanager"
-	"github.com/juju/juju/apiserver/facades/client/keymanager"     // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/inventory"
+	"github.com/juju/juju/apiserver/facades/client/keymanager" // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/leadershipreport"
 	"github.com/juju/juju/apiserver/facades/client/machinemanager" // ModelUser Write
-	"github.com/juju/juju/apiserver/facades/client/metricsdebug"   // ModelUser Write
-	"github.com/juju/juju/apiserver/facades/client/modelconfig"    // ModelUser Write
-	"github.com/juju/juju/apiserver/facades/client/modelmanager"   // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/machinepool"
+	"github.com/juju/juju/apiserver/facades/client/metricsdebug" // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/modelconfig"  // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/modelmanager" // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/offercatalog"
+	"github.com/juju/juju/apiserver/facades/client/operationstimeline"
+	"github.com/juju/juju/apiserver/facades/client/orphanedresources"
 	"github.com/juju/juju/apiserver/facades/client/payloads"
+	"github.com/juju/juju/apiserver/facades/client/relationdata"
 	"github.com/juju/juju/apiserver/facades/client/resources"
-	"github.com/juju/juju/apiserver/facades/client/spaces"    // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/spaces" // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/spacetopology"
 	"github.com/juju/juju/apiserver/facades/client/sshclient" // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/storage"
 	"github.com/juju/juju/apiserver/facades/client/subnets"
+	"github.com/juju/juju/apiserver/facades/client/unitmoves"
 	"github.com/juju/juju/apiserver/facades/client/usermanager"
 	"github.com/juju/juju/apiserver/facades/controller/actionpruner"
 	"github.com/juju/juju/apiserver/facades/controller/agenttools"
 	"github.com/juju/juju/apiserver/facades/controller/applicationscaler"
 	"github.com/juju/juju/apiserver/facades/controller/charmrevisionupdater"
 	"github.com/juju/juju/apiserver/facades/controller/cleaner"
+	"github.com/juju/juju/apiserver/facades/controller/controllerfirewaller"
 	"github.com/juju/juju/apiserver/facades/controller/crosscontroller"
 	"github.com/juju/juju/apiserver/facades/controller/crossmodelrelations"
+	"github.com/juju/juju/apiserver/facades/controller/ephemeralreaper"
 	"github.com/juju/juju/apiserver/facades/controller/externalcontrollerupdater"
 	"github.com/juju/juju/apiserver/facades/controller/firewaller"
 	"github.com/juju/juju/apiserver/facades/controller/imagemetadata"
@@ -84,8 +103,10 @@ import (
 	"github.com/juju/juju/apiserver/facades/controller/remoterelations"
 	"github.com/juju/juju/apiserver/facades/controller/resumer"
 	"github.com/juju/juju/apiserver/facades/controller/singular"
+	"github.com/juju/juju/apiserver/facades/controller/sshkeyimporter"
 	"github.com/juju/juju/apiserver/facades/controller/statushistory"
 	"github.com/juju/juju/apiserver/facades/controller/undertaker"
+	"github.com/juju/juju/apiserver/facades/controller/unitmover"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/state"
 )
@@ -111,6 +132,15 @@ func AllFacades() *facade.Registry {
 		}
 	}
 
+	// regForModelFeature registers a facade that is only available to
+	// models with the feature flag in their "features" config.
+	regForModelFeature := func(name string, version int, newFunc interface{}, flag string) {
+		err := registry.RegisterForModelFeature(name, version, newFunc, flag)
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	regHookContext := func(name string, version int, newHookContextFacade hookContextFacadeFn, facadeType reflect.Type) {
 		err := regHookContextFacade(registry, name, version, newHookContextFacade, facadeType)
 		if err != nil {
@@ -120,9 +150,11 @@ func AllFacades() *facade.Registry {
 
 	reg("Action", 2, action.NewActionAPI)
 	reg("ActionPruner", 1, actionpruner.NewAPI)
+	reg("Adoption", 1, adoption.NewFacade)
 	reg("Agent", 2, agent.NewAgentAPIV2)
 	reg("AgentTools", 1, agenttools.NewFacade)
-	reg("Annotations", 2, annotations.NewAPI)
+	reg("AgentVersionPins", 1, agentversionpins.NewFacade)
+	reg("Annotations", 2, annotations.NewFacade)
 
 	// Application facade versions 1-4 share NewFacadeV4 as
 	// the newer methodology for versioning wasn't started with
@@ -131,15 +163,20 @@ func AllFacades() *facade.Registry {
 	reg("Application", 2, application.NewFacadeV4)
 	reg("Application", 3, application.NewFacadeV4)
 	reg("Application", 4, application.NewFacadeV4)
-	reg("Application", 5, application.NewFacade) // adds AttachStorage & UpdateApplicationSeries & SetRelationStatus
+	reg("Application", 5, application.NewFacadeV5) // adds AttachStorage & UpdateApplicationSeries & SetRelationStatus
+	reg("Application", 6, application.NewFacadeV6) // adds GetAffinity & SetAffinity
+	reg("Application", 7, application.NewFacadeV7) // adds expose settings for endpoints
+	reg("Application", 8, application.NewFacade)   // adds RetainStorage to DestroyApplication
 
 	reg("ApplicationOffers", 1, applicationoffers.NewOffersAPI)
 	reg("ApplicationScaler", 1, applicationscaler.NewAPI)
-	reg("Backups", 1, backups.NewFacade)
+	reg("Backups", 1, backups.NewFacadeV1)
+	reg("Backups", 2, backups.NewFacade) // v2 adds Verify().
 	reg("Block", 2, block.NewAPI)
 	reg("Bundle", 1, bundle.NewFacade)
 	reg("CharmRevisionUpdater", 2, charmrevisionupdater.NewCharmRevisionUpdaterAPI)
 	reg("Charms", 2, charms.NewFacade)
+	reg("ChatSummary", 1, chatsummary.NewFacade)
 	reg("Cleaner", 2, cleaner.NewCleanerAPI)
 	reg("Client", 1, client.NewFacade)
 	reg("Cloud", 1, cloud.NewFacade)
@@ -147,31 +184,48 @@ func AllFacades() *facade.Registry {
 		reg("Cloud", 2, cloud.NewFacadeV2)
 	}
 
+	reg("ConfigProfiles", 1, configprofiles.NewFacade)
 	reg("Controller", 3, controller.NewControllerAPIv3)
 	reg("Controller", 4, controller.NewControllerAPIv4)
+	reg("ControllerFirewaller", 1, controllerfirewaller.NewFacade)
 	reg("CrossModelRelations", 1, crossmodelrelations.NewStateCrossModelRelationsAPI)
 	reg("CrossController", 1, crosscontroller.NewStateCrossControllerAPI)
+	reg("CrossModelDiagnostics", 1, crossmodeldiagnostics.NewFacade)
+	reg("EphemeralReaper", 1, ephemeralreaper.NewFacade)
 	reg("ExternalControllerUpdater", 1, externalcontrollerupdater.NewStateAPI)
 
 	reg("Deployer", 1, deployer.NewDeployerAPI)
 	reg("DiskManager", 2, diskmanager.NewDiskManagerAPI)
 	reg("FanConfigurer", 1, fanconfigurer.NewFanConfigurerAPI)
+	if featureflag.Enabled(feature.FaultInjection) {
+		reg("FaultInjection", 1, faultinjection.NewFacade)
+	}
 	reg("Firewaller", 3, firewaller.NewStateFirewallerAPIV3)
 	reg("Firewaller", 4, firewaller.NewStateFirewallerAPIV4)
+	reg("Firewaller", 5, firewaller.NewStateFirewallerAPIV5) // adds GetExposeInfo
+	reg("Firewaller", 6, firewaller.NewStateFirewallerAPIV6) // adds WatchAnnotations, GetHostnames
 	reg("FirewallRules", 1, firewallrules.NewFacade)
 	reg("HighAvailability", 2, highavailability.NewHighAvailabilityAPI)
 	reg("HostKeyReporter", 1, hostkeyreporter.NewFacade)
 	reg("ImageManager", 2, imagemanager.NewImageManagerAPI)
 	reg("ImageMetadata", 3, imagemetadata.NewAPI)
 
+	// Enabling the image-metadata feature for the controller makes
+	// ImageMetadataManager available to every model.
 	if featureflag.Enabled(feature.ImageMetadata) {
 		reg("ImageMetadataManager", 1, imagemetadatamanager.NewAPI)
+	} else {
+		regForModelFeature("ImageMetadataManager", 1, imagemetadatamanager.NewAPI, feature.ImageMetadata)
 	}
 
 	reg("InstancePoller", 3, instancepoller.NewFacade)
-	reg("KeyManager", 1, keymanager.NewKeyManagerAPI)
+	reg("Inventory", 1, inventory.NewFacade)
+	reg("KeyManager", 1, keymanager.NewKeyManagerAPIV1)
+	reg("KeyManager", 2, keymanager.NewKeyManagerAPI) // v2 adds per-user keys.
 	reg("KeyUpdater", 1, keyupdater.NewKeyUpdaterAPI)
+	reg("LeadershipReport", 1, leadershipreport.NewFacade)
 	reg("LeadershipService", 2, leadership.NewLeadershipServiceFacade)
+	reg("LeadershipService", 3, leadership.NewLeadershipServiceFacadeV3)
 	reg("LifeFlag", 1, lifeflag.NewExternalFacade)
 	reg("Logger", 1, loggerapi.NewLoggerAPI)
 	reg("LogForwarding", 1, logfwd.NewFacade)
@@ -180,6 +234,10 @@ func AllFacades() *facade.Registry {
 	reg("MachineManager", 2, machinemanager.NewFacade)
 	reg("MachineManager", 3, machinemanager.NewFacade)   // Version 3 adds DestroyMachine and ForceDestroyMachine.
 	reg("MachineManager", 4, machinemanager.NewFacadeV4) // Version 4 adds DestroyMachineWithParams.
+	reg("MachineManager", 5, machinemanager.NewFacadeV5) // Version 5 adds InstanceMetadata.
+	reg("MachineManager", 6, machinemanager.NewFacadeV6) // Version 6 adds PackageUpdates.
+
+	reg("MachinePool", 1, machinepool.NewFacade)
 
 	reg("MachineUndertaker", 1, machineundertaker.NewFacade)
 	reg("Machiner", 1, machine.NewMachinerAPI)
@@ -194,12 +252,25 @@ func AllFacades() *facade.Registry {
 	reg("MigrationMinion", 1, migrationminion.NewFacade)
 	reg("MigrationTarget", 1, migrationtarget.NewFacade)
 
-	reg("ModelConfig", 1, modelconfig.NewFacade)
+	reg("ModelConfig", 1, modelconfig.NewFacadeV1)
+	reg("ModelConfig", 2, modelconfig.NewFacadeV2) // Adds ConfigSchema.
+	reg("ModelConfig", 3, modelconfig.NewFacadeV3) // Adds ModelConfigHistory.
+	reg("ModelConfig", 4, modelconfig.NewFacadeV4) // ModelSet reports all config errors.
+	reg("ModelConfig", 5, modelconfig.NewFacadeV5) // ModelSet reports deprecated attributes.
 	reg("ModelManager", 2, modelmanager.NewFacadeV2)
 	reg("ModelManager", 3, modelmanager.NewFacadeV3)
 	reg("ModelManager", 4, modelmanager.NewFacadeV4)
+	reg("ModelManager", 5, modelmanager.NewFacadeV5) // Adds config profiles to CreateModel.
+	reg("ModelManager", 6, modelmanager.NewFacadeV6) // Adds TransferModelOwnership.
+	reg("ModelManager", 7, modelmanager.NewFacadeV7) // Adds SetModelExpiry.
 	reg("ModelUpgrader", 1, modelupgrader.NewStateFacade)
 
+	reg("OfferCatalog", 1, offercatalog.NewFacade)
+	reg("OperationsTimeline", 1, operationstimeline.NewFacade)
+	reg("OrphanedResources", 1, orphanedresources.NewFacade)
+
+	reg("PackageUpdateReporter", 1, packageupdatereporter.NewFacade)
+
 	reg("Payloads", 1, payloads.NewFacade)
 	regHookContext(
 		"PayloadsHookContext", 1,
@@ -211,8 +282,10 @@ func AllFacades() *facade.Registry {
 	reg("Provisioner", 3, provisioner.NewProvisionerAPI)
 	reg("Provisioner", 4, provisioner.NewProvisionerAPI)
 	reg("Provisioner", 5, provisioner.NewProvisionerAPIV5) // v5 adds DistributionGroupByMachineId()
+	reg("Provisioner", 6, provisioner.NewProvisionerAPIV6) // v6 adds ApplicationZones()
 	reg("ProxyUpdater", 1, proxyupdater.NewAPI)
 	reg("Reboot", 2, reboot.NewRebootAPI)
+	reg("RelationData", 1, relationdata.NewFacade)
 	reg("RemoteRelations", 1, remoterelations.NewStateRemoteRelationsAPI)
 
 	reg("Resources", 1, resources.NewPublicFacade)
@@ -228,9 +301,13 @@ func AllFacades() *facade.Registry {
 
 	reg("SSHClient", 1, sshclient.NewFacade)
 	reg("SSHClient", 2, sshclient.NewFacade) // v2 adds AllAddresses() method.
+	reg("SSHClient", 3, sshclient.NewFacade) // v3 adds Bastion() method.
+	reg("SSHClient", 4, sshclient.NewFacade) // v4 signals the ssh-tunnel endpoint.
+	reg("SSHKeyImporter", 1, sshkeyimporter.NewFacade)
 
 	reg("Spaces", 2, spaces.NewAPIV2)
 	reg("Spaces", 3, spaces.NewAPI)
+	reg("SpaceTopology", 1, spacetopology.NewFacade)
 
 	reg("StatusHistory", 2, statushistory.NewAPI)
 
@@ -240,17 +317,26 @@ func AllFacades() *facade.Registry {
 	reg("StorageProvisioner", 3, storageprovisioner.NewFacadeV3)
 	reg("StorageProvisioner", 4, storageprovisioner.NewFacadeV4)
 	reg("Subnets", 2, subnets.NewAPI)
+	reg("Tracing", 1, tracing.NewFacade)
 	reg("Undertaker", 1, undertaker.NewUndertakerAPI)
 	reg("UnitAssigner", 1, unitassigner.New)
+	reg("UnitMover", 1, unitmover.NewFacade)
+	reg("UnitMoves", 1, unitmoves.NewFacade)
 
 	reg("Uniter", 4, uniter.NewUniterAPIV4)
 	reg("Uniter", 5, uniter.NewUniterAPIV5)
 	reg("Uniter", 6, uniter.NewUniterAPIV6)
-	reg("Uniter", 7, uniter.NewUniterAPI)
-
-	reg("Upgrader", 1, upgrader.NewUpgraderFacade)
-	reg("UserManager", 1, usermanager.NewUserManagerAPI)
-	reg("UserManager", 2, usermanager.NewUserManagerAPI) // Adds ResetPassword
+	reg("Uniter", 7, uniter.NewUniterAPIV7)
+	reg("Uniter", 8, uniter.NewUniterAPIV8) // adds endpoints to OpenPorts and ClosePorts
+	reg("Uniter", 9, uniter.NewUniterAPI)   // adds relation departure barriers
+
+	reg("Upgrader", 1, upgrader.NewUpgraderFacadeV1)
+	reg("Upgrader", 2, upgrader.NewUpgraderFacade)
+	reg("UserManager", 1, usermanager.NewUserManagerAPIV2)
+	reg("UserManager", 2, usermanager.NewUserManagerAPIV2) // Adds ResetPassword
+	reg("UserManager", 3, usermanager.NewUserManagerAPIV3) // Adds teams
+	reg("UserManager", 4, usermanager.NewFacadeV4)         // Adds ListSessions and RevokeSessions.
+	reg("UserManager", 5, usermanager.NewFacade)           // Adds ListLoginLockouts and ClearLoginLockouts.
 
 	regRaw("AllWatcher", 1, NewAllWatcher, reflect.TypeOf((*SrvAllWatcher)(nil)))
 	// Note: AllModelWatcher uses the same infrastructure as AllWatcher
@@ -267,6 +353,8 @@ func AllFacades() *facade.Registry {
 	regRaw("FilesystemAttachmentsWatcher", 2, newFilesystemAttachmentsWatcher, reflect.TypeOf((*srvMachineStorageIdsWatcher)(nil)))
 	regRaw("EntityWatcher", 2, newEntitiesWatcher, reflect.TypeOf((*srvEntitiesWatcher)(nil)))
 	regRaw("MigrationStatusWatcher", 1, newMigrationStatusWatcher, reflect.TypeOf((*srvMigrationStatusWatcher)(nil)))
+	regRaw("StatusWatcher", 1, NewStatusWatcher, reflect.TypeOf((*SrvStatusWatcher)(nil)))
+	regRaw("StatusHistoryWatcher", 1, NewStatusHistoryWatcher, reflect.TypeOf((*SrvStatusHistoryWatcher)(nil)))
 
 	return registry
 }
@@ -4,10 +4,16 @@
 package apiserver_test
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade/facadetest"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	statetesting "github.com/juju/juju/state/testing"
 )
 
 type AllFacadesSuite struct {
@@ -21,3 +27,30 @@ func (s *AllFacadesSuite) TestNoPanic(c *gc.C) {
 	r := apiserver.AllFacades()
 	c.Assert(r, gc.NotNil)
 }
+
+type modelFeatureSuite struct {
+	statetesting.StateSuite
+}
+
+var _ = gc.Suite(&modelFeatureSuite{})
+
+func (s *modelFeatureSuite) TestFacadeNeedsModelFeature(c *gc.C) {
+	factory, err := apiserver.AllFacades().GetFactory("ImageMetadataManager", 1)
+	c.Assert(err, jc.ErrorIsNil)
+	resources := common.NewResources()
+	s.AddCleanup(func(*gc.C) { resources.StopAll() })
+	context := facadetest.Context{
+		State_:     s.State,
+		Resources_: resources,
+		Auth_:      apiservertesting.FakeAuthorizer{Tag: s.Owner},
+	}
+
+	_, err = factory(context)
+	c.Assert(err, gc.ErrorMatches, `feature "image-metadata" not enabled for the model`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+
+	err = s.Model.UpdateModelConfig(map[string]interface{}{"features": "image-metadata"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = factory(context)
+	c.Assert(err, jc.ErrorIsNil)
+}
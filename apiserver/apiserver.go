@@ -36,15 +36,20 @@ import (
 	"github.com/juju/juju/apiserver/common/apihttp"
 	"github.com/juju/juju/apiserver/common/crossmodel"
 	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/lockout"
 	"github.com/juju/juju/apiserver/logsink"
 	"github.com/juju/juju/apiserver/observer"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/sessions"
 	"github.com/juju/juju/apiserver/websocket"
+	"github.com/juju/juju/core/cache"
+	"github.com/juju/juju/core/tracing"
 	"github.com/juju/juju/resource"
 	"github.com/juju/juju/resource/resourceadapters"
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/jsoncodec"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/multiwatcher"
 )
 
 var logger = loggo.GetLogger("juju.apiserver")
@@ -73,6 +78,10 @@ type Server struct {
 	pingClock              clock.Clock
 	wg                     sync.WaitGroup
 	statePool              *state.StatePool
+	modelCache             *cache.Controller
+	spanExporter           tracing.Recorder
+	sessions               *sessions.Tracker
+	lockouts               *lockout.Tracker
 	lis                    net.Listener
 	tag                    names.Tag
 	dataDir                string
@@ -182,6 +191,21 @@ type ServerConfig struct {
 
 	// PrometheusRegisterer registers Prometheus collectors.
 	PrometheusRegisterer prometheus.Registerer
+
+	// SpanExporter, if non-nil, exports the trace spans recorded by
+	// agents to the site's tracing infrastructure.
+	SpanExporter tracing.Recorder
+
+	// SessionIdleTimeout is how long a user's API session may go
+	// without making a call before the server closes it. Sessions
+	// are not closed for being idle if this is zero.
+	SessionIdleTimeout time.Duration
+
+	// LoginLockout determines when users that repeatedly fail to log
+	// in are throttled and locked out. Nobody is locked out if both
+	// LoginLockout.MaxFailures and MaxAddressFailures are zero. The
+	// failures are counted by this API server alone.
+	LoginLockout lockout.Policy
 }
 
 // Validate validates the API server configuration.
@@ -204,6 +228,12 @@ func (c ServerConfig) Validate() error {
 	if err := c.RateLimitConfig.Validate(); err != nil {
 		return errors.Annotate(err, "validating rate limit configuration")
 	}
+	if c.SessionIdleTimeout < 0 {
+		return errors.NotValidf("negative SessionIdleTimeout")
+	}
+	if err := c.LoginLockout.Validate(); err != nil {
+		return errors.Annotate(err, "validating login lockout policy")
+	}
 	if c.LogSinkConfig != nil {
 		if err := c.LogSinkConfig.Validate(); err != nil {
 			return errors.Annotate(err, "validating logsink configuration")
@@ -365,6 +395,8 @@ func newServer(stPool *state.StatePool, lis net.Listener, cfg ServerConfig) (_ *
 		lis:                           lis,
 		newObserver:                   cfg.NewObserver,
 		statePool:                     stPool,
+		modelCache:                    cache.NewController(),
+		spanExporter:                  cfg.SpanExporter,
 		tag:                           cfg.Tag,
 		dataDir:                       cfg.DataDir,
 		logDir:                        cfg.LogDir,
@@ -424,6 +456,30 @@ func newServer(stPool *state.StatePool, lis net.Listener, cfg ServerConfig) (_ *
 		}
 	}
 
+	serverID := ""
+	if cfg.Tag != nil {
+		serverID = cfg.Tag.Id()
+	}
+	srv.sessions, err = sessions.NewTracker(sessions.Config{
+		ServerID:    serverID,
+		Clock:       cfg.Clock,
+		Hub:         cfg.Hub,
+		IdleTimeout: cfg.SessionIdleTimeout,
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "creating session tracker")
+	}
+	srv.lockouts, err = lockout.NewTracker(lockout.Config{
+		ServerID: serverID,
+		Clock:    cfg.Clock,
+		Hub:      cfg.Hub,
+		Policy:   cfg.LoginLockout,
+	})
+	if err != nil {
+		srv.sessions.Close()
+		return nil, errors.Annotate(err, "creating login lockout tracker")
+	}
+
 	go srv.run()
 	return srv, nil
 }
@@ -555,6 +611,8 @@ func (srv *Server) run() {
 		srv.tomb.Done()
 		srv.dbloggers.dispose()
 		srv.logSinkWriter.Close()
+		srv.sessions.Close()
+		srv.lockouts.Close()
 	}()
 
 	srv.wg.Add(1)
@@ -581,6 +639,12 @@ func (srv *Server) run() {
 		srv.tomb.Kill(srv.processModelRemovals())
 	}()
 
+	srv.wg.Add(1)
+	go func() {
+		defer srv.wg.Done()
+		srv.tomb.Kill(srv.updateModelCache())
+	}()
+
 	// for pat based handlers, they are matched in-order of being
 	// registered, first match wins. So more specific ones have to be
 	// registered first.
@@ -646,6 +710,7 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 	add("/model/:modeluuid/pubsub", pubsubHandler)
 	add("/model/:modeluuid/logstream", logStreamHandler)
 	add("/model/:modeluuid/log", debugLogHandler)
+	add("/model/:modeluuid/ssh-tunnel", srv.trackRequests(&sshTunnelHandler{ctxt: httpCtxt}))
 
 	logSinkHandler := logsink.NewHTTPHandler(
 		newAgentLogWriteCloserFunc(httpCtxt, srv.logSinkWriter, &srv.dbloggers),
@@ -754,6 +819,21 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 			ctxt: strictCtxt,
 		},
 	)
+	add("/model/:modeluuid/machine-progress",
+		&machineProgressHandler{
+			ctxt: httpCtxt,
+		},
+	)
+	add("/model/:modeluuid/data/:kind",
+		&dataSourceHandler{
+			ctxt: httpCtxt,
+		},
+	)
+	add("/model/:modeluuid/data-token",
+		&dataSourceTokenHandler{
+			ctxt: httpCtxt,
+		},
+	)
 	add("/model/:modeluuid/api", mainAPIHandler)
 
 	// GUI related paths.
@@ -907,20 +987,21 @@ func (srv *Server) apiHandler(w http.ResponseWriter, req *http.Request) {
 
 	connectionID := atomic.AddUint64(&srv.lastConnectionID, 1)
 
-	apiObserver := srv.newObserver()
+	session := srv.sessions.NewSession()
+	apiObserver := observer.NewMultiplexer(srv.newObserver(), session)
 	apiObserver.Join(req, connectionID)
 	defer apiObserver.Leave()
 
 	websocket.Serve(w, req, func(conn *websocket.Conn) {
 		modelUUID := req.URL.Query().Get(":modeluuid")
 		logger.Tracef("got a request for model %q", modelUUID)
-		if err := srv.serveConn(conn, modelUUID, apiObserver, req.Host); err != nil {
+		if err := srv.serveConn(conn, modelUUID, apiObserver, session, req.Host, req.RemoteAddr); err != nil {
 			logger.Errorf("error serving RPCs: %v", err)
 		}
 	})
 }
 
-func (srv *Server) serveConn(wsConn *websocket.Conn, modelUUID string, apiObserver observer.Observer, host string) error {
+func (srv *Server) serveConn(wsConn *websocket.Conn, modelUUID string, apiObserver observer.Observer, session *sessions.Session, host, remoteAddr string) error {
 	codec := jsoncodec.NewWebsocket(wsConn.Conn)
 	conn := rpc.NewConn(codec, apiObserver)
 
@@ -942,7 +1023,7 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, modelUUID string, apiObserv
 
 	if err == nil {
 		defer releaser()
-		h, err = newAPIHandler(srv, st, conn, modelUUID, host)
+		h, err = newAPIHandler(srv, st, conn, modelUUID, host, remoteAddr)
 	}
 
 	if err != nil {
@@ -962,6 +1043,8 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, modelUUID string, apiObserv
 	select {
 	case <-conn.Dead():
 	case <-srv.tomb.Dying():
+	case <-session.Done():
+		// The session was revoked, or timed out.
 	}
 	return conn.Close()
 }
@@ -1064,6 +1147,41 @@ func serverError(err error) error {
 	return nil
 }
 
+// updateModelCache keeps the model cache, used to serve annotation
+// reads, up to date with changes to the controller's models and their
+// annotations.
+func (srv *Server) updateModelCache() error {
+	w := srv.statePool.SystemState().WatchModelAnnotations(srv.statePool)
+	defer w.Stop()
+
+	deltas := make(chan []multiwatcher.Delta)
+	watchErr := make(chan error, 1)
+	go func() {
+		for {
+			d, err := w.Next()
+			if err != nil {
+				watchErr <- err
+				return
+			}
+			select {
+			case deltas <- d:
+			case <-srv.tomb.Dying():
+				return
+			}
+		}
+	}()
+	for {
+		select {
+		case <-srv.tomb.Dying():
+			return tomb.ErrDying
+		case err := <-watchErr:
+			return errors.Annotate(err, "watching models for the model cache")
+		case d := <-deltas:
+			srv.modelCache.Update(d)
+		}
+	}
+}
+
 func (srv *Server) processModelRemovals() error {
 	st := srv.statePool.SystemState()
 	w := st.WatchModelLives()
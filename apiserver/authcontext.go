@@ -190,6 +190,7 @@ func (a authenticator) localUserAuth() *authentication.UserAuthenticator {
 		Service: a.ctxt.localUserBakeryService,
 		Clock:   a.ctxt.clock,
 		LocalUserIdentityLocation: localUserIdentityLocation.String(),
+		SessionsRevoked:           a.ctxt.st.UserSessionsRevoked,
 	}
 }
 
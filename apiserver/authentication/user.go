@@ -38,6 +38,12 @@ type UserAuthenticator struct {
 	// to for local users. This always points at the same controller
 	// agent that is servicing the authorisation request.
 	LocalUserIdentityLocation string
+
+	// SessionsRevoked, if set, returns when the sessions of the given
+	// user were last revoked, or the zero time if they never were.
+	// Login macaroons obtained before then are refused, so that the
+	// user must authenticate again.
+	SessionsRevoked func(names.UserTag) (time.Time, error)
 }
 
 const (
@@ -152,8 +158,12 @@ func (u *UserAuthenticator) authenticateMacaroons(
 	entityFinder EntityFinder, tag names.UserTag, req params.LoginRequest,
 ) (state.Entity, error) {
 	// Check for a valid request macaroon.
+	macaroons, err := u.unrevokedMacaroons(tag, req.Macaroons)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	assert := map[string]string{usernameKey: tag.Id()}
-	_, err := u.Service.CheckAny(req.Macaroons, assert, checkers.New(checkers.TimeBefore))
+	_, err = u.Service.CheckAny(macaroons, assert, checkers.New(checkers.TimeBefore))
 	if err != nil {
 		cause := err
 		logger.Debugf("local-login macaroon authentication failed: %v", cause)
@@ -197,6 +207,58 @@ func (u *UserAuthenticator) authenticateMacaroons(
 	return entity, nil
 }
 
+// unrevokedMacaroons returns those of the given login macaroons that
+// were obtained after the user's sessions were last revoked.
+func (u *UserAuthenticator) unrevokedMacaroons(tag names.UserTag, mss []macaroon.Slice) ([]macaroon.Slice, error) {
+	if u.SessionsRevoked == nil {
+		return mss, nil
+	}
+	revoked, err := u.SessionsRevoked(tag)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot check session revocation")
+	}
+	if revoked.IsZero() {
+		return mss, nil
+	}
+	var result []macaroon.Slice
+	for _, ms := range mss {
+		if macaroonsIssued(ms).After(revoked) {
+			result = append(result, ms)
+		}
+	}
+	if len(result) < len(mss) {
+		logger.Debugf("ignoring login macaroons of %s obtained before its sessions were revoked", tag.Id())
+	}
+	return result, nil
+}
+
+// macaroonsIssued returns when the local login macaroons in ms were
+// issued. The discharge obtained by logging in with a password expires
+// localLoginExpiryTime after the login, and no other caveat expires
+// later.
+func macaroonsIssued(ms macaroon.Slice) time.Time {
+	var expiry time.Time
+	for _, m := range ms {
+		for _, cav := range m.Caveats() {
+			if cav.Location != "" {
+				continue
+			}
+			cond, arg, err := checkers.ParseCaveat(cav.Id)
+			if err != nil || cond != checkers.CondTimeBefore {
+				continue
+			}
+			t, err := time.Parse(time.RFC3339Nano, arg)
+			if err == nil && t.After(expiry) {
+				expiry = t
+			}
+		}
+	}
+	if expiry.IsZero() {
+		return expiry
+	}
+	return expiry.Add(-localLoginExpiryTime)
+}
+
 // ExternalMacaroonAuthenticator performs authentication for external users using
 // macaroons. If the authentication fails because provided macaroons are invalid,
 // and macaroon authentiction is enabled, it will return a *common.DischargeRequiredError
@@ -165,6 +165,38 @@ func (s *userAuthenticatorSuite) TestValidMacaroonUserLogin(c *gc.C) {
 	// no check for checker function, can't compare functions
 }
 
+func (s *userAuthenticatorSuite) TestMacaroonsObtainedBeforeRevocationIgnored(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Name: "bobbrown",
+	})
+	revoked := time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)
+	loginMacaroons := func(loggedIn time.Time) macaroon.Slice {
+		m, err := macaroon.New([]byte("root-key"), "id", "")
+		c.Assert(err, jc.ErrorIsNil)
+		err = m.AddFirstPartyCaveat("time-before " + loggedIn.Add(24*time.Hour).Format(time.RFC3339Nano))
+		c.Assert(err, jc.ErrorIsNil)
+		return macaroon.Slice{m}
+	}
+	before := loginMacaroons(revoked.Add(-time.Hour))
+	after := loginMacaroons(revoked.Add(time.Hour))
+	service := mockBakeryService{}
+
+	authenticator := &authentication.UserAuthenticator{
+		Service: &service,
+		SessionsRevoked: func(tag names.UserTag) (time.Time, error) {
+			c.Check(tag, gc.Equals, user.UserTag())
+			return revoked, nil
+		},
+	}
+	_, err := authenticator.Authenticate(s.State, user.Tag(), params.LoginRequest{
+		Macaroons: []macaroon.Slice{before, after},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	service.CheckCallNames(c, "CheckAny")
+	c.Assert(service.Calls()[0].Args[0], jc.DeepEquals, []macaroon.Slice{after})
+}
+
 func (s *userAuthenticatorSuite) TestCreateLocalLoginMacaroon(c *gc.C) {
 	service := mockBakeryService{}
 	clock := testing.NewClock(time.Time{})
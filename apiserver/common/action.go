@@ -151,8 +151,9 @@ func Actions(args params.Entities, actionFn func(string) (state.Action, error))
 			continue
 		}
 		results.Results[i].Action = &params.Action{
-			Name:       action.Name(),
-			Parameters: action.Parameters(),
+			Name:          action.Name(),
+			Parameters:    action.Parameters(),
+			CorrelationId: action.CorrelationID(),
 		}
 	}
 
@@ -235,10 +236,11 @@ func MakeActionResult(actionReceiverTag names.Tag, action state.Action) params.A
 	output, message := action.Results()
 	return params.ActionResult{
 		Action: &params.Action{
-			Receiver:   actionReceiverTag.String(),
-			Tag:        action.ActionTag().String(),
-			Name:       action.Name(),
-			Parameters: action.Parameters(),
+			Receiver:      actionReceiverTag.String(),
+			Tag:           action.ActionTag().String(),
+			Name:          action.Name(),
+			Parameters:    action.Parameters(),
+			CorrelationId: action.CorrelationID(),
 		},
 		Status:    string(action.Status()),
 		Message:   message,
@@ -36,6 +36,37 @@ func NewAPIAddresser(getter AddressAndCertGetter, resources facade.Resources) *A
 	}
 }
 
+// AgentAddressAndCertGetter is an AddressAndCertGetter that can also
+// report the API addresses advertised to a model's agents.
+type AgentAddressAndCertGetter interface {
+	AddressAndCertGetter
+	APIHostPortsForAgents() ([][]network.HostPort, error)
+	WatchAPIHostPortsForAgents() state.NotifyWatcher
+}
+
+// NewAgentAPIAddresser returns a new APIAddresser for use by agents. The
+// API addresses it reports are those advertised to agents, which follow
+// the model's address advertisement settings.
+func NewAgentAPIAddresser(getter AgentAddressAndCertGetter, resources facade.Resources) *APIAddresser {
+	return NewAPIAddresser(agentAddressGetter{getter}, resources)
+}
+
+// agentAddressGetter reports the API addresses advertised to agents in
+// place of all of the API addresses.
+type agentAddressGetter struct {
+	AgentAddressAndCertGetter
+}
+
+// APIHostPorts is part of the AddressAndCertGetter interface.
+func (g agentAddressGetter) APIHostPorts() ([][]network.HostPort, error) {
+	return g.APIHostPortsForAgents()
+}
+
+// WatchAPIHostPorts is part of the AddressAndCertGetter interface.
+func (g agentAddressGetter) WatchAPIHostPorts() state.NotifyWatcher {
+	return g.WatchAPIHostPortsForAgents()
+}
+
 // APIHostPorts returns the API server addresses.
 func (api *APIAddresser) APIHostPorts() (params.APIHostPortsResult, error) {
 	servers, err := api.getter.APIHostPorts()
@@ -8,6 +8,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 )
@@ -98,6 +99,26 @@ func (s *apiAddresserSuite) TestModelUUID(c *gc.C) {
 	c.Assert(string(result.Result), gc.Equals, "the environ uuid")
 }
 
+func (s *apiAddresserSuite) TestAgentAPIHostPorts(c *gc.C) {
+	fake := fakeAgentAddresses{
+		fakeAddresses: *s.fake,
+		agentHostPorts: [][]network.HostPort{
+			network.NewHostPorts(1, "agentaddresses"),
+		},
+	}
+	addresser := common.NewAgentAPIAddresser(fake, common.NewResources())
+	result, err := addresser.APIHostPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Servers, jc.DeepEquals, params.FromNetworkHostsPorts(fake.agentHostPorts))
+
+	addresses, err := addresser.APIAddresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses.Result, gc.DeepEquals, []string{"agentaddresses:1"})
+}
+
+// Verify that AgentAddressAndCertGetter is satisfied by *state.State.
+var _ common.AgentAddressAndCertGetter = (*state.State)(nil)
+
 var _ common.AddressAndCertGetter = fakeAddresses{}
 
 type fakeAddresses struct {
@@ -123,3 +144,16 @@ func (f fakeAddresses) APIHostPorts() ([][]network.HostPort, error) {
 func (fakeAddresses) WatchAPIHostPorts() state.NotifyWatcher {
 	panic("should never be called")
 }
+
+type fakeAgentAddresses struct {
+	fakeAddresses
+	agentHostPorts [][]network.HostPort
+}
+
+func (f fakeAgentAddresses) APIHostPortsForAgents() ([][]network.HostPort, error) {
+	return f.agentHostPorts, nil
+}
+
+func (fakeAgentAddresses) WatchAPIHostPortsForAgents() state.NotifyWatcher {
+	panic("should never be called")
+}
@@ -54,6 +54,11 @@ func MakeCloudSpecGetterForModel(st *state.State) func(names.ModelTag) (environs
 		if tag.Id() != st.ModelUUID() {
 			return environs.CloudSpec{}, errors.New("cannot get cloud spec for this model")
 		}
+		// The model's agents open their environs with this cloud
+		// spec, so this is where they meet an injected provider fault.
+		if err := stateenvirons.CheckProviderFault(st); err != nil {
+			return environs.CloudSpec{}, errors.Trace(err)
+		}
 		return configGetter.CloudSpec()
 	}
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/leadership"
 	"github.com/juju/juju/core/lease"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 )
 
@@ -170,7 +171,8 @@ func ServerErrorAndStatus(err error) (*params.Error, int) {
 		params.CodeUserNotFound,
 		params.CodeModelNotFound:
 		status = http.StatusNotFound
-	case params.CodeBadRequest:
+	case params.CodeBadRequest,
+		params.CodeConfigNotValid:
 		status = http.StatusBadRequest
 	case params.CodeMethodNotAllowed:
 		status = http.StatusMethodNotAllowed
@@ -197,6 +199,7 @@ func ServerError(err error) *params.Error {
 	}
 	logger.Tracef("server RPC error %v", errors.Details(err))
 	msg := err.Error()
+	configErr, isConfigErr := config.AsValidationError(err)
 	// Skip past annotations when looking for the code.
 	err = errors.Cause(err)
 	code, ok := singletonCode(err)
@@ -255,6 +258,21 @@ func ServerError(err error) *params.Error {
 		}
 		code = params.ErrCode(err)
 	}
+	if isConfigErr {
+		// A more specific code for a single failure is kept.
+		if code == "" {
+			code = params.CodeConfigNotValid
+		}
+		info = &params.ErrorInfo{
+			ConfigErrors: make([]params.ConfigAttributeError, len(configErr.Errors)),
+		}
+		for i, attrErr := range configErr.Errors {
+			info.ConfigErrors[i] = params.ConfigAttributeError{
+				Attribute: attrErr.Attribute,
+				Message:   attrErr.Error(),
+			}
+		}
+	}
 	return &params.Error{
 		Message: msg,
 		Code:    code,
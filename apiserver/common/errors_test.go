@@ -6,6 +6,7 @@ package common_test
 import (
 	stderrors "errors"
 	"net/http"
+	"reflect"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -18,6 +19,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/leadership"
 	"github.com/juju/juju/core/lease"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
 )
@@ -181,6 +183,25 @@ var errorTransformTests = []struct {
 		}
 		return true
 	},
+}, {
+	err: errors.Annotate(&config.ValidationError{
+		Errors: []config.AttributeError{
+			{Err: errors.New("bad model")},
+			{Attribute: "ssh-allow", Err: errors.New("bad CIDR")},
+		},
+	}, "cannot update model config"),
+	status: http.StatusBadRequest,
+	code:   params.CodeConfigNotValid,
+	helperFunc: func(err error) bool {
+		err1, ok := err.(*params.Error)
+		if !ok || err1.Info == nil {
+			return false
+		}
+		return reflect.DeepEqual(err1.Info.ConfigErrors, []params.ConfigAttributeError{
+			{Message: "bad model"},
+			{Attribute: "ssh-allow", Message: "bad CIDR"},
+		})
+	},
 }, {
 	err:    unhashableError{"foo"},
 	status: http.StatusInternalServerError,
@@ -238,6 +259,7 @@ func (s *errorsSuite) TestErrorTransform(c *gc.C) {
 			params.CodeUpgradeInProgress,
 			params.CodeMachineHasAttachedStorage,
 			params.CodeDischargeRequired,
+			params.CodeConfigNotValid,
 			params.CodeModelNotFound,
 			params.CodeRetry:
 			continue
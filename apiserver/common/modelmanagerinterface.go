@@ -40,6 +40,7 @@ type ModelManagerBackend interface {
 	GetBackend(string) (ModelManagerBackend, func() bool, error)
 
 	ComposeNewModelConfig(modelAttr map[string]interface{}, regionSpec *environs.RegionSpec) (map[string]interface{}, error)
+	ConfigProfile(name string) (state.ConfigProfile, error)
 	ControllerModelUUID() string
 	ControllerModelTag() names.ModelTag
 	ControllerConfig() (controller.Config, error)
@@ -83,6 +84,11 @@ type Model interface {
 	Life() state.Life
 	ModelTag() names.ModelTag
 	Owner() names.UserTag
+	OwnerTeam() (string, bool)
+	OwnershipTransfers() ([]state.ModelOwnershipTransfer, error)
+	TransferOwnership(owner, by names.UserTag) error
+	TransferOwnershipToTeam(team string, by names.UserTag) error
+	SetEphemeralExpiry(expiry time.Time) error
 	Status() (status.StatusInfo, error)
 	Cloud() string
 	CloudCredential() (names.CloudCredentialTag, bool)
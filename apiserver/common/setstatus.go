@@ -175,15 +175,34 @@ func (s *StatusSetter) SetStatus(args params.SetStatus) (params.ErrorResults, er
 			result.Results[i].Error = ServerError(err)
 			continue
 		}
+		since := clampStatusSince(arg.Since, now)
 		err = ErrPerm
 		if canModify(tag) {
-			err = s.setEntityStatus(tag, status.Status(arg.Status), arg.Info, arg.Data, &now)
+			err = s.setEntityStatus(tag, status.Status(arg.Status), arg.Info, arg.Data, since)
 		}
 		result.Results[i].Error = ServerError(err)
 	}
 	return result, nil
 }
 
+// maxStatusDelay is how long before it is reported a status may claim
+// to have been reached.
+const maxStatusDelay = 24 * time.Hour
+
+// clampStatusSince returns the time to record for a status reached at
+// since, and reported at now. Statuses reported late keep the time they
+// were reached, within maxStatusDelay, but no status may claim to be
+// from the future.
+func clampStatusSince(since *time.Time, now time.Time) *time.Time {
+	if since == nil || since.After(now) {
+		return &now
+	}
+	if earliest := now.Add(-maxStatusDelay); since.Before(earliest) {
+		return &earliest
+	}
+	return since
+}
+
 func (s *StatusSetter) updateEntityStatusData(tag names.Tag, data map[string]interface{}) error {
 	entity0, err := s.st.FindEntity(tag)
 	if err != nil {
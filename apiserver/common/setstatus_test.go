@@ -104,6 +104,47 @@ func (s *statusSetterSuite) TestSetUnitStatus(c *gc.C) {
 	c.Assert(unitStatus.Status, gc.Equals, status.Active)
 }
 
+func (s *statusSetterSuite) TestSetStatusSince(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, nil)
+	earlier := time.Now().Add(-time.Hour).Round(time.Millisecond)
+	later := time.Now().Add(time.Hour)
+	result, err := s.setter.SetStatus(params.SetStatus{[]params.EntityStatusArgs{{
+		Tag:    machine.Tag().String(),
+		Status: status.Started.String(),
+		Since:  &earlier,
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	machineStatus, err := machine.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineStatus.Since.Equal(earlier), jc.IsTrue)
+
+	// A status cannot be reported as reached in the future.
+	result, err = s.setter.SetStatus(params.SetStatus{[]params.EntityStatusArgs{{
+		Tag:    machine.Tag().String(),
+		Status: status.Stopped.String(),
+		Since:  &later,
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	machineStatus, err = machine.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineStatus.Since.Before(later), jc.IsTrue)
+
+	// Nor can it be reported as reached long ago.
+	longAgo := time.Now().Add(-7 * 24 * time.Hour)
+	result, err = s.setter.SetStatus(params.SetStatus{[]params.EntityStatusArgs{{
+		Tag:    machine.Tag().String(),
+		Status: status.Started.String(),
+		Since:  &longAgo,
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	machineStatus, err = machine.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineStatus.Since.After(time.Now().Add(-25*time.Hour)), jc.IsTrue)
+}
+
 func (s *statusSetterSuite) TestSetServiceStatus(c *gc.C) {
 	// Calls to set the status of a service should be going through the
 	// ServiceStatusSetter that checks for leadership, so permission denied
@@ -0,0 +1,48 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+// SSHAccessBackend provides the methods needed to decide whether a
+// user may ssh to a model's machines.
+type SSHAccessBackend interface {
+	User(names.UserTag) (*state.User, error)
+	UserPermission(subject names.UserTag, target names.Tag) (permission.Access, error)
+	ControllerTag() names.ControllerTag
+}
+
+// UserCanSSH reports whether the named user is able to ssh to the
+// machines of the specified model: that is, whether the user is active
+// and has admin access to the model, either directly or as a controller
+// superuser.
+func UserCanSSH(st SSHAccessBackend, name string, modelTag names.ModelTag) (bool, error) {
+	if !names.IsValidUser(name) {
+		return false, nil
+	}
+	userTag := names.NewUserTag(name)
+	if userTag.IsLocal() {
+		user, err := st.User(userTag)
+		if _, ok := errors.Cause(err).(state.DeletedUserError); ok || errors.IsNotFound(err) {
+			return false, nil
+		} else if err != nil {
+			return false, errors.Trace(err)
+		}
+		if user.IsDisabled() {
+			return false, nil
+		}
+	}
+	isAdmin, err := HasPermission(st.UserPermission, userTag, permission.AdminAccess, modelTag)
+	if err != nil || isAdmin {
+		return isAdmin, errors.Trace(err)
+	}
+	isSuperuser, err := HasPermission(st.UserPermission, userTag, permission.SuperuserAccess, st.ControllerTag())
+	return isSuperuser, errors.Trace(err)
+}
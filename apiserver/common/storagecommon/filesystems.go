@@ -53,7 +53,7 @@ func FilesystemParams(
 		"", // volume tag
 		size,
 		string(providerType),
-		cfg.Attrs(),
+		poolAttrs(pool, cfg, environConfig),
 		filesystemTags,
 		nil, // attachment params set by the caller
 	}
@@ -52,7 +52,7 @@ func VolumeParams(
 		v.Tag().String(),
 		size,
 		string(providerType),
-		cfg.Attrs(),
+		poolAttrs(pool, cfg, environConfig),
 		volumeTags,
 		nil, // attachment params set by the caller
 	}, nil
@@ -78,6 +78,30 @@ func StoragePoolConfig(name string, poolManager poolmanager.PoolManager, registr
 	return pool.Provider(), pool, nil
 }
 
+// poolAttrs returns the attributes of storage created in the named pool.
+// If the pool is one of the model's default block or filesystem sources,
+// the model's default pool attributes apply to any attributes not set
+// by the pool itself.
+func poolAttrs(pool string, cfg *storage.Config, environConfig *config.Config) map[string]interface{} {
+	attrs := cfg.Attrs()
+	if environConfig == nil {
+		return attrs
+	}
+	defaults := environConfig.StorageDefaultPoolAttrs()
+	if len(defaults) == 0 {
+		return attrs
+	}
+	blockSource, _ := environConfig.StorageDefaultBlockSource()
+	filesystemSource, _ := environConfig.StorageDefaultFilesystemSource()
+	if pool != blockSource && pool != filesystemSource {
+		return attrs
+	}
+	for k, v := range attrs {
+		defaults[k] = v
+	}
+	return defaults
+}
+
 // VolumesToState converts a slice of params.Volume to a mapping
 // of volume tags to state.VolumeInfo.
 func VolumesToState(in []params.Volume) (map[names.VolumeTag]state.VolumeInfo, error) {
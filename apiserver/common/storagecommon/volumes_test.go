@@ -89,3 +89,41 @@ func (*volumesSuite) TestVolumeParamsStorageTags(c *gc.C) {
 		},
 	})
 }
+
+func (*volumesSuite) TestVolumeParamsDefaultPoolAttrs(c *gc.C) {
+	cfg := testing.CustomModelConfig(c, testing.Attrs{
+		"storage-default-block-source": "loop",
+		"storage-default-pool-attrs":   "volume-type=io1 iops=30",
+	})
+	p, err := storagecommon.VolumeParams(
+		&fakeVolume{tag: names.NewVolumeTag("100"), params: &state.VolumeParams{
+			Pool: "loop", Size: 1024,
+		}},
+		nil, // StorageInstance
+		testing.ModelTag.Id(),
+		testing.ControllerTag.Id(),
+		cfg,
+		&fakePoolManager{},
+		provider.CommonStorageProviders(),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.Attributes, jc.DeepEquals, map[string]interface{}{
+		"volume-type": "io1",
+		"iops":        "30",
+	})
+
+	// The defaults only apply to the default pools.
+	p, err = storagecommon.VolumeParams(
+		&fakeVolume{tag: names.NewVolumeTag("101"), params: &state.VolumeParams{
+			Pool: "tmpfs", Size: 1024,
+		}},
+		nil, // StorageInstance
+		testing.ModelTag.Id(),
+		testing.ControllerTag.Id(),
+		cfg,
+		&fakePoolManager{},
+		provider.CommonStorageProviders(),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.Attributes, gc.IsNil)
+}
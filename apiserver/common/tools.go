@@ -300,9 +300,10 @@ func (f *ToolsFinder) matchingStorageTools(args params.FindToolsParams) (coretoo
 			return nil, errors.Annotatef(err, "unexpected bad version %q of agent binary in storage", m.Version)
 		}
 		list[i] = &coretools.Tools{
-			Version: vers,
-			Size:    m.Size,
-			SHA256:  m.SHA256,
+			Version:   vers,
+			Size:      m.Size,
+			SHA256:    m.SHA256,
+			Signature: m.Signature,
 		}
 	}
 	list, err = list.Match(toolsFilter(args))
@@ -0,0 +1,425 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/base64"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/macaroon-bakery.v1/bakery/checkers"
+	"gopkg.in/macaroon.v1"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/crossmodel"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+const (
+	// defaultDataSourcePageSize is the number of entities returned
+	// by the data source endpoint when no limit is requested.
+	defaultDataSourcePageSize = 100
+
+	// maxDataSourcePageSize is the largest limit that may be
+	// requested from the data source endpoint.
+	maxDataSourcePageSize = 500
+
+	// dataSourceTokenExpiry is how long a data source token may be
+	// used for after it is issued.
+	dataSourceTokenExpiry = 24 * time.Hour
+
+	// dataSourceCaveat is the condition of the first-party caveat
+	// that restricts a data source token to the data source endpoint.
+	// No other endpoint recognises it, so the token cannot be used
+	// to log in.
+	dataSourceCaveat = "juju-data-source"
+)
+
+// dataSourceHandler is an http.Handler for the read-only
+// "/model/:modeluuid/data/:kind" endpoint, which serves model,
+// application and offer metadata as plain JSON so that tools such as
+// the Terraform provider can reference Juju entities without an RPC
+// client. Requests are authenticated with a data source token, as
+// issued by dataSourceTokenHandler, presented as a bearer token in the
+// Authorization header, or else with a user's credentials or macaroons
+// in the same way as the other HTTP endpoints. Either way the user
+// must have read access to the model.
+//
+// The kind is one of "model", "applications" or "offers". Lists are
+// ordered by the entities' stable IDs, and are paged using the "limit"
+// and "after" query parameters.
+type dataSourceHandler struct {
+	ctxt httpContext
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *dataSourceHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	response, err := h.serveGet(req)
+	if err == nil {
+		err = sendStatusAndJSON(w, http.StatusOK, response)
+	} else {
+		err = sendError(w, err)
+	}
+	if err != nil {
+		logger.Errorf("%v", err)
+	}
+}
+
+func (h *dataSourceHandler) serveGet(req *http.Request) (*params.DataSourceResponse, error) {
+	if req.Method != "GET" {
+		return nil, errors.Trace(emitUnsupportedMethodErr(req.Method))
+	}
+	st, releaser, user, err := h.authenticate(req)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer releaser()
+	if err := checkModelReadAccess(st, user); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	query := req.URL.Query()
+	limit, err := dataSourceLimit(query.Get("limit"))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	after := query.Get("after")
+
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch kind := query.Get(":kind"); kind {
+	case "model":
+		return &params.DataSourceResponse{Model: dataSourceModel(model)}, nil
+	case "applications":
+		return dataSourceApplications(st, after, limit)
+	case "offers":
+		return dataSourceOffers(st, model, after, limit)
+	default:
+		return nil, errors.NotFoundf("data source %q", kind)
+	}
+}
+
+// authenticate returns the state of the request's model and the user
+// making the request, who is named by the request's data source token
+// if it has one.
+func (h *dataSourceHandler) authenticate(req *http.Request) (*state.State, state.StatePoolReleaser, names.UserTag, error) {
+	token, ok := bearerToken(req)
+	if !ok {
+		st, releaser, entity, err := h.ctxt.stateAndEntityForRequestAuthenticatedUser(req)
+		if err != nil {
+			return nil, nil, names.UserTag{}, errors.Trace(err)
+		}
+		return st, releaser, entity.Tag().(names.UserTag), nil
+	}
+	st, releaser, err := h.ctxt.stateForRequestUnauthenticated(req)
+	if err != nil {
+		return nil, nil, names.UserTag{}, errors.Trace(err)
+	}
+	user, err := checkDataSourceToken(h.ctxt.srv.loginAuthCtxt.localUserBakeryService, token, st.ModelUUID())
+	if err == nil {
+		err = checkUserEnabled(st, user)
+	}
+	if err != nil {
+		releaser()
+		return nil, nil, names.UserTag{}, errors.Trace(err)
+	}
+	return st, releaser, user, nil
+}
+
+// checkModelReadAccess returns an error if the user has neither read
+// access to the model nor superuser access to the controller.
+func checkModelReadAccess(st *state.State, user names.UserTag) error {
+	ok, err := common.HasPermission(
+		st.UserPermission,
+		user,
+		permission.SuperuserAccess,
+		st.ControllerTag(),
+	)
+	if err != nil || ok {
+		return errors.Trace(err)
+	}
+	ok, err = common.HasPermission(
+		st.UserPermission,
+		user,
+		permission.ReadAccess,
+		st.ModelTag(),
+	)
+	if err != nil || ok {
+		return errors.Trace(err)
+	}
+	return &params.Error{
+		Code:    params.CodeForbidden,
+		Message: "access denied",
+	}
+}
+
+// dataSourceTokenHandler is an http.Handler for the
+// "/model/:modeluuid/data-token" endpoint. A POST, authenticated with
+// a user's credentials or macaroons, returns a token that lets the
+// bearer read the model's data source endpoint on the user's behalf
+// until it expires. The token grants nothing else, so it may be handed
+// to infrastructure pipelines in place of the user's password.
+type dataSourceTokenHandler struct {
+	ctxt httpContext
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *dataSourceTokenHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	response, err := h.servePost(req)
+	if err == nil {
+		err = sendStatusAndJSON(w, http.StatusOK, response)
+	} else {
+		err = sendError(w, err)
+	}
+	if err != nil {
+		logger.Errorf("%v", err)
+	}
+}
+
+func (h *dataSourceTokenHandler) servePost(req *http.Request) (*params.DataSourceToken, error) {
+	if req.Method != "POST" {
+		return nil, errors.Trace(emitUnsupportedMethodErr(req.Method))
+	}
+	st, releaser, entity, err := h.ctxt.stateAndEntityForRequestAuthenticatedUser(req)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer releaser()
+	user := entity.Tag().(names.UserTag)
+	if err := checkModelReadAccess(st, user); err != nil {
+		return nil, errors.Trace(err)
+	}
+	authCtxt := h.ctxt.srv.loginAuthCtxt
+	expiry := authCtxt.clock.Now().Add(dataSourceTokenExpiry)
+	token, err := newDataSourceToken(authCtxt.localUserBakeryService, user, st.ModelUUID(), expiry)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &params.DataSourceToken{Token: token, Expires: expiry}, nil
+}
+
+// newDataSourceToken returns a token that lets its bearer read the
+// data source endpoint of the model with the given UUID, on behalf of
+// the user, until the expiry time. The token is a macaroon, encoded
+// with unpadded URL-safe base64.
+func newDataSourceToken(
+	service authentication.ExpirableStorageBakeryService,
+	user names.UserTag,
+	modelUUID string,
+	expiry time.Time,
+) (string, error) {
+	service, err := service.ExpireStorageAt(expiry)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	m, err := service.NewMacaroon("", nil, []checkers.Caveat{
+		checkers.TimeBeforeCaveat(expiry),
+		checkers.DeclaredCaveat("username", user.Id()),
+		checkers.DeclaredCaveat("model-uuid", modelUUID),
+		{Condition: dataSourceCaveat},
+	})
+	if err != nil {
+		return "", errors.Annotate(err, "cannot create data source token")
+	}
+	data, err := m.MarshalBinary()
+	if err != nil {
+		return "", errors.Annotate(err, "cannot marshal data source token")
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// checkDataSourceToken checks that the token was issued by
+// newDataSourceToken for the model with the given UUID, and has not
+// expired. It returns the user on whose behalf the token was issued.
+func checkDataSourceToken(service authentication.BakeryService, token, modelUUID string) (names.UserTag, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return names.UserTag{}, errors.Unauthorizedf("invalid data source token")
+	}
+	var m macaroon.Macaroon
+	if err := m.UnmarshalBinary(data); err != nil {
+		return names.UserTag{}, errors.Unauthorizedf("invalid data source token")
+	}
+	declared, err := service.CheckAny(
+		[]macaroon.Slice{{&m}},
+		map[string]string{"model-uuid": modelUUID},
+		checkers.New(
+			checkers.TimeBefore,
+			checkers.CheckerFunc{
+				Condition_: dataSourceCaveat,
+				Check_:     func(string, string) error { return nil },
+			},
+		),
+	)
+	if err != nil {
+		logger.Debugf("data source token check failed: %v", err)
+		return names.UserTag{}, errors.Unauthorizedf("invalid data source token")
+	}
+	username := declared["username"]
+	if !names.IsValidUser(username) {
+		return names.UserTag{}, errors.Unauthorizedf("invalid data source token")
+	}
+	return names.NewUserTag(username), nil
+}
+
+// checkUserEnabled returns an unauthorized error if the user is a
+// local user that has been disabled or removed since a token was
+// issued on their behalf.
+func checkUserEnabled(st *state.State, user names.UserTag) error {
+	if !user.IsLocal() {
+		return nil
+	}
+	u, err := st.User(user)
+	if _, ok := errors.Cause(err).(state.DeletedUserError); ok || errors.IsNotFound(err) {
+		return errors.Unauthorizedf("invalid data source token")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if u.IsDisabled() {
+		return errors.Unauthorizedf("invalid data source token")
+	}
+	return nil
+}
+
+// bearerToken returns the token presented in the request's
+// Authorization header with the "Bearer" scheme, if any.
+func bearerToken(req *http.Request) (string, bool) {
+	parts := strings.Fields(req.Header.Get("Authorization"))
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+func dataSourceLimit(value string) (int, error) {
+	if value == "" {
+		return defaultDataSourcePageSize, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > maxDataSourcePageSize {
+		return 0, errors.BadRequestf("limit must be between 1 and %d", maxDataSourcePageSize)
+	}
+	return limit, nil
+}
+
+// pageIDs returns the range of the sorted ids that forms the page
+// following the given cursor, and the cursor of the next page.
+func pageIDs(ids []string, after string, limit int) (start, end int, next string) {
+	start = sort.SearchStrings(ids, after)
+	if start < len(ids) && ids[start] == after {
+		start++
+	}
+	end = start + limit
+	if end >= len(ids) {
+		return start, len(ids), ""
+	}
+	return start, end, ids[end-1]
+}
+
+func dataSourceModel(model *state.Model) *params.DataSourceModel {
+	result := &params.DataSourceModel{
+		ID:          model.UUID(),
+		Name:        model.Name(),
+		Owner:       model.Owner().Id(),
+		Type:        string(model.Type()),
+		Cloud:       model.Cloud(),
+		CloudRegion: model.CloudRegion(),
+		Life:        model.Life().String(),
+	}
+	if tag, ok := model.CloudCredential(); ok {
+		result.CloudCredential = tag.Id()
+	}
+	return result
+}
+
+func dataSourceApplications(st *state.State, after string, limit int) (*params.DataSourceResponse, error) {
+	applications, err := st.AllApplications()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	modelUUID := st.ModelUUID()
+	ids := make([]string, len(applications))
+	byID := make(map[string]*state.Application)
+	for i, app := range applications {
+		ids[i] = modelUUID + ":" + app.Name()
+		byID[ids[i]] = app
+	}
+	sort.Strings(ids)
+	start, end, next := pageIDs(ids, after, limit)
+
+	result := &params.DataSourceResponse{
+		Applications: []params.DataSourceApplication{},
+		Next:         next,
+	}
+	for _, id := range ids[start:end] {
+		app := byID[id]
+		units, err := app.AllUnits()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		item := params.DataSourceApplication{
+			ID:        id,
+			ModelUUID: modelUUID,
+			Name:      app.Name(),
+			Channel:   string(app.Channel()),
+			Series:    app.Series(),
+			Exposed:   app.IsExposed(),
+			Units:     len(units),
+			Life:      app.Life().String(),
+		}
+		if curl, _ := app.CharmURL(); curl != nil {
+			item.Charm = curl.WithRevision(-1).String()
+			item.CharmRevision = curl.Revision
+		}
+		result.Applications = append(result.Applications, item)
+	}
+	return result, nil
+}
+
+func dataSourceOffers(st *state.State, model *state.Model, after string, limit int) (*params.DataSourceResponse, error) {
+	offers, err := state.NewApplicationOffers(st).AllApplicationOffers()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ids := make([]string, len(offers))
+	byID := make(map[string]*crossmodel.ApplicationOffer)
+	for i, offer := range offers {
+		ids[i] = offer.OfferUUID
+		byID[ids[i]] = offer
+	}
+	sort.Strings(ids)
+	start, end, next := pageIDs(ids, after, limit)
+
+	result := &params.DataSourceResponse{
+		Offers: []params.DataSourceOffer{},
+		Next:   next,
+	}
+	for _, id := range ids[start:end] {
+		offer := byID[id]
+		item := params.DataSourceOffer{
+			ID:              id,
+			ModelUUID:       model.UUID(),
+			Name:            offer.OfferName,
+			URL:             crossmodel.MakeURL(model.Owner().Id(), model.Name(), offer.OfferName, ""),
+			ApplicationName: offer.ApplicationName,
+			Endpoints:       []string{},
+		}
+		for name := range offer.Endpoints {
+			item.Endpoints = append(item.Endpoints, name)
+		}
+		sort.Strings(item.Endpoints)
+		result.Offers = append(result.Offers, item)
+	}
+	return result, nil
+}
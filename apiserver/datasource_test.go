@@ -0,0 +1,221 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/crossmodel"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type dataSourceSuite struct {
+	authHTTPSuite
+}
+
+var _ = gc.Suite(&dataSourceSuite{})
+
+func (s *dataSourceSuite) dataSourceURL(c *gc.C, kind string, query url.Values) string {
+	return s.makeURL(c, "https", "/model/"+s.modelUUID+"/data/"+kind, query).String()
+}
+
+func (s *dataSourceSuite) getDataSource(c *gc.C, kind string, query url.Values) params.DataSourceResponse {
+	resp := s.authRequest(c, httpRequestParams{method: "GET", url: s.dataSourceURL(c, kind, query)})
+	body := assertResponse(c, resp, http.StatusOK, params.ContentTypeJSON)
+	var result params.DataSourceResponse
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, jc.ErrorIsNil, gc.Commentf("body: %s", body))
+	return result
+}
+
+func (s *dataSourceSuite) assertErrorResponse(c *gc.C, resp *http.Response, expCode int, expError string) {
+	body := assertResponse(c, resp, expCode, params.ContentTypeJSON)
+	var result params.ErrorResult
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, jc.ErrorIsNil, gc.Commentf("body: %s", body))
+	c.Assert(result.Error, gc.NotNil)
+	c.Assert(result.Error.Message, gc.Matches, expError)
+}
+
+func (s *dataSourceSuite) TestModel(c *gc.C) {
+	result := s.getDataSource(c, "model", nil)
+	c.Assert(result.Model, gc.NotNil)
+	c.Check(*result.Model, jc.DeepEquals, params.DataSourceModel{
+		ID:              s.modelUUID,
+		Name:            s.IAASModel.Name(),
+		Owner:           s.IAASModel.Owner().Id(),
+		Type:            "iaas",
+		Cloud:           "dummy",
+		CloudRegion:     "dummy-region",
+		CloudCredential: "dummy/admin/cred",
+		Life:            "alive",
+	})
+	c.Check(result.Next, gc.Equals, "")
+}
+
+func (s *dataSourceSuite) TestApplicationsPaged(c *gc.C) {
+	ch := s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress", URL: "cs:quantal/wordpress-3"})
+	for _, name := range []string{"wp-c", "wp-a", "wp-b"} {
+		s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: name, Charm: ch})
+	}
+
+	result := s.getDataSource(c, "applications", url.Values{"limit": {"2"}})
+	c.Assert(result.Applications, gc.HasLen, 2)
+	c.Check(result.Applications[0], jc.DeepEquals, params.DataSourceApplication{
+		ID:            s.modelUUID + ":wp-a",
+		ModelUUID:     s.modelUUID,
+		Name:          "wp-a",
+		Charm:         "cs:quantal/wordpress",
+		CharmRevision: 3,
+		Series:        "quantal",
+		Life:          "alive",
+	})
+	c.Check(result.Applications[1].Name, gc.Equals, "wp-b")
+	c.Assert(result.Next, gc.Equals, s.modelUUID+":wp-b")
+
+	result = s.getDataSource(c, "applications", url.Values{"limit": {"2"}, "after": {result.Next}})
+	c.Assert(result.Applications, gc.HasLen, 1)
+	c.Check(result.Applications[0].Name, gc.Equals, "wp-c")
+	c.Check(result.Next, gc.Equals, "")
+}
+
+func (s *dataSourceSuite) TestOffers(c *gc.C) {
+	ch := s.Factory.MakeCharm(c, &factory.CharmParams{Name: "mysql"})
+	s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "mysql", Charm: ch})
+	offer, err := state.NewApplicationOffers(s.State).AddOffer(crossmodel.AddApplicationOfferArgs{
+		OfferName:       "hosted-mysql",
+		ApplicationName: "mysql",
+		Endpoints:       map[string]string{"database": "server"},
+		Owner:           "admin",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result := s.getDataSource(c, "offers", nil)
+	c.Assert(result.Offers, jc.DeepEquals, []params.DataSourceOffer{{
+		ID:              offer.OfferUUID,
+		ModelUUID:       s.modelUUID,
+		Name:            "hosted-mysql",
+		URL:             "admin/" + s.IAASModel.Name() + ".hosted-mysql",
+		ApplicationName: "mysql",
+		Endpoints:       []string{"database"},
+	}})
+}
+
+func (s *dataSourceSuite) TestUnknownKind(c *gc.C) {
+	resp := s.authRequest(c, httpRequestParams{method: "GET", url: s.dataSourceURL(c, "machines", nil)})
+	s.assertErrorResponse(c, resp, http.StatusNotFound, `data source "machines" not found`)
+}
+
+func (s *dataSourceSuite) TestInvalidLimit(c *gc.C) {
+	query := url.Values{"limit": {"0"}}
+	resp := s.authRequest(c, httpRequestParams{method: "GET", url: s.dataSourceURL(c, "applications", query)})
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, "limit must be between 1 and 500")
+}
+
+func (s *dataSourceSuite) TestRequiresGET(c *gc.C) {
+	resp := s.authRequest(c, httpRequestParams{method: "POST", url: s.dataSourceURL(c, "model", nil)})
+	s.assertErrorResponse(c, resp, http.StatusMethodNotAllowed, `unsupported method: "POST"`)
+}
+
+func (s *dataSourceSuite) TestRequiresAuth(c *gc.C) {
+	resp := s.sendRequest(c, httpRequestParams{method: "GET", url: s.dataSourceURL(c, "model", nil)})
+	s.assertErrorResponse(c, resp, http.StatusUnauthorized, "no credentials provided")
+}
+
+func (s *dataSourceSuite) TestRequiresModelAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Password:    "password",
+		NoModelUser: true,
+	})
+	resp := s.sendRequest(c, httpRequestParams{
+		method:   "GET",
+		url:      s.dataSourceURL(c, "model", nil),
+		tag:      user.Tag().String(),
+		password: "password",
+	})
+	s.assertErrorResponse(c, resp, http.StatusForbidden, "access denied")
+}
+
+func (s *dataSourceSuite) dataSourceToken(c *gc.C) params.DataSourceToken {
+	resp := s.authRequest(c, httpRequestParams{
+		method: "POST",
+		url:    s.makeURL(c, "https", "/model/"+s.modelUUID+"/data-token", nil).String(),
+	})
+	body := assertResponse(c, resp, http.StatusOK, params.ContentTypeJSON)
+	var result params.DataSourceToken
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, jc.ErrorIsNil, gc.Commentf("body: %s", body))
+	c.Assert(result.Token, gc.Not(gc.Equals), "")
+	return result
+}
+
+func (s *dataSourceSuite) TestToken(c *gc.C) {
+	token := s.dataSourceToken(c)
+	c.Assert(token.Expires.After(time.Now()), jc.IsTrue)
+
+	resp := s.sendRequest(c, httpRequestParams{
+		method:       "GET",
+		url:          s.dataSourceURL(c, "model", nil),
+		extraHeaders: map[string]string{"Authorization": "Bearer " + token.Token},
+	})
+	body := assertResponse(c, resp, http.StatusOK, params.ContentTypeJSON)
+	var result params.DataSourceResponse
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, jc.ErrorIsNil, gc.Commentf("body: %s", body))
+	c.Assert(result.Model, gc.NotNil)
+	c.Assert(result.Model.ID, gc.Equals, s.modelUUID)
+}
+
+func (s *dataSourceSuite) TestTokenScopedToModel(c *gc.C) {
+	token := s.dataSourceToken(c)
+	otherState := s.Factory.MakeModel(c, nil)
+	defer otherState.Close()
+
+	resp := s.sendRequest(c, httpRequestParams{
+		method:       "GET",
+		url:          s.makeURL(c, "https", "/model/"+otherState.ModelUUID()+"/data/model", nil).String(),
+		extraHeaders: map[string]string{"Authorization": "Bearer " + token.Token},
+	})
+	s.assertErrorResponse(c, resp, http.StatusUnauthorized, "invalid data source token")
+}
+
+func (s *dataSourceSuite) TestInvalidToken(c *gc.C) {
+	resp := s.sendRequest(c, httpRequestParams{
+		method:       "GET",
+		url:          s.dataSourceURL(c, "model", nil),
+		extraHeaders: map[string]string{"Authorization": "Bearer not-a-token"},
+	})
+	s.assertErrorResponse(c, resp, http.StatusUnauthorized, "invalid data source token")
+}
+
+func (s *dataSourceSuite) TestTokenDisabledUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "password"})
+	resp := s.sendRequest(c, httpRequestParams{
+		method:   "POST",
+		url:      s.makeURL(c, "https", "/model/"+s.modelUUID+"/data-token", nil).String(),
+		tag:      user.Tag().String(),
+		password: "password",
+	})
+	body := assertResponse(c, resp, http.StatusOK, params.ContentTypeJSON)
+	var token params.DataSourceToken
+	err := json.Unmarshal(body, &token)
+	c.Assert(err, jc.ErrorIsNil, gc.Commentf("body: %s", body))
+
+	err = user.Disable()
+	c.Assert(err, jc.ErrorIsNil)
+	resp = s.sendRequest(c, httpRequestParams{
+		method:       "GET",
+		url:          s.dataSourceURL(c, "model", nil),
+		extraHeaders: map[string]string{"Authorization": "Bearer " + token.Token},
+	})
+	s.assertErrorResponse(c, resp, http.StatusUnauthorized, "invalid data source token")
+}
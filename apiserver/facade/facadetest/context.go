@@ -5,18 +5,26 @@ package facadetest
 
 import (
 	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/lockout"
+	"github.com/juju/juju/apiserver/sessions"
+	"github.com/juju/juju/core/cache"
+	"github.com/juju/juju/core/tracing"
 	"github.com/juju/juju/state"
 )
 
 // Context implements facade.Context in the simplest possible way.
 type Context struct {
-	Abort_     <-chan struct{}
-	Auth_      facade.Authorizer
-	Dispose_   func()
-	Resources_ facade.Resources
-	State_     *state.State
-	StatePool_ *state.StatePool
-	ID_        string
+	Abort_        <-chan struct{}
+	Auth_         facade.Authorizer
+	Dispose_      func()
+	Resources_    facade.Resources
+	State_        *state.State
+	StatePool_    *state.StatePool
+	ModelCache_   *cache.Controller
+	SpanExporter_ tracing.Recorder
+	Sessions_     *sessions.Tracker
+	Lockouts_     *lockout.Tracker
+	ID_           string
 	// Identity is not part of the facade.Context interface, but is instead
 	// used to make sure that the context objects are the same.
 	Identity string
@@ -52,6 +60,26 @@ func (context Context) StatePool() *state.StatePool {
 	return context.StatePool_
 }
 
+// ModelCache is part of the facade.Context interface.
+func (context Context) ModelCache() *cache.Controller {
+	return context.ModelCache_
+}
+
+// SpanExporter is part of the facade.Context interface.
+func (context Context) SpanExporter() tracing.Recorder {
+	return context.SpanExporter_
+}
+
+// Sessions is part of the facade.Context interface.
+func (context Context) Sessions() *sessions.Tracker {
+	return context.Sessions_
+}
+
+// LoginLockouts is part of the facade.Context interface.
+func (context Context) LoginLockouts() *lockout.Tracker {
+	return context.Lockouts_
+}
+
 // ID is part of the facade.Context interface.
 func (context Context) ID() string {
 	return context.ID_
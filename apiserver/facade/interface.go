@@ -6,6 +6,10 @@ package facade
 import (
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/apiserver/lockout"
+	"github.com/juju/juju/apiserver/sessions"
+	"github.com/juju/juju/core/cache"
+	"github.com/juju/juju/core/tracing"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 )
@@ -66,6 +70,24 @@ type Context interface {
 	// creation of the expensive *State instances.
 	StatePool() *state.StatePool
 
+	// ModelCache returns the controller's in-memory cache of model
+	// data. It is eventually consistent with the database; facades
+	// should use it only to answer read-only calls.
+	ModelCache() *cache.Controller
+
+	// SpanExporter returns the recorder through which the controller
+	// exports trace spans to an OpenTelemetry collector, or nil if
+	// spans are not exported.
+	SpanExporter() tracing.Recorder
+
+	// Sessions returns the tracker of the API sessions of the users
+	// logged in to the API server.
+	Sessions() *sessions.Tracker
+
+	// LoginLockouts returns the tracker of the failed logins of users
+	// to the API server.
+	LoginLockouts() *lockout.Tracker
+
 	// ID returns a string that should almost always be "", unless
 	// this is a watcher facade, in which case it exists in lieu of
 	// actual arguments in the Next() call, and is used as a key
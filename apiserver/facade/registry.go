@@ -10,6 +10,7 @@ import (
 	"sort"
 
 	"github.com/juju/errors"
+	"github.com/juju/utils/set"
 
 	"github.com/juju/juju/state"
 )
@@ -18,6 +19,10 @@ import (
 type record struct {
 	factory    Factory
 	facadeType reflect.Type
+
+	// modelFeature holds the feature flag that a model must have
+	// enabled for the facade to be available to it, if any.
+	modelFeature string
 }
 
 // versions is our internal structure for tracking specific versions of a
@@ -45,18 +50,51 @@ func (f *Registry) RegisterStandard(name string, version int, newFunc interface{
 	return nil
 }
 
+// RegisterForModelFeature is like RegisterStandard, but the facade is
+// only available to models with the feature flag in their "features"
+// config. It is used for facades, such as experimental ones, that are
+// not available to every model.
+func (f *Registry) RegisterForModelFeature(name string, version int, newFunc interface{}, flag string) error {
+	wrapped, facadeType, err := wrapNewFacade(newFunc)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	gated := func(context Context) (Facade, error) {
+		cfg, err := context.State().ModelConfig()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !cfg.Features().Contains(flag) {
+			return nil, errors.NewNotSupported(nil, fmt.Sprintf("feature %q not enabled for the model", flag))
+		}
+		return wrapped(context)
+	}
+	err = f.register(name, version, record{
+		factory:      gated,
+		facadeType:   facadeType,
+		modelFeature: flag,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
 // Register adds a single named facade at a given version to the registry.
 // Factory will be called when someone wants to instantiate an object of
 // this facade, and facadeType defines the concrete type that the returned object will be.
 // The Type information is used to define what methods will be exported in the
 // API, and it must exactly match the actual object returned by the factory.
 func (f *Registry) Register(name string, version int, factory Factory, facadeType reflect.Type) error {
-	if f.facades == nil {
-		f.facades = make(map[string]versions, 1)
-	}
-	record := record{
+	return f.register(name, version, record{
 		factory:    factory,
 		facadeType: facadeType,
+	})
+}
+
+func (f *Registry) register(name string, version int, record record) error {
+	if f.facades == nil {
+		f.facades = make(map[string]versions, 1)
 	}
 	if vers, ok := f.facades[name]; ok {
 		if _, ok := vers[version]; ok {
@@ -109,10 +147,14 @@ type Description struct {
 }
 
 // descriptionFromVersions aggregates the information in a versions map into a
-// more friendly form for List().
-func descriptionFromVersions(name string, vers versions) Description {
+// more friendly form for List(). If features is not nil, versions that
+// need a model feature flag not in features are left out.
+func descriptionFromVersions(name string, vers versions, features set.Strings) Description {
 	intVersions := make([]int, 0, len(vers))
-	for version := range vers {
+	for version, record := range vers {
+		if features != nil && record.modelFeature != "" && !features.Contains(record.modelFeature) {
+			continue
+		}
 		intVersions = append(intVersions, version)
 	}
 	sort.Ints(intVersions)
@@ -124,6 +166,19 @@ func descriptionFromVersions(name string, vers versions) Description {
 
 // List returns a slice describing each of the registered Facades.
 func (f *Registry) List() []Description {
+	return f.list(nil)
+}
+
+// ListForModel is like List, but leaves out the facades that are not
+// available to a model with the supplied feature flags enabled.
+func (f *Registry) ListForModel(features set.Strings) []Description {
+	if features == nil {
+		features = set.NewStrings()
+	}
+	return f.list(features)
+}
+
+func (f *Registry) list(features set.Strings) []Description {
 	names := make([]string, 0, len(f.facades))
 	for name := range f.facades {
 		names = append(names, name)
@@ -132,7 +187,7 @@ func (f *Registry) List() []Description {
 	descriptions := make([]Description, 0, len(f.facades))
 	for _, name := range names {
 		facades := f.facades[name]
-		description := descriptionFromVersions(name, facades)
+		description := descriptionFromVersions(name, facades, features)
 		if len(description.Versions) > 0 {
 			descriptions = append(descriptions, description)
 		}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/set"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/common"
@@ -261,6 +262,26 @@ func (s *RegistrySuite) TestRegisterStandardError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `badtest\(0\) not found`)
 }
 
+func (s *RegistrySuite) TestRegisterForModelFeature(c *gc.C) {
+	registry := &facade.Registry{}
+	assertRegister(c, registry, "testing", 0)
+	err := registry.RegisterForModelFeature("testing", 1, validFactory, "experiment")
+	c.Assert(err, jc.ErrorIsNil)
+	facadeType, err := registry.GetType("testing", 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(facadeType, gc.Equals, intPtrType)
+
+	c.Check(registry.List(), jc.DeepEquals, []facade.Description{
+		{Name: "testing", Versions: []int{0, 1}},
+	})
+	c.Check(registry.ListForModel(nil), jc.DeepEquals, []facade.Description{
+		{Name: "testing", Versions: []int{0}},
+	})
+	c.Check(registry.ListForModel(set.NewStrings("experiment")), jc.DeepEquals, []facade.Description{
+		{Name: "testing", Versions: []int{0, 1}},
+	})
+}
+
 func assertRegister(c *gc.C, registry *facade.Registry, name string, version int) {
 	assertRegisterFlag(c, registry, name, version)
 }
@@ -4,6 +4,8 @@
 package keyupdater
 
 import (
+	"strings"
+
 	"github.com/juju/errors"
 	"github.com/juju/utils/ssh"
 	"gopkg.in/juju/names.v2"
@@ -11,6 +13,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/sshkeys"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/watcher"
 )
@@ -55,9 +58,11 @@ func NewKeyUpdaterAPI(
 }
 
 // WatchAuthorisedKeys starts a watcher to track changes to the authorised ssh keys
-// for the specified machines.
-// The current implementation relies on global authorised keys being stored in the environment config.
-// This will change as new user management and authorisation functionality is added.
+// for the specified machines. The keys are stored in the model config, but the
+// watcher also triggers when user access changes, as keys belonging to a user are
+// only authorised while that user has access to the model's machines, and when
+// the controller config changes, as the ssh bastion's key is only authorised
+// while the bastion is enabled.
 func (api *KeyUpdaterAPI) WatchAuthorisedKeys(arg params.Entities) (params.NotifyWatchResults, error) {
 	results := make([]params.NotifyWatchResult, len(arg.Entities))
 
@@ -86,7 +91,12 @@ func (api *KeyUpdaterAPI) WatchAuthorisedKeys(arg params.Entities) (params.Notif
 			continue
 		}
 		// 3. Watch for changes
-		watch := api.model.WatchForModelConfigChanges()
+		watch := common.NewMultiNotifyWatcher(
+			api.model.WatchForModelConfigChanges(),
+			api.model.WatchUserAccess(),
+			api.state.WatchUsers(),
+			api.state.WatchControllerConfig(),
+		)
 		// Consume the initial event.
 		if _, ok := <-watch.Changes(); ok {
 			results[i].NotifyWatcherId = api.resources.Register(watch)
@@ -99,8 +109,10 @@ func (api *KeyUpdaterAPI) WatchAuthorisedKeys(arg params.Entities) (params.Notif
 }
 
 // AuthorisedKeys reports the authorised ssh keys for the specified machines.
-// The current implementation relies on global authorised keys being stored in the environment config.
-// This will change as new user management and authorisation functionality is added.
+// The current implementation relies on authorised keys being stored in the model config;
+// keys belonging to a particular user are omitted unless that user currently has admin
+// access to the model. The model's bastion key is included while the controller's
+// ssh bastion is enabled.
 func (api *KeyUpdaterAPI) AuthorisedKeys(arg params.Entities) (params.StringsResults, error) {
 	if len(arg.Entities) == 0 {
 		return params.StringsResults{}, nil
@@ -111,7 +123,10 @@ func (api *KeyUpdaterAPI) AuthorisedKeys(arg params.Entities) (params.StringsRes
 	var keys []string
 	config, configErr := api.model.ModelConfig()
 	if configErr == nil {
-		keys = ssh.SplitAuthorisedKeys(config.AuthorizedKeys())
+		keys, configErr = api.machineKeys(ssh.SplitAuthorisedKeys(config.AuthorizedKeys()))
+	}
+	if configErr == nil {
+		keys, configErr = api.addBastionKey(keys)
 	}
 
 	canRead, err := api.getCanRead()
@@ -148,3 +163,49 @@ func (api *KeyUpdaterAPI) AuthorisedKeys(arg params.Entities) (params.StringsRes
 	}
 	return params.StringsResults{Results: results}, nil
 }
+
+// machineKeys returns those of the supplied keys that should be installed
+// on the model's machines. Keys that belong to no user in particular are
+// always included; keys that belong to a user are included only while that
+// user is able to ssh to the model's machines.
+func (api *KeyUpdaterAPI) machineKeys(keys []string) ([]string, error) {
+	canSSH := make(map[string]bool)
+	var result []string
+	for _, key := range keys {
+		owner := sshkeys.Owner(key)
+		if owner == "" {
+			result = append(result, key)
+			continue
+		}
+		ok, found := canSSH[owner]
+		if !found {
+			var err error
+			if ok, err = common.UserCanSSH(api.state, owner, api.model.ModelTag()); err != nil {
+				return nil, errors.Annotatef(err, "checking access for ssh key owner %q", owner)
+			}
+			canSSH[owner] = ok
+		}
+		if ok {
+			result = append(result, key)
+		}
+	}
+	return result, nil
+}
+
+// addBastionKey returns the supplied keys with the public half of the
+// model's bastion key appended, if the controller's ssh bastion is
+// enabled.
+func (api *KeyUpdaterAPI) addBastionKey(keys []string) ([]string, error) {
+	controllerConfig, err := api.state.ControllerConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if controllerConfig.BastionPort() == 0 {
+		return keys, nil
+	}
+	bastionKey, err := api.state.BastionKey()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return append(keys, strings.TrimSpace(bastionKey.PublicKey)), nil
+}
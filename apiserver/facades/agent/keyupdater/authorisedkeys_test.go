@@ -4,7 +4,10 @@
 package keyupdater_test
 
 import (
+	"strings"
+
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/set"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
@@ -13,8 +16,10 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing/factory"
 )
 
 type authorisedKeysSuite struct {
@@ -113,6 +118,33 @@ func (s *authorisedKeysSuite) TestWatchAuthorisedKeys(c *gc.C) {
 	wc.AssertClosed()
 }
 
+func (s *authorisedKeysSuite) TestWatchAuthorisedKeysUserAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
+	}
+	results, err := s.keyupdater.WatchAuthorisedKeys(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	resource := s.resources.Get(results.Results[0].NotifyWatcherId)
+	c.Assert(resource, gc.NotNil)
+
+	w := resource.(state.NotifyWatcher)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertNoChange()
+
+	_, err = s.State.SetUserAccess(user.UserTag(), s.IAASModel.ModelTag(), permission.ReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.State.RemoveUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
 func (s *authorisedKeysSuite) TestAuthorisedKeysForNoone(c *gc.C) {
 	// Not an error to request nothing, dumb, but not an error.
 	results, err := s.keyupdater.AuthorisedKeys(params.Entities{})
@@ -140,3 +172,76 @@ func (s *authorisedKeysSuite) TestAuthorisedKeys(c *gc.C) {
 		},
 	})
 }
+
+func (s *authorisedKeysSuite) TestAuthorisedKeysOfUsers(c *gc.C) {
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "mary", Access: permission.ReadAccess})
+	disabled := s.Factory.MakeUser(c, &factory.UserParams{Name: "fred"})
+	err := disabled.Disable()
+	c.Assert(err, jc.ErrorIsNil)
+	removed := s.Factory.MakeUser(c, &factory.UserParams{Name: "jim"})
+	err = s.State.RemoveUser(removed.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.setAuthorizedKeys(c, strings.Join([]string{
+		"key1",
+		"key2 # juju-user bob",
+		"key3 # juju-user mary",
+		"key4 # juju-user fred",
+		"key5 # juju-user jim",
+		"key6 # juju-user nobody",
+		"key7 # juju-user admin",
+	}, "\n"))
+
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
+	}
+	results, err := s.keyupdater.AuthorisedKeys(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.StringsResults{
+		Results: []params.StringsResult{
+			{Result: []string{"key1", "key2 # juju-user bob", "key7 # juju-user admin"}},
+		},
+	})
+}
+
+type bastionKeysSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&bastionKeysSuite{})
+
+func (s *bastionKeysSuite) SetUpTest(c *gc.C) {
+	s.ControllerConfigAttrs = map[string]interface{}{
+		"bastion-port": 17023,
+	}
+	s.JujuConnSuite.SetUpTest(c)
+}
+
+func (s *bastionKeysSuite) TestAuthorisedKeysIncludeHostedModelBastionKey(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	machine, err := st.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	resources := common.NewResources()
+	defer resources.StopAll()
+	api, err := keyupdater.NewKeyUpdaterAPI(st, resources, apiservertesting.FakeAuthorizer{
+		Tag: machine.Tag(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := api.AuthorisedKeys(params.Entities{
+		Entities: []params.Entity{{Tag: machine.Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+
+	bastionKey, err := st.BastionKey()
+	c.Assert(err, jc.ErrorIsNil)
+	controllerBastionKey, err := s.State.BastionKey()
+	c.Assert(err, jc.ErrorIsNil)
+	keys := set.NewStrings(results.Results[0].Result...)
+	c.Check(keys.Contains(strings.TrimSpace(bastionKey.PublicKey)), jc.IsTrue)
+	c.Check(keys.Contains(strings.TrimSpace(controllerBastionKey.PublicKey)), jc.IsFalse)
+}
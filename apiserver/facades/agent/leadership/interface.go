@@ -20,3 +20,13 @@ type LeadershipService interface {
 	// released for the given service.
 	BlockUntilLeadershipReleased(ApplicationTag names.ApplicationTag) (params.ErrorResult, error)
 }
+
+// LeadershipServiceV3 extends LeadershipService with the controller's
+// leadership lease settings.
+type LeadershipServiceV3 interface {
+	LeadershipService
+
+	// LeaseSettings returns the duration for which units should claim
+	// leadership, and how often leaders should renew their claims.
+	LeaseSettings() (params.LeadershipLeaseSettings, error)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/leadership"
 	"github.com/juju/juju/state"
 )
@@ -54,6 +55,49 @@ func NewLeadershipService(
 	}, nil
 }
 
+// NewLeadershipServiceFacadeV3 constructs a new LeadershipServiceV3 and
+// presents a signature that can be used for facade registration.
+func NewLeadershipServiceFacadeV3(
+	state *state.State, resources facade.Resources, authorizer facade.Authorizer,
+) (LeadershipServiceV3, error) {
+	return NewLeadershipServiceV3(state.LeadershipClaimer(), state.ControllerConfig, authorizer)
+}
+
+// NewLeadershipServiceV3 constructs a new LeadershipServiceV3, which
+// reads the lease settings from the supplied controller config.
+func NewLeadershipServiceV3(
+	claimer leadership.Claimer,
+	controllerConfig func() (controller.Config, error),
+	authorizer facade.Authorizer,
+) (LeadershipServiceV3, error) {
+	service, err := NewLeadershipService(claimer, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &leadershipServiceV3{
+		leadershipService: service.(*leadershipService),
+		controllerConfig:  controllerConfig,
+	}, nil
+}
+
+// leadershipServiceV3 implements the LeadershipServiceV3 interface.
+type leadershipServiceV3 struct {
+	*leadershipService
+	controllerConfig func() (controller.Config, error)
+}
+
+// LeaseSettings is part of the LeadershipServiceV3 interface.
+func (m *leadershipServiceV3) LeaseSettings() (params.LeadershipLeaseSettings, error) {
+	cfg, err := m.controllerConfig()
+	if err != nil {
+		return params.LeadershipLeaseSettings{}, errors.Trace(err)
+	}
+	return params.LeadershipLeaseSettings{
+		LeaseDurationSeconds:   cfg.LeadershipLeaseDuration().Seconds(),
+		RenewalIntervalSeconds: cfg.LeadershipRenewalInterval().Seconds(),
+	}, nil
+}
+
 // leadershipService implements the LeadershipService interface and
 // is the concrete implementation of the API endpoint.
 type leadershipService struct {
@@ -21,6 +21,7 @@ import (
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/facades/agent/leadership"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
 	coreleadership "github.com/juju/juju/core/leadership"
 )
 
@@ -277,3 +278,34 @@ func (s *leadershipSuite) TestCreateUnauthorized(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, "permission denied")
 	c.Check(err, jc.Satisfies, errors.IsUnauthorized)
 }
+
+func (s *leadershipSuite) TestLeaseSettings(c *gc.C) {
+	authorizer := stubAuthorizer{tag: names.NewUnitTag(StubUnitNm)}
+	controllerConfig := func() (controller.Config, error) {
+		return controller.Config{
+			controller.LeadershipLeaseDuration:   "2m",
+			controller.LeadershipRenewalInterval: "45s",
+		}, nil
+	}
+	ldrSvc, err := leadership.NewLeadershipServiceV3(&stubClaimer{}, controllerConfig, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	settings, err := ldrSvc.LeaseSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(settings, jc.DeepEquals, params.LeadershipLeaseSettings{
+		LeaseDurationSeconds:   120,
+		RenewalIntervalSeconds: 45,
+	})
+}
+
+func (s *leadershipSuite) TestLeaseSettingsError(c *gc.C) {
+	authorizer := stubAuthorizer{tag: names.NewUnitTag(StubUnitNm)}
+	controllerConfig := func() (controller.Config, error) {
+		return nil, errors.New("boom")
+	}
+	ldrSvc, err := leadership.NewLeadershipServiceV3(&stubClaimer{}, controllerConfig, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = ldrSvc.LeaseSettings()
+	c.Check(err, gc.ErrorMatches, "boom")
+}
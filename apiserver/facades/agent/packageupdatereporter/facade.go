@@ -0,0 +1,83 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package packageupdatereporter implements the API facade used by the
+// packageupdatereporter worker.
+package packageupdatereporter
+
+import (
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the State API used by the packageupdatereporter
+// facade.
+type Backend interface {
+	Machine(id string) (Machine, error)
+}
+
+// Machine defines the machine methods used by the
+// packageupdatereporter facade.
+type Machine interface {
+	SetPackageUpdates(state.MachinePackageUpdates) error
+}
+
+// Facade implements the API required by the packageupdatereporter
+// worker.
+type Facade struct {
+	backend      Backend
+	getCanModify common.GetAuthFunc
+}
+
+// New returns a new API facade for the packageupdatereporter worker.
+func New(backend Backend, _ facade.Resources, authorizer facade.Authorizer) (*Facade, error) {
+	if !authorizer.AuthMachineAgent() {
+		return nil, common.ErrPerm
+	}
+	return &Facade{
+		backend: backend,
+		getCanModify: func() (common.AuthFunc, error) {
+			return authorizer.AuthOwner, nil
+		},
+	}, nil
+}
+
+// ReportUpdates records the package updates pending on one or more
+// machines.
+func (facade *Facade) ReportUpdates(args params.SetPackageUpdates) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Updates)),
+	}
+
+	canModify, err := facade.getCanModify()
+	if err != nil {
+		return results, err
+	}
+
+	for i, arg := range args.Updates {
+		tag, err := names.ParseMachineTag(arg.Tag)
+		if err != nil || !canModify(tag) {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		results.Results[i].Error = common.ServerError(facade.setUpdates(tag, arg))
+	}
+	return results, nil
+}
+
+func (facade *Facade) setUpdates(tag names.MachineTag, arg params.PackageUpdates) error {
+	machine, err := facade.backend.Machine(tag.Id())
+	if err != nil {
+		return err
+	}
+	return machine.SetPackageUpdates(state.MachinePackageUpdates{
+		Pending:        arg.Pending,
+		Security:       arg.Security,
+		RebootRequired: arg.RebootRequired,
+		Updated:        arg.Updated,
+	})
+}
@@ -0,0 +1,111 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package packageupdatereporter_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/agent/packageupdatereporter"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+type facadeSuite struct {
+	testing.BaseSuite
+	backend    *mockBackend
+	authorizer *apiservertesting.FakeAuthorizer
+	facade     *packageupdatereporter.Facade
+}
+
+var _ = gc.Suite(&facadeSuite{})
+
+func (s *facadeSuite) SetUpTest(c *gc.C) {
+	s.backend = &mockBackend{}
+	s.authorizer = &apiservertesting.FakeAuthorizer{Tag: names.NewMachineTag("1")}
+	facade, err := packageupdatereporter.New(s.backend, nil, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	s.facade = facade
+}
+
+func (s *facadeSuite) TestNewRequiresMachineAgent(c *gc.C) {
+	s.authorizer.Tag = names.NewUnitTag("mysql/0")
+	_, err := packageupdatereporter.New(s.backend, nil, s.authorizer)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *facadeSuite) TestReportUpdates(c *gc.C) {
+	now := time.Date(2018, 4, 3, 12, 0, 0, 0, time.UTC)
+	args := params.SetPackageUpdates{
+		Updates: []params.PackageUpdates{{
+			Tag:     names.NewMachineTag("0").String(),
+			Pending: 1,
+		}, {
+			Tag:            names.NewMachineTag("1").String(),
+			Pending:        7,
+			Security:       2,
+			RebootRequired: true,
+			Updated:        now,
+		}, {
+			Tag: "application-mysql",
+		}},
+	}
+	result, err := s.facade.ReportUpdates(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{nil},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+	s.backend.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"Machine", []interface{}{"1"}},
+		{"SetPackageUpdates", []interface{}{state.MachinePackageUpdates{
+			Pending:        7,
+			Security:       2,
+			RebootRequired: true,
+			Updated:        now,
+		}}},
+	})
+}
+
+func (s *facadeSuite) TestReportUpdatesError(c *gc.C) {
+	s.backend.stub.SetErrors(nil, errors.New("boom"))
+	result, err := s.facade.ReportUpdates(params.SetPackageUpdates{
+		Updates: []params.PackageUpdates{{Tag: names.NewMachineTag("1").String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.ErrorMatches, "boom")
+}
+
+type mockBackend struct {
+	stub jujutesting.Stub
+}
+
+func (backend *mockBackend) Machine(id string) (packageupdatereporter.Machine, error) {
+	backend.stub.AddCall("Machine", id)
+	if err := backend.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	return &mockMachine{&backend.stub}, nil
+}
+
+type mockMachine struct {
+	stub *jujutesting.Stub
+}
+
+func (m *mockMachine) SetPackageUpdates(updates state.MachinePackageUpdates) error {
+	m.stub.AddCall("SetPackageUpdates", updates)
+	return m.stub.NextErr()
+}
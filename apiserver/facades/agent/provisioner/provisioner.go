@@ -120,7 +120,7 @@ func NewProvisionerAPI(st *state.State, resources facade.Resources, authorizer f
 		PasswordChanger:         common.NewPasswordChanger(st, getAuthFunc),
 		LifeGetter:              common.NewLifeGetter(st, getAuthFunc),
 		StateAddresser:          common.NewStateAddresser(st),
-		APIAddresser:            common.NewAPIAddresser(st, resources),
+		APIAddresser:            common.NewAgentAPIAddresser(st, resources),
 		ModelWatcher:            common.NewModelWatcher(model, resources, authorizer),
 		ModelMachinesWatcher:    common.NewModelMachinesWatcher(st, resources, authorizer),
 		ControllerConfigAPI:     common.NewStateControllerConfig(st),
@@ -153,6 +153,20 @@ func NewProvisionerAPIV5(st *state.State, resources facade.Resources, authorizer
 	return &ProvisionerAPIV5{provisionerAPI}, nil
 }
 
+// ProvisionerAPIV6 provides v6 of the provisioner facade.
+type ProvisionerAPIV6 struct {
+	*ProvisionerAPIV5
+}
+
+// NewProvisionerAPIV6 creates a new server-side Provisioner API facade.
+func NewProvisionerAPIV6(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*ProvisionerAPIV6, error) {
+	provisionerAPI, err := NewProvisionerAPIV5(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ProvisionerAPIV6{provisionerAPI}, nil
+}
+
 func (p *ProvisionerAPI) getMachine(canAccess common.AuthFunc, tag names.MachineTag) (*state.Machine, error) {
 	if !canAccess(tag) {
 		return nil, common.ErrPerm
@@ -291,6 +305,8 @@ func (p *ProvisionerAPI) ContainerConfig() (params.ContainerConfig, error) {
 	result.Proxy = config.ProxySettings()
 	result.AptProxy = config.AptProxySettings()
 	result.AptMirror = config.AptMirror()
+	result.CloudInitUserData = config.CloudInitUserData()
+	result.CACertificates = config.CustomCACertificates()
 
 	return result, nil
 }
@@ -542,6 +558,26 @@ func (p *ProvisionerAPIV5) DistributionGroupByMachineId(args params.Entities) (p
 	return result, nil
 }
 
+// ApplicationZones returns, for each given application entity, the
+// availability zones of the provisioned machines hosting its units.
+// The provisioner uses them to resolve machine-with placement
+// expressions.
+func (p *ProvisionerAPIV6) ApplicationZones(args params.Entities) (params.StringsResults, error) {
+	result := params.StringsResults{
+		Results: make([]params.StringsResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseApplicationTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		result.Results[i].Result, err = p.st.ApplicationZones(tag.Id())
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 // environManagerMachineIds returns a slice of all other environ manager machine.Ids.
 func environManagerMachineIds(st *state.State, m *state.Machine) ([]string, error) {
 	info, err := st.ControllerInfo()
@@ -1093,6 +1093,42 @@ func (s *withoutControllerSuite) TestDistributionGroupByMachineIdEnvironManagerA
 	})
 }
 
+func (s *withoutControllerSuite) TestApplicationZones(c *gc.C) {
+	app := s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	for i, zone := range []string{"zone-b", "zone-a", "zone-b", ""} {
+		unit, err := app.AddUnit(state.AddUnitParams{})
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.AssignToMachine(s.machines[i])
+		c.Assert(err, jc.ErrorIsNil)
+		if zone == "" {
+			// Machine 3 remains unprovisioned.
+			continue
+		}
+		hc := instance.MustParseHardware("availability-zone=" + zone)
+		err = s.machines[i].SetProvisioned(instance.Id(fmt.Sprintf("inst-%d", i)), "nonce", &hc)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "application-mysql"},
+		{Tag: "application-wordpress"},
+		{Tag: "application-haproxy"},
+		{Tag: "machine-0"},
+	}}
+	provisionerV6 := provisioner.ProvisionerAPIV6{&provisioner.ProvisionerAPIV5{s.provisioner}}
+	result, err := provisionerV6.ApplicationZones(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringsResults{
+		Results: []params.StringsResult{
+			{Result: []string{"zone-a", "zone-b"}},
+			{Result: []string{}},
+			{Error: apiservertesting.NotFoundError(`application "haproxy"`)},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
 func (s *withoutControllerSuite) TestDistributionGroupByMachineIdMachineAgentAuth(c *gc.C) {
 	anAuthorizer := s.authorizer
 	anAuthorizer.Tag = names.NewMachineTag("1")
@@ -128,6 +128,7 @@ func (api *ProxyUpdaterAPI) proxyConfig() params.ProxyConfigResult {
 	proxySettings.AutoNoProxy = network.APIHostPortsToNoProxyString(apiHostPorts)
 	result.ProxySettings = proxyUtilsSettingsToProxySettingsParam(proxySettings)
 	result.APTProxySettings = proxyUtilsSettingsToProxySettingsParam(env.AptProxySettings())
+	result.CACertificates = env.CustomCACertificates()
 	return result
 }
 
@@ -142,6 +142,16 @@ func (s *ProxyUpdaterSuite) TestProxyConfigExtendsExisting(c *gc.C) {
 	})
 }
 
+func (s *ProxyUpdaterSuite) TestProxyConfigCACertificates(c *gc.C) {
+	s.state.SetModelConfig(coretesting.Attrs{
+		"custom-ca-certificates": []interface{}{coretesting.CACert},
+	})
+	cfg := s.facade.ProxyConfig(s.oneEntity())
+	c.Assert(cfg.Results, gc.HasLen, 1)
+	c.Assert(cfg.Results[0].Error, gc.IsNil)
+	c.Assert(cfg.Results[0].CACertificates, jc.DeepEquals, []string{coretesting.CACert})
+}
+
 func (s *ProxyUpdaterSuite) TestProxyConfigNoDuplicates(c *gc.C) {
 	// Check that the ProxyConfig combines data from ModelConfig and APIHostPorts
 	s.state.SetModelConfig(coretesting.Attrs{
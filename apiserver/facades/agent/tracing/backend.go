@@ -0,0 +1,31 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tracing
+
+import (
+	"time"
+
+	"gopkg.in/juju/names.v2"
+
+	coretracing "github.com/juju/juju/core/tracing"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state functionality required by the Tracing
+// facade. It is implemented by *state.Model.
+type Backend interface {
+	// ModelTag returns the tag of the model.
+	ModelTag() names.ModelTag
+
+	// ModelConfig returns the model's config.
+	ModelConfig() (*config.Config, error)
+
+	// RecordSlowSpans records the spans of slow operations, and
+	// prunes spans older than maxAge.
+	RecordSlowSpans(spans []coretracing.Span, maxAge time.Duration) error
+
+	// SlowSpans returns the recorded spans that match the filter.
+	SlowSpans(filter state.SlowSpansFilter) ([]coretracing.Span, error)
+}
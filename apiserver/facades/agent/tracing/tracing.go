@@ -0,0 +1,158 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package tracing provides the Tracing facade, through which agents
+// record the spans of slow operations, such as hook executions and
+// cloud provider calls, and through which clients retrieve them.
+package tracing
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	coretracing "github.com/juju/juju/core/tracing"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.tracing")
+
+// API provides the Tracing facade for v1.
+type API struct {
+	backend    Backend
+	authorizer facade.Authorizer
+	exporter   coretracing.Recorder
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	model, err := ctx.State().Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewAPI(model, ctx.Auth(), ctx.SpanExporter())
+}
+
+// NewAPI returns a new Tracing API facade. If exporter is not nil, all
+// the spans recorded, slow or not, are also passed to it.
+func NewAPI(backend Backend, authorizer facade.Authorizer, exporter coretracing.Recorder) (*API, error) {
+	if !authorizer.AuthClient() && !authorizer.AuthMachineAgent() && !authorizer.AuthUnitAgent() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		authorizer: authorizer,
+		exporter:   exporter,
+	}, nil
+}
+
+// RecordSpans records the spans of the operations that took at least
+// as long as the model's threshold for their kind; the others are
+// dropped. All valid spans are passed to the exporter, if any.
+//
+// Agents may only record spans for themselves, except for controller
+// agents, which may record spans for any entity.
+func (api *API) RecordSpans(args params.TraceSpans) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Spans)),
+	}
+	if !api.authorizer.AuthMachineAgent() && !api.authorizer.AuthUnitAgent() {
+		return results, common.ErrPerm
+	}
+	cfg, err := api.backend.ModelConfig()
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	var valid, slow []coretracing.Span
+	for i, arg := range args.Spans {
+		span, threshold, err := api.span(cfg, arg)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		valid = append(valid, span)
+		if threshold != 0 && span.Duration >= threshold {
+			slow = append(slow, span)
+		}
+	}
+	if err := api.backend.RecordSlowSpans(slow, cfg.MaxStatusHistoryAge()); err != nil {
+		return results, errors.Trace(err)
+	}
+	if api.exporter != nil && len(valid) > 0 {
+		if err := api.exporter.RecordSpans(valid); err != nil {
+			logger.Warningf("cannot export %d spans: %v", len(valid), err)
+		}
+	}
+	return results, nil
+}
+
+// span returns the span described by arg, and the model's threshold
+// for slow operations of its kind.
+func (api *API) span(cfg *config.Config, arg params.TraceSpan) (coretracing.Span, time.Duration, error) {
+	tag, err := names.ParseTag(arg.Entity)
+	if err != nil {
+		return coretracing.Span{}, 0, errors.Trace(err)
+	}
+	if !api.authorizer.AuthController() && !api.authorizer.AuthOwner(tag) {
+		return coretracing.Span{}, 0, common.ErrPerm
+	}
+	threshold := cfg.SlowHookThreshold()
+	switch kind := coretracing.Kind(arg.Kind); kind {
+	case coretracing.KindHook:
+	case coretracing.KindProviderCall:
+		threshold = cfg.SlowProviderCallThreshold()
+	default:
+		return coretracing.Span{}, 0, errors.NotValidf("span kind %q", kind)
+	}
+	return coretracing.Span{
+		Kind:     coretracing.Kind(arg.Kind),
+		Entity:   tag.String(),
+		Name:     arg.Name,
+		Start:    arg.Start,
+		Duration: arg.Duration,
+		Error:    arg.Error,
+	}, threshold, nil
+}
+
+// SlowSpans returns the recorded spans of slow operations in the model
+// that match the arguments, in the order they started.
+func (api *API) SlowSpans(args params.SlowSpansArgs) (params.TraceSpans, error) {
+	if !api.authorizer.AuthClient() {
+		return params.TraceSpans{}, common.ErrPerm
+	}
+	allowed, err := api.authorizer.HasPermission(permission.ReadAccess, api.backend.ModelTag())
+	if err != nil {
+		return params.TraceSpans{}, errors.Trace(err)
+	}
+	if !allowed {
+		return params.TraceSpans{}, common.ErrPerm
+	}
+	spans, err := api.backend.SlowSpans(state.SlowSpansFilter{
+		Kind:   coretracing.Kind(args.Kind),
+		Entity: args.Entity,
+	})
+	if err != nil {
+		return params.TraceSpans{}, errors.Trace(err)
+	}
+	result := params.TraceSpans{
+		Spans: make([]params.TraceSpan, len(spans)),
+	}
+	for i, span := range spans {
+		result.Spans[i] = params.TraceSpan{
+			Kind:     string(span.Kind),
+			Entity:   span.Entity,
+			Name:     span.Name,
+			Start:    span.Start,
+			Duration: span.Duration,
+			Error:    span.Error,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,249 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tracing_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/agent/tracing"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	coretracing "github.com/juju/juju/core/tracing"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type TracingSuite struct {
+	testing.IsolationSuite
+	backend    *mockBackend
+	authorizer apiservertesting.FakeAuthorizer
+	start      time.Time
+}
+
+var _ = gc.Suite(&TracingSuite{})
+
+func (s *TracingSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	cfg, err := config.New(config.UseDefaults, coretesting.FakeConfig().Merge(coretesting.Attrs{
+		"slow-hook-threshold":          "1m",
+		"slow-provider-call-threshold": "10s",
+		"max-status-history-age":       "24h",
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend = &mockBackend{cfg: cfg}
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: names.NewUnitTag("mysql/0"),
+	}
+	s.start = time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC)
+}
+
+func (s *TracingSuite) TestNewAPIRequiresAgentOrClient(c *gc.C) {
+	s.authorizer.Tag = names.NewApplicationTag("mysql")
+	_, err := tracing.NewAPI(s.backend, s.authorizer, nil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *TracingSuite) TestRecordSpans(c *gc.C) {
+	api, err := tracing.NewAPI(s.backend, s.authorizer, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := api.RecordSpans(params.TraceSpans{
+		Spans: []params.TraceSpan{{
+			Kind:     "hook",
+			Entity:   "unit-mysql-0",
+			Name:     "install",
+			Start:    s.start,
+			Duration: 5 * time.Minute,
+			Error:    "exit status 1",
+		}, {
+			Kind:     "hook",
+			Entity:   "unit-mysql-0",
+			Name:     "update-status",
+			Start:    s.start,
+			Duration: time.Second,
+		}, {
+			Kind:     "hook",
+			Entity:   "unit-mysql-1",
+			Name:     "install",
+			Start:    s.start,
+			Duration: 5 * time.Minute,
+		}, {
+			Kind:     "sleep",
+			Entity:   "unit-mysql-0",
+			Duration: 5 * time.Minute,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{},
+			{Error: &params.Error{Code: params.CodeUnauthorized, Message: "permission denied"}},
+			{Error: &params.Error{Message: `span kind "sleep" not valid`}},
+		},
+	})
+	s.backend.CheckCall(c, 1, "RecordSlowSpans", []coretracing.Span{{
+		Kind:     coretracing.KindHook,
+		Entity:   "unit-mysql-0",
+		Name:     "install",
+		Start:    s.start,
+		Duration: 5 * time.Minute,
+		Error:    "exit status 1",
+	}}, 24*time.Hour)
+}
+
+func (s *TracingSuite) TestRecordSpansController(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("0")
+	s.authorizer.Controller = true
+	api, err := tracing.NewAPI(s.backend, s.authorizer, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := api.RecordSpans(params.TraceSpans{
+		Spans: []params.TraceSpan{{
+			Kind:     "provider-call",
+			Entity:   "machine-3",
+			Name:     "StartInstance",
+			Start:    s.start,
+			Duration: 20 * time.Second,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Combine(), jc.ErrorIsNil)
+	s.backend.CheckCall(c, 1, "RecordSlowSpans", []coretracing.Span{{
+		Kind:     coretracing.KindProviderCall,
+		Entity:   "machine-3",
+		Name:     "StartInstance",
+		Start:    s.start,
+		Duration: 20 * time.Second,
+	}}, 24*time.Hour)
+}
+
+func (s *TracingSuite) TestRecordSpansDisabled(c *gc.C) {
+	cfg, err := s.backend.cfg.Apply(map[string]interface{}{
+		"slow-hook-threshold": "0",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.cfg = cfg
+	api, err := tracing.NewAPI(s.backend, s.authorizer, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.RecordSpans(params.TraceSpans{
+		Spans: []params.TraceSpan{{
+			Kind:     "hook",
+			Entity:   "unit-mysql-0",
+			Name:     "install",
+			Duration: time.Hour,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.CheckCall(c, 1, "RecordSlowSpans", []coretracing.Span(nil), 24*time.Hour)
+}
+
+func (s *TracingSuite) TestRecordSpansExported(c *gc.C) {
+	var exporter exportRecorder
+	api, err := tracing.NewAPI(s.backend, s.authorizer, &exporter)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := api.RecordSpans(params.TraceSpans{
+		Spans: []params.TraceSpan{{
+			Kind:     "hook",
+			Entity:   "unit-mysql-0",
+			Name:     "update-status",
+			Start:    s.start,
+			Duration: time.Second,
+		}, {
+			Kind:     "hook",
+			Entity:   "unit-mysql-1",
+			Name:     "install",
+			Start:    s.start,
+			Duration: 5 * time.Minute,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCall(c, 1, "RecordSlowSpans", []coretracing.Span(nil), 24*time.Hour)
+	c.Assert(exporter, jc.DeepEquals, exportRecorder{{
+		Kind:     coretracing.KindHook,
+		Entity:   "unit-mysql-0",
+		Name:     "update-status",
+		Start:    s.start,
+		Duration: time.Second,
+	}})
+}
+
+func (s *TracingSuite) TestRecordSpansRequiresAgent(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("admin")
+	api, err := tracing.NewAPI(s.backend, s.authorizer, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.RecordSpans(params.TraceSpans{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *TracingSuite) TestSlowSpans(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("read")
+	s.backend.spans = []coretracing.Span{{
+		Kind:     coretracing.KindHook,
+		Entity:   "unit-mysql-0",
+		Name:     "install",
+		Start:    s.start,
+		Duration: 5 * time.Minute,
+	}}
+	api, err := tracing.NewAPI(s.backend, s.authorizer, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.SlowSpans(params.SlowSpansArgs{Kind: "hook"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.TraceSpans{
+		Spans: []params.TraceSpan{{
+			Kind:     "hook",
+			Entity:   "unit-mysql-0",
+			Name:     "install",
+			Start:    s.start,
+			Duration: 5 * time.Minute,
+		}},
+	})
+	s.backend.CheckCall(c, 1, "SlowSpans", state.SlowSpansFilter{Kind: coretracing.KindHook})
+}
+
+func (s *TracingSuite) TestSlowSpansRequiresRead(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("bob")
+	api, err := tracing.NewAPI(s.backend, s.authorizer, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.SlowSpans(params.SlowSpansArgs{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+type mockBackend struct {
+	testing.Stub
+	cfg   *config.Config
+	spans []coretracing.Span
+}
+
+func (m *mockBackend) ModelTag() names.ModelTag {
+	m.MethodCall(m, "ModelTag")
+	return coretesting.ModelTag
+}
+
+func (m *mockBackend) ModelConfig() (*config.Config, error) {
+	m.MethodCall(m, "ModelConfig")
+	return m.cfg, m.NextErr()
+}
+
+func (m *mockBackend) RecordSlowSpans(spans []coretracing.Span, maxAge time.Duration) error {
+	m.MethodCall(m, "RecordSlowSpans", spans, maxAge)
+	return m.NextErr()
+}
+
+func (m *mockBackend) SlowSpans(filter state.SlowSpansFilter) ([]coretracing.Span, error) {
+	m.MethodCall(m, "SlowSpans", filter)
+	return m.spans, m.NextErr()
+}
+
+type exportRecorder []coretracing.Span
+
+func (r *exportRecorder) RecordSpans(spans []coretracing.Span) error {
+	*r = append(*r, spans...)
+	return nil
+}
@@ -31,7 +31,7 @@ import (
 
 var logger = loggo.GetLogger("juju.apiserver.uniter")
 
-// UniterAPI implements the latest version (v7) of the Uniter API.
+// UniterAPI implements the latest version (v9) of the Uniter API.
 type UniterAPI struct {
 	*common.LifeGetter
 	*StatusAPI
@@ -53,9 +53,20 @@ type UniterAPI struct {
 	StorageAPI
 }
 
+// UniterAPIV8 doesn't support relation departure barriers.
+type UniterAPIV8 struct {
+	UniterAPI
+}
+
+// UniterAPIV7 doesn't support opening and closing ports for specific
+// endpoints.
+type UniterAPIV7 struct {
+	UniterAPIV8
+}
+
 // UniterAPIV6 adds NetworkInfo as a preferred method to calling NetworkConfig.
 type UniterAPIV6 struct {
-	UniterAPI
+	UniterAPIV7
 }
 
 // UniterAPIV5 returns a RelationResultsV5 instead of RelationResults
@@ -149,7 +160,7 @@ func NewUniterAPI(st *state.State, resources facade.Resources, authorizer facade
 		LifeGetter:                 common.NewLifeGetter(st, accessUnitOrApplication),
 		DeadEnsurer:                common.NewDeadEnsurer(st, accessUnit),
 		AgentEntityWatcher:         common.NewAgentEntityWatcher(st, resources, accessUnitOrApplication),
-		APIAddresser:               common.NewAPIAddresser(st, resources),
+		APIAddresser:               common.NewAgentAPIAddresser(st, resources),
 		ModelWatcher:               common.NewModelWatcher(m, resources, authorizer),
 		RebootRequester:            common.NewRebootRequester(st, accessMachine),
 		LeadershipSettingsAccessor: leadershipSettingsAccessorFactory(st, resources, authorizer),
@@ -170,14 +181,36 @@ func NewUniterAPI(st *state.State, resources facade.Resources, authorizer facade
 	}, nil
 }
 
+// NewUniterAPIV8 creates an instance of the V8 uniter API.
+func NewUniterAPIV8(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*UniterAPIV8, error) {
+	uniterAPI, err := NewUniterAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV8{
+		UniterAPI: *uniterAPI,
+	}, nil
+}
+
+// NewUniterAPIV7 creates an instance of the V7 uniter API.
+func NewUniterAPIV7(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*UniterAPIV7, error) {
+	uniterAPI, err := NewUniterAPIV8(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV7{
+		UniterAPIV8: *uniterAPI,
+	}, nil
+}
+
 // NewUniterAPIV6 creates an instance of the V6 uniter API.
 func NewUniterAPIV6(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*UniterAPIV6, error) {
-	uniterAPI, err := NewUniterAPI(st, resources, authorizer)
+	uniterAPI, err := NewUniterAPIV7(st, resources, authorizer)
 	if err != nil {
 		return nil, err
 	}
 	return &UniterAPIV6{
-		UniterAPI: *uniterAPI,
+		UniterAPIV7: *uniterAPI,
 	}, nil
 }
 
@@ -778,7 +811,8 @@ func (u *UniterAPI) SetWorkloadVersion(args params.EntityWorkloadVersions) (para
 }
 
 // OpenPorts sets the policy of the port range with protocol to be
-// opened, for all given units.
+// opened, for all given units. Port ranges with an endpoint are only
+// opened for that endpoint of the unit's application.
 func (u *UniterAPI) OpenPorts(args params.EntitiesPortRanges) (params.ErrorResults, error) {
 	result := params.ErrorResults{
 		Results: make([]params.ErrorResult, len(args.Entities)),
@@ -798,7 +832,11 @@ func (u *UniterAPI) OpenPorts(args params.EntitiesPortRanges) (params.ErrorResul
 			var unit *state.Unit
 			unit, err = u.getUnit(tag)
 			if err == nil {
-				err = unit.OpenPorts(entity.Protocol, entity.FromPort, entity.ToPort)
+				if entity.Endpoint != "" {
+					err = unit.OpenPortsForEndpoint(entity.Endpoint, entity.Protocol, entity.FromPort, entity.ToPort)
+				} else {
+					err = unit.OpenPorts(entity.Protocol, entity.FromPort, entity.ToPort)
+				}
 			}
 		}
 		result.Results[i].Error = common.ServerError(err)
@@ -827,7 +865,11 @@ func (u *UniterAPI) ClosePorts(args params.EntitiesPortRanges) (params.ErrorResu
 			var unit *state.Unit
 			unit, err = u.getUnit(tag)
 			if err == nil {
-				err = unit.ClosePorts(entity.Protocol, entity.FromPort, entity.ToPort)
+				if entity.Endpoint != "" {
+					err = unit.ClosePortsForEndpoint(entity.Endpoint, entity.Protocol, entity.FromPort, entity.ToPort)
+				} else {
+					err = unit.ClosePorts(entity.Protocol, entity.FromPort, entity.ToPort)
+				}
 			}
 		}
 		result.Results[i].Error = common.ServerError(err)
@@ -1456,6 +1498,96 @@ func (u *UniterAPI) SetRelationStatus(args params.RelationStatusArgs) (params.Er
 	return statusResults, nil
 }
 
+// SetRelationDepartureBarriers sets the departure barriers of the
+// given relations, so that their provider units wait for the requirer
+// units to confirm their departure before breaking them. Only units on
+// the provider side of a relation may set its barrier.
+func (u *UniterAPI) SetRelationDepartureBarriers(args params.RelationDepartureBarrierArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Args {
+		unitTag, err := names.ParseUnitTag(arg.Unit)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		relUnit, err := u.getRelationUnit(canAccess, arg.Relation, unitTag)
+		if err == nil {
+			if relUnit.Endpoint().Role != charm.RoleProvider {
+				err = errors.Errorf("unit %q is not a provider in relation %q", unitTag.Id(), relUnit.Relation())
+			} else {
+				err = relUnit.Relation().SetDepartureBarrier(arg.Timeout)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// ConfirmRelationDepartures records that each unit has cleaned up
+// after its relation, releasing it from the relation's departure
+// barrier. Only units on the requirer side of a relation may confirm
+// their departure.
+func (u *UniterAPI) ConfirmRelationDepartures(args params.RelationUnits) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.RelationUnits)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.RelationUnits {
+		unitTag, err := names.ParseUnitTag(arg.Unit)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		rel, _, err := u.getRelationAndUnit(canAccess, arg.Relation, unitTag)
+		if err == nil {
+			err = rel.ConfirmDeparture(unitTag.Id())
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// RelationDepartureBarriersReleased reports, for each relation/unit
+// pair, whether the relation's departure barrier allows the unit to
+// break the relation. Only provider units are held by the barrier.
+func (u *UniterAPI) RelationDepartureBarriersReleased(args params.RelationUnits) (params.BoolResults, error) {
+	result := params.BoolResults{
+		Results: make([]params.BoolResult, len(args.RelationUnits)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.BoolResults{}, err
+	}
+	for i, arg := range args.RelationUnits {
+		unitTag, err := names.ParseUnitTag(arg.Unit)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		relUnit, err := u.getRelationUnit(canAccess, arg.Relation, unitTag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		released := true
+		if relUnit.Endpoint().Role == charm.RoleProvider {
+			released, err = relUnit.Relation().DepartureBarrierReleased()
+		}
+		result.Results[i].Result = released
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 // WatchUnitAddresses returns a NotifyWatcher for observing changes
 // to each unit's addresses.
 func (u *UniterAPI) WatchUnitAddresses(args params.Entities) (params.NotifyWatchResults, error) {
@@ -2189,3 +2321,14 @@ func (u *UniterAPIV4) NetworkInfo(_, _ struct{}) {}
 
 // WatchUnitRelations isn't on the V4 API.
 func (u *UniterAPIV4) WatchUnitRelations(_, _ struct{}) {}
+
+// Mask the departure barrier methods from the V8 API.
+
+// SetRelationDepartureBarriers isn't on the V8 API.
+func (u *UniterAPIV8) SetRelationDepartureBarriers(_, _ struct{}) {}
+
+// ConfirmRelationDepartures isn't on the V8 API.
+func (u *UniterAPIV8) ConfirmRelationDepartures(_, _ struct{}) {}
+
+// RelationDepartureBarriersReleased isn't on the V8 API.
+func (u *UniterAPIV8) RelationDepartureBarriersReleased(_, _ struct{}) {}
@@ -957,6 +957,31 @@ func (s *uniterSuite) TestClosePorts(c *gc.C) {
 	c.Assert(openedPorts, gc.HasLen, 0)
 }
 
+func (s *uniterSuite) TestOpenClosePortsForEndpoint(c *gc.C) {
+	args := params.EntitiesPortRanges{Entities: []params.EntityPortRange{
+		{Tag: "unit-wordpress-0", Protocol: "tcp", FromPort: 80, ToPort: 80, Endpoint: "url"},
+		{Tag: "unit-wordpress-0", Protocol: "tcp", FromPort: 81, ToPort: 81, Endpoint: "foo"},
+	}}
+	result, err := s.uniter.OpenPorts(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `.*application "wordpress" has no "foo" relation`)
+
+	ports, err := s.wordpressUnit.OpenedPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports, gc.DeepEquals, []network.PortRange{
+		{Protocol: "tcp", FromPort: 80, ToPort: 80},
+	})
+
+	result, err = s.uniter.ClosePorts(params.EntitiesPortRanges{Entities: args.Entities[:1]})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), jc.ErrorIsNil)
+	ports, err = s.wordpressUnit.OpenedPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports, gc.HasLen, 0)
+}
+
 func (s *uniterSuite) TestWatchConfigSettings(c *gc.C) {
 	err := s.wordpressUnit.SetCharmURL(s.wpCharm.URL())
 	c.Assert(err, jc.ErrorIsNil)
@@ -1934,6 +1959,116 @@ func (s *uniterSuite) TestLeaveScope(c *gc.C) {
 	c.Assert(readSettings, gc.DeepEquals, settings)
 }
 
+func (s *uniterSuite) TestSetRelationDepartureBarriers(c *gc.C) {
+	rel := s.addRelation(c, "wordpress", "mysql")
+	mysqlUniter := s.makeMysqlUniter(c)
+
+	args := params.RelationDepartureBarrierArgs{Args: []params.RelationDepartureBarrierArg{
+		{Relation: rel.Tag().String(), Unit: "unit-mysql-0", Timeout: time.Minute},
+		{Relation: rel.Tag().String(), Unit: "unit-wordpress-0", Timeout: time.Minute},
+		{Relation: "relation-42", Unit: "unit-mysql-0", Timeout: time.Minute},
+		{Relation: rel.Tag().String(), Unit: "application-mysql", Timeout: time.Minute},
+	}}
+	result, err := mysqlUniter.SetRelationDepartureBarriers(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = rel.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	barrier, err := rel.DepartureBarrier()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(barrier.Timeout, gc.Equals, time.Minute)
+
+	// Requirer units may not set the barrier.
+	args = params.RelationDepartureBarrierArgs{Args: []params.RelationDepartureBarrierArg{
+		{Relation: rel.Tag().String(), Unit: "unit-wordpress-0", Timeout: time.Minute},
+	}}
+	result, err = s.uniter.SetRelationDepartureBarriers(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.ErrorMatches, `unit "wordpress/0" is not a provider in relation "wordpress:db mysql:server"`)
+}
+
+func (s *uniterSuite) TestConfirmRelationDepartures(c *gc.C) {
+	rel := s.addRelation(c, "wordpress", "mysql")
+	err := rel.SetDepartureBarrier(time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.RelationUnits{RelationUnits: []params.RelationUnit{
+		{Relation: rel.Tag().String(), Unit: "unit-wordpress-0"},
+		{Relation: rel.Tag().String(), Unit: "unit-mysql-0"},
+		{Relation: "relation-42", Unit: "unit-wordpress-0"},
+	}}
+	result, err := s.uniter.ConfirmRelationDepartures(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = rel.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	barrier, err := rel.DepartureBarrier()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(barrier.Confirmed, jc.DeepEquals, []string{"wordpress/0"})
+}
+
+func (s *uniterSuite) TestRelationDepartureBarriersReleased(c *gc.C) {
+	rel := s.addRelation(c, "wordpress", "mysql")
+	for _, unit := range []*state.Unit{s.wordpressUnit, s.mysqlUnit} {
+		relUnit, err := rel.Unit(unit)
+		c.Assert(err, jc.ErrorIsNil)
+		err = relUnit.EnterScope(nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := rel.SetDepartureBarrier(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	mysqlArgs := params.RelationUnits{RelationUnits: []params.RelationUnit{
+		{Relation: rel.Tag().String(), Unit: "unit-mysql-0"},
+	}}
+	mysqlUniter := s.makeMysqlUniter(c)
+	result, err := mysqlUniter.RelationDepartureBarriersReleased(mysqlArgs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{{Result: false}},
+	})
+
+	// The barrier never holds requirer units.
+	result, err = s.uniter.RelationDepartureBarriersReleased(params.RelationUnits{
+		RelationUnits: []params.RelationUnit{
+			{Relation: rel.Tag().String(), Unit: "unit-wordpress-0"},
+			{Relation: rel.Tag().String(), Unit: "unit-mysql-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{
+			{Result: true},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = rel.ConfirmDeparture("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = mysqlUniter.RelationDepartureBarriersReleased(mysqlArgs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{{Result: true}},
+	})
+}
+
 func (s *uniterSuite) TestRelationsSuspended(c *gc.C) {
 	rel := s.addRelation(c, "wordpress", "mysql")
 	relUnit, err := rel.Unit(s.wordpressUnit)
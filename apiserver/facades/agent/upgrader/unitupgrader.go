@@ -87,6 +87,13 @@ func (u *UnitUpgraderAPI) WatchAPIVersion(args params.Entities) (params.NotifyWa
 	return result, nil
 }
 
+// AgentBinaryTrustedKeys returns the armored OpenPGP public keys that
+// agent binaries must be signed with, or an empty result when the
+// controller does not require signed agent binaries.
+func (u *UnitUpgraderAPI) AgentBinaryTrustedKeys() (params.StringResult, error) {
+	return agentBinaryTrustedKeys(u.st)
+}
+
 // DesiredVersion reports the Agent Version that we want that unit to be running.
 // The desired version is what the unit's assigned machine is running.
 func (u *UnitUpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResults, error) {
@@ -4,8 +4,11 @@
 package upgrader
 
 import (
+	"fmt"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
 	"github.com/juju/version"
 	"gopkg.in/juju/names.v2"
 
@@ -40,7 +43,7 @@ func NewUpgraderFacade(st *state.State, resources facade.Resources, auth facade.
 	}
 	switch tag.(type) {
 	case names.MachineTag:
-		return NewUpgraderAPI(st, resources, auth)
+		return NewUpgraderAPI(st, resources, auth, clock.WallClock)
 	case names.UnitTag:
 		return NewUnitUpgraderAPI(st, resources, auth)
 	}
@@ -53,6 +56,36 @@ type Upgrader interface {
 	DesiredVersion(args params.Entities) (params.VersionResults, error)
 	Tools(args params.Entities) (params.ToolsResults, error)
 	SetTools(args params.EntitiesVersion) (params.ErrorResults, error)
+	AgentBinaryTrustedKeys() (params.StringResult, error)
+}
+
+// UpgraderV1 serves the version 1 Upgrader facade, which does not
+// report the keys agent binaries must be signed with.
+type UpgraderV1 struct {
+	Upgrader
+}
+
+// NewUpgraderFacadeV1 provides the signature required for version 1
+// facade registration.
+func NewUpgraderFacadeV1(st *state.State, resources facade.Resources, auth facade.Authorizer) (*UpgraderV1, error) {
+	upgrader, err := NewUpgraderFacade(st, resources, auth)
+	if err != nil {
+		return nil, err
+	}
+	return &UpgraderV1{upgrader}, nil
+}
+
+// AgentBinaryTrustedKeys isn't on the V1 API.
+func (*UpgraderV1) AgentBinaryTrustedKeys(_, _ struct{}) {}
+
+// agentBinaryTrustedKeys returns the controller's agent binary trusted
+// keys, which the agents check downloaded binaries against.
+func agentBinaryTrustedKeys(st *state.State) (params.StringResult, error) {
+	controllerConfig, err := st.ControllerConfig()
+	if err != nil {
+		return params.StringResult{}, errors.Trace(err)
+	}
+	return params.StringResult{Result: controllerConfig.AgentBinaryTrustedKeys()}, nil
 }
 
 // UpgraderAPI provides access to the Upgrader API facade.
@@ -64,13 +97,17 @@ type UpgraderAPI struct {
 	m          *state.Model
 	resources  facade.Resources
 	authorizer facade.Authorizer
+	clock      clock.Clock
 }
 
-// NewUpgraderAPI creates a new server-side UpgraderAPI facade.
+// NewUpgraderAPI creates a new server-side UpgraderAPI facade. The
+// clock is used to tell whether the model's maintenance windows are
+// open.
 func NewUpgraderAPI(
 	st *state.State,
 	resources facade.Resources,
 	authorizer facade.Authorizer,
+	clock clock.Clock,
 ) (*UpgraderAPI, error) {
 	if !authorizer.AuthMachineAgent() {
 		return nil, common.ErrPerm
@@ -91,11 +128,13 @@ func NewUpgraderAPI(
 		m:           env,
 		resources:   resources,
 		authorizer:  authorizer,
+		clock:       clock,
 	}, nil
 }
 
 // WatchAPIVersion starts a watcher to track if there is a new version
-// of the API that we want to upgrade to
+// of the API that we want to upgrade to, or a change to the agent
+// version pins that may hold the agent back.
 func (u *UpgraderAPI) WatchAPIVersion(args params.Entities) (params.NotifyWatchResults, error) {
 	result := params.NotifyWatchResults{
 		Results: make([]params.NotifyWatchResult, len(args.Entities)),
@@ -107,7 +146,10 @@ func (u *UpgraderAPI) WatchAPIVersion(args params.Entities) (params.NotifyWatchR
 		}
 		err = common.ErrPerm
 		if u.authorizer.AuthOwner(tag) {
-			watch := u.m.WatchForModelConfigChanges()
+			watch := common.NewMultiNotifyWatcher(
+				u.m.WatchForModelConfigChanges(),
+				u.st.WatchAgentVersionPins(),
+			)
 			// Consume the initial event. Technically, API
 			// calls to Watch 'transmit' the initial event
 			// in the Watch response. But NotifyWatchers
@@ -137,6 +179,67 @@ func (u *UpgraderAPI) getGlobalAgentVersion() (version.Number, *config.Config, e
 	return agentVersion, cfg, nil
 }
 
+// heldAgentVersion returns the version the agent with the supplied tag
+// is currently running, so that it is not upgraded while the model's
+// maintenance windows are closed, or while its agent version is pinned
+// to a range excluding the desired version. If the agent has not yet
+// reported its version, the desired version is returned.
+func (u *UpgraderAPI) heldAgentVersion(tag names.Tag, desired version.Number, reason string) (*version.Number, error) {
+	entity, err := u.st.FindEntity(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tooler, ok := entity.(state.AgentTooler)
+	if !ok {
+		return &desired, nil
+	}
+	tools, err := tooler.AgentTools()
+	if errors.IsNotFound(err) {
+		return &desired, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if tools.Version.Number != desired {
+		logger.Debugf("desired version is %s, but %s; holding %s at %s", desired, reason, tag, tools.Version.Number)
+	}
+	return &tools.Version.Number, nil
+}
+
+// pinnedAgentVersion returns the desired version, unless the agent
+// version pin of the machine with the supplied tag, or of an
+// application whose units it hosts, excludes it. Agents are never
+// moved to another version to satisfy a pin; they are held at the
+// version they are running until the desired version is allowed.
+func (u *UpgraderAPI) pinnedAgentVersion(tag names.Tag, desired version.Number) (*version.Number, error) {
+	pins, err := u.st.AgentVersionPins()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(pins) == 0 {
+		return &desired, nil
+	}
+	machine, err := u.st.Machine(tag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	units, err := machine.Units()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	pinned := []names.Tag{tag}
+	for _, unit := range units {
+		pinned = append(pinned, names.NewApplicationTag(unit.ApplicationName()))
+	}
+	for _, entity := range pinned {
+		pin, ok := pins[entity.String()]
+		if ok && !pin.Allows(desired) {
+			reason := fmt.Sprintf("%s is pinned to %s", names.ReadableString(entity), pin)
+			return u.heldAgentVersion(tag, desired, reason)
+		}
+	}
+	return &desired, nil
+}
+
 type hasIsManager interface {
 	IsManager() bool
 }
@@ -153,18 +256,28 @@ func (u *UpgraderAPI) entityIsManager(tag names.Tag) bool {
 	}
 }
 
+// AgentBinaryTrustedKeys returns the armored OpenPGP public keys that
+// agent binaries must be signed with, or an empty result when the
+// controller does not require signed agent binaries.
+func (u *UpgraderAPI) AgentBinaryTrustedKeys() (params.StringResult, error) {
+	return agentBinaryTrustedKeys(u.st)
+}
+
 // DesiredVersion reports the Agent Version that we want that agent to be running
 func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResults, error) {
 	results := make([]params.VersionResult, len(args.Entities))
 	if len(args.Entities) == 0 {
 		return params.VersionResults{}, nil
 	}
-	agentVersion, _, err := u.getGlobalAgentVersion()
+	agentVersion, cfg, err := u.getGlobalAgentVersion()
 	if err != nil {
 		return params.VersionResults{}, common.ServerError(err)
 	}
 	// Is the desired version greater than the current API server version?
 	isNewerVersion := agentVersion.Compare(jujuversion.Current) > 0
+	// Upgrades of non-manager agents are held back outside the model's
+	// maintenance windows.
+	windowOpen := cfg.MaintenanceWindows().Open(u.clock.Now())
 	for i, entity := range args.Entities {
 		tag, err := names.ParseTag(entity.Tag)
 		if err != nil {
@@ -183,13 +296,19 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 			// first - once they have restarted and are running the
 			// new version other agents will start to see the new
 			// agent version.
-			if !isNewerVersion || u.entityIsManager(tag) {
-				results[i].Version = &agentVersion
-			} else {
+			err = nil
+			isManager := u.entityIsManager(tag)
+			switch {
+			case isNewerVersion && !isManager:
 				logger.Debugf("desired version is %s, but current version is %s and agent is not a manager node", agentVersion, jujuversion.Current)
 				results[i].Version = &jujuversion.Current
+			case !windowOpen && !isManager:
+				results[i].Version, err = u.heldAgentVersion(tag, agentVersion, "maintenance window is closed")
+			case !isManager:
+				results[i].Version, err = u.pinnedAgentVersion(tag, agentVersion)
+			default:
+				results[i].Version = &agentVersion
 			}
-			err = nil
 		}
 		results[i].Error = common.ServerError(err)
 	}
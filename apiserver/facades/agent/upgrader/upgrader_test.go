@@ -5,8 +5,10 @@ package upgrader_test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/arch"
 	"github.com/juju/utils/series"
@@ -18,6 +20,8 @@ import (
 	"github.com/juju/juju/apiserver/facades/agent/upgrader"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/controller"
+	sstesting "github.com/juju/juju/environs/simplestreams/testing"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
@@ -35,6 +39,7 @@ type upgraderSuite struct {
 	upgrader   *upgrader.UpgraderAPI
 	resources  *common.Resources
 	authorizer apiservertesting.FakeAuthorizer
+	clock      *testing.Clock
 }
 
 var _ = gc.Suite(&upgraderSuite{})
@@ -43,6 +48,8 @@ func (s *upgraderSuite) SetUpTest(c *gc.C) {
 	s.JujuConnSuite.SetUpTest(c)
 	s.resources = common.NewResources()
 	s.AddCleanup(func(_ *gc.C) { s.resources.StopAll() })
+	// Wednesday midday.
+	s.clock = testing.NewClock(time.Date(2018, 6, 6, 12, 0, 0, 0, time.UTC))
 
 	// Create a machine to work with
 	var err error
@@ -58,7 +65,7 @@ func (s *upgraderSuite) SetUpTest(c *gc.C) {
 	s.authorizer = apiservertesting.FakeAuthorizer{
 		Tag: s.rawMachine.Tag(),
 	}
-	s.upgrader, err = upgrader.NewUpgraderAPI(s.State, s.resources, s.authorizer)
+	s.upgrader, err = upgrader.NewUpgraderAPI(s.State, s.resources, s.authorizer, s.clock)
 	c.Assert(err, jc.ErrorIsNil)
 }
 
@@ -102,7 +109,7 @@ func (s *upgraderSuite) TestWatchAPIVersion(c *gc.C) {
 func (s *upgraderSuite) TestUpgraderAPIRefusesNonMachineAgent(c *gc.C) {
 	anAuthorizer := s.authorizer
 	anAuthorizer.Tag = names.NewUnitTag("ubuntu/1")
-	anUpgrader, err := upgrader.NewUpgraderAPI(s.State, s.resources, anAuthorizer)
+	anUpgrader, err := upgrader.NewUpgraderAPI(s.State, s.resources, anAuthorizer, s.clock)
 	c.Check(err, gc.NotNil)
 	c.Check(anUpgrader, gc.IsNil)
 	c.Assert(err, gc.ErrorMatches, "permission denied")
@@ -112,7 +119,7 @@ func (s *upgraderSuite) TestWatchAPIVersionRefusesWrongAgent(c *gc.C) {
 	// We are a machine agent, but not the one we are trying to track
 	anAuthorizer := s.authorizer
 	anAuthorizer.Tag = names.NewMachineTag("12354")
-	anUpgrader, err := upgrader.NewUpgraderAPI(s.State, s.resources, anAuthorizer)
+	anUpgrader, err := upgrader.NewUpgraderAPI(s.State, s.resources, anAuthorizer, s.clock)
 	c.Check(err, jc.ErrorIsNil)
 	args := params.Entities{
 		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
@@ -135,7 +142,7 @@ func (s *upgraderSuite) TestToolsNothing(c *gc.C) {
 func (s *upgraderSuite) TestToolsRefusesWrongAgent(c *gc.C) {
 	anAuthorizer := s.authorizer
 	anAuthorizer.Tag = names.NewMachineTag("12354")
-	anUpgrader, err := upgrader.NewUpgraderAPI(s.State, s.resources, anAuthorizer)
+	anUpgrader, err := upgrader.NewUpgraderAPI(s.State, s.resources, anAuthorizer, s.clock)
 	c.Check(err, jc.ErrorIsNil)
 	args := params.Entities{
 		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
@@ -187,7 +194,7 @@ func (s *upgraderSuite) TestSetToolsNothing(c *gc.C) {
 func (s *upgraderSuite) TestSetToolsRefusesWrongAgent(c *gc.C) {
 	anAuthorizer := s.authorizer
 	anAuthorizer.Tag = names.NewMachineTag("12354")
-	anUpgrader, err := upgrader.NewUpgraderAPI(s.State, s.resources, anAuthorizer)
+	anUpgrader, err := upgrader.NewUpgraderAPI(s.State, s.resources, anAuthorizer, s.clock)
 	c.Check(err, jc.ErrorIsNil)
 	args := params.EntitiesVersion{
 		AgentTools: []params.EntityVersion{{
@@ -247,7 +254,7 @@ func (s *upgraderSuite) TestDesiredVersionNothing(c *gc.C) {
 func (s *upgraderSuite) TestDesiredVersionRefusesWrongAgent(c *gc.C) {
 	anAuthorizer := s.authorizer
 	anAuthorizer.Tag = names.NewMachineTag("12354")
-	anUpgrader, err := upgrader.NewUpgraderAPI(s.State, s.resources, anAuthorizer)
+	anUpgrader, err := upgrader.NewUpgraderAPI(s.State, s.resources, anAuthorizer, s.clock)
 	c.Check(err, jc.ErrorIsNil)
 	args := params.Entities{
 		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
@@ -317,7 +324,7 @@ func (s *upgraderSuite) TestDesiredVersionUnrestrictedForAPIAgents(c *gc.C) {
 	authorizer := apiservertesting.FakeAuthorizer{
 		Tag: s.apiMachine.Tag(),
 	}
-	upgraderAPI, err := upgrader.NewUpgraderAPI(s.State, s.resources, authorizer)
+	upgraderAPI, err := upgrader.NewUpgraderAPI(s.State, s.resources, authorizer, s.clock)
 	c.Assert(err, jc.ErrorIsNil)
 	args := params.Entities{Entities: []params.Entity{{Tag: s.apiMachine.Tag().String()}}}
 	results, err := upgraderAPI.DesiredVersion(args)
@@ -341,3 +348,179 @@ func (s *upgraderSuite) TestDesiredVersionRestrictedForNonAPIAgents(c *gc.C) {
 	c.Assert(agentVersion, gc.NotNil)
 	c.Check(*agentVersion, gc.DeepEquals, jujuversion.Current)
 }
+
+func (s *upgraderSuite) setMaintenanceWindow(c *gc.C, window string) {
+	err := s.IAASModel.UpdateModelConfig(map[string]interface{}{
+		"maintenance-window": window,
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *upgraderSuite) closeMaintenanceWindow(c *gc.C) {
+	s.setMaintenanceWindow(c, "fri 00:00-01:00")
+}
+
+func (s *upgraderSuite) TestDesiredVersionHeldOutsideMaintenanceWindow(c *gc.C) {
+	older := version.Binary{
+		Number: version.MustParse("2.0.0"),
+		Arch:   arch.HostArch(),
+		Series: series.MustHostSeries(),
+	}
+	err := s.rawMachine.SetAgentVersion(older)
+	c.Assert(err, jc.ErrorIsNil)
+	s.closeMaintenanceWindow(c)
+
+	args := params.Entities{Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}}}
+	results, err := s.upgrader.DesiredVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	agentVersion := results.Results[0].Version
+	c.Assert(agentVersion, gc.NotNil)
+	c.Check(*agentVersion, gc.DeepEquals, older.Number)
+}
+
+func (s *upgraderSuite) TestDesiredVersionNotHeldInsideMaintenanceWindow(c *gc.C) {
+	older := version.Binary{
+		Number: version.MustParse("2.0.0"),
+		Arch:   arch.HostArch(),
+		Series: series.MustHostSeries(),
+	}
+	err := s.rawMachine.SetAgentVersion(older)
+	c.Assert(err, jc.ErrorIsNil)
+	s.setMaintenanceWindow(c, "wed 11:00-13:00")
+
+	args := params.Entities{Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}}}
+	results, err := s.upgrader.DesiredVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	agentVersion := results.Results[0].Version
+	c.Assert(agentVersion, gc.NotNil)
+	c.Check(*agentVersion, gc.DeepEquals, jujuversion.Current)
+
+	// Once the window has closed, the agent is held again.
+	s.clock.Advance(2 * time.Hour)
+	results, err = s.upgrader.DesiredVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Check(*results.Results[0].Version, gc.DeepEquals, older.Number)
+}
+
+func (s *upgraderSuite) TestDesiredVersionNotHeldForAPIAgents(c *gc.C) {
+	s.closeMaintenanceWindow(c)
+	newVersion := s.bumpDesiredAgentVersion(c)
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag: s.apiMachine.Tag(),
+	}
+	upgraderAPI, err := upgrader.NewUpgraderAPI(s.State, s.resources, authorizer, s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+	args := params.Entities{Entities: []params.Entity{{Tag: s.apiMachine.Tag().String()}}}
+	results, err := upgraderAPI.DesiredVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	agentVersion := results.Results[0].Version
+	c.Assert(agentVersion, gc.NotNil)
+	c.Check(*agentVersion, gc.DeepEquals, newVersion)
+}
+
+func (s *upgraderSuite) setOlderAgentVersion(c *gc.C) version.Number {
+	older := version.Binary{
+		Number: version.MustParse("2.0.0"),
+		Arch:   arch.HostArch(),
+		Series: series.MustHostSeries(),
+	}
+	err := s.rawMachine.SetAgentVersion(older)
+	c.Assert(err, jc.ErrorIsNil)
+	return older.Number
+}
+
+func (s *upgraderSuite) assertDesiredVersion(c *gc.C, expected version.Number) {
+	args := params.Entities{Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}}}
+	results, err := s.upgrader.DesiredVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	agentVersion := results.Results[0].Version
+	c.Assert(agentVersion, gc.NotNil)
+	c.Check(*agentVersion, gc.DeepEquals, expected)
+}
+
+func (s *upgraderSuite) TestDesiredVersionHeldByMachinePin(c *gc.C) {
+	older := s.setOlderAgentVersion(c)
+	err := s.rawMachine.SetAgentVersionPin(state.AgentVersionPin{Max: older})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertDesiredVersion(c, older)
+}
+
+func (s *upgraderSuite) TestDesiredVersionHeldByApplicationPin(c *gc.C) {
+	older := s.setOlderAgentVersion(c)
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(s.rawMachine)
+	c.Assert(err, jc.ErrorIsNil)
+	err = app.SetAgentVersionPin(state.AgentVersionPin{Max: older})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertDesiredVersion(c, older)
+}
+
+func (s *upgraderSuite) TestDesiredVersionAllowedByPin(c *gc.C) {
+	older := s.setOlderAgentVersion(c)
+	err := s.rawMachine.SetAgentVersionPin(state.AgentVersionPin{Min: older})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertDesiredVersion(c, jujuversion.Current)
+}
+
+func (s *upgraderSuite) TestWatchAPIVersionNotifiesPinChanges(c *gc.C) {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
+	}
+	results, err := s.upgrader.WatchAPIVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	w := s.resources.Get(results.Results[0].NotifyWatcherId).(state.NotifyWatcher)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertNoChange()
+
+	err = s.rawMachine.SetAgentVersionPin(state.AgentVersionPin{Max: version.MustParse("2.0.0")})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
+func (s *upgraderSuite) TestAgentBinaryTrustedKeysNotConfigured(c *gc.C) {
+	result, err := s.upgrader.AgentBinaryTrustedKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.Equals, params.StringResult{})
+}
+
+func (s *upgraderSuite) TestUpgraderV1HidesAgentBinaryTrustedKeys(c *gc.C) {
+	facade, err := upgrader.NewUpgraderFacadeV1(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := interface{}(facade).(interface {
+		AgentBinaryTrustedKeys() (params.StringResult, error)
+	})
+	c.Assert(ok, jc.IsFalse)
+}
+
+type upgraderTrustedKeysSuite struct {
+	upgraderSuite
+}
+
+var _ = gc.Suite(&upgraderTrustedKeysSuite{})
+
+func (s *upgraderTrustedKeysSuite) SetUpTest(c *gc.C) {
+	s.ControllerConfigAttrs = map[string]interface{}{
+		controller.AgentBinaryTrustedKeys: sstesting.SignedMetadataPublicKey,
+	}
+	s.upgraderSuite.SetUpTest(c)
+}
+
+func (s *upgraderTrustedKeysSuite) TestAgentBinaryTrustedKeys(c *gc.C) {
+	result, err := s.upgrader.AgentBinaryTrustedKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.Equals, params.StringResult{Result: sstesting.SignedMetadataPublicKey})
+}
@@ -4,6 +4,7 @@
 package action_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/action"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/correlation"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
@@ -114,7 +116,7 @@ func (s *actionSuite) AssertBlocked(c *gc.C, err error, msg string) {
 func (s *actionSuite) TestBlockEnqueue(c *gc.C) {
 	// block all changes
 	s.BlockAllChanges(c, "Enqueue")
-	_, err := s.action.Enqueue(params.Actions{})
+	_, err := s.action.Enqueue(context.Background(), params.Actions{})
 	s.AssertBlocked(c, err, "Enqueue")
 }
 
@@ -134,7 +136,7 @@ func (s *actionSuite) TestActions(c *gc.C) {
 			{Receiver: s.mysqlUnit.Tag().String(), Name: "fakeaction", Parameters: map[string]interface{}{"baz": true}},
 		}}
 
-	r, err := s.action.Enqueue(arg)
+	r, err := s.action.Enqueue(context.Background(), arg)
 	c.Assert(err, gc.Equals, nil)
 	c.Assert(r.Results, gc.HasLen, len(arg.Actions))
 
@@ -164,7 +166,7 @@ func (s *actionSuite) TestActions(c *gc.C) {
 func (s *actionSuite) TestFindActionTagsByPrefix(c *gc.C) {
 	// NOTE: full testing with multiple matches has been moved to state package.
 	arg := params.Actions{Actions: []params.Action{{Receiver: s.wordpressUnit.Tag().String(), Name: "fakeaction", Parameters: map[string]interface{}{}}}}
-	r, err := s.action.Enqueue(arg)
+	r, err := s.action.Enqueue(context.Background(), arg)
 	c.Assert(err, gc.Equals, nil)
 	c.Assert(r.Results, gc.HasLen, len(arg.Actions))
 
@@ -196,7 +198,7 @@ func (s *actionSuite) TestFindActionsByName(c *gc.C) {
 		{Receiver: s.wordpressUnit.Tag().String(), Name: "juju-run", Parameters: map[string]interface{}{"command": "boo", "timeout": 5}},
 		{Receiver: s.mysqlUnit.Tag().String(), Name: "juju-run", Parameters: map[string]interface{}{"command": "boo", "timeout": 5}},
 	}}
-	r, err := s.action.Enqueue(arg)
+	r, err := s.action.Enqueue(context.Background(), arg)
 	c.Assert(err, gc.Equals, nil)
 	c.Assert(r.Results, gc.HasLen, len(arg.Actions))
 
@@ -213,6 +215,25 @@ func (s *actionSuite) TestFindActionsByName(c *gc.C) {
 	}
 }
 
+func (s *actionSuite) TestEnqueueRecordsCorrelationId(c *gc.C) {
+	arg := params.Actions{
+		Actions: []params.Action{
+			{Receiver: s.wordpressUnit.Tag().String(), Name: "fakeaction"},
+		},
+	}
+	ctx := correlation.WithID(context.Background(), "deadbeef")
+	res, err := s.action.Enqueue(ctx, arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 1)
+	c.Assert(res.Results[0].Error, gc.IsNil)
+	c.Assert(res.Results[0].Action.CorrelationId, gc.Equals, "deadbeef")
+
+	actions, err := s.wordpressUnit.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Assert(actions[0].CorrelationID(), gc.Equals, "deadbeef")
+}
+
 func (s *actionSuite) TestEnqueue(c *gc.C) {
 	// Make sure no Actions already exist on wordpress Unit.
 	actions, err := s.wordpressUnit.Actions()
@@ -239,7 +260,7 @@ func (s *actionSuite) TestEnqueue(c *gc.C) {
 			{Receiver: s.mysqlUnit.Tag().String(), Parameters: expectedParameters},
 		},
 	}
-	res, err := s.action.Enqueue(arg)
+	res, err := s.action.Enqueue(context.Background(), arg)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(res.Results, gc.HasLen, 4)
 
@@ -590,7 +611,7 @@ func (s *actionSuite) TestCancel(c *gc.C) {
 		}},
 	}
 
-	results, err := s.action.Enqueue(tests)
+	results, err := s.action.Enqueue(context.Background(), tests)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results.Results, gc.HasLen, 4)
 	for _, res := range results.Results {
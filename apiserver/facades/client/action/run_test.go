@@ -4,6 +4,8 @@
 package action_test
 
 import (
+	"context"
+
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -152,6 +154,7 @@ func (s *runSuite) TestBlockRunOnAllMachines(c *gc.C) {
 	// block all changes
 	s.BlockAllChanges(c, "TestBlockRunOnAllMachines")
 	_, err := s.client.RunOnAllMachines(
+		context.Background(),
 		params.RunParams{
 			Commands: "hostname",
 			Timeout:  testing.LongWait,
@@ -163,6 +166,7 @@ func (s *runSuite) TestBlockRunMachineAndService(c *gc.C) {
 	// block all changes
 	s.BlockAllChanges(c, "TestBlockRunMachineAndService")
 	_, err := s.client.Run(
+		context.Background(),
 		params.RunParams{
 			Commands:     "hostname",
 			Timeout:      testing.LongWait,
@@ -187,7 +191,7 @@ func (s *runSuite) TestRunMachineAndService(c *gc.C) {
 		},
 	}
 	called := false
-	s.PatchValue(action.QueueActions, func(client *action.ActionAPI, args params.Actions) (params.ActionResults, error) {
+	s.PatchValue(action.QueueActions, func(client *action.ActionAPI, ctx context.Context, args params.Actions) (params.ActionResults, error) {
 		called = true
 		c.Assert(args, jc.DeepEquals, expectedArgs)
 		return params.ActionResults{}, nil
@@ -202,6 +206,7 @@ func (s *runSuite) TestRunMachineAndService(c *gc.C) {
 	s.addUnit(c, magic)
 
 	s.client.Run(
+		context.Background(),
 		params.RunParams{
 			Commands:     "hostname",
 			Machines:     []string{"0"},
@@ -225,7 +230,7 @@ func (s *runSuite) TestRunOnAllMachines(c *gc.C) {
 		},
 	}
 	called := false
-	s.PatchValue(action.QueueActions, func(client *action.ActionAPI, args params.Actions) (params.ActionResults, error) {
+	s.PatchValue(action.QueueActions, func(client *action.ActionAPI, ctx context.Context, args params.Actions) (params.ActionResults, error) {
 		called = true
 		c.Assert(args, jc.DeepEquals, expectedArgs)
 		return params.ActionResults{}, nil
@@ -236,6 +241,7 @@ func (s *runSuite) TestRunOnAllMachines(c *gc.C) {
 	s.addMachine(c)
 
 	s.client.RunOnAllMachines(
+		context.Background(),
 		params.RunParams{
 			Commands: "hostname",
 			Timeout:  testing.LongWait,
@@ -251,13 +257,13 @@ func (s *runSuite) TestRunRequiresAdmin(c *gc.C) {
 	}
 	client, err := action.NewActionAPI(s.State, nil, auth)
 	c.Assert(err, jc.ErrorIsNil)
-	_, err = client.Run(params.RunParams{})
+	_, err = client.Run(context.Background(), params.RunParams{})
 	c.Assert(errors.Cause(err), gc.Equals, common.ErrPerm)
 
 	auth.AdminTag = alpha
 	client, err = action.NewActionAPI(s.State, nil, auth)
 	c.Assert(err, jc.ErrorIsNil)
-	_, err = client.Run(params.RunParams{})
+	_, err = client.Run(context.Background(), params.RunParams{})
 	c.Assert(err, jc.ErrorIsNil)
 }
 
@@ -269,12 +275,12 @@ func (s *runSuite) TestRunOnAllMachinesRequiresAdmin(c *gc.C) {
 	}
 	client, err := action.NewActionAPI(s.State, nil, auth)
 	c.Assert(err, jc.ErrorIsNil)
-	_, err = client.RunOnAllMachines(params.RunParams{})
+	_, err = client.RunOnAllMachines(context.Background(), params.RunParams{})
 	c.Assert(errors.Cause(err), gc.Equals, common.ErrPerm)
 
 	auth.AdminTag = alpha
 	client, err = action.NewActionAPI(s.State, nil, auth)
 	c.Assert(err, jc.ErrorIsNil)
-	_, err = client.RunOnAllMachines(params.RunParams{})
+	_, err = client.RunOnAllMachines(context.Background(), params.RunParams{})
 	c.Assert(err, jc.ErrorIsNil)
 }
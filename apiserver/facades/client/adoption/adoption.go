@@ -0,0 +1,221 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package adoption provides the Adoption facade, through which
+// operators recover machines a model no longer records, such as after
+// the loss of a controller. Instances in the model's cloud that are
+// tagged as belonging to the model but not tracked by it are listed as
+// candidates; each may be verified and then adopted using the
+// credentials held by the machine agent running on it, after which the
+// agent reconnects to the model.
+package adoption
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/stateenvirons"
+)
+
+// API provides the Adoption facade for v1.
+type API struct {
+	backend    Backend
+	lister     environs.TaggedResourceLister
+	authorizer facade.Authorizer
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	env, err := stateenvirons.GetNewEnvironFunc(environs.New)(ctx.State())
+	if err != nil {
+		return nil, errors.Annotate(err, "getting environ")
+	}
+	// The lister is left nil if the provider cannot enumerate its
+	// resources by tag; the facade then reports that adoption is not
+	// supported.
+	lister, _ := env.(environs.TaggedResourceLister)
+	return NewAPI(stateShim{ctx.State()}, lister, ctx.Auth())
+}
+
+// NewAPI returns a new Adoption API facade.
+func NewAPI(
+	backend Backend,
+	lister environs.TaggedResourceLister,
+	authorizer facade.Authorizer,
+) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		lister:     lister,
+		authorizer: authorizer,
+	}, nil
+}
+
+func (api *API) checkIsAdmin() error {
+	allowed, err := api.authorizer.HasPermission(permission.AdminAccess, api.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !allowed {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// Candidates returns the instances in the model's cloud that are
+// tagged as belonging to the model, but which no machine in the model
+// records. Controller instances are never candidates.
+func (api *API) Candidates() (params.AdoptionCandidatesResult, error) {
+	var result params.AdoptionCandidatesResult
+	if err := api.checkIsAdmin(); err != nil {
+		return result, errors.Trace(err)
+	}
+	candidates, err := api.candidates()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Candidates = make([]params.AdoptionCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		result.Candidates = append(result.Candidates, candidate)
+	}
+	sort.Slice(result.Candidates, func(i, j int) bool {
+		return result.Candidates[i].InstanceId < result.Candidates[j].InstanceId
+	})
+	return result, nil
+}
+
+// Verify checks that each of the specified instances can be adopted
+// as the specified machine, without changing anything.
+func (api *API) Verify(args params.AdoptMachines) (params.ErrorResults, error) {
+	return api.adopt(args, func(state.AdoptMachineArgs) error {
+		return nil
+	})
+}
+
+// Adopt verifies each of the specified instances, and recreates the
+// machine each was started for. The machine agents running on adopted
+// instances reconnect to the model using the credentials supplied.
+func (api *API) Adopt(args params.AdoptMachines) (params.ErrorResults, error) {
+	return api.adopt(args, api.backend.AdoptMachine)
+}
+
+func (api *API) adopt(
+	args params.AdoptMachines,
+	adoptFunc func(state.AdoptMachineArgs) error,
+) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Machines)),
+	}
+	if err := api.checkIsAdmin(); err != nil {
+		return result, errors.Trace(err)
+	}
+	if len(args.Machines) == 0 {
+		return result, nil
+	}
+	candidates, err := api.candidates()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Machines {
+		stateArgs, err := api.verify(candidates, arg)
+		if err == nil {
+			err = adoptFunc(stateArgs)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// verify checks that the instance to adopt is a candidate for adoption
+// as the specified machine, and that the model can adopt it.
+func (api *API) verify(
+	candidates map[string]params.AdoptionCandidate,
+	arg params.AdoptMachine,
+) (state.AdoptMachineArgs, error) {
+	tag, err := names.ParseMachineTag(arg.MachineTag)
+	if err != nil {
+		return state.AdoptMachineArgs{}, errors.Trace(err)
+	}
+	candidate, ok := candidates[arg.InstanceId]
+	if !ok {
+		return state.AdoptMachineArgs{}, errors.NotFoundf("untracked instance %q", arg.InstanceId)
+	}
+	if candidate.MachineTag != "" && candidate.MachineTag != tag.String() {
+		return state.AdoptMachineArgs{}, errors.Errorf(
+			"instance %q was started for %s, not %s",
+			arg.InstanceId, candidate.MachineTag, tag,
+		)
+	}
+	stateArgs := state.AdoptMachineArgs{
+		Id:         tag.Id(),
+		Series:     arg.Series,
+		InstanceId: instance.Id(arg.InstanceId),
+		Nonce:      arg.Nonce,
+		Password:   arg.Password,
+	}
+	if err := api.backend.VerifyAdoption(stateArgs); err != nil {
+		return state.AdoptMachineArgs{}, errors.Trace(err)
+	}
+	return stateArgs, nil
+}
+
+// candidates returns the instances that may be adopted, keyed on
+// instance id.
+func (api *API) candidates() (map[string]params.AdoptionCandidate, error) {
+	if api.lister == nil {
+		return nil, errors.NotSupportedf("listing tagged cloud resources")
+	}
+	resources, err := api.lister.TaggedResources(map[string]string{
+		tags.JujuModel: api.backend.ModelTag().Id(),
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "listing tagged resources")
+	}
+	instanceIds, err := api.backend.InstanceIds()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	modelName, err := api.backend.ModelName()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tracked := set.NewStrings()
+	for _, id := range instanceIds {
+		tracked.Add(string(id))
+	}
+
+	candidates := make(map[string]params.AdoptionCandidate)
+	for _, resource := range resources {
+		if resource.Kind != environs.ResourceKindInstance || tracked.Contains(resource.ID) {
+			continue
+		}
+		if resource.Tags[tags.JujuIsController] == "true" {
+			continue
+		}
+		candidate := params.AdoptionCandidate{InstanceId: resource.ID}
+		if units := resource.Tags[tags.JujuUnitsDeployed]; units != "" {
+			candidate.Units = strings.Fields(units)
+		}
+		// Instances are tagged with "<model name>-<machine tag>".
+		machine := strings.TrimPrefix(resource.Tags[tags.JujuMachine], modelName+"-")
+		if tag, err := names.ParseMachineTag(machine); err == nil {
+			candidate.MachineTag = tag.String()
+		}
+		candidates[resource.ID] = candidate
+	}
+	return candidates, nil
+}
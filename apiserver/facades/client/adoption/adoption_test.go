@@ -0,0 +1,226 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package adoption_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/adoption"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type AdoptionSuite struct {
+	testing.IsolationSuite
+	backend    mockBackend
+	lister     mockLister
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&AdoptionSuite{})
+
+func (s *AdoptionSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("admin"),
+	}
+	s.backend = mockBackend{
+		instanceIds: []instance.Id{"i-0"},
+	}
+	s.lister = mockLister{
+		resources: []environs.TaggedResource{{
+			Kind: environs.ResourceKindInstance,
+			ID:   "i-0",
+		}, {
+			Kind: environs.ResourceKindInstance,
+			ID:   "i-3",
+			Tags: map[string]string{
+				"juju-machine-id":     "mymodel-machine-3",
+				"juju-units-deployed": "mysql/0 wordpress/1",
+			},
+		}, {
+			Kind: environs.ResourceKindInstance,
+			ID:   "i-2",
+		}, {
+			Kind: environs.ResourceKindInstance,
+			ID:   "i-ctrl",
+			Tags: map[string]string{"juju-is-controller": "true"},
+		}, {
+			Kind: environs.ResourceKindVolume,
+			ID:   "vol-0",
+		}},
+	}
+}
+
+func (s *AdoptionSuite) newAPI(c *gc.C) *adoption.API {
+	api, err := adoption.NewAPI(&s.backend, &s.lister, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func adoptMachine(instId, machineTag string) params.AdoptMachine {
+	return params.AdoptMachine{
+		InstanceId: instId,
+		MachineTag: machineTag,
+		Series:     "xenial",
+		Nonce:      "nonce",
+		Password:   "password",
+	}
+}
+
+func (s *AdoptionSuite) TestNewAPIRequiresClient(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("0")
+	_, err := adoption.NewAPI(&s.backend, &s.lister, s.authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *AdoptionSuite) TestCandidates(c *gc.C) {
+	result, err := s.newAPI(c).Candidates()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.AdoptionCandidatesResult{
+		Candidates: []params.AdoptionCandidate{
+			{InstanceId: "i-2"},
+			{InstanceId: "i-3", MachineTag: "machine-3", Units: []string{"mysql/0", "wordpress/1"}},
+		},
+	})
+	s.lister.CheckCalls(c, []testing.StubCall{{
+		"TaggedResources", []interface{}{map[string]string{
+			"juju-model-uuid": coretesting.ModelTag.Id(),
+		}},
+	}})
+}
+
+func (s *AdoptionSuite) TestCandidatesNotSupported(c *gc.C) {
+	api, err := adoption.NewAPI(&s.backend, nil, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.Candidates()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *AdoptionSuite) TestCandidatesPermissionDenied(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("fred")
+	_, err := s.newAPI(c).Candidates()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.lister.CheckNoCalls(c)
+}
+
+func (s *AdoptionSuite) TestVerify(c *gc.C) {
+	s.backend.SetErrors(nil, nil, nil, errors.AlreadyExistsf("machine 2"))
+	result, err := s.newAPI(c).Verify(params.AdoptMachines{
+		Machines: []params.AdoptMachine{
+			adoptMachine("i-3", "machine-3"),
+			adoptMachine("i-2", "machine-2"),
+			adoptMachine("i-0", "machine-0"),
+			adoptMachine("i-3", "machine-4"),
+			adoptMachine("i-3", "unit-mysql-0"),
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Message: "machine 2 already exists", Code: params.CodeAlreadyExists}},
+			{Error: &params.Error{Message: `untracked instance "i-0" not found`, Code: params.CodeNotFound}},
+			{Error: &params.Error{Message: `instance "i-3" was started for machine-3, not machine-4`}},
+			{Error: &params.Error{Message: `"unit-mysql-0" is not a valid machine tag`}},
+		},
+	})
+	s.backend.CheckCallNames(c, "ModelTag", "ModelTag", "InstanceIds", "ModelName", "VerifyAdoption", "VerifyAdoption")
+	s.backend.CheckCall(c, 4, "VerifyAdoption", state.AdoptMachineArgs{
+		Id:         "3",
+		Series:     "xenial",
+		InstanceId: "i-3",
+		Nonce:      "nonce",
+		Password:   "password",
+	})
+}
+
+func (s *AdoptionSuite) TestAdopt(c *gc.C) {
+	s.backend.SetErrors(nil, nil, nil, nil, errors.AlreadyExistsf("machine 2"))
+	result, err := s.newAPI(c).Adopt(params.AdoptMachines{
+		Machines: []params.AdoptMachine{
+			adoptMachine("i-3", "machine-3"),
+			adoptMachine("i-2", "machine-2"),
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Message: "machine 2 already exists", Code: params.CodeAlreadyExists}},
+		},
+	})
+	s.backend.CheckCallNames(c,
+		"ModelTag", "ModelTag", "InstanceIds", "ModelName",
+		"VerifyAdoption", "AdoptMachine", "VerifyAdoption",
+	)
+	s.backend.CheckCall(c, 5, "AdoptMachine", state.AdoptMachineArgs{
+		Id:         "3",
+		Series:     "xenial",
+		InstanceId: "i-3",
+		Nonce:      "nonce",
+		Password:   "password",
+	})
+}
+
+func (s *AdoptionSuite) TestAdoptPermissionDenied(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("fred")
+	_, err := s.newAPI(c).Adopt(params.AdoptMachines{
+		Machines: []params.AdoptMachine{adoptMachine("i-3", "machine-3")},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.lister.CheckNoCalls(c)
+	s.backend.CheckCallNames(c, "ModelTag")
+}
+
+type mockBackend struct {
+	testing.Stub
+	instanceIds []instance.Id
+}
+
+func (m *mockBackend) ModelTag() names.ModelTag {
+	m.MethodCall(m, "ModelTag")
+	return coretesting.ModelTag
+}
+
+func (m *mockBackend) ModelName() (string, error) {
+	m.MethodCall(m, "ModelName")
+	return "mymodel", m.NextErr()
+}
+
+func (m *mockBackend) InstanceIds() ([]instance.Id, error) {
+	m.MethodCall(m, "InstanceIds")
+	return m.instanceIds, m.NextErr()
+}
+
+func (m *mockBackend) VerifyAdoption(args state.AdoptMachineArgs) error {
+	m.MethodCall(m, "VerifyAdoption", args)
+	return m.NextErr()
+}
+
+func (m *mockBackend) AdoptMachine(args state.AdoptMachineArgs) error {
+	m.MethodCall(m, "AdoptMachine", args)
+	return m.NextErr()
+}
+
+type mockLister struct {
+	testing.Stub
+	resources []environs.TaggedResource
+}
+
+func (m *mockLister) TaggedResources(tags map[string]string) ([]environs.TaggedResource, error) {
+	m.MethodCall(m, "TaggedResources", tags)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return m.resources, nil
+}
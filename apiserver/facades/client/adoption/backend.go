@@ -0,0 +1,77 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package adoption
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state functionality required by the adoption
+// facade.
+type Backend interface {
+	// ModelTag returns the tag of the model.
+	ModelTag() names.ModelTag
+
+	// ModelName returns the name of the model.
+	ModelName() (string, error)
+
+	// InstanceIds returns the IDs of the provisioned instances of every
+	// machine in the model.
+	InstanceIds() ([]instance.Id, error)
+
+	// VerifyAdoption checks that the described instance can be
+	// adopted into the model.
+	VerifyAdoption(state.AdoptMachineArgs) error
+
+	// AdoptMachine recreates the machine the described instance was
+	// started for.
+	AdoptMachine(state.AdoptMachineArgs) error
+}
+
+// This type is an untested shim to let us wrap state in a sensible
+// interface and avoid writing tests that depend on mongodb. If you were
+// to change any part of it so that it were no longer *obviously* and
+// *trivially* correct, you would be Doing It Wrong.
+type stateShim struct {
+	*state.State
+}
+
+func (s stateShim) ModelTag() names.ModelTag {
+	return names.NewModelTag(s.State.ModelUUID())
+}
+
+func (s stateShim) ModelName() (string, error) {
+	m, err := s.State.Model()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return m.Name(), nil
+}
+
+func (s stateShim) InstanceIds() ([]instance.Id, error) {
+	machines, err := s.State.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ids := make([]instance.Id, 0, len(machines))
+	for _, m := range machines {
+		id, err := m.InstanceId()
+		if errors.IsNotProvisioned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (s stateShim) AdoptMachine(args state.AdoptMachineArgs) error {
+	_, err := s.State.AdoptMachine(args)
+	return err
+}
@@ -0,0 +1,82 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agentversionpins
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+// API provides the AgentVersionPins facade for v1.
+type API struct {
+	backend    Backend
+	authorizer facade.Authorizer
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(stateShim{ctx.State()}, ctx.Auth())
+}
+
+// NewAPI returns a new AgentVersionPins API facade.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		authorizer: authorizer,
+	}, nil
+}
+
+func (api *API) checkCanWrite() error {
+	allowed, err := api.authorizer.HasPermission(permission.WriteAccess, api.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !allowed {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// SetAgentVersionPins pins the agents of the supplied machines, or of
+// the machines hosting the supplied applications' units, to a range of
+// agent versions. Upgrades of those agents to versions outside the
+// range are held back; an empty range removes the pin.
+func (api *API) SetAgentVersionPins(args params.AgentVersionPins) (params.ErrorResults, error) {
+	if err := api.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := make([]params.ErrorResult, len(args.Pins))
+	for i, arg := range args.Pins {
+		results[i].Error = common.ServerError(api.setAgentVersionPin(arg))
+	}
+	return params.ErrorResults{Results: results}, nil
+}
+
+func (api *API) setAgentVersionPin(arg params.AgentVersionPin) error {
+	tag, err := names.ParseTag(arg.Tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	switch tag.(type) {
+	case names.MachineTag, names.ApplicationTag:
+	default:
+		return errors.NotValidf("pinning agent version of %s", names.ReadableString(tag))
+	}
+	var pin state.AgentVersionPin
+	if arg.Range != "" {
+		if pin, err = state.ParseAgentVersionPin(arg.Range); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return api.backend.SetAgentVersionPin(tag, pin)
+}
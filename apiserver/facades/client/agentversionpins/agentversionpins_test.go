@@ -0,0 +1,108 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agentversionpins_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/agentversionpins"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type AgentVersionPinsSuite struct {
+	testing.IsolationSuite
+	backend    mockBackend
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&AgentVersionPinsSuite{})
+
+func (s *AgentVersionPinsSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("admin"),
+	}
+	s.backend = mockBackend{}
+}
+
+func (s *AgentVersionPinsSuite) TestNewAPIRequiresClient(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("0")
+	_, err := agentversionpins.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *AgentVersionPinsSuite) TestSetAgentVersionPins(c *gc.C) {
+	s.backend.SetErrors(nil, nil, errors.NotFoundf(`application "mysql"`))
+	api, err := agentversionpins.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := api.SetAgentVersionPins(params.AgentVersionPins{
+		Pins: []params.AgentVersionPin{
+			{Tag: "machine-0", Range: "2.3.1..2.3.4"},
+			{Tag: "application-wordpress"},
+			{Tag: "application-mysql", Range: "..2.3.4"},
+			{Tag: "unit-mysql-0", Range: "..2.3.4"},
+			{Tag: "machine-1", Range: "2.4.0..2.3.0"},
+			{Tag: "foo", Range: "2.3.1"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{},
+			{Error: &params.Error{Code: params.CodeNotFound, Message: `application "mysql" not found`}},
+			{Error: &params.Error{Message: `pinning agent version of unit mysql/0 not valid`}},
+			{Error: &params.Error{Message: `agent version range 2.4.0..2.3.0 not valid`}},
+			{Error: &params.Error{Message: `"foo" is not a valid tag`}},
+		},
+	})
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"ModelTag", nil},
+		{"SetAgentVersionPin", []interface{}{
+			names.NewMachineTag("0"),
+			state.AgentVersionPin{Min: version.MustParse("2.3.1"), Max: version.MustParse("2.3.4")},
+		}},
+		{"SetAgentVersionPin", []interface{}{
+			names.NewApplicationTag("wordpress"),
+			state.AgentVersionPin{},
+		}},
+		{"SetAgentVersionPin", []interface{}{
+			names.NewApplicationTag("mysql"),
+			state.AgentVersionPin{Max: version.MustParse("2.3.4")},
+		}},
+	})
+}
+
+func (s *AgentVersionPinsSuite) TestSetAgentVersionPinsPermissionDenied(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("fred")
+	api, err := agentversionpins.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.SetAgentVersionPins(params.AgentVersionPins{
+		Pins: []params.AgentVersionPin{{Tag: "machine-0", Range: "2.3.1"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "ModelTag")
+}
+
+type mockBackend struct {
+	testing.Stub
+}
+
+func (m *mockBackend) ModelTag() names.ModelTag {
+	m.MethodCall(m, "ModelTag")
+	return coretesting.ModelTag
+}
+
+func (m *mockBackend) SetAgentVersionPin(tag names.Tag, pin state.AgentVersionPin) error {
+	m.MethodCall(m, "SetAgentVersionPin", tag, pin)
+	return m.NextErr()
+}
@@ -0,0 +1,52 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agentversionpins
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state functionality required by the
+// agentversionpins facade.
+type Backend interface {
+	// ModelTag returns the tag of the model.
+	ModelTag() names.ModelTag
+
+	// SetAgentVersionPin replaces the agent version pin of the
+	// machine or application with the supplied tag.
+	SetAgentVersionPin(names.Tag, state.AgentVersionPin) error
+}
+
+// This type is an untested shim to let us wrap state in a sensible
+// interface and avoid writing tests that depend on mongodb. If you were
+// to change any part of it so that it were no longer *obviously* and
+// *trivially* correct, you would be Doing It Wrong.
+type stateShim struct {
+	*state.State
+}
+
+func (s stateShim) ModelTag() names.ModelTag {
+	return names.NewModelTag(s.State.ModelUUID())
+}
+
+func (s stateShim) SetAgentVersionPin(tag names.Tag, pin state.AgentVersionPin) error {
+	switch tag := tag.(type) {
+	case names.MachineTag:
+		m, err := s.State.Machine(tag.Id())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return m.SetAgentVersionPin(pin)
+	case names.ApplicationTag:
+		app, err := s.State.Application(tag.Id())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return app.SetAgentVersionPin(pin)
+	}
+	return errors.NotValidf("pinning agent version of %s", names.ReadableString(tag))
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/cache"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 )
@@ -29,6 +30,18 @@ type Annotations interface {
 type API struct {
 	access     annotationAccess
 	authorizer facade.Authorizer
+	modelCache *cache.Controller
+}
+
+// NewFacade returns a new annotations facade that answers reads from
+// the controller's model cache where it can.
+func NewFacade(ctx facade.Context) (*API, error) {
+	api, err := NewAPI(ctx.State(), ctx.Resources(), ctx.Auth())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	api.modelCache = ctx.ModelCache()
+	return api, nil
 }
 
 // NewAPI returns a new charm annotator API facade.
@@ -134,6 +147,9 @@ func (api *API) getEntityAnnotations(entityTag string) (map[string]string, error
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if annotations, ok := api.cachedAnnotations(tag); ok {
+		return annotations, nil
+	}
 	annotations, err := api.access.Annotations(entity)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -141,6 +157,29 @@ func (api *API) getEntityAnnotations(entityTag string) (map[string]string, error
 	return annotations, nil
 }
 
+// cachedAnnotations returns the annotations of the entity with the
+// supplied tag from the model cache, if they are cached.
+func (api *API) cachedAnnotations(tag names.Tag) (map[string]string, bool) {
+	model := api.cachedModel()
+	if model == nil {
+		return nil, false
+	}
+	return model.Annotations(tag.String())
+}
+
+// cachedModel returns the model cache's copy of the API's model, or
+// nil if the model is not cached.
+func (api *API) cachedModel() *cache.Model {
+	if api.modelCache == nil {
+		return nil
+	}
+	model, err := api.modelCache.Model(api.access.ModelTag().Id())
+	if err != nil {
+		return nil
+	}
+	return model
+}
+
 func (api *API) findEntity(tag names.Tag) (state.GlobalEntity, error) {
 	entity0, err := api.access.FindEntity(tag)
 	if err != nil {
@@ -165,5 +204,17 @@ func (api *API) setEntityAnnotations(entityTag string, annotations map[string]st
 	if err != nil {
 		return errors.Trace(err)
 	}
-	return api.access.SetAnnotations(entity, annotations)
+	if err := api.access.SetAnnotations(entity, annotations); err != nil {
+		return errors.Trace(err)
+	}
+	if model := api.cachedModel(); model != nil {
+		// Write the merged annotations through to the cache, so
+		// that a Get following this Set does not see stale data.
+		current, err := api.access.Annotations(entity)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		model.SetAnnotations(tag.String(), current)
+	}
+	return nil
 }
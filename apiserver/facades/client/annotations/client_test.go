@@ -10,11 +10,14 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/apiserver/facade/facadetest"
 	"github.com/juju/juju/apiserver/facades/client/annotations"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/cache"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/testing/factory"
 )
 
@@ -231,6 +234,98 @@ func (s *annotationSuite) TestMultipleEntitiesAnnotations(c *gc.C) {
 	c.Assert(rGet, jc.IsTrue)
 }
 
+func (s *annotationSuite) TestGetFromModelCache(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, &factory.MachineParams{
+		Jobs: []state.MachineJob{state.JobHostUnits},
+	})
+	modelCache := cache.NewController()
+	modelCache.Update([]multiwatcher.Delta{{
+		Entity: &multiwatcher.ModelInfo{ModelUUID: s.State.ModelUUID()},
+	}, {
+		Entity: &multiwatcher.AnnotationInfo{
+			ModelUUID:   s.State.ModelUUID(),
+			Tag:         machine.Tag().String(),
+			Annotations: map[string]string{"cached": "value"},
+		},
+	}})
+	api, err := annotations.NewFacade(facadetest.Context{
+		State_:      s.State,
+		Auth_:       s.authorizer,
+		ModelCache_: modelCache,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The annotations are not in the database, so must have been
+	// read from the cache.
+	results := api.Get(params.Entities{[]params.Entity{{machine.Tag().String()}}})
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error.Error, gc.IsNil)
+	c.Assert(results.Results[0].Annotations, jc.DeepEquals, map[string]string{"cached": "value"})
+
+	// Entities without cached annotations are read from the database.
+	results = api.Get(params.Entities{[]params.Entity{{s.Model.ModelTag().String()}}})
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error.Error, gc.IsNil)
+	c.Assert(results.Results[0].Annotations, gc.HasLen, 0)
+}
+
+func (s *annotationSuite) TestSetWritesThroughModelCache(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, &factory.MachineParams{
+		Jobs: []state.MachineJob{state.JobHostUnits},
+	})
+	modelCache := cache.NewController()
+	modelCache.Update([]multiwatcher.Delta{{
+		Entity: &multiwatcher.ModelInfo{ModelUUID: s.State.ModelUUID()},
+	}, {
+		Entity: &multiwatcher.AnnotationInfo{
+			ModelUUID:   s.State.ModelUUID(),
+			Tag:         machine.Tag().String(),
+			Annotations: map[string]string{"stale": "value"},
+		},
+	}})
+	api, err := annotations.NewFacade(facadetest.Context{
+		State_:      s.State,
+		Auth_:       s.authorizer,
+		ModelCache_: modelCache,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	setResult := api.Set(params.AnnotationsSet{Annotations: constructSetParameters(
+		[]string{machine.Tag().String()}, map[string]string{"fresh": "value"})})
+	c.Assert(setResult.Results, gc.HasLen, 0)
+
+	results := api.Get(params.Entities{[]params.Entity{{machine.Tag().String()}}})
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error.Error, gc.IsNil)
+	c.Assert(results.Results[0].Annotations, jc.DeepEquals, map[string]string{"fresh": "value"})
+}
+
+func (s *annotationSuite) TestGetFromModelCacheChecksEntity(c *gc.C) {
+	modelCache := cache.NewController()
+	modelCache.Update([]multiwatcher.Delta{{
+		Entity: &multiwatcher.ModelInfo{ModelUUID: s.State.ModelUUID()},
+	}, {
+		Entity: &multiwatcher.AnnotationInfo{
+			ModelUUID:   s.State.ModelUUID(),
+			Tag:         "machine-42",
+			Annotations: map[string]string{"cached": "value"},
+		},
+	}})
+	api, err := annotations.NewFacade(facadetest.Context{
+		State_:      s.State,
+		Auth_:       s.authorizer,
+		ModelCache_: modelCache,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The machine does not exist, so its cached annotations must
+	// not be returned.
+	results := api.Get(params.Entities{[]params.Entity{{"machine-42"}}})
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error.Error, gc.ErrorMatches, `.*permission denied`)
+	c.Assert(results.Results[0].Annotations, gc.HasLen, 0)
+}
+
 func (s *annotationSuite) testSetGetEntitiesAnnotations(c *gc.C, tag names.Tag) {
 	entity := tag.String()
 	entities := []string{entity}
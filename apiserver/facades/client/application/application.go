@@ -9,6 +9,7 @@ package application
 import (
 	"fmt"
 	"net"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -38,13 +39,28 @@ var logger = loggo.GetLogger("juju.apiserver.application")
 
 // APIv4 provides the Application API facade for versions 1-4.
 type APIv4 struct {
+	*APIv5
+}
+
+// APIv5 provides the Application API facade for version 5.
+type APIv5 struct {
+	*APIv6
+}
+
+// APIv6 provides the Application API facade for version 6.
+type APIv6 struct {
+	*APIv7
+}
+
+// APIv7 provides the Application API facade for version 7.
+type APIv7 struct {
 	*API
 }
 
 // API implements the application interface and is the concrete
 // implementation of the api end point.
 //
-// API provides the Application API facade for version 5.
+// API provides the Application API facade for version 8.
 type API struct {
 	backend    Backend
 	authorizer facade.Authorizer
@@ -67,7 +83,37 @@ func NewFacadeV4(ctx facade.Context) (*APIv4, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return &APIv4{api}, nil
+	return &APIv4{&APIv5{&APIv6{&APIv7{api}}}}, nil
+}
+
+// NewFacadeV5 provides the signature required for facade registration
+// for version 5.
+func NewFacadeV5(ctx facade.Context) (*APIv5, error) {
+	api, err := NewFacade(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv5{&APIv6{&APIv7{api}}}, nil
+}
+
+// NewFacadeV6 provides the signature required for facade registration
+// for version 6.
+func NewFacadeV6(ctx facade.Context) (*APIv6, error) {
+	api, err := NewFacade(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv6{&APIv7{api}}, nil
+}
+
+// NewFacadeV7 provides the signature required for facade registration
+// for version 7.
+func NewFacadeV7(ctx facade.Context) (*APIv7, error) {
+	api, err := NewFacade(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv7{api}, nil
 }
 
 // NewFacade provides the signature required for facade registration.
@@ -238,6 +284,19 @@ func deployApplication(
 		return errors.Trace(err)
 	}
 
+	if !ch.Meta().Subordinate {
+		series := args.Series
+		if series == "" {
+			series = curl.Series
+		}
+		if series == "" && len(ch.Meta().Series) > 0 {
+			series = ch.Meta().Series[0]
+		}
+		if err := validateArch(backend, series, args.Constraints); err != nil {
+			return errors.Annotatef(err, "cannot deploy %q", args.ApplicationName)
+		}
+	}
+
 	// Parse storage tags in AttachStorage.
 	if len(args.AttachStorage) > 0 && args.NumUnits != 1 {
 		return errors.Errorf("AttachStorage is non-empty, but NumUnits is %d", args.NumUnits)
@@ -370,7 +429,7 @@ func (api *API) Update(args params.ApplicationUpdate) error {
 	}
 	// Update application's constraints.
 	if args.Constraints != nil {
-		return app.SetConstraints(*args.Constraints)
+		return api.setConstraints(app, *args.Constraints)
 	}
 	return nil
 }
@@ -690,7 +749,10 @@ func (api *API) CharmRelations(p params.ApplicationCharmRelations) (params.Appli
 }
 
 // Expose changes the juju-managed firewall to expose any ports that
-// were also explicitly marked by units as open.
+// were also explicitly marked by units as open. If expose settings
+// are supplied for the application's endpoints, the ports opened for
+// each endpoint are only exposed to the spaces and CIDRs in its
+// settings.
 func (api *API) Expose(args params.ApplicationExpose) error {
 	if err := api.checkCanWrite(); err != nil {
 		return err
@@ -702,11 +764,22 @@ func (api *API) Expose(args params.ApplicationExpose) error {
 	if err != nil {
 		return err
 	}
-	return app.SetExposed()
+	if len(args.ExposedEndpoints) == 0 {
+		return app.SetExposed()
+	}
+	exposed := make(map[string]state.ExposedEndpoint, len(args.ExposedEndpoints))
+	for name, settings := range args.ExposedEndpoints {
+		exposed[name] = state.ExposedEndpoint{
+			ExposeToSpaces: settings.ExposeToSpaces,
+			ExposeToCIDRs:  settings.ExposeToCIDRs,
+		}
+	}
+	return app.MergeExposeSettings(exposed)
 }
 
 // Unexpose changes the juju-managed firewall to unexpose any ports that
-// were also explicitly marked by units as open.
+// were also explicitly marked by units as open. If endpoints are
+// supplied, only their expose settings are removed.
 func (api *API) Unexpose(args params.ApplicationUnexpose) error {
 	if err := api.checkCanWrite(); err != nil {
 		return err
@@ -718,7 +791,10 @@ func (api *API) Unexpose(args params.ApplicationUnexpose) error {
 	if err != nil {
 		return err
 	}
-	return app.ClearExposed()
+	if len(args.ExposedEndpoints) == 0 {
+		return app.ClearExposed()
+	}
+	return app.UnsetExposeSettings(args.ExposedEndpoints)
 }
 
 // AddUnits adds a given number of units to an application.
@@ -923,6 +999,18 @@ func (api *APIv4) DestroyApplication(args params.Entities) (params.DestroyApplic
 	return api.API.DestroyApplication(v5args)
 }
 
+// DestroyApplication removes a given set of applications. Version 7
+// of the facade predates retaining storage, and refuses to retain it
+// rather than destroy or detach storage that was asked to be kept.
+func (api *APIv7) DestroyApplication(args params.DestroyApplicationsParams) (params.DestroyApplicationResults, error) {
+	for _, arg := range args.Applications {
+		if arg.RetainStorage {
+			return params.DestroyApplicationResults{}, errors.NotSupportedf("retaining storage")
+		}
+	}
+	return api.API.DestroyApplication(args)
+}
+
 // DestroyApplication removes a given set of applications.
 func (api *API) DestroyApplication(args params.DestroyApplicationsParams) (params.DestroyApplicationResults, error) {
 	if err := api.checkCanWrite(); err != nil {
@@ -936,6 +1024,9 @@ func (api *API) DestroyApplication(args params.DestroyApplicationsParams) (param
 		if err != nil {
 			return nil, err
 		}
+		if arg.DestroyStorage && arg.RetainStorage {
+			return nil, errors.NotValidf("destroying and retaining storage together")
+		}
 		var info params.DestroyApplicationInfo
 		app, err := api.backend.Application(tag.Id())
 		if err != nil {
@@ -983,12 +1074,19 @@ func (api *API) DestroyApplication(args params.DestroyApplicationsParams) (param
 				if err != nil {
 					return nil, err
 				}
+				if arg.RetainStorage && len(destroyed) > 0 {
+					return nil, errors.Errorf(
+						"cannot retain %s: it cannot be detached from %s",
+						storageNames(destroyed), names.ReadableString(unit.UnitTag()),
+					)
+				}
 				info.DestroyedStorage = append(info.DestroyedStorage, destroyed...)
 				info.DetachedStorage = append(info.DetachedStorage, detached...)
 			}
 		}
 		op := app.DestroyOperation()
 		op.DestroyStorage = arg.DestroyStorage
+		op.RetainStorage = arg.RetainStorage
 		if err := api.backend.ApplyOperation(op); err != nil {
 			return nil, err
 		}
@@ -1006,6 +1104,19 @@ func (api *API) DestroyApplication(args params.DestroyApplicationsParams) (param
 	return params.DestroyApplicationResults{results}, nil
 }
 
+// storageNames returns a readable list of the storage with the given
+// tags.
+func storageNames(entities []params.Entity) string {
+	readable := make([]string, len(entities))
+	for i, entity := range entities {
+		readable[i] = entity.Tag
+		if tag, err := names.ParseStorageTag(entity.Tag); err == nil {
+			readable[i] = names.ReadableString(tag)
+		}
+	}
+	return strings.Join(readable, ", ")
+}
+
 // DestroyConsumedApplications removes a given set of consumed (remote) applications.
 func (api *API) DestroyConsumedApplications(args params.DestroyConsumedApplicationsParams) (params.ErrorResults, error) {
 	if err := api.checkCanWrite(); err != nil {
@@ -1080,7 +1191,93 @@ func (api *API) SetConstraints(args params.SetConstraints) error {
 	if err != nil {
 		return err
 	}
-	return app.SetConstraints(args.Constraints)
+	return api.setConstraints(app, args.Constraints)
+}
+
+// setConstraints sets the application's constraints, having checked
+// that agent binaries are available for the architecture they imply.
+func (api *API) setConstraints(app Application, cons constraints.Value) error {
+	if app.IsPrincipal() {
+		if err := validateArch(api.backend, app.Series(), cons); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return app.SetConstraints(cons)
+}
+
+// GetAffinity returns the affinity policies of the given applications.
+func (api *API) GetAffinity(args params.Entities) (params.ApplicationAffinityResults, error) {
+	if err := api.checkCanRead(); err != nil {
+		return params.ApplicationAffinityResults{}, errors.Trace(err)
+	}
+	results := params.ApplicationAffinityResults{
+		Results: make([]params.ApplicationAffinityResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		affinity, err := api.getAffinity(arg.Tag)
+		results.Results[i].Result = affinity
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *API) getAffinity(entity string) (*params.ApplicationAffinity, error) {
+	tag, err := names.ParseApplicationTag(entity)
+	if err != nil {
+		return nil, err
+	}
+	app, err := api.backend.Application(tag.Id())
+	if err != nil {
+		return nil, err
+	}
+	policy, err := app.Affinity()
+	if err != nil {
+		return nil, err
+	}
+	violations, err := app.AffinityViolations()
+	if err != nil {
+		return nil, err
+	}
+	return &params.ApplicationAffinity{
+		ApplicationTag: tag.String(),
+		NotWith:        policy.NotWith,
+		SameZoneAs:     policy.SameZoneAs,
+		Violations:     violations,
+	}, nil
+}
+
+// SetAffinity sets the affinity policies of the given applications,
+// which govern the machines their units are assigned to from then on.
+func (api *API) SetAffinity(args params.SetApplicationsAffinity) (params.ErrorResults, error) {
+	if err := api.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		err := api.setAffinity(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *API) setAffinity(arg params.ApplicationAffinity) error {
+	tag, err := names.ParseApplicationTag(arg.ApplicationTag)
+	if err != nil {
+		return err
+	}
+	app, err := api.backend.Application(tag.Id())
+	if err != nil {
+		return err
+	}
+	return app.SetAffinity(state.AffinityPolicy{
+		NotWith:    arg.NotWith,
+		SameZoneAs: arg.SameZoneAs,
+	})
 }
 
 // AddRelation adds a relation between the specified endpoints and returns the relation info.
@@ -1103,10 +1300,11 @@ func (api *API) AddRelation(args params.AddRelation) (_ params.AddRelationResult
 
 	// Validate any CIDRs.
 	for _, cidr := range args.ViaCIDRs {
-		if _, _, err := net.ParseCIDR(cidr); err != nil {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
 			return params.AddRelationResults{}, errors.Trace(err)
 		}
-		if cidr == "0.0.0.0/0" {
+		if ones, _ := ipNet.Mask.Size(); ones == 0 {
 			return params.AddRelationResults{}, errors.Errorf("CIDR %q not allowed", cidr)
 		}
 	}
@@ -1400,6 +1598,33 @@ func (api *API) maybeUpdateExistingApplicationEndpoints(
 // rpc/rpcreflect/type.go:newMethod skips 2-argument methods, so this
 // removes the method as far as the RPC machinery is concerned.
 
+// Expose changes the juju-managed firewall to expose any ports that
+// were also explicitly marked by units as open. Version 6 of the facade
+// predates expose settings for endpoints, and refuses them rather than
+// expose the application more widely than asked.
+func (api *APIv6) Expose(args params.ApplicationExpose) error {
+	if len(args.ExposedEndpoints) > 0 {
+		return errors.NotSupportedf("exposing application endpoints")
+	}
+	return api.API.Expose(args)
+}
+
+// Unexpose changes the juju-managed firewall to unexpose any ports that
+// were also explicitly marked by units as open. Version 6 of the facade
+// predates expose settings for endpoints, and refuses them.
+func (api *APIv6) Unexpose(args params.ApplicationUnexpose) error {
+	if len(args.ExposedEndpoints) > 0 {
+		return errors.NotSupportedf("unexposing application endpoints")
+	}
+	return api.API.Unexpose(args)
+}
+
+// GetAffinity isn't on the V5 API.
+func (u *APIv5) GetAffinity(_, _ struct{}) {}
+
+// SetAffinity isn't on the V5 API.
+func (u *APIv5) SetAffinity(_, _ struct{}) {}
+
 // UpdateApplicationSeries isn't on the V4 API.
 func (u *APIv4) UpdateApplicationSeries(_, _ struct{}) {}
 
@@ -1996,7 +1996,7 @@ func (s *applicationSuite) TestApplicationExpose(c *gc.C) {
 	c.Assert(apps[1].IsExposed(), jc.IsTrue)
 	for i, t := range applicationExposeTests {
 		c.Logf("test %d. %s", i, t.about)
-		err = s.applicationAPI.Expose(params.ApplicationExpose{t.application})
+		err = s.applicationAPI.Expose(params.ApplicationExpose{ApplicationName: t.application})
 		if t.err != "" {
 			c.Assert(err, gc.ErrorMatches, t.err)
 		} else {
@@ -2008,6 +2008,67 @@ func (s *applicationSuite) TestApplicationExpose(c *gc.C) {
 	}
 }
 
+func (s *applicationSuite) TestApplicationExposeEndpoints(c *gc.C) {
+	app := s.application
+	err := s.applicationAPI.Expose(params.ApplicationExpose{
+		ApplicationName: "mysql",
+		ExposedEndpoints: map[string]params.ExposedEndpoint{
+			"":             {},
+			"server-admin": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = app.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(app.ExposedEndpoints(), jc.DeepEquals, map[string]state.ExposedEndpoint{
+		"":             {ExposeToCIDRs: []string{"0.0.0.0/0"}},
+		"server-admin": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+
+	err = s.applicationAPI.Unexpose(params.ApplicationUnexpose{
+		ApplicationName:  "mysql",
+		ExposedEndpoints: []string{""},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = app.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(app.ExposedEndpoints(), jc.DeepEquals, map[string]state.ExposedEndpoint{
+		"server-admin": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+}
+
+func (s *applicationSuite) TestApplicationExposeUnknownEndpoint(c *gc.C) {
+	err := s.applicationAPI.Expose(params.ApplicationExpose{
+		ApplicationName: "mysql",
+		ExposedEndpoints: map[string]params.ExposedEndpoint{
+			"foo": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+		},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot expose application "mysql": application "mysql" has no "foo" relation`)
+}
+
+func (s *applicationSuite) TestApplicationExposeEndpointsV6(c *gc.C) {
+	apiV6 := &application.APIv6{&application.APIv7{s.applicationAPI}}
+	err := apiV6.Expose(params.ApplicationExpose{
+		ApplicationName: "mysql",
+		ExposedEndpoints: map[string]params.ExposedEndpoint{
+			"server-admin": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+		},
+	})
+	c.Assert(err, gc.ErrorMatches, "exposing application endpoints not supported")
+	err = apiV6.Unexpose(params.ApplicationUnexpose{
+		ApplicationName:  "mysql",
+		ExposedEndpoints: []string{""},
+	})
+	c.Assert(err, gc.ErrorMatches, "unexposing application endpoints not supported")
+
+	err = apiV6.Expose(params.ApplicationExpose{ApplicationName: "mysql"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.application.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.application.IsExposed(), jc.IsTrue)
+}
+
 func (s *applicationSuite) setupApplicationExpose(c *gc.C) {
 	charm := s.AddTestingCharm(c, "dummy")
 	applicationNames := []string{"dummy-application", "exposed-application"}
@@ -2048,7 +2109,7 @@ var applicationExposeTests = []struct {
 func (s *applicationSuite) assertApplicationExpose(c *gc.C) {
 	for i, t := range applicationExposeTests {
 		c.Logf("test %d. %s", i, t.about)
-		err := s.applicationAPI.Expose(params.ApplicationExpose{t.application})
+		err := s.applicationAPI.Expose(params.ApplicationExpose{ApplicationName: t.application})
 		if t.err != "" {
 			c.Assert(err, gc.ErrorMatches, t.err)
 		} else {
@@ -2063,7 +2124,7 @@ func (s *applicationSuite) assertApplicationExpose(c *gc.C) {
 func (s *applicationSuite) assertApplicationExposeBlocked(c *gc.C, msg string) {
 	for i, t := range applicationExposeTests {
 		c.Logf("test %d. %s", i, t.about)
-		err := s.applicationAPI.Expose(params.ApplicationExpose{t.application})
+		err := s.applicationAPI.Expose(params.ApplicationExpose{ApplicationName: t.application})
 		s.AssertBlocked(c, err, msg)
 	}
 }
@@ -2121,7 +2182,7 @@ func (s *applicationSuite) TestApplicationUnexpose(c *gc.C) {
 			app.SetExposed()
 		}
 		c.Assert(app.IsExposed(), gc.Equals, t.initial)
-		err := s.applicationAPI.Unexpose(params.ApplicationUnexpose{t.application})
+		err := s.applicationAPI.Unexpose(params.ApplicationUnexpose{ApplicationName: t.application})
 		if t.err == "" {
 			c.Assert(err, jc.ErrorIsNil)
 			app.Refresh()
@@ -2143,7 +2204,7 @@ func (s *applicationSuite) setupApplicationUnexpose(c *gc.C) *state.Application
 }
 
 func (s *applicationSuite) assertApplicationUnexpose(c *gc.C, app *state.Application) {
-	err := s.applicationAPI.Unexpose(params.ApplicationUnexpose{"dummy-application"})
+	err := s.applicationAPI.Unexpose(params.ApplicationUnexpose{ApplicationName: "dummy-application"})
 	c.Assert(err, jc.ErrorIsNil)
 	app.Refresh()
 	c.Assert(app.IsExposed(), gc.Equals, false)
@@ -2152,7 +2213,7 @@ func (s *applicationSuite) assertApplicationUnexpose(c *gc.C, app *state.Applica
 }
 
 func (s *applicationSuite) assertApplicationUnexposeBlocked(c *gc.C, app *state.Application, msg string) {
-	err := s.applicationAPI.Unexpose(params.ApplicationUnexpose{"dummy-application"})
+	err := s.applicationAPI.Unexpose(params.ApplicationUnexpose{ApplicationName: "dummy-application"})
 	s.AssertBlocked(c, err, msg)
 	err = app.Destroy()
 	c.Assert(err, jc.ErrorIsNil)
@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/application"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/network"
@@ -355,6 +356,82 @@ func (s *ApplicationSuite) TestDestroyApplicationDestroyStorage(c *gc.C) {
 	})
 }
 
+func (s *ApplicationSuite) TestDestroyApplicationRetainStorage(c *gc.C) {
+	s.backend.storageInstanceFilesystems["pgdata/1"].detachable = true
+	results, err := s.api.DestroyApplication(params.DestroyApplicationsParams{
+		Applications: []params.DestroyApplicationParams{{
+			ApplicationTag: "application-postgresql",
+			RetainStorage:  true,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0], jc.DeepEquals, params.DestroyApplicationResult{
+		Info: &params.DestroyApplicationInfo{
+			DestroyedUnits: []params.Entity{
+				{Tag: "unit-postgresql-0"},
+				{Tag: "unit-postgresql-1"},
+			},
+			DetachedStorage: []params.Entity{
+				{Tag: "storage-pgdata-0"},
+				{Tag: "storage-pgdata-1"},
+			},
+		},
+	})
+	s.backend.CheckCall(c, 8, "ApplyOperation", &state.DestroyApplicationOperation{
+		RetainStorage: true,
+	})
+}
+
+func (s *ApplicationSuite) TestDestroyApplicationRetainStorageV7(c *gc.C) {
+	apiV7 := &application.APIv7{s.api}
+	_, err := apiV7.DestroyApplication(params.DestroyApplicationsParams{
+		Applications: []params.DestroyApplicationParams{{
+			ApplicationTag: "application-postgresql",
+			RetainStorage:  true,
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, "retaining storage not supported")
+	s.backend.CheckNoCalls(c)
+}
+
+func (s *ApplicationSuite) TestDestroyApplicationRetainStorageNotDetachable(c *gc.C) {
+	results, err := s.api.DestroyApplication(params.DestroyApplicationsParams{
+		Applications: []params.DestroyApplicationParams{{
+			ApplicationTag: "application-postgresql",
+			RetainStorage:  true,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0], jc.DeepEquals, params.DestroyApplicationResult{
+		Error: &params.Error{
+			Message: "cannot retain storage pgdata/1: it cannot be detached from unit postgresql/0",
+		},
+	})
+	for _, call := range s.backend.Calls() {
+		c.Assert(call.FuncName, gc.Not(gc.Equals), "ApplyOperation")
+	}
+}
+
+func (s *ApplicationSuite) TestDestroyApplicationRetainAndDestroyStorage(c *gc.C) {
+	results, err := s.api.DestroyApplication(params.DestroyApplicationsParams{
+		Applications: []params.DestroyApplicationParams{{
+			ApplicationTag: "application-postgresql",
+			DestroyStorage: true,
+			RetainStorage:  true,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0], jc.DeepEquals, params.DestroyApplicationResult{
+		Error: &params.Error{
+			Message: "destroying and retaining storage together not valid",
+		},
+	})
+	s.backend.CheckCallNames(c, "ModelTag")
+}
+
 func (s *ApplicationSuite) TestDestroyApplicationNotFound(c *gc.C) {
 	delete(s.backend.applications, "postgresql")
 	results, err := s.api.DestroyApplication(params.DestroyApplicationsParams{
@@ -475,6 +552,65 @@ func (s *ApplicationSuite) TestDeployAttachStorage(c *gc.C) {
 	c.Assert(results.Results[2].Error, gc.ErrorMatches, `"volume-baz-0" is not a valid volume tag`)
 }
 
+func (s *ApplicationSuite) TestDeployArchNotAvailable(c *gc.C) {
+	s.backend.modelConstraints = constraints.MustParse("arch=arm64")
+	s.backend.agentArches = []string{"amd64", "s390x"}
+	results, err := s.api.Deploy(params.ApplicationsDeploy{
+		Applications: []params.ApplicationDeploy{{
+			ApplicationName: "foo",
+			CharmURL:        "cs:xenial/foo-0",
+			NumUnits:        1,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches,
+		`cannot deploy "foo": no agent binaries for architecture "arm64" on series "xenial", `+
+			`available architectures are amd64, s390x`)
+	s.backend.CheckCall(c, 2, "AgentArchitectures", "xenial")
+}
+
+func (s *ApplicationSuite) TestDeployArchConstraintOverridesModel(c *gc.C) {
+	s.backend.modelConstraints = constraints.MustParse("arch=arm64")
+	s.backend.agentArches = []string{"amd64"}
+	results, err := s.api.Deploy(params.ApplicationsDeploy{
+		Applications: []params.ApplicationDeploy{{
+			ApplicationName: "foo",
+			CharmURL:        "cs:xenial/foo-0",
+			NumUnits:        1,
+			Constraints:     constraints.MustParse("arch=amd64"),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	s.backend.CheckCallNames(c, "Charm", "AgentArchitectures")
+}
+
+func (s *ApplicationSuite) TestSetConstraintsArchNotAvailable(c *gc.C) {
+	s.backend.agentArches = []string{"amd64"}
+	err := s.api.SetConstraints(params.SetConstraints{
+		ApplicationName: "postgresql",
+		Constraints:     constraints.MustParse("arch=arm64"),
+	})
+	c.Assert(err, gc.ErrorMatches,
+		`no agent binaries for architecture "arm64" on series "quantal", available architectures are amd64`)
+	app := s.backend.applications["postgresql"].(*mockApplication)
+	app.CheckCallNames(c, "IsPrincipal", "Series")
+}
+
+func (s *ApplicationSuite) TestSetConstraintsArchAvailable(c *gc.C) {
+	s.backend.agentArches = []string{"amd64", "arm64"}
+	cons := constraints.MustParse("arch=arm64")
+	err := s.api.SetConstraints(params.SetConstraints{
+		ApplicationName: "postgresql",
+		Constraints:     cons,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	app := s.backend.applications["postgresql"].(*mockApplication)
+	app.CheckCall(c, 2, "SetConstraints", cons)
+}
+
 func (s *ApplicationSuite) TestAddUnitsAttachStorage(c *gc.C) {
 	results, err := s.api.AddUnits(params.AddApplicationUnits{
 		ApplicationName: "postgresql",
@@ -973,6 +1109,58 @@ func (s *ApplicationSuite) TestApplicationUpdateSeriesPermissionDenied(c *gc.C)
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 }
 
+func (s *ApplicationSuite) TestGetAffinity(c *gc.C) {
+	app := s.backend.applications["postgresql"].(*mockApplication)
+	app.affinity = state.AffinityPolicy{NotWith: []string{"mysql"}}
+	app.violations = map[string][]string{"postgresql/0": {"shares machine 0 with mysql/0"}}
+	results, err := s.api.GetAffinity(params.Entities{Entities: []params.Entity{
+		{Tag: "application-postgresql"}, {Tag: "application-name"}, {Tag: "unit-mysql-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ApplicationAffinityResults{
+		Results: []params.ApplicationAffinityResult{{
+			Result: &params.ApplicationAffinity{
+				ApplicationTag: "application-postgresql",
+				NotWith:        []string{"mysql"},
+				Violations:     map[string][]string{"postgresql/0": {"shares machine 0 with mysql/0"}},
+			},
+		}, {
+			Error: &params.Error{Message: `application "name" not found`, Code: "not found"},
+		}, {
+			Error: &params.Error{Message: `"unit-mysql-0" is not a valid application tag`},
+		}},
+	})
+	app.CheckCallNames(c, "Affinity", "AffinityViolations")
+}
+
+func (s *ApplicationSuite) TestSetAffinity(c *gc.C) {
+	results, err := s.api.SetAffinity(params.SetApplicationsAffinity{
+		Args: []params.ApplicationAffinity{{
+			ApplicationTag: "application-postgresql",
+			NotWith:        []string{"mysql"},
+			SameZoneAs:     []string{"haproxy"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+	s.blockChecker.CheckCallNames(c, "ChangeAllowed")
+	app := s.backend.applications["postgresql"].(*mockApplication)
+	app.CheckCall(c, 0, "SetAffinity", state.AffinityPolicy{
+		NotWith:    []string{"mysql"},
+		SameZoneAs: []string{"haproxy"},
+	})
+}
+
+func (s *ApplicationSuite) TestSetAffinityPermissionDenied(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("fred"))
+	_, err := s.api.SetAffinity(params.SetApplicationsAffinity{
+		Args: []params.ApplicationAffinity{{ApplicationTag: "application-postgresql"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	app := s.backend.applications["postgresql"].(*mockApplication)
+	app.CheckNoCalls(c)
+}
+
 func (s *ApplicationSuite) TestRemoteRelationBadCIDR(c *gc.C) {
 	endpoints := []string{"wordpress", "hosted-mysql:nope"}
 	_, err := s.api.AddRelation(params.AddRelation{Endpoints: endpoints, ViaCIDRs: []string{"bad.cidr"}})
@@ -984,3 +1172,9 @@ func (s *ApplicationSuite) TestRemoteRelationDisAllowedCIDR(c *gc.C) {
 	_, err := s.api.AddRelation(params.AddRelation{Endpoints: endpoints, ViaCIDRs: []string{"0.0.0.0/0"}})
 	c.Assert(err, gc.ErrorMatches, `CIDR "0.0.0.0/0" not allowed`)
 }
+
+func (s *ApplicationSuite) TestRemoteRelationDisAllowedIPv6CIDR(c *gc.C) {
+	endpoints := []string{"wordpress", "hosted-mysql:nope"}
+	_, err := s.api.AddRelation(params.AddRelation{Endpoints: endpoints, ViaCIDRs: []string{"2001:db8::/64", "::/0"}})
+	c.Assert(err, gc.ErrorMatches, `CIDR "::/0" not allowed`)
+}
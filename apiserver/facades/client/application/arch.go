@@ -0,0 +1,111 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state/stateenvirons"
+)
+
+// validateArch checks that agent binaries of the model's agent version
+// are available for the architecture that an application's machines
+// will be started with, so that a deployment whose machines could not
+// run an agent fails up front rather than when the machines are
+// provisioned. The application's arch constraint takes precedence over
+// the model's; if neither is set, the provider chooses the architecture
+// and there is nothing to check. Only the constraints are checked: the
+// architectures of existing machines are checked by state when units
+// are assigned to them, and charms do not declare architectures.
+func validateArch(backend Backend, series string, cons constraints.Value) error {
+	if series == "" {
+		return nil
+	}
+	if !cons.HasArch() {
+		modelCons, err := backend.ModelConstraints()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		cons = modelCons
+	}
+	if !cons.HasArch() {
+		return nil
+	}
+	arch := *cons.Arch
+	arches, err := backend.AgentArchitectures(series)
+	if err != nil {
+		// The machines' provisioning will report the failure to
+		// find agent binaries, so don't prevent the deployment.
+		logger.Warningf("cannot find agent binaries for series %q: %v", series, err)
+		return nil
+	}
+	if len(arches) == 0 {
+		// Likewise if there are none for the series at all; only
+		// a missing architecture is specific to the constraints.
+		return nil
+	}
+	for _, available := range arches {
+		if available == arch {
+			return nil
+		}
+	}
+	return errors.NewNotSupported(errors.Errorf(
+		"no agent binaries for architecture %q on series %q, available architectures are %s",
+		arch, series, strings.Join(arches, ", "),
+	), "")
+}
+
+// AgentArchitectures returns the architectures for which agent binaries
+// of the model's agent version are available for the given series,
+// whether cached by the controller or published in simplestreams.
+func (s stateShim) AgentArchitectures(series string) ([]string, error) {
+	model, err := s.State.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cfg, err := model.ModelConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	agentVersion, ok := cfg.AgentVersion()
+	if !ok {
+		return nil, errors.New("agent version not set in model config")
+	}
+	finder := common.NewToolsFinder(
+		stateenvirons.EnvironConfigGetter{s.State, model},
+		s.State,
+		common.NewToolsURLGetter(s.State.ModelUUID(), s.State),
+	)
+	result, err := finder.FindTools(params.FindToolsParams{
+		Number:       agentVersion,
+		MajorVersion: -1,
+		MinorVersion: -1,
+		Series:       series,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		if params.IsCodeNotFound(result.Error) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	arches := set.NewStrings()
+	for _, tools := range result.List {
+		arches.Add(tools.Version.Arch)
+	}
+	return arches.SortedValues(), nil
+}
+
+// ModelConstraints returns the model's constraints.
+func (s stateShim) ModelConstraints() (constraints.Value, error) {
+	return s.State.ModelConstraints()
+}
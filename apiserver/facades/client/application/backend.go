@@ -23,6 +23,7 @@ import (
 type Backend interface {
 	storagecommon.StorageInterface
 
+	AgentArchitectures(series string) ([]string, error)
 	AllModelUUIDs() ([]string, error)
 	Application(string) (Application, error)
 	ApplyOperation(state.ModelOperation) error
@@ -35,6 +36,7 @@ type Backend interface {
 	Relation(int) (Relation, error)
 	InferEndpoints(...string) ([]state.Endpoint, error)
 	Machine(string) (Machine, error)
+	ModelConstraints() (constraints.Value, error)
 	ModelTag() names.ModelTag
 	Unit(string) (Unit, error)
 	SaveController(info crossmodel.ControllerInfo, modelUUID string) (ExternalController, error)
@@ -58,6 +60,8 @@ type BlockChecker interface {
 // the same names.
 type Application interface {
 	AddUnit(state.AddUnitParams) (Unit, error)
+	Affinity() (state.AffinityPolicy, error)
+	AffinityViolations() (map[string][]string, error)
 	AllUnits() ([]Unit, error)
 	Charm() (Charm, bool, error)
 	CharmURL() (*charm.URL, bool)
@@ -69,12 +73,15 @@ type Application interface {
 	DestroyOperation() *state.DestroyApplicationOperation
 	Endpoints() ([]state.Endpoint, error)
 	IsPrincipal() bool
+	MergeExposeSettings(map[string]state.ExposedEndpoint) error
 	Series() string
+	SetAffinity(state.AffinityPolicy) error
 	SetCharm(state.SetCharmConfig) error
 	SetConstraints(constraints.Value) error
 	SetExposed() error
 	SetMetricCredentials([]byte) error
 	SetMinUnits(int) error
+	UnsetExposeSettings([]string) error
 	UpdateApplicationSeries(string, bool) error
 	UpdateConfigSettings(charm.Settings) error
 }
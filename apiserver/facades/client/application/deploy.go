@@ -136,7 +136,8 @@ func getEffectiveBindingsForCharmMeta(charmMeta *charm.Meta, givenBindings map[s
 	}
 
 	// Get the application-level default binding for all unspecified endpoints, if
-	// set. Otherwise use the empty default.
+	// set. Otherwise leave them unspecified, so that they are bound to the
+	// model's default space when the application is added.
 	applicationDefaultSpace, defaultSupplied := givenBindings[""]
 	if defaultSupplied {
 		// Record that a default binding was requested
@@ -147,7 +148,7 @@ func getEffectiveBindingsForCharmMeta(charmMeta *charm.Meta, givenBindings map[s
 	for endpoint, _ := range defaultBindings {
 		if givenSpace, isGiven := givenBindings[endpoint]; isGiven {
 			effectiveBindings[endpoint] = givenSpace
-		} else {
+		} else if defaultSupplied {
 			effectiveBindings[endpoint] = applicationDefaultSpace
 		}
 	}
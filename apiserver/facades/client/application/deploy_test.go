@@ -101,6 +101,41 @@ func (s *DeployLocalSuite) TestDeployWithImplicitBindings(c *gc.C) {
 	})
 }
 
+func (s *DeployLocalSuite) TestDeployWithModelDefaultSpace(c *gc.C) {
+	wordpressCharm := s.addWordpressCharm(c)
+	_, err := s.State.AddSpace("db", "", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("internal", "", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.IAASModel.UpdateModelConfig(map[string]interface{}{"default-space": "internal"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	app, err := application.DeployApplication(stateDeployer{s.State},
+		application.DeployApplicationParams{
+			ApplicationName: "bob",
+			Charm:           wordpressCharm,
+			EndpointBindings: map[string]string{
+				"db": "db",
+			},
+		})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertBindings(c, app, map[string]string{
+		// default binding, from the model config
+		"": "internal",
+		// relation names
+		"url":             "internal",
+		"logging-dir":     "internal",
+		"monitoring-port": "internal",
+		"db":              "db",
+		"cache":           "internal",
+		// extra-bindings names
+		"db-client": "internal",
+		"admin-api": "internal",
+		"foo-bar":   "internal",
+	})
+}
+
 func (s *DeployLocalSuite) addWordpressCharm(c *gc.C) *state.Charm {
 	wordpressCharmURL := charm.MustParseURL("local:quantal/wordpress")
 	return s.addWordpressCharmFromURL(c, wordpressCharmURL)
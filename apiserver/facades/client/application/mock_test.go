@@ -16,6 +16,7 @@ import (
 	"gopkg.in/macaroon.v1"
 
 	"github.com/juju/juju/apiserver/facades/client/application"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
@@ -82,6 +83,8 @@ type mockApplication struct {
 	jtesting.Stub
 	application.Application
 
+	affinity    state.AffinityPolicy
+	violations  map[string][]string
 	bindings    map[string]string
 	charm       *mockCharm
 	curl        *charm.URL
@@ -143,6 +146,21 @@ func (a *mockApplication) AddUnit(args state.AddUnitParams) (application.Unit, e
 	return &mockUnit{tag: unitTag}, nil
 }
 
+func (a *mockApplication) Affinity() (state.AffinityPolicy, error) {
+	a.MethodCall(a, "Affinity")
+	return a.affinity, a.NextErr()
+}
+
+func (a *mockApplication) AffinityViolations() (map[string][]string, error) {
+	a.MethodCall(a, "AffinityViolations")
+	return a.violations, a.NextErr()
+}
+
+func (a *mockApplication) SetAffinity(policy state.AffinityPolicy) error {
+	a.MethodCall(a, "SetAffinity", policy)
+	return a.NextErr()
+}
+
 func (a *mockApplication) IsPrincipal() bool {
 	a.MethodCall(a, "IsPrincipal")
 	a.PopNoErr()
@@ -160,6 +178,11 @@ func (a *mockApplication) Series() string {
 	return a.series
 }
 
+func (a *mockApplication) SetConstraints(cons constraints.Value) error {
+	a.MethodCall(a, "SetConstraints", cons)
+	return a.NextErr()
+}
+
 type mockRemoteApplication struct {
 	jtesting.Stub
 	name           string
@@ -268,12 +291,24 @@ type mockBackend struct {
 	storageInstances           map[string]*mockStorage
 	storageInstanceFilesystems map[string]*mockFilesystem
 	controllers                map[string]crossmodel.ControllerInfo
+	modelConstraints           constraints.Value
+	agentArches                []string
 }
 
 func (m *mockBackend) ControllerTag() names.ControllerTag {
 	return coretesting.ControllerTag
 }
 
+func (m *mockBackend) ModelConstraints() (constraints.Value, error) {
+	m.MethodCall(m, "ModelConstraints")
+	return m.modelConstraints, m.NextErr()
+}
+
+func (m *mockBackend) AgentArchitectures(series string) ([]string, error) {
+	m.MethodCall(m, "AgentArchitectures", series)
+	return m.agentArches, m.NextErr()
+}
+
 func (m *mockBackend) GetBlockForType(t state.BlockType) (state.Block, bool, error) {
 	return nil, false, nil
 }
@@ -40,6 +40,12 @@ type Backend interface {
 	RestoreInfo() *state.RestoreInfo
 }
 
+// APIv1 serves the backup-specific API methods of version 1 of the
+// facade.
+type APIv1 struct {
+	*API
+}
+
 // API serves backup-specific API methods.
 type API struct {
 	backend Backend
@@ -166,3 +172,8 @@ func MetadataFromResult(result params.BackupsMetadataResult) *backups.Metadata {
 	meta.SetFileInfo(result.Size, result.Checksum, result.ChecksumFormat)
 	return meta
 }
+
+// Verify isn't on the V1 API; rpc/rpcreflect/type.go:newMethod skips
+// 2-argument methods, so this removes the method as far as the RPC
+// machinery is concerned.
+func (a *APIv1) Verify(_, _ struct{}) {}
@@ -41,6 +41,16 @@ func NewFacade(st *state.State, resources facade.Resources, authorizer facade.Au
 	return NewAPI(&stateShim{st, model}, resources, authorizer)
 }
 
+// NewFacadeV1 provides the signature required for registering
+// version 1 of the facade.
+func NewFacadeV1(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*APIv1, error) {
+	api, err := NewFacade(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv1{api}, nil
+}
+
 // ControllerTag disambiguates the ControllerTag method pending further
 // refactoring to separate model functionality from state functionality.
 func (s *stateShim) ControllerTag() names.ControllerTag {
@@ -0,0 +1,30 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Verify restores the identified backup into a scratch mongod server
+// on the controller and reports any discrepancies between it and the
+// live controller.
+func (a *API) Verify(args params.BackupsVerifyArgs) (params.BackupsVerifyResult, error) {
+	var result params.BackupsVerifyResult
+	backupsMethods, closer := newBackups(a.backend)
+	defer closer.Close()
+
+	session := a.backend.MongoSession().Copy()
+	defer session.Close()
+
+	verified, err := backupsMethods.Verify(args.ID, session)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Models = verified.Models
+	result.Discrepancies = verified.Discrepancies
+	return result, nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"reflect"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	backupsAPI "github.com/juju/juju/apiserver/facades/client/backups"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/state/backups"
+)
+
+func (s *backupsSuite) TestVerifyOkay(c *gc.C) {
+	impl := s.setBackups(c, s.meta, "")
+	impl.VerifyResult = &backups.VerifyResult{
+		Models:        []string{"uuid-1"},
+		Discrepancies: []string{`model "new" (uuid-2) missing from backup`},
+	}
+	result, err := s.api.Verify(params.BackupsVerifyArgs{ID: "some-id"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result, jc.DeepEquals, params.BackupsVerifyResult{
+		Models:        []string{"uuid-1"},
+		Discrepancies: []string{`model "new" (uuid-2) missing from backup`},
+	})
+	c.Check(impl.Calls, jc.DeepEquals, []string{"Verify"})
+	c.Check(impl.IDArg, gc.Equals, "some-id")
+}
+
+func (s *backupsSuite) TestVerifyError(c *gc.C) {
+	s.setBackups(c, nil, "failed!")
+	_, err := s.api.Verify(params.BackupsVerifyArgs{ID: "some-id"})
+	c.Check(err, gc.ErrorMatches, "failed!")
+}
+
+func (s *backupsSuite) TestVerifyNotOnV1(c *gc.C) {
+	objType := rpcreflect.ObjTypeOf(reflect.TypeOf(&backupsAPI.APIv1{}))
+	_, err := objType.Method("Verify")
+	c.Check(err, gc.Equals, rpcreflect.ErrMethodNotFound)
+	_, err = objType.Method("Create")
+	c.Check(err, jc.ErrorIsNil)
+}
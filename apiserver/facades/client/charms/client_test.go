@@ -10,8 +10,12 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/facades/client/charms"
+	"github.com/juju/juju/apiserver/lockout"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/sessions"
 	"github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/cache"
+	"github.com/juju/juju/core/tracing"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
@@ -29,13 +33,17 @@ var _ = gc.Suite(&charmsSuite{})
 // charmsSuiteContext implements the facade.Context interface.
 type charmsSuiteContext struct{ cs *charmsSuite }
 
-func (ctx *charmsSuiteContext) Abort() <-chan struct{}      { return nil }
-func (ctx *charmsSuiteContext) Auth() facade.Authorizer     { return ctx.cs.auth }
-func (ctx *charmsSuiteContext) Dispose()                    {}
-func (ctx *charmsSuiteContext) Resources() facade.Resources { return common.NewResources() }
-func (ctx *charmsSuiteContext) State() *state.State         { return ctx.cs.State }
-func (ctx *charmsSuiteContext) StatePool() *state.StatePool { return nil }
-func (ctx *charmsSuiteContext) ID() string                  { return "" }
+func (ctx *charmsSuiteContext) Abort() <-chan struct{}          { return nil }
+func (ctx *charmsSuiteContext) Auth() facade.Authorizer         { return ctx.cs.auth }
+func (ctx *charmsSuiteContext) Dispose()                        {}
+func (ctx *charmsSuiteContext) Resources() facade.Resources     { return common.NewResources() }
+func (ctx *charmsSuiteContext) State() *state.State             { return ctx.cs.State }
+func (ctx *charmsSuiteContext) StatePool() *state.StatePool     { return nil }
+func (ctx *charmsSuiteContext) ModelCache() *cache.Controller   { return nil }
+func (ctx *charmsSuiteContext) SpanExporter() tracing.Recorder  { return nil }
+func (ctx *charmsSuiteContext) Sessions() *sessions.Tracker     { return nil }
+func (ctx *charmsSuiteContext) LoginLockouts() *lockout.Tracker { return nil }
+func (ctx *charmsSuiteContext) ID() string                      { return "" }
 
 func (s *charmsSuite) SetUpTest(c *gc.C) {
 	s.JujuConnSuite.SetUpTest(c)
@@ -0,0 +1,154 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package chatsummary
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/version"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
+)
+
+// Backend defines the state functionality required by the chatsummary
+// facade.
+type Backend interface {
+	ModelTag() names.ModelTag
+
+	// ControllerConfig returns the controller's configuration, which
+	// holds the webhooks summaries may be pushed to.
+	ControllerConfig() (controller.Config, error)
+
+	// Model returns the model being summarised.
+	Model() (Model, error)
+
+	AllMachines() ([]Machine, error)
+	AllApplications() ([]Application, error)
+
+	// LatestCharmRevision returns the latest known revision of the
+	// charm with the given revisionless URL, or an error satisfying
+	// errors.IsNotFound if none is known.
+	LatestCharmRevision(curl *charm.URL) (int, error)
+}
+
+// Model defines the model functionality required by the chatsummary
+// facade.
+type Model interface {
+	Name() string
+
+	// AgentVersion returns the model's current agent version.
+	AgentVersion() (version.Number, error)
+
+	LatestToolsVersion() version.Number
+}
+
+// Machine defines the machine functionality required by the
+// chatsummary facade.
+type Machine interface {
+	Id() string
+	Status() (status.StatusInfo, error)
+}
+
+// Application defines the application functionality required by the
+// chatsummary facade.
+type Application interface {
+	Name() string
+	CharmURL() (*charm.URL, bool)
+	AllUnits() ([]Unit, error)
+}
+
+// Unit defines the unit functionality required by the chatsummary
+// facade.
+type Unit interface {
+	Name() string
+	Status() (status.StatusInfo, error)
+	AgentStatus() (status.StatusInfo, error)
+}
+
+// This type is an untested shim to let us wrap state in a sensible
+// interface and avoid writing tests that depend on mongodb. If you were
+// to change any part of it so that it were no longer *obviously* and
+// *trivially* correct, you would be Doing It Wrong.
+type stateShim struct {
+	*state.State
+}
+
+func (s stateShim) ModelTag() names.ModelTag {
+	return names.NewModelTag(s.State.ModelUUID())
+}
+
+func (s stateShim) Model() (Model, error) {
+	model, err := s.State.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return modelShim{model}, nil
+}
+
+func (s stateShim) AllMachines() ([]Machine, error) {
+	machines, err := s.State.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]Machine, len(machines))
+	for i, machine := range machines {
+		result[i] = machine
+	}
+	return result, nil
+}
+
+func (s stateShim) AllApplications() ([]Application, error) {
+	applications, err := s.State.AllApplications()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]Application, len(applications))
+	for i, app := range applications {
+		result[i] = applicationShim{app}
+	}
+	return result, nil
+}
+
+func (s stateShim) LatestCharmRevision(curl *charm.URL) (int, error) {
+	ch, err := s.State.LatestPlaceholderCharm(curl)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return ch.Revision(), nil
+}
+
+type modelShim struct {
+	*state.Model
+}
+
+func (m modelShim) AgentVersion() (version.Number, error) {
+	cfg, err := m.Model.Config()
+	if err != nil {
+		return version.Number{}, errors.Trace(err)
+	}
+	current, ok := cfg.AgentVersion()
+	if !ok {
+		return version.Number{}, errors.NotFoundf("agent version")
+	}
+	return current, nil
+}
+
+type applicationShim struct {
+	*state.Application
+}
+
+func (a applicationShim) AllUnits() ([]Unit, error) {
+	units, err := a.Application.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]Unit, len(units))
+	for i, unit := range units {
+		result[i] = unit
+	}
+	return result, nil
+}
@@ -0,0 +1,316 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package chatsummary provides the ChatSummary facade, which reports a
+// compact, human oriented summary of a model - its health, size,
+// recent errors and pending upgrades - sized for chat messages, so that
+// chat bots need not digest FullStatus.
+package chatsummary
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/status"
+)
+
+const (
+	// MaxRecentErrors is the largest number of errors included in a
+	// summary.
+	MaxRecentErrors = 5
+
+	// maxMessageLength is the length at which status messages are
+	// truncated in a summary.
+	maxMessageLength = 120
+
+	// webhookTimeout bounds the time taken to push a summary.
+	webhookTimeout = 30 * time.Second
+)
+
+const (
+	healthHealthy  = "healthy"
+	healthDegraded = "degraded"
+	healthError    = "error"
+)
+
+// HTTPClient defines the HTTP client functionality used to push
+// summaries to webhooks.
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// API provides the ChatSummary facade for v1.
+type API struct {
+	backend    Backend
+	authorizer facade.Authorizer
+	client     HTTPClient
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	client := &http.Client{
+		Timeout: webhookTimeout,
+		// Redirects are not followed, so that summaries are only
+		// ever sent to the configured webhooks.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return NewAPI(stateShim{ctx.State()}, ctx.Auth(), client)
+}
+
+// NewAPI returns a new ChatSummary API facade.
+func NewAPI(backend Backend, authorizer facade.Authorizer, client HTTPClient) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		authorizer: authorizer,
+		client:     client,
+	}, nil
+}
+
+func (api *API) checkAccess(access permission.Access) error {
+	allowed, err := api.authorizer.HasPermission(access, api.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !allowed {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// Summary returns the chat summary of the model.
+func (api *API) Summary() (params.ChatSummary, error) {
+	if err := api.checkAccess(permission.ReadAccess); err != nil {
+		return params.ChatSummary{}, errors.Trace(err)
+	}
+	summary, err := api.summary()
+	if err != nil {
+		return params.ChatSummary{}, errors.Trace(err)
+	}
+	return summary, nil
+}
+
+// PushSummary posts the chat summary of the model to the given webhook.
+// As the controller makes the request, pushing requires admin access
+// to the model, and the webhook must be one of those configured in the
+// controller's chat-webhook-urls.
+func (api *API) PushSummary(args params.ChatSummaryPushArgs) error {
+	if err := api.checkAccess(permission.AdminAccess); err != nil {
+		return errors.Trace(err)
+	}
+	webhook, err := url.Parse(args.WebhookURL)
+	if err != nil || (webhook.Scheme != "http" && webhook.Scheme != "https") || webhook.Host == "" {
+		return errors.NotValidf("webhook URL %q", args.WebhookURL)
+	}
+	controllerConfig, err := api.backend.ControllerConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !set.NewStrings(controllerConfig.ChatWebhookURLs()...).Contains(args.WebhookURL) {
+		return errors.Errorf("webhook URL %q is not one of the controller's %s", args.WebhookURL, controller.ChatWebhookURLs)
+	}
+	summary, err := api.summary()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	body, err := json.Marshal(map[string]string{"text": summary.Text})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req, err := http.NewRequest("POST", webhook.String(), bytes.NewReader(body))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", params.ContentTypeJSON)
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return errors.Annotate(err, "cannot push summary")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("cannot push summary: webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (api *API) summary() (params.ChatSummary, error) {
+	model, err := api.backend.Model()
+	if err != nil {
+		return params.ChatSummary{}, errors.Trace(err)
+	}
+	result := params.ChatSummary{
+		Model:  model.Name(),
+		Health: healthHealthy,
+	}
+	var errs []params.ChatSummaryError
+	degrade := func() {
+		if result.Health == healthHealthy {
+			result.Health = healthDegraded
+		}
+	}
+
+	machines, err := api.backend.AllMachines()
+	if err != nil {
+		return params.ChatSummary{}, errors.Trace(err)
+	}
+	result.Machines = len(machines)
+	for _, machine := range machines {
+		info, err := machine.Status()
+		if err != nil {
+			return params.ChatSummary{}, errors.Annotatef(err, "machine %s", machine.Id())
+		}
+		switch info.Status {
+		case status.Error:
+			errs = append(errs, summaryError("machine "+machine.Id(), info))
+		case status.Down:
+			degrade()
+		}
+	}
+
+	applications, err := api.backend.AllApplications()
+	if err != nil {
+		return params.ChatSummary{}, errors.Trace(err)
+	}
+	result.Applications = len(applications)
+	sort.Slice(applications, func(i, j int) bool {
+		return applications[i].Name() < applications[j].Name()
+	})
+	for _, app := range applications {
+		units, err := app.AllUnits()
+		if err != nil {
+			return params.ChatSummary{}, errors.Annotatef(err, "application %s", app.Name())
+		}
+		result.Units += len(units)
+		for _, unit := range units {
+			agent, err := unit.AgentStatus()
+			if err != nil {
+				return params.ChatSummary{}, errors.Annotatef(err, "unit %s", unit.Name())
+			}
+			workload, err := unit.Status()
+			if err != nil {
+				return params.ChatSummary{}, errors.Annotatef(err, "unit %s", unit.Name())
+			}
+			switch {
+			case agent.Status == status.Error:
+				errs = append(errs, summaryError(unit.Name(), agent))
+			case workload.Status == status.Error:
+				errs = append(errs, summaryError(unit.Name(), workload))
+			case workload.Status == status.Blocked, agent.Status == status.Lost:
+				degrade()
+			}
+		}
+
+		upgrade, err := api.charmUpgrade(app)
+		if err != nil {
+			return params.ChatSummary{}, errors.Annotatef(err, "application %s", app.Name())
+		}
+		if upgrade != "" {
+			result.PendingUpgrades = append(result.PendingUpgrades, upgrade)
+		}
+	}
+
+	if len(errs) > 0 {
+		result.Health = healthError
+		sort.SliceStable(errs, func(i, j int) bool {
+			a, b := errs[i].Since, errs[j].Since
+			return a != nil && (b == nil || a.After(*b))
+		})
+		if len(errs) > MaxRecentErrors {
+			errs = errs[:MaxRecentErrors]
+		}
+		result.RecentErrors = errs
+	}
+
+	current, err := model.AgentVersion()
+	if err != nil && !errors.IsNotFound(err) {
+		return params.ChatSummary{}, errors.Trace(err)
+	}
+	if latest := model.LatestToolsVersion(); err == nil && current.Compare(latest) < 0 {
+		result.PendingUpgrades = append([]string{
+			fmt.Sprintf("model agents %s -> %s", current, latest),
+		}, result.PendingUpgrades...)
+	}
+
+	result.Text = summaryText(result)
+	return result, nil
+}
+
+// charmUpgrade returns a description of the upgrade available to the
+// charm store charm of the application, if any.
+func (api *API) charmUpgrade(app Application) (string, error) {
+	curl, _ := app.CharmURL()
+	if curl == nil || curl.Schema != "cs" {
+		return "", nil
+	}
+	latest, err := api.backend.LatestCharmRevision(curl.WithRevision(-1))
+	if errors.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	if latest <= curl.Revision {
+		return "", nil
+	}
+	return fmt.Sprintf("%s %s -> %s", app.Name(), curl, curl.WithRevision(latest)), nil
+}
+
+func summaryError(entity string, info status.StatusInfo) params.ChatSummaryError {
+	message := info.Message
+	if len(message) > maxMessageLength {
+		message = message[:maxMessageLength-3] + "..."
+	}
+	return params.ChatSummaryError{
+		Entity:  entity,
+		Message: message,
+		Since:   info.Since,
+	}
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// summaryText renders the summary as a chat message.
+func summaryText(summary params.ChatSummary) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s (%s): %s, %s, %s",
+		summary.Model,
+		summary.Health,
+		plural(summary.Machines, "machine"),
+		plural(summary.Applications, "application"),
+		plural(summary.Units, "unit"),
+	)
+	if len(summary.RecentErrors) > 0 {
+		buf.WriteString("\nrecent errors:")
+		for _, e := range summary.RecentErrors {
+			fmt.Fprintf(&buf, "\n- %s: %s", e.Entity, e.Message)
+		}
+	}
+	if len(summary.PendingUpgrades) > 0 {
+		buf.WriteString("\npending upgrades:")
+		for _, upgrade := range summary.PendingUpgrades {
+			fmt.Fprintf(&buf, "\n- %s", upgrade)
+		}
+	}
+	return buf.String()
+}
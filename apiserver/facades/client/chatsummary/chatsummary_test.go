@@ -0,0 +1,384 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package chatsummary_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/chatsummary"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ChatSummarySuite struct {
+	testing.IsolationSuite
+	backend    *mockBackend
+	authorizer apiservertesting.FakeAuthorizer
+	client     *mockHTTPClient
+}
+
+var _ = gc.Suite(&ChatSummarySuite{})
+
+func (s *ChatSummarySuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("admin"),
+	}
+	s.client = &mockHTTPClient{status: http.StatusOK}
+	earlier := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+	s.backend = &mockBackend{
+		model: &mockModel{
+			name:         "prod",
+			agentVersion: version.MustParse("2.4.0"),
+			latest:       version.MustParse("2.4.1"),
+		},
+		machines: []chatsummary.Machine{
+			&mockMachine{id: "0", status: status.StatusInfo{Status: status.Started}},
+			&mockMachine{id: "1", status: status.StatusInfo{
+				Status:  status.Error,
+				Message: "instance not found",
+				Since:   &earlier,
+			}},
+		},
+		applications: []chatsummary.Application{
+			&mockApplication{
+				name: "wordpress",
+				curl: charm.MustParseURL("cs:wordpress-3"),
+				units: []chatsummary.Unit{
+					&mockUnit{
+						name:     "wordpress/0",
+						agent:    status.StatusInfo{Status: status.Idle},
+						workload: status.StatusInfo{Status: status.Active},
+					},
+				},
+			},
+			&mockApplication{
+				name: "mysql",
+				curl: charm.MustParseURL("cs:mysql-58"),
+				units: []chatsummary.Unit{
+					&mockUnit{
+						name: "mysql/0",
+						agent: status.StatusInfo{
+							Status:  status.Error,
+							Message: `hook failed: "install"`,
+							Since:   &later,
+						},
+						workload: status.StatusInfo{Status: status.Maintenance},
+					},
+					&mockUnit{
+						name:     "mysql/1",
+						agent:    status.StatusInfo{Status: status.Idle},
+						workload: status.StatusInfo{Status: status.Blocked},
+					},
+				},
+			},
+		},
+		latestRevisions: map[string]int{
+			"cs:wordpress": 5,
+			"cs:mysql":     58,
+		},
+		controllerConfig: controller.Config{
+			controller.ChatWebhookURLs: "https://chat.example.com/hooks/abc",
+		},
+	}
+}
+
+func (s *ChatSummarySuite) newAPI(c *gc.C) *chatsummary.API {
+	api, err := chatsummary.NewAPI(s.backend, s.authorizer, s.client)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *ChatSummarySuite) TestNewAPIRequiresClient(c *gc.C) {
+	s.authorizer.Tag = names.NewUnitTag("mysql/0")
+	_, err := chatsummary.NewAPI(s.backend, s.authorizer, s.client)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *ChatSummarySuite) TestSummary(c *gc.C) {
+	summary, err := s.newAPI(c).Summary()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(summary.RecentErrors, gc.HasLen, 2)
+	c.Check(summary.RecentErrors[0].Since, gc.NotNil)
+	c.Check(summary.RecentErrors[1].Since, gc.NotNil)
+	summary.RecentErrors[0].Since = nil
+	summary.RecentErrors[1].Since = nil
+	c.Assert(summary, jc.DeepEquals, params.ChatSummary{
+		Model:        "prod",
+		Health:       "error",
+		Machines:     2,
+		Applications: 2,
+		Units:        3,
+		RecentErrors: []params.ChatSummaryError{
+			{Entity: "mysql/0", Message: `hook failed: "install"`},
+			{Entity: "machine 1", Message: "instance not found"},
+		},
+		PendingUpgrades: []string{
+			"model agents 2.4.0 -> 2.4.1",
+			"wordpress cs:wordpress-3 -> cs:wordpress-5",
+		},
+		Text: `prod (error): 2 machines, 2 applications, 3 units
+recent errors:
+- mysql/0: hook failed: "install"
+- machine 1: instance not found
+pending upgrades:
+- model agents 2.4.0 -> 2.4.1
+- wordpress cs:wordpress-3 -> cs:wordpress-5`,
+	})
+}
+
+func (s *ChatSummarySuite) TestSummaryDegraded(c *gc.C) {
+	s.backend.machines = s.backend.machines[:1]
+	s.backend.applications[1].(*mockApplication).units = s.backend.applications[1].(*mockApplication).units[1:]
+	s.backend.model.latest = s.backend.model.agentVersion
+	delete(s.backend.latestRevisions, "cs:wordpress")
+
+	summary, err := s.newAPI(c).Summary()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(summary.Health, gc.Equals, "degraded")
+	c.Check(summary.RecentErrors, gc.HasLen, 0)
+	c.Check(summary.PendingUpgrades, gc.HasLen, 0)
+	c.Check(summary.Text, gc.Equals, "prod (degraded): 1 machine, 2 applications, 2 units")
+}
+
+func (s *ChatSummarySuite) TestSummaryLimitsErrors(c *gc.C) {
+	var units []chatsummary.Unit
+	for i := 0; i < chatsummary.MaxRecentErrors+2; i++ {
+		units = append(units, &mockUnit{
+			name:     fmt.Sprintf("mysql/%d", i),
+			agent:    status.StatusInfo{Status: status.Idle},
+			workload: status.StatusInfo{Status: status.Error, Message: "broken"},
+		})
+	}
+	s.backend.applications[1].(*mockApplication).units = units
+
+	summary, err := s.newAPI(c).Summary()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(summary.RecentErrors, gc.HasLen, chatsummary.MaxRecentErrors)
+	c.Check(summary.Units, gc.Equals, chatsummary.MaxRecentErrors+3)
+}
+
+func (s *ChatSummarySuite) TestSummaryPermissionDenied(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("fred")
+	_, err := s.newAPI(c).Summary()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *ChatSummarySuite) TestSummaryError(c *gc.C) {
+	s.backend.SetErrors(nil, errors.New("boom"))
+	_, err := s.newAPI(c).Summary()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ChatSummarySuite) TestPushSummary(c *gc.C) {
+	err := s.newAPI(c).PushSummary(params.ChatSummaryPushArgs{
+		WebhookURL: "https://chat.example.com/hooks/abc",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.client.requests, gc.HasLen, 1)
+	req := s.client.requests[0]
+	c.Check(req.Method, gc.Equals, "POST")
+	c.Check(req.URL.String(), gc.Equals, "https://chat.example.com/hooks/abc")
+	c.Check(req.Header.Get("Content-Type"), gc.Equals, "application/json")
+	var body map[string]string
+	err = json.Unmarshal(s.client.bodies[0], &body)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(strings.HasPrefix(body["text"], "prod (error): 2 machines"), jc.IsTrue)
+}
+
+func (s *ChatSummarySuite) TestPushSummaryWebhookFailure(c *gc.C) {
+	s.client.status = http.StatusNotFound
+	err := s.newAPI(c).PushSummary(params.ChatSummaryPushArgs{
+		WebhookURL: "https://chat.example.com/hooks/abc",
+	})
+	c.Assert(err, gc.ErrorMatches, "cannot push summary: webhook returned 404 Not Found")
+}
+
+func (s *ChatSummarySuite) TestPushSummaryInvalidURL(c *gc.C) {
+	for _, url := range []string{"", "ftp://example.com", "/hooks/abc", "http://"} {
+		err := s.newAPI(c).PushSummary(params.ChatSummaryPushArgs{WebhookURL: url})
+		c.Check(err, gc.ErrorMatches, `webhook URL ".*" not valid`)
+	}
+	c.Assert(s.client.requests, gc.HasLen, 0)
+}
+
+func (s *ChatSummarySuite) TestPushSummaryNotConfigured(c *gc.C) {
+	for _, url := range []string{
+		"https://chat.example.com/hooks/other",
+		"http://127.0.0.1:17070/",
+		"http://169.254.169.254/latest/meta-data/",
+	} {
+		err := s.newAPI(c).PushSummary(params.ChatSummaryPushArgs{WebhookURL: url})
+		c.Check(err, gc.ErrorMatches, `webhook URL ".*" is not one of the controller's chat-webhook-urls`)
+	}
+	c.Assert(s.client.requests, gc.HasLen, 0)
+}
+
+func (s *ChatSummarySuite) TestPushSummaryRequiresAdmin(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("write")
+	err := s.newAPI(c).PushSummary(params.ChatSummaryPushArgs{
+		WebhookURL: "https://chat.example.com/hooks/abc",
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(s.client.requests, gc.HasLen, 0)
+}
+
+type mockBackend struct {
+	testing.Stub
+	model            *mockModel
+	machines         []chatsummary.Machine
+	applications     []chatsummary.Application
+	latestRevisions  map[string]int
+	controllerConfig controller.Config
+}
+
+func (m *mockBackend) ModelTag() names.ModelTag {
+	m.MethodCall(m, "ModelTag")
+	return coretesting.ModelTag
+}
+
+func (m *mockBackend) ControllerConfig() (controller.Config, error) {
+	m.MethodCall(m, "ControllerConfig")
+	return m.controllerConfig, nil
+}
+
+func (m *mockBackend) Model() (chatsummary.Model, error) {
+	m.MethodCall(m, "Model")
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return m.model, nil
+}
+
+func (m *mockBackend) AllMachines() ([]chatsummary.Machine, error) {
+	m.MethodCall(m, "AllMachines")
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return m.machines, nil
+}
+
+func (m *mockBackend) AllApplications() ([]chatsummary.Application, error) {
+	m.MethodCall(m, "AllApplications")
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return m.applications, nil
+}
+
+func (m *mockBackend) LatestCharmRevision(curl *charm.URL) (int, error) {
+	m.MethodCall(m, "LatestCharmRevision", curl)
+	if err := m.NextErr(); err != nil {
+		return 0, err
+	}
+	revision, ok := m.latestRevisions[curl.String()]
+	if !ok {
+		return 0, errors.NotFoundf("charm %q", curl)
+	}
+	return revision, nil
+}
+
+type mockModel struct {
+	name         string
+	agentVersion version.Number
+	latest       version.Number
+}
+
+func (m *mockModel) Name() string {
+	return m.name
+}
+
+func (m *mockModel) AgentVersion() (version.Number, error) {
+	return m.agentVersion, nil
+}
+
+func (m *mockModel) LatestToolsVersion() version.Number {
+	return m.latest
+}
+
+type mockMachine struct {
+	id     string
+	status status.StatusInfo
+}
+
+func (m *mockMachine) Id() string {
+	return m.id
+}
+
+func (m *mockMachine) Status() (status.StatusInfo, error) {
+	return m.status, nil
+}
+
+type mockApplication struct {
+	name  string
+	curl  *charm.URL
+	units []chatsummary.Unit
+}
+
+func (a *mockApplication) Name() string {
+	return a.name
+}
+
+func (a *mockApplication) CharmURL() (*charm.URL, bool) {
+	return a.curl, false
+}
+
+func (a *mockApplication) AllUnits() ([]chatsummary.Unit, error) {
+	return a.units, nil
+}
+
+type mockUnit struct {
+	name     string
+	agent    status.StatusInfo
+	workload status.StatusInfo
+}
+
+func (u *mockUnit) Name() string {
+	return u.name
+}
+
+func (u *mockUnit) Status() (status.StatusInfo, error) {
+	return u.workload, nil
+}
+
+func (u *mockUnit) AgentStatus() (status.StatusInfo, error) {
+	return u.agent, nil
+}
+
+type mockHTTPClient struct {
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (c *mockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.requests = append(c.requests, req)
+	c.bodies = append(c.bodies, body)
+	return &http.Response{
+		StatusCode: c.status,
+		Status:     fmt.Sprintf("%d %s", c.status, http.StatusText(c.status)),
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+	}, nil
+}
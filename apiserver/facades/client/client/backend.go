@@ -28,6 +28,7 @@ type Backend interface {
 	AddMachineInsideNewMachine(template, parentTemplate state.MachineTemplate, containerType instance.ContainerType) (*state.Machine, error)
 	AddOneMachine(state.MachineTemplate) (*state.Machine, error)
 	AddRelation(...state.Endpoint) (*state.Relation, error)
+	AgentVersionPins() (map[string]state.AgentVersionPin, error)
 	AllApplications() ([]*state.Application, error)
 	AllApplicationOffers() ([]*crossmodel.ApplicationOffer, error)
 	AllRemoteApplications() ([]*state.RemoteApplication, error)
@@ -47,6 +48,7 @@ type Backend interface {
 	FindEntity(names.Tag) (state.Entity, error)
 	InferEndpoints(...string) ([]state.Endpoint, error)
 	IsController() bool
+	KeyRelation(string) (*state.Relation, error)
 	LatestMigration() (state.ModelMigration, error)
 	LatestPlaceholderCharm(*charm.URL) (*state.Charm, error)
 	Machine(string) (*state.Machine, error)
@@ -80,6 +82,7 @@ type Pool interface {
 // Unit represents a state.Unit.
 type Unit interface {
 	status.StatusHistoryGetter
+	status.StatusHistoryPager
 	Life() state.Life
 	Destroy() (err error)
 	IsPrincipal() bool
@@ -87,6 +90,7 @@ type Unit interface {
 	PrivateAddress() (network.Address, error)
 	Resolve(retryHooks bool) error
 	AgentHistory() status.StatusHistoryGetter
+	WatchStatusHistory(status.HistoryKind) (state.StatusHistoryWatcher, error)
 }
 
 // TODO - CAAS(ericclaudejones): This should contain state alone, model will be
@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
 	"github.com/juju/utils/os"
 	"github.com/juju/utils/series"
 	"gopkg.in/juju/names.v2"
@@ -60,7 +61,7 @@ type caCerter interface {
 type Client struct {
 	// TODO(wallyworld) - we'll retain model config facade methods
 	// on the client facade until GUI and Python client library are updated.
-	*modelconfig.ModelConfigAPI
+	*modelconfig.ModelConfigAPIV1
 	caCerter
 
 	api        *API
@@ -144,7 +145,7 @@ func NewFacade(ctx facade.Context) (*Client, error) {
 	return NewClient(
 		&stateShim{st, model},
 		&poolShim{ctx.StatePool()},
-		modelConfigAPI,
+		&modelconfig.ModelConfigAPIV1{&modelconfig.ModelConfigAPIV3{modelConfigAPI}},
 		resources,
 		authorizer,
 		statusSetter,
@@ -159,7 +160,7 @@ func NewFacade(ctx facade.Context) (*Client, error) {
 func NewClient(
 	backend Backend,
 	pool Pool,
-	modelConfigAPI *modelconfig.ModelConfigAPI,
+	modelConfigAPI *modelconfig.ModelConfigAPIV1,
 	resources facade.Resources,
 	authorizer facade.Authorizer,
 	statusSetter *common.StatusSetter,
@@ -172,8 +173,8 @@ func NewClient(
 		return nil, common.ErrPerm
 	}
 	client := &Client{
-		ModelConfigAPI: modelConfigAPI,
-		caCerter:       caCerter,
+		ModelConfigAPIV1: modelConfigAPI,
+		caCerter:         caCerter,
 		api: &API{
 			stateAccessor: backend,
 			pool:          pool,
@@ -213,6 +214,23 @@ func (c *Client) WatchAll() (params.AllWatcherId, error) {
 	}, nil
 }
 
+// WatchStatus initiates a watcher that reports changes to the full
+// status of the connected model, filtered by the supplied patterns,
+// as diffs. The watcher is used through the StatusWatcher facade.
+func (c *Client) WatchStatus(args params.StatusParams) (params.StatusWatcherId, error) {
+	if err := c.checkCanRead(); err != nil {
+		return params.StatusWatcherId{}, err
+	}
+	watchParams := state.WatchParams{IncludeOffers: c.checkIsAdmin() == nil}
+	deltas := c.api.stateAccessor.Watch(watchParams)
+	w := newStatusWatcher(deltas, func() (params.FullStatus, error) {
+		return c.FullStatus(args)
+	}, clock.WallClock, statusWatchInterval)
+	return params.StatusWatcherId{
+		StatusWatcherId: c.api.resources.Register(w),
+	}, nil
+}
+
 // Resolved implements the server side of Client.Resolved.
 func (c *Client) Resolved(p params.Resolved) error {
 	if err := c.checkCanWrite(); err != nil {
@@ -7,6 +7,9 @@ import (
 	"github.com/juju/juju/environs"
 )
 
+// NewStatusWatcher is exported for testing.
+var NewStatusWatcher = newStatusWatcher
+
 // Filtering exports
 var (
 	MatchPortRanges = matchPortRanges
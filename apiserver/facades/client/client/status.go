@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/state/watcher"
 	"github.com/juju/juju/status"
 )
 
@@ -100,6 +101,130 @@ func (c *Client) machineStatusHistory(machineTag names.MachineTag, filter status
 	return agentStatusFromStatusInfo(sInfo, kind), nil
 }
 
+// applicationStatusHistory returns status history for the given application.
+func (c *Client) applicationStatusHistory(applicationTag names.ApplicationTag, filter status.StatusHistoryFilter) ([]params.DetailedStatus, error) {
+	application, err := c.api.stateAccessor.Application(applicationTag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sInfo, err := application.StatusHistory(filter)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return agentStatusFromStatusInfo(sInfo, status.KindApplication), nil
+}
+
+// relationStatusHistory returns status history for the given relation.
+func (c *Client) relationStatusHistory(relationTag names.RelationTag, filter status.StatusHistoryFilter) ([]params.DetailedStatus, error) {
+	relation, err := c.api.stateAccessor.KeyRelation(relationTag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sInfo, err := relation.StatusHistory(filter)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return agentStatusFromStatusInfo(sInfo, status.KindRelation), nil
+}
+
+// statusHistoryPage returns the page of status history identified by
+// the request's token, rather than the entire history.
+func (c *Client) statusHistoryPage(request params.StatusHistoryRequest, filter status.StatusHistoryFilter, kind status.HistoryKind) params.StatusHistoryResult {
+	var (
+		pager status.StatusHistoryPager
+		page  status.StatusHistoryPage
+	)
+	err := errors.NotValidf("%q requires a unit, got %T", kind, request.Tag)
+	switch kind {
+	case status.KindUnit, status.KindWorkload, status.KindUnitAgent:
+		var u names.UnitTag
+		if u, err = names.ParseUnitTag(request.Tag); err == nil {
+			var unit Unit
+			if unit, err = c.api.stateAccessor.Unit(u.Id()); err == nil {
+				pager = unit
+			}
+		}
+	case status.KindApplication:
+		var a names.ApplicationTag
+		if a, err = names.ParseApplicationTag(request.Tag); err == nil {
+			var application *state.Application
+			if application, err = c.api.stateAccessor.Application(a.Id()); err == nil {
+				pager = application
+			}
+		}
+	case status.KindRelation:
+		var r names.RelationTag
+		if r, err = names.ParseRelationTag(request.Tag); err == nil {
+			var relation *state.Relation
+			if relation, err = c.api.stateAccessor.KeyRelation(r.Id()); err == nil {
+				pager = relation
+			}
+		}
+	default:
+		var m names.MachineTag
+		if m, err = names.ParseMachineTag(request.Tag); err == nil {
+			var machine *state.Machine
+			if machine, err = c.api.stateAccessor.Machine(m.Id()); err == nil {
+				pager = machine
+			}
+		}
+	}
+	if err == nil {
+		page, err = pager.StatusHistoryPage(kind, filter, request.PageSize, request.Token)
+	}
+	if err != nil {
+		return params.StatusHistoryResult{
+			Error: common.ServerError(errors.Annotatef(err, "fetching status history for %q", request.Tag)),
+		}
+	}
+	hist := make([]params.DetailedStatus, len(page.Statuses))
+	for i, s := range page.Statuses {
+		hist[i] = params.DetailedStatus{
+			Status: string(s.Status),
+			Info:   s.Info,
+			Data:   s.Data,
+			Since:  s.Since,
+			Kind:   string(s.Kind),
+		}
+	}
+	sort.Sort(byTime(hist))
+	return params.StatusHistoryResult{
+		History:   params.History{Statuses: squashHistory(hist, filter.Squash)},
+		NextToken: page.NextToken,
+	}
+}
+
+// squashHistory squashes the repeated entries in hist, which is sorted
+// oldest first, if squash is not nil. A page of status history is
+// squashed by itself, so repetitions across pages are not squashed.
+func squashHistory(hist []params.DetailedStatus, squash *status.SquashParams) []params.DetailedStatus {
+	if squash == nil {
+		return hist
+	}
+	history := make(status.History, len(hist))
+	for i, s := range hist {
+		history[i] = status.DetailedStatus{
+			Status: status.Status(s.Status),
+			Info:   s.Info,
+			Data:   s.Data,
+			Since:  s.Since,
+			Kind:   status.HistoryKind(s.Kind),
+		}
+	}
+	history = history.Squash(*squash)
+	result := make([]params.DetailedStatus, len(history))
+	for i, s := range history {
+		result[i] = params.DetailedStatus{
+			Status: string(s.Status),
+			Info:   s.Info,
+			Data:   s.Data,
+			Since:  s.Since,
+			Kind:   string(s.Kind),
+		}
+	}
+	return result
+}
+
 // StatusHistory returns a slice of past statuses for several entities.
 func (c *Client) StatusHistory(request params.StatusHistoryRequests) params.StatusHistoryResults {
 
@@ -112,6 +237,16 @@ func (c *Client) StatusHistory(request params.StatusHistoryRequests) params.Stat
 			FromDate: request.Filter.Date,
 			Delta:    request.Filter.Delta,
 			Exclude:  set.NewStrings(request.Filter.Exclude...),
+
+			IncludeStatus: set.NewStrings(request.Filter.IncludeStatus...),
+			ExcludeStatus: set.NewStrings(request.Filter.ExcludeStatus...),
+			MessageRegex:  request.Filter.MessageRegex,
+		}
+		if squash := request.Filter.Squash; squash != nil && squash.Enabled {
+			filter.Squash = &status.SquashParams{
+				CycleSizes: squash.CycleSizes,
+				MaxLength:  squash.MaxLength,
+			}
 		}
 		if err := c.checkCanRead(); err != nil {
 			history := params.StatusHistoryResult{
@@ -130,11 +265,16 @@ func (c *Client) StatusHistory(request params.StatusHistoryRequests) params.Stat
 			continue
 		}
 
+		kind := status.HistoryKind(request.Kind)
+		if request.PageSize > 0 {
+			results.Results = append(results.Results, c.statusHistoryPage(request, filter, kind))
+			continue
+		}
+
 		var (
 			err  error
 			hist []params.DetailedStatus
 		)
-		kind := status.HistoryKind(request.Kind)
 		err = errors.NotValidf("%q requires a unit, got %T", kind, request.Tag)
 		switch kind {
 		case status.KindUnit, status.KindWorkload, status.KindUnitAgent:
@@ -142,6 +282,16 @@ func (c *Client) StatusHistory(request params.StatusHistoryRequests) params.Stat
 			if u, err = names.ParseUnitTag(request.Tag); err == nil {
 				hist, err = c.unitStatusHistory(u, filter, kind)
 			}
+		case status.KindApplication:
+			var a names.ApplicationTag
+			if a, err = names.ParseApplicationTag(request.Tag); err == nil {
+				hist, err = c.applicationStatusHistory(a, filter)
+			}
+		case status.KindRelation:
+			var r names.RelationTag
+			if r, err = names.ParseRelationTag(request.Tag); err == nil {
+				hist, err = c.relationStatusHistory(r, filter)
+			}
 		default:
 			var m names.MachineTag
 			if m, err = names.ParseMachineTag(request.Tag); err == nil {
@@ -151,6 +301,7 @@ func (c *Client) StatusHistory(request params.StatusHistoryRequests) params.Stat
 
 		if err == nil {
 			sort.Sort(byTime(hist))
+			hist = squashHistory(hist, filter.Squash)
 		}
 
 		results.Results = append(results.Results,
@@ -162,6 +313,82 @@ func (c *Client) StatusHistory(request params.StatusHistoryRequests) params.Stat
 	return results
 }
 
+// watchStatusHistory returns a watcher reporting the entries added to
+// the status history selected by the request's kind and tag.
+func (c *Client) watchStatusHistory(request params.StatusHistoryRequest) (state.StatusHistoryWatcher, error) {
+	kind := status.HistoryKind(request.Kind)
+	switch kind {
+	case status.KindUnit, status.KindWorkload, status.KindUnitAgent:
+		u, err := names.ParseUnitTag(request.Tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		unit, err := c.api.stateAccessor.Unit(u.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return unit.WatchStatusHistory(kind)
+	case status.KindApplication:
+		a, err := names.ParseApplicationTag(request.Tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		application, err := c.api.stateAccessor.Application(a.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return application.WatchStatusHistory(kind)
+	case status.KindRelation:
+		r, err := names.ParseRelationTag(request.Tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		relation, err := c.api.stateAccessor.KeyRelation(r.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return relation.WatchStatusHistory(kind)
+	default:
+		m, err := names.ParseMachineTag(request.Tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		machine, err := c.api.stateAccessor.Machine(m.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return machine.WatchStatusHistory(kind)
+	}
+}
+
+// WatchStatusHistory initiates watchers that report the entries added
+// to the status histories selected by the requests' kinds and tags, as
+// they are recorded. The requests' filters, sizes and paging are
+// ignored. The watchers are used through the StatusHistoryWatcher
+// facade; the entries already recorded can be read with StatusHistory.
+func (c *Client) WatchStatusHistory(args params.StatusHistoryRequests) params.StatusHistoryWatchResults {
+	results := params.StatusHistoryWatchResults{
+		Results: make([]params.StatusHistoryWatchResult, len(args.Requests)),
+	}
+	for i, request := range args.Requests {
+		if err := c.checkCanRead(); err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		w, err := c.watchStatusHistory(request)
+		if err == nil {
+			// Consume the initial event, which holds no entries.
+			if _, ok := <-w.Changes(); ok {
+				results.Results[i].StatusHistoryWatcherId = c.api.resources.Register(w)
+				continue
+			}
+			err = watcher.EnsureErr(w)
+		}
+		results.Results[i].Error = common.ServerError(errors.Annotatef(err, "watching status history for %q", request.Tag))
+	}
+	return results
+}
+
 // FullStatus gives the information needed for juju status over the api
 func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error) {
 	if err := c.checkCanRead(); err != nil {
@@ -195,6 +422,9 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 	if context.machines, err = fetchMachines(c.api.stateAccessor, nil); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch machines")
 	}
+	if context.agentVersionPins, err = c.api.stateAccessor.AgentVersionPins(); err != nil {
+		return noStatus, errors.Annotate(err, "could not fetch agent version pins")
+	}
 	// These may be empty when machines have not finished deployment.
 	if context.ipAddresses, context.spaces, context.linkLayerDevices, err =
 		fetchNetworkInterfaces(c.api.stateAccessor); err != nil {
@@ -401,6 +631,9 @@ type statusContext struct {
 	units         map[string]map[string]*state.Unit
 	latestCharms  map[charm.URL]*state.Charm
 	leaders       map[string]string
+
+	// agentVersionPins: entity tag -> agent version pin
+	agentVersionPins map[string]state.AgentVersionPin
 }
 
 // fetchMachines returns a map from top level machine id to machines, where machines[0] is the host
@@ -706,6 +939,9 @@ func (c *statusContext) makeMachineStatus(machine *state.Machine) (status params
 	status.Jobs = paramsJobsFromJobs(machine.Jobs())
 	status.WantsVote = machine.WantsVote()
 	status.HasVote = machine.HasVote()
+	if pin, ok := c.agentVersionPins[machine.Tag().String()]; ok {
+		status.AgentVersionPin = pin.String()
+	}
 	sInfo, err := c.status.MachineInstance(machineID)
 	populateStatusFromStatusInfoAndErr(&status.InstanceStatus, sInfo, err)
 	// TODO: fetch all instance data for machines in one go.
@@ -832,12 +1068,32 @@ func (context *statusContext) processRelations() []params.RelationStatus {
 			Endpoints: eps,
 		}
 		rStatus, err := relation.Status()
+		if errors.IsNotFound(err) {
+			// Relations added before relation status was recorded
+			// have none, so report the status their state implies.
+			rStatus, err = impliedRelationStatus(relation), nil
+		}
 		populateStatusFromStatusInfoAndErr(&relStatus.Status, rStatus, err)
 		out = append(out, relStatus)
 	}
 	return out
 }
 
+// impliedRelationStatus returns the status implied by the relation's life
+// and whether it is suspended, for relations with no recorded status.
+func impliedRelationStatus(relation *state.Relation) status.StatusInfo {
+	switch {
+	case relation.Life() == state.Dead:
+		return status.StatusInfo{Status: status.Broken}
+	case relation.Suspended():
+		return status.StatusInfo{
+			Status:  status.Suspended,
+			Message: relation.SuspendedReason(),
+		}
+	}
+	return status.StatusInfo{Status: status.Joined}
+}
+
 // This method exists only to dedup the loaded relations as they will
 // appear multiple times in context.relations.
 func (context *statusContext) getAllRelations() []*state.Relation {
@@ -895,6 +1151,9 @@ func (context *statusContext) processApplication(application *state.Application)
 		Exposed: application.IsExposed(),
 		Life:    processLife(application),
 	}
+	if pin, ok := context.agentVersionPins[application.Tag().String()]; ok {
+		processedStatus.AgentVersionPin = pin.String()
+	}
 
 	if latestCharm, ok := context.latestCharms[*applicationCharm.URL().WithRevision(-1)]; ok && latestCharm != nil {
 		if latestCharm.Revision() > applicationCharm.URL().Revision {
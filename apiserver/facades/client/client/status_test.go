@@ -8,6 +8,7 @@ import (
 
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
+	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
@@ -21,6 +22,7 @@ import (
 	"github.com/juju/juju/instance"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
 	"github.com/juju/juju/testing/factory"
 )
 
@@ -73,6 +75,76 @@ func (s *statusSuite) TestFullStatusUnitLeadership(c *gc.C) {
 	c.Assert(unit.Leader, jc.IsTrue)
 }
 
+func (s *statusSuite) TestFullStatusAgentVersionPins(c *gc.C) {
+	unit := s.Factory.MakeUnit(c, nil)
+	app, err := unit.Application()
+	c.Assert(err, jc.ErrorIsNil)
+	err = app.SetAgentVersionPin(state.AgentVersionPin{Max: version.MustParse("2.3.4")})
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.Machine(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetAgentVersionPin(state.AgentVersionPin{Min: version.MustParse("2.3.1")})
+	c.Assert(err, jc.ErrorIsNil)
+
+	status, err := s.APIState.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(status.Applications[app.Name()].AgentVersionPin, gc.Equals, "..2.3.4")
+	c.Check(status.Machines[machineId].AgentVersionPin, gc.Equals, "2.3.1..")
+}
+
+func (s *statusSuite) TestStatusHistoryApplication(c *gc.C) {
+	app := s.Factory.MakeApplication(c, nil)
+	now := time.Now()
+	err := app.SetStatus(status.StatusInfo{Status: status.Blocked, Message: "waiting for db", Since: &now})
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := s.APIState.Client()
+	filter := status.StatusHistoryFilter{Size: 10}
+	for _, page := range []bool{false, true} {
+		var history status.History
+		if page {
+			history, _, err = client.StatusHistoryPage(status.KindApplication, app.ApplicationTag(), filter, 10, "")
+		} else {
+			history, err = client.StatusHistory(status.KindApplication, app.ApplicationTag(), filter)
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(history, gc.HasLen, 1)
+		c.Check(history[0].Kind, gc.Equals, status.KindApplication)
+		c.Check(history[0].Status, gc.Equals, status.Blocked)
+		c.Check(history[0].Info, gc.Equals, "waiting for db")
+	}
+}
+
+func (s *statusSuite) TestStatusHistoryRelation(c *gc.C) {
+	rel := s.Factory.MakeRelation(c, nil)
+	now := time.Now()
+	err := rel.SetStatus(status.StatusInfo{Status: status.Joined, Since: &now})
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := s.APIState.Client()
+	filter := status.StatusHistoryFilter{Size: 10}
+	for _, page := range []bool{false, true} {
+		var history status.History
+		if page {
+			history, _, err = client.StatusHistoryPage(status.KindRelation, rel.Tag(), filter, 10, "")
+		} else {
+			history, err = client.StatusHistory(status.KindRelation, rel.Tag(), filter)
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(history, gc.HasLen, 1)
+		c.Check(history[0].Kind, gc.Equals, status.KindRelation)
+		c.Check(history[0].Status, gc.Equals, status.Joined)
+	}
+}
+
+func (s *statusSuite) TestStatusHistoryApplicationWrongTag(c *gc.C) {
+	unit := s.Factory.MakeUnit(c, nil)
+	_, err := s.APIState.Client().StatusHistory(status.KindApplication, unit.UnitTag(), status.StatusHistoryFilter{Size: 10})
+	c.Assert(err, gc.ErrorMatches, `.*fetching status history for "unit-.*": "unit-.*" is not a valid application tag`)
+}
+
 var _ = gc.Suite(&statusUnitTestSuite{})
 
 type statusUnitTestSuite struct {
@@ -4,6 +4,7 @@
 package client_test
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/juju/errors"
@@ -11,9 +12,11 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facades/client/client"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/status"
 	"github.com/juju/juju/testing"
 )
@@ -22,12 +25,15 @@ var _ = gc.Suite(&statusHistoryTestSuite{})
 
 type statusHistoryTestSuite struct {
 	testing.BaseSuite
-	st  *mockState
-	api *client.Client
+	st        *mockState
+	resources *common.Resources
+	api       *client.Client
 }
 
 func (s *statusHistoryTestSuite) SetUpTest(c *gc.C) {
 	s.st = &mockState{}
+	s.resources = common.NewResources()
+	s.AddCleanup(func(*gc.C) { s.resources.StopAll() })
 	tag := names.NewUserTag("admin")
 	authorizer := &apiservertesting.FakeAuthorizer{Tag: tag}
 	var err error
@@ -35,7 +41,7 @@ func (s *statusHistoryTestSuite) SetUpTest(c *gc.C) {
 		s.st,
 		nil, // pool
 		nil, // modelconfig API
-		nil, // resources
+		s.resources,
 		authorizer,
 		nil, // statusSetter
 		nil, // toolsFinder
@@ -95,33 +101,51 @@ func (s *statusHistoryTestSuite) TestSizeRequired(c *gc.C) {
 
 func (s *statusHistoryTestSuite) TestNoConflictingFilters(c *gc.C) {
 	now := time.Now()
+	yesterday := time.Hour * 24
 	r := s.api.StatusHistory(params.StatusHistoryRequests{
 		Requests: []params.StatusHistoryRequest{{
 			Tag:    "unit-unit-1",
 			Kind:   status.KindUnit.String(),
-			Filter: params.StatusHistoryFilter{Size: 1, Date: &now},
+			Filter: params.StatusHistoryFilter{Date: &now, Delta: &yesterday},
 		}}})
 	c.Assert(r.Results, gc.HasLen, 1)
-	c.Assert(r.Results[0].Error.Message, gc.Equals, "cannot validate status history filter: Size and Date together not valid")
+	c.Assert(r.Results[0].Error.Message, gc.Equals, "cannot validate status history filter: Date and Delta together not valid")
 
-	yesterday := time.Hour * 24
 	r = s.api.StatusHistory(params.StatusHistoryRequests{
 		Requests: []params.StatusHistoryRequest{{
-			Tag:    "unit-unit-1",
-			Kind:   status.KindUnit.String(),
-			Filter: params.StatusHistoryFilter{Size: 1, Delta: &yesterday},
+			Tag:  "unit-unit-1",
+			Kind: status.KindUnit.String(),
+			Filter: params.StatusHistoryFilter{
+				Size:          1,
+				IncludeStatus: []string{"error"},
+				ExcludeStatus: []string{"idle"},
+			},
 		}}})
 	c.Assert(r.Results, gc.HasLen, 1)
-	c.Assert(r.Results[0].Error.Message, gc.Equals, "cannot validate status history filter: Size and Delta together not valid")
+	c.Assert(r.Results[0].Error.Message, gc.Equals, "cannot validate status history filter: IncludeStatus and ExcludeStatus together not valid")
+}
 
-	r = s.api.StatusHistory(params.StatusHistoryRequests{
-		Requests: []params.StatusHistoryRequest{{
-			Tag:    "unit-unit-1",
-			Kind:   status.KindUnit.String(),
-			Filter: params.StatusHistoryFilter{Date: &now, Delta: &yesterday},
-		}}})
-	c.Assert(r.Results, gc.HasLen, 1)
-	c.Assert(r.Results[0].Error.Message, gc.Equals, "cannot validate status history filter: Date and Delta together not valid")
+func (s *statusHistoryTestSuite) TestSizeWithTimeBound(c *gc.C) {
+	s.st.unitHistory = statusInfoWithDates([]status.StatusInfo{
+		{Status: status.Active, Message: "one"},
+		{Status: status.Active, Message: "two"},
+	})
+	now := time.Now()
+	yesterday := time.Hour * 24
+	for _, filter := range []params.StatusHistoryFilter{
+		{Size: 1, Date: &now},
+		{Size: 1, Delta: &yesterday},
+	} {
+		r := s.api.StatusHistory(params.StatusHistoryRequests{
+			Requests: []params.StatusHistoryRequest{{
+				Tag:    "unit-unit-1",
+				Kind:   status.KindWorkload.String(),
+				Filter: filter,
+			}}})
+		c.Assert(r.Results, gc.HasLen, 1)
+		c.Assert(r.Results[0].Error, gc.IsNil)
+		c.Assert(r.Results[0].History.Statuses, gc.HasLen, 1)
+	}
 }
 
 func (s *statusHistoryTestSuite) TestStatusHistoryUnitOnly(c *gc.C) {
@@ -220,10 +244,177 @@ func (s *statusHistoryTestSuite) TestStatusHistoryCombined(c *gc.C) {
 	checkStatusInfo(c, h.Results[0].History.Statuses, expected)
 }
 
+func (s *statusHistoryTestSuite) TestStatusHistorySquash(c *gc.C) {
+	s.st.unitHistory = statusInfoWithDates([]status.StatusInfo{
+		{Status: status.Idle},
+		{Status: status.Idle},
+		{Status: status.Idle},
+		{Status: status.Active, Message: "running"},
+	})
+	h := s.api.StatusHistory(params.StatusHistoryRequests{
+		Requests: []params.StatusHistoryRequest{{
+			Tag:  "unit-unit-0",
+			Kind: status.KindWorkload.String(),
+			Filter: params.StatusHistoryFilter{
+				Size:   10,
+				Squash: &params.StatusHistorySquash{Enabled: true},
+			},
+		}}})
+	c.Assert(h.Results, gc.HasLen, 1)
+	c.Assert(h.Results[0].Error, gc.IsNil)
+	statuses := h.Results[0].History.Statuses
+	c.Assert(statuses, gc.HasLen, 3)
+	c.Assert(statuses[0].Info, gc.Equals, "running")
+	c.Assert(statuses[1].Status, gc.Equals, status.Idle.String())
+	c.Assert(statuses[2].Info, gc.Equals, "last 1 statuses repeated 2 times")
+	c.Assert(statuses[2].Kind, gc.Equals, status.KindWorkload.String())
+
+	h = s.api.StatusHistory(params.StatusHistoryRequests{
+		Requests: []params.StatusHistoryRequest{{
+			Tag:  "unit-unit-0",
+			Kind: status.KindWorkload.String(),
+			Filter: params.StatusHistoryFilter{
+				Size:   10,
+				Squash: &params.StatusHistorySquash{Enabled: true, MaxLength: 1},
+			},
+		}}})
+	c.Assert(h.Results, gc.HasLen, 1)
+	c.Assert(h.Results[0].Error, gc.IsNil)
+	c.Assert(h.Results[0].History.Statuses, gc.HasLen, 1)
+	c.Assert(h.Results[0].History.Statuses[0].Info, gc.Equals, "last 1 statuses repeated 2 times")
+}
+
+func (s *statusHistoryTestSuite) TestStatusHistorySquashDisabled(c *gc.C) {
+	s.st.unitHistory = statusInfoWithDates([]status.StatusInfo{
+		{Status: status.Idle},
+		{Status: status.Idle},
+	})
+	h := s.api.StatusHistory(params.StatusHistoryRequests{
+		Requests: []params.StatusHistoryRequest{{
+			Tag:  "unit-unit-0",
+			Kind: status.KindWorkload.String(),
+			Filter: params.StatusHistoryFilter{
+				Size:   10,
+				Squash: &params.StatusHistorySquash{CycleSizes: []int{1}},
+			},
+		}}})
+	c.Assert(h.Results, gc.HasLen, 1)
+	c.Assert(h.Results[0].Error, gc.IsNil)
+	c.Assert(h.Results[0].History.Statuses, gc.HasLen, 2)
+}
+
+func (s *statusHistoryTestSuite) TestStatusHistorySquashInvalid(c *gc.C) {
+	r := s.api.StatusHistory(params.StatusHistoryRequests{
+		Requests: []params.StatusHistoryRequest{{
+			Tag:  "unit-unit-0",
+			Kind: status.KindWorkload.String(),
+			Filter: params.StatusHistoryFilter{
+				Size: 10,
+				Squash: &params.StatusHistorySquash{
+					Enabled:    true,
+					CycleSizes: []int{0},
+				},
+			},
+		}}})
+	c.Assert(r.Results, gc.HasLen, 1)
+	c.Assert(r.Results[0].Error.Message, gc.Equals, "cannot validate status history filter: squash cycle size 0 (must be between 1 and 10) not valid")
+}
+
+func (s *statusHistoryTestSuite) TestStatusHistoryPage(c *gc.C) {
+	s.st.unitHistory = statusInfoWithDates([]status.StatusInfo{
+		{
+			Status:  status.Maintenance,
+			Message: "working",
+		},
+		{
+			Status:  status.Active,
+			Message: "running",
+		},
+	})
+	h := s.api.StatusHistory(params.StatusHistoryRequests{
+		Requests: []params.StatusHistoryRequest{{
+			Tag:      "unit-unit-0",
+			Kind:     status.KindWorkload.String(),
+			Filter:   params.StatusHistoryFilter{Size: 10},
+			PageSize: 1,
+		}}})
+	c.Assert(h.Results, gc.HasLen, 1)
+	c.Assert(h.Results[0].Error, gc.IsNil)
+	checkStatusInfo(c, h.Results[0].History.Statuses, s.st.unitHistory[:1])
+	c.Assert(h.Results[0].History.Statuses[0].Kind, gc.Equals, status.KindWorkload.String())
+	c.Assert(h.Results[0].NextToken, gc.Equals, "1")
+
+	h = s.api.StatusHistory(params.StatusHistoryRequests{
+		Requests: []params.StatusHistoryRequest{{
+			Tag:      "unit-unit-0",
+			Kind:     status.KindWorkload.String(),
+			Filter:   params.StatusHistoryFilter{Size: 10},
+			PageSize: 1,
+			Token:    "1",
+		}}})
+	c.Assert(h.Results, gc.HasLen, 1)
+	c.Assert(h.Results[0].Error, gc.IsNil)
+	checkStatusInfo(c, h.Results[0].History.Statuses, s.st.unitHistory[1:])
+	c.Assert(h.Results[0].NextToken, gc.Equals, "")
+}
+
+func (s *statusHistoryTestSuite) TestStatusHistoryPageError(c *gc.C) {
+	h := s.api.StatusHistory(params.StatusHistoryRequests{
+		Requests: []params.StatusHistoryRequest{{
+			Tag:      "unit-unit-1",
+			Kind:     status.KindWorkload.String(),
+			Filter:   params.StatusHistoryFilter{Size: 10},
+			PageSize: 1,
+		}}})
+	c.Assert(h.Results, gc.HasLen, 1)
+	c.Assert(h.Results[0].Error, gc.ErrorMatches, `fetching status history for "unit-unit-1": unit/1 not found`)
+}
+
+func (s *statusHistoryTestSuite) TestWatchStatusHistory(c *gc.C) {
+	r := s.api.WatchStatusHistory(params.StatusHistoryRequests{
+		Requests: []params.StatusHistoryRequest{{
+			Tag:  "unit-unit-0",
+			Kind: status.KindWorkload.String(),
+		}, {
+			Tag:  "unit-unit-1",
+			Kind: status.KindWorkload.String(),
+		}}})
+	c.Assert(r.Results, gc.HasLen, 2)
+	c.Assert(r.Results[0].Error, gc.IsNil)
+	c.Assert(r.Results[0].StatusHistoryWatcherId, gc.Equals, "1")
+	c.Assert(r.Results[1].Error, gc.ErrorMatches, `watching status history for "unit-unit-1": unit/1 not found`)
+
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	w := s.resources.Get("1").(*mockStatusHistoryWatcher)
+	c.Assert(w.kind, gc.Equals, status.KindWorkload)
+	// The initial event was consumed by the call.
+	select {
+	case <-w.changes:
+		c.Fatalf("initial event not consumed")
+	default:
+	}
+}
+
+func (s *statusHistoryTestSuite) TestWatchStatusHistoryStopped(c *gc.C) {
+	s.st.historyWatcherErr = errors.New("boom")
+	r := s.api.WatchStatusHistory(params.StatusHistoryRequests{
+		Requests: []params.StatusHistoryRequest{{
+			Tag:  "unit-unit-0",
+			Kind: status.KindWorkload.String(),
+		}}})
+	c.Assert(r.Results, gc.HasLen, 1)
+	c.Assert(r.Results[0].Error, gc.ErrorMatches, `watching status history for "unit-unit-0": boom`)
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+}
+
 type mockState struct {
 	client.Backend
 	unitHistory  []status.StatusInfo
 	agentHistory []status.StatusInfo
+
+	// historyWatcherErr, if set, stops the unit's status history
+	// watchers before their initial event.
+	historyWatcherErr error
 }
 
 func (m *mockState) ModelUUID() string {
@@ -243,21 +434,85 @@ func (m *mockState) Unit(name string) (client.Unit, error) {
 		return nil, errors.NotFoundf("%v", name)
 	}
 	return &mockUnit{
-		status: m.unitHistory,
-		agent:  &mockUnitAgent{m.agentHistory},
+		status:            m.unitHistory,
+		agent:             &mockUnitAgent{m.agentHistory},
+		historyWatcherErr: m.historyWatcherErr,
 	}, nil
 }
 
 type mockUnit struct {
-	status statuses
-	agent  *mockUnitAgent
+	status            statuses
+	agent             *mockUnitAgent
+	historyWatcherErr error
 	client.Unit
 }
 
+func (m *mockUnit) WatchStatusHistory(kind status.HistoryKind) (state.StatusHistoryWatcher, error) {
+	w := &mockStatusHistoryWatcher{
+		kind:    kind,
+		changes: make(chan []status.DetailedStatus, 1),
+		err:     m.historyWatcherErr,
+	}
+	if w.err != nil {
+		close(w.changes)
+	} else {
+		w.changes <- []status.DetailedStatus{}
+	}
+	return w, nil
+}
+
+type mockStatusHistoryWatcher struct {
+	state.StatusHistoryWatcher
+	kind    status.HistoryKind
+	changes chan []status.DetailedStatus
+	err     error
+}
+
+func (w *mockStatusHistoryWatcher) Changes() <-chan []status.DetailedStatus {
+	return w.changes
+}
+
+func (w *mockStatusHistoryWatcher) Err() error {
+	return w.err
+}
+
+func (w *mockStatusHistoryWatcher) Stop() error {
+	return nil
+}
+
 func (m *mockUnit) StatusHistory(filter status.StatusHistoryFilter) ([]status.StatusInfo, error) {
 	return m.status.StatusHistory(filter)
 }
 
+// StatusHistoryPage pages through the unit's workload history one
+// entry at a time, using the index of the next entry as the token.
+func (m *mockUnit) StatusHistoryPage(kind status.HistoryKind, filter status.StatusHistoryFilter, pageSize int, token string) (status.StatusHistoryPage, error) {
+	start := 0
+	if token != "" {
+		var err error
+		if start, err = strconv.Atoi(token); err != nil {
+			return status.StatusHistoryPage{}, err
+		}
+	}
+	end := start + pageSize
+	if end > len(m.status) {
+		end = len(m.status)
+	}
+	var page status.StatusHistoryPage
+	for _, s := range m.status[start:end] {
+		page.Statuses = append(page.Statuses, status.DetailedStatus{
+			Status: s.Status,
+			Info:   s.Message,
+			Since:  s.Since,
+			Kind:   kind,
+		})
+	}
+	if end < len(m.status) {
+		page.NextToken = strconv.Itoa(end)
+	}
+	return page, nil
+}
+
 func (m *mockUnit) AgentHistory() status.StatusHistoryGetter {
 	return m.agent
 }
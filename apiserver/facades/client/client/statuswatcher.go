@@ -0,0 +1,235 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"gopkg.in/tomb.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+// deltaWatcher is the part of state.Multiwatcher used to learn of
+// changes to the model.
+type deltaWatcher interface {
+	Next() ([]multiwatcher.Delta, error)
+	Stop() error
+}
+
+// statusWatchInterval is the shortest time between recalculations of
+// the full status reported by a status watcher.
+const statusWatchInterval = 2 * time.Second
+
+// statusWatcher sends the changes to a model's full status as diffs.
+// The first event holds the whole status, as diffs from an empty one.
+// The status is recalculated when the model changes, but no more than
+// once per interval, and changes not yet consumed are coalesced into a
+// single event.
+type statusWatcher struct {
+	tomb     tomb.Tomb
+	deltas   deltaWatcher
+	status   func() (params.FullStatus, error)
+	clock    clock.Clock
+	interval time.Duration
+	out      chan []params.StatusDiff
+}
+
+func newStatusWatcher(
+	deltas deltaWatcher,
+	status func() (params.FullStatus, error),
+	clock clock.Clock,
+	interval time.Duration,
+) *statusWatcher {
+	w := &statusWatcher{
+		deltas:   deltas,
+		status:   status,
+		clock:    clock,
+		interval: interval,
+		out:      make(chan []params.StatusDiff),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Stop stops the watcher, and returns any error encountered while running
+// or shutting down.
+func (w *statusWatcher) Stop() error {
+	w.Kill()
+	return w.Wait()
+}
+
+// Kill kills the watcher without waiting for it to shut down.
+func (w *statusWatcher) Kill() {
+	w.tomb.Kill(nil)
+}
+
+// Wait waits for the watcher to die and returns any
+// error encountered when it was running.
+func (w *statusWatcher) Wait() error {
+	return w.tomb.Wait()
+}
+
+// Err returns any error encountered while running or shutting down, or
+// tomb.ErrStillAlive if the watcher is still running.
+func (w *statusWatcher) Err() error {
+	return w.tomb.Err()
+}
+
+// Changes returns the event channel for the statusWatcher.
+func (w *statusWatcher) Changes() <-chan []params.StatusDiff {
+	return w.out
+}
+
+func (w *statusWatcher) loop() error {
+	defer w.deltas.Stop()
+	changes := make(chan error)
+	go func() {
+		for {
+			_, err := w.deltas.Next()
+			select {
+			case changes <- err:
+			case <-w.tomb.Dying():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	var sent, latest map[string]statusField
+	var pending []params.StatusDiff
+	dirty := true
+	// next is when the status may next be recalculated, and wait
+	// fires then if the model changes before.
+	var next time.Time
+	var wait <-chan time.Time
+	for {
+		if dirty && wait == nil {
+			if delay := next.Sub(w.clock.Now()); delay > 0 {
+				wait = w.clock.After(delay)
+			} else {
+				status, err := w.status()
+				if err != nil {
+					return errors.Trace(err)
+				}
+				if latest, err = flattenStatus(status); err != nil {
+					return errors.Trace(err)
+				}
+				pending = diffStatus(sent, latest)
+				dirty = false
+				next = w.clock.Now().Add(w.interval)
+			}
+		}
+		var out chan []params.StatusDiff
+		if len(pending) > 0 {
+			out = w.out
+		}
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case err := <-changes:
+			if err != nil {
+				return errors.Trace(err)
+			}
+			dirty = true
+		case <-wait:
+			wait = nil
+		case out <- pending:
+			sent, pending = latest, nil
+		}
+	}
+}
+
+// statusField holds one value of the JSON encoding of a full status
+// that is not an object.
+type statusField struct {
+	entity []string
+	field  string
+	value  interface{}
+}
+
+// flattenStatus returns the fields of the JSON encoding of the
+// status that are not objects, keyed on their path. Null fields are
+// omitted.
+func flattenStatus(status params.FullStatus) (map[string]statusField, error) {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var tree map[string]interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, errors.Trace(err)
+	}
+	fields := make(map[string]statusField)
+	flattenObject(fields, nil, tree)
+	return fields, nil
+}
+
+func flattenObject(fields map[string]statusField, entity []string, obj map[string]interface{}) {
+	for key, value := range obj {
+		switch value := value.(type) {
+		case nil:
+		case map[string]interface{}:
+			child := make([]string, len(entity), len(entity)+1)
+			copy(child, entity)
+			flattenObject(fields, append(child, key), value)
+		default:
+			path := strings.Join(append(entity[:len(entity):len(entity)], key), "\x00")
+			fields[path] = statusField{
+				entity: entity,
+				field:  key,
+				value:  value,
+			}
+		}
+	}
+}
+
+// diffStatus returns the diffs that turn the old flattened status
+// into the new one, ordered by path.
+func diffStatus(old, new map[string]statusField) []params.StatusDiff {
+	var paths []string
+	for path, f := range new {
+		if o, ok := old[path]; !ok || !reflect.DeepEqual(o.value, f.value) {
+			paths = append(paths, path)
+		}
+	}
+	for path := range old {
+		if _, ok := new[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	diffs := make([]params.StatusDiff, len(paths))
+	for i, path := range paths {
+		o, inOld := old[path]
+		f, inNew := new[path]
+		if !inNew {
+			f = o
+		}
+		diffs[i] = params.StatusDiff{
+			Entity: f.entity,
+			Field:  f.field,
+		}
+		if inOld {
+			diffs[i].Old = o.value
+		}
+		if inNew {
+			diffs[i].New = f.value
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,207 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facades/client/client"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state/multiwatcher"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type statusWatcherSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&statusWatcherSuite{})
+
+// fakeDeltaWatcher reports a change to the model each time a value
+// is sent on changes.
+type fakeDeltaWatcher struct {
+	changes  chan struct{}
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func newFakeDeltaWatcher() *fakeDeltaWatcher {
+	return &fakeDeltaWatcher{
+		changes: make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+func (w *fakeDeltaWatcher) Next() ([]multiwatcher.Delta, error) {
+	select {
+	case <-w.changes:
+		return nil, nil
+	case <-w.stopped:
+		return nil, errors.New("watcher was stopped")
+	}
+}
+
+func (w *fakeDeltaWatcher) Stop() error {
+	w.stopOnce.Do(func() { close(w.stopped) })
+	return nil
+}
+
+func (w *fakeDeltaWatcher) change(c *gc.C) {
+	select {
+	case w.changes <- struct{}{}:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out sending change")
+	}
+}
+
+func nextDiffs(c *gc.C, changes <-chan []params.StatusDiff) []params.StatusDiff {
+	select {
+	case diffs, ok := <-changes:
+		c.Assert(ok, jc.IsTrue)
+		return diffs
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for status diffs")
+	}
+	panic("unreachable")
+}
+
+func (s *statusWatcherSuite) TestDiffs(c *gc.C) {
+	status := params.FullStatus{
+		Model: params.ModelStatusInfo{Name: "first"},
+		Machines: map[string]params.MachineStatus{
+			"0": {Id: "0", Series: "xenial"},
+		},
+	}
+	deltas := newFakeDeltaWatcher()
+	w := client.NewStatusWatcher(deltas, func() (params.FullStatus, error) {
+		return status, nil
+	}, testing.NewClock(time.Time{}), 0)
+	defer func() {
+		c.Check(w.Stop(), jc.ErrorIsNil)
+	}()
+
+	// The first event holds the whole status.
+	diffs := nextDiffs(c, w.Changes())
+	for _, diff := range diffs {
+		c.Check(diff.Old, gc.IsNil)
+	}
+	c.Check(containsDiff(diffs, params.StatusDiff{
+		Entity: []string{"model"},
+		Field:  "name",
+		New:    "first",
+	}), jc.IsTrue)
+	c.Check(containsDiff(diffs, params.StatusDiff{
+		Entity: []string{"machines", "0"},
+		Field:  "series",
+		New:    "xenial",
+	}), jc.IsTrue)
+
+	// Later events hold only what has changed.
+	status.Model.Name = "second"
+	deltas.change(c)
+	c.Check(nextDiffs(c, w.Changes()), jc.DeepEquals, []params.StatusDiff{{
+		Entity: []string{"model"},
+		Field:  "name",
+		Old:    "first",
+		New:    "second",
+	}})
+
+	status.Machines = nil
+	deltas.change(c)
+	diffs = nextDiffs(c, w.Changes())
+	c.Assert(diffs, gc.Not(gc.HasLen), 0)
+	for _, diff := range diffs {
+		c.Check(diff.Entity[:2], jc.DeepEquals, []string{"machines", "0"})
+		c.Check(diff.New, gc.IsNil)
+	}
+	c.Check(containsDiff(diffs, params.StatusDiff{
+		Entity: []string{"machines", "0"},
+		Field:  "series",
+		Old:    "xenial",
+	}), jc.IsTrue)
+}
+
+func (s *statusWatcherSuite) TestStatusError(c *gc.C) {
+	deltas := newFakeDeltaWatcher()
+	w := client.NewStatusWatcher(deltas, func() (params.FullStatus, error) {
+		return params.FullStatus{}, errors.New("boom")
+	}, testing.NewClock(time.Time{}), time.Second)
+	select {
+	case _, ok := <-w.Changes():
+		c.Assert(ok, jc.IsFalse)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for watcher to stop")
+	}
+	c.Assert(w.Wait(), gc.ErrorMatches, "boom")
+	select {
+	case <-deltas.stopped:
+	default:
+		c.Fatalf("model watcher not stopped")
+	}
+}
+
+func (s *statusWatcherSuite) TestRecalculatedAtMostOncePerInterval(c *gc.C) {
+	var mu sync.Mutex
+	calls := 0
+	name := "first"
+	deltas := newFakeDeltaWatcher()
+	clock := testing.NewClock(time.Time{})
+	w := client.NewStatusWatcher(deltas, func() (params.FullStatus, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return params.FullStatus{Model: params.ModelStatusInfo{Name: name}}, nil
+	}, clock, time.Second)
+	defer func() {
+		c.Check(w.Stop(), jc.ErrorIsNil)
+	}()
+	nextDiffs(c, w.Changes())
+
+	// Changes within the interval wait for it to pass, and are
+	// reported together.
+	mu.Lock()
+	name = "second"
+	mu.Unlock()
+	deltas.change(c)
+	deltas.change(c)
+	c.Assert(clock.WaitAdvance(500*time.Millisecond, coretesting.LongWait, 1), jc.ErrorIsNil)
+	deltas.change(c)
+	select {
+	case diffs := <-w.Changes():
+		c.Fatalf("unexpected diffs %v", diffs)
+	case <-time.After(coretesting.ShortWait):
+	}
+	clock.Advance(500 * time.Millisecond)
+	c.Check(nextDiffs(c, w.Changes()), jc.DeepEquals, []params.StatusDiff{{
+		Entity: []string{"model"},
+		Field:  "name",
+		Old:    "first",
+		New:    "second",
+	}})
+	mu.Lock()
+	defer mu.Unlock()
+	c.Check(calls, gc.Equals, 2)
+}
+
+func containsDiff(diffs []params.StatusDiff, want params.StatusDiff) bool {
+	for _, diff := range diffs {
+		if diff.Field == want.Field && diff.Old == want.Old && diff.New == want.New &&
+			len(diff.Entity) == len(want.Entity) {
+			match := true
+			for i := range diff.Entity {
+				match = match && diff.Entity[i] == want.Entity[i]
+			}
+			if match {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package configprofiles
+
+import (
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state functionality required by the
+// configprofiles facade. It is implemented by *state.State.
+type Backend interface {
+	// ControllerTag returns the tag of the controller.
+	ControllerTag() names.ControllerTag
+
+	// ConfigProfiles returns all the config profiles stored on the
+	// controller, sorted by name.
+	ConfigProfiles() ([]state.ConfigProfile, error)
+
+	// ConfigProfile returns the named config profile.
+	ConfigProfile(name string) (state.ConfigProfile, error)
+
+	// AddConfigProfile stores a new config profile.
+	AddConfigProfile(name string, attrs map[string]interface{}) error
+
+	// UpdateConfigProfile replaces the values set by the named
+	// config profile.
+	UpdateConfigProfile(name string, attrs map[string]interface{}) error
+
+	// RemoveConfigProfile removes the named config profile.
+	RemoveConfigProfile(name string) error
+}
@@ -0,0 +1,135 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package configprofiles provides the ConfigProfiles facade, through
+// which the named sets of model config values stored on a controller
+// are managed. Any user may list and read the profiles, so that they
+// can be named when adding a model; only controller superusers may
+// change them.
+package configprofiles
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+// API provides the ConfigProfiles facade for v1.
+type API struct {
+	backend    Backend
+	authorizer facade.Authorizer
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(ctx.State(), ctx.Auth())
+}
+
+// NewAPI returns a new ConfigProfiles API facade.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		authorizer: authorizer,
+	}, nil
+}
+
+func (api *API) checkIsSuperuser() error {
+	allowed, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.backend.ControllerTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !allowed {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// List returns all the config profiles stored on the controller.
+func (api *API) List() (params.ConfigProfiles, error) {
+	profiles, err := api.backend.ConfigProfiles()
+	if err != nil {
+		return params.ConfigProfiles{}, errors.Trace(err)
+	}
+	result := params.ConfigProfiles{
+		Profiles: make([]params.ConfigProfile, len(profiles)),
+	}
+	for i, profile := range profiles {
+		result.Profiles[i] = toParams(profile)
+	}
+	return result, nil
+}
+
+// Get returns the named config profiles.
+func (api *API) Get(args params.ConfigProfileNames) (params.ConfigProfileResults, error) {
+	results := params.ConfigProfileResults{
+		Results: make([]params.ConfigProfileResult, len(args.Names)),
+	}
+	for i, name := range args.Names {
+		profile, err := api.backend.ConfigProfile(name)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result := toParams(profile)
+		results.Results[i].Result = &result
+	}
+	return results, nil
+}
+
+// Add stores new config profiles on the controller.
+func (api *API) Add(args params.ConfigProfiles) (params.ErrorResults, error) {
+	return api.set(args, api.backend.AddConfigProfile)
+}
+
+// Update replaces the values set by existing config profiles. Models
+// that the profiles were applied to are not changed.
+func (api *API) Update(args params.ConfigProfiles) (params.ErrorResults, error) {
+	return api.set(args, api.backend.UpdateConfigProfile)
+}
+
+func (api *API) set(
+	args params.ConfigProfiles,
+	setFunc func(string, map[string]interface{}) error,
+) (params.ErrorResults, error) {
+	if err := api.checkIsSuperuser(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Profiles)),
+	}
+	for i, profile := range args.Profiles {
+		err := setFunc(profile.Name, profile.Config)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// Remove removes config profiles from the controller. Models that the
+// profiles were applied to are not changed.
+func (api *API) Remove(args params.ConfigProfileNames) (params.ErrorResults, error) {
+	if err := api.checkIsSuperuser(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Names)),
+	}
+	for i, name := range args.Names {
+		err := api.backend.RemoveConfigProfile(name)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func toParams(profile state.ConfigProfile) params.ConfigProfile {
+	return params.ConfigProfile{
+		Name:   profile.Name,
+		Config: profile.Config,
+	}
+}
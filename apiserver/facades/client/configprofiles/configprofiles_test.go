@@ -0,0 +1,207 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package configprofiles_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/configprofiles"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ConfigProfilesSuite struct {
+	testing.IsolationSuite
+	backend    mockBackend
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&ConfigProfilesSuite{})
+
+func (s *ConfigProfilesSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("admin"),
+	}
+	s.backend = mockBackend{
+		profiles: []state.ConfigProfile{{
+			Name:   "dev",
+			Config: map[string]interface{}{"development": true},
+		}, {
+			Name:   "hardened",
+			Config: map[string]interface{}{"automatically-retry-hooks": false},
+		}},
+	}
+}
+
+func (s *ConfigProfilesSuite) newAPI(c *gc.C) *configprofiles.API {
+	api, err := configprofiles.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *ConfigProfilesSuite) TestNewAPIRequiresClient(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("0")
+	_, err := configprofiles.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *ConfigProfilesSuite) TestList(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("fred")
+	result, err := s.newAPI(c).List()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ConfigProfiles{
+		Profiles: []params.ConfigProfile{{
+			Name:   "dev",
+			Config: map[string]interface{}{"development": true},
+		}, {
+			Name:   "hardened",
+			Config: map[string]interface{}{"automatically-retry-hooks": false},
+		}},
+	})
+}
+
+func (s *ConfigProfilesSuite) TestGet(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("fred")
+	result, err := s.newAPI(c).Get(params.ConfigProfileNames{
+		Names: []string{"hardened", "missing"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ConfigProfileResults{
+		Results: []params.ConfigProfileResult{{
+			Result: &params.ConfigProfile{
+				Name:   "hardened",
+				Config: map[string]interface{}{"automatically-retry-hooks": false},
+			},
+		}, {
+			Error: &params.Error{
+				Code:    params.CodeNotFound,
+				Message: `config profile "missing" not found`,
+			},
+		}},
+	})
+}
+
+func (s *ConfigProfilesSuite) TestAdd(c *gc.C) {
+	s.backend.SetErrors(nil, errors.AlreadyExistsf(`config profile "dev"`))
+	result, err := s.newAPI(c).Add(params.ConfigProfiles{
+		Profiles: []params.ConfigProfile{{
+			Name:   "ci",
+			Config: map[string]interface{}{"test-mode": true},
+		}, {
+			Name:   "dev",
+			Config: map[string]interface{}{"development": true},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}, {
+			Error: &params.Error{
+				Code:    params.CodeAlreadyExists,
+				Message: `config profile "dev" already exists`,
+			},
+		}},
+	})
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"ControllerTag", nil},
+		{"AddConfigProfile", []interface{}{"ci", map[string]interface{}{"test-mode": true}}},
+		{"AddConfigProfile", []interface{}{"dev", map[string]interface{}{"development": true}}},
+	})
+}
+
+func (s *ConfigProfilesSuite) TestUpdate(c *gc.C) {
+	result, err := s.newAPI(c).Update(params.ConfigProfiles{
+		Profiles: []params.ConfigProfile{{
+			Name:   "dev",
+			Config: map[string]interface{}{"test-mode": true},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"ControllerTag", nil},
+		{"UpdateConfigProfile", []interface{}{"dev", map[string]interface{}{"test-mode": true}}},
+	})
+}
+
+func (s *ConfigProfilesSuite) TestRemove(c *gc.C) {
+	result, err := s.newAPI(c).Remove(params.ConfigProfileNames{
+		Names: []string{"dev"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"ControllerTag", nil},
+		{"RemoveConfigProfile", []interface{}{"dev"}},
+	})
+}
+
+func (s *ConfigProfilesSuite) TestChangesPermissionDenied(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("fred")
+	api := s.newAPI(c)
+	_, err := api.Add(params.ConfigProfiles{
+		Profiles: []params.ConfigProfile{{Name: "dev"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = api.Update(params.ConfigProfiles{
+		Profiles: []params.ConfigProfile{{Name: "dev"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = api.Remove(params.ConfigProfileNames{Names: []string{"dev"}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "ControllerTag", "ControllerTag", "ControllerTag")
+}
+
+type mockBackend struct {
+	testing.Stub
+	profiles []state.ConfigProfile
+}
+
+func (m *mockBackend) ControllerTag() names.ControllerTag {
+	m.MethodCall(m, "ControllerTag")
+	return coretesting.ControllerTag
+}
+
+func (m *mockBackend) ConfigProfiles() ([]state.ConfigProfile, error) {
+	m.MethodCall(m, "ConfigProfiles")
+	return m.profiles, m.NextErr()
+}
+
+func (m *mockBackend) ConfigProfile(name string) (state.ConfigProfile, error) {
+	m.MethodCall(m, "ConfigProfile", name)
+	if err := m.NextErr(); err != nil {
+		return state.ConfigProfile{}, err
+	}
+	for _, profile := range m.profiles {
+		if profile.Name == name {
+			return profile, nil
+		}
+	}
+	return state.ConfigProfile{}, errors.NotFoundf("config profile %q", name)
+}
+
+func (m *mockBackend) AddConfigProfile(name string, attrs map[string]interface{}) error {
+	m.MethodCall(m, "AddConfigProfile", name, attrs)
+	return m.NextErr()
+}
+
+func (m *mockBackend) UpdateConfigProfile(name string, attrs map[string]interface{}) error {
+	m.MethodCall(m, "UpdateConfigProfile", name, attrs)
+	return m.NextErr()
+}
+
+func (m *mockBackend) RemoveConfigProfile(name string) error {
+	m.MethodCall(m, "RemoveConfigProfile", name)
+	return m.NextErr()
+}
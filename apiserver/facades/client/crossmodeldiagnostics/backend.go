@@ -0,0 +1,151 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package crossmodeldiagnostics
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/macaroon.v1"
+
+	"github.com/juju/juju/core/crossmodel"
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state functionality required by the
+// crossmodeldiagnostics facade.
+type Backend interface {
+	// ModelTag returns the tag of the model.
+	ModelTag() names.ModelTag
+
+	// Relation returns the relation with the given id.
+	Relation(id int) (Relation, error)
+
+	// RemoteApplication returns the remote application with the
+	// given name.
+	RemoteApplication(name string) (RemoteApplication, error)
+
+	// GetToken returns the token by which the entity is known to
+	// the other model of a cross model relation.
+	GetToken(entity names.Tag) (string, error)
+
+	// GetRemoteEntity returns the tag of the entity known by the
+	// token to the other model of a cross model relation.
+	GetRemoteEntity(token string) (names.Tag, error)
+
+	// GetMacaroon returns the macaroon held for the entity.
+	GetMacaroon(entity names.Tag) (*macaroon.Macaroon, error)
+
+	// IngressNetworks returns the ingress networks of the relation.
+	IngressNetworks(relationKey string) (state.RelationNetworks, error)
+
+	// EgressNetworks returns the egress networks of the relation.
+	EgressNetworks(relationKey string) (state.RelationNetworks, error)
+
+	// FirewallRule returns the firewall rule for the service.
+	FirewallRule(service state.WellKnownServiceType) (*state.FirewallRule, error)
+
+	// ControllerInfoForModel returns the details of the external
+	// controller hosting the model with the given UUID.
+	ControllerInfoForModel(modelUUID string) (crossmodel.ControllerInfo, error)
+
+	// OtherModel returns a backend for the model with the given
+	// UUID, and a function that must be called to release it once
+	// it is no longer needed. It returns a NotFound error if the
+	// model is not hosted by this controller.
+	OtherModel(modelUUID string) (Backend, func(), error)
+}
+
+// Relation defines the relation functionality required by the
+// crossmodeldiagnostics facade.
+type Relation interface {
+	String() string
+	Id() int
+	Tag() names.Tag
+	Endpoints() []state.Endpoint
+}
+
+// RemoteApplication defines the remote application functionality
+// required by the crossmodeldiagnostics facade.
+type RemoteApplication interface {
+	Name() string
+	SourceModel() names.ModelTag
+	IsConsumerProxy() bool
+	Macaroon() (*macaroon.Macaroon, error)
+}
+
+// This type is an untested shim to let us wrap state in a sensible
+// interface and avoid writing tests that depend on mongodb. If you were
+// to change any part of it so that it were no longer *obviously* and
+// *trivially* correct, you would be Doing It Wrong.
+type stateShim struct {
+	*state.State
+	pool *state.StatePool
+}
+
+func (s stateShim) ModelTag() names.ModelTag {
+	return names.NewModelTag(s.State.ModelUUID())
+}
+
+func (s stateShim) Relation(id int) (Relation, error) {
+	rel, err := s.State.Relation(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return rel, nil
+}
+
+func (s stateShim) RemoteApplication(name string) (RemoteApplication, error) {
+	app, err := s.State.RemoteApplication(name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return app, nil
+}
+
+func (s stateShim) GetToken(entity names.Tag) (string, error) {
+	return s.State.RemoteEntities().GetToken(entity)
+}
+
+func (s stateShim) GetRemoteEntity(token string) (names.Tag, error) {
+	return s.State.RemoteEntities().GetRemoteEntity(token)
+}
+
+func (s stateShim) GetMacaroon(entity names.Tag) (*macaroon.Macaroon, error) {
+	return s.State.RemoteEntities().GetMacaroon(entity)
+}
+
+func (s stateShim) IngressNetworks(relationKey string) (state.RelationNetworks, error) {
+	return state.NewRelationIngressNetworks(s.State).Networks(relationKey)
+}
+
+func (s stateShim) EgressNetworks(relationKey string) (state.RelationNetworks, error) {
+	return state.NewRelationEgressNetworks(s.State).Networks(relationKey)
+}
+
+func (s stateShim) FirewallRule(service state.WellKnownServiceType) (*state.FirewallRule, error) {
+	return state.NewFirewallRules(s.State).Rule(service)
+}
+
+func (s stateShim) ControllerInfoForModel(modelUUID string) (crossmodel.ControllerInfo, error) {
+	ctrl, err := state.NewExternalControllers(s.State).ControllerForModel(modelUUID)
+	if err != nil {
+		return crossmodel.ControllerInfo{}, errors.Trace(err)
+	}
+	return ctrl.ControllerInfo(), nil
+}
+
+func (s stateShim) OtherModel(modelUUID string) (Backend, func(), error) {
+	exists, err := s.State.ModelExists(modelUUID)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if !exists {
+		return nil, nil, errors.NotFoundf("model %q", modelUUID)
+	}
+	st, releaser, err := s.pool.Get(modelUUID)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return stateShim{State: st, pool: s.pool}, func() { releaser() }, nil
+}
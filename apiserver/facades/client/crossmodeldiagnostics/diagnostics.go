@@ -0,0 +1,320 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package crossmodeldiagnostics provides the CrossModelDiagnostics
+// facade, which reports what each model taking part in a cross model
+// relation knows about it: the macaroon authorising it, the ingress
+// and egress networks last exchanged, the firewall rule admitting
+// them, and whether the offering controller can be reached. Model
+// admins use it to find out why a cross model relation is not working
+// without trawling through the logs of both controllers.
+package crossmodeldiagnostics
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/macaroon-bakery.v1/bakery/checkers"
+	"gopkg.in/macaroon.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+// probeTimeout is how long the connectivity probe waits for each
+// address of the offering controller.
+const probeTimeout = 5 * time.Second
+
+// hostedElsewhere is reported when the other model of a cross model
+// relation is not hosted by this controller.
+const hostedElsewhere = "model is hosted by another controller"
+
+// DialFunc connects to the address, giving up after the timeout.
+type DialFunc func(addr string, timeout time.Duration) (net.Conn, error)
+
+// API provides the CrossModelDiagnostics facade for v1.
+type API struct {
+	backend    Backend
+	authorizer facade.Authorizer
+	clock      clock.Clock
+	dial       DialFunc
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	backend := stateShim{State: ctx.State(), pool: ctx.StatePool()}
+	return NewAPI(backend, ctx.Auth(), clock.WallClock, net.DialTimeout)
+}
+
+// NewAPI returns a new CrossModelDiagnostics API facade.
+func NewAPI(backend Backend, authorizer facade.Authorizer, clock clock.Clock, dial DialFunc) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		authorizer: authorizer,
+		clock:      clock,
+		dial:       dial,
+	}, nil
+}
+
+func (api *API) checkIsAdmin() error {
+	allowed, err := api.authorizer.HasPermission(permission.AdminAccess, api.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !allowed {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// RelationDiagnostics reports the state of each of the specified cross
+// model relations in the models taking part in it, and probes the
+// connection to the controller hosting the offer. It requires admin
+// access to the model.
+func (api *API) RelationDiagnostics(args params.CrossModelRelationDiagnosticsArgs) (params.CrossModelRelationDiagnosticsResults, error) {
+	if err := api.checkIsAdmin(); err != nil {
+		return params.CrossModelRelationDiagnosticsResults{}, errors.Trace(err)
+	}
+	results := params.CrossModelRelationDiagnosticsResults{
+		Results: make([]params.CrossModelRelationDiagnosticsResult, len(args.RelationIds)),
+	}
+	for i, id := range args.RelationIds {
+		result, err := api.relationDiagnostics(id)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = result
+	}
+	return results, nil
+}
+
+func (api *API) relationDiagnostics(id int) (*params.CrossModelRelationDiagnostics, error) {
+	rel, err := api.backend.Relation(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	app, err := api.remoteApplication(rel)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := &params.CrossModelRelationDiagnostics{
+		RelationId:        rel.Id(),
+		RelationKey:       rel.Tag().Id(),
+		RemoteApplication: app.Name(),
+		Role:              "consumer",
+	}
+	if app.IsConsumerProxy() {
+		result.Role = "offerer"
+	}
+	if result.Local, err = api.sideDiagnostics(api.backend, rel.Tag(), app); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	otherModel := app.SourceModel()
+	result.Remote, result.RemoteUnavailable, err = api.remoteDiagnostics(otherModel.Id(), rel.Tag())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	switch {
+	case app.IsConsumerProxy():
+		result.Probe.Note = "not probed: the offering side does not connect to the consuming controller"
+	case result.RemoteUnavailable != hostedElsewhere:
+		result.Probe.Note = "not probed: the offer is hosted by this controller"
+	default:
+		if result.Probe, err = api.probe(otherModel.Id()); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return result, nil
+}
+
+// remoteApplication returns the remote application at the other end of
+// the relation, or a NotValid error if the relation is not a cross
+// model relation.
+func (api *API) remoteApplication(rel Relation) (RemoteApplication, error) {
+	for _, ep := range rel.Endpoints() {
+		app, err := api.backend.RemoteApplication(ep.ApplicationName)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return app, nil
+	}
+	return nil, errors.NewNotValid(nil, fmt.Sprintf("relation %q is not a cross model relation", rel))
+}
+
+// sideDiagnostics describes the relation with the given tag in the
+// model of the backend. The remote application, if known, supplies the
+// macaroon if none is held for the relation itself.
+func (api *API) sideDiagnostics(backend Backend, relTag names.Tag, app RemoteApplication) (params.RelationSideDiagnostics, error) {
+	result := params.RelationSideDiagnostics{
+		ModelTag: backend.ModelTag().String(),
+	}
+
+	mac, err := backend.GetMacaroon(relTag)
+	if errors.IsNotFound(err) && app != nil {
+		mac, err = app.Macaroon()
+	}
+	if err != nil && !errors.IsNotFound(err) {
+		return result, errors.Trace(err)
+	}
+	if mac != nil {
+		result.Macaroon = api.macaroonDiagnostics(mac)
+	}
+
+	if result.Ingress, err = networksDiagnostics(backend.IngressNetworks(relTag.Id())); err != nil {
+		return result, errors.Trace(err)
+	}
+	if result.Egress, err = networksDiagnostics(backend.EgressNetworks(relTag.Id())); err != nil {
+		return result, errors.Trace(err)
+	}
+
+	rule, err := backend.FirewallRule(state.JujuApplicationOfferRule)
+	if errors.IsNotFound(err) {
+		return result, nil
+	} else if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Firewall.WhitelistCIDRs = rule.WhitelistCIDRs
+	if result.Ingress != nil {
+		result.Firewall.Blocked, err = blockedCIDRs(rule.WhitelistCIDRs, result.Ingress.CIDRs)
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+	}
+	return result, nil
+}
+
+// remoteDiagnostics describes the relation in the model with the given
+// UUID, which knows it by the token of the local relation. If that
+// model is not hosted by this controller, or does not know the
+// relation, it returns the reason instead.
+func (api *API) remoteDiagnostics(modelUUID string, relTag names.Tag) (*params.RelationSideDiagnostics, string, error) {
+	other, release, err := api.backend.OtherModel(modelUUID)
+	if errors.IsNotFound(err) {
+		return nil, hostedElsewhere, nil
+	} else if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	defer release()
+
+	token, err := api.backend.GetToken(relTag)
+	if errors.IsNotFound(err) {
+		return nil, "relation has not been exchanged with the other model", nil
+	} else if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	otherTag, err := other.GetRemoteEntity(token)
+	if errors.IsNotFound(err) {
+		return nil, "relation is not known to the other model", nil
+	} else if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	result, err := api.sideDiagnostics(other, otherTag, nil)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	return &result, "", nil
+}
+
+// probe connects to the controller hosting the model with the given
+// UUID, trying each of its addresses in turn, and reports the first
+// that accepts the connection and how long that took.
+func (api *API) probe(modelUUID string) (params.ConnectivityProbeResult, error) {
+	info, err := api.backend.ControllerInfoForModel(modelUUID)
+	if errors.IsNotFound(err) {
+		return params.ConnectivityProbeResult{
+			Note: "not probed: the offering controller is not known",
+		}, nil
+	} else if err != nil {
+		return params.ConnectivityProbeResult{}, errors.Trace(err)
+	}
+	result := params.ConnectivityProbeResult{Probed: true}
+	if len(info.Addrs) == 0 {
+		result.Error = "the offering controller has no known addresses"
+		return result, nil
+	}
+	for _, addr := range info.Addrs {
+		start := api.clock.Now()
+		conn, err := api.dial(addr, probeTimeout)
+		if err != nil {
+			result.Error = err.Error()
+			continue
+		}
+		conn.Close()
+		return params.ConnectivityProbeResult{
+			Probed:  true,
+			Address: addr,
+			Latency: api.clock.Now().Sub(start),
+		}, nil
+	}
+	return result, nil
+}
+
+func (api *API) macaroonDiagnostics(mac *macaroon.Macaroon) *params.MacaroonDiagnostics {
+	result := &params.MacaroonDiagnostics{
+		Id:       mac.Id(),
+		Location: mac.Location(),
+	}
+	if expiry, ok := checkers.MacaroonsExpiryTime(macaroon.Slice{mac}); ok {
+		result.Expiry = &expiry
+		result.Expired = !api.clock.Now().Before(expiry)
+	}
+	return result
+}
+
+func networksDiagnostics(networks state.RelationNetworks, err error) (*params.RelationNetworksDiagnostics, error) {
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := &params.RelationNetworksDiagnostics{
+		CIDRs: networks.CIDRS(),
+	}
+	if updated := networks.Updated(); !updated.IsZero() {
+		result.Updated = &updated
+	}
+	return result, nil
+}
+
+// blockedCIDRs returns the networks that are not within the whitelist.
+// An empty whitelist allows all networks.
+func blockedCIDRs(whitelist, cidrs []string) ([]string, error) {
+	if len(whitelist) == 0 {
+		return nil, nil
+	}
+	var allowed []*net.IPNet
+	for _, cidr := range whitelist {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		allowed = append(allowed, ipNet)
+	}
+	var blocked []string
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !network.SubnetInAnyRange(allowed, ipNet) {
+			blocked = append(blocked, cidr)
+		}
+	}
+	return blocked, nil
+}
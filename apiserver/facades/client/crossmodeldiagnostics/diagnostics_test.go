@@ -0,0 +1,485 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package crossmodeldiagnostics_test
+
+import (
+	"net"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/macaroon-bakery.v1/bakery/checkers"
+	"gopkg.in/macaroon.v1"
+
+	"github.com/juju/juju/apiserver/facades/client/crossmodeldiagnostics"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/crossmodel"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+const (
+	offererModelUUID  = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+	consumerModelUUID = "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+)
+
+type DiagnosticsSuite struct {
+	testing.IsolationSuite
+	backend    *mockBackend
+	authorizer apiservertesting.FakeAuthorizer
+	clock      *testing.Clock
+	dialed     []string
+}
+
+var _ = gc.Suite(&DiagnosticsSuite{})
+
+func (s *DiagnosticsSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("admin"),
+	}
+	s.clock = testing.NewClock(time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC))
+	s.dialed = nil
+	s.backend = newMockBackend(coretesting.ModelTag.Id())
+	s.backend.relations[1] = &mockRelation{
+		id:  1,
+		key: "wordpress:db mysql:server",
+		endpoints: []state.Endpoint{{
+			ApplicationName: "wordpress",
+			Relation:        charm.Relation{Name: "db", Role: charm.RoleRequirer},
+		}, {
+			ApplicationName: "mysql",
+			Relation:        charm.Relation{Name: "server", Role: charm.RoleProvider},
+		}},
+	}
+	s.backend.relations[2] = &mockRelation{
+		id:  2,
+		key: "wordpress:cache memcached:cache",
+		endpoints: []state.Endpoint{{
+			ApplicationName: "wordpress",
+			Relation:        charm.Relation{Name: "cache", Role: charm.RoleRequirer},
+		}, {
+			ApplicationName: "memcached",
+			Relation:        charm.Relation{Name: "cache", Role: charm.RoleProvider},
+		}},
+	}
+	s.backend.remoteApps["mysql"] = &mockRemoteApplication{
+		name:        "mysql",
+		sourceModel: names.NewModelTag(offererModelUUID),
+	}
+}
+
+func (s *DiagnosticsSuite) newAPI(c *gc.C) *crossmodeldiagnostics.API {
+	api, err := crossmodeldiagnostics.NewAPI(s.backend, s.authorizer, s.clock, s.dial)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+// dial fails to connect to addresses on 10.0.0.1, and takes 20ms to
+// connect to any other.
+func (s *DiagnosticsSuite) dial(addr string, timeout time.Duration) (net.Conn, error) {
+	s.dialed = append(s.dialed, addr)
+	if host, _, _ := net.SplitHostPort(addr); host == "10.0.0.1" {
+		return nil, errors.New("connection refused")
+	}
+	s.clock.Advance(20 * time.Millisecond)
+	conn, other := net.Pipe()
+	other.Close()
+	return conn, nil
+}
+
+func (s *DiagnosticsSuite) newMacaroon(c *gc.C, expiry time.Time) *macaroon.Macaroon {
+	mac, err := macaroon.New([]byte("secret"), "id", "location")
+	c.Assert(err, jc.ErrorIsNil)
+	err = mac.AddFirstPartyCaveat(checkers.TimeBeforeCaveat(expiry).Condition)
+	c.Assert(err, jc.ErrorIsNil)
+	return mac
+}
+
+func (s *DiagnosticsSuite) TestNewAPIRequiresClient(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("0")
+	_, err := crossmodeldiagnostics.NewAPI(s.backend, s.authorizer, s.clock, s.dial)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *DiagnosticsSuite) TestRelationDiagnosticsRequiresAdmin(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("read")
+	_, err := s.newAPI(c).RelationDiagnostics(params.CrossModelRelationDiagnosticsArgs{
+		RelationIds: []int{1},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "ModelTag")
+}
+
+func (s *DiagnosticsSuite) TestConsumerOfOfferOnOtherController(c *gc.C) {
+	expiry := s.clock.Now().Add(-time.Minute)
+	s.backend.macaroons["relation-wordpress.db#mysql.server"] = s.newMacaroon(c, expiry)
+	egressUpdated := s.clock.Now().Add(-time.Hour)
+	s.backend.egress["wordpress:db mysql:server"] = &mockRelationNetworks{
+		cidrs:   []string{"10.1.0.0/24"},
+		updated: egressUpdated,
+	}
+	s.backend.controllers[offererModelUUID] = crossmodel.ControllerInfo{
+		Addrs: []string{"10.0.0.1:17070", "10.0.0.2:17070"},
+	}
+
+	results, err := s.newAPI(c).RelationDiagnostics(params.CrossModelRelationDiagnosticsArgs{
+		RelationIds: []int{1},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.CrossModelRelationDiagnosticsResults{
+		Results: []params.CrossModelRelationDiagnosticsResult{{
+			Result: &params.CrossModelRelationDiagnostics{
+				RelationId:        1,
+				RelationKey:       "wordpress:db mysql:server",
+				RemoteApplication: "mysql",
+				Role:              "consumer",
+				Local: params.RelationSideDiagnostics{
+					ModelTag: coretesting.ModelTag.String(),
+					Macaroon: &params.MacaroonDiagnostics{
+						Id:       "id",
+						Location: "location",
+						Expiry:   &expiry,
+						Expired:  true,
+					},
+					Egress: &params.RelationNetworksDiagnostics{
+						CIDRs:   []string{"10.1.0.0/24"},
+						Updated: &egressUpdated,
+					},
+				},
+				RemoteUnavailable: "model is hosted by another controller",
+				Probe: params.ConnectivityProbeResult{
+					Probed:  true,
+					Address: "10.0.0.2:17070",
+					Latency: 20 * time.Millisecond,
+				},
+			},
+		}},
+	})
+	c.Assert(s.dialed, jc.DeepEquals, []string{"10.0.0.1:17070", "10.0.0.2:17070"})
+}
+
+func (s *DiagnosticsSuite) TestProbeFailure(c *gc.C) {
+	s.backend.controllers[offererModelUUID] = crossmodel.ControllerInfo{
+		Addrs: []string{"10.0.0.1:17070"},
+	}
+	results, err := s.newAPI(c).RelationDiagnostics(params.CrossModelRelationDiagnosticsArgs{
+		RelationIds: []int{1},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Result.Probe, jc.DeepEquals, params.ConnectivityProbeResult{
+		Probed: true,
+		Error:  "connection refused",
+	})
+}
+
+func (s *DiagnosticsSuite) TestProbeUnknownController(c *gc.C) {
+	results, err := s.newAPI(c).RelationDiagnostics(params.CrossModelRelationDiagnosticsArgs{
+		RelationIds: []int{1},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Result.Probe, jc.DeepEquals, params.ConnectivityProbeResult{
+		Note: "not probed: the offering controller is not known",
+	})
+	c.Assert(s.dialed, gc.HasLen, 0)
+}
+
+func (s *DiagnosticsSuite) TestOffererWithConsumerOnSameController(c *gc.C) {
+	delete(s.backend.remoteApps, "mysql")
+	s.backend.relations[3] = &mockRelation{
+		id:  3,
+		key: "remote-abc:db mysql:server",
+		endpoints: []state.Endpoint{{
+			ApplicationName: "remote-abc",
+			Relation:        charm.Relation{Name: "db", Role: charm.RoleRequirer},
+		}, {
+			ApplicationName: "mysql",
+			Relation:        charm.Relation{Name: "server", Role: charm.RoleProvider},
+		}},
+	}
+	expiry := s.clock.Now().Add(time.Hour)
+	s.backend.remoteApps["remote-abc"] = &mockRemoteApplication{
+		name:          "remote-abc",
+		sourceModel:   names.NewModelTag(consumerModelUUID),
+		consumerProxy: true,
+		macaroon:      s.newMacaroon(c, expiry),
+	}
+	s.backend.ingress["remote-abc:db mysql:server"] = &mockRelationNetworks{
+		cidrs: []string{"10.0.1.0/24", "192.168.1.0/24"},
+	}
+	s.backend.rule = &state.FirewallRule{
+		WellKnownService: state.JujuApplicationOfferRule,
+		WhitelistCIDRs:   []string{"10.0.0.0/16"},
+	}
+	s.backend.tokens["relation-remote-abc.db#mysql.server"] = "token-3"
+
+	consumer := newMockBackend(consumerModelUUID)
+	consumer.entities["token-3"] = names.NewRelationTag("wordpress:db mysql:server")
+	consumer.egress["wordpress:db mysql:server"] = &mockRelationNetworks{
+		cidrs: []string{"10.0.1.0/24", "192.168.1.0/24"},
+	}
+	s.backend.others[consumerModelUUID] = consumer
+
+	results, err := s.newAPI(c).RelationDiagnostics(params.CrossModelRelationDiagnosticsArgs{
+		RelationIds: []int{3},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.CrossModelRelationDiagnosticsResults{
+		Results: []params.CrossModelRelationDiagnosticsResult{{
+			Result: &params.CrossModelRelationDiagnostics{
+				RelationId:        3,
+				RelationKey:       "remote-abc:db mysql:server",
+				RemoteApplication: "remote-abc",
+				Role:              "offerer",
+				Local: params.RelationSideDiagnostics{
+					ModelTag: coretesting.ModelTag.String(),
+					Macaroon: &params.MacaroonDiagnostics{
+						Id:       "id",
+						Location: "location",
+						Expiry:   &expiry,
+					},
+					Ingress: &params.RelationNetworksDiagnostics{
+						CIDRs: []string{"10.0.1.0/24", "192.168.1.0/24"},
+					},
+					Firewall: params.FirewallDiagnostics{
+						WhitelistCIDRs: []string{"10.0.0.0/16"},
+						Blocked:        []string{"192.168.1.0/24"},
+					},
+				},
+				Remote: &params.RelationSideDiagnostics{
+					ModelTag: names.NewModelTag(consumerModelUUID).String(),
+					Egress: &params.RelationNetworksDiagnostics{
+						CIDRs: []string{"10.0.1.0/24", "192.168.1.0/24"},
+					},
+				},
+				Probe: params.ConnectivityProbeResult{
+					Note: "not probed: the offering side does not connect to the consuming controller",
+				},
+			},
+		}},
+	})
+	c.Assert(consumer.released, jc.IsTrue)
+}
+
+func (s *DiagnosticsSuite) TestConsumerOfOfferOnSameController(c *gc.C) {
+	s.backend.others[offererModelUUID] = newMockBackend(offererModelUUID)
+	results, err := s.newAPI(c).RelationDiagnostics(params.CrossModelRelationDiagnosticsArgs{
+		RelationIds: []int{1},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	result := results.Results[0].Result
+	c.Assert(result.Remote, gc.IsNil)
+	c.Assert(result.RemoteUnavailable, gc.Equals, "relation has not been exchanged with the other model")
+	c.Assert(result.Probe, jc.DeepEquals, params.ConnectivityProbeResult{
+		Note: "not probed: the offer is hosted by this controller",
+	})
+	c.Assert(s.dialed, gc.HasLen, 0)
+}
+
+func (s *DiagnosticsSuite) TestRelationDiagnosticsErrors(c *gc.C) {
+	results, err := s.newAPI(c).RelationDiagnostics(params.CrossModelRelationDiagnosticsArgs{
+		RelationIds: []int{2, 4},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.CrossModelRelationDiagnosticsResults{
+		Results: []params.CrossModelRelationDiagnosticsResult{{
+			Error: &params.Error{Message: `relation "wordpress:cache memcached:cache" is not a cross model relation`},
+		}, {
+			Error: &params.Error{Code: params.CodeNotFound, Message: "relation 4 not found"},
+		}},
+	})
+}
+
+type mockBackend struct {
+	testing.Stub
+	modelUUID   string
+	relations   map[int]*mockRelation
+	remoteApps  map[string]*mockRemoteApplication
+	tokens      map[string]string
+	entities    map[string]names.Tag
+	macaroons   map[string]*macaroon.Macaroon
+	ingress     map[string]*mockRelationNetworks
+	egress      map[string]*mockRelationNetworks
+	rule        *state.FirewallRule
+	controllers map[string]crossmodel.ControllerInfo
+	others      map[string]*mockBackend
+	released    bool
+}
+
+func newMockBackend(modelUUID string) *mockBackend {
+	return &mockBackend{
+		modelUUID:   modelUUID,
+		relations:   make(map[int]*mockRelation),
+		remoteApps:  make(map[string]*mockRemoteApplication),
+		tokens:      make(map[string]string),
+		entities:    make(map[string]names.Tag),
+		macaroons:   make(map[string]*macaroon.Macaroon),
+		ingress:     make(map[string]*mockRelationNetworks),
+		egress:      make(map[string]*mockRelationNetworks),
+		controllers: make(map[string]crossmodel.ControllerInfo),
+		others:      make(map[string]*mockBackend),
+	}
+}
+
+func (m *mockBackend) ModelTag() names.ModelTag {
+	m.MethodCall(m, "ModelTag")
+	return names.NewModelTag(m.modelUUID)
+}
+
+func (m *mockBackend) Relation(id int) (crossmodeldiagnostics.Relation, error) {
+	m.MethodCall(m, "Relation", id)
+	rel, ok := m.relations[id]
+	if !ok {
+		return nil, errors.NotFoundf("relation %d", id)
+	}
+	return rel, nil
+}
+
+func (m *mockBackend) RemoteApplication(name string) (crossmodeldiagnostics.RemoteApplication, error) {
+	m.MethodCall(m, "RemoteApplication", name)
+	app, ok := m.remoteApps[name]
+	if !ok {
+		return nil, errors.NotFoundf("remote application %q", name)
+	}
+	return app, nil
+}
+
+func (m *mockBackend) GetToken(entity names.Tag) (string, error) {
+	m.MethodCall(m, "GetToken", entity)
+	token, ok := m.tokens[entity.String()]
+	if !ok {
+		return "", errors.NotFoundf("token for %s", entity)
+	}
+	return token, nil
+}
+
+func (m *mockBackend) GetRemoteEntity(token string) (names.Tag, error) {
+	m.MethodCall(m, "GetRemoteEntity", token)
+	tag, ok := m.entities[token]
+	if !ok {
+		return nil, errors.NotFoundf("entity with token %q", token)
+	}
+	return tag, nil
+}
+
+func (m *mockBackend) GetMacaroon(entity names.Tag) (*macaroon.Macaroon, error) {
+	m.MethodCall(m, "GetMacaroon", entity)
+	mac, ok := m.macaroons[entity.String()]
+	if !ok {
+		return nil, errors.NotFoundf("macaroon for %s", entity)
+	}
+	return mac, nil
+}
+
+func (m *mockBackend) IngressNetworks(relationKey string) (state.RelationNetworks, error) {
+	m.MethodCall(m, "IngressNetworks", relationKey)
+	networks, ok := m.ingress[relationKey]
+	if !ok {
+		return nil, errors.NotFoundf("ingress networks for relation %v", relationKey)
+	}
+	return networks, nil
+}
+
+func (m *mockBackend) EgressNetworks(relationKey string) (state.RelationNetworks, error) {
+	m.MethodCall(m, "EgressNetworks", relationKey)
+	networks, ok := m.egress[relationKey]
+	if !ok {
+		return nil, errors.NotFoundf("egress networks for relation %v", relationKey)
+	}
+	return networks, nil
+}
+
+func (m *mockBackend) FirewallRule(service state.WellKnownServiceType) (*state.FirewallRule, error) {
+	m.MethodCall(m, "FirewallRule", service)
+	if m.rule == nil {
+		return nil, errors.NotFoundf("firewall rules for service %v", service)
+	}
+	return m.rule, nil
+}
+
+func (m *mockBackend) ControllerInfoForModel(modelUUID string) (crossmodel.ControllerInfo, error) {
+	m.MethodCall(m, "ControllerInfoForModel", modelUUID)
+	info, ok := m.controllers[modelUUID]
+	if !ok {
+		return crossmodel.ControllerInfo{}, errors.NotFoundf("external controller with model %v", modelUUID)
+	}
+	return info, nil
+}
+
+func (m *mockBackend) OtherModel(modelUUID string) (crossmodeldiagnostics.Backend, func(), error) {
+	m.MethodCall(m, "OtherModel", modelUUID)
+	other, ok := m.others[modelUUID]
+	if !ok {
+		return nil, nil, errors.NotFoundf("model %q", modelUUID)
+	}
+	return other, func() { other.released = true }, nil
+}
+
+type mockRelation struct {
+	id        int
+	key       string
+	endpoints []state.Endpoint
+}
+
+func (m *mockRelation) String() string {
+	return m.key
+}
+
+func (m *mockRelation) Id() int {
+	return m.id
+}
+
+func (m *mockRelation) Tag() names.Tag {
+	return names.NewRelationTag(m.key)
+}
+
+func (m *mockRelation) Endpoints() []state.Endpoint {
+	return m.endpoints
+}
+
+type mockRemoteApplication struct {
+	name          string
+	sourceModel   names.ModelTag
+	consumerProxy bool
+	macaroon      *macaroon.Macaroon
+}
+
+func (m *mockRemoteApplication) Name() string {
+	return m.name
+}
+
+func (m *mockRemoteApplication) SourceModel() names.ModelTag {
+	return m.sourceModel
+}
+
+func (m *mockRemoteApplication) IsConsumerProxy() bool {
+	return m.consumerProxy
+}
+
+func (m *mockRemoteApplication) Macaroon() (*macaroon.Macaroon, error) {
+	return m.macaroon, nil
+}
+
+type mockRelationNetworks struct {
+	state.RelationNetworks
+	cidrs   []string
+	updated time.Time
+}
+
+func (m *mockRelationNetworks) CIDRS() []string {
+	return m.cidrs
+}
+
+func (m *mockRelationNetworks) Updated() time.Time {
+	return m.updated
+}
@@ -0,0 +1,86 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package faultinjection
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state functionality required by the
+// faultinjection facade.
+type Backend interface {
+	// ControllerTag returns the tag of the controller.
+	ControllerTag() names.ControllerTag
+
+	// ControllerModelUUID returns the UUID of the controller model.
+	ControllerModelUUID() string
+
+	// InjectFault injects the fault into the model with the given UUID.
+	InjectFault(modelUUID string, fault state.Fault) error
+
+	// ClearFault clears the fault of the given kind from the model
+	// with the given UUID.
+	ClearFault(modelUUID string, kind state.FaultKind) error
+
+	// Faults returns the faults in effect in the model with the
+	// given UUID.
+	Faults(modelUUID string) ([]state.Fault, error)
+
+	// PutAuditEntry records the entry in the controller's audit log.
+	PutAuditEntry(entry audit.AuditEntry) error
+}
+
+// This type is an untested shim to let us wrap state in a sensible
+// interface and avoid writing tests that depend on mongodb. If you were
+// to change any part of it so that it were no longer *obviously* and
+// *trivially* correct, you would be Doing It Wrong.
+type poolShim struct {
+	pool *state.StatePool
+}
+
+func (p poolShim) ControllerTag() names.ControllerTag {
+	return p.pool.SystemState().ControllerTag()
+}
+
+func (p poolShim) ControllerModelUUID() string {
+	return p.pool.SystemState().ControllerModelUUID()
+}
+
+func (p poolShim) withModel(modelUUID string, f func(*state.State) error) error {
+	st, release, err := p.pool.Get(modelUUID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer release()
+	return f(st)
+}
+
+func (p poolShim) InjectFault(modelUUID string, fault state.Fault) error {
+	return p.withModel(modelUUID, func(st *state.State) error {
+		return st.InjectFault(fault)
+	})
+}
+
+func (p poolShim) ClearFault(modelUUID string, kind state.FaultKind) error {
+	return p.withModel(modelUUID, func(st *state.State) error {
+		return st.ClearFault(kind)
+	})
+}
+
+func (p poolShim) Faults(modelUUID string) ([]state.Fault, error) {
+	var faults []state.Fault
+	err := p.withModel(modelUUID, func(st *state.State) (err error) {
+		faults, err = st.Faults()
+		return err
+	})
+	return faults, err
+}
+
+func (p poolShim) PutAuditEntry(entry audit.AuditEntry) error {
+	return p.pool.SystemState().PutAuditEntryFn()(entry)
+}
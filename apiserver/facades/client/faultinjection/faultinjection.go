@@ -0,0 +1,194 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package faultinjection provides a facade for injecting faults into
+// models, to check how agents and the controller cope with provider
+// failures, disconnections and a slow database. Injected faults only
+// have an effect while the fault-injection feature flag is enabled.
+package faultinjection
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+	jujuversion "github.com/juju/juju/version"
+)
+
+// MaxFaultDuration is the longest a fault may be injected for, so that
+// a forgotten fault cannot leave a model broken.
+const MaxFaultDuration = time.Hour
+
+// remoteAddress is recorded in the audit entries written by the
+// facade. Facades are not told the client's address; the API server's
+// audit observer records it against the request itself.
+const remoteAddress = "unknown"
+
+// API provides the FaultInjection facade for v1.
+type API struct {
+	backend    Backend
+	authorizer facade.Authorizer
+	clock      clock.Clock
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(poolShim{ctx.StatePool()}, ctx.Auth(), clock.WallClock)
+}
+
+// NewAPI returns a new FaultInjection API facade. Only controller
+// superusers may use it.
+func NewAPI(backend Backend, authorizer facade.Authorizer, clock clock.Clock) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	isAdmin, err := authorizer.HasPermission(permission.SuperuserAccess, backend.ControllerTag())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !isAdmin {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		authorizer: authorizer,
+		clock:      clock,
+	}, nil
+}
+
+// InjectFaults injects each of the given faults into its model for
+// the requested duration. Every injected fault is recorded in the
+// controller's audit log before it takes effect.
+func (api *API) InjectFaults(args params.InjectFaultArgs) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Faults)),
+	}
+	for i, arg := range args.Faults {
+		err := api.injectFault(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *API) injectFault(arg params.InjectFaultArg) error {
+	modelTag, err := names.ParseModelTag(arg.ModelTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	kind := state.FaultKind(arg.Kind)
+	if err := kind.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if arg.Duration <= 0 || arg.Duration > MaxFaultDuration {
+		return errors.NotValidf("fault duration %v (must be positive and at most %v)", arg.Duration, MaxFaultDuration)
+	}
+	if kind == state.FaultAgentDisconnect && modelTag.Id() == api.backend.ControllerModelUUID() {
+		// The controller's own agents must be able to log in, or
+		// there would be nothing left to clear the fault.
+		return errors.NotSupportedf("%s fault on the controller model", kind)
+	}
+	fault := state.Fault{
+		Kind:    kind,
+		Expires: api.clock.Now().Add(arg.Duration),
+	}
+	if kind == state.FaultSlowTransactions {
+		fault.Delay = arg.Delay
+	}
+	data := map[string]interface{}{
+		"kind":    string(kind),
+		"expires": fault.Expires.UTC().Format(time.RFC3339),
+	}
+	if fault.Delay > 0 {
+		data["delay"] = fault.Delay.String()
+	}
+	if err := api.audit(modelTag, "inject-fault", data); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(api.backend.InjectFault(modelTag.Id(), fault))
+}
+
+// ClearFaults clears each of the given faults from its model before
+// it would otherwise expire.
+func (api *API) ClearFaults(args params.ClearFaultArgs) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Faults)),
+	}
+	for i, arg := range args.Faults {
+		err := api.clearFault(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *API) clearFault(arg params.ClearFaultArg) error {
+	modelTag, err := names.ParseModelTag(arg.ModelTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	kind := state.FaultKind(arg.Kind)
+	if err := kind.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := api.audit(modelTag, "clear-fault", map[string]interface{}{"kind": string(kind)}); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(api.backend.ClearFault(modelTag.Id(), kind))
+}
+
+// Faults returns the faults in effect in each of the given models.
+func (api *API) Faults(args params.Entities) (params.FaultsResults, error) {
+	results := params.FaultsResults{
+		Results: make([]params.FaultsResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		faults, err := api.faults(entity.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Faults = faults
+	}
+	return results, nil
+}
+
+func (api *API) faults(tag string) ([]params.Fault, error) {
+	modelTag, err := names.ParseModelTag(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	faults, err := api.backend.Faults(modelTag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]params.Fault, len(faults))
+	for i, fault := range faults {
+		result[i] = params.Fault{
+			Kind:    string(fault.Kind),
+			Expires: fault.Expires,
+			Delay:   fault.Delay,
+		}
+	}
+	return result, nil
+}
+
+func (api *API) audit(modelTag names.ModelTag, operation string, data map[string]interface{}) error {
+	entry := audit.AuditEntry{
+		JujuServerVersion: jujuversion.Current,
+		ModelUUID:         modelTag.Id(),
+		Timestamp:         api.clock.Now().UTC(),
+		RemoteAddress:     remoteAddress,
+		OriginType:        "user",
+		OriginName:        api.authorizer.GetAuthTag().String(),
+		Operation:         operation,
+		Data:              data,
+	}
+	return errors.Annotate(api.backend.PutAuditEntry(entry), "recording fault in audit log")
+}
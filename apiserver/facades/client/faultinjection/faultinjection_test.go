@@ -0,0 +1,237 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package faultinjection_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/faultinjection"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+const controllerModelUUID = "c0ffee00-1bad-500d-9000-4b1d0d06f00d"
+
+type FaultInjectionSuite struct {
+	testing.IsolationSuite
+	backend    mockBackend
+	authorizer apiservertesting.FakeAuthorizer
+	clock      *testing.Clock
+	api        *faultinjection.API
+}
+
+var _ = gc.Suite(&FaultInjectionSuite{})
+
+func (s *FaultInjectionSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.backend = mockBackend{}
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag:      names.NewUserTag("admin"),
+		AdminTag: names.NewUserTag("admin"),
+	}
+	s.clock = testing.NewClock(time.Date(2018, 5, 1, 12, 0, 0, 0, time.UTC))
+	api, err := faultinjection.NewAPI(&s.backend, s.authorizer, s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = api
+}
+
+func (s *FaultInjectionSuite) TestNewAPIRequiresClient(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("0")
+	_, err := faultinjection.NewAPI(&s.backend, s.authorizer, s.clock)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *FaultInjectionSuite) TestNewAPIRequiresSuperuser(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("bob")
+	_, err := faultinjection.NewAPI(&s.backend, s.authorizer, s.clock)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *FaultInjectionSuite) TestInjectFaults(c *gc.C) {
+	results, err := s.api.InjectFaults(params.InjectFaultArgs{
+		Faults: []params.InjectFaultArg{{
+			ModelTag: coretesting.ModelTag.String(),
+			Kind:     "slow-transactions",
+			Duration: 5 * time.Minute,
+			Delay:    time.Second,
+		}, {
+			ModelTag: coretesting.ModelTag.String(),
+			Kind:     "provider-api",
+			Duration: time.Minute,
+			Delay:    time.Second,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{{}, {}})
+
+	now := s.clock.Now()
+	entry := audit.AuditEntry{
+		ModelUUID:     coretesting.ModelTag.Id(),
+		Timestamp:     now,
+		RemoteAddress: "unknown",
+		OriginType:    "user",
+		OriginName:    "user-admin",
+		Operation:     "inject-fault",
+	}
+	slowEntry := entry
+	slowEntry.Data = map[string]interface{}{
+		"kind":    "slow-transactions",
+		"expires": "2018-05-01T12:05:00Z",
+		"delay":   "1s",
+	}
+	providerEntry := entry
+	providerEntry.Data = map[string]interface{}{
+		"kind":    "provider-api",
+		"expires": "2018-05-01T12:01:00Z",
+	}
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"ControllerTag", nil},
+		{"ControllerModelUUID", nil},
+		{"PutAuditEntry", []interface{}{slowEntry}},
+		{"InjectFault", []interface{}{coretesting.ModelTag.Id(), state.Fault{
+			Kind:    state.FaultSlowTransactions,
+			Expires: now.Add(5 * time.Minute),
+			Delay:   time.Second,
+		}}},
+		{"ControllerModelUUID", nil},
+		{"PutAuditEntry", []interface{}{providerEntry}},
+		{"InjectFault", []interface{}{coretesting.ModelTag.Id(), state.Fault{
+			Kind:    state.FaultProviderAPI,
+			Expires: now.Add(time.Minute),
+		}}},
+	})
+}
+
+func (s *FaultInjectionSuite) TestInjectFaultsInvalid(c *gc.C) {
+	modelTag := coretesting.ModelTag.String()
+	results, err := s.api.InjectFaults(params.InjectFaultArgs{
+		Faults: []params.InjectFaultArg{
+			{ModelTag: "machine-0", Kind: "provider-api", Duration: time.Minute},
+			{ModelTag: modelTag, Kind: "meteor-strike", Duration: time.Minute},
+			{ModelTag: modelTag, Kind: "provider-api"},
+			{ModelTag: modelTag, Kind: "provider-api", Duration: 2 * time.Hour},
+			{ModelTag: names.NewModelTag(controllerModelUUID).String(), Kind: "agent-disconnect", Duration: time.Minute},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 5)
+	c.Check(results.Results[0].Error, gc.ErrorMatches, `"machine-0" is not a valid model tag`)
+	c.Check(results.Results[1].Error, gc.ErrorMatches, `fault kind "meteor-strike" not valid`)
+	c.Check(results.Results[2].Error, gc.ErrorMatches, `fault duration 0s \(must be positive and at most 1h0m0s\) not valid`)
+	c.Check(results.Results[3].Error, gc.ErrorMatches, `fault duration 2h0m0s \(must be positive and at most 1h0m0s\) not valid`)
+	c.Check(results.Results[4].Error, gc.ErrorMatches, `agent-disconnect fault on the controller model not supported`)
+	s.backend.CheckCallNames(c, "ControllerTag", "ControllerModelUUID")
+}
+
+func (s *FaultInjectionSuite) TestInjectFaultsAuditFailure(c *gc.C) {
+	s.backend.SetErrors(errors.New("boom"))
+	results, err := s.api.InjectFaults(params.InjectFaultArgs{
+		Faults: []params.InjectFaultArg{{
+			ModelTag: coretesting.ModelTag.String(),
+			Kind:     "agent-disconnect",
+			Duration: time.Minute,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "recording fault in audit log: boom")
+	s.backend.CheckCallNames(c, "ControllerTag", "ControllerModelUUID", "PutAuditEntry")
+}
+
+func (s *FaultInjectionSuite) TestClearFaults(c *gc.C) {
+	results, err := s.api.ClearFaults(params.ClearFaultArgs{
+		Faults: []params.ClearFaultArg{
+			{ModelTag: coretesting.ModelTag.String(), Kind: "agent-disconnect"},
+			{ModelTag: coretesting.ModelTag.String(), Kind: "unknown"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Check(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[1].Error, gc.ErrorMatches, `fault kind "unknown" not valid`)
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"ControllerTag", nil},
+		{"PutAuditEntry", []interface{}{audit.AuditEntry{
+			ModelUUID:     coretesting.ModelTag.Id(),
+			Timestamp:     s.clock.Now(),
+			RemoteAddress: "unknown",
+			OriginType:    "user",
+			OriginName:    "user-admin",
+			Operation:     "clear-fault",
+			Data:          map[string]interface{}{"kind": "agent-disconnect"},
+		}}},
+		{"ClearFault", []interface{}{coretesting.ModelTag.Id(), state.FaultAgentDisconnect}},
+	})
+}
+
+func (s *FaultInjectionSuite) TestFaults(c *gc.C) {
+	expires := s.clock.Now().Add(time.Minute)
+	s.backend.faults = []state.Fault{{
+		Kind:    state.FaultSlowTransactions,
+		Expires: expires,
+		Delay:   time.Second,
+	}}
+	results, err := s.api.Faults(params.Entities{
+		Entities: []params.Entity{
+			{Tag: coretesting.ModelTag.String()},
+			{Tag: "unit-mysql-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Check(results.Results[0], jc.DeepEquals, params.FaultsResult{
+		Faults: []params.Fault{{
+			Kind:    "slow-transactions",
+			Expires: expires,
+			Delay:   time.Second,
+		}},
+	})
+	c.Check(results.Results[1].Error, gc.ErrorMatches, `"unit-mysql-0" is not a valid model tag`)
+}
+
+type mockBackend struct {
+	testing.Stub
+	faults []state.Fault
+}
+
+func (b *mockBackend) ControllerTag() names.ControllerTag {
+	b.MethodCall(b, "ControllerTag")
+	return coretesting.ControllerTag
+}
+
+func (b *mockBackend) ControllerModelUUID() string {
+	b.MethodCall(b, "ControllerModelUUID")
+	return controllerModelUUID
+}
+
+func (b *mockBackend) InjectFault(modelUUID string, fault state.Fault) error {
+	b.MethodCall(b, "InjectFault", modelUUID, fault)
+	return b.NextErr()
+}
+
+func (b *mockBackend) ClearFault(modelUUID string, kind state.FaultKind) error {
+	b.MethodCall(b, "ClearFault", modelUUID, kind)
+	return b.NextErr()
+}
+
+func (b *mockBackend) Faults(modelUUID string) ([]state.Fault, error) {
+	b.MethodCall(b, "Faults", modelUUID)
+	return b.faults, b.NextErr()
+}
+
+func (b *mockBackend) PutAuditEntry(entry audit.AuditEntry) error {
+	// The server version varies from build to build.
+	entry.JujuServerVersion = version.Zero
+	b.MethodCall(b, "PutAuditEntry", entry)
+	return b.NextErr()
+}
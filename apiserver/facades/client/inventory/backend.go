@@ -0,0 +1,148 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package inventory
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state functionality required by the inventory
+// facade.
+type Backend interface {
+	// ControllerTag returns the tag of the controller.
+	ControllerTag() names.ControllerTag
+
+	// AllModelUUIDs returns the UUIDs of all of the models in the
+	// controller.
+	AllModelUUIDs() ([]string, error)
+
+	// Model returns the model with the given UUID, and a function
+	// that must be called when the model is no longer needed.
+	Model(uuid string) (Model, func(), error)
+
+	// RecordInventory records the fingerprints of the entities in
+	// the controller's inventory, returning the generations at which
+	// they last changed.
+	RecordInventory(fingerprints map[string]string) (state.InventoryGenerations, error)
+}
+
+// Model defines the model functionality required by the inventory
+// facade.
+type Model interface {
+	UUID() string
+	Name() string
+	Owner() names.UserTag
+	Type() state.ModelType
+	Cloud() string
+	CloudRegion() string
+	Life() state.Life
+	AllMachines() ([]Machine, error)
+	AllApplications() ([]Application, error)
+	AllStorageInstances() ([]state.StorageInstance, error)
+}
+
+// Machine defines the machine functionality required by the inventory
+// facade.
+type Machine interface {
+	Id() string
+	Life() state.Life
+	Series() string
+	InstanceId() (instance.Id, error)
+	HardwareCharacteristics() (*instance.HardwareCharacteristics, error)
+	Addresses() []network.Address
+}
+
+// Application defines the application functionality required by the
+// inventory facade.
+type Application interface {
+	Name() string
+	Life() state.Life
+	Series() string
+	CharmURL() (*charm.URL, bool)
+	UnitNames() ([]string, error)
+}
+
+// This type is an untested shim to let us wrap state in a sensible
+// interface and avoid writing tests that depend on mongodb. If you were
+// to change any part of it so that it were no longer *obviously* and
+// *trivially* correct, you would be Doing It Wrong.
+type stateShim struct {
+	*state.State
+	pool *state.StatePool
+}
+
+func (s stateShim) Model(uuid string) (Model, func(), error) {
+	st, release, err := s.pool.Get(uuid)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	model, err := st.Model()
+	if err != nil {
+		release()
+		return nil, nil, errors.Trace(err)
+	}
+	return modelShim{Model: model, st: st}, func() { release() }, nil
+}
+
+type modelShim struct {
+	*state.Model
+	st *state.State
+}
+
+func (m modelShim) AllMachines() ([]Machine, error) {
+	machines, err := m.st.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]Machine, len(machines))
+	for i, machine := range machines {
+		result[i] = machine
+	}
+	return result, nil
+}
+
+func (m modelShim) AllApplications() ([]Application, error) {
+	applications, err := m.st.AllApplications()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]Application, len(applications))
+	for i, app := range applications {
+		result[i] = applicationShim{app}
+	}
+	return result, nil
+}
+
+func (m modelShim) AllStorageInstances() ([]state.StorageInstance, error) {
+	if m.Model.Type() != state.ModelTypeIAAS {
+		return nil, nil
+	}
+	im, err := m.Model.IAASModel()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return im.AllStorageInstances()
+}
+
+type applicationShim struct {
+	*state.Application
+}
+
+func (a applicationShim) UnitNames() ([]string, error) {
+	units, err := a.Application.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]string, len(units))
+	for i, unit := range units {
+		result[i] = unit.Name()
+	}
+	return result, nil
+}
@@ -0,0 +1,384 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package inventory provides the Inventory facade, which exports the
+// inventory of a controller - its models, machines, applications and
+// storage - in a stable schema for external asset management systems.
+// Each entity records the inventory generation at which it last
+// changed, so that the inventory may be exported incrementally.
+package inventory
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.inventory")
+
+// API provides the Inventory facade for v1.
+type API struct {
+	backend    Backend
+	authorizer facade.Authorizer
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(stateShim{ctx.State(), ctx.StatePool()}, ctx.Auth())
+}
+
+// NewAPI returns a new Inventory API facade.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		authorizer: authorizer,
+	}, nil
+}
+
+func (api *API) checkIsSuperuser() error {
+	allowed, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.backend.ControllerTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !allowed {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// Export returns the inventory of the controller. If a generation is
+// given, only the entities added, changed or removed after that
+// generation are included.
+func (api *API) Export(args params.InventoryArgs) (params.Inventory, error) {
+	if err := api.checkIsSuperuser(); err != nil {
+		return params.Inventory{}, errors.Trace(err)
+	}
+	if args.SinceGeneration < 0 {
+		return params.Inventory{}, errors.NotValidf("generation %d", args.SinceGeneration)
+	}
+	models, err := api.allModels()
+	if err != nil {
+		return params.Inventory{}, errors.Trace(err)
+	}
+
+	fingerprints := make(map[string]string)
+	for _, model := range models {
+		if err := addFingerprints(fingerprints, model); err != nil {
+			return params.Inventory{}, errors.Trace(err)
+		}
+	}
+	generations, err := api.backend.RecordInventory(fingerprints)
+	if err != nil {
+		return params.Inventory{}, errors.Trace(err)
+	}
+	since := args.SinceGeneration
+	if since > generations.Generation {
+		return params.Inventory{}, errors.NewNotValid(nil, fmt.Sprintf(
+			"generation %d is later than the current generation %d", since, generations.Generation,
+		))
+	}
+	if since > 0 && since < generations.Pruned {
+		return params.Inventory{}, errors.NewNotValid(nil, fmt.Sprintf(
+			"removals before generation %d are no longer recorded; export the whole inventory", generations.Pruned,
+		))
+	}
+
+	inventory := params.Inventory{
+		SchemaVersion:   params.InventorySchemaVersion,
+		ControllerUUID:  api.backend.ControllerTag().Id(),
+		Generation:      generations.Generation,
+		SinceGeneration: since,
+		Models:          []params.InventoryModel{},
+	}
+	for _, model := range models {
+		if model, ok := changedSince(model, generations.Changed, since); ok {
+			inventory.Models = append(inventory.Models, model)
+		}
+	}
+	if since > 0 {
+		inventory.Removed = removedSince(generations.Removed, since)
+	}
+	return inventory, nil
+}
+
+func (api *API) allModels() ([]params.InventoryModel, error) {
+	uuids, err := api.backend.AllModelUUIDs()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sort.Strings(uuids)
+	models := make([]params.InventoryModel, 0, len(uuids))
+	for _, uuid := range uuids {
+		model, err := api.model(uuid)
+		if errors.IsNotFound(err) {
+			// The model was removed after it was listed.
+			continue
+		} else if err != nil {
+			return nil, errors.Annotatef(err, "cannot get inventory of model %s", uuid)
+		}
+		models = append(models, model)
+	}
+	return models, nil
+}
+
+func (api *API) model(uuid string) (params.InventoryModel, error) {
+	model, release, err := api.backend.Model(uuid)
+	if err != nil {
+		return params.InventoryModel{}, errors.Trace(err)
+	}
+	defer release()
+
+	result := params.InventoryModel{
+		UUID:        model.UUID(),
+		Name:        model.Name(),
+		Owner:       model.Owner().String(),
+		Type:        string(model.Type()),
+		Cloud:       model.Cloud(),
+		CloudRegion: model.CloudRegion(),
+		Life:        model.Life().String(),
+	}
+
+	machines, err := model.AllMachines()
+	if err != nil {
+		return params.InventoryModel{}, errors.Trace(err)
+	}
+	for _, machine := range machines {
+		m, err := machineInventory(machine)
+		if err != nil {
+			return params.InventoryModel{}, errors.Annotatef(err, "machine %s", machine.Id())
+		}
+		result.Machines = append(result.Machines, m)
+	}
+
+	applications, err := model.AllApplications()
+	if err != nil {
+		return params.InventoryModel{}, errors.Trace(err)
+	}
+	for _, app := range applications {
+		a, err := applicationInventory(app)
+		if err != nil {
+			return params.InventoryModel{}, errors.Annotatef(err, "application %s", app.Name())
+		}
+		result.Applications = append(result.Applications, a)
+	}
+
+	storage, err := model.AllStorageInstances()
+	if err != nil {
+		return params.InventoryModel{}, errors.Trace(err)
+	}
+	for _, s := range storage {
+		inventory := params.InventoryStorage{
+			Id:   s.StorageTag().Id(),
+			Name: s.StorageName(),
+			Kind: s.Kind().String(),
+			Pool: s.Pool(),
+			Life: s.Life().String(),
+		}
+		if owner, ok := s.Owner(); ok {
+			inventory.Owner = owner.String()
+		}
+		result.Storage = append(result.Storage, inventory)
+	}
+
+	sort.Slice(result.Machines, func(i, j int) bool {
+		return result.Machines[i].Id < result.Machines[j].Id
+	})
+	sort.Slice(result.Applications, func(i, j int) bool {
+		return result.Applications[i].Name < result.Applications[j].Name
+	})
+	sort.Slice(result.Storage, func(i, j int) bool {
+		return result.Storage[i].Id < result.Storage[j].Id
+	})
+	return result, nil
+}
+
+func machineInventory(machine Machine) (params.InventoryMachine, error) {
+	result := params.InventoryMachine{
+		Id:     machine.Id(),
+		Life:   machine.Life().String(),
+		Series: machine.Series(),
+	}
+	instanceId, err := machine.InstanceId()
+	if errors.IsNotProvisioned(err) {
+		return result, nil
+	} else if err != nil {
+		return params.InventoryMachine{}, errors.Trace(err)
+	}
+	result.InstanceId = string(instanceId)
+
+	hw, err := machine.HardwareCharacteristics()
+	if err != nil && !errors.IsNotFound(err) {
+		return params.InventoryMachine{}, errors.Trace(err)
+	}
+	if hw != nil {
+		result.Hardware = &params.MachineHardware{
+			Arch:             hw.Arch,
+			Mem:              hw.Mem,
+			RootDisk:         hw.RootDisk,
+			Cores:            hw.CpuCores,
+			CpuPower:         hw.CpuPower,
+			Tags:             hw.Tags,
+			AvailabilityZone: hw.AvailabilityZone,
+		}
+	}
+	for _, addr := range machine.Addresses() {
+		result.Addresses = append(result.Addresses, params.FromNetworkAddress(addr))
+	}
+	sort.Slice(result.Addresses, func(i, j int) bool {
+		return result.Addresses[i].Value < result.Addresses[j].Value
+	})
+	return result, nil
+}
+
+func applicationInventory(app Application) (params.InventoryApplication, error) {
+	result := params.InventoryApplication{
+		Name:   app.Name(),
+		Life:   app.Life().String(),
+		Series: app.Series(),
+	}
+	if curl, _ := app.CharmURL(); curl != nil {
+		result.CharmURL = curl.String()
+		result.CharmRevision = curl.Revision
+	}
+	units, err := app.UnitNames()
+	if err != nil {
+		return params.InventoryApplication{}, errors.Trace(err)
+	}
+	sort.Strings(units)
+	result.Units = units
+	return result, nil
+}
+
+// modelKey and entityKey return the keys under which the inventory
+// generations of a model, and of an entity in a model, are recorded.
+func modelKey(uuid string) string {
+	return names.NewModelTag(uuid).String()
+}
+
+func entityKey(uuid string, tag names.Tag) string {
+	return uuid + "/" + tag.String()
+}
+
+// addFingerprints adds the fingerprints of the model and of each of
+// its entities, which change whenever the inventory of the entity
+// changes, to fingerprints.
+func addFingerprints(fingerprints map[string]string, model params.InventoryModel) error {
+	modelOnly := model
+	modelOnly.Machines = nil
+	modelOnly.Applications = nil
+	modelOnly.Storage = nil
+	add := func(key string, v interface{}) error {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		sum := sha256.Sum256(data)
+		fingerprints[key] = hex.EncodeToString(sum[:])
+		return nil
+	}
+	if err := add(modelKey(model.UUID), modelOnly); err != nil {
+		return errors.Trace(err)
+	}
+	for _, m := range model.Machines {
+		if err := add(entityKey(model.UUID, names.NewMachineTag(m.Id)), m); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for _, a := range model.Applications {
+		if err := add(entityKey(model.UUID, names.NewApplicationTag(a.Name)), a); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for _, s := range model.Storage {
+		if err := add(entityKey(model.UUID, names.NewStorageTag(s.Id)), s); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// changedSince sets the generations of the model and of its entities,
+// and returns the model holding only those entities that have changed
+// since the given generation. It returns false if neither the model nor
+// any of its entities has changed.
+func changedSince(model params.InventoryModel, changed map[string]int64, since int64) (params.InventoryModel, bool) {
+	result := model
+	result.Generation = changed[modelKey(model.UUID)]
+	result.Machines = nil
+	result.Applications = nil
+	result.Storage = nil
+	for _, m := range model.Machines {
+		m.Generation = changed[entityKey(model.UUID, names.NewMachineTag(m.Id))]
+		if m.Generation > since {
+			result.Machines = append(result.Machines, m)
+		}
+	}
+	for _, a := range model.Applications {
+		a.Generation = changed[entityKey(model.UUID, names.NewApplicationTag(a.Name))]
+		if a.Generation > since {
+			result.Applications = append(result.Applications, a)
+		}
+	}
+	for _, s := range model.Storage {
+		s.Generation = changed[entityKey(model.UUID, names.NewStorageTag(s.Id))]
+		if s.Generation > since {
+			result.Storage = append(result.Storage, s)
+		}
+	}
+	ok := result.Generation > since ||
+		len(result.Machines) > 0 ||
+		len(result.Applications) > 0 ||
+		len(result.Storage) > 0
+	return result, ok
+}
+
+// removedSince returns the entities removed from the inventory since
+// the given generation, ordered by the generation of their removal.
+func removedSince(removed map[string]int64, since int64) []params.InventoryRemoval {
+	var result []params.InventoryRemoval
+	for key, generation := range removed {
+		if generation <= since {
+			continue
+		}
+		removal := params.InventoryRemoval{Generation: generation}
+		if i := strings.Index(key, "/"); i >= 0 {
+			removal.ModelUUID = key[:i]
+			removal.Tag = key[i+1:]
+		} else {
+			tag, err := names.ParseModelTag(key)
+			if err != nil {
+				logger.Warningf("ignoring unexpected inventory key %q", key)
+				continue
+			}
+			removal.ModelUUID = tag.Id()
+			removal.Tag = key
+		}
+		result = append(result, removal)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Generation != b.Generation {
+			return a.Generation < b.Generation
+		}
+		if a.ModelUUID != b.ModelUUID {
+			return a.ModelUUID < b.ModelUUID
+		}
+		return a.Tag < b.Tag
+	})
+	return result
+}
@@ -0,0 +1,387 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package inventory_test
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/client/inventory"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+const modelUUID = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+
+type InventorySuite struct {
+	testing.IsolationSuite
+	backend    *mockBackend
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&InventorySuite{})
+
+func (s *InventorySuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("superuser"),
+	}
+	arch := "amd64"
+	mem := uint64(4096)
+	s.backend = &mockBackend{
+		models: map[string]*mockModel{
+			modelUUID: {
+				uuid: modelUUID,
+				machines: []inventory.Machine{
+					&mockMachine{
+						id:         "0",
+						instanceId: "i-0",
+						hardware:   &instance.HardwareCharacteristics{Arch: &arch, Mem: &mem},
+						addresses:  network.NewAddresses("10.0.0.2", "10.0.0.1"),
+					},
+					&mockMachine{id: "1"},
+				},
+				applications: []inventory.Application{
+					&mockApplication{
+						name:  "mysql",
+						curl:  charm.MustParseURL("cs:quantal/mysql-42"),
+						units: []string{"mysql/1", "mysql/0"},
+					},
+				},
+				storage: []state.StorageInstance{
+					&mockStorageInstance{
+						tag:   names.NewStorageTag("data/0"),
+						owner: names.NewUnitTag("mysql/0"),
+					},
+				},
+			},
+		},
+	}
+}
+
+func (s *InventorySuite) newAPI(c *gc.C) *inventory.API {
+	api, err := inventory.NewAPI(s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *InventorySuite) TestNewAPIRequiresClient(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("0")
+	_, err := inventory.NewAPI(s.backend, s.authorizer)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *InventorySuite) TestExportRequiresSuperuser(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("admin")
+	_, err := s.newAPI(c).Export(params.InventoryArgs{})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrPerm)
+	s.backend.CheckNoCalls(c)
+}
+
+func (s *InventorySuite) TestExport(c *gc.C) {
+	s.backend.generations = state.InventoryGenerations{
+		Generation: 3,
+		Changed: map[string]int64{
+			"model-" + modelUUID:             1,
+			modelUUID + "/machine-0":         1,
+			modelUUID + "/machine-1":         3,
+			modelUUID + "/application-mysql": 2,
+			modelUUID + "/storage-data-0":    2,
+		},
+		Removed: map[string]int64{
+			modelUUID + "/machine-2": 3,
+		},
+	}
+	result, err := s.newAPI(c).Export(params.InventoryArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	arch := "amd64"
+	mem := uint64(4096)
+	c.Assert(result, jc.DeepEquals, params.Inventory{
+		SchemaVersion:  params.InventorySchemaVersion,
+		ControllerUUID: coretesting.ControllerTag.Id(),
+		Generation:     3,
+		Models: []params.InventoryModel{{
+			UUID:        modelUUID,
+			Name:        "testmodel",
+			Owner:       "user-bob",
+			Type:        "iaas",
+			Cloud:       "dummy",
+			CloudRegion: "dummy-region",
+			Life:        "alive",
+			Generation:  1,
+			Machines: []params.InventoryMachine{{
+				Id:         "0",
+				Life:       "alive",
+				Series:     "quantal",
+				InstanceId: "i-0",
+				Hardware:   &params.MachineHardware{Arch: &arch, Mem: &mem},
+				Addresses: []params.Address{
+					{Value: "10.0.0.1", Type: "ipv4", Scope: "local-cloud"},
+					{Value: "10.0.0.2", Type: "ipv4", Scope: "local-cloud"},
+				},
+				Generation: 1,
+			}, {
+				Id:         "1",
+				Life:       "alive",
+				Series:     "quantal",
+				Generation: 3,
+			}},
+			Applications: []params.InventoryApplication{{
+				Name:          "mysql",
+				Life:          "alive",
+				Series:        "quantal",
+				CharmURL:      "cs:quantal/mysql-42",
+				CharmRevision: 42,
+				Units:         []string{"mysql/0", "mysql/1"},
+				Generation:    2,
+			}},
+			Storage: []params.InventoryStorage{{
+				Id:         "data/0",
+				Name:       "data",
+				Kind:       "block",
+				Pool:       "ebs",
+				Owner:      "unit-mysql-0",
+				Life:       "alive",
+				Generation: 2,
+			}},
+		}},
+	})
+
+	s.backend.CheckCallNames(c, "AllModelUUIDs", "Model", "RecordInventory")
+	fingerprints := s.backend.Calls()[2].Args[0].(map[string]string)
+	keys := make([]string, 0, len(fingerprints))
+	for key, fingerprint := range fingerprints {
+		c.Check(fingerprint, gc.HasLen, 64)
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	c.Assert(keys, jc.DeepEquals, []string{
+		modelUUID + "/application-mysql",
+		modelUUID + "/machine-0",
+		modelUUID + "/machine-1",
+		modelUUID + "/storage-data-0",
+		"model-" + modelUUID,
+	})
+}
+
+func (s *InventorySuite) TestExportSinceGeneration(c *gc.C) {
+	s.backend.generations = state.InventoryGenerations{
+		Generation: 3,
+		Changed: map[string]int64{
+			"model-" + modelUUID:             1,
+			modelUUID + "/machine-0":         1,
+			modelUUID + "/machine-1":         3,
+			modelUUID + "/application-mysql": 2,
+			modelUUID + "/storage-data-0":    2,
+		},
+		Removed: map[string]int64{
+			modelUUID + "/machine-2":                     3,
+			modelUUID + "/machine-3":                     1,
+			"model-0ddba110-0000-4000-8000-000000000000": 3,
+		},
+	}
+	result, err := s.newAPI(c).Export(params.InventoryArgs{SinceGeneration: 2})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Generation, gc.Equals, int64(3))
+	c.Assert(result.SinceGeneration, gc.Equals, int64(2))
+	c.Assert(result.Models, gc.HasLen, 1)
+	model := result.Models[0]
+	c.Assert(model.Generation, gc.Equals, int64(1))
+	c.Assert(model.Machines, jc.DeepEquals, []params.InventoryMachine{{
+		Id:         "1",
+		Life:       "alive",
+		Series:     "quantal",
+		Generation: 3,
+	}})
+	c.Assert(model.Applications, gc.HasLen, 0)
+	c.Assert(model.Storage, gc.HasLen, 0)
+	c.Assert(result.Removed, jc.DeepEquals, []params.InventoryRemoval{{
+		ModelUUID:  "0ddba110-0000-4000-8000-000000000000",
+		Tag:        "model-0ddba110-0000-4000-8000-000000000000",
+		Generation: 3,
+	}, {
+		ModelUUID:  modelUUID,
+		Tag:        "machine-2",
+		Generation: 3,
+	}})
+}
+
+func (s *InventorySuite) TestExportSinceGenerationUnchanged(c *gc.C) {
+	s.backend.generations = state.InventoryGenerations{
+		Generation: 3,
+		Changed: map[string]int64{
+			"model-" + modelUUID:             1,
+			modelUUID + "/machine-0":         1,
+			modelUUID + "/machine-1":         1,
+			modelUUID + "/application-mysql": 1,
+			modelUUID + "/storage-data-0":    1,
+		},
+	}
+	result, err := s.newAPI(c).Export(params.InventoryArgs{SinceGeneration: 3})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Models, gc.HasLen, 0)
+	c.Assert(result.Removed, gc.HasLen, 0)
+}
+
+func (s *InventorySuite) TestExportSinceFutureGeneration(c *gc.C) {
+	s.backend.generations = state.InventoryGenerations{Generation: 3}
+	_, err := s.newAPI(c).Export(params.InventoryArgs{SinceGeneration: 4})
+	c.Assert(err, gc.ErrorMatches, "generation 4 is later than the current generation 3")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *InventorySuite) TestExportSincePrunedGeneration(c *gc.C) {
+	s.backend.generations = state.InventoryGenerations{Generation: 9, Pruned: 5}
+	_, err := s.newAPI(c).Export(params.InventoryArgs{SinceGeneration: 4})
+	c.Assert(err, gc.ErrorMatches, "removals before generation 5 are no longer recorded; export the whole inventory")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *InventorySuite) TestExportSkipsRemovedModel(c *gc.C) {
+	s.backend.extraUUIDs = []string{"0ddba110-0000-4000-8000-000000000000"}
+	s.backend.generations = state.InventoryGenerations{
+		Generation: 1,
+		Changed:    map[string]int64{"model-" + modelUUID: 1},
+	}
+	result, err := s.newAPI(c).Export(params.InventoryArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Models, gc.HasLen, 1)
+	c.Assert(result.Models[0].UUID, gc.Equals, modelUUID)
+}
+
+type mockBackend struct {
+	testing.Stub
+	models      map[string]*mockModel
+	extraUUIDs  []string
+	generations state.InventoryGenerations
+}
+
+func (b *mockBackend) ControllerTag() names.ControllerTag {
+	return coretesting.ControllerTag
+}
+
+func (b *mockBackend) AllModelUUIDs() ([]string, error) {
+	b.MethodCall(b, "AllModelUUIDs")
+	uuids := append([]string{}, b.extraUUIDs...)
+	for uuid := range b.models {
+		uuids = append(uuids, uuid)
+	}
+	return uuids, b.NextErr()
+}
+
+func (b *mockBackend) Model(uuid string) (inventory.Model, func(), error) {
+	b.MethodCall(b, "Model", uuid)
+	model, ok := b.models[uuid]
+	if !ok {
+		return nil, nil, errors.NotFoundf("model %q", uuid)
+	}
+	return model, func() {}, b.NextErr()
+}
+
+func (b *mockBackend) RecordInventory(fingerprints map[string]string) (state.InventoryGenerations, error) {
+	b.MethodCall(b, "RecordInventory", fingerprints)
+	return b.generations, b.NextErr()
+}
+
+type mockModel struct {
+	uuid         string
+	machines     []inventory.Machine
+	applications []inventory.Application
+	storage      []state.StorageInstance
+}
+
+func (m *mockModel) UUID() string          { return m.uuid }
+func (m *mockModel) Name() string          { return "testmodel" }
+func (m *mockModel) Owner() names.UserTag  { return names.NewUserTag("bob") }
+func (m *mockModel) Type() state.ModelType { return state.ModelTypeIAAS }
+func (m *mockModel) Cloud() string         { return "dummy" }
+func (m *mockModel) CloudRegion() string   { return "dummy-region" }
+func (m *mockModel) Life() state.Life      { return state.Alive }
+
+func (m *mockModel) AllMachines() ([]inventory.Machine, error) {
+	return m.machines, nil
+}
+
+func (m *mockModel) AllApplications() ([]inventory.Application, error) {
+	return m.applications, nil
+}
+
+func (m *mockModel) AllStorageInstances() ([]state.StorageInstance, error) {
+	return m.storage, nil
+}
+
+type mockMachine struct {
+	id         string
+	instanceId instance.Id
+	hardware   *instance.HardwareCharacteristics
+	addresses  []network.Address
+}
+
+func (m *mockMachine) Id() string       { return m.id }
+func (m *mockMachine) Life() state.Life { return state.Alive }
+func (m *mockMachine) Series() string   { return "quantal" }
+
+func (m *mockMachine) InstanceId() (instance.Id, error) {
+	if m.instanceId == "" {
+		return "", errors.NotProvisionedf("machine %v", m.id)
+	}
+	return m.instanceId, nil
+}
+
+func (m *mockMachine) HardwareCharacteristics() (*instance.HardwareCharacteristics, error) {
+	if m.hardware == nil {
+		return nil, errors.NotFoundf("instance data for machine %v", m.id)
+	}
+	return m.hardware, nil
+}
+
+func (m *mockMachine) Addresses() []network.Address {
+	return m.addresses
+}
+
+type mockApplication struct {
+	name  string
+	curl  *charm.URL
+	units []string
+}
+
+func (a *mockApplication) Name() string     { return a.name }
+func (a *mockApplication) Life() state.Life { return state.Alive }
+func (a *mockApplication) Series() string   { return "quantal" }
+
+func (a *mockApplication) CharmURL() (*charm.URL, bool) {
+	return a.curl, false
+}
+
+func (a *mockApplication) UnitNames() ([]string, error) {
+	return append([]string{}, a.units...), nil
+}
+
+type mockStorageInstance struct {
+	state.StorageInstance
+	tag   names.StorageTag
+	owner names.Tag
+}
+
+func (s *mockStorageInstance) StorageTag() names.StorageTag { return s.tag }
+func (s *mockStorageInstance) StorageName() string          { return "data" }
+func (s *mockStorageInstance) Kind() state.StorageKind      { return state.StorageKindBlock }
+func (s *mockStorageInstance) Pool() string                 { return "ebs" }
+func (s *mockStorageInstance) Life() state.Life             { return state.Alive }
+
+func (s *mockStorageInstance) Owner() (names.Tag, bool) {
+	return s.owner, s.owner != nil
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/sshkeys"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
@@ -36,7 +37,9 @@ type KeyManager interface {
 }
 
 // KeyManagerAPI implements the KeyUpdater interface and is the concrete
-// implementation of the api end point.
+// implementation of the api end point. Keys added through the v2 API
+// belong to the user they are added for, and are only installed on the
+// model's machines while that user has admin access to the model.
 type KeyManagerAPI struct {
 	state      *state.State
 	model      *state.Model
@@ -46,7 +49,25 @@ type KeyManagerAPI struct {
 	check      *common.BlockChecker
 }
 
-var _ KeyManager = (*KeyManagerAPI)(nil)
+// KeyManagerAPIV1 provides the v1 KeyManager API, in which all keys are
+// shared by every user of the model.
+type KeyManagerAPIV1 struct {
+	*KeyManagerAPI
+}
+
+var (
+	_ KeyManager = (*KeyManagerAPI)(nil)
+	_ KeyManager = (*KeyManagerAPIV1)(nil)
+)
+
+// NewKeyManagerAPIV1 creates a new server-side v1 keymanager API end point.
+func NewKeyManagerAPIV1(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*KeyManagerAPIV1, error) {
+	api, err := NewKeyManagerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &KeyManagerAPIV1{api}, nil
+}
 
 // NewKeyManagerAPI creates a new server-side keyupdater API end point.
 func NewKeyManagerAPI(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*KeyManagerAPI, error) {
@@ -200,6 +221,31 @@ func (api *KeyManagerAPI) currentKeyDataForAdd() (keys []string, fingerprints se
 
 // AddKeys adds new authorised ssh keys for the specified user.
 func (api *KeyManagerAPI) AddKeys(arg params.ModifyUserSSHKeys) (params.ErrorResults, error) {
+	return api.addKeys(arg, true)
+}
+
+// AddKeys adds new authorised ssh keys, shared by all users of the model.
+func (api *KeyManagerAPIV1) AddKeys(arg params.ModifyUserSSHKeys) (params.ErrorResults, error) {
+	return api.addKeys(arg, false)
+}
+
+// keyOwner returns the name of the user who will own keys added for the
+// supplied user, or the empty string if the keys are to be shared by all
+// users of the model.
+func keyOwner(user string, perUser bool) (string, error) {
+	if !perUser {
+		return "", nil
+	}
+	if !names.IsValidUser(user) {
+		return "", errors.NotValidf("user name %q", user)
+	}
+	return names.NewUserTag(user).Id(), nil
+}
+
+// addKeys adds the supplied keys to the model. If perUser is true, the
+// keys belong to the user named in the arguments; otherwise they are
+// shared by all users.
+func (api *KeyManagerAPI) addKeys(arg params.ModifyUserSSHKeys, perUser bool) (params.ErrorResults, error) {
 	if err := api.check.ChangeAllowed(); err != nil {
 		return params.ErrorResults{}, errors.Trace(err)
 	}
@@ -213,8 +259,11 @@ func (api *KeyManagerAPI) AddKeys(arg params.ModifyUserSSHKeys) (params.ErrorRes
 	if err := api.checkCanWrite(arg.User); err != nil {
 		return params.ErrorResults{}, common.ServerError(err)
 	}
+	owner, err := keyOwner(arg.User, perUser)
+	if err != nil {
+		return params.ErrorResults{}, common.ServerError(err)
+	}
 
-	// For now, authorised keys are global, common to all users.
 	sshKeys, currentFingerprints, err := api.currentKeyDataForAdd()
 	if err != nil {
 		return params.ErrorResults{}, common.ServerError(fmt.Errorf("reading current key data: %v", err))
@@ -232,6 +281,9 @@ func (api *KeyManagerAPI) AddKeys(arg params.ModifyUserSSHKeys) (params.ErrorRes
 			result.Results[i].Error = common.ServerError(fmt.Errorf("duplicate ssh key: %s", key))
 			continue
 		}
+		if owner != "" {
+			key = sshkeys.WithOwner(key, owner)
+		}
 		sshKeys = append(sshKeys, key)
 	}
 	err = api.writeSSHKeys(sshKeys)
@@ -291,6 +343,19 @@ func runSSHKeyImport(keyIds []string) map[string][]importedSSHKey {
 
 // ImportKeys imports new authorised ssh keys from the specified key ids for the specified user.
 func (api *KeyManagerAPI) ImportKeys(arg params.ModifyUserSSHKeys) (params.ErrorResults, error) {
+	return api.importKeys(arg, true)
+}
+
+// ImportKeys imports new authorised ssh keys from the specified key ids,
+// shared by all users of the model.
+func (api *KeyManagerAPIV1) ImportKeys(arg params.ModifyUserSSHKeys) (params.ErrorResults, error) {
+	return api.importKeys(arg, false)
+}
+
+// importKeys imports keys from the supplied key ids. If perUser is true,
+// the keys belong to the user named in the arguments; otherwise they are
+// shared by all users.
+func (api *KeyManagerAPI) importKeys(arg params.ModifyUserSSHKeys, perUser bool) (params.ErrorResults, error) {
 	if err := api.check.ChangeAllowed(); err != nil {
 		return params.ErrorResults{}, errors.Trace(err)
 	}
@@ -304,8 +369,11 @@ func (api *KeyManagerAPI) ImportKeys(arg params.ModifyUserSSHKeys) (params.Error
 	if err := api.checkCanWrite(arg.User); err != nil {
 		return params.ErrorResults{}, common.ServerError(err)
 	}
+	owner, err := keyOwner(arg.User, perUser)
+	if err != nil {
+		return params.ErrorResults{}, common.ServerError(err)
+	}
 
-	// For now, authorised keys are global, common to all users.
 	sshKeys, currentFingerprints, err := api.currentKeyDataForAdd()
 	if err != nil {
 		return params.ErrorResults{}, common.ServerError(fmt.Errorf("reading current key data: %v", err))
@@ -325,7 +393,11 @@ func (api *KeyManagerAPI) ImportKeys(arg params.ModifyUserSSHKeys) (params.Error
 				compoundErr += fmt.Sprintf("%v\n", errors.Errorf("duplicate ssh key: %s", keyInfo.key))
 				continue
 			}
-			sshKeys = append(sshKeys, keyInfo.key)
+			key := keyInfo.key
+			if owner != "" {
+				key = sshkeys.WithOwner(key, owner)
+			}
+			sshKeys = append(sshKeys, key)
 		}
 		if compoundErr != "" {
 			result.Results[i].Error = common.ServerError(errors.Errorf(strings.TrimSuffix(compoundErr, "\n")))
@@ -364,6 +436,11 @@ func (api *KeyManagerAPI) currentKeyDataForDelete() (
 		if comment != "" {
 			byComment[comment] = key
 		}
+		// Keys may also be identified by their comment as it was
+		// given, without the metadata Juju records in it.
+		if baseComment := sshkeys.BaseComment(comment); baseComment != "" {
+			byComment[baseComment] = key
+		}
 	}
 	return currentKeys, byFingerprint, byComment, nil
 }
@@ -19,6 +19,7 @@ import (
 	keymanagertesting "github.com/juju/juju/apiserver/facades/client/keymanager/testing"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/sshkeys"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
@@ -205,13 +206,55 @@ func (s *keyManagerSuite) assertAddKeys(c *gc.C, st *state.State, apiUser names.
 			{Error: apiservertesting.ServerError("invalid ssh key: invalid-key")},
 		},
 	})
-	s.assertKeysForModel(c, st, append(initialKeys, newKey))
+	s.assertKeysForModel(c, st, append(initialKeys, sshkeys.WithOwner(newKey, s.AdminUserTag(c).Id())))
 }
 
 func (s *keyManagerSuite) TestAddKeys(c *gc.C) {
 	s.assertAddKeys(c, s.State, s.AdminUserTag(c), true)
 }
 
+func (s *keyManagerSuite) TestAddKeysForUser(c *gc.C) {
+	key1 := sshtesting.ValidKeyOne.Key + " user@host"
+	s.setAuthorisedKeys(c, key1)
+
+	newKey := sshtesting.ValidKeyThree.Key + " newuser@host"
+	args := params.ModifyUserSSHKeys{
+		User: "bob",
+		Keys: []string{newKey},
+	}
+	results, err := s.keymanager.AddKeys(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{Error: nil}},
+	})
+	s.assertModelKeys(c, []string{key1, newKey + " # juju-user bob"})
+}
+
+func (s *keyManagerSuite) TestAddKeysInvalidOwner(c *gc.C) {
+	args := params.ModifyUserSSHKeys{
+		User: "not/valid",
+		Keys: []string{sshtesting.ValidKeyThree.Key},
+	}
+	_, err := s.keymanager.AddKeys(args)
+	c.Assert(err, gc.ErrorMatches, `user name "not/valid" not valid`)
+}
+
+func (s *keyManagerSuite) TestAddKeysV1(c *gc.C) {
+	key1 := sshtesting.ValidKeyOne.Key + " user@host"
+	s.setAuthorisedKeys(c, key1)
+	api, err := keymanager.NewKeyManagerAPIV1(s.State, s.resources, s.authoriser)
+	c.Assert(err, jc.ErrorIsNil)
+
+	newKey := sshtesting.ValidKeyThree.Key + " newuser@host"
+	args := params.ModifyUserSSHKeys{
+		User: s.AdminUserTag(c).Name(),
+		Keys: []string{newKey},
+	}
+	_, err = api.AddKeys(args)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertModelKeys(c, []string{key1, newKey})
+}
+
 func (s *keyManagerSuite) TestAddKeysSuperUser(c *gc.C) {
 	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "superuser-fred", NoModelUser: true})
 	s.assertAddKeys(c, s.State, user.UserTag(), true)
@@ -384,6 +427,23 @@ func (s *keyManagerSuite) TestBlockDeleteKeys(c *gc.C) {
 	s.assertModelKeys(c, initialKeys)
 }
 
+func (s *keyManagerSuite) TestDeleteOwnedKeyByComment(c *gc.C) {
+	key1 := sshtesting.ValidKeyOne.Key + " user@host"
+	key2 := sshtesting.ValidKeyTwo.Key + " another@host # juju-user bob"
+	s.setAuthorisedKeys(c, strings.Join([]string{key1, key2}, "\n"))
+
+	args := params.ModifyUserSSHKeys{
+		User: s.AdminUserTag(c).Name(),
+		Keys: []string{"another@host"},
+	}
+	results, err := s.keymanager.DeleteKeys(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{Error: nil}},
+	})
+	s.assertModelKeys(c, []string{key1})
+}
+
 func (s *keyManagerSuite) TestCannotDeleteAllKeys(c *gc.C) {
 	key1 := sshtesting.ValidKeyOne.Key + " user@host"
 	key2 := sshtesting.ValidKeyTwo.Key
@@ -496,7 +556,12 @@ func (s *keyManagerSuite) assertImportKeys(c *gc.C, st *state.State, apiUser nam
 			{Error: apiservertesting.ServerError(fmt.Sprintf("duplicate ssh key: %s", key2))},
 		},
 	})
-	s.assertKeysForModel(c, st, append(initialKeys, key3, keymv[0], keymv[1], keymp[0], key4))
+	owner := s.AdminUserTag(c).Id()
+	var imported []string
+	for _, key := range []string{key3, keymv[0], keymv[1], keymp[0], key4} {
+		imported = append(imported, sshkeys.WithOwner(key, owner))
+	}
+	s.assertKeysForModel(c, st, append(initialKeys, imported...))
 }
 
 func (s *keyManagerSuite) TestImportKeys(c *gc.C) {
@@ -0,0 +1,102 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leadershipreport
+
+import (
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/status"
+)
+
+// FlappingWindow is the period over which changes of leadership are
+// counted when reporting on leadership flapping.
+const FlappingWindow = time.Hour
+
+// API provides the LeadershipReport facade for v1.
+type API struct {
+	backend    Backend
+	authorizer facade.Authorizer
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(stateShim{ctx.State()}, ctx.Auth())
+}
+
+// NewAPI returns a new LeadershipReport API facade.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		authorizer: authorizer,
+	}, nil
+}
+
+func (api *API) checkCanRead() error {
+	allowed, err := api.authorizer.HasPermission(permission.ReadAccess, api.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !allowed {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// Leaders returns the current leader, leadership lease expiry and the
+// number of recent leadership changes of every application in the
+// model that has a leader, along with the controller's configured
+// leadership lease parameters.
+func (api *API) Leaders() (params.ApplicationLeadershipResults, error) {
+	var result params.ApplicationLeadershipResults
+	if err := api.checkCanRead(); err != nil {
+		return result, errors.Trace(err)
+	}
+	cfg, err := api.backend.ControllerConfig()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.LeaseDuration = cfg.LeadershipLeaseDuration().Seconds()
+	result.RenewalInterval = cfg.LeadershipRenewalInterval().Seconds()
+
+	details, err := api.backend.ApplicationLeadershipDetails()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	appNames := make([]string, 0, len(details))
+	for appName := range details {
+		appNames = append(appNames, appName)
+	}
+	sort.Strings(appNames)
+
+	window := FlappingWindow
+	result.Results = make([]params.ApplicationLeadershipResult, len(appNames))
+	for i, appName := range appNames {
+		detail := details[appName]
+		appResult := &result.Results[i]
+		appResult.ApplicationTag = names.NewApplicationTag(appName).String()
+		appResult.Leader = detail.Leader
+		appResult.Expiry = detail.Expiry
+
+		history, err := api.backend.LeadershipHistory(appName, status.StatusHistoryFilter{
+			Delta: &window,
+		})
+		if err != nil {
+			appResult.Error = common.ServerError(err)
+			continue
+		}
+		appResult.RecentChanges = len(history)
+	}
+	return result, nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leadershipreport_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/leadershipreport"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type LeadershipReportSuite struct {
+	testing.IsolationSuite
+	backend    mockBackend
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&LeadershipReportSuite{})
+
+func (s *LeadershipReportSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("admin"),
+	}
+	s.backend = mockBackend{
+		expiry: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func (s *LeadershipReportSuite) TestNewAPIRequiresClient(c *gc.C) {
+	s.authorizer.Tag = names.NewUnitTag("mysql/0")
+	_, err := leadershipreport.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *LeadershipReportSuite) TestLeaders(c *gc.C) {
+	api, err := leadershipreport.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.Leaders()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ApplicationLeadershipResults{
+		LeaseDuration:   60,
+		RenewalInterval: 30,
+		Results: []params.ApplicationLeadershipResult{{
+			ApplicationTag: "application-mysql",
+			Leader:         "mysql/1",
+			Expiry:         s.backend.expiry,
+			RecentChanges:  2,
+		}, {
+			ApplicationTag: "application-wordpress",
+			Leader:         "wordpress/0",
+			Expiry:         s.backend.expiry,
+			RecentChanges:  1,
+		}},
+	})
+	s.backend.CheckCall(c, 3, "LeadershipHistory", "mysql")
+	s.backend.CheckCall(c, 4, "LeadershipHistory", "wordpress")
+}
+
+func (s *LeadershipReportSuite) TestLeadersHistoryError(c *gc.C) {
+	s.backend.SetErrors(nil, nil, errors.New("boom"))
+	api, err := leadershipreport.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.Leaders()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, "boom")
+	c.Assert(result.Results[1].Error, gc.IsNil)
+}
+
+func (s *LeadershipReportSuite) TestLeadersPermissionDenied(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("fred")
+	api, err := leadershipreport.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.Leaders()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+type mockBackend struct {
+	testing.Stub
+	expiry time.Time
+}
+
+func (m *mockBackend) ModelTag() names.ModelTag {
+	m.MethodCall(m, "ModelTag")
+	return coretesting.ModelTag
+}
+
+func (m *mockBackend) ControllerConfig() (controller.Config, error) {
+	m.MethodCall(m, "ControllerConfig")
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return controller.Config{}, nil
+}
+
+func (m *mockBackend) ApplicationLeadershipDetails() (map[string]state.ApplicationLeadership, error) {
+	m.MethodCall(m, "ApplicationLeadershipDetails")
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return map[string]state.ApplicationLeadership{
+		"wordpress": {Leader: "wordpress/0", Expiry: m.expiry},
+		"mysql":     {Leader: "mysql/1", Expiry: m.expiry},
+	}, nil
+}
+
+func (m *mockBackend) LeadershipHistory(appName string, filter status.StatusHistoryFilter) ([]status.StatusInfo, error) {
+	m.MethodCall(m, "LeadershipHistory", appName)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	if appName == "mysql" {
+		return []status.StatusInfo{{}, {}}, nil
+	}
+	return []status.StatusInfo{{}}, nil
+}
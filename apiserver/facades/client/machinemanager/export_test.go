@@ -4,3 +4,5 @@
 package machinemanager
 
 var InstanceTypes = instanceTypes
+
+var InstanceMetadata = instanceMetadata
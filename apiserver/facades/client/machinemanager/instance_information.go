@@ -5,11 +5,13 @@ package machinemanager
 
 import (
 	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state/stateenvirons"
 )
 
@@ -21,13 +23,11 @@ func (mm *MachineManagerAPI) InstanceTypes(cons params.ModelInstanceTypesConstra
 
 type environGetFunc func(st environs.EnvironConfigGetter, newEnviron environs.NewEnvironFunc) (environs.Environ, error)
 
-func instanceTypes(mm *MachineManagerAPI,
-	getEnviron environGetFunc,
-	cons params.ModelInstanceTypesConstraints,
-) (params.InstanceTypesResults, error) {
+// modelEnviron returns the environ of the model managed by mm.
+func modelEnviron(mm *MachineManagerAPI, getEnviron environGetFunc) (environs.Environ, error) {
 	model, err := mm.st.Model()
 	if err != nil {
-		return params.InstanceTypesResults{}, errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 
 	cloudSpec := func() (environs.CloudSpec, error) {
@@ -40,8 +40,17 @@ func instanceTypes(mm *MachineManagerAPI,
 		CloudSpecFunc:   cloudSpec,
 		ModelConfigFunc: model.Config,
 	}
+	return getEnviron(backend, environs.New)
+}
 
-	env, err := getEnviron(backend, environs.New)
+func instanceTypes(mm *MachineManagerAPI,
+	getEnviron environGetFunc,
+	cons params.ModelInstanceTypesConstraints,
+) (params.InstanceTypesResults, error) {
+	env, err := modelEnviron(mm, getEnviron)
+	if err != nil {
+		return params.InstanceTypesResults{}, errors.Trace(err)
+	}
 	result := make([]params.InstanceTypesResult, len(cons.Constraints))
 	// TODO(perrito666) Cache the results to avoid excessive querying of the cloud.
 	for i, c := range cons.Constraints {
@@ -59,3 +68,91 @@ func instanceTypes(mm *MachineManagerAPI,
 
 	return params.InstanceTypesResults{Results: result}, nil
 }
+
+// InstanceMetadata returns the provider-specific metadata of the
+// instances of the given machines.
+func (mm *MachineManagerAPIV5) InstanceMetadata(args params.Entities) (params.InstanceMetadataResults, error) {
+	return instanceMetadata(mm.MachineManagerAPI, environs.GetEnviron, args)
+}
+
+func instanceMetadata(mm *MachineManagerAPI,
+	getEnviron environGetFunc,
+	args params.Entities,
+) (params.InstanceMetadataResults, error) {
+	if err := mm.checkCanRead(); err != nil {
+		return params.InstanceMetadataResults{}, err
+	}
+	env, err := modelEnviron(mm, getEnviron)
+	if err != nil {
+		return params.InstanceMetadataResults{}, errors.Trace(err)
+	}
+	getter, ok := environs.SupportsInstanceMetadata(env)
+	if !ok {
+		// Only the dummy provider reports instance metadata so far.
+		return params.InstanceMetadataResults{}, errors.NotSupportedf("instance metadata on %s", env.Config().Type())
+	}
+
+	results := make([]params.InstanceMetadataResult, len(args.Entities))
+	ids := make([]instance.Id, 0, len(args.Entities))
+	index := make([]int, 0, len(args.Entities))
+	for i, entity := range args.Entities {
+		id, err := mm.machineInstanceId(entity.Tag)
+		if err != nil {
+			results[i].Error = common.ServerError(err)
+			continue
+		}
+		ids = append(ids, id)
+		index = append(index, i)
+	}
+	if len(ids) == 0 {
+		return params.InstanceMetadataResults{Results: results}, nil
+	}
+
+	metadata, err := getter.InstanceMetadata(ids)
+	switch err {
+	case nil, environs.ErrPartialInstances:
+	case environs.ErrNoInstances:
+		metadata = make([]*environs.InstanceMetadata, len(ids))
+	default:
+		return params.InstanceMetadataResults{}, errors.Trace(err)
+	}
+	for i, md := range metadata {
+		if md == nil {
+			results[index[i]].Error = common.ServerError(errors.NotFoundf("instance %q", ids[i]))
+			continue
+		}
+		results[index[i]].Result = fromInstanceMetadata(md)
+	}
+	return params.InstanceMetadataResults{Results: results}, nil
+}
+
+func (mm *MachineManagerAPI) machineInstanceId(tag string) (instance.Id, error) {
+	machineTag, err := names.ParseMachineTag(tag)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	machine, err := mm.st.Machine(machineTag.Id())
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return machine.InstanceId()
+}
+
+func fromInstanceMetadata(md *environs.InstanceMetadata) *params.InstanceMetadata {
+	result := &params.InstanceMetadata{
+		InstanceId:      string(md.InstanceId),
+		InstanceProfile: md.InstanceProfile,
+		ImageId:         md.ImageId,
+		Tenancy:         md.Tenancy,
+	}
+	for _, iface := range md.NetworkInterfaces {
+		result.NetworkInterfaces = append(result.NetworkInterfaces, params.InstanceNetworkInterface{
+			ProviderId:       string(iface.ProviderId),
+			DeviceIndex:      iface.DeviceIndex,
+			MACAddress:       iface.MACAddress,
+			ProviderSubnetId: string(iface.ProviderSubnetId),
+			Addresses:        params.FromNetworkAddresses(iface.Addresses...),
+		})
+	}
+	return result
+}
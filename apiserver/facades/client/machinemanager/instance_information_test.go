@@ -18,7 +18,10 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/instances"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider/dummy"
+	coretesting "github.com/juju/juju/testing"
 )
 
 type instanceTypesSuite struct{}
@@ -80,10 +83,105 @@ func (p *instanceTypesSuite) TestInstanceTypes(c *gc.C) {
 	c.Assert(r.Results, gc.DeepEquals, expected)
 }
 
+func (p *instanceTypesSuite) TestInstanceMetadata(c *gc.C) {
+	backend := &mockBackend{
+		machines: map[string]*mockMachine{
+			"0": {instanceId: "inst-0"},
+			"1": {instanceId: "inst-1"},
+			"2": {},
+		},
+	}
+	authorizer := testing.FakeAuthorizer{Tag: names.NewUserTag("admin"),
+		Controller: true}
+	api, err := machinemanager.NewMachineManagerAPI(backend, &mockPool{}, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	env := mockEnviron{
+		metadata: map[instance.Id]*environs.InstanceMetadata{
+			"inst-0": {
+				InstanceId: "inst-0",
+				ImageId:    "ami-0",
+				Tenancy:    "dedicated",
+				NetworkInterfaces: []environs.InstanceNetworkInterface{{
+					ProviderId:       "eni-0",
+					MACAddress:       "aa:bb:cc:dd:ee:ff",
+					ProviderSubnetId: "subnet-0",
+					Addresses:        network.NewAddresses("10.0.0.1"),
+				}},
+			},
+		},
+	}
+	fakeEnvironGet := func(st environs.EnvironConfigGetter,
+		newEnviron environs.NewEnvironFunc,
+	) (environs.Environ, error) {
+		return &env, nil
+	}
+	r, err := machinemanager.InstanceMetadata(api, fakeEnvironGet, params.Entities{
+		Entities: []params.Entity{
+			{Tag: "machine-0"}, {Tag: "machine-1"}, {Tag: "machine-2"}, {Tag: "machine-3"}, {Tag: "unit-foo-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.Results, jc.DeepEquals, []params.InstanceMetadataResult{{
+		Result: &params.InstanceMetadata{
+			InstanceId: "inst-0",
+			ImageId:    "ami-0",
+			Tenancy:    "dedicated",
+			NetworkInterfaces: []params.InstanceNetworkInterface{{
+				ProviderId:       "eni-0",
+				MACAddress:       "aa:bb:cc:dd:ee:ff",
+				ProviderSubnetId: "subnet-0",
+				Addresses: []params.Address{{
+					Value: "10.0.0.1",
+					Type:  "ipv4",
+					Scope: "local-cloud",
+				}},
+			}},
+		},
+	}, {
+		Error: &params.Error{Message: `instance "inst-1" not found`, Code: "not found"},
+	}, {
+		Error: &params.Error{Message: `machine not provisioned`, Code: "not provisioned"},
+	}, {
+		Error: &params.Error{Message: `machine 3 not found`, Code: "not found"},
+	}, {
+		Error: &params.Error{Message: `"unit-foo-0" is not a valid machine tag`},
+	}})
+	c.Assert(env.Calls(), gc.HasLen, 1)
+	env.CheckCall(c, 0, "InstanceMetadata", []instance.Id{"inst-0", "inst-1"})
+}
+
+func (p *instanceTypesSuite) TestInstanceMetadataNotSupported(c *gc.C) {
+	authorizer := testing.FakeAuthorizer{Tag: names.NewUserTag("admin"),
+		Controller: true}
+	api, err := machinemanager.NewMachineManagerAPI(&mockBackend{}, &mockPool{}, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	fakeEnvironGet := func(st environs.EnvironConfigGetter,
+		newEnviron environs.NewEnvironFunc,
+	) (environs.Environ, error) {
+		return &mockBasicEnviron{}, nil
+	}
+	_, err = machinemanager.InstanceMetadata(api, fakeEnvironGet, params.Entities{
+		Entities: []params.Entity{{Tag: "machine-0"}},
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, "instance metadata on someprovider not supported")
+}
+
 type mockBackend struct {
 	machinemanager.Backend
 
 	cloudSpec environs.CloudSpec
+	machines  map[string]*mockMachine
+}
+
+func (b *mockBackend) Machine(id string) (machinemanager.Machine, error) {
+	m, ok := b.machines[id]
+	if !ok {
+		return nil, errors.NotFoundf("machine %v", id)
+	}
+	return m, nil
 }
 
 func (b *mockBackend) ModelTag() names.ModelTag {
@@ -118,7 +216,8 @@ type mockEnviron struct {
 	machinemanager.Backend
 	jujutesting.Stub
 
-	results map[constraints.Value]instances.InstanceTypesWithCostMetadata
+	results  map[constraints.Value]instances.InstanceTypesWithCostMetadata
+	metadata map[instance.Id]*environs.InstanceMetadata
 }
 
 func (m *mockEnviron) InstanceTypes(c constraints.Value) (instances.InstanceTypesWithCostMetadata, error) {
@@ -129,6 +228,28 @@ func (m *mockEnviron) InstanceTypes(c constraints.Value) (instances.InstanceType
 	return it, nil
 }
 
+func (m *mockEnviron) InstanceMetadata(ids []instance.Id) ([]*environs.InstanceMetadata, error) {
+	m.MethodCall(m, "InstanceMetadata", ids)
+	result := make([]*environs.InstanceMetadata, len(ids))
+	for i, id := range ids {
+		result[i] = m.metadata[id]
+	}
+	return result, environs.ErrPartialInstances
+}
+
+// mockBasicEnviron cannot report instance metadata.
+type mockBasicEnviron struct {
+	environs.Environ
+}
+
+func (*mockBasicEnviron) Config() *config.Config {
+	cfg, err := config.New(config.NoDefaults, coretesting.FakeConfig())
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
 type mockModel struct {
 	machinemanager.Model
 }
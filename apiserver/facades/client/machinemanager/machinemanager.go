@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils"
 	"github.com/juju/utils/set"
 	"gopkg.in/juju/names.v2"
 
@@ -57,6 +58,32 @@ func NewFacadeV4(ctx facade.Context) (*MachineManagerAPIV4, error) {
 	return &MachineManagerAPIV4{machineManagerAPI}, nil
 }
 
+type MachineManagerAPIV5 struct {
+	*MachineManagerAPIV4
+}
+
+// NewFacadeV5 creates a new server-side MachineManager API facade.
+func NewFacadeV5(ctx facade.Context) (*MachineManagerAPIV5, error) {
+	machineManagerAPIV4, err := NewFacadeV4(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &MachineManagerAPIV5{machineManagerAPIV4}, nil
+}
+
+type MachineManagerAPIV6 struct {
+	*MachineManagerAPIV5
+}
+
+// NewFacadeV6 creates a new server-side MachineManager API facade.
+func NewFacadeV6(ctx facade.Context) (*MachineManagerAPIV6, error) {
+	machineManagerAPIV5, err := NewFacadeV5(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &MachineManagerAPIV6{machineManagerAPIV5}, nil
+}
+
 // NewMachineManagerAPI creates a new server-side MachineManager API facade.
 func NewMachineManagerAPI(backend Backend, pool Pool, auth facade.Authorizer) (*MachineManagerAPI, error) {
 	if !auth.AuthClient() {
@@ -70,6 +97,17 @@ func NewMachineManagerAPI(backend Backend, pool Pool, auth facade.Authorizer) (*
 	}, nil
 }
 
+func (mm *MachineManagerAPI) checkCanRead() error {
+	canRead, err := mm.authorizer.HasPermission(permission.ReadAccess, mm.st.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !canRead {
+		return common.ErrPerm
+	}
+	return nil
+}
+
 func (mm *MachineManagerAPI) checkCanWrite() error {
 	canWrite, err := mm.authorizer.HasPermission(permission.WriteAccess, mm.st.ModelTag())
 	if err != nil {
@@ -330,3 +368,33 @@ func (mm *MachineManagerAPIV4) updateOneMachineSeries(arg params.UpdateSeriesArg
 	}
 	return machine.UpdateMachineSeries(arg.Series, arg.Force)
 }
+
+// PackageUpdates returns the package updates last reported by the
+// agents of the model's machines, ordered by machine id. Machines whose
+// agents have not reported are omitted.
+func (mm *MachineManagerAPIV6) PackageUpdates() (params.PackageUpdatesResults, error) {
+	if err := mm.checkCanRead(); err != nil {
+		return params.PackageUpdatesResults{}, err
+	}
+	all, err := mm.st.AllMachinePackageUpdates()
+	if err != nil {
+		return params.PackageUpdatesResults{}, errors.Trace(err)
+	}
+	ids := make([]string, 0, len(all))
+	for id := range all {
+		ids = append(ids, id)
+	}
+	utils.SortStringsNaturally(ids)
+	results := make([]params.PackageUpdates, len(ids))
+	for i, id := range ids {
+		updates := all[id]
+		results[i] = params.PackageUpdates{
+			Tag:            names.NewMachineTag(id).String(),
+			Pending:        updates.Pending,
+			Security:       updates.Security,
+			RebootRequired: updates.RebootRequired,
+			Updated:        updates.Updated,
+		}
+	}
+	return params.PackageUpdatesResults{Results: results}, nil
+}
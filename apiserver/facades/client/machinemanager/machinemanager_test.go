@@ -4,6 +4,8 @@
 package machinemanager_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	jtesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -14,6 +16,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/storage"
@@ -313,11 +316,47 @@ func (s *MachineManagerSuite) TestUpdateMachineSeriesPermissionDenied(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 }
 
+func (s *MachineManagerSuite) TestPackageUpdates(c *gc.C) {
+	now := time.Date(2018, 4, 3, 12, 0, 0, 0, time.UTC)
+	s.st.packageUpdates = map[string]state.MachinePackageUpdates{
+		"10": {Pending: 1, Updated: now},
+		"2":  {Pending: 5, Security: 2, RebootRequired: true, Updated: now},
+	}
+	apiV6 := machinemanager.MachineManagerAPIV6{
+		&machinemanager.MachineManagerAPIV5{&machinemanager.MachineManagerAPIV4{s.api}},
+	}
+	results, err := apiV6.PackageUpdates()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.PackageUpdatesResults{
+		Results: []params.PackageUpdates{{
+			Tag:            "machine-2",
+			Pending:        5,
+			Security:       2,
+			RebootRequired: true,
+			Updated:        now,
+		}, {
+			Tag:     "machine-10",
+			Pending: 1,
+			Updated: now,
+		}},
+	})
+}
+
+func (s *MachineManagerSuite) TestPackageUpdatesPermissionDenied(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("fred"))
+	apiV6 := machinemanager.MachineManagerAPIV6{
+		&machinemanager.MachineManagerAPIV5{&machinemanager.MachineManagerAPIV4{s.api}},
+	}
+	_, err := apiV6.PackageUpdates()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
 type mockState struct {
 	machinemanager.Backend
 	calls            int
 	machineTemplates []state.MachineTemplate
 	machines         map[string]*mockMachine
+	packageUpdates   map[string]state.MachinePackageUpdates
 	err              error
 	blockMsg         string
 	block            state.BlockType
@@ -362,6 +401,10 @@ func (st *mockState) Machine(id string) (machinemanager.Machine, error) {
 	}
 }
 
+func (st *mockState) AllMachinePackageUpdates() (map[string]state.MachinePackageUpdates, error) {
+	return st.packageUpdates, nil
+}
+
 func (st *mockState) StorageInstance(tag names.StorageTag) (state.StorageInstance, error) {
 	return &mockStorage{
 		tag:  tag,
@@ -415,8 +458,9 @@ type mockMachine struct {
 	jtesting.Stub
 	machinemanager.Machine
 
-	keep   bool
-	series string
+	keep       bool
+	series     string
+	instanceId instance.Id
 }
 
 func (m *mockMachine) Destroy() error {
@@ -437,6 +481,13 @@ func (m *mockMachine) Series() string {
 	return m.series
 }
 
+func (m *mockMachine) InstanceId() (instance.Id, error) {
+	if m.instanceId == "" {
+		return "", errors.NotProvisionedf("machine")
+	}
+	return m.instanceId, nil
+}
+
 func (m *mockMachine) Units() ([]machinemanager.Unit, error) {
 	return []machinemanager.Unit{
 		&mockUnit{names.NewUnitTag("foo/0")},
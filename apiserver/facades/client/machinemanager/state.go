@@ -25,6 +25,7 @@ type Backend interface {
 	AddOneMachine(template state.MachineTemplate) (*state.Machine, error)
 	AddMachineInsideNewMachine(template, parentTemplate state.MachineTemplate, containerType instance.ContainerType) (*state.Machine, error)
 	AddMachineInsideMachine(template state.MachineTemplate, parentId string, containerType instance.ContainerType) (*state.Machine, error)
+	AllMachinePackageUpdates() (map[string]state.MachinePackageUpdates, error)
 }
 
 type Pool interface {
@@ -44,6 +45,7 @@ type Machine interface {
 	Destroy() error
 	ForceDestroy() error
 	Series() string
+	InstanceId() (instance.Id, error)
 	Units() ([]Unit, error)
 	SetKeepInstance(keepInstance bool) error
 	UpdateMachineSeries(string, bool) error
@@ -0,0 +1,53 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinepool
+
+import (
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state functionality required by the machinepool
+// facade.
+type Backend interface {
+	// ModelTag returns the tag of the model.
+	ModelTag() names.ModelTag
+
+	// Machine returns the machine with the specified id.
+	Machine(id string) (Machine, error)
+
+	// PoolMachines returns the machines in the model's machine pool.
+	PoolMachines() ([]state.PoolMachine, error)
+}
+
+// Machine defines the machine functionality required by the
+// machinepool facade.
+type Machine interface {
+	// AddToPool adds the machine to the model's machine pool.
+	AddToPool() error
+
+	// RemoveFromPool removes the machine from the model's machine pool.
+	RemoveFromPool() error
+}
+
+// This type is an untested shim to let us wrap state in a sensible
+// interface and avoid writing tests that depend on mongodb. If you were
+// to change any part of it so that it were no longer *obviously* and
+// *trivially* correct, you would be Doing It Wrong.
+type stateShim struct {
+	*state.State
+}
+
+func (s stateShim) ModelTag() names.ModelTag {
+	return names.NewModelTag(s.State.ModelUUID())
+}
+
+func (s stateShim) Machine(id string) (Machine, error) {
+	m, err := s.State.Machine(id)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package machinepool provides the MachinePool facade, through which
+// operators register pre-provisioned machines in a model's machine
+// pool. Units being deployed are assigned to available pool machines
+// before new machines are provisioned.
+package machinepool
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+)
+
+const (
+	statusAvailable = "available"
+	statusAllocated = "allocated"
+)
+
+// API provides the MachinePool facade for v1.
+type API struct {
+	backend    Backend
+	authorizer facade.Authorizer
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(stateShim{ctx.State()}, ctx.Auth())
+}
+
+// NewAPI returns a new MachinePool API facade.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		authorizer: authorizer,
+	}, nil
+}
+
+func (api *API) checkPermission(access permission.Access) error {
+	allowed, err := api.authorizer.HasPermission(access, api.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !allowed {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// Add adds the specified machines to the model's machine pool.
+func (api *API) Add(args params.Entities) (params.ErrorResults, error) {
+	return api.update(args, Machine.AddToPool)
+}
+
+// Remove removes the specified machines from the model's machine pool.
+// Units already placed on the machines are not affected.
+func (api *API) Remove(args params.Entities) (params.ErrorResults, error) {
+	return api.update(args, Machine.RemoveFromPool)
+}
+
+func (api *API) update(args params.Entities, op func(Machine) error) (params.ErrorResults, error) {
+	if err := api.checkPermission(permission.WriteAccess); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		m, err := api.backend.Machine(tag.Id())
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Error = common.ServerError(op(m))
+	}
+	return results, nil
+}
+
+// List returns the machines in the model's machine pool.
+func (api *API) List() (params.MachinePoolResult, error) {
+	var result params.MachinePoolResult
+	if err := api.checkPermission(permission.ReadAccess); err != nil {
+		return result, errors.Trace(err)
+	}
+	machines, err := api.backend.PoolMachines()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Machines = make([]params.PoolMachine, len(machines))
+	for i, m := range machines {
+		pm := params.PoolMachine{
+			MachineTag: names.NewMachineTag(m.MachineId).String(),
+			Status:     statusAvailable,
+		}
+		if !m.Available() {
+			pm.Status = statusAllocated
+			pm.UnitTag = names.NewUnitTag(m.Unit).String()
+		}
+		result.Machines[i] = pm
+	}
+	return result, nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinepool_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/machinepool"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type MachinePoolSuite struct {
+	testing.IsolationSuite
+	backend    mockBackend
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&MachinePoolSuite{})
+
+func (s *MachinePoolSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("admin"),
+	}
+	s.backend = mockBackend{
+		machines: map[string]*mockMachine{
+			"0": {},
+			"1": {},
+		},
+		pool: []state.PoolMachine{
+			{MachineId: "0"},
+			{MachineId: "1", Unit: "mysql/0"},
+		},
+	}
+}
+
+func (s *MachinePoolSuite) TestNewAPIRequiresClient(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("0")
+	_, err := machinepool.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *MachinePoolSuite) TestList(c *gc.C) {
+	api, err := machinepool.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.List()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.MachinePoolResult{
+		Machines: []params.PoolMachine{
+			{MachineTag: "machine-0", Status: "available"},
+			{MachineTag: "machine-1", Status: "allocated", UnitTag: "unit-mysql-0"},
+		},
+	})
+}
+
+func (s *MachinePoolSuite) TestListPermissionDenied(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("fred")
+	api, err := machinepool.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.List()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "ModelTag")
+}
+
+func (s *MachinePoolSuite) TestAdd(c *gc.C) {
+	s.backend.machines["1"].SetErrors(errors.AlreadyExistsf("machine 1 in pool"))
+	api, err := machinepool.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.Add(params.Entities{Entities: []params.Entity{
+		{Tag: "machine-0"}, {Tag: "machine-1"}, {Tag: "machine-2"}, {Tag: "unit-mysql-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 4)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, jc.Satisfies, params.IsCodeAlreadyExists)
+	c.Assert(result.Results[2].Error, jc.Satisfies, params.IsCodeNotFound)
+	c.Assert(result.Results[3].Error, gc.ErrorMatches, `"unit-mysql-0" is not a valid machine tag`)
+	s.backend.machines["0"].CheckCallNames(c, "AddToPool")
+	s.backend.machines["1"].CheckCallNames(c, "AddToPool")
+}
+
+func (s *MachinePoolSuite) TestRemove(c *gc.C) {
+	api, err := machinepool.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.Remove(params.Entities{Entities: []params.Entity{{Tag: "machine-1"}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), jc.ErrorIsNil)
+	s.backend.machines["1"].CheckCallNames(c, "RemoveFromPool")
+}
+
+func (s *MachinePoolSuite) TestAddRequiresWriteAccess(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("fred")
+	api, err := machinepool.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.Add(params.Entities{Entities: []params.Entity{{Tag: "machine-0"}}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.machines["0"].CheckNoCalls(c)
+}
+
+type mockBackend struct {
+	testing.Stub
+	machines map[string]*mockMachine
+	pool     []state.PoolMachine
+}
+
+func (m *mockBackend) ModelTag() names.ModelTag {
+	m.MethodCall(m, "ModelTag")
+	return coretesting.ModelTag
+}
+
+func (m *mockBackend) Machine(id string) (machinepool.Machine, error) {
+	m.MethodCall(m, "Machine", id)
+	machine, ok := m.machines[id]
+	if !ok {
+		return nil, errors.NotFoundf("machine %s", id)
+	}
+	return machine, nil
+}
+
+func (m *mockBackend) PoolMachines() ([]state.PoolMachine, error) {
+	m.MethodCall(m, "PoolMachines")
+	return m.pool, m.NextErr()
+}
+
+type mockMachine struct {
+	testing.Stub
+}
+
+func (m *mockMachine) AddToPool() error {
+	m.MethodCall(m, "AddToPool")
+	return m.NextErr()
+}
+
+func (m *mockMachine) RemoveFromPool() error {
+	m.MethodCall(m, "RemoveFromPool")
+	return m.NextErr()
+}
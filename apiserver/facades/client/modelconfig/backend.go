@@ -4,11 +4,14 @@
 package modelconfig
 
 import (
+	"github.com/juju/errors"
 	names "gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/stateenvirons"
 )
 
 // Backend contains the state.State methods used in this package,
@@ -18,9 +21,12 @@ type Backend interface {
 	ControllerTag() names.ControllerTag
 	ModelTag() names.ModelTag
 	ModelConfigValues() (config.ConfigValues, error)
-	UpdateModelConfig(map[string]interface{}, []string, ...state.ValidateConfigFunc) error
+	UpdateModelConfigBy(names.UserTag, map[string]interface{}, []string, ...state.ValidateConfigFunc) error
+	ModelConfigHistory(key string) ([]state.ModelConfigChange, error)
 	SetSLA(level, owner string, credentials []byte) error
 	SLALevel() (string, error)
+	ConfigDocs() (config.Docs, error)
+	Environ() (environs.Environ, error)
 }
 
 type stateShim struct {
@@ -28,14 +34,34 @@ type stateShim struct {
 	model *state.Model
 }
 
-func (st stateShim) UpdateModelConfig(u map[string]interface{}, r []string, a ...state.ValidateConfigFunc) error {
-	return st.model.UpdateModelConfig(u, r, a...)
+func (st stateShim) UpdateModelConfigBy(user names.UserTag, u map[string]interface{}, r []string, a ...state.ValidateConfigFunc) error {
+	return st.model.UpdateModelConfigBy(user, u, r, a...)
+}
+
+func (st stateShim) ModelConfigHistory(key string) ([]state.ModelConfigChange, error) {
+	return st.model.ModelConfigHistory(key)
 }
 
 func (st stateShim) ModelConfigValues() (config.ConfigValues, error) {
 	return st.model.ModelConfigValues()
 }
 
+func (st stateShim) ConfigDocs() (config.Docs, error) {
+	cfg, err := st.model.Config()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	provider, err := environs.Provider(cfg.Type())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return environs.ConfigDocs(provider)
+}
+
+func (st stateShim) Environ() (environs.Environ, error) {
+	return stateenvirons.GetNewEnvironFunc(environs.New)(st.State)
+}
+
 func (st stateShim) ModelTag() names.ModelTag {
 	m, err := st.State.Model()
 	if err != nil {
@@ -6,17 +6,20 @@ package modelconfig
 import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/dns"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 )
 
-// NewFacade is used for API registration.
-func NewFacade(st *state.State, _ facade.Resources, auth facade.Authorizer) (*ModelConfigAPI, error) {
+// NewFacadeV5 is used for API registration.
+func NewFacadeV5(st *state.State, _ facade.Resources, auth facade.Authorizer) (*ModelConfigAPI, error) {
 	model, err := st.Model()
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -24,6 +27,42 @@ func NewFacade(st *state.State, _ facade.Resources, auth facade.Authorizer) (*Mo
 	return NewModelConfigAPI(NewStateBackend(model), auth)
 }
 
+// NewFacadeV4 is used for API registration.
+func NewFacadeV4(st *state.State, resources facade.Resources, auth facade.Authorizer) (*ModelConfigAPIV4, error) {
+	api, err := NewFacadeV5(st, resources, auth)
+	if err != nil {
+		return nil, err
+	}
+	return &ModelConfigAPIV4{api}, nil
+}
+
+// NewFacadeV3 is used for API registration.
+func NewFacadeV3(st *state.State, resources facade.Resources, auth facade.Authorizer) (*ModelConfigAPIV3, error) {
+	api, err := NewFacadeV4(st, resources, auth)
+	if err != nil {
+		return nil, err
+	}
+	return &ModelConfigAPIV3{api}, nil
+}
+
+// NewFacadeV2 is used for API registration.
+func NewFacadeV2(st *state.State, resources facade.Resources, auth facade.Authorizer) (*ModelConfigAPIV2, error) {
+	api, err := NewFacadeV3(st, resources, auth)
+	if err != nil {
+		return nil, err
+	}
+	return &ModelConfigAPIV2{api}, nil
+}
+
+// NewFacadeV1 is used for API registration.
+func NewFacadeV1(st *state.State, resources facade.Resources, auth facade.Authorizer) (*ModelConfigAPIV1, error) {
+	api, err := NewFacadeV3(st, resources, auth)
+	if err != nil {
+		return nil, err
+	}
+	return &ModelConfigAPIV1{api}, nil
+}
+
 // ModelConfigAPI is the endpoint which implements the model config facade.
 type ModelConfigAPI struct {
 	backend Backend
@@ -31,6 +70,30 @@ type ModelConfigAPI struct {
 	check   *common.BlockChecker
 }
 
+// ModelConfigAPIV4 does not report deprecated attributes set by
+// ModelSet, as before version 5 of the model config facade.
+type ModelConfigAPIV4 struct {
+	*ModelConfigAPI
+}
+
+// ModelConfigAPIV3 returns ModelSet failures as a plain error, as
+// before version 4 of the model config facade.
+type ModelConfigAPIV3 struct {
+	*ModelConfigAPIV4
+}
+
+// ModelConfigAPIV2 hides the methods added in version 3 of the model
+// config facade.
+type ModelConfigAPIV2 struct {
+	*ModelConfigAPIV3
+}
+
+// ModelConfigAPIV1 hides the methods added in versions 2 and 3 of the
+// model config facade.
+type ModelConfigAPIV1 struct {
+	*ModelConfigAPIV3
+}
+
 // NewModelConfigAPI creates a new instance of the ModelConfig Facade.
 func NewModelConfigAPI(backend Backend, authorizer facade.Authorizer) (*ModelConfigAPI, error) {
 	if !authorizer.AuthClient() {
@@ -82,7 +145,11 @@ func (c *ModelConfigAPI) canReadModel() error {
 }
 
 // ModelGet implements the server-side part of the
-// model-config CLI command.
+// model-config CLI command. Each value is reported with its
+// source: whether it is a Juju default, inherited from the
+// controller, cloud or region defaults, or set on the model.
+// The values of attributes that the provider's config schema
+// marks as secret are masked.
 func (c *ModelConfigAPI) ModelGet() (params.ModelConfigResults, error) {
 	result := params.ModelConfigResults{}
 	if err := c.canReadModel(); err != nil {
@@ -93,6 +160,15 @@ func (c *ModelConfigAPI) ModelGet() (params.ModelConfigResults, error) {
 	if err != nil {
 		return result, errors.Trace(err)
 	}
+	docs, err := c.backend.ConfigDocs()
+	if errors.IsNotSupported(err) {
+		// Without the provider's schema, only the
+		// attributes common to all providers are known.
+		docs, err = config.SchemaDocs(nil, nil)
+	}
+	if err != nil {
+		return result, errors.Trace(err)
+	}
 
 	result.Config = make(map[string]params.ConfigValue)
 	for attr, val := range values {
@@ -102,6 +178,9 @@ func (c *ModelConfigAPI) ModelGet() (params.ModelConfigResults, error) {
 		if attr == config.AuthorizedKeysKey {
 			continue
 		}
+		if docs[attr].Secret && val.Value != nil && val.Value != "" {
+			val.Value = config.MaskedValue
+		}
 		result.Config[attr] = params.ConfigValue{
 			Value:  val.Value,
 			Source: val.Source,
@@ -112,13 +191,17 @@ func (c *ModelConfigAPI) ModelGet() (params.ModelConfigResults, error) {
 
 // ModelSet implements the server-side part of the
 // set-model-config CLI command.
-func (c *ModelConfigAPI) ModelSet(args params.ModelSet) error {
+//
+// A config that is not valid is reported in the result, rather than
+// as an error, so that the reasons it is not valid reach the client.
+// The result also holds a warning for each deprecated attribute set.
+func (c *ModelConfigAPI) ModelSet(args params.ModelSet) (params.ModelSetResult, error) {
 	if err := c.checkCanWrite(); err != nil {
-		return err
+		return params.ModelSetResult{}, err
 	}
 
 	if err := c.check.ChangeAllowed(); err != nil {
-		return errors.Trace(err)
+		return params.ModelSetResult{}, errors.Trace(err)
 	}
 	// Make sure we don't allow changing agent-version.
 	checkAgentVersion := func(updateAttrs map[string]interface{}, removeAttrs []string, oldConfig *config.Config) error {
@@ -161,9 +244,63 @@ func (c *ModelConfigAPI) ModelSet(args params.ModelSet) error {
 		return nil
 	}
 
+	// Features needing the provider's support can only be enabled on
+	// clouds that have it.
+	checkProviderSupport := func(updateAttrs map[string]interface{}, removeAttrs []string, oldConfig *config.Config) error {
+		_, setLoadBalancerType := updateAttrs[config.LoadBalancerTypeKey]
+		_, setDNSProvider := updateAttrs[config.DNSProviderKey]
+		_, setContainerNetworking := updateAttrs[config.ContainerNetworkingMethod]
+		if !setLoadBalancerType && !setDNSProvider && !setContainerNetworking {
+			return nil
+		}
+		newConfig, err := oldConfig.Apply(updateAttrs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := dns.ValidateProvider(newConfig.DNSProvider()); err != nil {
+			return errors.Trace(err)
+		}
+		if !setLoadBalancerType && !setContainerNetworking {
+			return nil
+		}
+		env, err := c.backend.Environ()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := environs.ValidateContainerNetworkingMethod(env, newConfig.ContainerNetworkingMethod()); err != nil {
+			return errors.Trace(err)
+		}
+		return environs.ValidateLoadBalancerType(env, newConfig.LoadBalancerType())
+	}
+
 	// Replace any deprecated attributes with their new values.
 	attrs := config.ProcessDeprecatedAttributes(args.Config)
-	return c.backend.UpdateModelConfig(attrs, nil, checkAgentVersion, checkLogTrace)
+	err := c.backend.UpdateModelConfigBy(c.authUser(), attrs, nil, checkAgentVersion, checkLogTrace, checkProviderSupport)
+	return params.ModelSetResult{
+		Error:    common.ServerError(err),
+		Warnings: config.DeprecationWarnings(args.Config),
+	}, nil
+}
+
+// ModelSet on the v4 API does not report deprecated attributes.
+func (c *ModelConfigAPIV4) ModelSet(args params.ModelSet) (params.ErrorResult, error) {
+	result, err := c.ModelConfigAPI.ModelSet(args)
+	if err != nil {
+		return params.ErrorResult{}, err
+	}
+	return params.ErrorResult{Error: result.Error}, nil
+}
+
+// ModelSet on the v3 API returns any failure as an error.
+func (c *ModelConfigAPIV3) ModelSet(args params.ModelSet) error {
+	result, err := c.ModelConfigAPIV4.ModelSet(args)
+	if err != nil {
+		return err
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
 }
 
 // ModelUnset implements the server-side part of the
@@ -175,9 +312,86 @@ func (c *ModelConfigAPI) ModelUnset(args params.ModelUnset) error {
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
-	return c.backend.UpdateModelConfig(nil, args.Keys)
+	return c.backend.UpdateModelConfigBy(c.authUser(), nil, args.Keys)
+}
+
+// authUser returns the tag of the user making the request, which is
+// recorded in the model config history.
+func (c *ModelConfigAPI) authUser() names.UserTag {
+	user, _ := c.auth.GetAuthTag().(names.UserTag)
+	return user
+}
+
+// ModelConfigHistory returns the recorded changes to the model's
+// config, most recent first. The values of attributes that the
+// provider's config schema marks as secret are redacted.
+func (c *ModelConfigAPI) ModelConfigHistory(args params.ModelConfigHistoryArgs) (params.ModelConfigHistoryResult, error) {
+	result := params.ModelConfigHistoryResult{}
+	if err := c.canReadModel(); err != nil {
+		return result, errors.Trace(err)
+	}
+	changes, err := c.backend.ModelConfigHistory(args.Key)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	docs, err := c.backend.ConfigDocs()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Changes = make([]params.ModelConfigChange, len(changes))
+	for i, change := range changes {
+		paramsChange := params.ModelConfigChange{
+			Time: change.Time,
+			User: change.User,
+			Key:  change.Key,
+		}
+		if docs[change.Key].Secret {
+			paramsChange.Redacted = true
+		} else {
+			paramsChange.Old = change.Old
+			paramsChange.New = change.New
+		}
+		result.Changes[i] = paramsChange
+	}
+	return result, nil
+}
+
+// ModelConfigHistory isn't on the v2 API.
+func (c *ModelConfigAPIV2) ModelConfigHistory(_, _ struct{}) {}
+
+// ModelConfigHistory isn't on the v1 API.
+func (c *ModelConfigAPIV1) ModelConfigHistory(_, _ struct{}) {}
+
+// ConfigSchema returns documentation for the config fields of the
+// model's provider, including any guidance the provider attaches to
+// its own fields.
+func (c *ModelConfigAPI) ConfigSchema() (params.ConfigSchemaResult, error) {
+	result := params.ConfigSchemaResult{}
+	if err := c.canReadModel(); err != nil {
+		return result, errors.Trace(err)
+	}
+	docs, err := c.backend.ConfigDocs()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Fields = make(map[string]params.ConfigSchemaField)
+	for name, doc := range docs {
+		result.Fields[name] = params.ConfigSchemaField{
+			Description: doc.Description,
+			Type:        string(doc.Type),
+			Group:       string(doc.Group),
+			Immutable:   doc.Immutable,
+			Example:     doc.Example,
+			Values:      doc.Values,
+			Deprecated:  doc.Deprecated,
+		}
+	}
+	return result, nil
 }
 
+// ConfigSchema isn't on the v1 API.
+func (c *ModelConfigAPIV1) ConfigSchema(_, _ struct{}) {}
+
 // SetSLALevel sets the sla level on the model.
 func (c *ModelConfigAPI) SetSLALevel(args params.ModelSLA) error {
 	if err := c.checkCanWrite(); err != nil {
@@ -216,6 +216,7 @@ func (s *modelInfoSuite) TestModelInfo(c *gc.C) {
 	s.st.model.CheckCalls(c, []gitjujutesting.StubCall{
 		{"UUID", nil},
 		{"Owner", nil},
+		{"OwnerTeam", nil},
 		{"Name", nil},
 		{"UUID", nil},
 		{"ControllerUUID", nil},
@@ -237,9 +238,37 @@ func (s *modelInfoSuite) TestModelInfo(c *gc.C) {
 		{"LastModelConnection", []interface{}{names.NewLocalUserTag("bob")}},
 		{"LastModelConnection", []interface{}{names.NewLocalUserTag("charlotte")}},
 		{"LastModelConnection", []interface{}{names.NewLocalUserTag("mary")}},
+		{"OwnershipTransfers", nil},
 	})
 }
 
+func (s *modelInfoSuite) TestModelInfoOwnershipTransfers(c *gc.C) {
+	transferred := time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.st.model.ownerTeam = "ops"
+	s.st.model.transfers = []state.ModelOwnershipTransfer{{
+		Time:     transferred,
+		By:       names.NewUserTag("admin"),
+		FromUser: names.NewUserTag("bob"),
+		ToUser:   names.NewUserTag("bob"),
+		ToTeam:   "ops",
+	}}
+	info := s.getModelInfo(c, s.st.model.cfg.UUID())
+	c.Assert(info.OwnerTeam, gc.Equals, "ops")
+	c.Assert(info.OwnershipTransfers, jc.DeepEquals, []params.ModelOwnershipTransfer{{
+		Time:    transferred,
+		ByTag:   "user-admin",
+		FromTag: "user-bob",
+		ToTag:   "user-bob",
+		ToTeam:  "ops",
+	}})
+
+	mary := names.NewUserTag("mary@local")
+	s.authorizer.HasWriteTag = mary
+	s.setAPIUser(c, mary)
+	info = s.getModelInfo(c, s.st.model.cfg.UUID())
+	c.Assert(info.OwnershipTransfers, gc.HasLen, 0)
+}
+
 func (s *modelInfoSuite) TestModelInfoOwner(c *gc.C) {
 	s.setAPIUser(c, names.NewUserTag("bob@local"))
 	info := s.getModelInfo(c, s.st.model.cfg.UUID())
@@ -673,6 +702,17 @@ func (st *mockState) ComposeNewModelConfig(modelAttr map[string]interface{}, reg
 	return attr, st.NextErr()
 }
 
+func (st *mockState) ConfigProfile(name string) (state.ConfigProfile, error) {
+	st.MethodCall(st, "ConfigProfile", name)
+	return state.ConfigProfile{
+		Name: name,
+		Config: map[string]interface{}{
+			"bar":  "profile",
+			"quux": "profile",
+		},
+	}, st.NextErr()
+}
+
 func (st *mockState) ControllerUUID() string {
 	st.MethodCall(st, "ControllerUUID")
 	return st.controllerUUID
@@ -909,6 +949,8 @@ func (m *mockMachine) Status() (status.StatusInfo, error) {
 type mockModel struct {
 	gitjujutesting.Stub
 	owner           names.UserTag
+	ownerTeam       string
+	transfers       []state.ModelOwnershipTransfer
 	life            state.Life
 	tag             names.ModelTag
 	status          status.StatusInfo
@@ -929,6 +971,31 @@ func (m *mockModel) Owner() names.UserTag {
 	return m.owner
 }
 
+func (m *mockModel) OwnerTeam() (string, bool) {
+	m.MethodCall(m, "OwnerTeam")
+	return m.ownerTeam, m.ownerTeam != ""
+}
+
+func (m *mockModel) OwnershipTransfers() ([]state.ModelOwnershipTransfer, error) {
+	m.MethodCall(m, "OwnershipTransfers")
+	return m.transfers, m.NextErr()
+}
+
+func (m *mockModel) TransferOwnership(owner, by names.UserTag) error {
+	m.MethodCall(m, "TransferOwnership", owner, by)
+	return m.NextErr()
+}
+
+func (m *mockModel) TransferOwnershipToTeam(team string, by names.UserTag) error {
+	m.MethodCall(m, "TransferOwnershipToTeam", team, by)
+	return m.NextErr()
+}
+
+func (m *mockModel) SetEphemeralExpiry(expiry time.Time) error {
+	m.MethodCall(m, "SetEphemeralExpiry", expiry)
+	return m.NextErr()
+}
+
 func (m *mockModel) ModelTag() names.ModelTag {
 	m.MethodCall(m, "ModelTag")
 	return m.tag
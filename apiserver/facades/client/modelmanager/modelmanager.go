@@ -28,6 +28,7 @@ import (
 	"github.com/juju/juju/controller/modelmanager"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/dns"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/stateenvirons"
@@ -37,6 +38,48 @@ import (
 
 var logger = loggo.GetLogger("juju.apiserver.modelmanager")
 
+// ModelManagerV7 defines the methods on the version 7 facade for the
+// modelmanager API endpoint.
+type ModelManagerV7 interface {
+	CreateModel(args params.ModelCreateArgs) (params.ModelInfo, error)
+	DumpModels(args params.DumpModelRequest) params.StringResults
+	DumpModelsDB(args params.Entities) params.MapResults
+	ListModelSummaries(request params.ModelSummariesRequest) (params.ModelSummaryResults, error)
+	ListModels(user params.Entity) (params.UserModelList, error)
+	DestroyModels(args params.DestroyModelsParams) (params.ErrorResults, error)
+	ModelInfo(args params.Entities) (params.ModelInfoResults, error)
+	ModelStatus(req params.Entities) (params.ModelStatusResults, error)
+	TransferModelOwnership(args params.TransferModelOwnershipRequest) (params.ErrorResults, error)
+	SetModelExpiry(args params.SetModelExpiryArgs) (params.ErrorResults, error)
+}
+
+// ModelManagerV6 defines the methods on the version 6 facade for the
+// modelmanager API endpoint.
+type ModelManagerV6 interface {
+	CreateModel(args params.ModelCreateArgs) (params.ModelInfo, error)
+	DumpModels(args params.DumpModelRequest) params.StringResults
+	DumpModelsDB(args params.Entities) params.MapResults
+	ListModelSummaries(request params.ModelSummariesRequest) (params.ModelSummaryResults, error)
+	ListModels(user params.Entity) (params.UserModelList, error)
+	DestroyModels(args params.DestroyModelsParams) (params.ErrorResults, error)
+	ModelInfo(args params.Entities) (params.ModelInfoResults, error)
+	ModelStatus(req params.Entities) (params.ModelStatusResults, error)
+	TransferModelOwnership(args params.TransferModelOwnershipRequest) (params.ErrorResults, error)
+}
+
+// ModelManagerV5 defines the methods on the version 5 facade for the
+// modelmanager API endpoint.
+type ModelManagerV5 interface {
+	CreateModel(args params.ModelCreateArgs) (params.ModelInfo, error)
+	DumpModels(args params.DumpModelRequest) params.StringResults
+	DumpModelsDB(args params.Entities) params.MapResults
+	ListModelSummaries(request params.ModelSummariesRequest) (params.ModelSummaryResults, error)
+	ListModels(user params.Entity) (params.UserModelList, error)
+	DestroyModels(args params.DestroyModelsParams) (params.ErrorResults, error)
+	ModelInfo(args params.Entities) (params.ModelInfoResults, error)
+	ModelStatus(req params.Entities) (params.ModelStatusResults, error)
+}
+
 // ModelManagerV4 defines the methods on the version 2 facade for the
 // modelmanager API endpoint.
 type ModelManagerV4 interface {
@@ -87,10 +130,28 @@ type ModelManagerAPI struct {
 	model       common.Model
 }
 
-// ModelManagerAPIV2 provides a way to wrap the different calls between
+// ModelManagerAPIV6 provides a way to wrap the different calls between
+// version 6 and version 7 of the model manager API
+type ModelManagerAPIV6 struct {
+	*ModelManagerAPI
+}
+
+// ModelManagerAPIV5 provides a way to wrap the different calls between
+// version 5 and version 6 of the model manager API
+type ModelManagerAPIV5 struct {
+	*ModelManagerAPIV6
+}
+
+// ModelManagerAPIV4 provides a way to wrap the different calls between
+// version 4 and version 5 of the model manager API
+type ModelManagerAPIV4 struct {
+	*ModelManagerAPIV5
+}
+
+// ModelManagerAPIV3 provides a way to wrap the different calls between
 // version 3 and version 4 of the model manager API
 type ModelManagerAPIV3 struct {
-	*ModelManagerAPI
+	*ModelManagerAPIV4
 }
 
 // ModelManagerAPIV2 provides a way to wrap the different calls between
@@ -100,13 +161,16 @@ type ModelManagerAPIV2 struct {
 }
 
 var (
-	_ ModelManagerV4 = (*ModelManagerAPI)(nil)
+	_ ModelManagerV7 = (*ModelManagerAPI)(nil)
+	_ ModelManagerV6 = (*ModelManagerAPIV6)(nil)
+	_ ModelManagerV5 = (*ModelManagerAPIV5)(nil)
+	_ ModelManagerV4 = (*ModelManagerAPIV4)(nil)
 	_ ModelManagerV3 = (*ModelManagerAPIV3)(nil)
 	_ ModelManagerV2 = (*ModelManagerAPIV2)(nil)
 )
 
-// NewFacadeV4 is used for API registration.
-func NewFacadeV4(ctx facade.Context) (*ModelManagerAPI, error) {
+// NewFacadeV7 is used for API registration.
+func NewFacadeV7(ctx facade.Context) (*ModelManagerAPI, error) {
 	st := ctx.State()
 	pool := ctx.StatePool()
 	ctlrSt := pool.SystemState()
@@ -134,6 +198,33 @@ func NewFacadeV4(ctx facade.Context) (*ModelManagerAPI, error) {
 	)
 }
 
+// NewFacadeV6 is used for API registration.
+func NewFacadeV6(ctx facade.Context) (*ModelManagerAPIV6, error) {
+	v7, err := NewFacadeV7(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &ModelManagerAPIV6{v7}, nil
+}
+
+// NewFacadeV5 is used for API registration.
+func NewFacadeV5(ctx facade.Context) (*ModelManagerAPIV5, error) {
+	v6, err := NewFacadeV6(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &ModelManagerAPIV5{v6}, nil
+}
+
+// NewFacadeV4 is used for API registration.
+func NewFacadeV4(ctx facade.Context) (*ModelManagerAPIV4, error) {
+	v5, err := NewFacadeV5(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &ModelManagerAPIV4{v5}, nil
+}
+
 // NewFacadeV3 is used for API registration.
 func NewFacadeV3(ctx facade.Context) (*ModelManagerAPIV3, error) {
 	v4, err := NewFacadeV4(ctx)
@@ -221,12 +312,18 @@ type ConfigSource interface {
 func (m *ModelManagerAPI) newModelConfig(
 	cloudSpec environs.CloudSpec,
 	args params.ModelCreateArgs,
+	profile *state.ConfigProfile,
 	source ConfigSource,
 ) (*config.Config, error) {
 	// For now, we just smash to the two maps together as we store
 	// the account values and the model config together in the
 	// *config.Config instance.
 	joint := make(map[string]interface{})
+	if profile != nil {
+		for key, value := range profile.Config {
+			joint[key] = value
+		}
+	}
 	for key, value := range args.Config {
 		joint[key] = value
 	}
@@ -266,6 +363,27 @@ func (m *ModelManagerAPI) newModelConfig(
 	return creator.NewModelConfig(cloudSpec, baseConfig, joint)
 }
 
+// configProfile returns the config profile named in the args, holding
+// only the values that are not overridden by the args' config, or nil
+// if no profile is named.
+func (m *ModelManagerAPI) configProfile(args params.ModelCreateArgs) (*state.ConfigProfile, error) {
+	if args.Profile == "" {
+		return nil, nil
+	}
+	profile, err := m.state.ConfigProfile(args.Profile)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	applied := make(map[string]interface{})
+	for key, value := range profile.Config {
+		if _, ok := args.Config[key]; !ok {
+			applied[key] = value
+		}
+	}
+	profile.Config = applied
+	return &profile, nil
+}
+
 func (m *ModelManagerAPI) newCAASModelConfig(
 	cloudSpec environs.CloudSpec,
 	args params.ModelCreateArgs,
@@ -406,6 +524,9 @@ func (m *ModelManagerAPI) CreateModel(args params.ModelCreateArgs) (params.Model
 	var model common.Model
 
 	if jujucloud.CloudIsCAAS(cloud) {
+		if args.Profile != "" {
+			return result, errors.NotSupportedf("config profiles for CAAS models")
+		}
 		model, err = m.newCAASModel(
 			cloudSpec,
 			args,
@@ -428,6 +549,15 @@ func (m *ModelManagerAPI) CreateModel(args params.ModelCreateArgs) (params.Model
 	return m.getModelInfo(model.ModelTag())
 }
 
+// ephemeralExpiry returns the expiry requested for a new model, or the
+// zero time if the model is to be persistent.
+func ephemeralExpiry(args params.ModelCreateArgs) time.Time {
+	if args.EphemeralExpiry == nil {
+		return time.Time{}
+	}
+	return *args.EphemeralExpiry
+}
+
 func (m *ModelManagerAPI) newCAASModel(cloudSpec environs.CloudSpec,
 	createArgs params.ModelCreateArgs,
 	cloudTag names.CloudTag,
@@ -445,6 +575,7 @@ func (m *ModelManagerAPI) newCAASModel(cloudSpec environs.CloudSpec,
 		CloudCredential: cloudCredentialTag,
 		Config:          newConfig,
 		Owner:           ownerTag,
+		EphemeralExpiry: ephemeralExpiry(createArgs),
 	})
 	if err != nil {
 		return nil, errors.Annotate(err, "failed to create new model")
@@ -463,7 +594,11 @@ func (m *ModelManagerAPI) newIAASModel(
 	cloudCredentialTag names.CloudCredentialTag,
 	ownerTag names.UserTag,
 ) (common.Model, error) {
-	newConfig, err := m.newModelConfig(cloudSpec, createArgs, controllerModel)
+	profile, err := m.configProfile(createArgs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	newConfig, err := m.newModelConfig(cloudSpec, createArgs, profile, controllerModel)
 	if err != nil {
 		return nil, errors.Annotate(err, "failed to create config")
 	}
@@ -476,6 +611,15 @@ func (m *ModelManagerAPI) newIAASModel(
 	if err != nil {
 		return nil, errors.Annotate(err, "failed to open environ")
 	}
+	if err := environs.ValidateContainerNetworkingMethod(env, newConfig.ContainerNetworkingMethod()); err != nil {
+		return nil, errors.Annotate(err, "failed to create config")
+	}
+	if err := environs.ValidateLoadBalancerType(env, newConfig.LoadBalancerType()); err != nil {
+		return nil, errors.Annotate(err, "failed to create config")
+	}
+	if err := dns.ValidateProvider(newConfig.DNSProvider()); err != nil {
+		return nil, errors.Annotate(err, "failed to create config")
+	}
 
 	controllerCfg, err := m.state.ControllerConfig()
 	if err != nil {
@@ -501,6 +645,8 @@ func (m *ModelManagerAPI) newIAASModel(
 		Owner:           ownerTag,
 		StorageProviderRegistry: storageProviderRegistry,
 		EnvironVersion:          env.Provider().Version(),
+		ConfigProfile:           profile,
+		EphemeralExpiry:         ephemeralExpiry(createArgs),
 	})
 	if err != nil {
 		return nil, errors.Annotate(err, "failed to create new model")
@@ -801,6 +947,28 @@ func (m *ModelManagerAPI) ListModels(user params.Entity) (params.UserModelList,
 	return result, nil
 }
 
+// TransferModelOwnership was added in version 6 of the facade.
+func (*ModelManagerAPIV5) TransferModelOwnership(_, _ struct{}) {}
+
+// SetModelExpiry was added in version 7 of the facade.
+func (*ModelManagerAPIV6) SetModelExpiry(_, _ struct{}) {}
+
+// CreateModel creates a new model using the account and model config
+// specified in the args. Version 6 and earlier of the facade do not
+// support ephemeral models, so any expiry in the args is ignored.
+func (m *ModelManagerAPIV6) CreateModel(args params.ModelCreateArgs) (params.ModelInfo, error) {
+	args.EphemeralExpiry = nil
+	return m.ModelManagerAPI.CreateModel(args)
+}
+
+// CreateModel creates a new model using the account and model config
+// specified in the args. Version 4 and earlier of the facade do not
+// support config profiles, so any profile in the args is ignored.
+func (m *ModelManagerAPIV4) CreateModel(args params.ModelCreateArgs) (params.ModelInfo, error) {
+	args.Profile = ""
+	return m.ModelManagerAPIV6.CreateModel(args)
+}
+
 // DestroyModels will try to destroy the specified models.
 // If there is a block on destruction, this method will return an error.
 func (m *ModelManagerAPIV3) DestroyModels(args params.Entities) (params.ErrorResults, error) {
@@ -902,11 +1070,13 @@ func (m *ModelManagerAPI) getModelInfo(tag names.ModelTag) (params.ModelInfo, er
 	}
 
 	owner := model.Owner()
+	ownerTeam, _ := model.OwnerTeam()
 	info := params.ModelInfo{
 		Name:           model.Name(),
 		UUID:           model.UUID(),
 		ControllerUUID: model.ControllerUUID(),
 		OwnerTag:       owner.String(),
+		OwnerTeam:      ownerTeam,
 		Life:           params.Life(model.Life().String()),
 		CloudTag:       names.NewCloudTag(model.Cloud()).String(),
 		CloudRegion:    model.CloudRegion(),
@@ -998,6 +1168,23 @@ func (m *ModelManagerAPI) getModelInfo(tag names.ModelTag) (params.ModelInfo, er
 		}
 	}
 
+	if authorizedOwner {
+		transfers, err := model.OwnershipTransfers()
+		if shouldErr(err) {
+			return params.ModelInfo{}, errors.Trace(err)
+		}
+		for _, transfer := range transfers {
+			info.OwnershipTransfers = append(info.OwnershipTransfers, params.ModelOwnershipTransfer{
+				Time:     transfer.Time,
+				ByTag:    transfer.By.String(),
+				FromTag:  transfer.FromUser.String(),
+				FromTeam: transfer.FromTeam,
+				ToTag:    transfer.ToUser.String(),
+				ToTeam:   transfer.ToTeam,
+			})
+		}
+	}
+
 	migration, err := st.LatestMigration()
 	if err != nil && !errors.IsNotFound(err) {
 		return params.ModelInfo{}, errors.Trace(err)
@@ -1069,6 +1256,100 @@ func (m *ModelManagerAPI) ModifyModelAccess(args params.ModifyModelAccessRequest
 	return result, nil
 }
 
+// TransferModelOwnership transfers the ownership of models to other
+// users, or to teams. The caller must be an admin of each model, or a
+// controller superuser.
+func (m *ModelManagerAPI) TransferModelOwnership(args params.TransferModelOwnershipRequest) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Transfers)),
+	}
+	if err := m.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Transfers {
+		err := m.transferModelOwnership(arg)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (m *ModelManagerAPI) transferModelOwnership(arg params.TransferModelOwnership) error {
+	modelTag, err := names.ParseModelTag(arg.ModelTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if (arg.OwnerTag == "") == (arg.Team == "") {
+		return errors.NotValidf("transfer to both or neither of a user and a team")
+	}
+	if !m.isAdmin {
+		isModelAdmin, err := m.authorizer.HasPermission(permission.AdminAccess, modelTag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !isModelAdmin {
+			return common.ErrPerm
+		}
+	}
+
+	model, release, err := m.state.GetModel(modelTag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer release()
+	if arg.Team != "" {
+		return errors.Trace(model.TransferOwnershipToTeam(arg.Team, m.apiUser))
+	}
+	owner, err := names.ParseUserTag(arg.OwnerTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(model.TransferOwnership(owner, m.apiUser))
+}
+
+// SetModelExpiry sets or clears the time after which each model is
+// destroyed along with its storage. Since that destroys the model, the
+// caller must be an admin of each model, or a controller superuser.
+func (m *ModelManagerAPI) SetModelExpiry(args params.SetModelExpiryArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Models)),
+	}
+	if err := m.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Models {
+		err := m.setModelExpiry(arg)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (m *ModelManagerAPI) setModelExpiry(arg params.ModelExpiry) error {
+	modelTag, err := names.ParseModelTag(arg.ModelTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !m.isAdmin {
+		isModelAdmin, err := m.authorizer.HasPermission(permission.AdminAccess, modelTag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !isModelAdmin {
+			return common.ErrPerm
+		}
+	}
+
+	model, release, err := m.state.GetModel(modelTag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer release()
+	var expiry time.Time
+	if arg.Expiry != nil {
+		expiry = *arg.Expiry
+	}
+	return errors.Trace(model.SetEphemeralExpiry(expiry))
+}
+
 func userAuthorizedToChangeAccess(st common.ModelManagerBackend, userIsAdmin bool, userTag names.UserTag) error {
 	if userIsAdmin {
 		// Just confirm that the model that has been given is a valid model.
@@ -1223,6 +1504,11 @@ func (m *ModelManagerAPI) setModelDefaults(args params.ModelDefaultValues) error
 	if _, found := args.Config["agent-version"]; found {
 		return errors.New("agent-version cannot have a default value")
 	}
+	// The expiry is recorded on each model, not in its config; a
+	// default would otherwise destroy every model on the controller.
+	if _, found := args.Config["ephemeral-expiry"]; found {
+		return errors.New("ephemeral-expiry cannot have a default value")
+	}
 
 	var rspec *environs.RegionSpec
 	if args.CloudRegion != "" {
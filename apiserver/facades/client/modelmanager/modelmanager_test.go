@@ -329,6 +329,93 @@ func (s *modelManagerSuite) TestCreateModelArgsWithCloud(c *gc.C) {
 	c.Assert(newModelArgs.CloudName, gc.Equals, "some-cloud")
 }
 
+func (s *modelManagerSuite) TestCreateModelArgsWithProfile(c *gc.C) {
+	args := params.ModelCreateArgs{
+		Name:     "foo",
+		OwnerTag: "user-admin",
+		Config: map[string]interface{}{
+			"bar": "baz",
+		},
+		CloudRegion:        "qux",
+		CloudCredentialTag: "cloudcred-some-cloud_admin_some-credential",
+		Profile:            "hardened",
+	}
+	_, err := s.api.CreateModel(args)
+	c.Assert(err, jc.ErrorIsNil)
+	s.st.CheckCall(c, 5, "ConfigProfile", "hardened")
+
+	// The profile's value for bar is overridden by the args, so only
+	// its value for quux is applied and recorded.
+	newModelArgs := s.getModelArgs(c)
+	c.Assert(newModelArgs.ConfigProfile, jc.DeepEquals, &state.ConfigProfile{
+		Name:   "hardened",
+		Config: map[string]interface{}{"quux": "profile"},
+	})
+	attrs := newModelArgs.Config.AllAttrs()
+	c.Assert(attrs["bar"], gc.Equals, "baz")
+	c.Assert(attrs["quux"], gc.Equals, "profile")
+}
+
+func (s *modelManagerSuite) TestCreateModelArgsWithProfileV4(c *gc.C) {
+	args := params.ModelCreateArgs{
+		Name:               "foo",
+		OwnerTag:           "user-admin",
+		CloudRegion:        "qux",
+		CloudCredentialTag: "cloudcred-some-cloud_admin_some-credential",
+		Profile:            "hardened",
+	}
+	api := &modelmanager.ModelManagerAPIV4{&modelmanager.ModelManagerAPIV5{&modelmanager.ModelManagerAPIV6{s.api}}}
+	_, err := api.CreateModel(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	newModelArgs := s.getModelArgs(c)
+	c.Assert(newModelArgs.ConfigProfile, gc.IsNil)
+	_, ok := newModelArgs.Config.AllAttrs()["quux"]
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *modelManagerSuite) TestCreateModelArgsWithEphemeralExpiry(c *gc.C) {
+	expiry := time.Date(2018, 4, 1, 12, 0, 0, 0, time.UTC)
+	args := params.ModelCreateArgs{
+		Name:               "foo",
+		OwnerTag:           "user-admin",
+		CloudRegion:        "qux",
+		CloudCredentialTag: "cloudcred-some-cloud_admin_some-credential",
+		EphemeralExpiry:    &expiry,
+	}
+	_, err := s.api.CreateModel(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.getModelArgs(c).EphemeralExpiry, gc.Equals, expiry)
+}
+
+func (s *modelManagerSuite) TestCreateModelArgsWithEphemeralExpiryV6(c *gc.C) {
+	expiry := time.Date(2018, 4, 1, 12, 0, 0, 0, time.UTC)
+	args := params.ModelCreateArgs{
+		Name:               "foo",
+		OwnerTag:           "user-admin",
+		CloudRegion:        "qux",
+		CloudCredentialTag: "cloudcred-some-cloud_admin_some-credential",
+		EphemeralExpiry:    &expiry,
+	}
+	api := &modelmanager.ModelManagerAPIV6{s.api}
+	_, err := api.CreateModel(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.getModelArgs(c).EphemeralExpiry.IsZero(), jc.IsTrue)
+}
+
+func (s *modelManagerSuite) TestCreateModelArgsWithMissingProfile(c *gc.C) {
+	s.st.SetErrors(nil, nil, errors.NotFoundf(`config profile "hardened"`))
+	args := params.ModelCreateArgs{
+		Name:               "foo",
+		OwnerTag:           "user-admin",
+		CloudRegion:        "qux",
+		CloudCredentialTag: "cloudcred-some-cloud_admin_some-credential",
+		Profile:            "hardened",
+	}
+	_, err := s.api.CreateModel(args)
+	c.Assert(err, gc.ErrorMatches, `config profile "hardened" not found`)
+}
+
 func (s *modelManagerSuite) TestCreateModelArgsWithCloudNotFound(c *gc.C) {
 	s.st.SetErrors(errors.NotFoundf("cloud"))
 	args := params.ModelCreateArgs{
@@ -518,6 +605,19 @@ func (s *modelManagerSuite) TestSetModelDefaults(c *gc.C) {
 	})
 }
 
+func (s *modelManagerSuite) TestSetModelDefaultsEphemeralExpiry(c *gc.C) {
+	result, err := s.api.SetModelDefaults(params.SetModelDefaults{
+		Config: []params.ModelDefaultValues{{
+			Config: map[string]interface{}{
+				"ephemeral-expiry": "2018-04-01T12:00:00Z",
+			},
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.ErrorMatches, "ephemeral-expiry cannot have a default value")
+	_, ok := s.st.cfgDefaults["ephemeral-expiry"]
+	c.Assert(ok, jc.IsFalse)
+}
+
 func (s *modelManagerSuite) blockAllChanges(c *gc.C, msg string) {
 	s.st.blockMsg = msg
 	s.st.block = state.ChangeBlock
@@ -632,7 +732,9 @@ func (s *modelManagerSuite) TestUnsetModelDefaultsAsNormalUser(c *gc.C) {
 
 func (s *modelManagerSuite) TestDumpModelV2(c *gc.C) {
 	api := &modelmanager.ModelManagerAPIV2{
-		&modelmanager.ModelManagerAPIV3{s.api},
+		&modelmanager.ModelManagerAPIV3{
+			&modelmanager.ModelManagerAPIV4{&modelmanager.ModelManagerAPIV5{&modelmanager.ModelManagerAPIV6{s.api}}},
+		},
 	}
 
 	results := api.DumpModels(params.Entities{[]params.Entity{{
@@ -812,7 +914,9 @@ func (s *modelManagerSuite) TestAddModelCantCreateModelForSomeoneElse(c *gc.C) {
 }
 
 func (s *modelManagerSuite) TestDestroyModelsV3(c *gc.C) {
-	api := &modelmanager.ModelManagerAPIV3{s.api}
+	api := &modelmanager.ModelManagerAPIV3{
+		&modelmanager.ModelManagerAPIV4{&modelmanager.ModelManagerAPIV5{&modelmanager.ModelManagerAPIV6{s.api}}},
+	}
 	results, err := api.DestroyModels(params.Entities{
 		Entities: []params.Entity{{coretesting.ModelTag.String()}},
 	})
@@ -840,6 +944,122 @@ func (s *modelManagerSuite) TestDestroyModelsV3(c *gc.C) {
 	})
 }
 
+func (s *modelManagerSuite) TestTransferModelOwnership(c *gc.C) {
+	results, err := s.api.TransferModelOwnership(params.TransferModelOwnershipRequest{
+		Transfers: []params.TransferModelOwnership{{
+			ModelTag: coretesting.ModelTag.String(),
+			OwnerTag: "user-bob",
+		}, {
+			ModelTag: coretesting.ModelTag.String(),
+			Team:     "ops",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{[]params.ErrorResult{{}, {}}})
+	s.st.model.CheckCalls(c, []gitjujutesting.StubCall{
+		{"TransferOwnership", []interface{}{names.NewUserTag("bob"), names.NewUserTag("admin")}},
+		{"TransferOwnershipToTeam", []interface{}{"ops", names.NewUserTag("admin")}},
+	})
+}
+
+func (s *modelManagerSuite) TestTransferModelOwnershipModelAdmin(c *gc.C) {
+	modelAdmin := names.NewUserTag("admin" + coretesting.ModelTag.String())
+	s.setAPIUser(c, modelAdmin)
+	results, err := s.api.TransferModelOwnership(params.TransferModelOwnershipRequest{
+		Transfers: []params.TransferModelOwnership{{
+			ModelTag: coretesting.ModelTag.String(),
+			OwnerTag: "user-bob",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{[]params.ErrorResult{{}}})
+	s.st.model.CheckCallNames(c, "TransferOwnership")
+}
+
+func (s *modelManagerSuite) TestTransferModelOwnershipPermissionDenied(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("write"))
+	results, err := s.api.TransferModelOwnership(params.TransferModelOwnershipRequest{
+		Transfers: []params.TransferModelOwnership{{
+			ModelTag: coretesting.ModelTag.String(),
+			OwnerTag: "user-write",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "permission denied")
+	s.st.model.CheckNoCalls(c)
+}
+
+func (s *modelManagerSuite) TestTransferModelOwnershipErrors(c *gc.C) {
+	s.st.model.SetErrors(errors.New("boom"))
+	results, err := s.api.TransferModelOwnership(params.TransferModelOwnershipRequest{
+		Transfers: []params.TransferModelOwnership{{
+			ModelTag: coretesting.ModelTag.String(),
+		}, {
+			ModelTag: coretesting.ModelTag.String(),
+			OwnerTag: "user-bob",
+			Team:     "ops",
+		}, {
+			ModelTag: "bad-tag",
+			OwnerTag: "user-bob",
+		}, {
+			ModelTag: coretesting.ModelTag.String(),
+			OwnerTag: "user-bob",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "transfer to both or neither of a user and a team not valid")
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "transfer to both or neither of a user and a team not valid")
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `"bad-tag" is not a valid tag`)
+	c.Assert(results.Results[3].Error, gc.ErrorMatches, "boom")
+}
+
+func (s *modelManagerSuite) TestSetModelExpiry(c *gc.C) {
+	expiry := time.Date(2018, 4, 1, 12, 0, 0, 0, time.UTC)
+	results, err := s.api.SetModelExpiry(params.SetModelExpiryArgs{
+		Models: []params.ModelExpiry{{
+			ModelTag: coretesting.ModelTag.String(),
+			Expiry:   &expiry,
+		}, {
+			ModelTag: coretesting.ModelTag.String(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{[]params.ErrorResult{{}, {}}})
+	s.st.model.CheckCalls(c, []gitjujutesting.StubCall{
+		{"SetEphemeralExpiry", []interface{}{expiry}},
+		{"SetEphemeralExpiry", []interface{}{time.Time{}}},
+	})
+}
+
+func (s *modelManagerSuite) TestSetModelExpiryModelAdmin(c *gc.C) {
+	modelAdmin := names.NewUserTag("admin" + coretesting.ModelTag.String())
+	s.setAPIUser(c, modelAdmin)
+	results, err := s.api.SetModelExpiry(params.SetModelExpiryArgs{
+		Models: []params.ModelExpiry{{ModelTag: coretesting.ModelTag.String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{[]params.ErrorResult{{}}})
+	s.st.model.CheckCallNames(c, "SetEphemeralExpiry")
+}
+
+func (s *modelManagerSuite) TestSetModelExpiryPermissionDenied(c *gc.C) {
+	// Write access to a model is not enough to have it destroyed.
+	s.setAPIUser(c, names.NewUserTag("write"))
+	expiry := time.Date(2018, 4, 1, 12, 0, 0, 0, time.UTC)
+	results, err := s.api.SetModelExpiry(params.SetModelExpiryArgs{
+		Models: []params.ModelExpiry{{
+			ModelTag: coretesting.ModelTag.String(),
+			Expiry:   &expiry,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "permission denied")
+	s.st.model.CheckNoCalls(c)
+}
+
 // modelManagerStateSuite contains end-to-end tests.
 // Prefer adding tests to modelManagerSuite above.
 type modelManagerStateSuite struct {
@@ -992,6 +1212,29 @@ func (s *modelManagerStateSuite) TestCreateModelBadConfig(c *gc.C) {
 	}
 }
 
+func (s *modelManagerStateSuite) TestCreateModelProviderContainerNetworkingUnsupported(c *gc.C) {
+	admin := s.AdminUserTag(c)
+	s.setAPIUser(c, admin)
+	args := createArgs(admin)
+	args.Config["container-networking-method"] = "provider"
+	_, err := s.modelmanager.CreateModel(args)
+	c.Assert(err, gc.ErrorMatches,
+		`failed to create config: container-networking-method "provider" on dummy not supported`,
+	)
+}
+
+func (s *modelManagerStateSuite) TestCreateModelDNSProviderUnsupported(c *gc.C) {
+	admin := s.AdminUserTag(c)
+	s.setAPIUser(c, admin)
+	args := createArgs(admin)
+	args.Config["dns-provider"] = "route53"
+	args.Config["dns-zone"] = "example.com"
+	_, err := s.modelmanager.CreateModel(args)
+	c.Assert(err, gc.ErrorMatches,
+		`failed to create config: dns-provider "route53" not supported`,
+	)
+}
+
 func (s *modelManagerStateSuite) TestCreateModelSameAgentVersion(c *gc.C) {
 	admin := s.AdminUserTag(c)
 	s.setAPIUser(c, admin)
@@ -1566,7 +1809,9 @@ func (s *modelManagerStateSuite) TestModifyModelAccessInvalidAction(c *gc.C) {
 
 func (s *modelManagerSuite) TestModelStatusV2(c *gc.C) {
 	api := &modelmanager.ModelManagerAPIV2{
-		&modelmanager.ModelManagerAPIV3{s.api},
+		&modelmanager.ModelManagerAPIV3{
+			&modelmanager.ModelManagerAPIV4{&modelmanager.ModelManagerAPIV5{&modelmanager.ModelManagerAPIV6{s.api}}},
+		},
 	}
 	// Check that we err out immediately if a model errs.
 	results, err := api.ModelStatus(params.Entities{[]params.Entity{{
@@ -1595,7 +1840,9 @@ func (s *modelManagerSuite) TestModelStatusV2(c *gc.C) {
 }
 
 func (s *modelManagerSuite) TestModelStatusV3(c *gc.C) {
-	api := &modelmanager.ModelManagerAPIV3{s.api}
+	api := &modelmanager.ModelManagerAPIV3{
+		&modelmanager.ModelManagerAPIV4{&modelmanager.ModelManagerAPIV5{&modelmanager.ModelManagerAPIV6{s.api}}},
+	}
 
 	// Check that we err out immediately if a model errs.
 	results, err := api.ModelStatus(params.Entities{[]params.Entity{{
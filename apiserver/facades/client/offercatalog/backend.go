@@ -0,0 +1,33 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package offercatalog
+
+import (
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state functionality required by the
+// offercatalog facade. It is implemented by *state.State.
+type Backend interface {
+	// ControllerTag returns the tag of the controller.
+	ControllerTag() names.ControllerTag
+
+	// RegisterOfferCatalog records the offers of another controller
+	// in the offer catalog.
+	RegisterOfferCatalog(state.OfferCatalogRegistration) error
+
+	// UnregisterOfferCatalog removes the offers registered by the
+	// controller with the given UUID.
+	UnregisterOfferCatalog(controllerUUID string) error
+
+	// OfferCatalogRegistrations returns the offers registered by
+	// each controller.
+	OfferCatalogRegistrations() ([]state.OfferCatalogRegistration, error)
+
+	// FindOfferCatalogEntries returns the offers in the catalog that
+	// match the filter.
+	FindOfferCatalogEntries(state.OfferCatalogFilter) ([]state.OfferCatalogEntry, error)
+}
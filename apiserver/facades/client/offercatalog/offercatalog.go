@@ -0,0 +1,181 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package offercatalog provides the OfferCatalog facade, through which
+// controllers register their application offers with another
+// controller acting as a directory, and users search the offers so
+// registered. Any user may search the catalog; only controller
+// superusers may change it. Finding an offer in the catalog grants no
+// access to it: the controller hosting the offer decides whether the
+// user may consume it.
+package offercatalog
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+// API provides the OfferCatalog facade for v1.
+type API struct {
+	backend    Backend
+	authorizer facade.Authorizer
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(ctx.State(), ctx.Auth())
+}
+
+// NewAPI returns a new OfferCatalog API facade.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		authorizer: authorizer,
+	}, nil
+}
+
+func (api *API) checkIsSuperuser() error {
+	allowed, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.backend.ControllerTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !allowed {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// Register records the offers of other controllers in the offer
+// catalog, replacing any offers each controller registered before.
+func (api *API) Register(args params.OfferCatalogRegistrations) (params.ErrorResults, error) {
+	if err := api.checkIsSuperuser(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Registrations)),
+	}
+	for i, arg := range args.Registrations {
+		reg, err := fromParams(arg)
+		if err == nil {
+			err = api.backend.RegisterOfferCatalog(reg)
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// Unregister removes the offers registered by the given controllers
+// from the offer catalog.
+func (api *API) Unregister(args params.Entities) (params.ErrorResults, error) {
+	if err := api.checkIsSuperuser(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		tag, err := names.ParseControllerTag(arg.Tag)
+		if err == nil {
+			err = api.backend.UnregisterOfferCatalog(tag.Id())
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// List returns the offers registered with the offer catalog by each
+// controller.
+func (api *API) List() (params.OfferCatalogRegistrations, error) {
+	if err := api.checkIsSuperuser(); err != nil {
+		return params.OfferCatalogRegistrations{}, errors.Trace(err)
+	}
+	regs, err := api.backend.OfferCatalogRegistrations()
+	if err != nil {
+		return params.OfferCatalogRegistrations{}, errors.Trace(err)
+	}
+	result := params.OfferCatalogRegistrations{
+		Registrations: make([]params.OfferCatalogRegistration, len(regs)),
+	}
+	for i, reg := range regs {
+		updated := reg.Updated
+		result.Registrations[i] = params.OfferCatalogRegistration{
+			ControllerTag:  names.NewControllerTag(reg.ControllerUUID).String(),
+			ControllerName: reg.ControllerName,
+			Offers:         entriesToParams(reg.Offers),
+			Updated:        &updated,
+		}
+	}
+	return result, nil
+}
+
+// Find returns the offers in the offer catalog that match the filter.
+func (api *API) Find(arg params.OfferCatalogFilter) (params.OfferCatalogEntries, error) {
+	entries, err := api.backend.FindOfferCatalogEntries(state.OfferCatalogFilter{
+		Interface: arg.Interface,
+		OfferName: arg.OfferName,
+	})
+	if err != nil {
+		return params.OfferCatalogEntries{}, errors.Trace(err)
+	}
+	return params.OfferCatalogEntries{Offers: entriesToParams(entries)}, nil
+}
+
+func fromParams(arg params.OfferCatalogRegistration) (state.OfferCatalogRegistration, error) {
+	tag, err := names.ParseControllerTag(arg.ControllerTag)
+	if err != nil {
+		return state.OfferCatalogRegistration{}, errors.Trace(err)
+	}
+	reg := state.OfferCatalogRegistration{
+		ControllerUUID: tag.Id(),
+		ControllerName: arg.ControllerName,
+	}
+	for _, offer := range arg.Offers {
+		entry := state.OfferCatalogEntry{
+			ControllerUUID:         tag.Id(),
+			OfferURL:               offer.OfferURL,
+			OfferName:              offer.OfferName,
+			ApplicationDescription: offer.ApplicationDescription,
+		}
+		for _, ep := range offer.Endpoints {
+			entry.Endpoints = append(entry.Endpoints, charm.Relation{
+				Name:      ep.Name,
+				Interface: ep.Interface,
+				Role:      ep.Role,
+				Limit:     ep.Limit,
+			})
+		}
+		reg.Offers = append(reg.Offers, entry)
+	}
+	return reg, nil
+}
+
+func entriesToParams(entries []state.OfferCatalogEntry) []params.OfferCatalogEntry {
+	result := make([]params.OfferCatalogEntry, len(entries))
+	for i, entry := range entries {
+		result[i] = params.OfferCatalogEntry{
+			ControllerTag:          names.NewControllerTag(entry.ControllerUUID).String(),
+			OfferURL:               entry.OfferURL,
+			OfferName:              entry.OfferName,
+			ApplicationDescription: entry.ApplicationDescription,
+		}
+		for _, ep := range entry.Endpoints {
+			result[i].Endpoints = append(result[i].Endpoints, params.RemoteEndpoint{
+				Name:      ep.Name,
+				Interface: ep.Interface,
+				Role:      ep.Role,
+				Limit:     ep.Limit,
+			})
+		}
+	}
+	return result
+}
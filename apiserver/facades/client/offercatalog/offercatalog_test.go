@@ -0,0 +1,197 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package offercatalog_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/offercatalog"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+const dataControllerUUID = "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+
+var dataControllerTag = names.NewControllerTag(dataControllerUUID).String()
+
+type OfferCatalogSuite struct {
+	testing.IsolationSuite
+	backend    mockBackend
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&OfferCatalogSuite{})
+
+func (s *OfferCatalogSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("admin"),
+	}
+	s.backend = mockBackend{
+		entries: []state.OfferCatalogEntry{{
+			ControllerUUID: dataControllerUUID,
+			OfferURL:       "data:fred/prod.postgresql",
+			OfferName:      "postgresql",
+			Endpoints: []charm.Relation{
+				{Name: "db", Interface: "pgsql", Role: charm.RoleProvider},
+			},
+		}},
+	}
+}
+
+func (s *OfferCatalogSuite) newAPI(c *gc.C) *offercatalog.API {
+	api, err := offercatalog.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *OfferCatalogSuite) TestNewAPIRequiresClient(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("0")
+	_, err := offercatalog.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *OfferCatalogSuite) TestFind(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("fred")
+	result, err := s.newAPI(c).Find(params.OfferCatalogFilter{Interface: "pgsql"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.OfferCatalogEntries{
+		Offers: []params.OfferCatalogEntry{{
+			ControllerTag: dataControllerTag,
+			OfferURL:      "data:fred/prod.postgresql",
+			OfferName:     "postgresql",
+			Endpoints: []params.RemoteEndpoint{
+				{Name: "db", Interface: "pgsql", Role: charm.RoleProvider},
+			},
+		}},
+	})
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"FindOfferCatalogEntries", []interface{}{state.OfferCatalogFilter{Interface: "pgsql"}}},
+	})
+}
+
+func (s *OfferCatalogSuite) TestRegister(c *gc.C) {
+	s.backend.SetErrors(errors.NotValidf("offer URL"))
+	result, err := s.newAPI(c).Register(params.OfferCatalogRegistrations{
+		Registrations: []params.OfferCatalogRegistration{{
+			ControllerTag:  dataControllerTag,
+			ControllerName: "data",
+			Offers: []params.OfferCatalogEntry{{
+				OfferURL:  "data:fred/prod.postgresql",
+				OfferName: "postgresql",
+				Endpoints: []params.RemoteEndpoint{
+					{Name: "db", Interface: "pgsql", Role: charm.RoleProvider},
+				},
+			}},
+		}, {
+			ControllerTag: "machine-0",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{
+			Error: &params.Error{Message: "offer URL not valid"},
+		}, {
+			Error: &params.Error{Message: `"machine-0" is not a valid controller tag`},
+		}},
+	})
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"ControllerTag", nil},
+		{"RegisterOfferCatalog", []interface{}{state.OfferCatalogRegistration{
+			ControllerUUID: dataControllerUUID,
+			ControllerName: "data",
+			Offers: []state.OfferCatalogEntry{{
+				ControllerUUID: dataControllerUUID,
+				OfferURL:       "data:fred/prod.postgresql",
+				OfferName:      "postgresql",
+				Endpoints: []charm.Relation{
+					{Name: "db", Interface: "pgsql", Role: charm.RoleProvider},
+				},
+			}},
+		}}},
+	})
+}
+
+func (s *OfferCatalogSuite) TestUnregister(c *gc.C) {
+	result, err := s.newAPI(c).Unregister(params.Entities{
+		Entities: []params.Entity{{Tag: dataControllerTag}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"ControllerTag", nil},
+		{"UnregisterOfferCatalog", []interface{}{dataControllerUUID}},
+	})
+}
+
+func (s *OfferCatalogSuite) TestList(c *gc.C) {
+	updated := time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC)
+	s.backend.registrations = []state.OfferCatalogRegistration{{
+		ControllerUUID: dataControllerUUID,
+		ControllerName: "data",
+		Offers:         s.backend.entries,
+		Updated:        updated,
+	}}
+	result, err := s.newAPI(c).List()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Registrations, gc.HasLen, 1)
+	c.Assert(result.Registrations[0].ControllerTag, gc.Equals, dataControllerTag)
+	c.Assert(result.Registrations[0].ControllerName, gc.Equals, "data")
+	c.Assert(result.Registrations[0].Offers, gc.HasLen, 1)
+	c.Assert(*result.Registrations[0].Updated, gc.Equals, updated)
+}
+
+func (s *OfferCatalogSuite) TestChangesPermissionDenied(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("fred")
+	api := s.newAPI(c)
+	_, err := api.Register(params.OfferCatalogRegistrations{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = api.Unregister(params.Entities{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = api.List()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "ControllerTag", "ControllerTag", "ControllerTag")
+}
+
+type mockBackend struct {
+	testing.Stub
+	entries       []state.OfferCatalogEntry
+	registrations []state.OfferCatalogRegistration
+}
+
+func (m *mockBackend) ControllerTag() names.ControllerTag {
+	m.MethodCall(m, "ControllerTag")
+	return coretesting.ControllerTag
+}
+
+func (m *mockBackend) RegisterOfferCatalog(reg state.OfferCatalogRegistration) error {
+	m.MethodCall(m, "RegisterOfferCatalog", reg)
+	return m.NextErr()
+}
+
+func (m *mockBackend) UnregisterOfferCatalog(controllerUUID string) error {
+	m.MethodCall(m, "UnregisterOfferCatalog", controllerUUID)
+	return m.NextErr()
+}
+
+func (m *mockBackend) OfferCatalogRegistrations() ([]state.OfferCatalogRegistration, error) {
+	m.MethodCall(m, "OfferCatalogRegistrations")
+	return m.registrations, m.NextErr()
+}
+
+func (m *mockBackend) FindOfferCatalogEntries(filter state.OfferCatalogFilter) ([]state.OfferCatalogEntry, error) {
+	m.MethodCall(m, "FindOfferCatalogEntries", filter)
+	return m.entries, m.NextErr()
+}
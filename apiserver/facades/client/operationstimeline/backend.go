@@ -0,0 +1,91 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operationstimeline
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
+)
+
+// Backend defines the state functionality required by the
+// operationstimeline facade.
+type Backend interface {
+	// ModelTag returns the tag of the model.
+	ModelTag() names.ModelTag
+
+	// Application returns the application with the given name.
+	Application(name string) (Application, error)
+
+	// Machine returns the machine with the given id.
+	Machine(id string) (Machine, error)
+}
+
+// Application defines the application functionality required by the
+// operationstimeline facade.
+type Application interface {
+	AllUnits() ([]Unit, error)
+}
+
+// Unit defines the unit functionality required by the
+// operationstimeline facade.
+type Unit interface {
+	UnitTag() names.UnitTag
+	AssignedMachineId() (string, error)
+	AgentHistory() status.StatusHistoryGetter
+}
+
+// Machine defines the machine functionality required by the
+// operationstimeline facade.
+type Machine interface {
+	MachineTag() names.MachineTag
+	StatusHistory(filter status.StatusHistoryFilter) ([]status.StatusInfo, error)
+	InstanceStatusHistory(filter status.StatusHistoryFilter) ([]status.StatusInfo, error)
+}
+
+// This type is an untested shim to let us wrap state in a sensible
+// interface and avoid writing tests that depend on mongodb. If you were
+// to change any part of it so that it were no longer *obviously* and
+// *trivially* correct, you would be Doing It Wrong.
+type stateShim struct {
+	*state.State
+}
+
+func (s stateShim) ModelTag() names.ModelTag {
+	return names.NewModelTag(s.State.ModelUUID())
+}
+
+func (s stateShim) Application(name string) (Application, error) {
+	app, err := s.State.Application(name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return applicationShim{app}, nil
+}
+
+func (s stateShim) Machine(id string) (Machine, error) {
+	m, err := s.State.Machine(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return m, nil
+}
+
+type applicationShim struct {
+	*state.Application
+}
+
+func (a applicationShim) AllUnits() ([]Unit, error) {
+	units, err := a.Application.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]Unit, len(units))
+	for i, u := range units {
+		result[i] = u
+	}
+	return result, nil
+}
@@ -0,0 +1,264 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package operationstimeline provides the OperationsTimeline facade,
+// which correlates the status history of an application's units and
+// their machines into a timeline of the operations that deployed
+// them, for the analysis of slow deployments.
+package operationstimeline
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/status"
+)
+
+// historySize is the number of status history entries read for each
+// machine and unit.
+const historySize = 1000
+
+// API provides the OperationsTimeline facade for v1.
+type API struct {
+	backend    Backend
+	authorizer facade.Authorizer
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(stateShim{ctx.State()}, ctx.Auth())
+}
+
+// NewAPI returns a new OperationsTimeline API facade.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		authorizer: authorizer,
+	}, nil
+}
+
+func (api *API) checkCanRead() error {
+	allowed, err := api.authorizer.HasPermission(permission.ReadAccess, api.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !allowed {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// DeployTimeline returns, for each of the specified applications, the
+// provisioning and agent start of the machines hosting its units and
+// the hooks its units have run, ordered by the time they started.
+func (api *API) DeployTimeline(args params.Entities) (params.DeployTimelineResults, error) {
+	if err := api.checkCanRead(); err != nil {
+		return params.DeployTimelineResults{}, errors.Trace(err)
+	}
+	results := params.DeployTimelineResults{
+		Results: make([]params.DeployTimelineResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		tag, err := names.ParseApplicationTag(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		timeline, err := api.deployTimeline(tag.Id())
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = timeline
+	}
+	return results, nil
+}
+
+func (api *API) deployTimeline(appName string) (*params.DeployTimeline, error) {
+	app, err := api.backend.Application(appName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	units, err := app.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	filter := status.StatusHistoryFilter{Size: historySize}
+	timeline := &params.DeployTimeline{Application: appName}
+	machines := make(map[string]bool)
+	for _, unit := range units {
+		history, err := unit.AgentHistory().StatusHistory(filter)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot get status history of %s", unit.UnitTag().Id())
+		}
+		timeline.Events = append(timeline.Events, unitEvents(unit.UnitTag(), history)...)
+
+		machineId, err := unit.AssignedMachineId()
+		if errors.IsNotAssigned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if machines[machineId] {
+			continue
+		}
+		machines[machineId] = true
+		events, err := api.machineEvents(machineId, filter)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot get status history of machine %s", machineId)
+		}
+		timeline.Events = append(timeline.Events, events...)
+	}
+	sort.SliceStable(timeline.Events, func(i, j int) bool {
+		a, b := timeline.Events[i], timeline.Events[j]
+		if !a.Start.Equal(b.Start) {
+			return a.Start.Before(b.Start)
+		}
+		return a.Entity < b.Entity
+	})
+	return timeline, nil
+}
+
+func (api *API) machineEvents(id string, filter status.StatusHistoryFilter) ([]params.TimelineEvent, error) {
+	machine, err := api.backend.Machine(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	instanceHistory, err := machine.InstanceStatusHistory(filter)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	agentHistory, err := machine.StatusHistory(filter)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machineEvents(machine.MachineTag(), instanceHistory, agentHistory), nil
+}
+
+// machineEvents returns the provisioning and agent start events of a
+// machine, given its instance and agent status histories.
+func machineEvents(tag names.MachineTag, instanceHistory, agentHistory []status.StatusInfo) []params.TimelineEvent {
+	instanceHistory = sortedHistory(instanceHistory)
+	agentHistory = sortedHistory(agentHistory)
+
+	var events []params.TimelineEvent
+	var running *params.TimelineEvent
+	if len(instanceHistory) > 0 {
+		provision := params.TimelineEvent{
+			Entity: tag.String(),
+			Kind:   params.TimelineProvision,
+			Start:  *instanceHistory[0].Since,
+		}
+		for _, s := range instanceHistory {
+			if s.Status == status.Running || s.Status == status.ProvisioningError {
+				provision.End = s.Since
+				if s.Status == status.ProvisioningError {
+					provision.Error = s.Message
+				}
+				break
+			}
+		}
+		events = append(events, provision)
+		if provision.End != nil && provision.Error == "" {
+			running = &provision
+		}
+	}
+
+	agentStart := params.TimelineEvent{
+		Entity: tag.String(),
+		Kind:   params.TimelineAgentStart,
+	}
+	switch {
+	case running != nil:
+		agentStart.Start = *running.End
+	case len(agentHistory) > 0:
+		agentStart.Start = *agentHistory[0].Since
+	default:
+		return events
+	}
+	for _, s := range agentHistory {
+		if s.Status == status.Started && !s.Since.Before(agentStart.Start) {
+			agentStart.End = s.Since
+			break
+		}
+	}
+	if running == nil && agentStart.End == nil {
+		return events
+	}
+	return append(events, agentStart)
+}
+
+// unitEvents returns the hook events of a unit, given its agent status
+// history. A hook finishes when the agent next reports its status.
+func unitEvents(tag names.UnitTag, agentHistory []status.StatusInfo) []params.TimelineEvent {
+	agentHistory = sortedHistory(agentHistory)
+
+	var events []params.TimelineEvent
+	for i, s := range agentHistory {
+		if s.Status != status.Executing {
+			continue
+		}
+		hookName, ok := runningHookName(s.Message)
+		if !ok {
+			continue
+		}
+		event := params.TimelineEvent{
+			Entity: tag.String(),
+			Kind:   params.TimelineHook,
+			Name:   hookName,
+			Start:  *s.Since,
+		}
+		if strings.Contains(hookName, "-relation-") {
+			event.Kind = params.TimelineRelation
+		}
+		if i+1 < len(agentHistory) {
+			next := agentHistory[i+1]
+			event.End = next.Since
+			if next.Status == status.Error {
+				event.Error = next.Message
+			}
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// runningHookName returns the name of the hook in a unit agent status
+// message reporting that the hook is running.
+func runningHookName(message string) (string, bool) {
+	const prefix, suffix = "running ", " hook"
+	if !strings.HasPrefix(message, prefix) || !strings.HasSuffix(message, suffix) {
+		return "", false
+	}
+	name := message[len(prefix) : len(message)-len(suffix)]
+	if name == "" || strings.Contains(name, " ") {
+		return "", false
+	}
+	return name, true
+}
+
+// sortedHistory returns the status history, which the backend returns
+// most recent first, in the order in which the statuses were set.
+// Entries without a time are dropped.
+func sortedHistory(history []status.StatusInfo) []status.StatusInfo {
+	sorted := make([]status.StatusInfo, 0, len(history))
+	for _, s := range history {
+		if s.Since != nil {
+			sorted = append(sorted, s)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Since.Before(*sorted[j].Since)
+	})
+	return sorted
+}
@@ -0,0 +1,275 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operationstimeline_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/operationstimeline"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type OperationsTimelineSuite struct {
+	testing.IsolationSuite
+	backend    mockBackend
+	authorizer apiservertesting.FakeAuthorizer
+	start      time.Time
+}
+
+var _ = gc.Suite(&OperationsTimelineSuite{})
+
+func (s *OperationsTimelineSuite) at(seconds int) *time.Time {
+	t := s.start.Add(time.Duration(seconds) * time.Second)
+	return &t
+}
+
+func (s *OperationsTimelineSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("read"),
+	}
+	s.start = time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC)
+	// Status history is returned most recent first.
+	s.backend = mockBackend{
+		applications: map[string][]*mockUnit{
+			"mysql": {{
+				tag:       names.NewUnitTag("mysql/0"),
+				machineId: "0",
+				history: []status.StatusInfo{
+					{Status: status.Idle, Since: s.at(260)},
+					{Status: status.Executing, Message: "running db-relation-joined hook", Since: s.at(250)},
+					{Status: status.Error, Message: `hook failed: "start"`, Since: s.at(240)},
+					{Status: status.Executing, Message: "running start hook", Since: s.at(230)},
+					{Status: status.Executing, Message: "running install hook", Since: s.at(130)},
+					{Status: status.Allocating, Since: s.at(0)},
+				},
+			}, {
+				tag:       names.NewUnitTag("mysql/1"),
+				machineId: "0",
+				history: []status.StatusInfo{
+					{Status: status.Executing, Message: "running install hook", Since: s.at(135)},
+					{Status: status.Allocating, Since: s.at(0)},
+				},
+			}, {
+				tag:         names.NewUnitTag("mysql/2"),
+				notAssigned: true,
+			}},
+		},
+		machines: map[string]*mockMachine{
+			"0": {
+				tag: names.NewMachineTag("0"),
+				instanceHistory: []status.StatusInfo{
+					{Status: status.Running, Since: s.at(90)},
+					{Status: status.Provisioning, Since: s.at(5)},
+					{Status: status.Pending, Since: s.at(0)},
+				},
+				history: []status.StatusInfo{
+					{Status: status.Started, Since: s.at(120)},
+					{Status: status.Pending, Since: s.at(0)},
+				},
+			},
+		},
+	}
+}
+
+func (s *OperationsTimelineSuite) TestNewAPIRequiresClient(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("0")
+	_, err := operationstimeline.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *OperationsTimelineSuite) TestDeployTimelineRequiresRead(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("bob")
+	api, err := operationstimeline.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.DeployTimeline(params.Entities{
+		Entities: []params.Entity{{Tag: "application-mysql"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *OperationsTimelineSuite) TestDeployTimeline(c *gc.C) {
+	api, err := operationstimeline.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := api.DeployTimeline(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "application-mysql"},
+			{Tag: "application-wordpress"},
+			{Tag: "unit-mysql-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Result, jc.DeepEquals, &params.DeployTimeline{
+		Application: "mysql",
+		Events: []params.TimelineEvent{{
+			Entity: "machine-0",
+			Kind:   params.TimelineProvision,
+			Start:  *s.at(0),
+			End:    s.at(90),
+		}, {
+			Entity: "machine-0",
+			Kind:   params.TimelineAgentStart,
+			Start:  *s.at(90),
+			End:    s.at(120),
+		}, {
+			Entity: "unit-mysql-0",
+			Kind:   params.TimelineHook,
+			Name:   "install",
+			Start:  *s.at(130),
+			End:    s.at(230),
+		}, {
+			Entity: "unit-mysql-1",
+			Kind:   params.TimelineHook,
+			Name:   "install",
+			Start:  *s.at(135),
+		}, {
+			Entity: "unit-mysql-0",
+			Kind:   params.TimelineHook,
+			Name:   "start",
+			Start:  *s.at(230),
+			End:    s.at(240),
+			Error:  `hook failed: "start"`,
+		}, {
+			Entity: "unit-mysql-0",
+			Kind:   params.TimelineRelation,
+			Name:   "db-relation-joined",
+			Start:  *s.at(250),
+			End:    s.at(260),
+		}},
+	})
+	c.Assert(results.Results[1].Error, jc.DeepEquals, &params.Error{
+		Code:    params.CodeNotFound,
+		Message: `application "wordpress" not found`,
+	})
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `"unit-mysql-0" is not a valid application tag`)
+	s.backend.CheckCallNames(c, "ModelTag", "Application", "Machine", "Application")
+}
+
+func (s *OperationsTimelineSuite) TestDeployTimelineProvisioningError(c *gc.C) {
+	s.backend.machines["0"].instanceHistory = []status.StatusInfo{
+		{Status: status.ProvisioningError, Message: "no matching tools", Since: s.at(30)},
+		{Status: status.Pending, Since: s.at(0)},
+	}
+	s.backend.machines["0"].history = []status.StatusInfo{
+		{Status: status.Pending, Since: s.at(0)},
+	}
+	s.backend.applications["mysql"] = s.backend.applications["mysql"][2:]
+	s.backend.applications["mysql"][0].notAssigned = false
+	s.backend.applications["mysql"][0].machineId = "0"
+
+	api, err := operationstimeline.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := api.DeployTimeline(params.Entities{
+		Entities: []params.Entity{{Tag: "application-mysql"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Result, jc.DeepEquals, &params.DeployTimeline{
+		Application: "mysql",
+		Events: []params.TimelineEvent{{
+			Entity: "machine-0",
+			Kind:   params.TimelineProvision,
+			Start:  *s.at(0),
+			End:    s.at(30),
+			Error:  "no matching tools",
+		}},
+	})
+}
+
+type mockBackend struct {
+	testing.Stub
+	applications map[string][]*mockUnit
+	machines     map[string]*mockMachine
+}
+
+func (m *mockBackend) ModelTag() names.ModelTag {
+	m.MethodCall(m, "ModelTag")
+	return coretesting.ModelTag
+}
+
+func (m *mockBackend) Application(name string) (operationstimeline.Application, error) {
+	m.MethodCall(m, "Application", name)
+	units, ok := m.applications[name]
+	if !ok {
+		return nil, errors.NotFoundf("application %q", name)
+	}
+	return mockApplication{units}, nil
+}
+
+func (m *mockBackend) Machine(id string) (operationstimeline.Machine, error) {
+	m.MethodCall(m, "Machine", id)
+	machine, ok := m.machines[id]
+	if !ok {
+		return nil, errors.NotFoundf("machine %s", id)
+	}
+	return machine, nil
+}
+
+type mockApplication struct {
+	units []*mockUnit
+}
+
+func (a mockApplication) AllUnits() ([]operationstimeline.Unit, error) {
+	units := make([]operationstimeline.Unit, len(a.units))
+	for i, u := range a.units {
+		units[i] = u
+	}
+	return units, nil
+}
+
+type mockUnit struct {
+	tag         names.UnitTag
+	machineId   string
+	notAssigned bool
+	history     []status.StatusInfo
+}
+
+func (u *mockUnit) UnitTag() names.UnitTag {
+	return u.tag
+}
+
+func (u *mockUnit) AssignedMachineId() (string, error) {
+	if u.notAssigned {
+		return "", errors.NotAssignedf("unit %q", u.tag.Id())
+	}
+	return u.machineId, nil
+}
+
+func (u *mockUnit) AgentHistory() status.StatusHistoryGetter {
+	return statusHistory(u.history)
+}
+
+type mockMachine struct {
+	tag             names.MachineTag
+	history         []status.StatusInfo
+	instanceHistory []status.StatusInfo
+}
+
+func (m *mockMachine) MachineTag() names.MachineTag {
+	return m.tag
+}
+
+func (m *mockMachine) StatusHistory(status.StatusHistoryFilter) ([]status.StatusInfo, error) {
+	return m.history, nil
+}
+
+func (m *mockMachine) InstanceStatusHistory(status.StatusHistoryFilter) ([]status.StatusInfo, error) {
+	return m.instanceHistory, nil
+}
+
+type statusHistory []status.StatusInfo
+
+func (h statusHistory) StatusHistory(status.StatusHistoryFilter) ([]status.StatusInfo, error) {
+	return h, nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package orphanedresources
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state functionality required by the
+// orphanedresources facade.
+type Backend interface {
+	// ModelTag returns the tag of the model.
+	ModelTag() names.ModelTag
+
+	// InstanceIds returns the IDs of the provisioned instances of every
+	// machine in the model.
+	InstanceIds() ([]instance.Id, error)
+
+	// VolumeIds returns the provider IDs of every provisioned volume
+	// in the model.
+	VolumeIds() ([]string, error)
+}
+
+// This type is an untested shim to let us wrap state in a sensible
+// interface and avoid writing tests that depend on mongodb. If you were
+// to change any part of it so that it were no longer *obviously* and
+// *trivially* correct, you would be Doing It Wrong.
+type stateShim struct {
+	*state.State
+}
+
+func (s stateShim) ModelTag() names.ModelTag {
+	return names.NewModelTag(s.State.ModelUUID())
+}
+
+func (s stateShim) InstanceIds() ([]instance.Id, error) {
+	machines, err := s.State.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ids := make([]instance.Id, 0, len(machines))
+	for _, m := range machines {
+		id, err := m.InstanceId()
+		if errors.IsNotProvisioned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (s stateShim) VolumeIds() ([]string, error) {
+	im, err := s.State.IAASModel()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	volumes, err := im.AllVolumes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ids := make([]string, 0, len(volumes))
+	for _, v := range volumes {
+		info, err := v.Info()
+		if errors.IsNotProvisioned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ids = append(ids, info.VolumeId)
+	}
+	return ids, nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package orphanedresources
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state/stateenvirons"
+)
+
+// API provides the OrphanedResources facade for v1.
+type API struct {
+	backend    Backend
+	newEnviron func() (environs.Environ, error)
+	authorizer facade.Authorizer
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	st := ctx.State()
+	newEnviron := func() (environs.Environ, error) {
+		return stateenvirons.GetNewEnvironFunc(environs.New)(st)
+	}
+	return NewAPI(stateShim{st}, newEnviron, ctx.Auth())
+}
+
+// NewAPI returns a new OrphanedResources API facade.
+func NewAPI(
+	backend Backend,
+	newEnviron func() (environs.Environ, error),
+	authorizer facade.Authorizer,
+) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		newEnviron: newEnviron,
+		authorizer: authorizer,
+	}, nil
+}
+
+func (api *API) checkCanRead() error {
+	allowed, err := api.authorizer.HasPermission(permission.ReadAccess, api.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !allowed {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// OrphanedResources returns the instances and volumes in the model's
+// cloud that are tagged as belonging to the model, but which the model
+// no longer tracks. Nothing is removed; the result is intended to be
+// reviewed by an operator.
+func (api *API) OrphanedResources() (params.OrphanedResourcesResult, error) {
+	var result params.OrphanedResourcesResult
+	if err := api.checkCanRead(); err != nil {
+		return result, errors.Trace(err)
+	}
+	env, err := api.newEnviron()
+	if err != nil {
+		return result, errors.Annotate(err, "opening environment")
+	}
+	lister, ok := env.(environs.TaggedResourceLister)
+	if !ok {
+		return result, errors.NotSupportedf("listing tagged cloud resources")
+	}
+
+	// List the cloud before reading state, so that a resource
+	// provisioned in the meantime is not mistaken for an orphan.
+	resources, err := lister.TaggedResources(map[string]string{
+		tags.JujuModel: api.backend.ModelTag().Id(),
+	})
+	if err != nil {
+		return result, errors.Annotate(err, "listing tagged resources")
+	}
+	instanceIds, err := api.backend.InstanceIds()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	volumeIds, err := api.backend.VolumeIds()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	tracked := map[string]set.Strings{
+		environs.ResourceKindInstance: set.NewStrings(),
+		environs.ResourceKindVolume:   set.NewStrings(volumeIds...),
+	}
+	for _, id := range instanceIds {
+		tracked[environs.ResourceKindInstance].Add(string(id))
+	}
+
+	for _, resource := range resources {
+		ids, ok := tracked[resource.Kind]
+		if !ok || ids.Contains(resource.ID) {
+			continue
+		}
+		result.Resources = append(result.Resources, params.OrphanedResource{
+			Kind: resource.Kind,
+			ID:   resource.ID,
+			Tags: resource.Tags,
+		})
+	}
+	sort.Slice(result.Resources, func(i, j int) bool {
+		ri, rj := result.Resources[i], result.Resources[j]
+		if ri.Kind != rj.Kind {
+			return ri.Kind < rj.Kind
+		}
+		return ri.ID < rj.ID
+	})
+	return result, nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package orphanedresources_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/orphanedresources"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type OrphanedResourcesSuite struct {
+	testing.IsolationSuite
+	backend    mockBackend
+	lister     mockLister
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&OrphanedResourcesSuite{})
+
+func (s *OrphanedResourcesSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("admin"),
+	}
+	s.backend = mockBackend{
+		instanceIds: []instance.Id{"i-0", "i-1"},
+		volumeIds:   []string{"vol-0"},
+	}
+	s.lister = mockLister{
+		resources: []environs.TaggedResource{
+			{Kind: environs.ResourceKindVolume, ID: "vol-1", Tags: map[string]string{"k": "v"}},
+			{Kind: environs.ResourceKindInstance, ID: "i-1"},
+			{Kind: environs.ResourceKindInstance, ID: "i-2"},
+			{Kind: environs.ResourceKindVolume, ID: "vol-0"},
+			{Kind: environs.ResourceKindSecurityGroup, ID: "sg-0"},
+		},
+	}
+}
+
+func (s *OrphanedResourcesSuite) newEnviron() (environs.Environ, error) {
+	return mockListerEnviron{mockLister: &s.lister}, nil
+}
+
+func (s *OrphanedResourcesSuite) TestNewAPIRequiresClient(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("0")
+	_, err := orphanedresources.NewAPI(&s.backend, s.newEnviron, s.authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *OrphanedResourcesSuite) TestOrphanedResources(c *gc.C) {
+	api, err := orphanedresources.NewAPI(&s.backend, s.newEnviron, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.OrphanedResources()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.OrphanedResourcesResult{
+		Resources: []params.OrphanedResource{
+			{Kind: "instance", ID: "i-2"},
+			{Kind: "volume", ID: "vol-1", Tags: map[string]string{"k": "v"}},
+		},
+	})
+	s.lister.CheckCalls(c, []testing.StubCall{{
+		"TaggedResources", []interface{}{map[string]string{
+			"juju-model-uuid": coretesting.ModelTag.Id(),
+		}},
+	}})
+}
+
+func (s *OrphanedResourcesSuite) TestOrphanedResourcesNotSupported(c *gc.C) {
+	newEnviron := func() (environs.Environ, error) {
+		return mockEnviron{}, nil
+	}
+	api, err := orphanedresources.NewAPI(&s.backend, newEnviron, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.OrphanedResources()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *OrphanedResourcesSuite) TestOrphanedResourcesEnvironError(c *gc.C) {
+	newEnviron := func() (environs.Environ, error) {
+		return nil, errors.New("boom")
+	}
+	api, err := orphanedresources.NewAPI(&s.backend, newEnviron, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.OrphanedResources()
+	c.Assert(err, gc.ErrorMatches, "opening environment: boom")
+}
+
+func (s *OrphanedResourcesSuite) TestOrphanedResourcesListError(c *gc.C) {
+	s.lister.SetErrors(errors.New("boom"))
+	api, err := orphanedresources.NewAPI(&s.backend, s.newEnviron, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.OrphanedResources()
+	c.Assert(err, gc.ErrorMatches, "listing tagged resources: boom")
+	s.backend.CheckCallNames(c, "ModelTag", "ModelTag")
+}
+
+func (s *OrphanedResourcesSuite) TestOrphanedResourcesPermissionDenied(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("fred")
+	api, err := orphanedresources.NewAPI(&s.backend, s.newEnviron, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.OrphanedResources()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.lister.CheckNoCalls(c)
+}
+
+type mockBackend struct {
+	testing.Stub
+	instanceIds []instance.Id
+	volumeIds   []string
+}
+
+func (m *mockBackend) ModelTag() names.ModelTag {
+	m.MethodCall(m, "ModelTag")
+	return coretesting.ModelTag
+}
+
+func (m *mockBackend) InstanceIds() ([]instance.Id, error) {
+	m.MethodCall(m, "InstanceIds")
+	return m.instanceIds, m.NextErr()
+}
+
+func (m *mockBackend) VolumeIds() ([]string, error) {
+	m.MethodCall(m, "VolumeIds")
+	return m.volumeIds, m.NextErr()
+}
+
+type mockLister struct {
+	testing.Stub
+	resources []environs.TaggedResource
+}
+
+func (m *mockLister) TaggedResources(tags map[string]string) ([]environs.TaggedResource, error) {
+	m.MethodCall(m, "TaggedResources", tags)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return m.resources, nil
+}
+
+type mockEnviron struct {
+	environs.Environ
+}
+
+type mockListerEnviron struct {
+	environs.Environ
+	*mockLister
+}
@@ -0,0 +1,53 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package relationdata
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state functionality required by the
+// relationdata facade.
+type Backend interface {
+	// ModelTag returns the tag of the model.
+	ModelTag() names.ModelTag
+
+	// Relation returns the relation with the given id.
+	Relation(id int) (Relation, error)
+}
+
+// Relation defines the relation functionality required by the
+// relationdata facade.
+type Relation interface {
+	String() string
+	Id() int
+	Endpoints() []state.Endpoint
+
+	// UnitSettings returns the settings of every unit in the
+	// relation's scope, keyed on unit name.
+	UnitSettings() (map[string]map[string]interface{}, error)
+}
+
+// This type is an untested shim to let us wrap state in a sensible
+// interface and avoid writing tests that depend on mongodb. If you were
+// to change any part of it so that it were no longer *obviously* and
+// *trivially* correct, you would be Doing It Wrong.
+type stateShim struct {
+	*state.State
+}
+
+func (s stateShim) ModelTag() names.ModelTag {
+	return names.NewModelTag(s.State.ModelUUID())
+}
+
+func (s stateShim) Relation(id int) (Relation, error) {
+	rel, err := s.State.Relation(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return rel, nil
+}
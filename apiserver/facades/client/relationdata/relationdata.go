@@ -0,0 +1,156 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package relationdata provides the RelationData facade, through which
+// operators inspect the data that the units of a relation have
+// exchanged, without running relation-get on the units themselves.
+package relationdata
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+)
+
+// secretWords are the words that, appearing in the name of a
+// relation setting, suggest that its value is a secret.
+var secretWords = []string{
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"key",
+	"private",
+	"credential",
+}
+
+// API provides the RelationData facade for v1.
+type API struct {
+	backend    Backend
+	authorizer facade.Authorizer
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(stateShim{ctx.State()}, ctx.Auth())
+}
+
+// NewAPI returns a new RelationData API facade.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		authorizer: authorizer,
+	}, nil
+}
+
+func (api *API) checkAccess(access permission.Access) error {
+	allowed, err := api.authorizer.HasPermission(access, api.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !allowed {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// RelationData returns the data bags of the units in scope in each of
+// the specified relations. Settings whose names suggest that they hold
+// secrets are redacted unless Reveal is set, which requires admin
+// access to the model.
+func (api *API) RelationData(args params.RelationDataArgs) (params.RelationDataResults, error) {
+	if err := api.checkAccess(permission.ReadAccess); err != nil {
+		return params.RelationDataResults{}, errors.Trace(err)
+	}
+	if args.Reveal {
+		if err := api.checkAccess(permission.AdminAccess); err != nil {
+			return params.RelationDataResults{}, errors.Trace(err)
+		}
+	}
+	results := params.RelationDataResults{
+		Results: make([]params.RelationDataResult, len(args.RelationIds)),
+	}
+	for i, id := range args.RelationIds {
+		data, err := api.relationData(id, args.Reveal)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = data
+	}
+	return results, nil
+}
+
+func (api *API) relationData(id int, reveal bool) (*params.RelationData, error) {
+	rel, err := api.backend.Relation(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	settings, err := rel.UnitSettings()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	data := &params.RelationData{
+		Id:  rel.Id(),
+		Key: rel.String(),
+	}
+	for _, ep := range rel.Endpoints() {
+		endpoint := params.RelationEndpointData{
+			ApplicationName: ep.ApplicationName,
+			Name:            ep.Name,
+			Role:            string(ep.Role),
+		}
+		for unitName, unitSettings := range settings {
+			appName, err := names.UnitApplication(unitName)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if appName != ep.ApplicationName {
+				continue
+			}
+			if endpoint.Units == nil {
+				endpoint.Units = make(map[string]params.RelationUnitData)
+			}
+			endpoint.Units[unitName] = unitData(unitSettings, reveal)
+		}
+		data.Endpoints = append(data.Endpoints, endpoint)
+	}
+	return data, nil
+}
+
+// unitData returns the supplied unit settings with the values of
+// secret settings removed, unless reveal is true.
+func unitData(settings map[string]interface{}, reveal bool) params.RelationUnitData {
+	data := params.RelationUnitData{
+		Settings: make(map[string]interface{}, len(settings)),
+	}
+	for name, value := range settings {
+		if !reveal && isSecret(name) {
+			data.Redacted = append(data.Redacted, name)
+			continue
+		}
+		data.Settings[name] = value
+	}
+	sort.Strings(data.Redacted)
+	return data
+}
+
+func isSecret(name string) bool {
+	name = strings.ToLower(name)
+	for _, word := range secretWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package relationdata_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/relationdata"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type RelationDataSuite struct {
+	testing.IsolationSuite
+	backend    mockBackend
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&RelationDataSuite{})
+
+func (s *RelationDataSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("admin"),
+	}
+	s.backend = mockBackend{
+		relations: map[int]*mockRelation{
+			1: {
+				id:  1,
+				key: "wordpress:db mysql:server",
+				endpoints: []state.Endpoint{{
+					ApplicationName: "wordpress",
+					Relation:        charm.Relation{Name: "db", Role: charm.RoleRequirer},
+				}, {
+					ApplicationName: "mysql",
+					Relation:        charm.Relation{Name: "server", Role: charm.RoleProvider},
+				}},
+				settings: map[string]map[string]interface{}{
+					"wordpress/0": {"private-address": "10.0.0.1"},
+					"mysql/0": {
+						"host":     "10.0.0.2",
+						"user":     "wordpress",
+						"password": "sekrit",
+						"API-Key":  "0123",
+					},
+				},
+			},
+		},
+	}
+}
+
+func (s *RelationDataSuite) TestNewAPIRequiresClient(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("0")
+	_, err := relationdata.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *RelationDataSuite) TestRelationData(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("read")
+	api, err := relationdata.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := api.RelationData(params.RelationDataArgs{RelationIds: []int{1, 2}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.RelationDataResults{
+		Results: []params.RelationDataResult{{
+			Result: &params.RelationData{
+				Id:  1,
+				Key: "wordpress:db mysql:server",
+				Endpoints: []params.RelationEndpointData{{
+					ApplicationName: "wordpress",
+					Name:            "db",
+					Role:            "requirer",
+					Units: map[string]params.RelationUnitData{
+						"wordpress/0": {
+							Settings: map[string]interface{}{"private-address": "10.0.0.1"},
+						},
+					},
+				}, {
+					ApplicationName: "mysql",
+					Name:            "server",
+					Role:            "provider",
+					Units: map[string]params.RelationUnitData{
+						"mysql/0": {
+							Settings: map[string]interface{}{"host": "10.0.0.2", "user": "wordpress"},
+							Redacted: []string{"API-Key", "password"},
+						},
+					},
+				}},
+			},
+		}, {
+			Error: &params.Error{Code: params.CodeNotFound, Message: "relation 2 not found"},
+		}},
+	})
+	s.backend.CheckCallNames(c, "ModelTag", "Relation", "Relation")
+}
+
+func (s *RelationDataSuite) TestRelationDataReveal(c *gc.C) {
+	api, err := relationdata.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := api.RelationData(params.RelationDataArgs{RelationIds: []int{1}, Reveal: true})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Result.Endpoints[1].Units["mysql/0"], jc.DeepEquals, params.RelationUnitData{
+		Settings: map[string]interface{}{
+			"host":     "10.0.0.2",
+			"user":     "wordpress",
+			"password": "sekrit",
+			"API-Key":  "0123",
+		},
+	})
+}
+
+func (s *RelationDataSuite) TestRelationDataRevealRequiresAdmin(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("read")
+	api, err := relationdata.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.RelationData(params.RelationDataArgs{RelationIds: []int{1}, Reveal: true})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "ModelTag", "ModelTag")
+}
+
+func (s *RelationDataSuite) TestRelationDataPermissionDenied(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("fred")
+	api, err := relationdata.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.RelationData(params.RelationDataArgs{RelationIds: []int{1}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "ModelTag")
+}
+
+func (s *RelationDataSuite) TestRelationDataSettingsError(c *gc.C) {
+	s.backend.relations[1].SetErrors(errors.New("boom"))
+	api, err := relationdata.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := api.RelationData(params.RelationDataArgs{RelationIds: []int{1}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "boom")
+}
+
+type mockBackend struct {
+	testing.Stub
+	relations map[int]*mockRelation
+}
+
+func (m *mockBackend) ModelTag() names.ModelTag {
+	m.MethodCall(m, "ModelTag")
+	return coretesting.ModelTag
+}
+
+func (m *mockBackend) Relation(id int) (relationdata.Relation, error) {
+	m.MethodCall(m, "Relation", id)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	rel, ok := m.relations[id]
+	if !ok {
+		return nil, errors.NotFoundf("relation %d", id)
+	}
+	return rel, nil
+}
+
+type mockRelation struct {
+	testing.Stub
+	id        int
+	key       string
+	endpoints []state.Endpoint
+	settings  map[string]map[string]interface{}
+}
+
+func (m *mockRelation) String() string {
+	return m.key
+}
+
+func (m *mockRelation) Id() int {
+	return m.id
+}
+
+func (m *mockRelation) Endpoints() []state.Endpoint {
+	return m.endpoints
+}
+
+func (m *mockRelation) UnitSettings() (map[string]map[string]interface{}, error) {
+	m.MethodCall(m, "UnitSettings")
+	return m.settings, m.NextErr()
+}
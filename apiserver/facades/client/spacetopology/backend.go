@@ -0,0 +1,171 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package spacetopology
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state functionality required by the
+// spacetopology facade.
+type Backend interface {
+	// ModelTag returns the tag of the model.
+	ModelTag() names.ModelTag
+
+	// AllSpaces returns the model's spaces.
+	AllSpaces() ([]Space, error)
+
+	// AllSubnets returns the model's subnets.
+	AllSubnets() ([]Subnet, error)
+
+	// AllMachines returns the model's machines.
+	AllMachines() ([]Machine, error)
+
+	// AllApplications returns the model's applications.
+	AllApplications() ([]Application, error)
+}
+
+// Space defines the space functionality required by the spacetopology
+// facade.
+type Space interface {
+	Name() string
+	IsPublic() bool
+	ProviderId() network.Id
+}
+
+// Subnet defines the subnet functionality required by the
+// spacetopology facade.
+type Subnet interface {
+	CIDR() string
+	SpaceName() string
+	VLANTag() int
+	AvailabilityZone() string
+	ProviderId() network.Id
+}
+
+// Machine defines the machine functionality required by the
+// spacetopology facade.
+type Machine interface {
+	Id() string
+	AllAddresses() ([]Address, error)
+}
+
+// Address defines the machine address functionality required by the
+// spacetopology facade.
+type Address interface {
+	Value() string
+	DeviceName() string
+	SubnetCIDR() string
+}
+
+// Application defines the application functionality required by the
+// spacetopology facade.
+type Application interface {
+	Name() string
+	EndpointBindings() (map[string]string, error)
+	AllUnits() ([]Unit, error)
+}
+
+// Unit defines the unit functionality required by the spacetopology
+// facade.
+type Unit interface {
+	Name() string
+	AssignedMachineId() (string, error)
+}
+
+// This type is an untested shim to let us wrap state in a sensible
+// interface and avoid writing tests that depend on mongodb. If you were
+// to change any part of it so that it were no longer *obviously* and
+// *trivially* correct, you would be Doing It Wrong.
+type stateShim struct {
+	*state.State
+}
+
+func (s stateShim) ModelTag() names.ModelTag {
+	return names.NewModelTag(s.State.ModelUUID())
+}
+
+func (s stateShim) AllSpaces() ([]Space, error) {
+	spaces, err := s.State.AllSpaces()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]Space, len(spaces))
+	for i, space := range spaces {
+		result[i] = space
+	}
+	return result, nil
+}
+
+func (s stateShim) AllSubnets() ([]Subnet, error) {
+	subnets, err := s.State.AllSubnets()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]Subnet, len(subnets))
+	for i, subnet := range subnets {
+		result[i] = subnet
+	}
+	return result, nil
+}
+
+func (s stateShim) AllMachines() ([]Machine, error) {
+	machines, err := s.State.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]Machine, len(machines))
+	for i, machine := range machines {
+		result[i] = machineShim{machine}
+	}
+	return result, nil
+}
+
+func (s stateShim) AllApplications() ([]Application, error) {
+	applications, err := s.State.AllApplications()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]Application, len(applications))
+	for i, application := range applications {
+		result[i] = applicationShim{application}
+	}
+	return result, nil
+}
+
+type machineShim struct {
+	*state.Machine
+}
+
+func (m machineShim) AllAddresses() ([]Address, error) {
+	addresses, err := m.Machine.AllAddresses()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]Address, len(addresses))
+	for i, address := range addresses {
+		result[i] = address
+	}
+	return result, nil
+}
+
+type applicationShim struct {
+	*state.Application
+}
+
+func (a applicationShim) AllUnits() ([]Unit, error) {
+	units, err := a.Application.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]Unit, len(units))
+	for i, unit := range units {
+		result[i] = unit
+	}
+	return result, nil
+}
@@ -0,0 +1,294 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package spacetopology provides the SpaceTopology facade, which
+// reports how a model's spaces, subnets, machines and application
+// endpoint bindings fit together, as a graph that dashboards can
+// render as a network diagram.
+package spacetopology
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/permission"
+)
+
+// The kinds of the nodes in a topology.
+const (
+	spaceNode       = "space"
+	subnetNode      = "subnet"
+	machineNode     = "machine"
+	applicationNode = "application"
+)
+
+// The kinds of the edges in a topology.
+const (
+	containsEdge = "contains"
+	addressEdge  = "address"
+	bindingEdge  = "binding"
+	unitEdge     = "unit"
+)
+
+// defaultSpaceLabel labels the node of the default space, which has no
+// name.
+const defaultSpaceLabel = "(default)"
+
+// API provides the SpaceTopology facade for v1.
+type API struct {
+	backend    Backend
+	authorizer facade.Authorizer
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(stateShim{ctx.State()}, ctx.Auth())
+}
+
+// NewAPI returns a new SpaceTopology API facade.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		authorizer: authorizer,
+	}, nil
+}
+
+func (api *API) checkCanRead() error {
+	canRead, err := api.authorizer.HasPermission(permission.ReadAccess, api.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !canRead {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// Topology returns the model's spaces, subnets, machines and
+// applications, and the ways they are connected, as a graph. Nodes
+// are sorted by id, and edges by the ids they join. It requires read
+// access to the model.
+func (api *API) Topology() (params.SpaceTopology, error) {
+	if err := api.checkCanRead(); err != nil {
+		return params.SpaceTopology{}, errors.Trace(err)
+	}
+	g := &graph{nodes: make(map[string]params.TopologyNode)}
+
+	spaces, err := api.backend.AllSpaces()
+	if err != nil {
+		return params.SpaceTopology{}, errors.Annotate(err, "getting spaces")
+	}
+	for _, space := range spaces {
+		attrs := map[string]string{"public": strconv.FormatBool(space.IsPublic())}
+		if id := space.ProviderId(); id != "" {
+			attrs["provider-id"] = string(id)
+		}
+		g.addNode(spaceNode, space.Name(), attrs)
+	}
+
+	subnets, err := api.backend.AllSubnets()
+	if err != nil {
+		return params.SpaceTopology{}, errors.Annotate(err, "getting subnets")
+	}
+	for _, subnet := range subnets {
+		attrs := make(map[string]string)
+		if tag := subnet.VLANTag(); tag != 0 {
+			attrs["vlan-tag"] = strconv.Itoa(tag)
+		}
+		if zone := subnet.AvailabilityZone(); zone != "" {
+			attrs["availability-zone"] = zone
+		}
+		if id := subnet.ProviderId(); id != "" {
+			attrs["provider-id"] = string(id)
+		}
+		id := g.addNode(subnetNode, subnet.CIDR(), attrs)
+		g.addEdge(g.space(subnet.SpaceName()), id, containsEdge, nil)
+	}
+
+	machines, err := api.backend.AllMachines()
+	if err != nil {
+		return params.SpaceTopology{}, errors.Annotate(err, "getting machines")
+	}
+	for _, machine := range machines {
+		id := g.addNode(machineNode, machine.Id(), nil)
+		addresses, err := machine.AllAddresses()
+		if err != nil {
+			return params.SpaceTopology{}, errors.Annotatef(err, "getting addresses of machine %q", machine.Id())
+		}
+		for _, address := range addresses {
+			// Addresses outside the model's subnets, such as
+			// loopback addresses, are left out.
+			subnetId, ok := g.node(subnetNode, address.SubnetCIDR())
+			if !ok {
+				continue
+			}
+			g.addEdge(id, subnetId, addressEdge, map[string]string{
+				"address": address.Value(),
+				"device":  address.DeviceName(),
+			})
+		}
+	}
+
+	applications, err := api.backend.AllApplications()
+	if err != nil {
+		return params.SpaceTopology{}, errors.Annotate(err, "getting applications")
+	}
+	for _, application := range applications {
+		if err := g.addApplication(application); err != nil {
+			return params.SpaceTopology{}, errors.Annotatef(err, "application %q", application.Name())
+		}
+	}
+	return g.topology(), nil
+}
+
+// graph accumulates the nodes and edges of a topology.
+type graph struct {
+	nodes map[string]params.TopologyNode
+	edges []params.TopologyEdge
+}
+
+// addNode adds a node of the given kind, and returns its id.
+func (g *graph) addNode(kind, label string, attrs map[string]string) string {
+	id := nodeId(kind, label)
+	if len(attrs) == 0 {
+		attrs = nil
+	}
+	g.nodes[id] = params.TopologyNode{
+		Id:         id,
+		Kind:       kind,
+		Label:      label,
+		Attributes: attrs,
+	}
+	return id
+}
+
+// node returns the id of the node of the given kind and label, and
+// whether it has been added.
+func (g *graph) node(kind, label string) (string, bool) {
+	id := nodeId(kind, label)
+	_, ok := g.nodes[id]
+	return id, ok
+}
+
+// space returns the id of the named space's node. The default space,
+// and any space not already added, is added as it is first needed.
+func (g *graph) space(name string) string {
+	id, ok := g.node(spaceNode, name)
+	if ok {
+		return id
+	}
+	if name == environs.DefaultSpaceName {
+		g.nodes[id] = params.TopologyNode{
+			Id:         id,
+			Kind:       spaceNode,
+			Label:      defaultSpaceLabel,
+			Attributes: map[string]string{"default": "true"},
+		}
+		return id
+	}
+	return g.addNode(spaceNode, name, nil)
+}
+
+// addEdge adds an edge of the given kind joining the nodes.
+func (g *graph) addEdge(from, to, kind string, attrs map[string]string) {
+	g.edges = append(g.edges, params.TopologyEdge{
+		From:       from,
+		To:         to,
+		Kind:       kind,
+		Attributes: attrs,
+	})
+}
+
+// addApplication adds the application's node, and the edges joining
+// it to the spaces its endpoints are bound to and to the machines its
+// units are assigned to.
+func (g *graph) addApplication(application Application) error {
+	id := g.addNode(applicationNode, application.Name(), nil)
+	bindings, err := application.EndpointBindings()
+	if err != nil {
+		return errors.Annotate(err, "getting endpoint bindings")
+	}
+	for endpoint, space := range bindings {
+		// The application's default binding applies only to
+		// endpoints added later, which are bound as they are added.
+		if endpoint == "" {
+			continue
+		}
+		g.addEdge(id, g.space(space), bindingEdge, map[string]string{"endpoint": endpoint})
+	}
+	units, err := application.AllUnits()
+	if err != nil {
+		return errors.Annotate(err, "getting units")
+	}
+	for _, unit := range units {
+		machineId, err := unit.AssignedMachineId()
+		if errors.IsNotAssigned(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if to, ok := g.node(machineNode, machineId); ok {
+			g.addEdge(id, to, unitEdge, map[string]string{"unit": unit.Name()})
+		}
+	}
+	return nil
+}
+
+// topology returns the topology, with its nodes and edges sorted.
+func (g *graph) topology() params.SpaceTopology {
+	result := params.SpaceTopology{
+		Nodes: make([]params.TopologyNode, 0, len(g.nodes)),
+		Edges: g.edges,
+	}
+	for _, node := range g.nodes {
+		result.Nodes = append(result.Nodes, node)
+	}
+	sort.Slice(result.Nodes, func(i, j int) bool {
+		return result.Nodes[i].Id < result.Nodes[j].Id
+	})
+	if result.Edges == nil {
+		result.Edges = []params.TopologyEdge{}
+	}
+	sort.SliceStable(result.Edges, func(i, j int) bool {
+		a, b := result.Edges[i], result.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return edgeDetail(a) < edgeDetail(b)
+	})
+	return result
+}
+
+// nodeId returns the id of the node of the given kind and label.
+func nodeId(kind, label string) string {
+	return kind + ":" + label
+}
+
+// edgeDetail returns the attribute that distinguishes edges of the
+// same kind joining the same nodes.
+func edgeDetail(edge params.TopologyEdge) string {
+	switch edge.Kind {
+	case addressEdge:
+		return edge.Attributes["address"]
+	case bindingEdge:
+		return edge.Attributes["endpoint"]
+	case unitEdge:
+		return edge.Attributes["unit"]
+	}
+	return ""
+}
@@ -0,0 +1,323 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package spacetopology_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/spacetopology"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type TopologySuite struct {
+	testing.IsolationSuite
+	backend    *mockBackend
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&TopologySuite{})
+
+func (s *TopologySuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("admin"),
+	}
+	s.backend = &mockBackend{
+		spaces: []spacetopology.Space{
+			&mockSpace{name: "public", public: true, providerId: "sp-1"},
+			&mockSpace{name: "internal"},
+		},
+		subnets: []spacetopology.Subnet{
+			&mockSubnet{cidr: "10.0.0.0/24", space: "public", zone: "zone1", providerId: "sn-1"},
+			&mockSubnet{cidr: "10.1.0.0/24", space: "internal", vlanTag: 42},
+			&mockSubnet{cidr: "10.2.0.0/24"},
+		},
+		machines: []spacetopology.Machine{
+			&mockMachine{id: "0", addresses: []spacetopology.Address{
+				&mockAddress{value: "10.0.0.5", device: "eth0", cidr: "10.0.0.0/24"},
+				&mockAddress{value: "10.1.0.5", device: "eth1", cidr: "10.1.0.0/24"},
+				&mockAddress{value: "127.0.0.1", device: "lo", cidr: "127.0.0.0/8"},
+			}},
+			&mockMachine{id: "1"},
+		},
+		applications: []spacetopology.Application{
+			&mockApplication{
+				name: "wordpress",
+				bindings: map[string]string{
+					"":      "internal",
+					"url":   "public",
+					"db":    "internal",
+					"cache": "",
+				},
+				units: []spacetopology.Unit{
+					&mockUnit{name: "wordpress/0", machineId: "0"},
+					&mockUnit{name: "wordpress/1"},
+				},
+			},
+		},
+	}
+}
+
+func (s *TopologySuite) newAPI(c *gc.C) *spacetopology.API {
+	api, err := spacetopology.NewAPI(s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *TopologySuite) TestNewAPIRequiresClient(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("0")
+	_, err := spacetopology.NewAPI(s.backend, s.authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *TopologySuite) TestTopologyRequiresRead(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("nobody")
+	_, err := s.newAPI(c).Topology()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "ModelTag")
+}
+
+func (s *TopologySuite) TestTopology(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("read")
+	result, err := s.newAPI(c).Topology()
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.CheckCallNames(c, "ModelTag", "AllSpaces", "AllSubnets", "AllMachines", "AllApplications")
+	c.Assert(result.Nodes, jc.DeepEquals, []params.TopologyNode{{
+		Id:    "application:wordpress",
+		Kind:  "application",
+		Label: "wordpress",
+	}, {
+		Id:    "machine:0",
+		Kind:  "machine",
+		Label: "0",
+	}, {
+		Id:    "machine:1",
+		Kind:  "machine",
+		Label: "1",
+	}, {
+		Id:         "space:",
+		Kind:       "space",
+		Label:      "(default)",
+		Attributes: map[string]string{"default": "true"},
+	}, {
+		Id:         "space:internal",
+		Kind:       "space",
+		Label:      "internal",
+		Attributes: map[string]string{"public": "false"},
+	}, {
+		Id:    "space:public",
+		Kind:  "space",
+		Label: "public",
+		Attributes: map[string]string{
+			"public":      "true",
+			"provider-id": "sp-1",
+		},
+	}, {
+		Id:    "subnet:10.0.0.0/24",
+		Kind:  "subnet",
+		Label: "10.0.0.0/24",
+		Attributes: map[string]string{
+			"availability-zone": "zone1",
+			"provider-id":       "sn-1",
+		},
+	}, {
+		Id:         "subnet:10.1.0.0/24",
+		Kind:       "subnet",
+		Label:      "10.1.0.0/24",
+		Attributes: map[string]string{"vlan-tag": "42"},
+	}, {
+		Id:    "subnet:10.2.0.0/24",
+		Kind:  "subnet",
+		Label: "10.2.0.0/24",
+	}})
+	c.Assert(result.Edges, jc.DeepEquals, []params.TopologyEdge{{
+		From:       "application:wordpress",
+		To:         "machine:0",
+		Kind:       "unit",
+		Attributes: map[string]string{"unit": "wordpress/0"},
+	}, {
+		From:       "application:wordpress",
+		To:         "space:",
+		Kind:       "binding",
+		Attributes: map[string]string{"endpoint": "cache"},
+	}, {
+		From:       "application:wordpress",
+		To:         "space:internal",
+		Kind:       "binding",
+		Attributes: map[string]string{"endpoint": "db"},
+	}, {
+		From:       "application:wordpress",
+		To:         "space:public",
+		Kind:       "binding",
+		Attributes: map[string]string{"endpoint": "url"},
+	}, {
+		From:       "machine:0",
+		To:         "subnet:10.0.0.0/24",
+		Kind:       "address",
+		Attributes: map[string]string{"address": "10.0.0.5", "device": "eth0"},
+	}, {
+		From:       "machine:0",
+		To:         "subnet:10.1.0.0/24",
+		Kind:       "address",
+		Attributes: map[string]string{"address": "10.1.0.5", "device": "eth1"},
+	}, {
+		From: "space:",
+		To:   "subnet:10.2.0.0/24",
+		Kind: "contains",
+	}, {
+		From: "space:internal",
+		To:   "subnet:10.1.0.0/24",
+		Kind: "contains",
+	}, {
+		From: "space:public",
+		To:   "subnet:10.0.0.0/24",
+		Kind: "contains",
+	}})
+}
+
+func (s *TopologySuite) TestTopologyEmptyModel(c *gc.C) {
+	s.backend = &mockBackend{}
+	result, err := s.newAPI(c).Topology()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.SpaceTopology{
+		Nodes: []params.TopologyNode{},
+		Edges: []params.TopologyEdge{},
+	})
+}
+
+func (s *TopologySuite) TestTopologyError(c *gc.C) {
+	s.backend.SetErrors(errors.New("boom"))
+	_, err := s.newAPI(c).Topology()
+	c.Assert(err, gc.ErrorMatches, "getting spaces: boom")
+}
+
+func (s *TopologySuite) TestTopologyUnitError(c *gc.C) {
+	s.backend.applications = []spacetopology.Application{
+		&mockApplication{
+			name:  "wordpress",
+			units: []spacetopology.Unit{&mockUnit{name: "wordpress/0", err: errors.New("boom")}},
+		},
+	}
+	_, err := s.newAPI(c).Topology()
+	c.Assert(err, gc.ErrorMatches, `application "wordpress": boom`)
+}
+
+type mockBackend struct {
+	testing.Stub
+	spaces       []spacetopology.Space
+	subnets      []spacetopology.Subnet
+	machines     []spacetopology.Machine
+	applications []spacetopology.Application
+}
+
+func (m *mockBackend) ModelTag() names.ModelTag {
+	m.MethodCall(m, "ModelTag")
+	return coretesting.ModelTag
+}
+
+func (m *mockBackend) AllSpaces() ([]spacetopology.Space, error) {
+	m.MethodCall(m, "AllSpaces")
+	return m.spaces, m.NextErr()
+}
+
+func (m *mockBackend) AllSubnets() ([]spacetopology.Subnet, error) {
+	m.MethodCall(m, "AllSubnets")
+	return m.subnets, m.NextErr()
+}
+
+func (m *mockBackend) AllMachines() ([]spacetopology.Machine, error) {
+	m.MethodCall(m, "AllMachines")
+	return m.machines, m.NextErr()
+}
+
+func (m *mockBackend) AllApplications() ([]spacetopology.Application, error) {
+	m.MethodCall(m, "AllApplications")
+	return m.applications, m.NextErr()
+}
+
+type mockSpace struct {
+	name       string
+	public     bool
+	providerId network.Id
+}
+
+func (s *mockSpace) Name() string           { return s.name }
+func (s *mockSpace) IsPublic() bool         { return s.public }
+func (s *mockSpace) ProviderId() network.Id { return s.providerId }
+
+type mockSubnet struct {
+	cidr       string
+	space      string
+	vlanTag    int
+	zone       string
+	providerId network.Id
+}
+
+func (s *mockSubnet) CIDR() string             { return s.cidr }
+func (s *mockSubnet) SpaceName() string        { return s.space }
+func (s *mockSubnet) VLANTag() int             { return s.vlanTag }
+func (s *mockSubnet) AvailabilityZone() string { return s.zone }
+func (s *mockSubnet) ProviderId() network.Id   { return s.providerId }
+
+type mockMachine struct {
+	id        string
+	addresses []spacetopology.Address
+}
+
+func (m *mockMachine) Id() string { return m.id }
+
+func (m *mockMachine) AllAddresses() ([]spacetopology.Address, error) {
+	return m.addresses, nil
+}
+
+type mockAddress struct {
+	value  string
+	device string
+	cidr   string
+}
+
+func (a *mockAddress) Value() string      { return a.value }
+func (a *mockAddress) DeviceName() string { return a.device }
+func (a *mockAddress) SubnetCIDR() string { return a.cidr }
+
+type mockApplication struct {
+	name     string
+	bindings map[string]string
+	units    []spacetopology.Unit
+}
+
+func (a *mockApplication) Name() string { return a.name }
+
+func (a *mockApplication) EndpointBindings() (map[string]string, error) {
+	return a.bindings, nil
+}
+
+func (a *mockApplication) AllUnits() ([]spacetopology.Unit, error) {
+	return a.units, nil
+}
+
+type mockUnit struct {
+	name      string
+	machineId string
+	err       error
+}
+
+func (u *mockUnit) Name() string { return u.name }
+
+func (u *mockUnit) AssignedMachineId() (string, error) {
+	if u.err != nil {
+		return "", u.err
+	}
+	if u.machineId == "" {
+		return "", errors.NotAssignedf("unit %q", u.name)
+	}
+	return u.machineId, nil
+}
@@ -202,3 +202,29 @@ func (facade *Facade) Proxy() (params.SSHProxyResult, error) {
 	}
 	return params.SSHProxyResult{UseProxy: config.ProxySSH()}, nil
 }
+
+// Bastion returns the details needed to connect to the model's machines
+// through the ssh bastion running on the controller machines. The port
+// in the result is zero if the bastion is disabled.
+func (facade *Facade) Bastion() (params.SSHBastionResult, error) {
+	if err := facade.checkIsModelAdmin(); err != nil {
+		return params.SSHBastionResult{}, errors.Trace(err)
+	}
+	config, err := facade.backend.ControllerConfig()
+	if err != nil {
+		return params.SSHBastionResult{}, errors.Trace(err)
+	}
+	port := config.BastionPort()
+	if port == 0 {
+		return params.SSHBastionResult{}, nil
+	}
+	hostKeys, err := facade.backend.ControllerSSHHostKeys()
+	if err != nil {
+		return params.SSHBastionResult{}, errors.Trace(err)
+	}
+	return params.SSHBastionResult{
+		Port:      port,
+		ModelUUID: facade.backend.ModelTag().Id(),
+		HostKeys:  hostKeys,
+	}, nil
+}
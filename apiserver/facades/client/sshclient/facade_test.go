@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/sshclient"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/network"
@@ -177,9 +178,54 @@ func (s *facadeSuite) TestProxyFalse(c *gc.C) {
 	})
 }
 
+func (s *facadeSuite) TestBastion(c *gc.C) {
+	s.backend.bastionPort = 17022
+	result, err := s.facade.Bastion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result, jc.DeepEquals, params.SSHBastionResult{
+		Port:      17022,
+		ModelUUID: "deadbeef-2f18-4fd2-967d-db9663db7bea",
+		HostKeys:  []string{"rsa-controller"},
+	})
+	s.backend.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"ControllerConfig", []interface{}{}},
+		{"ControllerSSHHostKeys", []interface{}{}},
+	})
+}
+
+func (s *facadeSuite) TestBastionDisabled(c *gc.C) {
+	result, err := s.facade.Bastion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result, jc.DeepEquals, params.SSHBastionResult{})
+	s.backend.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"ControllerConfig", []interface{}{}},
+	})
+}
+
+func (s *facadeSuite) TestBastionNotModelAdmin(c *gc.C) {
+	s.authorizer.AdminTag = names.NewUserTag("alice")
+	_, err := s.facade.Bastion()
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
 type mockBackend struct {
-	stub     jujutesting.Stub
-	proxySSH bool
+	stub        jujutesting.Stub
+	proxySSH    bool
+	bastionPort int
+}
+
+func (backend *mockBackend) ControllerConfig() (controller.Config, error) {
+	backend.stub.AddCall("ControllerConfig")
+	cfg := testing.FakeControllerConfig()
+	if backend.bastionPort != 0 {
+		cfg[controller.BastionPort] = backend.bastionPort
+	}
+	return cfg, nil
+}
+
+func (backend *mockBackend) ControllerSSHHostKeys() ([]string, error) {
+	backend.stub.AddCall("ControllerSSHHostKeys")
+	return []string{"rsa-controller"}, nil
 }
 
 func (backend *mockBackend) ModelTag() names.ModelTag {
@@ -8,6 +8,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/network"
@@ -22,6 +23,8 @@ type Backend interface {
 	GetMachineForEntity(tag string) (SSHMachine, error)
 	GetSSHHostKeys(names.MachineTag) (state.SSHHostKeys, error)
 	ModelTag() names.ModelTag
+	ControllerConfig() (controller.Config, error)
+	ControllerSSHHostKeys() ([]string, error)
 }
 
 // SSHMachine specifies the methods on State.Machine of interest to
@@ -34,17 +37,45 @@ type SSHMachine interface {
 	AllNetworkAddresses() ([]network.Address, error)
 }
 
-// NewFacade wraps New to express the supplied *state.State as a Backend.
-func NewFacade(st *state.State, res facade.Resources, auth facade.Authorizer) (*Facade, error) {
+// NewFacade wraps New to express the supplied context's *state.State
+// as a Backend.
+func NewFacade(ctx facade.Context) (*Facade, error) {
+	st := ctx.State()
 	m, err := st.Model()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return New(&backend{stateenvirons.EnvironConfigGetter{st, m}}, res, auth)
+	return New(&backend{stateenvirons.EnvironConfigGetter{st, m}, ctx.StatePool()}, ctx.Resources(), ctx.Auth())
 }
 
 type backend struct {
 	stateenvirons.EnvironConfigGetter
+	pool *state.StatePool
+}
+
+// ControllerSSHHostKeys returns the ssh host keys of the controller
+// machines, which are also presented by the controller's ssh bastion.
+func (b *backend) ControllerSSHHostKeys() ([]string, error) {
+	info, err := b.State.ControllerInfo()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	st, release, err := b.pool.Get(info.ModelTag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer release()
+	var keys []string
+	for _, id := range info.MachineIds {
+		machineKeys, err := st.GetSSHHostKeys(names.NewMachineTag(id))
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		keys = append(keys, machineKeys...)
+	}
+	return keys, nil
 }
 
 // GetMachineForEntity takes a machine or unit tag (as a string) and
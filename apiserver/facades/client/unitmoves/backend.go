@@ -0,0 +1,53 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package unitmoves
+
+import (
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state functionality required by the unitmoves
+// facade.
+type Backend interface {
+	// ModelTag returns the tag of the model.
+	ModelTag() names.ModelTag
+
+	// MoveUnit starts moving the named unit to the machine chosen by
+	// the placement directive.
+	MoveUnit(unitName string, placement *instance.Placement) (state.UnitMove, error)
+
+	// UnitMove returns the most recent move of the named unit.
+	UnitMove(unitName string) (state.UnitMove, error)
+
+	// EvacuateMachine starts moving the units off the machine with
+	// the given id, so that it can be removed.
+	EvacuateMachine(machineId string, placement *instance.Placement) (state.MachineEvacuation, error)
+
+	// MachineEvacuation returns the evacuation of the machine with
+	// the given id.
+	MachineEvacuation(machineId string) (state.MachineEvacuation, error)
+}
+
+// BlockChecker defines the block-checking functionality required by
+// the unitmoves facade. This is implemented by
+// apiserver/common.BlockChecker.
+type BlockChecker interface {
+	ChangeAllowed() error
+	RemoveAllowed() error
+}
+
+// This type is an untested shim to let us wrap state in a sensible
+// interface and avoid writing tests that depend on mongodb. If you were
+// to change any part of it so that it were no longer *obviously* and
+// *trivially* correct, you would be Doing It Wrong.
+type stateShim struct {
+	*state.State
+}
+
+func (s stateShim) ModelTag() names.ModelTag {
+	return names.NewModelTag(s.State.ModelUUID())
+}
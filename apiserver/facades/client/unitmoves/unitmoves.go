@@ -0,0 +1,198 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package unitmoves provides the UnitMoves facade, through which
+// operators move units to other machines. A move deploys a replacement
+// unit on the target machine and retires the original once the
+// replacement has joined its relations; a controller worker drives
+// each move through its phases. Whole machines can be evacuated for
+// maintenance, after which they are removed.
+package unitmoves
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+// API provides the UnitMoves facade for v1.
+type API struct {
+	backend    Backend
+	check      BlockChecker
+	authorizer facade.Authorizer
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(stateShim{ctx.State()}, common.NewBlockChecker(ctx.State()), ctx.Auth())
+}
+
+// NewAPI returns a new UnitMoves API facade.
+func NewAPI(backend Backend, blockChecker BlockChecker, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		check:      blockChecker,
+		authorizer: authorizer,
+	}, nil
+}
+
+func (api *API) checkPermission(access permission.Access) error {
+	allowed, err := api.authorizer.HasPermission(access, api.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !allowed {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// MoveUnits starts moving each of the given units to the machine
+// chosen by its placement directive, or to a new machine if it has
+// none. The moves complete in the background; their progress is
+// reported by UnitMoves.
+func (api *API) MoveUnits(args params.MoveUnitArgs) (params.UnitMoveResults, error) {
+	if err := api.checkPermission(permission.WriteAccess); err != nil {
+		return params.UnitMoveResults{}, errors.Trace(err)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.UnitMoveResults{}, errors.Trace(err)
+	}
+	results := params.UnitMoveResults{
+		Results: make([]params.UnitMoveResult, len(args.Units)),
+	}
+	for i, arg := range args.Units {
+		tag, err := names.ParseUnitTag(arg.UnitTag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		move, err := api.backend.MoveUnit(tag.Id(), arg.Placement)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = unitMoveParams(move)
+	}
+	return results, nil
+}
+
+// UnitMoves returns the progress of the most recent move of each of
+// the given units.
+func (api *API) UnitMoves(args params.Entities) (params.UnitMoveResults, error) {
+	if err := api.checkPermission(permission.ReadAccess); err != nil {
+		return params.UnitMoveResults{}, errors.Trace(err)
+	}
+	results := params.UnitMoveResults{
+		Results: make([]params.UnitMoveResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		move, err := api.backend.UnitMove(tag.Id())
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = unitMoveParams(move)
+	}
+	return results, nil
+}
+
+// EvacuateMachines starts moving the units off each of the given
+// machines, so that the machines can be removed. Units with storage
+// are not moved; they are reported so that they can be moved by hand.
+func (api *API) EvacuateMachines(args params.EvacuateMachineArgs) (params.MachineEvacuationResults, error) {
+	if err := api.checkPermission(permission.WriteAccess); err != nil {
+		return params.MachineEvacuationResults{}, errors.Trace(err)
+	}
+	if err := api.check.RemoveAllowed(); err != nil {
+		return params.MachineEvacuationResults{}, errors.Trace(err)
+	}
+	results := params.MachineEvacuationResults{
+		Results: make([]params.MachineEvacuationResult, len(args.Machines)),
+	}
+	for i, arg := range args.Machines {
+		tag, err := names.ParseMachineTag(arg.MachineTag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		evacuation, err := api.backend.EvacuateMachine(tag.Id(), arg.Placement)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = machineEvacuationParams(evacuation)
+	}
+	return results, nil
+}
+
+// MachineEvacuations returns the progress of the evacuation of each of
+// the given machines.
+func (api *API) MachineEvacuations(args params.Entities) (params.MachineEvacuationResults, error) {
+	if err := api.checkPermission(permission.ReadAccess); err != nil {
+		return params.MachineEvacuationResults{}, errors.Trace(err)
+	}
+	results := params.MachineEvacuationResults{
+		Results: make([]params.MachineEvacuationResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		evacuation, err := api.backend.MachineEvacuation(tag.Id())
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = machineEvacuationParams(evacuation)
+	}
+	return results, nil
+}
+
+func unitMoveParams(move state.UnitMove) *params.UnitMove {
+	return &params.UnitMove{
+		UnitTag:        names.NewUnitTag(move.Unit).String(),
+		ReplacementTag: names.NewUnitTag(move.Replacement).String(),
+		Phase:          string(move.Phase),
+		Message:        move.Message,
+		Started:        move.Started,
+		Updated:        move.Updated,
+	}
+}
+
+func machineEvacuationParams(evacuation state.MachineEvacuation) *params.MachineEvacuation {
+	result := &params.MachineEvacuation{
+		MachineTag: names.NewMachineTag(evacuation.Machine).String(),
+		Started:    evacuation.Started,
+		Done:       evacuation.Done,
+	}
+	for _, unitName := range evacuation.Moves {
+		result.MoveTags = append(result.MoveTags, names.NewUnitTag(unitName).String())
+	}
+	for _, unit := range evacuation.Manual {
+		manual := params.EvacuatingUnit{
+			UnitTag: names.NewUnitTag(unit.Unit).String(),
+			Reason:  unit.Reason,
+		}
+		for _, id := range unit.Storage {
+			manual.StorageTags = append(manual.StorageTags, names.NewStorageTag(id).String())
+		}
+		result.Manual = append(result.Manual, manual)
+	}
+	return result
+}
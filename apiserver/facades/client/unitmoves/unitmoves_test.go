@@ -0,0 +1,249 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package unitmoves_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/unitmoves"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type UnitMovesSuite struct {
+	testing.IsolationSuite
+	backend      mockBackend
+	blockChecker mockBlockChecker
+	authorizer   apiservertesting.FakeAuthorizer
+	started      time.Time
+}
+
+var _ = gc.Suite(&UnitMovesSuite{})
+
+func (s *UnitMovesSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("admin"),
+	}
+	s.started = time.Date(2018, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.backend = mockBackend{
+		move: state.UnitMove{
+			Unit:        "mysql/0",
+			Replacement: "mysql/1",
+			Phase:       state.UnitMoveDeploying,
+			Message:     "waiting for unit mysql/1 to start",
+			Started:     s.started,
+			Updated:     s.started.Add(time.Minute),
+		},
+	}
+	s.blockChecker = mockBlockChecker{}
+}
+
+func (s *UnitMovesSuite) newAPI(c *gc.C) *unitmoves.API {
+	api, err := unitmoves.NewAPI(&s.backend, &s.blockChecker, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *UnitMovesSuite) TestNewAPIRequiresClient(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("0")
+	_, err := unitmoves.NewAPI(&s.backend, &s.blockChecker, s.authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *UnitMovesSuite) TestMoveUnits(c *gc.C) {
+	placement := &instance.Placement{Scope: instance.MachineScope, Directive: "3"}
+	s.backend.SetErrors(nil, errors.AlreadyExistsf("move of unit %q", "mysql/2"))
+	results, err := s.newAPI(c).MoveUnits(params.MoveUnitArgs{
+		Units: []params.MoveUnitArg{
+			{UnitTag: "unit-mysql-0", Placement: placement},
+			{UnitTag: "machine-0"},
+			{UnitTag: "unit-mysql-2"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Check(results.Results[0], jc.DeepEquals, params.UnitMoveResult{
+		Result: &params.UnitMove{
+			UnitTag:        "unit-mysql-0",
+			ReplacementTag: "unit-mysql-1",
+			Phase:          "deploying",
+			Message:        "waiting for unit mysql/1 to start",
+			Started:        s.started,
+			Updated:        s.started.Add(time.Minute),
+		},
+	})
+	c.Check(results.Results[1].Error, gc.ErrorMatches, `"machine-0" is not a valid unit tag`)
+	c.Check(results.Results[2].Error, jc.Satisfies, params.IsCodeAlreadyExists)
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"ModelTag", nil},
+		{"MoveUnit", []interface{}{"mysql/0", placement}},
+		{"MoveUnit", []interface{}{"mysql/2", (*instance.Placement)(nil)}},
+	})
+	s.blockChecker.CheckCallNames(c, "ChangeAllowed")
+}
+
+func (s *UnitMovesSuite) TestMoveUnitsBlocked(c *gc.C) {
+	s.blockChecker.SetErrors(errors.New("blocked"))
+	_, err := s.newAPI(c).MoveUnits(params.MoveUnitArgs{
+		Units: []params.MoveUnitArg{{UnitTag: "unit-mysql-0"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "blocked")
+	s.backend.CheckCallNames(c, "ModelTag")
+}
+
+func (s *UnitMovesSuite) TestMoveUnitsRequiresWriteAccess(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("fred")
+	_, err := s.newAPI(c).MoveUnits(params.MoveUnitArgs{
+		Units: []params.MoveUnitArg{{UnitTag: "unit-mysql-0"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "ModelTag")
+	s.blockChecker.CheckNoCalls(c)
+}
+
+func (s *UnitMovesSuite) TestUnitMoves(c *gc.C) {
+	s.backend.SetErrors(nil, errors.NotFoundf("move of unit %q", "mysql/2"))
+	results, err := s.newAPI(c).UnitMoves(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "unit-mysql-0"},
+			{Tag: "unit-mysql-2"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Check(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[0].Result.Phase, gc.Equals, "deploying")
+	c.Check(results.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"ModelTag", nil},
+		{"UnitMove", []interface{}{"mysql/0"}},
+		{"UnitMove", []interface{}{"mysql/2"}},
+	})
+}
+
+func (s *UnitMovesSuite) TestUnitMovesPermissionDenied(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("fred")
+	_, err := s.newAPI(c).UnitMoves(params.Entities{
+		Entities: []params.Entity{{Tag: "unit-mysql-0"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *UnitMovesSuite) TestEvacuateMachines(c *gc.C) {
+	s.backend.evacuation = state.MachineEvacuation{
+		Machine: "2",
+		Moves:   []string{"wordpress/0"},
+		Manual: []state.EvacuatingUnit{{
+			Unit:    "mysql/0",
+			Storage: []string{"database/0"},
+			Reason:  "unit has storage",
+		}},
+		Started: s.started,
+	}
+	results, err := s.newAPI(c).EvacuateMachines(params.EvacuateMachineArgs{
+		Machines: []params.EvacuateMachineArg{
+			{MachineTag: "machine-2"},
+			{MachineTag: "unit-mysql-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Check(results.Results[0], jc.DeepEquals, params.MachineEvacuationResult{
+		Result: &params.MachineEvacuation{
+			MachineTag: "machine-2",
+			MoveTags:   []string{"unit-wordpress-0"},
+			Manual: []params.EvacuatingUnit{{
+				UnitTag:     "unit-mysql-0",
+				StorageTags: []string{"storage-database-0"},
+				Reason:      "unit has storage",
+			}},
+			Started: s.started,
+		},
+	})
+	c.Check(results.Results[1].Error, gc.ErrorMatches, `"unit-mysql-0" is not a valid machine tag`)
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"ModelTag", nil},
+		{"EvacuateMachine", []interface{}{"2", (*instance.Placement)(nil)}},
+	})
+	s.blockChecker.CheckCallNames(c, "RemoveAllowed")
+}
+
+func (s *UnitMovesSuite) TestEvacuateMachinesBlocked(c *gc.C) {
+	s.blockChecker.SetErrors(errors.New("blocked"))
+	_, err := s.newAPI(c).EvacuateMachines(params.EvacuateMachineArgs{
+		Machines: []params.EvacuateMachineArg{{MachineTag: "machine-2"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "blocked")
+	s.backend.CheckCallNames(c, "ModelTag")
+}
+
+func (s *UnitMovesSuite) TestMachineEvacuations(c *gc.C) {
+	s.backend.evacuation = state.MachineEvacuation{Machine: "2", Done: true}
+	s.backend.SetErrors(nil, errors.NotFoundf("evacuation of machine 3"))
+	results, err := s.newAPI(c).MachineEvacuations(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-2"}, {Tag: "machine-3"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Check(results.Results[0].Result, jc.DeepEquals, &params.MachineEvacuation{
+		MachineTag: "machine-2",
+		Done:       true,
+	})
+	c.Check(results.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+}
+
+type mockBackend struct {
+	testing.Stub
+	move       state.UnitMove
+	evacuation state.MachineEvacuation
+}
+
+func (m *mockBackend) ModelTag() names.ModelTag {
+	m.MethodCall(m, "ModelTag")
+	return coretesting.ModelTag
+}
+
+func (m *mockBackend) MoveUnit(unitName string, placement *instance.Placement) (state.UnitMove, error) {
+	m.MethodCall(m, "MoveUnit", unitName, placement)
+	return m.move, m.NextErr()
+}
+
+func (m *mockBackend) UnitMove(unitName string) (state.UnitMove, error) {
+	m.MethodCall(m, "UnitMove", unitName)
+	return m.move, m.NextErr()
+}
+
+func (m *mockBackend) EvacuateMachine(machineId string, placement *instance.Placement) (state.MachineEvacuation, error) {
+	m.MethodCall(m, "EvacuateMachine", machineId, placement)
+	return m.evacuation, m.NextErr()
+}
+
+func (m *mockBackend) MachineEvacuation(machineId string) (state.MachineEvacuation, error) {
+	m.MethodCall(m, "MachineEvacuation", machineId)
+	return m.evacuation, m.NextErr()
+}
+
+type mockBlockChecker struct {
+	testing.Stub
+}
+
+func (c *mockBlockChecker) ChangeAllowed() error {
+	c.MethodCall(c, "ChangeAllowed")
+	return c.NextErr()
+}
+
+func (c *mockBlockChecker) RemoveAllowed() error {
+	c.MethodCall(c, "RemoveAllowed")
+	return c.NextErr()
+}
@@ -0,0 +1,88 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package usermanager
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// checkCanManageLockouts returns an error if the API user may not see
+// or clear login lockouts. Only controller superusers may do so.
+func (api *UserManagerAPI) checkCanManageLockouts() error {
+	if api.lockouts == nil {
+		return errors.NotSupportedf("login lockouts")
+	}
+	isSuperUser, err := api.hasControllerAdminAccess()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !isSuperUser {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// ListLoginLockouts returns the recent failed logins of users seen by
+// the API server handling the request, including those for which users
+// are locked out. Other API servers count their failed logins
+// separately, and are not reported.
+func (api *UserManagerAPI) ListLoginLockouts(args params.ListLoginLockoutsRequest) (params.LoginLockoutResults, error) {
+	var result params.LoginLockoutResults
+	if err := api.checkCanManageLockouts(); err != nil {
+		return result, errors.Trace(err)
+	}
+	users, err := parseUserTags(args.UserTags)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+
+	result.Results = []params.LoginLockoutInfo{}
+	for _, info := range api.lockouts.Lockouts() {
+		if len(users) > 0 && !users[info.User.Id()] {
+			continue
+		}
+		lockout := params.LoginLockoutInfo{
+			Server:      info.Server,
+			Address:     info.Address,
+			Failures:    info.Failures,
+			LastFailure: info.LastFailure,
+		}
+		if info.User.Id() != "" {
+			lockout.UserTag = info.User.String()
+		}
+		if !info.LockedUntil.IsZero() {
+			lockedUntil := info.LockedUntil
+			lockout.LockedUntil = &lockedUntil
+		}
+		result.Results = append(result.Results, lockout)
+	}
+	return result, nil
+}
+
+// ClearLoginLockouts forgets the failed logins of the given users from
+// any address, and of any user from the given addresses, on every API
+// server of the controller. Lockouts may be cleared even when changes
+// are blocked, so that access can always be restored.
+func (api *UserManagerAPI) ClearLoginLockouts(args params.ClearLoginLockoutsRequest) error {
+	if err := api.checkCanManageLockouts(); err != nil {
+		return errors.Trace(err)
+	}
+	users := make([]names.UserTag, len(args.UserTags))
+	for i, arg := range args.UserTags {
+		user, err := names.ParseUserTag(arg)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		users[i] = user
+	}
+	if len(users)+len(args.Addresses) == 0 {
+		return nil
+	}
+	logger.Infof("%s clearing login lockouts of users %v from addresses %v", api.apiUser.Id(), args.UserTags, args.Addresses)
+	return errors.Trace(api.lockouts.Clear(users, args.Addresses))
+}
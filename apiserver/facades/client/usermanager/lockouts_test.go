@@ -0,0 +1,122 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package usermanager_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facade/facadetest"
+	"github.com/juju/juju/apiserver/facades/client/usermanager"
+	"github.com/juju/juju/apiserver/lockout"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/pubsub/centralhub"
+	"github.com/juju/juju/testing/factory"
+)
+
+func (s *userManagerSuite) newLockoutTracker(c *gc.C) *lockout.Tracker {
+	tracker, err := lockout.NewTracker(lockout.Config{
+		ServerID: "0",
+		Clock:    testing.NewClock(time.Now()),
+		Hub:      centralhub.New(names.NewMachineTag("0")),
+		Policy: lockout.Policy{
+			MaxFailures: 2,
+			Duration:    time.Hour,
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { tracker.Close() })
+	return tracker
+}
+
+func (s *userManagerSuite) newFacadeWithLockouts(c *gc.C, tracker *lockout.Tracker, user names.UserTag) *usermanager.UserManagerAPI {
+	api, err := usermanager.NewFacade(facadetest.Context{
+		State_:     s.State,
+		Resources_: s.resources,
+		Auth_:      apiservertesting.FakeAuthorizer{Tag: user},
+		Lockouts_:  tracker,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *userManagerSuite) TestListLoginLockouts(c *gc.C) {
+	tracker := s.newLockoutTracker(c)
+	bob := names.NewUserTag("bob")
+	tracker.Failed(bob, "10.0.0.1:40000")
+	tracker.Failed(bob, "10.0.0.1:40000")
+	tracker.Failed(names.NewUserTag("mary"), "10.0.0.2:40000")
+	api := s.newFacadeWithLockouts(c, tracker, s.AdminUserTag(c))
+
+	results, err := api.ListLoginLockouts(params.ListLoginLockoutsRequest{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+
+	results, err = api.ListLoginLockouts(params.ListLoginLockoutsRequest{
+		UserTags: []string{bob.String()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	result := results.Results[0]
+	c.Assert(result.Server, gc.Equals, "0")
+	c.Assert(result.UserTag, gc.Equals, "user-bob")
+	c.Assert(result.Address, gc.Equals, "10.0.0.1")
+	c.Assert(result.Failures, gc.Equals, 2)
+	c.Assert(result.LockedUntil, gc.NotNil)
+}
+
+func (s *userManagerSuite) TestClearLoginLockouts(c *gc.C) {
+	tracker := s.newLockoutTracker(c)
+	bob := names.NewUserTag("bob")
+	tracker.Failed(bob, "10.0.0.1:40000")
+	tracker.Failed(bob, "10.0.0.1:40000")
+	c.Assert(tracker.Check(bob, "10.0.0.1:40000"), gc.NotNil)
+	api := s.newFacadeWithLockouts(c, tracker, s.AdminUserTag(c))
+	s.BlockAllChanges(c, "TestClearLoginLockouts")
+
+	err := api.ClearLoginLockouts(params.ClearLoginLockoutsRequest{
+		UserTags: []string{bob.String()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tracker.Check(bob, "10.0.0.1:40000"), jc.ErrorIsNil)
+}
+
+func (s *userManagerSuite) TestLoginLockoutsNonSuperUser(c *gc.C) {
+	tracker := s.newLockoutTracker(c)
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	api := s.newFacadeWithLockouts(c, tracker, user.UserTag())
+
+	_, err := api.ListLoginLockouts(params.ListLoginLockoutsRequest{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	err = api.ClearLoginLockouts(params.ClearLoginLockoutsRequest{Addresses: []string{"10.0.0.1"}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *userManagerSuite) TestLoginLockoutsNotSupported(c *gc.C) {
+	_, err := s.usermanager.ListLoginLockouts(params.ListLoginLockoutsRequest{})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *userManagerSuite) TestV4HidesLoginLockouts(c *gc.C) {
+	api, err := usermanager.NewFacadeV4(facadetest.Context{
+		State_:     s.State,
+		Resources_: s.resources,
+		Auth_:      apiservertesting.FakeAuthorizer{Tag: s.AdminUserTag(c)},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := interface{}(api).(interface {
+		ListLoginLockouts(params.ListLoginLockoutsRequest) (params.LoginLockoutResults, error)
+	})
+	c.Check(ok, jc.IsFalse)
+	_, ok = interface{}(api).(interface {
+		ClearLoginLockouts(params.ClearLoginLockoutsRequest) error
+	})
+	c.Check(ok, jc.IsFalse)
+}
@@ -0,0 +1,117 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package usermanager
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// ListSessions returns the API sessions of logged in users held by
+// every API server of the controller, and the IDs of the API servers
+// that did not report their sessions in time. Controller superusers
+// may list any user's sessions; other users may list only their own.
+func (api *UserManagerAPI) ListSessions(args params.ListSessionsRequest) (params.SessionInfoResults, error) {
+	var result params.SessionInfoResults
+	if api.sessions == nil {
+		return result, errors.NotSupportedf("API sessions")
+	}
+	isSuperUser, err := api.hasControllerAdminAccess()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	users, err := parseUserTags(args.UserTags)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if !isSuperUser {
+		for user := range users {
+			if user != api.apiUser.Id() {
+				return result, common.ErrPerm
+			}
+		}
+		users = map[string]bool{api.apiUser.Id(): true}
+	}
+
+	infos, unreachable, err := api.sessions.AllSessions()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Unreachable = unreachable
+	result.Results = []params.SessionInfo{}
+	for _, info := range infos {
+		if len(users) > 0 && !users[info.User.Id()] {
+			continue
+		}
+		var modelTag string
+		if info.ModelUUID != "" {
+			modelTag = names.NewModelTag(info.ModelUUID).String()
+		}
+		result.Results = append(result.Results, params.SessionInfo{
+			ID:            info.ID,
+			Server:        info.Server,
+			UserTag:       info.User.String(),
+			ModelTag:      modelTag,
+			RemoteAddress: info.RemoteAddress,
+			Started:       info.Started,
+			LastActive:    info.LastActive,
+			FacadeCalls:   info.FacadeCalls,
+		})
+	}
+	return result, nil
+}
+
+// RevokeSessions ends the identified API sessions, and all the
+// sessions of the given users, on every API server of the controller.
+// The revocation of a user's sessions is recorded, so that the user
+// cannot log in again with a macaroon obtained before it. Only
+// controller superusers may revoke sessions. Sessions may be revoked
+// even when changes are blocked, so that access can always be ended
+// immediately.
+func (api *UserManagerAPI) RevokeSessions(args params.RevokeSessionsRequest) error {
+	if api.sessions == nil {
+		return errors.NotSupportedf("API sessions")
+	}
+	isSuperUser, err := api.hasControllerAdminAccess()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !isSuperUser {
+		return common.ErrPerm
+	}
+	users := make([]names.UserTag, len(args.UserTags))
+	for i, arg := range args.UserTags {
+		user, err := names.ParseUserTag(arg)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		users[i] = user
+	}
+	if len(args.SessionIDs)+len(users) == 0 {
+		return nil
+	}
+	logger.Infof("%s revoking API sessions %v of users %v", api.apiUser.Id(), args.SessionIDs, args.UserTags)
+	for _, user := range users {
+		if err := api.state.RevokeUserSessions(user); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(api.sessions.Revoke(args.SessionIDs, users))
+}
+
+// parseUserTags returns the set of names of the given users.
+func parseUserTags(tags []string) (map[string]bool, error) {
+	users := make(map[string]bool)
+	for _, arg := range tags {
+		user, err := names.ParseUserTag(arg)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		users[user.Id()] = true
+	}
+	return users, nil
+}
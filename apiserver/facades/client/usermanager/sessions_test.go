@@ -0,0 +1,172 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package usermanager_test
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facade/facadetest"
+	"github.com/juju/juju/apiserver/facades/client/usermanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/sessions"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/pubsub/centralhub"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+func (s *userManagerSuite) newSessionTracker(c *gc.C) *sessions.Tracker {
+	tracker, err := sessions.NewTracker(sessions.Config{
+		ServerID: "0",
+		Clock:    testing.NewClock(time.Now()),
+		Hub:      centralhub.New(names.NewMachineTag("0")),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { tracker.Close() })
+	return tracker
+}
+
+func (s *userManagerSuite) newFacadeWithSessions(c *gc.C, tracker *sessions.Tracker, user names.UserTag) *usermanager.UserManagerAPI {
+	api, err := usermanager.NewFacade(facadetest.Context{
+		State_:     s.State,
+		Resources_: s.resources,
+		Auth_:      apiservertesting.FakeAuthorizer{Tag: user},
+		Sessions_:  tracker,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func loginSession(tracker *sessions.Tracker, connectionID uint64, user names.UserTag) *sessions.Session {
+	session := tracker.NewSession()
+	session.Join(&http.Request{RemoteAddr: "10.0.0.1:40000"}, connectionID)
+	session.Login(user, coretesting.ModelTag, false, "")
+	return session
+}
+
+func (s *userManagerSuite) TestListSessions(c *gc.C) {
+	tracker := s.newSessionTracker(c)
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	loginSession(tracker, 1, s.AdminUserTag(c))
+	loginSession(tracker, 2, bob.UserTag())
+	api := s.newFacadeWithSessions(c, tracker, s.AdminUserTag(c))
+
+	results, err := api.ListSessions(params.ListSessionsRequest{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+
+	results, err = api.ListSessions(params.ListSessionsRequest{
+		UserTags: []string{bob.Tag().String()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].ID, gc.Equals, "0:2")
+	c.Assert(results.Results[0].Server, gc.Equals, "0")
+	c.Assert(results.Results[0].UserTag, gc.Equals, "user-bob")
+	c.Assert(results.Results[0].ModelTag, gc.Equals, coretesting.ModelTag.String())
+	c.Assert(results.Results[0].RemoteAddress, gc.Equals, "10.0.0.1:40000")
+}
+
+func (s *userManagerSuite) TestListSessionsNonSuperUser(c *gc.C) {
+	tracker := s.newSessionTracker(c)
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	loginSession(tracker, 1, s.AdminUserTag(c))
+	loginSession(tracker, 2, bob.UserTag())
+	api := s.newFacadeWithSessions(c, tracker, bob.UserTag())
+
+	results, err := api.ListSessions(params.ListSessionsRequest{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].UserTag, gc.Equals, "user-bob")
+
+	_, err = api.ListSessions(params.ListSessionsRequest{
+		UserTags: []string{s.AdminUserTag(c).String()},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *userManagerSuite) TestV3HidesSessions(c *gc.C) {
+	api, err := usermanager.NewUserManagerAPIV3(s.State, s.resources, apiservertesting.FakeAuthorizer{Tag: s.AdminUserTag(c)})
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := interface{}(api).(interface {
+		ListSessions(params.ListSessionsRequest) (params.SessionInfoResults, error)
+	})
+	c.Check(ok, jc.IsFalse)
+	_, ok = interface{}(api).(interface {
+		RevokeSessions(params.RevokeSessionsRequest) error
+	})
+	c.Check(ok, jc.IsFalse)
+}
+
+func (s *userManagerSuite) TestListSessionsNotSupported(c *gc.C) {
+	_, err := s.usermanager.ListSessions(params.ListSessionsRequest{})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *userManagerSuite) TestRevokeSessions(c *gc.C) {
+	tracker := s.newSessionTracker(c)
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	admin := loginSession(tracker, 1, s.AdminUserTag(c))
+	bobSession := loginSession(tracker, 2, bob.UserTag())
+	api := s.newFacadeWithSessions(c, tracker, s.AdminUserTag(c))
+
+	err := api.RevokeSessions(params.RevokeSessionsRequest{
+		UserTags: []string{bob.Tag().String()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case <-bobSession.Done():
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("session not revoked")
+	}
+	revoked, err := s.State.UserSessionsRevoked(bob.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(revoked.IsZero(), jc.IsFalse)
+	revoked, err = s.State.UserSessionsRevoked(s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(revoked.IsZero(), jc.IsTrue)
+	select {
+	case <-admin.Done():
+		c.Fatalf("admin session revoked")
+	default:
+	}
+}
+
+func (s *userManagerSuite) TestRevokeSessionsWhenBlocked(c *gc.C) {
+	tracker := s.newSessionTracker(c)
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	session := loginSession(tracker, 1, bob.UserTag())
+	api := s.newFacadeWithSessions(c, tracker, s.AdminUserTag(c))
+	s.BlockAllChanges(c, "TestRevokeSessionsWhenBlocked")
+
+	err := api.RevokeSessions(params.RevokeSessionsRequest{SessionIDs: []string{"0:1"}})
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case <-session.Done():
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("session not revoked")
+	}
+}
+
+func (s *userManagerSuite) TestRevokeSessionsNonSuperUser(c *gc.C) {
+	tracker := s.newSessionTracker(c)
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	session := loginSession(tracker, 1, s.AdminUserTag(c))
+	api := s.newFacadeWithSessions(c, tracker, bob.UserTag())
+
+	err := api.RevokeSessions(params.RevokeSessionsRequest{SessionIDs: []string{"0:1"}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	select {
+	case <-session.Done():
+		c.Fatalf("session revoked")
+	default:
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package usermanager
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// checkCanManageTeams returns an error if the API user may not add,
+// remove or change teams. Only controller superusers may do so.
+func (api *UserManagerAPI) checkCanManageTeams() error {
+	isSuperUser, err := api.hasControllerAdminAccess()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !isSuperUser {
+		return common.ErrPerm
+	}
+	return errors.Trace(api.check.ChangeAllowed())
+}
+
+// AddTeams adds teams, with no members, to the controller.
+func (api *UserManagerAPI) AddTeams(args params.TeamNames) (params.ErrorResults, error) {
+	if err := api.checkCanManageTeams(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Names)),
+	}
+	for i, name := range args.Names {
+		_, err := api.state.AddTeam(name, api.apiUser)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// RemoveTeams removes teams from the controller. Teams that own models
+// cannot be removed.
+func (api *UserManagerAPI) RemoveTeams(args params.TeamNames) (params.ErrorResults, error) {
+	if err := api.checkCanManageTeams(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Names)),
+	}
+	for i, name := range args.Names {
+		err := api.state.RemoveTeam(name)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// ModifyTeamMembers adds users to, and removes users from, teams.
+func (api *UserManagerAPI) ModifyTeamMembers(args params.ModifyTeamMembersRequest) (params.ErrorResults, error) {
+	if err := api.checkCanManageTeams(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Changes)),
+	}
+	for i, arg := range args.Changes {
+		err := api.modifyTeamMember(arg)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *UserManagerAPI) modifyTeamMember(arg params.ModifyTeamMember) error {
+	user, err := names.ParseUserTag(arg.UserTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	team, err := api.state.Team(arg.Team)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	switch arg.Action {
+	case params.AddTeamMember:
+		return errors.Trace(team.AddMember(user))
+	case params.RemoveTeamMember:
+		return errors.Trace(team.RemoveMember(user))
+	}
+	return errors.NotValidf("team action %q", arg.Action)
+}
+
+// Teams returns information on all the teams on the controller. Any
+// user may see the teams, as they own models that the user may be
+// given access to.
+func (api *UserManagerAPI) Teams() (params.TeamInfoResults, error) {
+	teams, err := api.state.AllTeams()
+	if err != nil {
+		return params.TeamInfoResults{}, errors.Trace(err)
+	}
+	result := params.TeamInfoResults{
+		Results: make([]params.TeamInfo, len(teams)),
+	}
+	for i, team := range teams {
+		members := team.Members()
+		info := params.TeamInfo{
+			Name:        team.Name(),
+			Members:     make([]string, len(members)),
+			CreatedBy:   team.CreatedBy(),
+			DateCreated: team.DateCreated(),
+		}
+		for j, member := range members {
+			info.Members[j] = member.String()
+		}
+		result.Results[i] = info
+	}
+	return result, nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package usermanager_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/usermanager"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+func (s *userManagerSuite) TestAddTeams(c *gc.C) {
+	results, err := s.usermanager.AddTeams(params.TeamNames{
+		Names: []string{"ops", "not valid"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot add team "not valid": team name "not valid" not valid`)
+
+	team, err := s.State.Team("ops")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(team.CreatedBy(), gc.Equals, s.adminName)
+}
+
+func (s *userManagerSuite) TestTeams(c *gc.C) {
+	team, err := s.State.AddTeam("ops", s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	c.Assert(team.AddMember(bob.UserTag()), jc.ErrorIsNil)
+
+	results, err := s.usermanager.Teams()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Name, gc.Equals, "ops")
+	c.Assert(results.Results[0].Members, jc.DeepEquals, []string{"user-bob"})
+	c.Assert(results.Results[0].CreatedBy, gc.Equals, s.adminName)
+}
+
+func (s *userManagerSuite) TestModifyTeamMembers(c *gc.C) {
+	_, err := s.State.AddTeam("ops", s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+
+	results, err := s.usermanager.ModifyTeamMembers(params.ModifyTeamMembersRequest{
+		Changes: []params.ModifyTeamMember{{
+			Team:    "ops",
+			Action:  params.AddTeamMember,
+			UserTag: bob.Tag().String(),
+		}, {
+			Team:    "ops",
+			Action:  params.AddTeamMember,
+			UserTag: alex.Tag().String(),
+		}, {
+			Team:    "ops",
+			Action:  params.RemoveTeamMember,
+			UserTag: alex.Tag().String(),
+		}, {
+			Team:    "dev",
+			Action:  params.AddTeamMember,
+			UserTag: bob.Tag().String(),
+		}, {
+			Team:    "ops",
+			Action:  "rename",
+			UserTag: bob.Tag().String(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 5)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.IsNil)
+	c.Assert(results.Results[2].Error, gc.IsNil)
+	c.Assert(results.Results[3].Error, gc.ErrorMatches, `team "dev" not found`)
+	c.Assert(results.Results[4].Error, gc.ErrorMatches, `team action "rename" not valid`)
+
+	team, err := s.State.Team("ops")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(team.Members(), jc.DeepEquals, []names.UserTag{bob.UserTag()})
+}
+
+func (s *userManagerSuite) TestRemoveTeams(c *gc.C) {
+	_, err := s.State.AddTeam("ops", s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.usermanager.RemoveTeams(params.TeamNames{
+		Names: []string{"ops", "dev"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot remove team "dev": team "dev" not found`)
+
+	_, err = s.State.Team("ops")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *userManagerSuite) TestManageTeamsAsNormalUser(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoModelUser: true})
+	usermanager, err := usermanager.NewUserManagerAPI(
+		s.State, s.resources, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = usermanager.AddTeams(params.TeamNames{Names: []string{"ops"}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = usermanager.RemoveTeams(params.TeamNames{Names: []string{"ops"}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = usermanager.ModifyTeamMembers(params.ModifyTeamMembersRequest{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+
+	_, err = s.State.Team("ops")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *userManagerSuite) TestV2HidesTeams(c *gc.C) {
+	api, err := usermanager.NewUserManagerAPIV2(s.State, s.resources, apiservertesting.FakeAuthorizer{Tag: s.AdminUserTag(c)})
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := interface{}(api).(interface {
+		AddTeams(params.TeamNames) (params.ErrorResults, error)
+	})
+	c.Check(ok, jc.IsFalse)
+	_, ok = interface{}(api).(interface {
+		RemoveTeams(params.TeamNames) (params.ErrorResults, error)
+	})
+	c.Check(ok, jc.IsFalse)
+	_, ok = interface{}(api).(interface {
+		ModifyTeamMembers(params.ModifyTeamMembersRequest) (params.ErrorResults, error)
+	})
+	c.Check(ok, jc.IsFalse)
+	_, ok = interface{}(api).(interface {
+		Teams() (params.TeamInfoResults, error)
+	})
+	c.Check(ok, jc.IsFalse)
+}
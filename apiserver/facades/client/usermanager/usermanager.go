@@ -12,7 +12,9 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/lockout"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/sessions"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 )
@@ -27,6 +29,21 @@ type UserManagerAPI struct {
 	check      *common.BlockChecker
 	apiUser    names.UserTag
 	isAdmin    bool
+	sessions   *sessions.Tracker
+	lockouts   *lockout.Tracker
+}
+
+// NewFacade returns a new user manager facade that can also list and
+// revoke the API sessions of logged in users, and list and clear the
+// login lockouts of users.
+func NewFacade(ctx facade.Context) (*UserManagerAPI, error) {
+	api, err := NewUserManagerAPI(ctx.State(), ctx.Resources(), ctx.Auth())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	api.sessions = ctx.Sessions()
+	api.lockouts = ctx.LoginLockouts()
+	return api, nil
 }
 
 // NewUserManagerAPI provides the signature required for facade registration.
@@ -58,6 +75,86 @@ func NewUserManagerAPI(
 	}, nil
 }
 
+// UserManagerAPIV4 serves version 4 of the UserManager facade, which
+// cannot list or clear login lockouts.
+type UserManagerAPIV4 struct {
+	*UserManagerAPI
+}
+
+// NewFacadeV4 provides the signature required for version 4 facade
+// registration.
+func NewFacadeV4(ctx facade.Context) (*UserManagerAPIV4, error) {
+	api, err := NewFacade(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &UserManagerAPIV4{api}, nil
+}
+
+// ListLoginLockouts isn't on the V4 API.
+func (*UserManagerAPIV4) ListLoginLockouts(_, _ struct{}) {}
+
+// ClearLoginLockouts isn't on the V4 API.
+func (*UserManagerAPIV4) ClearLoginLockouts(_, _ struct{}) {}
+
+// UserManagerAPIV3 serves version 3 of the UserManager facade, which
+// cannot list or revoke API sessions.
+type UserManagerAPIV3 struct {
+	*UserManagerAPIV4
+}
+
+// NewUserManagerAPIV3 provides the signature required for version 3
+// facade registration.
+func NewUserManagerAPIV3(
+	st *state.State,
+	resources facade.Resources,
+	authorizer facade.Authorizer,
+) (*UserManagerAPIV3, error) {
+	api, err := NewUserManagerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &UserManagerAPIV3{&UserManagerAPIV4{api}}, nil
+}
+
+// ListSessions isn't on the V3 API.
+func (*UserManagerAPIV3) ListSessions(_, _ struct{}) {}
+
+// RevokeSessions isn't on the V3 API.
+func (*UserManagerAPIV3) RevokeSessions(_, _ struct{}) {}
+
+// UserManagerAPIV2 serves versions 1 and 2 of the UserManager facade,
+// which cannot manage teams.
+type UserManagerAPIV2 struct {
+	*UserManagerAPIV3
+}
+
+// NewUserManagerAPIV2 provides the signature required for version 1
+// and 2 facade registration.
+func NewUserManagerAPIV2(
+	st *state.State,
+	resources facade.Resources,
+	authorizer facade.Authorizer,
+) (*UserManagerAPIV2, error) {
+	api, err := NewUserManagerAPIV3(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &UserManagerAPIV2{api}, nil
+}
+
+// AddTeams isn't on the V2 API.
+func (*UserManagerAPIV2) AddTeams(_, _ struct{}) {}
+
+// RemoveTeams isn't on the V2 API.
+func (*UserManagerAPIV2) RemoveTeams(_, _ struct{}) {}
+
+// ModifyTeamMembers isn't on the V2 API.
+func (*UserManagerAPIV2) ModifyTeamMembers(_, _ struct{}) {}
+
+// Teams isn't on the V2 API.
+func (*UserManagerAPIV2) Teams(_, _ struct{}) {}
+
 func (api *UserManagerAPI) hasControllerAdminAccess() (bool, error) {
 	isAdmin, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.state.ControllerTag())
 	if errors.IsNotFound(err) {
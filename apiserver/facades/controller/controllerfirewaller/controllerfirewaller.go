@@ -0,0 +1,183 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package controllerfirewaller provides the facade used to manage the
+// ingress rules of the controller's own instances.
+package controllerfirewaller
+
+import (
+	"net"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.controllerfirewaller")
+
+// lookupHost resolves the hostnames among the controller machines'
+// API addresses; it is patched in tests.
+var lookupHost = net.LookupHost
+
+// SSHPort is the port on which the controller machines accept ssh
+// connections.
+const SSHPort = 22
+
+// Backend defines the state functionality required by the
+// controllerfirewaller facade. For details on the methods, see the
+// methods on state.State with the same names.
+type Backend interface {
+	IsController() bool
+	ControllerConfig() (controller.Config, error)
+	APIHostPorts() ([][]network.HostPort, error)
+	WatchControllerConfig() state.NotifyWatcher
+	WatchAPIHostPorts() state.NotifyWatcher
+}
+
+// API provides the ControllerFirewaller facade for v1.
+type API struct {
+	backend   Backend
+	resources facade.Resources
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(ctx.State(), ctx.Resources(), ctx.Auth())
+}
+
+// NewAPI returns a new ControllerFirewaller API facade.
+func NewAPI(backend Backend, resources facade.Resources, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthController() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:   backend,
+		resources: resources,
+	}, nil
+}
+
+// checkController returns an error if the facade was not created for
+// the controller model; only its environ may manage the ingress rules
+// of the controller's instances.
+func (api *API) checkController() error {
+	if !api.backend.IsController() {
+		return errors.NotSupportedf("managing controller ingress rules outside the controller model")
+	}
+	return nil
+}
+
+// WatchControllerIngressRules returns a watcher that notifies of
+// changes to the controller config or the addresses of the controller
+// machines, either of which may change the controller ingress rules.
+func (api *API) WatchControllerIngressRules() (params.NotifyWatchResult, error) {
+	if err := api.checkController(); err != nil {
+		return params.NotifyWatchResult{}, errors.Trace(err)
+	}
+	watch := common.NewMultiNotifyWatcher(
+		api.backend.WatchControllerConfig(),
+		api.backend.WatchAPIHostPorts(),
+	)
+	if _, ok := <-watch.Changes(); ok {
+		return params.NotifyWatchResult{
+			NotifyWatcherId: api.resources.Register(watch),
+		}, nil
+	}
+	return params.NotifyWatchResult{}, watcher.EnsureErr(watch)
+}
+
+// ControllerIngressRules returns the rules governing ingress to the
+// controller's instances. The API and ssh ports may be reached from
+// the controller's admin CIDRs, or from anywhere if none are
+// configured; the database port may be reached only from the
+// controller machines themselves, for replication between them.
+func (api *API) ControllerIngressRules() (params.ControllerIngressRulesResult, error) {
+	var result params.ControllerIngressRulesResult
+	if err := api.checkController(); err != nil {
+		return result, errors.Trace(err)
+	}
+	cfg, err := api.backend.ControllerConfig()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	hostPorts, err := api.backend.APIHostPorts()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	rules, err := IngressRules(cfg, hostPorts, lookupHost)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.ControllerUUID = cfg.ControllerUUID()
+	result.Rules = make([]params.IngressRule, len(rules))
+	for i, rule := range rules {
+		result.Rules[i] = params.FromNetworkIngressRule(rule)
+	}
+	return result, nil
+}
+
+// IngressRules returns the ingress rules for the instances of the
+// controller with the given config, whose machines have the given API
+// addresses. Addresses that are hostnames are resolved with lookupHost,
+// so that peers known only by name can still replicate; a hostname
+// that cannot be resolved is skipped.
+func IngressRules(
+	cfg controller.Config,
+	hostPorts [][]network.HostPort,
+	lookupHost func(string) ([]string, error),
+) ([]network.IngressRule, error) {
+	adminCIDRs := cfg.ControllerAdminCIDRs()
+	apiRule, err := network.NewIngressRule("tcp", cfg.APIPort(), cfg.APIPort(), adminCIDRs...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sshRule, err := network.NewIngressRule("tcp", SSHPort, SSHPort, adminCIDRs...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rules := []network.IngressRule{apiRule, sshRule}
+
+	peerCIDRs := set.NewStrings()
+	addPeer := func(addr string) {
+		ip := net.ParseIP(addr)
+		switch {
+		case ip == nil:
+		case ip.To4() != nil:
+			peerCIDRs.Add(ip.String() + "/32")
+		default:
+			peerCIDRs.Add(ip.String() + "/128")
+		}
+	}
+	for _, serverHostPorts := range hostPorts {
+		for _, hp := range serverHostPorts {
+			if hp.Type != network.HostName {
+				addPeer(hp.Value)
+				continue
+			}
+			addrs, err := lookupHost(hp.Value)
+			if err != nil {
+				logger.Warningf("cannot resolve controller address %q: %v", hp.Value, err)
+				continue
+			}
+			for _, addr := range addrs {
+				addPeer(addr)
+			}
+		}
+	}
+	if !peerCIDRs.IsEmpty() {
+		stateRule, err := network.NewIngressRule("tcp", cfg.StatePort(), cfg.StatePort(), peerCIDRs.SortedValues()...)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		rules = append(rules, stateRule)
+	}
+	network.SortIngressRules(rules)
+	return rules, nil
+}
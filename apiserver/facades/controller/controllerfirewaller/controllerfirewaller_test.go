@@ -0,0 +1,189 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controllerfirewaller_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/controller/controllerfirewaller"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ControllerFirewallerSuite struct {
+	testing.IsolationSuite
+	backend    mockBackend
+	resources  *common.Resources
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&ControllerFirewallerSuite{})
+
+func (s *ControllerFirewallerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag:        names.NewMachineTag("0"),
+		Controller: true,
+	}
+	s.resources = common.NewResources()
+	s.AddCleanup(func(*gc.C) { s.resources.StopAll() })
+	s.backend = mockBackend{
+		controller: true,
+		hostPorts: [][]network.HostPort{
+			network.NewHostPorts(17070, "10.0.0.1", "203.0.113.1", "controller-0.example.com"),
+			network.NewHostPorts(17070, "10.0.0.2", "2001:db8::2"),
+		},
+	}
+	s.setConfig(c, nil)
+	s.PatchValue(controllerfirewaller.LookupHost, func(host string) ([]string, error) {
+		if host == "controller-0.example.com" {
+			return []string{"10.0.0.3"}, nil
+		}
+		return nil, errors.NotFoundf("host %q", host)
+	})
+}
+
+func (s *ControllerFirewallerSuite) setConfig(c *gc.C, attrs map[string]interface{}) {
+	cfg, err := controller.NewConfig(coretesting.ControllerTag.Id(), coretesting.CACert, attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.cfg = cfg
+}
+
+func (s *ControllerFirewallerSuite) newAPI(c *gc.C) *controllerfirewaller.API {
+	api, err := controllerfirewaller.NewAPI(&s.backend, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *ControllerFirewallerSuite) TestNewAPIRequiresController(c *gc.C) {
+	s.authorizer.Controller = false
+	_, err := controllerfirewaller.NewAPI(&s.backend, s.resources, s.authorizer)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *ControllerFirewallerSuite) TestControllerIngressRules(c *gc.C) {
+	result, err := s.newAPI(c).ControllerIngressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ControllerIngressRulesResult{
+		ControllerUUID: coretesting.ControllerTag.Id(),
+		Rules: []params.IngressRule{{
+			PortRange: params.PortRange{FromPort: 22, ToPort: 22, Protocol: "tcp"},
+		}, {
+			PortRange: params.PortRange{FromPort: 17070, ToPort: 17070, Protocol: "tcp"},
+		}, {
+			PortRange: params.PortRange{FromPort: 37017, ToPort: 37017, Protocol: "tcp"},
+			SourceCIDRs: []string{
+				"10.0.0.1/32",
+				"10.0.0.2/32",
+				"10.0.0.3/32",
+				"2001:db8::2/128",
+				"203.0.113.1/32",
+			},
+		}},
+	})
+}
+
+func (s *ControllerFirewallerSuite) TestControllerIngressRulesUnresolvableHostname(c *gc.C) {
+	s.backend.hostPorts = [][]network.HostPort{
+		network.NewHostPorts(17070, "10.0.0.1", "controller-1.example.com"),
+	}
+	result, err := s.newAPI(c).ControllerIngressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Rules[2], jc.DeepEquals, params.IngressRule{
+		PortRange:   params.PortRange{FromPort: 37017, ToPort: 37017, Protocol: "tcp"},
+		SourceCIDRs: []string{"10.0.0.1/32"},
+	})
+}
+
+func (s *ControllerFirewallerSuite) TestControllerIngressRulesAdminCIDRs(c *gc.C) {
+	s.setConfig(c, map[string]interface{}{
+		"controller-admin-cidrs": "192.0.2.0/24,198.51.100.7/32",
+	})
+	s.backend.hostPorts = [][]network.HostPort{
+		network.NewHostPorts(17070, "10.0.0.1"),
+	}
+	result, err := s.newAPI(c).ControllerIngressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	adminCIDRs := []string{"192.0.2.0/24", "198.51.100.7/32"}
+	c.Assert(result.Rules, jc.DeepEquals, []params.IngressRule{{
+		PortRange:   params.PortRange{FromPort: 22, ToPort: 22, Protocol: "tcp"},
+		SourceCIDRs: adminCIDRs,
+	}, {
+		PortRange:   params.PortRange{FromPort: 17070, ToPort: 17070, Protocol: "tcp"},
+		SourceCIDRs: adminCIDRs,
+	}, {
+		PortRange:   params.PortRange{FromPort: 37017, ToPort: 37017, Protocol: "tcp"},
+		SourceCIDRs: []string{"10.0.0.1/32"},
+	}})
+}
+
+func (s *ControllerFirewallerSuite) TestControllerIngressRulesError(c *gc.C) {
+	s.backend.SetErrors(errors.New("boom"))
+	_, err := s.newAPI(c).ControllerIngressRules()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ControllerFirewallerSuite) TestWatchControllerIngressRules(c *gc.C) {
+	result, err := s.newAPI(c).WatchControllerIngressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.NotifyWatcherId, gc.Equals, "1")
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	s.backend.CheckCallNames(c, "IsController", "WatchControllerConfig", "WatchAPIHostPorts")
+}
+
+func (s *ControllerFirewallerSuite) TestNotControllerModel(c *gc.C) {
+	s.backend.controller = false
+	api := s.newAPI(c)
+	_, err := api.ControllerIngressRules()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = api.WatchControllerIngressRules()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+type mockBackend struct {
+	testing.Stub
+	controller bool
+	cfg        controller.Config
+	hostPorts  [][]network.HostPort
+}
+
+func (b *mockBackend) IsController() bool {
+	b.MethodCall(b, "IsController")
+	return b.controller
+}
+
+func (b *mockBackend) ControllerConfig() (controller.Config, error) {
+	b.MethodCall(b, "ControllerConfig")
+	if err := b.NextErr(); err != nil {
+		return nil, err
+	}
+	return b.cfg, nil
+}
+
+func (b *mockBackend) APIHostPorts() ([][]network.HostPort, error) {
+	b.MethodCall(b, "APIHostPorts")
+	if err := b.NextErr(); err != nil {
+		return nil, err
+	}
+	return b.hostPorts, nil
+}
+
+func (b *mockBackend) WatchControllerConfig() state.NotifyWatcher {
+	b.MethodCall(b, "WatchControllerConfig")
+	return apiservertesting.NewFakeNotifyWatcher()
+}
+
+func (b *mockBackend) WatchAPIHostPorts() state.NotifyWatcher {
+	b.MethodCall(b, "WatchAPIHostPorts")
+	return apiservertesting.NewFakeNotifyWatcher()
+}
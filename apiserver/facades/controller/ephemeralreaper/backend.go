@@ -0,0 +1,65 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ephemeralreaper
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state methods needed by the ephemeral reaper
+// facade.
+type Backend interface {
+	// EphemeralExpiry returns the time after which the model is
+	// destroyed, and whether the model is ephemeral at all.
+	EphemeralExpiry() (time.Time, bool, error)
+
+	// WatchModel returns a watcher that notifies of changes to the
+	// model, including its expiry.
+	WatchModel() state.NotifyWatcher
+
+	// IsController reports whether the model is the controller
+	// model.
+	IsController() bool
+
+	// DestroyModel destroys the model along with its storage.
+	DestroyModel() error
+}
+
+// backendShim is an untested shim over state, to make the facade
+// testable.
+type backendShim struct {
+	model *state.Model
+	st    *state.State
+	pool  *state.StatePool
+}
+
+// EphemeralExpiry is part of the Backend interface.
+func (b backendShim) EphemeralExpiry() (time.Time, bool, error) {
+	if err := b.model.Refresh(); err != nil {
+		return time.Time{}, false, errors.Trace(err)
+	}
+	expiry, ok := b.model.EphemeralExpiry()
+	return expiry, ok, nil
+}
+
+// WatchModel is part of the Backend interface.
+func (b backendShim) WatchModel() state.NotifyWatcher {
+	return b.model.Watch()
+}
+
+// IsController is part of the Backend interface.
+func (b backendShim) IsController() bool {
+	return b.st.IsController()
+}
+
+// DestroyModel is part of the Backend interface.
+func (b backendShim) DestroyModel() error {
+	destroyStorage := true
+	return common.DestroyModel(common.NewModelManagerBackend(b.model, b.pool), &destroyStorage)
+}
@@ -0,0 +1,96 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ephemeralreaper
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state/watcher"
+)
+
+// API implements the API facade used by the ephemeral reaper worker,
+// which destroys ephemeral models once they expire.
+type API struct {
+	backend   Backend
+	resources facade.Resources
+	clock     clock.Clock
+}
+
+// NewAPI returns a new ephemeral reaper API facade.
+func NewAPI(backend Backend, resources facade.Resources, authorizer facade.Authorizer, clock clock.Clock) (*API, error) {
+	if !authorizer.AuthController() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:   backend,
+		resources: resources,
+		clock:     clock,
+	}, nil
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	st := ctx.State()
+	m, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	backend := backendShim{model: m, st: st, pool: ctx.StatePool()}
+	return NewAPI(backend, ctx.Resources(), ctx.Auth(), clock.WallClock)
+}
+
+// WatchModel returns a NotifyWatcher that notifies of changes to the
+// model, including changes to its expiry.
+func (api *API) WatchModel() (params.NotifyWatchResult, error) {
+	result := params.NotifyWatchResult{}
+	watch := api.backend.WatchModel()
+	// Consume the initial event; NotifyWatchers have no state to
+	// transmit in the Watch response.
+	if _, ok := <-watch.Changes(); ok {
+		result.NotifyWatcherId = api.resources.Register(watch)
+	} else {
+		return result, watcher.EnsureErr(watch)
+	}
+	return result, nil
+}
+
+// ModelExpiry returns the time after which the model is destroyed,
+// which is nil if the model is not ephemeral.
+func (api *API) ModelExpiry() (params.ModelExpiryResult, error) {
+	expiry, ok, err := api.backend.EphemeralExpiry()
+	if err != nil {
+		return params.ModelExpiryResult{Error: common.ServerError(err)}, nil
+	}
+	if !ok {
+		return params.ModelExpiryResult{}, nil
+	}
+	return params.ModelExpiryResult{Expiry: &expiry}, nil
+}
+
+// DestroyModel destroys the model, and its storage, once its expiry
+// has passed. The worker decides when that is; the facade checks the
+// expiry again so that it never destroys a model early, or one that
+// is not ephemeral.
+func (api *API) DestroyModel() error {
+	expiry, ok, err := api.backend.EphemeralExpiry()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !ok {
+		return errors.New("model is not ephemeral")
+	}
+	if api.clock.Now().Before(expiry) {
+		return errors.Errorf("model does not expire until %s", expiry.Format(time.RFC3339))
+	}
+	if api.backend.IsController() {
+		return errors.New("cannot destroy the controller model")
+	}
+	return errors.Trace(api.backend.DestroyModel())
+}
@@ -0,0 +1,147 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ephemeralreaper_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/controller/ephemeralreaper"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+)
+
+type ReaperSuite struct {
+	testing.IsolationSuite
+	backend    *mockBackend
+	resources  *common.Resources
+	authorizer apiservertesting.FakeAuthorizer
+	clock      *testing.Clock
+}
+
+var _ = gc.Suite(&ReaperSuite{})
+
+func (s *ReaperSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.backend = &mockBackend{
+		Stub:   &testing.Stub{},
+		expiry: time.Date(2018, 4, 1, 12, 0, 0, 0, time.UTC),
+	}
+	s.resources = common.NewResources()
+	s.AddCleanup(func(*gc.C) { s.resources.StopAll() })
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag:        names.NewMachineTag("0"),
+		Controller: true,
+	}
+	s.clock = testing.NewClock(s.backend.expiry.Add(time.Second))
+}
+
+func (s *ReaperSuite) newAPI(c *gc.C) *ephemeralreaper.API {
+	api, err := ephemeralreaper.NewAPI(s.backend, s.resources, s.authorizer, s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *ReaperSuite) TestRequiresController(c *gc.C) {
+	s.authorizer.Controller = false
+	_, err := ephemeralreaper.NewAPI(s.backend, s.resources, s.authorizer, s.clock)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *ReaperSuite) TestWatchModel(c *gc.C) {
+	result, err := s.newAPI(c).WatchModel()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.NotifyWatchResult{NotifyWatcherId: "1"})
+	c.Assert(s.resources.Get("1"), gc.NotNil)
+	s.backend.CheckCallNames(c, "WatchModel")
+}
+
+func (s *ReaperSuite) TestModelExpiry(c *gc.C) {
+	result, err := s.newAPI(c).ModelExpiry()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ModelExpiryResult{Expiry: &s.backend.expiry})
+}
+
+func (s *ReaperSuite) TestModelExpiryNotEphemeral(c *gc.C) {
+	s.backend.expiry = time.Time{}
+	result, err := s.newAPI(c).ModelExpiry()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ModelExpiryResult{})
+}
+
+func (s *ReaperSuite) TestModelExpiryError(c *gc.C) {
+	s.backend.SetErrors(errors.New("boom"))
+	result, err := s.newAPI(c).ModelExpiry()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.ErrorMatches, "boom")
+}
+
+func (s *ReaperSuite) TestDestroyModel(c *gc.C) {
+	err := s.newAPI(c).DestroyModel()
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.CheckCallNames(c, "EphemeralExpiry", "IsController", "DestroyModel")
+}
+
+func (s *ReaperSuite) TestDestroyModelError(c *gc.C) {
+	s.backend.SetErrors(nil, errors.New("boom"))
+	err := s.newAPI(c).DestroyModel()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ReaperSuite) TestDestroyModelNotEphemeral(c *gc.C) {
+	s.backend.expiry = time.Time{}
+	err := s.newAPI(c).DestroyModel()
+	c.Assert(err, gc.ErrorMatches, "model is not ephemeral")
+	s.backend.CheckCallNames(c, "EphemeralExpiry")
+}
+
+func (s *ReaperSuite) TestDestroyModelNotExpired(c *gc.C) {
+	s.clock = testing.NewClock(s.backend.expiry.Add(-time.Second))
+	err := s.newAPI(c).DestroyModel()
+	c.Assert(err, gc.ErrorMatches, "model does not expire until 2018-04-01T12:00:00Z")
+	s.backend.CheckCallNames(c, "EphemeralExpiry")
+}
+
+func (s *ReaperSuite) TestDestroyModelController(c *gc.C) {
+	s.backend.controller = true
+	err := s.newAPI(c).DestroyModel()
+	c.Assert(err, gc.ErrorMatches, "cannot destroy the controller model")
+	s.backend.CheckCallNames(c, "EphemeralExpiry", "IsController")
+}
+
+type mockBackend struct {
+	*testing.Stub
+	expiry     time.Time
+	controller bool
+}
+
+func (b *mockBackend) EphemeralExpiry() (time.Time, bool, error) {
+	b.MethodCall(b, "EphemeralExpiry")
+	if err := b.NextErr(); err != nil {
+		return time.Time{}, false, err
+	}
+	return b.expiry, !b.expiry.IsZero(), nil
+}
+
+func (b *mockBackend) WatchModel() state.NotifyWatcher {
+	b.MethodCall(b, "WatchModel")
+	return apiservertesting.NewFakeNotifyWatcher()
+}
+
+func (b *mockBackend) IsController() bool {
+	b.MethodCall(b, "IsController")
+	return b.controller
+}
+
+func (b *mockBackend) DestroyModel() error {
+	b.MethodCall(b, "DestroyModel")
+	return b.NextErr()
+}
@@ -112,6 +112,18 @@ func (st *mockState) FindEntity(tag names.Tag) (state.Entity, error) {
 	return nil, errors.NotImplementedf("FindEntity")
 }
 
+func (st *mockState) WatchAnnotations() state.NotifyWatcher {
+	st.MethodCall(st, "WatchAnnotations")
+	// TODO - implement when remaining firewaller tests become unit tests
+	return nil
+}
+
+func (st *mockState) Annotation(entity state.GlobalEntity, key string) (string, error) {
+	st.MethodCall(st, "Annotation", entity, key)
+	// TODO - implement when remaining firewaller tests become unit tests
+	return "", errors.NotImplementedf("Annotation")
+}
+
 func (st *mockState) FirewallRule(service state.WellKnownServiceType) (*state.FirewallRule, error) {
 	r, ok := st.firewallRules[service]
 	if !ok {
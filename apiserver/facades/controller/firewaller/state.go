@@ -25,16 +25,21 @@ type State interface {
 	FindEntity(tag names.Tag) (state.Entity, error)
 
 	FirewallRule(service state.WellKnownServiceType) (*state.FirewallRule, error)
+
+	WatchAnnotations() state.NotifyWatcher
+
+	Annotation(entity state.GlobalEntity, key string) (string, error)
 }
 
 // TODO(wallyworld) - for tests, remove when remaining firewaller tests become unit tests.
 func StateShim(st *state.State, m *state.Model) stateShim {
-	return stateShim{st: st, State: firewall.StateShim(st, m)}
+	return stateShim{st: st, model: m, State: firewall.StateShim(st, m)}
 }
 
 type stateShim struct {
 	firewall.State
-	st *state.State
+	st    *state.State
+	model *state.Model
 }
 
 func (st stateShim) ModelUUID() string {
@@ -54,6 +59,14 @@ func (st stateShim) WatchOpenedPorts() state.StringsWatcher {
 	return st.st.WatchOpenedPorts()
 }
 
+func (st stateShim) WatchAnnotations() state.NotifyWatcher {
+	return st.st.WatchAnnotations()
+}
+
+func (st stateShim) Annotation(entity state.GlobalEntity, key string) (string, error) {
+	return st.model.Annotation(entity, key)
+}
+
 func (s stateShim) FirewallRule(service state.WellKnownServiceType) (*state.FirewallRule, error) {
 	api := state.NewFirewallRules(s.st)
 	return api.Rule(service)
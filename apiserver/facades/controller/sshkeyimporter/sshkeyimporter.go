@@ -0,0 +1,156 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sshkeyimporter
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/set"
+	"github.com/juju/utils/ssh"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/sshkeys"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.sshkeyimporter")
+
+// Backend defines the model functionality required by the
+// sshkeyimporter facade. For details on the methods, see the methods
+// on state.Model with the same names.
+type Backend interface {
+	ModelConfig() (*config.Config, error)
+	UpdateModelConfig(map[string]interface{}, []string, ...state.ValidateConfigFunc) error
+}
+
+// ImportFunc fetches the keys published by a key server source.
+type ImportFunc func(source string) ([]string, error)
+
+// API provides the SSHKeyImporter facade for v1.
+type API struct {
+	*common.ModelWatcher
+	backend    Backend
+	clock      clock.Clock
+	importKeys ImportFunc
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	model, err := ctx.State().Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	modelWatcher := common.NewModelWatcher(model, ctx.Resources(), ctx.Auth())
+	return NewAPI(model, modelWatcher, clock.WallClock, sshkeys.Import, ctx.Auth())
+}
+
+// NewAPI returns a new SSHKeyImporter API facade.
+func NewAPI(
+	backend Backend,
+	modelWatcher *common.ModelWatcher,
+	clock clock.Clock,
+	importKeys ImportFunc,
+	authorizer facade.Authorizer,
+) (*API, error) {
+	if !authorizer.AuthController() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		ModelWatcher: modelWatcher,
+		backend:      backend,
+		clock:        clock,
+		importKeys:   importKeys,
+	}, nil
+}
+
+// RefreshKeys imports the keys published by each of the model's ssh key
+// sources, replacing the keys previously imported from them, and
+// removes any keys that have expired. Keys imported from a source that
+// is no longer configured are removed; keys previously imported from a
+// source that cannot currently be reached are kept.
+func (api *API) RefreshKeys() (params.SSHKeyRefreshResult, error) {
+	var result params.SSHKeyRefreshResult
+	cfg, err := api.backend.ModelConfig()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	current := ssh.SplitAuthorisedKeys(cfg.AuthorizedKeys())
+	sources := cfg.SSHKeySources()
+
+	imported := make(map[string][]string)
+	for _, source := range sources {
+		keys, err := api.importKeys(source)
+		sourceResult := params.SSHKeySourceResult{Source: source}
+		if err != nil {
+			logger.Warningf("cannot refresh ssh keys from %s: %v", source, err)
+			sourceResult.Error = common.ServerError(err)
+		} else {
+			imported[source] = keys
+			sourceResult.Keys = len(keys)
+		}
+		result.Sources = append(result.Sources, sourceResult)
+	}
+
+	configured := set.NewStrings(sources...)
+	var keys []string
+	for _, key := range current {
+		if source := sshkeys.ImportSource(key); source != "" {
+			if !configured.Contains(source) {
+				continue
+			}
+			if _, ok := imported[source]; ok {
+				continue
+			}
+		}
+		keys = append(keys, key)
+	}
+	fingerprints := set.NewStrings()
+	for _, key := range keys {
+		if fingerprint, _, err := ssh.KeyFingerprint(key); err == nil {
+			fingerprints.Add(fingerprint)
+		}
+	}
+	for _, source := range sources {
+		for _, key := range imported[source] {
+			fingerprint, _, err := ssh.KeyFingerprint(key)
+			if err != nil {
+				logger.Warningf("ignoring invalid ssh key from %s: %v", source, err)
+				continue
+			}
+			if fingerprints.Contains(fingerprint) {
+				continue
+			}
+			fingerprints.Add(fingerprint)
+			keys = append(keys, key)
+		}
+	}
+
+	now := api.clock.Now()
+	keys, nextExpiry := sshkeys.Unexpired(keys, now)
+	result.RefreshAfter = cfg.SSHKeyRefreshInterval()
+	if !nextExpiry.IsZero() && nextExpiry.Sub(now) < result.RefreshAfter {
+		result.RefreshAfter = nextExpiry.Sub(now)
+	}
+
+	authorizedKeys := strings.Join(keys, "\n")
+	if authorizedKeys == strings.Join(current, "\n") {
+		return result, nil
+	}
+	if len(keys) == 0 {
+		return result, errors.New("cannot remove all authorized keys")
+	}
+	attrs := map[string]interface{}{
+		config.AuthorizedKeysKey: authorizedKeys,
+	}
+	if err := api.backend.UpdateModelConfig(attrs, nil); err != nil {
+		return result, errors.Annotate(err, "updating authorized keys")
+	}
+	return result, nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sshkeyimporter_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	sshtesting "github.com/juju/utils/ssh/testing"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/controller/sshkeyimporter"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type SSHKeyImporterSuite struct {
+	testing.IsolationSuite
+	backend    mockBackend
+	clock      *testing.Clock
+	imported   map[string][]string
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&SSHKeyImporterSuite{})
+
+var (
+	localKey    = sshtesting.ValidKeyOne.Key + " user@host"
+	oldLPKey    = sshtesting.ValidKeyTwo.Key + " someone@laptop # ssh-import-id lp:someone"
+	newLPKey    = sshtesting.ValidKeyThree.Key + " someone@desktop # ssh-import-id lp:someone"
+	removedKey  = sshtesting.ValidKeyThree.Key + " other@host # ssh-import-id gh:other"
+	expiredKey  = `expiry-time="20180101" ` + sshtesting.ValidKeyTwo.Key + " temp@host"
+	expiringKey = `expiry-time="201803011200" ` + sshtesting.ValidKeyTwo.Key + " temp@host"
+)
+
+func (s *SSHKeyImporterSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag:        names.NewMachineTag("0"),
+		Controller: true,
+	}
+	s.clock = testing.NewClock(time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC))
+	s.imported = map[string][]string{
+		"lp:someone": {newLPKey},
+	}
+	s.backend = mockBackend{}
+}
+
+func (s *SSHKeyImporterSuite) newAPI(c *gc.C) *sshkeyimporter.API {
+	api, err := sshkeyimporter.NewAPI(&s.backend, nil, s.clock, s.importKeys, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *SSHKeyImporterSuite) importKeys(source string) ([]string, error) {
+	keys, ok := s.imported[source]
+	if !ok {
+		return nil, errors.NotFoundf("ssh keys for %s", source)
+	}
+	return keys, nil
+}
+
+func (s *SSHKeyImporterSuite) setConfig(c *gc.C, keys []string, sources string) {
+	cfg, err := config.New(config.UseDefaults, coretesting.FakeConfig().Merge(coretesting.Attrs{
+		"authorized-keys": strings.Join(keys, "\n"),
+		"ssh-key-sources": sources,
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.cfg = cfg
+}
+
+func (s *SSHKeyImporterSuite) TestNewAPIRequiresController(c *gc.C) {
+	s.authorizer.Controller = false
+	_, err := sshkeyimporter.NewAPI(&s.backend, nil, s.clock, s.importKeys, s.authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *SSHKeyImporterSuite) TestRefreshKeys(c *gc.C) {
+	s.setConfig(c, []string{localKey, oldLPKey, removedKey, expiredKey}, "lp:someone")
+	result, err := s.newAPI(c).RefreshKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.SSHKeyRefreshResult{
+		Sources: []params.SSHKeySourceResult{{
+			Source: "lp:someone",
+			Keys:   1,
+		}},
+		RefreshAfter: 24 * time.Hour,
+	})
+	s.backend.CheckCall(c, 1, "UpdateModelConfig", map[string]interface{}{
+		"authorized-keys": localKey + "\n" + newLPKey,
+	})
+}
+
+func (s *SSHKeyImporterSuite) TestRefreshKeysSourceUnavailable(c *gc.C) {
+	s.setConfig(c, []string{localKey, oldLPKey, removedKey}, "lp:someone gh:other")
+	delete(s.imported, "lp:someone")
+	result, err := s.newAPI(c).RefreshKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Sources, gc.HasLen, 2)
+	c.Assert(result.Sources[0].Error, gc.ErrorMatches, "ssh keys for lp:someone not found")
+	c.Assert(result.Sources[1].Error, gc.ErrorMatches, "ssh keys for gh:other not found")
+	// Keys previously imported from unreachable sources are kept.
+	s.backend.CheckCallNames(c, "ModelConfig")
+}
+
+func (s *SSHKeyImporterSuite) TestRefreshKeysNextExpiry(c *gc.C) {
+	s.setConfig(c, []string{localKey, expiringKey}, "")
+	result, err := s.newAPI(c).RefreshKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.RefreshAfter, gc.Equals, 12*time.Hour)
+	s.backend.CheckCallNames(c, "ModelConfig")
+}
+
+func (s *SSHKeyImporterSuite) TestRefreshKeysWillNotRemoveAllKeys(c *gc.C) {
+	s.setConfig(c, []string{expiredKey}, "")
+	_, err := s.newAPI(c).RefreshKeys()
+	c.Assert(err, gc.ErrorMatches, "cannot remove all authorized keys")
+	s.backend.CheckCallNames(c, "ModelConfig")
+}
+
+type mockBackend struct {
+	testing.Stub
+	cfg *config.Config
+}
+
+func (m *mockBackend) ModelConfig() (*config.Config, error) {
+	m.MethodCall(m, "ModelConfig")
+	return m.cfg, m.NextErr()
+}
+
+func (m *mockBackend) UpdateModelConfig(attrs map[string]interface{}, remove []string, _ ...state.ValidateConfigFunc) error {
+	m.MethodCall(m, "UpdateModelConfig", attrs)
+	return m.NextErr()
+}
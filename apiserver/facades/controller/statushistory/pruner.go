@@ -4,6 +4,8 @@
 package statushistory
 
 import (
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
@@ -31,12 +33,18 @@ func NewAPI(st *state.State, r facade.Resources, auth facade.Authorizer) (*API,
 	}, nil
 }
 
-// Prune endpoint removes status history entries until
-// only the ones newer than now - p.MaxHistoryTime remain and
-// the history is smaller than p.MaxHistoryMB.
+// Prune endpoint removes status history entries until no entity has
+// more than p.MaxEntriesPerEntity entries, if set, only the ones newer
+// than now - p.MaxHistoryTime remain and the history is smaller than
+// p.MaxHistoryMB.
 func (api *API) Prune(p params.StatusHistoryPruneArgs) error {
 	if !api.authorizer.AuthController() {
 		return common.ErrPerm
 	}
+	if p.MaxEntriesPerEntity > 0 {
+		if err := state.PruneStatusHistoryByEntity(api.st, p.MaxEntriesPerEntity); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	return state.PruneStatusHistory(api.st, p.MaxHistoryTime, p.MaxHistoryMB)
 }
@@ -0,0 +1,93 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package unitmover provides the API facade used by the unit mover
+// worker, which drives unit moves through their phases and removes
+// evacuated machines.
+package unitmover
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+// Backend defines the state methods needed by the unit mover facade.
+type Backend interface {
+	// WatchUnitMoves returns a watcher that notifies when unit moves
+	// are started or change phase.
+	WatchUnitMoves() state.NotifyWatcher
+
+	// AdvanceUnitMoves moves each unfinished unit move on as far as
+	// it can go, returning the number still unfinished.
+	AdvanceUnitMoves() (int, error)
+
+	// WatchMachineEvacuations returns a watcher that notifies when
+	// machine evacuations are started or finished.
+	WatchMachineEvacuations() state.NotifyWatcher
+
+	// AdvanceMachineEvacuations destroys each evacuating machine
+	// that no longer hosts any units, returning the number of
+	// evacuations still waiting for units to leave.
+	AdvanceMachineEvacuations() (int, error)
+}
+
+// API implements the API facade used by the unit mover worker.
+type API struct {
+	backend   Backend
+	resources facade.Resources
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(ctx.State(), ctx.Resources(), ctx.Auth())
+}
+
+// NewAPI returns a new unit mover API facade.
+func NewAPI(backend Backend, resources facade.Resources, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthController() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:   backend,
+		resources: resources,
+	}, nil
+}
+
+// WatchUnitMoves returns a watcher that notifies when unit moves are
+// started or change phase, and when machine evacuations are started or
+// finished.
+func (api *API) WatchUnitMoves() (params.NotifyWatchResult, error) {
+	watch := common.NewMultiNotifyWatcher(
+		api.backend.WatchUnitMoves(),
+		api.backend.WatchMachineEvacuations(),
+	)
+	if _, ok := <-watch.Changes(); ok {
+		return params.NotifyWatchResult{
+			NotifyWatcherId: api.resources.Register(watch),
+		}, nil
+	}
+	return params.NotifyWatchResult{
+		Error: common.ServerError(watcher.EnsureErr(watch)),
+	}, nil
+}
+
+// AdvanceUnitMoves moves each unfinished unit move on as far as it can
+// go, and then removes the evacuated machines that no longer host any
+// units. It returns the number of moves and evacuations still
+// unfinished.
+func (api *API) AdvanceUnitMoves() (params.IntResult, error) {
+	moves, err := api.backend.AdvanceUnitMoves()
+	if err != nil {
+		return params.IntResult{}, errors.Trace(err)
+	}
+	evacuations, err := api.backend.AdvanceMachineEvacuations()
+	if err != nil {
+		return params.IntResult{}, errors.Trace(err)
+	}
+	return params.IntResult{Result: moves + evacuations}, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package unitmover_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/controller/unitmover"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+)
+
+type UnitMoverSuite struct {
+	testing.IsolationSuite
+	backend    *mockBackend
+	resources  *common.Resources
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&UnitMoverSuite{})
+
+func (s *UnitMoverSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.backend = &mockBackend{moves: 2, evacuations: 1}
+	s.resources = common.NewResources()
+	s.AddCleanup(func(*gc.C) { s.resources.StopAll() })
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag:        names.NewMachineTag("0"),
+		Controller: true,
+	}
+}
+
+func (s *UnitMoverSuite) TestNewAPIRequiresController(c *gc.C) {
+	s.authorizer.Controller = false
+	_, err := unitmover.NewAPI(s.backend, s.resources, s.authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *UnitMoverSuite) TestWatchUnitMoves(c *gc.C) {
+	api, err := unitmover.NewAPI(s.backend, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.WatchUnitMoves()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.NotifyWatcherId, gc.Equals, "1")
+	c.Assert(s.resources.Get("1"), gc.NotNil)
+	s.backend.CheckCallNames(c, "WatchUnitMoves", "WatchMachineEvacuations")
+}
+
+func (s *UnitMoverSuite) TestAdvanceUnitMoves(c *gc.C) {
+	api, err := unitmover.NewAPI(s.backend, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.AdvanceUnitMoves()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Result, gc.Equals, 3)
+	s.backend.CheckCallNames(c, "AdvanceUnitMoves", "AdvanceMachineEvacuations")
+}
+
+func (s *UnitMoverSuite) TestAdvanceUnitMovesError(c *gc.C) {
+	s.backend.SetErrors(errors.New("boom"))
+	api, err := unitmover.NewAPI(s.backend, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.AdvanceUnitMoves()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type mockBackend struct {
+	testing.Stub
+	moves       int
+	evacuations int
+}
+
+func (b *mockBackend) WatchUnitMoves() state.NotifyWatcher {
+	b.MethodCall(b, "WatchUnitMoves")
+	return apiservertesting.NewFakeNotifyWatcher()
+}
+
+func (b *mockBackend) AdvanceUnitMoves() (int, error) {
+	b.MethodCall(b, "AdvanceUnitMoves")
+	return b.moves, b.NextErr()
+}
+
+func (b *mockBackend) WatchMachineEvacuations() state.NotifyWatcher {
+	b.MethodCall(b, "WatchMachineEvacuations")
+	return apiservertesting.NewFakeNotifyWatcher()
+}
+
+func (b *mockBackend) AdvanceMachineEvacuations() (int, error) {
+	b.MethodCall(b, "AdvanceMachineEvacuations")
+	return b.evacuations, b.NextErr()
+}
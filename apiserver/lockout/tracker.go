@@ -0,0 +1,439 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package lockout throttles, and then locks out, users that repeatedly
+// fail to log in to the API server, to resist password guessing and
+// credential stuffing.
+//
+// Failures are counted per user and source address, so that a user
+// under attack from one address may still log in from another, and
+// optionally per address, so that an address trying many users is
+// locked out too. Each API server counts only the failures it sees, so
+// in a highly available controller a user may fail up to the limit
+// against each API server; lockouts are cleared on all API servers.
+package lockout
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/pubsub"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/pubsub/apiserver"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.lockout")
+
+// DefaultFailurePause is the default value for Policy.FailurePause.
+const DefaultFailurePause = time.Second
+
+// DefaultMaxRecords is the default value for Config.MaxRecords.
+const DefaultMaxRecords = 10000
+
+// Policy determines when users are throttled and locked out.
+type Policy struct {
+	// MaxFailures is the number of consecutive failed logins a user
+	// may make from an address before further logins by the user from
+	// that address are refused. Users are never throttled or locked
+	// out if MaxFailures is zero.
+	MaxFailures int
+
+	// MaxAddressFailures is the number of failed logins, by any users,
+	// that may be made from an address before all further logins from
+	// that address are refused. Addresses are never locked out if
+	// MaxAddressFailures is zero.
+	MaxAddressFailures int
+
+	// Duration is how long a user is locked out for. Failures are
+	// forgotten once this long has passed without another.
+	Duration time.Duration
+
+	// FailurePause is how long a failed login is held before it is
+	// reported, for each recent failure of the user from the address.
+	FailurePause time.Duration
+}
+
+// Validate checks that the policy is valid.
+func (p Policy) Validate() error {
+	if p.MaxFailures < 0 {
+		return errors.NotValidf("negative MaxFailures")
+	}
+	if p.MaxAddressFailures < 0 {
+		return errors.NotValidf("negative MaxAddressFailures")
+	}
+	if p.enabled() && p.Duration <= 0 {
+		return errors.NotValidf("non-positive Duration")
+	}
+	if p.FailurePause < 0 {
+		return errors.NotValidf("negative FailurePause")
+	}
+	return nil
+}
+
+func (p Policy) enabled() bool {
+	return p.MaxFailures > 0 || p.MaxAddressFailures > 0
+}
+
+// Info describes the failed logins of a user from an address.
+type Info struct {
+	// Server identifies the API server that saw the failures.
+	Server string
+
+	// User is the user that failed to log in, or the zero tag if
+	// the failures are those of any users from the address.
+	User names.UserTag
+
+	// Address is the address the user failed to log in from.
+	Address string
+
+	// Failures is the number of consecutive failed logins.
+	Failures int
+
+	// LastFailure is when the user last failed to log in.
+	LastFailure time.Time
+
+	// LockedUntil is when the user may next log in from the address,
+	// or the zero time if the user is not locked out.
+	LockedUntil time.Time
+}
+
+// Config holds the configuration of a Tracker.
+type Config struct {
+	// ServerID identifies the API server.
+	ServerID string
+
+	// Clock is used to time failures and lockouts.
+	Clock clock.Clock
+
+	// Hub is used to publish clearances to, and receive them from,
+	// all API servers.
+	Hub *pubsub.StructuredHub
+
+	// Policy determines when users are locked out.
+	Policy Policy
+
+	// MaxRecords bounds the number of users and addresses whose
+	// failures are remembered. When it is reached, expired failures
+	// are forgotten, and then the least recent of those that are not
+	// locked out. If every record is locked out, further failures by
+	// other users and addresses are held for the longest pause
+	// instead of being counted. It defaults to DefaultMaxRecords.
+	MaxRecords int
+}
+
+// Validate checks that the config is valid.
+func (config Config) Validate() error {
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.Hub == nil {
+		return errors.NotValidf("nil Hub")
+	}
+	if err := config.Policy.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if config.MaxRecords < 0 {
+		return errors.NotValidf("negative MaxRecords")
+	}
+	return nil
+}
+
+// key identifies the logins of a user from an address, or of any
+// users from an address if user is empty.
+type key struct {
+	user    string
+	address string
+}
+
+type record struct {
+	user        names.UserTag
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// Tracker records the failed logins of users to an API server.
+type Tracker struct {
+	config      Config
+	unsubscribe func()
+
+	mu      sync.Mutex
+	records map[key]*record
+}
+
+// NewTracker returns a Tracker that clears its lockouts when clearances
+// are published on the hub. Close must be called when the tracker is no
+// longer needed.
+func NewTracker(config Config) (*Tracker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if config.MaxRecords == 0 {
+		config.MaxRecords = DefaultMaxRecords
+	}
+	t := &Tracker{
+		config:  config,
+		records: make(map[key]*record),
+	}
+	unsubscribe, err := config.Hub.Subscribe(apiserver.LoginLockoutClearTopic, t.onClearance)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	t.unsubscribe = unsubscribe
+	return t, nil
+}
+
+// Close stops the tracker receiving clearances.
+func (t *Tracker) Close() {
+	t.unsubscribe()
+}
+
+// Check returns an unauthorized error if the user, or every user, is
+// locked out from the remote address, which may include a port.
+func (t *Tracker) Check(user names.UserTag, remoteAddr string) error {
+	if !t.config.Policy.enabled() {
+		return nil
+	}
+	now := t.config.Clock.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if r := t.current(newKey(user, remoteAddr), now); r != nil && now.Before(r.lockedUntil) {
+		return errors.Unauthorizedf(
+			"too many failed logins; try again in %s",
+			r.lockedUntil.Sub(now).Round(time.Second),
+		)
+	}
+	k := addressKey(remoteAddr)
+	if r := t.current(k, now); r != nil && now.Before(r.lockedUntil) {
+		return errors.Unauthorizedf(
+			"too many failed logins from %s; try again in %s",
+			k.address, r.lockedUntil.Sub(now).Round(time.Second),
+		)
+	}
+	return nil
+}
+
+// Failed records a failed login by the user from the remote address,
+// locking the user out if it has failed too often. It returns how long
+// the failure should be held before it is reported.
+func (t *Tracker) Failed(user names.UserTag, remoteAddr string) time.Duration {
+	policy := t.config.Policy
+	if !policy.enabled() {
+		return 0
+	}
+	now := t.config.Clock.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var pause time.Duration
+	if policy.MaxFailures > 0 {
+		pause = t.fail(newKey(user, remoteAddr), user, policy.MaxFailures, now)
+	}
+	if policy.MaxAddressFailures > 0 {
+		addressPause := t.fail(addressKey(remoteAddr), names.UserTag{}, policy.MaxAddressFailures, now)
+		if addressPause > pause {
+			pause = addressPause
+		}
+	}
+	return pause
+}
+
+// fail records a failed login against the record with the given key,
+// locking it out once it has maxFailures failures. It returns how long
+// the failure should be held before it is reported. It must be called
+// with t.mu held.
+func (t *Tracker) fail(k key, user names.UserTag, maxFailures int, now time.Time) time.Duration {
+	policy := t.config.Policy
+	r := t.current(k, now)
+	if r == nil {
+		if !t.makeRoom(now) {
+			// Every record is locked out, so the failure cannot be
+			// counted; hold it as long as the longest pause instead.
+			logger.Warningf("too many lockouts recorded; throttling failed login of %q from %s", k.user, k.address)
+			return time.Duration(maxFailures) * policy.FailurePause
+		}
+		r = &record{user: user}
+		t.records[k] = r
+	}
+	r.failures++
+	r.lastFailure = now
+	if r.failures < maxFailures {
+		return time.Duration(r.failures) * policy.FailurePause
+	}
+	if r.lockedUntil.IsZero() {
+		if k.user == "" {
+			logger.Warningf(
+				"locking out all users from %s for %s after %d failed logins",
+				k.address, policy.Duration, r.failures,
+			)
+		} else {
+			logger.Warningf(
+				"locking out %s from %s for %s after %d failed logins",
+				user.Id(), k.address, policy.Duration, r.failures,
+			)
+		}
+	}
+	r.lockedUntil = now.Add(policy.Duration)
+	return 0
+}
+
+// makeRoom ensures there is room for another record, by forgetting
+// expired failures and then, if the tracker is still full, the least
+// recent of those that are not locked out. Lockouts are never
+// forgotten early; makeRoom returns false if every record is locked
+// out. It must be called with t.mu held.
+func (t *Tracker) makeRoom(now time.Time) bool {
+	if len(t.records) < t.config.MaxRecords {
+		return true
+	}
+	var (
+		oldest        key
+		oldestFailure time.Time
+	)
+	for k := range t.records {
+		r := t.current(k, now)
+		if r == nil || now.Before(r.lockedUntil) {
+			continue
+		}
+		if oldestFailure.IsZero() || r.lastFailure.Before(oldestFailure) {
+			oldest, oldestFailure = k, r.lastFailure
+		}
+	}
+	if len(t.records) < t.config.MaxRecords {
+		return true
+	}
+	if oldestFailure.IsZero() {
+		return false
+	}
+	logger.Warningf("too many failed logins recorded; forgetting those of %q from %s", oldest.user, oldest.address)
+	delete(t.records, oldest)
+	return true
+}
+
+// Lockouts returns the failed logins recorded by the API server that
+// have not yet been forgotten, ordered by user and address. Failures
+// by any users from an address are reported with the zero user tag.
+func (t *Tracker) Lockouts() []Info {
+	now := t.config.Clock.Now()
+	t.mu.Lock()
+	var result []Info
+	for k := range t.records {
+		r := t.current(k, now)
+		if r == nil {
+			continue
+		}
+		info := Info{
+			Server:      t.config.ServerID,
+			User:        r.user,
+			Address:     k.address,
+			Failures:    r.failures,
+			LastFailure: r.lastFailure,
+		}
+		if now.Before(r.lockedUntil) {
+			info.LockedUntil = r.lockedUntil
+		}
+		result = append(result, info)
+	}
+	t.mu.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].User.Id() != result[j].User.Id() {
+			return result[i].User.Id() < result[j].User.Id()
+		}
+		return result[i].Address < result[j].Address
+	})
+	return result
+}
+
+// Clear forgets the failed logins of the given users from any address,
+// and of any user from the given addresses, on every API server.
+func (t *Tracker) Clear(users []names.UserTag, addresses []string) error {
+	clearance := apiserver.LoginLockoutClearance{
+		Users:     make([]string, len(users)),
+		Addresses: addresses,
+	}
+	for i, user := range users {
+		clearance.Users[i] = user.Id()
+	}
+	t.clear(clearance)
+	if _, err := t.config.Hub.Publish(apiserver.LoginLockoutClearTopic, clearance); err != nil {
+		return errors.Annotate(err, "publishing login lockout clearance")
+	}
+	return nil
+}
+
+func (t *Tracker) onClearance(topic string, clearance apiserver.LoginLockoutClearance, err error) {
+	if err != nil {
+		logger.Errorf("cannot read login lockout clearance: %v", err)
+		return
+	}
+	t.clear(clearance)
+}
+
+// clear forgets the failed logins matching the clearance. Clearing is
+// idempotent, so clearances published by this server may be applied
+// again when they are received.
+func (t *Tracker) clear(clearance apiserver.LoginLockoutClearance) {
+	users := make(map[string]bool)
+	for _, user := range clearance.Users {
+		users[strings.ToLower(user)] = true
+	}
+	addresses := make(map[string]bool)
+	for _, address := range clearance.Addresses {
+		addresses[hostOnly(address)] = true
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for k := range t.records {
+		if users[k.user] || addresses[k.address] {
+			logger.Infof("clearing failed logins of %s from %s", k.user, k.address)
+			delete(t.records, k)
+		}
+	}
+}
+
+// current returns the record with the given key, or nil if there is
+// none or its failures have been forgotten. It must be called with
+// t.mu held.
+func (t *Tracker) current(k key, now time.Time) *record {
+	r, ok := t.records[k]
+	if !ok {
+		return nil
+	}
+	expiry := r.lastFailure.Add(t.config.Policy.Duration)
+	if r.lockedUntil.After(expiry) {
+		expiry = r.lockedUntil
+	}
+	if !now.Before(expiry) {
+		delete(t.records, k)
+		return nil
+	}
+	return r
+}
+
+func newKey(user names.UserTag, remoteAddr string) key {
+	return key{
+		user:    strings.ToLower(user.Id()),
+		address: hostOnly(remoteAddr),
+	}
+}
+
+func addressKey(remoteAddr string) key {
+	return key{address: hostOnly(remoteAddr)}
+}
+
+// hostOnly returns the host part of an address that may include a
+// port.
+func hostOnly(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
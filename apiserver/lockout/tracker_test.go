@@ -0,0 +1,273 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lockout_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/pubsub"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/lockout"
+	"github.com/juju/juju/pubsub/apiserver"
+	"github.com/juju/juju/pubsub/centralhub"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type trackerSuite struct {
+	testing.IsolationSuite
+
+	clock *testing.Clock
+	hub   *pubsub.StructuredHub
+	bob   names.UserTag
+}
+
+var _ = gc.Suite(&trackerSuite{})
+
+var start = time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func (s *trackerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testing.NewClock(start)
+	s.hub = centralhub.New(names.NewMachineTag("0"))
+	s.bob = names.NewUserTag("bob")
+}
+
+func (s *trackerSuite) newTracker(c *gc.C, policy lockout.Policy) *lockout.Tracker {
+	tracker, err := lockout.NewTracker(lockout.Config{
+		ServerID: "0",
+		Clock:    s.clock,
+		Hub:      s.hub,
+		Policy:   policy,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { tracker.Close() })
+	return tracker
+}
+
+var defaultPolicy = lockout.Policy{
+	MaxFailures:  3,
+	Duration:     time.Hour,
+	FailurePause: time.Second,
+}
+
+func (s *trackerSuite) TestValidate(c *gc.C) {
+	_, err := lockout.NewTracker(lockout.Config{Hub: s.hub})
+	c.Assert(err, gc.ErrorMatches, "nil Clock not valid")
+	_, err = lockout.NewTracker(lockout.Config{Clock: s.clock})
+	c.Assert(err, gc.ErrorMatches, "nil Hub not valid")
+	_, err = lockout.NewTracker(lockout.Config{
+		Clock:  s.clock,
+		Hub:    s.hub,
+		Policy: lockout.Policy{MaxFailures: 3},
+	})
+	c.Assert(err, gc.ErrorMatches, "non-positive Duration not valid")
+	_, err = lockout.NewTracker(lockout.Config{
+		Clock:  s.clock,
+		Hub:    s.hub,
+		Policy: lockout.Policy{MaxAddressFailures: -1},
+	})
+	c.Assert(err, gc.ErrorMatches, "negative MaxAddressFailures not valid")
+	_, err = lockout.NewTracker(lockout.Config{
+		Clock:      s.clock,
+		Hub:        s.hub,
+		MaxRecords: -1,
+	})
+	c.Assert(err, gc.ErrorMatches, "negative MaxRecords not valid")
+}
+
+func (s *trackerSuite) TestLockout(c *gc.C) {
+	tracker := s.newTracker(c, defaultPolicy)
+	c.Assert(tracker.Failed(s.bob, "10.0.0.1:40000"), gc.Equals, time.Second)
+	c.Assert(tracker.Failed(s.bob, "10.0.0.1:40001"), gc.Equals, 2*time.Second)
+	c.Assert(tracker.Check(s.bob, "10.0.0.1:40002"), jc.ErrorIsNil)
+
+	c.Assert(tracker.Failed(s.bob, "10.0.0.1:40003"), gc.Equals, time.Duration(0))
+	err := tracker.Check(s.bob, "10.0.0.1:40004")
+	c.Assert(err, gc.ErrorMatches, "too many failed logins; try again in 1h0m0s")
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+	c.Assert(tracker.Check(names.NewUserTag("Bob"), "10.0.0.1:40005"), gc.NotNil)
+
+	// Other users, and other addresses, are not locked out.
+	c.Assert(tracker.Check(names.NewUserTag("mary"), "10.0.0.1:40006"), jc.ErrorIsNil)
+	c.Assert(tracker.Check(s.bob, "10.0.0.2:40000"), jc.ErrorIsNil)
+
+	c.Assert(tracker.Lockouts(), jc.DeepEquals, []lockout.Info{{
+		Server:      "0",
+		User:        s.bob,
+		Address:     "10.0.0.1",
+		Failures:    3,
+		LastFailure: start,
+		LockedUntil: start.Add(time.Hour),
+	}})
+
+	s.clock.Advance(time.Hour)
+	c.Assert(tracker.Check(s.bob, "10.0.0.1:40007"), jc.ErrorIsNil)
+	c.Assert(tracker.Lockouts(), gc.HasLen, 0)
+}
+
+func (s *trackerSuite) TestFailuresForgotten(c *gc.C) {
+	tracker := s.newTracker(c, defaultPolicy)
+	tracker.Failed(s.bob, "10.0.0.1:40000")
+	tracker.Failed(s.bob, "10.0.0.1:40000")
+	s.clock.Advance(time.Hour)
+	c.Assert(tracker.Failed(s.bob, "10.0.0.1:40000"), gc.Equals, time.Second)
+	c.Assert(tracker.Check(s.bob, "10.0.0.1:40000"), jc.ErrorIsNil)
+}
+
+func (s *trackerSuite) TestSucceededForgetsFailures(c *gc.C) {
+	tracker := s.newTracker(c, defaultPolicy)
+	tracker.Failed(s.bob, "10.0.0.1:40000")
+	tracker.Failed(s.bob, "10.0.0.1:40000")
+	tracker.Succeeded(s.bob, "10.0.0.1:40001")
+	c.Assert(tracker.Lockouts(), gc.HasLen, 0)
+	c.Assert(tracker.Failed(s.bob, "10.0.0.1:40000"), gc.Equals, time.Second)
+}
+
+func (s *trackerSuite) TestDisabled(c *gc.C) {
+	tracker := s.newTracker(c, lockout.Policy{})
+	for i := 0; i < 100; i++ {
+		c.Assert(tracker.Failed(s.bob, "10.0.0.1:40000"), gc.Equals, time.Duration(0))
+	}
+	c.Assert(tracker.Check(s.bob, "10.0.0.1:40000"), jc.ErrorIsNil)
+	c.Assert(tracker.Lockouts(), gc.HasLen, 0)
+}
+
+func (s *trackerSuite) lockOut(tracker *lockout.Tracker, user names.UserTag, addr string) {
+	for i := 0; i < defaultPolicy.MaxFailures; i++ {
+		tracker.Failed(user, addr)
+	}
+}
+
+func (s *trackerSuite) TestClear(c *gc.C) {
+	tracker := s.newTracker(c, defaultPolicy)
+	mary := names.NewUserTag("mary")
+	s.lockOut(tracker, s.bob, "10.0.0.1:40000")
+	s.lockOut(tracker, s.bob, "10.0.0.2:40000")
+	s.lockOut(tracker, mary, "10.0.0.1:40000")
+	s.lockOut(tracker, mary, "10.0.0.3:40000")
+
+	err := tracker.Clear([]names.UserTag{s.bob}, []string{"10.0.0.3"})
+	c.Assert(err, jc.ErrorIsNil)
+	lockouts := tracker.Lockouts()
+	c.Assert(lockouts, gc.HasLen, 1)
+	c.Assert(lockouts[0].User, gc.Equals, mary)
+	c.Assert(lockouts[0].Address, gc.Equals, "10.0.0.1")
+}
+
+func (s *trackerSuite) TestClearanceFromOtherServer(c *gc.C) {
+	tracker := s.newTracker(c, defaultPolicy)
+	s.lockOut(tracker, s.bob, "10.0.0.1:40000")
+
+	done, err := s.hub.Publish(apiserver.LoginLockoutClearTopic, apiserver.LoginLockoutClearance{
+		Users: []string{"Bob"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case <-done:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("clearance not handled")
+	}
+	c.Assert(tracker.Check(s.bob, "10.0.0.1:40000"), jc.ErrorIsNil)
+}
+
+func (s *trackerSuite) TestAddressLockout(c *gc.C) {
+	tracker := s.newTracker(c, lockout.Policy{
+		MaxAddressFailures: 3,
+		Duration:           time.Hour,
+		FailurePause:       time.Second,
+	})
+	c.Assert(tracker.Failed(names.NewUserTag("bob"), "10.0.0.1:40000"), gc.Equals, time.Second)
+	c.Assert(tracker.Failed(names.NewUserTag("mary"), "10.0.0.1:40001"), gc.Equals, 2*time.Second)
+	c.Assert(tracker.Check(names.NewUserTag("fred"), "10.0.0.1:40002"), jc.ErrorIsNil)
+
+	c.Assert(tracker.Failed(names.NewUserTag("fred"), "10.0.0.1:40003"), gc.Equals, time.Duration(0))
+	err := tracker.Check(names.NewUserTag("jane"), "10.0.0.1:40004")
+	c.Assert(err, gc.ErrorMatches, "too many failed logins from 10.0.0.1; try again in 1h0m0s")
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+	c.Assert(tracker.Check(s.bob, "10.0.0.2:40000"), jc.ErrorIsNil)
+
+	c.Assert(tracker.Lockouts(), jc.DeepEquals, []lockout.Info{{
+		Server:      "0",
+		Address:     "10.0.0.1",
+		Failures:    3,
+		LastFailure: start,
+		LockedUntil: start.Add(time.Hour),
+	}})
+
+	err = tracker.Clear(nil, []string{"10.0.0.1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tracker.Check(names.NewUserTag("jane"), "10.0.0.1:40005"), jc.ErrorIsNil)
+}
+
+func (s *trackerSuite) TestMaxRecords(c *gc.C) {
+	tracker, err := lockout.NewTracker(lockout.Config{
+		ServerID:   "0",
+		Clock:      s.clock,
+		Hub:        s.hub,
+		Policy:     defaultPolicy,
+		MaxRecords: 2,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer tracker.Close()
+
+	tracker.Failed(s.bob, "10.0.0.1:40000")
+	s.clock.Advance(time.Minute)
+	tracker.Failed(s.bob, "10.0.0.2:40000")
+	s.clock.Advance(time.Minute)
+	tracker.Failed(s.bob, "10.0.0.3:40000")
+
+	// The least recent failures are forgotten to make room.
+	lockouts := tracker.Lockouts()
+	c.Assert(lockouts, gc.HasLen, 2)
+	c.Assert(lockouts[0].Address, gc.Equals, "10.0.0.2")
+	c.Assert(lockouts[1].Address, gc.Equals, "10.0.0.3")
+
+	// Expired failures are forgotten first.
+	s.clock.Advance(time.Hour - time.Minute)
+	tracker.Failed(s.bob, "10.0.0.4:40000")
+	lockouts = tracker.Lockouts()
+	c.Assert(lockouts, gc.HasLen, 2)
+	c.Assert(lockouts[0].Address, gc.Equals, "10.0.0.3")
+	c.Assert(lockouts[1].Address, gc.Equals, "10.0.0.4")
+}
+
+func (s *trackerSuite) TestMaxRecordsKeepsLockouts(c *gc.C) {
+	tracker, err := lockout.NewTracker(lockout.Config{
+		ServerID:   "0",
+		Clock:      s.clock,
+		Hub:        s.hub,
+		Policy:     defaultPolicy,
+		MaxRecords: 2,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer tracker.Close()
+
+	s.lockOut(tracker, s.bob, "10.0.0.1:40000")
+	s.clock.Advance(time.Minute)
+	tracker.Failed(s.bob, "10.0.0.2:40000")
+	s.clock.Advance(time.Minute)
+	tracker.Failed(s.bob, "10.0.0.3:40000")
+
+	// The lockout is kept, although its failures are least recent.
+	lockouts := tracker.Lockouts()
+	c.Assert(lockouts, gc.HasLen, 2)
+	c.Assert(lockouts[0].Address, gc.Equals, "10.0.0.1")
+	c.Assert(lockouts[1].Address, gc.Equals, "10.0.0.3")
+	c.Assert(tracker.Check(s.bob, "10.0.0.1:40001"), gc.NotNil)
+
+	// When every record is locked out, new failures are throttled
+	// rather than recorded.
+	s.lockOut(tracker, s.bob, "10.0.0.3:40000")
+	c.Assert(tracker.Failed(s.bob, "10.0.0.4:40000"), gc.Equals, 3*time.Second)
+	lockouts = tracker.Lockouts()
+	c.Assert(lockouts, gc.HasLen, 2)
+	c.Assert(lockouts[0].Address, gc.Equals, "10.0.0.1")
+	c.Assert(lockouts[1].Address, gc.Equals, "10.0.0.3")
+	c.Assert(tracker.Check(s.bob, "10.0.0.1:40001"), gc.NotNil)
+}
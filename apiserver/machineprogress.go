@@ -0,0 +1,80 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"net/http"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
+)
+
+// maxMachineProgressMessage is the longest progress message that is
+// recorded; longer messages are truncated.
+const maxMachineProgressMessage = 256
+
+// machineProgressHandler is an http.Handler for the
+// "/model/:modeluuid/machine-progress" endpoint. Machines that are
+// being provisioned POST the phases of their cloud-init configuration
+// to it, authenticating with the credentials and nonce in their
+// user-data, and the phases are recorded as the machine's instance
+// status.
+type machineProgressHandler struct {
+	ctxt httpContext
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *machineProgressHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if err := h.processPost(req); err != nil {
+		if err := sendError(w, err); err != nil {
+			logger.Errorf("%v", err)
+		}
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *machineProgressHandler) processPost(req *http.Request) error {
+	if req.Method != "POST" {
+		return errors.MethodNotAllowedf("unsupported method: %q", req.Method)
+	}
+	_, releaser, entity, err := h.ctxt.stateForRequestAuthenticatedTag(req, names.MachineTagKind)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer releaser()
+	machine, ok := entity.(*state.Machine)
+	if !ok {
+		return errors.BadRequestf("expected a machine, got %s", names.ReadableString(entity.Tag()))
+	}
+
+	message := req.FormValue("message")
+	if message == "" {
+		return errors.BadRequestf("missing progress message")
+	}
+	if len(message) > maxMachineProgressMessage {
+		message = message[:maxMachineProgressMessage]
+	}
+
+	// Progress is only recorded while the instance is being
+	// provisioned, so that a report delayed until after the agent
+	// has started does not hide the instance's real status.
+	current, err := machine.InstanceStatus()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if current.Status != status.Provisioning {
+		logger.Debugf("ignoring progress %q for machine %s with instance status %q", message, machine.Id(), current.Status)
+		return nil
+	}
+	now := h.ctxt.srv.clock.Now()
+	return errors.Trace(machine.SetInstanceStatus(status.StatusInfo{
+		Status:  status.Provisioning,
+		Message: message,
+		Since:   &now,
+	}))
+}
@@ -0,0 +1,120 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/testing/factory"
+)
+
+type machineProgressSuite struct {
+	authHTTPSuite
+	machine  *state.Machine
+	password string
+}
+
+var _ = gc.Suite(&machineProgressSuite{})
+
+func (s *machineProgressSuite) SetUpTest(c *gc.C) {
+	s.authHTTPSuite.SetUpTest(c)
+	s.machine, s.password = s.Factory.MakeMachineReturningPassword(c, &factory.MachineParams{
+		Nonce: "noncy",
+	})
+	err := s.machine.SetInstanceStatus(status.StatusInfo{
+		Status:  status.Provisioning,
+		Message: "starting",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *machineProgressSuite) progressURL(c *gc.C) string {
+	return s.makeURL(c, "https", "/model/"+s.modelUUID+"/machine-progress", nil).String()
+}
+
+func (s *machineProgressSuite) sendProgress(c *gc.C, method, nonce, message string) *http.Response {
+	form := url.Values{"message": {message}}
+	return s.sendRequest(c, httpRequestParams{
+		tag:         s.machine.Tag().String(),
+		password:    s.password,
+		nonce:       nonce,
+		method:      method,
+		url:         s.progressURL(c),
+		contentType: "application/x-www-form-urlencoded",
+		body:        strings.NewReader(form.Encode()),
+	})
+}
+
+func (s *machineProgressSuite) assertErrorResponse(c *gc.C, resp *http.Response, expCode int, expError string) {
+	body := assertResponse(c, resp, expCode, params.ContentTypeJSON)
+	var result params.ErrorResult
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, jc.ErrorIsNil, gc.Commentf("body: %s", body))
+	c.Assert(result.Error, gc.NotNil)
+	c.Assert(result.Error.Message, gc.Matches, expError)
+}
+
+func (s *machineProgressSuite) TestProgressRecordedInInstanceStatus(c *gc.C) {
+	resp := s.sendProgress(c, "POST", "noncy", "downloading agent binaries")
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+
+	resp = s.sendProgress(c, "POST", "noncy", "starting machine agent")
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+
+	history, err := s.machine.InstanceStatusHistory(status.StatusHistoryFilter{Size: 3})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 3)
+	for i, message := range []string{
+		"starting machine agent",
+		"downloading agent binaries",
+		"starting",
+	} {
+		c.Check(history[i].Status, gc.Equals, status.Provisioning)
+		c.Check(history[i].Message, gc.Equals, message)
+	}
+}
+
+func (s *machineProgressSuite) TestProgressIgnoredOnceProvisioned(c *gc.C) {
+	err := s.machine.SetInstanceStatus(status.StatusInfo{Status: status.Running})
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp := s.sendProgress(c, "POST", "noncy", "starting machine agent")
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+
+	current, err := s.machine.InstanceStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(current.Status, gc.Equals, status.Running)
+}
+
+func (s *machineProgressSuite) TestRequiresPOST(c *gc.C) {
+	resp := s.sendProgress(c, "GET", "noncy", "starting machine agent")
+	s.assertErrorResponse(c, resp, http.StatusMethodNotAllowed, `unsupported method: "GET"`)
+}
+
+func (s *machineProgressSuite) TestRequiresNonce(c *gc.C) {
+	resp := s.sendProgress(c, "POST", "wrong", "starting machine agent")
+	s.assertErrorResponse(c, resp, http.StatusUnauthorized, ".*not provisioned.*")
+}
+
+func (s *machineProgressSuite) TestRequiresMachineAuth(c *gc.C) {
+	resp := s.authRequest(c, httpRequestParams{method: "POST", url: s.progressURL(c)})
+	s.assertErrorResponse(c, resp, http.StatusInternalServerError, ".*tag kind user not valid$")
+}
+
+func (s *machineProgressSuite) TestRequiresMessage(c *gc.C) {
+	resp := s.sendProgress(c, "POST", "noncy", "")
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, "missing progress message")
+}
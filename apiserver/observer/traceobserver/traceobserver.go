@@ -0,0 +1,120 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package traceobserver provides an implementation of
+// apiserver/observer.ObserverFactory that records a span for each API
+// request handled.
+package traceobserver
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/observer"
+	"github.com/juju/juju/core/tracing"
+	"github.com/juju/juju/rpc"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.observer.traceobserver")
+
+// Config contains the configuration for an Observer.
+type Config struct {
+	// Clock is used to time the requests.
+	Clock clock.Clock
+
+	// Recorder records the spans of the requests.
+	Recorder tracing.Recorder
+}
+
+// Validate validates the observer factory configuration.
+func (cfg Config) Validate() error {
+	if cfg.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if cfg.Recorder == nil {
+		return errors.NotValidf("nil Recorder")
+	}
+	return nil
+}
+
+// NewObserverFactory returns a function that, when called, returns a
+// new Observer for an API connection.
+func NewObserverFactory(config Config) (observer.ObserverFactory, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Annotate(err, "validating config")
+	}
+	return func() observer.Observer {
+		return &Observer{
+			clock:    config.Clock,
+			recorder: config.Recorder,
+		}
+	}, nil
+}
+
+// Observer is an API server connection observer that records a
+// facade-call span for each request, attributed to the entity logged
+// in to the connection.
+type Observer struct {
+	clock    clock.Clock
+	recorder tracing.Recorder
+
+	mu     sync.Mutex
+	entity string
+}
+
+// Login is part of the observer.Observer interface.
+func (o *Observer) Login(entity names.Tag, _ names.ModelTag, _ bool, _ string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.entity = entity.String()
+}
+
+// Join is part of the observer.Observer interface.
+func (*Observer) Join(req *http.Request, connectionID uint64) {}
+
+// Leave is part of the observer.Observer interface.
+func (*Observer) Leave() {}
+
+// RPCObserver is part of the observer.Observer interface.
+func (o *Observer) RPCObserver() rpc.Observer {
+	return &rpcObserver{observer: o}
+}
+
+func (o *Observer) loggedIn() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.entity
+}
+
+type rpcObserver struct {
+	observer     *Observer
+	requestStart time.Time
+}
+
+// ServerRequest is part of the rpc.Observer interface.
+func (o *rpcObserver) ServerRequest(hdr *rpc.Header, body interface{}) {
+	o.requestStart = o.observer.clock.Now()
+}
+
+// ServerReply is part of the rpc.Observer interface.
+func (o *rpcObserver) ServerReply(req rpc.Request, hdr *rpc.Header, body interface{}) {
+	span := tracing.Span{
+		Kind:     tracing.KindFacadeCall,
+		Entity:   o.observer.loggedIn(),
+		Name:     req.Type + "." + req.Action,
+		Start:    o.requestStart,
+		Duration: o.observer.clock.Now().Sub(o.requestStart),
+		Error:    hdr.Error,
+
+		CorrelationID: hdr.CorrelationId,
+	}
+	if err := o.observer.recorder.RecordSpans([]tracing.Span{span}); err != nil {
+		logger.Warningf("cannot record span for %s: %v", span.Name, err)
+	}
+}
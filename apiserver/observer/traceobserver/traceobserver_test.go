@@ -0,0 +1,120 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package traceobserver_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/observer"
+	"github.com/juju/juju/apiserver/observer/traceobserver"
+	"github.com/juju/juju/core/tracing"
+	"github.com/juju/juju/rpc"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type observerSuite struct {
+	testing.IsolationSuite
+	clock    *testing.Clock
+	recorder *fakeRecorder
+	factory  observer.ObserverFactory
+}
+
+var _ = gc.Suite(&observerSuite{})
+
+func (s *observerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testing.NewClock(time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC))
+	s.recorder = &fakeRecorder{}
+
+	var err error
+	s.factory, err = traceobserver.NewObserverFactory(traceobserver.Config{
+		Clock:    s.clock,
+		Recorder: s.recorder,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *observerSuite) TestNewObserverFactoryValidates(c *gc.C) {
+	_, err := traceobserver.NewObserverFactory(traceobserver.Config{Clock: s.clock})
+	c.Assert(err, gc.ErrorMatches, "validating config: nil Recorder not valid")
+}
+
+func (s *observerSuite) TestRPCObserver(c *gc.C) {
+	o := s.factory()
+	o.Login(names.NewUserTag("admin"), coretesting.ModelTag, false, "")
+
+	start := s.clock.Now()
+	req := rpc.Request{
+		Type:    "Client",
+		Version: 1,
+		Action:  "FullStatus",
+	}
+	rpcObserver := o.RPCObserver()
+	rpcObserver.ServerRequest(&rpc.Header{Request: req}, nil)
+	s.clock.Advance(1500 * time.Millisecond)
+	rpcObserver.ServerReply(req, &rpc.Header{Error: "permission denied"}, nil)
+
+	c.Assert(s.recorder.spans, jc.DeepEquals, []tracing.Span{{
+		Kind:     tracing.KindFacadeCall,
+		Entity:   "user-admin",
+		Name:     "Client.FullStatus",
+		Start:    start,
+		Duration: 1500 * time.Millisecond,
+		Error:    "permission denied",
+	}})
+}
+
+func (s *observerSuite) TestRPCObserverCorrelationId(c *gc.C) {
+	o := s.factory()
+	o.Login(names.NewUserTag("admin"), coretesting.ModelTag, false, "")
+
+	start := s.clock.Now()
+	req := rpc.Request{
+		Type:    "Action",
+		Version: 3,
+		Action:  "Enqueue",
+	}
+	rpcObserver := o.RPCObserver()
+	rpcObserver.ServerRequest(&rpc.Header{Request: req, CorrelationId: "deadbeef"}, nil)
+	s.clock.Advance(time.Second)
+	rpcObserver.ServerReply(req, &rpc.Header{CorrelationId: "deadbeef"}, nil)
+
+	c.Assert(s.recorder.spans, jc.DeepEquals, []tracing.Span{{
+		Kind:          tracing.KindFacadeCall,
+		Entity:        "user-admin",
+		Name:          "Action.Enqueue",
+		Start:         start,
+		Duration:      time.Second,
+		CorrelationID: "deadbeef",
+	}})
+}
+
+func (s *observerSuite) TestRPCObserverBeforeLogin(c *gc.C) {
+	req := rpc.Request{
+		Type:    "Admin",
+		Version: 3,
+		Action:  "Login",
+	}
+	rpcObserver := s.factory().RPCObserver()
+	rpcObserver.ServerRequest(&rpc.Header{Request: req}, nil)
+	rpcObserver.ServerReply(req, &rpc.Header{}, nil)
+
+	c.Assert(s.recorder.spans, gc.HasLen, 1)
+	c.Assert(s.recorder.spans[0].Entity, gc.Equals, "")
+	c.Assert(s.recorder.spans[0].Name, gc.Equals, "Admin.Login")
+}
+
+type fakeRecorder struct {
+	spans []tracing.Span
+}
+
+func (r *fakeRecorder) RecordSpans(spans []tracing.Span) error {
+	r.spans = append(r.spans, spans...)
+	return nil
+}
@@ -34,10 +34,11 @@ type Actions struct {
 
 // Action describes an Action that will be or has been queued up.
 type Action struct {
-	Tag        string                 `json:"tag"`
-	Receiver   string                 `json:"receiver"`
-	Name       string                 `json:"name"`
-	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Tag           string                 `json:"tag"`
+	Receiver      string                 `json:"receiver"`
+	Name          string                 `json:"name"`
+	Parameters    map[string]interface{} `json:"parameters,omitempty"`
+	CorrelationId string                 `json:"correlation-id,omitempty"`
 }
 
 // ActionResults is a slice of ActionResult for bulk requests.
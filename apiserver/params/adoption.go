@@ -0,0 +1,40 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// AdoptionCandidate describes an instance that is tagged as belonging
+// to a model, but which no machine in the model records.
+type AdoptionCandidate struct {
+	// InstanceId is the provider-specific id of the instance.
+	InstanceId string `json:"instance-id"`
+
+	// MachineTag is the tag of the machine the instance was started
+	// for, if the instance's tags record it.
+	MachineTag string `json:"machine-tag,omitempty"`
+
+	// Units holds the names of the units that were deployed to the
+	// instance, if the instance's tags record them.
+	Units []string `json:"units,omitempty"`
+}
+
+// AdoptionCandidatesResult holds the instances that may be adopted
+// into a model.
+type AdoptionCandidatesResult struct {
+	Candidates []AdoptionCandidate `json:"candidates"`
+}
+
+// AdoptMachine holds the details of an instance to adopt, taken from
+// the configuration of the machine agent running on it.
+type AdoptMachine struct {
+	InstanceId string `json:"instance-id"`
+	MachineTag string `json:"machine-tag"`
+	Series     string `json:"series"`
+	Nonce      string `json:"nonce"`
+	Password   string `json:"password"`
+}
+
+// AdoptMachines holds the parameters for the Verify and Adopt calls.
+type AdoptMachines struct {
+	Machines []AdoptMachine `json:"machines"`
+}
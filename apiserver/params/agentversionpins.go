@@ -0,0 +1,21 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// AgentVersionPin pins the agents of a machine, or of the machines
+// hosting an application's units, to a range of agent versions.
+type AgentVersionPin struct {
+	// Tag is the tag of the machine or application.
+	Tag string `json:"tag"`
+
+	// Range is the range of agent versions, in the form "MIN..MAX",
+	// either of which may be omitted. A single version pins the agents
+	// to exactly that version, and an empty range removes the pin.
+	Range string `json:"range,omitempty"`
+}
+
+// AgentVersionPins holds the arguments for setting agent version pins.
+type AgentVersionPins struct {
+	Pins []AgentVersionPin `json:"pins"`
+}
@@ -42,6 +42,20 @@ type ErrorInfo struct {
 	// If it is empty, the macaroon will be associated with
 	// the original URL from which the error was returned.
 	MacaroonPath string `json:"macaroon-path,omitempty"`
+
+	// ConfigErrors holds the reasons that a model configuration is
+	// not valid, at most one for each attribute. This field is
+	// associated with the CodeConfigNotValid error code.
+	ConfigErrors []ConfigAttributeError `json:"config-errors,omitempty"`
+}
+
+// ConfigAttributeError describes why an attribute of a model
+// configuration is not valid.
+type ConfigAttributeError struct {
+	// Attribute is the name of the attribute, and is empty if the
+	// error concerns the configuration as a whole.
+	Attribute string `json:"attribute,omitempty"`
+	Message   string `json:"message"`
 }
 
 func (e Error) Error() string {
@@ -98,6 +112,7 @@ const (
 	CodeRedirect                  = "redirection required"
 	CodeRetry                     = "retry"
 	CodeIncompatibleSeries        = "incompatible series"
+	CodeConfigNotValid            = "config not valid"
 )
 
 // ErrCode returns the error code associated with
@@ -263,3 +278,7 @@ func IsCodeIncompatibleSeries(err error) bool {
 func IsCodeForbidden(err error) bool {
 	return ErrCode(err) == CodeForbidden
 }
+
+func IsCodeConfigNotValid(err error) bool {
+	return ErrCode(err) == CodeConfigNotValid
+}
@@ -19,6 +19,21 @@ type BackupsInfoArgs struct {
 	ID string `json:"id"`
 }
 
+// BackupsVerifyArgs holds the args for the API Verify method.
+type BackupsVerifyArgs struct {
+	ID string `json:"id"`
+}
+
+// BackupsVerifyResult holds the result of an API Verify call.
+type BackupsVerifyResult struct {
+	// Models holds the UUIDs of the models found in the backup.
+	Models []string `json:"models"`
+
+	// Discrepancies describes each difference found between the
+	// restored backup and the live controller.
+	Discrepancies []string `json:"discrepancies,omitempty"`
+}
+
 // BackupsListArgs holds the args for the API List method.
 type BackupsListArgs struct {
 }
@@ -0,0 +1,49 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import "time"
+
+// ChatSummary holds a compact, human oriented summary of a model,
+// sized to be posted as a chat message.
+type ChatSummary struct {
+	// Model is the name of the model.
+	Model string `json:"model"`
+
+	// Health is "healthy", "degraded" (something is blocked or
+	// waiting on the operator) or "error".
+	Health string `json:"health"`
+
+	Machines     int `json:"machines"`
+	Applications int `json:"applications"`
+	Units        int `json:"units"`
+
+	// RecentErrors holds the most recent errors in the model, latest
+	// first.
+	RecentErrors []ChatSummaryError `json:"recent-errors,omitempty"`
+
+	// PendingUpgrades describes the upgrades available to the model's
+	// agents and charms.
+	PendingUpgrades []string `json:"pending-upgrades,omitempty"`
+
+	// Text holds the whole summary rendered as a single message.
+	Text string `json:"text"`
+}
+
+// ChatSummaryError describes an entity in an error state.
+type ChatSummaryError struct {
+	Entity  string     `json:"entity"`
+	Message string     `json:"message"`
+	Since   *time.Time `json:"since,omitempty"`
+}
+
+// ChatSummaryPushArgs holds the arguments for pushing a model's chat
+// summary to a webhook.
+type ChatSummaryPushArgs struct {
+	// WebhookURL is the URL to which the summary is POSTed, as a JSON
+	// object with the message in its "text" field, as accepted by
+	// the incoming webhooks of common chat services. It must be one
+	// of the URLs in the controller's chat-webhook-urls config.
+	WebhookURL string `json:"webhook-url"`
+}
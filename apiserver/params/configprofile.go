@@ -0,0 +1,33 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// ConfigProfile holds a named set of model config values, stored on
+// the controller, that may be applied to models as they are added.
+type ConfigProfile struct {
+	Name   string                 `json:"name"`
+	Config map[string]interface{} `json:"config"`
+}
+
+// ConfigProfiles holds the parameters for the Add and Update calls,
+// and the result of the List call.
+type ConfigProfiles struct {
+	Profiles []ConfigProfile `json:"profiles"`
+}
+
+// ConfigProfileNames holds the parameters for the Get and Remove calls.
+type ConfigProfileNames struct {
+	Names []string `json:"names"`
+}
+
+// ConfigProfileResult holds a config profile or an error.
+type ConfigProfileResult struct {
+	Result *ConfigProfile `json:"result,omitempty"`
+	Error  *Error         `json:"error,omitempty"`
+}
+
+// ConfigProfileResults holds the result of the Get call.
+type ConfigProfileResults struct {
+	Results []ConfigProfileResult `json:"results"`
+}
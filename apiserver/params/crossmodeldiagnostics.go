@@ -0,0 +1,113 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import "time"
+
+// CrossModelRelationDiagnosticsArgs holds the ids of the cross model
+// relations to diagnose.
+type CrossModelRelationDiagnosticsArgs struct {
+	RelationIds []int `json:"relation-ids"`
+}
+
+// CrossModelRelationDiagnostics describes the state of a cross model
+// relation, as seen from each of the models taking part in it.
+type CrossModelRelationDiagnostics struct {
+	RelationId        int    `json:"relation-id"`
+	RelationKey       string `json:"relation-key"`
+	RemoteApplication string `json:"remote-application"`
+
+	// Role is the role of the model holding the relation: "consumer"
+	// if it consumes an offer, or "offerer" if it hosts one.
+	Role string `json:"role"`
+
+	// Local describes the relation in the model holding it.
+	Local RelationSideDiagnostics `json:"local"`
+
+	// Remote describes the relation in the other model, when that
+	// model is hosted by the same controller. Otherwise
+	// RemoteUnavailable says why it is not reported.
+	Remote            *RelationSideDiagnostics `json:"remote,omitempty"`
+	RemoteUnavailable string                   `json:"remote-unavailable,omitempty"`
+
+	// Probe holds the result of connecting to the offering
+	// controller.
+	Probe ConnectivityProbeResult `json:"probe"`
+}
+
+// RelationSideDiagnostics describes a cross model relation in one of
+// the models taking part in it.
+type RelationSideDiagnostics struct {
+	ModelTag string `json:"model-tag"`
+
+	// Macaroon describes the macaroon used to authorise changes to
+	// the relation, if one is held.
+	Macaroon *MacaroonDiagnostics `json:"macaroon,omitempty"`
+
+	// Ingress and Egress hold the networks last recorded for the
+	// relation, if any.
+	Ingress *RelationNetworksDiagnostics `json:"ingress,omitempty"`
+	Egress  *RelationNetworksDiagnostics `json:"egress,omitempty"`
+
+	// Firewall describes the model's firewall rule for connections to
+	// application offers.
+	Firewall FirewallDiagnostics `json:"firewall"`
+}
+
+// MacaroonDiagnostics describes a macaroon held for a cross model
+// relation.
+type MacaroonDiagnostics struct {
+	Id       string     `json:"id"`
+	Location string     `json:"location"`
+	Expiry   *time.Time `json:"expiry,omitempty"`
+	Expired  bool       `json:"expired"`
+}
+
+// RelationNetworksDiagnostics holds the ingress or egress networks of
+// a cross model relation.
+type RelationNetworksDiagnostics struct {
+	CIDRs   []string   `json:"cidrs"`
+	Updated *time.Time `json:"updated,omitempty"`
+}
+
+// FirewallDiagnostics describes how a model's firewall rule for
+// application offers applies to a cross model relation.
+type FirewallDiagnostics struct {
+	// WhitelistCIDRs holds the networks allowed to connect to
+	// application offers. It is empty if any network may connect.
+	WhitelistCIDRs []string `json:"whitelist-cidrs,omitempty"`
+
+	// Blocked holds the relation's ingress networks that are not
+	// in the whitelist.
+	Blocked []string `json:"blocked,omitempty"`
+}
+
+// ConnectivityProbeResult holds the result of connecting to the
+// controller hosting an offer.
+type ConnectivityProbeResult struct {
+	// Probed reports whether a connection was attempted. If not,
+	// Note says why.
+	Probed bool   `json:"probed"`
+	Note   string `json:"note,omitempty"`
+
+	// Address is the address connected to, and Latency the time
+	// taken to connect. If no address could be reached, Error holds
+	// the reason.
+	Address string        `json:"address,omitempty"`
+	Latency time.Duration `json:"latency,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// CrossModelRelationDiagnosticsResult holds the diagnostics of a cross
+// model relation or an error.
+type CrossModelRelationDiagnosticsResult struct {
+	Result *CrossModelRelationDiagnostics `json:"result,omitempty"`
+	Error  *Error                         `json:"error,omitempty"`
+}
+
+// CrossModelRelationDiagnosticsResults holds the results of a
+// RelationDiagnostics call.
+type CrossModelRelationDiagnosticsResults struct {
+	Results []CrossModelRelationDiagnosticsResult `json:"results"`
+}
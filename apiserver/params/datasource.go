@@ -0,0 +1,67 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import "time"
+
+// DataSourceResponse holds a page of the entities served by the
+// read-only "/model/:modeluuid/data/:kind" HTTP endpoint, which is
+// intended for infrastructure tooling such as Terraform data sources.
+// Only the field corresponding to the requested kind is set.
+type DataSourceResponse struct {
+	Model        *DataSourceModel        `json:"model,omitempty"`
+	Applications []DataSourceApplication `json:"applications,omitempty"`
+	Offers       []DataSourceOffer       `json:"offers,omitempty"`
+
+	// Next holds the cursor to pass as the "after" query parameter
+	// to fetch the following page. It is empty on the last page.
+	Next string `json:"next,omitempty"`
+}
+
+// DataSourceModel describes a model. Its ID is the model UUID.
+type DataSourceModel struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Owner           string `json:"owner"`
+	Type            string `json:"type"`
+	Cloud           string `json:"cloud"`
+	CloudRegion     string `json:"cloud-region,omitempty"`
+	CloudCredential string `json:"cloud-credential,omitempty"`
+	Life            string `json:"life"`
+}
+
+// DataSourceApplication describes an application. Its ID is the model
+// UUID and application name joined by a colon, which never changes for
+// the lifetime of the application.
+type DataSourceApplication struct {
+	ID            string `json:"id"`
+	ModelUUID     string `json:"model-uuid"`
+	Name          string `json:"name"`
+	Charm         string `json:"charm"`
+	CharmRevision int    `json:"charm-revision"`
+	Channel       string `json:"channel,omitempty"`
+	Series        string `json:"series"`
+	Exposed       bool   `json:"exposed"`
+	Units         int    `json:"units"`
+	Life          string `json:"life"`
+}
+
+// DataSourceOffer describes an application offer. Its ID is the offer
+// UUID.
+type DataSourceOffer struct {
+	ID              string   `json:"id"`
+	ModelUUID       string   `json:"model-uuid"`
+	Name            string   `json:"name"`
+	URL             string   `json:"url"`
+	ApplicationName string   `json:"application-name"`
+	Endpoints       []string `json:"endpoints"`
+}
+
+// DataSourceToken holds a token, issued by the
+// "/model/:modeluuid/data-token" HTTP endpoint, that lets its bearer
+// read the model's data source endpoint until it expires.
+type DataSourceToken struct {
+	Token   string    `json:"token"`
+	Expires time.Time `json:"expires"`
+}
@@ -0,0 +1,56 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import "time"
+
+// InjectFaultArg holds the details of a fault to inject into a model.
+type InjectFaultArg struct {
+	// ModelTag identifies the model to inject the fault into.
+	ModelTag string `json:"model-tag"`
+
+	// Kind is the kind of fault, eg "provider-api".
+	Kind string `json:"kind"`
+
+	// Duration is how long the fault stays in effect.
+	Duration time.Duration `json:"duration"`
+
+	// Delay is how long each transaction is held up by a
+	// "slow-transactions" fault. It is ignored for other kinds.
+	Delay time.Duration `json:"delay,omitempty"`
+}
+
+// InjectFaultArgs holds the faults to inject.
+type InjectFaultArgs struct {
+	Faults []InjectFaultArg `json:"faults"`
+}
+
+// ClearFaultArg identifies a fault to clear from a model.
+type ClearFaultArg struct {
+	ModelTag string `json:"model-tag"`
+	Kind     string `json:"kind"`
+}
+
+// ClearFaultArgs holds the faults to clear.
+type ClearFaultArgs struct {
+	Faults []ClearFaultArg `json:"faults"`
+}
+
+// Fault describes a fault in effect in a model.
+type Fault struct {
+	Kind    string        `json:"kind"`
+	Expires time.Time     `json:"expires"`
+	Delay   time.Duration `json:"delay,omitempty"`
+}
+
+// FaultsResult holds the faults in effect in a model, or an error.
+type FaultsResult struct {
+	Faults []Fault `json:"faults,omitempty"`
+	Error  *Error  `json:"error,omitempty"`
+}
+
+// FaultsResults holds the results of a bulk Faults call.
+type FaultsResults struct {
+	Results []FaultsResult `json:"results"`
+}
@@ -60,3 +60,14 @@ func (v KnownServiceValue) Validate() error {
 	}
 	return errors.NotValidf("known service %q", v)
 }
+
+// ControllerIngressRulesResult holds the rules governing ingress to the
+// instances of a controller.
+type ControllerIngressRulesResult struct {
+	// ControllerUUID is the UUID of the controller.
+	ControllerUUID string `json:"controller-uuid"`
+
+	// Rules holds the ingress rules; traffic not matched by a rule
+	// should be refused.
+	Rules []IngressRule `json:"rules"`
+}
@@ -72,3 +72,36 @@ type InstanceType struct {
 	Deprecated   bool     `json:"deprecated,omitempty"`
 	Cost         int      `json:"cost,omitempty"`
 }
+
+// InstanceMetadataResults contains the bulk result of prompting a cloud
+// for the metadata of the instances of machines.
+type InstanceMetadataResults struct {
+	Results []InstanceMetadataResult `json:"results"`
+}
+
+// InstanceMetadataResult contains the provider-specific metadata of the
+// instance of a machine.
+type InstanceMetadataResult struct {
+	Result *InstanceMetadata `json:"result,omitempty"`
+	Error  *Error            `json:"error,omitempty"`
+}
+
+// InstanceMetadata holds provider-specific details of an instance. See
+// also environs.InstanceMetadata, from which this is transformed.
+type InstanceMetadata struct {
+	InstanceId        string                     `json:"instance-id"`
+	InstanceProfile   string                     `json:"instance-profile,omitempty"`
+	ImageId           string                     `json:"image-id,omitempty"`
+	Tenancy           string                     `json:"tenancy,omitempty"`
+	NetworkInterfaces []InstanceNetworkInterface `json:"network-interfaces,omitempty"`
+}
+
+// InstanceNetworkInterface describes a network interface attached to an
+// instance, as reported by the provider.
+type InstanceNetworkInterface struct {
+	ProviderId       string    `json:"provider-id"`
+	DeviceIndex      int       `json:"device-index"`
+	MACAddress       string    `json:"mac-address,omitempty"`
+	ProviderSubnetId string    `json:"provider-subnet-id,omitempty"`
+	Addresses        []Address `json:"addresses,omitempty"`
+}
@@ -128,6 +128,15 @@ type ModelCreateArgs struct {
 	// and the owner is the controller owner, the same credential
 	// used for the controller model will be used.
 	CloudCredentialTag string `json:"credential,omitempty"`
+
+	// Profile is the name of a config profile stored on the
+	// controller, whose values are applied to the new model unless
+	// overridden by Config.
+	Profile string `json:"profile,omitempty"`
+
+	// EphemeralExpiry, if not nil, is the time after which the model
+	// is destroyed along with its storage.
+	EphemeralExpiry *time.Time `json:"ephemeral-expiry,omitempty"`
 }
 
 // Model holds the result of an API call returning a name and UUID
@@ -417,6 +426,10 @@ type EntityStatusArgs struct {
 	Status string                 `json:"status"`
 	Info   string                 `json:"info"`
 	Data   map[string]interface{} `json:"data"`
+
+	// Since, if set, holds when the status was reached, for statuses
+	// that are reported some time after they change.
+	Since *time.Time `json:"since,omitempty"`
 }
 
 // SetStatus holds the parameters for making a SetStatus/UpdateStatus call.
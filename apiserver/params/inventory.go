@@ -0,0 +1,113 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// InventorySchemaVersion is the version of the schema of the inventory
+// document returned by the Inventory facade. It is incremented only
+// when a change to the schema could break existing consumers.
+const InventorySchemaVersion = 1
+
+// InventoryArgs holds the arguments for exporting the controller's
+// inventory.
+type InventoryArgs struct {
+	// SinceGeneration, if non-zero, restricts the export to the
+	// entities added, changed or removed after that generation.
+	SinceGeneration int64 `json:"since-generation,omitempty"`
+}
+
+// Inventory holds the inventory of a controller: its models, and their
+// machines, applications and storage.
+type Inventory struct {
+	// SchemaVersion is the version of the inventory schema.
+	SchemaVersion int `json:"schema-version"`
+
+	// ControllerUUID is the UUID of the controller.
+	ControllerUUID string `json:"controller-uuid"`
+
+	// Generation is the inventory generation at the time of the
+	// export, which may be passed as the SinceGeneration of the next
+	// export.
+	Generation int64 `json:"generation"`
+
+	// SinceGeneration is the generation that the export is
+	// relative to, or zero if the export is complete.
+	SinceGeneration int64 `json:"since-generation,omitempty"`
+
+	// Models holds the models in the inventory. In an incremental
+	// export, a model is included if it or any of its machines,
+	// applications or storage has changed, and holds only the
+	// entities that have changed.
+	Models []InventoryModel `json:"models"`
+
+	// Removed holds, in an incremental export, the entities that
+	// have been removed since SinceGeneration.
+	Removed []InventoryRemoval `json:"removed,omitempty"`
+}
+
+// InventoryModel holds the inventory of a model.
+type InventoryModel struct {
+	UUID        string `json:"uuid"`
+	Name        string `json:"name"`
+	Owner       string `json:"owner-tag"`
+	Type        string `json:"type"`
+	Cloud       string `json:"cloud"`
+	CloudRegion string `json:"cloud-region,omitempty"`
+	Life        string `json:"life"`
+
+	// Generation is the inventory generation at which the model
+	// was added or last changed.
+	Generation int64 `json:"generation"`
+
+	Machines     []InventoryMachine     `json:"machines,omitempty"`
+	Applications []InventoryApplication `json:"applications,omitempty"`
+	Storage      []InventoryStorage     `json:"storage,omitempty"`
+}
+
+// InventoryMachine holds the inventory of a machine.
+type InventoryMachine struct {
+	Id         string           `json:"id"`
+	Life       string           `json:"life"`
+	Series     string           `json:"series"`
+	InstanceId string           `json:"instance-id,omitempty"`
+	Hardware   *MachineHardware `json:"hardware,omitempty"`
+	Addresses  []Address        `json:"addresses,omitempty"`
+	Generation int64            `json:"generation"`
+}
+
+// InventoryApplication holds the inventory of an application.
+type InventoryApplication struct {
+	Name          string   `json:"name"`
+	Life          string   `json:"life"`
+	Series        string   `json:"series"`
+	CharmURL      string   `json:"charm-url"`
+	CharmRevision int      `json:"charm-revision"`
+	Units         []string `json:"units,omitempty"`
+	Generation    int64    `json:"generation"`
+}
+
+// InventoryStorage holds the inventory of a storage instance.
+type InventoryStorage struct {
+	Id         string `json:"id"`
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	Pool       string `json:"pool,omitempty"`
+	Owner      string `json:"owner-tag,omitempty"`
+	Life       string `json:"life"`
+	Generation int64  `json:"generation"`
+}
+
+// InventoryRemoval identifies an entity that has been removed from the
+// inventory.
+type InventoryRemoval struct {
+	// ModelUUID is the UUID of the model that held the entity, or
+	// the removed model.
+	ModelUUID string `json:"model-uuid"`
+
+	// Tag is the tag of the removed entity.
+	Tag string `json:"tag"`
+
+	// Generation is the inventory generation at which the entity
+	// was removed.
+	Generation int64 `json:"generation"`
+}
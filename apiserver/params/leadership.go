@@ -3,6 +3,8 @@
 
 package params
 
+import "time"
+
 // ClaimLeadershipBulkParams is a collection of parameters for making
 // a bulk leadership claim.
 type ClaimLeadershipBulkParams struct {
@@ -26,6 +28,19 @@ type ClaimLeadershipParams struct {
 	DurationSeconds float64 `json:"duration"`
 }
 
+// LeadershipLeaseSettings holds the controller's configuration for the
+// leadership leases claimed by units.
+type LeadershipLeaseSettings struct {
+
+	// LeaseDurationSeconds is the number of seconds for which a unit
+	// should claim leadership.
+	LeaseDurationSeconds float64 `json:"lease-duration"`
+
+	// RenewalIntervalSeconds is the number of seconds a leader should
+	// wait before renewing its claim.
+	RenewalIntervalSeconds float64 `json:"renewal-interval"`
+}
+
 // ClaimLeadershipBulkResults is the collection of results from a bulk
 // leadership claim.
 type ClaimLeadershipBulkResults ErrorResults
@@ -84,3 +99,40 @@ type MergeLeadershipSettingsParam struct {
 	// Settings are the Leadership settings you wish to merge in.
 	Settings Settings `json:"settings"`
 }
+
+// ApplicationLeadershipResults holds the leadership details of every
+// application in a model that currently has a leader.
+type ApplicationLeadershipResults struct {
+	// LeaseDuration is the configured leadership lease duration, in
+	// seconds.
+	LeaseDuration float64 `json:"lease-duration"`
+
+	// RenewalInterval is the configured leadership renewal interval,
+	// in seconds.
+	RenewalInterval float64 `json:"renewal-interval"`
+
+	// Results holds the leadership details of each application.
+	Results []ApplicationLeadershipResult `json:"results"`
+}
+
+// ApplicationLeadershipResult holds the leadership details of a single
+// application.
+type ApplicationLeadershipResult struct {
+	// ApplicationTag is the application whose leadership is described.
+	ApplicationTag string `json:"application-tag"`
+
+	// Leader is the name of the unit currently holding leadership.
+	Leader string `json:"leader"`
+
+	// Expiry is the latest time at which the leadership lease might
+	// still be valid if it is not renewed.
+	Expiry time.Time `json:"expiry"`
+
+	// RecentChanges is the number of times leadership of the
+	// application changed hands within the flapping window.
+	RecentChanges int `json:"recent-changes"`
+
+	// Error holds any error encountered reading the leadership
+	// history of the application.
+	Error *Error `json:"error,omitempty"`
+}
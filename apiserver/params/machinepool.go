@@ -0,0 +1,23 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// PoolMachine describes a machine in a model's machine pool.
+type PoolMachine struct {
+	// MachineTag is the tag of the machine.
+	MachineTag string `json:"machine-tag"`
+
+	// Status is "available" if units may be assigned to the machine,
+	// or "allocated" if it hosts a unit assigned from the pool.
+	Status string `json:"status"`
+
+	// UnitTag is the tag of the unit the machine is allocated to, if
+	// any.
+	UnitTag string `json:"unit-tag,omitempty"`
+}
+
+// MachinePoolResult holds the machines in a model's machine pool.
+type MachinePoolResult struct {
+	Machines []PoolMachine `json:"machines"`
+}
@@ -10,7 +10,9 @@ import (
 )
 
 // ConfigValue encapsulates a configuration
-// value and its source.
+// value and its source. The source is one of the
+// labels defined in environs/config, eg "default",
+// "controller", "region" or "model".
 type ConfigValue struct {
 	Value  interface{} `json:"value"`
 	Source string      `json:"source"`
@@ -22,6 +24,48 @@ type ModelConfigResults struct {
 	Config map[string]ConfigValue `json:"config"`
 }
 
+// ConfigSchemaField documents a model config field.
+type ConfigSchemaField struct {
+	Description string        `json:"description"`
+	Type        string        `json:"type"`
+	Group       string        `json:"group,omitempty"`
+	Immutable   bool          `json:"immutable,omitempty"`
+	Example     interface{}   `json:"example,omitempty"`
+	Values      []interface{} `json:"values,omitempty"`
+	Deprecated  string        `json:"deprecated,omitempty"`
+}
+
+// ConfigSchemaResult contains the result of the ModelConfig facade's
+// ConfigSchema call, keyed on config field name.
+type ConfigSchemaResult struct {
+	Fields map[string]ConfigSchemaField `json:"fields"`
+}
+
+// ModelConfigHistoryArgs holds the arguments of the ModelConfig
+// facade's ModelConfigHistory call.
+type ModelConfigHistoryArgs struct {
+	// Key, if not empty, restricts the history to changes of the
+	// named attribute.
+	Key string `json:"key,omitempty"`
+}
+
+// ModelConfigChange records a change to a model config attribute.
+// The values of secret attributes are omitted, and Redacted set.
+type ModelConfigChange struct {
+	Time     time.Time   `json:"time"`
+	User     string      `json:"user,omitempty"`
+	Key      string      `json:"key"`
+	Old      interface{} `json:"old,omitempty"`
+	New      interface{} `json:"new,omitempty"`
+	Redacted bool        `json:"redacted,omitempty"`
+}
+
+// ModelConfigHistoryResult contains the result of the ModelConfig
+// facade's ModelConfigHistory call, most recent change first.
+type ModelConfigHistoryResult struct {
+	Changes []ModelConfigChange `json:"changes"`
+}
+
 // HostedModelConfig contains the model config and the cloud spec
 // for the model, both things that a client needs to talk directly
 // with the provider. This is used to take down mis-behaving models
@@ -66,6 +110,15 @@ type ModelSet struct {
 	Config map[string]interface{} `json:"config"`
 }
 
+// ModelSetResult holds the result of the ModelConfig facade's ModelSet
+// call.
+type ModelSetResult struct {
+	Error *Error `json:"error,omitempty"`
+
+	// Warnings holds a warning for each deprecated attribute set.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
 // ModelUnset contains the arguments for ModelUnset client API
 // call.
 type ModelUnset struct {
@@ -136,6 +189,14 @@ type ModelInfo struct {
 	// OwnerTag is the tag of the user that owns the model.
 	OwnerTag string `json:"owner-tag"`
 
+	// OwnerTeam is the name of the team that owns the model, if
+	// the model has been transferred to a team.
+	OwnerTeam string `json:"owner-team,omitempty"`
+
+	// OwnershipTransfers records the changes of the model's owner,
+	// oldest first. It is only reported to the model's admins.
+	OwnershipTransfers []ModelOwnershipTransfer `json:"ownership-transfers,omitempty"`
+
 	// Life is the current lifecycle state of the model.
 	Life Life `json:"life"`
 
@@ -342,6 +403,52 @@ type ModifyModelAccess struct {
 	ModelTag string               `json:"model-tag"`
 }
 
+// TransferModelOwnershipRequest holds the models whose ownership is to
+// be transferred.
+type TransferModelOwnershipRequest struct {
+	Transfers []TransferModelOwnership `json:"transfers"`
+}
+
+// TransferModelOwnership holds the new owner of a model: either the
+// user with OwnerTag, or the team with the name Team.
+type TransferModelOwnership struct {
+	ModelTag string `json:"model-tag"`
+	OwnerTag string `json:"owner-tag,omitempty"`
+	Team     string `json:"team,omitempty"`
+}
+
+// ModelOwnershipTransfer records a change of a model's owner. The team
+// fields are empty unless the model was owned by, or transferred to, a
+// team.
+type ModelOwnershipTransfer struct {
+	Time     time.Time `json:"time"`
+	ByTag    string    `json:"by-tag"`
+	FromTag  string    `json:"from-tag"`
+	FromTeam string    `json:"from-team,omitempty"`
+	ToTag    string    `json:"to-tag"`
+	ToTeam   string    `json:"to-team,omitempty"`
+}
+
+// SetModelExpiryArgs holds the models whose ephemeral expiry is to be
+// changed.
+type SetModelExpiryArgs struct {
+	Models []ModelExpiry `json:"models"`
+}
+
+// ModelExpiry holds the time after which a model is destroyed. A nil
+// Expiry makes the model persistent.
+type ModelExpiry struct {
+	ModelTag string     `json:"model-tag"`
+	Expiry   *time.Time `json:"expiry,omitempty"`
+}
+
+// ModelExpiryResult holds the time after which a model is destroyed,
+// which is nil if the model is persistent, or an error.
+type ModelExpiryResult struct {
+	Expiry *time.Time `json:"expiry,omitempty"`
+	Error  *Error     `json:"error,omitempty"`
+}
+
 // ModelAction is an action that can be performed on a model.
 type ModelAction string
 
@@ -247,6 +247,32 @@ func (pr PortRange) NetworkPortRange() network.PortRange {
 	}
 }
 
+// IngressRule represents a rule allowing ingress to a range of ports
+// from the given address blocks. See also network.IngressRule, from/to
+// which this is transformed.
+type IngressRule struct {
+	PortRange   PortRange `json:"port-range"`
+	SourceCIDRs []string  `json:"source-cidrs,omitempty"`
+}
+
+// FromNetworkIngressRule is a convenience helper to create a parameter
+// out of the network type, here for IngressRule.
+func FromNetworkIngressRule(rule network.IngressRule) IngressRule {
+	return IngressRule{
+		PortRange:   FromNetworkPortRange(rule.PortRange),
+		SourceCIDRs: rule.SourceCIDRs,
+	}
+}
+
+// NetworkIngressRule is a convenience helper to return the parameter
+// as network type, here for IngressRule.
+func (rule IngressRule) NetworkIngressRule() network.IngressRule {
+	return network.IngressRule{
+		PortRange:   rule.PortRange.NetworkPortRange(),
+		SourceCIDRs: rule.SourceCIDRs,
+	}
+}
+
 // EntityPort holds an entity's tag, a protocol and a port.
 type EntityPort struct {
 	Tag      string `json:"tag"`
@@ -266,6 +292,10 @@ type EntityPortRange struct {
 	Protocol string `json:"protocol"`
 	FromPort int    `json:"from-port"`
 	ToPort   int    `json:"to-port"`
+
+	// Endpoint is the name of the endpoint the port range is opened
+	// or closed for. It is empty for all the unit's endpoints.
+	Endpoint string `json:"endpoint,omitempty"`
 }
 
 // EntitiesPortRanges holds the parameters for making an OpenPorts or
@@ -433,13 +463,36 @@ type MachineAddressesResults struct {
 }
 
 // MachinePortRange holds a single port range open on a machine for
-// the given unit and relation tags.
+// the given unit and relation tags, and the charm endpoint it was
+// opened for, if any.
 type MachinePortRange struct {
 	UnitTag     string    `json:"unit-tag"`
 	RelationTag string    `json:"relation-tag"`
+	Endpoint    string    `json:"endpoint,omitempty"`
 	PortRange   PortRange `json:"port-range"`
 }
 
+// ExposeInfoResults holds the results of calling an API method
+// returning the expose details of applications.
+type ExposeInfoResults struct {
+	Results []ExposeInfoResult `json:"results"`
+}
+
+// ExposeInfoResult holds the expose details of an application, or an
+// error.
+type ExposeInfoResult struct {
+	// Exposed reports whether the application is exposed.
+	Exposed bool `json:"exposed,omitempty"`
+
+	// ExposedEndpointCIDRs holds, keyed on endpoint name, the CIDRs
+	// that may access the ports opened for each exposed endpoint.
+	// The CIDRs for the empty endpoint name apply to all endpoints
+	// without CIDRs of their own.
+	ExposedEndpointCIDRs map[string][]string `json:"exposed-endpoint-cidrs,omitempty"`
+
+	Error *Error `json:"error,omitempty"`
+}
+
 // MachinePorts holds a machine and subnet tags. It's used when referring to
 // opened ports on the machine for a subnet.
 type MachinePorts struct {
@@ -658,6 +711,7 @@ type ProxyConfig struct {
 type ProxyConfigResult struct {
 	ProxySettings    ProxyConfig `json:"proxy-settings"`
 	APTProxySettings ProxyConfig `json:"apt-proxy-settings"`
+	CACertificates   []string    `json:"ca-certificates,omitempty"`
 	Error            *Error      `json:"error,omitempty"`
 }
 
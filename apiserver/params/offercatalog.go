@@ -0,0 +1,43 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import "time"
+
+// OfferCatalogEntry describes an application offer registered with a
+// controller's offer catalog by the controller hosting it.
+type OfferCatalogEntry struct {
+	ControllerTag          string           `json:"controller-tag,omitempty"`
+	OfferURL               string           `json:"offer-url"`
+	OfferName              string           `json:"offer-name"`
+	ApplicationDescription string           `json:"application-description,omitempty"`
+	Endpoints              []RemoteEndpoint `json:"endpoints"`
+}
+
+// OfferCatalogRegistration holds the offers of a single controller,
+// registered with another controller's offer catalog.
+type OfferCatalogRegistration struct {
+	ControllerTag  string              `json:"controller-tag"`
+	ControllerName string              `json:"controller-name"`
+	Offers         []OfferCatalogEntry `json:"offers"`
+	Updated        *time.Time          `json:"updated,omitempty"`
+}
+
+// OfferCatalogRegistrations holds the parameters for the Register
+// call, and the result of the List call.
+type OfferCatalogRegistrations struct {
+	Registrations []OfferCatalogRegistration `json:"registrations"`
+}
+
+// OfferCatalogFilter holds the parameters for the Find call. Empty
+// fields match all offers.
+type OfferCatalogFilter struct {
+	Interface string `json:"interface,omitempty"`
+	OfferName string `json:"offer-name,omitempty"`
+}
+
+// OfferCatalogEntries holds the result of the Find call.
+type OfferCatalogEntries struct {
+	Offers []OfferCatalogEntry `json:"offers"`
+}
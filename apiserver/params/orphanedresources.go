@@ -0,0 +1,23 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// OrphanedResource describes a cloud resource that is tagged as
+// belonging to a model, but which the model no longer tracks.
+type OrphanedResource struct {
+	// Kind is the kind of resource, eg "instance" or "volume".
+	Kind string `json:"kind"`
+
+	// ID is the provider-specific ID of the resource.
+	ID string `json:"id"`
+
+	// Tags holds the resource's tags.
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// OrphanedResourcesResult holds the orphaned resources found in a
+// model's cloud.
+type OrphanedResourcesResult struct {
+	Resources []OrphanedResource `json:"resources"`
+}
@@ -0,0 +1,27 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import "time"
+
+// PackageUpdates describes the package updates pending on a machine.
+type PackageUpdates struct {
+	Tag            string    `json:"tag"`
+	Pending        int       `json:"pending"`
+	Security       int       `json:"security"`
+	RebootRequired bool      `json:"reboot-required"`
+	Updated        time.Time `json:"updated"`
+}
+
+// SetPackageUpdates holds the package updates to record for one or
+// more machines.
+type SetPackageUpdates struct {
+	Updates []PackageUpdates `json:"updates"`
+}
+
+// PackageUpdatesResults holds the package updates last reported by the
+// machines of a model.
+type PackageUpdatesResults struct {
+	Results []PackageUpdates `json:"results"`
+}
@@ -145,6 +145,20 @@ type RelationStatusArg struct {
 	Message    string              `json:"message"`
 }
 
+// RelationDepartureBarrierArgs holds the parameters for setting the
+// departure barriers of one or more relations.
+type RelationDepartureBarrierArgs struct {
+	Args []RelationDepartureBarrierArg `json:"args"`
+}
+
+// RelationDepartureBarrierArg holds the timeout of a relation's
+// departure barrier, set by the given unit.
+type RelationDepartureBarrierArg struct {
+	Relation string        `json:"relation"`
+	Unit     string        `json:"unit"`
+	Timeout  time.Duration `json:"timeout"`
+}
+
 // RelationSuspendedArgs holds the parameters for setting
 // the suspended status of one or more relations.
 type RelationSuspendedArgs struct {
@@ -338,6 +352,19 @@ type ApplicationSetCharm struct {
 // ApplicationExpose holds the parameters for making the application Expose call.
 type ApplicationExpose struct {
 	ApplicationName string `json:"application"`
+
+	// ExposedEndpoints holds the expose settings to merge into the
+	// application's existing settings, keyed on endpoint name. The
+	// settings for the empty endpoint name apply to all endpoints
+	// without settings of their own. If empty, all such endpoints
+	// are exposed to 0.0.0.0/0.
+	ExposedEndpoints map[string]ExposedEndpoint `json:"exposed-endpoints,omitempty"`
+}
+
+// ExposedEndpoint holds the expose settings of an application endpoint.
+type ExposedEndpoint struct {
+	ExposeToSpaces []string `json:"expose-to-spaces,omitempty"`
+	ExposeToCIDRs  []string `json:"expose-to-cidrs,omitempty"`
 }
 
 // ApplicationSet holds the parameters for an application Set
@@ -383,6 +410,11 @@ type ApplicationCharmRelationsResults struct {
 // ApplicationUnexpose holds parameters for the application Unexpose call.
 type ApplicationUnexpose struct {
 	ApplicationName string `json:"application"`
+
+	// ExposedEndpoints holds the names of the endpoints whose expose
+	// settings should be removed. If empty, the application is
+	// unexposed entirely.
+	ExposedEndpoints []string `json:"exposed-endpoints,omitempty"`
 }
 
 // ApplicationMetricCredential holds parameters for the SetApplicationCredentials call.
@@ -496,6 +528,12 @@ type DestroyApplicationParams struct {
 	// DestroyStorage controls whether or not storage attached to
 	// units of the application should be destroyed.
 	DestroyStorage bool `json:"destroy-storage,omitempty"`
+
+	// RetainStorage controls whether or not storage attached to
+	// units of the application must be retained. If it is set, the
+	// application is only destroyed if all of its storage can be
+	// detached, and the storage is marked as retained from it.
+	RetainStorage bool `json:"retain-storage,omitempty"`
 }
 
 // DestroyConsumedApplicationsParams holds bulk parameters for the
@@ -583,6 +621,35 @@ type SetConstraints struct {
 	Constraints     constraints.Value `json:"constraints"`
 }
 
+// ApplicationAffinity holds the affinity policy of an application.
+// Violations holds, by unit name, the ways in which the placement of
+// the application's units breaks affinity policy; it is ignored by
+// SetAffinity.
+type ApplicationAffinity struct {
+	ApplicationTag string              `json:"application-tag"`
+	NotWith        []string            `json:"not-with,omitempty"`
+	SameZoneAs     []string            `json:"same-zone-as,omitempty"`
+	Violations     map[string][]string `json:"violations,omitempty"`
+}
+
+// SetApplicationsAffinity holds the parameters for making the
+// SetAffinity call.
+type SetApplicationsAffinity struct {
+	Args []ApplicationAffinity `json:"args"`
+}
+
+// ApplicationAffinityResult holds the affinity policy of a single
+// application, or an error for trying to get it.
+type ApplicationAffinityResult struct {
+	Result *ApplicationAffinity `json:"result,omitempty"`
+	Error  *Error               `json:"error,omitempty"`
+}
+
+// ApplicationAffinityResults holds the results of the GetAffinity call.
+type ApplicationAffinityResults struct {
+	Results []ApplicationAffinityResult `json:"results"`
+}
+
 // ResolveCharms stores charm references for a ResolveCharms call.
 type ResolveCharms struct {
 	References []string `json:"references"`
@@ -675,6 +742,8 @@ type ContainerConfig struct {
 	AptProxy                proxy.Settings `json:"apt-proxy"`
 	AptMirror               string         `json:"apt-mirror"`
 	*UpdateBehavior
+	CloudInitUserData map[string]interface{} `json:"cloudinit-userdata,omitempty"`
+	CACertificates    []string               `json:"ca-certificates,omitempty"`
 }
 
 // ProvisioningScriptParams contains the parameters for the
@@ -0,0 +1,54 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// RelationDataArgs holds the ids of the relations whose data is
+// requested.
+type RelationDataArgs struct {
+	RelationIds []int `json:"relation-ids"`
+
+	// Reveal requests that settings whose names suggest they hold
+	// secrets are returned rather than redacted. It requires admin
+	// access to the model.
+	Reveal bool `json:"reveal,omitempty"`
+}
+
+// RelationData holds the data bags of a relation.
+type RelationData struct {
+	Id        int                    `json:"id"`
+	Key       string                 `json:"key"`
+	Endpoints []RelationEndpointData `json:"endpoints"`
+}
+
+// RelationEndpointData holds the data bags of the units of one of the
+// applications in a relation.
+type RelationEndpointData struct {
+	ApplicationName string `json:"application-name"`
+	Name            string `json:"name"`
+	Role            string `json:"role"`
+
+	// Units holds the data bags of the units in the relation's
+	// scope, keyed on unit name.
+	Units map[string]RelationUnitData `json:"units,omitempty"`
+}
+
+// RelationUnitData holds the settings of a unit within a relation,
+// possibly with secrets redacted.
+type RelationUnitData struct {
+	Settings map[string]interface{} `json:"settings"`
+
+	// Redacted lists the names of the settings that were redacted.
+	Redacted []string `json:"redacted,omitempty"`
+}
+
+// RelationDataResult holds the data of a relation or an error.
+type RelationDataResult struct {
+	Result *RelationData `json:"result,omitempty"`
+	Error  *Error        `json:"error,omitempty"`
+}
+
+// RelationDataResults holds the results of a RelationData call.
+type RelationDataResults struct {
+	Results []RelationDataResult `json:"results"`
+}
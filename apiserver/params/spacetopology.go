@@ -0,0 +1,37 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// SpaceTopology describes the network topology of a model as a graph,
+// for dashboards to render. Its nodes are the model's spaces, subnets,
+// machines and applications. Its edges join each space to the subnets
+// it contains, each machine to the subnets it has addresses on, each
+// application to the spaces its endpoints are bound to, and each
+// application to the machines its units are assigned to.
+type SpaceTopology struct {
+	Nodes []TopologyNode `json:"nodes"`
+	Edges []TopologyEdge `json:"edges"`
+}
+
+// TopologyNode is a node of a SpaceTopology. Id is unique within the
+// topology, and is made of the node's Kind ("space", "subnet",
+// "machine" or "application") and the name, CIDR or id it is known
+// by, which is also its Label, eg "subnet:10.0.0.0/24".
+type TopologyNode struct {
+	Id         string            `json:"id"`
+	Kind       string            `json:"kind"`
+	Label      string            `json:"label"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// TopologyEdge is an edge of a SpaceTopology, joining the nodes with
+// the ids From and To. Its Kind is "contains" (a space and a subnet),
+// "address" (a machine and a subnet), "binding" (an application and a
+// space) or "unit" (an application and a machine).
+type TopologyEdge struct {
+	From       string            `json:"from"`
+	To         string            `json:"to"`
+	Kind       string            `json:"kind"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
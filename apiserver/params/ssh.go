@@ -3,6 +3,8 @@
 
 package params
 
+import "time"
+
 // SSHHostKeySet defines SSH host keys for one or more entities
 // (typically machines).
 type SSHHostKeySet struct {
@@ -20,6 +22,20 @@ type SSHProxyResult struct {
 	UseProxy bool `json:"use-proxy"`
 }
 
+// SSHBastionResult defines the response from the SSHClient.Bastion API.
+// Port is zero if the controller's ssh bastion is disabled.
+type SSHBastionResult struct {
+	Port      int      `json:"port,omitempty"`
+	ModelUUID string   `json:"model-uuid,omitempty"`
+	HostKeys  []string `json:"host-keys,omitempty"`
+}
+
+// SSHTunnelData holds data relayed in either direction over the
+// ssh-tunnel endpoint.
+type SSHTunnelData struct {
+	Data []byte `json:"data"`
+}
+
 // SSHAddressResults defines the response from various APIs on the
 // SSHClient facade.
 type SSHAddressResults struct {
@@ -58,3 +74,22 @@ type SSHPublicKeysResult struct {
 	Error      *Error   `json:"error,omitempty"`
 	PublicKeys []string `json:"public-keys,omitempty"`
 }
+
+// SSHKeyRefreshResult holds the outcome of refreshing a model's
+// authorized keys from their key server sources.
+type SSHKeyRefreshResult struct {
+	// Sources holds the outcome of importing keys from each source.
+	Sources []SSHKeySourceResult `json:"sources"`
+
+	// RefreshAfter is how long to wait before the keys should next be
+	// refreshed.
+	RefreshAfter time.Duration `json:"refresh-after"`
+}
+
+// SSHKeySourceResult holds the outcome of importing keys from a
+// single key server source.
+type SSHKeySourceResult struct {
+	Source string `json:"source"`
+	Keys   int    `json:"keys"`
+	Error  *Error `json:"error,omitempty"`
+}
@@ -30,6 +30,38 @@ type FullStatus struct {
 	Relations          []RelationStatus                   `json:"relations"`
 }
 
+// StatusWatcherId holds the id of a watcher created by the
+// WatchStatus API call.
+type StatusWatcherId struct {
+	StatusWatcherId string `json:"watcher-id"`
+}
+
+// StatusDiff describes a change to one field of the JSON encoding of
+// a model's FullStatus. Arrays are compared as a whole and are never
+// descended into.
+type StatusDiff struct {
+	// Entity holds the keys of the objects leading from the root of
+	// the FullStatus to the object holding the field; for example
+	// ["applications", "mysql", "units", "mysql/0", "workload-status"].
+	Entity []string `json:"entity"`
+
+	// Field is the key of the changed field.
+	Field string `json:"field"`
+
+	// Old holds the field's previous value, and is nil if the field
+	// has been added.
+	Old interface{} `json:"old,omitempty"`
+
+	// New holds the field's current value, and is nil if the field
+	// has been removed.
+	New interface{} `json:"new,omitempty"`
+}
+
+// StatusWatchResult holds the diffs returned by StatusWatcher.Next.
+type StatusWatchResult struct {
+	Diffs []StatusDiff `json:"diffs"`
+}
+
 // ModelStatusInfo holds status information about the model itself.
 type ModelStatusInfo struct {
 	Name             string         `json:"name"`
@@ -101,6 +133,10 @@ type MachineStatus struct {
 	Jobs      []multiwatcher.MachineJob `json:"jobs"`
 	HasVote   bool                      `json:"has-vote"`
 	WantsVote bool                      `json:"wants-vote"`
+
+	// AgentVersionPin holds the range of agent versions, in the form
+	// "MIN..MAX", that the machine's agents are pinned to, if any.
+	AgentVersionPin string `json:"agent-version-pin,omitempty"`
 }
 
 // ApplicationStatus holds status info about an application.
@@ -117,6 +153,11 @@ type ApplicationStatus struct {
 	MeterStatuses   map[string]MeterStatus `json:"meter-statuses"`
 	Status          DetailedStatus         `json:"status"`
 	WorkloadVersion string                 `json:"workload-version"`
+
+	// AgentVersionPin holds the range of agent versions, in the form
+	// "MIN..MAX", that the agents of the machines hosting the
+	// application's units are pinned to, if any.
+	AgentVersionPin string `json:"agent-version-pin,omitempty"`
 }
 
 // RemoteApplicationStatus holds status info about a remote application.
@@ -212,6 +253,27 @@ type StatusHistoryFilter struct {
 	Date    *time.Time     `json:"date"`
 	Delta   *time.Duration `json:"delta"`
 	Exclude []string       `json:"exclude"`
+
+	// IncludeStatus and ExcludeStatus select entries by status value,
+	// and MessageRegex by message.
+	IncludeStatus []string `json:"include-status,omitempty"`
+	ExcludeStatus []string `json:"exclude-status,omitempty"`
+	MessageRegex  string   `json:"message-regex,omitempty"`
+
+	// Squash, if enabled, asks for repeated entries to be squashed
+	// before the history is returned.
+	Squash *StatusHistorySquash `json:"squash,omitempty"`
+}
+
+// StatusHistorySquash holds the parameters for squashing repeated
+// entries in a status history. CycleSizes holds the sizes of the
+// cycles of repeated entries to squash, in the order they are tried,
+// and MaxLength, if positive, limits the squashed history to its most
+// recent entries.
+type StatusHistorySquash struct {
+	Enabled    bool  `json:"enabled"`
+	CycleSizes []int `json:"cycle-sizes,omitempty"`
+	MaxLength  int   `json:"max-length,omitempty"`
 }
 
 // StatusHistoryRequest holds the parameters to filter a status history query.
@@ -220,6 +282,12 @@ type StatusHistoryRequest struct {
 	Size   int                 `json:"size"`
 	Filter StatusHistoryFilter `json:"filter"`
 	Tag    string              `json:"tag"`
+
+	// PageSize, if positive, requests the history a page of at most
+	// this many entries at a time, starting with the most recent.
+	// Token identifies the page to fetch, and is empty for the first.
+	PageSize int    `json:"page-size,omitempty"`
+	Token    string `json:"token,omitempty"`
 }
 
 // StatusHistoryRequests holds a slice of StatusHistoryArgs.
@@ -231,6 +299,11 @@ type StatusHistoryRequests struct {
 type StatusHistoryResult struct {
 	History History `json:"history"`
 	Error   *Error  `json:"error,omitempty"`
+
+	// NextToken identifies the page following this one, when the
+	// history was requested a page at a time and there are more
+	// entries.
+	NextToken string `json:"next-token,omitempty"`
 }
 
 // StatusHistoryResults holds a slice of StatusHistoryResult.
@@ -238,11 +311,30 @@ type StatusHistoryResults struct {
 	Results []StatusHistoryResult `json:"results"`
 }
 
+// StatusHistoryWatchResult holds the id of a watcher created by the
+// WatchStatusHistory API call, and the entries returned by
+// StatusHistoryWatcher.Next.
+type StatusHistoryWatchResult struct {
+	StatusHistoryWatcherId string           `json:"watcher-id,omitempty"`
+	Statuses               []DetailedStatus `json:"statuses,omitempty"`
+	Error                  *Error           `json:"error,omitempty"`
+}
+
+// StatusHistoryWatchResults holds the results of the
+// WatchStatusHistory API call.
+type StatusHistoryWatchResults struct {
+	Results []StatusHistoryWatchResult `json:"results"`
+}
+
 // StatusHistoryPruneArgs holds arguments for status history
 // prunning process.
 type StatusHistoryPruneArgs struct {
 	MaxHistoryTime time.Duration `json:"max-history-time"`
 	MaxHistoryMB   int           `json:"max-history-mb"`
+
+	// MaxEntriesPerEntity is the most entries kept in the status
+	// history of each entity, or 0 for no limit.
+	MaxEntriesPerEntity int `json:"max-entries-per-entity,omitempty"`
 }
 
 // StatusResult holds an entity status, extra information, or an
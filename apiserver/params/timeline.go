@@ -0,0 +1,68 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import "time"
+
+// The kinds of operation recorded in a deploy timeline.
+const (
+	// TimelineProvision covers the provisioning of a machine's
+	// instance, until the instance is running.
+	TimelineProvision = "provision"
+
+	// TimelineAgentStart covers the time from a machine's instance
+	// running to its agent starting.
+	TimelineAgentStart = "agent-start"
+
+	// TimelineHook covers the execution of a hook by a unit.
+	TimelineHook = "hook"
+
+	// TimelineRelation covers the execution of a relation hook by a
+	// unit, through which the unit establishes a relation.
+	TimelineRelation = "relation"
+)
+
+// DeployTimeline holds the operations that deployed the units of an
+// application, ordered by the time they started.
+type DeployTimeline struct {
+	Application string          `json:"application"`
+	Events      []TimelineEvent `json:"events"`
+}
+
+// TimelineEvent describes a single operation in a deploy timeline.
+type TimelineEvent struct {
+	// Entity is the tag of the machine or unit that performed the
+	// operation.
+	Entity string `json:"entity"`
+
+	// Kind is the kind of operation; one of TimelineProvision,
+	// TimelineAgentStart, TimelineHook and TimelineRelation.
+	Kind string `json:"kind"`
+
+	// Name identifies the operation within its kind, such as the
+	// name of a hook.
+	Name string `json:"name,omitempty"`
+
+	// Start is when the operation started.
+	Start time.Time `json:"start"`
+
+	// End is when the operation finished. It is nil if the operation
+	// had not finished when the timeline was generated.
+	End *time.Time `json:"end,omitempty"`
+
+	// Error holds the message reported if the operation failed.
+	Error string `json:"error,omitempty"`
+}
+
+// DeployTimelineResult holds the deploy timeline of an application or
+// an error.
+type DeployTimelineResult struct {
+	Result *DeployTimeline `json:"result,omitempty"`
+	Error  *Error          `json:"error,omitempty"`
+}
+
+// DeployTimelineResults holds the results of a DeployTimeline call.
+type DeployTimelineResults struct {
+	Results []DeployTimelineResult `json:"results"`
+}
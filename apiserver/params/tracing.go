@@ -0,0 +1,30 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import "time"
+
+// TraceSpan describes an operation performed for an entity, such as
+// the execution of a hook or a call to the cloud provider.
+type TraceSpan struct {
+	Kind     string        `json:"kind"`
+	Entity   string        `json:"entity"`
+	Name     string        `json:"name"`
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// TraceSpans holds spans to record, or the slow operation spans
+// recorded for a model.
+type TraceSpans struct {
+	Spans []TraceSpan `json:"spans"`
+}
+
+// SlowSpansArgs restricts the spans returned by Tracing.SlowSpans.
+// Empty fields match all spans.
+type SlowSpansArgs struct {
+	Kind   string `json:"kind,omitempty"`
+	Entity string `json:"entity,omitempty"`
+}
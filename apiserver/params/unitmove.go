@@ -0,0 +1,89 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"time"
+
+	"github.com/juju/juju/instance"
+)
+
+// MoveUnitArg identifies a unit to move, and where to move it to.
+type MoveUnitArg struct {
+	UnitTag string `json:"unit-tag"`
+
+	// Placement chooses the machine to move the unit to. The unit
+	// is moved to a new machine if it is nil.
+	Placement *instance.Placement `json:"placement,omitempty"`
+}
+
+// MoveUnitArgs holds the units to move.
+type MoveUnitArgs struct {
+	Units []MoveUnitArg `json:"units"`
+}
+
+// UnitMove describes the progress of a unit move.
+type UnitMove struct {
+	UnitTag        string    `json:"unit-tag"`
+	ReplacementTag string    `json:"replacement-tag"`
+	Phase          string    `json:"phase"`
+	Message        string    `json:"message"`
+	Started        time.Time `json:"started"`
+	Updated        time.Time `json:"updated"`
+}
+
+// UnitMoveResult holds a unit move, or an error.
+type UnitMoveResult struct {
+	Result *UnitMove `json:"result,omitempty"`
+	Error  *Error    `json:"error,omitempty"`
+}
+
+// UnitMoveResults holds the results of a bulk unit move call.
+type UnitMoveResults struct {
+	Results []UnitMoveResult `json:"results"`
+}
+
+// EvacuateMachineArg identifies a machine to evacuate, and where to
+// move its units to.
+type EvacuateMachineArg struct {
+	MachineTag string `json:"machine-tag"`
+
+	// Placement chooses the machines to move the units to. Units are
+	// moved to new machines if it is nil.
+	Placement *instance.Placement `json:"placement,omitempty"`
+}
+
+// EvacuateMachineArgs holds the machines to evacuate.
+type EvacuateMachineArgs struct {
+	Machines []EvacuateMachineArg `json:"machines"`
+}
+
+// MachineEvacuation describes the progress of a machine evacuation.
+type MachineEvacuation struct {
+	MachineTag string           `json:"machine-tag"`
+	MoveTags   []string         `json:"move-tags,omitempty"`
+	Manual     []EvacuatingUnit `json:"manual,omitempty"`
+	Started    time.Time        `json:"started"`
+	Done       bool             `json:"done"`
+}
+
+// EvacuatingUnit describes a unit that must be moved off an evacuating
+// machine by hand.
+type EvacuatingUnit struct {
+	UnitTag     string   `json:"unit-tag"`
+	StorageTags []string `json:"storage-tags,omitempty"`
+	Reason      string   `json:"reason"`
+}
+
+// MachineEvacuationResult holds a machine evacuation, or an error.
+type MachineEvacuationResult struct {
+	Result *MachineEvacuation `json:"result,omitempty"`
+	Error  *Error             `json:"error,omitempty"`
+}
+
+// MachineEvacuationResults holds the results of a bulk machine
+// evacuation call.
+type MachineEvacuationResults struct {
+	Results []MachineEvacuationResult `json:"results"`
+}
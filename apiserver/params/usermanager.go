@@ -67,3 +67,120 @@ type AddUserResult struct {
 	SecretKey []byte `json:"secret-key,omitempty"`
 	Error     *Error `json:"error,omitempty"`
 }
+
+// TeamNames holds the names of teams.
+type TeamNames struct {
+	Names []string `json:"names"`
+}
+
+// TeamInfo holds information on a team.
+type TeamInfo struct {
+	Name        string    `json:"name"`
+	Members     []string  `json:"members"`
+	CreatedBy   string    `json:"created-by"`
+	DateCreated time.Time `json:"date-created"`
+}
+
+// TeamInfoResults holds the result of a Teams API call.
+type TeamInfoResults struct {
+	Results []TeamInfo `json:"results"`
+}
+
+// TeamAction is an action that can be performed on a team's members.
+type TeamAction string
+
+// Actions that can be performed on a team's members.
+const (
+	AddTeamMember    TeamAction = "add"
+	RemoveTeamMember TeamAction = "remove"
+)
+
+// ModifyTeamMembersRequest holds the changes to make to the members
+// of teams.
+type ModifyTeamMembersRequest struct {
+	Changes []ModifyTeamMember `json:"changes"`
+}
+
+// ModifyTeamMember holds a change to the members of a team.
+type ModifyTeamMember struct {
+	Team    string     `json:"team"`
+	Action  TeamAction `json:"action"`
+	UserTag string     `json:"user-tag"`
+}
+
+// ListSessionsRequest holds the arguments of a ListSessions API call.
+type ListSessionsRequest struct {
+	// UserTags, if not empty, restricts the sessions listed to those
+	// of the given users.
+	UserTags []string `json:"user-tags,omitempty"`
+}
+
+// SessionInfo describes the API session of a logged in user.
+type SessionInfo struct {
+	ID            string         `json:"id"`
+	Server        string         `json:"server"`
+	UserTag       string         `json:"user-tag"`
+	ModelTag      string         `json:"model-tag,omitempty"`
+	RemoteAddress string         `json:"remote-address"`
+	Started       time.Time      `json:"started"`
+	LastActive    time.Time      `json:"last-active"`
+	FacadeCalls   map[string]int `json:"facade-calls,omitempty"`
+}
+
+// SessionInfoResults holds the result of a ListSessions API call.
+type SessionInfoResults struct {
+	Results []SessionInfo `json:"results"`
+
+	// Unreachable holds the IDs of the API servers that did not report
+	// their sessions in time.
+	Unreachable []string `json:"unreachable,omitempty"`
+}
+
+// RevokeSessionsRequest identifies the API sessions to end.
+type RevokeSessionsRequest struct {
+	SessionIDs []string `json:"session-ids,omitempty"`
+
+	// UserTags identifies users all of whose sessions are ended.
+	UserTags []string `json:"user-tags,omitempty"`
+}
+
+// ListLoginLockoutsRequest holds the arguments of a ListLoginLockouts
+// API call.
+type ListLoginLockoutsRequest struct {
+	// UserTags, if not empty, restricts the lockouts listed to those
+	// of the given users.
+	UserTags []string `json:"user-tags,omitempty"`
+}
+
+// LoginLockoutInfo describes the recent failed logins of a user from
+// an address.
+type LoginLockoutInfo struct {
+	Server      string    `json:"server"`
+	Address     string    `json:"address"`
+	Failures    int       `json:"failures"`
+	LastFailure time.Time `json:"last-failure"`
+
+	// UserTag is the user that failed to log in, or empty if the
+	// failures are those of any users from the address.
+	UserTag string `json:"user-tag"`
+
+	// LockedUntil is when the user may next log in from the address,
+	// or nil if the user is not locked out.
+	LockedUntil *time.Time `json:"locked-until,omitempty"`
+}
+
+// LoginLockoutResults holds the result of a ListLoginLockouts API call.
+type LoginLockoutResults struct {
+	Results []LoginLockoutInfo `json:"results"`
+}
+
+// ClearLoginLockoutsRequest identifies the login lockouts to clear.
+type ClearLoginLockoutsRequest struct {
+	// UserTags identifies users whose lockouts are cleared, from any
+	// address.
+	UserTags []string `json:"user-tags,omitempty"`
+
+	// Addresses holds addresses from which the lockouts of any user
+	// are cleared.
+	Addresses []string `json:"addresses,omitempty"`
+}
@@ -17,10 +17,13 @@ var controllerFacadeNames = set.NewStrings(
 	"AllModelWatcher",
 	"ApplicationOffers",
 	"Cloud",
+	"ConfigProfiles",
 	"Controller",
 	"CrossController",
+	"Inventory",
 	"MigrationTarget",
 	"ModelManager",
+	"OfferCatalog",
 	"UserManager",
 )
 
@@ -37,6 +37,7 @@ var allowedMethodsDuringMigration = map[string]set.Strings{
 		"AllAddresses",
 		"PublicKeys",
 		"Proxy",
+		"Bastion",
 	),
 	"Pinger": set.NewStrings(
 		"Ping",
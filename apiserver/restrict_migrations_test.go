@@ -29,6 +29,7 @@ func (r *restrictMigrationsSuite) TestAllowedMethods(c *gc.C) {
 	checkAllowed("Client", "FullStatus")
 	checkAllowed("SSHClient", "PublicAddress")
 	checkAllowed("SSHClient", "Proxy")
+	checkAllowed("SSHClient", "Bastion")
 	checkAllowed("Pinger", "Ping")
 }
 
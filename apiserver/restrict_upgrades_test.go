@@ -30,6 +30,7 @@ func (r *restrictUpgradesSuite) TestAllowedMethods(c *gc.C) {
 	checkAllowed("Client", "AbortCurrentUpgrade")
 	checkAllowed("SSHClient", "PublicAddress")
 	checkAllowed("SSHClient", "Proxy")
+	checkAllowed("SSHClient", "Bastion")
 	checkAllowed("Pinger", "Ping")
 }
 
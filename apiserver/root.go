@@ -4,6 +4,7 @@
 package apiserver
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"reflect"
@@ -15,7 +16,11 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/lockout"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/sessions"
+	"github.com/juju/juju/core/cache"
+	"github.com/juju/juju/core/tracing"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/rpcreflect"
@@ -61,12 +66,15 @@ type apiHandler struct {
 	// serverHost is the host:port of the API server that the client
 	// connected to.
 	serverHost string
+
+	// remoteAddr is the address the client connected from.
+	remoteAddr string
 }
 
 var _ = (*apiHandler)(nil)
 
 // newAPIHandler returns a new apiHandler.
-func newAPIHandler(srv *Server, st *state.State, rpcConn *rpc.Conn, modelUUID string, serverHost string) (*apiHandler, error) {
+func newAPIHandler(srv *Server, st *state.State, rpcConn *rpc.Conn, modelUUID, serverHost, remoteAddr string) (*apiHandler, error) {
 	m, err := st.Model()
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -78,6 +86,7 @@ func newAPIHandler(srv *Server, st *state.State, rpcConn *rpc.Conn, modelUUID st
 		rpcConn:    rpcConn,
 		modelUUID:  modelUUID,
 		serverHost: serverHost,
+		remoteAddr: remoteAddr,
 	}
 	if err := r.resources.RegisterNamed("machineID", common.StringResource(srv.tag.Id())); err != nil {
 		return nil, errors.Trace(err)
@@ -144,17 +153,27 @@ func (s *srvCaller) ResultType() reflect.Type {
 // Call takes the object Id and an instance of ParamsType to create an object and place
 // a call on its method. It then returns an instance of ResultType.
 func (s *srvCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+	return s.CallContext(context.Background(), objId, arg)
+}
+
+// CallContext is like Call, but passes the context to the method.
+// See rpcreflect.ContextMethodCaller for more detail.
+func (s *srvCaller) CallContext(ctx context.Context, objId string, arg reflect.Value) (reflect.Value, error) {
 	objVal, err := s.creator(objId)
 	if err != nil {
 		return reflect.Value{}, err
 	}
-	return s.objMethod.Call(objVal, arg)
+	return s.objMethod.CallContext(ctx, objVal, arg)
 }
 
 // apiRoot implements basic method dispatching to the facade registry.
 type apiRoot struct {
 	state       *state.State
 	pool        *state.StatePool
+	modelCache  *cache.Controller
+	spans       tracing.Recorder
+	sessions    *sessions.Tracker
+	lockouts    *lockout.Tracker
 	facades     *facade.Registry
 	resources   *common.Resources
 	authorizer  facade.Authorizer
@@ -163,10 +182,14 @@ type apiRoot struct {
 }
 
 // newAPIRoot returns a new apiRoot.
-func newAPIRoot(st *state.State, pool *state.StatePool, facades *facade.Registry, resources *common.Resources, authorizer facade.Authorizer) *apiRoot {
+func newAPIRoot(st *state.State, pool *state.StatePool, modelCache *cache.Controller, spans tracing.Recorder, sessionTracker *sessions.Tracker, lockouts *lockout.Tracker, facades *facade.Registry, resources *common.Resources, authorizer facade.Authorizer) *apiRoot {
 	r := &apiRoot{
 		state:       st,
 		pool:        pool,
+		modelCache:  modelCache,
+		spans:       spans,
+		sessions:    sessionTracker,
+		lockouts:    lockouts,
 		facades:     facades,
 		resources:   resources,
 		authorizer:  authorizer,
@@ -406,6 +429,26 @@ func (ctx *facadeContext) StatePool() *state.StatePool {
 	return ctx.r.pool
 }
 
+// ModelCache is part of of the facade.Context interface.
+func (ctx *facadeContext) ModelCache() *cache.Controller {
+	return ctx.r.modelCache
+}
+
+// SpanExporter is part of of the facade.Context interface.
+func (ctx *facadeContext) SpanExporter() tracing.Recorder {
+	return ctx.r.spans
+}
+
+// Sessions is part of of the facade.Context interface.
+func (ctx *facadeContext) Sessions() *sessions.Tracker {
+	return ctx.r.sessions
+}
+
+// LoginLockouts is part of of the facade.Context interface.
+func (ctx *facadeContext) LoginLockouts() *lockout.Tracker {
+	return ctx.r.lockouts
+}
+
 // ID is part of of the facade.Context interface.
 func (ctx *facadeContext) ID() string {
 	return ctx.key.objId
@@ -502,7 +545,10 @@ func (r *apiHandler) UserHasPermission(user names.UserTag, operation permission.
 
 // DescribeFacades returns the list of available Facades and their Versions
 func DescribeFacades(registry *facade.Registry) []params.FacadeVersions {
-	facades := registry.List()
+	return describeFacades(registry.List())
+}
+
+func describeFacades(facades []facade.Description) []params.FacadeVersions {
 	result := make([]params.FacadeVersions, len(facades))
 	for i, facade := range facades {
 		result[i].Name = facade.Name
@@ -0,0 +1,164 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sessions
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/observer"
+	"github.com/juju/juju/rpc"
+)
+
+// pingFacade is the facade that clients call to keep their connections
+// alive. Calls to it do not make a session active.
+const pingFacade = "Pinger"
+
+// Session observes an API connection. Once a user logs in over the
+// connection it is tracked, and may be revoked or time out.
+type Session struct {
+	tracker *Tracker
+
+	// done is closed when the session ends.
+	done    chan struct{}
+	endOnce sync.Once
+
+	// mu guards the fields below it.
+	mu          sync.Mutex
+	id          string
+	user        names.UserTag
+	modelUUID   string
+	remoteAddr  string
+	started     time.Time
+	lastActive  time.Time
+	facadeCalls map[string]int
+}
+
+var _ observer.Observer = (*Session)(nil)
+
+// Done returns a channel that is closed when the session is revoked or
+// times out, or the connection is closed. The API server closes the
+// connection when the session ends.
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// Info returns a description of the session.
+func (s *Session) Info() Info {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info := Info{
+		ID:            s.id,
+		Server:        s.tracker.config.ServerID,
+		User:          s.user,
+		ModelUUID:     s.modelUUID,
+		RemoteAddress: s.remoteAddr,
+		Started:       s.started,
+		LastActive:    s.lastActive,
+		FacadeCalls:   make(map[string]int, len(s.facadeCalls)),
+	}
+	for facade, n := range s.facadeCalls {
+		info.FacadeCalls[facade] = n
+	}
+	return info
+}
+
+// Join is part of the observer.Observer interface.
+func (s *Session) Join(req *http.Request, connectionID uint64) {
+	now := s.tracker.config.Clock.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.id = s.tracker.sessionID(connectionID)
+	s.remoteAddr = req.RemoteAddr
+	s.started = now
+	s.lastActive = now
+}
+
+// Login is part of the observer.Observer interface. Only the sessions
+// of users are tracked.
+func (s *Session) Login(entity names.Tag, model names.ModelTag, _ bool, _ string) {
+	user, ok := entity.(names.UserTag)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	s.user = user
+	s.modelUUID = model.Id()
+	s.mu.Unlock()
+
+	s.tracker.add(s)
+	if timeout := s.tracker.config.IdleTimeout; timeout > 0 {
+		go s.endWhenIdle(timeout)
+	}
+}
+
+// Leave is part of the observer.Observer interface.
+func (s *Session) Leave() {
+	s.tracker.remove(s)
+	s.endOnce.Do(func() { close(s.done) })
+}
+
+// RPCObserver is part of the observer.Observer interface.
+func (s *Session) RPCObserver() rpc.Observer {
+	return rpcObserver{s}
+}
+
+// called records a call to the named facade.
+func (s *Session) called(facade string) {
+	now := s.tracker.config.Clock.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.facadeCalls == nil {
+		s.facadeCalls = make(map[string]int)
+	}
+	s.facadeCalls[facade]++
+	if facade != pingFacade {
+		s.lastActive = now
+	}
+}
+
+// end ends the session, for the given reason.
+func (s *Session) end(reason string) {
+	s.endOnce.Do(func() {
+		info := s.Info()
+		logger.Infof("ending API session %s of %s from %s: %s", info.ID, info.User.Id(), info.RemoteAddress, reason)
+		close(s.done)
+	})
+}
+
+// endWhenIdle ends the session once it has made no API calls for the
+// given time.
+func (s *Session) endWhenIdle(timeout time.Duration) {
+	clock := s.tracker.config.Clock
+	for {
+		s.mu.Lock()
+		idle := clock.Now().Sub(s.lastActive)
+		s.mu.Unlock()
+		if idle >= timeout {
+			s.end("idle for " + idle.String())
+			return
+		}
+		select {
+		case <-s.done:
+			return
+		case <-clock.After(timeout - idle):
+		}
+	}
+}
+
+// rpcObserver records the facade calls made over a session.
+type rpcObserver struct {
+	session *Session
+}
+
+// ServerRequest is part of the rpc.Observer interface.
+func (o rpcObserver) ServerRequest(hdr *rpc.Header, body interface{}) {
+	o.session.called(hdr.Request.Type)
+}
+
+// ServerReply is part of the rpc.Observer interface.
+func (rpcObserver) ServerReply(rpc.Request, *rpc.Header, interface{}) {}
@@ -0,0 +1,410 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package sessions tracks the API sessions of logged in users, so that
+// they can be listed, and revoked when access must end immediately.
+package sessions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/pubsub"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/pubsub/apiserver"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.sessions")
+
+// DefaultListTimeout is how long AllSessions waits, by default, for the
+// other API servers to report their sessions.
+const DefaultListTimeout = 5 * time.Second
+
+// Info describes an API session.
+type Info struct {
+	// ID identifies the session among those of all API servers.
+	ID string
+
+	// Server identifies the API server holding the session.
+	Server string
+
+	// User is the user that logged in.
+	User names.UserTag
+
+	// ModelUUID is the UUID of the model the session is connected to,
+	// or empty if it is connected to the controller only.
+	ModelUUID string
+
+	// RemoteAddress is the address the client connected from.
+	RemoteAddress string
+
+	// Started is when the client connected.
+	Started time.Time
+
+	// LastActive is when the client last made an API call, other than
+	// a ping.
+	LastActive time.Time
+
+	// FacadeCalls holds the number of calls made to each facade.
+	FacadeCalls map[string]int
+}
+
+// Config holds the configuration of a Tracker.
+type Config struct {
+	// ServerID identifies the API server, and prefixes the IDs of
+	// the sessions it holds.
+	ServerID string
+
+	// Clock is used to time sessions.
+	Clock clock.Clock
+
+	// Hub is used to publish revocations to, and receive them from,
+	// all API servers.
+	Hub *pubsub.StructuredHub
+
+	// IdleTimeout is how long a user session may go without making an
+	// API call before it is ended. Sessions are not ended for being
+	// idle if IdleTimeout is zero.
+	IdleTimeout time.Duration
+
+	// ListTimeout is how long AllSessions waits for the other API
+	// servers to report their sessions. DefaultListTimeout is used if
+	// ListTimeout is zero.
+	ListTimeout time.Duration
+}
+
+// Validate checks that the config is valid.
+func (config Config) Validate() error {
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.Hub == nil {
+		return errors.NotValidf("nil Hub")
+	}
+	if config.IdleTimeout < 0 {
+		return errors.NotValidf("negative IdleTimeout")
+	}
+	if config.ListTimeout < 0 {
+		return errors.NotValidf("negative ListTimeout")
+	}
+	return nil
+}
+
+// Tracker records the sessions of the users logged in to an API
+// server.
+type Tracker struct {
+	config       Config
+	unsubscribes []func()
+
+	mu       sync.Mutex
+	sessions map[string]*Session
+
+	// servers holds the IDs of the controller's API servers, as last
+	// published by the peergrouper. They are requested when the
+	// tracker is created.
+	servers map[string]bool
+
+	// lastRequest numbers the session list requests made by the
+	// tracker.
+	lastRequest uint64
+}
+
+// NewTracker returns a Tracker that ends its sessions when revocations
+// are published on the hub, and reports them when they are requested.
+// Close must be called when the tracker is no longer needed.
+func NewTracker(config Config) (*Tracker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	t := &Tracker{
+		config:   config,
+		sessions: make(map[string]*Session),
+	}
+	subscriptions := []struct {
+		topic   string
+		handler interface{}
+	}{
+		{apiserver.SessionRevokeTopic, t.onRevocation},
+		{apiserver.SessionListTopic, t.onListRequest},
+		{apiserver.DetailsTopic, t.onDetails},
+	}
+	for _, sub := range subscriptions {
+		unsubscribe, err := config.Hub.Subscribe(sub.topic, sub.handler)
+		if err != nil {
+			t.Close()
+			return nil, errors.Trace(err)
+		}
+		t.unsubscribes = append(t.unsubscribes, unsubscribe)
+	}
+	// The peergrouper only publishes the API servers' details when
+	// they change, so ask for them rather than wait.
+	request := apiserver.DetailsRequest{Requester: "sessions-tracker", LocalOnly: true}
+	if _, err := config.Hub.Publish(apiserver.DetailsRequestTopic, request); err != nil {
+		t.Close()
+		return nil, errors.Annotate(err, "requesting API server details")
+	}
+	return t, nil
+}
+
+// Close stops the tracker receiving revocations and list requests.
+func (t *Tracker) Close() {
+	for _, unsubscribe := range t.unsubscribes {
+		unsubscribe()
+	}
+}
+
+// NewSession returns a Session to observe a new API connection. The
+// session is tracked once a user logs in over the connection.
+func (t *Tracker) NewSession() *Session {
+	return &Session{
+		tracker: t,
+		done:    make(chan struct{}),
+	}
+}
+
+// Sessions returns the sessions held by the API server, ordered by
+// start time.
+func (t *Tracker) Sessions() []Info {
+	t.mu.Lock()
+	sessions := make([]*Session, 0, len(t.sessions))
+	for _, s := range t.sessions {
+		sessions = append(sessions, s)
+	}
+	t.mu.Unlock()
+
+	result := make([]Info, len(sessions))
+	for i, s := range sessions {
+		result[i] = s.Info()
+	}
+	sortInfos(result)
+	return result
+}
+
+// AllSessions returns the sessions held by every API server of the
+// controller, ordered by start time. It waits at most the configured
+// ListTimeout for the other API servers to report their sessions, and
+// also returns the IDs of those that did not.
+func (t *Tracker) AllSessions() ([]Info, []string, error) {
+	t.mu.Lock()
+	t.lastRequest++
+	requestID := fmt.Sprintf("%s:%d", t.config.ServerID, t.lastRequest)
+	pending := make(map[string]bool)
+	for id := range t.servers {
+		if id != t.config.ServerID {
+			pending[id] = true
+		}
+	}
+	t.mu.Unlock()
+
+	result := t.Sessions()
+	if len(pending) == 0 {
+		return result, nil, nil
+	}
+
+	replies := make(chan apiserver.SessionListReply)
+	done := make(chan struct{})
+	unsubscribe, err := t.config.Hub.Subscribe(apiserver.SessionListReplyTopic,
+		func(topic string, reply apiserver.SessionListReply, err error) {
+			if err != nil {
+				logger.Errorf("cannot read session list reply: %v", err)
+				return
+			}
+			if reply.RequestID != requestID {
+				return
+			}
+			select {
+			case replies <- reply:
+			case <-done:
+			}
+		},
+	)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	defer unsubscribe()
+	defer close(done)
+
+	request := apiserver.SessionListRequest{RequestID: requestID}
+	if _, err := t.config.Hub.Publish(apiserver.SessionListTopic, request); err != nil {
+		return nil, nil, errors.Annotate(err, "publishing session list request")
+	}
+	replied := map[string]bool{t.config.ServerID: true}
+	timeout := t.config.Clock.After(t.listTimeout())
+	for len(pending) > 0 {
+		select {
+		case reply := <-replies:
+			if replied[reply.Server] {
+				continue
+			}
+			replied[reply.Server] = true
+			delete(pending, reply.Server)
+			for _, details := range reply.Sessions {
+				result = append(result, sessionInfo(reply.Server, details))
+			}
+		case <-timeout:
+			unreachable := make([]string, 0, len(pending))
+			for id := range pending {
+				unreachable = append(unreachable, id)
+			}
+			sort.Strings(unreachable)
+			sortInfos(result)
+			return result, unreachable, nil
+		}
+	}
+	sortInfos(result)
+	return result, nil, nil
+}
+
+func (t *Tracker) listTimeout() time.Duration {
+	if t.config.ListTimeout == 0 {
+		return DefaultListTimeout
+	}
+	return t.config.ListTimeout
+}
+
+func (t *Tracker) onListRequest(topic string, request apiserver.SessionListRequest, err error) {
+	if err != nil {
+		logger.Errorf("cannot read session list request: %v", err)
+		return
+	}
+	reply := apiserver.SessionListReply{
+		RequestID: request.RequestID,
+		Server:    t.config.ServerID,
+	}
+	for _, info := range t.Sessions() {
+		reply.Sessions = append(reply.Sessions, apiserver.SessionDetails{
+			ID:            info.ID,
+			User:          info.User.Id(),
+			ModelUUID:     info.ModelUUID,
+			RemoteAddress: info.RemoteAddress,
+			Started:       info.Started.UnixNano(),
+			LastActive:    info.LastActive.UnixNano(),
+			FacadeCalls:   info.FacadeCalls,
+		})
+	}
+	if _, err := t.config.Hub.Publish(apiserver.SessionListReplyTopic, reply); err != nil {
+		logger.Errorf("cannot publish session list reply: %v", err)
+	}
+}
+
+func (t *Tracker) onDetails(topic string, details apiserver.Details, err error) {
+	if err != nil {
+		logger.Errorf("cannot read API server details: %v", err)
+		return
+	}
+	servers := make(map[string]bool)
+	for id := range details.Servers {
+		servers[id] = true
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.servers = servers
+}
+
+// sessionInfo returns the Info describing a session reported by
+// another API server.
+func sessionInfo(server string, details apiserver.SessionDetails) Info {
+	info := Info{
+		ID:            details.ID,
+		Server:        server,
+		User:          names.NewUserTag(details.User),
+		ModelUUID:     details.ModelUUID,
+		RemoteAddress: details.RemoteAddress,
+		Started:       time.Unix(0, details.Started).UTC(),
+		LastActive:    time.Unix(0, details.LastActive).UTC(),
+		FacadeCalls:   make(map[string]int, len(details.FacadeCalls)),
+	}
+	for facade, n := range details.FacadeCalls {
+		info.FacadeCalls[facade] = n
+	}
+	return info
+}
+
+func sortInfos(infos []Info) {
+	sort.Slice(infos, func(i, j int) bool {
+		if !infos[i].Started.Equal(infos[j].Started) {
+			return infos[i].Started.Before(infos[j].Started)
+		}
+		return infos[i].ID < infos[j].ID
+	})
+}
+
+// Revoke ends the identified sessions, and all the sessions of the
+// given users, on every API server. Sessions held by this API server
+// are ended before Revoke returns; others are ended as the revocation
+// reaches their servers.
+func (t *Tracker) Revoke(sessionIDs []string, users []names.UserTag) error {
+	revocation := apiserver.SessionRevocation{
+		SessionIDs: sessionIDs,
+		Users:      make([]string, len(users)),
+	}
+	for i, user := range users {
+		revocation.Users[i] = user.Id()
+	}
+	t.revoke(revocation)
+	if _, err := t.config.Hub.Publish(apiserver.SessionRevokeTopic, revocation); err != nil {
+		return errors.Annotate(err, "publishing session revocation")
+	}
+	return nil
+}
+
+func (t *Tracker) onRevocation(topic string, revocation apiserver.SessionRevocation, err error) {
+	if err != nil {
+		logger.Errorf("cannot read session revocation: %v", err)
+		return
+	}
+	t.revoke(revocation)
+}
+
+// revoke ends the sessions held by this API server that match the
+// revocation. Ending a session more than once is harmless, so
+// revocations published by this server may be applied again when
+// they are received.
+func (t *Tracker) revoke(revocation apiserver.SessionRevocation) {
+	ids := make(map[string]bool)
+	for _, id := range revocation.SessionIDs {
+		ids[id] = true
+	}
+	users := make(map[string]bool)
+	for _, user := range revocation.Users {
+		users[strings.ToLower(user)] = true
+	}
+
+	t.mu.Lock()
+	var revoked []*Session
+	for id, s := range t.sessions {
+		if ids[id] || users[strings.ToLower(s.user.Id())] {
+			revoked = append(revoked, s)
+		}
+	}
+	t.mu.Unlock()
+
+	for _, s := range revoked {
+		s.end("revoked")
+	}
+}
+
+func (t *Tracker) sessionID(connectionID uint64) string {
+	return fmt.Sprintf("%s:%d", t.config.ServerID, connectionID)
+}
+
+func (t *Tracker) add(s *Session) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sessions[s.id] = s
+}
+
+func (t *Tracker) remove(s *Session) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.sessions[s.id] == s {
+		delete(t.sessions, s.id)
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sessions_test
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/juju/pubsub"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/sessions"
+	"github.com/juju/juju/pubsub/apiserver"
+	"github.com/juju/juju/pubsub/centralhub"
+	"github.com/juju/juju/rpc"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type trackerSuite struct {
+	testing.IsolationSuite
+
+	clock *testing.Clock
+	hub   *pubsub.StructuredHub
+}
+
+var _ = gc.Suite(&trackerSuite{})
+
+func (s *trackerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testing.NewClock(time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC))
+	s.hub = centralhub.New(names.NewMachineTag("0"))
+}
+
+func (s *trackerSuite) newTracker(c *gc.C, idleTimeout time.Duration) *sessions.Tracker {
+	tracker, err := sessions.NewTracker(sessions.Config{
+		ServerID:    "0",
+		Clock:       s.clock,
+		Hub:         s.hub,
+		IdleTimeout: idleTimeout,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { tracker.Close() })
+	return tracker
+}
+
+func (s *trackerSuite) login(tracker *sessions.Tracker, connectionID uint64, entity names.Tag) *sessions.Session {
+	session := tracker.NewSession()
+	session.Join(&http.Request{RemoteAddr: "10.0.0.1:40000"}, connectionID)
+	session.Login(entity, coretesting.ModelTag, false, "")
+	return session
+}
+
+func (s *trackerSuite) assertDone(c *gc.C, session *sessions.Session) {
+	select {
+	case <-session.Done():
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("session not ended")
+	}
+}
+
+func (s *trackerSuite) assertNotDone(c *gc.C, session *sessions.Session) {
+	select {
+	case <-session.Done():
+		c.Fatalf("session ended")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *trackerSuite) TestValidate(c *gc.C) {
+	_, err := sessions.NewTracker(sessions.Config{Hub: s.hub})
+	c.Assert(err, gc.ErrorMatches, "nil Clock not valid")
+	_, err = sessions.NewTracker(sessions.Config{Clock: s.clock})
+	c.Assert(err, gc.ErrorMatches, "nil Hub not valid")
+	_, err = sessions.NewTracker(sessions.Config{Clock: s.clock, Hub: s.hub, IdleTimeout: -time.Second})
+	c.Assert(err, gc.ErrorMatches, "negative IdleTimeout not valid")
+	_, err = sessions.NewTracker(sessions.Config{Clock: s.clock, Hub: s.hub, ListTimeout: -time.Second})
+	c.Assert(err, gc.ErrorMatches, "negative ListTimeout not valid")
+}
+
+func (s *trackerSuite) TestSessions(c *gc.C) {
+	tracker := s.newTracker(c, 0)
+	s.login(tracker, 1, names.NewUserTag("bob"))
+	s.login(tracker, 2, names.NewMachineTag("3"))
+	s.clock.Advance(time.Minute)
+	session := s.login(tracker, 3, names.NewUserTag("mary"))
+
+	s.clock.Advance(time.Minute)
+	rpcObserver := session.RPCObserver()
+	rpcObserver.ServerRequest(&rpc.Header{Request: rpc.Request{Type: "Client", Action: "FullStatus"}}, nil)
+	rpcObserver.ServerRequest(&rpc.Header{Request: rpc.Request{Type: "Client", Action: "FullStatus"}}, nil)
+	s.clock.Advance(time.Minute)
+	rpcObserver.ServerRequest(&rpc.Header{Request: rpc.Request{Type: "Pinger", Action: "Ping"}}, nil)
+
+	start := time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)
+	c.Assert(tracker.Sessions(), jc.DeepEquals, []sessions.Info{{
+		ID:            "0:1",
+		Server:        "0",
+		User:          names.NewUserTag("bob"),
+		ModelUUID:     coretesting.ModelTag.Id(),
+		RemoteAddress: "10.0.0.1:40000",
+		Started:       start,
+		LastActive:    start,
+		FacadeCalls:   map[string]int{},
+	}, {
+		ID:            "0:3",
+		Server:        "0",
+		User:          names.NewUserTag("mary"),
+		ModelUUID:     coretesting.ModelTag.Id(),
+		RemoteAddress: "10.0.0.1:40000",
+		Started:       start.Add(time.Minute),
+		LastActive:    start.Add(2 * time.Minute),
+		FacadeCalls:   map[string]int{"Client": 2, "Pinger": 1},
+	}})
+
+	session.Leave()
+	c.Assert(tracker.Sessions(), gc.HasLen, 1)
+	s.assertDone(c, session)
+}
+
+func (s *trackerSuite) TestRevokeSession(c *gc.C) {
+	tracker := s.newTracker(c, 0)
+	bob := s.login(tracker, 1, names.NewUserTag("bob"))
+	mary := s.login(tracker, 2, names.NewUserTag("mary"))
+
+	err := tracker.Revoke([]string{"0:1"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertDone(c, bob)
+	s.assertNotDone(c, mary)
+}
+
+func (s *trackerSuite) TestRevokeUser(c *gc.C) {
+	tracker := s.newTracker(c, 0)
+	bob1 := s.login(tracker, 1, names.NewUserTag("bob"))
+	bob2 := s.login(tracker, 2, names.NewUserTag("Bob"))
+	mary := s.login(tracker, 3, names.NewUserTag("mary"))
+
+	err := tracker.Revoke(nil, []names.UserTag{names.NewUserTag("bob")})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertDone(c, bob1)
+	s.assertDone(c, bob2)
+	s.assertNotDone(c, mary)
+}
+
+func (s *trackerSuite) TestRevocationFromOtherServer(c *gc.C) {
+	tracker := s.newTracker(c, 0)
+	bob := s.login(tracker, 1, names.NewUserTag("bob"))
+
+	done, err := s.hub.Publish(apiserver.SessionRevokeTopic, apiserver.SessionRevocation{
+		SessionIDs: []string{"1:1", "0:1"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case <-done:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("revocation not handled")
+	}
+	s.assertDone(c, bob)
+}
+
+func (s *trackerSuite) TestIdleTimeout(c *gc.C) {
+	tracker := s.newTracker(c, time.Hour)
+	session := s.login(tracker, 1, names.NewUserTag("bob"))
+
+	// Activity resets the timeout; pings do not.
+	s.clock.WaitAdvance(30*time.Minute, coretesting.LongWait, 1)
+	session.RPCObserver().ServerRequest(&rpc.Header{Request: rpc.Request{Type: "Client"}}, nil)
+	s.clock.WaitAdvance(30*time.Minute, coretesting.LongWait, 1)
+	session.RPCObserver().ServerRequest(&rpc.Header{Request: rpc.Request{Type: "Pinger"}}, nil)
+	s.assertNotDone(c, session)
+
+	s.clock.WaitAdvance(30*time.Minute, coretesting.LongWait, 1)
+	s.assertDone(c, session)
+}
+
+func (s *trackerSuite) TestAgentsNotTracked(c *gc.C) {
+	tracker := s.newTracker(c, time.Hour)
+	session := s.login(tracker, 1, names.NewMachineTag("0"))
+	c.Assert(tracker.Sessions(), gc.HasLen, 0)
+
+	s.clock.Advance(2 * time.Hour)
+	s.assertNotDone(c, session)
+}
+
+func (s *trackerSuite) publishServers(c *gc.C, ids ...string) {
+	details := apiserver.Details{Servers: make(map[string]apiserver.APIServer)}
+	for _, id := range ids {
+		details.Servers[id] = apiserver.APIServer{ID: id}
+	}
+	done, err := s.hub.Publish(apiserver.DetailsTopic, details)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case <-done:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("details not handled")
+	}
+}
+
+func (s *trackerSuite) TestRequestsServerDetails(c *gc.C) {
+	requests := make(chan apiserver.DetailsRequest, 1)
+	unsubscribe, err := s.hub.Subscribe(apiserver.DetailsRequestTopic,
+		func(topic string, request apiserver.DetailsRequest, err error) {
+			c.Check(err, jc.ErrorIsNil)
+			requests <- request
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	defer unsubscribe()
+
+	s.newTracker(c, 0)
+	select {
+	case request := <-requests:
+		c.Assert(request.Requester, gc.Equals, "sessions-tracker")
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("API server details not requested")
+	}
+}
+
+func (s *trackerSuite) TestAllSessions(c *gc.C) {
+	tracker := s.newTracker(c, 0)
+	other, err := sessions.NewTracker(sessions.Config{
+		ServerID: "1",
+		Clock:    s.clock,
+		Hub:      s.hub,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer other.Close()
+	s.publishServers(c, "0", "1")
+
+	s.login(tracker, 1, names.NewUserTag("bob"))
+	s.login(other, 2, names.NewUserTag("mary"))
+	otherSession := other.NewSession()
+	otherSession.Join(&http.Request{RemoteAddr: "10.0.0.2:40000"}, 3)
+	otherSession.Login(names.NewUserTag("bob"), names.ModelTag{}, false, "")
+
+	infos, unreachable, err := tracker.AllSessions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(unreachable, gc.HasLen, 0)
+	c.Assert(infos, gc.HasLen, 3)
+	start := time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)
+	c.Check(infos[1], jc.DeepEquals, sessions.Info{
+		ID:            "1:2",
+		Server:        "1",
+		User:          names.NewUserTag("mary"),
+		ModelUUID:     coretesting.ModelTag.Id(),
+		RemoteAddress: "10.0.0.1:40000",
+		Started:       start,
+		LastActive:    start,
+		FacadeCalls:   map[string]int{},
+	})
+	c.Check(infos[0].ID, gc.Equals, "0:1")
+	c.Check(infos[2].ID, gc.Equals, "1:3")
+	c.Check(infos[2].ModelUUID, gc.Equals, "")
+}
+
+func (s *trackerSuite) TestAllSessionsUnreachableServer(c *gc.C) {
+	tracker := s.newTracker(c, 0)
+	s.publishServers(c, "0", "2")
+	s.login(tracker, 1, names.NewUserTag("bob"))
+
+	type result struct {
+		infos       []sessions.Info
+		unreachable []string
+		err         error
+	}
+	results := make(chan result, 1)
+	go func() {
+		infos, unreachable, err := tracker.AllSessions()
+		results <- result{infos, unreachable, err}
+	}()
+	s.clock.WaitAdvance(sessions.DefaultListTimeout, coretesting.LongWait, 1)
+	select {
+	case r := <-results:
+		c.Assert(r.err, jc.ErrorIsNil)
+		c.Check(r.infos, gc.HasLen, 1)
+		c.Check(r.unreachable, jc.DeepEquals, []string{"2"})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("sessions not listed")
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/websocket"
+	"github.com/juju/juju/state"
+)
+
+const (
+	// sshTunnelPort is the port to which tunnelled connections are
+	// made on the target machine.
+	sshTunnelPort = 22
+
+	// sshTunnelDialTimeout bounds the time taken to connect to the
+	// target machine.
+	sshTunnelDialTimeout = 30 * time.Second
+
+	// sshTunnelBufferSize is the largest amount of data relayed from
+	// the target machine in a single message.
+	sshTunnelBufferSize = 32 * 1024
+)
+
+// sshTunnelDial is used to connect to the target machine; it is a
+// variable so that tests can replace it.
+var sshTunnelDial = net.DialTimeout
+
+// sshTunnelHandler is an http.Handler for the
+// "/model/:modeluuid/ssh-tunnel" endpoint. It relays an SSH connection
+// between the client and the ssh port of the machine named by the
+// "target" query parameter, a machine id or unit name, over the
+// websocket, so that users who can reach the controller's API but
+// neither the machine nor the controller's bastion can still ssh to it.
+// The ssh session itself is end to end between the client and the
+// machine; the controller sees only encrypted data.
+type sshTunnelHandler struct {
+	ctxt httpContext
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *sshTunnelHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	handler := func(socket *websocket.Conn) {
+		defer socket.Close()
+
+		conn, err := h.connect(req)
+		if err != nil {
+			if err := socket.SendInitialErrorV0(err); err != nil {
+				logger.Errorf("sending ssh tunnel error: %v", err)
+			}
+			return
+		}
+		defer conn.Close()
+		if err := socket.SendInitialErrorV0(nil); err != nil {
+			logger.Errorf("sending ssh tunnel initial response: %v", err)
+			return
+		}
+		h.relay(socket, conn)
+	}
+	websocket.Serve(w, req, handler)
+}
+
+// connect authenticates the request and connects to the ssh port of
+// the target machine.
+func (h *sshTunnelHandler) connect(req *http.Request) (net.Conn, error) {
+	st, releaser, entity, err := h.ctxt.stateAndEntityForRequestAuthenticatedUser(req)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer releaser()
+
+	canSSH, err := common.UserCanSSH(st, entity.Tag().Id(), st.ModelTag())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !canSSH {
+		return nil, common.ErrPerm
+	}
+
+	address, err := sshTunnelAddress(st, req.URL.Query().Get("target"))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	conn, err := sshTunnelDial("tcp", net.JoinHostPort(address, strconv.Itoa(sshTunnelPort)), sshTunnelDialTimeout)
+	if err != nil {
+		return nil, errors.Annotatef(err, "connecting to %s", address)
+	}
+	return conn, nil
+}
+
+// sshTunnelAddress returns the private address of the machine named
+// by target, or of the machine to which the named unit is assigned.
+func sshTunnelAddress(st *state.State, target string) (string, error) {
+	machineId := target
+	switch {
+	case names.IsValidUnit(target):
+		unit, err := st.Unit(target)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		if machineId, err = unit.AssignedMachineId(); err != nil {
+			return "", errors.Trace(err)
+		}
+	case names.IsValidMachine(target):
+	default:
+		return "", errors.BadRequestf("invalid ssh target %q", target)
+	}
+	machine, err := st.Machine(machineId)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	address, err := machine.PrivateAddress()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return address.Value, nil
+}
+
+// relay copies data between the websocket and the connection to the
+// target machine until either is closed or the server stops.
+func (h *sshTunnelHandler) relay(socket *websocket.Conn, conn net.Conn) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		buf := make([]byte, sshTunnelBufferSize)
+		for {
+			n, err := conn.Read(buf)
+			if n > 0 {
+				socket.SetWriteDeadline(time.Now().Add(websocket.WriteWait))
+				if err := socket.WriteJSON(params.SSHTunnelData{Data: buf[:n]}); err != nil {
+					logger.Debugf("ssh tunnel write error: %v", err)
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	go func() {
+		select {
+		case <-done:
+		case <-h.ctxt.stop():
+		}
+		// Closing both ends unblocks the loops.
+		conn.Close()
+		socket.Close()
+	}()
+	for {
+		var data params.SSHTunnelData
+		if err := socket.ReadJSON(&data); err != nil {
+			logger.Debugf("ssh tunnel read error: %v", err)
+			break
+		}
+		if _, err := conn.Write(data.Data); err != nil {
+			break
+		}
+	}
+	conn.Close()
+	<-done
+}
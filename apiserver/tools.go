@@ -20,6 +20,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	envtools "github.com/juju/juju/environs/tools"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/binarystorage"
@@ -135,6 +136,28 @@ func (h *toolsDownloadHandler) processGet(r *http.Request, st *state.State) ([]b
 	return data, nil
 }
 
+// toolsDownloadClient returns the HTTP client with which to download
+// agent binaries from toolsURL. There is usually no need to verify the
+// server's identity, because we verify the SHA-256 hash; but credentials
+// for agent-metadata-url are only ever sent to a verified server under
+// that URL, and never to the public streams.
+func toolsDownloadClient(cfg *config.Config, toolsURL string) (*http.Client, error) {
+	metadataURL, ok := cfg.AgentMetadataURL()
+	if !ok || !config.URLWithin(metadataURL, toolsURL) {
+		return utils.GetNonValidatingHTTPClient(), nil
+	}
+	auth, ok := cfg.AgentMetadataAuth()
+	if !ok {
+		return utils.GetNonValidatingHTTPClient(), nil
+	}
+	client := utils.GetValidatingHTTPClient()
+	transport, err := auth.RoundTripper(metadataURL, client.Transport)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
 // fetchAndCacheTools fetches tools with the specified version by searching for a URL
 // in simplestreams and GETting it, caching the result in tools storage before returning
 // to the caller.
@@ -149,9 +172,12 @@ func (h *toolsDownloadHandler) fetchAndCacheTools(v version.Binary, stor binarys
 		return nil, err
 	}
 
-	// No need to verify the server's identity because we verify the SHA-256 hash.
 	logger.Infof("fetching %v agent binaries from %v", v, tools.URL)
-	resp, err := utils.GetNonValidatingHTTPClient().Get(tools.URL)
+	client, err := toolsDownloadClient(env.Config(), tools.URL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	resp, err := client.Get(tools.URL)
 	if err != nil {
 		return nil, err
 	}
@@ -173,12 +199,25 @@ func (h *toolsDownloadHandler) fetchAndCacheTools(v version.Binary, stor binarys
 	if sha256 != tools.SHA256 {
 		return nil, errors.Errorf("hash mismatch for %s", tools.URL)
 	}
+	// The hash only protects against corruption; when trusted keys are
+	// configured, the tarball must also be signed by one of them so
+	// that binaries from a compromised mirror are never cached.
+	controllerConfig, err := st.ControllerConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if trustedKeys := controllerConfig.AgentBinaryTrustedKeys(); trustedKeys != "" {
+		if err := envtools.VerifySignature(bytes.NewReader(data), tools, trustedKeys); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 
 	// Cache tarball in tools storage before returning.
 	metadata := binarystorage.Metadata{
-		Version: v.String(),
-		Size:    tools.Size,
-		SHA256:  tools.SHA256,
+		Version:   v.String(),
+		Size:      tools.Size,
+		SHA256:    tools.SHA256,
+		Signature: tools.Signature,
 	}
 	if err := stor.Add(bytes.NewReader(data), metadata); err != nil {
 		return nil, errors.Annotate(err, "error caching agent binaries")
@@ -28,6 +28,8 @@ import (
 	apitesting "github.com/juju/juju/api/testing"
 	commontesting "github.com/juju/juju/apiserver/common/testing"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
+	sstesting "github.com/juju/juju/environs/simplestreams/testing"
 	envtesting "github.com/juju/juju/environs/testing"
 	envtools "github.com/juju/juju/environs/tools"
 	toolstesting "github.com/juju/juju/environs/tools/testing"
@@ -635,3 +637,34 @@ func (s *toolsWithMacaroonsSuite) TestCanPostWithLocalLogin(c *gc.C) {
 func (s *toolsWithMacaroonsSuite) doer() func(*http.Request) (*http.Response, error) {
 	return bakeryDo(nil, bakeryGetError)
 }
+
+type toolsTrustedKeysSuite struct {
+	toolsSuite
+}
+
+var _ = gc.Suite(&toolsTrustedKeysSuite{})
+
+func (s *toolsTrustedKeysSuite) SetUpTest(c *gc.C) {
+	s.ControllerConfigAttrs = map[string]interface{}{
+		controller.AgentBinaryTrustedKeys: sstesting.SignedMetadataPublicKey,
+	}
+	s.toolsSuite.SetUpTest(c)
+}
+
+func (s *toolsTrustedKeysSuite) TestDownloadFetchesAndVerifiesSignature(c *gc.C) {
+	// The fake tools are published without a signature, so the API
+	// server must refuse to fetch and cache them.
+	s.PatchValue(&jujuversion.Current, testing.FakeVersionNumber)
+	stor := s.DefaultToolsStorage
+	envtesting.RemoveTools(c, stor, "released")
+	current := version.Binary{
+		Number: jujuversion.Current,
+		Arch:   arch.HostArch(),
+		Series: series.MustHostSeries(),
+	}
+	tools := envtesting.AssertUploadFakeToolsVersions(c, stor, "released", "released", current)[0]
+
+	resp := s.downloadRequest(c, tools.Version, "")
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, "error fetching agent binaries: agent binaries .* are not signed")
+	s.assertToolsNotStored(c, tools.Version.String())
+}
@@ -85,6 +85,114 @@ func (aw *SrvAllWatcher) Next() (params.AllWatcherNextResults, error) {
 	}, err
 }
 
+// statusDiffWatcher is implemented by the watchers created by the
+// WatchStatus API call.
+type statusDiffWatcher interface {
+	Changes() <-chan []params.StatusDiff
+	Err() error
+}
+
+// NewStatusWatcher returns a new API server endpoint for interacting
+// with a watcher created by the WatchStatus API call.
+func NewStatusWatcher(context facade.Context) (facade.Facade, error) {
+	id := context.ID()
+	auth := context.Auth()
+	resources := context.Resources()
+
+	// As with the AllWatcher, the permission check is made when the
+	// watcher is created.
+	if !auth.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	watcher, ok := resources.Get(id).(statusDiffWatcher)
+	if !ok {
+		return nil, common.ErrUnknownWatcher
+	}
+	return &SrvStatusWatcher{
+		watcherCommon: newWatcherCommon(context),
+		watcher:       watcher,
+	}, nil
+}
+
+// SrvStatusWatcher defines the API methods on a watcher that reports
+// the changes to a model's full status as diffs.
+type SrvStatusWatcher struct {
+	watcherCommon
+	watcher statusDiffWatcher
+}
+
+// Next returns the diffs to the model's full status since the most
+// recent call to Next. The first call returns the whole status, as
+// diffs from an empty one.
+func (w *SrvStatusWatcher) Next() (params.StatusWatchResult, error) {
+	if diffs, ok := <-w.watcher.Changes(); ok {
+		return params.StatusWatchResult{
+			Diffs: diffs,
+		}, nil
+	}
+	err := w.watcher.Err()
+	if err == nil {
+		err = common.ErrStoppedWatcher
+	}
+	return params.StatusWatchResult{}, err
+}
+
+// NewStatusHistoryWatcher returns a new API server endpoint for
+// interacting with a watcher created by the WatchStatusHistory API
+// call.
+func NewStatusHistoryWatcher(context facade.Context) (facade.Facade, error) {
+	id := context.ID()
+	auth := context.Auth()
+	resources := context.Resources()
+
+	// As with the AllWatcher, the permission check is made when the
+	// watcher is created.
+	if !auth.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	watcher, ok := resources.Get(id).(state.StatusHistoryWatcher)
+	if !ok {
+		return nil, common.ErrUnknownWatcher
+	}
+	return &SrvStatusHistoryWatcher{
+		watcherCommon: newWatcherCommon(context),
+		watcher:       watcher,
+	}, nil
+}
+
+// SrvStatusHistoryWatcher defines the API methods on a watcher that
+// reports the entries added to an entity's status history.
+type SrvStatusHistoryWatcher struct {
+	watcherCommon
+	watcher state.StatusHistoryWatcher
+}
+
+// Next returns the entries added to the status history since the most
+// recent call to Next, or the WatchStatusHistory call that created the
+// watcher, oldest first. It blocks until there are entries to return.
+func (w *SrvStatusHistoryWatcher) Next() (params.StatusHistoryWatchResult, error) {
+	if entries, ok := <-w.watcher.Changes(); ok {
+		result := params.StatusHistoryWatchResult{
+			Statuses: make([]params.DetailedStatus, len(entries)),
+		}
+		for i, entry := range entries {
+			result.Statuses[i] = params.DetailedStatus{
+				Status: string(entry.Status),
+				Info:   entry.Info,
+				Data:   entry.Data,
+				Since:  entry.Since,
+				Kind:   string(entry.Kind),
+			}
+		}
+		return result, nil
+	}
+	err := w.watcher.Err()
+	if err == nil {
+		err = common.ErrStoppedWatcher
+	}
+	return params.StatusHistoryWatchResult{}, err
+}
+
 // srvNotifyWatcher defines the API access to methods on a state.NotifyWatcher.
 // Each client has its own current set of watchers, stored in resources.
 type srvNotifyWatcher struct {
@@ -4,3 +4,4 @@
 package cloudconfig
 
 var ToolsDownloadCommand = toolsDownloadCommand
+var MachineProgressCommand = machineProgressCommand
@@ -0,0 +1,122 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cloudconfig
+
+import (
+	"path"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/os"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/environs/config"
+)
+
+const (
+	hardeningSysctlFile     = "/etc/sysctl.d/60-juju-hardening.conf"
+	hardeningAuditRulesFile = "/etc/audit/rules.d/juju-hardening.rules"
+	hardeningSSHConfigFile  = "/etc/ssh/sshd_config.juju-hardening"
+)
+
+// cisLevel1Sysctl holds the kernel parameters set by the cis-level1
+// profile. IP forwarding and reverse path filtering are left alone, as
+// containers, the fan and machines in several spaces rely on them.
+var cisLevel1Sysctl = []string{
+	"net.ipv4.conf.all.accept_redirects = 0",
+	"net.ipv4.conf.default.accept_redirects = 0",
+	"net.ipv4.conf.all.secure_redirects = 0",
+	"net.ipv4.conf.default.secure_redirects = 0",
+	"net.ipv4.conf.all.send_redirects = 0",
+	"net.ipv4.conf.default.send_redirects = 0",
+	"net.ipv4.conf.all.accept_source_route = 0",
+	"net.ipv4.conf.default.accept_source_route = 0",
+	"net.ipv4.conf.all.log_martians = 1",
+	"net.ipv4.conf.default.log_martians = 1",
+	"net.ipv4.icmp_echo_ignore_broadcasts = 1",
+	"net.ipv4.icmp_ignore_bogus_error_responses = 1",
+	"net.ipv4.tcp_syncookies = 1",
+	"net.ipv6.conf.all.accept_redirects = 0",
+	"net.ipv6.conf.default.accept_redirects = 0",
+	"kernel.randomize_va_space = 2",
+	"fs.suid_dumpable = 0",
+}
+
+// cisLevel1AuditRules holds the audit rules added by the cis-level1
+// profile.
+var cisLevel1AuditRules = []string{
+	"-w /etc/passwd -p wa -k identity",
+	"-w /etc/group -p wa -k identity",
+	"-w /etc/shadow -p wa -k identity",
+	"-w /etc/gshadow -p wa -k identity",
+	"-w /etc/sudoers -p wa -k scope",
+	"-w /etc/sudoers.d/ -p wa -k scope",
+	"-w /etc/ssh/sshd_config -p wa -k sshd",
+	"-w /etc/hosts -p wa -k system-locale",
+	"-a always,exit -F arch=b64 -S sethostname -S setdomainname -k system-locale",
+	"-w /var/log/faillog -p wa -k logins",
+	"-w /var/log/lastlog -p wa -k logins",
+}
+
+// cisLevel1SSHConfig holds the SSH daemon settings of the cis-level1
+// profile. Juju only uses key based logins as the ubuntu user, so they
+// do not get in its way.
+var cisLevel1SSHConfig = [][2]string{
+	{"PermitRootLogin", "no"},
+	{"PermitEmptyPasswords", "no"},
+	{"PasswordAuthentication", "no"},
+	{"HostbasedAuthentication", "no"},
+	{"IgnoreRhosts", "yes"},
+	{"X11Forwarding", "no"},
+	{"MaxAuthTries", "4"},
+	{"LoginGraceTime", "60"},
+}
+
+// addOSHardening applies the OS hardening profile of the instance config
+// on first boot, and records the profile in the data dir so that the
+// machine agent can report it.
+func (w *unixConfigure) addOSHardening() error {
+	switch w.icfg.OSHardeningProfile {
+	case "", config.OSHardeningNone:
+		return nil
+	case config.OSHardeningCISLevel1:
+	default:
+		return errors.NotValidf("OS hardening profile %q", w.icfg.OSHardeningProfile)
+	}
+
+	w.conf.AddScripts(`echo 'Applying OS hardening profile ` + w.icfg.OSHardeningProfile + `'`)
+
+	w.conf.AddRunTextFile(hardeningSysctlFile, strings.Join(cisLevel1Sysctl, "\n")+"\n", 0644)
+	w.conf.AddScripts("sysctl -p " + hardeningSysctlFile)
+
+	switch w.os {
+	case os.CentOS, os.OpenSUSE:
+		w.conf.AddPackage("audit")
+	default:
+		w.conf.AddPackage("auditd")
+	}
+	w.conf.AddRunTextFile(hardeningAuditRulesFile, strings.Join(cisLevel1AuditRules, "\n")+"\n", 0640)
+	w.conf.AddScripts("augenrules --load || service auditd restart")
+
+	// sshd uses the first value it finds for each setting, so existing
+	// settings are removed and the hardened ones put at the top.
+	keys := make([]string, len(cisLevel1SSHConfig))
+	settings := []string{"# Added by juju"}
+	for i, setting := range cisLevel1SSHConfig {
+		keys[i] = setting[0]
+		settings = append(settings, setting[0]+" "+setting[1])
+	}
+	w.conf.AddRunTextFile(hardeningSSHConfigFile, strings.Join(settings, "\n")+"\n", 0600)
+	w.conf.AddScripts(
+		`sed -i -E '/^#?[[:space:]]*(`+strings.Join(keys, "|")+`)[[:space:]]/d' /etc/ssh/sshd_config`,
+		"cat "+hardeningSSHConfigFile+" /etc/ssh/sshd_config > /etc/ssh/sshd_config.new",
+		"mv /etc/ssh/sshd_config.new /etc/ssh/sshd_config",
+		"rm "+hardeningSSHConfigFile,
+		"service ssh reload || service sshd reload",
+	)
+
+	profileFile := path.Join(w.icfg.DataDir, agent.OSHardeningProfileFile)
+	w.conf.AddRunTextFile(profileFile, w.icfg.OSHardeningProfile, 0644)
+	return nil
+}
@@ -155,6 +155,27 @@ type InstanceConfig struct {
 	// ifup when bridging bonded interfaces. See bugs #1594855 and
 	// #1269921.
 	NetBondReconfigureDelay int
+
+	// CloudInitUserData defines additional cloud-config, from the
+	// cloudinit-userdata model config attribute, that is merged into
+	// the cloud-init user data of the instance.
+	CloudInitUserData map[string]interface{}
+
+	// CACertificates holds PEM encoded CA certificates, from the
+	// custom-ca-certificates model config attribute, that are added
+	// to the system trust store of the instance.
+	CACertificates []string
+
+	// HardenMetadataService, from the instance-metadata-hardening
+	// model config attribute, restricts access to the cloud's instance
+	// metadata service on the instance to the root user, and blocks it
+	// for any containers the instance hosts.
+	HardenMetadataService bool
+
+	// OSHardeningProfile, from the os-hardening-profile model config
+	// attribute, names the OS hardening baseline applied to the
+	// instance on first boot.
+	OSHardeningProfile string
 }
 
 // ControllerConfig represents controller-specific initialization information
@@ -780,6 +801,8 @@ func PopulateInstanceConfig(icfg *InstanceConfig,
 	aptMirror string,
 	enableOSRefreshUpdates bool,
 	enableOSUpgrade bool,
+	cloudInitUserData map[string]interface{},
+	caCertificates []string,
 ) error {
 	icfg.AuthorizedKeys = authorizedKeys
 	if icfg.AgentEnvironment == nil {
@@ -794,6 +817,8 @@ func PopulateInstanceConfig(icfg *InstanceConfig,
 	icfg.AptMirror = aptMirror
 	icfg.EnableOSRefreshUpdate = enableOSRefreshUpdates
 	icfg.EnableOSUpgrade = enableOSUpgrade
+	icfg.CloudInitUserData = cloudInitUserData
+	icfg.CACertificates = caCertificates
 	return nil
 }
 
@@ -819,9 +844,13 @@ func FinishInstanceConfig(icfg *InstanceConfig, cfg *config.Config) (err error)
 		cfg.AptMirror(),
 		cfg.EnableOSRefreshUpdate(),
 		cfg.EnableOSUpgrade(),
+		cfg.CloudInitUserData(),
+		cfg.CustomCACertificates(),
 	); err != nil {
 		return errors.Trace(err)
 	}
+	icfg.HardenMetadataService = cfg.InstanceMetadataHardening()
+	icfg.OSHardeningProfile = cfg.OSHardeningProfile()
 	if icfg.Controller != nil {
 		// Add NUMACTL preference. Needed to work for both bootstrap and high availability
 		// Only makes sense for controller
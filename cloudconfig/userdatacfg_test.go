@@ -387,6 +387,9 @@ printf '%s\\n' '.*"Stop all network interfaces on shutdown".*' > '/etc/init/juju
 install -D -m 644 /dev/null '/var/lib/juju/nonce.txt'
 printf '%s\\n' 'FAKE_NONCE' > '/var/lib/juju/nonce.txt'
 test -n "\$JUJU_PROGRESS_FD" \|\| \(exec \{JUJU_PROGRESS_FD\}>&2\) 2>/dev/null && exec \{JUJU_PROGRESS_FD\}>&2 \|\| JUJU_PROGRESS_FD=2
+install -D -m 644 /dev/null '/var/lib/juju/controller-ca\.pem'
+printf '%s\\n' '.*CERTIFICATE.*' > '/var/lib/juju/controller-ca\.pem'
+\(set \+x; for url in .* --cacert '/var/lib/juju/controller-ca\.pem' .* 'message=configuring machine' .*
 \[ -e /etc/profile.d/juju-proxy.sh \] \|\| printf .* >> /etc/profile.d/juju-proxy.sh
 mkdir -p /var/lib/juju/locks
 \(id ubuntu &> /dev/null\) && chown ubuntu:ubuntu /var/lib/juju/locks
@@ -394,6 +397,7 @@ mkdir -p /var/log/juju
 chown syslog:adm /var/log/juju
 bin='/var/lib/juju/tools/1\.2\.3-quantal-amd64'
 mkdir -p \$bin
+\(set \+x; for url in .* 'message=downloading agent binaries' .*
 echo 'Fetching Juju agent version.*
 curl -sSfw '.*' --connect-timeout 20 --noproxy "\*" --insecure -o \$bin/tools\.tar\.gz 'https://state-addr\.testing\.invalid:54321/deadbeef-0bad-400d-8000-4b1d0d06f00d/tools/1\.2\.3-quantal-amd64'
 sha256sum \$bin/tools\.tar\.gz > \$bin/juju1\.2\.3-quantal-amd64\.sha256
@@ -403,6 +407,7 @@ printf %s '{"version":"1\.2\.3-quantal-amd64","url":"https://state-addr\.testing
 mkdir -p '/var/lib/juju/agents/machine-99'
 cat > '/var/lib/juju/agents/machine-99/agent\.conf' << 'EOF'\\n.*\\nEOF
 chmod 0600 '/var/lib/juju/agents/machine-99/agent\.conf'
+\(set \+x; for url in .* 'message=starting machine agent' .*
 ln -s 1\.2\.3-quantal-amd64 '/var/lib/juju/tools/machine-99'
 echo 'Starting Juju machine agent \(service jujud-machine-99\)'.*
 cat > /etc/init/jujud-machine-99\.conf << 'EOF'\\ndescription "juju agent for machine-99"\\nauthor "Juju Team <juju@lists\.ubuntu\.com>"\\nstart on runlevel \[2345\]\\nstop on runlevel \[!2345\]\\nrespawn\\nnormal exit 0\\n\\nlimit nofile 20000 20000\\n\\nscript\\n\\n\\n  # Ensure log files are properly protected\\n  touch /var/log/juju/machine-99\.log\\n  chown syslog:syslog /var/log/juju/machine-99\.log\\n  chmod 0600 /var/log/juju/machine-99\.log\\n\\n  exec '/var/lib/juju/tools/machine-99/jujud' machine --data-dir '/var/lib/juju' --machine-id 99 --debug >> /var/log/juju/machine-99\.log 2>&1\\nend script\\nEOF\\n
@@ -1196,6 +1201,183 @@ DefaultEnvironment="http_proxy=http://user@10.0.0.1" "HTTP_PROXY=http://user@10.
 	c.Assert(found, jc.IsTrue)
 }
 
+func (s *cloudinitSuite) TestCloudInitUserData(c *gc.C) {
+	environConfig := minimalModelConfig(c)
+	environConfig, err := environConfig.Apply(map[string]interface{}{
+		"cloudinit-userdata": `
+packages: [htop]
+write_files:
+  - path: /etc/motd
+    content: managed by juju
+runcmd:
+  - touch /tmp/done
+  - [sh, -c, echo hello]
+`,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	instanceCfg := s.createInstanceConfig(c, environConfig)
+	cloudcfg, err := cloudinit.New("quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	udata, err := cloudconfig.NewUserdataConfig(instanceCfg, cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.Configure()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(set.NewStrings(cloudcfg.Packages()...).Contains("htop"), jc.IsTrue)
+	cmds := set.NewStrings(cloudcfg.RunCmds()...)
+	c.Assert(cmds.Contains("touch /tmp/done"), jc.IsTrue)
+	c.Assert(cmds.Contains("'sh' '-c' 'echo hello'"), jc.IsTrue)
+
+	data, err := cloudcfg.RenderYAML()
+	c.Assert(err, jc.ErrorIsNil)
+	var rendered map[string]interface{}
+	err = goyaml.Unmarshal(data, &rendered)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rendered["write_files"], jc.DeepEquals, []interface{}{
+		map[interface{}]interface{}{
+			"path":    "/etc/motd",
+			"content": "managed by juju",
+		},
+	})
+}
+
+func (s *cloudinitSuite) TestCloudInitUserDataSkipsManagedModules(c *gc.C) {
+	instanceCfg := s.createInstanceConfig(c, minimalModelConfig(c))
+	instanceCfg.CloudInitUserData = map[string]interface{}{
+		"ssh_authorized_keys": []interface{}{"ssh-rsa AAAA mallory"},
+		"apt_proxy":           "http://proxy.invalid",
+		"locale":              "fr_FR.UTF-8",
+	}
+	cloudcfg, err := cloudinit.New("quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	udata, err := cloudconfig.NewUserdataConfig(instanceCfg, cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.Configure()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(cloudcfg.PackageProxy(), gc.Not(gc.Equals), "http://proxy.invalid")
+	c.Assert(cloudcfg.Locale(), gc.Equals, "fr_FR.UTF-8")
+	data, err := cloudcfg.RenderYAML()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Not(jc.Contains), "mallory")
+}
+
+func (s *cloudinitSuite) TestCACertificates(c *gc.C) {
+	environConfig := minimalModelConfig(c)
+	environConfig, err := environConfig.Apply(map[string]interface{}{
+		"custom-ca-certificates": []interface{}{testing.CACert, testing.OtherCACert},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	instanceCfg := s.createInstanceConfig(c, environConfig)
+	cloudcfg, err := cloudinit.New("quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	udata, err := cloudconfig.NewUserdataConfig(instanceCfg, cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.Configure()
+	c.Assert(err, jc.ErrorIsNil)
+
+	cmds := cloudcfg.BootCmds()
+	c.Assert(len(cmds) > 0, jc.IsTrue)
+	c.Assert(cmds[len(cmds)-1], gc.Equals, "update-ca-certificates")
+	bootcmds := strings.Join(cmds, "\n")
+	for i, cert := range []string{testing.CACert, testing.OtherCACert} {
+		filename := fmt.Sprintf("/usr/local/share/ca-certificates/juju-ca-%d.crt", i)
+		c.Assert(bootcmds, jc.Contains, "install -D -m 644 /dev/null '"+filename+"'")
+		c.Assert(bootcmds, jc.Contains, cert)
+	}
+}
+
+func (s *cloudinitSuite) TestCACertificatesNotSet(c *gc.C) {
+	instanceCfg := s.createInstanceConfig(c, minimalModelConfig(c))
+	cloudcfg, err := cloudinit.New("quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	udata, err := cloudconfig.NewUserdataConfig(instanceCfg, cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.Configure()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.Join(cloudcfg.BootCmds(), "\n"), gc.Not(jc.Contains), "update-ca-certificates")
+}
+
+func (s *cloudinitSuite) TestInstanceMetadataHardening(c *gc.C) {
+	environConfig := minimalModelConfig(c)
+	environConfig, err := environConfig.Apply(map[string]interface{}{
+		"instance-metadata-hardening": true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	instanceCfg := s.createInstanceConfig(c, environConfig)
+	c.Assert(instanceCfg.HardenMetadataService, jc.IsTrue)
+	cloudcfg, err := cloudinit.New("quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	udata, err := cloudconfig.NewUserdataConfig(instanceCfg, cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.Configure()
+	c.Assert(err, jc.ErrorIsNil)
+
+	bootcmds := strings.Join(cloudcfg.BootCmds(), "\n")
+	c.Assert(bootcmds, jc.Contains,
+		"iptables -C OUTPUT -d 169.254.169.254 -m owner ! --uid-owner 0 -j REJECT 2>/dev/null || "+
+			"iptables -I OUTPUT -d 169.254.169.254 -m owner ! --uid-owner 0 -j REJECT")
+	c.Assert(bootcmds, jc.Contains,
+		"iptables -C FORWARD -d 169.254.169.254 -j REJECT 2>/dev/null || "+
+			"iptables -I FORWARD -d 169.254.169.254 -j REJECT")
+}
+
+func (s *cloudinitSuite) TestInstanceMetadataHardeningNotSet(c *gc.C) {
+	instanceCfg := s.createInstanceConfig(c, minimalModelConfig(c))
+	cloudcfg, err := cloudinit.New("quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	udata, err := cloudconfig.NewUserdataConfig(instanceCfg, cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.Configure()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.Join(cloudcfg.BootCmds(), "\n"), gc.Not(jc.Contains), "169.254.169.254")
+}
+
+func (s *cloudinitSuite) TestOSHardeningProfile(c *gc.C) {
+	environConfig := minimalModelConfig(c)
+	environConfig, err := environConfig.Apply(map[string]interface{}{
+		"os-hardening-profile": "cis-level1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	instanceCfg := s.createInstanceConfig(c, environConfig)
+	c.Assert(instanceCfg.OSHardeningProfile, gc.Equals, "cis-level1")
+	cloudcfg, err := cloudinit.New("quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	udata, err := cloudconfig.NewUserdataConfig(instanceCfg, cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.Configure()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(strings.Join(cloudcfg.Packages(), " "), jc.Contains, "auditd")
+	runcmds := strings.Join(cloudcfg.RunCmds(), "\n")
+	for _, expected := range []string{
+		"install -D -m 644 /dev/null '/etc/sysctl.d/60-juju-hardening.conf'",
+		"kernel.randomize_va_space = 2",
+		"sysctl -p /etc/sysctl.d/60-juju-hardening.conf",
+		"install -D -m 640 /dev/null '/etc/audit/rules.d/juju-hardening.rules'",
+		"-w /etc/passwd -p wa -k identity",
+		"PermitRootLogin no",
+		"service ssh reload || service sshd reload",
+		"install -D -m 644 /dev/null '/var/lib/juju/os-hardening-profile'",
+	} {
+		c.Check(runcmds, jc.Contains, expected)
+	}
+	c.Check(runcmds, gc.Not(jc.Contains), "net.ipv4.ip_forward")
+}
+
+func (s *cloudinitSuite) TestOSHardeningProfileNone(c *gc.C) {
+	instanceCfg := s.createInstanceConfig(c, minimalModelConfig(c))
+	c.Assert(instanceCfg.OSHardeningProfile, gc.Equals, "none")
+	cloudcfg, err := cloudinit.New("quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	udata, err := cloudconfig.NewUserdataConfig(instanceCfg, cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.Configure()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.Join(cloudcfg.Packages(), " "), gc.Not(jc.Contains), "auditd")
+	c.Assert(strings.Join(cloudcfg.RunCmds(), "\n"), gc.Not(jc.Contains), "os-hardening-profile")
+}
+
 func (s *cloudinitSuite) TestAptMirror(c *gc.C) {
 	environConfig := minimalModelConfig(c)
 	environConfig, err := environConfig.Apply(map[string]interface{}{
@@ -1310,6 +1492,30 @@ done`
 	c.Assert(command, gc.Equals, expected)
 }
 
+func (*cloudinitSuite) TestMachineProgressCommand(c *gc.C) {
+	info := &api.Info{
+		Addrs:    []string{"10.0.0.1:17070", "10.0.0.2:17070"},
+		Password: "sekrit",
+		ModelTag: testing.ModelTag,
+		Tag:      names.NewMachineTag("1"),
+	}
+	command := cloudconfig.MachineProgressCommand(info, "/var/lib/juju/controller-ca.pem", "noncy", "starting machine agent")
+	c.Assert(command, gc.Equals, fmt.Sprintf(`(set +x; for url in `+
+		`'https://10.0.0.1:17070/model/%[1]s/machine-progress' `+
+		`'https://10.0.0.2:17070/model/%[1]s/machine-progress'; do `+
+		`curl -sSf --connect-timeout 5 --max-time 10 --noproxy "*" --cacert '/var/lib/juju/controller-ca.pem' `+
+		`-u 'machine-1:sekrit' -H 'X-Juju-Nonce: noncy' --data-urlencode 'message=starting machine agent' `+
+		`"$url" > /dev/null 2>&1 && break; done) || true`,
+		testing.ModelTag.Id(),
+	))
+}
+
+func (s *cloudinitSuite) TestBootstrapMachineDoesNotReportProgress(c *gc.C) {
+	for _, script := range s.bootstrapConfigScripts(c) {
+		c.Check(script, gc.Not(jc.Contains), "machine-progress")
+	}
+}
+
 func expectedUbuntuUser(groups, keys []string) map[string]interface{} {
 	user := map[string]interface{}{
 		"name":        "ubuntu",
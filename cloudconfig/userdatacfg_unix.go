@@ -24,9 +24,13 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cloudconfig/cloudinit"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/simplestreams"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/juju/paths"
 	"github.com/juju/juju/service"
 	"github.com/juju/juju/service/systemd"
 	"github.com/juju/juju/service/upstart"
@@ -139,6 +143,10 @@ func (w *unixConfigure) ConfigureBasic() error {
 		)
 		w.addCleanShutdownJob(service.InitSystemSystemd)
 	}
+	if err := w.addCACertificates(); err != nil {
+		return errors.Trace(err)
+	}
+	w.addMetadataServiceHardening()
 	SetUbuntuUser(w.conf, w.icfg.AuthorizedKeys)
 
 	if w.icfg.Bootstrap != nil {
@@ -168,6 +176,46 @@ func (w *unixConfigure) ConfigureBasic() error {
 	return nil
 }
 
+// addCACertificates adds the custom CA certificates to the system
+// trust store. They are added by boot commands, so that they are
+// trusted before any packages are installed.
+func (w *unixConfigure) addCACertificates() error {
+	if len(w.icfg.CACertificates) == 0 {
+		return nil
+	}
+	store, err := paths.SystemTrustStore(w.icfg.Series)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for i, cert := range w.icfg.CACertificates {
+		filename := path.Join(store.Dir, fmt.Sprintf("juju-ca-%d%s", i, store.FileExtension))
+		w.conf.AddBootTextFile(filename, cert, 0644)
+	}
+	w.conf.AddBootCmd(store.UpdateCommand)
+	return nil
+}
+
+// metadataServiceAddress is the link-local address at which clouds
+// serve instance metadata.
+const metadataServiceAddress = "169.254.169.254"
+
+// addMetadataServiceHardening restricts access to the instance metadata
+// service to the root user, and stops it being reached by containers
+// through the host, so that workloads cannot read instance credentials.
+// The rules are added by boot commands, so that they are restored on
+// every boot.
+func (w *unixConfigure) addMetadataServiceHardening() {
+	if !w.icfg.HardenMetadataService {
+		return
+	}
+	for _, rule := range []string{
+		"OUTPUT -d " + metadataServiceAddress + " -m owner ! --uid-owner 0 -j REJECT",
+		"FORWARD -d " + metadataServiceAddress + " -j REJECT",
+	} {
+		w.conf.AddBootCmd(fmt.Sprintf("iptables -C %s 2>/dev/null || iptables -I %s", rule, rule))
+	}
+}
+
 func (w *unixConfigure) addCleanShutdownJob(initSystem string) {
 	switch initSystem {
 	case service.InitSystemUpstart:
@@ -240,6 +288,15 @@ func (w *unixConfigure) ConfigureJuju() error {
 		w.icfg.EnableOSUpgrade,
 	)
 
+	// Packages are updated and installed before any commands are run,
+	// so the first report tells the controller that has finished.
+	w.addControllerCACert()
+	w.reportProgress("configuring machine")
+
+	if err := w.addOSHardening(); err != nil {
+		return errors.Trace(err)
+	}
+
 	// Write out the normal proxy settings so that the settings are
 	// sourced by bash, and ssh through that.
 	w.conf.AddScripts(
@@ -285,6 +342,7 @@ func (w *unixConfigure) ConfigureJuju() error {
 	)
 
 	// Fetch the tools and unarchive them into it.
+	w.reportProgress("downloading agent binaries")
 	if err := w.addDownloadToolsCmds(); err != nil {
 		return errors.Trace(err)
 	}
@@ -321,9 +379,87 @@ func (w *unixConfigure) ConfigureJuju() error {
 		}
 	}
 
+	// Add the operator's cloud-config last, so that their packages
+	// and files are in place before the machine agent starts.
+	w.addCloudInitUserData()
+
+	w.reportProgress("starting machine agent")
 	return w.addMachineAgentToBoot()
 }
 
+// addCloudInitUserData merges the cloud-config from the
+// cloudinit-userdata model config attribute into the user data.
+// Packages and runcmds are added to those of Juju; modules that Juju
+// manages are skipped, and any other module is set as given. The config
+// has been validated, but is checked again here so that an unexpected
+// value is skipped rather than fatal.
+func (w *unixConfigure) addCloudInitUserData() {
+	for key, value := range w.icfg.CloudInitUserData {
+		if config.IsManagedCloudInitModule(key) {
+			logger.Warningf("ignoring cloudinit-userdata %s; it is managed by Juju", key)
+			continue
+		}
+		switch key {
+		case "packages":
+			packages, _ := value.([]interface{})
+			for _, pkg := range packages {
+				if pkg, ok := pkg.(string); ok {
+					w.conf.AddPackage(pkg)
+				} else {
+					logger.Warningf("ignoring cloudinit-userdata package %v", pkg)
+				}
+			}
+		case "runcmd":
+			cmds, _ := value.([]interface{})
+			for _, cmd := range cmds {
+				switch cmd := cmd.(type) {
+				case string:
+					w.conf.AddRunCmd(cmd)
+				case []interface{}:
+					// As with cloud-init, each argument of a
+					// command given as a list is quoted.
+					args := make([]string, len(cmd))
+					for i, arg := range cmd {
+						args[i] = shquote(fmt.Sprint(arg))
+					}
+					w.conf.AddRunCmd(args...)
+				default:
+					logger.Warningf("ignoring cloudinit-userdata runcmd %v", cmd)
+				}
+			}
+		default:
+			w.conf.SetAttr(key, value)
+		}
+	}
+}
+
+// controllerCACertFile is the file, relative to the data directory, in
+// which the controller's CA certificate is written so that progress
+// reports can verify the controller's identity.
+const controllerCACertFile = "controller-ca.pem"
+
+// addControllerCACert writes the controller's CA certificate, with
+// which progress reports verify the controller they are sent to. A
+// bootstrap machine has no controller to report to.
+func (w *unixConfigure) addControllerCACert() {
+	if w.icfg.Bootstrap != nil {
+		return
+	}
+	w.conf.AddRunTextFile(path.Join(w.icfg.DataDir, controllerCACertFile), w.icfg.APIInfo.CACert, 0644)
+}
+
+// reportProgress adds a command that reports the given phase of the
+// machine's configuration to the controller, which records it as the
+// machine's instance status. Reporting is best effort, and a bootstrap
+// machine has no controller to report to.
+func (w *unixConfigure) reportProgress(phase string) {
+	if w.icfg.Bootstrap != nil {
+		return
+	}
+	caCertPath := path.Join(w.icfg.DataDir, controllerCACertFile)
+	w.conf.AddRunCmd(machineProgressCommand(w.icfg.APIInfo, caCertPath, w.icfg.MachineNonce, phase))
+}
+
 func (w *unixConfigure) configureBootstrap() error {
 	// Add the Juju GUI to the bootstrap node.
 	cleanup, err := w.setUpGUI()
@@ -482,6 +618,33 @@ func (w *unixConfigure) setUpGUI() (func(), error) {
 
 }
 
+// machineProgressCommand returns a command that POSTs the given
+// progress message to the machine-progress endpoint of each controller
+// in turn until one accepts it. The controllers' certificates are
+// verified against the CA certificate in caCertPath, as the request
+// carries the agent's credentials. Tracing is disabled while it runs so
+// that the credentials are not written to the cloud-init log, and
+// failures are ignored.
+func machineProgressCommand(info *api.Info, caCertPath, nonce, message string) string {
+	urls := make([]string, len(info.Addrs))
+	for i, addr := range info.Addrs {
+		urls[i] = shquote(fmt.Sprintf(
+			"https://%s/model/%s/machine-progress", addr, info.ModelTag.Id(),
+		))
+	}
+	return fmt.Sprintf(
+		`(set +x; for url in %s; do `+
+			`curl -sSf --connect-timeout 5 --max-time 10 --noproxy "*" --cacert %s `+
+			`-u %s -H %s --data-urlencode %s "$url" > /dev/null 2>&1 && break; `+
+			`done) || true`,
+		strings.Join(urls, " "),
+		shquote(caCertPath),
+		shquote(info.Tag.String()+":"+info.Password),
+		shquote(params.MachineNonceHeader+": "+nonce),
+		shquote("message="+message),
+	)
+}
+
 // toolsDownloadCommand takes a curl command minus the source URL,
 // and generates a command that will cycle through the URLs until
 // one succeeds.
@@ -204,6 +204,9 @@ func FormatActionResult(result params.ActionResult) map[string]interface{} {
 	if len(result.Output) != 0 {
 		response["results"] = result.Output
 	}
+	if result.Action != nil && result.Action.CorrelationId != "" {
+		response["correlation-id"] = result.Action.CorrelationId
+	}
 
 	if result.Enqueued.IsZero() && result.Started.IsZero() && result.Completed.IsZero() {
 		return response
@@ -0,0 +1,222 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+var usageAffinitySummary = `
+Displays the affinity policy of an application.`[1:]
+
+var usageAffinityDetails = `
+Shows the affinity policy set for an application with ` + "`juju set-affinity`" + `,
+and the ways in which the placement of its units breaks the policy, if any.
+
+Examples:
+    juju affinity mysql
+    juju affinity -m mymodel mysql --format json
+
+See also:
+    set-affinity`[1:]
+
+var usageSetAffinitySummary = `
+Sets the affinity policy of an application.`[1:]
+
+var usageSetAffinityDetails = `
+Sets the policy that governs where the units of an application are placed
+relative to the units of other applications. The policy replaces any
+policy previously set, and applies to units assigned to machines from then
+on; units already assigned are not moved.
+
+Units are never placed on a machine, or a container on a machine, that
+hosts units of an application given with --not-with. Units are placed in
+the availability zones of the applications given with --same-zone-as where
+possible. If a placement directive given to deploy or add-unit forces a
+unit onto a machine that breaks the policy, the unit is placed there
+anyway and the violation is shown by ` + "`juju affinity`" + `.
+
+Examples:
+    juju set-affinity mysql --not-with mariadb,postgresql
+    juju set-affinity wordpress --same-zone-as mysql
+    juju set-affinity mysql --clear
+
+See also:
+    affinity
+    add-unit
+    deploy`[1:]
+
+// NewAffinityCommand returns a command which shows the affinity policy
+// of an application.
+func NewAffinityCommand() modelcmd.ModelCommand {
+	return modelcmd.Wrap(&affinityCommand{})
+}
+
+// NewSetAffinityCommand returns a command which sets the affinity
+// policy of an application.
+func NewSetAffinityCommand() modelcmd.ModelCommand {
+	return modelcmd.Wrap(&setAffinityCommand{})
+}
+
+type affinityAPI interface {
+	Close() error
+	GetAffinity(string) (application.Affinity, error)
+	SetAffinity(string, application.Affinity) error
+}
+
+type affinityCommandBase struct {
+	modelcmd.ModelCommandBase
+	applicationName string
+	api             affinityAPI
+}
+
+func (c *affinityCommandBase) getAPI() (affinityAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return application.NewClient(root), nil
+}
+
+func (c *affinityCommandBase) parseApplicationName(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return nil, errors.Errorf("no application name specified")
+	}
+	if !names.IsValidApplication(args[0]) {
+		return nil, errors.Errorf("invalid application name %q", args[0])
+	}
+	c.applicationName = args[0]
+	return args[1:], nil
+}
+
+type affinityCommand struct {
+	affinityCommandBase
+	out cmd.Output
+}
+
+// Info implements Command.
+func (c *affinityCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "affinity",
+		Args:    "<application>",
+		Purpose: usageAffinitySummary,
+		Doc:     usageAffinityDetails,
+	}
+}
+
+// SetFlags implements Command.
+func (c *affinityCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements Command.
+func (c *affinityCommand) Init(args []string) error {
+	args, err := c.parseApplicationName(args)
+	if err != nil {
+		return err
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.
+func (c *affinityCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	affinity, err := client.GetAffinity(c.applicationName)
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, affinityPolicy{
+		NotWith:    affinity.NotWith,
+		SameZoneAs: affinity.SameZoneAs,
+		Violations: affinity.Violations,
+	})
+}
+
+type affinityPolicy struct {
+	NotWith    []string            `yaml:"not-with,omitempty" json:"not-with,omitempty"`
+	SameZoneAs []string            `yaml:"same-zone-as,omitempty" json:"same-zone-as,omitempty"`
+	Violations map[string][]string `yaml:"violations,omitempty" json:"violations,omitempty"`
+}
+
+type setAffinityCommand struct {
+	affinityCommandBase
+	notWith    []string
+	sameZoneAs []string
+	clear      bool
+}
+
+// Info implements Command.
+func (c *setAffinityCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set-affinity",
+		Args:    "<application>",
+		Purpose: usageSetAffinitySummary,
+		Doc:     usageSetAffinityDetails,
+	}
+}
+
+// SetFlags implements Command.
+func (c *setAffinityCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.Var(cmd.NewAppendStringsValue(&c.notWith), "not-with", "Never co-locate units with these comma delimited applications")
+	f.Var(cmd.NewAppendStringsValue(&c.sameZoneAs), "same-zone-as", "Prefer the zones of these comma delimited applications")
+	f.BoolVar(&c.clear, "clear", false, "Remove the application's affinity policy")
+}
+
+// Init implements Command.
+func (c *setAffinityCommand) Init(args []string) error {
+	args, err := c.parseApplicationName(args)
+	if err != nil {
+		return err
+	}
+	for _, appNames := range [][]string{c.notWith, c.sameZoneAs} {
+		for _, name := range appNames {
+			if !names.IsValidApplication(name) {
+				return errors.Errorf("invalid application name %q", name)
+			}
+		}
+	}
+	hasPolicy := len(c.notWith) > 0 || len(c.sameZoneAs) > 0
+	switch {
+	case c.clear && hasPolicy:
+		return errors.New("cannot specify --clear with --not-with or --same-zone-as")
+	case !c.clear && !hasPolicy:
+		return errors.New("no affinity specified; use --clear to remove the policy")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.
+func (c *setAffinityCommand) Run(_ *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	err = client.SetAffinity(c.applicationName, application.Affinity{
+		NotWith:    c.notWith,
+		SameZoneAs: c.sameZoneAs,
+	})
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
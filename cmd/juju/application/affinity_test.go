@@ -0,0 +1,124 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	apiapplication "github.com/juju/juju/api/application"
+	"github.com/juju/juju/cmd/juju/application"
+	"github.com/juju/juju/testing"
+)
+
+type AffinitySuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	api *fakeAffinityAPI
+}
+
+var _ = gc.Suite(&AffinitySuite{})
+
+func (s *AffinitySuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = &fakeAffinityAPI{}
+}
+
+func (s *AffinitySuite) TestSetInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{},
+		err:  `no application name specified`,
+	}, {
+		args: []string{"mysql-0", "--clear"},
+		err:  `invalid application name "mysql-0"`,
+	}, {
+		args: []string{"mysql", "--not-with", "bad_name"},
+		err:  `invalid application name "bad_name"`,
+	}, {
+		args: []string{"mysql"},
+		err:  `no affinity specified; use --clear to remove the policy`,
+	}, {
+		args: []string{"mysql", "--clear", "--not-with", "mariadb"},
+		err:  `cannot specify --clear with --not-with or --same-zone-as`,
+	}, {
+		args: []string{"mysql", "--clear", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"mysql", "--not-with", "mariadb,postgresql"},
+	}, {
+		args: []string{"mysql", "--clear"},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		cmd := application.NewSetAffinityCommandForTest(s.api, application.NewMockStore())
+		err := cmdtesting.InitCommand(cmd, test.args)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *AffinitySuite) TestSetAffinity(c *gc.C) {
+	cmd := application.NewSetAffinityCommandForTest(s.api, application.NewMockStore())
+	_, err := cmdtesting.RunCommand(c, cmd, "mysql", "--not-with", "mariadb,postgresql", "--same-zone-as", "haproxy")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCall(c, 0, "SetAffinity", "mysql", apiapplication.Affinity{
+		NotWith:    []string{"mariadb", "postgresql"},
+		SameZoneAs: []string{"haproxy"},
+	})
+	s.api.CheckCall(c, 1, "Close")
+}
+
+func (s *AffinitySuite) TestClearAffinity(c *gc.C) {
+	cmd := application.NewSetAffinityCommandForTest(s.api, application.NewMockStore())
+	_, err := cmdtesting.RunCommand(c, cmd, "mysql", "--clear")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCall(c, 0, "SetAffinity", "mysql", apiapplication.Affinity{})
+}
+
+func (s *AffinitySuite) TestShowAffinity(c *gc.C) {
+	s.api.affinity = apiapplication.Affinity{
+		NotWith:    []string{"mariadb"},
+		SameZoneAs: []string{"haproxy"},
+		Violations: map[string][]string{"mysql/1": {"shares machine 3 with mariadb/0"}},
+	}
+	cmd := application.NewAffinityCommandForTest(s.api, application.NewMockStore())
+	ctx, err := cmdtesting.RunCommand(c, cmd, "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+not-with:
+- mariadb
+same-zone-as:
+- haproxy
+violations:
+  mysql/1:
+  - shares machine 3 with mariadb/0
+`[1:])
+	s.api.CheckCall(c, 0, "GetAffinity", "mysql")
+}
+
+type fakeAffinityAPI struct {
+	jujutesting.Stub
+	affinity apiapplication.Affinity
+}
+
+func (f *fakeAffinityAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeAffinityAPI) GetAffinity(appName string) (apiapplication.Affinity, error) {
+	f.MethodCall(f, "GetAffinity", appName)
+	return f.affinity, f.NextErr()
+}
+
+func (f *fakeAffinityAPI) SetAffinity(appName string, affinity apiapplication.Affinity) error {
+	f.MethodCall(f, "SetAffinity", appName, affinity)
+	return f.NextErr()
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6"
 	charmresource "gopkg.in/juju/charm.v6/resource"
@@ -24,6 +25,7 @@ import (
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/constraints"
+	envtesting "github.com/juju/juju/environs/testing"
 	"github.com/juju/juju/resource"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
@@ -31,6 +33,7 @@ import (
 	"github.com/juju/juju/testcharms"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
+	jujuversion "github.com/juju/juju/version"
 )
 
 // LTS-dependent requires new entry upon new LTS release. There are numerous
@@ -817,6 +820,13 @@ func (s *BundleDeployCharmStoreSuite) TestDeployBundleApplicationOptions(c *gc.C
 func (s *BundleDeployCharmStoreSuite) TestDeployBundleApplicationConstrants(c *gc.C) {
 	testcharms.UploadCharm(c, s.client, "xenial/wordpress-42", "wordpress")
 	testcharms.UploadCharm(c, s.client, "precise/dummy-0", "dummy")
+	// Deploying with an arch constraint requires agent binaries for
+	// that architecture.
+	envtesting.AssertUploadFakeToolsVersions(c, s.DefaultToolsStorage, "released", "released", version.Binary{
+		Number: jujuversion.Current,
+		Arch:   "i386",
+		Series: "precise",
+	})
 	err := s.DeployBundleYAML(c, `
         applications:
             wordpress:
@@ -0,0 +1,233 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/crossmodeldiagnostics"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+var diagnoseRelationHelpSummary = `
+Shows diagnostics for a cross model relation.`[1:]
+
+var diagnoseRelationHelpDetails = `
+Shows what the models taking part in a cross model relation know about it,
+to help find out why the relation is not working. The relation is specified
+using its id, as shown by "juju status --relations".
+
+For each model the command shows the macaroon authorising the relation and
+when it expires, the ingress and egress networks last exchanged and when
+they were recorded, and the networks allowed by the model's firewall rule
+for application offers. Ingress networks outside that rule are listed under
+"blocked". The other model is only shown if it is hosted by the same
+controller.
+
+When the model consumes an offer hosted by another controller, the command
+also connects to that controller and shows how long the connection took.
+
+The command requires admin access to the model.
+
+Examples:
+    juju diagnose-relation 4
+    juju diagnose-relation 4 --format json
+
+See also:
+    show-relation
+    firewall-rules
+    status`
+
+// NewDiagnoseRelationCommand returns a command to show diagnostics for
+// a cross model relation.
+func NewDiagnoseRelationCommand() cmd.Command {
+	cmd := &diagnoseRelationCommand{}
+	cmd.newAPIFunc = func() (DiagnoseRelationAPI, error) {
+		root, err := cmd.NewAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return crossmodeldiagnostics.NewClient(root), nil
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+type diagnoseRelationCommand struct {
+	modelcmd.ModelCommandBase
+	out        cmd.Output
+	relationId int
+	newAPIFunc func() (DiagnoseRelationAPI, error)
+}
+
+// DiagnoseRelationAPI defines the API methods that the
+// diagnose-relation command uses.
+type DiagnoseRelationAPI interface {
+	Close() error
+	RelationDiagnostics(relationId int) (*params.CrossModelRelationDiagnostics, error)
+}
+
+// Info implements Command.
+func (c *diagnoseRelationCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "diagnose-relation",
+		Args:    "<relation-id>",
+		Purpose: diagnoseRelationHelpSummary,
+		Doc:     diagnoseRelationHelpDetails,
+	}
+}
+
+// SetFlags implements Command.
+func (c *diagnoseRelationCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements Command.
+func (c *diagnoseRelationCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no relation id specified")
+	}
+	id, err := strconv.Atoi(strings.TrimSpace(args[0]))
+	if err != nil || id < 0 {
+		return errors.NotValidf("relation ID %q", args[0])
+	}
+	c.relationId = id
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.
+func (c *diagnoseRelationCommand) Run(ctx *cmd.Context) error {
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	diag, err := client.RelationDiagnostics(c.relationId)
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, formatRelationDiagnostics(diag))
+}
+
+type relationDiagnostics struct {
+	Id                int                      `yaml:"id" json:"id"`
+	Key               string                   `yaml:"key" json:"key"`
+	RemoteApplication string                   `yaml:"remote-application" json:"remote-application"`
+	Role              string                   `yaml:"role" json:"role"`
+	Local             relationSideDiagnostics  `yaml:"local" json:"local"`
+	Remote            *relationSideDiagnostics `yaml:"remote,omitempty" json:"remote,omitempty"`
+	RemoteUnavailable string                   `yaml:"remote-unavailable,omitempty" json:"remote-unavailable,omitempty"`
+	Probe             probeResult              `yaml:"probe" json:"probe"`
+}
+
+type relationSideDiagnostics struct {
+	Model    string               `yaml:"model" json:"model"`
+	Macaroon *macaroonDiagnostics `yaml:"macaroon,omitempty" json:"macaroon,omitempty"`
+	Ingress  *networksDiagnostics `yaml:"ingress,omitempty" json:"ingress,omitempty"`
+	Egress   *networksDiagnostics `yaml:"egress,omitempty" json:"egress,omitempty"`
+	Firewall firewallDiagnostics  `yaml:"firewall" json:"firewall"`
+}
+
+type macaroonDiagnostics struct {
+	Id       string `yaml:"id" json:"id"`
+	Location string `yaml:"location,omitempty" json:"location,omitempty"`
+	Expiry   string `yaml:"expiry,omitempty" json:"expiry,omitempty"`
+	Expired  bool   `yaml:"expired" json:"expired"`
+}
+
+type networksDiagnostics struct {
+	CIDRs   []string `yaml:"cidrs" json:"cidrs"`
+	Updated string   `yaml:"updated,omitempty" json:"updated,omitempty"`
+}
+
+type firewallDiagnostics struct {
+	Whitelist []string `yaml:"whitelist,omitempty" json:"whitelist,omitempty"`
+	Blocked   []string `yaml:"blocked,omitempty" json:"blocked,omitempty"`
+}
+
+type probeResult struct {
+	Probed  bool   `yaml:"probed" json:"probed"`
+	Note    string `yaml:"note,omitempty" json:"note,omitempty"`
+	Address string `yaml:"address,omitempty" json:"address,omitempty"`
+	Latency string `yaml:"latency,omitempty" json:"latency,omitempty"`
+	Error   string `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+func formatRelationDiagnostics(diag *params.CrossModelRelationDiagnostics) relationDiagnostics {
+	result := relationDiagnostics{
+		Id:                diag.RelationId,
+		Key:               diag.RelationKey,
+		RemoteApplication: diag.RemoteApplication,
+		Role:              diag.Role,
+		Local:             formatRelationSideDiagnostics(diag.Local),
+		RemoteUnavailable: diag.RemoteUnavailable,
+		Probe: probeResult{
+			Probed:  diag.Probe.Probed,
+			Note:    diag.Probe.Note,
+			Address: diag.Probe.Address,
+			Error:   diag.Probe.Error,
+		},
+	}
+	if diag.Remote != nil {
+		remote := formatRelationSideDiagnostics(*diag.Remote)
+		result.Remote = &remote
+	}
+	if diag.Probe.Address != "" {
+		result.Probe.Latency = diag.Probe.Latency.String()
+	}
+	return result
+}
+
+func formatRelationSideDiagnostics(side params.RelationSideDiagnostics) relationSideDiagnostics {
+	result := relationSideDiagnostics{
+		Model: side.ModelTag,
+		Firewall: firewallDiagnostics{
+			Whitelist: side.Firewall.WhitelistCIDRs,
+			Blocked:   side.Firewall.Blocked,
+		},
+		Ingress: formatNetworksDiagnostics(side.Ingress),
+		Egress:  formatNetworksDiagnostics(side.Egress),
+	}
+	if tag, err := names.ParseModelTag(side.ModelTag); err == nil {
+		result.Model = tag.Id()
+	}
+	if side.Macaroon != nil {
+		result.Macaroon = &macaroonDiagnostics{
+			Id:       side.Macaroon.Id,
+			Location: side.Macaroon.Location,
+			Expiry:   formatOptionalTime(side.Macaroon.Expiry),
+			Expired:  side.Macaroon.Expired,
+		}
+	}
+	return result
+}
+
+func formatNetworksDiagnostics(networks *params.RelationNetworksDiagnostics) *networksDiagnostics {
+	if networks == nil {
+		return nil
+	}
+	return &networksDiagnostics{
+		CIDRs:   networks.CIDRs,
+		Updated: formatOptionalTime(networks.Updated),
+	}
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
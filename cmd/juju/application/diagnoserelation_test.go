@@ -0,0 +1,131 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+type DiagnoseRelationSuite struct {
+	testing.IsolationSuite
+	mockAPI *mockDiagnoseRelationAPI
+}
+
+var _ = gc.Suite(&DiagnoseRelationSuite{})
+
+func (s *DiagnoseRelationSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	expiry := time.Date(2018, 3, 1, 11, 0, 0, 0, time.UTC)
+	updated := time.Date(2018, 3, 1, 9, 0, 0, 0, time.UTC)
+	s.mockAPI = &mockDiagnoseRelationAPI{
+		diag: &params.CrossModelRelationDiagnostics{
+			RelationId:        4,
+			RelationKey:       "wordpress:db mysql:server",
+			RemoteApplication: "mysql",
+			Role:              "consumer",
+			Local: params.RelationSideDiagnostics{
+				ModelTag: "model-deadbeef-0bad-400d-8000-4b1d0d06f00d",
+				Macaroon: &params.MacaroonDiagnostics{
+					Id:       "id",
+					Location: "location",
+					Expiry:   &expiry,
+				},
+				Egress: &params.RelationNetworksDiagnostics{
+					CIDRs:   []string{"10.1.0.0/24"},
+					Updated: &updated,
+				},
+			},
+			RemoteUnavailable: "model is hosted by another controller",
+			Probe: params.ConnectivityProbeResult{
+				Probed:  true,
+				Address: "10.0.0.2:17070",
+				Latency: 20 * time.Millisecond,
+			},
+		},
+	}
+}
+
+func (s *DiagnoseRelationSuite) runDiagnoseRelation(c *gc.C, args ...string) (string, error) {
+	ctx, err := cmdtesting.RunCommand(c, NewDiagnoseRelationCommandForTest(s.mockAPI), args...)
+	if err != nil {
+		return "", err
+	}
+	return cmdtesting.Stdout(ctx), nil
+}
+
+func (s *DiagnoseRelationSuite) TestDiagnoseRelationInvalidArguments(c *gc.C) {
+	_, err := s.runDiagnoseRelation(c)
+	c.Assert(err, gc.ErrorMatches, "no relation id specified")
+
+	_, err = s.runDiagnoseRelation(c, "wordpress")
+	c.Assert(err, gc.ErrorMatches, `relation ID "wordpress" not valid`)
+
+	_, err = s.runDiagnoseRelation(c, "4", "5")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["5"\]`)
+}
+
+func (s *DiagnoseRelationSuite) TestDiagnoseRelation(c *gc.C) {
+	out, err := s.runDiagnoseRelation(c, "4")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockAPI.CheckCalls(c, []testing.StubCall{
+		{"RelationDiagnostics", []interface{}{4}},
+		{"Close", nil},
+	})
+	c.Assert(out, gc.Equals, `
+id: 4
+key: wordpress:db mysql:server
+remote-application: mysql
+role: consumer
+local:
+  model: deadbeef-0bad-400d-8000-4b1d0d06f00d
+  macaroon:
+    id: id
+    location: location
+    expiry: "2018-03-01T11:00:00Z"
+    expired: false
+  egress:
+    cidrs:
+    - 10.1.0.0/24
+    updated: "2018-03-01T09:00:00Z"
+  firewall: {}
+remote-unavailable: model is hosted by another controller
+probe:
+  probed: true
+  address: 10.0.0.2:17070
+  latency: 20ms
+`[1:])
+}
+
+func (s *DiagnoseRelationSuite) TestDiagnoseRelationFail(c *gc.C) {
+	s.mockAPI.SetErrors(errors.New("boom"))
+	_, err := s.runDiagnoseRelation(c, "4")
+	c.Assert(err, gc.ErrorMatches, "boom")
+	s.mockAPI.CheckCallNames(c, "RelationDiagnostics", "Close")
+}
+
+type mockDiagnoseRelationAPI struct {
+	testing.Stub
+	diag *params.CrossModelRelationDiagnostics
+}
+
+func (m *mockDiagnoseRelationAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return nil
+}
+
+func (m *mockDiagnoseRelationAPI) RelationDiagnostics(relationId int) (*params.CrossModelRelationDiagnostics, error) {
+	m.MethodCall(m, "RelationDiagnostics", relationId)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return m.diag, nil
+}
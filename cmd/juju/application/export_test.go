@@ -5,6 +5,7 @@ package application
 
 import (
 	"github.com/juju/cmd"
+	"github.com/juju/utils/clock"
 	"gopkg.in/juju/charmrepo.v2/csclient"
 	"gopkg.in/macaroon-bakery.v1/httpbakery"
 
@@ -46,6 +47,49 @@ func NewAddUnitCommandForTest(api serviceAddUnitAPI) cmd.Command {
 	})
 }
 
+// NewAffinityCommandForTest returns an affinity command with the api
+// provided as specified.
+func NewAffinityCommandForTest(api affinityAPI, store jujuclient.ClientStore) modelcmd.ModelCommand {
+	cmd := &affinityCommand{affinityCommandBase: affinityCommandBase{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewSetAffinityCommandForTest returns a set-affinity command with the
+// api provided as specified.
+func NewSetAffinityCommandForTest(api affinityAPI, store jujuclient.ClientStore) modelcmd.ModelCommand {
+	cmd := &setAffinityCommand{affinityCommandBase: affinityCommandBase{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewMoveUnitCommandForTest returns a move-unit command with the api
+// and clock provided as specified.
+func NewMoveUnitCommandForTest(api moveUnitAPI, clock clock.Clock, store jujuclient.ClientStore) modelcmd.ModelCommand {
+	cmd := &moveUnitCommand{api: api, clock: clock}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewRemoveApplicationCommandForTest returns a remove-application
+// command with the apis and storage exporter provided as specified.
+func NewRemoveApplicationCommandForTest(
+	api removeApplicationAPI,
+	apiVersion int,
+	storageAPI storageDetailsLister,
+	newStorageExporter NewStorageExporterFunc,
+	store jujuclient.ClientStore,
+) modelcmd.ModelCommand {
+	cmd := &removeApplicationCommand{
+		api:                api,
+		apiVersion:         apiVersion,
+		storageAPI:         storageAPI,
+		NewStorageExporter: newStorageExporter,
+	}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
 // NewAddRelationCommandForTest returns an AddRelationCommand with the api provided as specified.
 func NewAddRelationCommandForTest(addAPI applicationAddRelationAPI, consumeAPI applicationConsumeDetailsAPI) modelcmd.ModelCommand {
 	cmd := &addRelationCommand{addRelationAPI: addAPI, consumeDetailsAPI: consumeAPI}
@@ -90,6 +134,22 @@ func NewSuspendRelationCommandForTest(api SetRelationSuspendedAPI) modelcmd.Mode
 	return modelcmd.Wrap(cmd)
 }
 
+// NewShowRelationCommandForTest returns a ShowRelationCommand with the api provided as specified.
+func NewShowRelationCommandForTest(api ShowRelationAPI) modelcmd.ModelCommand {
+	cmd := &showRelationCommand{newAPIFunc: func() (ShowRelationAPI, error) {
+		return api, nil
+	}}
+	return modelcmd.Wrap(cmd)
+}
+
+// NewDiagnoseRelationCommandForTest returns a DiagnoseRelationCommand with the api provided as specified.
+func NewDiagnoseRelationCommandForTest(api DiagnoseRelationAPI) modelcmd.ModelCommand {
+	cmd := &diagnoseRelationCommand{newAPIFunc: func() (DiagnoseRelationAPI, error) {
+		return api, nil
+	}}
+	return modelcmd.Wrap(cmd)
+}
+
 // NewResumeRelationCommandForTest returns a ResumeRelationCommand with the api provided as specified.
 func NewResumeRelationCommandForTest(api SetRelationSuspendedAPI) modelcmd.ModelCommand {
 	cmd := &resumeRelationCommand{newAPIFunc: func() (SetRelationSuspendedAPI, error) {
@@ -6,8 +6,10 @@ package application
 import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 
 	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/modelcmd"
 )
@@ -19,8 +21,19 @@ var usageExposeDetails = `
 Adjusts the firewall rules and any relevant security mechanisms of the
 cloud to allow public access to the application.
 
+The ports opened for particular endpoints of the application's charm
+can be exposed to a restricted set of spaces and CIDRs, by naming the
+endpoints with --endpoints and the allowed sources with --to-spaces and
+--to-cidrs. Without --endpoints, the sources apply to all endpoints
+without settings of their own, and to ports not opened for any
+endpoint. Without --to-spaces or --to-cidrs, the ports are exposed to
+0.0.0.0/0. Settings for endpoints are merged into any existing
+settings.
+
 Examples:
     juju expose wordpress
+    juju expose mysql --endpoints server-admin --to-cidrs 10.0.0.0/8
+    juju expose mysql --endpoints server --to-spaces public,dmz
 
 See also: 
     unexpose`[1:]
@@ -34,6 +47,9 @@ func NewExposeCommand() modelcmd.ModelCommand {
 type exposeCommand struct {
 	modelcmd.ModelCommandBase
 	ApplicationName string
+	Endpoints       []string
+	ToSpaces        []string
+	ToCIDRs         []string
 }
 
 func (c *exposeCommand) Info() *cmd.Info {
@@ -45,6 +61,13 @@ func (c *exposeCommand) Info() *cmd.Info {
 	}
 }
 
+func (c *exposeCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.Var(cmd.NewStringsValue(nil, &c.Endpoints), "endpoints", "Comma-separated list of endpoints to expose")
+	f.Var(cmd.NewStringsValue(nil, &c.ToSpaces), "to-spaces", "Comma-separated list of spaces to expose the endpoints to")
+	f.Var(cmd.NewStringsValue(nil, &c.ToCIDRs), "to-cidrs", "Comma-separated list of CIDRs to expose the endpoints to")
+}
+
 func (c *exposeCommand) Init(args []string) error {
 	if len(args) == 0 {
 		return errors.New("no application name specified")
@@ -57,6 +80,8 @@ type serviceExposeAPI interface {
 	Close() error
 	Expose(serviceName string) error
 	Unexpose(serviceName string) error
+	ExposeEndpoints(serviceName string, exposedEndpoints map[string]params.ExposedEndpoint) error
+	UnexposeEndpoints(serviceName string, endpoints []string) error
 }
 
 func (c *exposeCommand) getAPI() (serviceExposeAPI, error) {
@@ -75,5 +100,22 @@ func (c *exposeCommand) Run(_ *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
-	return block.ProcessBlockedError(client.Expose(c.ApplicationName), block.BlockChange)
+	if len(c.Endpoints) == 0 && len(c.ToSpaces) == 0 && len(c.ToCIDRs) == 0 {
+		return block.ProcessBlockedError(client.Expose(c.ApplicationName), block.BlockChange)
+	}
+	endpoints := c.Endpoints
+	if len(endpoints) == 0 {
+		// The settings apply to all endpoints without settings
+		// of their own.
+		endpoints = []string{""}
+	}
+	exposed := make(map[string]params.ExposedEndpoint, len(endpoints))
+	for _, endpoint := range endpoints {
+		exposed[endpoint] = params.ExposedEndpoint{
+			ExposeToSpaces: c.ToSpaces,
+			ExposeToCIDRs:  c.ToCIDRs,
+		}
+	}
+	err = client.ExposeEndpoints(c.ApplicationName, exposed)
+	return block.ProcessBlockedError(err, block.BlockChange)
 }
@@ -11,6 +11,7 @@ import (
 
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
 )
@@ -55,6 +56,23 @@ func (s *ExposeSuite) TestExpose(c *gc.C) {
 	})
 }
 
+func (s *ExposeSuite) TestExposeEndpoints(c *gc.C) {
+	s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "mysql"})
+
+	err := runExpose(c, "mysql", "--endpoints", "server-admin", "--to-cidrs", "10.0.0.0/8,192.168.0.0/16")
+	c.Assert(err, jc.ErrorIsNil)
+	err = runExpose(c, "mysql", "--to-cidrs", "172.16.0.0/12")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertExposed(c, "mysql")
+
+	app, err := s.State.Application("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(app.ExposedEndpoints(), jc.DeepEquals, map[string]state.ExposedEndpoint{
+		"":             {ExposeToCIDRs: []string{"172.16.0.0/12"}},
+		"server-admin": {ExposeToCIDRs: []string{"10.0.0.0/8", "192.168.0.0/16"}},
+	})
+}
+
 func (s *ExposeSuite) TestBlockExpose(c *gc.C) {
 	s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "some-application-name"})
 
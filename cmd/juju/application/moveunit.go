@@ -0,0 +1,203 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"os"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/unitmoves"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/instance"
+)
+
+// moveUnitPollInterval is how often move-unit checks on the progress
+// of the move while waiting for it to finish.
+const moveUnitPollInterval = 2 * time.Second
+
+var usageMoveUnitSummary = `
+Moves a unit to another machine.`[1:]
+
+var usageMoveUnitDetails = `
+Moves a unit of an IAAS model to another machine. Units cannot change
+machines, so the move deploys a replacement unit of the same application
+on the target machine, waits for it to start and join each of the
+original unit's relations, and then removes the original unit. Storage
+that can be detached from the original unit is attached to the
+replacement once the original unit has gone.
+
+The target is chosen with --to, which takes the same placement
+directives as add-unit; a new machine is provisioned if it is omitted.
+
+The command waits for the move to finish, reporting each phase as it is
+reached. With --no-wait it returns once the move has started; running
+the command again with --status reports the progress of the move.
+
+Examples:
+    juju move-unit mysql/0
+    juju move-unit mysql/0 --to 3
+    juju move-unit mysql/0 --to zone=us-east-1a --no-wait
+    juju move-unit mysql/0 --status
+
+See also:
+    add-unit
+    remove-unit`[1:]
+
+// NewMoveUnitCommand returns a command which moves a unit to another
+// machine.
+func NewMoveUnitCommand() modelcmd.ModelCommand {
+	return modelcmd.Wrap(&moveUnitCommand{})
+}
+
+type moveUnitAPI interface {
+	Close() error
+	MoveUnit(string, *instance.Placement) (params.UnitMove, error)
+	UnitMove(string) (params.UnitMove, error)
+}
+
+type moveUnitCommand struct {
+	modelcmd.ModelCommandBase
+	api   moveUnitAPI
+	clock clock.Clock
+
+	unitName      string
+	placementSpec string
+	placement     *instance.Placement
+	noWait        bool
+	status        bool
+}
+
+// Info implements Command.
+func (c *moveUnitCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "move-unit",
+		Args:    "<unit>",
+		Purpose: usageMoveUnitSummary,
+		Doc:     usageMoveUnitDetails,
+	}
+}
+
+// SetFlags implements Command.
+func (c *moveUnitCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.placementSpec, "to", "", "The machine and/or container to move the unit to")
+	f.BoolVar(&c.noWait, "no-wait", false, "Return once the move has started")
+	f.BoolVar(&c.status, "status", false, "Report the progress of the unit's most recent move")
+}
+
+// Init implements Command.
+func (c *moveUnitCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.Errorf("no unit specified")
+	}
+	if !names.IsValidUnit(args[0]) {
+		return errors.Errorf("invalid unit name %q", args[0])
+	}
+	c.unitName = args[0]
+	if c.status && (c.placementSpec != "" || c.noWait) {
+		return errors.New("cannot specify --status with --to or --no-wait")
+	}
+	placement, err := parsePlacement(c.placementSpec)
+	if err != nil {
+		return err
+	}
+	c.placement = placement
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *moveUnitCommand) getAPI() (moveUnitAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return unitmoves.NewClient(root), nil
+}
+
+// Run implements Command.
+func (c *moveUnitCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if c.status {
+		move, err := client.UnitMove(c.unitName)
+		if err != nil {
+			return err
+		}
+		reportUnitMove(ctx, move)
+		return unitMoveError(move)
+	}
+
+	move, err := client.MoveUnit(c.unitName, c.placement)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	replacement, err := names.ParseUnitTag(move.ReplacementTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("moving unit %s to unit %s", c.unitName, replacement.Id())
+	if c.noWait {
+		return nil
+	}
+
+	clk := c.clock
+	if clk == nil {
+		clk = clock.WallClock
+	}
+	interrupted := make(chan os.Signal, 1)
+	ctx.InterruptNotify(interrupted)
+	defer ctx.StopInterruptNotify(interrupted)
+	last := params.UnitMove{}
+	for {
+		if move.Phase != last.Phase || move.Message != last.Message {
+			reportUnitMove(ctx, move)
+			last = move
+		}
+		if unitMoveFinished(move) {
+			return unitMoveError(move)
+		}
+		select {
+		case <-interrupted:
+			ctx.Infof("stopped waiting; the move continues in the background")
+			return nil
+		case <-clk.After(moveUnitPollInterval):
+		}
+		if move, err = client.UnitMove(c.unitName); err != nil {
+			return err
+		}
+	}
+}
+
+func reportUnitMove(ctx *cmd.Context, move params.UnitMove) {
+	if move.Message == "" {
+		ctx.Infof("%s", move.Phase)
+		return
+	}
+	ctx.Infof("%s: %s", move.Phase, move.Message)
+}
+
+func unitMoveFinished(move params.UnitMove) bool {
+	return move.Phase == "done" || move.Phase == "failed"
+}
+
+func unitMoveError(move params.UnitMove) error {
+	if move.Phase == "failed" {
+		return cmd.ErrSilent
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/application"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/testing"
+)
+
+type MoveUnitSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	api *fakeMoveUnitAPI
+}
+
+var _ = gc.Suite(&MoveUnitSuite{})
+
+func (s *MoveUnitSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = &fakeMoveUnitAPI{
+		started: params.UnitMove{
+			UnitTag:        "unit-mysql-0",
+			ReplacementTag: "unit-mysql-1",
+			Phase:          "provisioning",
+			Message:        "waiting for machine 3",
+		},
+	}
+}
+
+func (s *MoveUnitSuite) runMoveUnit(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := application.NewMoveUnitCommandForTest(s.api, immediateClock{}, application.NewMockStore())
+	return cmdtesting.RunCommand(c, command, args...)
+}
+
+func (s *MoveUnitSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{},
+		err:  `no unit specified`,
+	}, {
+		args: []string{"mysql"},
+		err:  `invalid unit name "mysql"`,
+	}, {
+		args: []string{"mysql/0", "--to", "#:bad"},
+		err:  `invalid --to parameter "#:bad"`,
+	}, {
+		args: []string{"mysql/0", "--status", "--no-wait"},
+		err:  `cannot specify --status with --to or --no-wait`,
+	}, {
+		args: []string{"mysql/0", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"mysql/0", "--to", "3"},
+	}, {
+		args: []string{"mysql/0", "--status"},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := application.NewMoveUnitCommandForTest(s.api, immediateClock{}, application.NewMockStore())
+		err := cmdtesting.InitCommand(command, test.args)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *MoveUnitSuite) TestMoveUnitWaits(c *gc.C) {
+	s.api.progress = []params.UnitMove{
+		s.api.started,
+		{Phase: "deploying", Message: "waiting for unit mysql/1 to start"},
+		{Phase: "joining"},
+		{Phase: "retiring", Message: "waiting for unit mysql/0 to be removed"},
+		{Phase: "done", Message: "moved to unit mysql/1 on machine 3"},
+	}
+	ctx, err := s.runMoveUnit(c, "mysql/0", "--to", "3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+moving unit mysql/0 to unit mysql/1
+provisioning: waiting for machine 3
+deploying: waiting for unit mysql/1 to start
+joining
+retiring: waiting for unit mysql/0 to be removed
+done: moved to unit mysql/1 on machine 3
+`[1:])
+	s.api.CheckCall(c, 0, "MoveUnit", "mysql/0", &instance.Placement{Scope: "#", Directive: "3"})
+	s.api.CheckCallNames(c, "MoveUnit", "UnitMove", "UnitMove", "UnitMove", "UnitMove", "UnitMove", "Close")
+}
+
+func (s *MoveUnitSuite) TestMoveUnitFailed(c *gc.C) {
+	s.api.progress = []params.UnitMove{
+		{Phase: "failed", Message: "cannot provision machine 3: no capacity"},
+	}
+	ctx, err := s.runMoveUnit(c, "mysql/0")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+moving unit mysql/0 to unit mysql/1
+provisioning: waiting for machine 3
+failed: cannot provision machine 3: no capacity
+`[1:])
+	s.api.CheckCall(c, 0, "MoveUnit", "mysql/0", (*instance.Placement)(nil))
+}
+
+func (s *MoveUnitSuite) TestMoveUnitNoWait(c *gc.C) {
+	ctx, err := s.runMoveUnit(c, "mysql/0", "--no-wait")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "moving unit mysql/0 to unit mysql/1\n")
+	s.api.CheckCallNames(c, "MoveUnit", "Close")
+}
+
+func (s *MoveUnitSuite) TestMoveUnitStatus(c *gc.C) {
+	s.api.progress = []params.UnitMove{
+		{Phase: "joining", Message: "waiting for unit mysql/1 to join wordpress:db mysql:server"},
+	}
+	ctx, err := s.runMoveUnit(c, "mysql/0", "--status")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "joining: waiting for unit mysql/1 to join wordpress:db mysql:server\n")
+	s.api.CheckCallNames(c, "UnitMove", "Close")
+}
+
+func (s *MoveUnitSuite) TestMoveUnitBlocked(c *gc.C) {
+	s.api.SetErrors(&params.Error{Code: params.CodeOperationBlocked, Message: "TestBlockMoveUnit"})
+	_, err := s.runMoveUnit(c, "mysql/0")
+	testing.AssertOperationWasBlocked(c, err, ".*TestBlockMoveUnit.*")
+}
+
+func (s *MoveUnitSuite) TestMoveUnitError(c *gc.C) {
+	s.api.SetErrors(errors.AlreadyExistsf("move of unit %q", "mysql/0"))
+	_, err := s.runMoveUnit(c, "mysql/0")
+	c.Assert(err, gc.ErrorMatches, `move of unit "mysql/0" already exists`)
+}
+
+type fakeMoveUnitAPI struct {
+	jujutesting.Stub
+	started  params.UnitMove
+	progress []params.UnitMove
+}
+
+func (f *fakeMoveUnitAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeMoveUnitAPI) MoveUnit(unitName string, placement *instance.Placement) (params.UnitMove, error) {
+	f.MethodCall(f, "MoveUnit", unitName, placement)
+	return f.started, f.NextErr()
+}
+
+func (f *fakeMoveUnitAPI) UnitMove(unitName string) (params.UnitMove, error) {
+	f.MethodCall(f, "UnitMove", unitName)
+	if len(f.progress) == 0 {
+		return params.UnitMove{}, errors.NotFoundf("move of unit %q", unitName)
+	}
+	move := f.progress[0]
+	f.progress = f.progress[1:]
+	return move, f.NextErr()
+}
+
+// immediateClock is a clock whose timers fire straight away, so that
+// move-unit does not wait between polls.
+type immediateClock struct {
+	clock.Clock
+}
+
+func (immediateClock) After(time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	ch <- time.Time{}
+	return ch
+}
@@ -4,6 +4,11 @@
 package application
 
 import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
@@ -11,6 +16,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/api/storage"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/modelcmd"
@@ -21,11 +27,45 @@ func NewRemoveApplicationCommand() cmd.Command {
 	return modelcmd.Wrap(&removeApplicationCommand{})
 }
 
+// NewRemoveApplicationCommandWithExporter returns a command which
+// removes an application, and which can export the contents of its
+// filesystems first with the exporter returned by newStorageExporter.
+func NewRemoveApplicationCommandWithExporter(newStorageExporter NewStorageExporterFunc) cmd.Command {
+	return modelcmd.Wrap(&removeApplicationCommand{
+		NewStorageExporter: newStorageExporter,
+	})
+}
+
+// StorageExporter streams the contents of filesystems attached to
+// units out of the model.
+type StorageExporter interface {
+	// ExportStorage writes the contents of the filesystem mounted at
+	// location on the machine of the given unit to w, as a gzipped
+	// tar archive.
+	ExportStorage(unit, location string, w io.Writer) error
+
+	// Close releases the exporter's resources.
+	Close() error
+}
+
+// NewStorageExporterFunc returns a StorageExporter for the model of
+// the given command.
+type NewStorageExporterFunc func(modelcmd.ModelCommandBase) (StorageExporter, error)
+
 // removeServiceCommand causes an existing application to be destroyed.
 type removeApplicationCommand struct {
 	modelcmd.ModelCommandBase
 	DestroyStorage   bool
+	RetainStorage    bool
+	ExportData       string
 	ApplicationNames []string
+
+	// NewStorageExporter returns the exporter used for --export-data.
+	NewStorageExporter NewStorageExporterFunc
+
+	api        removeApplicationAPI
+	apiVersion int
+	storageAPI storageDetailsLister
 }
 
 var helpSummaryRmApp = `
@@ -40,9 +80,25 @@ before removing them. Removing units which are co-located with units of
 other charms or a Juju controller will not result in the removal of the
 machine.
 
+Storage attached to the application's units is detached and left in the
+model, unless it cannot be detached or --destroy-storage is given, in
+which case it is destroyed along with the units. With --retain-storage,
+all of the storage is detached and kept, marked with the name of the
+application it was retained from; the application is not removed if any
+of its storage cannot be detached.
+
+The contents of filesystems attached to the units can be saved before
+the application is removed with --export-data, which writes a gzipped
+tar archive for each filesystem into the given directory, named for the
+unit and the storage. Nothing is removed if an export fails. Block
+storage cannot be exported, so --export-data requires --retain-storage
+if the application has any.
+
 Examples:
     juju remove-application hadoop
-    juju remove-application -m test-model mariadb`[1:]
+    juju remove-application -m test-model mariadb
+    juju remove-application --retain-storage postgresql
+    juju remove-application --export-data ./pgdata-backup postgresql`[1:]
 
 func (c *removeApplicationCommand) Info() *cmd.Info {
 	return &cmd.Info{
@@ -56,6 +112,8 @@ func (c *removeApplicationCommand) Info() *cmd.Info {
 func (c *removeApplicationCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ModelCommandBase.SetFlags(f)
 	f.BoolVar(&c.DestroyStorage, "destroy-storage", false, "Destroy storage attached to application units")
+	f.BoolVar(&c.RetainStorage, "retain-storage", false, "Detach and keep all storage attached to application units")
+	f.StringVar(&c.ExportData, "export-data", "", "Save the contents of application filesystems to the given directory before removal")
 }
 
 func (c *removeApplicationCommand) Init(args []string) error {
@@ -67,6 +125,12 @@ func (c *removeApplicationCommand) Init(args []string) error {
 			return errors.Errorf("invalid application name %q", arg)
 		}
 	}
+	if c.DestroyStorage && c.RetainStorage {
+		return errors.New("cannot specify both --destroy-storage and --retain-storage")
+	}
+	if c.ExportData != "" && c.DestroyStorage {
+		return errors.New("cannot specify both --export-data and --destroy-storage")
+	}
 	c.ApplicationNames = args
 	return nil
 }
@@ -81,7 +145,15 @@ type removeApplicationAPI interface {
 	ModelUUID() string
 }
 
+type storageDetailsLister interface {
+	Close() error
+	ListStorageDetails() ([]params.StorageDetails, error)
+}
+
 func (c *removeApplicationCommand) getAPI() (removeApplicationAPI, int, error) {
+	if c.api != nil {
+		return c.api, c.apiVersion, nil
+	}
 	root, err := c.NewAPIRoot()
 	if err != nil {
 		return nil, -1, errors.Trace(err)
@@ -90,6 +162,17 @@ func (c *removeApplicationCommand) getAPI() (removeApplicationAPI, int, error) {
 	return application.NewClient(root), version, nil
 }
 
+func (c *removeApplicationCommand) getStorageAPI() (storageDetailsLister, error) {
+	if c.storageAPI != nil {
+		return c.storageAPI, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return storage.NewClient(root), nil
+}
+
 func (c *removeApplicationCommand) Run(ctx *cmd.Context) error {
 	client, apiVersion, err := c.getAPI()
 	if err != nil {
@@ -103,9 +186,139 @@ func (c *removeApplicationCommand) Run(ctx *cmd.Context) error {
 	if c.DestroyStorage && apiVersion < 5 {
 		return errors.New("--destroy-storage is not supported by this controller")
 	}
+	if c.RetainStorage && apiVersion < 8 {
+		return errors.New("--retain-storage is not supported by this controller")
+	}
+	if c.ExportData != "" {
+		if err := c.exportData(ctx); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	return c.removeApplications(ctx, client)
 }
 
+// storageExport describes storage attached to a unit of an
+// application being removed.
+type storageExport struct {
+	unit     names.UnitTag
+	storage  names.StorageTag
+	kind     params.StorageKind
+	location string
+}
+
+// fileName returns the name of the archive that the storage is
+// exported to.
+func (e storageExport) fileName() string {
+	return strings.Replace(e.unit.Id(), "/", "-", -1) + "-" +
+		strings.Replace(e.storage.Id(), "/", "-", -1) + ".tar.gz"
+}
+
+// exportData saves the contents of the filesystems attached to units
+// of the applications being removed into c.ExportData. It fails,
+// before anything is removed, if any of the storage cannot be saved.
+func (c *removeApplicationCommand) exportData(ctx *cmd.Context) error {
+	if c.NewStorageExporter == nil {
+		return errors.New("--export-data is not supported by this client")
+	}
+	storageAPI, err := c.getStorageAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer storageAPI.Close()
+	details, err := storageAPI.ListStorageDetails()
+	if err != nil {
+		return errors.Annotate(err, "cannot list storage")
+	}
+	exports := c.storageExports(details)
+	for _, e := range exports {
+		if e.kind != params.StorageKindFilesystem && !c.RetainStorage {
+			return errors.Errorf(
+				"cannot export %s: only filesystems can be exported; use --retain-storage to keep it",
+				names.ReadableString(e.storage),
+			)
+		}
+	}
+	if len(exports) == 0 {
+		ctx.Infof("no storage to export")
+		return nil
+	}
+	if err := os.MkdirAll(c.ExportData, 0700); err != nil {
+		return errors.Annotate(err, "cannot create export directory")
+	}
+	exporter, err := c.NewStorageExporter(c.ModelCommandBase)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer exporter.Close()
+	for _, e := range exports {
+		if e.kind != params.StorageKindFilesystem {
+			continue
+		}
+		path := filepath.Join(c.ExportData, e.fileName())
+		ctx.Infof("exporting %s from %s to %s", names.ReadableString(e.storage), names.ReadableString(e.unit), path)
+		if err := exportStorage(exporter, e, path); err != nil {
+			return errors.Annotatef(err, "cannot export %s", names.ReadableString(e.storage))
+		}
+	}
+	return nil
+}
+
+// storageExports returns the storage attached to units of the
+// applications being removed.
+func (c *removeApplicationCommand) storageExports(details []params.StorageDetails) []storageExport {
+	removing := make(map[string]bool)
+	for _, name := range c.ApplicationNames {
+		removing[name] = true
+	}
+	var exports []storageExport
+	for _, d := range details {
+		storageTag, err := names.ParseStorageTag(d.StorageTag)
+		if err != nil {
+			logger.Warningf("%s", err)
+			continue
+		}
+		for _, attachment := range d.Attachments {
+			unitTag, err := names.ParseUnitTag(attachment.UnitTag)
+			if err != nil {
+				logger.Warningf("%s", err)
+				continue
+			}
+			appName, err := names.UnitApplication(unitTag.Id())
+			if err != nil || !removing[appName] {
+				continue
+			}
+			exports = append(exports, storageExport{
+				unit:     unitTag,
+				storage:  storageTag,
+				kind:     d.Kind,
+				location: attachment.Location,
+			})
+		}
+	}
+	return exports
+}
+
+// exportStorage writes the contents of the exported storage to a new
+// file at path, which is removed again if the export fails.
+func exportStorage(exporter StorageExporter, e storageExport, path string) error {
+	if e.location == "" {
+		return errors.Errorf("%s is not yet mounted", names.ReadableString(e.storage))
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = exporter.ExportStorage(e.unit.Id(), e.location, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return errors.Trace(err)
+	}
+	return nil
+}
+
 // TODO(axw) 2017-03-16 #1673323
 // Drop this in Juju 3.0.
 func (c *removeApplicationCommand) removeApplicationsDeprecated(
@@ -128,6 +341,7 @@ func (c *removeApplicationCommand) removeApplications(
 	results, err := client.DestroyApplications(application.DestroyApplicationsParams{
 		Applications:   c.ApplicationNames,
 		DestroyStorage: c.DestroyStorage,
+		RetainStorage:  c.RetainStorage,
 	})
 	if err := block.ProcessBlockedError(err, block.BlockRemove); err != nil {
 		return errors.Trace(err)
@@ -163,6 +377,10 @@ func (c *removeApplicationCommand) removeApplications(
 				logger.Warningf("%s", err)
 				continue
 			}
+			if c.RetainStorage {
+				ctx.Infof("- will retain %s", names.ReadableString(storageTag))
+				continue
+			}
 			ctx.Infof("- will detach %s", names.ReadableString(storageTag))
 		}
 	}
@@ -0,0 +1,230 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6"
+
+	apiapplication "github.com/juju/juju/api/application"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/application"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/testing"
+)
+
+type RemoveApplicationStorageSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	api        *fakeRemoveApplicationAPI
+	storageAPI *fakeStorageLister
+	exporter   *fakeStorageExporter
+	dir        string
+}
+
+var _ = gc.Suite(&RemoveApplicationStorageSuite{})
+
+func (s *RemoveApplicationStorageSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = &fakeRemoveApplicationAPI{
+		results: []params.DestroyApplicationResult{{
+			Info: &params.DestroyApplicationInfo{
+				DetachedStorage: []params.Entity{{Tag: "storage-pgdata-0"}},
+			},
+		}},
+	}
+	s.storageAPI = &fakeStorageLister{
+		details: []params.StorageDetails{{
+			StorageTag: "storage-pgdata-0",
+			Kind:       params.StorageKindFilesystem,
+			Attachments: map[string]params.StorageAttachmentDetails{
+				"unit-postgresql-0": {
+					UnitTag:  "unit-postgresql-0",
+					Location: "/srv/pgdata",
+				},
+			},
+		}, {
+			StorageTag: "storage-logs-0",
+			Kind:       params.StorageKindFilesystem,
+			Attachments: map[string]params.StorageAttachmentDetails{
+				"unit-mysql-0": {
+					UnitTag:  "unit-mysql-0",
+					Location: "/srv/logs",
+				},
+			},
+		}},
+	}
+	s.exporter = &fakeStorageExporter{}
+	s.dir = filepath.Join(c.MkDir(), "export")
+}
+
+func (s *RemoveApplicationStorageSuite) runRemoveApplication(c *gc.C, args ...string) (*cmd.Context, error) {
+	newExporter := func(modelcmd.ModelCommandBase) (application.StorageExporter, error) {
+		return s.exporter, nil
+	}
+	command := application.NewRemoveApplicationCommandForTest(
+		s.api, 8, s.storageAPI, newExporter, application.NewMockStore(),
+	)
+	return cmdtesting.RunCommand(c, command, args...)
+}
+
+func (s *RemoveApplicationStorageSuite) TestInit(c *gc.C) {
+	_, err := s.runRemoveApplication(c, "postgresql", "--retain-storage", "--destroy-storage")
+	c.Assert(err, gc.ErrorMatches, "cannot specify both --destroy-storage and --retain-storage")
+	_, err = s.runRemoveApplication(c, "postgresql", "--export-data", s.dir, "--destroy-storage")
+	c.Assert(err, gc.ErrorMatches, "cannot specify both --export-data and --destroy-storage")
+}
+
+func (s *RemoveApplicationStorageSuite) TestRetainStorage(c *gc.C) {
+	ctx, err := s.runRemoveApplication(c, "postgresql", "--retain-storage")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+removing application postgresql
+- will retain storage pgdata/0
+`[1:])
+	s.api.CheckCall(c, 0, "DestroyApplications", apiapplication.DestroyApplicationsParams{
+		Applications:  []string{"postgresql"},
+		RetainStorage: true,
+	})
+}
+
+func (s *RemoveApplicationStorageSuite) TestRetainStorageNotSupported(c *gc.C) {
+	command := application.NewRemoveApplicationCommandForTest(
+		s.api, 7, s.storageAPI, nil, application.NewMockStore(),
+	)
+	_, err := cmdtesting.RunCommand(c, command, "postgresql", "--retain-storage")
+	c.Assert(err, gc.ErrorMatches, "--retain-storage is not supported by this controller")
+	s.api.CheckNoCalls(c)
+}
+
+func (s *RemoveApplicationStorageSuite) TestExportData(c *gc.C) {
+	s.exporter.data = "archive"
+	ctx, err := s.runRemoveApplication(c, "postgresql", "--export-data", s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	path := filepath.Join(s.dir, "postgresql-0-pgdata-0.tar.gz")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, ""+
+		"exporting storage pgdata/0 from unit postgresql/0 to "+path+"\n"+
+		"removing application postgresql\n"+
+		"- will detach storage pgdata/0\n")
+	s.exporter.CheckCalls(c, []jujutesting.StubCall{
+		{"ExportStorage", []interface{}{"postgresql/0", "/srv/pgdata"}},
+		{"Close", nil},
+	})
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "archive")
+	s.api.CheckCallNames(c, "DestroyApplications", "Close")
+}
+
+func (s *RemoveApplicationStorageSuite) TestExportDataFailure(c *gc.C) {
+	s.exporter.SetErrors(errors.New("tar: no space left on device"))
+	_, err := s.runRemoveApplication(c, "postgresql", "--export-data", s.dir)
+	c.Assert(err, gc.ErrorMatches, "cannot export storage pgdata/0: tar: no space left on device")
+	_, err = os.Stat(filepath.Join(s.dir, "postgresql-0-pgdata-0.tar.gz"))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+	s.api.CheckCallNames(c, "Close")
+}
+
+func (s *RemoveApplicationStorageSuite) TestExportDataBlockStorage(c *gc.C) {
+	s.storageAPI.details[0].Kind = params.StorageKindBlock
+	_, err := s.runRemoveApplication(c, "postgresql", "--export-data", s.dir)
+	c.Assert(err, gc.ErrorMatches,
+		"cannot export storage pgdata/0: only filesystems can be exported; use --retain-storage to keep it")
+	s.exporter.CheckNoCalls(c)
+	s.api.CheckCallNames(c, "Close")
+
+	ctx, err := s.runRemoveApplication(c, "postgresql", "--export-data", s.dir, "--retain-storage")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+removing application postgresql
+- will retain storage pgdata/0
+`[1:])
+	s.exporter.CheckCallNames(c, "Close")
+}
+
+func (s *RemoveApplicationStorageSuite) TestExportDataNoStorage(c *gc.C) {
+	ctx, err := s.runRemoveApplication(c, "wordpress", "--export-data", s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Matches, "no storage to export\n(.|\n)*")
+	s.exporter.CheckNoCalls(c)
+}
+
+type fakeRemoveApplicationAPI struct {
+	jujutesting.Stub
+	results []params.DestroyApplicationResult
+}
+
+func (f *fakeRemoveApplicationAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeRemoveApplicationAPI) DestroyApplications(args apiapplication.DestroyApplicationsParams) ([]params.DestroyApplicationResult, error) {
+	f.MethodCall(f, "DestroyApplications", args)
+	return f.results, f.NextErr()
+}
+
+func (f *fakeRemoveApplicationAPI) DestroyDeprecated(appName string) error {
+	f.MethodCall(f, "DestroyDeprecated", appName)
+	return f.NextErr()
+}
+
+func (f *fakeRemoveApplicationAPI) DestroyUnits(args apiapplication.DestroyUnitsParams) ([]params.DestroyUnitResult, error) {
+	f.MethodCall(f, "DestroyUnits", args)
+	return nil, f.NextErr()
+}
+
+func (f *fakeRemoveApplicationAPI) DestroyUnitsDeprecated(unitNames ...string) error {
+	f.MethodCall(f, "DestroyUnitsDeprecated", unitNames)
+	return f.NextErr()
+}
+
+func (f *fakeRemoveApplicationAPI) GetCharmURL(appName string) (*charm.URL, error) {
+	f.MethodCall(f, "GetCharmURL", appName)
+	return nil, f.NextErr()
+}
+
+func (f *fakeRemoveApplicationAPI) ModelUUID() string {
+	return "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+}
+
+type fakeStorageLister struct {
+	details []params.StorageDetails
+}
+
+func (f *fakeStorageLister) Close() error {
+	return nil
+}
+
+func (f *fakeStorageLister) ListStorageDetails() ([]params.StorageDetails, error) {
+	return f.details, nil
+}
+
+type fakeStorageExporter struct {
+	jujutesting.Stub
+	data string
+}
+
+func (f *fakeStorageExporter) ExportStorage(unit, location string, w io.Writer) error {
+	f.MethodCall(f, "ExportStorage", unit, location)
+	if err := f.NextErr(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, f.data)
+	return err
+}
+
+func (f *fakeStorageExporter) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
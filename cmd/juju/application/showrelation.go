@@ -0,0 +1,158 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/api/relationdata"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+var showRelationHelpSummary = `
+Shows the data exchanged over a relation.`[1:]
+
+var showRelationHelpDetails = `
+Shows the settings that each unit in scope in a relation has published
+to the other units in the relation, as read by relation-get. The
+relation is specified using its id, as shown by "juju status --relations".
+
+Settings whose names suggest that they hold secrets, such as passwords,
+tokens and private keys, are redacted and listed under "redacted". They
+are shown by passing --show-secrets, which requires admin access to the
+model.
+
+Examples:
+    juju show-relation 4
+    juju show-relation 4 --show-secrets --format json
+
+See also:
+    add-relation
+    remove-relation
+    status`
+
+// NewShowRelationCommand returns a command to show the data bags of a
+// relation.
+func NewShowRelationCommand() cmd.Command {
+	cmd := &showRelationCommand{}
+	cmd.newAPIFunc = func() (ShowRelationAPI, error) {
+		root, err := cmd.NewAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return relationdata.NewClient(root), nil
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+type showRelationCommand struct {
+	modelcmd.ModelCommandBase
+	out         cmd.Output
+	relationId  int
+	showSecrets bool
+	newAPIFunc  func() (ShowRelationAPI, error)
+}
+
+// ShowRelationAPI defines the API methods that the show-relation
+// command uses.
+type ShowRelationAPI interface {
+	Close() error
+	RelationData(relationId int, reveal bool) (*params.RelationData, error)
+}
+
+// Info implements Command.
+func (c *showRelationCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show-relation",
+		Args:    "<relation-id>",
+		Purpose: showRelationHelpSummary,
+		Doc:     showRelationHelpDetails,
+	}
+}
+
+// SetFlags implements Command.
+func (c *showRelationCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.showSecrets, "show-secrets", false, "Show settings that look like secrets")
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements Command.
+func (c *showRelationCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no relation id specified")
+	}
+	id, err := strconv.Atoi(strings.TrimSpace(args[0]))
+	if err != nil || id < 0 {
+		return errors.NotValidf("relation ID %q", args[0])
+	}
+	c.relationId = id
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.
+func (c *showRelationCommand) Run(ctx *cmd.Context) error {
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	data, err := client.RelationData(c.relationId, c.showSecrets)
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, formatRelationData(data))
+}
+
+type relationData struct {
+	Id           int                             `yaml:"id" json:"id"`
+	Key          string                          `yaml:"key" json:"key"`
+	Applications map[string]relationEndpointData `yaml:"applications" json:"applications"`
+}
+
+type relationEndpointData struct {
+	Endpoint string                      `yaml:"endpoint" json:"endpoint"`
+	Role     string                      `yaml:"role" json:"role"`
+	Units    map[string]relationUnitData `yaml:"units,omitempty" json:"units,omitempty"`
+}
+
+type relationUnitData struct {
+	Settings map[string]interface{} `yaml:"settings" json:"settings"`
+	Redacted []string               `yaml:"redacted,omitempty" json:"redacted,omitempty"`
+}
+
+func formatRelationData(data *params.RelationData) relationData {
+	result := relationData{
+		Id:           data.Id,
+		Key:          data.Key,
+		Applications: make(map[string]relationEndpointData),
+	}
+	for _, ep := range data.Endpoints {
+		endpoint := relationEndpointData{
+			Endpoint: ep.Name,
+			Role:     ep.Role,
+		}
+		for unitName, unit := range ep.Units {
+			if endpoint.Units == nil {
+				endpoint.Units = make(map[string]relationUnitData)
+			}
+			endpoint.Units[unitName] = relationUnitData{
+				Settings: unit.Settings,
+				Redacted: unit.Redacted,
+			}
+		}
+		result.Applications[ep.ApplicationName] = endpoint
+	}
+	return result
+}
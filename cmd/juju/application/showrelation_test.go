@@ -0,0 +1,131 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+type ShowRelationSuite struct {
+	testing.IsolationSuite
+	mockAPI *mockShowRelationAPI
+}
+
+var _ = gc.Suite(&ShowRelationSuite{})
+
+func (s *ShowRelationSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.mockAPI = &mockShowRelationAPI{
+		data: &params.RelationData{
+			Id:  4,
+			Key: "wordpress:db mysql:server",
+			Endpoints: []params.RelationEndpointData{{
+				ApplicationName: "wordpress",
+				Name:            "db",
+				Role:            "requirer",
+				Units: map[string]params.RelationUnitData{
+					"wordpress/0": {
+						Settings: map[string]interface{}{"private-address": "10.0.0.1"},
+					},
+				},
+			}, {
+				ApplicationName: "mysql",
+				Name:            "server",
+				Role:            "provider",
+				Units: map[string]params.RelationUnitData{
+					"mysql/0": {
+						Settings: map[string]interface{}{"user": "wordpress"},
+						Redacted: []string{"password"},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func (s *ShowRelationSuite) runShowRelation(c *gc.C, args ...string) (string, error) {
+	ctx, err := cmdtesting.RunCommand(c, NewShowRelationCommandForTest(s.mockAPI), args...)
+	if err != nil {
+		return "", err
+	}
+	return cmdtesting.Stdout(ctx), nil
+}
+
+func (s *ShowRelationSuite) TestShowRelationInvalidArguments(c *gc.C) {
+	_, err := s.runShowRelation(c)
+	c.Assert(err, gc.ErrorMatches, "no relation id specified")
+
+	_, err = s.runShowRelation(c, "wordpress")
+	c.Assert(err, gc.ErrorMatches, `relation ID "wordpress" not valid`)
+
+	_, err = s.runShowRelation(c, "4", "5")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["5"\]`)
+}
+
+func (s *ShowRelationSuite) TestShowRelation(c *gc.C) {
+	out, err := s.runShowRelation(c, "4")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockAPI.CheckCalls(c, []testing.StubCall{
+		{"RelationData", []interface{}{4, false}},
+		{"Close", nil},
+	})
+	c.Assert(out, gc.Equals, `
+id: 4
+key: wordpress:db mysql:server
+applications:
+  mysql:
+    endpoint: server
+    role: provider
+    units:
+      mysql/0:
+        settings:
+          user: wordpress
+        redacted:
+        - password
+  wordpress:
+    endpoint: db
+    role: requirer
+    units:
+      wordpress/0:
+        settings:
+          private-address: 10.0.0.1
+`[1:])
+}
+
+func (s *ShowRelationSuite) TestShowRelationShowSecrets(c *gc.C) {
+	_, err := s.runShowRelation(c, "4", "--show-secrets")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockAPI.CheckCall(c, 0, "RelationData", 4, true)
+}
+
+func (s *ShowRelationSuite) TestShowRelationFail(c *gc.C) {
+	s.mockAPI.SetErrors(errors.New("boom"))
+	_, err := s.runShowRelation(c, "4")
+	c.Assert(err, gc.ErrorMatches, "boom")
+	s.mockAPI.CheckCallNames(c, "RelationData", "Close")
+}
+
+type mockShowRelationAPI struct {
+	testing.Stub
+	data *params.RelationData
+}
+
+func (m *mockShowRelationAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return nil
+}
+
+func (m *mockShowRelationAPI) RelationData(relationId int, reveal bool) (*params.RelationData, error) {
+	m.MethodCall(m, "RelationData", relationId, reveal)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return m.data, nil
+}
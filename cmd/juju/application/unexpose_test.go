@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/testcharms"
 	"github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
 )
 
 type UnexposeSuite struct {
@@ -65,6 +66,24 @@ func (s *UnexposeSuite) TestUnexpose(c *gc.C) {
 	})
 }
 
+func (s *UnexposeSuite) TestUnexposeEndpoints(c *gc.C) {
+	s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "mysql"})
+
+	err := runExpose(c, "mysql", "--endpoints", "server,server-admin", "--to-cidrs", "10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = runUnexpose(c, "mysql", "--endpoints", "server")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertExposed(c, "mysql", true)
+
+	err = runUnexpose(c, "mysql", "--endpoints", "server")
+	c.Assert(err, gc.ErrorMatches, `cannot unexpose application "mysql": expose settings for endpoint "server" not found`)
+
+	err = runUnexpose(c, "mysql", "--endpoints", "server-admin")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertExposed(c, "mysql", false)
+}
+
 func (s *UnexposeSuite) TestBlockUnexpose(c *gc.C) {
 	ch := testcharms.Repo.CharmArchivePath(s.CharmsPath, "multi-series")
 	err := runDeploy(c, ch, "some-application-name", "--series", "trusty")
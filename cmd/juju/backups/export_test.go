@@ -67,6 +67,12 @@ func NewRemoveCommandForTest() cmd.Command {
 	return modelcmd.Wrap(c)
 }
 
+func NewVerifyCommandForTest() cmd.Command {
+	c := &verifyCommand{}
+	c.Log = &cmd.Log{}
+	return modelcmd.Wrap(c)
+}
+
 func NewRestoreCommandForTest(
 	store jujuclient.ClientStore,
 	api RestoreAPI,
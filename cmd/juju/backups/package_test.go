@@ -115,9 +115,10 @@ func (s *BaseBackupsSuite) checkStd(c *gc.C, ctx *cmd.Context, out, err string)
 }
 
 type fakeAPIClient struct {
-	metaresult *params.BackupsMetadataResult
-	archive    io.ReadCloser
-	err        error
+	metaresult   *params.BackupsMetadataResult
+	verifyresult *params.BackupsVerifyResult
+	archive      io.ReadCloser
+	err          error
 
 	calls []string
 	args  []string
@@ -189,6 +190,16 @@ func (c *fakeAPIClient) Remove(id string) error {
 	return nil
 }
 
+func (c *fakeAPIClient) Verify(id string) (*params.BackupsVerifyResult, error) {
+	c.calls = append(c.calls, "Verify")
+	c.args = append(c.args, "id")
+	c.idArg = id
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.verifyresult, nil
+}
+
 func (c *fakeAPIClient) Close() error {
 	return nil
 }
@@ -0,0 +1,91 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const verifyDoc = `
+verify-backup restores the specified backup into a scratch mongod server
+started on the controller for the purpose, compares it with the live
+controller and reports any discrepancies found. The live controller's
+database is not modified, and the scratch server is stopped and its data
+removed once verification is complete.
+
+Discrepancies such as models created since the backup was taken are
+expected; a backup containing no models, or missing collections, is
+unlikely to be restorable.
+`
+
+// NewVerifyCommand returns a command used to verify that a backup
+// is restorable.
+func NewVerifyCommand() cmd.Command {
+	return modelcmd.Wrap(&verifyCommand{})
+}
+
+// verifyCommand is the sub-command for verifying a backup.
+type verifyCommand struct {
+	CommandBase
+	// ID is the backup ID to verify.
+	ID string
+}
+
+// Info implements Command.Info.
+func (c *verifyCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "verify-backup",
+		Args:    "<ID>",
+		Purpose: "Check that the specified backup can be restored.",
+		Doc:     verifyDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *verifyCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing ID")
+	}
+	id, args := args[0], args[1:]
+	if err := cmd.CheckEmpty(args); err != nil {
+		return errors.Trace(err)
+	}
+	c.ID = id
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *verifyCommand) Run(ctx *cmd.Context) error {
+	if c.Log != nil {
+		if err := c.Log.Start(ctx); err != nil {
+			return err
+		}
+	}
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	result, err := client.Verify(c.ID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	fmt.Fprintf(ctx.Stdout, "restored %d model(s) from backup %v\n", len(result.Models), c.ID)
+	if len(result.Discrepancies) == 0 {
+		fmt.Fprintln(ctx.Stdout, "no discrepancies found")
+		return nil
+	}
+	fmt.Fprintf(ctx.Stdout, "%d discrepancies found:\n", len(result.Discrepancies))
+	for _, discrepancy := range result.Discrepancies {
+		fmt.Fprintf(ctx.Stdout, "  %s\n", discrepancy)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/backups"
+)
+
+type verifySuite struct {
+	BaseBackupsSuite
+	subcommand cmd.Command
+}
+
+var _ = gc.Suite(&verifySuite{})
+
+func (s *verifySuite) SetUpTest(c *gc.C) {
+	s.BaseBackupsSuite.SetUpTest(c)
+	s.subcommand = backups.NewVerifyCommandForTest()
+}
+
+func (s *verifySuite) TestMissingID(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, s.subcommand)
+	c.Check(err, gc.ErrorMatches, "missing ID")
+}
+
+func (s *verifySuite) TestOkay(c *gc.C) {
+	client := s.setSuccess()
+	client.verifyresult = &params.BackupsVerifyResult{
+		Models: []string{"uuid-1", "uuid-2"},
+	}
+	ctx, err := cmdtesting.RunCommand(c, s.subcommand, "spam")
+	c.Check(err, jc.ErrorIsNil)
+	client.Check(c, "spam", "", "Verify")
+	s.checkStd(c, ctx, "restored 2 model(s) from backup spam\nno discrepancies found\n", "")
+}
+
+func (s *verifySuite) TestDiscrepancies(c *gc.C) {
+	client := s.setSuccess()
+	client.verifyresult = &params.BackupsVerifyResult{
+		Models:        []string{"uuid-1"},
+		Discrepancies: []string{`model "new" (uuid-2) missing from backup`},
+	}
+	ctx, err := cmdtesting.RunCommand(c, s.subcommand, "spam")
+	c.Check(err, jc.ErrorIsNil)
+	s.checkStd(c, ctx, ""+
+		"restored 1 model(s) from backup spam\n"+
+		"1 discrepancies found:\n"+
+		"  model \"new\" (uuid-2) missing from backup\n",
+		"")
+}
+
+func (s *verifySuite) TestError(c *gc.C) {
+	s.setFailure("failed!")
+	_, err := cmdtesting.RunCommand(c, s.subcommand, "spam")
+	c.Check(errors.Cause(err), gc.ErrorMatches, "failed!")
+}
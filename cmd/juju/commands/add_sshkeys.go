@@ -6,11 +6,14 @@ package commands
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/gnuflag"
 
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/sshkeys"
 )
 
 var usageAddSSHKeySummary = `
@@ -23,6 +26,11 @@ key of the user who created the model (assuming it is stored in the
 default location ~/.ssh/). Additional keys may be added with this command,
 quoting the entire public key as an argument.
 
+Keys added with this command belong to the current user. They are only
+copied to the model's machines while that user has admin access to the
+model, and are withdrawn from the machines if the user's access is revoked
+or the user is removed.
+
 Examples:
     juju add-ssh-key "ssh-rsa qYfS5LieM79HIOr535ret6xy
     AAAAB3NzaC1yc2EAAAADAQA6fgBAAABAQCygc6Rc9XgHdhQqTJ
@@ -39,6 +47,11 @@ to the command:
 
 juju add-ssh-key "$(cat ~/mykey.pub)"
 
+A key may be given an expiry date with --expires, after which it is
+removed from the model and no longer accepted by its machines:
+
+juju add-ssh-key --expires 2018-12-31 "$(cat ~/mykey.pub)"
+
 See also: 
     ssh-keys
     remove-ssh-key
@@ -54,6 +67,8 @@ type addKeysCommand struct {
 	SSHKeysBase
 	user    string
 	sshKeys []string
+	expires string
+	expiry  time.Time
 }
 
 // Info implements Command.Info.
@@ -66,6 +81,12 @@ func (c *addKeysCommand) Info() *cmd.Info {
 	}
 }
 
+// SetFlags implements Command.SetFlags.
+func (c *addKeysCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.SSHKeysBase.SetFlags(f)
+	f.StringVar(&c.expires, "expires", "", "Date (YYYY-MM-DD) or time (RFC3339) at which the keys expire")
+}
+
 // Init implements Command.Init.
 func (c *addKeysCommand) Init(args []string) error {
 	switch len(args) {
@@ -74,9 +95,29 @@ func (c *addKeysCommand) Init(args []string) error {
 	default:
 		c.sshKeys = args
 	}
+	if c.expires != "" {
+		expiry, err := parseExpiry(c.expires)
+		if err != nil {
+			return err
+		}
+		c.expiry = expiry
+	}
 	return nil
 }
 
+// parseExpiry parses the value of the --expires flag. A date on its own
+// is taken to mean midnight UTC at the start of that day.
+func parseExpiry(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid expiry %q: expected YYYY-MM-DD or RFC3339 time", value)
+	}
+	return t, nil
+}
+
 // Run implements Command.Run.
 func (c *addKeysCommand) Run(context *cmd.Context) error {
 	client, err := c.NewKeyManagerClient()
@@ -84,10 +125,15 @@ func (c *addKeysCommand) Run(context *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
-	// TODO(alexisb) - currently keys are global which is not ideal.
-	// keymanager needs to be updated to allow keys per user
-	c.user = "admin"
-	results, err := client.AddKeys(c.user, c.sshKeys...)
+	c.user = c.apiUser
+	keys := c.sshKeys
+	if !c.expiry.IsZero() {
+		keys = make([]string, len(c.sshKeys))
+		for i, key := range c.sshKeys {
+			keys[i] = sshkeys.WithExpiry(key, c.expiry)
+		}
+	}
+	results, err := client.AddKeys(c.user, keys...)
 	if err != nil {
 		return block.ProcessBlockedError(err, block.BlockChange)
 	}
@@ -418,6 +418,18 @@ func (c *bootstrapCommand) Run(ctx *cmd.Context) (resultErr error) {
 		return errors.Trace(err)
 	}
 
+	// If an earlier bootstrap of the controller was interrupted after
+	// its instance was started, resume it with the same identity.
+	checkpointPath := bootstrapCheckpointPath(cloud.Name, region.Name, c.controllerName)
+	checkpoints := bootstrap.NewCheckpointFile(checkpointPath)
+	checkpoint, err := checkpoints.ReadCheckpoint()
+	if err == nil {
+		ctx.Infof("Resuming interrupted bootstrap of controller %q", c.controllerName)
+		config.useCheckpoint(checkpoint)
+	} else if !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+
 	// Read existing current controller so we can clean up on error.
 	var oldCurrentController string
 	store := c.ClientStore()
@@ -504,7 +516,17 @@ func (c *bootstrapCommand) Run(ctx *cmd.Context) (resultErr error) {
 	// If we error out for any reason, clean up the environment.
 	defer func() {
 		if resultErr != nil {
-			if c.KeepBrokenEnvironment {
+			if _, err := checkpoints.ReadCheckpoint(); err == nil && !c.KeepBrokenEnvironment {
+				logger.Errorf("%v", resultErr)
+				resultErr = cmd.ErrSilent
+				ctx.Infof(`
+bootstrap failed after the controller instance was started.
+The instance has been left running so that the bootstrap can be resumed
+by running the same bootstrap command again. To abandon the bootstrap
+instead, remove %s
+and use your cloud console or equivalent CLI tools to terminate the
+instance and remove remaining resources.`[1:], checkpointPath)
+			} else if c.KeepBrokenEnvironment {
 				ctx.Infof(`
 bootstrap failed but --keep-broken was specified. 
 This means that cloud resources are left behind, but not registered to 
@@ -606,6 +628,9 @@ See `[1:] + "`juju kill-controller`" + `.`)
 			RetryDelay:     config.bootstrap.BootstrapRetryDelay,
 			AddressesDelay: config.bootstrap.BootstrapAddressesDelay,
 		},
+		KeepBroken:  c.KeepBrokenEnvironment,
+		Progress:    bootstrapProgress{ctx},
+		Checkpoints: checkpoints,
 	})
 	if err != nil {
 		return errors.Annotate(err, "failed to bootstrap model")
@@ -843,6 +868,41 @@ type bootstrapConfigs struct {
 	userConfigAttrs          map[string]interface{}
 }
 
+// bootstrapCheckpointPath returns the path of the file holding the
+// checkpoint of an interrupted bootstrap of the named controller in
+// the given cloud and region. Controllers of the same name in other
+// clouds or regions have their own checkpoints.
+func bootstrapCheckpointPath(cloudName, regionName, controllerName string) string {
+	return osenv.JujuXDGDataHomePath("bootstrap", cloudName, regionName, controllerName+".yaml")
+}
+
+// bootstrapProgress reports the phases of a bootstrap to the user.
+type bootstrapProgress struct {
+	ctx *cmd.Context
+}
+
+// PhaseStarted is part of the bootstrap.Progress interface.
+func (p bootstrapProgress) PhaseStarted(phase bootstrap.Phase) {
+	p.ctx.Verbosef("Bootstrap phase %q started", phase)
+}
+
+// PhaseFinished is part of the bootstrap.Progress interface.
+func (p bootstrapProgress) PhaseFinished(phase bootstrap.Phase, err error) {
+	if err != nil {
+		p.ctx.Infof("Bootstrap phase %q failed: %v", phase, err)
+		return
+	}
+	p.ctx.Verbosef("Bootstrap phase %q finished", phase)
+}
+
+// useCheckpoint updates the configs to use the controller and model
+// UUIDs of the bootstrap recorded in the given checkpoint, so that
+// the bootstrap can be resumed.
+func (cfgs bootstrapConfigs) useCheckpoint(checkpoint *bootstrap.Checkpoint) {
+	cfgs.controller[controller.ControllerUUIDKey] = checkpoint.ControllerUUID
+	cfgs.bootstrapModel[config.UUIDKey] = checkpoint.ModelUUID
+}
+
 func (c *bootstrapCommand) bootstrapConfigs(
 	ctx *cmd.Context,
 	cloud jujucloud.Cloud,
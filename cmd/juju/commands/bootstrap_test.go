@@ -572,6 +572,25 @@ func (s *BootstrapSuite) TestBootstrapTimeout(c *gc.C) {
 	c.Assert(bootstrap.args.DialOpts.Timeout, gc.Equals, 99*time.Second)
 }
 
+func (s *BootstrapSuite) TestBootstrapReportsProgress(c *gc.C) {
+	s.patchVersionAndSeries(c, "raring")
+
+	var bootstrap fakeBootstrapFuncs
+	s.PatchValue(&getBootstrapFuncs, func() BootstrapInterface {
+		return &bootstrap
+	})
+	cmdtesting.RunCommand(c, s.newBootstrapCommand(), "dummy", "devcontroller", "--auto-upgrade")
+	c.Assert(bootstrap.args.Progress, gc.FitsTypeOf, bootstrapProgress{})
+	c.Assert(bootstrap.args.Checkpoints, gc.NotNil)
+}
+
+func (s *BootstrapSuite) TestBootstrapCheckpointPath(c *gc.C) {
+	path := bootstrapCheckpointPath("aws", "us-east-1", "ctrl")
+	c.Assert(path, gc.Equals, osenv.JujuXDGDataHomePath("bootstrap", "aws", "us-east-1", "ctrl.yaml"))
+	c.Assert(bootstrapCheckpointPath("aws", "us-west-2", "ctrl"), gc.Not(gc.Equals), path)
+	c.Assert(bootstrapCheckpointPath("google", "us-east-1", "ctrl"), gc.Not(gc.Equals), path)
+}
+
 func (s *BootstrapSuite) TestBootstrapDefaultConfigStripsProcessedAttributes(c *gc.C) {
 	s.patchVersionAndSeries(c, "raring")
 
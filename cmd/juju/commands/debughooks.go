@@ -6,9 +6,13 @@ package commands
 import (
 	"encoding/base64"
 	"fmt"
+	"io"
+	"sort"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 	"github.com/juju/utils/set"
 	"gopkg.in/juju/charm.v6/hooks"
 	"gopkg.in/juju/names.v2"
@@ -18,12 +22,14 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/network/ssh"
+	"github.com/juju/juju/status"
 	unitdebug "github.com/juju/juju/worker/uniter/runner/debug"
 )
 
 func newDebugHooksCommand(hostChecker ssh.ReachableChecker) cmd.Command {
 	c := new(debugHooksCommand)
 	c.getActionAPI = c.newActionsAPI
+	c.getStatusHistoryAPI = c.newStatusHistoryAPI
 	c.setHostChecker(hostChecker)
 	return modelcmd.Wrap(c)
 }
@@ -31,18 +37,39 @@ func newDebugHooksCommand(hostChecker ssh.ReachableChecker) cmd.Command {
 // debugHooksCommand is responsible for launching a ssh shell on a given unit or machine.
 type debugHooksCommand struct {
 	sshCommand
-	hooks []string
+	hooks       []string
+	lastFailure bool
 
-	getActionAPI func() (ActionsAPI, error)
+	getActionAPI        func() (ActionsAPI, error)
+	getStatusHistoryAPI func() (StatusHistoryAPI, error)
 }
 
 const debugHooksDoc = `
 Interactively debug hooks or actions remotely on an application unit.
 
+With --last-failure, no session is started; instead the output, juju-log
+messages and workload status captured when the unit's most recent hook
+failed are shown.
+
+With --via-api, the ssh session is tunnelled through the controller's API
+server rather than made directly to the unit's machine, so that hooks may be
+debugged on machines which have no address reachable from the client. Only
+the API server need be reachable; the session remains encrypted end to end.
+
 See the "juju help ssh" for information about SSH related options
 accepted by the debug-hooks command.
+
+Examples:
+
+    juju debug-hooks mysql/0 config-changed
+    juju debug-hooks --last-failure mysql/0
+    juju debug-hooks --via-api mysql/0 config-changed
 `
 
+// lastFailureHistorySize is the number of unit agent status history
+// entries searched for the last hook failure.
+const lastFailureHistorySize = 50
+
 func (c *debugHooksCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "debug-hooks",
@@ -52,6 +79,12 @@ func (c *debugHooksCommand) Info() *cmd.Info {
 	}
 }
 
+func (c *debugHooksCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.sshCommand.SetFlags(f)
+	f.BoolVar(&c.lastFailure, "last-failure", false, "Show what was captured when the unit's last hook failed")
+	f.BoolVar(&c.viaAPI, "via-api", false, "Tunnel the session through the controller's API server")
+}
+
 func (c *debugHooksCommand) Init(args []string) error {
 	if len(args) < 1 {
 		return errors.Errorf("no unit name specified")
@@ -61,6 +94,16 @@ func (c *debugHooksCommand) Init(args []string) error {
 		return errors.Errorf("%q is not a valid unit name", c.Target)
 	}
 
+	if c.lastFailure {
+		if len(args) > 1 {
+			return errors.New("cannot specify hooks or actions with --last-failure")
+		}
+		if c.viaAPI {
+			return errors.New("cannot specify both --last-failure and --via-api")
+		}
+		return nil
+	}
+
 	// If any of the hooks is "*", then debug all hooks.
 	c.hooks = append([]string{}, args[1:]...)
 	for _, h := range c.hooks {
@@ -80,6 +123,13 @@ type ActionsAPI interface {
 	ApplicationCharmActions(params.Entity) (map[string]params.ActionSpec, error)
 }
 
+// StatusHistoryAPI defines the API used to find a unit's last hook
+// failure.
+type StatusHistoryAPI interface {
+	StatusHistory(kind status.HistoryKind, tag names.Tag, filter status.StatusHistoryFilter) (status.History, error)
+	Close() error
+}
+
 func (c *debugHooksCommand) getApplicationAPI() (charmRelationsAPI, error) {
 	root, err := c.NewAPIRoot()
 	if err != nil {
@@ -96,6 +146,10 @@ func (c *debugHooksCommand) newActionsAPI() (ActionsAPI, error) {
 	return action.NewClient(root), nil
 }
 
+func (c *debugHooksCommand) newStatusHistoryAPI() (StatusHistoryAPI, error) {
+	return c.NewAPIClient()
+}
+
 func (c *debugHooksCommand) validateHooksOrActions() error {
 	if len(c.hooks) == 0 {
 		return nil
@@ -179,6 +233,9 @@ func (c *debugHooksCommand) getValidHooks(appName string) (set.Strings, error) {
 // and connects to it via SSH to execute the debug-hooks
 // script.
 func (c *debugHooksCommand) Run(ctx *cmd.Context) error {
+	if c.lastFailure {
+		return c.showLastFailure(ctx)
+	}
 	err := c.initRun()
 	if err != nil {
 		return err
@@ -195,3 +252,76 @@ func (c *debugHooksCommand) Run(ctx *cmd.Context) error {
 	c.Args = args
 	return c.sshCommand.Run(ctx)
 }
+
+// showLastFailure writes out the context recorded with the most recent
+// hook failure in the unit agent's status history.
+func (c *debugHooksCommand) showLastFailure(ctx *cmd.Context) error {
+	client, err := c.getStatusHistoryAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	history, err := client.StatusHistory(
+		status.KindUnitAgent,
+		names.NewUnitTag(c.Target),
+		status.StatusHistoryFilter{Size: lastFailureHistorySize},
+	)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// The history is ordered most recent first.
+	for _, entry := range history {
+		if entry.Status != status.Error {
+			continue
+		}
+		if _, ok := entry.Data["hook"]; !ok {
+			continue
+		}
+		writeHookFailure(ctx, entry)
+		return nil
+	}
+	return errors.Errorf("no hook failure found for unit %q", c.Target)
+}
+
+func writeHookFailure(ctx *cmd.Context, entry status.DetailedStatus) {
+	out := ctx.Stdout
+	fmt.Fprintf(out, "%s\n", entry.Info)
+	if entry.Since != nil {
+		fmt.Fprintf(out, "Since: %s\n", entry.Since.Local().Format(time.RFC3339))
+	}
+	if workload, ok := entry.Data["workload-status"]; ok {
+		fmt.Fprintf(out, "Workload status: %v", workload)
+		if message, ok := entry.Data["workload-message"]; ok {
+			fmt.Fprintf(out, " (%v)", message)
+		}
+		fmt.Fprintln(out)
+	}
+	var keys []string
+	for key := range entry.Data {
+		switch key {
+		case "hook", "hook-output", "hook-log", "workload-status", "workload-message":
+		default:
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(out, "%s: %v\n", key, entry.Data[key])
+	}
+	writeLines(out, "juju-log messages", entry.Data["hook-log"])
+	writeLines(out, "Hook output", entry.Data["hook-output"])
+}
+
+// writeLines writes out the lines of a captured list, if any, under the
+// given heading.
+func writeLines(out io.Writer, heading string, value interface{}) {
+	lines, _ := value.([]interface{})
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "\n%s:\n", heading)
+	for _, line := range lines {
+		fmt.Fprintf(out, "  %v\n", line)
+	}
+}
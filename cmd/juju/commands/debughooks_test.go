@@ -7,11 +7,15 @@ import (
 	"regexp"
 	"runtime"
 
+	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/cmd/modelcmd"
 	jujussh "github.com/juju/juju/network/ssh"
+	"github.com/juju/juju/status"
 )
 
 var _ = gc.Suite(&DebugHooksSuite{})
@@ -147,3 +151,84 @@ func (s *DebugHooksSuite) TestDebugHooksCommand(c *gc.C) {
 		}
 	}
 }
+
+type fakeStatusHistoryAPI struct {
+	history status.History
+	kind    status.HistoryKind
+	tag     names.Tag
+}
+
+func (f *fakeStatusHistoryAPI) StatusHistory(kind status.HistoryKind, tag names.Tag, filter status.StatusHistoryFilter) (status.History, error) {
+	f.kind = kind
+	f.tag = tag
+	return f.history, nil
+}
+
+func (f *fakeStatusHistoryAPI) Close() error {
+	return nil
+}
+
+func (s *DebugHooksSuite) runLastFailure(c *gc.C, api *fakeStatusHistoryAPI, args ...string) (*cmd.Context, error) {
+	command := &debugHooksCommand{}
+	command.getStatusHistoryAPI = func() (StatusHistoryAPI, error) {
+		return api, nil
+	}
+	return cmdtesting.RunCommand(c, modelcmd.Wrap(command), append([]string{"--last-failure"}, args...)...)
+}
+
+func (s *DebugHooksSuite) TestLastFailure(c *gc.C) {
+	api := &fakeStatusHistoryAPI{
+		history: status.History{{
+			Status: status.Idle,
+		}, {
+			Status: status.Error,
+			Info:   `hook failed: "db-relation-changed"`,
+			Data: map[string]interface{}{
+				"hook":             "db-relation-changed",
+				"relation-id":      3,
+				"workload-status":  "blocked",
+				"workload-message": "waiting for database",
+				"hook-log":         []interface{}{"ERROR cannot connect"},
+				"hook-output":      []interface{}{"Traceback:", "ConnectionError"},
+			},
+		}, {
+			Status: status.Error,
+			Info:   `hook failed: "install"`,
+			Data:   map[string]interface{}{"hook": "install"},
+		}},
+	}
+	ctx, err := s.runLastFailure(c, api, "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(api.kind, gc.Equals, status.KindUnitAgent)
+	c.Check(api.tag, gc.Equals, names.NewUnitTag("mysql/0"))
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+hook failed: "db-relation-changed"
+Workload status: blocked (waiting for database)
+relation-id: 3
+
+juju-log messages:
+  ERROR cannot connect
+
+Hook output:
+  Traceback:
+  ConnectionError
+`[1:])
+}
+
+func (s *DebugHooksSuite) TestLastFailureNotFound(c *gc.C) {
+	api := &fakeStatusHistoryAPI{
+		history: status.History{{Status: status.Idle}},
+	}
+	_, err := s.runLastFailure(c, api, "mysql/0")
+	c.Assert(err, gc.ErrorMatches, `no hook failure found for unit "mysql/0"`)
+}
+
+func (s *DebugHooksSuite) TestLastFailureWithHooks(c *gc.C) {
+	_, err := s.runLastFailure(c, &fakeStatusHistoryAPI{}, "mysql/0", "install")
+	c.Assert(err, gc.ErrorMatches, "cannot specify hooks or actions with --last-failure")
+}
+
+func (s *DebugHooksSuite) TestLastFailureViaAPI(c *gc.C) {
+	_, err := s.runLastFailure(c, &fakeStatusHistoryAPI{}, "--via-api", "mysql/0")
+	c.Assert(err, gc.ErrorMatches, "cannot specify both --last-failure and --via-api")
+}
@@ -86,6 +86,7 @@ var expectedCommands = []string{
 	"payload-register",
 	"payload-status-set",
 	"payload-unregister",
+	"relation-barrier",
 	"relation-get",
 	"relation-ids",
 	"relation-list",
@@ -25,6 +25,10 @@ The user identity supplied is the username on the respective service given by
 
 If the user has multiple keys on the service, all the keys will be added.
 
+As with keys added by ` + "`juju add-ssh-key`" + `, imported keys belong to the
+current user, and are only copied to the model's machines while that user
+has admin access to the model.
+
 Once the keys are imported, they can be viewed with the `[1:] + "`juju ssh-keys`" + `
 command, where comments will indicate which ones were imported in
 this way.
@@ -95,9 +99,7 @@ func (c *importKeysCommand) Run(context *cmd.Context) error {
 	}
 	defer client.Close()
 
-	// TODO(alexisb) - currently keys are global which is not ideal.
-	// keymanager needs to be updated to allow keys per user
-	c.user = "admin"
+	c.user = c.apiUser
 	results, err := client.ImportKeys(c.user, c.sshKeyIds...)
 	if err != nil {
 		return block.ProcessBlockedError(err, block.BlockChange)
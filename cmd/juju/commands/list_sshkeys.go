@@ -74,9 +74,7 @@ func (c *listKeysCommand) Run(context *cmd.Context) error {
 	if c.showFullKey {
 		mode = ssh.FullKeys
 	}
-	// TODO(alexisb) - currently keys are global which is not ideal.
-	// keymanager needs to be updated to allow keys per user
-	c.user = "admin"
+	c.user = c.apiUser
 	results, err := client.ListKeys(mode, c.user)
 	if err != nil {
 		return errors.Trace(err)
@@ -280,9 +280,12 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(crossmodel.NewShowOfferedEndpointCommand())
 	r.Register(crossmodel.NewListEndpointsCommand())
 	r.Register(crossmodel.NewFindEndpointsCommand())
+	r.Register(crossmodel.NewRegisterOffersCommand())
 	r.Register(application.NewConsumeCommand())
 	r.Register(application.NewSuspendRelationCommand())
 	r.Register(application.NewResumeRelationCommand())
+	r.Register(application.NewShowRelationCommand())
+	r.Register(application.NewDiagnoseRelationCommand())
 
 	// Firewall rule commands.
 	r.Register(firewall.NewSetFirewallRuleCommand())
@@ -290,7 +293,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 
 	// Destruction commands.
 	r.Register(application.NewRemoveRelationCommand())
-	r.Register(application.NewRemoveApplicationCommand())
+	r.Register(application.NewRemoveApplicationCommandWithExporter(newStorageExporter))
 	r.Register(application.NewRemoveUnitCommand())
 	r.Register(application.NewRemoveSaasCommand())
 
@@ -298,6 +301,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(status.NewStatusCommand())
 	r.Register(newSwitchCommand())
 	r.Register(status.NewStatusHistoryCommand())
+	r.Register(status.NewDeployTimelineCommand())
 
 	// Error resolution and debugging commands.
 	r.Register(newDefaultRunCommand())
@@ -329,6 +333,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(backups.NewRemoveCommand())
 	r.Register(backups.NewRestoreCommand())
 	r.Register(backups.NewUploadCommand())
+	r.Register(backups.NewVerifyCommand())
 
 	// Manage authorized ssh keys.
 	r.Register(NewAddKeysCommand())
@@ -357,6 +362,11 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(machine.NewRemoveCommand())
 	r.Register(machine.NewListMachinesCommand())
 	r.Register(machine.NewShowMachineCommand())
+	r.Register(machine.NewAddToPoolCommand())
+	r.Register(machine.NewRemoveFromPoolCommand())
+	r.Register(machine.NewListPoolCommand())
+	r.Register(machine.NewEvacuateCommand())
+	r.Register(machine.NewReportCommand())
 
 	// Manage model
 	r.Register(model.NewConfigCommand())
@@ -366,6 +376,10 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(model.NewGrantCommand())
 	r.Register(model.NewRevokeCommand())
 	r.Register(model.NewShowCommand())
+	r.Register(model.NewOrphanedResourcesCommand())
+	r.Register(model.NewPinAgentVersionCommand())
+	r.Register(model.NewMarkUnmanagedCommand())
+	r.Register(model.NewSetModelExpiryCommand())
 
 	r.Register(newMigrateCommand())
 	if featureflag.Enabled(feature.DeveloperMode) {
@@ -391,6 +405,9 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(application.NewUnexposeCommand())
 	r.Register(application.NewServiceGetConstraintsCommand())
 	r.Register(application.NewServiceSetConstraintsCommand())
+	r.Register(application.NewAffinityCommand())
+	r.Register(application.NewSetAffinityCommand())
+	r.Register(application.NewMoveUnitCommand())
 
 	// Operation protection commands
 	r.Register(block.NewDisableCommand())
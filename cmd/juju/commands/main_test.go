@@ -396,8 +396,10 @@ var commandNames = []string{
 	"add-ssh-key",
 	"add-storage",
 	"add-subnet",
+	"add-to-machine-pool",
 	"add-unit",
 	"add-user",
+	"affinity",
 	"agree",
 	"agreements",
 	"attach",
@@ -425,9 +427,11 @@ var commandNames = []string{
 	"debug-hooks",
 	"debug-log",
 	"deploy",
+	"deploy-timeline",
 	"destroy-controller",
 	"destroy-model",
 	"detach-storage",
+	"diagnose-relation",
 	"disable-command",
 	"disable-user",
 	"disabled-commands",
@@ -436,6 +440,7 @@ var commandNames = []string{
 	"enable-destroy-controller",
 	"enable-ha",
 	"enable-user",
+	"evacuate-machine",
 	"expose",
 	"find-offers",
 	"firewall-rules",
@@ -460,9 +465,11 @@ var commandNames = []string{
 	"list-credentials",
 	"list-disabled-commands",
 	"list-firewall-rules",
+	"list-machine-pool",
 	"list-machines",
 	"list-models",
 	"list-offers",
+	"list-orphaned-resources",
 	"list-payloads",
 	"list-plans",
 	"list-regions",
@@ -476,19 +483,25 @@ var commandNames = []string{
 	"list-wallets",
 	"login",
 	"logout",
+	"machine-pool",
 	"machines",
+	"mark-unmanaged",
 	"metrics",
 	"migrate",
 	"model-config",
 	"model-default",
 	"model-defaults",
 	"models",
+	"move-unit",
 	"offer",
 	"offers",
+	"orphaned-resources",
 	"payloads",
+	"pin-agent-version",
 	"plans",
 	"regions",
 	"register",
+	"register-offers",
 	"relate", //alias for add-relation
 	"reload-spaces",
 	"remove-application",
@@ -497,6 +510,7 @@ var commandNames = []string{
 	"remove-cloud",
 	"remove-consumed-application",
 	"remove-credential",
+	"remove-from-machine-pool",
 	"remove-machine",
 	"remove-offer",
 	"remove-relation",
@@ -505,6 +519,7 @@ var commandNames = []string{
 	"remove-storage",
 	"remove-unit",
 	"remove-user",
+	"report",
 	"resolved",
 	"resolve",
 	"resources",
@@ -515,12 +530,14 @@ var commandNames = []string{
 	"run",
 	"run-action",
 	"scp",
+	"set-affinity",
 	"set-constraints",
 	"set-default-credential",
 	"set-default-region",
 	"set-firewall-rule",
 	"set-meter-status",
 	"set-model-constraints",
+	"set-model-expiry",
 	"set-plan",
 	"set-wallet",
 	"show-action-output",
@@ -528,9 +545,11 @@ var commandNames = []string{
 	"show-backup",
 	"show-cloud",
 	"show-controller",
+	"show-deploy-timeline",
 	"show-machine",
 	"show-model",
 	"show-offer",
+	"show-relation",
 	"show-status",
 	"show-status-log",
 	"show-storage",
@@ -558,6 +577,7 @@ var commandNames = []string{
 	"upgrade-juju",
 	"upload-backup",
 	"users",
+	"verify-backup",
 	"version",
 	"wallets",
 	"whoami",
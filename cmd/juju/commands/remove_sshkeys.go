@@ -74,9 +74,7 @@ func (c *removeKeysCommand) Run(context *cmd.Context) error {
 	}
 	defer client.Close()
 
-	// TODO(alexisb) - currently keys are global which is not ideal.
-	// keymanager needs to be updated to allow keys per user
-	c.user = "admin"
+	c.user = c.apiUser
 	results, err := client.DeleteKeys(c.user, c.keyIds...)
 	if err != nil {
 		return block.ProcessBlockedError(err, block.BlockChange)
@@ -69,6 +69,9 @@ is equivalent to
 Commands run for applications or units are executed in a 'hook context' for
 the unit.
 
+Commands are run with bash on Linux machines, and with PowerShell on Windows
+machines. Output from Windows machines is returned as UTF-8.
+
 --all is provided as a simple way to run the command on all the machines
 in the model.  If you specify --all you cannot provide additional
 targets.
@@ -28,6 +28,14 @@ The SSH host keys of the target are verified. The --no-host-key-checks option
 can be used to disable these checks. Use of this option is not recommended as
 it opens up the possibility of a man-in-the-middle attack.
 
+If the controller runs an SSH bastion (see the "bastion-port" controller
+config), the connection is made through the bastion, which connects to the
+target on the user's behalf; the target then need not be reachable from the
+client. Only keys added by a user with admin access to the model are accepted
+by the bastion, and connections must originate from an address permitted by
+the "ssh-allow" model config. Connections through the bastion are always made
+to the 'ubuntu' account.
+
 The default identity known to Juju and used by this command is ~/.ssh/id_rsa
 
 Options can be passed to the local OpenSSH client (ssh) on platforms 
@@ -5,6 +5,7 @@ package commands
 
 import (
 	"bufio"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net"
@@ -41,6 +42,10 @@ type SSHCommon struct {
 	knownHostsPath  string
 	hostChecker     jujussh.ReachableChecker
 	forceAPIv1      bool
+	bastion         *params.SSHBastionResult
+	viaAPI          bool
+	openTunnel      tunnelOpener
+	tunnel          *sshTunnel
 }
 
 const jujuSSHClientForceAPIv1 = "JUJU_SSHCLIENT_API_V1"
@@ -52,6 +57,7 @@ type sshAPIClient interface {
 	AllAddresses(target string) ([]string, error)
 	PublicKeys(target string) ([]string, error)
 	Proxy() (bool, error)
+	Bastion() (params.SSHBastionResult, error)
 	Close() error
 }
 
@@ -148,6 +154,37 @@ func (c *SSHCommon) initRun() error {
 	// Used mostly for testing, but useful for debugging and/or
 	// backwards-compatibility with some scripts.
 	c.forceAPIv1 = os.Getenv(jujuSSHClientForceAPIv1) != ""
+
+	if c.viaAPI {
+		// The tunnel through the API server replaces both the
+		// bastion and proxy-ssh.
+		c.proxy = false
+		return nil
+	}
+	if err := c.initBastion(); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// initBastion records the details of the controller's ssh bastion, if
+// the controller runs one. Connections to machines and units are then
+// made through the bastion rather than directly or via proxy-ssh.
+func (c *SSHCommon) initBastion() error {
+	c.bastion = nil
+	if c.apiClient.BestAPIVersion() < 3 || c.forceAPIv1 {
+		return nil
+	}
+	bastion, err := c.apiClient.Bastion()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if bastion.Port == 0 {
+		return nil
+	}
+	logger.Debugf("using the controller's ssh bastion on port %d", bastion.Port)
+	c.bastion = &bastion
+	c.proxy = false
 	return nil
 }
 
@@ -159,6 +196,10 @@ func (c *SSHCommon) cleanupRun() {
 		os.Remove(c.knownHostsPath)
 		c.knownHostsPath = ""
 	}
+	if c.tunnel != nil {
+		c.tunnel.Close()
+		c.tunnel = nil
+	}
 	if c.apiClient != nil {
 		c.apiClient.Close()
 		c.apiClient = nil
@@ -202,6 +243,13 @@ func (c *SSHCommon) getSSHOptions(enablePty bool, targets ...*resolvedTarget) (*
 		}
 	}
 
+	if c.bastion != nil {
+		options.SetPort(c.bastion.Port)
+	}
+	if c.tunnel != nil {
+		options.SetPort(c.tunnel.port())
+	}
+
 	return &options, nil
 }
 
@@ -215,10 +263,23 @@ func (c *SSHCommon) generateKnownHosts(targets []*resolvedTarget) (string, error
 	for _, target := range targets {
 		if target.isAgent() {
 			agentCount++
+			if c.bastion != nil {
+				// The bastion presents the controller machines' host keys.
+				host := fmt.Sprintf("[%s]:%d", target.host, c.bastion.Port)
+				knownHosts.add(host, c.bastion.HostKeys)
+				continue
+			}
 			keys, err := c.apiClient.PublicKeys(target.entity)
 			if err != nil {
 				return "", errors.Annotatef(err, "retrieving SSH host keys for %q", target.entity)
 			}
+			if c.tunnel != nil {
+				// The target's own sshd answers on the tunnel's
+				// local port.
+				host := fmt.Sprintf("[%s]:%d", target.host, c.tunnel.port())
+				knownHosts.add(host, keys)
+				continue
+			}
 			knownHosts.add(target.host, keys)
 		} else {
 			nonAgentCount++
@@ -311,6 +372,9 @@ func (c *SSHCommon) initAPIClient() error {
 	}
 	c.apiClient = sshclient.NewFacade(conn)
 	c.apiAddr = conn.Addr()
+	c.openTunnel = func(target string) (io.ReadWriteCloser, error) {
+		return sshclient.OpenTunnel(conn, target)
+	}
 	return nil
 }
 
@@ -320,6 +384,12 @@ func (c *SSHCommon) resolveTarget(target string) (*resolvedTarget, error) {
 		// Not a machine or unit agent target - use directly.
 		return out, nil
 	}
+	if c.viaAPI {
+		return c.resolveTunnelTarget(out)
+	}
+	if c.bastion != nil {
+		return c.resolveBastionTarget(out)
+	}
 
 	getAddress := c.reachableAddressGetter
 	if c.apiClient.BestAPIVersion() < 2 || c.forceAPIv1 {
@@ -344,6 +414,42 @@ func (c *SSHCommon) resolveTarget(target string) (*resolvedTarget, error) {
 	return c.resolveWithRetry(*out, getAddress)
 }
 
+// resolveBastionTarget resolves a machine or unit target to the
+// controller's ssh bastion. The bastion user name identifies the model
+// and the target, with any slashes replaced by dots so that scp does
+// not mistake the target for a local path; the bastion connects to the
+// target on the user's behalf as the ubuntu user.
+func (c *SSHCommon) resolveBastionTarget(target *resolvedTarget) (*resolvedTarget, error) {
+	if target.user != "ubuntu" {
+		return nil, errors.Errorf("cannot connect as %q through the controller's ssh bastion", target.user)
+	}
+	host, _, err := net.SplitHostPort(c.apiAddr)
+	if err != nil {
+		return nil, errors.Errorf("failed to get bastion address: %v", err)
+	}
+	target.user = c.bastion.ModelUUID + "+" + strings.Replace(target.entity, "/", ".", -1)
+	target.host = host
+	return target, nil
+}
+
+// resolveTunnelTarget resolves a machine or unit target to a local
+// tunnel through the controller's API server to the target's ssh port.
+func (c *SSHCommon) resolveTunnelTarget(target *resolvedTarget) (*resolvedTarget, error) {
+	if c.apiClient.BestAPIVersion() < 4 || c.openTunnel == nil {
+		return nil, errors.New("the controller does not support tunnelling ssh through the API")
+	}
+	if c.tunnel != nil {
+		c.tunnel.Close()
+	}
+	tunnel, err := newSSHTunnel(target.entity, c.openTunnel)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	c.tunnel = tunnel
+	target.host = tunnel.host()
+	return target, nil
+}
+
 func (c *SSHCommon) resolveAsAgent(target string) (*resolvedTarget, bool) {
 	out := new(resolvedTarget)
 	out.user, out.entity = splitUserTarget(target)
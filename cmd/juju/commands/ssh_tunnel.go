@@ -0,0 +1,103 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"io"
+	"net"
+	"sync"
+
+	"github.com/juju/errors"
+)
+
+// tunnelOpener opens a connection to the ssh port of a machine or
+// unit target through the controller's API server.
+type tunnelOpener func(target string) (io.ReadWriteCloser, error)
+
+// sshTunnel listens on a local port and relays each connection made
+// to it through the controller's API server to the ssh port of a
+// single target, so that ssh can reach machines that are only
+// reachable from the controller.
+type sshTunnel struct {
+	listener net.Listener
+	target   string
+	open     tunnelOpener
+	wg       sync.WaitGroup
+}
+
+// newSSHTunnel starts a tunnel to the given target listening on an
+// ephemeral port on the loopback interface.
+func newSSHTunnel(target string, open tunnelOpener) (*sshTunnel, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, errors.Annotate(err, "listening for ssh tunnel connections")
+	}
+	t := &sshTunnel{
+		listener: listener,
+		target:   target,
+		open:     open,
+	}
+	t.wg.Add(1)
+	go t.loop()
+	return t, nil
+}
+
+// host returns the address on which the tunnel listens.
+func (t *sshTunnel) host() string {
+	return t.listener.Addr().(*net.TCPAddr).IP.String()
+}
+
+// port returns the port on which the tunnel listens.
+func (t *sshTunnel) port() int {
+	return t.listener.Addr().(*net.TCPAddr).Port
+}
+
+// Close stops the tunnel from accepting connections, and waits for
+// those already accepted to finish.
+func (t *sshTunnel) Close() error {
+	err := t.listener.Close()
+	t.wg.Wait()
+	return errors.Trace(err)
+}
+
+func (t *sshTunnel) loop() {
+	defer t.wg.Done()
+	for {
+		conn, err := t.listener.Accept()
+		if err != nil {
+			// The listener has been closed.
+			return
+		}
+		t.wg.Add(1)
+		go func() {
+			defer t.wg.Done()
+			t.relay(conn)
+		}()
+	}
+}
+
+// relay copies data between the local connection and a tunnel to the
+// target until either side is closed.
+func (t *sshTunnel) relay(conn net.Conn) {
+	defer conn.Close()
+	remote, err := t.open(t.target)
+	if err != nil {
+		logger.Errorf("%v", err)
+		return
+	}
+	defer remote.Close()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(remote, conn)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(conn, remote)
+		done <- struct{}{}
+	}()
+	// When either direction finishes, the deferred closes unblock the
+	// other.
+	<-done
+}
@@ -0,0 +1,72 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"strconv"
+
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type SSHTunnelSuite struct {
+	jujutesting.IsolationSuite
+}
+
+var _ = gc.Suite(&SSHTunnelSuite{})
+
+func (s *SSHTunnelSuite) TestRelay(c *gc.C) {
+	var opened []string
+	open := func(target string) (io.ReadWriteCloser, error) {
+		opened = append(opened, target)
+		local, remote := net.Pipe()
+		go func() {
+			// Echo back to the client.
+			defer remote.Close()
+			io.Copy(remote, remote)
+		}()
+		return local, nil
+	}
+	tunnel, err := newSSHTunnel("mysql/0", open)
+	c.Assert(err, jc.ErrorIsNil)
+	defer tunnel.Close()
+	c.Assert(tunnel.host(), gc.Equals, "127.0.0.1")
+
+	conn, err := net.Dial("tcp", net.JoinHostPort(tunnel.host(), strconv.Itoa(tunnel.port())))
+	c.Assert(err, jc.ErrorIsNil)
+	greeting := "SSH-2.0-test\r\n"
+	_, err = conn.Write([]byte(greeting))
+	c.Assert(err, jc.ErrorIsNil)
+
+	data := make([]byte, len(greeting))
+	_, err = io.ReadFull(conn, data)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, greeting)
+	conn.Close()
+
+	c.Assert(tunnel.Close(), jc.ErrorIsNil)
+	c.Assert(opened, jc.DeepEquals, []string{"mysql/0"})
+}
+
+func (s *SSHTunnelSuite) TestOpenError(c *gc.C) {
+	open := func(target string) (io.ReadWriteCloser, error) {
+		return nil, errors.New("permission denied")
+	}
+	tunnel, err := newSSHTunnel("0", open)
+	c.Assert(err, jc.ErrorIsNil)
+	defer tunnel.Close()
+
+	conn, err := net.Dial("tcp", net.JoinHostPort(tunnel.host(), strconv.Itoa(tunnel.port())))
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn.Close()
+	// The connection is closed without any data.
+	data, err := ioutil.ReadAll(conn)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, gc.HasLen, 0)
+}
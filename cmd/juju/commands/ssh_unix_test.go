@@ -9,6 +9,8 @@ package commands
 import (
 	"fmt"
 	"reflect"
+	"regexp"
+	"strings"
 
 	"github.com/juju/cmd/cmdtesting"
 	"github.com/juju/testing"
@@ -16,6 +18,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/controller"
 	jujussh "github.com/juju/juju/network/ssh"
 )
 
@@ -326,3 +329,35 @@ type callbackAttempt struct {
 func (a callbackAttempt) Next() bool {
 	return a.next()
 }
+
+type SSHBastionSuite struct {
+	SSHCommonSuite
+}
+
+var _ = gc.Suite(&SSHBastionSuite{})
+
+func (s *SSHBastionSuite) SetUpTest(c *gc.C) {
+	s.ControllerConfigAttrs = map[string]interface{}{
+		controller.BastionPort: 17022,
+	}
+	s.SSHCommonSuite.SetUpTest(c)
+}
+
+func (s *SSHBastionSuite) TestSSHCommandThroughBastion(c *gc.C) {
+	s.setupModel(c)
+
+	for _, target := range []string{"0", "mysql/0"} {
+		ctx, err := cmdtesting.RunCommand(c, newSSHCommand(s.hostChecker), target)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(cmdtesting.Stderr(ctx), gc.Equals, "")
+		user := s.State.ModelUUID() + "+" + strings.Replace(target, "/", ".", -1)
+		c.Check(cmdtesting.Stdout(ctx), gc.Matches, `(?s).*-p 17022 .*`+regexp.QuoteMeta(user+"@")+`\S+\n.*`)
+	}
+}
+
+func (s *SSHBastionSuite) TestSSHCommandThroughBastionOtherUser(c *gc.C) {
+	s.setupModel(c)
+
+	_, err := cmdtesting.RunCommand(c, newSSHCommand(s.hostChecker), "jenkins@0")
+	c.Assert(err, gc.ErrorMatches, `cannot connect as "jenkins" through the controller's ssh bastion`)
+}
@@ -4,12 +4,18 @@
 package commands
 
 import (
+	"gopkg.in/juju/names.v2"
+
 	"github.com/juju/juju/api/keymanager"
 	"github.com/juju/juju/cmd/modelcmd"
 )
 
 type SSHKeysBase struct {
 	modelcmd.ModelCommandBase
+
+	// apiUser holds the name of the user that the keymanager client
+	// is logged in as. Keys are added on behalf of this user.
+	apiUser string
 }
 
 // NewKeyManagerClient returns a keymanager client for the root api endpoint
@@ -19,5 +25,8 @@ func (c *SSHKeysBase) NewKeyManagerClient() (*keymanager.Client, error) {
 	if err != nil {
 		return nil, err
 	}
+	if userTag, ok := root.AuthTag().(names.UserTag); ok {
+		c.apiUser = userTag.Id()
+	}
 	return keymanager.NewClient(root), nil
 }
@@ -133,7 +133,23 @@ func (s *AddKeySuite) TestAddKey(c *gc.C) {
 	context, err := cmdtesting.RunCommand(c, NewAddKeysCommand(), key2, "invalid-key")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(cmdtesting.Stderr(context), gc.Matches, `cannot add key "invalid-key".*\n`)
-	s.assertEnvironKeys(c, key1, key2)
+	s.assertEnvironKeys(c, key1, key2+" # juju-user admin")
+}
+
+func (s *AddKeySuite) TestAddKeyWithExpiry(c *gc.C) {
+	key1 := sshtesting.ValidKeyOne.Key + " user@host"
+	s.setAuthorizedKeys(c, key1)
+
+	key2 := sshtesting.ValidKeyTwo.Key + " another@host"
+	_, err := cmdtesting.RunCommand(c, NewAddKeysCommand(), "--expires", "2099-12-31", key2)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEnvironKeys(c, key1, `expiry-time="209912310000" `+key2+" # juju-user admin")
+}
+
+func (s *AddKeySuite) TestAddKeyInvalidExpiry(c *gc.C) {
+	key2 := sshtesting.ValidKeyTwo.Key + " another@host"
+	_, err := cmdtesting.RunCommand(c, NewAddKeysCommand(), "--expires", "tomorrow", key2)
+	c.Assert(err, gc.ErrorMatches, `invalid expiry "tomorrow": expected YYYY-MM-DD or RFC3339 time`)
 }
 
 func (s *AddKeySuite) TestBlockAddKey(c *gc.C) {
@@ -195,7 +211,7 @@ func (s *ImportKeySuite) TestImportKeys(c *gc.C) {
 	context, err := cmdtesting.RunCommand(c, NewImportKeysCommand(), "lp:validuser", "lp:invalid-key")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(cmdtesting.Stderr(context), gc.Matches, `cannot import key id "lp:invalid-key".*\n`)
-	s.assertEnvironKeys(c, key1, sshtesting.ValidKeyThree.Key)
+	s.assertEnvironKeys(c, key1, sshtesting.ValidKeyThree.Key+" # juju-user admin")
 }
 
 func (s *ImportKeySuite) TestBlockImportKeys(c *gc.C) {
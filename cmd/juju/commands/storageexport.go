@@ -0,0 +1,91 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"github.com/juju/utils/ssh"
+
+	"github.com/juju/juju/cmd/juju/application"
+	"github.com/juju/juju/cmd/modelcmd"
+	jujussh "github.com/juju/juju/network/ssh"
+)
+
+// sshStorageExporter implements application.StorageExporter by running
+// tar on the unit's machine over ssh, connecting as juju ssh would.
+type sshStorageExporter struct {
+	SSHCommon
+}
+
+// newStorageExporter returns an application.StorageExporter for the
+// model of the given command.
+func newStorageExporter(base modelcmd.ModelCommandBase) (application.StorageExporter, error) {
+	return newSSHStorageExporter(base, nil)
+}
+
+func newSSHStorageExporter(base modelcmd.ModelCommandBase, hostChecker jujussh.ReachableChecker) (*sshStorageExporter, error) {
+	e := &sshStorageExporter{}
+	e.ModelCommandBase = base
+	e.setHostChecker(hostChecker)
+	if err := e.initRun(); err != nil {
+		e.cleanupRun()
+		return nil, errors.Trace(err)
+	}
+	return e, nil
+}
+
+// ExportStorage is part of the application.StorageExporter interface.
+func (e *sshStorageExporter) ExportStorage(unit, location string, w io.Writer) error {
+	defer e.cleanupExport()
+	target, err := e.resolveTarget(unit)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	options, err := e.getSSHOptions(false, target)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var stderr bytes.Buffer
+	command := ssh.Command(target.userHost(), tarCommand(location), options)
+	command.Stdout = w
+	command.Stderr = &stderr
+	if err := command.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return errors.Annotate(err, msg)
+		}
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// Close is part of the application.StorageExporter interface.
+func (e *sshStorageExporter) Close() error {
+	e.cleanupRun()
+	return nil
+}
+
+// cleanupExport removes the known_hosts file and tunnel created for a
+// single export, leaving the API connection open for the next.
+func (e *sshStorageExporter) cleanupExport() {
+	if e.knownHostsPath != "" {
+		os.Remove(e.knownHostsPath)
+		e.knownHostsPath = ""
+	}
+	if e.tunnel != nil {
+		e.tunnel.Close()
+		e.tunnel = nil
+	}
+}
+
+// tarCommand returns the command that writes the contents of the
+// directory at location to stdout as a gzipped tar archive.
+func tarCommand(location string) []string {
+	return []string{"sudo", "tar", "-C", utils.ShQuote(location), "-czf", "-", "."}
+}
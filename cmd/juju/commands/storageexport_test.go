@@ -0,0 +1,39 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type StorageExportSuite struct {
+	jujutesting.IsolationSuite
+}
+
+var _ = gc.Suite(&StorageExportSuite{})
+
+func (s *StorageExportSuite) TestTarCommand(c *gc.C) {
+	c.Assert(tarCommand("/srv/data dir"), jc.DeepEquals, []string{
+		"sudo", "tar", "-C", "'/srv/data dir'", "-czf", "-", ".",
+	})
+}
+
+func (s *StorageExportSuite) TestCleanupExport(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "known_hosts")
+	err := ioutil.WriteFile(path, nil, 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var e sshStorageExporter
+	e.knownHostsPath = path
+	e.cleanupExport()
+	c.Assert(e.knownHostsPath, gc.Equals, "")
+	_, err = ioutil.ReadFile(path)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
@@ -154,6 +154,10 @@ func ProviderConfigSchemaSourceByType(cloudType string) (config.ConfigSchemaSour
 
 // PrintConfigSchema is used to print model configuration schema.
 type PrintConfigSchema struct {
-	Type        string `yaml:"type,omitempty" json:"type,omitempty"`
-	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Type        string        `yaml:"type,omitempty" json:"type,omitempty"`
+	Description string        `yaml:"description,omitempty" json:"description,omitempty"`
+	Immutable   bool          `yaml:"immutable,omitempty" json:"immutable,omitempty"`
+	Example     interface{}   `yaml:"example,omitempty" json:"example,omitempty"`
+	Values      []interface{} `yaml:"values,omitempty" json:"values,omitempty"`
+	Deprecated  string        `yaml:"deprecated,omitempty" json:"deprecated,omitempty"`
 }
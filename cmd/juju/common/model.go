@@ -26,6 +26,7 @@ type ModelInfo struct {
 	ControllerUUID string                      `json:"controller-uuid" yaml:"controller-uuid"`
 	ControllerName string                      `json:"controller-name" yaml:"controller-name"`
 	Owner          string                      `json:"owner" yaml:"owner"`
+	OwnerTeam      string                      `json:"owner-team,omitempty" yaml:"owner-team,omitempty"`
 	Cloud          string                      `json:"cloud" yaml:"cloud"`
 	CloudRegion    string                      `json:"region,omitempty" yaml:"region,omitempty"`
 	ProviderType   string                      `json:"type,omitempty" yaml:"type,omitempty"`
@@ -36,6 +37,19 @@ type ModelInfo struct {
 	SLA            string                      `json:"sla,omitempty" yaml:"sla,omitempty"`
 	SLAOwner       string                      `json:"sla-owner,omitempty" yaml:"sla-owner,omitempty"`
 	AgentVersion   string                      `json:"agent-version,omitempty" yaml:"agent-version,omitempty"`
+
+	OwnershipTransfers []ModelOwnershipTransfer `json:"ownership-transfers,omitempty" yaml:"ownership-transfers,omitempty"`
+}
+
+// ModelOwnershipTransfer contains information about a change of a
+// model's owner.
+type ModelOwnershipTransfer struct {
+	Time      string `json:"time" yaml:"time"`
+	By        string `json:"by" yaml:"by"`
+	FromOwner string `json:"from-owner" yaml:"from-owner"`
+	FromTeam  string `json:"from-team,omitempty" yaml:"from-team,omitempty"`
+	ToOwner   string `json:"to-owner" yaml:"to-owner"`
+	ToTeam    string `json:"to-team,omitempty" yaml:"to-team,omitempty"`
 }
 
 // ModelMachineInfo contains information about a machine in a model.
@@ -88,6 +102,7 @@ func ModelInfoFromParams(info params.ModelInfo, now time.Time) (ModelInfo, error
 		UUID:           info.UUID,
 		ControllerUUID: info.ControllerUUID,
 		Owner:          ownerTag.Id(),
+		OwnerTeam:      info.OwnerTeam,
 		Life:           string(info.Life),
 		Cloud:          cloudTag.Id(),
 		CloudRegion:    info.CloudRegion,
@@ -130,9 +145,37 @@ func ModelInfoFromParams(info params.ModelInfo, now time.Time) (ModelInfo, error
 		modelInfo.SLA = ModelSLAFromParams(info.SLA)
 		modelInfo.SLAOwner = ModelSLAOwnerFromParams(info.SLA)
 	}
+	for _, transfer := range info.OwnershipTransfers {
+		outTransfer, err := ModelOwnershipTransferFromParams(transfer, now)
+		if err != nil {
+			return ModelInfo{}, errors.Trace(err)
+		}
+		modelInfo.OwnershipTransfers = append(modelInfo.OwnershipTransfers, outTransfer)
+	}
 	return modelInfo, nil
 }
 
+// ModelOwnershipTransferFromParams translates a
+// params.ModelOwnershipTransfer to ModelOwnershipTransfer.
+func ModelOwnershipTransferFromParams(transfer params.ModelOwnershipTransfer, now time.Time) (ModelOwnershipTransfer, error) {
+	var users [3]names.UserTag
+	for i, tag := range []string{transfer.ByTag, transfer.FromTag, transfer.ToTag} {
+		user, err := names.ParseUserTag(tag)
+		if err != nil {
+			return ModelOwnershipTransfer{}, errors.Trace(err)
+		}
+		users[i] = user
+	}
+	return ModelOwnershipTransfer{
+		Time:      UserFriendlyDuration(transfer.Time, now),
+		By:        users[0].Id(),
+		FromOwner: users[1].Id(),
+		FromTeam:  transfer.FromTeam,
+		ToOwner:   users[2].Id(),
+		ToTeam:    transfer.ToTeam,
+	}, nil
+}
+
 // ModelMachineInfoFromParams translates []params.ModelMachineInfo to a map of
 // machine ids to ModelMachineInfo.
 func ModelMachineInfoFromParams(machines []params.ModelMachineInfo) map[string]ModelMachineInfo {
@@ -8,10 +8,12 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
+	"github.com/juju/utils/clock"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api"
@@ -38,6 +40,7 @@ func NewAddModelCommand() cmd.Command {
 			return cloudapi.NewClient(caller)
 		},
 		providerRegistry: environs.GlobalProviderRegistry(),
+		clock:            clock.WallClock,
 	})
 }
 
@@ -48,13 +51,33 @@ type addModelCommand struct {
 	newAddModelAPI   func(base.APICallCloser) AddModelAPI
 	newCloudAPI      func(base.APICallCloser) CloudAPI
 	providerRegistry environs.ProviderRegistry
+	clock            clock.Clock
 
 	Name           string
 	Owner          string
 	CredentialName string
 	CloudRegion    string
+	Profile        string
 	Config         common.ConfigFlag
 	noSwitch       bool
+	Ephemeral      bool
+	TTL            time.Duration
+}
+
+// defaultEphemeralTTL is how long an ephemeral model lives if no
+// --ttl is given.
+const defaultEphemeralTTL = 24 * time.Hour
+
+// ephemeralDefaults holds the model config used for ephemeral models,
+// unless overridden with --config. Ephemeral models are expected to
+// be short lived and busy, so history is pruned aggressively, and
+// their metrics are of no interest to anyone.
+var ephemeralDefaults = map[string]interface{}{
+	config.MaxStatusHistoryAge:      "1h",
+	config.MaxStatusHistorySize:     "16M",
+	config.MaxActionResultsAge:      "1h",
+	config.MaxActionResultsSize:     "16M",
+	config.TransmitVendorMetricsKey: false,
 }
 
 const addModelHelpDoc = `
@@ -93,6 +116,19 @@ to manage multiple clouds, so the only valid cloud is the same cloud
 as the controller model is deployed to. This may change in a future
 release.
 
+A config profile stored on the controller may be named with --profile.
+Its values are applied to the new model unless overridden with --config,
+and "juju model-config" reports them as coming from the profile.
+
+An ephemeral model, added with --ephemeral, is destroyed along with its
+storage once its time to live, given by --ttl, has passed; the default is
+24 hours. This suits test pipelines which might otherwise leave models
+behind. Ephemeral models prune their status and action history
+aggressively and do not transmit vendor metrics, unless configured
+otherwise with --config. The expiry time is recorded on the model, and
+only admins of the model may change it, with "juju set-model-expiry",
+to extend the model's life or to keep the model indefinitely.
+
 Examples:
 
     juju add-model mymodel
@@ -100,6 +136,8 @@ Examples:
     juju add-model mymodel aws/us-east-1
     juju add-model mymodel --config my-config.yaml --config image-stream=daily
     juju add-model mymodel --credential credential_name --config authorized-keys="ssh-rsa ..."
+    juju add-model mymodel --profile hardened --config logging-config="<root>=DEBUG"
+    juju add-model ci-1234 --ephemeral --ttl 2h
 `
 
 func (c *addModelCommand) Info() *cmd.Info {
@@ -116,7 +154,10 @@ func (c *addModelCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.Owner, "owner", "", "The owner of the new model if not the current user")
 	f.StringVar(&c.CredentialName, "credential", "", "Credential used to add the model")
 	f.Var(&c.Config, "config", "Path to YAML model configuration file or individual options (--config config.yaml [--config key=value ...])")
+	f.StringVar(&c.Profile, "profile", "", "Config profile to apply to the model")
 	f.BoolVar(&c.noSwitch, "no-switch", false, "Do not switch to the newly created model")
+	f.BoolVar(&c.Ephemeral, "ephemeral", false, "Destroy the model automatically once its time to live has passed")
+	f.DurationVar(&c.TTL, "ttl", 0, "How long an ephemeral model lives (default 24h)")
 }
 
 func (c *addModelCommand) Init(args []string) error {
@@ -137,15 +178,31 @@ func (c *addModelCommand) Init(args []string) error {
 		return errors.Errorf("%q is not a valid user", c.Owner)
 	}
 
+	if c.TTL != 0 && !c.Ephemeral {
+		return errors.New("--ttl may only be used with --ephemeral")
+	}
+	if c.TTL < 0 {
+		return errors.Errorf("invalid --ttl %v: must be positive", c.TTL)
+	}
+	if c.Ephemeral && c.TTL == 0 {
+		c.TTL = defaultEphemeralTTL
+	}
+
 	return cmd.CheckEmpty(args)
 }
 
 type AddModelAPI interface {
 	CreateModel(
-		name, owner, cloudName, cloudRegion string,
+		name, owner, cloudName, cloudRegion, profile string,
 		cloudCredential names.CloudCredentialTag,
 		config map[string]interface{},
 	) (base.ModelInfo, error)
+	CreateEphemeralModel(
+		name, owner, cloudName, cloudRegion, profile string,
+		cloudCredential names.CloudCredentialTag,
+		config map[string]interface{},
+		expiry time.Time,
+	) (base.ModelInfo, error)
 }
 
 type CloudAPI interface {
@@ -229,7 +286,13 @@ func (c *addModelCommand) Run(ctx *cmd.Context) error {
 	}
 
 	addModelClient := c.newAddModelAPI(api)
-	model, err := addModelClient.CreateModel(c.Name, modelOwner, cloudTag.Id(), cloudRegion, credentialTag, attrs)
+	var model base.ModelInfo
+	if c.Ephemeral {
+		expiry := c.clock.Now().Add(c.TTL).UTC()
+		model, err = addModelClient.CreateEphemeralModel(c.Name, modelOwner, cloudTag.Id(), cloudRegion, c.Profile, credentialTag, attrs, expiry)
+	} else {
+		model, err = addModelClient.CreateModel(c.Name, modelOwner, cloudTag.Id(), cloudRegion, c.Profile, credentialTag, attrs)
+	}
 	if err != nil {
 		if params.IsCodeUnauthorized(err) {
 			common.PermissionsMessage(ctx.Stderr, "add a model")
@@ -591,5 +654,12 @@ func (c *addModelCommand) getConfigValues(ctx *cmd.Context) (map[string]interfac
 			return nil, errors.Trace(err)
 		}
 	}
+	if c.Ephemeral {
+		for key, value := range ephemeralDefaults {
+			if _, ok := attrs[key]; !ok {
+				attrs[key] = value
+			}
+		}
+	}
 	return attrs, nil
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"strings"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
@@ -437,6 +438,53 @@ func (s *AddModelSuite) TestComandLineConfigPassedThrough(c *gc.C) {
 	c.Assert(s.fakeAddModelAPI.config["cloud"], gc.Equals, "special")
 }
 
+func (s *AddModelSuite) TestProfilePassedThrough(c *gc.C) {
+	_, err := s.run(c, "test", "--profile", "hardened")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.fakeAddModelAPI.profile, gc.Equals, "hardened")
+}
+
+func (s *AddModelSuite) runEphemeral(c *gc.C, args ...string) (*cmd.Context, error) {
+	command, addModel := controller.NewAddModelCommandForTest(
+		&fakeAPIConnection{},
+		s.fakeAddModelAPI,
+		s.fakeCloudAPI,
+		s.store,
+		s.fakeProviderRegistry,
+	)
+	addModel.SetClock(gitjujutesting.NewClock(time.Date(2018, 4, 1, 12, 0, 0, 0, time.UTC)))
+	return cmdtesting.RunCommand(c, command, args...)
+}
+
+func (s *AddModelSuite) TestEphemeral(c *gc.C) {
+	_, err := s.runEphemeral(c, "test", "--ephemeral", "--ttl", "2h", "--config", "max-status-history-age=30m")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.fakeAddModelAPI.expiry, gc.Equals, time.Date(2018, 4, 1, 14, 0, 0, 0, time.UTC))
+	cfg := s.fakeAddModelAPI.config
+	c.Assert(cfg["max-status-history-age"], gc.Equals, "30m")
+	c.Assert(cfg["max-action-results-age"], gc.Equals, "1h")
+	c.Assert(cfg["transmit-vendor-metrics"], gc.Equals, false)
+}
+
+func (s *AddModelSuite) TestEphemeralDefaultTTL(c *gc.C) {
+	_, err := s.runEphemeral(c, "test", "--ephemeral")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fakeAddModelAPI.expiry, gc.Equals, time.Date(2018, 4, 2, 12, 0, 0, 0, time.UTC))
+}
+
+func (s *AddModelSuite) TestTTLRequiresEphemeral(c *gc.C) {
+	_, err := s.run(c, "test", "--ttl", "2h")
+	c.Assert(err, gc.ErrorMatches, "--ttl may only be used with --ephemeral")
+}
+
+func (s *AddModelSuite) TestNotEphemeral(c *gc.C) {
+	_, err := s.run(c, "test")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fakeAddModelAPI.expiry.IsZero(), jc.IsTrue)
+}
+
 func (s *AddModelSuite) TestConfigFileValuesPassedThrough(c *gc.C) {
 	config := map[string]string{
 		"account": "magic",
@@ -591,8 +639,10 @@ type fakeAddClient struct {
 	owner           string
 	cloudName       string
 	cloudRegion     string
+	profile         string
 	cloudCredential names.CloudCredentialTag
 	config          map[string]interface{}
+	expiry          time.Time
 	err             error
 	model           base.ModelInfo
 }
@@ -603,7 +653,7 @@ func (*fakeAddClient) Close() error {
 	return nil
 }
 
-func (f *fakeAddClient) CreateModel(name, owner, cloudName, cloudRegion string, cloudCredential names.CloudCredentialTag, config map[string]interface{}) (base.ModelInfo, error) {
+func (f *fakeAddClient) CreateModel(name, owner, cloudName, cloudRegion, profile string, cloudCredential names.CloudCredentialTag, config map[string]interface{}) (base.ModelInfo, error) {
 	if f.err != nil {
 		return base.ModelInfo{}, f.err
 	}
@@ -611,10 +661,16 @@ func (f *fakeAddClient) CreateModel(name, owner, cloudName, cloudRegion string,
 	f.cloudCredential = cloudCredential
 	f.cloudName = cloudName
 	f.cloudRegion = cloudRegion
+	f.profile = profile
 	f.config = config
 	return f.model, nil
 }
 
+func (f *fakeAddClient) CreateEphemeralModel(name, owner, cloudName, cloudRegion, profile string, cloudCredential names.CloudCredentialTag, config map[string]interface{}, expiry time.Time) (base.ModelInfo, error) {
+	f.expiry = expiry
+	return f.CreateModel(name, owner, cloudName, cloudRegion, profile, cloudCredential, config)
+}
+
 // TODO(wallyworld) - improve this stub and add test asserts
 type fakeCloudAPI struct {
 	controller.CloudAPI
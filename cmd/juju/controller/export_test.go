@@ -56,11 +56,18 @@ func NewAddModelCommandForTest(
 			return cloudAPI
 		},
 		providerRegistry: providerRegistry,
+		clock:            clock.WallClock,
 	}
 	c.SetClientStore(store)
 	return modelcmd.WrapController(c), &AddModelCommand{c}
 }
 
+// SetClock sets the clock used to work out when an ephemeral model
+// expires.
+func (c *AddModelCommand) SetClock(clock clock.Clock) {
+	c.clock = clock
+}
+
 // NewListModelsCommandForTest returns a ListModelsCommand with the API
 // and userCreds provided as specified.
 func NewListModelsCommandForTest(modelAPI ModelManagerAPI, sysAPI ModelsSysAPI, store jujuclient.ClientStore) cmd.Command {
@@ -55,10 +55,28 @@ func NewListEndpointsCommandForTest(store jujuclient.ClientStore, api ListAPI) c
 	return modelcmd.Wrap(aCmd)
 }
 
-func NewFindEndpointsCommandForTest(store jujuclient.ClientStore, api FindAPI) cmd.Command {
-	aCmd := &findCommand{newAPIFunc: func(controllerName string) (FindAPI, error) {
-		return api, nil
-	}}
+func NewFindEndpointsCommandForTest(store jujuclient.ClientStore, api FindAPI, catalogAPI CatalogFindAPI) cmd.Command {
+	aCmd := &findCommand{
+		newAPIFunc: func(controllerName string) (FindAPI, error) {
+			return api, nil
+		},
+		newCatalogAPIFunc: func(controllerName string) (CatalogFindAPI, error) {
+			return catalogAPI, nil
+		},
+	}
+	aCmd.SetClientStore(store)
+	return modelcmd.WrapController(aCmd)
+}
+
+func NewRegisterOffersCommandForTest(store jujuclient.ClientStore, api FindAPI, catalogAPI CatalogRegisterAPI) cmd.Command {
+	aCmd := &registerOffersCommand{
+		newAPIFunc: func(controllerName string) (FindAPI, error) {
+			return api, nil
+		},
+		newCatalogAPIFunc: func(controllerName string) (CatalogRegisterAPI, error) {
+			return catalogAPI, nil
+		},
+	}
 	aCmd.SetClientStore(store)
 	return modelcmd.WrapController(aCmd)
 }
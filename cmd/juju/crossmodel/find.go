@@ -7,8 +7,10 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
+	"gopkg.in/juju/charm.v6"
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/core/crossmodel"
 )
@@ -18,6 +20,11 @@ Find which offered application endpoints are available to the current user.
 
 This command is aimed for a user who wants to discover what endpoints are available to them.
 
+When no offer URL is given, offers that other controllers have registered
+with the controller's offer catalog are listed as well. Such offers show
+an access level of "-": whether they can be consumed is decided by the
+controller hosting them when they are consumed.
+
 Examples:
    $ juju find-offers
    $ juju find-offers mycontroller:
@@ -25,9 +32,11 @@ Examples:
    $ juju find-offers --interface mysql
    $ juju find-offers --url fred/prod.db2
    $ juju find-offers --offer db2
+   $ juju find-offers --interface postgresql
    
 See also:
    show-offer   
+   register-offers
 `
 
 type findCommand struct {
@@ -40,8 +49,13 @@ type findCommand struct {
 	offerName      string
 	interfaceName  string
 
-	out        cmd.Output
-	newAPIFunc func(string) (FindAPI, error)
+	// searchCatalog is true when the offer catalog is searched as
+	// well as the controller's own offers.
+	searchCatalog bool
+
+	out               cmd.Output
+	newAPIFunc        func(string) (FindAPI, error)
+	newCatalogAPIFunc func(string) (CatalogFindAPI, error)
 }
 
 // NewFindEndpointsCommand constructs command that
@@ -51,6 +65,9 @@ func NewFindEndpointsCommand() cmd.Command {
 	findCmd.newAPIFunc = func(controllerName string) (FindAPI, error) {
 		return findCmd.NewRemoteEndpointsAPI(controllerName)
 	}
+	findCmd.newCatalogAPIFunc = func(controllerName string) (CatalogFindAPI, error) {
+		return findCmd.NewOfferCatalogAPI(controllerName)
+	}
 	return modelcmd.WrapController(findCmd)
 }
 
@@ -130,6 +147,11 @@ func (c *findCommand) Run(ctx *cmd.Context) (err error) {
 	if err != nil {
 		return err
 	}
+	if c.searchCatalog {
+		if output, err = c.addCatalogOffers(output); err != nil {
+			return err
+		}
+	}
 	if len(output) == 0 {
 		return errors.New("no matching application offers found")
 	}
@@ -144,6 +166,7 @@ func (c *findCommand) validateOrSetURL() error {
 	if c.url == "" {
 		c.url = controllerName + ":"
 		c.source = controllerName
+		c.searchCatalog = true
 		return nil
 	}
 	urlParts, err := crossmodel.ParseOfferURLParts(c.url)
@@ -169,6 +192,62 @@ func (c *findCommand) validateOrSetURL() error {
 	return nil
 }
 
+// addCatalogOffers adds the offers in the controller's offer catalog
+// that match the search to the output. Offers already found on the
+// controller itself are not replaced, and controllers without an offer
+// catalog are silently skipped.
+func (c *findCommand) addCatalogOffers(output map[string]ApplicationOfferResult) (map[string]ApplicationOfferResult, error) {
+	api, err := c.newCatalogAPIFunc(c.source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer api.Close()
+	if api.BestAPIVersion() < 1 {
+		return output, nil
+	}
+
+	entries, err := api.Find(params.OfferCatalogFilter{
+		Interface: c.interfaceName,
+		OfferName: c.offerName,
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "searching offer catalog")
+	}
+	if output == nil && len(entries) > 0 {
+		output = make(map[string]ApplicationOfferResult, len(entries))
+	}
+	for _, entry := range entries {
+		url, err := crossmodel.ParseOfferURL(entry.OfferURL)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if _, ok := output[url.String()]; ok {
+			continue
+		}
+		endpoints := make([]charm.Relation, len(entry.Endpoints))
+		for i, ep := range entry.Endpoints {
+			endpoints[i] = charm.Relation{
+				Name:      ep.Name,
+				Interface: ep.Interface,
+				Role:      ep.Role,
+			}
+		}
+		output[url.String()] = ApplicationOfferResult{
+			Access:    "-",
+			Endpoints: convertRemoteEndpoints(endpoints...),
+		}
+	}
+	return output, nil
+}
+
+// CatalogFindAPI defines the offer catalog API methods that the cross
+// model find command uses.
+type CatalogFindAPI interface {
+	Close() error
+	BestAPIVersion() int
+	Find(filter params.OfferCatalogFilter) ([]params.OfferCatalogEntry, error)
+}
+
 // FindAPI defines the API methods that cross model find command uses.
 type FindAPI interface {
 	Close() error
@@ -13,13 +13,15 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/crossmodel"
 	jujucrossmodel "github.com/juju/juju/core/crossmodel"
 )
 
 type findSuite struct {
 	BaseCrossModelSuite
-	mockAPI *mockFindAPI
+	mockAPI        *mockFindAPI
+	mockCatalogAPI *mockCatalogAPI
 }
 
 var _ = gc.Suite(&findSuite{})
@@ -31,10 +33,11 @@ func (s *findSuite) SetUpTest(c *gc.C) {
 		offerName:         "hosted-db2",
 		expectedModelName: "test",
 	}
+	s.mockCatalogAPI = &mockCatalogAPI{}
 }
 
 func (s *findSuite) runFind(c *gc.C, args ...string) (*cmd.Context, error) {
-	return cmdtesting.RunCommand(c, crossmodel.NewFindEndpointsCommandForTest(s.store, s.mockAPI), args...)
+	return cmdtesting.RunCommand(c, crossmodel.NewFindEndpointsCommandForTest(s.store, s.mockAPI, s.mockCatalogAPI), args...)
 }
 
 func (s *findSuite) TestFindNoArgs(c *gc.C) {
@@ -173,6 +176,90 @@ different  fred/model.hosted-db2  consume  http:db2, http:log
 	)
 }
 
+func (s *findSuite) TestFindIncludesCatalog(c *gc.C) {
+	s.mockCatalogAPI.version = 1
+	s.mockCatalogAPI.entries = []params.OfferCatalogEntry{{
+		OfferURL:  "data:mary/prod.hosted-db2",
+		OfferName: "hosted-db2",
+		Endpoints: []params.RemoteEndpoint{
+			{Name: "db2", Interface: "http", Role: charm.RoleRequirer},
+		},
+	}, {
+		// Offers already found on the controller are not repeated.
+		OfferURL:  "master:fred/test.hosted-db2",
+		OfferName: "hosted-db2",
+	}}
+	s.assertFind(
+		c,
+		[]string{"--offer", "hosted-db2", "--format", "yaml"},
+		`
+data:mary/prod.hosted-db2:
+  access: '-'
+  endpoints:
+    db2:
+      interface: http
+      role: requirer
+master:fred/test.hosted-db2:
+  access: consume
+  endpoints:
+    db2:
+      interface: http
+      role: requirer
+    log:
+      interface: http
+      role: provider
+  users:
+    bob:
+      display-name: Bob
+      access: consume
+`[1:],
+	)
+	c.Assert(s.mockCatalogAPI.filter, jc.DeepEquals, params.OfferCatalogFilter{OfferName: "hosted-db2"})
+}
+
+func (s *findSuite) TestFindOnlyCatalog(c *gc.C) {
+	s.mockAPI.results = []*jujucrossmodel.ApplicationOfferDetails{}
+	s.mockCatalogAPI.version = 1
+	s.mockCatalogAPI.entries = []params.OfferCatalogEntry{{
+		OfferURL:  "data:mary/prod.pg",
+		OfferName: "pg",
+		Endpoints: []params.RemoteEndpoint{
+			{Name: "db", Interface: "postgresql", Role: charm.RoleProvider},
+		},
+	}}
+	s.assertFind(
+		c,
+		[]string{"--interface", "postgresql"},
+		`
+Store  URL           Access  Interfaces
+data   mary/prod.pg  -       postgresql:db
+
+`[1:],
+	)
+	c.Assert(s.mockCatalogAPI.filter, jc.DeepEquals, params.OfferCatalogFilter{Interface: "postgresql"})
+}
+
+func (s *findSuite) TestFindCatalogNotSearchedForURL(c *gc.C) {
+	s.mockAPI.expectedModelName = "model"
+	s.mockCatalogAPI.version = 1
+	s.mockCatalogAPI.err = errors.New("should not be called")
+	s.assertFind(
+		c,
+		[]string{"fred/model.hosted-db2", "--format", "tabular"},
+		`
+Store   URL                    Access   Interfaces
+master  fred/model.hosted-db2  consume  http:db2, http:log
+
+`[1:],
+	)
+}
+
+func (s *findSuite) TestFindCatalogError(c *gc.C) {
+	s.mockCatalogAPI.version = 1
+	s.mockCatalogAPI.err = errors.New("boom")
+	s.assertFindError(c, nil, "searching offer catalog: boom")
+}
+
 func (s *findSuite) assertFind(c *gc.C, args []string, expected string) {
 	context, err := s.runFind(c, args...)
 	c.Assert(err, jc.ErrorIsNil)
@@ -228,3 +315,37 @@ func (s mockFindAPI) FindApplicationOffers(filters ...jujucrossmodel.Application
 		}},
 	}}, nil
 }
+
+type mockCatalogAPI struct {
+	version int
+	err     error
+
+	entries []params.OfferCatalogEntry
+	filter  params.OfferCatalogFilter
+
+	registered   []params.OfferCatalogRegistration
+	unregistered []string
+}
+
+func (m *mockCatalogAPI) Close() error {
+	return nil
+}
+
+func (m *mockCatalogAPI) BestAPIVersion() int {
+	return m.version
+}
+
+func (m *mockCatalogAPI) Find(filter params.OfferCatalogFilter) ([]params.OfferCatalogEntry, error) {
+	m.filter = filter
+	return m.entries, m.err
+}
+
+func (m *mockCatalogAPI) Register(reg params.OfferCatalogRegistration) error {
+	m.registered = append(m.registered, reg)
+	return m.err
+}
+
+func (m *mockCatalogAPI) Unregister(controllerUUID string) error {
+	m.unregistered = append(m.unregistered, controllerUUID)
+	return m.err
+}
@@ -0,0 +1,185 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package crossmodel
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/crossmodel"
+)
+
+const registerOffersCommandDoc = `
+Register the application offers of a controller with the offer catalog of
+another controller acting as a directory.
+
+Users of the directory controller can then find the registered offers with
+find-offers, without having access to the controller hosting them. Finding
+an offer grants no access to it: the hosting controller still decides
+whether a user may consume the offer when they do so.
+
+Registering replaces the offers registered before, so the command should be
+run again whenever offers are added or removed. The offers are registered
+under the name by which the hosting controller is known to the user running
+the command, which becomes the controller part of their URLs.
+
+Both controllers must be known to the client, and the user must be a
+superuser of the directory controller.
+
+Examples:
+   $ juju register-offers directory
+   $ juju register-offers -c prod directory
+   $ juju register-offers --remove directory
+
+See also:
+   find-offers
+   offer
+`
+
+// NewRegisterOffersCommand returns a command that registers the offers
+// of a controller with another controller's offer catalog.
+func NewRegisterOffersCommand() cmd.Command {
+	registerCmd := &registerOffersCommand{}
+	registerCmd.newAPIFunc = func(controllerName string) (FindAPI, error) {
+		return registerCmd.NewRemoteEndpointsAPI(controllerName)
+	}
+	registerCmd.newCatalogAPIFunc = func(controllerName string) (CatalogRegisterAPI, error) {
+		return registerCmd.NewOfferCatalogAPI(controllerName)
+	}
+	return modelcmd.WrapController(registerCmd)
+}
+
+type registerOffersCommand struct {
+	RemoteEndpointsCommandBase
+
+	directory string
+	remove    bool
+
+	newAPIFunc        func(string) (FindAPI, error)
+	newCatalogAPIFunc func(string) (CatalogRegisterAPI, error)
+}
+
+// CatalogRegisterAPI defines the offer catalog API methods that the
+// register offers command uses.
+type CatalogRegisterAPI interface {
+	Close() error
+	BestAPIVersion() int
+	Register(reg params.OfferCatalogRegistration) error
+	Unregister(controllerUUID string) error
+}
+
+// Info implements Command.Info.
+func (c *registerOffersCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "register-offers",
+		Args:    "<directory-controller>",
+		Purpose: "Registers a controller's offers with another controller's offer catalog.",
+		Doc:     registerOffersCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *registerOffersCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.RemoteEndpointsCommandBase.SetFlags(f)
+	f.BoolVar(&c.remove, "remove", false, "remove the controller's offers from the catalog instead")
+}
+
+// Init implements Command.Init.
+func (c *registerOffersCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no directory controller specified")
+	}
+	c.directory = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *registerOffersCommand) Run(ctx *cmd.Context) error {
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if controllerName == c.directory {
+		return errors.Errorf("cannot register the offers of controller %q with itself", controllerName)
+	}
+	details, err := c.ClientStore().ControllerByName(controllerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	catalog, err := c.newCatalogAPIFunc(c.directory)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer catalog.Close()
+	if catalog.BestAPIVersion() < 1 {
+		return errors.NotSupportedf("offer catalog on controller %q", c.directory)
+	}
+
+	if c.remove {
+		if err := catalog.Unregister(details.ControllerUUID); err != nil {
+			return errors.Trace(err)
+		}
+		ctx.Infof("Removed the offers of %q from the offer catalog of %q.", controllerName, c.directory)
+		return nil
+	}
+
+	reg, err := c.registration(controllerName, details.ControllerUUID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := catalog.Register(reg); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("Registered %d offer(s) of %q with the offer catalog of %q.", len(reg.Offers), controllerName, c.directory)
+	return nil
+}
+
+// registration returns the offers of the named controller, with URLs
+// qualified by the controller name so that they can be found from the
+// directory controller.
+func (c *registerOffersCommand) registration(controllerName, controllerUUID string) (params.OfferCatalogRegistration, error) {
+	api, err := c.newAPIFunc(controllerName)
+	if err != nil {
+		return params.OfferCatalogRegistration{}, errors.Trace(err)
+	}
+	defer api.Close()
+
+	offers, err := api.FindApplicationOffers(crossmodel.ApplicationOfferFilter{})
+	if err != nil {
+		return params.OfferCatalogRegistration{}, errors.Trace(err)
+	}
+	reg := params.OfferCatalogRegistration{
+		ControllerTag:  names.NewControllerTag(controllerUUID).String(),
+		ControllerName: controllerName,
+		Offers:         make([]params.OfferCatalogEntry, len(offers)),
+	}
+	for i, offer := range offers {
+		url, err := crossmodel.ParseOfferURL(offer.OfferURL)
+		if err != nil {
+			return params.OfferCatalogRegistration{}, errors.Trace(err)
+		}
+		url.Source = controllerName
+		entry := params.OfferCatalogEntry{
+			OfferURL:               url.String(),
+			OfferName:              offer.OfferName,
+			ApplicationDescription: offer.ApplicationDescription,
+			Endpoints:              make([]params.RemoteEndpoint, len(offer.Endpoints)),
+		}
+		for j, ep := range offer.Endpoints {
+			entry.Endpoints[j] = params.RemoteEndpoint{
+				Name:      ep.Name,
+				Interface: ep.Interface,
+				Role:      ep.Role,
+				Limit:     ep.Limit,
+			}
+		}
+		reg.Offers[i] = entry
+	}
+	return reg, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package crossmodel_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/crossmodel"
+	jujucrossmodel "github.com/juju/juju/core/crossmodel"
+	"github.com/juju/juju/jujuclient"
+)
+
+const masterControllerUUID = "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+
+type registerOffersSuite struct {
+	BaseCrossModelSuite
+	mockAPI        *mockFindAPI
+	mockCatalogAPI *mockCatalogAPI
+}
+
+var _ = gc.Suite(&registerOffersSuite{})
+
+func (s *registerOffersSuite) SetUpTest(c *gc.C) {
+	s.BaseCrossModelSuite.SetUpTest(c)
+	s.store.Controllers["test-master"] = jujuclient.ControllerDetails{
+		ControllerUUID: masterControllerUUID,
+	}
+	s.mockAPI = &mockFindAPI{
+		c:                 c,
+		offerName:         "hosted-db2",
+		expectedModelName: "test",
+		expectedFilter:    &jujucrossmodel.ApplicationOfferFilter{},
+	}
+	s.mockCatalogAPI = &mockCatalogAPI{version: 1}
+}
+
+func (s *registerOffersSuite) runRegister(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, crossmodel.NewRegisterOffersCommandForTest(s.store, s.mockAPI, s.mockCatalogAPI), args...)
+}
+
+func (s *registerOffersSuite) TestInit(c *gc.C) {
+	_, err := s.runRegister(c)
+	c.Assert(err, gc.ErrorMatches, "no directory controller specified")
+	_, err = s.runRegister(c, "directory", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *registerOffersSuite) TestRegister(c *gc.C) {
+	s.mockAPI.controllerName = "local-name"
+	ctx, err := s.runRegister(c, "directory")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `Registered 1 offer(s) of "test-master" with the offer catalog of "directory".`+"\n")
+	c.Assert(s.mockCatalogAPI.registered, jc.DeepEquals, []params.OfferCatalogRegistration{{
+		ControllerTag:  "controller-" + masterControllerUUID,
+		ControllerName: "test-master",
+		Offers: []params.OfferCatalogEntry{{
+			OfferURL:  "test-master:fred/test.hosted-db2",
+			OfferName: "hosted-db2",
+			Endpoints: []params.RemoteEndpoint{
+				{Name: "log", Interface: "http", Role: charm.RoleProvider},
+				{Name: "db2", Interface: "http", Role: charm.RoleRequirer},
+			},
+		}},
+	}})
+}
+
+func (s *registerOffersSuite) TestRemove(c *gc.C) {
+	s.mockAPI.msg = "should not be called"
+	_, err := s.runRegister(c, "--remove", "directory")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockCatalogAPI.registered, gc.HasLen, 0)
+	c.Assert(s.mockCatalogAPI.unregistered, jc.DeepEquals, []string{masterControllerUUID})
+}
+
+func (s *registerOffersSuite) TestRegisterWithItself(c *gc.C) {
+	_, err := s.runRegister(c, "test-master")
+	c.Assert(err, gc.ErrorMatches, `cannot register the offers of controller "test-master" with itself`)
+}
+
+func (s *registerOffersSuite) TestCatalogNotSupported(c *gc.C) {
+	s.mockCatalogAPI.version = 0
+	_, err := s.runRegister(c, "directory")
+	c.Assert(err, gc.ErrorMatches, `offer catalog on controller "directory" not supported`)
+}
+
+func (s *registerOffersSuite) TestRegisterError(c *gc.C) {
+	s.mockCatalogAPI.err = errors.New("permission denied")
+	_, err := s.runRegister(c, "directory")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
@@ -7,6 +7,7 @@ import (
 	"gopkg.in/juju/charm.v6"
 
 	"github.com/juju/juju/api/applicationoffers"
+	"github.com/juju/juju/api/offercatalog"
 	"github.com/juju/juju/cmd/modelcmd"
 )
 
@@ -25,6 +26,16 @@ func (c *RemoteEndpointsCommandBase) NewRemoteEndpointsAPI(controllerName string
 	return applicationoffers.NewClient(root), nil
 }
 
+// NewOfferCatalogAPI returns an offer catalog api for the named
+// controller.
+func (c *RemoteEndpointsCommandBase) NewOfferCatalogAPI(controllerName string) (*offercatalog.Client, error) {
+	root, err := c.CommandBase.NewAPIRoot(c.ClientStore(), controllerName, "")
+	if err != nil {
+		return nil, err
+	}
+	return offercatalog.NewClient(root), nil
+}
+
 // RemoteEndpoint defines the serialization behaviour of remote endpoints.
 // This is used in map-style yaml output where remote endpoint name is the key.
 type RemoteEndpoint struct {
@@ -10,7 +10,9 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
+	"github.com/juju/utils/set"
 
+	"github.com/juju/juju/api/machinemanager"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/status"
 	"github.com/juju/juju/cmd/modelcmd"
@@ -22,6 +24,13 @@ type statusAPI interface {
 	Close() error
 }
 
+// instanceMetadataAPI defines the API methods used by show-machine to
+// report the provider metadata of machine instances.
+type instanceMetadataAPI interface {
+	InstanceMetadata(machines ...string) ([]params.InstanceMetadataResult, error)
+	Close() error
+}
+
 // baseMachineCommand provides access to information about machines in a model.
 type baselistMachinesCommand struct {
 	modelcmd.ModelCommandBase
@@ -31,6 +40,11 @@ type baselistMachinesCommand struct {
 	machineIds    []string
 	defaultFormat string
 	color         bool
+
+	// showInstanceMetadata is true if the provider metadata of the
+	// machine instances should be included in yaml and json output.
+	showInstanceMetadata bool
+	metadataAPI          instanceMetadataAPI
 }
 
 // SetFlags sets utc and format flags based on user specified options.
@@ -52,6 +66,17 @@ var newAPIClientForMachines = func(c *baselistMachinesCommand) (statusAPI, error
 	return c.NewAPIClient()
 }
 
+var newInstanceMetadataAPI = func(c *baselistMachinesCommand) (instanceMetadataAPI, error) {
+	if c.metadataAPI != nil {
+		return c.metadataAPI, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machinemanager.NewClient(root), nil
+}
+
 // Run implements Command.Run for baseMachinesCommand.
 func (c *baselistMachinesCommand) Run(ctx *cmd.Context) error {
 	apiclient, err := newAPIClientForMachines(c)
@@ -73,6 +98,9 @@ func (c *baselistMachinesCommand) Run(ctx *cmd.Context) error {
 	}
 
 	formatter := status.NewStatusFormatter(fullStatus, c.isoTime)
+	if c.showInstanceMetadata && c.out.Name() != "tabular" {
+		formatter.SetInstanceMetadata(c.instanceMetadata(ctx, fullStatus))
+	}
 	formatted := formatter.MachineFormat(c.machineIds)
 	return c.out.Write(ctx, formatted)
 }
@@ -80,3 +108,44 @@ func (c *baselistMachinesCommand) Run(ctx *cmd.Context) error {
 func (c *baselistMachinesCommand) tabular(writer io.Writer, value interface{}) error {
 	return status.FormatMachineTabular(writer, c.color, value)
 }
+
+// instanceMetadata returns the provider metadata of the instances of
+// the machines being shown, keyed by machine ID. Failing to get the
+// metadata is reported, but does not prevent the machines being shown.
+func (c *baselistMachinesCommand) instanceMetadata(ctx *cmd.Context, fullStatus *params.FullStatus) map[string]params.InstanceMetadata {
+	var machineIds []string
+	for id := range fullStatus.Machines {
+		if len(c.machineIds) == 0 || set.NewStrings(c.machineIds...).Contains(id) {
+			machineIds = append(machineIds, id)
+		}
+	}
+	if len(machineIds) == 0 {
+		return nil
+	}
+	api, err := newInstanceMetadataAPI(c)
+	if err != nil {
+		fmt.Fprintf(ctx.Stderr, "cannot get provider metadata: %v\n", err)
+		return nil
+	}
+	defer api.Close()
+
+	results, err := api.InstanceMetadata(machineIds...)
+	if errors.IsNotSupported(err) || params.IsCodeNotSupported(err) {
+		// Either the controller or the cloud cannot report the
+		// metadata; say so, rather than leaving it silently out.
+		fmt.Fprintf(ctx.Stderr, "provider metadata not shown: %v\n", err)
+		return nil
+	} else if err != nil {
+		fmt.Fprintf(ctx.Stderr, "cannot get provider metadata: %v\n", err)
+		return nil
+	}
+	metadata := make(map[string]params.InstanceMetadata)
+	for i, result := range results {
+		if result.Error != nil {
+			// Machines that are not provisioned have no metadata.
+			continue
+		}
+		metadata[machineIds[i]] = *result.Result
+	}
+	return metadata
+}
@@ -0,0 +1,278 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/unitmoves"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/instance"
+)
+
+// evacuatePollInterval is how often evacuate-machine checks on the
+// progress of the evacuation while waiting for it to finish.
+const evacuatePollInterval = 2 * time.Second
+
+const evacuateMachineHelpDoc = `
+Moves all of the units off a machine, and then removes the machine, so
+that its hardware can be taken away for maintenance.
+
+Units without storage are moved as by "juju move-unit": a replacement
+unit is deployed for each of them and the original is removed once the
+replacement has joined its relations. Units with storage hold state
+that juju cannot move for them; instead, the steps to move them by hand
+are listed. The machine is removed once all of its units have gone.
+
+The machines that units are moved to are chosen with --to, which takes
+the same placement directives as add-unit; new machines are provisioned
+if it is omitted. Machines hosting containers cannot be evacuated until
+the containers have been.
+
+The command waits for the units to be moved and the machine to be
+removed, unless --no-wait is given or units must be moved by hand. Run
+the command again with --status to report the progress of the
+evacuation.
+
+Examples:
+
+    juju evacuate-machine 3
+    juju evacuate-machine 3 --to zone=us-east-1b
+    juju evacuate-machine 3 --status
+
+See also:
+    move-unit
+    remove-machine
+`
+
+// EvacuateMachineAPI defines the API methods used by the
+// evacuate-machine command.
+type EvacuateMachineAPI interface {
+	Close() error
+	EvacuateMachine(string, *instance.Placement) (params.MachineEvacuation, error)
+	MachineEvacuation(string) (params.MachineEvacuation, error)
+	UnitMove(string) (params.UnitMove, error)
+}
+
+// NewEvacuateCommand returns a command that moves the units off a
+// machine and then removes it.
+func NewEvacuateCommand() cmd.Command {
+	return modelcmd.Wrap(&evacuateCommand{})
+}
+
+type evacuateCommand struct {
+	modelcmd.ModelCommandBase
+	api   EvacuateMachineAPI
+	clock clock.Clock
+
+	machineId     string
+	placementSpec string
+	placement     *instance.Placement
+	noWait        bool
+	status        bool
+}
+
+// Info implements Command.
+func (c *evacuateCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "evacuate-machine",
+		Args:    "<machine number>",
+		Purpose: "Moves all units off a machine and removes it.",
+		Doc:     evacuateMachineHelpDoc,
+	}
+}
+
+// SetFlags implements Command.
+func (c *evacuateCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.placementSpec, "to", "", "The machines and/or containers to move the units to")
+	f.BoolVar(&c.noWait, "no-wait", false, "Return once the evacuation has started")
+	f.BoolVar(&c.status, "status", false, "Report the progress of the machine's evacuation")
+}
+
+// Init implements Command.
+func (c *evacuateCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.Errorf("no machine specified")
+	}
+	if !names.IsValidMachine(args[0]) {
+		return errors.Errorf("invalid machine id %q", args[0])
+	}
+	c.machineId = args[0]
+	if c.status && (c.placementSpec != "" || c.noWait) {
+		return errors.New("cannot specify --status with --to or --no-wait")
+	}
+	if c.placementSpec != "" {
+		placement, err := instance.ParsePlacement(c.placementSpec)
+		if err == instance.ErrPlacementScopeMissing {
+			placement, err = instance.ParsePlacement("model-uuid" + ":" + c.placementSpec)
+		}
+		if err != nil {
+			return errors.Errorf("invalid --to parameter %q", c.placementSpec)
+		}
+		c.placement = placement
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *evacuateCommand) getAPI() (EvacuateMachineAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return unitmoves.NewClient(root), nil
+}
+
+// Run implements Command.
+func (c *evacuateCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if c.status {
+		evacuation, err := client.MachineEvacuation(c.machineId)
+		if err != nil {
+			return err
+		}
+		return c.reportStatus(ctx, client, evacuation)
+	}
+
+	evacuation, err := client.EvacuateMachine(c.machineId, c.placement)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockRemove)
+	}
+	ctx.Infof("evacuating machine %s", c.machineId)
+	for _, tag := range evacuation.MoveTags {
+		ctx.Infof("moving unit %s", unitName(tag))
+	}
+	for _, unit := range evacuation.Manual {
+		reportManualSteps(ctx, unit)
+	}
+	if c.noWait || len(evacuation.Manual) > 0 {
+		ctx.Infof("machine %s will be removed once all of its units have gone", c.machineId)
+		return nil
+	}
+	return c.wait(ctx, client, evacuation)
+}
+
+// wait reports the progress of the evacuation's unit moves until the
+// machine has been removed, or a move fails.
+func (c *evacuateCommand) wait(ctx *cmd.Context, client EvacuateMachineAPI, evacuation params.MachineEvacuation) error {
+	clk := c.clock
+	if clk == nil {
+		clk = clock.WallClock
+	}
+	interrupted := make(chan os.Signal, 1)
+	ctx.InterruptNotify(interrupted)
+	defer ctx.StopInterruptNotify(interrupted)
+
+	last := make(map[string]params.UnitMove)
+	for {
+		failed := false
+		for _, tag := range evacuation.MoveTags {
+			name := unitName(tag)
+			move, err := client.UnitMove(name)
+			if err != nil {
+				return err
+			}
+			if move.Phase != last[name].Phase || move.Message != last[name].Message {
+				reportMove(ctx, name, move)
+				last[name] = move
+			}
+			failed = failed || move.Phase == "failed"
+		}
+		if evacuation.Done {
+			ctx.Infof("machine %s is being removed", c.machineId)
+			return nil
+		}
+		if failed {
+			ctx.Infof("units that failed to move must be moved by hand before machine %s is removed", c.machineId)
+			return cmd.ErrSilent
+		}
+		select {
+		case <-interrupted:
+			ctx.Infof("stopped waiting; the evacuation continues in the background")
+			return nil
+		case <-clk.After(evacuatePollInterval):
+		}
+		var err error
+		if evacuation, err = client.MachineEvacuation(c.machineId); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *evacuateCommand) reportStatus(ctx *cmd.Context, client EvacuateMachineAPI, evacuation params.MachineEvacuation) error {
+	if evacuation.Done {
+		ctx.Infof("machine %s has been evacuated", c.machineId)
+		return nil
+	}
+	for _, tag := range evacuation.MoveTags {
+		name := unitName(tag)
+		move, err := client.UnitMove(name)
+		if err != nil {
+			return err
+		}
+		reportMove(ctx, name, move)
+	}
+	for _, unit := range evacuation.Manual {
+		reportManualSteps(ctx, unit)
+	}
+	ctx.Infof("machine %s will be removed once all of its units have gone", c.machineId)
+	return nil
+}
+
+func reportMove(ctx *cmd.Context, name string, move params.UnitMove) {
+	if move.Message == "" {
+		ctx.Infof("%s: %s", name, move.Phase)
+		return
+	}
+	ctx.Infof("%s: %s: %s", name, move.Phase, move.Message)
+}
+
+// reportManualSteps lists the steps an operator takes to move a unit
+// that juju would not move.
+func reportManualSteps(ctx *cmd.Context, unit params.EvacuatingUnit) {
+	name := unitName(unit.UnitTag)
+	appName, _ := names.UnitApplication(name)
+	var storage []string
+	for _, tag := range unit.StorageTags {
+		if storageTag, err := names.ParseStorageTag(tag); err == nil {
+			storage = append(storage, storageTag.Id())
+		}
+	}
+	reason := unit.Reason
+	if len(storage) > 0 {
+		reason += " " + strings.Join(storage, ", ")
+	}
+	ctx.Infof("unit %s must be moved by hand (%s):", name, reason)
+	ctx.Infof("    juju add-unit %s", appName)
+	if len(storage) > 0 {
+		ctx.Infof("    # wait for the new unit to take over the data in %s", strings.Join(storage, ", "))
+	}
+	ctx.Infof("    juju remove-unit %s", name)
+}
+
+func unitName(tag string) string {
+	unitTag, err := names.ParseUnitTag(tag)
+	if err != nil {
+		return tag
+	}
+	return unitTag.Id()
+}
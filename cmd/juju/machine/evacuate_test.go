@@ -0,0 +1,228 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type EvacuateCommandSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake  fakeEvacuateClient
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&EvacuateCommandSuite{})
+
+func (s *EvacuateCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = fakeEvacuateClient{
+		evacuations: []params.MachineEvacuation{{
+			MachineTag: "machine-3",
+			MoveTags:   []string{"unit-wordpress-0"},
+		}},
+		moves: []params.UnitMove{
+			{Phase: "provisioning", Message: "waiting for machine 4"},
+		},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		testing.ModelTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+}
+
+func (s *EvacuateCommandSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := machine.NewEvacuateCommandForTest(&s.fake, immediateClock{}, s.store)
+	return cmdtesting.RunCommand(c, command, args...)
+}
+
+func (s *EvacuateCommandSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{},
+		err:  `no machine specified`,
+	}, {
+		args: []string{"mysql/0"},
+		err:  `invalid machine id "mysql/0"`,
+	}, {
+		args: []string{"3", "--to", "#:bad"},
+		err:  `invalid --to parameter "#:bad"`,
+	}, {
+		args: []string{"3", "--status", "--to", "4"},
+		err:  `cannot specify --status with --to or --no-wait`,
+	}, {
+		args: []string{"3", "4"},
+		err:  `unrecognized args: \["4"\]`,
+	}, {
+		args: []string{"3", "--to", "zone=us-east-1b"},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := machine.NewEvacuateCommandForTest(&s.fake, immediateClock{}, s.store)
+		err := cmdtesting.InitCommand(command, test.args)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *EvacuateCommandSuite) TestEvacuateWaits(c *gc.C) {
+	s.fake.evacuations = append(s.fake.evacuations,
+		params.MachineEvacuation{MachineTag: "machine-3", MoveTags: []string{"unit-wordpress-0"}},
+		params.MachineEvacuation{MachineTag: "machine-3", MoveTags: []string{"unit-wordpress-0"}, Done: true},
+	)
+	s.fake.moves = append(s.fake.moves,
+		params.UnitMove{Phase: "deploying", Message: "waiting for unit wordpress/1 to start"},
+		params.UnitMove{Phase: "done", Message: "moved to unit wordpress/1 on machine 4"},
+	)
+	ctx, err := s.run(c, "3", "--to", "4")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+evacuating machine 3
+moving unit wordpress/0
+wordpress/0: provisioning: waiting for machine 4
+wordpress/0: deploying: waiting for unit wordpress/1 to start
+wordpress/0: done: moved to unit wordpress/1 on machine 4
+machine 3 is being removed
+`[1:])
+	s.fake.CheckCall(c, 0, "EvacuateMachine", "3", &instance.Placement{Scope: "#", Directive: "4"})
+	s.fake.CheckCallNames(c,
+		"EvacuateMachine", "UnitMove",
+		"MachineEvacuation", "UnitMove",
+		"MachineEvacuation", "UnitMove",
+		"Close",
+	)
+}
+
+func (s *EvacuateCommandSuite) TestEvacuateMoveFailed(c *gc.C) {
+	s.fake.moves[0] = params.UnitMove{Phase: "failed", Message: "cannot provision machine 4: no capacity"}
+	ctx, err := s.run(c, "3")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+evacuating machine 3
+moving unit wordpress/0
+wordpress/0: failed: cannot provision machine 4: no capacity
+units that failed to move must be moved by hand before machine 3 is removed
+`[1:])
+}
+
+func (s *EvacuateCommandSuite) TestEvacuateManual(c *gc.C) {
+	s.fake.evacuations[0].Manual = []params.EvacuatingUnit{{
+		UnitTag:     "unit-mysql-0",
+		StorageTags: []string{"storage-database-0"},
+		Reason:      "unit has storage",
+	}}
+	ctx, err := s.run(c, "3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+evacuating machine 3
+moving unit wordpress/0
+unit mysql/0 must be moved by hand (unit has storage database/0):
+    juju add-unit mysql
+    # wait for the new unit to take over the data in database/0
+    juju remove-unit mysql/0
+machine 3 will be removed once all of its units have gone
+`[1:])
+	s.fake.CheckCallNames(c, "EvacuateMachine", "Close")
+}
+
+func (s *EvacuateCommandSuite) TestEvacuateNoWait(c *gc.C) {
+	ctx, err := s.run(c, "3", "--no-wait")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+evacuating machine 3
+moving unit wordpress/0
+machine 3 will be removed once all of its units have gone
+`[1:])
+	s.fake.CheckCallNames(c, "EvacuateMachine", "Close")
+}
+
+func (s *EvacuateCommandSuite) TestStatus(c *gc.C) {
+	ctx, err := s.run(c, "3", "--status")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+wordpress/0: provisioning: waiting for machine 4
+machine 3 will be removed once all of its units have gone
+`[1:])
+	s.fake.CheckCallNames(c, "MachineEvacuation", "UnitMove", "Close")
+}
+
+func (s *EvacuateCommandSuite) TestEvacuateBlocked(c *gc.C) {
+	s.fake.SetErrors(&params.Error{Code: params.CodeOperationBlocked, Message: "TestBlockEvacuate"})
+	_, err := s.run(c, "3")
+	testing.AssertOperationWasBlocked(c, err, ".*TestBlockEvacuate.*")
+}
+
+type fakeEvacuateClient struct {
+	gitjujutesting.Stub
+	evacuations []params.MachineEvacuation
+	moves       []params.UnitMove
+}
+
+func (f *fakeEvacuateClient) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeEvacuateClient) EvacuateMachine(machineId string, placement *instance.Placement) (params.MachineEvacuation, error) {
+	f.MethodCall(f, "EvacuateMachine", machineId, placement)
+	return f.nextEvacuation(), f.NextErr()
+}
+
+func (f *fakeEvacuateClient) MachineEvacuation(machineId string) (params.MachineEvacuation, error) {
+	f.MethodCall(f, "MachineEvacuation", machineId)
+	return f.nextEvacuation(), f.NextErr()
+}
+
+func (f *fakeEvacuateClient) nextEvacuation() params.MachineEvacuation {
+	evacuation := f.evacuations[0]
+	if len(f.evacuations) > 1 {
+		f.evacuations = f.evacuations[1:]
+	}
+	return evacuation
+}
+
+func (f *fakeEvacuateClient) UnitMove(unitName string) (params.UnitMove, error) {
+	f.MethodCall(f, "UnitMove", unitName)
+	move := f.moves[0]
+	if len(f.moves) > 1 {
+		f.moves = f.moves[1:]
+	}
+	return move, f.NextErr()
+}
+
+// immediateClock is a clock whose timers fire straight away, so that
+// evacuate-machine does not wait between polls.
+type immediateClock struct {
+	clock.Clock
+}
+
+func (immediateClock) After(time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	ch <- time.Time{}
+	return ch
+}
@@ -5,9 +5,11 @@ package machine
 
 import (
 	"github.com/juju/cmd"
+	"github.com/juju/utils/clock"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
 	"github.com/juju/juju/storage"
 )
 
@@ -36,8 +38,8 @@ func NewListCommandForTest(api statusAPI) cmd.Command {
 }
 
 // NewShowCommandForTest returns a showMachineCommand with specified api
-func NewShowCommandForTest(api statusAPI) cmd.Command {
-	cmd := newShowMachineCommand(api)
+func NewShowCommandForTest(api statusAPI, metadataAPI instanceMetadataAPI) cmd.Command {
+	cmd := newShowMachineCommand(api, metadataAPI)
 	return modelcmd.Wrap(cmd)
 }
 
@@ -54,6 +56,46 @@ func NewRemoveCommandForTest(apiRoot api.Connection, machineAPI RemoveMachineAPI
 	return modelcmd.Wrap(cmd), &RemoveCommand{cmd}
 }
 
+// NewAddToPoolCommandForTest returns an addToPoolCommand with the api
+// provided as specified.
+func NewAddToPoolCommandForTest(api MachinePoolAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &addToPoolCommand{poolCommandBase: poolCommandBase{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewRemoveFromPoolCommandForTest returns a removeFromPoolCommand with
+// the api provided as specified.
+func NewRemoveFromPoolCommandForTest(api MachinePoolAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &removeFromPoolCommand{poolCommandBase: poolCommandBase{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewListPoolCommandForTest returns a listPoolCommand with the api
+// provided as specified.
+func NewListPoolCommandForTest(api MachinePoolAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &listPoolCommand{poolCommandBase: poolCommandBase{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewEvacuateCommandForTest returns an evacuateCommand with the api and
+// clock provided as specified.
+func NewEvacuateCommandForTest(api EvacuateMachineAPI, clock clock.Clock, store jujuclient.ClientStore) cmd.Command {
+	cmd := &evacuateCommand{api: api, clock: clock}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewSecurityUpdatesCommandForTest returns a securityUpdatesCommand with
+// the api provided as specified.
+func NewSecurityUpdatesCommandForTest(api PackageUpdatesAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &securityUpdatesCommand{api: api}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
 func NewDisksFlag(disks *[]storage.Constraints) *disksFlag {
 	return &disksFlag{disks}
 }
@@ -0,0 +1,301 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"io"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/machinepool"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+// MachinePoolAPI defines the API methods used by the machine pool
+// commands.
+type MachinePoolAPI interface {
+	Close() error
+	Add(machineIds ...string) ([]params.ErrorResult, error)
+	Remove(machineIds ...string) ([]params.ErrorResult, error)
+	List() ([]params.PoolMachine, error)
+}
+
+// poolCommandBase holds what is common to the machine pool commands.
+type poolCommandBase struct {
+	modelcmd.ModelCommandBase
+	api MachinePoolAPI
+}
+
+func (c *poolCommandBase) getAPI() (MachinePoolAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machinepool.NewClient(root), nil
+}
+
+func parseMachineIds(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return nil, errors.Errorf("no machines specified")
+	}
+	for _, id := range args {
+		if !names.IsValidMachine(id) {
+			return nil, errors.Errorf("invalid machine id %q", id)
+		}
+	}
+	return args, nil
+}
+
+// reportPoolResults reports the outcome of a pool operation on each of
+// the machines, returning cmd.ErrSilent if any failed.
+func reportPoolResults(ctx *cmd.Context, verb string, machineIds []string, results []params.ErrorResult) error {
+	anyFailed := false
+	for i, id := range machineIds {
+		if err := results[i].Error; err != nil {
+			anyFailed = true
+			ctx.Infof("%s machine %s failed: %s", verb, id, err)
+			continue
+		}
+		ctx.Infof("%s machine %s", verb, id)
+	}
+	if anyFailed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
+
+const addToPoolHelpDoc = `
+Adds existing machines to the model's machine pool. Units being deployed
+are placed on available machines in the pool, if any satisfy their
+series and constraints, before new machines are provisioned.
+
+Only provisioned machines that may host units, and do not yet host any,
+can be added. This is typically used with machines added with
+"juju add-machine ssh:..." or allocated from bare metal in advance.
+
+Examples:
+
+    juju add-to-machine-pool 3 4
+
+See also:
+    add-machine
+    list-machine-pool
+    remove-from-machine-pool
+`
+
+// NewAddToPoolCommand returns a command that adds machines to the
+// model's machine pool.
+func NewAddToPoolCommand() cmd.Command {
+	return modelcmd.Wrap(&addToPoolCommand{})
+}
+
+type addToPoolCommand struct {
+	poolCommandBase
+	machineIds []string
+}
+
+// Info implements Command.
+func (c *addToPoolCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add-to-machine-pool",
+		Args:    "<machine number> ...",
+		Purpose: "Adds machines to the model's machine pool.",
+		Doc:     addToPoolHelpDoc,
+	}
+}
+
+// Init implements Command.
+func (c *addToPoolCommand) Init(args []string) (err error) {
+	c.machineIds, err = parseMachineIds(args)
+	return err
+}
+
+// Run implements Command.
+func (c *addToPoolCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	results, err := client.Add(c.machineIds...)
+	if err != nil {
+		return err
+	}
+	return reportPoolResults(ctx, "adding", c.machineIds, results)
+}
+
+const removeFromPoolHelpDoc = `
+Removes machines from the model's machine pool, so that units being
+deployed are no longer placed on them. Units already on the machines are
+not affected, and the machines themselves are not removed.
+
+Examples:
+
+    juju remove-from-machine-pool 3
+
+See also:
+    add-to-machine-pool
+    list-machine-pool
+    remove-machine
+`
+
+// NewRemoveFromPoolCommand returns a command that removes machines
+// from the model's machine pool.
+func NewRemoveFromPoolCommand() cmd.Command {
+	return modelcmd.Wrap(&removeFromPoolCommand{})
+}
+
+type removeFromPoolCommand struct {
+	poolCommandBase
+	machineIds []string
+}
+
+// Info implements Command.
+func (c *removeFromPoolCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove-from-machine-pool",
+		Args:    "<machine number> ...",
+		Purpose: "Removes machines from the model's machine pool.",
+		Doc:     removeFromPoolHelpDoc,
+	}
+}
+
+// Init implements Command.
+func (c *removeFromPoolCommand) Init(args []string) (err error) {
+	c.machineIds, err = parseMachineIds(args)
+	return err
+}
+
+// Run implements Command.
+func (c *removeFromPoolCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	results, err := client.Remove(c.machineIds...)
+	if err != nil {
+		return err
+	}
+	return reportPoolResults(ctx, "removing", c.machineIds, results)
+}
+
+const listPoolHelpDoc = `
+Lists the machines in the model's machine pool. A machine is "available"
+if units being deployed may be placed on it, or "allocated" if it hosts a
+unit that was placed on it from the pool.
+
+Examples:
+
+    juju list-machine-pool
+    juju machine-pool --format yaml
+
+See also:
+    add-to-machine-pool
+    remove-from-machine-pool
+`
+
+// NewListPoolCommand returns a command that lists the machines in the
+// model's machine pool.
+func NewListPoolCommand() cmd.Command {
+	return modelcmd.Wrap(&listPoolCommand{})
+}
+
+type listPoolCommand struct {
+	poolCommandBase
+	out cmd.Output
+}
+
+// Info implements Command.
+func (c *listPoolCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list-machine-pool",
+		Purpose: "Lists the machines in the model's machine pool.",
+		Doc:     listPoolHelpDoc,
+		Aliases: []string{"machine-pool"},
+	}
+}
+
+// SetFlags implements Command.
+func (c *listPoolCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatPoolTabular,
+	})
+}
+
+// Init implements Command.
+func (c *listPoolCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.
+func (c *listPoolCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	results, err := client.List()
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("The machine pool is empty.")
+		return nil
+	}
+	machines := make([]poolMachine, len(results))
+	for i, r := range results {
+		machineTag, err := names.ParseMachineTag(r.MachineTag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		machines[i] = poolMachine{
+			Machine: machineTag.Id(),
+			Status:  r.Status,
+		}
+		if r.UnitTag != "" {
+			unitTag, err := names.ParseUnitTag(r.UnitTag)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			machines[i].Unit = unitTag.Id()
+		}
+	}
+	return c.out.Write(ctx, machines)
+}
+
+type poolMachine struct {
+	Machine string `yaml:"machine" json:"machine"`
+	Status  string `yaml:"status" json:"status"`
+	Unit    string `yaml:"unit,omitempty" json:"unit,omitempty"`
+}
+
+func formatPoolTabular(writer io.Writer, value interface{}) error {
+	machines, ok := value.([]poolMachine)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", machines, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Machine", "Status", "Unit")
+	for _, m := range machines {
+		w.Println(m.Machine, m.Status, m.Unit)
+	}
+	tw.Flush()
+	return nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type MachinePoolCommandSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake  fakeMachinePoolClient
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&MachinePoolCommandSuite{})
+
+type fakeMachinePoolClient struct {
+	gitjujutesting.Stub
+	machines []params.PoolMachine
+	results  []params.ErrorResult
+}
+
+func (f *fakeMachinePoolClient) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeMachinePoolClient) Add(machineIds ...string) ([]params.ErrorResult, error) {
+	f.MethodCall(f, "Add", machineIds)
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	return f.results, nil
+}
+
+func (f *fakeMachinePoolClient) Remove(machineIds ...string) ([]params.ErrorResult, error) {
+	f.MethodCall(f, "Remove", machineIds)
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	return f.results, nil
+}
+
+func (f *fakeMachinePoolClient) List() ([]params.PoolMachine, error) {
+	f.MethodCall(f, "List")
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	return f.machines, nil
+}
+
+func (s *MachinePoolCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = fakeMachinePoolClient{
+		machines: []params.PoolMachine{{
+			MachineTag: "machine-0",
+			Status:     "allocated",
+			UnitTag:    "unit-mysql-0",
+		}, {
+			MachineTag: "machine-10",
+			Status:     "allocated",
+			UnitTag:    "unit-wordpress-1",
+		}},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		testing.ModelTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+}
+
+func (s *MachinePoolCommandSuite) TestListTabular(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, machine.NewListPoolCommandForTest(&s.fake, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCallNames(c, "List", "Close")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Machine  Status     Unit
+0        allocated  mysql/0
+10       allocated  wordpress/1
+
+`[1:])
+}
+
+func (s *MachinePoolCommandSuite) TestListYAML(c *gc.C) {
+	s.fake.machines[1] = params.PoolMachine{MachineTag: "machine-10", Status: "available"}
+	ctx, err := cmdtesting.RunCommand(c, machine.NewListPoolCommandForTest(&s.fake, s.store), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+- machine: "0"
+  status: allocated
+  unit: mysql/0
+- machine: "10"
+  status: available
+`[1:])
+}
+
+func (s *MachinePoolCommandSuite) TestListEmpty(c *gc.C) {
+	s.fake.machines = nil
+	ctx, err := cmdtesting.RunCommand(c, machine.NewListPoolCommandForTest(&s.fake, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "The machine pool is empty.\n")
+}
+
+func (s *MachinePoolCommandSuite) TestAdd(c *gc.C) {
+	s.fake.results = []params.ErrorResult{{}, {}}
+	ctx, err := cmdtesting.RunCommand(c, machine.NewAddToPoolCommandForTest(&s.fake, s.store), "1", "2")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"Add", []interface{}{[]string{"1", "2"}}},
+		{"Close", nil},
+	})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "adding machine 1\nadding machine 2\n")
+}
+
+func (s *MachinePoolCommandSuite) TestAddFailure(c *gc.C) {
+	s.fake.results = []params.ErrorResult{{Error: &params.Error{Message: "machine 1 not provisioned"}}}
+	ctx, err := cmdtesting.RunCommand(c, machine.NewAddToPoolCommandForTest(&s.fake, s.store), "1")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "adding machine 1 failed: machine 1 not provisioned\n")
+}
+
+func (s *MachinePoolCommandSuite) TestAddInit(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, machine.NewAddToPoolCommandForTest(&s.fake, s.store))
+	c.Assert(err, gc.ErrorMatches, "no machines specified")
+	_, err = cmdtesting.RunCommand(c, machine.NewAddToPoolCommandForTest(&s.fake, s.store), "lxd")
+	c.Assert(err, gc.ErrorMatches, `invalid machine id "lxd"`)
+	s.fake.CheckNoCalls(c)
+}
+
+func (s *MachinePoolCommandSuite) TestRemove(c *gc.C) {
+	s.fake.results = []params.ErrorResult{{}}
+	ctx, err := cmdtesting.RunCommand(c, machine.NewRemoveFromPoolCommandForTest(&s.fake, s.store), "3")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCallNames(c, "Remove", "Close")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "removing machine 3\n")
+}
+
+func (s *MachinePoolCommandSuite) TestError(c *gc.C) {
+	s.fake.SetErrors(errors.New("boom"))
+	_, err := cmdtesting.RunCommand(c, machine.NewListPoolCommandForTest(&s.fake, s.store))
+	c.Assert(err, gc.ErrorMatches, "boom")
+	s.fake.CheckCallNames(c, "List", "Close")
+}
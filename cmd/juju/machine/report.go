@@ -0,0 +1,190 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"io"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+const reportHelpDoc = `
+Reports on the model's machines, as gathered by their agents.
+
+See also:
+    show-machine
+`
+
+// NewReportCommand returns the "report" super-command, which holds
+// the commands reporting on the model's machines.
+func NewReportCommand() cmd.Command {
+	report := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "report",
+		Doc:         reportHelpDoc,
+		UsagePrefix: "juju",
+		Purpose:     "Reports on the model's machines.",
+	})
+	report.Register(NewSecurityUpdatesCommand())
+	return report
+}
+
+const securityUpdatesHelpDoc = `
+Lists the package updates pending on each of the model's machines, and
+whether the machine needs rebooting for updates already installed to
+take effect. Machine agents check for updates when they start and every
+six hours after; machines whose agents have not yet reported, or that
+do not run Ubuntu, are not listed.
+
+The model's enable-os-refresh-update and enable-os-upgrade settings
+only update new machines when they are provisioned; this command shows
+which machines have fallen behind since.
+
+Examples:
+
+    juju report security-updates
+    juju report security-updates --format yaml
+
+See also:
+    machines
+    model-config
+`
+
+// PackageUpdatesAPI defines the API methods used by the
+// security-updates command.
+type PackageUpdatesAPI interface {
+	Close() error
+	PackageUpdates() ([]params.PackageUpdates, error)
+}
+
+// NewSecurityUpdatesCommand returns a command that reports the package
+// updates pending on the model's machines.
+func NewSecurityUpdatesCommand() cmd.Command {
+	return modelcmd.Wrap(&securityUpdatesCommand{})
+}
+
+type securityUpdatesCommand struct {
+	modelcmd.ModelCommandBase
+	api     PackageUpdatesAPI
+	out     cmd.Output
+	isoTime bool
+}
+
+// Info implements Command.
+func (c *securityUpdatesCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "security-updates",
+		Purpose: "Lists the package updates pending on the model's machines.",
+		Doc:     securityUpdatesHelpDoc,
+	}
+}
+
+// SetFlags implements Command.
+func (c *securityUpdatesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.isoTime, "utc", false, "Display time as UTC in RFC3339 format")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatPackageUpdatesTabular,
+	})
+}
+
+// Init implements Command.
+func (c *securityUpdatesCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *securityUpdatesCommand) getAPI() (PackageUpdatesAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+// Run implements Command.
+func (c *securityUpdatesCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	results, err := client.PackageUpdates()
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No machines have reported package updates.")
+		return nil
+	}
+	machines := make([]machinePackageUpdates, len(results))
+	var withSecurity, needReboot int
+	for i, r := range results {
+		machineTag, err := names.ParseMachineTag(r.Tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		machines[i] = machinePackageUpdates{
+			Machine:        machineTag.Id(),
+			Updates:        r.Pending,
+			Security:       r.Security,
+			RebootRequired: r.RebootRequired,
+			Updated:        common.FormatTime(&r.Updated, c.isoTime),
+		}
+		if r.Security > 0 {
+			withSecurity++
+		}
+		if r.RebootRequired {
+			needReboot++
+		}
+	}
+	if err := c.out.Write(ctx, machines); err != nil {
+		return err
+	}
+	if c.out.Name() == "tabular" {
+		ctx.Infof("%d of %d machines have security updates pending, %d need rebooting.",
+			withSecurity, len(machines), needReboot)
+	}
+	return nil
+}
+
+type machinePackageUpdates struct {
+	Machine        string `yaml:"machine" json:"machine"`
+	Updates        int    `yaml:"updates" json:"updates"`
+	Security       int    `yaml:"security" json:"security"`
+	RebootRequired bool   `yaml:"reboot-required" json:"reboot-required"`
+	Updated        string `yaml:"updated" json:"updated"`
+}
+
+func formatPackageUpdatesTabular(writer io.Writer, value interface{}) error {
+	machines, ok := value.([]machinePackageUpdates)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", machines, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Machine", "Updates", "Security", "Reboot required", "Updated")
+	for _, m := range machines {
+		reboot := "no"
+		if m.RebootRequired {
+			reboot = "yes"
+		}
+		w.Println(m.Machine, m.Updates, m.Security, reboot, m.Updated)
+	}
+	tw.Flush()
+	return nil
+}
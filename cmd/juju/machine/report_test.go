@@ -0,0 +1,117 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type SecurityUpdatesCommandSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake  fakePackageUpdatesClient
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&SecurityUpdatesCommandSuite{})
+
+type fakePackageUpdatesClient struct {
+	gitjujutesting.Stub
+	updates []params.PackageUpdates
+}
+
+func (f *fakePackageUpdatesClient) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakePackageUpdatesClient) PackageUpdates() ([]params.PackageUpdates, error) {
+	f.MethodCall(f, "PackageUpdates")
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	return f.updates, nil
+}
+
+func (s *SecurityUpdatesCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	updated := time.Date(2018, 4, 3, 12, 0, 0, 0, time.UTC)
+	s.fake = fakePackageUpdatesClient{
+		updates: []params.PackageUpdates{{
+			Tag:      "machine-0",
+			Pending:  12,
+			Security: 3,
+			Updated:  updated,
+		}, {
+			Tag:            "machine-10",
+			RebootRequired: true,
+			Updated:        updated,
+		}},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		testing.ModelTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+}
+
+func (s *SecurityUpdatesCommandSuite) TestTabular(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, machine.NewSecurityUpdatesCommandForTest(&s.fake, s.store), "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCallNames(c, "PackageUpdates", "Close")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Machine  Updates  Security  Reboot required  Updated
+0        12       3         no               2018-04-03 12:00:00Z
+10       0        0         yes              2018-04-03 12:00:00Z
+
+`[1:])
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "1 of 2 machines have security updates pending, 1 need rebooting.\n")
+}
+
+func (s *SecurityUpdatesCommandSuite) TestJSON(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, machine.NewSecurityUpdatesCommandForTest(&s.fake, s.store), "--format", "json", "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `[`+
+		`{"machine":"0","updates":12,"security":3,"reboot-required":false,"updated":"2018-04-03 12:00:00Z"},`+
+		`{"machine":"10","updates":0,"security":0,"reboot-required":true,"updated":"2018-04-03 12:00:00Z"}`+
+		"]\n")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "")
+}
+
+func (s *SecurityUpdatesCommandSuite) TestNoReports(c *gc.C) {
+	s.fake.updates = nil
+	ctx, err := cmdtesting.RunCommand(c, machine.NewSecurityUpdatesCommandForTest(&s.fake, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No machines have reported package updates.\n")
+}
+
+func (s *SecurityUpdatesCommandSuite) TestInit(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, machine.NewSecurityUpdatesCommandForTest(&s.fake, s.store), "0")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["0"\]`)
+	s.fake.CheckNoCalls(c)
+}
+
+func (s *SecurityUpdatesCommandSuite) TestAPIError(c *gc.C) {
+	s.fake.SetErrors(errors.New("boom"))
+	_, err := cmdtesting.RunCommand(c, machine.NewSecurityUpdatesCommandForTest(&s.fake, s.store))
+	c.Assert(err, gc.ErrorMatches, "boom")
+	s.fake.CheckCallNames(c, "PackageUpdates", "Close")
+}
@@ -14,6 +14,11 @@ Show a specified machine on a model.  Default format is in yaml,
 other formats can be specified with the "--format" option.
 Available formats are yaml, tabular, and json
 
+The yaml and json formats include the metadata reported by the cloud
+for each machine's instance, such as its image, instance profile,
+tenancy and network interfaces, where the cloud supports it. When it
+does not, a note saying so is written to stderr.
+
 Examples:
     # Display status for machine 0
     juju show-machine 0
@@ -25,13 +30,15 @@ Examples:
 
 // NewShowMachineCommand returns a command that shows details on the specified machine[s].
 func NewShowMachineCommand() cmd.Command {
-	return modelcmd.Wrap(newShowMachineCommand(nil))
+	return modelcmd.Wrap(newShowMachineCommand(nil, nil))
 }
 
-func newShowMachineCommand(api statusAPI) *showMachineCommand {
+func newShowMachineCommand(api statusAPI, metadataAPI instanceMetadataAPI) *showMachineCommand {
 	showCmd := &showMachineCommand{}
 	showCmd.defaultFormat = "yaml"
 	showCmd.api = api
+	showCmd.metadataAPI = metadataAPI
+	showCmd.showInstanceMetadata = true
 	return showCmd
 }
 
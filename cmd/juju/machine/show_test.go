@@ -6,9 +6,11 @@ package machine_test
 import (
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/machine"
 	"github.com/juju/juju/testing"
 )
@@ -20,7 +22,32 @@ type MachineShowCommandSuite struct {
 var _ = gc.Suite(&MachineShowCommandSuite{})
 
 func newMachineShowCommand() cmd.Command {
-	return machine.NewShowCommandForTest(&fakeStatusAPI{})
+	return machine.NewShowCommandForTest(&fakeStatusAPI{}, &fakeInstanceMetadataAPI{})
+}
+
+// fakeInstanceMetadataAPI reports metadata only for the instance of
+// machine 0, if it has any metadata to report at all.
+type fakeInstanceMetadataAPI struct {
+	metadata *params.InstanceMetadata
+}
+
+func (api *fakeInstanceMetadataAPI) InstanceMetadata(machines ...string) ([]params.InstanceMetadataResult, error) {
+	if api.metadata == nil {
+		return nil, errors.NotSupportedf("instance metadata")
+	}
+	results := make([]params.InstanceMetadataResult, len(machines))
+	for i, id := range machines {
+		if id == "0" {
+			results[i].Result = api.metadata
+		} else {
+			results[i].Error = &params.Error{Message: "not found", Code: params.CodeNotFound}
+		}
+	}
+	return results, nil
+}
+
+func (*fakeInstanceMetadataAPI) Close() error {
+	return nil
 }
 
 func (s *MachineShowCommandSuite) SetUpTest(c *gc.C) {
@@ -111,6 +138,7 @@ func (s *MachineShowCommandSuite) TestShowSingleMachine(c *gc.C) {
 		"        is-up: true\n"+
 		"    constraints: mem=3584M\n"+
 		"    hardware: availability-zone=us-east-1\n")
+	c.Assert(cmdtesting.Stderr(context), gc.Equals, "provider metadata not shown: instance metadata not supported\n")
 }
 
 func (s *MachineShowCommandSuite) TestShowTabularMachine(c *gc.C) {
@@ -131,3 +159,56 @@ func (s *MachineShowCommandSuite) TestShowJsonMachine(c *gc.C) {
 	c.Assert(cmdtesting.Stdout(context), gc.Equals, ""+
 		"{\"model\":\"dummyenv\",\"machines\":{\"0\":{\"juju-status\":{\"current\":\"started\"},\"dns-name\":\"10.0.0.1\",\"ip-addresses\":[\"10.0.0.1\",\"10.0.1.1\"],\"instance-id\":\"juju-badd06-0\",\"machine-status\":{},\"series\":\"trusty\",\"network-interfaces\":{\"eth0\":{\"ip-addresses\":[\"10.0.0.1\",\"10.0.1.1\"],\"mac-address\":\"aa:bb:cc:dd:ee:ff\",\"is-up\":true}},\"constraints\":\"mem=3584M\",\"hardware\":\"availability-zone=us-east-1\"},\"1\":{\"juju-status\":{\"current\":\"started\"},\"dns-name\":\"10.0.0.2\",\"ip-addresses\":[\"10.0.0.2\",\"10.0.1.2\"],\"instance-id\":\"juju-badd06-1\",\"machine-status\":{},\"series\":\"trusty\",\"network-interfaces\":{\"eth0\":{\"ip-addresses\":[\"10.0.0.2\",\"10.0.1.2\"],\"mac-address\":\"aa:bb:cc:dd:ee:ff\",\"is-up\":true}},\"containers\":{\"1/lxd/0\":{\"juju-status\":{\"current\":\"pending\"},\"dns-name\":\"10.0.0.3\",\"ip-addresses\":[\"10.0.0.3\",\"10.0.1.3\"],\"instance-id\":\"juju-badd06-1-lxd-0\",\"machine-status\":{},\"series\":\"trusty\",\"network-interfaces\":{\"eth0\":{\"ip-addresses\":[\"10.0.0.3\",\"10.0.1.3\"],\"mac-address\":\"aa:bb:cc:dd:ee:ff\",\"is-up\":true}}}}}}}\n")
 }
+
+func (s *MachineShowCommandSuite) TestShowMachineProviderMetadata(c *gc.C) {
+	metadataAPI := &fakeInstanceMetadataAPI{
+		metadata: &params.InstanceMetadata{
+			InstanceId:      "juju-badd06-0",
+			InstanceProfile: "juju-controller",
+			ImageId:         "ami-0abc",
+			Tenancy:         "dedicated",
+			NetworkInterfaces: []params.InstanceNetworkInterface{{
+				ProviderId:       "eni-0abc",
+				MACAddress:       "aa:bb:cc:dd:ee:ff",
+				ProviderSubnetId: "subnet-0abc",
+				Addresses:        []params.Address{{Value: "10.0.0.1"}, {Value: "10.0.1.1"}},
+			}},
+		},
+	}
+	command := machine.NewShowCommandForTest(&fakeStatusAPI{}, metadataAPI)
+	context, err := cmdtesting.RunCommand(c, command, "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(context), gc.Equals, ""+
+		"model: dummyenv\n"+
+		"machines:\n"+
+		"  \"0\":\n"+
+		"    juju-status:\n"+
+		"      current: started\n"+
+		"    dns-name: 10.0.0.1\n"+
+		"    ip-addresses:\n"+
+		"    - 10.0.0.1\n"+
+		"    - 10.0.1.1\n"+
+		"    instance-id: juju-badd06-0\n"+
+		"    series: trusty\n"+
+		"    network-interfaces:\n"+
+		"      eth0:\n"+
+		"        ip-addresses:\n"+
+		"        - 10.0.0.1\n"+
+		"        - 10.0.1.1\n"+
+		"        mac-address: aa:bb:cc:dd:ee:ff\n"+
+		"        is-up: true\n"+
+		"    constraints: mem=3584M\n"+
+		"    hardware: availability-zone=us-east-1\n"+
+		"    provider-metadata:\n"+
+		"      instance-profile: juju-controller\n"+
+		"      image-id: ami-0abc\n"+
+		"      tenancy: dedicated\n"+
+		"      network-interfaces:\n"+
+		"      - provider-id: eni-0abc\n"+
+		"        device-index: 0\n"+
+		"        mac-address: aa:bb:cc:dd:ee:ff\n"+
+		"        provider-subnet-id: subnet-0abc\n"+
+		"        addresses:\n"+
+		"        - 10.0.0.1\n"+
+		"        - 10.0.1.1\n")
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -16,6 +17,7 @@ import (
 	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/api/modelconfig"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/modelcmd"
@@ -32,6 +34,15 @@ are displayed.
 Supplying one key name returns only the value for the key. Supplying key=value
 will set the supplied key to the supplied value, this can be repeated for
 multiple keys. You can also specify a yaml file containing key values.
+
+The --schema option describes the keys available to the model, or the
+supplied key, including any examples, valid values and deprecation notes
+specific to the model's cloud.
+
+The --history option lists the changes made to the model's configuration,
+or to the supplied key, most recent first, with the user that made each
+change. The values of secret keys are not shown. Changes older than the
+max-config-history-age key are discarded.
 `
 	modelConfigHelpDocKeys = `
 The following keys are available:
@@ -45,6 +56,8 @@ Examples:
     juju model-config path/to/file.yaml
     juju model-config -m othercontroller:mymodel default-series=yakkety test-mode=false
     juju model-config --reset default-series test-mode
+    juju model-config --schema vpc-id
+    juju model-config --history http-proxy
 
 See also:
     models
@@ -72,6 +85,8 @@ type configCommand struct {
 	keys       []string
 	reset      []string // Holds the keys to be reset until parsed.
 	resetKeys  []string // Holds the keys to be reset once parsed.
+	schema     bool
+	history    bool
 	setOptions common.ConfigFlag
 }
 
@@ -82,6 +97,8 @@ type configCommandAPI interface {
 	ModelGetWithMetadata() (config.ConfigValues, error)
 	ModelSet(config map[string]interface{}) error
 	ModelUnset(keys ...string) error
+	ConfigSchema() (config.Docs, error)
+	ModelConfigHistory(key string) ([]params.ModelConfigChange, error)
 }
 
 // Info implements part of the cmd.Command interface.
@@ -117,6 +134,8 @@ func (c *configCommand) SetFlags(f *gnuflag.FlagSet) {
 		"yaml":    cmd.FormatYaml,
 	})
 	f.Var(cmd.NewAppendStringsValue(&c.reset), "reset", "Reset the provided comma delimited keys")
+	f.BoolVar(&c.schema, "schema", false, "Describe the available keys, or the provided key")
+	f.BoolVar(&c.history, "history", false, "List the changes made to the model's configuration, or to the provided key")
 }
 
 // Init implements part of the cmd.Command interface.
@@ -129,6 +148,16 @@ func (c *configCommand) Init(args []string) error {
 		return errors.Trace(err)
 	}
 
+	if c.schema && c.history {
+		return errors.New("cannot describe keys and list history simultaneously")
+	}
+	if c.schema {
+		return c.handleSchema(args)
+	}
+	if c.history {
+		return c.handleHistory(args)
+	}
+
 	switch len(args) {
 	case 0:
 		return c.handleZeroArgs()
@@ -139,6 +168,34 @@ func (c *configCommand) Init(args []string) error {
 	}
 }
 
+// handleSchema handles the case where the schema is requested, for all
+// keys or a single key.
+func (c *configCommand) handleSchema(args []string) error {
+	if len(c.reset) > 0 {
+		return errors.New("cannot describe and reset model values simultaneously")
+	}
+	if len(args) > 1 || (len(args) == 1 && strings.Contains(args[0], "=")) {
+		return errors.New("can only describe a single key, or all keys")
+	}
+	c.keys = args
+	c.action = c.getSchema
+	return nil
+}
+
+// handleHistory handles the case where the history of changes is
+// requested, for all keys or a single key.
+func (c *configCommand) handleHistory(args []string) error {
+	if len(c.reset) > 0 {
+		return errors.New("cannot list history and reset model values simultaneously")
+	}
+	if len(args) > 1 || (len(args) == 1 && strings.Contains(args[0], "=")) {
+		return errors.New("can only list the history of a single key, or all keys")
+	}
+	c.keys = args
+	c.action = c.getHistory
+	return nil
+}
+
 // handleZeroArgs handles the case where there are no positional args.
 func (c *configCommand) handleZeroArgs() error {
 	// If reset is empty we're getting configuration
@@ -295,7 +352,26 @@ func (c *configCommand) setConfig(client configCommandAPI, ctx *cmd.Context) err
 	if err := c.verifyKnownKeys(client, keys); err != nil {
 		return errors.Trace(err)
 	}
-	return block.ProcessBlockedError(client.ModelSet(values), block.BlockChange)
+	return block.ProcessBlockedError(configNotValidError(client.ModelSet(values)), block.BlockChange)
+}
+
+// configNotValidError returns an error listing, one per line, the
+// reasons that the controller reports the model config is not valid.
+// Other errors are returned unchanged.
+func configNotValidError(err error) error {
+	apiErr, ok := errors.Cause(err).(*params.Error)
+	if !ok || apiErr.Info == nil || len(apiErr.Info.ConfigErrors) == 0 {
+		return err
+	}
+	lines := []string{"model config not valid:"}
+	for _, attrErr := range apiErr.Info.ConfigErrors {
+		if attrErr.Attribute == "" {
+			lines = append(lines, "  "+attrErr.Message)
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %s", attrErr.Attribute, attrErr.Message))
+	}
+	return errors.New(strings.Join(lines, "\n"))
 }
 
 // get writes the value of a single key or the full output for the model to the cmd.Context.
@@ -339,6 +415,83 @@ func (c *configCommand) getConfig(client configCommandAPI, ctx *cmd.Context) err
 	return c.out.Write(ctx, attrs)
 }
 
+// getSchema writes the documentation for a single key, or for all the
+// keys available to the model, to the cmd.Context.
+func (c *configCommand) getSchema(client configCommandAPI, ctx *cmd.Context) error {
+	docs, err := client.ConfigSchema()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	fields := make(map[string]common.PrintConfigSchema)
+	for name, doc := range docs {
+		if c.isModelAttribute(name) {
+			continue
+		}
+		if len(c.keys) == 1 && name != c.keys[0] {
+			continue
+		}
+		fields[name] = common.PrintConfigSchema{
+			Description: doc.Description,
+			Type:        fmt.Sprintf("%s", doc.Type),
+			Immutable:   doc.Immutable,
+			Example:     doc.Example,
+			Values:      doc.Values,
+			Deprecated:  doc.Deprecated,
+		}
+	}
+	if len(c.keys) == 1 && len(fields) == 0 {
+		return errors.Errorf("key %q not found in the model's config schema", c.keys[0])
+	}
+	if c.out.Name() == "tabular" {
+		// There is no tabular form of the schema, so
+		// fall back to YAML.
+		return c.out.WriteFormatter(ctx, cmd.FormatYaml, fields)
+	}
+	return c.out.Write(ctx, fields)
+}
+
+// configChange holds a change to a model config key, for formatting.
+type configChange struct {
+	Time     time.Time   `yaml:"time" json:"time"`
+	User     string      `yaml:"user,omitempty" json:"user,omitempty"`
+	Key      string      `yaml:"key" json:"key"`
+	Old      interface{} `yaml:"old,omitempty" json:"old,omitempty"`
+	New      interface{} `yaml:"new,omitempty" json:"new,omitempty"`
+	Redacted bool        `yaml:"redacted,omitempty" json:"redacted,omitempty"`
+}
+
+// getHistory writes the changes made to the model's config, or to a
+// single key, to the cmd.Context.
+func (c *configCommand) getHistory(client configCommandAPI, ctx *cmd.Context) error {
+	var key string
+	if len(c.keys) == 1 {
+		key = c.keys[0]
+	}
+	changes, err := client.ModelConfigHistory(key)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	history := make([]configChange, len(changes))
+	for i, change := range changes {
+		history[i] = configChange{
+			Time:     change.Time,
+			User:     change.User,
+			Key:      change.Key,
+			Old:      change.Old,
+			New:      change.New,
+			Redacted: change.Redacted,
+		}
+	}
+	if c.out.Name() == "tabular" {
+		if len(history) == 0 {
+			ctx.Infof("No changes recorded.")
+			return nil
+		}
+		return c.out.WriteFormatter(ctx, formatConfigHistoryTabular, history)
+	}
+	return c.out.Write(ctx, history)
+}
+
 // verifyKnownKeys is a helper to validate the keys we are operating with
 // against the set of known attributes from the model.
 func (c *configCommand) verifyKnownKeys(client configCommandAPI, keys []string) error {
@@ -403,6 +556,46 @@ func formatConfigTabular(writer io.Writer, value interface{}) error {
 	return nil
 }
 
+// formatConfigHistoryTabular writes a tabular summary of config changes.
+func formatConfigHistoryTabular(writer io.Writer, value interface{}) error {
+	history, ok := value.([]configChange)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", history, value)
+	}
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Time", "User", "Key", "Old", "New")
+	for _, change := range history {
+		oldValue, newValue := "<redacted>", "<redacted>"
+		if !change.Redacted {
+			var err error
+			if oldValue, err = formatConfigHistoryValue(change.Old); err != nil {
+				return errors.Annotatef(err, "formatting value for %q", change.Key)
+			}
+			if newValue, err = formatConfigHistoryValue(change.New); err != nil {
+				return errors.Annotatef(err, "formatting value for %q", change.Key)
+			}
+		}
+		w.Println(common.FormatTime(&change.Time, true), change.User, change.Key, oldValue, newValue)
+	}
+	tw.Flush()
+	return nil
+}
+
+// formatConfigHistoryValue formats an old or new value of a config
+// change for tabular output; a missing value is shown as "-".
+func formatConfigHistoryValue(value interface{}) (string, error) {
+	if value == nil {
+		return "-", nil
+	}
+	out := &bytes.Buffer{}
+	if err := cmd.FormatYaml(out, value); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(out.String(), "\n"), nil
+}
+
 // modelConfigDetails gets ModelDetails when a model is not available
 // to use.
 func (c *configCommand) modelConfigDetails() (map[string]interface{}, error) {
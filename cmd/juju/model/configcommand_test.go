@@ -10,9 +10,13 @@ import (
 	"github.com/juju/cmd/cmdtesting"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	jujucommon "github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/juju/model"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/testing"
 )
 
@@ -69,6 +73,48 @@ func (s *ConfigCommandSuite) TestInit(c *gc.C) {
 			desc:   "test reset interspersed",
 			args:   []string{"--reset", "one", "special=foo", "--reset", "two"},
 			nilErr: true,
+		}, {
+			// Test schema
+			desc:   "schema for all keys succeeds",
+			args:   []string{"--schema"},
+			nilErr: true,
+		}, {
+			desc:   "schema for one key succeeds",
+			args:   []string{"--schema", "one"},
+			nilErr: true,
+		}, {
+			desc:       "schema for multiple keys fails",
+			args:       []string{"--schema", "one", "two"},
+			errorMatch: "can only describe a single key, or all keys",
+		}, {
+			desc:       "schema cannot set values",
+			args:       []string{"--schema", "one=two"},
+			errorMatch: "can only describe a single key, or all keys",
+		}, {
+			desc:       "schema cannot reset values",
+			args:       []string{"--schema", "--reset", "one"},
+			errorMatch: "cannot describe and reset model values simultaneously",
+		}, {
+			// Test history
+			desc:   "history for all keys succeeds",
+			args:   []string{"--history"},
+			nilErr: true,
+		}, {
+			desc:   "history for one key succeeds",
+			args:   []string{"--history", "one"},
+			nilErr: true,
+		}, {
+			desc:       "history for multiple keys fails",
+			args:       []string{"--history", "one", "two"},
+			errorMatch: "can only list the history of a single key, or all keys",
+		}, {
+			desc:       "history cannot reset values",
+			args:       []string{"--history", "--reset", "one"},
+			errorMatch: "cannot list history and reset model values simultaneously",
+		}, {
+			desc:       "history and schema are exclusive",
+			args:       []string{"--history", "--schema"},
+			errorMatch: "cannot describe keys and list history simultaneously",
 		},
 	} {
 		c.Logf("test %d: %s", i, test.desc)
@@ -171,6 +217,119 @@ func (s *ConfigCommandSuite) TestAllValuesTabular(c *gc.C) {
 	c.Assert(output, gc.Equals, expected)
 }
 
+func (s *ConfigCommandSuite) setSchema() {
+	s.fake.schema = config.Docs{
+		"name": {Attr: environschema.Attr{
+			Description: "The name of the current model",
+			Type:        environschema.Tstring,
+		}},
+		"vpc-id": {
+			Attr: environschema.Attr{
+				Description: "Use a specific VPC",
+				Type:        environschema.Tstring,
+				Immutable:   true,
+				Example:     "vpc-a1b2c3d4",
+			},
+			Deprecated: "use spaces instead",
+		},
+		"logging-config": {Attr: environschema.Attr{
+			Description: "The configuration string to use when configuring Juju agent logging",
+			Type:        environschema.Tstring,
+		}},
+	}
+}
+
+func (s *ConfigCommandSuite) TestSchemaSingleKey(c *gc.C) {
+	s.setSchema()
+	context, err := s.run(c, "--schema", "vpc-id")
+	c.Assert(err, jc.ErrorIsNil)
+
+	output := cmdtesting.Stdout(context)
+	c.Assert(output, gc.Equals, ""+
+		"vpc-id:\n"+
+		"  type: string\n"+
+		"  description: Use a specific VPC\n"+
+		"  immutable: true\n"+
+		"  example: vpc-a1b2c3d4\n"+
+		"  deprecated: use spaces instead\n")
+}
+
+func (s *ConfigCommandSuite) TestSchemaAllKeysJSON(c *gc.C) {
+	s.setSchema()
+	context, err := s.run(c, "--schema", "--format=json")
+	c.Assert(err, jc.ErrorIsNil)
+
+	output := cmdtesting.Stdout(context)
+	c.Assert(output, gc.Equals, ""+
+		`{"logging-config":{"type":"string","description":"The configuration string to use when configuring Juju agent logging"},`+
+		`"vpc-id":{"type":"string","description":"Use a specific VPC","immutable":true,"example":"vpc-a1b2c3d4","deprecated":"use spaces instead"}}`+"\n")
+}
+
+func (s *ConfigCommandSuite) setHistory() {
+	now := testing.NonZeroTime().UTC()
+	s.fake.history = []params.ModelConfigChange{{
+		Time: now,
+		User: "bob",
+		Key:  "http-proxy",
+		Old:  "http://old-proxy",
+		New:  "http://new-proxy",
+	}, {
+		Time:     now,
+		User:     "bob",
+		Key:      "api-token",
+		Redacted: true,
+	}, {
+		Time: now,
+		Key:  "test-mode",
+		New:  true,
+	}}
+}
+
+func (s *ConfigCommandSuite) TestHistoryTabular(c *gc.C) {
+	s.setHistory()
+	context, err := s.run(c, "--history")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.historyKey, gc.Equals, "")
+
+	timestamp := jujucommon.FormatTime(&s.fake.history[0].Time, true)
+	output := cmdtesting.Stdout(context)
+	c.Assert(output, gc.Equals, ""+
+		"Time                  User  Key         Old               New\n"+
+		timestamp+"  bob   http-proxy  http://old-proxy  http://new-proxy\n"+
+		timestamp+"  bob   api-token   <redacted>        <redacted>\n"+
+		timestamp+"        test-mode   -                 true\n"+
+		"\n")
+}
+
+func (s *ConfigCommandSuite) TestHistorySingleKeyYAML(c *gc.C) {
+	s.setHistory()
+	s.fake.history = s.fake.history[:1]
+	context, err := s.run(c, "--history", "http-proxy", "--format=yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.historyKey, gc.Equals, "http-proxy")
+
+	output := cmdtesting.Stdout(context)
+	c.Assert(output, gc.Matches, ""+
+		"- time: .*\n"+
+		"  user: bob\n"+
+		"  key: http-proxy\n"+
+		"  old: http://old-proxy\n"+
+		"  new: http://new-proxy\n")
+}
+
+func (s *ConfigCommandSuite) TestHistoryNone(c *gc.C) {
+	context, err := s.run(c, "--history")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(context), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(context), gc.Equals, "No changes recorded.\n")
+}
+
+func (s *ConfigCommandSuite) TestSchemaUnknownKey(c *gc.C) {
+	s.setSchema()
+	_, err := s.run(c, "--schema", "unknown")
+	c.Assert(err, gc.ErrorMatches, `key "unknown" not found in the model's config schema`)
+}
+
 func (s *ConfigCommandSuite) TestSetAgentVersion(c *gc.C) {
 	_, err := s.run(c, "agent-version=2.0.0")
 	c.Assert(err, gc.ErrorMatches, `"agent-version"" must be set via "upgrade-juju"`)
@@ -234,6 +393,24 @@ func (s *ConfigCommandSuite) TestBlockedError(c *gc.C) {
 	testing.AssertOperationWasBlocked(c, err, ".*TestBlockedError.*")
 }
 
+func (s *ConfigCommandSuite) TestConfigNotValidError(c *gc.C) {
+	s.fake.err = &params.Error{
+		Message: "bad model; bad CIDR",
+		Code:    params.CodeConfigNotValid,
+		Info: &params.ErrorInfo{
+			ConfigErrors: []params.ConfigAttributeError{
+				{Message: "bad model"},
+				{Attribute: "ssh-allow", Message: "bad CIDR"},
+			},
+		},
+	}
+	_, err := s.run(c, "ssh-allow=nonsense")
+	c.Assert(err, gc.ErrorMatches, `
+model config not valid:
+  bad model
+  ssh-allow: bad CIDR`[1:])
+}
+
 func (s *ConfigCommandSuite) TestResetPassesValues(c *gc.C) {
 	_, err := s.run(c, "--reset", "special,running")
 	c.Assert(err, jc.ErrorIsNil)
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/utils/clock"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/base"
@@ -57,6 +58,38 @@ func NewDumpCommandForTest(api DumpModelAPI, store jujuclient.ClientStore) cmd.C
 	return modelcmd.Wrap(cmd)
 }
 
+// NewMarkUnmanagedCommandForTest returns a markUnmanagedCommand with
+// the api provided as specified.
+func NewMarkUnmanagedCommandForTest(api MarkUnmanagedAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &markUnmanagedCommand{api: api}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewSetModelExpiryCommandForTest returns a setModelExpiryCommand with
+// the api and clock provided as specified.
+func NewSetModelExpiryCommandForTest(api SetModelExpiryAPI, clock clock.Clock, store jujuclient.ClientStore) cmd.Command {
+	cmd := &setModelExpiryCommand{api: api, clock: clock}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewPinAgentVersionCommandForTest returns a pinAgentVersionCommand
+// with the api provided as specified.
+func NewPinAgentVersionCommandForTest(api PinAgentVersionAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &pinAgentVersionCommand{api: api}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewOrphanedResourcesCommandForTest returns an orphanedResourcesCommand
+// with the api provided as specified.
+func NewOrphanedResourcesCommandForTest(api OrphanedResourcesAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &orphanedResourcesCommand{api: api}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
 // NewDumpDBCommandForTest returns a DumpDBCommand with the api provided as specified.
 func NewDumpDBCommandForTest(api DumpDBAPI, store jujuclient.ClientStore) cmd.Command {
 	cmd := &dumpDBCommand{api: api}
@@ -10,6 +10,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
 	jujucloud "github.com/juju/juju/cloud"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/testing"
@@ -42,6 +43,9 @@ type fakeEnvAPI struct {
 	values        map[string]interface{}
 	cloud, region string
 	defaults      config.ConfigValues
+	schema        config.Docs
+	history       []params.ModelConfigChange
+	historyKey    string
 	err           error
 	keys          []string
 	resetKeys     []string
@@ -51,6 +55,15 @@ func (f *fakeEnvAPI) Close() error {
 	return nil
 }
 
+func (f *fakeEnvAPI) ConfigSchema() (config.Docs, error) {
+	return f.schema, nil
+}
+
+func (f *fakeEnvAPI) ModelConfigHistory(key string) ([]params.ModelConfigChange, error) {
+	f.historyKey = key
+	return f.history, f.err
+}
+
 func (f *fakeEnvAPI) ModelGet() (map[string]interface{}, error) {
 	return f.values, nil
 }
@@ -0,0 +1,143 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/api/modelconfig"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/environs/config"
+)
+
+const markUnmanagedHelpDoc = `
+Marks instances in the model's cloud as unmanaged, so that they are never
+stopped by the provisioner when it harvests unknown instances. This is
+useful when instances that were not started by Juju share the model's
+cloud account or region.
+
+The instances are recorded in the model's "provisioner-harvest-exempt"
+configuration. Use the --remove option to make instances eligible for
+harvesting once more.
+
+Examples:
+
+    juju mark-unmanaged i-0123456789abcdef0
+    juju mark-unmanaged --remove i-0123456789abcdef0
+
+See also:
+    model-config
+`
+
+// NewMarkUnmanagedCommand returns a command to exempt instances from
+// harvesting.
+func NewMarkUnmanagedCommand() cmd.Command {
+	return modelcmd.Wrap(&markUnmanagedCommand{})
+}
+
+type markUnmanagedCommand struct {
+	modelcmd.ModelCommandBase
+	api MarkUnmanagedAPI
+
+	instanceIds []string
+	remove      bool
+}
+
+// MarkUnmanagedAPI defines the API methods used by the mark-unmanaged
+// command.
+type MarkUnmanagedAPI interface {
+	Close() error
+	ModelGet() (map[string]interface{}, error)
+	ModelSet(config map[string]interface{}) error
+}
+
+// Info implements Command.
+func (c *markUnmanagedCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "mark-unmanaged",
+		Args:    "<instance-id> [...]",
+		Purpose: "Exempts instances from harvesting by the provisioner.",
+		Doc:     markUnmanagedHelpDoc,
+	}
+}
+
+// SetFlags implements Command.
+func (c *markUnmanagedCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.remove, "remove", false, "Make the instances eligible for harvesting again")
+}
+
+// Init implements Command.
+func (c *markUnmanagedCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no instance specified")
+	}
+	for _, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, ", ") {
+			return errors.Errorf("invalid instance ID %q", arg)
+		}
+	}
+	c.instanceIds = args
+	return nil
+}
+
+func (c *markUnmanagedCommand) getAPI() (MarkUnmanagedAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Annotate(err, "opening API connection")
+	}
+	return modelconfig.NewClient(root), nil
+}
+
+// Run implements Command.
+func (c *markUnmanagedCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	attrs, err := client.ModelGet()
+	if err != nil {
+		return err
+	}
+	var exempt []string
+	if v, ok := attrs[config.ProvisionerHarvestExemptKey].(string); ok && v != "" {
+		for _, id := range strings.Split(v, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				exempt = append(exempt, id)
+			}
+		}
+	}
+	existing := set.NewStrings(exempt...)
+	if c.remove {
+		remove := set.NewStrings(c.instanceIds...)
+		var remaining []string
+		for _, id := range exempt {
+			if !remove.Contains(id) {
+				remaining = append(remaining, id)
+			}
+		}
+		exempt = remaining
+	} else {
+		for _, id := range c.instanceIds {
+			if !existing.Contains(id) {
+				exempt = append(exempt, id)
+				existing.Add(id)
+			}
+		}
+	}
+	return block.ProcessBlockedError(client.ModelSet(map[string]interface{}{
+		config.ProvisionerHarvestExemptKey: strings.Join(exempt, ","),
+	}), block.BlockChange)
+}
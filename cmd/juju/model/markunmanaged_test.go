@@ -0,0 +1,109 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type MarkUnmanagedCommandSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake  fakeMarkUnmanagedClient
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&MarkUnmanagedCommandSuite{})
+
+type fakeMarkUnmanagedClient struct {
+	gitjujutesting.Stub
+	attrs map[string]interface{}
+}
+
+func (f *fakeMarkUnmanagedClient) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeMarkUnmanagedClient) ModelGet() (map[string]interface{}, error) {
+	f.MethodCall(f, "ModelGet")
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	return f.attrs, nil
+}
+
+func (f *fakeMarkUnmanagedClient) ModelSet(attrs map[string]interface{}) error {
+	f.MethodCall(f, "ModelSet", attrs)
+	return f.NextErr()
+}
+
+func (s *MarkUnmanagedCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = fakeMarkUnmanagedClient{
+		attrs: map[string]interface{}{
+			"provisioner-harvest-exempt": "i-0, i-1",
+		},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		testing.ModelTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+}
+
+func (s *MarkUnmanagedCommandSuite) run(c *gc.C, args ...string) error {
+	_, err := cmdtesting.RunCommand(c, model.NewMarkUnmanagedCommandForTest(&s.fake, s.store), args...)
+	return err
+}
+
+func (s *MarkUnmanagedCommandSuite) TestInitNoArgs(c *gc.C) {
+	err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "no instance specified")
+}
+
+func (s *MarkUnmanagedCommandSuite) TestInitInvalidID(c *gc.C) {
+	err := s.run(c, "i-0,i-1")
+	c.Assert(err, gc.ErrorMatches, `invalid instance ID "i-0,i-1"`)
+}
+
+func (s *MarkUnmanagedCommandSuite) TestMarkUnmanaged(c *gc.C) {
+	err := s.run(c, "i-1", "i-2")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"ModelGet", nil},
+		{"ModelSet", []interface{}{map[string]interface{}{
+			"provisioner-harvest-exempt": "i-0,i-1,i-2",
+		}}},
+		{"Close", nil},
+	})
+}
+
+func (s *MarkUnmanagedCommandSuite) TestMarkUnmanagedRemove(c *gc.C) {
+	err := s.run(c, "--remove", "i-0", "i-3")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCall(c, 1, "ModelSet", map[string]interface{}{
+		"provisioner-harvest-exempt": "i-1",
+	})
+}
+
+func (s *MarkUnmanagedCommandSuite) TestMarkUnmanagedError(c *gc.C) {
+	s.fake.SetErrors(errors.New("boom"))
+	err := s.run(c, "i-2")
+	c.Assert(err, gc.ErrorMatches, "boom")
+	s.fake.CheckCallNames(c, "ModelGet", "Close")
+}
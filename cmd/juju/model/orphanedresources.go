@@ -0,0 +1,140 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"io"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/api/orphanedresources"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+const orphanedResourcesHelpDoc = `
+Lists the instances and volumes in the model's cloud that are tagged as
+belonging to the model, but which the model no longer tracks. Such
+resources are typically left behind by failed provisioning or by
+interrupted removals, and continue to be billed.
+
+The command is read-only: nothing is removed. The resources reported can
+be reviewed and removed using the cloud's own tools.
+
+Not all clouds support listing resources by tag.
+
+Examples:
+
+    juju list-orphaned-resources
+    juju orphaned-resources -m mymodel --format yaml
+
+See also:
+    destroy-model
+`
+
+// NewOrphanedResourcesCommand returns a command to list the cloud
+// resources that a model no longer tracks.
+func NewOrphanedResourcesCommand() cmd.Command {
+	return modelcmd.Wrap(&orphanedResourcesCommand{})
+}
+
+type orphanedResourcesCommand struct {
+	modelcmd.ModelCommandBase
+	out cmd.Output
+	api OrphanedResourcesAPI
+}
+
+// OrphanedResourcesAPI defines the API methods used by the
+// list-orphaned-resources command.
+type OrphanedResourcesAPI interface {
+	Close() error
+	OrphanedResources() ([]params.OrphanedResource, error)
+}
+
+// Info implements Command.
+func (c *orphanedResourcesCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list-orphaned-resources",
+		Purpose: "Lists cloud resources that the model no longer tracks.",
+		Doc:     orphanedResourcesHelpDoc,
+		Aliases: []string{"orphaned-resources"},
+	}
+}
+
+// SetFlags implements Command.
+func (c *orphanedResourcesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatOrphanedResourcesTabular,
+	})
+}
+
+// Init implements Command.
+func (c *orphanedResourcesCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *orphanedResourcesCommand) getAPI() (OrphanedResourcesAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return orphanedresources.NewClient(root), nil
+}
+
+// Run implements Command.
+func (c *orphanedResourcesCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	results, err := client.OrphanedResources()
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No orphaned resources found.")
+		return nil
+	}
+	resources := make([]orphanedResource, len(results))
+	for i, r := range results {
+		resources[i] = orphanedResource{
+			Kind: r.Kind,
+			ID:   r.ID,
+			Tags: r.Tags,
+		}
+	}
+	return c.out.Write(ctx, resources)
+}
+
+type orphanedResource struct {
+	Kind string            `yaml:"kind" json:"kind"`
+	ID   string            `yaml:"id" json:"id"`
+	Tags map[string]string `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+func formatOrphanedResourcesTabular(writer io.Writer, value interface{}) error {
+	resources, ok := value.([]orphanedResource)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", resources, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Kind", "ID")
+	for _, r := range resources {
+		w.Println(r.Kind, r.ID)
+	}
+	tw.Flush()
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type OrphanedResourcesCommandSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake  fakeOrphanedResourcesClient
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&OrphanedResourcesCommandSuite{})
+
+type fakeOrphanedResourcesClient struct {
+	gitjujutesting.Stub
+	resources []params.OrphanedResource
+}
+
+func (f *fakeOrphanedResourcesClient) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeOrphanedResourcesClient) OrphanedResources() ([]params.OrphanedResource, error) {
+	f.MethodCall(f, "OrphanedResources")
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	return f.resources, nil
+}
+
+func (s *OrphanedResourcesCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = fakeOrphanedResourcesClient{
+		resources: []params.OrphanedResource{{
+			Kind: "instance",
+			ID:   "i-0123",
+			Tags: map[string]string{"juju-machine-id": "foo-machine-3"},
+		}, {
+			Kind: "volume",
+			ID:   "vol-4567",
+		}},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		testing.ModelTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+}
+
+func (s *OrphanedResourcesCommandSuite) TestTabular(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, model.NewOrphanedResourcesCommandForTest(&s.fake, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCallNames(c, "OrphanedResources", "Close")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Kind      ID
+instance  i-0123
+volume    vol-4567
+
+`[1:])
+}
+
+func (s *OrphanedResourcesCommandSuite) TestYAML(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, model.NewOrphanedResourcesCommandForTest(&s.fake, s.store), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+- kind: instance
+  id: i-0123
+  tags:
+    juju-machine-id: foo-machine-3
+- kind: volume
+  id: vol-4567
+`[1:])
+}
+
+func (s *OrphanedResourcesCommandSuite) TestNone(c *gc.C) {
+	s.fake.resources = nil
+	ctx, err := cmdtesting.RunCommand(c, model.NewOrphanedResourcesCommandForTest(&s.fake, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No orphaned resources found.\n")
+}
+
+func (s *OrphanedResourcesCommandSuite) TestError(c *gc.C) {
+	s.fake.SetErrors(errors.New("boom"))
+	_, err := cmdtesting.RunCommand(c, model.NewOrphanedResourcesCommandForTest(&s.fake, s.store))
+	c.Assert(err, gc.ErrorMatches, "boom")
+	s.fake.CheckCallNames(c, "OrphanedResources", "Close")
+}
+
+func (s *OrphanedResourcesCommandSuite) TestInitRejectsArgs(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, model.NewOrphanedResourcesCommandForTest(&s.fake, s.store), "foo")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["foo"\]`)
+}
@@ -0,0 +1,149 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/version"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/agentversionpins"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const pinAgentVersionHelpDoc = `
+Pins the agents of machines, or of the machines hosting an application's
+units, to a range of agent versions. While the model's agent version is
+outside the range, upgrades of the pinned agents are held back and they
+continue to run the version they are running; this allows a staged
+upgrade to exclude some machines until they are ready.
+
+The range is given as MIN..MAX, where either bound may be omitted; a
+single version pins the agents to exactly that version. Pins are shown
+in the output of juju status. Use the --remove option to remove the pins
+of the given machines and applications.
+
+Examples:
+
+    juju pin-agent-version 2.3.4 0 1
+    juju pin-agent-version ..2.3.9 mysql
+    juju pin-agent-version --remove mysql
+
+See also:
+    upgrade-juju
+    status
+`
+
+// NewPinAgentVersionCommand returns a command to pin the agent
+// versions of machines and applications.
+func NewPinAgentVersionCommand() cmd.Command {
+	return modelcmd.Wrap(&pinAgentVersionCommand{})
+}
+
+type pinAgentVersionCommand struct {
+	modelcmd.ModelCommandBase
+	api PinAgentVersionAPI
+
+	versionRange string
+	tags         []names.Tag
+	remove       bool
+}
+
+// PinAgentVersionAPI defines the API methods used by the
+// pin-agent-version command.
+type PinAgentVersionAPI interface {
+	Close() error
+	SetAgentVersionPins(versionRange string, tags ...names.Tag) error
+}
+
+// Info implements Command.
+func (c *pinAgentVersionCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "pin-agent-version",
+		Args:    "[<version range>] <machine|application> [...]",
+		Purpose: "Pins machines or applications to a range of agent versions.",
+		Doc:     pinAgentVersionHelpDoc,
+	}
+}
+
+// SetFlags implements Command.
+func (c *pinAgentVersionCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.remove, "remove", false, "Remove the agent version pins")
+}
+
+// Init implements Command.
+func (c *pinAgentVersionCommand) Init(args []string) error {
+	if !c.remove {
+		if len(args) == 0 {
+			return errors.New("no version range specified")
+		}
+		if err := validateVersionRange(args[0]); err != nil {
+			return errors.Trace(err)
+		}
+		c.versionRange, args = args[0], args[1:]
+	}
+	if len(args) == 0 {
+		return errors.New("no machine or application specified")
+	}
+	for _, arg := range args {
+		switch {
+		case names.IsValidMachine(arg):
+			c.tags = append(c.tags, names.NewMachineTag(arg))
+		case names.IsValidApplication(arg):
+			c.tags = append(c.tags, names.NewApplicationTag(arg))
+		default:
+			return errors.Errorf("invalid machine or application %q", arg)
+		}
+	}
+	return nil
+}
+
+// validateVersionRange checks the syntax of the version range; the
+// controller checks that the range is not empty.
+func validateVersionRange(s string) error {
+	bounds := []string{s}
+	if i := strings.Index(s, ".."); i >= 0 {
+		bounds = []string{s[:i], s[i+2:]}
+	}
+	valid := false
+	for _, bound := range bounds {
+		if bound == "" {
+			continue
+		}
+		if _, err := version.Parse(bound); err != nil {
+			return errors.Errorf("invalid version range %q", s)
+		}
+		valid = true
+	}
+	if !valid {
+		return errors.Errorf("invalid version range %q", s)
+	}
+	return nil
+}
+
+func (c *pinAgentVersionCommand) getAPI() (PinAgentVersionAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Annotate(err, "opening API connection")
+	}
+	return agentversionpins.NewClient(root), nil
+}
+
+// Run implements Command.
+func (c *pinAgentVersionCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.SetAgentVersionPins(c.versionRange, c.tags...)
+}
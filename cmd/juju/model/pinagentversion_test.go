@@ -0,0 +1,119 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/cmd/juju/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type PinAgentVersionCommandSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake  fakePinAgentVersionClient
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&PinAgentVersionCommandSuite{})
+
+type fakePinAgentVersionClient struct {
+	gitjujutesting.Stub
+}
+
+func (f *fakePinAgentVersionClient) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakePinAgentVersionClient) SetAgentVersionPins(versionRange string, tags ...names.Tag) error {
+	f.MethodCall(f, "SetAgentVersionPins", versionRange, tags)
+	return f.NextErr()
+}
+
+func (s *PinAgentVersionCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = fakePinAgentVersionClient{}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		testing.ModelTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+}
+
+func (s *PinAgentVersionCommandSuite) run(c *gc.C, args ...string) error {
+	_, err := cmdtesting.RunCommand(c, model.NewPinAgentVersionCommandForTest(&s.fake, s.store), args...)
+	return err
+}
+
+func (s *PinAgentVersionCommandSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no version range specified",
+	}, {
+		args: []string{"2.3.4"},
+		err:  "no machine or application specified",
+	}, {
+		args: []string{"--remove"},
+		err:  "no machine or application specified",
+	}, {
+		args: []string{"..", "0"},
+		err:  `invalid version range ".."`,
+	}, {
+		args: []string{"2.x..", "0"},
+		err:  `invalid version range "2.x.."`,
+	}, {
+		args: []string{"2.3.4", "mysql/0"},
+		err:  `invalid machine or application "mysql/0"`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := s.run(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+	s.fake.CheckNoCalls(c)
+}
+
+func (s *PinAgentVersionCommandSuite) TestPin(c *gc.C) {
+	err := s.run(c, "2.3.1..2.3.4", "0", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"SetAgentVersionPins", []interface{}{
+			"2.3.1..2.3.4",
+			[]names.Tag{names.NewMachineTag("0"), names.NewApplicationTag("mysql")},
+		}},
+		{"Close", nil},
+	})
+}
+
+func (s *PinAgentVersionCommandSuite) TestRemove(c *gc.C) {
+	err := s.run(c, "--remove", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"SetAgentVersionPins", []interface{}{
+			"",
+			[]names.Tag{names.NewApplicationTag("mysql")},
+		}},
+		{"Close", nil},
+	})
+}
+
+func (s *PinAgentVersionCommandSuite) TestPinError(c *gc.C) {
+	s.fake.SetErrors(errors.New(`application "mysql" not found`))
+	err := s.run(c, "2.3.4", "mysql")
+	c.Assert(err, gc.ErrorMatches, `application "mysql" not found`)
+}
@@ -0,0 +1,117 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const setModelExpiryHelpDoc = `
+Sets how much longer an ephemeral model lives before it is destroyed,
+along with its storage. The time to live is counted from now. Giving
+"never" keeps the model indefinitely.
+
+Only admins of the model, or of the controller, may change its expiry.
+
+Examples:
+
+    juju set-model-expiry 4h
+    juju set-model-expiry -m ci-1234 never
+
+See also:
+    add-model
+    destroy-model
+`
+
+// NewSetModelExpiryCommand returns a command to change when an
+// ephemeral model is destroyed.
+func NewSetModelExpiryCommand() cmd.Command {
+	return modelcmd.Wrap(&setModelExpiryCommand{clock: clock.WallClock})
+}
+
+type setModelExpiryCommand struct {
+	modelcmd.ModelCommandBase
+	api   SetModelExpiryAPI
+	clock clock.Clock
+
+	ttl time.Duration
+}
+
+// SetModelExpiryAPI defines the API methods used by the
+// set-model-expiry command.
+type SetModelExpiryAPI interface {
+	Close() error
+	SetModelExpiry(model names.ModelTag, expiry time.Time) error
+}
+
+// Info implements Command.
+func (c *setModelExpiryCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set-model-expiry",
+		Args:    "<time to live>|never",
+		Purpose: "Changes when an ephemeral model is destroyed.",
+		Doc:     setModelExpiryHelpDoc,
+	}
+}
+
+// Init implements Command.
+func (c *setModelExpiryCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no time to live specified")
+	}
+	if args[0] != "never" {
+		ttl, err := time.ParseDuration(args[0])
+		if err != nil {
+			return errors.Annotate(err, "invalid time to live")
+		}
+		if ttl <= 0 {
+			return errors.Errorf("invalid time to live %v: must be positive", ttl)
+		}
+		c.ttl = ttl
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *setModelExpiryCommand) getAPI() (SetModelExpiryAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewModelManagerAPIClient()
+}
+
+// Run implements Command.
+func (c *setModelExpiryCommand) Run(ctx *cmd.Context) error {
+	_, modelDetails, err := c.ModelDetails()
+	if err != nil {
+		return errors.Annotate(err, "getting model details")
+	}
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var expiry time.Time
+	if c.ttl != 0 {
+		expiry = c.clock.Now().Add(c.ttl).UTC()
+	}
+	modelTag := names.NewModelTag(modelDetails.ModelUUID)
+	if err := client.SetModelExpiry(modelTag, expiry); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	if expiry.IsZero() {
+		ctx.Infof("Model will not be destroyed automatically.")
+	} else {
+		ctx.Infof("Model will be destroyed at %s.", expiry.Format(time.RFC3339))
+	}
+	return nil
+}
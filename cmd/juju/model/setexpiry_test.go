@@ -0,0 +1,116 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/cmd/juju/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type SetModelExpirySuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake  fakeSetModelExpiryClient
+	clock *gitjujutesting.Clock
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&SetModelExpirySuite{})
+
+type fakeSetModelExpiryClient struct {
+	gitjujutesting.Stub
+}
+
+func (f *fakeSetModelExpiryClient) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeSetModelExpiryClient) SetModelExpiry(model names.ModelTag, expiry time.Time) error {
+	f.MethodCall(f, "SetModelExpiry", model, expiry)
+	return f.NextErr()
+}
+
+func (s *SetModelExpirySuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake.ResetCalls()
+	s.clock = gitjujutesting.NewClock(time.Date(2018, 4, 1, 12, 0, 0, 0, time.UTC))
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		testing.ModelTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+}
+
+func (s *SetModelExpirySuite) run(c *gc.C, args ...string) (string, error) {
+	command := model.NewSetModelExpiryCommandForTest(&s.fake, s.clock, s.store)
+	ctx, err := cmdtesting.RunCommand(c, command, args...)
+	if err != nil {
+		return "", err
+	}
+	return cmdtesting.Stderr(ctx), nil
+}
+
+func (s *SetModelExpirySuite) TestSetExpiry(c *gc.C) {
+	out, err := s.run(c, "4h")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, "Model will be destroyed at 2018-04-01T16:00:00Z.\n")
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"SetModelExpiry", []interface{}{testing.ModelTag, time.Date(2018, 4, 1, 16, 0, 0, 0, time.UTC)}},
+		{"Close", nil},
+	})
+}
+
+func (s *SetModelExpirySuite) TestSetNever(c *gc.C) {
+	out, err := s.run(c, "never")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, "Model will not be destroyed automatically.\n")
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"SetModelExpiry", []interface{}{testing.ModelTag, time.Time{}}},
+		{"Close", nil},
+	})
+}
+
+func (s *SetModelExpirySuite) TestInitErrors(c *gc.C) {
+	for _, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no time to live specified",
+	}, {
+		args: []string{"soon"},
+		err:  `invalid time to live: time: invalid duration .*soon.*`,
+	}, {
+		args: []string{"-1h"},
+		err:  `invalid time to live -1h0m0s: must be positive`,
+	}, {
+		args: []string{"1h", "2h"},
+		err:  `unrecognized args: \["2h"\]`,
+	}} {
+		_, err := s.run(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+	s.fake.CheckNoCalls(c)
+}
+
+func (s *SetModelExpirySuite) TestAPIError(c *gc.C) {
+	s.fake.SetErrors(errors.New("permission denied"))
+	_, err := s.run(c, "4h")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
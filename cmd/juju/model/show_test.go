@@ -517,6 +517,35 @@ func (s *ShowCommandSuite) TestShowBasicWithSLAIncompleteModelsJson(c *gc.C) {
 	s.assertShowOutput(c, "json")
 }
 
+func (s *ShowCommandSuite) TestShowWithOwnershipTransfersJson(c *gc.C) {
+	info := createBasicModelInfo()
+	info.OwnerTeam = "ops"
+	info.OwnershipTransfers = []params.ModelOwnershipTransfer{{
+		Time:    time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC),
+		ByTag:   "user-admin",
+		FromTag: "user-owner",
+		ToTag:   "user-owner",
+		ToTeam:  "ops",
+	}}
+	s.fake.infos = []params.ModelInfoResult{
+		params.ModelInfoResult{Result: info},
+	}
+	s.expectedDisplay = "{\"basic-model\":" +
+		"{\"name\":\"owner/basic-model\"," +
+		"\"short-name\":\"basic-model\"," +
+		"\"model-uuid\":\"deadbeef-0bad-400d-8000-4b1d0d06f00d\"," +
+		"\"controller-uuid\":\"deadbeef-1bad-500d-9000-4b1d0d06f00d\"," +
+		"\"controller-name\":\"testing\"," +
+		"\"owner\":\"owner\"," +
+		"\"owner-team\":\"ops\"," +
+		"\"cloud\":\"altostratus\"," +
+		"\"region\":\"mid-level\"," +
+		"\"life\":\"dead\"," +
+		"\"ownership-transfers\":[{\"time\":\"2018-06-01\",\"by\":\"admin\"," +
+		"\"from-owner\":\"owner\",\"to-owner\":\"owner\",\"to-team\":\"ops\"}]}}\n"
+	s.assertShowOutput(c, "json")
+}
+
 func (s *ShowCommandSuite) TestShowModelWithAgentVersionInJson(c *gc.C) {
 	s.expectedDisplay = "{\"basic-model\":" +
 		"{\"name\":\"owner/basic-model\"," +
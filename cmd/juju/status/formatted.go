@@ -6,6 +6,7 @@ package status
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	"gopkg.in/juju/names.v2"
 
@@ -19,7 +20,7 @@ type formattedStatus struct {
 	Applications       map[string]applicationStatus       `json:"applications"`
 	RemoteApplications map[string]remoteApplicationStatus `json:"application-endpoints,omitempty" yaml:"application-endpoints,omitempty"`
 	Offers             map[string]offerStatus             `json:"offers,omitempty" yaml:"offers,omitempty"`
-	Relations          []relationStatus                   `json:"-" yaml:"-"`
+	Relations          []relationStatus                   `json:"relations,omitempty" yaml:"relations,omitempty"`
 }
 
 type formattedMachineStatus struct {
@@ -66,6 +67,23 @@ type machineStatus struct {
 	Constraints       string                      `json:"constraints,omitempty" yaml:"constraints,omitempty"`
 	Hardware          string                      `json:"hardware,omitempty" yaml:"hardware,omitempty"`
 	HAStatus          string                      `json:"controller-member-status,omitempty" yaml:"controller-member-status,omitempty"`
+	AgentVersionPin   string                      `json:"agent-version-pin,omitempty" yaml:"agent-version-pin,omitempty"`
+	ProviderMetadata  *providerMetadata           `json:"provider-metadata,omitempty" yaml:"provider-metadata,omitempty"`
+}
+
+type providerMetadata struct {
+	InstanceProfile   string                     `json:"instance-profile,omitempty" yaml:"instance-profile,omitempty"`
+	ImageId           string                     `json:"image-id,omitempty" yaml:"image-id,omitempty"`
+	Tenancy           string                     `json:"tenancy,omitempty" yaml:"tenancy,omitempty"`
+	NetworkInterfaces []providerNetworkInterface `json:"network-interfaces,omitempty" yaml:"network-interfaces,omitempty"`
+}
+
+type providerNetworkInterface struct {
+	ProviderId       string   `json:"provider-id" yaml:"provider-id"`
+	DeviceIndex      int      `json:"device-index" yaml:"device-index"`
+	MACAddress       string   `json:"mac-address,omitempty" yaml:"mac-address,omitempty"`
+	ProviderSubnetId string   `json:"provider-subnet-id,omitempty" yaml:"provider-subnet-id,omitempty"`
+	Addresses        []string `json:"addresses,omitempty" yaml:"addresses,omitempty"`
 }
 
 // A goyaml bug means we can't declare these types
@@ -87,21 +105,22 @@ func (s machineStatus) MarshalYAML() (interface{}, error) {
 }
 
 type applicationStatus struct {
-	Err           error                 `json:"-" yaml:",omitempty"`
-	Charm         string                `json:"charm" yaml:"charm"`
-	Series        string                `json:"series"`
-	OS            string                `json:"os"`
-	CharmOrigin   string                `json:"charm-origin" yaml:"charm-origin"`
-	CharmName     string                `json:"charm-name" yaml:"charm-name"`
-	CharmRev      int                   `json:"charm-rev" yaml:"charm-rev"`
-	CanUpgradeTo  string                `json:"can-upgrade-to,omitempty" yaml:"can-upgrade-to,omitempty"`
-	Exposed       bool                  `json:"exposed" yaml:"exposed"`
-	Life          string                `json:"life,omitempty" yaml:"life,omitempty"`
-	StatusInfo    statusInfoContents    `json:"application-status,omitempty" yaml:"application-status"`
-	Relations     map[string][]string   `json:"relations,omitempty" yaml:"relations,omitempty"`
-	SubordinateTo []string              `json:"subordinate-to,omitempty" yaml:"subordinate-to,omitempty"`
-	Units         map[string]unitStatus `json:"units,omitempty" yaml:"units,omitempty"`
-	Version       string                `json:"version,omitempty" yaml:"version,omitempty"`
+	Err             error                 `json:"-" yaml:",omitempty"`
+	Charm           string                `json:"charm" yaml:"charm"`
+	Series          string                `json:"series"`
+	OS              string                `json:"os"`
+	CharmOrigin     string                `json:"charm-origin" yaml:"charm-origin"`
+	CharmName       string                `json:"charm-name" yaml:"charm-name"`
+	CharmRev        int                   `json:"charm-rev" yaml:"charm-rev"`
+	CanUpgradeTo    string                `json:"can-upgrade-to,omitempty" yaml:"can-upgrade-to,omitempty"`
+	Exposed         bool                  `json:"exposed" yaml:"exposed"`
+	Life            string                `json:"life,omitempty" yaml:"life,omitempty"`
+	StatusInfo      statusInfoContents    `json:"application-status,omitempty" yaml:"application-status"`
+	Relations       map[string][]string   `json:"relations,omitempty" yaml:"relations,omitempty"`
+	SubordinateTo   []string              `json:"subordinate-to,omitempty" yaml:"subordinate-to,omitempty"`
+	Units           map[string]unitStatus `json:"units,omitempty" yaml:"units,omitempty"`
+	Version         string                `json:"version,omitempty" yaml:"version,omitempty"`
+	AgentVersionPin string                `json:"agent-version-pin,omitempty" yaml:"agent-version-pin,omitempty"`
 }
 
 type applicationStatusNoMarshal applicationStatus
@@ -274,10 +293,21 @@ func (s unitStatus) MarshalYAML() (interface{}, error) {
 }
 
 type relationStatus struct {
-	Provider  string
-	Requirer  string
-	Interface string
-	Type      string
-	Status    string
-	Message   string
+	Provider  string `json:"provider" yaml:"provider"`
+	Requirer  string `json:"requirer" yaml:"requirer"`
+	Interface string `json:"interface" yaml:"interface"`
+	Type      string `json:"type" yaml:"type"`
+	Status    string `json:"status" yaml:"status"`
+	Message   string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// sortRelations sorts the relations by provider, then requirer.
+func sortRelations(relations []relationStatus) {
+	sort.Slice(relations, func(i, j int) bool {
+		a, b := relations[i], relations[j]
+		if a.Provider == b.Provider {
+			return a.Requirer < b.Requirer
+		}
+		return a.Provider < b.Provider
+	})
 }
@@ -22,20 +22,25 @@ type statusFormatter struct {
 	controllerName string
 	relations      map[int]params.RelationStatus
 	isoTime        bool
+	showRelations  bool
+	metadata       map[string]params.InstanceMetadata
 }
 
 // NewStatusFormatter takes stored model information (params.FullStatus) and populates
 // the statusFormatter struct used in various status formatting methods
 func NewStatusFormatter(status *params.FullStatus, isoTime bool) *statusFormatter {
-	return newStatusFormatter(status, "", isoTime)
+	return newStatusFormatter(status, "", isoTime, false)
 }
 
-func newStatusFormatter(status *params.FullStatus, controllerName string, isoTime bool) *statusFormatter {
+// newStatusFormatter returns a statusFormatter for the status. The status
+// of each relation is only included when showRelations is true.
+func newStatusFormatter(status *params.FullStatus, controllerName string, isoTime, showRelations bool) *statusFormatter {
 	sf := statusFormatter{
 		status:         status,
 		controllerName: controllerName,
 		relations:      make(map[int]params.RelationStatus),
 		isoTime:        isoTime,
+		showRelations:  showRelations,
 	}
 	for _, relation := range status.Relations {
 		sf.relations[relation.Id] = relation
@@ -43,6 +48,13 @@ func newStatusFormatter(status *params.FullStatus, controllerName string, isoTim
 	return &sf
 }
 
+// SetInstanceMetadata records the provider metadata of the instances
+// of machines, keyed by machine ID, to be included when the machines
+// are formatted.
+func (sf *statusFormatter) SetInstanceMetadata(metadata map[string]params.InstanceMetadata) {
+	sf.metadata = metadata
+}
+
 func (sf *statusFormatter) format() (formattedStatus, error) {
 	if sf.status == nil {
 		return formattedStatus{}, nil
@@ -66,7 +78,6 @@ func (sf *statusFormatter) format() (formattedStatus, error) {
 		Applications:       make(map[string]applicationStatus),
 		RemoteApplications: make(map[string]remoteApplicationStatus),
 		Offers:             make(map[string]offerStatus),
-		Relations:          make([]relationStatus, len(sf.relations)),
 	}
 	if sf.status.Model.MeterStatus.Color != "" {
 		out.Model.MeterStatus = &meterStatus{
@@ -86,10 +97,12 @@ func (sf *statusFormatter) format() (formattedStatus, error) {
 	for name, offer := range sf.status.Offers {
 		out.Offers[name] = sf.formatOffer(name, offer)
 	}
-	i := 0
-	for _, rel := range sf.relations {
-		out.Relations[i] = sf.formatRelation(rel)
-		i++
+	if sf.showRelations {
+		out.Relations = make([]relationStatus, 0, len(sf.relations))
+		for _, rel := range sf.relations {
+			out.Relations = append(out.Relations, sf.formatRelation(rel))
+		}
+		sortRelations(out.Relations)
 	}
 	return out, nil
 }
@@ -132,6 +145,7 @@ func (sf *statusFormatter) formatMachine(machine params.MachineStatus) machineSt
 		Containers:        make(map[string]machineStatus),
 		Constraints:       machine.Constraints,
 		Hardware:          machine.Hardware,
+		AgentVersionPin:   machine.AgentVersionPin,
 	}
 
 	for k, d := range machine.NetworkInterfaces {
@@ -147,6 +161,9 @@ func (sf *statusFormatter) formatMachine(machine params.MachineStatus) machineSt
 	for k, m := range machine.Containers {
 		out.Containers[k] = sf.formatMachine(m)
 	}
+	if md, ok := sf.metadata[machine.Id]; ok {
+		out.ProviderMetadata = formatProviderMetadata(md)
+	}
 
 	for _, job := range machine.Jobs {
 		if job == multiwatcher.JobManageModel {
@@ -157,6 +174,28 @@ func (sf *statusFormatter) formatMachine(machine params.MachineStatus) machineSt
 	return out
 }
 
+func formatProviderMetadata(md params.InstanceMetadata) *providerMetadata {
+	out := &providerMetadata{
+		InstanceProfile: md.InstanceProfile,
+		ImageId:         md.ImageId,
+		Tenancy:         md.Tenancy,
+	}
+	for _, iface := range md.NetworkInterfaces {
+		addresses := make([]string, len(iface.Addresses))
+		for i, addr := range iface.Addresses {
+			addresses[i] = addr.Value
+		}
+		out.NetworkInterfaces = append(out.NetworkInterfaces, providerNetworkInterface{
+			ProviderId:       iface.ProviderId,
+			DeviceIndex:      iface.DeviceIndex,
+			MACAddress:       iface.MACAddress,
+			ProviderSubnetId: iface.ProviderSubnetId,
+			Addresses:        addresses,
+		})
+	}
+	return out
+}
+
 func (sf *statusFormatter) formatApplication(name string, application params.ApplicationStatus) applicationStatus {
 	appOS, _ := series.GetOSFromSeries(application.Series)
 	var (
@@ -182,21 +221,22 @@ func (sf *statusFormatter) formatApplication(name string, application params.App
 	}
 
 	out := applicationStatus{
-		Err:           application.Err,
-		Charm:         application.Charm,
-		Series:        application.Series,
-		OS:            strings.ToLower(appOS.String()),
-		CharmOrigin:   charmOrigin,
-		CharmName:     charmName,
-		CharmRev:      charmRev,
-		Exposed:       application.Exposed,
-		Life:          application.Life,
-		Relations:     application.Relations,
-		CanUpgradeTo:  application.CanUpgradeTo,
-		SubordinateTo: application.SubordinateTo,
-		Units:         make(map[string]unitStatus),
-		StatusInfo:    sf.getApplicationStatusInfo(application),
-		Version:       application.WorkloadVersion,
+		Err:             application.Err,
+		Charm:           application.Charm,
+		Series:          application.Series,
+		OS:              strings.ToLower(appOS.String()),
+		CharmOrigin:     charmOrigin,
+		CharmName:       charmName,
+		CharmRev:        charmRev,
+		Exposed:         application.Exposed,
+		Life:            application.Life,
+		Relations:       application.Relations,
+		CanUpgradeTo:    application.CanUpgradeTo,
+		SubordinateTo:   application.SubordinateTo,
+		Units:           make(map[string]unitStatus),
+		StatusInfo:      sf.getApplicationStatusInfo(application),
+		Version:         application.WorkloadVersion,
+		AgentVersionPin: application.AgentVersionPin,
 	}
 	for k, m := range application.Units {
 		out.Units[k] = sf.formatUnit(unitFormatInfo{
@@ -5,7 +5,9 @@ package status
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -41,6 +43,13 @@ type statusHistoryCommand struct {
 	entityName           string
 	date                 time.Time
 	includeStatusUpdates bool
+	includeStatus        string
+	excludeStatus        string
+	messageRegex         string
+	squash               bool
+	squashCycles         string
+	squashMaxLength      int
+	squashParams         status.SquashParams
 }
 
 var statusHistoryDoc = fmt.Sprintf(`
@@ -51,6 +60,34 @@ The statuses are available for the following types.
 %v
  and sorted by time of occurrence.
  The default is unit.
+
+Applications and relations have status histories of their own, shown
+with --type application and --type relation. A relation is named by
+its endpoints, as shown in the relations section of "juju status".
+
+The history can be searched by status value, with --status or
+--exclude-status, and by message, with --message.
+
+Repeated entries, such as those of update-status hooks, can be squashed
+by the controller with --squash, so that long histories are not sent in
+full. A run of entries repeating a cycle of 1, 2 or 3 entries is shown as
+the cycle followed by a count of the repetitions; other cycle sizes can
+be tried with --squash-cycles, and --squash-max limits the squashed
+history to its most recent entries.
+
+The history is written as a table by default. It can be exported for
+offline analysis with --format json-lines or --format csv, which use
+the field names time, kind, status, message and data, and written to a
+file with --output.
+
+Examples:
+
+    juju show-status-log mysql/0 --status error,blocked
+    juju show-status-log mysql/0 --message "hook failed"
+    juju show-status-log mysql/0 -n 5000 --include-status-updates --squash --squash-max 50
+    juju show-status-log --type application mysql
+    juju show-status-log --type relation "wordpress:db mysql:server"
+    juju show-status-log mysql/0 --format csv --output mysql-0.csv
 `, supportedHistoryKindDescs())
 
 func (c *statusHistoryCommand) Info() *cmd.Info {
@@ -85,12 +122,25 @@ func supportedHistoryKindDescs() string {
 
 func (c *statusHistoryCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":       historyRecordsFormatter(cmd.FormatYaml),
+		"json":       historyRecordsFormatter(cmd.FormatJson),
+		"json-lines": historyFormatter(status.WriteHistoryJSONLines),
+		"csv":        historyFormatter(status.WriteHistoryCSV),
+		"tabular":    historyFormatter(c.formatTabular),
+	})
 	f.StringVar(&c.outputContent, "type", "unit", fmt.Sprintf("Type of statuses to be displayed [%v]", supportedHistoryKindTypes()))
-	f.IntVar(&c.backlogSize, "n", 0, "Returns the last N logs (cannot be combined with --days or --date)")
-	f.IntVar(&c.backlogSizeDays, "days", 0, "Returns the logs for the past <days> days (cannot be combined with -n or --date)")
-	f.StringVar(&c.backlogDate, "from-date", "", "Returns logs for any date after the passed one, the expected date format is YYYY-MM-DD (cannot be combined with -n or --days)")
+	f.IntVar(&c.backlogSize, "n", 0, "Returns the last N logs (may be combined with --days or --from-date)")
+	f.IntVar(&c.backlogSizeDays, "days", 0, "Returns the logs for the past <days> days (cannot be combined with --from-date)")
+	f.StringVar(&c.backlogDate, "from-date", "", "Returns logs for any date after the passed one, the expected date format is YYYY-MM-DD (cannot be combined with --days)")
 	f.BoolVar(&c.isoTime, "utc", false, "Display time as UTC in RFC3339 format")
 	f.BoolVar(&c.includeStatusUpdates, "include-status-updates", false, "Inlcude update status hook messages in the returned logs")
+	f.StringVar(&c.includeStatus, "status", "", "Returns only logs with these comma-separated status values, e.g. error,blocked (cannot be combined with --exclude-status)")
+	f.StringVar(&c.excludeStatus, "exclude-status", "", "Omits logs with these comma-separated status values (cannot be combined with --status)")
+	f.StringVar(&c.messageRegex, "message", "", "Returns only logs whose message matches this regular expression")
+	f.BoolVar(&c.squash, "squash", false, "Squashes repeated logs on the controller before they are returned")
+	f.StringVar(&c.squashCycles, "squash-cycles", "", "The comma-separated sizes of the cycles of repeated logs to squash, tried in order (default 1,2,3; requires --squash)")
+	f.IntVar(&c.squashMaxLength, "squash-max", 0, "Returns at most this many of the most recent squashed logs (requires --squash)")
 }
 
 func (c *statusHistoryCommand) Init(args []string) error {
@@ -119,8 +169,8 @@ func (c *statusHistoryCommand) Init(args []string) error {
 	if emptyDate && emptySize && emptyDays {
 		c.backlogSize = 20
 	}
-	if (!emptyDays && !emptySize) || (!emptyDays && !emptyDate) || (!emptySize && !emptyDate) {
-		return errors.Errorf("backlog size, backlog date and backlog days back cannot be specified together")
+	if !emptyDays && !emptyDate {
+		return errors.Errorf("backlog date and backlog days back cannot be specified together")
 	}
 	if c.backlogDate != "" {
 		var err error
@@ -130,6 +180,19 @@ func (c *statusHistoryCommand) Init(args []string) error {
 		}
 	}
 
+	if c.includeStatus != "" && c.excludeStatus != "" {
+		return errors.Errorf("--status and --exclude-status cannot be specified together")
+	}
+	if c.messageRegex != "" {
+		if _, err := regexp.Compile(c.messageRegex); err != nil {
+			return errors.Annotate(err, "parsing message regex")
+		}
+	}
+
+	if err := c.initSquash(); err != nil {
+		return errors.Trace(err)
+	}
+
 	kind := status.HistoryKind(c.outputContent)
 	if kind.Valid() {
 		return nil
@@ -137,8 +200,101 @@ func (c *statusHistoryCommand) Init(args []string) error {
 	return errors.Errorf("unexpected status type %q", c.outputContent)
 }
 
+// initSquash validates the squash flags and records the parameters
+// they give.
+func (c *statusHistoryCommand) initSquash() error {
+	if !c.squash {
+		if c.squashCycles != "" || c.squashMaxLength != 0 {
+			return errors.Errorf("--squash-cycles and --squash-max require --squash")
+		}
+		return nil
+	}
+	var sizes []int
+	for _, value := range strings.Split(c.squashCycles, ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		size, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.Errorf("invalid squash cycle size %q", value)
+		}
+		sizes = append(sizes, size)
+	}
+	c.squashParams = status.SquashParams{
+		CycleSizes: sizes,
+		MaxLength:  c.squashMaxLength,
+	}
+	return errors.Annotate(c.squashParams.Validate(), "invalid squash parameters")
+}
+
+// statusValues returns the set of comma-separated status values.
+func statusValues(values string) set.Strings {
+	result := set.NewStrings()
+	for _, value := range strings.Split(values, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			result.Add(value)
+		}
+	}
+	return result
+}
+
 const runningHookMSG = "running update-status hook"
 
+// statusHistoryPageSize is the number of status history entries
+// fetched at a time.
+const statusHistoryPageSize = 500
+
+// statusHistoryPager is the API used to fetch status history a page at
+// a time.
+type statusHistoryPager interface {
+	StatusHistoryPage(kind status.HistoryKind, tag names.Tag, filter status.StatusHistoryFilter, pageSize int, token string) (status.History, string, error)
+}
+
+// fetchStatusHistory fetches the whole of the status history that passes
+// the filter, a page at a time, oldest first. If a page cannot be
+// fetched, the more recent entries already fetched are returned along
+// with the error.
+func fetchStatusHistory(api statusHistoryPager, kind status.HistoryKind, tag names.Tag, filter status.StatusHistoryFilter) (status.History, error) {
+	var history status.History
+	var token string
+	for {
+		page, next, err := api.StatusHistoryPage(kind, tag, filter, statusHistoryPageSize, token)
+		if err != nil {
+			return history, errors.Trace(err)
+		}
+		// Each page is older than the last.
+		history = append(page, history...)
+		if next == "" {
+			return history, nil
+		}
+		token = next
+	}
+}
+
+// statusHistoryTag returns the tag of the entity with the given name
+// whose status history of the given kind is reported.
+func statusHistoryTag(kind status.HistoryKind, name string) (names.Tag, error) {
+	switch kind {
+	case status.KindUnit, status.KindWorkload, status.KindUnitAgent:
+		if names.IsValidUnit(name) {
+			return names.NewUnitTag(name), nil
+		}
+	case status.KindApplication:
+		if names.IsValidApplication(name) {
+			return names.NewApplicationTag(name), nil
+		}
+	case status.KindRelation:
+		if names.IsValidRelation(name) {
+			return names.NewRelationTag(name), nil
+		}
+	default:
+		if names.IsValidMachine(name) {
+			return names.NewMachineTag(name), nil
+		}
+	}
+	return nil, errors.Errorf("%q is not a valid name for a %s", name, kind)
+}
+
 func (c *statusHistoryCommand) Run(ctx *cmd.Context) error {
 	apiclient, err := c.NewAPIClient()
 	if err != nil {
@@ -153,8 +309,11 @@ func (c *statusHistoryCommand) Run(ctx *cmd.Context) error {
 		delta = &t
 	}
 	filterArgs := status.StatusHistoryFilter{
-		Size:  c.backlogSize,
-		Delta: delta,
+		Size:          c.backlogSize,
+		Delta:         delta,
+		IncludeStatus: statusValues(c.includeStatus),
+		ExcludeStatus: statusValues(c.excludeStatus),
+		MessageRegex:  c.messageRegex,
 	}
 	if !c.includeStatusUpdates {
 		filterArgs.Exclude = set.NewStrings(runningHookMSG)
@@ -163,20 +322,19 @@ func (c *statusHistoryCommand) Run(ctx *cmd.Context) error {
 	if !c.date.IsZero() {
 		filterArgs.FromDate = &c.date
 	}
-	var tag names.Tag
-	switch kind {
-	case status.KindUnit, status.KindWorkload, status.KindUnitAgent:
-		if !names.IsValidUnit(c.entityName) {
-			return errors.Errorf("%q is not a valid name for a %s", c.entityName, kind)
-		}
-		tag = names.NewUnitTag(c.entityName)
-	default:
-		if !names.IsValidMachine(c.entityName) {
-			return errors.Errorf("%q is not a valid name for a %s", c.entityName, kind)
-		}
-		tag = names.NewMachineTag(c.entityName)
+	tag, err := statusHistoryTag(kind, c.entityName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var statuses status.History
+	if c.squash {
+		// The history is squashed by the controller, so it is
+		// fetched all at once rather than a page at a time.
+		filterArgs.Squash = &c.squashParams
+		statuses, err = apiclient.StatusHistory(kind, tag, filterArgs)
+	} else {
+		statuses, err = fetchStatusHistory(apiclient, kind, tag, filterArgs)
 	}
-	statuses, err := apiclient.StatusHistory(kind, tag, filterArgs)
 	historyLen := len(statuses)
 	if err != nil {
 		if historyLen == 0 {
@@ -190,24 +348,38 @@ func (c *statusHistoryCommand) Run(ctx *cmd.Context) error {
 		return errors.Errorf("no status history available")
 	}
 
-	table := [][]string{{"TIME", "TYPE", "STATUS", "MESSAGE"}}
-	lengths := []int{1, 1, 1, 1}
+	if c.squash {
+		// Controllers that do not squash status history return it
+		// in full, so squash it here too.
+		statuses = statuses.Squash(c.squashParams)
+	} else {
+		statuses = statuses.SquashLogs(status.DefaultSquashCycleSizes...)
+	}
+	return c.out.Write(ctx, statuses)
+}
 
-	statuses = statuses.SquashLogs(1)
-	statuses = statuses.SquashLogs(2)
-	statuses = statuses.SquashLogs(3)
-	for _, v := range statuses {
-		fields := []string{common.FormatTime(v.Since, c.isoTime), string(v.Kind), string(v.Status), v.Info}
-		for k, v := range fields {
-			if len(v) > lengths[k] {
-				lengths[k] = len(v)
-			}
+// historyFormatter adapts a function that writes status history to the
+// cmd.Formatter interface.
+func historyFormatter(write func(io.Writer, status.History) error) cmd.Formatter {
+	return func(writer io.Writer, value interface{}) error {
+		history, ok := value.(status.History)
+		if !ok {
+			return errors.Errorf("expected value of type %T, got %T", history, value)
 		}
-		table = append(table, fields)
+		return write(writer, history)
 	}
-	f := fmt.Sprintf("%%-%ds\t%%-%ds\t%%-%ds\t%%-%ds\n", lengths[0], lengths[1], lengths[2], lengths[3])
-	for _, v := range table {
-		fmt.Printf(f, v[0], v[1], v[2], v[3])
-	}
-	return nil
+}
+
+// historyRecordsFormatter returns a cmd.Formatter that writes the
+// exported records of status history with the given formatter.
+func historyRecordsFormatter(format cmd.Formatter) cmd.Formatter {
+	return historyFormatter(func(writer io.Writer, history status.History) error {
+		return format(writer, history.Records())
+	})
+}
+
+func (c *statusHistoryCommand) formatTabular(writer io.Writer, history status.History) error {
+	return status.WriteHistoryTabular(writer, history, func(t time.Time) string {
+		return common.FormatTime(&t, c.isoTime)
+	})
 }
@@ -0,0 +1,174 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/status"
+)
+
+type StatusHistorySuite struct {
+	gitjujutesting.IsolationSuite
+}
+
+var _ = gc.Suite(&StatusHistorySuite{})
+
+type fakeStatusHistoryPager struct {
+	gitjujutesting.Stub
+	pages  []status.History
+	tokens []string
+}
+
+func (f *fakeStatusHistoryPager) StatusHistoryPage(kind status.HistoryKind, tag names.Tag, filter status.StatusHistoryFilter, pageSize int, token string) (status.History, string, error) {
+	f.MethodCall(f, "StatusHistoryPage", kind, tag, filter, pageSize, token)
+	if err := f.NextErr(); err != nil {
+		return nil, "", err
+	}
+	page := f.pages[0]
+	f.pages = f.pages[1:]
+	next := f.tokens[0]
+	f.tokens = f.tokens[1:]
+	return page, next, nil
+}
+
+func historyOf(infos ...string) status.History {
+	history := make(status.History, len(infos))
+	for i, info := range infos {
+		history[i] = status.DetailedStatus{Info: info}
+	}
+	return history
+}
+
+func (s *StatusHistorySuite) TestFetchStatusHistoryPages(c *gc.C) {
+	pager := &fakeStatusHistoryPager{
+		pages:  []status.History{historyOf("c", "d"), historyOf("a", "b")},
+		tokens: []string{"next", ""},
+	}
+	tag := names.NewUnitTag("mysql/0")
+	filter := status.StatusHistoryFilter{Size: 4}
+	history, err := fetchStatusHistory(pager, status.KindUnit, tag, filter)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, jc.DeepEquals, historyOf("a", "b", "c", "d"))
+	pager.CheckCalls(c, []gitjujutesting.StubCall{
+		{"StatusHistoryPage", []interface{}{status.KindUnit, tag, filter, statusHistoryPageSize, ""}},
+		{"StatusHistoryPage", []interface{}{status.KindUnit, tag, filter, statusHistoryPageSize, "next"}},
+	})
+}
+
+func (s *StatusHistorySuite) TestFetchStatusHistoryError(c *gc.C) {
+	pager := &fakeStatusHistoryPager{
+		pages:  []status.History{historyOf("c", "d")},
+		tokens: []string{"next"},
+	}
+	pager.SetErrors(nil, errors.New("boom"))
+	history, err := fetchStatusHistory(pager, status.KindUnit, names.NewUnitTag("mysql/0"), status.StatusHistoryFilter{Size: 4})
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(history, jc.DeepEquals, historyOf("c", "d"))
+}
+
+func (s *StatusHistorySuite) TestStatusValues(c *gc.C) {
+	c.Assert(statusValues(""), gc.HasLen, 0)
+	c.Assert(statusValues("error, blocked,,error").SortedValues(), jc.DeepEquals, []string{"blocked", "error"})
+}
+
+func (s *StatusHistorySuite) TestInitSquash(c *gc.C) {
+	command := &statusHistoryCommand{squash: true, squashCycles: "1, 4", squashMaxLength: 50}
+	err := command.initSquash()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(command.squashParams, jc.DeepEquals, status.SquashParams{
+		CycleSizes: []int{1, 4},
+		MaxLength:  50,
+	})
+
+	command = &statusHistoryCommand{squash: true}
+	err = command.initSquash()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(command.squashParams, jc.DeepEquals, status.SquashParams{})
+}
+
+func (s *StatusHistorySuite) TestInitSquashErrors(c *gc.C) {
+	for i, test := range []struct {
+		command statusHistoryCommand
+		err     string
+	}{{
+		command: statusHistoryCommand{squashMaxLength: 5},
+		err:     "--squash-cycles and --squash-max require --squash",
+	}, {
+		command: statusHistoryCommand{squash: true, squashCycles: "1,two"},
+		err:     `invalid squash cycle size "two"`,
+	}, {
+		command: statusHistoryCommand{squash: true, squashCycles: "11"},
+		err:     `invalid squash parameters: squash cycle size 11 \(must be between 1 and 10\) not valid`,
+	}, {
+		command: statusHistoryCommand{squash: true, squashMaxLength: -1},
+		err:     "invalid squash parameters: negative squash max length -1 not valid",
+	}} {
+		c.Logf("test %d", i)
+		err := test.command.initSquash()
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *StatusHistorySuite) TestStatusHistoryTag(c *gc.C) {
+	for i, test := range []struct {
+		kind   status.HistoryKind
+		name   string
+		expect names.Tag
+	}{
+		{status.KindUnit, "mysql/0", names.NewUnitTag("mysql/0")},
+		{status.KindWorkload, "mysql/0", names.NewUnitTag("mysql/0")},
+		{status.KindMachine, "0", names.NewMachineTag("0")},
+		{status.KindContainer, "0/lxd/1", names.NewMachineTag("0/lxd/1")},
+		{status.KindApplication, "mysql", names.NewApplicationTag("mysql")},
+		{status.KindRelation, "wordpress:db mysql:server", names.NewRelationTag("wordpress:db mysql:server")},
+	} {
+		c.Logf("test %d: %s %q", i, test.kind, test.name)
+		tag, err := statusHistoryTag(test.kind, test.name)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(tag, gc.Equals, test.expect)
+	}
+}
+
+func (s *StatusHistorySuite) TestStatusHistoryTagInvalid(c *gc.C) {
+	_, err := statusHistoryTag(status.KindApplication, "mysql/0")
+	c.Assert(err, gc.ErrorMatches, `"mysql/0" is not a valid name for a application`)
+	_, err = statusHistoryTag(status.KindRelation, "mysql")
+	c.Assert(err, gc.ErrorMatches, `"mysql" is not a valid name for a relation`)
+}
+
+func (s *StatusHistorySuite) TestHistoryFormatter(c *gc.C) {
+	var buf bytes.Buffer
+	format := historyFormatter(status.WriteHistoryCSV)
+	err := format(&buf, historyOf("ready"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, "time,kind,status,message,data\n,,,ready,\n")
+
+	err = format(&buf, "ready")
+	c.Assert(err, gc.ErrorMatches, `expected value of type status.History, got string`)
+}
+
+func (s *StatusHistorySuite) TestFormatTabularISOTime(c *gc.C) {
+	since := time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC)
+	history := status.History{{
+		Status: status.Active,
+		Info:   "ready",
+		Since:  &since,
+		Kind:   status.KindWorkload,
+	}}
+	command := &statusHistoryCommand{isoTime: true}
+	var buf bytes.Buffer
+	err := command.formatTabular(&buf, history)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, ""+
+		"TIME                  KIND      STATUS  MESSAGE\n"+
+		"2018-03-01 10:00:00Z  workload  active  ready\n")
+}
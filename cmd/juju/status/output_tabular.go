@@ -108,10 +108,12 @@ func FormatTabular(writer io.Writer, forceColor bool, value interface{}) error {
 		if len(version) > maxVersionWidth {
 			version = version[:truncatedWidth] + ellipsis
 		}
-		// Notes may well contain other things later.
-		notes := ""
+		var notes []string
 		if app.Exposed {
-			notes = "exposed"
+			notes = append(notes, "exposed")
+		}
+		if app.AgentVersionPin != "" {
+			notes = append(notes, "pinned "+app.AgentVersionPin)
 		}
 		w.Print(appName, version)
 		w.PrintStatus(app.StatusInfo.Current)
@@ -125,7 +127,7 @@ func FormatTabular(writer io.Writer, forceColor bool, value interface{}) error {
 			app.CharmOrigin,
 			app.CharmRev,
 			app.OS,
-			notes)
+			strings.Join(notes, ", "))
 
 		for un, u := range app.Units {
 			units[un] = u
@@ -192,23 +194,12 @@ func FormatTabular(writer io.Writer, forceColor bool, value interface{}) error {
 	}
 
 	if len(fs.Relations) > 0 {
-		sort.Slice(fs.Relations, func(i, j int) bool {
-			a, b := fs.Relations[i], fs.Relations[j]
-			if a.Provider == b.Provider {
-				return a.Requirer < b.Requirer
-			}
-			return a.Provider < b.Provider
-		})
-		outputHeaders("Relation provider", "Requirer", "Interface", "Type", "Message")
+		sortRelations(fs.Relations)
+		outputHeaders("Relation provider", "Requirer", "Interface", "Type", "Status", "Message")
 		for _, r := range fs.Relations {
 			w.Print(r.Provider, r.Requirer, r.Interface, r.Type)
-			if r.Status != string(relation.Joined) {
-				w.PrintColor(cmdcrossmodel.RelationStatusColor(relation.Status(r.Status)), r.Status)
-				if r.Message != "" {
-					w.Print(" - " + r.Message)
-				}
-			}
-			w.Println()
+			w.PrintColor(cmdcrossmodel.RelationStatusColor(relation.Status(r.Status)), r.Status)
+			w.Println(r.Message)
 		}
 	}
 
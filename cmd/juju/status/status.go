@@ -14,6 +14,7 @@ import (
 	"github.com/juju/gnuflag"
 	"github.com/juju/loggo"
 
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/juju/osenv"
@@ -23,9 +24,23 @@ var logger = loggo.GetLogger("juju.cmd.juju.status")
 
 type statusAPI interface {
 	Status(patterns []string) (*params.FullStatus, error)
+	WatchStatus(patterns []string) (statusWatcher, error)
 	Close() error
 }
 
+// statusClient adapts an api.Client to the statusAPI interface.
+type statusClient struct {
+	*api.Client
+}
+
+func (c statusClient) WatchStatus(patterns []string) (statusWatcher, error) {
+	w, err := c.Client.WatchStatus(patterns)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
 // NewStatusCommand returns a new command, which reports on the
 // runtime state of various system entities.
 func NewStatusCommand() cmd.Command {
@@ -34,10 +49,12 @@ func NewStatusCommand() cmd.Command {
 
 type statusCommand struct {
 	modelcmd.ModelCommandBase
-	out      cmd.Output
-	patterns []string
-	isoTime  bool
-	api      statusAPI
+	out       cmd.Output
+	patterns  []string
+	isoTime   bool
+	watch     bool
+	relations bool
+	api       statusAPI
 
 	color bool
 }
@@ -58,7 +75,8 @@ matched, then all of its subordinates will be displayed.
 The available output formats are:
 
 - tabular (default): Displays status in a tabular format with a separate table
-      for the model, machines, applications, relations (if any) and units.
+      for the model, machines, applications and units, and for relations
+      when --relations is specified.
       Note: in this format, the AZ column refers to the cloud region's
       availability zone.
 - {short|line|oneline}: List units and their subordinates. For each unit, the IP
@@ -73,10 +91,21 @@ The available output formats are:
 - json: Displays information about the model, machines, applications, and units
       in structured JSON format.
 
+With --relations, the status of each relation is included: joined while
+it is healthy, or suspended, broken or in error, with any message set when
+the status changed. Relations are reported as the controller records them,
+rather than inferred from the status of their units' agents.
+
+With --watch, the status is written again each time it changes, until
+the command is interrupted. Only the changes are sent by the controller,
+which keeps watching the status of large models cheap.
+
 Examples:
     juju show-status
     juju show-status mysql
     juju show-status nova-*
+    juju show-status --relations
+    juju show-status --watch
 
 See also:
     machines
@@ -99,6 +128,8 @@ func (c *statusCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ModelCommandBase.SetFlags(f)
 	f.BoolVar(&c.isoTime, "utc", false, "Display time as UTC in RFC3339 format")
 	f.BoolVar(&c.color, "color", false, "Force use of ANSI color codes")
+	f.BoolVar(&c.watch, "watch", false, "Write the status again each time it changes")
+	f.BoolVar(&c.relations, "relations", false, "Show the status of relations")
 
 	defaultFormat := "tabular"
 
@@ -130,7 +161,11 @@ func (c *statusCommand) Init(args []string) error {
 }
 
 var newAPIClientForStatus = func(c *statusCommand) (statusAPI, error) {
-	return c.NewAPIClient()
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return statusClient{client}, nil
 }
 
 func (c *statusCommand) Run(ctx *cmd.Context) error {
@@ -140,6 +175,14 @@ func (c *statusCommand) Run(ctx *cmd.Context) error {
 	}
 	defer apiclient.Close()
 
+	if c.watch {
+		controllerName, err := c.ControllerName()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return c.runWatch(ctx, apiclient, controllerName)
+	}
+
 	status, err := apiclient.Status(c.patterns)
 	if err != nil {
 		if status == nil {
@@ -156,7 +199,7 @@ func (c *statusCommand) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	formatter := newStatusFormatter(status, controllerName, c.isoTime)
+	formatter := newStatusFormatter(status, controllerName, c.isoTime, c.relations)
 	formatted, err := formatter.format()
 	if err != nil {
 		return errors.Trace(err)
@@ -14,6 +14,7 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	"github.com/juju/version"
@@ -3851,6 +3852,7 @@ type fakeAPIClient struct {
 	statusReturn *params.FullStatus
 	patternsUsed []string
 	closeCalled  bool
+	watcher      *fakeStatusWatcher
 }
 
 func (a *fakeAPIClient) Status(patterns []string) (*params.FullStatus, error) {
@@ -3858,11 +3860,94 @@ func (a *fakeAPIClient) Status(patterns []string) (*params.FullStatus, error) {
 	return a.statusReturn, nil
 }
 
+func (a *fakeAPIClient) WatchStatus(patterns []string) (statusWatcher, error) {
+	a.patternsUsed = patterns
+	return a.watcher, nil
+}
+
 func (a *fakeAPIClient) Close() error {
 	a.closeCalled = true
 	return nil
 }
 
+// fakeStatusWatcher returns each of its diffs in turn, and then fails.
+type fakeStatusWatcher struct {
+	diffs      [][]params.StatusDiff
+	stopCalled bool
+}
+
+func (w *fakeStatusWatcher) Next() ([]params.StatusDiff, error) {
+	if len(w.diffs) == 0 {
+		return nil, errors.New("watcher stopped")
+	}
+	diffs := w.diffs[0]
+	w.diffs = w.diffs[1:]
+	return diffs, nil
+}
+
+func (w *fakeStatusWatcher) Stop() error {
+	w.stopCalled = true
+	return nil
+}
+
+func (s *StatusSuite) TestStatusModelApply(c *gc.C) {
+	model := newStatusModel()
+	model.apply([]params.StatusDiff{
+		{Entity: []string{"model"}, Field: "name", New: "controller"},
+		{Entity: []string{"machines", "0"}, Field: "id", New: "0"},
+		{Entity: []string{"machines", "0", "agent-status"}, Field: "status", New: "started"},
+		{Entity: []string{"machines", "1"}, Field: "id", New: "1"},
+		{Field: "relations", New: []interface{}{
+			map[string]interface{}{"id": 1.0, "key": "wordpress:db mysql:server"},
+		}},
+	})
+	model.apply([]params.StatusDiff{
+		{Entity: []string{"machines", "0", "agent-status"}, Field: "status", Old: "started", New: "down"},
+		{Entity: []string{"machines", "1"}, Field: "id", Old: "1"},
+	})
+	status, err := model.status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, jc.DeepEquals, &params.FullStatus{
+		Model: params.ModelStatusInfo{Name: "controller"},
+		Machines: map[string]params.MachineStatus{
+			"0": {
+				Id:          "0",
+				AgentStatus: params.DetailedStatus{Status: "down"},
+			},
+		},
+		Relations: []params.RelationStatus{
+			{Id: 1, Key: "wordpress:db mysql:server"},
+		},
+	})
+}
+
+func (s *StatusSuite) TestStatusWatch(c *gc.C) {
+	ctx := s.newContext(c)
+	defer s.resetContext(c, ctx)
+
+	client := fakeAPIClient{
+		watcher: &fakeStatusWatcher{
+			diffs: [][]params.StatusDiff{{
+				{Entity: []string{"model"}, Field: "name", New: "first"},
+				{Entity: []string{"model"}, Field: "cloud-tag", New: "cloud-dummy"},
+			}, {
+				{Entity: []string{"model"}, Field: "name", Old: "first", New: "second"},
+			}},
+		},
+	}
+	s.PatchValue(&newAPIClientForStatus, func(_ *statusCommand) (statusAPI, error) {
+		return &client, nil
+	})
+
+	code, stdout, stderr := runStatus(c, "--watch", "--format", "yaml", "mysql")
+	c.Check(code, gc.Equals, 1)
+	c.Check(string(stderr), gc.Equals, "ERROR watcher stopped\n")
+	c.Check(string(stdout), gc.Matches, `(?s)model:\n  name: first\n.*\nmodel:\n  name: second\n.*`)
+	c.Check(client.patternsUsed, jc.DeepEquals, []string{"mysql"})
+	c.Check(client.watcher.stopCalled, jc.IsTrue)
+	c.Check(client.closeCalled, jc.IsTrue)
+}
+
 func (s *StatusSuite) TestStatusWithFormatSummary(c *gc.C) {
 	ctx := s.newContext(c)
 	defer s.resetContext(c, ctx)
@@ -4083,7 +4168,7 @@ func (s *StatusSuite) prepareTabularData(c *gc.C) *context {
 func (s *StatusSuite) TestStatusWithFormatTabular(c *gc.C) {
 	ctx := s.prepareTabularData(c)
 	defer s.resetContext(c, ctx)
-	code, stdout, stderr := runStatus(c, "--format", "tabular")
+	code, stdout, stderr := runStatus(c, "--format", "tabular", "--relations")
 	c.Check(code, gc.Equals, 0)
 	c.Check(string(stderr), gc.Equals, "")
 	expected := `
@@ -4113,15 +4198,63 @@ Machine  State    DNS       Inst id       Series   AZ          Message
 Offer         Application  Charm  Rev  Connected  Endpoint  Interface  Role
 hosted-mysql  mysql        mysql  1    1/1        server    mysql      provider
 
-Relation provider      Requirer                   Interface  Type         Message
-mysql:juju-info        logging:info               juju-info  subordinate  
+Relation provider      Requirer                   Interface  Type         Status     Message
+mysql:juju-info        logging:info               juju-info  subordinate  joined     
 mysql:server           wordpress:db               mysql      regular      suspended  
-wordpress:logging-dir  logging:logging-directory  logging    subordinate  
+wordpress:logging-dir  logging:logging-directory  logging    subordinate  joined     
 
 `[1:]
 	c.Assert(string(stdout), gc.Equals, expected)
 }
 
+func (s *StatusSuite) TestStatusWithFormatTabularNoRelations(c *gc.C) {
+	ctx := s.prepareTabularData(c)
+	defer s.resetContext(c, ctx)
+	code, stdout, stderr := runStatus(c, "--format", "tabular")
+	c.Check(code, gc.Equals, 0)
+	c.Check(string(stderr), gc.Equals, "")
+	c.Assert(string(stdout), gc.Not(jc.Contains), "Relation provider")
+}
+
+func (s *StatusSuite) TestStatusWithRelationsYaml(c *gc.C) {
+	ctx := s.prepareTabularData(c)
+	defer s.resetContext(c, ctx)
+	code, stdout, stderr := runStatus(c, "--format", "yaml", "--relations")
+	c.Check(code, gc.Equals, 0)
+	c.Check(string(stderr), gc.Equals, "")
+	var out struct {
+		Relations []map[string]string `yaml:"relations"`
+	}
+	err := goyaml.Unmarshal(stdout, &out)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.Relations, jc.DeepEquals, []map[string]string{{
+		"provider":  "mysql:juju-info",
+		"requirer":  "logging:info",
+		"interface": "juju-info",
+		"type":      "subordinate",
+		"status":    "joined",
+	}, {
+		"provider":  "mysql:server",
+		"requirer":  "wordpress:db",
+		"interface": "mysql",
+		"type":      "regular",
+		"status":    "suspended",
+	}, {
+		"provider":  "wordpress:logging-dir",
+		"requirer":  "logging:logging-directory",
+		"interface": "logging",
+		"type":      "subordinate",
+		"status":    "joined",
+	}})
+
+	code, stdout, _ = runStatus(c, "--format", "yaml")
+	c.Check(code, gc.Equals, 0)
+	out.Relations = nil
+	err = goyaml.Unmarshal(stdout, &out)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.Relations, gc.HasLen, 0)
+}
+
 func (s *StatusSuite) TestFormatTabularHookActionName(c *gc.C) {
 	status := formattedStatus{
 		Applications: map[string]applicationStatus{
@@ -4169,6 +4302,21 @@ Machine  State  DNS  Inst id  Series  AZ  Message
 `[1:])
 }
 
+func (s *StatusSuite) TestFormatTabularAgentVersionPin(c *gc.C) {
+	status := formattedStatus{
+		Applications: map[string]applicationStatus{
+			"foo": {
+				Exposed:         true,
+				AgentVersionPin: "2.3.1..2.3.4",
+			},
+		},
+	}
+	out := &bytes.Buffer{}
+	err := FormatTabular(out, false, status)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.String(), jc.Contains, "exposed, pinned 2.3.1..2.3.4\n")
+}
+
 func (s *StatusSuite) TestStatusWithNilStatusAPI(c *gc.C) {
 	ctx := s.newContext(c)
 	defer s.resetContext(c, ctx)
@@ -0,0 +1,268 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/operationstimeline"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+var deployTimelineDoc = `
+Reports the operations that deployed the units of an application, in
+the order they started, with their durations. This helps to find where
+the time goes in a slow deployment.
+
+The operations reported are:
+
+- provision: provisioning a machine's instance, until it is running.
+- agent-start: starting the machine agent once its instance is running.
+- hook: running a hook on a unit.
+- relation: running a relation hook on a unit, as the relation is
+      established.
+
+The timeline is built from the status history of the units and their
+machines, so operations older than the retained history are not shown.
+In the tabular format, the chart column shows when each operation ran
+relative to the others.
+
+Examples:
+    juju show-deploy-timeline mysql
+    juju show-deploy-timeline wordpress --format yaml
+
+See also:
+    show-status
+    show-status-log
+`
+
+// chartWidth is the number of characters in the chart column of the
+// tabular format.
+const chartWidth = 40
+
+// NewDeployTimelineCommand returns a command that reports the timeline
+// of the operations that deployed an application.
+func NewDeployTimelineCommand() cmd.Command {
+	return modelcmd.Wrap(&deployTimelineCommand{})
+}
+
+type deployTimelineCommand struct {
+	modelcmd.ModelCommandBase
+	out         cmd.Output
+	application string
+	api         DeployTimelineAPI
+}
+
+// DeployTimelineAPI defines the API methods used by the
+// show-deploy-timeline command.
+type DeployTimelineAPI interface {
+	Close() error
+	DeployTimeline(application string) (*params.DeployTimeline, error)
+}
+
+// Info implements Command.
+func (c *deployTimelineCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show-deploy-timeline",
+		Args:    "<application>",
+		Purpose: "Reports the timeline of the operations that deployed an application.",
+		Doc:     deployTimelineDoc,
+		Aliases: []string{"deploy-timeline"},
+	}
+}
+
+// SetFlags implements Command.
+func (c *deployTimelineCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatDeployTimelineTabular,
+	})
+}
+
+// Init implements Command.
+func (c *deployTimelineCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no application specified")
+	}
+	if !names.IsValidApplication(args[0]) {
+		return errors.NotValidf("application name %q", args[0])
+	}
+	c.application = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *deployTimelineCommand) getAPI() (DeployTimelineAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return operationstimeline.NewClient(root), nil
+}
+
+// Run implements Command.
+func (c *deployTimelineCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	timeline, err := client.DeployTimeline(c.application)
+	if err != nil {
+		return err
+	}
+	if len(timeline.Events) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No deploy operations found for %s.", c.application)
+		return nil
+	}
+	return c.out.Write(ctx, formatDeployTimeline(timeline))
+}
+
+type deployTimeline struct {
+	Application string          `yaml:"application" json:"application"`
+	Start       string          `yaml:"start" json:"start"`
+	Duration    string          `yaml:"duration" json:"duration"`
+	Events      []timelineEvent `yaml:"events" json:"events"`
+}
+
+type timelineEvent struct {
+	Entity    string `yaml:"entity" json:"entity"`
+	Operation string `yaml:"operation" json:"operation"`
+	Name      string `yaml:"name,omitempty" json:"name,omitempty"`
+	Start     string `yaml:"start" json:"start"`
+	Offset    string `yaml:"offset" json:"offset"`
+	Duration  string `yaml:"duration,omitempty" json:"duration,omitempty"`
+	Error     string `yaml:"error,omitempty" json:"error,omitempty"`
+
+	// offset and duration are used to draw the chart in the tabular
+	// format. duration is negative if the operation has not finished.
+	offset   time.Duration
+	duration time.Duration
+}
+
+func formatDeployTimeline(timeline *params.DeployTimeline) deployTimeline {
+	result := deployTimeline{
+		Application: timeline.Application,
+		Events:      make([]timelineEvent, len(timeline.Events)),
+	}
+	if len(timeline.Events) == 0 {
+		return result
+	}
+	start := timeline.Events[0].Start
+	result.Start = formatTimelineTime(start)
+	var end time.Time
+	for i, e := range timeline.Events {
+		event := timelineEvent{
+			Entity:    entityName(e.Entity),
+			Operation: e.Kind,
+			Name:      e.Name,
+			Start:     formatTimelineTime(e.Start),
+			Error:     e.Error,
+			offset:    e.Start.Sub(start),
+			duration:  -1,
+		}
+		event.Offset = event.offset.String()
+		if e.End != nil {
+			event.duration = e.End.Sub(e.Start)
+			event.Duration = event.duration.String()
+			if e.End.After(end) {
+				end = *e.End
+			}
+		}
+		if e.Start.After(end) {
+			end = e.Start
+		}
+		result.Events[i] = event
+	}
+	result.Duration = end.Sub(start).String()
+	return result
+}
+
+func formatTimelineTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// entityName returns the name of the machine or unit with the tag, as
+// it is shown by juju status.
+func entityName(tag string) string {
+	t, err := names.ParseTag(tag)
+	if err != nil {
+		return tag
+	}
+	if t.Kind() == names.MachineTagKind {
+		return "machine " + t.Id()
+	}
+	return t.Id()
+}
+
+func formatDeployTimelineTabular(writer io.Writer, value interface{}) error {
+	timeline, ok := value.(deployTimeline)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", timeline, value)
+	}
+	var total time.Duration
+	for _, e := range timeline.Events {
+		end := e.offset
+		if e.duration > 0 {
+			end += e.duration
+		}
+		if end > total {
+			total = end
+		}
+	}
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Offset", "Duration", "Entity", "Operation", "Chart", "Message")
+	for _, e := range timeline.Events {
+		operation := e.Operation
+		if e.Name != "" {
+			operation += " " + e.Name
+		}
+		duration := e.Duration
+		if e.duration < 0 {
+			duration = "(running)"
+		}
+		w.Println(e.Offset, duration, e.Entity, operation, chart(e.offset, e.duration, total), e.Error)
+	}
+	tw.Flush()
+	fmt.Fprintf(writer, "\nTotal: %s\n", timeline.Duration)
+	return nil
+}
+
+// chart draws a bar showing when an operation ran, relative to the
+// whole timeline. An operation that has not finished runs to the end
+// of the chart.
+func chart(offset, duration, total time.Duration) string {
+	if total <= 0 {
+		return "|" + strings.Repeat("#", chartWidth) + "|"
+	}
+	start := int(int64(offset) * chartWidth / int64(total))
+	end := chartWidth
+	if duration >= 0 {
+		end = int(int64(offset+duration) * chartWidth / int64(total))
+	}
+	if start >= chartWidth {
+		start = chartWidth - 1
+	}
+	if end <= start {
+		end = start + 1
+	}
+	return "|" + strings.Repeat(" ", start) + strings.Repeat("#", end-start) + strings.Repeat(" ", chartWidth-end) + "|"
+}
@@ -0,0 +1,179 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type DeployTimelineSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake  fakeDeployTimelineClient
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&DeployTimelineSuite{})
+
+type fakeDeployTimelineClient struct {
+	gitjujutesting.Stub
+	timeline *params.DeployTimeline
+}
+
+func (f *fakeDeployTimelineClient) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeDeployTimelineClient) DeployTimeline(application string) (*params.DeployTimeline, error) {
+	f.MethodCall(f, "DeployTimeline", application)
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	return f.timeline, nil
+}
+
+func (s *DeployTimelineSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	start := time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC)
+	at := func(seconds int) *time.Time {
+		t := start.Add(time.Duration(seconds) * time.Second)
+		return &t
+	}
+	s.fake = fakeDeployTimelineClient{
+		timeline: &params.DeployTimeline{
+			Application: "mysql",
+			Events: []params.TimelineEvent{{
+				Entity: "machine-0",
+				Kind:   params.TimelineProvision,
+				Start:  *at(0),
+				End:    at(90),
+			}, {
+				Entity: "machine-0",
+				Kind:   params.TimelineAgentStart,
+				Start:  *at(90),
+				End:    at(120),
+			}, {
+				Entity: "unit-mysql-0",
+				Kind:   params.TimelineHook,
+				Name:   "install",
+				Start:  *at(130),
+				End:    at(230),
+			}, {
+				Entity: "unit-mysql-1",
+				Kind:   params.TimelineHook,
+				Name:   "install",
+				Start:  *at(135),
+			}, {
+				Entity: "unit-mysql-0",
+				Kind:   params.TimelineHook,
+				Name:   "start",
+				Start:  *at(230),
+				End:    at(240),
+				Error:  `hook failed: "start"`,
+			}, {
+				Entity: "unit-mysql-0",
+				Kind:   params.TimelineRelation,
+				Name:   "db-relation-joined",
+				Start:  *at(250),
+				End:    at(260),
+			}},
+		},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		testing.ModelTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+}
+
+func (s *DeployTimelineSuite) runDeployTimeline(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := &deployTimelineCommand{api: &s.fake}
+	command.SetClientStore(s.store)
+	return cmdtesting.RunCommand(c, modelcmd.Wrap(command), args...)
+}
+
+func (s *DeployTimelineSuite) TestInit(c *gc.C) {
+	_, err := s.runDeployTimeline(c)
+	c.Assert(err, gc.ErrorMatches, "no application specified")
+	_, err = s.runDeployTimeline(c, "mysql/0")
+	c.Assert(err, gc.ErrorMatches, `application name "mysql/0" not valid`)
+	_, err = s.runDeployTimeline(c, "mysql", "wordpress")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["wordpress"\]`)
+}
+
+func (s *DeployTimelineSuite) TestTabular(c *gc.C) {
+	ctx, err := s.runDeployTimeline(c, "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"DeployTimeline", []interface{}{"mysql"}},
+		{"Close", nil},
+	})
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"Offset  Duration   Entity     Operation                    Chart                                       Message\n"+
+		"0s      1m30s      machine 0  provision                    |#############                           |  \n"+
+		"1m30s   30s        machine 0  agent-start                  |             #####                      |  \n"+
+		"2m10s   1m40s      mysql/0    hook install                 |                    ###############     |  \n"+
+		"2m15s   (running)  mysql/1    hook install                 |                    ####################|  \n"+
+		"3m50s   10s        mysql/0    hook start                   |                                   #    |  hook failed: \"start\"\n"+
+		"4m10s   10s        mysql/0    relation db-relation-joined  |                                      ##|  \n"+
+		"\n"+
+		"Total: 4m20s\n")
+}
+
+func (s *DeployTimelineSuite) TestYAML(c *gc.C) {
+	s.fake.timeline.Events = s.fake.timeline.Events[3:5]
+	ctx, err := s.runDeployTimeline(c, "mysql", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+application: mysql
+start: 2018-03-01T10:02:15Z
+duration: 1m45s
+events:
+- entity: mysql/1
+  operation: hook
+  name: install
+  start: 2018-03-01T10:02:15Z
+  offset: 0s
+- entity: mysql/0
+  operation: hook
+  name: start
+  start: 2018-03-01T10:03:50Z
+  offset: 1m35s
+  duration: 10s
+  error: 'hook failed: "start"'
+`[1:])
+}
+
+func (s *DeployTimelineSuite) TestNoEvents(c *gc.C) {
+	s.fake.timeline.Events = nil
+	ctx, err := s.runDeployTimeline(c, "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No deploy operations found for mysql.\n")
+}
+
+func (s *DeployTimelineSuite) TestError(c *gc.C) {
+	s.fake.SetErrors(errors.New("boom"))
+	_, err := s.runDeployTimeline(c, "mysql")
+	c.Assert(err, gc.ErrorMatches, "boom")
+	s.fake.CheckCallNames(c, "DeployTimeline", "Close")
+}
@@ -0,0 +1,113 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+type statusWatcher interface {
+	Next() ([]params.StatusDiff, error)
+	Stop() error
+}
+
+// statusModel is a local copy of a model's full status, kept up to
+// date by applying the diffs reported by a status watcher. It holds
+// the JSON encoding of the status as a tree of objects.
+type statusModel struct {
+	tree map[string]interface{}
+}
+
+func newStatusModel() *statusModel {
+	return &statusModel{tree: make(map[string]interface{})}
+}
+
+// apply applies the diffs to the model.
+func (m *statusModel) apply(diffs []params.StatusDiff) {
+	for _, diff := range diffs {
+		if diff.New == nil {
+			removeStatusField(m.tree, diff.Entity, diff.Field)
+			continue
+		}
+		obj := m.tree
+		for _, key := range diff.Entity {
+			child, ok := obj[key].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				obj[key] = child
+			}
+			obj = child
+		}
+		obj[diff.Field] = diff.New
+	}
+}
+
+// removeStatusField removes the field from the object at the end of
+// the entity path, and removes any objects left empty as a result.
+func removeStatusField(obj map[string]interface{}, entity []string, field string) {
+	if len(entity) == 0 {
+		delete(obj, field)
+		return
+	}
+	child, ok := obj[entity[0]].(map[string]interface{})
+	if !ok {
+		return
+	}
+	removeStatusField(child, entity[1:], field)
+	if len(child) == 0 {
+		delete(obj, entity[0])
+	}
+}
+
+// status returns the model's full status.
+func (m *statusModel) status() (*params.FullStatus, error) {
+	data, err := json.Marshal(m.tree)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var status params.FullStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &status, nil
+}
+
+// runWatch writes the model's status, and then writes it again each
+// time it changes, until the watcher fails.
+func (c *statusCommand) runWatch(ctx *cmd.Context, apiclient statusAPI, controllerName string) error {
+	w, err := apiclient.WatchStatus(c.patterns)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer w.Stop()
+
+	model := newStatusModel()
+	for first := true; ; first = false {
+		diffs, err := w.Next()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		model.apply(diffs)
+		status, err := model.status()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		formatted, err := newStatusFormatter(status, controllerName, c.isoTime, c.relations).format()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !first {
+			fmt.Fprintln(ctx.Stdout)
+		}
+		if err := c.out.Write(ctx, formatted); err != nil {
+			return errors.Trace(err)
+		}
+	}
+}
@@ -42,6 +42,7 @@ var (
 		"charm-revision-updater",
 		"compute-provisioner",
 		"environ-tracker",
+		"ephemeral-reaper",
 		"firewaller",
 		"instance-poller",
 		"machine-undertaker",
@@ -50,13 +51,20 @@ var (
 		"migration-inactive-flag",
 		"migration-master",
 		"application-scaler",
+		"ssh-key-importer",
 		"state-cleaner",
 		"status-history-pruner",
 		"storage-provisioner",
 		"unit-assigner",
+		"unit-mover",
 		"remote-relations",
 		"log-forwarder",
 	}
+	// controllerModelWorkers are only installed for the controller
+	// model.
+	controllerModelWorkers = []string{
+		"controller-firewaller",
+	}
 	migratingModelWorkers = []string{
 		"environ-tracker",
 		"migration-fortress",
@@ -126,6 +134,7 @@ var (
 		"logging-config-updater",
 		"machine-action-runner",
 		"machiner",
+		// "package-update-reporter", not stable, exits without apt-check
 		"proxy-config-updater",
 		"reboot-executor",
 		"ssh-authkeys-updater",
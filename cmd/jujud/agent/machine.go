@@ -33,6 +33,7 @@ import (
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/juju/worker.v1"
 	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/txn"
 	"gopkg.in/natefinch/lumberjack.v2"
 	"gopkg.in/tomb.v1"
 
@@ -45,8 +46,10 @@ import (
 	apimachiner "github.com/juju/juju/api/machiner"
 	apiprovisioner "github.com/juju/juju/api/provisioner"
 	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/lockout"
 	"github.com/juju/juju/apiserver/observer"
 	"github.com/juju/juju/apiserver/observer/metricobserver"
+	"github.com/juju/juju/apiserver/observer/traceobserver"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/audit"
 	"github.com/juju/juju/cert"
@@ -57,6 +60,7 @@ import (
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/container/kvm"
 	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/tracing"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/simplestreams"
 	"github.com/juju/juju/instance"
@@ -92,6 +96,7 @@ import (
 	"github.com/juju/juju/worker/peergrouper"
 	"github.com/juju/juju/worker/provisioner"
 	psworker "github.com/juju/juju/worker/pubsub"
+	"github.com/juju/juju/worker/traceexporter"
 	"github.com/juju/juju/worker/upgradesteps"
 )
 
@@ -342,6 +347,38 @@ func (a *MachineAgent) registerPrometheusCollectors() error {
 	return nil
 }
 
+// afterRunTransaction is called when a mgo/txn transaction has run.
+// It updates the transaction metrics and, if traces are exported,
+// records a span for the transaction.
+func (a *MachineAgent) afterRunTransaction(dbName, modelUUID string, ops []txn.Op, duration time.Duration, err error) {
+	a.mongoTxnCollector.AfterRunTransaction(dbName, modelUUID, ops, duration, err)
+	if !a.spanExporter.Enabled() {
+		return
+	}
+
+	collections := set.NewStrings()
+	for _, op := range ops {
+		if op.Insert != nil || op.Update != nil || op.Remove {
+			collections.Add(op.C)
+		}
+	}
+	span := tracing.Span{
+		Kind:     tracing.KindTxn,
+		Name:     strings.Join(collections.SortedValues(), ","),
+		Start:    time.Now().Add(-duration),
+		Duration: duration,
+	}
+	if names.IsValidModel(modelUUID) {
+		span.Entity = names.NewModelTag(modelUUID).String()
+	}
+	if err != nil {
+		span.Error = err.Error()
+	}
+	if err := a.spanExporter.RecordSpans([]tracing.Span{span}); err != nil {
+		logger.Warningf("cannot record transaction span: %v", err)
+	}
+}
+
 // MachineAgent is responsible for tying together all functionality
 // needed to orchestrate a Jujud instance which controls a machine.
 type MachineAgent struct {
@@ -374,6 +411,10 @@ type MachineAgent struct {
 	mongoDialCollector         *mongometrics.DialCollector
 	preUpgradeSteps            upgrades.PreUpgradeStepsFunc
 
+	// spanExporter passes the spans of the controller's transactions
+	// to the trace exporter run by the API server, if any.
+	spanExporter tracing.RecorderSwitch
+
 	// Only API servers have hubs. This is temporary until the apiserver and
 	// peergrouper have manifolds.
 	centralHub *pubsub.StructuredHub
@@ -863,7 +904,7 @@ func (a *MachineAgent) openStateForUpgrade() (*state.State, error) {
 		// point in reading existing controller config from state in order
 		// to pass in the max-txn-log-size value.
 		InitDatabaseFunc:       state.InitDatabase,
-		RunTransactionObserver: a.mongoTxnCollector.AfterRunTransaction,
+		RunTransactionObserver: a.afterRunTransaction,
 	})
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -1006,7 +1047,7 @@ func (a *MachineAgent) initController(agentConfig agent.Config) (*state.Controll
 		NewPolicy: stateenvirons.GetNewPolicyFunc(
 			stateenvirons.GetNewEnvironFunc(environs.New),
 		),
-		RunTransactionObserver: a.mongoTxnCollector.AfterRunTransaction,
+		RunTransactionObserver: a.afterRunTransaction,
 	})
 	return ctlr, nil
 }
@@ -1028,7 +1069,7 @@ func (a *MachineAgent) initState(agentConfig agent.Config) (*state.State, error)
 	st, _, err := openState(
 		agentConfig,
 		dialOpts,
-		a.mongoTxnCollector.AfterRunTransaction,
+		a.afterRunTransaction,
 	)
 	if err != nil {
 		return nil, err
@@ -1126,7 +1167,7 @@ func (a *MachineAgent) startStateWorkers(
 				st, _, err := openState(
 					agentConfig,
 					dialOpts,
-					a.mongoTxnCollector.AfterRunTransaction,
+					a.afterRunTransaction,
 				)
 				return st, err
 			}
@@ -1187,6 +1228,7 @@ func (a *MachineAgent) startModelWorkers(controllerUUID, modelUUID string) (work
 		ActionPrunerInterval:        24 * time.Hour,
 		NewEnvironFunc:              newEnvirons,
 		NewMigrationMaster:          migrationmaster.NewWorker,
+		IsControllerModel:           modelUUID == a.CurrentConfig().Model().Id(),
 	})
 	if err := dependency.Install(engine, manifolds); err != nil {
 		if err := worker.Stop(engine); err != nil {
@@ -1232,7 +1274,7 @@ func (a *MachineAgent) newAPIserverWorker(
 	statePool *state.StatePool,
 	certChanged chan params.StateServingInfo,
 	dependencyReporter dependency.Reporter,
-) (worker.Worker, error) {
+) (_ worker.Worker, err error) {
 	agentConfig := a.CurrentConfig()
 	// If the configuration does not have the required information,
 	// it is currently not a recoverable error, so we kill the whole
@@ -1270,6 +1312,32 @@ func (a *MachineAgent) newAPIserverWorker(
 		return nil, errors.Annotate(err, "cannot fetch the controller config")
 	}
 
+	// spanExporter is left nil, rather than holding a nil
+	// *traceexporter.Exporter, when traces are not exported.
+	var exporter *traceexporter.Exporter
+	var spanExporter tracing.Recorder
+	if otlpEndpoint := controllerConfig.OTLPEndpoint(); otlpEndpoint != "" {
+		exporter, err = traceexporter.New(traceexporter.Config{
+			Endpoint:      otlpEndpoint,
+			Headers:       controllerConfig.OTLPHeaders(),
+			SamplingRatio: controllerConfig.OTLPSamplingRatio(),
+			InstanceID:    tag.String(),
+			FlushInterval: 5 * time.Second,
+			MaxQueueSize:  10000,
+			HTTPClient:    &http.Client{Timeout: 30 * time.Second},
+			Clock:         clock.WallClock,
+		})
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot start trace exporter")
+		}
+		defer func() {
+			if err != nil {
+				worker.Stop(exporter)
+			}
+		}()
+		spanExporter = exporter
+	}
+
 	newObserver, err := newObserverFn(
 		controllerConfig,
 		clock.WallClock,
@@ -1278,6 +1346,7 @@ func (a *MachineAgent) newAPIserverWorker(
 		newAuditEntrySink(st, logDir),
 		auditErrorHandler,
 		a.prometheusRegistry,
+		spanExporter,
 	)
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot create RPC observer factory")
@@ -1319,6 +1388,14 @@ func (a *MachineAgent) newAPIserverWorker(
 		RateLimitConfig:               rateLimitConfig,
 		LogSinkConfig:                 &logSinkConfig,
 		PrometheusRegisterer:          a.prometheusRegistry,
+		SpanExporter:                  spanExporter,
+		SessionIdleTimeout:            controllerConfig.APISessionIdleTimeout(),
+		LoginLockout: lockout.Policy{
+			MaxFailures:        controllerConfig.LoginLockoutMaxFailures(),
+			MaxAddressFailures: controllerConfig.LoginLockoutMaxAddressFailures(),
+			Duration:           controllerConfig.LoginLockoutDuration(),
+			FailurePause:       lockout.DefaultFailurePause,
+		},
 	})
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot start api server worker")
@@ -1334,6 +1411,10 @@ func (a *MachineAgent) newAPIserverWorker(
 		return newStateMetricsWorker(statePool, a.prometheusRegistry), nil
 	})
 
+	workers := []worker.Worker{server, stateMetricsRunner}
+	if exporter != nil {
+		workers = append(workers, exporter)
+	}
 	var apiserverWorker catacombWorker
 	if err := catacomb.Invoke(catacomb.Plan{
 		Site: &apiserverWorker.Catacomb,
@@ -1341,6 +1422,7 @@ func (a *MachineAgent) newAPIserverWorker(
 			defer st.Close()
 			defer statePool.Close()
 			defer a.statePool.set(nil)
+			defer a.spanExporter.Set(nil)
 			<-apiserverWorker.Catacomb.Dying()
 			// Wait for the workers to die before
 			// closing the state pool, as they
@@ -1349,11 +1431,12 @@ func (a *MachineAgent) newAPIserverWorker(
 			stateMetricsRunner.Wait()
 			return apiserverWorker.Catacomb.ErrDying()
 		},
-		Init: []worker.Worker{server, stateMetricsRunner},
+		Init: workers,
 	}); err != nil {
 		return nil, errors.Trace(err)
 	}
 	a.statePool.set(statePool)
+	a.spanExporter.Set(spanExporter)
 	return &apiserverWorker, nil
 }
 
@@ -1483,6 +1566,7 @@ func newObserverFn(
 	persistAuditEntry audit.AuditEntrySinkFn,
 	auditErrorHandler observer.ErrorHandler,
 	prometheusRegisterer prometheus.Registerer,
+	spanRecorder tracing.Recorder,
 ) (observer.ObserverFactory, error) {
 
 	var observerFactories []observer.ObserverFactory
@@ -1519,6 +1603,18 @@ func newObserverFn(
 	}
 	observerFactories = append(observerFactories, metricObserver)
 
+	// Tracing observer, when spans are exported.
+	if spanRecorder != nil {
+		traceObserver, err := traceobserver.NewObserverFactory(traceobserver.Config{
+			Clock:    clock,
+			Recorder: spanRecorder,
+		})
+		if err != nil {
+			return nil, errors.Annotate(err, "creating trace observer factory")
+		}
+		observerFactories = append(observerFactories, traceObserver)
+	}
+
 	return observer.ObserverFactoryMultiplexer(observerFactories...), nil
 
 }
@@ -29,6 +29,7 @@ import (
 	proxyconfig "github.com/juju/juju/utils/proxy"
 	jworker "github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/agent"
+	"github.com/juju/juju/worker/agentlimits"
 	"github.com/juju/juju/worker/apiaddressupdater"
 	"github.com/juju/juju/worker/apicaller"
 	"github.com/juju/juju/worker/apiconfigwatcher"
@@ -51,11 +52,13 @@ import (
 	"github.com/juju/juju/worker/machiner"
 	"github.com/juju/juju/worker/migrationflag"
 	"github.com/juju/juju/worker/migrationminion"
+	"github.com/juju/juju/worker/packageupdatereporter"
 	"github.com/juju/juju/worker/proxyupdater"
 	psworker "github.com/juju/juju/worker/pubsub"
 	"github.com/juju/juju/worker/reboot"
 	"github.com/juju/juju/worker/resumer"
 	"github.com/juju/juju/worker/singular"
+	"github.com/juju/juju/worker/sshbastion"
 	workerstate "github.com/juju/juju/worker/state"
 	"github.com/juju/juju/worker/stateconfigwatcher"
 	"github.com/juju/juju/worker/storageprovisioner"
@@ -74,6 +77,10 @@ const (
 	// globalClockUpdaterBackoffDelay is the amount of time to
 	// delay when a concurrent global clock update is detected.
 	globalClockUpdaterBackoffDelay = 10 * time.Second
+
+	// packageUpdateReportInterval is the interval between reports of
+	// the package updates pending on the machine.
+	packageUpdateReportInterval = 6 * time.Hour
 )
 
 // ManifoldsConfig allows specialisation of the result of Manifolds.
@@ -487,6 +494,17 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			UpdateAgentFunc: config.UpdateLoggerConfig,
 		})),
 
+		// The agent limits worker bounds the CPU and memory used by
+		// the agent according to the model config, restarting the
+		// agent should it use more memory than permitted.
+		agentLimitsName: ifNotMigrating(agentlimits.Manifold(agentlimits.ManifoldConfig{
+			AgentName:     agentName,
+			APICallerName: apiCallerName,
+			Clock:         config.Clock,
+			NewFacade:     agentlimits.NewFacade,
+			NewWorker:     agentlimits.New,
+		})),
+
 		// The diskmanager worker periodically lists block devices on the
 		// machine it runs on. This worker will be run on all Juju-managed
 		// machines (one per machine agent).
@@ -599,6 +617,17 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			NewWorker:     hostkeyreporter.NewWorker,
 		})),
 
+		packageUpdateReporterName: ifNotMigrating(packageupdatereporter.Manifold(packageupdatereporter.ManifoldConfig{
+			AgentName:     agentName,
+			APICallerName: apiCallerName,
+			ClockName:     clockName,
+			RootDir:       config.RootDir,
+			Interval:      packageUpdateReportInterval,
+			CheckUpdates:  packageupdatereporter.CheckAptUpdates,
+			NewFacade:     packageupdatereporter.NewFacade,
+			NewWorker:     packageupdatereporter.NewWorker,
+		})),
+
 		externalControllerUpdaterName: ifNotMigrating(ifPrimaryController(externalcontrollerupdater.Manifold(
 			externalcontrollerupdater.ManifoldConfig{
 				APICallerName:                      apiCallerName,
@@ -613,6 +642,13 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 				NewWorker:     dblogpruner.NewWorker,
 			},
 		))),
+		sshBastionName: ifNotMigrating(sshbastion.Manifold(sshbastion.ManifoldConfig{
+			AgentName: agentName,
+			ClockName: clockName,
+			StateName: stateName,
+			RootDir:   config.RootDir,
+			NewWorker: sshbastion.NewWorker,
+		})),
 		txnPrunerName: ifNotMigrating(ifPrimaryController(txnpruner.Manifold(
 			txnpruner.ManifoldConfig{
 				ClockName:     clockName,
@@ -687,6 +723,7 @@ const (
 	apiWorkersName                = "unconverted-api-workers"
 	rebootName                    = "reboot-executor"
 	loggingConfigUpdaterName      = "logging-config-updater"
+	agentLimitsName               = "agent-limits"
 	diskManagerName               = "disk-manager"
 	proxyConfigUpdater            = "proxy-config-updater"
 	apiAddressUpdaterName         = "api-address-updater"
@@ -700,6 +737,7 @@ const (
 	toolsVersionCheckerName       = "tools-version-checker"
 	machineActionName             = "machine-action-runner"
 	hostKeyReporterName           = "host-key-reporter"
+	packageUpdateReporterName     = "package-update-reporter"
 	fanConfigurerName             = "fan-configurer"
 	externalControllerUpdaterName = "external-controller-updater"
 	globalClockUpdaterName        = "global-clock-updater"
@@ -707,4 +745,5 @@ const (
 	isControllerFlagName          = "is-controller-flag"
 	logPrunerName                 = "log-pruner"
 	txnPrunerName                 = "transaction-pruner"
+	sshBastionName                = "ssh-bastion"
 )
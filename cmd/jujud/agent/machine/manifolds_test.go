@@ -44,6 +44,7 @@ func (*ManifoldsSuite) TestManifoldNames(c *gc.C) {
 	sort.Strings(keys)
 	expectedKeys := []string{
 		"agent",
+		"agent-limits",
 		"api-address-updater",
 		"api-caller",
 		"api-config-watcher",
@@ -65,11 +66,13 @@ func (*ManifoldsSuite) TestManifoldNames(c *gc.C) {
 		"migration-fortress",
 		"migration-minion",
 		"migration-inactive-flag",
+		"package-update-reporter",
 		"proxy-config-updater",
 		"pubsub-forwarder",
 		"reboot-executor",
 		"serving-info-setter",
 		"ssh-authkeys-updater",
+		"ssh-bastion",
 		"ssh-identity-writer",
 		"state",
 		"state-config-watcher",
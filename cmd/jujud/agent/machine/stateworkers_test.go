@@ -110,6 +110,10 @@ func (t *mockStateTracker) Use() (*state.State, error) {
 	return new(state.State), nil
 }
 
+func (t *mockStateTracker) Pool() *state.StatePool {
+	return nil
+}
+
 func (t *mockStateTracker) Done() error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -1203,8 +1203,9 @@ func (s *MachineSuite) TestControllerModelWorkers(c *gc.C) {
 	instrumented := TrackModels(c, tracker, modelManifolds)
 	s.PatchValue(&modelManifolds, instrumented)
 
+	workers := append(alwaysModelWorkers, aliveModelWorkers...)
 	matcher := NewWorkerMatcher(c, tracker, uuid,
-		append(alwaysModelWorkers, aliveModelWorkers...))
+		append(workers, controllerModelWorkers...))
 	s.assertJobWithState(c, state.JobManageModel, func(agent.Config, *state.State) {
 		WaitMatch(c, matcher.Check, coretesting.LongWait, s.BackingState.StartSync)
 	})
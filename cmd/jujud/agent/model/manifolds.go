@@ -25,8 +25,10 @@ import (
 	"github.com/juju/juju/worker/charmrevision"
 	"github.com/juju/juju/worker/charmrevision/charmrevisionmanifold"
 	"github.com/juju/juju/worker/cleaner"
+	"github.com/juju/juju/worker/controllerfirewaller"
 	"github.com/juju/juju/worker/dependency"
 	"github.com/juju/juju/worker/environ"
+	"github.com/juju/juju/worker/ephemeralreaper"
 	"github.com/juju/juju/worker/firewaller"
 	"github.com/juju/juju/worker/fortress"
 	"github.com/juju/juju/worker/gate"
@@ -43,10 +45,12 @@ import (
 	"github.com/juju/juju/worker/pruner"
 	"github.com/juju/juju/worker/remoterelations"
 	"github.com/juju/juju/worker/singular"
+	"github.com/juju/juju/worker/sshkeyimporter"
 	"github.com/juju/juju/worker/statushistorypruner"
 	"github.com/juju/juju/worker/storageprovisioner"
 	"github.com/juju/juju/worker/undertaker"
 	"github.com/juju/juju/worker/unitassigner"
+	"github.com/juju/juju/worker/unitmover"
 )
 
 // ManifoldsConfig holds the dependencies and configuration options for a
@@ -99,6 +103,10 @@ type ManifoldsConfig struct {
 	// NewMigrationMaster is called to create a new migrationmaster
 	// worker.
 	NewMigrationMaster func(migrationmaster.Config) (worker.Worker, error)
+
+	// IsControllerModel is true if the manifolds administer the
+	// controller model, for which some additional workers are run.
+	IsControllerModel bool
 }
 
 // Manifolds returns a set of interdependent dependency manifolds that will
@@ -316,6 +324,24 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			NewFacade:     actionpruner.NewFacade,
 			PruneInterval: config.ActionPrunerInterval,
 		})),
+		sshKeyImporterName: ifNotMigrating(sshkeyimporter.Manifold(sshkeyimporter.ManifoldConfig{
+			APICallerName: apiCallerName,
+			ClockName:     clockName,
+			NewFacade:     sshkeyimporter.NewFacade,
+			NewWorker:     sshkeyimporter.New,
+		})),
+		ephemeralReaperName: ifNotMigrating(ephemeralreaper.Manifold(ephemeralreaper.ManifoldConfig{
+			APICallerName: apiCallerName,
+			ClockName:     clockName,
+			NewFacade:     ephemeralreaper.NewFacade,
+			NewWorker:     ephemeralreaper.New,
+		})),
+		unitMoverName: ifNotMigrating(unitmover.Manifold(unitmover.ManifoldConfig{
+			APICallerName: apiCallerName,
+			ClockName:     clockName,
+			NewFacade:     unitmover.NewFacade,
+			NewWorker:     unitmover.New,
+		})),
 		machineUndertakerName: ifNotMigrating(machineundertaker.Manifold(machineundertaker.ManifoldConfig{
 			APICallerName: apiCallerName,
 			EnvironName:   environTrackerName,
@@ -336,6 +362,16 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 		NewRemoteRelationsFacade: remoterelations.NewRemoteRelationsFacade,
 		NewWorker:                remoterelations.NewWorker,
 	}))
+	if config.IsControllerModel {
+		// Only the controller model's environ manages the ingress
+		// rules of the controller's own instances.
+		result[controllerFirewallerName] = ifNotMigrating(controllerfirewaller.Manifold(controllerfirewaller.ManifoldConfig{
+			APICallerName: apiCallerName,
+			EnvironName:   environTrackerName,
+			NewFacade:     controllerfirewaller.NewFacade,
+			NewWorker:     controllerfirewaller.New,
+		}))
+	}
 	return result
 }
 
@@ -431,7 +467,9 @@ const (
 	computeProvisionerName   = "compute-provisioner"
 	storageProvisionerName   = "storage-provisioner"
 	firewallerName           = "firewaller"
+	controllerFirewallerName = "controller-firewaller"
 	unitAssignerName         = "unit-assigner"
+	unitMoverName            = "unit-mover"
 	applicationScalerName    = "application-scaler"
 	instancePollerName       = "instance-poller"
 	charmRevisionUpdaterName = "charm-revision-updater"
@@ -439,6 +477,8 @@ const (
 	stateCleanerName         = "state-cleaner"
 	statusHistoryPrunerName  = "status-history-pruner"
 	actionPrunerName         = "action-pruner"
+	sshKeyImporterName       = "ssh-key-importer"
+	ephemeralReaperName      = "ephemeral-reaper"
 	machineUndertakerName    = "machine-undertaker"
 	remoteRelationsName      = "remote-relations"
 	logForwarderName         = "log-forwarder"
@@ -40,6 +40,7 @@ func (s *ManifoldsSuite) TestNames(c *gc.C) {
 		"clock",
 		"compute-provisioner",
 		"environ-tracker",
+		"ephemeral-reaper",
 		"firewaller",
 		"instance-poller",
 		"is-responsible-flag",
@@ -55,14 +56,25 @@ func (s *ManifoldsSuite) TestNames(c *gc.C) {
 		"not-alive-flag",
 		"not-dead-flag",
 		"remote-relations",
+		"ssh-key-importer",
 		"state-cleaner",
 		"status-history-pruner",
 		"storage-provisioner",
 		"undertaker",
 		"unit-assigner",
+		"unit-mover",
 	})
 }
 
+func (s *ManifoldsSuite) TestControllerModelNames(c *gc.C) {
+	manifolds := model.Manifolds(model.ManifoldsConfig{
+		Agent:             &mockAgent{},
+		IsControllerModel: true,
+	})
+	_, ok := manifolds["controller-firewaller"]
+	c.Check(ok, jc.IsTrue)
+}
+
 func (s *ManifoldsSuite) TestFlagDependencies(c *gc.C) {
 	exclusions := set.NewStrings(
 		"agent",
@@ -155,7 +167,9 @@ func (s *ManifoldsCrossModelSuite) TestNames(c *gc.C) {
 		"charm-revision-updater",
 		"clock",
 		"compute-provisioner",
+		"controller-firewaller",
 		"environ-tracker",
+		"ephemeral-reaper",
 		"firewaller",
 		"instance-poller",
 		"is-responsible-flag",
@@ -171,10 +185,12 @@ func (s *ManifoldsCrossModelSuite) TestNames(c *gc.C) {
 		"not-alive-flag",
 		"not-dead-flag",
 		"remote-relations",
+		"ssh-key-importer",
 		"state-cleaner",
 		"status-history-pruner",
 		"storage-provisioner",
 		"undertaker",
 		"unit-assigner",
+		"unit-mover",
 	})
 }
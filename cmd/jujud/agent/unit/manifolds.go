@@ -22,6 +22,7 @@ import (
 	"github.com/juju/juju/utils/proxy"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/agent"
+	"github.com/juju/juju/worker/agentlimits"
 	"github.com/juju/juju/worker/apiaddressupdater"
 	"github.com/juju/juju/worker/apicaller"
 	"github.com/juju/juju/worker/apiconfigwatcher"
@@ -242,6 +243,17 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			UpdateAgentFunc: config.UpdateLoggerConfig,
 		})),
 
+		// The agent limits worker bounds the CPU and memory used by
+		// the agent according to the model config, restarting the
+		// agent should it use more memory than permitted.
+		agentLimitsName: ifNotMigrating(agentlimits.Manifold(agentlimits.ManifoldConfig{
+			AgentName:     agentName,
+			APICallerName: apiCallerName,
+			Clock:         clock.WallClock,
+			NewFacade:     agentlimits.NewFacade,
+			NewWorker:     agentlimits.New,
+		})),
+
 		// The api address updater is a leaf worker that rewrites agent config
 		// as the controller addresses change. We should only need one of
 		// these in a consolidated agent.
@@ -371,6 +383,7 @@ const (
 	migrationMinionName       = "migration-minion"
 
 	loggingConfigUpdaterName = "logging-config-updater"
+	agentLimitsName          = "agent-limits"
 	proxyConfigUpdaterName   = "proxy-config-updater"
 	apiAddressUpdaterName    = "api-address-updater"
 
@@ -43,6 +43,7 @@ func (s *ManifoldsSuite) TestManifoldNames(c *gc.C) {
 		"migration-minion",
 		"migration-inactive-flag",
 		"logging-config-updater",
+		"agent-limits",
 		"proxy-config-updater",
 		"api-address-updater",
 		"charm-dir",
@@ -23,6 +23,7 @@ import (
 	cmdutil "github.com/juju/juju/cmd/jujud/util"
 	"github.com/juju/juju/juju/sockets"
 	"github.com/juju/juju/worker/uniter"
+	"github.com/juju/juju/worker/uniter/runner"
 )
 
 type RunCommand struct {
@@ -48,7 +49,8 @@ or the unit id:
 If --no-context is specified, the <unit-name> positional
 argument is not needed.
 
-The commands are executed with '/bin/bash -s', and the output returned.
+The commands are executed with '/bin/bash -s', or with PowerShell on
+Windows, and the output returned.
 `
 
 // Info returns usage information for the command.
@@ -192,7 +194,7 @@ func (c *RunCommand) executeNoContext() (*exec.ExecResponse, error) {
 	defer logger.Debugf("release lock %q for juju-run", c.MachineLockName)
 	defer releaser.Release()
 
-	runCmd := c.appendProxyToCommands()
+	runCmd := runner.ShellCommands(c.appendProxyToCommands())
 
 	return exec.RunCommands(
 		exec.RunParams{
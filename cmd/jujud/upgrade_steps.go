@@ -0,0 +1,148 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/clock"
+	"github.com/juju/version"
+	"gopkg.in/juju/names.v2"
+
+	agentcmd "github.com/juju/juju/cmd/jujud/agent"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/upgrades"
+	jujuversion "github.com/juju/juju/version"
+)
+
+const upgradeStepsDoc = `
+upgrade-steps lists the state upgrade steps that a controller would run
+when upgrading to the version of this jujud binary, without running
+them. Each step is validated against the controller's database and,
+where possible, the number of documents it will change is estimated,
+so that the downtime of the upgrade can be gauged beforehand.
+
+It must be run on a controller machine, with the jujud binary of the
+version being upgraded to. Steps are only run by the machine agent, so
+--dry-run must be specified.
+`
+
+// NewUpgradeStepsCommand returns a new upgrade-steps command.
+func NewUpgradeStepsCommand() cmd.Command {
+	return &upgradeStepsCommand{
+		agentConfig: agentcmd.NewAgentConf(""),
+	}
+}
+
+type upgradeStepsCommand struct {
+	cmd.CommandBase
+	agentConfig agentcmd.AgentConf
+	machineId   string
+	dryRun      bool
+	toVersion   string
+	to          version.Number
+}
+
+// Info implements cmd.Command.
+func (c *upgradeStepsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "upgrade-steps",
+		Purpose: "list and validate the pending state upgrade steps",
+		Doc:     upgradeStepsDoc,
+	}
+}
+
+// SetFlags implements cmd.Command.
+func (c *upgradeStepsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.agentConfig.AddFlags(f)
+	f.StringVar(&c.machineId, "machine-id", "", "id of the controller machine on this host")
+	f.BoolVar(&c.dryRun, "dry-run", false, "list the upgrade steps without running them")
+	f.StringVar(&c.toVersion, "to", "", "version to upgrade to (defaults to the jujud version)")
+}
+
+// Init implements cmd.Command.
+func (c *upgradeStepsCommand) Init(args []string) error {
+	if !c.dryRun {
+		return errors.New("upgrade steps are only run by the machine agent; specify --dry-run to list them")
+	}
+	if !names.IsValidMachine(c.machineId) {
+		return errors.New("--machine-id option expects a non-negative integer")
+	}
+	c.to = jujuversion.Current
+	if c.toVersion != "" {
+		to, err := version.Parse(c.toVersion)
+		if err != nil {
+			return errors.Annotate(err, "invalid --to version")
+		}
+		c.to = to
+	}
+	if err := c.agentConfig.CheckArgs(args); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(c.agentConfig.ReadConfig(names.NewMachineTag(c.machineId).String()))
+}
+
+// Run implements cmd.Command.
+func (c *upgradeStepsCommand) Run(ctx *cmd.Context) error {
+	config := c.agentConfig.CurrentConfig()
+	info, ok := config.MongoInfo()
+	if !ok {
+		return errors.New("no database connection info available (is this a controller host?)")
+	}
+	st, err := state.Open(state.OpenParams{
+		Clock:              clock.WallClock,
+		ControllerTag:      config.Controller(),
+		ControllerModelTag: config.Model(),
+		MongoInfo:          info,
+		MongoDialOpts:      mongo.DefaultDialOpts(),
+	})
+	if err != nil {
+		return errors.Annotate(err, "failed to connect to database")
+	}
+	defer st.Close()
+
+	from := config.UpgradedToVersion()
+	planned, err := upgrades.PlanStateUpgrade(from, c.to, upgrades.NewStateBackend(st))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(planned) == 0 {
+		ctx.Infof("no state upgrade steps pending from %s to %s", from, c.to)
+		return nil
+	}
+	ctx.Infof("state upgrade steps pending from %s to %s:", from, c.to)
+	if failed := formatPlannedSteps(ctx.Stdout, planned); failed > 0 {
+		return errors.Errorf("%d upgrade steps failed validation", failed)
+	}
+	return nil
+}
+
+// formatPlannedSteps writes a table of the planned steps to the
+// writer, returning the number of steps that failed validation.
+func formatPlannedSteps(writer io.Writer, planned []upgrades.PlannedStep) int {
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Version", "Documents", "Step", "Problem")
+	var failed int
+	for _, step := range planned {
+		docs := "unknown"
+		if step.EstimatedDocs >= 0 {
+			docs = fmt.Sprint(step.EstimatedDocs)
+		}
+		problem := ""
+		if step.Err != nil {
+			problem = step.Err.Error()
+			failed++
+		}
+		w.Println(step.TargetVersion, docs, step.Description, problem)
+	}
+	tw.Flush()
+	return failed
+}
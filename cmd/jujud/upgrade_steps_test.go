@@ -0,0 +1,60 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/upgrades"
+)
+
+type UpgradeStepsCommandSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&UpgradeStepsCommandSuite{})
+
+func (s *UpgradeStepsCommandSuite) TestInitRequiresDryRun(c *gc.C) {
+	err := cmdtesting.InitCommand(NewUpgradeStepsCommand(), []string{"--machine-id", "0"})
+	c.Assert(err, gc.ErrorMatches, "upgrade steps are only run by the machine agent; specify --dry-run to list them")
+}
+
+func (s *UpgradeStepsCommandSuite) TestInitRequiresMachineId(c *gc.C) {
+	err := cmdtesting.InitCommand(NewUpgradeStepsCommand(), []string{"--dry-run"})
+	c.Assert(err, gc.ErrorMatches, "--machine-id option expects a non-negative integer")
+}
+
+func (s *UpgradeStepsCommandSuite) TestInitInvalidVersion(c *gc.C) {
+	err := cmdtesting.InitCommand(NewUpgradeStepsCommand(), []string{
+		"--dry-run", "--machine-id", "0", "--to", "foo",
+	})
+	c.Assert(err, gc.ErrorMatches, `invalid --to version: invalid version "foo"`)
+}
+
+func (s *UpgradeStepsCommandSuite) TestFormatPlannedSteps(c *gc.C) {
+	var buf bytes.Buffer
+	failed := formatPlannedSteps(&buf, []upgrades.PlannedStep{{
+		TargetVersion: version.MustParse("2.3.0"),
+		Description:   "add a 'type' field to model documents",
+		EstimatedDocs: 3,
+	}, {
+		TargetVersion: version.MustParse("2.3.0"),
+		Description:   "migrate old leases",
+		EstimatedDocs: -1,
+		Err:           errors.New("bad lease"),
+	}})
+	c.Assert(failed, gc.Equals, 1)
+	c.Assert(buf.String(), jc.DeepEquals, `
+Version  Documents  Step                                   Problem
+2.3.0    3          add a 'type' field to model documents  
+2.3.0    unknown    migrate old leases                     bad lease
+`[1:])
+}
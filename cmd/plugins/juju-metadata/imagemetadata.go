@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/filestorage"
 	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/mirror"
 	"github.com/juju/juju/environs/simplestreams"
 	"github.com/juju/juju/environs/storage"
 )
@@ -203,15 +204,18 @@ func (c *imageMetadataCommand) Run(context *cmd.Context) error {
 		VirtType: c.VirtType,
 		Storage:  c.Storage,
 	}
-	cloudSpec := simplestreams.CloudSpec{
-		Region:   c.Region,
-		Endpoint: c.Endpoint,
-	}
 	targetStorage, err := filestorage.NewFileStorageWriter(c.Dir)
 	if err != nil {
 		return err
 	}
-	err = imagemetadata.MergeAndWriteMetadata(c.Series, []*imagemetadata.ImageMetadata{im}, &cloudSpec, targetStorage)
+	err = mirror.GenerateImageMetadata(targetStorage, mirror.ImageParams{
+		Series: c.Series,
+		Images: []*imagemetadata.ImageMetadata{im},
+		CloudSpec: simplestreams.CloudSpec{
+			Region:   c.Region,
+			Endpoint: c.Endpoint,
+		},
+	})
 	if err != nil {
 		return errors.Errorf("image metadata files could not be created: %v", err)
 	}
@@ -5,16 +5,13 @@ package main
 
 import (
 	"io/ioutil"
-	"os"
-	"path/filepath"
-	"strings"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
 	"github.com/juju/loggo"
 
-	"github.com/juju/juju/environs/simplestreams"
+	"github.com/juju/juju/environs/mirror"
 )
 
 func newSignMetadataCommand() cmd.Command {
@@ -74,42 +71,5 @@ func (c *signMetadataCommand) Run(context *cmd.Context) error {
 		return err
 	}
 	dir := context.AbsPath(c.dir)
-	return process(dir, string(keyData), c.passphrase)
-}
-
-func process(dir, key, passphrase string) error {
-	logger.Debugf("processing directory %q", dir)
-	// Do any json files in dir
-	filenames, err := filepath.Glob(filepath.Join(dir, "*"+simplestreams.UnsignedSuffix))
-	if len(filenames) > 0 {
-		logger.Infof("signing %d file(s) in %q", len(filenames), dir)
-	}
-	for _, filename := range filenames {
-		logger.Infof("signing file %q", filename)
-		f, err := os.Open(filename)
-		if err != nil {
-			return errors.Errorf("opening file %q: %v", filename, err)
-		}
-		encoded, err := simplestreams.Encode(f, key, passphrase)
-		if err != nil {
-			return errors.Errorf("encoding file %q: %v", filename, err)
-		}
-		signedFilename := strings.Replace(filename, simplestreams.UnsignedSuffix, simplestreams.SignedSuffix, -1)
-		if err = ioutil.WriteFile(signedFilename, encoded, 0644); err != nil {
-			return errors.Errorf("writing signed file %q: %v", signedFilename, err)
-		}
-	}
-	// Now process any directories in dir.
-	files, err := ioutil.ReadDir(dir)
-	if err != nil {
-		return err
-	}
-	for _, f := range files {
-		if f.IsDir() {
-			if err = process(filepath.Join(dir, f.Name()), key, passphrase); err != nil {
-				return err
-			}
-		}
-	}
-	return nil
+	return mirror.SignMetadata(dir, string(keyData), c.passphrase)
 }
@@ -5,6 +5,7 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -14,8 +15,8 @@ import (
 
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/environs/filestorage"
+	"github.com/juju/juju/environs/mirror"
 	"github.com/juju/juju/environs/simplestreams"
-	"github.com/juju/juju/environs/storage"
 	envtools "github.com/juju/juju/environs/tools"
 	"github.com/juju/juju/juju/keys"
 	"github.com/juju/juju/juju/osenv"
@@ -34,6 +35,8 @@ type toolsMetadataCommand struct {
 	stream      string
 	clean       bool
 	public      bool
+	keyFile     string
+	passphrase  string
 }
 
 const toolsMetadataDoc = `
@@ -59,6 +62,11 @@ Newly generated metadata will be merged with any exisitng metadata that is alrea
 To first remove metadata for the specified stream before generating new metadata,
 use the --clean option.
 
+To have controllers configured with agent-binary-trusted-keys accept the agent
+binaries, sign each tarball by passing the armored private key with -k; the
+signatures are recorded in the metadata. If the key is encrypted, the passphrase
+given with -p is used to decrypt it.
+
 Examples:
 
 # generate metadata for "released":
@@ -72,6 +80,9 @@ juju metadata generate-agents -d <workingdir> --stream proposed
 
 # generate metadata for "proposed", first removing existing "proposed" metadata:
 juju metadata generate-agents -d <workingdir> --stream proposed --clean
+
+# generate metadata for "released", signing the agent binaries:
+juju metadata generate-agents -d <workingdir> -k <keyfile> -p <passphrase>
 `
 
 func (c *toolsMetadataCommand) Info() *cmd.Info {
@@ -91,6 +102,8 @@ func (c *toolsMetadataCommand) SetFlags(f *gnuflag.FlagSet) {
 		"remove any existing metadata for the specified stream before generating new metadata")
 	f.BoolVar(&c.public, "public", false,
 		"agent binaries are for a public cloud, so generate mirror information")
+	f.StringVar(&c.keyFile, "k", "", "file containing the armored private key to sign the agent binaries with")
+	f.StringVar(&c.passphrase, "p", "", "passphrase used to decrypt the private key")
 }
 
 func (c *toolsMetadataCommand) Run(context *cmd.Context) error {
@@ -105,6 +118,15 @@ func (c *toolsMetadataCommand) Run(context *cmd.Context) error {
 		c.metadataDir = context.AbsPath(c.metadataDir)
 	}
 
+	var signingKey string
+	if c.keyFile != "" {
+		keyData, err := ioutil.ReadFile(context.AbsPath(c.keyFile))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		signingKey = string(keyData)
+	}
+
 	sourceStorage, err := filestorage.NewFileStorageReader(c.metadataDir)
 	if err != nil {
 		return errors.Trace(err)
@@ -128,11 +150,14 @@ func (c *toolsMetadataCommand) Run(context *cmd.Context) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	writeMirrors := envtools.DoNotWriteMirrors
-	if c.public {
-		writeMirrors = envtools.WriteMirrors
-	}
-	return errors.Trace(mergeAndWriteMetadata(targetStorage, c.stream, c.stream, c.clean, toolsList, writeMirrors))
+	return errors.Trace(mirror.GenerateToolsMetadata(targetStorage, mirror.ToolsParams{
+		Stream:     c.stream,
+		Tools:      toolsList,
+		Clean:      c.clean,
+		Public:     c.public,
+		SigningKey: signingKey,
+		Passphrase: c.passphrase,
+	}))
 }
 
 func toolsDataSources(urls ...string) []simplestreams.DataSource {
@@ -148,28 +173,3 @@ func toolsDataSources(urls ...string) []simplestreams.DataSource {
 	}
 	return dataSources
 }
-
-// This is essentially the same as tools.MergeAndWriteMetadata, but also
-// resolves metadata for existing agents by fetching them and computing
-// size/sha256 locally.
-func mergeAndWriteMetadata(
-	stor storage.Storage, toolsDir, stream string, clean bool, toolsList coretools.List, writeMirrors envtools.ShouldWriteMirrors,
-) error {
-	existing, err := envtools.ReadAllMetadata(stor)
-	if err != nil {
-		return err
-	}
-	if clean {
-		delete(existing, stream)
-	}
-	metadata := envtools.MetadataFromTools(toolsList, toolsDir)
-	var mergedMetadata []*envtools.ToolsMetadata
-	if mergedMetadata, err = envtools.MergeMetadata(metadata, existing[stream]); err != nil {
-		return err
-	}
-	if err = envtools.ResolveMetadata(stor, toolsDir, mergedMetadata); err != nil {
-		return err
-	}
-	existing[stream] = mergedMetadata
-	return envtools.WriteMetadata(stor, existing, []string{stream}, writeMirrors)
-}
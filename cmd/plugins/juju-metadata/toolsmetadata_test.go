@@ -6,6 +6,7 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -24,6 +25,7 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/bootstrap"
 	"github.com/juju/juju/environs/config"
+	sstesting "github.com/juju/juju/environs/simplestreams/testing"
 	"github.com/juju/juju/environs/tools"
 	toolstesting "github.com/juju/juju/environs/tools/testing"
 	"github.com/juju/juju/juju/keys"
@@ -161,6 +163,25 @@ func (s *ToolsMetadataSuite) TestGenerateStream(c *gc.C) {
 	c.Assert(obtainedVersionStrings, gc.DeepEquals, versionStrings)
 }
 
+func (s *ToolsMetadataSuite) TestGenerateSigned(c *gc.C) {
+	metadataDir := c.MkDir()
+	toolstesting.MakeTools(c, metadataDir, "released", versionStrings)
+	keyFile := filepath.Join(c.MkDir(), "private.asc")
+	err := ioutil.WriteFile(keyFile, []byte(sstesting.SignedMetadataPrivateKey), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(newToolsMetadataCommand(), ctx, []string{
+		"-d", metadataDir, "-k", keyFile, "-p", sstesting.PrivateKeyPassphrase,
+	})
+	c.Assert(code, gc.Equals, 0)
+	metadata := toolstesting.ParseMetadataFromDir(c, metadataDir, "released", false)
+	c.Assert(metadata, gc.HasLen, len(versionStrings))
+	for _, md := range metadata {
+		c.Check(md.Signature, gc.Not(gc.Equals), "")
+	}
+}
+
 func (s *ToolsMetadataSuite) TestGenerateMultipleStreams(c *gc.C) {
 	metadataDir := c.MkDir()
 	toolstesting.MakeTools(c, metadataDir, "proposed", versionStrings)
@@ -5,13 +5,16 @@ package controller
 
 import (
 	"fmt"
+	"net"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/schema"
 	"github.com/juju/utils"
 	utilscert "github.com/juju/utils/cert"
+	"golang.org/x/crypto/openpgp"
 	"gopkg.in/macaroon-bakery.v1/bakery"
 
 	"github.com/juju/juju/cert"
@@ -82,6 +85,94 @@ const (
 	// MaxTxnLogSize is the maximum size the of capped txn log collection, eg "10M"
 	MaxTxnLogSize = "max-txn-log-size"
 
+	// LeadershipLeaseDuration is the length of time for which an
+	// application leadership claim is held before it must be renewed,
+	// eg "1m".
+	LeadershipLeaseDuration = "leadership-lease-duration"
+
+	// LeadershipRenewalInterval is how often an application leader
+	// renews its leadership lease, eg "30s". It must be shorter than
+	// the lease duration. Unit agents read both settings when their
+	// leadership tracker starts.
+	LeadershipRenewalInterval = "leadership-renewal-interval"
+
+	// BastionPort is the port on which each controller machine accepts
+	// ssh connections destined for the machines of its models. The
+	// bastion is disabled when no port is configured.
+	BastionPort = "bastion-port"
+
+	// BastionRecordSessions sets whether the output of ssh sessions
+	// made through the bastion is recorded on the controller.
+	BastionRecordSessions = "bastion-record-sessions"
+
+	// DatabaseBackend is the database backend that holds the
+	// controller's settings, status and annotations. It can only be
+	// chosen at bootstrap.
+	DatabaseBackend = "database-backend"
+
+	// MongoDatabaseBackend keeps all of the controller's data in
+	// MongoDB.
+	MongoDatabaseBackend = "mongodb"
+
+	// AgentBinaryTrustedKeys holds the armored OpenPGP public keys
+	// that agent binaries fetched from simplestreams must be signed
+	// with. Signatures are not checked when no keys are configured.
+	AgentBinaryTrustedKeys = "agent-binary-trusted-keys"
+
+	// OTLPEndpoint is the base URL of an OpenTelemetry collector to
+	// which the controller exports traces, using OTLP over HTTP, eg
+	// "https://otel.example.com:4318". Traces are not exported when
+	// no endpoint is configured.
+	OTLPEndpoint = "otlp-endpoint"
+
+	// OTLPHeaders holds the HTTP headers sent with each export to the
+	// OTLP endpoint, as comma-separated key=value pairs, eg
+	// "authorization=Bearer xyz".
+	OTLPHeaders = "otlp-headers"
+
+	// OTLPSamplingRatio is the fraction of spans that are exported,
+	// between 0 and 1.
+	OTLPSamplingRatio = "otlp-sampling-ratio"
+
+	// APISessionIdleTimeout is how long a user's API session may go
+	// without making a call before the controller closes it, eg "8h".
+	// Sessions are not closed for being idle when no timeout is
+	// configured.
+	APISessionIdleTimeout = "api-session-idle-timeout"
+
+	// LoginLockoutMaxFailures is the number of consecutive failed
+	// logins a user may make from an address before further logins by
+	// the user from that address are refused for LoginLockoutDuration.
+	// Users are never locked out if it is zero, the default. Each API
+	// server counts the failures it sees separately.
+	LoginLockoutMaxFailures = "login-lockout-max-failures"
+
+	// LoginLockoutMaxAddressFailures is the number of failed logins,
+	// by any users, that may be made from an address before all
+	// further logins from that address are refused for
+	// LoginLockoutDuration. Addresses are never locked out if it is
+	// zero, the default. Each API server counts the failures it sees
+	// separately.
+	LoginLockoutMaxAddressFailures = "login-lockout-max-address-failures"
+
+	// LoginLockoutDuration is how long a user is locked out after too
+	// many failed logins from an address, eg "15m". Failed logins are
+	// forgotten once this long has passed without another failure.
+	LoginLockoutDuration = "login-lockout-duration"
+
+	// ControllerAdminCIDRs is a comma-separated list of the address
+	// blocks, in CIDR notation, from which the controller's API and SSH
+	// ports may be reached, eg "10.0.0.0/8,192.0.2.1/32". The ports may
+	// be reached from anywhere when no blocks are configured.
+	ControllerAdminCIDRs = "controller-admin-cidrs"
+
+	// ChatWebhookURLs is a comma-separated list of the chat webhook
+	// URLs to which model summaries may be pushed. The controller
+	// makes those requests itself, so only an administrator may
+	// choose their destinations. Summaries cannot be pushed when no
+	// URLs are configured.
+	ChatWebhookURLs = "chat-webhook-urls"
+
 	// Attribute Defaults
 
 	// DefaultAuditingEnabled contains the default value for the
@@ -98,6 +189,9 @@ const (
 	// DefaultAPIPort is the default port the API server is listening on.
 	DefaultAPIPort int = 17070
 
+	// DefaultDatabaseBackend is the default value for DatabaseBackend.
+	DefaultDatabaseBackend = MongoDatabaseBackend
+
 	// DefaultMongoMemoryProfile is the default profile used by mongo.
 	DefaultMongoMemoryProfile = MongoProfLow
 
@@ -110,6 +204,37 @@ const (
 
 	// DefaultMaxTxnLogCollectionMB is the maximum size the txn log collection.
 	DefaultMaxTxnLogCollectionMB = 10 // 10 MB
+
+	// DefaultLeadershipLeaseDuration is the default length of an
+	// application leadership lease.
+	DefaultLeadershipLeaseDuration = time.Minute
+
+	// DefaultLeadershipRenewalInterval is the default interval at which
+	// application leaders renew their leases.
+	DefaultLeadershipRenewalInterval = 30 * time.Second
+
+	// MinLeadershipLeaseDuration and MaxLeadershipLeaseDuration bound
+	// the values accepted for LeadershipLeaseDuration.
+	MinLeadershipLeaseDuration = 5 * time.Second
+	MaxLeadershipLeaseDuration = 5 * time.Minute
+
+	// DefaultOTLPSamplingRatio is the default fraction of spans that
+	// are exported to the OTLP endpoint.
+	DefaultOTLPSamplingRatio = 1.0
+
+	// DefaultLoginLockoutMaxFailures is the default number of failed
+	// logins after which a user is locked out; users are not locked
+	// out by default.
+	DefaultLoginLockoutMaxFailures = 0
+
+	// DefaultLoginLockoutMaxAddressFailures is the default number of
+	// failed logins after which an address is locked out; addresses
+	// are not locked out by default.
+	DefaultLoginLockoutMaxAddressFailures = 0
+
+	// DefaultLoginLockoutDuration is the default length of a login
+	// lockout.
+	DefaultLoginLockoutDuration = 15 * time.Minute
 )
 
 // ControllerOnlyConfigAttributes are attributes which are only relevant
@@ -129,6 +254,21 @@ var ControllerOnlyConfigAttributes = []string{
 	MaxLogsSize,
 	MaxLogsAge,
 	MaxTxnLogSize,
+	LeadershipLeaseDuration,
+	LeadershipRenewalInterval,
+	BastionPort,
+	BastionRecordSessions,
+	DatabaseBackend,
+	AgentBinaryTrustedKeys,
+	OTLPEndpoint,
+	OTLPHeaders,
+	OTLPSamplingRatio,
+	APISessionIdleTimeout,
+	LoginLockoutMaxFailures,
+	LoginLockoutMaxAddressFailures,
+	LoginLockoutDuration,
+	ControllerAdminCIDRs,
+	ChatWebhookURLs,
 }
 
 // ControllerOnlyAttribute returns true if the specified attribute name
@@ -313,6 +453,178 @@ func (c Config) MaxTxnLogSizeMB() int {
 	return int(val)
 }
 
+// LeadershipLeaseDuration is the length of time for which an application
+// leadership claim is held before it must be renewed.
+func (c Config) LeadershipLeaseDuration() time.Duration {
+	// Value has already been validated.
+	val, _ := time.ParseDuration(c.asString(LeadershipLeaseDuration))
+	if val == 0 {
+		return DefaultLeadershipLeaseDuration
+	}
+	return val
+}
+
+// LeadershipRenewalInterval is how often an application leader renews
+// its leadership lease.
+func (c Config) LeadershipRenewalInterval() time.Duration {
+	// Value has already been validated.
+	val, _ := time.ParseDuration(c.asString(LeadershipRenewalInterval))
+	if val == 0 {
+		return DefaultLeadershipRenewalInterval
+	}
+	return val
+}
+
+// BastionPort returns the port on which the controller's ssh bastion
+// listens, or zero if the bastion is disabled.
+func (c Config) BastionPort() int {
+	// Values obtained over the api are encoded as float64.
+	if value, ok := c[BastionPort].(float64); ok {
+		return int(value)
+	}
+	value, _ := c[BastionPort].(int)
+	return value
+}
+
+// BastionRecordSessions reports whether the output of ssh sessions made
+// through the bastion should be recorded.
+func (c Config) BastionRecordSessions() bool {
+	value, _ := c[BastionRecordSessions].(bool)
+	return value
+}
+
+// DatabaseBackend returns the database backend that holds the
+// controller's settings, status and annotations.
+func (c Config) DatabaseBackend() string {
+	if value, ok := c[DatabaseBackend].(string); ok && value != "" {
+		return value
+	}
+	return DefaultDatabaseBackend
+}
+
+// AgentBinaryTrustedKeys returns the armored OpenPGP public keys that
+// agent binaries must be signed with, or the empty string if signatures
+// are not checked.
+func (c Config) AgentBinaryTrustedKeys() string {
+	return c.asString(AgentBinaryTrustedKeys)
+}
+
+// OTLPEndpoint returns the base URL of the OpenTelemetry collector to
+// which traces are exported, or the empty string if traces are not
+// exported.
+func (c Config) OTLPEndpoint() string {
+	return c.asString(OTLPEndpoint)
+}
+
+// OTLPHeaders returns the HTTP headers to send with each export to the
+// OTLP endpoint.
+func (c Config) OTLPHeaders() map[string]string {
+	headers, _ := parseOTLPHeaders(c.asString(OTLPHeaders))
+	return headers
+}
+
+// OTLPSamplingRatio returns the fraction of spans that are exported to
+// the OTLP endpoint.
+func (c Config) OTLPSamplingRatio() float64 {
+	switch value := c[OTLPSamplingRatio].(type) {
+	case float64:
+		return value
+	case int:
+		return float64(value)
+	}
+	return DefaultOTLPSamplingRatio
+}
+
+// APISessionIdleTimeout returns how long a user's API session may go
+// without making a call before it is closed, or zero if sessions are
+// not closed for being idle.
+func (c Config) APISessionIdleTimeout() time.Duration {
+	// Value has already been validated.
+	val, _ := time.ParseDuration(c.asString(APISessionIdleTimeout))
+	return val
+}
+
+// LoginLockoutMaxFailures returns the number of consecutive failed
+// logins after which a user is locked out, or zero if users are never
+// locked out.
+func (c Config) LoginLockoutMaxFailures() int {
+	// Values obtained over the api are encoded as float64.
+	switch value := c[LoginLockoutMaxFailures].(type) {
+	case float64:
+		return int(value)
+	case int:
+		return value
+	}
+	return DefaultLoginLockoutMaxFailures
+}
+
+// LoginLockoutMaxAddressFailures returns the number of failed logins,
+// by any users, after which an address is locked out, or zero if
+// addresses are never locked out.
+func (c Config) LoginLockoutMaxAddressFailures() int {
+	// Values obtained over the api are encoded as float64.
+	switch value := c[LoginLockoutMaxAddressFailures].(type) {
+	case float64:
+		return int(value)
+	case int:
+		return value
+	}
+	return DefaultLoginLockoutMaxAddressFailures
+}
+
+// LoginLockoutDuration returns how long a user is locked out after too
+// many failed logins.
+func (c Config) LoginLockoutDuration() time.Duration {
+	// Value has already been validated.
+	val, _ := time.ParseDuration(c.asString(LoginLockoutDuration))
+	if val == 0 {
+		return DefaultLoginLockoutDuration
+	}
+	return val
+}
+
+// ControllerAdminCIDRs returns the address blocks from which the
+// controller's API and SSH ports may be reached, or nil if they may be
+// reached from anywhere.
+func (c Config) ControllerAdminCIDRs() []string {
+	var cidrs []string
+	for _, cidr := range strings.Split(c.asString(ControllerAdminCIDRs), ",") {
+		if cidr = strings.TrimSpace(cidr); cidr != "" {
+			cidrs = append(cidrs, cidr)
+		}
+	}
+	return cidrs
+}
+
+// ChatWebhookURLs returns the chat webhook URLs to which model
+// summaries may be pushed.
+func (c Config) ChatWebhookURLs() []string {
+	var urls []string
+	for _, u := range strings.Split(c.asString(ChatWebhookURLs), ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// parseOTLPHeaders parses comma-separated key=value pairs.
+func parseOTLPHeaders(value string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || key == "" {
+			return nil, errors.NotValidf("header %q", pair)
+		}
+		headers[key] = strings.TrimSpace(parts[1])
+	}
+	return headers, nil
+}
+
 // Validate ensures that config is a valid configuration.
 func Validate(c Config) error {
 	if v, ok := c[IdentityPublicKey].(string); ok {
@@ -372,6 +684,124 @@ func Validate(c Config) error {
 		}
 	}
 
+	if v, ok := c[LeadershipLeaseDuration].(string); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return errors.Annotate(err, "invalid leadership lease duration in configuration")
+		}
+		if d < MinLeadershipLeaseDuration || d > MaxLeadershipLeaseDuration {
+			return errors.Errorf("%s: %v must be between %v and %v",
+				LeadershipLeaseDuration, d, MinLeadershipLeaseDuration, MaxLeadershipLeaseDuration)
+		}
+	}
+
+	if v, ok := c[LeadershipRenewalInterval].(string); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return errors.Annotate(err, "invalid leadership renewal interval in configuration")
+		}
+		if d <= 0 {
+			return errors.Errorf("%s: %v must be positive", LeadershipRenewalInterval, d)
+		}
+		if lease := c.LeadershipLeaseDuration(); d >= lease {
+			return errors.Errorf("%s: %v must be less than %s %v",
+				LeadershipRenewalInterval, d, LeadershipLeaseDuration, lease)
+		}
+	}
+
+	if _, ok := c[BastionPort]; ok {
+		port := c.BastionPort()
+		if port < 0 || port > 65535 {
+			return errors.Errorf("%s: %d out of range", BastionPort, port)
+		}
+		for _, key := range []string{APIPort, StatePort} {
+			if _, ok := c[key]; ok && port != 0 && port == c.mustInt(key) {
+				return errors.Errorf("%s: %d already used for %s", BastionPort, port, key)
+			}
+		}
+	}
+
+	if v, ok := c[DatabaseBackend].(string); ok && v != MongoDatabaseBackend {
+		return errors.NotSupportedf("%s %q", DatabaseBackend, v)
+	}
+
+	if v, ok := c[AgentBinaryTrustedKeys].(string); ok && v != "" {
+		if _, err := openpgp.ReadArmoredKeyRing(strings.NewReader(v)); err != nil {
+			return errors.Annotatef(err, "invalid %s", AgentBinaryTrustedKeys)
+		}
+	}
+
+	if v, ok := c[OTLPEndpoint].(string); ok && v != "" {
+		u, err := url.Parse(v)
+		if err != nil {
+			return errors.Annotatef(err, "invalid %s", OTLPEndpoint)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return errors.Errorf("%s: expected http or https URL, got %q", OTLPEndpoint, v)
+		}
+	}
+
+	if v, ok := c[OTLPHeaders].(string); ok {
+		if _, err := parseOTLPHeaders(v); err != nil {
+			return errors.Annotatef(err, "invalid %s", OTLPHeaders)
+		}
+	}
+
+	if _, ok := c[OTLPSamplingRatio]; ok {
+		ratio := c.OTLPSamplingRatio()
+		if ratio < 0 || ratio > 1 {
+			return errors.Errorf("%s: %v must be between 0 and 1", OTLPSamplingRatio, ratio)
+		}
+	}
+
+	if v, ok := c[APISessionIdleTimeout].(string); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return errors.Annotatef(err, "invalid %s", APISessionIdleTimeout)
+		}
+		if d < 0 {
+			return errors.Errorf("%s: %v must not be negative", APISessionIdleTimeout, d)
+		}
+	}
+
+	if _, ok := c[LoginLockoutMaxFailures]; ok {
+		if n := c.LoginLockoutMaxFailures(); n < 0 {
+			return errors.Errorf("%s: %d must not be negative", LoginLockoutMaxFailures, n)
+		}
+	}
+
+	if _, ok := c[LoginLockoutMaxAddressFailures]; ok {
+		if n := c.LoginLockoutMaxAddressFailures(); n < 0 {
+			return errors.Errorf("%s: %d must not be negative", LoginLockoutMaxAddressFailures, n)
+		}
+	}
+
+	if v, ok := c[LoginLockoutDuration].(string); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return errors.Annotatef(err, "invalid %s", LoginLockoutDuration)
+		}
+		if d <= 0 {
+			return errors.Errorf("%s: %v must be positive", LoginLockoutDuration, d)
+		}
+	}
+
+	for _, cidr := range c.ControllerAdminCIDRs() {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.Annotatef(err, "invalid %s", ControllerAdminCIDRs)
+		}
+	}
+
+	for _, v := range c.ChatWebhookURLs() {
+		u, err := url.Parse(v)
+		if err != nil {
+			return errors.Annotatef(err, "invalid %s", ChatWebhookURLs)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.Errorf("%s: expected http or https URL, got %q", ChatWebhookURLs, v)
+		}
+	}
+
 	return nil
 }
 
@@ -382,31 +812,61 @@ func GenerateControllerCertAndKey(caCert, caKey string, hostAddresses []string)
 }
 
 var configChecker = schema.FieldMap(schema.Fields{
-	AuditingEnabled:         schema.Bool(),
-	APIPort:                 schema.ForceInt(),
-	StatePort:               schema.ForceInt(),
-	IdentityURL:             schema.String(),
-	IdentityPublicKey:       schema.String(),
-	SetNUMAControlPolicyKey: schema.Bool(),
-	AutocertURLKey:          schema.String(),
-	AutocertDNSNameKey:      schema.String(),
-	AllowModelAccessKey:     schema.Bool(),
-	MongoMemoryProfile:      schema.String(),
-	MaxLogsAge:              schema.String(),
-	MaxLogsSize:             schema.String(),
-	MaxTxnLogSize:           schema.String(),
+	AuditingEnabled:                schema.Bool(),
+	APIPort:                        schema.ForceInt(),
+	StatePort:                      schema.ForceInt(),
+	IdentityURL:                    schema.String(),
+	IdentityPublicKey:              schema.String(),
+	SetNUMAControlPolicyKey:        schema.Bool(),
+	AutocertURLKey:                 schema.String(),
+	AutocertDNSNameKey:             schema.String(),
+	AllowModelAccessKey:            schema.Bool(),
+	MongoMemoryProfile:             schema.String(),
+	MaxLogsAge:                     schema.String(),
+	MaxLogsSize:                    schema.String(),
+	MaxTxnLogSize:                  schema.String(),
+	LeadershipLeaseDuration:        schema.String(),
+	LeadershipRenewalInterval:      schema.String(),
+	BastionPort:                    schema.ForceInt(),
+	BastionRecordSessions:          schema.Bool(),
+	DatabaseBackend:                schema.String(),
+	AgentBinaryTrustedKeys:         schema.String(),
+	OTLPEndpoint:                   schema.String(),
+	OTLPHeaders:                    schema.String(),
+	OTLPSamplingRatio:              schema.Float(),
+	APISessionIdleTimeout:          schema.String(),
+	LoginLockoutMaxFailures:        schema.ForceInt(),
+	LoginLockoutMaxAddressFailures: schema.ForceInt(),
+	LoginLockoutDuration:           schema.String(),
+	ControllerAdminCIDRs:           schema.String(),
+	ChatWebhookURLs:                schema.String(),
 }, schema.Defaults{
-	APIPort:                 DefaultAPIPort,
-	AuditingEnabled:         DefaultAuditingEnabled,
-	StatePort:               DefaultStatePort,
-	IdentityURL:             schema.Omit,
-	IdentityPublicKey:       schema.Omit,
-	SetNUMAControlPolicyKey: DefaultNUMAControlPolicy,
-	AutocertURLKey:          schema.Omit,
-	AutocertDNSNameKey:      schema.Omit,
-	AllowModelAccessKey:     schema.Omit,
-	MongoMemoryProfile:      schema.Omit,
-	MaxLogsAge:              fmt.Sprintf("%vh", DefaultMaxLogsAgeDays*24),
-	MaxLogsSize:             fmt.Sprintf("%vM", DefaultMaxLogCollectionMB),
-	MaxTxnLogSize:           fmt.Sprintf("%vM", DefaultMaxTxnLogCollectionMB),
+	APIPort:                        DefaultAPIPort,
+	AuditingEnabled:                DefaultAuditingEnabled,
+	StatePort:                      DefaultStatePort,
+	IdentityURL:                    schema.Omit,
+	IdentityPublicKey:              schema.Omit,
+	SetNUMAControlPolicyKey:        DefaultNUMAControlPolicy,
+	AutocertURLKey:                 schema.Omit,
+	AutocertDNSNameKey:             schema.Omit,
+	AllowModelAccessKey:            schema.Omit,
+	MongoMemoryProfile:             schema.Omit,
+	MaxLogsAge:                     fmt.Sprintf("%vh", DefaultMaxLogsAgeDays*24),
+	MaxLogsSize:                    fmt.Sprintf("%vM", DefaultMaxLogCollectionMB),
+	MaxTxnLogSize:                  fmt.Sprintf("%vM", DefaultMaxTxnLogCollectionMB),
+	LeadershipLeaseDuration:        schema.Omit,
+	LeadershipRenewalInterval:      schema.Omit,
+	BastionPort:                    schema.Omit,
+	BastionRecordSessions:          schema.Omit,
+	DatabaseBackend:                schema.Omit,
+	AgentBinaryTrustedKeys:         schema.Omit,
+	OTLPEndpoint:                   schema.Omit,
+	OTLPHeaders:                    schema.Omit,
+	OTLPSamplingRatio:              schema.Omit,
+	APISessionIdleTimeout:          schema.Omit,
+	LoginLockoutMaxFailures:        schema.Omit,
+	LoginLockoutMaxAddressFailures: schema.Omit,
+	LoginLockoutDuration:           schema.Omit,
+	ControllerAdminCIDRs:           schema.Omit,
+	ChatWebhookURLs:                schema.Omit,
 })
@@ -15,6 +15,7 @@ import (
 
 	"github.com/juju/juju/cert"
 	"github.com/juju/juju/controller"
+	sstesting "github.com/juju/juju/environs/simplestreams/testing"
 	"github.com/juju/juju/testing"
 )
 
@@ -121,6 +122,134 @@ var validateTests = []struct {
 		controller.CACertKey:         testing.CACert,
 	},
 	expectError: `invalid identity public key: wrong length for base64 key, got 3 want 32`,
+}, {
+	about: "invalid leadership lease duration",
+	config: controller.Config{
+		controller.CACertKey:               testing.CACert,
+		controller.LeadershipLeaseDuration: "xxx",
+	},
+	expectError: `invalid leadership lease duration in configuration: time: invalid duration "?xxx"?`,
+}, {
+	about: "leadership lease duration too short",
+	config: controller.Config{
+		controller.CACertKey:               testing.CACert,
+		controller.LeadershipLeaseDuration: "1s",
+	},
+	expectError: `leadership-lease-duration: 1s must be between 5s and 5m0s`,
+}, {
+	about: "leadership renewal interval not less than lease duration",
+	config: controller.Config{
+		controller.CACertKey:                 testing.CACert,
+		controller.LeadershipLeaseDuration:   "30s",
+		controller.LeadershipRenewalInterval: "30s",
+	},
+	expectError: `leadership-renewal-interval: 30s must be less than leadership-lease-duration 30s`,
+}, {
+	about: "leadership renewal interval checked against default lease duration",
+	config: controller.Config{
+		controller.CACertKey:                 testing.CACert,
+		controller.LeadershipRenewalInterval: "2m",
+	},
+	expectError: `leadership-renewal-interval: 2m0s must be less than leadership-lease-duration 1m0s`,
+}, {
+	about: "bastion port out of range",
+	config: controller.Config{
+		controller.CACertKey:   testing.CACert,
+		controller.BastionPort: 70000,
+	},
+	expectError: `bastion-port: 70000 out of range`,
+}, {
+	about: "bastion port clashes with api port",
+	config: controller.Config{
+		controller.CACertKey:   testing.CACert,
+		controller.APIPort:     17070,
+		controller.BastionPort: 17070,
+	},
+	expectError: `bastion-port: 17070 already used for api-port`,
+}, {
+	about: "unsupported database backend",
+	config: controller.Config{
+		controller.CACertKey:       testing.CACert,
+		controller.DatabaseBackend: "bbolt",
+	},
+	expectError: `database-backend "bbolt" not supported`,
+}, {
+	about: "invalid agent binary trusted keys",
+	config: controller.Config{
+		controller.CACertKey:              testing.CACert,
+		controller.AgentBinaryTrustedKeys: "not a key",
+	},
+	expectError: `invalid agent-binary-trusted-keys: .*`,
+}, {
+	about: "OTLP endpoint not HTTP",
+	config: controller.Config{
+		controller.CACertKey:    testing.CACert,
+		controller.OTLPEndpoint: "grpc://otel.example.com:4317",
+	},
+	expectError: `otlp-endpoint: expected http or https URL, got "grpc://otel.example.com:4317"`,
+}, {
+	about: "invalid OTLP headers",
+	config: controller.Config{
+		controller.CACertKey:   testing.CACert,
+		controller.OTLPHeaders: "authorization",
+	},
+	expectError: `invalid otlp-headers: header "authorization" not valid`,
+}, {
+	about: "OTLP sampling ratio out of range",
+	config: controller.Config{
+		controller.CACertKey:         testing.CACert,
+		controller.OTLPSamplingRatio: 1.5,
+	},
+	expectError: `otlp-sampling-ratio: 1.5 must be between 0 and 1`,
+}, {
+	about: "invalid API session idle timeout",
+	config: controller.Config{
+		controller.CACertKey:             testing.CACert,
+		controller.APISessionIdleTimeout: "soon",
+	},
+	expectError: `invalid api-session-idle-timeout: time: invalid duration soon`,
+}, {
+	about: "negative API session idle timeout",
+	config: controller.Config{
+		controller.CACertKey:             testing.CACert,
+		controller.APISessionIdleTimeout: "-1h",
+	},
+	expectError: `api-session-idle-timeout: -1h0m0s must not be negative`,
+}, {
+	about: "negative login lockout max failures",
+	config: controller.Config{
+		controller.CACertKey:               testing.CACert,
+		controller.LoginLockoutMaxFailures: -1,
+	},
+	expectError: `login-lockout-max-failures: -1 must not be negative`,
+}, {
+	about: "negative login lockout max address failures",
+	config: controller.Config{
+		controller.CACertKey:                      testing.CACert,
+		controller.LoginLockoutMaxAddressFailures: -1,
+	},
+	expectError: `login-lockout-max-address-failures: -1 must not be negative`,
+}, {
+	about: "zero login lockout duration",
+	config: controller.Config{
+		controller.CACertKey:            testing.CACert,
+		controller.LoginLockoutDuration: "0s",
+	},
+	expectError: `login-lockout-duration: 0s must be positive`,
+}, {
+	about: "invalid controller admin CIDR",
+	config: controller.Config{
+		controller.CACertKey:            testing.CACert,
+		controller.ControllerAdminCIDRs: "10.0.0.0/8,10.0.0.1",
+	},
+	expectError: `invalid controller-admin-cidrs: invalid CIDR address: 10.0.0.1`,
+}, {
+	about: "invalid chat webhook URL",
+	config: controller.Config{
+		controller.CACertKey:       testing.CACert,
+		controller.ChatWebhookURLs: "https://chat.example.com/hook,file:///etc/passwd",
+	},
+	expectError: `chat-webhook-urls: expected http or https URL, got "file:///etc/passwd"`,
 }}
 
 func (s *ConfigSuite) TestValidate(c *gc.C) {
@@ -173,3 +302,176 @@ func (s *ConfigSuite) TestTxnLogConfigValue(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(cfg.MaxTxnLogSizeMB(), gc.Equals, 8192)
 }
+
+func (s *ConfigSuite) TestLeadershipConfigDefaults(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.LeadershipLeaseDuration(), gc.Equals, time.Minute)
+	c.Assert(cfg.LeadershipRenewalInterval(), gc.Equals, 30*time.Second)
+}
+
+func (s *ConfigSuite) TestLeadershipConfigValues(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"leadership-lease-duration":   "2m",
+			"leadership-renewal-interval": "45s",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.LeadershipLeaseDuration(), gc.Equals, 2*time.Minute)
+	c.Assert(cfg.LeadershipRenewalInterval(), gc.Equals, 45*time.Second)
+}
+
+func (s *ConfigSuite) TestBastionConfigDefaults(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.BastionPort(), gc.Equals, 0)
+	c.Assert(cfg.BastionRecordSessions(), jc.IsFalse)
+}
+
+func (s *ConfigSuite) TestBastionConfigValues(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"bastion-port":            "17022",
+			"bastion-record-sessions": true,
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.BastionPort(), gc.Equals, 17022)
+	c.Assert(cfg.BastionRecordSessions(), jc.IsTrue)
+}
+
+func (s *ConfigSuite) TestDatabaseBackend(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.DatabaseBackend(), gc.Equals, controller.MongoDatabaseBackend)
+
+	cfg, err = controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"database-backend": "mongodb",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.DatabaseBackend(), gc.Equals, controller.MongoDatabaseBackend)
+}
+
+func (s *ConfigSuite) TestAgentBinaryTrustedKeys(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AgentBinaryTrustedKeys(), gc.Equals, "")
+
+	cfg, err = controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"agent-binary-trusted-keys": sstesting.SignedMetadataPublicKey,
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AgentBinaryTrustedKeys(), gc.Equals, sstesting.SignedMetadataPublicKey)
+}
+
+func (s *ConfigSuite) TestOTLPConfigDefaults(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.OTLPEndpoint(), gc.Equals, "")
+	c.Assert(cfg.OTLPHeaders(), gc.HasLen, 0)
+	c.Assert(cfg.OTLPSamplingRatio(), gc.Equals, 1.0)
+}
+
+func (s *ConfigSuite) TestOTLPConfigValues(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"otlp-endpoint":       "https://otel.example.com:4318",
+			"otlp-headers":        "authorization=Bearer xyz, x-tenant=juju",
+			"otlp-sampling-ratio": 0.25,
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.OTLPEndpoint(), gc.Equals, "https://otel.example.com:4318")
+	c.Assert(cfg.OTLPHeaders(), jc.DeepEquals, map[string]string{
+		"authorization": "Bearer xyz",
+		"x-tenant":      "juju",
+	})
+	c.Assert(cfg.OTLPSamplingRatio(), gc.Equals, 0.25)
+}
+
+func (s *ConfigSuite) TestAPISessionIdleTimeout(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.APISessionIdleTimeout(), gc.Equals, time.Duration(0))
+
+	cfg, err = controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{"api-session-idle-timeout": "8h"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.APISessionIdleTimeout(), gc.Equals, 8*time.Hour)
+}
+
+func (s *ConfigSuite) TestLoginLockout(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.LoginLockoutMaxFailures(), gc.Equals, 0)
+	c.Assert(cfg.LoginLockoutMaxAddressFailures(), gc.Equals, 0)
+	c.Assert(cfg.LoginLockoutDuration(), gc.Equals, controller.DefaultLoginLockoutDuration)
+
+	cfg, err = controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"login-lockout-max-failures":         5,
+			"login-lockout-max-address-failures": 50,
+			"login-lockout-duration":             "1h",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.LoginLockoutMaxFailures(), gc.Equals, 5)
+	c.Assert(cfg.LoginLockoutMaxAddressFailures(), gc.Equals, 50)
+	c.Assert(cfg.LoginLockoutDuration(), gc.Equals, time.Hour)
+}
+
+func (s *ConfigSuite) TestControllerAdminCIDRs(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.ControllerAdminCIDRs(), gc.IsNil)
+
+	cfg, err = controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{"controller-admin-cidrs": "10.0.0.0/8, 192.0.2.1/32"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.ControllerAdminCIDRs(), jc.DeepEquals, []string{"10.0.0.0/8", "192.0.2.1/32"})
+}
+
+func (s *ConfigSuite) TestChatWebhookURLs(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.ChatWebhookURLs(), gc.IsNil)
+
+	cfg, err = controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{"chat-webhook-urls": "https://chat.example.com/a, https://chat.example.com/b"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.ChatWebhookURLs(), jc.DeepEquals, []string{"https://chat.example.com/a", "https://chat.example.com/b"})
+}
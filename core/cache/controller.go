@@ -0,0 +1,106 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package cache holds an in-memory copy of the models of a controller
+// and their annotations, kept up to date from a watcher, so that
+// annotation reads can be answered without querying the database.
+package cache
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+// Controller holds the cached models of a controller.
+type Controller struct {
+	mu     sync.Mutex
+	models map[string]*Model
+}
+
+// NewController returns an empty model cache.
+func NewController() *Controller {
+	return &Controller{
+		models: make(map[string]*Model),
+	}
+}
+
+// Model returns the cached model with the specified UUID. It returns a
+// NotFound error if the model is not (yet) in the cache.
+func (c *Controller) Model(uuid string) (*Model, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	model, found := c.models[uuid]
+	if !found || !model.hasInfo() {
+		return nil, errors.NotFoundf("model %q", uuid)
+	}
+	return model, nil
+}
+
+// ModelUUIDs returns the UUIDs of the cached models, in sorted order.
+func (c *Controller) ModelUUIDs() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	uuids := make([]string, 0, len(c.models))
+	for uuid, model := range c.models {
+		if model.hasInfo() {
+			uuids = append(uuids, uuid)
+		}
+	}
+	sort.Strings(uuids)
+	return uuids
+}
+
+// Update applies the deltas, as returned by the model annotations
+// watcher, to the cache. Deltas for kinds of entity that are not cached are
+// ignored.
+func (c *Controller) Update(deltas []multiwatcher.Delta) {
+	for _, delta := range deltas {
+		switch info := delta.Entity.(type) {
+		case *multiwatcher.ModelInfo:
+			if delta.Removed {
+				c.removeModel(info.ModelUUID)
+				continue
+			}
+			c.ensureModel(info.ModelUUID).setInfo(info)
+		case *multiwatcher.AnnotationInfo:
+			if delta.Removed {
+				if model := c.existingModel(info.ModelUUID); model != nil {
+					model.removeAnnotations(info.Tag)
+				}
+				continue
+			}
+			c.ensureModel(info.ModelUUID).setAnnotations(info.Tag, info.Annotations)
+		}
+	}
+}
+
+// ensureModel returns the cached model with the specified UUID,
+// adding it if necessary. Entities of a model may be reported before
+// the model itself; the model is not returned by Model until its info
+// has been seen.
+func (c *Controller) ensureModel(uuid string) *Model {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	model, found := c.models[uuid]
+	if !found {
+		model = newModel()
+		c.models[uuid] = model
+	}
+	return model
+}
+
+func (c *Controller) existingModel(uuid string) *Model {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.models[uuid]
+}
+
+func (c *Controller) removeModel(uuid string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.models, uuid)
+}
@@ -0,0 +1,106 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cache_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/cache"
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+type controllerSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&controllerSuite{})
+
+var modelInfo = &multiwatcher.ModelInfo{
+	ModelUUID: "model-uuid",
+	Name:      "test-model",
+	Life:      multiwatcher.Life("alive"),
+}
+
+func (s *controllerSuite) TestModelNotFound(c *gc.C) {
+	controller := cache.NewController()
+	_, err := controller.Model("model-uuid")
+	c.Assert(err, gc.ErrorMatches, `model "model-uuid" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *controllerSuite) TestUpdateModel(c *gc.C) {
+	controller := cache.NewController()
+	controller.Update([]multiwatcher.Delta{{Entity: modelInfo}})
+
+	c.Assert(controller.ModelUUIDs(), jc.DeepEquals, []string{"model-uuid"})
+	model, err := controller.Model("model-uuid")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(model.Name(), gc.Equals, "test-model")
+	c.Check(model.Life(), gc.Equals, multiwatcher.Life("alive"))
+}
+
+func (s *controllerSuite) TestRemoveModel(c *gc.C) {
+	controller := cache.NewController()
+	controller.Update([]multiwatcher.Delta{{Entity: modelInfo}})
+	controller.Update([]multiwatcher.Delta{{Removed: true, Entity: modelInfo}})
+
+	_, err := controller.Model("model-uuid")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(controller.ModelUUIDs(), gc.HasLen, 0)
+}
+
+func (s *controllerSuite) TestAnnotationsBeforeModel(c *gc.C) {
+	controller := cache.NewController()
+	controller.Update([]multiwatcher.Delta{{
+		Entity: &multiwatcher.AnnotationInfo{
+			ModelUUID:   "model-uuid",
+			Tag:         "machine-0",
+			Annotations: map[string]string{"foo": "bar"},
+		},
+	}})
+	_, err := controller.Model("model-uuid")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	controller.Update([]multiwatcher.Delta{{Entity: modelInfo}})
+	model, err := controller.Model("model-uuid")
+	c.Assert(err, jc.ErrorIsNil)
+	annotations, found := model.Annotations("machine-0")
+	c.Assert(found, jc.IsTrue)
+	c.Assert(annotations, jc.DeepEquals, map[string]string{"foo": "bar"})
+}
+
+func (s *controllerSuite) TestRemoveAnnotations(c *gc.C) {
+	info := &multiwatcher.AnnotationInfo{
+		ModelUUID:   "model-uuid",
+		Tag:         "machine-0",
+		Annotations: map[string]string{"foo": "bar"},
+	}
+	controller := cache.NewController()
+	controller.Update([]multiwatcher.Delta{{Entity: modelInfo}, {Entity: info}})
+	controller.Update([]multiwatcher.Delta{{Removed: true, Entity: info}})
+
+	model, err := controller.Model("model-uuid")
+	c.Assert(err, jc.ErrorIsNil)
+	_, found := model.Annotations("machine-0")
+	c.Assert(found, jc.IsFalse)
+}
+
+func (s *controllerSuite) TestSetAnnotations(c *gc.C) {
+	controller := cache.NewController()
+	controller.Update([]multiwatcher.Delta{{Entity: modelInfo}})
+	model, err := controller.Model("model-uuid")
+	c.Assert(err, jc.ErrorIsNil)
+
+	model.SetAnnotations("machine-0", map[string]string{"foo": "bar"})
+	annotations, found := model.Annotations("machine-0")
+	c.Assert(found, jc.IsTrue)
+	c.Assert(annotations, jc.DeepEquals, map[string]string{"foo": "bar"})
+
+	model.SetAnnotations("machine-0", nil)
+	_, found = model.Annotations("machine-0")
+	c.Assert(found, jc.IsFalse)
+}
@@ -0,0 +1,88 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cache
+
+import (
+	"sync"
+
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+// Model holds the cached data of a model.
+type Model struct {
+	mu          sync.Mutex
+	info        *multiwatcher.ModelInfo
+	annotations map[string]map[string]string
+}
+
+func newModel() *Model {
+	return &Model{
+		annotations: make(map[string]map[string]string),
+	}
+}
+
+// Name returns the name of the model.
+func (m *Model) Name() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.info.Name
+}
+
+// Life returns the life of the model.
+func (m *Model) Life() multiwatcher.Life {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.info.Life
+}
+
+// Annotations returns a copy of the annotations of the entity with the
+// specified tag, and whether the entity has any annotations cached.
+func (m *Model) Annotations(tag string) (map[string]string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	annotations, found := m.annotations[tag]
+	if !found {
+		return nil, false
+	}
+	result := make(map[string]string, len(annotations))
+	for key, value := range annotations {
+		result[key] = value
+	}
+	return result, true
+}
+
+// SetAnnotations records the annotations of the entity with the
+// specified tag, as just written to the database, so that reads from
+// the cache see them without waiting for the watcher to report them.
+func (m *Model) SetAnnotations(tag string, annotations map[string]string) {
+	if len(annotations) == 0 {
+		m.removeAnnotations(tag)
+		return
+	}
+	m.setAnnotations(tag, annotations)
+}
+
+func (m *Model) hasInfo() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.info != nil
+}
+
+func (m *Model) setInfo(info *multiwatcher.ModelInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.info = info
+}
+
+func (m *Model) setAnnotations(tag string, annotations map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.annotations[tag] = annotations
+}
+
+func (m *Model) removeAnnotations(tag string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.annotations, tag)
+}
@@ -0,0 +1,63 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package correlation defines the IDs that tie together the work done
+// across the controller and agents on behalf of one operator action:
+// the API request made, the transactions it ran and the hooks that
+// resulted. The ID is included in log lines and status data, so that
+// the effects of one action can be grouped.
+package correlation
+
+import (
+	"context"
+	"unicode"
+
+	"github.com/juju/utils"
+)
+
+const (
+	// StatusDataKey is the key under which the correlation ID of the
+	// work that set a status is recorded in the status data.
+	StatusDataKey = "correlation-id"
+
+	// EnvVar is the environment variable holding the correlation ID
+	// of the work that caused a hook to run.
+	EnvVar = "JUJU_CORRELATION_ID"
+
+	// maxIDLength is the length of the longest valid correlation ID.
+	maxIDLength = 64
+)
+
+// NewID returns a new correlation ID.
+func NewID() string {
+	return utils.MustNewUUID().String()
+}
+
+type contextKey struct{}
+
+// WithID returns a context carrying the correlation ID.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// ID returns the correlation ID carried by the context, or "" if it
+// carries none.
+func ID(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// IsValidID reports whether id may be used as a correlation ID. IDs
+// supplied by clients are checked, as they end up in log lines and
+// hook environments.
+func IsValidID(id string) bool {
+	if id == "" || len(id) > maxIDLength {
+		return false
+	}
+	for _, r := range id {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' && r != '.' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package correlation_test
+
+import (
+	"context"
+	"strings"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/correlation"
+)
+
+type CorrelationSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&CorrelationSuite{})
+
+func (s *CorrelationSuite) TestNewID(c *gc.C) {
+	id := correlation.NewID()
+	c.Assert(correlation.IsValidID(id), jc.IsTrue)
+	c.Assert(correlation.NewID(), gc.Not(gc.Equals), id)
+}
+
+func (s *CorrelationSuite) TestContext(c *gc.C) {
+	ctx := context.Background()
+	c.Assert(correlation.ID(ctx), gc.Equals, "")
+	ctx = correlation.WithID(ctx, "deadbeef")
+	c.Assert(correlation.ID(ctx), gc.Equals, "deadbeef")
+}
+
+func (s *CorrelationSuite) TestIsValidID(c *gc.C) {
+	for _, id := range []string{"deadbeef", "deploy_1.2-3", strings.Repeat("a", 64)} {
+		c.Check(correlation.IsValidID(id), jc.IsTrue, gc.Commentf("%q", id))
+	}
+	for _, id := range []string{"", "has space", "new\nline", "$(rm)", strings.Repeat("a", 65)} {
+		c.Check(correlation.IsValidID(id), jc.IsFalse, gc.Commentf("%q", id))
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package sshkeys provides support for the per-key metadata that Juju
+// records alongside a model's authorized ssh keys: when each key
+// expires, the key server source from which it was imported, and the
+// Juju user to whom it belongs.
+package sshkeys
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+)
+
+const (
+	// ExpiryOption is the authorized_keys option that records when a
+	// key expires, in the form expiry-time="YYYYMMDD[HHMM[SS]]". Juju
+	// interprets the time as UTC.
+	ExpiryOption = "expiry-time"
+
+	// importMarker is appended to the comment of keys imported from a
+	// key server, followed by the source of the key.
+	importMarker = "# ssh-import-id "
+
+	// ownerMarker is appended to the comment of keys belonging to a
+	// particular Juju user, followed by the name of the user.
+	ownerMarker = "# juju-user "
+)
+
+var expiryLayouts = map[int]string{
+	8:  "20060102",
+	12: "200601021504",
+	14: "20060102150405",
+}
+
+// Expiry returns the time at which the supplied authorized key expires.
+// The boolean result is false if the key has no expiry.
+func Expiry(key string) (time.Time, bool, error) {
+	options, _ := splitOptions(key)
+	for _, option := range options {
+		value, ok := optionValue(option, ExpiryOption)
+		if !ok {
+			continue
+		}
+		value = strings.TrimSuffix(value, "Z")
+		layout, ok := expiryLayouts[len(value)]
+		if !ok {
+			return time.Time{}, false, errors.NotValidf("%s %q", ExpiryOption, value)
+		}
+		t, err := time.ParseInLocation(layout, value, time.UTC)
+		if err != nil {
+			return time.Time{}, false, errors.NotValidf("%s %q", ExpiryOption, value)
+		}
+		return t, true, nil
+	}
+	return time.Time{}, false, nil
+}
+
+// WithExpiry returns the supplied authorized key, set to expire at the
+// supplied time. Any existing expiry is replaced.
+func WithExpiry(key string, expiry time.Time) string {
+	options, rest := splitOptions(StripExpiry(key))
+	option := ExpiryOption + `="` + expiry.UTC().Format(expiryLayouts[12]) + `"`
+	return joinOptions(append([]string{option}, options...), rest)
+}
+
+// StripExpiry returns the supplied authorized key without its expiry.
+// Not every version of sshd understands the expiry option, so keys are
+// stripped of it before being written to a machine's authorized_keys.
+func StripExpiry(key string) string {
+	options, rest := splitOptions(key)
+	var kept []string
+	for _, option := range options {
+		if _, ok := optionValue(option, ExpiryOption); !ok {
+			kept = append(kept, option)
+		}
+	}
+	if len(kept) == len(options) {
+		return key
+	}
+	return joinOptions(kept, rest)
+}
+
+// Unexpired returns the supplied keys that have not expired at the
+// supplied time, along with the earliest time at which one of those
+// keys will expire. The time is zero if none of the keys expire. Keys
+// whose expiry cannot be parsed are treated as having expired.
+func Unexpired(keys []string, now time.Time) ([]string, time.Time) {
+	var unexpired []string
+	var next time.Time
+	for _, key := range keys {
+		expiry, ok, err := Expiry(key)
+		if err != nil || (ok && !now.Before(expiry)) {
+			continue
+		}
+		if ok && (next.IsZero() || expiry.Before(next)) {
+			next = expiry
+		}
+		unexpired = append(unexpired, key)
+	}
+	return unexpired, next
+}
+
+// MarkImported returns the supplied authorized key, with its comment
+// recording that it was imported from the supplied key server source,
+// eg "lp:someone" or "gh:someone".
+func MarkImported(key, source string) string {
+	if ImportSource(key) == source {
+		return key
+	}
+	return strings.TrimSpace(key) + " " + importMarker + source
+}
+
+// ImportSource returns the key server source from which the supplied
+// authorized key was imported, or the empty string if the key was not
+// imported.
+func ImportSource(key string) string {
+	return markerValue(key, importMarker)
+}
+
+// WithOwner returns the supplied authorized key, with its comment
+// recording that it belongs to the Juju user with the supplied name.
+// Any existing owner is replaced.
+func WithOwner(key, user string) string {
+	if Owner(key) == user {
+		return key
+	}
+	key = strings.TrimSpace(key)
+	if i := strings.LastIndex(key, ownerMarker); i >= 0 {
+		key = strings.TrimSpace(key[:i])
+	}
+	return key + " " + ownerMarker + user
+}
+
+// Owner returns the name of the Juju user to whom the supplied
+// authorized key belongs, or the empty string if the key is shared by
+// all users of the model.
+func Owner(key string) string {
+	return markerValue(key, ownerMarker)
+}
+
+// BaseComment returns the supplied key comment without any of the
+// metadata recorded in it by Juju.
+func BaseComment(comment string) string {
+	for _, marker := range []string{importMarker, ownerMarker} {
+		if i := strings.Index(comment, marker); i >= 0 {
+			comment = comment[:i]
+		}
+	}
+	return strings.TrimSpace(comment)
+}
+
+// RunSSHImportId runs ssh-import-id to fetch the keys published by
+// the supplied key server source. It is a variable so that it may be
+// replaced in tests.
+var RunSSHImportId = func(source string) (string, error) {
+	return utils.RunCommand("ssh-import-id", "-o", "-", source)
+}
+
+// Import fetches the keys published by the supplied key server
+// source, eg "lp:someone" or "gh:someone". Each key returned is marked
+// as imported from the source.
+func Import(source string) ([]string, error) {
+	output, err := RunSSHImportId(source)
+	if err != nil {
+		return nil, errors.Annotatef(err, "importing ssh keys for %s", source)
+	}
+	var keys []string
+	for _, line := range strings.Split(output, "\n") {
+		if _, rest := splitOptions(line); !isKeyType(rest) {
+			continue
+		}
+		keys = append(keys, MarkImported(line, source))
+	}
+	if len(keys) == 0 {
+		return nil, errors.NotFoundf("ssh keys for %s", source)
+	}
+	return keys, nil
+}
+
+// ValidateSource returns an error if the supplied key server source
+// is not of a form understood by ssh-import-id.
+func ValidateSource(source string) error {
+	parts := strings.SplitN(source, ":", 2)
+	if len(parts) != 2 || parts[1] == "" || (parts[0] != "lp" && parts[0] != "gh") {
+		return errors.NotValidf(`ssh key source %q (expected "lp:<user>" or "gh:<user>")`, source)
+	}
+	return nil
+}
+
+// isKeyType reports whether the supplied text starts with an ssh
+// public key type.
+func isKeyType(text string) bool {
+	return strings.HasPrefix(text, "ssh-") ||
+		strings.HasPrefix(text, "ecdsa-") ||
+		strings.HasPrefix(text, "sk-")
+}
+
+// splitOptions splits an authorized key into its leading options and
+// the remainder of the key.
+func splitOptions(key string) ([]string, string) {
+	key = strings.TrimSpace(key)
+	if key == "" || isKeyType(key) {
+		return nil, key
+	}
+	var options []string
+	var inQuote bool
+	start := 0
+	for i := 0; i < len(key); i++ {
+		switch c := key[i]; {
+		case c == '\\' && inQuote:
+			i++
+		case c == '"':
+			inQuote = !inQuote
+		case c == ',' && !inQuote:
+			options = append(options, key[start:i])
+			start = i + 1
+		case (c == ' ' || c == '\t') && !inQuote:
+			options = append(options, key[start:i])
+			return options, strings.TrimSpace(key[i:])
+		}
+	}
+	// There is nothing after the options, so the line is not a key.
+	return nil, key
+}
+
+func joinOptions(options []string, rest string) string {
+	if len(options) == 0 {
+		return rest
+	}
+	return strings.Join(options, ",") + " " + rest
+}
+
+// markerValue returns the word following the last occurrence of the
+// supplied marker in the key, or the empty string if there is none.
+// Markers are only ever appended to the end of a key, so the value must
+// be the last thing in the key or be followed by another marker.
+func markerValue(key, marker string) string {
+	i := strings.LastIndex(key, marker)
+	if i < 0 {
+		return ""
+	}
+	rest := strings.TrimSpace(key[i+len(marker):])
+	value := rest
+	if j := strings.Index(rest, " # "); j >= 0 {
+		value = rest[:j]
+	}
+	if value == "" || strings.ContainsAny(value, " \t") {
+		return ""
+	}
+	return value
+}
+
+// optionValue returns the unquoted value of the option if it has the
+// supplied name.
+func optionValue(option, name string) (string, bool) {
+	if !strings.HasPrefix(strings.ToLower(option), name+"=") {
+		return "", false
+	}
+	return strings.Trim(option[len(name)+1:], `"`), true
+}
@@ -0,0 +1,125 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sshkeys_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/sshkeys"
+)
+
+type SSHKeysSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&SSHKeysSuite{})
+
+const key = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQC user@host"
+
+func (s *SSHKeysSuite) TestExpiryNone(c *gc.C) {
+	_, ok, err := sshkeys.Expiry(key)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *SSHKeysSuite) TestExpiry(c *gc.C) {
+	for _, value := range []string{"20180301", "201803010000", "20180301000000", "20180301Z"} {
+		expiry, ok, err := sshkeys.Expiry(`no-pty,expiry-time="` + value + `" ` + key)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(ok, jc.IsTrue)
+		c.Check(expiry, gc.Equals, time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC))
+	}
+}
+
+func (s *SSHKeysSuite) TestExpiryInvalid(c *gc.C) {
+	_, _, err := sshkeys.Expiry(`expiry-time="tomorrow" ` + key)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *SSHKeysSuite) TestWithExpiry(c *gc.C) {
+	expiry := time.Date(2018, 3, 1, 12, 30, 0, 0, time.UTC)
+	withExpiry := sshkeys.WithExpiry(`no-pty,expiry-time="20170101" `+key, expiry)
+	c.Assert(withExpiry, gc.Equals, `expiry-time="201803011230",no-pty `+key)
+	got, ok, err := sshkeys.Expiry(withExpiry)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(got, gc.Equals, expiry)
+}
+
+func (s *SSHKeysSuite) TestStripExpiry(c *gc.C) {
+	c.Assert(sshkeys.StripExpiry(key), gc.Equals, key)
+	c.Assert(sshkeys.StripExpiry(`expiry-time="20180301" `+key), gc.Equals, key)
+	c.Assert(sshkeys.StripExpiry(`command="echo a, b",expiry-time="20180301" `+key), gc.Equals, `command="echo a, b" `+key)
+}
+
+func (s *SSHKeysSuite) TestUnexpired(c *gc.C) {
+	keys := []string{
+		key,
+		`expiry-time="20180101" ` + key,
+		`expiry-time="20180301" ` + key,
+		`expiry-time="20180201" ` + key,
+		`expiry-time="whenever" ` + key,
+	}
+	unexpired, next := sshkeys.Unexpired(keys, time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+	c.Assert(unexpired, jc.DeepEquals, []string{keys[0], keys[2], keys[3]})
+	c.Assert(next, gc.Equals, time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func (s *SSHKeysSuite) TestMarkImported(c *gc.C) {
+	imported := sshkeys.MarkImported(key, "lp:someone")
+	c.Assert(imported, gc.Equals, key+" # ssh-import-id lp:someone")
+	c.Assert(sshkeys.MarkImported(imported, "lp:someone"), gc.Equals, imported)
+	c.Assert(sshkeys.ImportSource(imported), gc.Equals, "lp:someone")
+	c.Assert(sshkeys.ImportSource(key), gc.Equals, "")
+}
+
+func (s *SSHKeysSuite) TestWithOwner(c *gc.C) {
+	owned := sshkeys.WithOwner(key, "bob")
+	c.Assert(owned, gc.Equals, key+" # juju-user bob")
+	c.Assert(sshkeys.Owner(owned), gc.Equals, "bob")
+	c.Assert(sshkeys.Owner(key), gc.Equals, "")
+	c.Assert(sshkeys.WithOwner(owned, "mary"), gc.Equals, key+" # juju-user mary")
+}
+
+func (s *SSHKeysSuite) TestOwnerOfImportedKey(c *gc.C) {
+	owned := sshkeys.WithOwner(sshkeys.MarkImported(key, "lp:someone"), "bob")
+	c.Assert(sshkeys.Owner(owned), gc.Equals, "bob")
+	c.Assert(sshkeys.ImportSource(owned), gc.Equals, "lp:someone")
+}
+
+func (s *SSHKeysSuite) TestBaseComment(c *gc.C) {
+	c.Assert(sshkeys.BaseComment("user@host # ssh-import-id lp:someone # juju-user bob"), gc.Equals, "user@host")
+	c.Assert(sshkeys.BaseComment("user@host"), gc.Equals, "user@host")
+}
+
+func (s *SSHKeysSuite) TestImport(c *gc.C) {
+	s.PatchValue(&sshkeys.RunSSHImportId, func(source string) (string, error) {
+		c.Assert(source, gc.Equals, "gh:someone")
+		return "INFO something\n" + key + "\n", nil
+	})
+	keys, err := sshkeys.Import("gh:someone")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, jc.DeepEquals, []string{key + " # ssh-import-id gh:someone"})
+}
+
+func (s *SSHKeysSuite) TestImportNoKeys(c *gc.C) {
+	s.PatchValue(&sshkeys.RunSSHImportId, func(string) (string, error) {
+		return "ERROR no keys\n", nil
+	})
+	_, err := sshkeys.Import("gh:someone")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SSHKeysSuite) TestValidateSource(c *gc.C) {
+	c.Assert(sshkeys.ValidateSource("lp:someone"), jc.ErrorIsNil)
+	c.Assert(sshkeys.ValidateSource("gh:someone"), jc.ErrorIsNil)
+	for _, source := range []string{"someone", "lp:", "xx:someone"} {
+		c.Check(sshkeys.ValidateSource(source), jc.Satisfies, errors.IsNotValid)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package tracing describes the spans that agents record for slow
+// operations, such as hook executions and cloud provider calls, so
+// that operators can see where the time in a deployment went.
+package tracing
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+)
+
+var logger = loggo.GetLogger("juju.core.tracing")
+
+// Kind identifies the type of operation a span records.
+type Kind string
+
+const (
+	// KindHook is the kind of a span recording the execution of a
+	// hook by a unit agent. The span's name is the hook name.
+	KindHook Kind = "hook"
+
+	// KindProviderCall is the kind of a span recording a call to the
+	// cloud provider. The span's name is the provider method called.
+	KindProviderCall Kind = "provider-call"
+
+	// KindFacadeCall is the kind of a span recording the handling of
+	// an API request by a controller. The span's name is the facade
+	// and method called, as in "Client.FullStatus".
+	KindFacadeCall Kind = "facade-call"
+
+	// KindTxn is the kind of a span recording the running of a
+	// database transaction by a controller. The span's name lists the
+	// collections the transaction changed.
+	KindTxn Kind = "txn"
+)
+
+// Span records an operation performed for an entity.
+type Span struct {
+	// Kind is the type of the operation.
+	Kind Kind
+
+	// Entity is the tag of the entity the operation was performed
+	// for, such as the unit running a hook, or the machine whose
+	// instance is being started.
+	Entity string
+
+	// Name identifies the operation within its kind.
+	Name string
+
+	// Start is when the operation started.
+	Start time.Time
+
+	// Duration is how long the operation took.
+	Duration time.Duration
+
+	// Error holds the error the operation failed with, if any.
+	Error string
+
+	// CorrelationID identifies the operator action the operation
+	// was performed for, if known.
+	CorrelationID string
+}
+
+// Recorder records spans.
+type Recorder interface {
+	// RecordSpans records the spans. It is up to the recorder which
+	// spans are kept; typically only those of slow operations are.
+	RecordSpans(spans []Span) error
+}
+
+// Tracer times operations and passes the spans describing them to
+// its recorder.
+type Tracer struct {
+	// Recorder records the spans. If it is nil, no spans are
+	// recorded.
+	Recorder Recorder
+
+	// Clock is used to time the operations.
+	Clock clock.Clock
+}
+
+// Trace calls f and records a span of the given kind and name for the
+// entity, describing the call. The error returned by f is returned.
+// Failing to record the span is logged, and does not fail the
+// operation.
+func (t Tracer) Trace(kind Kind, entity, name string, f func() error) error {
+	if t.Recorder == nil {
+		return f()
+	}
+	start := t.Clock.Now()
+	err := f()
+	span := Span{
+		Kind:     kind,
+		Entity:   entity,
+		Name:     name,
+		Start:    start,
+		Duration: t.Clock.Now().Sub(start),
+	}
+	if err != nil {
+		span.Error = err.Error()
+	}
+	if recordErr := t.Recorder.RecordSpans([]Span{span}); recordErr != nil {
+		logger.Warningf("cannot record %s %q span for %s: %v", kind, name, entity, recordErr)
+	}
+	return err
+}
+
+// RecorderSwitch is a Recorder that passes spans on to a recorder
+// that may be replaced while it is in use. Spans are dropped while no
+// recorder is set.
+type RecorderSwitch struct {
+	mu       sync.Mutex
+	recorder Recorder
+}
+
+// Set sets the recorder to which spans are passed. A nil recorder
+// causes spans to be dropped.
+func (s *RecorderSwitch) Set(recorder Recorder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recorder = recorder
+}
+
+// Enabled reports whether a recorder is set, so that callers can avoid
+// building spans that would only be dropped.
+func (s *RecorderSwitch) Enabled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.recorder != nil
+}
+
+// RecordSpans is part of the Recorder interface.
+func (s *RecorderSwitch) RecordSpans(spans []Span) error {
+	s.mu.Lock()
+	recorder := s.recorder
+	s.mu.Unlock()
+	if recorder == nil {
+		return nil
+	}
+	return recorder.RecordSpans(spans)
+}
@@ -0,0 +1,114 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tracing_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/tracing"
+)
+
+type TracerSuite struct {
+	testing.IsolationSuite
+	clock    *testing.Clock
+	recorder *fakeRecorder
+	tracer   tracing.Tracer
+}
+
+var _ = gc.Suite(&TracerSuite{})
+
+func (s *TracerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testing.NewClock(time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC))
+	s.recorder = &fakeRecorder{}
+	s.tracer = tracing.Tracer{
+		Recorder: s.recorder,
+		Clock:    s.clock,
+	}
+}
+
+func (s *TracerSuite) TestTrace(c *gc.C) {
+	start := s.clock.Now()
+	err := s.tracer.Trace(tracing.KindHook, "unit-mysql-0", "install", func() error {
+		s.clock.Advance(time.Minute)
+		return nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.recorder.spans, jc.DeepEquals, []tracing.Span{{
+		Kind:     tracing.KindHook,
+		Entity:   "unit-mysql-0",
+		Name:     "install",
+		Start:    start,
+		Duration: time.Minute,
+	}})
+}
+
+func (s *TracerSuite) TestTraceError(c *gc.C) {
+	err := s.tracer.Trace(tracing.KindProviderCall, "machine-0", "StartInstance", func() error {
+		return errors.New("no capacity")
+	})
+	c.Assert(err, gc.ErrorMatches, "no capacity")
+	c.Assert(s.recorder.spans, gc.HasLen, 1)
+	c.Assert(s.recorder.spans[0].Error, gc.Equals, "no capacity")
+}
+
+func (s *TracerSuite) TestTraceRecordError(c *gc.C) {
+	s.recorder.err = errors.New("boom")
+	called := false
+	err := s.tracer.Trace(tracing.KindHook, "unit-mysql-0", "install", func() error {
+		called = true
+		return nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *TracerSuite) TestTraceWithoutRecorder(c *gc.C) {
+	tracer := tracing.Tracer{}
+	err := tracer.Trace(tracing.KindHook, "unit-mysql-0", "install", func() error {
+		return errors.New("failed")
+	})
+	c.Assert(err, gc.ErrorMatches, "failed")
+}
+
+func (s *TracerSuite) TestRecorderSwitch(c *gc.C) {
+	var recorderSwitch tracing.RecorderSwitch
+	span := tracing.Span{Kind: tracing.KindTxn, Entity: "model-deadbeef", Name: "units"}
+
+	// Spans are dropped until a recorder is set.
+	c.Assert(recorderSwitch.Enabled(), jc.IsFalse)
+	err := recorderSwitch.RecordSpans([]tracing.Span{span})
+	c.Assert(err, jc.ErrorIsNil)
+
+	recorderSwitch.Set(s.recorder)
+	c.Assert(recorderSwitch.Enabled(), jc.IsTrue)
+	err = recorderSwitch.RecordSpans([]tracing.Span{span})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.recorder.spans, jc.DeepEquals, []tracing.Span{span})
+
+	s.recorder.err = errors.New("boom")
+	err = recorderSwitch.RecordSpans([]tracing.Span{span})
+	c.Assert(err, gc.ErrorMatches, "boom")
+
+	recorderSwitch.Set(nil)
+	c.Assert(recorderSwitch.Enabled(), jc.IsFalse)
+	err = recorderSwitch.RecordSpans([]tracing.Span{span})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.recorder.spans, gc.HasLen, 2)
+}
+
+type fakeRecorder struct {
+	spans []tracing.Span
+	err   error
+}
+
+func (r *fakeRecorder) RecordSpans(spans []tracing.Span) error {
+	r.spans = append(r.spans, spans...)
+	return r.err
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/tools"
 )
 
@@ -60,6 +61,11 @@ type BootstrapParams struct {
 	// that rely on it for selecting images. This will be empty for
 	// providers that do not implements simplestreams.HasRegion.
 	ImageMetadata []*imagemetadata.ImageMetadata
+
+	// KeepBroken, if true, means that the provider should not clean
+	// up after a failed bootstrap, so that the failure can be
+	// investigated.
+	KeepBroken bool
 }
 
 // BootstrapFinalizer is a function returned from Environ.Bootstrap.
@@ -95,6 +101,47 @@ type BootstrapResult struct {
 	// bootstrap process by transferring the tools and installing the
 	// initial Juju controller.
 	Finalize BootstrapFinalizer
+
+	// ResumeState, if non-nil, records what is needed to finalize
+	// the bootstrap in a later process, should this one be
+	// interrupted. It is only set by providers that implement
+	// BootstrapResumer.
+	ResumeState *BootstrapResumeState
+}
+
+// BootstrapResumeState holds the details of a started bootstrap
+// instance that are needed to finalize the bootstrap in another
+// process.
+type BootstrapResumeState struct {
+	// InstanceId is the ID of the bootstrap instance.
+	InstanceId instance.Id `yaml:"instance-id"`
+
+	// Arch is the instance's architecture.
+	Arch string `yaml:"arch"`
+
+	// Series is the instance's series.
+	Series string `yaml:"series"`
+
+	// Hardware holds the instance's hardware characteristics.
+	Hardware *instance.HardwareCharacteristics `yaml:"hardware,omitempty"`
+
+	// InitialSSHHostKeys holds the SSH host keys that the instance
+	// was configured with, so that its identity can be verified.
+	InitialSSHHostKeys instancecfg.SSHHostKeys `yaml:"initial-ssh-host-keys"`
+
+	// Config holds model config attributes that the provider
+	// updated when starting the instance.
+	Config map[string]interface{} `yaml:"config,omitempty"`
+}
+
+// BootstrapResumer is implemented by environs that can finalize a
+// bootstrap whose instance was started by an earlier, interrupted,
+// call to Bootstrap.
+type BootstrapResumer interface {
+	// ResumeBootstrap returns the result of the bootstrap, with the
+	// given parameters, that started the instance described by the
+	// given state.
+	ResumeBootstrap(ctx BootstrapContext, args BootstrapParams, state BootstrapResumeState) (*BootstrapResult, error)
 }
 
 // BootstrapContext is an interface that is passed to
@@ -32,6 +32,7 @@ import (
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/dns"
 	"github.com/juju/juju/environs/gui"
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/simplestreams"
@@ -140,6 +141,20 @@ type BootstrapParams struct {
 
 	// DialOpts contains the bootstrap dial options.
 	DialOpts environs.BootstrapDialOpts
+
+	// KeepBroken, if true, asks the provider to leave behind whatever
+	// it created if bootstrap fails, so that it can be investigated.
+	KeepBroken bool
+
+	// Progress, if non-nil, is notified as the bootstrap moves
+	// through its phases.
+	Progress Progress
+
+	// Checkpoints, if non-nil, stores a checkpoint once the
+	// controller instance has been started, so that an interrupted
+	// bootstrap can be resumed without starting another instance.
+	// The checkpoint is removed once the bootstrap completes.
+	Checkpoints CheckpointStore
 }
 
 // Validate validates the bootstrap parameters.
@@ -180,7 +195,26 @@ func withDefaultControllerConstraints(cons constraints.Value) constraints.Value
 // Bootstrap bootstraps the given environment. The supplied constraints are
 // used to provision the instance, and are also set within the bootstrapped
 // environment.
-func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args BootstrapParams) error {
+//
+// If args.Checkpoints holds a checkpoint for the same controller, and
+// the environ implements environs.BootstrapResumer, the bootstrap is
+// resumed using the instance started by the earlier bootstrap.
+func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args BootstrapParams) (err error) {
+	progress := args.Progress
+	if progress == nil {
+		progress = noProgress{}
+	}
+	phase := PhasePrepare
+	progress.PhaseStarted(phase)
+	defer func() {
+		progress.PhaseFinished(phase, err)
+	}()
+	nextPhase := func(next Phase) {
+		progress.PhaseFinished(phase, nil)
+		phase = next
+		progress.PhaseStarted(phase)
+	}
+
 	if err := args.Validate(); err != nil {
 		return errors.Annotate(err, "validating bootstrap parameters")
 	}
@@ -197,6 +231,22 @@ func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args Boo
 
 	_, supportsNetworking := environs.SupportsNetworking(environ)
 	logger.Debugf("model %q supports service/machine networks: %v", cfg.Name(), supportsNetworking)
+	if err := environs.ValidateContainerNetworkingMethod(environ, cfg.ContainerNetworkingMethod()); err != nil {
+		return errors.Trace(err)
+	}
+	if err := environs.ValidateLoadBalancerType(environ, cfg.LoadBalancerType()); err != nil {
+		return errors.Trace(err)
+	}
+	if err := dns.ValidateProvider(cfg.DNSProvider()); err != nil {
+		return errors.Trace(err)
+	}
+	// The admin CIDRs are only enforced by environs that can manage
+	// the ingress rules of the controller's instances.
+	if len(args.ControllerConfig.ControllerAdminCIDRs()) > 0 {
+		if _, ok := environ.(environs.ControllerFirewaller); !ok {
+			return errors.NotSupportedf("%s on %s", controller.ControllerAdminCIDRs, cfg.Type())
+		}
+	}
 	disableNetworkManagement, _ := cfg.DisableNetworkManagement()
 	logger.Debugf("network management by juju enabled: %v", !disableNetworkManagement)
 
@@ -368,9 +418,13 @@ func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args Boo
 		return err
 	}
 
-	ctx.Verbosef("Starting new instance for initial controller")
+	checkpoint, err := readCheckpoint(args.Checkpoints, args.ControllerConfig.ControllerUUID(), cfg.UUID())
+	if err != nil {
+		return errors.Trace(err)
+	}
 
-	result, err := environ.Bootstrap(ctx, environs.BootstrapParams{
+	nextPhase(PhaseStartInstance)
+	environBootstrapParams := environs.BootstrapParams{
 		CloudName:            args.Cloud.Name,
 		CloudRegion:          args.CloudRegion,
 		ControllerConfig:     args.ControllerConfig,
@@ -380,10 +434,35 @@ func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args Boo
 		Placement:            args.Placement,
 		AvailableTools:       availableTools,
 		ImageMetadata:        imageMetadata,
-	})
-	if err != nil {
-		return err
+		KeepBroken:           args.KeepBroken,
 	}
+	var result *environs.BootstrapResult
+	if resumer, ok := environ.(environs.BootstrapResumer); ok && checkpoint != nil {
+		ctx.Verbosef("Resuming bootstrap with instance %s", checkpoint.Instance.InstanceId)
+		result, err = resumer.ResumeBootstrap(ctx, environBootstrapParams, checkpoint.Instance)
+		if err != nil {
+			return errors.Annotate(err, "cannot resume bootstrap")
+		}
+	} else {
+		ctx.Verbosef("Starting new instance for initial controller")
+		result, err = environ.Bootstrap(ctx, environBootstrapParams)
+		if err != nil {
+			return err
+		}
+		if args.Checkpoints != nil && result.ResumeState != nil {
+			if err := args.Checkpoints.WriteCheckpoint(Checkpoint{
+				ControllerUUID: args.ControllerConfig.ControllerUUID(),
+				ModelUUID:      cfg.UUID(),
+				Phase:          PhaseInstallAgent,
+				Instance:       *result.ResumeState,
+			}); err != nil {
+				// Failing to write the checkpoint only means that
+				// the bootstrap cannot be resumed if interrupted.
+				logger.Warningf("cannot write bootstrap checkpoint: %v", err)
+			}
+		}
+	}
+	nextPhase(PhaseInstallAgent)
 
 	matchingTools, err := availableTools.Match(coretools.Filter{
 		Arch:   result.Arch,
@@ -403,6 +482,20 @@ func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args Boo
 	if err := setBootstrapToolsVersion(environ, newestVersion); err != nil {
 		return err
 	}
+	// Locally built agent binaries are trusted; any others must be
+	// signed by one of the controller's trusted keys.
+	if trustedKeys := args.ControllerConfig.AgentBinaryTrustedKeys(); trustedKeys != "" && builtTools == nil {
+		ctx.Infof("Verifying agent binary signatures")
+		verifiedDir, err := ioutil.TempDir("", "juju-verified-agent")
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer os.RemoveAll(verifiedDir)
+		selectedToolsList, err = verifyBootstrapTools(selectedToolsList, trustedKeys, verifiedDir)
+		if err != nil {
+			return errors.Annotate(err, "cannot verify agent binaries")
+		}
+	}
 
 	ctx.Infof("Installing Juju agent on bootstrap instance")
 	publicKey, err := userPublicSigningKey()
@@ -434,10 +527,40 @@ func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args Boo
 	if err := result.Finalize(ctx, instanceConfig, args.DialOpts); err != nil {
 		return err
 	}
+	if args.Checkpoints != nil {
+		if err := args.Checkpoints.RemoveCheckpoint(); err != nil {
+			logger.Warningf("cannot remove bootstrap checkpoint: %v", err)
+		}
+	}
 	ctx.Infof("Bootstrap agent now started")
 	return nil
 }
 
+// readCheckpoint returns the checkpoint from which to resume the
+// bootstrap of the controller and controller model with the given
+// UUIDs, or nil if the bootstrap should start afresh.
+func readCheckpoint(store CheckpointStore, controllerUUID, modelUUID string) (*Checkpoint, error) {
+	if store == nil {
+		return nil, nil
+	}
+	checkpoint, err := store.ReadCheckpoint()
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Annotate(err, "reading bootstrap checkpoint")
+	}
+	if checkpoint.ControllerUUID != controllerUUID ||
+		checkpoint.ModelUUID != modelUUID ||
+		checkpoint.Phase != PhaseInstallAgent {
+		logger.Warningf(
+			"ignoring bootstrap checkpoint for controller %q in phase %q",
+			checkpoint.ControllerUUID, checkpoint.Phase,
+		)
+		return nil, nil
+	}
+	return checkpoint, nil
+}
+
 func finalizeInstanceBootstrapConfig(
 	ctx environs.BootstrapContext,
 	icfg *instancecfg.InstanceConfig,
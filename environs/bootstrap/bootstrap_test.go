@@ -120,6 +120,77 @@ func (s *bootstrapSuite) TestBootstrapNeedsSettings(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *bootstrapSuite) TestBootstrapProviderContainerNetworkingUnsupported(c *gc.C) {
+	env := newEnviron("foo", useDefaultKeys, map[string]interface{}{
+		"container-networking-method": "provider",
+	})
+	s.setDummyStorage(c, env)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{
+		ControllerConfig: coretesting.FakeControllerConfig(),
+		AdminSecret:      "admin-secret",
+		CAPrivateKey:     coretesting.CAKey,
+	})
+	c.Assert(err, gc.ErrorMatches, `container-networking-method "provider" on dummy not supported`)
+	c.Assert(env.bootstrapCount, gc.Equals, 0)
+}
+
+func (s *bootstrapSuite) TestBootstrapProviderContainerNetworkingSupported(c *gc.C) {
+	env := newEnviron("foo", useDefaultKeys, map[string]interface{}{
+		"container-networking-method": "provider",
+	})
+	s.setDummyStorage(c, env)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), bootstrapEnvironWithContainerAddresses{bootstrapEnviron: env}, bootstrap.BootstrapParams{
+		ControllerConfig: coretesting.FakeControllerConfig(),
+		AdminSecret:      "admin-secret",
+		CAPrivateKey:     coretesting.CAKey,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.bootstrapCount, gc.Equals, 1)
+}
+
+func (s *bootstrapSuite) TestBootstrapLoadBalancersUnsupported(c *gc.C) {
+	env := newEnviron("foo", useDefaultKeys, map[string]interface{}{
+		"load-balancer-type": "network",
+	})
+	s.setDummyStorage(c, env)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{
+		ControllerConfig: coretesting.FakeControllerConfig(),
+		AdminSecret:      "admin-secret",
+		CAPrivateKey:     coretesting.CAKey,
+	})
+	c.Assert(err, gc.ErrorMatches, `load-balancer-type "network" on dummy not supported`)
+	c.Assert(env.bootstrapCount, gc.Equals, 0)
+}
+
+func (s *bootstrapSuite) TestBootstrapDNSProviderUnsupported(c *gc.C) {
+	env := newEnviron("foo", useDefaultKeys, map[string]interface{}{
+		"dns-provider": "route53",
+		"dns-zone":     "example.com",
+	})
+	s.setDummyStorage(c, env)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{
+		ControllerConfig: coretesting.FakeControllerConfig(),
+		AdminSecret:      "admin-secret",
+		CAPrivateKey:     coretesting.CAKey,
+	})
+	c.Assert(err, gc.ErrorMatches, `dns-provider "route53" not supported`)
+	c.Assert(env.bootstrapCount, gc.Equals, 0)
+}
+
+func (s *bootstrapSuite) TestBootstrapControllerAdminCIDRsUnsupported(c *gc.C) {
+	env := newEnviron("foo", useDefaultKeys, nil)
+	s.setDummyStorage(c, env)
+	controllerCfg := coretesting.FakeControllerConfig()
+	controllerCfg["controller-admin-cidrs"] = "192.0.2.0/24"
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{
+		ControllerConfig: controllerCfg,
+		AdminSecret:      "admin-secret",
+		CAPrivateKey:     coretesting.CAKey,
+	})
+	c.Assert(err, gc.ErrorMatches, `controller-admin-cidrs on dummy not supported`)
+	c.Assert(env.bootstrapCount, gc.Equals, 0)
+}
+
 func (s *bootstrapSuite) TestBootstrapEmptyConstraints(c *gc.C) {
 	env := newEnviron("foo", useDefaultKeys, nil)
 	s.setDummyStorage(c, env)
@@ -1377,6 +1448,15 @@ func (e bootstrapEnvironWithRegion) Region() (simplestreams.CloudSpec, error) {
 	return e.region, nil
 }
 
+type bootstrapEnvironWithContainerAddresses struct {
+	*bootstrapEnviron
+	environs.Networking // stub out all methods we don't care about.
+}
+
+func (e bootstrapEnvironWithContainerAddresses) SupportsContainerAddresses() (bool, error) {
+	return true, nil
+}
+
 type bootstrapEnvironNoExplicitArchitectures struct {
 	*bootstrapEnvironWithRegion
 }
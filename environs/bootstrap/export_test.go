@@ -9,5 +9,6 @@ var (
 	FindTools                = &findTools
 	FindBootstrapTools       = findBootstrapTools
 	FindPackagedTools        = findPackagedTools
+	VerifyBootstrapTools     = verifyBootstrapTools
 	GUIFetchMetadata         = &guiFetchMetadata
 )
@@ -0,0 +1,126 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bootstrap
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/environs"
+)
+
+// Phase identifies a phase of the bootstrap process.
+type Phase string
+
+const (
+	// PhasePrepare is the phase in which agent binaries and images
+	// are selected, and constraints validated.
+	PhasePrepare Phase = "prepare"
+
+	// PhaseStartInstance is the phase in which the controller
+	// instance is started, or found if the bootstrap is resumed.
+	PhaseStartInstance Phase = "start-instance"
+
+	// PhaseInstallAgent is the phase in which the agent binaries are
+	// transferred to the controller instance, and the controller
+	// agent installed and started.
+	PhaseInstallAgent Phase = "install-agent"
+)
+
+// Progress is notified as the bootstrap process moves through its
+// phases, so that a user interface can report on them.
+type Progress interface {
+	// PhaseStarted is called when the given phase starts.
+	PhaseStarted(phase Phase)
+
+	// PhaseFinished is called when the given phase finishes,
+	// with the error that caused it to fail, if any.
+	PhaseFinished(phase Phase, err error)
+}
+
+type noProgress struct{}
+
+func (noProgress) PhaseStarted(Phase)         {}
+func (noProgress) PhaseFinished(Phase, error) {}
+
+// Checkpoint records how far an interrupted bootstrap got, so that a
+// later bootstrap of the same controller can resume from there.
+type Checkpoint struct {
+	// ControllerUUID is the UUID of the controller being
+	// bootstrapped.
+	ControllerUUID string `yaml:"controller-uuid"`
+
+	// ModelUUID is the UUID of the controller model.
+	ModelUUID string `yaml:"model-uuid"`
+
+	// Phase is the phase that the bootstrap should resume from.
+	Phase Phase `yaml:"phase"`
+
+	// Instance holds the details of the started controller
+	// instance.
+	Instance environs.BootstrapResumeState `yaml:"instance"`
+}
+
+// CheckpointStore stores the checkpoint of a bootstrap in progress.
+type CheckpointStore interface {
+	// ReadCheckpoint returns the stored checkpoint, or an error
+	// satisfying errors.IsNotFound if there is none.
+	ReadCheckpoint() (*Checkpoint, error)
+
+	// WriteCheckpoint stores the given checkpoint, replacing any
+	// that is already stored.
+	WriteCheckpoint(Checkpoint) error
+
+	// RemoveCheckpoint removes the stored checkpoint, if any.
+	RemoveCheckpoint() error
+}
+
+// NewCheckpointFile returns a CheckpointStore that stores the checkpoint
+// as YAML in the file with the given path. The checkpoint holds the
+// instance's SSH host keys, so the file is only readable by its owner.
+func NewCheckpointFile(path string) CheckpointStore {
+	return checkpointFile(path)
+}
+
+type checkpointFile string
+
+// ReadCheckpoint is part of the CheckpointStore interface.
+func (f checkpointFile) ReadCheckpoint() (*Checkpoint, error) {
+	data, err := ioutil.ReadFile(string(f))
+	if os.IsNotExist(err) {
+		return nil, errors.NotFoundf("bootstrap checkpoint %q", string(f))
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var checkpoint Checkpoint
+	if err := yaml.Unmarshal(data, &checkpoint); err != nil {
+		return nil, errors.Annotate(err, "cannot unmarshal bootstrap checkpoint")
+	}
+	return &checkpoint, nil
+}
+
+// WriteCheckpoint is part of the CheckpointStore interface.
+func (f checkpointFile) WriteCheckpoint(checkpoint Checkpoint) error {
+	data, err := yaml.Marshal(checkpoint)
+	if err != nil {
+		return errors.Annotate(err, "cannot marshal bootstrap checkpoint")
+	}
+	if err := os.MkdirAll(filepath.Dir(string(f)), 0700); err != nil {
+		return errors.Trace(err)
+	}
+	return utils.AtomicWriteFile(string(f), data, 0600)
+}
+
+// RemoveCheckpoint is part of the CheckpointStore interface.
+func (f checkpointFile) RemoveCheckpoint() error {
+	if err := os.Remove(string(f)); err != nil && !os.IsNotExist(err) {
+		return errors.Trace(err)
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bootstrap_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/bootstrap"
+	envtesting "github.com/juju/juju/environs/testing"
+	"github.com/juju/juju/instance"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type checkpointFileSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&checkpointFileSuite{})
+
+func (s *checkpointFileSuite) TestReadWriteRemove(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "bootstrap", "ctrl.yaml")
+	store := bootstrap.NewCheckpointFile(path)
+
+	_, err := store.ReadCheckpoint()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	arch := "amd64"
+	checkpoint := bootstrap.Checkpoint{
+		ControllerUUID: coretesting.ControllerTag.Id(),
+		ModelUUID:      coretesting.ModelTag.Id(),
+		Phase:          bootstrap.PhaseInstallAgent,
+		Instance: environs.BootstrapResumeState{
+			InstanceId: "i-123",
+			Arch:       arch,
+			Series:     "xenial",
+			Hardware:   &instance.HardwareCharacteristics{Arch: &arch},
+			Config:     map[string]interface{}{"vpc-id": "vpc-1"},
+		},
+	}
+	err = store.WriteCheckpoint(checkpoint)
+	c.Assert(err, jc.ErrorIsNil)
+
+	info, err := os.Stat(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Mode().Perm(), gc.Equals, os.FileMode(0600))
+
+	read, err := store.ReadCheckpoint()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*read, jc.DeepEquals, checkpoint)
+
+	err = store.RemoveCheckpoint()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = store.ReadCheckpoint()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = store.RemoveCheckpoint()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+type recordingProgress struct {
+	events []string
+}
+
+func (p *recordingProgress) PhaseStarted(phase bootstrap.Phase) {
+	p.events = append(p.events, "started "+string(phase))
+}
+
+func (p *recordingProgress) PhaseFinished(phase bootstrap.Phase, err error) {
+	event := "finished " + string(phase)
+	if err != nil {
+		event += ": " + err.Error()
+	}
+	p.events = append(p.events, event)
+}
+
+type resumableEnviron struct {
+	*bootstrapEnviron
+	resumeCount int
+	resumeState environs.BootstrapResumeState
+	finalizeErr error
+}
+
+func (e *resumableEnviron) Bootstrap(ctx environs.BootstrapContext, args environs.BootstrapParams) (*environs.BootstrapResult, error) {
+	result, err := e.bootstrapEnviron.Bootstrap(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	result.ResumeState = &environs.BootstrapResumeState{
+		InstanceId: "i-123",
+		Arch:       result.Arch,
+		Series:     result.Series,
+	}
+	return e.withFinalizeErr(result), nil
+}
+
+func (e *resumableEnviron) ResumeBootstrap(
+	ctx environs.BootstrapContext,
+	args environs.BootstrapParams,
+	state environs.BootstrapResumeState,
+) (*environs.BootstrapResult, error) {
+	e.resumeCount++
+	e.resumeState = state
+	result, err := e.bootstrapEnviron.Bootstrap(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	e.bootstrapCount--
+	return e.withFinalizeErr(result), nil
+}
+
+func (e *resumableEnviron) withFinalizeErr(result *environs.BootstrapResult) *environs.BootstrapResult {
+	finalize := result.Finalize
+	result.Finalize = func(ctx environs.BootstrapContext, icfg *instancecfg.InstanceConfig, opts environs.BootstrapDialOpts) error {
+		if err := finalize(ctx, icfg, opts); err != nil {
+			return err
+		}
+		return e.finalizeErr
+	}
+	return result
+}
+
+type resumableBootstrap struct {
+	env         *resumableEnviron
+	checkpoints bootstrap.CheckpointStore
+	progress    *recordingProgress
+}
+
+func (s *bootstrapSuite) newResumableBootstrap(c *gc.C) *resumableBootstrap {
+	env := newEnviron("foo", useDefaultKeys, nil)
+	s.setDummyStorage(c, env)
+	return &resumableBootstrap{
+		env:         &resumableEnviron{bootstrapEnviron: env},
+		checkpoints: bootstrap.NewCheckpointFile(filepath.Join(c.MkDir(), "ctrl.yaml")),
+		progress:    &recordingProgress{},
+	}
+}
+
+func (b *resumableBootstrap) bootstrap(c *gc.C) error {
+	return bootstrap.Bootstrap(envtesting.BootstrapContext(c), b.env, bootstrap.BootstrapParams{
+		ControllerConfig: coretesting.FakeControllerConfig(),
+		AdminSecret:      "admin-secret",
+		CAPrivateKey:     coretesting.CAKey,
+		Progress:         b.progress,
+		Checkpoints:      b.checkpoints,
+	})
+}
+
+func (s *bootstrapSuite) TestBootstrapReportsPhases(c *gc.C) {
+	b := s.newResumableBootstrap(c)
+	err := b.bootstrap(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(b.progress.events, jc.DeepEquals, []string{
+		"started prepare",
+		"finished prepare",
+		"started start-instance",
+		"finished start-instance",
+		"started install-agent",
+		"finished install-agent",
+	})
+	c.Assert(b.env.bootstrapCount, gc.Equals, 1)
+	c.Assert(b.env.resumeCount, gc.Equals, 0)
+
+	// The checkpoint is removed once the bootstrap completes.
+	_, err = b.checkpoints.ReadCheckpoint()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *bootstrapSuite) TestBootstrapResumesFromCheckpoint(c *gc.C) {
+	b := s.newResumableBootstrap(c)
+	b.env.finalizeErr = errors.New("connection reset")
+	err := b.bootstrap(c)
+	c.Assert(err, gc.ErrorMatches, "connection reset")
+	c.Assert(b.progress.events[len(b.progress.events)-1], gc.Equals,
+		"finished install-agent: connection reset",
+	)
+
+	checkpoint, err := b.checkpoints.ReadCheckpoint()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(checkpoint.ControllerUUID, gc.Equals, coretesting.FakeControllerConfig().ControllerUUID())
+	c.Assert(checkpoint.ModelUUID, gc.Equals, b.env.Config().UUID())
+	c.Assert(checkpoint.Phase, gc.Equals, bootstrap.PhaseInstallAgent)
+	c.Assert(checkpoint.Instance.InstanceId, gc.Equals, instance.Id("i-123"))
+
+	// Bootstrapping again resumes with the started instance.
+	b.env.finalizeErr = nil
+	err = b.bootstrap(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(b.env.bootstrapCount, gc.Equals, 1)
+	c.Assert(b.env.resumeCount, gc.Equals, 1)
+	c.Assert(b.env.resumeState, jc.DeepEquals, checkpoint.Instance)
+	c.Assert(b.env.finalizerCount, gc.Equals, 2)
+
+	_, err = b.checkpoints.ReadCheckpoint()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *bootstrapSuite) TestBootstrapIgnoresCheckpointForOtherController(c *gc.C) {
+	b := s.newResumableBootstrap(c)
+	err := b.checkpoints.WriteCheckpoint(bootstrap.Checkpoint{
+		ControllerUUID: "deadbeef-0bad-400d-8000-4b1d0d06f00d",
+		ModelUUID:      b.env.Config().UUID(),
+		Phase:          bootstrap.PhaseInstallAgent,
+		Instance:       environs.BootstrapResumeState{InstanceId: "i-456"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = b.bootstrap(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(b.env.bootstrapCount, gc.Equals, 1)
+	c.Assert(b.env.resumeCount, gc.Equals, 0)
+}
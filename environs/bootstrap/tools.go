@@ -4,9 +4,16 @@
 package bootstrap
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
 
 	"github.com/juju/errors"
+	"github.com/juju/utils"
 	"github.com/juju/utils/arch"
 	jujuos "github.com/juju/utils/os"
 	"github.com/juju/utils/series"
@@ -121,3 +128,58 @@ func findBootstrapTools(env environs.Environ, vers *version.Number, arch, series
 	streams := envtools.PreferredStreams(vers, env.Config().Development(), env.Config().AgentStream())
 	return findTools(env, cliVersion.Major, cliVersion.Minor, streams, filter)
 }
+
+// verifyBootstrapTools fetches the agent binaries in the list and checks
+// their size, hash and signature against the trusted keys, writing the
+// verified tarballs into dir. The returned list refers to the verified
+// copies, so that the tarball transferred to the bootstrap instance is
+// the one that was checked rather than a second download.
+func verifyBootstrapTools(toolsList coretools.List, trustedKeys, dir string) (coretools.List, error) {
+	verified := make(coretools.List, len(toolsList))
+	for i, tools := range toolsList {
+		data, err := fetchBootstrapTools(tools.URL)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot fetch agent binaries %s", tools.Version)
+		}
+		if int64(len(data)) != tools.Size {
+			return nil, errors.Errorf("size mismatch for %s", tools.URL)
+		}
+		if hash := fmt.Sprintf("%x", sha256.Sum256(data)); hash != tools.SHA256 {
+			return nil, errors.Errorf("hash mismatch for %s", tools.URL)
+		}
+		if err := envtools.VerifySignature(bytes.NewReader(data), tools, trustedKeys); err != nil {
+			return nil, errors.Trace(err)
+		}
+		filename := filepath.Join(dir, fmt.Sprintf("juju-%s.tgz", tools.Version))
+		if err := ioutil.WriteFile(filename, data, 0644); err != nil {
+			return nil, errors.Trace(err)
+		}
+		verifiedTools := *tools
+		verifiedTools.URL = fmt.Sprintf("file://%s", filename)
+		verified[i] = &verifiedTools
+	}
+	return verified, nil
+}
+
+// fetchBootstrapTools returns the contents of the agent binary tarball
+// at the given URL.
+func fetchBootstrapTools(rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if u.Scheme == "file" {
+		return ioutil.ReadFile(filepath.FromSlash(u.Path))
+	}
+	// No need to verify the server's identity because we verify the
+	// signature of the tarball.
+	resp, err := utils.GetNonValidatingHTTPClient().Get(rawURL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("bad HTTP response: %v", resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
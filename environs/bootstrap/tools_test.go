@@ -4,6 +4,12 @@
 package bootstrap_test
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/arch"
@@ -14,6 +20,8 @@ import (
 
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/bootstrap"
+	"github.com/juju/juju/environs/simplestreams"
+	sstesting "github.com/juju/juju/environs/simplestreams/testing"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/tools"
 	jujuversion "github.com/juju/juju/version"
@@ -246,3 +254,61 @@ func (s *toolsSuite) TestFindAvailableToolsCompleteNoValidate(c *gc.C) {
 	c.Assert(availableTools, gc.HasLen, len(allTools))
 	c.Assert(env.constraintsValidatorCount, gc.Equals, 0)
 }
+
+func (s *toolsSuite) signedToolsFile(c *gc.C, data string) *tools.Tools {
+	filename := filepath.Join(c.MkDir(), "tools.tgz")
+	err := ioutil.WriteFile(filename, []byte(data), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	signature, err := simplestreams.SignDetached(
+		strings.NewReader(data),
+		sstesting.SignedMetadataPrivateKey,
+		sstesting.PrivateKeyPassphrase,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	return &tools.Tools{
+		Version:   version.MustParseBinary("2.3.4-xenial-amd64"),
+		URL:       "file://" + filename,
+		Size:      int64(len(data)),
+		SHA256:    fmt.Sprintf("%x", sha256.Sum256([]byte(data))),
+		Signature: signature,
+	}
+}
+
+func (s *toolsSuite) TestVerifyBootstrapTools(c *gc.C) {
+	signed := s.signedToolsFile(c, "agent binary")
+	dir := c.MkDir()
+	verified, err := bootstrap.VerifyBootstrapTools(tools.List{signed}, sstesting.SignedMetadataPublicKey, dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(verified, gc.HasLen, 1)
+
+	filename := filepath.Join(dir, "juju-2.3.4-xenial-amd64.tgz")
+	c.Assert(verified[0].URL, gc.Equals, "file://"+filename)
+	c.Assert(verified[0].SHA256, gc.Equals, signed.SHA256)
+	data, err := ioutil.ReadFile(filename)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "agent binary")
+}
+
+func (s *toolsSuite) TestVerifyBootstrapToolsUnsigned(c *gc.C) {
+	unsigned := s.signedToolsFile(c, "agent binary")
+	unsigned.Signature = ""
+	_, err := bootstrap.VerifyBootstrapTools(tools.List{unsigned}, sstesting.SignedMetadataPublicKey, c.MkDir())
+	c.Assert(err, gc.ErrorMatches, "agent binaries 2.3.4-xenial-amd64 are not signed")
+}
+
+func (s *toolsSuite) TestVerifyBootstrapToolsTampered(c *gc.C) {
+	signed := s.signedToolsFile(c, "agent binary")
+	tampered := s.signedToolsFile(c, "tampered binary")
+	// The mirror serves a tarball whose metadata matches but whose
+	// signature was made over the original.
+	tampered.Signature = signed.Signature
+	_, err := bootstrap.VerifyBootstrapTools(tools.List{tampered}, sstesting.SignedMetadataPublicKey, c.MkDir())
+	c.Assert(err, gc.ErrorMatches, "invalid signature for agent binaries 2.3.4-xenial-amd64: .*")
+}
+
+func (s *toolsSuite) TestVerifyBootstrapToolsHashMismatch(c *gc.C) {
+	signed := s.signedToolsFile(c, "agent binary")
+	signed.SHA256 = fmt.Sprintf("%x", sha256.Sum256([]byte("something else")))
+	_, err := bootstrap.VerifyBootstrapTools(tools.List{signed}, sstesting.SignedMetadataPublicKey, c.MkDir())
+	c.Assert(err, gc.ErrorMatches, "hash mismatch for file://.*")
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+
+	"github.com/juju/juju/environs/config"
 )
 
 var logger = loggo.GetLogger("juju.environs")
@@ -117,3 +119,21 @@ func RegisteredProviders() []string {
 func Provider(providerType string) (EnvironProvider, error) {
 	return GlobalProviderRegistry().Provider(providerType)
 }
+
+// ConfigDocs returns documentation for the configuration fields of the
+// specified provider. It returns a NotSupported error if the provider
+// does not describe its configuration schema.
+func ConfigDocs(provider EnvironProvider) (config.Docs, error) {
+	if p, ok := provider.(ProviderConfigDocs); ok {
+		return p.ConfigDocs(), nil
+	}
+	p, ok := provider.(ProviderSchema)
+	if !ok {
+		return nil, errors.NotSupportedf("provider config schema")
+	}
+	docs := make(config.Docs)
+	for name, field := range p.Schema() {
+		docs[name] = config.FieldDoc{Attr: field}
+	}
+	return docs, nil
+}
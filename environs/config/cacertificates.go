@@ -0,0 +1,60 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/juju/errors"
+)
+
+// validateCACertificates checks that each of the PEM blobs held by the
+// custom-ca-certificates attribute holds one or more certificates, and
+// nothing else.
+func validateCACertificates(certs []string) error {
+	for i, cert := range certs {
+		rest := []byte(cert)
+		n := 0
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			if block.Type != "CERTIFICATE" {
+				return errors.Errorf("certificate %d: unexpected PEM block %q", i, block.Type)
+			}
+			if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+				return errors.Annotatef(err, "certificate %d", i)
+			}
+			n++
+		}
+		if n == 0 {
+			return errors.Errorf("certificate %d: no PEM encoded certificate found", i)
+		}
+		if len(bytes.TrimSpace(rest)) > 0 {
+			return errors.Errorf("certificate %d: unexpected data after the last certificate", i)
+		}
+	}
+	return nil
+}
+
+// stringList returns the value of a list attribute, which is a
+// []interface{} when coerced by the schema, as a []string.
+func stringList(value interface{}) []string {
+	switch value := value.(type) {
+	case []string:
+		return value
+	case []interface{}:
+		result := make([]string, len(value))
+		for i, v := range value {
+			result[i] = fmt.Sprint(v)
+		}
+		return result
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// managedCloudInitModules holds the cloud-init modules that Juju
+// configures itself, to set up machines, including controllers, or
+// from other model config such as the apt proxy and mirror settings.
+// The cloudinit-userdata attribute may therefore not configure them.
+var managedCloudInitModules = []string{
+	"apt",
+	"apt_mirror",
+	"apt_preferences",
+	"apt_proxy",
+	"apt_sources",
+	"bootcmd",
+	"disable_root",
+	"output",
+	"package_mirror",
+	"package_proxy",
+	"package_sources",
+	"package_update",
+	"package_upgrade",
+	"ssh_authorized_keys",
+	"ssh_keys",
+	"users",
+}
+
+// IsManagedCloudInitModule reports whether Juju configures the named
+// cloud-init module itself, so that cloudinit-userdata may not.
+func IsManagedCloudInitModule(module string) bool {
+	for _, managed := range managedCloudInitModules {
+		if module == managed {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCloudInitUserData parses the YAML cloud-config held by the
+// cloudinit-userdata attribute, returning nil if it is empty. Nested
+// mappings are returned as map[string]interface{}, so that the result
+// may be marshalled as JSON as well as YAML.
+func parseCloudInitUserData(value string) (map[string]interface{}, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal([]byte(value), &raw); err != nil {
+		return nil, errors.Annotate(err, "cannot parse cloud-config")
+	}
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	for _, module := range managedCloudInitModules {
+		if _, ok := raw[module]; ok {
+			return nil, errors.Errorf("%s may not be configured; it is managed by Juju", module)
+		}
+	}
+	result := make(map[string]interface{}, len(raw))
+	for k, v := range raw {
+		result[k] = normaliseYAMLValue(v)
+	}
+	if err := checkStringList(result, "packages"); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if runcmd, ok := result["runcmd"]; ok {
+		cmds, ok := runcmd.([]interface{})
+		if !ok {
+			return nil, errors.Errorf("runcmd must be a list, got %T", runcmd)
+		}
+		for i, cmd := range cmds {
+			switch cmd.(type) {
+			case string, []interface{}:
+			default:
+				return nil, errors.Errorf("runcmd entry %d must be a string or a list, got %T", i, cmd)
+			}
+		}
+	}
+	if writeFiles, ok := result["write_files"]; ok {
+		files, ok := writeFiles.([]interface{})
+		if !ok {
+			return nil, errors.Errorf("write_files must be a list, got %T", writeFiles)
+		}
+		for i, f := range files {
+			file, ok := f.(map[string]interface{})
+			if !ok {
+				return nil, errors.Errorf("write_files entry %d must be a mapping, got %T", i, f)
+			}
+			if path, _ := file["path"].(string); path == "" {
+				return nil, errors.Errorf("write_files entry %d has no path", i)
+			}
+		}
+	}
+	return result, nil
+}
+
+// checkStringList returns an error if the named value of attrs is set
+// to anything other than a list of strings.
+func checkStringList(attrs map[string]interface{}, name string) error {
+	value, ok := attrs[name]
+	if !ok {
+		return nil
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return errors.Errorf("%s must be a list of strings, got %T", name, value)
+	}
+	for i, item := range list {
+		if _, ok := item.(string); !ok {
+			return errors.Errorf("%s entry %d must be a string, got %T", name, i, item)
+		}
+	}
+	return nil
+}
+
+// normaliseYAMLValue converts the map[interface{}]interface{} values
+// produced by the YAML decoder, at any depth, to map[string]interface{}.
+func normaliseYAMLValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			result[fmt.Sprint(k)] = normaliseYAMLValue(v)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, v := range value {
+			result[i] = normaliseYAMLValue(v)
+		}
+		return result
+	}
+	return value
+}
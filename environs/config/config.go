@@ -6,6 +6,7 @@ package config
 import (
 	"fmt"
 	"net"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -16,13 +17,16 @@ import (
 	"github.com/juju/utils"
 	"github.com/juju/utils/proxy"
 	"github.com/juju/utils/series"
+	"github.com/juju/utils/set"
 	"github.com/juju/version"
 	"gopkg.in/juju/charmrepo.v2"
 	"gopkg.in/juju/environschema.v1"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/sshkeys"
 	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/juju/osenv"
 	"github.com/juju/juju/logfwd/syslog"
 	"github.com/juju/juju/network"
@@ -46,6 +50,31 @@ const (
 	FwNone = "none"
 )
 
+const (
+	// LoadBalancerNone requests that no load balancers are
+	// provisioned for exposed applications.
+	LoadBalancerNone = "none"
+
+	// LoadBalancerNetwork requests layer 4 load balancers that
+	// forward connections to the exposed ports unchanged.
+	LoadBalancerNetwork = "network"
+
+	// LoadBalancerApplication requests layer 7 load balancers that
+	// forward HTTP requests to the exposed ports.
+	LoadBalancerApplication = "application"
+)
+
+const (
+	// OSHardeningNone requests that no OS hardening is applied to
+	// provisioned machines.
+	OSHardeningNone = "none"
+
+	// OSHardeningCISLevel1 requests a baseline based on the CIS level 1
+	// server benchmark: kernel network and memory protections, auditd,
+	// and a restricted SSH daemon configuration.
+	OSHardeningCISLevel1 = "cis-level1"
+)
+
 // TODO(katco-): Please grow this over time.
 // Centralized place to store values of config keys. This transitions
 // mistakes in referencing key-values to a compile-time error.
@@ -72,12 +101,29 @@ const (
 	// ProvisionerHarvestModeKey stores the key for this setting.
 	ProvisionerHarvestModeKey = "provisioner-harvest-mode"
 
+	// ProvisionerHarvestDryRunKey stores the key for this setting.
+	ProvisionerHarvestDryRunKey = "provisioner-harvest-dry-run"
+
+	// ProvisionerHarvestExemptKey stores the key for this setting.
+	ProvisionerHarvestExemptKey = "provisioner-harvest-exempt"
+
 	// AgentStreamKey stores the key for this setting.
 	AgentStreamKey = "agent-stream"
 
 	// AgentMetadataURLKey stores the key for this setting.
 	AgentMetadataURLKey = "agent-metadata-url"
 
+	// AgentMetadataAuthKey is the key for the credentials used to
+	// access agent-metadata-url, of the form "basic <user>:<password>"
+	// or "bearer <token>".
+	AgentMetadataAuthKey = "agent-metadata-auth"
+
+	// ImageMetadataURLsKey is the key for an ordered list of mirrors
+	// holding image metadata. They are searched in turn, after any
+	// image-metadata-url, so that sites without internet access may
+	// run redundant internal mirrors.
+	ImageMetadataURLsKey = "image-metadata-urls"
+
 	// HTTPProxyKey stores the key for this setting.
 	HTTPProxyKey = "http-proxy"
 
@@ -102,6 +148,48 @@ const (
 	// AptNoProxyKey stores the key for this setting.
 	AptNoProxyKey = "apt-no-proxy"
 
+	// SnapHTTPProxyKey stores the key for this setting.
+	SnapHTTPProxyKey = "snap-http-proxy"
+
+	// SnapHTTPSProxyKey stores the key for this setting.
+	SnapHTTPSProxyKey = "snap-https-proxy"
+
+	// SnapStoreProxyKey is the ID of the snap store proxy that machines
+	// fetch snaps through.
+	SnapStoreProxyKey = "snap-store-proxy"
+
+	// SnapStoreAssertionsKey holds the signed assertions that machines
+	// must acknowledge to trust the snap store proxy.
+	SnapStoreAssertionsKey = "snap-store-assertions"
+
+	// ProxyOverridesKey is an optional space-separated list of
+	// app=spec pairs overriding the proxy settings of the named
+	// applications. Each spec is a semicolon-separated list of
+	// http, https, ftp and no-proxy settings, such as
+	// "http=http://proxy:3128;no-proxy=localhost,10.0.0.1".
+	ProxyOverridesKey = "proxy-overrides"
+
+	// CloudInitUserDataKey is the key for cloud-config YAML that is
+	// merged into the cloud-init user data of every machine provisioned
+	// in the model, such as additional packages, write_files and
+	// runcmds. Modules that Juju manages may not be configured.
+	CloudInitUserDataKey = "cloudinit-userdata"
+
+	// OSHardeningProfileKey is the key for the OS hardening baseline
+	// applied to machines when they are provisioned.
+	OSHardeningProfileKey = "os-hardening-profile"
+
+	// InstanceMetadataHardeningKey is the key for whether access to the
+	// cloud's instance metadata service is restricted on provisioned
+	// machines, so that workloads cannot read instance credentials.
+	InstanceMetadataHardeningKey = "instance-metadata-hardening"
+
+	// CustomCACertificatesKey is the key for a list of PEM encoded CA
+	// certificates that are installed into the system trust store of
+	// every machine in the model, so that mirrors and other services
+	// using certificates signed by private CAs are trusted.
+	CustomCACertificatesKey = "custom-ca-certificates"
+
 	// NetBondReconfigureDelay is the key to pass when bridging
 	// the network for containers.
 	NetBondReconfigureDelayKey = "net-bond-reconfigure-delay"
@@ -116,6 +204,13 @@ const (
 	// The default filesystem storage source.
 	StorageDefaultFilesystemSourceKey = "storage-default-filesystem-source"
 
+	// StorageDefaultPoolAttrsKey is an optional space-separated list
+	// of k=v pairs holding default attributes for storage created in
+	// the pools named by the default block and filesystem sources,
+	// such as "volume-type=io1 iops=30". The attributes of a named
+	// pool take precedence over these defaults.
+	StorageDefaultPoolAttrsKey = "storage-default-pool-attrs"
+
 	// ResourceTagsKey is an optional list or space-separated string
 	// of k=v pairs, defining the tags for ResourceTags.
 	ResourceTagsKey = "resource-tags"
@@ -158,10 +253,22 @@ const (
 	// collection can grow to before it is pruned, eg "5M"
 	MaxStatusHistorySize = "max-status-history-size"
 
+	// MaxStatusHistoryPerEntity is the maximum number of status history
+	// entries to keep for each entity when pruning, or 0 for no limit.
+	MaxStatusHistoryPerEntity = "max-status-history-per-entity"
+
+	// StatusHistoryPruneInterval is how often the status history is
+	// pruned, eg "5m".
+	StatusHistoryPruneInterval = "status-history-prune-interval"
+
 	// MaxActionResultsAge is the maximum age of actions to keep when pruning, eg
 	// "72h"
 	MaxActionResultsAge = "max-action-results-age"
 
+	// MaxConfigHistoryAge is the maximum age of model config history
+	// entries to keep when pruning, eg "720h". Zero keeps them all.
+	MaxConfigHistoryAge = "max-config-history-age"
+
 	// MaxActionResultsSize is the maximum size the actions collection can
 	// grow to before it is pruned, eg "5M"
 	MaxActionResultsSize = "max-action-results-size"
@@ -176,6 +283,117 @@ const (
 	// FanConfig defines the configuration for FAN network running in the model.
 	FanConfig = "fan-config"
 
+	// DefaultSpaceKey is the name of the space to which the endpoints
+	// of new applications are bound, unless they are bound otherwise
+	// when the application is deployed.
+	DefaultSpaceKey = "default-space"
+
+	// APIAdvertiseAddressesKey is a comma-separated list of addresses,
+	// each optionally followed by a port, that are always advertised
+	// to the model's agents as API server addresses, eg an external
+	// NAT address. Addresses without a port use the controller's API
+	// port.
+	APIAdvertiseAddressesKey = "api-advertise-addresses"
+
+	// APIAdvertiseExcludeCIDRsKey is a comma-separated list of the
+	// networks, in CIDR notation, whose addresses are never advertised
+	// to the model's agents as API server addresses, eg a management
+	// VLAN.
+	APIAdvertiseExcludeCIDRsKey = "api-advertise-exclude-cidrs"
+
+	// MaintenanceWindow restricts when disruptive automated operations,
+	// such as agent upgrades, may run in the model, eg
+	// "mon-fri 01:00-03:00; sat,sun 22:00-06:00".
+	MaintenanceWindow = "maintenance-window"
+
+	// TimeZone is the IANA time zone in which the model's maintenance
+	// windows are interpreted, eg "Europe/London".
+	TimeZone = "time-zone"
+
+	// SSHKeySourcesKey lists the key server sources, eg "lp:someone
+	// gh:someone", from which keys are imported into the model's
+	// authorized keys and periodically refreshed.
+	SSHKeySourcesKey = "ssh-key-sources"
+
+	// SSHKeyRefreshIntervalKey is how often keys are refreshed from the
+	// sources listed in SSHKeySourcesKey.
+	SSHKeyRefreshIntervalKey = "ssh-key-refresh-interval"
+
+	// SSHAllowKey lists the CIDRs, eg "10.0.0.0/8,192.168.0.0/16",
+	// from which ssh connections to the model's machines are accepted
+	// by the controller's ssh bastion.
+	SSHAllowKey = "ssh-allow"
+
+	// AgentMaxProcsKey is the maximum number of CPUs that each machine
+	// and unit agent in the model may use simultaneously; 0 leaves the
+	// agents free to use every CPU.
+	AgentMaxProcsKey = "agent-max-procs"
+
+	// AgentMemoryLimitKey is the memory, eg "512M", that each machine
+	// and unit agent in the model may use before it restarts itself;
+	// an empty value leaves the agents unlimited.
+	AgentMemoryLimitKey = "agent-memory-limit"
+
+	// ProviderRetryAttemptsKey is the number of times that workers
+	// attempt a failed cloud provider operation before giving up.
+	ProviderRetryAttemptsKey = "provider-retry-attempts"
+
+	// ProviderRetryDelayKey is how long workers wait before retrying a
+	// failed cloud provider operation for the first time. The delay
+	// doubles with each further attempt.
+	ProviderRetryDelayKey = "provider-retry-delay"
+
+	// ProviderRetryMaxDelayKey is the longest that workers wait between
+	// attempts of a failed cloud provider operation.
+	ProviderRetryMaxDelayKey = "provider-retry-max-delay"
+
+	// SlowHookThresholdKey is how long a hook must run for its
+	// execution to be recorded as a slow operation. Zero disables
+	// the recording.
+	SlowHookThresholdKey = "slow-hook-threshold"
+
+	// SlowProviderCallThresholdKey is how long a cloud provider call
+	// must take to be recorded as a slow operation. Zero disables the
+	// recording.
+	SlowProviderCallThresholdKey = "slow-provider-call-threshold"
+
+	// LoadBalancerTypeKey is the type of load balancer provisioned
+	// for the exposed endpoints of the model's applications: "none",
+	// "network" or "application".
+	LoadBalancerTypeKey = "load-balancer-type"
+
+	// LoadBalancerHealthCheckPathKey is the HTTP request path used to
+	// check the health of the instances behind application load
+	// balancers.
+	LoadBalancerHealthCheckPathKey = "load-balancer-health-check-path"
+
+	// LoadBalancerHealthCheckIntervalKey is the time between the
+	// health checks made by load balancers.
+	LoadBalancerHealthCheckIntervalKey = "load-balancer-health-check-interval"
+
+	// LoadBalancerHealthCheckThresholdKey is the number of consecutive
+	// health checks that must succeed or fail to change the health of
+	// an instance behind a load balancer.
+	LoadBalancerHealthCheckThresholdKey = "load-balancer-health-check-threshold"
+
+	// DNSProviderKey is the name of the DNS provider that manages the
+	// records of exposed applications with a hostname annotation.
+	// Records are not managed if it is empty.
+	DNSProviderKey = "dns-provider"
+
+	// DNSZoneKey is the DNS zone, such as "example.com", in which
+	// records are managed for exposed applications.
+	DNSZoneKey = "dns-zone"
+
+	// DNSTTLKey is the time to live, in seconds, of the records
+	// managed for exposed applications.
+	DNSTTLKey = "dns-ttl"
+
+	// FeaturesKey lists the feature flags, eg "image-metadata",
+	// enabled for the model. Only the flags in feature.ModelFlags
+	// may be enabled.
+	FeaturesKey = "features"
+
 	//
 	// Deprecated Settings Attributes
 	//
@@ -269,6 +487,10 @@ type Config struct {
 	// unknown holds the other attributes that are passed in (aka UnknownAttrs).
 	// the union of these two are AllAttrs
 	defined, unknown map[string]interface{}
+
+	// sources holds the source of each attribute; see
+	// AllAttrsWithSource.
+	sources map[string]string
 }
 
 // Defaulting is a value that specifies whether a configuration
@@ -295,7 +517,15 @@ const (
 //     ~/.local/share/juju/<name>-private-key.pem
 //
 // if $XDG_DATA_HOME is defined it will be used instead of ~/.local/share
+//
+// Deprecated attributes are replaced by the attributes that rename
+// them, and a warning is logged for each.
 func New(withDefaults Defaulting, attrs map[string]interface{}) (*Config, error) {
+	for _, warning := range DeprecationWarnings(attrs) {
+		logger.Warningf("%s", warning)
+	}
+	attrs = ProcessDeprecatedAttributes(attrs)
+
 	checker := noDefaultsChecker
 	if withDefaults {
 		checker = withDefaultsChecker
@@ -323,6 +553,15 @@ func New(withDefaults Defaulting, attrs map[string]interface{}) (*Config, error)
 			c.unknown[k] = v
 		}
 	}
+	// Attributes not supplied were filled in from Juju's defaults.
+	c.sources = make(map[string]string)
+	for k := range c.AllAttrs() {
+		if _, ok := attrs[k]; ok {
+			c.sources[k] = JujuModelConfigSource
+		} else {
+			c.sources[k] = JujuDefaultSource
+		}
+	}
 	return c, nil
 }
 
@@ -333,12 +572,71 @@ const (
 	// DefaultStatusHistorySize is the default value for MaxStatusHistorySize.
 	DefaultStatusHistorySize = "5G"
 
+	// DefaultStatusHistoryPerEntity is the default value for
+	// MaxStatusHistoryPerEntity: the history of each entity is only
+	// limited by the age and size of the whole status history.
+	DefaultStatusHistoryPerEntity = 0
+
+	// DefaultStatusHistoryPruneInterval is the default value for
+	// StatusHistoryPruneInterval.
+	DefaultStatusHistoryPruneInterval = "5m"
+
+	// MinStatusHistoryPruneInterval and MaxStatusHistoryPruneInterval
+	// bound the value of StatusHistoryPruneInterval.
+	MinStatusHistoryPruneInterval = 30 * time.Second
+	MaxStatusHistoryPruneInterval = 24 * time.Hour
+
 	// DefaultUpdateStatusHookInterval is the default value for UpdateStatusHookInterval
 	DefaultUpdateStatusHookInterval = "5m"
 
 	DefaultActionResultsAge = "336h" // 2 weeks
 
+	// DefaultConfigHistoryAge is the default value for MaxConfigHistoryAge.
+	DefaultConfigHistoryAge = "2160h" // 90 days
+
 	DefaultActionResultsSize = "5G"
+
+	// DefaultSSHKeyRefreshInterval is the default value for
+	// SSHKeyRefreshIntervalKey.
+	DefaultSSHKeyRefreshInterval = "24h"
+
+	// DefaultSSHAllow is the default value for SSHAllowKey.
+	DefaultSSHAllow = "0.0.0.0/0,::/0"
+
+	// DefaultProviderRetryAttempts is the default value for
+	// ProviderRetryAttemptsKey.
+	DefaultProviderRetryAttempts = 10
+
+	// DefaultProviderRetryDelay is the default value for
+	// ProviderRetryDelayKey.
+	DefaultProviderRetryDelay = "10s"
+
+	// DefaultProviderRetryMaxDelay is the default value for
+	// ProviderRetryMaxDelayKey.
+	DefaultProviderRetryMaxDelay = "5m"
+
+	// DefaultSlowHookThreshold is the default value for
+	// SlowHookThresholdKey.
+	DefaultSlowHookThreshold = "1m"
+
+	// DefaultSlowProviderCallThreshold is the default value for
+	// SlowProviderCallThresholdKey.
+	DefaultSlowProviderCallThreshold = "30s"
+
+	// DefaultLoadBalancerHealthCheckPath is the default value for
+	// LoadBalancerHealthCheckPathKey.
+	DefaultLoadBalancerHealthCheckPath = "/"
+
+	// DefaultLoadBalancerHealthCheckInterval is the default value for
+	// LoadBalancerHealthCheckIntervalKey.
+	DefaultLoadBalancerHealthCheckInterval = "10s"
+
+	// DefaultLoadBalancerHealthCheckThreshold is the default value for
+	// LoadBalancerHealthCheckThresholdKey.
+	DefaultLoadBalancerHealthCheckThreshold = 3
+
+	// DefaultDNSTTL is the default value for DNSTTLKey.
+	DefaultDNSTTL = 300
 )
 
 var defaultConfigValues = map[string]interface{}{
@@ -404,10 +702,13 @@ var defaultConfigValues = map[string]interface{}{
 	"apt-mirror":     "",
 
 	// Status history settings
-	MaxStatusHistoryAge:  DefaultStatusHistoryAge,
-	MaxStatusHistorySize: DefaultStatusHistorySize,
-	MaxActionResultsAge:  DefaultActionResultsAge,
-	MaxActionResultsSize: DefaultActionResultsSize,
+	MaxStatusHistoryAge:        DefaultStatusHistoryAge,
+	MaxStatusHistorySize:       DefaultStatusHistorySize,
+	MaxStatusHistoryPerEntity:  DefaultStatusHistoryPerEntity,
+	StatusHistoryPruneInterval: DefaultStatusHistoryPruneInterval,
+	MaxActionResultsAge:        DefaultActionResultsAge,
+	MaxActionResultsSize:       DefaultActionResultsSize,
+	MaxConfigHistoryAge:        DefaultConfigHistoryAge,
 }
 
 // ConfigDefaults returns the config default values
@@ -440,16 +741,29 @@ func (c *Config) ensureUnitLogging() error {
 	return nil
 }
 
-// ProcessDeprecatedAttributes gathers any deprecated attributes in attrs and adds or replaces
-// them with new name value pairs for the replacement attrs.
-// Ths ensures that older versions of Juju which require that deprecated
-// attribute values still be used will work as expected.
+// ProcessDeprecatedAttributes returns a copy of attrs in which each
+// deprecated attribute is replaced by the attribute that renames it,
+// as described by DeprecatedAttributes. If both are set, the value of
+// the new attribute is kept.
 func ProcessDeprecatedAttributes(attrs map[string]interface{}) map[string]interface{} {
 	processedAttrs := make(map[string]interface{}, len(attrs))
 	for k, v := range attrs {
 		processedAttrs[k] = v
 	}
-	// No deprecated attributes at the moment.
+	for _, attr := range deprecatedAttributes {
+		v, ok := processedAttrs[attr.OldKey]
+		if !ok {
+			continue
+		}
+		delete(processedAttrs, attr.OldKey)
+		if _, ok := processedAttrs[attr.NewKey]; ok {
+			continue
+		}
+		if attr.Transform != nil {
+			v = attr.Transform(v)
+		}
+		processedAttrs[attr.NewKey] = v
+	}
 	return processedAttrs
 }
 
@@ -457,9 +771,10 @@ func ProcessDeprecatedAttributes(attrs map[string]interface{}) map[string]interf
 func CoerceForStorage(attrs map[string]interface{}) map[string]interface{} {
 	coercedAttrs := make(map[string]interface{}, len(attrs))
 	for attrName, attrValue := range attrs {
-		if attrName == ResourceTagsKey {
-			// Resource Tags are specified by the user as a string but transformed
-			// to a map when config is parsed. We want to store as a string.
+		if attrName == ResourceTagsKey || attrName == ProxyOverridesKey || attrName == StorageDefaultPoolAttrsKey {
+			// Resource Tags, proxy overrides and default pool attributes
+			// are specified by the user as a string but transformed to a
+			// map when config is parsed. We want to store as a string.
 			var tagsSlice []string
 			if tags, ok := attrValue.(map[string]string); ok {
 				for resKey, resValue := range tags {
@@ -475,8 +790,11 @@ func CoerceForStorage(attrs map[string]interface{}) map[string]interface{} {
 
 // Validate ensures that config is a valid configuration.  If old is not nil,
 // it holds the previous environment configuration for consideration when
-// validating changes.
+// validating changes. Every check is made, and an invalid configuration
+// results in a *ValidationError describing all that is wrong with it.
 func Validate(cfg, old *Config) error {
+	errs := &ValidationError{}
+
 	// Check that all other fields that have been specified are non-empty,
 	// unless they're allowed to be empty for backward compatibility,
 	for attr, val := range cfg.defined {
@@ -484,81 +802,223 @@ func Validate(cfg, old *Config) error {
 			continue
 		}
 		if !allowEmpty(attr) {
-			return fmt.Errorf("empty %s in model configuration", attr)
+			errs.add(attr, fmt.Errorf("empty %s in model configuration", attr))
 		}
 	}
 
 	modelName := cfg.asString(NameKey)
 	if modelName == "" {
-		return errors.New("empty name in model configuration")
-	}
-	if !names.IsValidModelName(modelName) {
-		return fmt.Errorf("%q is not a valid name: model names may only contain lowercase letters, digits and hyphens", modelName)
+		errs.add(NameKey, errors.New("empty name in model configuration"))
+	} else if !names.IsValidModelName(modelName) {
+		errs.add(NameKey, fmt.Errorf("%q is not a valid name: model names may only contain lowercase letters, digits and hyphens", modelName))
 	}
 
 	// Check that the agent version parses ok if set explicitly; otherwise leave
 	// it alone.
 	if v, ok := cfg.defined[AgentVersionKey].(string); ok {
 		if _, err := version.Parse(v); err != nil {
-			return fmt.Errorf("invalid agent version in model configuration: %q", v)
+			errs.add(AgentVersionKey, fmt.Errorf("invalid agent version in model configuration: %q", v))
 		}
 	}
 
 	// If the logging config is set, make sure it is valid.
 	if v, ok := cfg.defined["logging-config"].(string); ok {
 		if _, err := loggo.ParseConfigString(v); err != nil {
-			return err
+			errs.add("logging-config", err)
 		}
 	}
 
 	if lfCfg, ok := cfg.LogFwdSyslog(); ok {
 		if err := lfCfg.Validate(); err != nil {
-			return errors.Annotate(err, "invalid syslog forwarding config")
+			errs.add("", errors.Annotate(err, "invalid syslog forwarding config"))
 		}
 	}
 
 	if uuid := cfg.UUID(); !utils.IsValidUUIDString(uuid) {
-		return errors.Errorf("uuid: expected UUID, got string(%q)", uuid)
+		errs.add(UUIDKey, errors.Errorf("uuid: expected UUID, got string(%q)", uuid))
 	}
 
 	// Ensure the resource tags have the expected k=v format.
 	if _, err := cfg.resourceTags(); err != nil {
-		return errors.Annotate(err, "validating resource tags")
+		errs.add(ResourceTagsKey, errors.Annotate(err, "validating resource tags"))
+	}
+
+	if _, err := cfg.proxyOverrides(); err != nil {
+		errs.add(ProxyOverridesKey, errors.Annotate(err, "validating proxy overrides"))
+	}
+
+	if _, err := parseCloudInitUserData(cfg.asString(CloudInitUserDataKey)); err != nil {
+		errs.add(CloudInitUserDataKey, errors.Annotate(err, "invalid cloudinit-userdata"))
+	}
+
+	if err := validateCACertificates(cfg.CustomCACertificates()); err != nil {
+		errs.add(CustomCACertificatesKey, errors.Annotate(err, "invalid custom-ca-certificates"))
+	}
+
+	if v := cfg.asString(AgentMetadataAuthKey); v != "" {
+		if _, err := ParseHTTPAuth(v); err != nil {
+			errs.add(AgentMetadataAuthKey, errors.Annotate(err, "invalid agent-metadata-auth"))
+		}
+	}
+
+	if err := validateImageMetadataURLs(cfg.ImageMetadataURLs()); err != nil {
+		errs.add(ImageMetadataURLsKey, errors.Annotate(err, "invalid image-metadata-urls"))
+	}
+
+	if _, err := ParseNoProxy(cfg.NoProxy()); err != nil {
+		errs.add(NoProxyKey, errors.Annotate(err, "invalid no-proxy"))
+	}
+
+	if err := validateSnapStore(cfg.SnapStoreProxy(), cfg.SnapStoreAssertions()); err != nil {
+		errs.add(SnapStoreAssertionsKey, errors.Trace(err))
 	}
 
 	if v, ok := cfg.defined[MaxStatusHistoryAge].(string); ok {
 		if _, err := time.ParseDuration(v); err != nil {
-			return errors.Annotate(err, "invalid max status history age in model configuration")
+			errs.add(MaxStatusHistoryAge, errors.Annotate(err, "invalid max status history age in model configuration"))
 		}
 	}
 
 	if v, ok := cfg.defined[MaxStatusHistorySize].(string); ok {
 		if _, err := utils.ParseSize(v); err != nil {
-			return errors.Annotate(err, "invalid max status history size in model configuration")
+			errs.add(MaxStatusHistorySize, errors.Annotate(err, "invalid max status history size in model configuration"))
+		}
+	}
+
+	if v, ok := cfg.defined[MaxStatusHistoryPerEntity].(int); ok && v < 0 {
+		errs.add(MaxStatusHistoryPerEntity, errors.Errorf("invalid max status history per entity in model configuration: %d is negative", v))
+	}
+
+	if v, ok := cfg.defined[StatusHistoryPruneInterval].(string); ok {
+		if f, err := time.ParseDuration(v); err != nil {
+			errs.add(StatusHistoryPruneInterval, errors.Annotate(err, "invalid status history prune interval in model configuration"))
+		} else if f < MinStatusHistoryPruneInterval {
+			errs.add(StatusHistoryPruneInterval, errors.Errorf("status history prune interval %v cannot be less than %v", f, MinStatusHistoryPruneInterval))
+		} else if f > MaxStatusHistoryPruneInterval {
+			errs.add(StatusHistoryPruneInterval, errors.Errorf("status history prune interval %v cannot be greater than %v", f, MaxStatusHistoryPruneInterval))
+		}
+	}
+
+	if v, ok := cfg.defined[MaxConfigHistoryAge].(string); ok {
+		if _, err := time.ParseDuration(v); err != nil {
+			errs.add(MaxConfigHistoryAge, errors.Annotate(err, "invalid max config history age in model configuration"))
 		}
 	}
 
 	if v, ok := cfg.defined[MaxActionResultsAge].(string); ok {
 		if _, err := time.ParseDuration(v); err != nil {
-			return errors.Annotate(err, "invalid max action age in model configuration")
+			errs.add(MaxActionResultsAge, errors.Annotate(err, "invalid max action age in model configuration"))
 		}
 	}
 
 	if v, ok := cfg.defined[MaxActionResultsSize].(string); ok {
 		if _, err := utils.ParseSize(v); err != nil {
-			return errors.Annotate(err, "invalid max action size in model configuration")
+			errs.add(MaxActionResultsSize, errors.Annotate(err, "invalid max action size in model configuration"))
 		}
 	}
 
 	if v, ok := cfg.defined[UpdateStatusHookInterval].(string); ok {
 		if f, err := time.ParseDuration(v); err != nil {
-			return errors.Annotate(err, "invalid update status hook interval in model configuration")
+			errs.add(UpdateStatusHookInterval, errors.Annotate(err, "invalid update status hook interval in model configuration"))
 		} else {
 			if f < 1*time.Minute {
-				return errors.Annotatef(err, "update status hook frequency %v cannot be less than 1m", f)
+				errs.add(UpdateStatusHookInterval, errors.Annotatef(err, "update status hook frequency %v cannot be less than 1m", f))
 			}
 			if f > 60*time.Minute {
-				return errors.Annotatef(err, "update status hook frequency %v cannot be greater than 60m", f)
+				errs.add(UpdateStatusHookInterval, errors.Annotatef(err, "update status hook frequency %v cannot be greater than 60m", f))
+			}
+		}
+	}
+
+	if v, ok := cfg.defined[MaintenanceWindow].(string); ok {
+		if _, err := ParseMaintenanceWindows(v); err != nil {
+			errs.add(MaintenanceWindow, errors.Annotate(err, "invalid maintenance window in model configuration"))
+		}
+	}
+
+	if v, ok := cfg.defined[TimeZone].(string); ok {
+		if _, err := time.LoadLocation(v); err != nil {
+			errs.add(TimeZone, errors.Annotate(err, "invalid time zone in model configuration"))
+		}
+	}
+
+	for _, source := range cfg.SSHKeySources() {
+		if err := sshkeys.ValidateSource(source); err != nil {
+			errs.add(SSHKeySourcesKey, errors.Annotate(err, "invalid ssh key sources in model configuration"))
+		}
+	}
+
+	if v, ok := cfg.defined[SSHKeyRefreshIntervalKey].(string); ok {
+		if d, err := time.ParseDuration(v); err != nil {
+			errs.add(SSHKeyRefreshIntervalKey, errors.Annotate(err, "invalid ssh key refresh interval in model configuration"))
+		} else if d < time.Minute {
+			errs.add(SSHKeyRefreshIntervalKey, errors.Errorf("ssh key refresh interval %v cannot be less than 1m", d))
+		}
+	}
+
+	if v, ok := cfg.defined[SSHAllowKey].(string); ok {
+		for _, cidr := range strings.Split(v, ",") {
+			if _, _, err := net.ParseCIDR(strings.TrimSpace(cidr)); err != nil {
+				errs.add(SSHAllowKey, errors.Annotatef(err, "invalid ssh-allow CIDR: %v", cidr))
+			}
+		}
+	}
+
+	if v, ok := cfg.defined[AgentMaxProcsKey].(int); ok && v < 0 {
+		errs.add(AgentMaxProcsKey, errors.Errorf("agent max procs %d cannot be negative", v))
+	}
+
+	if v, ok := cfg.defined[AgentMemoryLimitKey].(string); ok && v != "" {
+		if _, err := utils.ParseSize(v); err != nil {
+			errs.add(AgentMemoryLimitKey, errors.Annotate(err, "invalid agent memory limit in model configuration"))
+		}
+	}
+
+	if v, ok := cfg.defined[ProviderRetryAttemptsKey].(int); ok && v < 1 {
+		errs.add(ProviderRetryAttemptsKey, errors.Errorf("provider retry attempts %d must be at least 1", v))
+	}
+
+	if v, ok := cfg.defined[LoadBalancerHealthCheckPathKey].(string); ok && !strings.HasPrefix(v, "/") {
+		errs.add(LoadBalancerHealthCheckPathKey, errors.Errorf("load balancer health check path %q must start with /", v))
+	}
+
+	if v, ok := cfg.defined[LoadBalancerHealthCheckThresholdKey].(int); ok && v < 1 {
+		errs.add(LoadBalancerHealthCheckThresholdKey, errors.Errorf("load balancer health check threshold %d must be at least 1", v))
+	}
+
+	if cfg.DNSProvider() != "" && cfg.DNSZone() == "" {
+		errs.add(DNSZoneKey, errors.Errorf("%s must be set to use DNS provider %q", DNSZoneKey, cfg.DNSProvider()))
+	}
+
+	if v, ok := cfg.defined[DNSTTLKey].(int); ok && v < 1 {
+		errs.add(DNSTTLKey, errors.Errorf("DNS TTL %d must be at least 1", v))
+	}
+
+	if v, ok := cfg.defined[FeaturesKey].(string); ok {
+		supported := set.NewStrings(feature.ModelFlags...)
+		for _, flag := range splitFeatures(v) {
+			if !supported.Contains(flag) {
+				errs.add(FeaturesKey, errors.NotSupportedf("feature %q", flag))
+			}
+		}
+	}
+
+	for _, key := range []string{ProviderRetryDelayKey, ProviderRetryMaxDelayKey, LoadBalancerHealthCheckIntervalKey} {
+		if v, ok := cfg.defined[key].(string); ok {
+			if d, err := time.ParseDuration(v); err != nil {
+				errs.add(key, errors.Annotatef(err, "invalid %s in model configuration", key))
+			} else if d <= 0 {
+				errs.add(key, errors.Errorf("%s %v must be positive", key, d))
+			}
+		}
+	}
+
+	for _, key := range []string{SlowHookThresholdKey, SlowProviderCallThresholdKey} {
+		if v, ok := cfg.defined[key].(string); ok {
+			if d, err := time.ParseDuration(v); err != nil {
+				errs.add(key, errors.Annotatef(err, "invalid %s in model configuration", key))
+			} else if d < 0 {
+				errs.add(key, errors.Errorf("%s %v must not be negative", key, d))
 			}
 		}
 	}
@@ -566,11 +1026,12 @@ func Validate(cfg, old *Config) error {
 	if v, ok := cfg.defined[EgressSubnets].(string); ok && v != "" {
 		cidrs := strings.Split(v, ",")
 		for _, cidr := range cidrs {
-			if _, _, err := net.ParseCIDR(strings.TrimSpace(cidr)); err != nil {
-				return errors.Annotatef(err, "invalid egress subnet: %v", cidr)
-			}
-			if cidr == "0.0.0.0/0" {
-				return errors.Errorf("CIDR %q not allowed", cidr)
+			cidr = strings.TrimSpace(cidr)
+			if _, ipNet, err := net.ParseCIDR(cidr); err != nil {
+				errs.add(EgressSubnets, errors.Annotatef(err, "invalid egress subnet: %v", cidr))
+			} else if ones, _ := ipNet.Mask.Size(); ones == 0 {
+				// Neither 0.0.0.0/0 nor ::/0 identify the model's traffic.
+				errs.add(EgressSubnets, errors.Errorf("CIDR %q not allowed", cidr))
 			}
 		}
 	}
@@ -578,7 +1039,23 @@ func Validate(cfg, old *Config) error {
 	if v, ok := cfg.defined[FanConfig].(string); ok && v != "" {
 		_, err := network.ParseFanConfig(v)
 		if err != nil {
-			return err
+			errs.add(FanConfig, err)
+		}
+	}
+
+	if v, ok := cfg.defined[DefaultSpaceKey].(string); ok && v != "" && !names.IsValidSpace(v) {
+		errs.add(DefaultSpaceKey, errors.Errorf("invalid default-space %q", v))
+	}
+
+	for _, addr := range cfg.APIAdvertiseAddresses() {
+		if _, err := network.ParseAdvertisedAddress(addr, 1); err != nil {
+			errs.add(APIAdvertiseAddressesKey, errors.Annotatef(err, "invalid %s", APIAdvertiseAddressesKey))
+		}
+	}
+
+	for _, cidr := range cfg.APIAdvertiseExcludeCIDRs() {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			errs.add(APIAdvertiseExcludeCIDRsKey, errors.Annotatef(err, "invalid %s", APIAdvertiseExcludeCIDRsKey))
 		}
 	}
 
@@ -586,13 +1063,13 @@ func Validate(cfg, old *Config) error {
 		switch v {
 		case "fan":
 			if cfg, err := cfg.FanConfig(); err != nil || cfg == nil {
-				return errors.New("container-networking-method cannot be set to 'fan' without fan-config set")
+				errs.add(ContainerNetworkingMethod, errors.New("container-networking-method cannot be set to 'fan' without fan-config set"))
 			}
-		case "provider": // TODO(wpk) FIXME we should check that the provider supports this setting!
+		case "provider": // Checked against the provider by environs.ValidateContainerNetworkingMethod.
 		case "local":
 		case "": // We'll try to autoconfigure it
 		default:
-			return fmt.Errorf("Invalid value for container-networking-method - %v", v)
+			errs.add(ContainerNetworkingMethod, fmt.Errorf("Invalid value for container-networking-method - %v", v))
 		}
 	}
 	// Check the immutable config values.  These can't change
@@ -603,17 +1080,21 @@ func Validate(cfg, old *Config) error {
 				continue
 			}
 			if newv := cfg.defined[attr]; newv != oldv {
-				return fmt.Errorf("cannot change %s from %#v to %#v", attr, oldv, newv)
+				errs.add(attr, newImmutableAttributeError(attr, oldv, newv))
 			}
 		}
 		if _, oldFound := old.AgentVersion(); oldFound {
 			if _, newFound := cfg.AgentVersion(); !newFound {
-				return errors.New("cannot clear agent-version")
+				errs.add(AgentVersionKey, errors.New("cannot clear agent-version"))
 			}
 		}
 	}
 
-	cfg.defined = ProcessDeprecatedAttributes(cfg.defined)
+	runValidators(cfg, old, errs)
+	if len(errs.Errors) > 0 {
+		errs.sort()
+		return errs
+	}
 	return nil
 }
 
@@ -724,6 +1205,88 @@ func (c *Config) ProxySettings() proxy.Settings {
 	}
 }
 
+// ApplicationProxySettings returns the proxy settings in effect for the
+// units of the named application: the model's proxy settings, with
+// those set in the application's proxy override taking precedence.
+func (c *Config) ApplicationProxySettings(application string) proxy.Settings {
+	settings := c.ProxySettings()
+	override, ok := c.ProxyOverrides()[application]
+	if !ok {
+		return settings
+	}
+	if override.Http != "" {
+		settings.Http = override.Http
+	}
+	if override.Https != "" {
+		settings.Https = override.Https
+	}
+	if override.Ftp != "" {
+		settings.Ftp = override.Ftp
+	}
+	if override.NoProxy != "" {
+		settings.NoProxy = override.NoProxy
+	}
+	return settings
+}
+
+// ProxyOverrides returns the proxy settings overridden for each
+// application, keyed on application name. Only the non-empty settings
+// of each override apply.
+func (c *Config) ProxyOverrides() map[string]proxy.Settings {
+	overrides, err := c.proxyOverrides()
+	if err != nil {
+		panic(err) // should be prevented by Validate
+	}
+	return overrides
+}
+
+func (c *Config) proxyOverrides() (map[string]proxy.Settings, error) {
+	v, ok := c.defined[ProxyOverridesKey].(map[string]string)
+	if !ok {
+		return nil, nil
+	}
+	overrides := make(map[string]proxy.Settings, len(v))
+	for application, spec := range v {
+		if !names.IsValidApplication(application) {
+			return nil, errors.NotValidf("application name %q", application)
+		}
+		settings, err := parseProxyOverride(spec)
+		if err != nil {
+			return nil, errors.Annotatef(err, "application %q", application)
+		}
+		overrides[application] = settings
+	}
+	return overrides, nil
+}
+
+// parseProxyOverride parses a proxy override spec, as described for
+// ProxyOverridesKey.
+func parseProxyOverride(spec string) (proxy.Settings, error) {
+	var settings proxy.Settings
+	for _, field := range strings.Split(spec, ";") {
+		if field == "" {
+			continue
+		}
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return proxy.Settings{}, errors.Errorf("expected key=value, got %q", field)
+		}
+		switch value := parts[1]; parts[0] {
+		case "http":
+			settings.Http = value
+		case "https":
+			settings.Https = value
+		case "ftp":
+			settings.Ftp = value
+		case "no-proxy":
+			settings.NoProxy = value
+		default:
+			return proxy.Settings{}, errors.Errorf("unknown proxy setting %q", parts[0])
+		}
+	}
+	return settings, nil
+}
+
 // HTTPProxy returns the http proxy for the environment.
 func (c *Config) HTTPProxy() string {
 	return c.asString(HTTPProxyKey)
@@ -744,6 +1307,13 @@ func (c *Config) NoProxy() string {
 	return c.asString(NoProxyKey)
 }
 
+// NoProxyEntries returns the parsed 'no-proxy' for the environment.
+func (c *Config) NoProxyEntries() NoProxyEntries {
+	// The value has already been validated.
+	entries, _ := ParseNoProxy(c.NoProxy())
+	return entries
+}
+
 func (c *Config) getWithFallback(key, fallback string) string {
 	value := c.asString(key)
 	if value == "" {
@@ -793,6 +1363,98 @@ func (c *Config) AptNoProxy() string {
 	return c.getWithFallback(AptNoProxyKey, NoProxyKey)
 }
 
+// SnapProxySettings returns the http and https proxy settings used by
+// snapd.
+func (c *Config) SnapProxySettings() proxy.Settings {
+	return proxy.Settings{
+		Http:  c.SnapHTTPProxy(),
+		Https: c.SnapHTTPSProxy(),
+	}
+}
+
+// SnapHTTPProxy returns the snap http proxy for the environment.
+// Falls back to the default http-proxy if not specified.
+func (c *Config) SnapHTTPProxy() string {
+	return addSchemeIfMissing("http", c.getWithFallback(SnapHTTPProxyKey, HTTPProxyKey))
+}
+
+// SnapHTTPSProxy returns the snap https proxy for the environment.
+// Falls back to the default https-proxy if not specified.
+func (c *Config) SnapHTTPSProxy() string {
+	return addSchemeIfMissing("https", c.getWithFallback(SnapHTTPSProxyKey, HTTPSProxyKey))
+}
+
+// SnapStoreProxy returns the ID of the snap store proxy that machines
+// fetch snaps through, or the empty string if snaps are fetched from
+// the snap store.
+func (c *Config) SnapStoreProxy() string {
+	return c.asString(SnapStoreProxyKey)
+}
+
+// SnapStoreAssertions returns the signed assertions that machines
+// must acknowledge to trust the snap store proxy.
+func (c *Config) SnapStoreAssertions() string {
+	return c.asString(SnapStoreAssertionsKey)
+}
+
+// validateSnapStore checks that a snap store proxy and its assertions
+// are configured together, and that the assertions include the store
+// assertion for the proxy.
+func validateSnapStore(storeID, assertions string) error {
+	if storeID == "" && assertions == "" {
+		return nil
+	}
+	if assertions == "" {
+		return errors.Errorf("%s must be set to use snap store proxy %q", SnapStoreAssertionsKey, storeID)
+	}
+	if storeID == "" {
+		return errors.Errorf("%s must be set to use %s", SnapStoreProxyKey, SnapStoreAssertionsKey)
+	}
+	parsed, err := parseSnapAssertions(assertions)
+	if err != nil {
+		return errors.Annotatef(err, "invalid %s", SnapStoreAssertionsKey)
+	}
+	for _, assertion := range parsed {
+		if assertion["type"] == "store" && assertion["store"] == storeID {
+			return nil
+		}
+	}
+	return errors.Errorf("%s has no store assertion for snap store proxy %q", SnapStoreAssertionsKey, storeID)
+}
+
+// CloudInitUserData returns the cloud-config that is merged into the
+// cloud-init user data of every machine provisioned in the model, or
+// nil if there is none. Nested mappings are of type
+// map[string]interface{}.
+func (c *Config) CloudInitUserData() map[string]interface{} {
+	// The value has been validated, so cannot fail to parse.
+	userData, _ := parseCloudInitUserData(c.asString(CloudInitUserDataKey))
+	return userData
+}
+
+// OSHardeningProfile returns the OS hardening baseline applied to
+// newly provisioned machines.
+func (c *Config) OSHardeningProfile() string {
+	if value, ok := c.defined[OSHardeningProfileKey].(string); ok && value != "" {
+		return value
+	}
+	return OSHardeningNone
+}
+
+// InstanceMetadataHardening reports whether access to the cloud's
+// instance metadata service is restricted on newly provisioned machines.
+func (c *Config) InstanceMetadataHardening() bool {
+	v, _ := c.defined[InstanceMetadataHardeningKey].(bool)
+	return v
+}
+
+// CustomCACertificates returns the PEM encoded CA certificates that
+// are installed into the system trust store of every machine in the
+// model. Each entry may hold more than one certificate.
+func (c *Config) CustomCACertificates() []string {
+	return stringList(c.defined[CustomCACertificatesKey])
+}
+
 // AptMirror sets the apt mirror for the environment.
 func (c *Config) AptMirror() string {
 	return c.asString("apt-mirror")
@@ -864,6 +1526,18 @@ func (c *Config) AgentMetadataURL() (string, bool) {
 	return "", false
 }
 
+// AgentMetadataAuth returns the credentials with which requests to
+// agent-metadata-url are authenticated, and whether they have been set.
+func (c *Config) AgentMetadataAuth() (HTTPAuth, bool) {
+	v := c.asString(AgentMetadataAuthKey)
+	if v == "" {
+		return HTTPAuth{}, false
+	}
+	// The value has been validated, so cannot fail to parse.
+	auth, _ := ParseHTTPAuth(v)
+	return auth, true
+}
+
 // ImageMetadataURL returns the URL at which the metadata used to locate image ids is located,
 // and wether it has been set.
 func (c *Config) ImageMetadataURL() (string, bool) {
@@ -873,6 +1547,37 @@ func (c *Config) ImageMetadataURL() (string, bool) {
 	return "", false
 }
 
+// ImageMetadataURLs returns the mirrors at which the metadata used to
+// locate image ids is located, in the order they are to be tried.
+func (c *Config) ImageMetadataURLs() []string {
+	return stringList(c.defined[ImageMetadataURLsKey])
+}
+
+// validateImageMetadataURLs checks that each image metadata mirror is a
+// directory or an http, https or file URL, and is listed only once.
+func validateImageMetadataURLs(urls []string) error {
+	seen := set.NewStrings()
+	for _, u := range urls {
+		if u == "" {
+			return errors.New("empty URL")
+		}
+		parsed, err := url.Parse(u)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		switch parsed.Scheme {
+		case "", "http", "https", "file":
+		default:
+			return errors.Errorf("URL %q: unsupported scheme %q", u, parsed.Scheme)
+		}
+		if seen.Contains(u) {
+			return errors.Errorf("URL %q specified more than once", u)
+		}
+		seen.Add(u)
+	}
+	return nil
+}
+
 // Development returns whether the environment is in development mode.
 func (c *Config) Development() bool {
 	value, _ := c.defined["development"].(bool)
@@ -946,6 +1651,29 @@ func (c *Config) ProvisionerHarvestMode() HarvestMode {
 	}
 }
 
+// ProvisionerHarvestDryRun reports whether the provisioner should only
+// report the instances it would harvest, rather than stopping them.
+func (c *Config) ProvisionerHarvestDryRun() bool {
+	v, _ := c.defined[ProvisionerHarvestDryRunKey].(bool)
+	return v
+}
+
+// ProvisionerHarvestExemptions returns the IDs of the instances that
+// the provisioner must never harvest as unknown instances.
+func (c *Config) ProvisionerHarvestExemptions() []string {
+	raw := c.asString(ProvisionerHarvestExemptKey)
+	if raw == "" {
+		return nil
+	}
+	var result []string
+	for _, id := range strings.Split(raw, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
 // ImageStream returns the simplestreams stream
 // used to identify which image ids to search
 // when starting an instance.
@@ -1004,6 +1732,21 @@ func (c *Config) StorageDefaultFilesystemSource() (string, bool) {
 	return bs, bs != ""
 }
 
+// StorageDefaultPoolAttrs returns the default attributes of storage
+// created in the model's default storage pools, or nil if there are
+// none.
+func (c *Config) StorageDefaultPoolAttrs() map[string]interface{} {
+	v, ok := c.defined[StorageDefaultPoolAttrsKey].(map[string]string)
+	if !ok || len(v) == 0 {
+		return nil
+	}
+	attrs := make(map[string]interface{}, len(v))
+	for k, v := range v {
+		attrs[k] = v
+	}
+	return attrs
+}
+
 // ResourceTags returns a set of tags to set on environment resources
 // that Juju creates and manages, if the provider supports them. These
 // tags have no special meaning to Juju, but may be used for existing
@@ -1045,6 +1788,25 @@ func (c *Config) MaxStatusHistorySizeMB() uint {
 	return uint(val)
 }
 
+// MaxStatusHistoryPerEntity is the maximum number of status history
+// entries kept for each entity when pruning, or 0 if there is no limit.
+func (c *Config) MaxStatusHistoryPerEntity() int {
+	value, _ := c.defined[MaxStatusHistoryPerEntity].(int)
+	return value
+}
+
+// StatusHistoryPruneInterval is how often the status history is pruned.
+func (c *Config) StatusHistoryPruneInterval() time.Duration {
+	// Models created before the setting was introduced don't have it.
+	raw := c.asString(StatusHistoryPruneInterval)
+	if raw == "" {
+		raw = DefaultStatusHistoryPruneInterval
+	}
+	// Value has already been validated.
+	val, _ := time.ParseDuration(raw)
+	return val
+}
+
 func (c *Config) MaxActionResultsAge() time.Duration {
 	// Value has already been validated.
 	val, _ := time.ParseDuration(c.mustString(MaxActionResultsAge))
@@ -1057,6 +1819,20 @@ func (c *Config) MaxActionResultsSizeMB() uint {
 	return uint(val)
 }
 
+// MaxConfigHistoryAge is the maximum age of model config history
+// entries before being pruned. Zero means that they are kept.
+func (c *Config) MaxConfigHistoryAge() time.Duration {
+	// Models created before the history was recorded do not
+	// have the attribute set.
+	raw := c.asString(MaxConfigHistoryAge)
+	if raw == "" {
+		raw = DefaultConfigHistoryAge
+	}
+	// Value has already been validated.
+	val, _ := time.ParseDuration(raw)
+	return val
+}
+
 // UpdateStatusHookInterval is how often to run the charm
 // update-status hook.
 func (c *Config) UpdateStatusHookInterval() time.Duration {
@@ -1095,6 +1871,229 @@ func (c *Config) FanConfig() (network.FanConfig, error) {
 	return network.ParseFanConfig(c.asString(FanConfig))
 }
 
+// DefaultSpace returns the name of the space to which the endpoints of
+// new applications are bound by default, or "" for the default space.
+func (c *Config) DefaultSpace() string {
+	return c.asString(DefaultSpaceKey)
+}
+
+// APIAdvertiseAddresses returns the addresses, each optionally followed
+// by a port, that are always advertised to the model's agents as API
+// server addresses.
+func (c *Config) APIAdvertiseAddresses() []string {
+	return c.asList(APIAdvertiseAddressesKey)
+}
+
+// APIAdvertiseExcludeCIDRs returns the networks whose addresses are never
+// advertised to the model's agents as API server addresses.
+func (c *Config) APIAdvertiseExcludeCIDRs() []string {
+	return c.asList(APIAdvertiseExcludeCIDRsKey)
+}
+
+// asList returns the named comma-separated attribute as a list, leaving
+// out empty items.
+func (c *Config) asList(name string) []string {
+	var result []string
+	for _, item := range strings.Split(c.asString(name), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// MaintenanceWindows returns the windows during which disruptive
+// automated operations may run in the model, in the model's time zone.
+func (c *Config) MaintenanceWindows() MaintenanceWindows {
+	// Values have already been validated.
+	windows, _ := ParseMaintenanceWindows(c.asString(MaintenanceWindow))
+	location, err := time.LoadLocation(c.asString(TimeZone))
+	if err != nil {
+		location = time.UTC
+	}
+	return MaintenanceWindows{
+		Windows:  windows,
+		Location: location,
+	}
+}
+
+// SSHKeySources returns the key server sources from which keys are
+// imported into the model's authorized keys.
+func (c *Config) SSHKeySources() []string {
+	return strings.FieldsFunc(c.asString(SSHKeySourcesKey), func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
+// SSHKeyRefreshInterval returns how often keys are refreshed from the
+// model's ssh key sources.
+func (c *Config) SSHKeyRefreshInterval() time.Duration {
+	// Value has already been validated.
+	d, err := time.ParseDuration(c.asString(SSHKeyRefreshIntervalKey))
+	if err != nil {
+		d, _ = time.ParseDuration(DefaultSSHKeyRefreshInterval)
+	}
+	return d
+}
+
+// SSHAllow returns the CIDRs from which ssh connections to the model's
+// machines are accepted by the controller's ssh bastion.
+func (c *Config) SSHAllow() []string {
+	value := c.asString(SSHAllowKey)
+	if value == "" {
+		value = DefaultSSHAllow
+	}
+	var cidrs []string
+	for _, cidr := range strings.Split(value, ",") {
+		cidrs = append(cidrs, strings.TrimSpace(cidr))
+	}
+	return cidrs
+}
+
+// AgentMaxProcs returns the maximum number of CPUs that each agent in
+// the model may use simultaneously, or 0 if agents may use every CPU.
+func (c *Config) AgentMaxProcs() int {
+	value, _ := c.defined[AgentMaxProcsKey].(int)
+	return value
+}
+
+// AgentMemoryLimitMB returns the memory in MiB that each agent in the
+// model may use before restarting itself, or 0 if there is no limit.
+func (c *Config) AgentMemoryLimitMB() uint64 {
+	// Value has already been validated.
+	val, _ := utils.ParseSize(c.asString(AgentMemoryLimitKey))
+	return val
+}
+
+// ProviderRetryAttempts returns the number of times that workers
+// attempt a failed cloud provider operation before giving up.
+func (c *Config) ProviderRetryAttempts() int {
+	if value, ok := c.defined[ProviderRetryAttemptsKey].(int); ok {
+		return value
+	}
+	return DefaultProviderRetryAttempts
+}
+
+// ProviderRetryDelay returns how long workers wait before retrying a
+// failed cloud provider operation for the first time.
+func (c *Config) ProviderRetryDelay() time.Duration {
+	// Value has already been validated.
+	d, err := time.ParseDuration(c.asString(ProviderRetryDelayKey))
+	if err != nil {
+		d, _ = time.ParseDuration(DefaultProviderRetryDelay)
+	}
+	return d
+}
+
+// ProviderRetryMaxDelay returns the longest that workers wait between
+// attempts of a failed cloud provider operation.
+func (c *Config) ProviderRetryMaxDelay() time.Duration {
+	// Value has already been validated.
+	d, err := time.ParseDuration(c.asString(ProviderRetryMaxDelayKey))
+	if err != nil {
+		d, _ = time.ParseDuration(DefaultProviderRetryMaxDelay)
+	}
+	return d
+}
+
+// SlowHookThreshold returns how long a hook must run for its execution
+// to be recorded as a slow operation. Zero means that hook executions
+// are not recorded.
+func (c *Config) SlowHookThreshold() time.Duration {
+	// Value has already been validated.
+	d, err := time.ParseDuration(c.asString(SlowHookThresholdKey))
+	if err != nil {
+		d, _ = time.ParseDuration(DefaultSlowHookThreshold)
+	}
+	return d
+}
+
+// SlowProviderCallThreshold returns how long a cloud provider call
+// must take to be recorded as a slow operation. Zero means that
+// provider calls are not recorded.
+func (c *Config) SlowProviderCallThreshold() time.Duration {
+	// Value has already been validated.
+	d, err := time.ParseDuration(c.asString(SlowProviderCallThresholdKey))
+	if err != nil {
+		d, _ = time.ParseDuration(DefaultSlowProviderCallThreshold)
+	}
+	return d
+}
+
+// LoadBalancerType returns the type of load balancer provisioned for
+// the exposed endpoints of the model's applications.
+func (c *Config) LoadBalancerType() string {
+	if value, ok := c.defined[LoadBalancerTypeKey].(string); ok {
+		return value
+	}
+	return LoadBalancerNone
+}
+
+// LoadBalancerHealthCheckPath returns the HTTP request path used by
+// load balancer health checks.
+func (c *Config) LoadBalancerHealthCheckPath() string {
+	if value, ok := c.defined[LoadBalancerHealthCheckPathKey].(string); ok {
+		return value
+	}
+	return DefaultLoadBalancerHealthCheckPath
+}
+
+// LoadBalancerHealthCheckInterval returns the time between load
+// balancer health checks.
+func (c *Config) LoadBalancerHealthCheckInterval() time.Duration {
+	// Value has already been validated.
+	d, err := time.ParseDuration(c.asString(LoadBalancerHealthCheckIntervalKey))
+	if err != nil {
+		d, _ = time.ParseDuration(DefaultLoadBalancerHealthCheckInterval)
+	}
+	return d
+}
+
+// LoadBalancerHealthCheckThreshold returns the number of consecutive
+// load balancer health checks that must succeed or fail to change an
+// instance's health.
+func (c *Config) LoadBalancerHealthCheckThreshold() int {
+	if value, ok := c.defined[LoadBalancerHealthCheckThresholdKey].(int); ok {
+		return value
+	}
+	return DefaultLoadBalancerHealthCheckThreshold
+}
+
+// DNSProvider returns the name of the DNS provider that manages the
+// records of exposed applications, or the empty string if records are
+// not managed.
+func (c *Config) DNSProvider() string {
+	value, _ := c.defined[DNSProviderKey].(string)
+	return value
+}
+
+// DNSZone returns the DNS zone in which records are managed for
+// exposed applications, without a trailing dot.
+func (c *Config) DNSZone() string {
+	value, _ := c.defined[DNSZoneKey].(string)
+	return strings.TrimSuffix(value, ".")
+}
+
+// DNSTTL returns the time to live, in seconds, of the records managed
+// for exposed applications.
+func (c *Config) DNSTTL() int {
+	if value, ok := c.defined[DNSTTLKey].(int); ok {
+		return value
+	}
+	return DefaultDNSTTL
+}
+
+// Features returns the feature flags enabled for the model.
+func (c *Config) Features() set.Strings {
+	return set.NewStrings(splitFeatures(c.asString(FeaturesKey))...)
+}
+
+func splitFeatures(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
 // UnknownAttrs returns a copy of the raw configuration attributes
 // that are supposedly specific to the environment type. They could
 // also be wrong attributes, though. Only the specific environment
@@ -1116,13 +2115,52 @@ func (c *Config) AllAttrs() map[string]interface{} {
 	return allAttrs
 }
 
+// AllAttrsWithSource returns a copy of the configuration attributes,
+// each labelled with the source that supplied it. Attributes filled in
+// from Juju's defaults have a source of JujuDefaultSource, and those set
+// on the model JujuModelConfigSource. When the config was created by a
+// DefaultsResolver, inherited attributes have the source of the layer
+// of defaults that supplied them.
+func (c *Config) AllAttrsWithSource() ConfigValues {
+	values := make(ConfigValues)
+	for k, v := range c.AllAttrs() {
+		source, ok := c.sources[k]
+		if !ok {
+			source = JujuModelConfigSource
+		}
+		values[k] = ConfigValue{Value: v, Source: source}
+	}
+	return values
+}
+
+// NewWithSources returns a new configuration holding the given values,
+// without filling in defaults, and records the source of each so that
+// AllAttrsWithSource reports it.
+func NewWithSources(values ConfigValues) (*Config, error) {
+	cfg, err := New(NoDefaults, values.AllAttrs())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for k, v := range values {
+		if _, ok := cfg.sources[k]; ok {
+			cfg.sources[k] = v.Source
+		}
+	}
+	return cfg, nil
+}
+
 // Remove returns a new configuration that has the attributes of c minus attrs.
 func (c *Config) Remove(attrs []string) (*Config, error) {
 	defined := c.AllAttrs()
 	for _, k := range attrs {
 		delete(defined, k)
 	}
-	return New(NoDefaults, defined)
+	cfg, err := New(NoDefaults, defined)
+	if err != nil {
+		return nil, err
+	}
+	cfg.inheritSources(c, nil)
+	return cfg, nil
 }
 
 // Apply returns a new configuration that has the attributes of c plus attrs.
@@ -1131,7 +2169,25 @@ func (c *Config) Apply(attrs map[string]interface{}) (*Config, error) {
 	for k, v := range attrs {
 		defined[k] = v
 	}
-	return New(NoDefaults, defined)
+	cfg, err := New(NoDefaults, defined)
+	if err != nil {
+		return nil, err
+	}
+	cfg.inheritSources(c, attrs)
+	return cfg, nil
+}
+
+// inheritSources copies the sources of the attributes of old to c,
+// other than those that have been set anew.
+func (c *Config) inheritSources(old *Config, set map[string]interface{}) {
+	for k := range c.sources {
+		if _, ok := set[k]; ok {
+			continue
+		}
+		if source, ok := old.sources[k]; ok {
+			c.sources[k] = source
+		}
+	}
 }
 
 // fields holds the validation schema fields derived from configSchema.
@@ -1170,10 +2226,13 @@ var alwaysOptional = schema.Defaults{
 	// Environ providers will specify their own defaults.
 	StorageDefaultBlockSourceKey:      schema.Omit,
 	StorageDefaultFilesystemSourceKey: schema.Omit,
+	StorageDefaultPoolAttrsKey:        schema.Omit,
 
 	"firewall-mode":              schema.Omit,
 	"logging-config":             schema.Omit,
 	ProvisionerHarvestModeKey:    schema.Omit,
+	ProvisionerHarvestDryRunKey:  schema.Omit,
+	ProvisionerHarvestExemptKey:  schema.Omit,
 	HTTPProxyKey:                 schema.Omit,
 	HTTPSProxyKey:                schema.Omit,
 	FTPProxyKey:                  schema.Omit,
@@ -1182,15 +2241,26 @@ var alwaysOptional = schema.Defaults{
 	AptHTTPSProxyKey:             schema.Omit,
 	AptFTPProxyKey:               schema.Omit,
 	AptNoProxyKey:                schema.Omit,
+	SnapHTTPProxyKey:             schema.Omit,
+	SnapHTTPSProxyKey:            schema.Omit,
+	SnapStoreProxyKey:            schema.Omit,
+	SnapStoreAssertionsKey:       schema.Omit,
 	"apt-mirror":                 schema.Omit,
 	AgentStreamKey:               schema.Omit,
 	ResourceTagsKey:              schema.Omit,
+	ProxyOverridesKey:            schema.Omit,
+	CloudInitUserDataKey:         schema.Omit,
+	CustomCACertificatesKey:      schema.Omit,
+	InstanceMetadataHardeningKey: schema.Omit,
+	OSHardeningProfileKey:        schema.Omit,
 	"cloudimg-base-url":          schema.Omit,
 	"enable-os-refresh-update":   schema.Omit,
 	"enable-os-upgrade":          schema.Omit,
 	"image-stream":               schema.Omit,
 	"image-metadata-url":         schema.Omit,
+	ImageMetadataURLsKey:         schema.Omit,
 	AgentMetadataURLKey:          schema.Omit,
+	AgentMetadataAuthKey:         schema.Omit,
 	"default-series":             schema.Omit,
 	"development":                schema.Omit,
 	"ssl-hostname-verification":  schema.Omit,
@@ -1204,11 +2274,40 @@ var alwaysOptional = schema.Defaults{
 	ContainerNetworkingMethod:    schema.Omit,
 	MaxStatusHistoryAge:          schema.Omit,
 	MaxStatusHistorySize:         schema.Omit,
+	MaxStatusHistoryPerEntity:    schema.Omit,
+	StatusHistoryPruneInterval:   schema.Omit,
 	MaxActionResultsAge:          schema.Omit,
 	MaxActionResultsSize:         schema.Omit,
+	MaxConfigHistoryAge:          schema.Omit,
 	UpdateStatusHookInterval:     schema.Omit,
 	EgressSubnets:                schema.Omit,
 	FanConfig:                    schema.Omit,
+	MaintenanceWindow:            schema.Omit,
+	DefaultSpaceKey:              schema.Omit,
+	APIAdvertiseAddressesKey:     schema.Omit,
+	APIAdvertiseExcludeCIDRsKey:  schema.Omit,
+	TimeZone:                     schema.Omit,
+	SSHKeySourcesKey:             schema.Omit,
+	SSHKeyRefreshIntervalKey:     schema.Omit,
+	SSHAllowKey:                  schema.Omit,
+	AgentMaxProcsKey:             schema.Omit,
+	AgentMemoryLimitKey:          schema.Omit,
+	ProviderRetryAttemptsKey:     schema.Omit,
+	ProviderRetryDelayKey:        schema.Omit,
+	ProviderRetryMaxDelayKey:     schema.Omit,
+	SlowHookThresholdKey:         schema.Omit,
+	SlowProviderCallThresholdKey: schema.Omit,
+
+	LoadBalancerTypeKey:                 schema.Omit,
+	LoadBalancerHealthCheckPathKey:      schema.Omit,
+	LoadBalancerHealthCheckIntervalKey:  schema.Omit,
+	LoadBalancerHealthCheckThresholdKey: schema.Omit,
+
+	DNSProviderKey: schema.Omit,
+	DNSZoneKey:     schema.Omit,
+	DNSTTLKey:      schema.Omit,
+
+	FeaturesKey: schema.Omit,
 }
 
 func allowEmpty(attr string) bool {
@@ -1262,7 +2361,9 @@ func (cfg *Config) ValidateUnknownAttrs(extrafields schema.Fields, defaults sche
 	coerced, err := checker.Coerce(attrs, nil)
 	if err != nil {
 		// TODO(ericsnow) Drop this?
-		logger.Debugf("coercion failed attributes: %#v, checker: %#v, %v", attrs, checker, err)
+		// The values are not logged, as they may include
+		// provider credentials.
+		logger.Debugf("coercion failed attributes: %v, checker: %#v, %v", attrNames(attrs), checker, err)
 		return nil, err
 	}
 	result := coerced.(map[string]interface{})
@@ -1332,7 +2433,8 @@ func AptProxyConfigMap(proxySettings proxy.Settings) map[string]interface{} {
 // Schema returns a configuration schema that includes both
 // the given extra fields and all the fields defined in this package.
 // It returns an error if extra defines any fields defined in this
-// package.
+// package, or if the example or valid values given for an extra
+// field are not of the field's type.
 func Schema(extra environschema.Fields) (environschema.Fields, error) {
 	fields := make(environschema.Fields)
 	for name, field := range configSchema {
@@ -1348,11 +2450,67 @@ func Schema(extra environschema.Fields) (environschema.Fields, error) {
 		if _, ok := fields[name]; ok {
 			return nil, errors.Errorf("config field %q clashes with global config", name)
 		}
+		if err := checkFieldGuidance(name, field); err != nil {
+			return nil, errors.Trace(err)
+		}
 		fields[name] = field
 	}
 	return fields, nil
 }
 
+// checkFieldGuidance checks that the example and valid values attached
+// to a field can be used as values of the field.
+func checkFieldGuidance(name string, field environschema.Attr) error {
+	if field.Example == nil {
+		return nil
+	}
+	checkers, _, err := environschema.Fields{name: field}.ValidationSchema()
+	if err != nil {
+		return errors.Annotatef(err, "config field %q", name)
+	}
+	if _, err := checkers[name].Coerce(field.Example, []string{name}); err != nil {
+		return errors.Annotatef(err, "config field %q example", name)
+	}
+	return nil
+}
+
+// FieldDoc documents a config field for users: its schema, along with
+// guidance that environschema.Attr cannot record.
+type FieldDoc struct {
+	environschema.Attr
+
+	// Deprecated, if not empty, says that the field should no longer
+	// be used, and what should be used instead.
+	Deprecated string
+}
+
+// Docs maps config field names to their documentation.
+type Docs map[string]FieldDoc
+
+// SchemaDocs returns documentation for the fields in the schema
+// returned by Schema for the given extra fields. Deprecation notes,
+// keyed on field name, are attached to the extra fields they name; it
+// is an error for a note to name any other field.
+func SchemaDocs(extra environschema.Fields, deprecated map[string]string) (Docs, error) {
+	fields, err := Schema(extra)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	docs := make(Docs)
+	for name, field := range fields {
+		docs[name] = FieldDoc{Attr: field}
+	}
+	for name, note := range deprecated {
+		if _, ok := extra[name]; !ok {
+			return nil, errors.Errorf("deprecated config field %q not defined", name)
+		}
+		doc := docs[name]
+		doc.Deprecated = note
+		docs[name] = doc
+	}
+	return docs, nil
+}
+
 // configSchema holds information on all the fields defined by
 // the config package.
 // TODO(rog) make this available to external packages.
@@ -1362,6 +2520,12 @@ var configSchema = environschema.Fields{
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	AgentMetadataAuthKey: {
+		Description: `Credentials for agent-metadata-url, either "basic <user>:<password>" or "bearer <token>"`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+		Secret:      true,
+	},
 	AgentStreamKey: {
 		Description: `Version of Juju to use for deploy/upgrades.`,
 		Type:        environschema.Tstring,
@@ -1396,6 +2560,26 @@ var configSchema = environschema.Fields{
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	SnapHTTPProxyKey: {
+		Description: "The snap-centric HTTP proxy for the model",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	SnapHTTPSProxyKey: {
+		Description: "The snap-centric HTTPS proxy for the model",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	SnapStoreProxyKey: {
+		Description: "The ID of the snap store proxy that machines fetch snaps through",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	SnapStoreAssertionsKey: {
+		Description: "The signed assertions machines acknowledge to trust the snap store proxy",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	"apt-mirror": {
 		// TODO document acceptable format
 		Description: "The APT mirror for the model",
@@ -1479,6 +2663,11 @@ global or per instance security groups.`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	ImageMetadataURLsKey: {
+		Description: "An ordered list of mirrors at which OS image metadata is located, tried in turn after image-metadata-url",
+		Type:        environschema.Tlist,
+		Group:       environschema.EnvironGroup,
+	},
 	"image-stream": {
 		Description: `The simplestreams stream used to identify which image ids to search when starting an instance.`,
 		Type:        environschema.Tstring,
@@ -1497,8 +2686,34 @@ global or per instance security groups.`,
 		Group:       environschema.EnvironGroup,
 	},
 	NoProxyKey: {
-		Description: "List of domain addresses not to be proxied (comma-separated)",
+		Description: "List of domain names, IP addresses and CIDRs not to be proxied (comma-separated)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	ProxyOverridesKey: {
+		Description: "Space-separated app=spec pairs overriding the proxy settings of applications, where spec is a semicolon-separated list of http, https, ftp and no-proxy settings",
+		Type:        environschema.Tattrs,
+		Group:       environschema.EnvironGroup,
+	},
+	CloudInitUserDataKey: {
+		Description: "Cloud-config YAML merged into the cloud-init user data of every machine, eg to add packages, write_files or runcmds; modules Juju manages, such as users, ssh_authorized_keys and the apt settings, may not be set",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	OSHardeningProfileKey: {
+		Description: "The OS hardening baseline applied to new machines on first boot (default none)",
 		Type:        environschema.Tstring,
+		Values:      []interface{}{OSHardeningNone, OSHardeningCISLevel1},
+		Group:       environschema.EnvironGroup,
+	},
+	InstanceMetadataHardeningKey: {
+		Description: "Whether to restrict access to the cloud's instance metadata service on new machines to the root user, and block it for containers (default false)",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
+	CustomCACertificatesKey: {
+		Description: "PEM encoded CA certificates to install into the system trust store of every machine, eg for mirrors using private CAs",
+		Type:        environschema.Tlist,
 		Group:       environschema.EnvironGroup,
 	},
 	ProvisionerHarvestModeKey: {
@@ -1508,6 +2723,16 @@ global or per instance security groups.`,
 		Values:      []interface{}{"all", "none", "unknown", "destroyed"},
 		Group:       environschema.EnvironGroup,
 	},
+	ProvisionerHarvestDryRunKey: {
+		Description: "Whether the provisioner should only log the machines it would harvest, rather than stopping them (default false)",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
+	ProvisionerHarvestExemptKey: {
+		Description: "Comma-separated IDs of unknown instances that the provisioner must never harvest",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	"proxy-ssh": {
 		// default: true
 		Description: `Whether SSH commands should be proxied through the API server`,
@@ -1543,6 +2768,7 @@ global or per instance security groups.`,
 		Description: `The syslog client key in PEM format.`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
+		Secret:      true,
 	},
 	"ssl-hostname-verification": {
 		Description: "Whether SSL hostname verification is enabled (default true)",
@@ -1559,6 +2785,11 @@ global or per instance security groups.`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	StorageDefaultPoolAttrsKey: {
+		Description: "Space-separated k=v attributes for storage created in the default block and filesystem storage sources, eg volume-type=io1 iops=30",
+		Type:        environschema.Tattrs,
+		Group:       environschema.EnvironGroup,
+	},
 	"test-mode": {
 		Description: `Whether the model is intended for testing.
 If true, accessing the charm store does not affect statistical
@@ -1609,6 +2840,16 @@ data of the store. (default false)`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	MaxStatusHistoryPerEntity: {
+		Description: "The maximum number of status history entries kept for each entity, or 0 for no limit",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	StatusHistoryPruneInterval: {
+		Description: "How often to prune the status history, in human-readable time format (default 5m, range 30s-24h)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	MaxActionResultsAge: {
 		Description: "The maximum age for action entries before they are pruned, in human-readable time format",
 		Type:        environschema.Tstring,
@@ -1619,6 +2860,11 @@ data of the store. (default false)`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	MaxConfigHistoryAge: {
+		Description: "The maximum age for model config history entries before they are pruned, in human-readable time format; 0 keeps them all",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	UpdateStatusHookInterval: {
 		Description: "How often to run the charm update-status hook, in human-readable time format (default 5m, range 1-60m)",
 		Type:        environschema.Tstring,
@@ -1634,4 +2880,120 @@ data of the store. (default false)`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	DefaultSpaceKey: {
+		Description: "The space to which the endpoints of new applications are bound, unless bound otherwise when deployed",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	APIAdvertiseAddressesKey: {
+		Description: `Comma-separated addresses, with optional ports, always advertised to the model's agents for reaching the controller, eg "203.0.113.7:17070"`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	APIAdvertiseExcludeCIDRsKey: {
+		Description: "Comma-separated CIDRs of networks whose controller addresses are never advertised to the model's agents",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	MaintenanceWindow: {
+		Description: `Weekly windows during which disruptive automated operations may run, eg "mon-fri 01:00-03:00; sat,sun 22:00-06:00" (default: any time)`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	TimeZone: {
+		Description: "The IANA time zone in which maintenance windows are interpreted (default UTC)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	SSHKeySourcesKey: {
+		Description: `Key server sources from which ssh keys are imported and kept up to date, eg "lp:someone gh:someone"`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	SSHKeyRefreshIntervalKey: {
+		Description: "How often ssh keys are refreshed from their sources (default " + DefaultSSHKeyRefreshInterval + ")",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	SSHAllowKey: {
+		Description: "Comma-separated CIDRs from which ssh connections to machines are accepted by the controller's ssh bastion (default " + DefaultSSHAllow + ")",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	AgentMaxProcsKey: {
+		Description: "The maximum number of CPUs each machine and unit agent may use simultaneously (0 for no limit)",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	AgentMemoryLimitKey: {
+		Description: `The memory, eg "512M", each machine and unit agent may use before restarting itself (empty for no limit)`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	ProviderRetryAttemptsKey: {
+		Description: "The number of times a failed cloud operation is attempted before giving up (default 10)",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	ProviderRetryDelayKey: {
+		Description: "How long to wait before first retrying a failed cloud operation; the delay doubles with each attempt (default " + DefaultProviderRetryDelay + ")",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	ProviderRetryMaxDelayKey: {
+		Description: "The longest to wait between attempts of a failed cloud operation (default " + DefaultProviderRetryMaxDelay + ")",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	SlowHookThresholdKey: {
+		Description: "How long a hook must run to be recorded as a slow operation; 0 disables the recording (default " + DefaultSlowHookThreshold + ")",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	SlowProviderCallThresholdKey: {
+		Description: "How long a cloud provider call must take to be recorded as a slow operation; 0 disables the recording (default " + DefaultSlowProviderCallThreshold + ")",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LoadBalancerTypeKey: {
+		Description: "The type of cloud load balancer provisioned for the exposed endpoints of applications (default none)",
+		Type:        environschema.Tstring,
+		Values:      []interface{}{LoadBalancerNone, LoadBalancerNetwork, LoadBalancerApplication},
+		Group:       environschema.EnvironGroup,
+	},
+	LoadBalancerHealthCheckPathKey: {
+		Description: "The HTTP path requested by load balancer health checks (default " + DefaultLoadBalancerHealthCheckPath + ")",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LoadBalancerHealthCheckIntervalKey: {
+		Description: "The time between load balancer health checks (default " + DefaultLoadBalancerHealthCheckInterval + ")",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LoadBalancerHealthCheckThresholdKey: {
+		Description: "The number of consecutive load balancer health checks that must succeed or fail to change an instance's health (default 3)",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	DNSProviderKey: {
+		Description: "The DNS provider that manages records for exposed applications with a hostname annotation; records are not managed if empty",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	DNSZoneKey: {
+		Description: "The DNS zone in which records are managed for exposed applications",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	DNSTTLKey: {
+		Description: "The time to live, in seconds, of the DNS records managed for exposed applications (default 300)",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	FeaturesKey: {
+		Description: "Experimental feature flags enabled for the model, eg \"" + feature.ImageMetadata + "\"",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 }
@@ -0,0 +1,68 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/juju/version"
+)
+
+// DeprecatedAttribute describes a config attribute that has been
+// renamed. Configs holding the old attribute, whether stored or
+// supplied by users, are migrated to the new one when they are read.
+type DeprecatedAttribute struct {
+	// OldKey is the name the attribute is deprecated under. It must
+	// not be a field of the config schema.
+	OldKey string
+
+	// NewKey is the name of the attribute that replaces it.
+	NewKey string
+
+	// Transform, if not nil, converts a value of the old attribute
+	// to one of the new. Otherwise the value is used unchanged. The
+	// converted value is validated as a value of the new attribute.
+	Transform func(interface{}) interface{}
+
+	// RemovedIn is the Juju version in which the old attribute will
+	// no longer be migrated, and the entry removed from the table.
+	RemovedIn version.Number
+}
+
+// Warning returns the message logged, and reported to users, when a
+// config holds the deprecated attribute.
+func (attr DeprecatedAttribute) Warning() string {
+	return fmt.Sprintf(
+		"config attribute %q is deprecated and will be removed in Juju %s; use %q instead",
+		attr.OldKey, attr.RemovedIn, attr.NewKey,
+	)
+}
+
+// deprecatedAttributes is the table of renamed config attributes.
+// Entries must be removed once RemovedIn is reached.
+var deprecatedAttributes []DeprecatedAttribute
+
+// DeprecatedAttributes returns the table of renamed config attributes.
+func DeprecatedAttributes() []DeprecatedAttribute {
+	result := make([]DeprecatedAttribute, len(deprecatedAttributes))
+	copy(result, deprecatedAttributes)
+	return result
+}
+
+// DeprecationWarnings returns a warning for each deprecated attribute
+// in attrs, in the order of the deprecation table.
+func DeprecationWarnings(attrs map[string]interface{}) []string {
+	var warnings []string
+	for _, attr := range deprecatedAttributes {
+		if _, ok := attrs[attr.OldKey]; !ok {
+			continue
+		}
+		warning := attr.Warning()
+		if _, ok := attrs[attr.NewKey]; ok {
+			warning += fmt.Sprintf(" (ignored, as %q is also set)", attr.NewKey)
+		}
+		warnings = append(warnings, warning)
+	}
+	return warnings
+}
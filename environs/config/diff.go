@@ -0,0 +1,116 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/environschema.v1"
+)
+
+// ConfigChangeType describes how an attribute differs between two
+// configurations.
+type ConfigChangeType string
+
+const (
+	// ConfigAdded is the type of a change setting an attribute that
+	// was not set before.
+	ConfigAdded ConfigChangeType = "added"
+
+	// ConfigRemoved is the type of a change removing an attribute.
+	ConfigRemoved ConfigChangeType = "removed"
+
+	// ConfigChanged is the type of a change to the value of an
+	// attribute.
+	ConfigChanged ConfigChangeType = "changed"
+)
+
+// ConfigChange records a difference in one attribute between two
+// configurations.
+type ConfigChange struct {
+	Key  string
+	Type ConfigChangeType
+
+	// Old holds the previous value, and is nil if the attribute was
+	// added.
+	Old interface{}
+
+	// New holds the new value, and is nil if the attribute was
+	// removed.
+	New interface{}
+
+	// Secret is true if the schema marks the attribute as secret,
+	// in which case its values should not be shown to users.
+	Secret bool
+}
+
+// Masked returns the change with its values cleared if the attribute
+// is secret.
+func (ch ConfigChange) Masked() ConfigChange {
+	if ch.Secret {
+		ch.Old, ch.New = nil, nil
+	}
+	return ch
+}
+
+// Diff returns the changes to the attributes of old that give c,
+// ordered by attribute name. A nil old is treated as an empty
+// configuration. Attributes are marked secret according to the fields
+// defined in this package; use DiffSchema to take the fields of a
+// provider into account.
+func (c *Config) Diff(old *Config) ([]ConfigChange, error) {
+	return c.DiffSchema(old, nil)
+}
+
+// DiffSchema is like Diff, but also marks as secret the attributes that
+// the given extra fields mark as secret.
+func (c *Config) DiffSchema(old *Config, extra environschema.Fields) ([]ConfigChange, error) {
+	fields, err := Schema(extra)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	newAttrs := c.AllAttrs()
+	oldAttrs := make(map[string]interface{})
+	if old != nil {
+		oldAttrs = old.AllAttrs()
+	}
+
+	var changes []ConfigChange
+	for key, newValue := range newAttrs {
+		oldValue, ok := oldAttrs[key]
+		switch {
+		case !ok:
+			changes = append(changes, ConfigChange{
+				Key:  key,
+				Type: ConfigAdded,
+				New:  newValue,
+			})
+		case !reflect.DeepEqual(oldValue, newValue):
+			changes = append(changes, ConfigChange{
+				Key:  key,
+				Type: ConfigChanged,
+				Old:  oldValue,
+				New:  newValue,
+			})
+		}
+	}
+	for key, oldValue := range oldAttrs {
+		if _, ok := newAttrs[key]; !ok {
+			changes = append(changes, ConfigChange{
+				Key:  key,
+				Type: ConfigRemoved,
+				Old:  oldValue,
+			})
+		}
+	}
+	for i := range changes {
+		changes[i].Secret = fields[changes[i].Key].Secret
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes, nil
+}
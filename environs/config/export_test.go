@@ -5,4 +5,6 @@ package config
 
 var (
 	ConfigSchema = configSchema
+
+	DeprecatedAttributesTable = &deprecatedAttributes
 )
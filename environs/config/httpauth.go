@@ -0,0 +1,119 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// HTTPAuth holds the credentials used to authenticate requests to an
+// HTTP endpoint, either a username and password for basic
+// authentication or a bearer token.
+type HTTPAuth struct {
+	Username string
+	Password string
+	Token    string
+}
+
+// ParseHTTPAuth parses credentials of the form "basic <user>:<password>"
+// or "bearer <token>".
+func ParseHTTPAuth(value string) (HTTPAuth, error) {
+	fields := strings.SplitN(strings.TrimSpace(value), " ", 2)
+	if len(fields) != 2 || strings.TrimSpace(fields[1]) == "" {
+		return HTTPAuth{}, errors.New(`expected "basic <user>:<password>" or "bearer <token>"`)
+	}
+	credentials := strings.TrimSpace(fields[1])
+	switch strings.ToLower(fields[0]) {
+	case "basic":
+		parts := strings.SplitN(credentials, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return HTTPAuth{}, errors.New(`basic credentials must be of the form "<user>:<password>"`)
+		}
+		return HTTPAuth{Username: parts[0], Password: parts[1]}, nil
+	case "bearer":
+		return HTTPAuth{Token: credentials}, nil
+	}
+	return HTTPAuth{}, errors.Errorf("unsupported authentication scheme %q", fields[0])
+}
+
+// Authorize sets the Authorization header of the given request.
+func (a HTTPAuth) Authorize(req *http.Request) {
+	if a.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.Token)
+		return
+	}
+	req.SetBasicAuth(a.Username, a.Password)
+}
+
+// RoundTripper returns an http.RoundTripper that sends requests with
+// base, or with http.DefaultTransport if base is nil, authorizing
+// those for URLs under baseURL. Other requests, such as those that
+// follow a redirect to another host, are sent without an
+// Authorization header.
+func (a HTTPAuth) RoundTripper(baseURL string, base http.RoundTripper) (http.RoundTripper, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &authRoundTripper{auth: a, baseURL: parsed, base: base}, nil
+}
+
+type authRoundTripper struct {
+	auth    HTTPAuth
+	baseURL *url.URL
+	base    http.RoundTripper
+}
+
+// RoundTrip is part of the http.RoundTripper interface.
+func (t *authRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the request it is given.
+	sent := new(http.Request)
+	*sent = *req
+	sent.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		sent.Header[k] = append([]string(nil), v...)
+	}
+	if urlWithin(t.baseURL, req.URL) {
+		t.auth.Authorize(sent)
+	} else {
+		sent.Header.Del("Authorization")
+	}
+	return t.base.RoundTrip(sent)
+}
+
+// URLWithin reports whether target is baseURL or a URL under it:
+// the two must have the same scheme and host, and target's path must
+// be baseURL's path or lie beneath it. Unparseable URLs are never
+// within one another.
+func URLWithin(baseURL, target string) bool {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return false
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+	return urlWithin(base, u)
+}
+
+func urlWithin(base, u *url.URL) bool {
+	if !strings.EqualFold(u.Scheme, base.Scheme) || !strings.EqualFold(u.Host, base.Host) {
+		return false
+	}
+	if u.User != nil || u.Opaque != "" {
+		return false
+	}
+	basePath := strings.TrimSuffix(path.Clean("/"+base.Path), "/")
+	targetPath := path.Clean("/" + u.Path)
+	return targetPath == basePath || strings.HasPrefix(targetPath, basePath+"/")
+}
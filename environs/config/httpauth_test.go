@@ -0,0 +1,157 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config_test
+
+import (
+	"net/http"
+
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/testing"
+)
+
+type HTTPAuthSuite struct {
+	gitjujutesting.IsolationSuite
+}
+
+var _ = gc.Suite(&HTTPAuthSuite{})
+
+func (s *HTTPAuthSuite) TestParseHTTPAuth(c *gc.C) {
+	for i, test := range []struct {
+		value  string
+		expect config.HTTPAuth
+		err    string
+	}{{
+		value:  "basic fred:sekrit:42",
+		expect: config.HTTPAuth{Username: "fred", Password: "sekrit:42"},
+	}, {
+		value:  "Bearer abc123",
+		expect: config.HTTPAuth{Token: "abc123"},
+	}, {
+		value: "bearer",
+		err:   `expected "basic <user>:<password>" or "bearer <token>"`,
+	}, {
+		value: "basic fred",
+		err:   `basic credentials must be of the form "<user>:<password>"`,
+	}, {
+		value: "digest fred:sekrit",
+		err:   `unsupported authentication scheme "digest"`,
+	}} {
+		c.Logf("test %d: %q", i, test.value)
+		auth, err := config.ParseHTTPAuth(test.value)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(auth, jc.DeepEquals, test.expect)
+	}
+}
+
+type recordingRoundTripper struct {
+	req *http.Request
+}
+
+func (r *recordingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.req = req
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func (s *HTTPAuthSuite) TestRoundTripperBasic(c *gc.C) {
+	base := &recordingRoundTripper{}
+	rt, err := config.HTTPAuth{Username: "fred", Password: "sekrit"}.RoundTripper("https://streams.internal/", base)
+	c.Assert(err, jc.ErrorIsNil)
+	req, err := http.NewRequest("GET", "https://streams.internal/tools", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = rt.RoundTrip(req)
+	c.Assert(err, jc.ErrorIsNil)
+	user, password, ok := base.req.BasicAuth()
+	c.Check(ok, jc.IsTrue)
+	c.Check(user, gc.Equals, "fred")
+	c.Check(password, gc.Equals, "sekrit")
+	// The original request is left alone.
+	c.Check(req.Header.Get("Authorization"), gc.Equals, "")
+}
+
+func (s *HTTPAuthSuite) TestRoundTripperBearer(c *gc.C) {
+	base := &recordingRoundTripper{}
+	rt, err := config.HTTPAuth{Token: "abc123"}.RoundTripper("https://streams.internal/", base)
+	c.Assert(err, jc.ErrorIsNil)
+	req, err := http.NewRequest("GET", "https://streams.internal/tools", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = rt.RoundTrip(req)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(base.req.Header.Get("Authorization"), gc.Equals, "Bearer abc123")
+}
+
+func (s *HTTPAuthSuite) TestRoundTripperOtherHost(c *gc.C) {
+	base := &recordingRoundTripper{}
+	rt, err := config.HTTPAuth{Token: "abc123"}.RoundTripper("https://streams.internal/tools", base)
+	c.Assert(err, jc.ErrorIsNil)
+	// A redirect to another host carries no credentials, even if
+	// they were copied from the original request.
+	req, err := http.NewRequest("GET", "https://elsewhere.example.com/tools/agent.tgz", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	req.Header.Set("Authorization", "Bearer abc123")
+
+	_, err = rt.RoundTrip(req)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(base.req.Header.Get("Authorization"), gc.Equals, "")
+}
+
+func (s *HTTPAuthSuite) TestURLWithin(c *gc.C) {
+	for i, test := range []struct {
+		target string
+		expect bool
+	}{
+		{"https://streams.internal/tools", true},
+		{"https://streams.internal/tools/", true},
+		{"https://Streams.Internal/tools/released/agent.tgz", true},
+		{"https://streams.internal/toolsmith/agent.tgz", false},
+		{"https://streams.internal/tools/../secret", false},
+		{"http://streams.internal/tools/agent.tgz", false},
+		{"https://streams.internal:8443/tools/agent.tgz", false},
+		{"https://streams.internal.evil.com/tools/agent.tgz", false},
+		{"https://streams.internal@evil.com/tools/agent.tgz", false},
+		{"https://user@streams.internal/tools/agent.tgz", false},
+		{"%zz", false},
+	} {
+		c.Logf("test %d: %s", i, test.target)
+		c.Check(config.URLWithin("https://streams.internal/tools", test.target), gc.Equals, test.expect)
+	}
+}
+
+func (s *HTTPAuthSuite) TestAgentMetadataAuth(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"agent-metadata-auth": "bearer abc123",
+	})
+	auth, ok := cfg.AgentMetadataAuth()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(auth, jc.DeepEquals, config.HTTPAuth{Token: "abc123"})
+}
+
+func (s *HTTPAuthSuite) TestAgentMetadataAuthNotSet(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	_, ok := cfg.AgentMetadataAuth()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *HTTPAuthSuite) TestAgentMetadataAuthInvalid(c *gc.C) {
+	_, err := config.New(config.UseDefaults, testing.FakeConfig().Merge(testing.Attrs{
+		"agent-metadata-auth": "sekrit",
+	}))
+	c.Assert(err, gc.ErrorMatches, `invalid agent-metadata-auth: expected "basic <user>:<password>" or "bearer <token>"`)
+}
+
+func (s *HTTPAuthSuite) TestAgentMetadataAuthMasked(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"agent-metadata-auth": "basic fred:sekrit",
+	})
+	c.Assert(cfg.MaskedAttrs()["agent-metadata-auth"], gc.Equals, config.MaskedValue)
+}
@@ -0,0 +1,86 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+)
+
+// migratableAttributes holds those immutable attributes for which a
+// model with a different value can be arrived at by adding a new model
+// with that value and moving the applications across to it.
+var migratableAttributes = map[string]bool{
+	NameKey:         true,
+	"firewall-mode": true,
+}
+
+// ImmutableAttributeError is returned by Validate when a change is made
+// to an attribute that may not change in the lifetime of a model.
+type ImmutableAttributeError struct {
+	// Attribute is the name of the attribute that was changed.
+	Attribute string
+
+	// Old and New hold the attribute's current and rejected values.
+	Old, New interface{}
+
+	// Migration describes how to arrive at a model with the new
+	// value, or is empty if there is no supported way to do so.
+	Migration string
+}
+
+func newImmutableAttributeError(attr string, oldv, newv interface{}) *ImmutableAttributeError {
+	err := &ImmutableAttributeError{
+		Attribute: attr,
+		Old:       oldv,
+		New:       newv,
+	}
+	if migratableAttributes[attr] {
+		err.Migration = migrationPath(fmt.Sprintf("%s %#v", attr, newv))
+	}
+	return err
+}
+
+func migrationPath(value string) string {
+	return fmt.Sprintf("add a new model with %s and migrate the applications to it", value)
+}
+
+// Error is part of the error interface.
+func (e *ImmutableAttributeError) Error() string {
+	msg := fmt.Sprintf("cannot change %s from %#v to %#v", e.Attribute, e.Old, e.New)
+	if e.Migration != "" {
+		msg += ": " + e.Migration
+	}
+	return msg
+}
+
+// IsImmutableAttributeError reports whether the cause of err is an
+// *ImmutableAttributeError.
+func IsImmutableAttributeError(err error) bool {
+	_, ok := errors.Cause(err).(*ImmutableAttributeError)
+	return ok
+}
+
+// CanChange reports whether the named attribute may be changed in a
+// model with this config. If not, the reason returned says why, and how
+// to arrive at a model with a different value where that is supported.
+// It is intended for clients that present config for editing.
+func (c *Config) CanChange(attr string) (bool, string) {
+	for _, immutable := range immutableAttributes {
+		if attr != immutable {
+			continue
+		}
+		if _, ok := c.defined[attr]; !ok {
+			// Validate only rejects changes to values that are set.
+			return true, ""
+		}
+		reason := fmt.Sprintf("%s cannot change in the lifetime of a model", attr)
+		if migratableAttributes[attr] {
+			reason += "; to use a different value, " + migrationPath("that value")
+		}
+		return false, reason
+	}
+	return true, ""
+}
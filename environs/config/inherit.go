@@ -0,0 +1,79 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"github.com/juju/errors"
+)
+
+// DefaultsLayer holds the config defaults set at one level of the
+// hierarchy from which models inherit config.
+type DefaultsLayer struct {
+	// Source labels the attributes that come from the layer, eg
+	// JujuRegionSource.
+	Source string
+
+	// Attrs holds the attribute values set at this level.
+	Attrs map[string]interface{}
+}
+
+// DefaultsResolver resolves the config of a model from the defaults
+// layered over Juju's own: those of the controller, then those of the
+// model's cloud, then those of its cloud region. Each layer overrides
+// the ones before it, and the model's own attributes override them all.
+type DefaultsResolver struct {
+	// Controller holds the defaults shared by all models on the
+	// controller.
+	Controller map[string]interface{}
+
+	// Cloud holds the defaults of models on the model's cloud.
+	Cloud map[string]interface{}
+
+	// Region holds the defaults of models in the model's cloud region.
+	Region map[string]interface{}
+}
+
+// Layers returns the layers of defaults, in order of increasing
+// precedence.
+func (r DefaultsResolver) Layers() []DefaultsLayer {
+	return []DefaultsLayer{
+		{Source: JujuControllerSource, Attrs: r.Controller},
+		{Source: JujuCloudSource, Attrs: r.Cloud},
+		{Source: JujuRegionSource, Attrs: r.Region},
+	}
+}
+
+// Resolve returns the model attributes layered over the defaults, each
+// labelled with the source that supplied it. Juju's own defaults are
+// not included; see NewConfig.
+func (r DefaultsResolver) Resolve(modelAttrs map[string]interface{}) ConfigValues {
+	values := make(ConfigValues)
+	for _, layer := range r.Layers() {
+		for k, v := range layer.Attrs {
+			values[k] = ConfigValue{Value: v, Source: layer.Source}
+		}
+	}
+	for k, v := range modelAttrs {
+		values[k] = ConfigValue{Value: v, Source: JujuModelConfigSource}
+	}
+	return values
+}
+
+// NewConfig returns a new configuration holding the model attributes
+// layered over the defaults, with any remaining attributes filled in
+// from Juju's own defaults. The configuration records the source of
+// each attribute; see Config.AllAttrsWithSource.
+func (r DefaultsResolver) NewConfig(modelAttrs map[string]interface{}) (*Config, error) {
+	values := r.Resolve(modelAttrs)
+	cfg, err := New(UseDefaults, values.AllAttrs())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for k, v := range values {
+		if _, ok := cfg.sources[k]; ok {
+			cfg.sources[k] = v.Source
+		}
+	}
+	return cfg, nil
+}
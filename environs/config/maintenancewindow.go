@@ -0,0 +1,168 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// MaintenanceWindow is a recurring weekly period during which disruptive
+// automated operations may be run in a model.
+type MaintenanceWindow struct {
+	// Days holds the days of the week on which the window opens.
+	Days [7]bool
+
+	// Start is the offset from midnight at which the window opens.
+	Start time.Duration
+
+	// Length is how long the window stays open. A window may run past
+	// midnight into the following day.
+	Length time.Duration
+}
+
+// MaintenanceWindows is a set of maintenance windows, interpreted in a
+// particular time zone. An empty set of windows places no restriction
+// on when operations may run.
+type MaintenanceWindows struct {
+	Windows  []MaintenanceWindow
+	Location *time.Location
+}
+
+// Open reports whether the supplied time falls within any of the windows.
+func (w MaintenanceWindows) Open(t time.Time) bool {
+	if len(w.Windows) == 0 {
+		return true
+	}
+	loc := w.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+	// A window that opened yesterday may still be open today.
+	for _, dayOffset := range []int{0, -1} {
+		day := t.AddDate(0, 0, dayOffset)
+		midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+		for _, window := range w.Windows {
+			if !window.Days[day.Weekday()] {
+				continue
+			}
+			start := midnight.Add(window.Start)
+			if !t.Before(start) && t.Before(start.Add(window.Length)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseMaintenanceWindows parses a maintenance window specification. The
+// specification is a semicolon-separated list of windows, each of the
+// form "<days> <HH:MM>-<HH:MM>", where days is "*" or a comma-separated
+// list of day names and day ranges, eg "mon-fri 01:00-03:00; sat,sun
+// 22:00-06:00". An empty specification yields no windows.
+func ParseMaintenanceWindows(spec string) ([]MaintenanceWindow, error) {
+	var windows []MaintenanceWindow
+	for _, part := range strings.Split(spec, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		window, err := parseMaintenanceWindow(part)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid maintenance window %q", part)
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+func parseMaintenanceWindow(spec string) (MaintenanceWindow, error) {
+	var window MaintenanceWindow
+	fields := strings.Fields(spec)
+	if len(fields) != 2 {
+		return window, errors.New(`expected "<days> <HH:MM>-<HH:MM>"`)
+	}
+	days, err := parseWeekdays(fields[0])
+	if err != nil {
+		return window, errors.Trace(err)
+	}
+	times := strings.Split(fields[1], "-")
+	if len(times) != 2 {
+		return window, errors.Errorf("expected time range, got %q", fields[1])
+	}
+	start, err := parseTimeOfDay(times[0])
+	if err != nil {
+		return window, errors.Trace(err)
+	}
+	end, err := parseTimeOfDay(times[1])
+	if err != nil {
+		return window, errors.Trace(err)
+	}
+	length := end - start
+	if length <= 0 {
+		length += 24 * time.Hour
+	}
+	window.Days = days
+	window.Start = start
+	window.Length = length
+	return window, nil
+}
+
+func parseWeekdays(spec string) ([7]bool, error) {
+	var days [7]bool
+	if spec == "*" {
+		for i := range days {
+			days[i] = true
+		}
+		return days, nil
+	}
+	for _, item := range strings.Split(spec, ",") {
+		bounds := strings.Split(strings.ToLower(item), "-")
+		if len(bounds) > 2 {
+			return days, errors.Errorf("invalid day range %q", item)
+		}
+		first, ok := weekdays[bounds[0]]
+		if !ok {
+			return days, errors.Errorf("invalid day %q", bounds[0])
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, ok = weekdays[bounds[1]]; !ok {
+				return days, errors.Errorf("invalid day %q", bounds[1])
+			}
+		}
+		for day := first; ; day = (day + 1) % 7 {
+			days[day] = true
+			if day == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+func parseTimeOfDay(spec string) (time.Duration, error) {
+	var hours, minutes int
+	if _, err := fmt.Sscanf(spec, "%d:%d", &hours, &minutes); err != nil {
+		return 0, errors.Errorf("invalid time %q", spec)
+	}
+	if hours < 0 || hours > 24 || minutes < 0 || minutes > 59 || (hours == 24 && minutes != 0) {
+		return 0, errors.Errorf("invalid time %q", spec)
+	}
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/environschema.v1"
+)
+
+// MaskedValue replaces the values of secret attributes in masked
+// configuration attributes.
+const MaskedValue = "<redacted>"
+
+// MaskedAttrs returns a copy of the configuration attributes, like
+// AllAttrs, with the values of the attributes that the fields defined
+// in this package mark as secret replaced by MaskedValue. Use
+// MaskedAttrsSchema to take the fields of a provider into account.
+func (c *Config) MaskedAttrs() map[string]interface{} {
+	return MaskAttrs(c.AllAttrs(), configSchema)
+}
+
+// MaskedAttrsSchema is like MaskedAttrs, but also masks the attributes
+// that the given extra fields mark as secret.
+func (c *Config) MaskedAttrsSchema(extra environschema.Fields) (map[string]interface{}, error) {
+	fields, err := Schema(extra)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return MaskAttrs(c.AllAttrs(), fields), nil
+}
+
+// MaskAttrs replaces the values of the attributes that the fields mark
+// as secret with MaskedValue, and returns the attributes. Unset and
+// empty attributes are left alone, so that users can see that no
+// secret has been set.
+func MaskAttrs(attrs map[string]interface{}, fields environschema.Fields) map[string]interface{} {
+	for name, value := range attrs {
+		if !fields[name].Secret || value == nil || value == "" {
+			continue
+		}
+		attrs[name] = MaskedValue
+	}
+	return attrs
+}
+
+// attrNames returns the sorted names of the attributes.
+func attrNames(attrs map[string]interface{}) []string {
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,95 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"net"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// NoProxyEntries holds the parsed entries of a no-proxy setting, which
+// lists the destinations that are reached without a proxy.
+type NoProxyEntries struct {
+	// Hostnames holds the domain names listed, in lower case. A name
+	// matches itself and its subdomains. A name with a leading "." or
+	// "*." matches only its subdomains. The name "*" matches every
+	// destination.
+	Hostnames []string
+
+	// IPs holds the IP addresses listed.
+	IPs []net.IP
+
+	// CIDRs holds the IP address ranges listed.
+	CIDRs []*net.IPNet
+}
+
+// ParseNoProxy parses a comma-separated no-proxy setting. Entries
+// containing a "/" must be valid CIDRs. Any port in an entry is
+// ignored.
+func ParseNoProxy(value string) (NoProxyEntries, error) {
+	var entries NoProxyEntries
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			_, ipNet, err := net.ParseCIDR(entry)
+			if err != nil {
+				return NoProxyEntries{}, errors.NotValidf("CIDR %q", entry)
+			}
+			entries.CIDRs = append(entries.CIDRs, ipNet)
+			continue
+		}
+		if host, _, err := net.SplitHostPort(entry); err == nil {
+			entry = host
+		}
+		if ip := net.ParseIP(strings.Trim(entry, "[]")); ip != nil {
+			entries.IPs = append(entries.IPs, ip)
+			continue
+		}
+		entries.Hostnames = append(entries.Hostnames, strings.ToLower(entry))
+	}
+	return entries, nil
+}
+
+// Matches reports whether connections to the host, which may include
+// a port, should bypass the proxy.
+func (e NoProxyEntries) Matches(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.Trim(host, "[]"))
+	if ip := net.ParseIP(host); ip != nil {
+		for _, entry := range e.IPs {
+			if entry.Equal(ip) {
+				return true
+			}
+		}
+		for _, entry := range e.CIDRs {
+			if entry.Contains(ip) {
+				return true
+			}
+		}
+	}
+	for _, entry := range e.Hostnames {
+		switch {
+		case entry == "*":
+			return true
+		case strings.HasPrefix(entry, "*."):
+			if strings.HasSuffix(host, entry[1:]) {
+				return true
+			}
+		case strings.HasPrefix(entry, "."):
+			if strings.HasSuffix(host, entry) {
+				return true
+			}
+		case host == entry || strings.HasSuffix(host, "."+entry):
+			return true
+		}
+	}
+	return false
+}
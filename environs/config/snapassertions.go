@@ -0,0 +1,93 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// snapAssertion holds the single-line headers of a signed snap
+// assertion. The body and signature are not retained.
+type snapAssertion map[string]string
+
+// parseSnapAssertions parses a stream of signed snap assertions, as
+// fetched from a snap store proxy for acknowledgement with "snap ack".
+// Each assertion consists of headers, an optional body whose length is
+// given by the body-length header, and a signature, separated by blank
+// lines. Signatures are not verified; that is left to snapd.
+func parseSnapAssertions(blob string) ([]snapAssertion, error) {
+	var assertions []snapAssertion
+	rest := strings.Replace(blob, "\r\n", "\n", -1)
+	for {
+		rest = strings.TrimLeft(rest, "\n")
+		if rest == "" {
+			break
+		}
+		n := len(assertions) + 1
+		i := strings.Index(rest, "\n\n")
+		if i < 0 {
+			return nil, errors.Errorf("assertion %d has no signature", n)
+		}
+		headers, err := parseSnapAssertionHeaders(rest[:i])
+		if err != nil {
+			return nil, errors.Annotatef(err, "assertion %d", n)
+		}
+		rest = rest[i+2:]
+		if v, ok := headers["body-length"]; ok {
+			length, err := strconv.Atoi(v)
+			if err != nil || length < 0 {
+				return nil, errors.Errorf("assertion %d has invalid body-length %q", n, v)
+			}
+			if length > 0 {
+				if len(rest) < length+2 || rest[length:length+2] != "\n\n" {
+					return nil, errors.Errorf("assertion %d body does not match its body-length", n)
+				}
+				rest = rest[length+2:]
+			}
+		}
+		signature := rest
+		if i := strings.Index(rest, "\n\n"); i >= 0 {
+			signature, rest = rest[:i], rest[i+2:]
+		} else {
+			rest = ""
+		}
+		if strings.TrimSpace(signature) == "" {
+			return nil, errors.Errorf("assertion %d has no signature", n)
+		}
+		assertions = append(assertions, headers)
+	}
+	if len(assertions) == 0 {
+		return nil, errors.New("no assertions found")
+	}
+	return assertions, nil
+}
+
+// parseSnapAssertionHeaders parses the header block of a snap
+// assertion. Multi-line header values, which are indented, are
+// accepted but not recorded.
+func parseSnapAssertionHeaders(block string) (snapAssertion, error) {
+	headers := make(snapAssertion)
+	for i, line := range strings.Split(block, "\n") {
+		if strings.HasPrefix(line, " ") {
+			if i == 0 {
+				return nil, errors.Errorf("unexpected indented line %q", line)
+			}
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Errorf("invalid header %q", line)
+		}
+		headers[parts[0]] = strings.TrimSpace(parts[1])
+	}
+	for _, name := range []string{"type", "sign-key-sha3-384"} {
+		if headers[name] == "" {
+			return nil, errors.Errorf("missing %q header", name)
+		}
+	}
+	return headers, nil
+}
@@ -19,6 +19,10 @@ const (
 	// come from those associated with the controller.
 	JujuControllerSource = "controller"
 
+	// JujuCloudSource is used to label model config attributes that come
+	// from those associated with the cloud where the model is running.
+	JujuCloudSource = "cloud"
+
 	// JujuRegionSource is used to label model config attributes that come from
 	// those associated with the region where the model is
 	// running.
@@ -27,8 +31,19 @@ const (
 	// JujuModelConfigSource is used to label model config attributes that
 	// have been explicitly set by the user.
 	JujuModelConfigSource = "model"
+
+	// JujuProfileSourcePrefix prefixes the labels of model config
+	// attributes that come from the config profile applied to the
+	// model when it was added. See ProfileSource.
+	JujuProfileSourcePrefix = "profile:"
 )
 
+// ProfileSource returns the label of model config attributes that come
+// from the named config profile.
+func ProfileSource(profileName string) string {
+	return JujuProfileSourcePrefix + profileName
+}
+
 // ConfigValue encapsulates a configuration
 // value and its source.
 type ConfigValue struct {
@@ -47,7 +62,7 @@ type ConfigValues map[string]ConfigValue
 func (c ConfigValues) AllAttrs() map[string]interface{} {
 	result := make(map[string]interface{})
 	for attr, val := range c {
-		result[attr] = val
+		result[attr] = val.Value
 	}
 	return result
 }
@@ -0,0 +1,116 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// AttributeError records why an attribute of a model configuration is
+// not valid.
+type AttributeError struct {
+	// Attribute is the name of the attribute, and is empty if the
+	// error concerns the configuration as a whole.
+	Attribute string
+
+	// Err describes what is wrong with the attribute.
+	Err error
+}
+
+// Error is part of the error interface.
+func (e AttributeError) Error() string {
+	return e.Err.Error()
+}
+
+// ValidationError is returned by Validate, and holds every reason that
+// a configuration is not valid, at most one for each attribute, so
+// that they can all be reported at once.
+type ValidationError struct {
+	// Errors holds the failures, those concerning the configuration
+	// as a whole first and then ordered by attribute name.
+	Errors []AttributeError
+}
+
+// Error is part of the error interface.
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Cause returns the cause of the only failure, if there is just one,
+// so that callers can still test for a particular kind of error with
+// errors.Cause.
+func (e *ValidationError) Cause() error {
+	if len(e.Errors) != 1 {
+		return nil
+	}
+	return errors.Cause(e.Errors[0].Err)
+}
+
+// add records a failure of the attribute. Only the first failure of an
+// attribute is kept, as later checks of the same value tend to repeat
+// it.
+func (e *ValidationError) add(attr string, err error) {
+	if err == nil {
+		return
+	}
+	if attr != "" {
+		for _, existing := range e.Errors {
+			if existing.Attribute == attr {
+				return
+			}
+		}
+	}
+	e.Errors = append(e.Errors, AttributeError{Attribute: attr, Err: err})
+}
+
+// merge records the failures described by err, which may itself be a
+// *ValidationError or an AttributeError.
+func (e *ValidationError) merge(err error) {
+	if err == nil {
+		return
+	}
+	if verr, ok := AsValidationError(err); ok {
+		for _, attrErr := range verr.Errors {
+			e.add(attrErr.Attribute, attrErr.Err)
+		}
+		return
+	}
+	if attrErr, ok := errors.Cause(err).(AttributeError); ok {
+		e.add(attrErr.Attribute, attrErr.Err)
+		return
+	}
+	e.add("", err)
+}
+
+func (e *ValidationError) sort() {
+	sort.SliceStable(e.Errors, func(i, j int) bool {
+		return e.Errors[i].Attribute < e.Errors[j].Attribute
+	})
+}
+
+// AsValidationError returns the *ValidationError that err was derived
+// from, if any. It is needed in place of errors.Cause, which sees
+// through a ValidationError holding a single failure.
+func AsValidationError(err error) (*ValidationError, bool) {
+	for err != nil {
+		if verr, ok := err.(*ValidationError); ok {
+			return verr, true
+		}
+		wrapper, ok := err.(interface {
+			Underlying() error
+		})
+		if !ok {
+			break
+		}
+		err = wrapper.Underlying()
+	}
+	return nil, false
+}
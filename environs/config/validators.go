@@ -0,0 +1,101 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/juju/errors"
+)
+
+// Validator checks a model configuration. If old is not nil, it holds
+// the previous configuration for consideration when validating
+// changes.
+type Validator func(cfg, old *Config) error
+
+// validatorKey identifies a registered Validator.
+type validatorKey struct {
+	providerType string
+	name         string
+}
+
+var validators = struct {
+	mu         sync.Mutex
+	registered map[validatorKey]Validator
+}{
+	registered: make(map[validatorKey]Validator),
+}
+
+// RegisterValidator registers a Validator to be run by Validate. The
+// validator is only run for configurations of the given provider
+// type, or for all configurations if providerType is empty. This lets
+// providers and other subsystems own the checks that this package
+// cannot make itself.
+//
+// RegisterValidator will panic if the name is registered more than
+// once for the provider type. The returned function unregisters the
+// validator, and is used by tests.
+func RegisterValidator(providerType, name string, v Validator) (unregister func()) {
+	key := validatorKey{providerType: providerType, name: name}
+	validators.mu.Lock()
+	defer validators.mu.Unlock()
+	if _, ok := validators.registered[key]; ok {
+		panic(fmt.Errorf("juju: duplicate config validator %q for provider type %q", name, providerType))
+	}
+	validators.registered[key] = v
+	return func() {
+		validators.mu.Lock()
+		defer validators.mu.Unlock()
+		delete(validators.registered, key)
+	}
+}
+
+// runValidators runs the registered validators that apply to cfg, in
+// name order, adding their failures to errs. Every validator is run,
+// so that all problems are reported at once.
+func runValidators(cfg, old *Config, errs *ValidationError) {
+	validators.mu.Lock()
+	var keys []validatorKey
+	for key := range validators.registered {
+		if key.providerType == "" || key.providerType == cfg.Type() {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].name != keys[j].name {
+			return keys[i].name < keys[j].name
+		}
+		return keys[i].providerType < keys[j].providerType
+	})
+	registered := make([]Validator, len(keys))
+	for i, key := range keys {
+		registered[i] = validators.registered[key]
+	}
+	validators.mu.Unlock()
+
+	for _, validate := range registered {
+		errs.merge(validate(cfg, old))
+	}
+}
+
+// ContainerNetworkingMethodValidator returns a Validator, for use by
+// providers, that rejects container-networking-method values other
+// than those given. An unsupported value is only rejected when it is
+// set or changed, so that existing models remain valid.
+func ContainerNetworkingMethodValidator(supported ...string) Validator {
+	return func(cfg, old *Config) error {
+		method := cfg.ContainerNetworkingMethod()
+		if method == "" || (old != nil && old.ContainerNetworkingMethod() == method) {
+			return nil
+		}
+		for _, s := range supported {
+			if method == s {
+				return nil
+			}
+		}
+		return errors.NotSupportedf("container-networking-method %q on %s", method, cfg.Type())
+	}
+}
@@ -7,8 +7,10 @@ import (
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/provider/dummy"
 	_ "github.com/juju/juju/provider/manual"
 	"github.com/juju/juju/testing"
@@ -135,3 +137,40 @@ func (s *suite) TestUnregisterProvider(c *gc.C) {
 	_, err = environs.Provider("alias2")
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
+
+type schemaProvider struct {
+	environs.EnvironProvider
+}
+
+func (schemaProvider) Schema() environschema.Fields {
+	return environschema.Fields{
+		"foo": {Description: "fooish", Type: environschema.Tstring},
+	}
+}
+
+type docsProvider struct {
+	schemaProvider
+}
+
+func (docsProvider) ConfigDocs() config.Docs {
+	return config.Docs{
+		"foo": {Deprecated: "use bar instead"},
+	}
+}
+
+func (s *suite) TestConfigDocs(c *gc.C) {
+	_, err := environs.ConfigDocs(&dummyProvider{})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+
+	docs, err := environs.ConfigDocs(schemaProvider{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(docs, jc.DeepEquals, config.Docs{
+		"foo": {Attr: environschema.Attr{Description: "fooish", Type: environschema.Tstring}},
+	})
+
+	docs, err = environs.ConfigDocs(docsProvider{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(docs, jc.DeepEquals, config.Docs{
+		"foo": {Deprecated: "use bar instead"},
+	})
+}
@@ -0,0 +1,127 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package dns defines the interface through which Juju manages the DNS
+// records of exposed applications, and the registry of DNS providers
+// that implement it. The DNS provider used by a model, and the zone it
+// manages records in, are selected by the dns-provider and dns-zone
+// model config attributes.
+package dns
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/network"
+)
+
+// HostnameAnnotation is the application annotation holding the
+// hostname at which the exposed application is published.
+const HostnameAnnotation = "hostname"
+
+// Record describes the DNS records for one hostname.
+type Record struct {
+	// Name is the fully qualified hostname, without a trailing dot.
+	Name string
+
+	// TTL is the time to live of the records, in seconds.
+	TTL int
+
+	// Addresses holds the addresses the hostname resolves to. The
+	// provider creates address records for IP addresses, and alias
+	// records for hostnames.
+	Addresses []network.Address
+}
+
+// Zone manages the records of a model's exposed applications in a DNS
+// zone. Implementations must only list and remove records that were
+// created for the model.
+type Zone interface {
+	// EnsureRecord creates the records described by the argument, or
+	// replaces the existing records for its name.
+	EnsureRecord(record Record) error
+
+	// Records returns the records managed for the model in the zone.
+	Records() ([]Record, error)
+
+	// RemoveRecord removes the records for the supplied name.
+	// Removing records that do not exist is not an error.
+	RemoveRecord(name string) error
+}
+
+// NewZoneFunc returns the Zone with the supplied name, managed on
+// behalf of the model of the supplied environ.
+type NewZoneFunc func(env environs.Environ, zone string) (Zone, error)
+
+var (
+	mu        sync.Mutex
+	providers = make(map[string]NewZoneFunc)
+)
+
+// Register registers the DNS provider with the supplied name. It panics
+// if a provider is already registered with the name. The returned
+// function unregisters the provider, and is used by tests.
+func Register(name string, newZone NewZoneFunc) (unregister func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := providers[name]; ok {
+		panic(fmt.Errorf("juju: duplicate DNS provider %q", name))
+	}
+	providers[name] = newZone
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(providers, name)
+	}
+}
+
+// Open returns the Zone selected by the environ's model config. The
+// returned error satisfies errors.IsNotFound if the config names a
+// DNS provider that is not registered.
+func Open(env environs.Environ) (Zone, error) {
+	cfg := env.Config()
+	name := cfg.DNSProvider()
+	if name == "" {
+		return nil, errors.NotValidf("model without a DNS provider")
+	}
+	mu.Lock()
+	newZone, ok := providers[name]
+	mu.Unlock()
+	if !ok {
+		return nil, errors.NotFoundf("DNS provider %q", name)
+	}
+	zone, err := newZone(env, cfg.DNSZone())
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot open DNS zone %q", cfg.DNSZone())
+	}
+	return zone, nil
+}
+
+// ValidateProvider checks that the named DNS provider is registered,
+// so that a model config naming a provider that this controller
+// cannot use is rejected rather than ignored. The empty name, which
+// disables DNS management, is always valid.
+func ValidateProvider(name string) error {
+	if name == "" {
+		return nil
+	}
+	mu.Lock()
+	_, ok := providers[name]
+	mu.Unlock()
+	if !ok {
+		return errors.NotSupportedf("dns-provider %q", name)
+	}
+	return nil
+}
+
+// InZone reports whether the hostname lies within the zone. Neither
+// may have a trailing dot.
+func InZone(hostname, zone string) bool {
+	hostname = strings.ToLower(hostname)
+	zone = strings.ToLower(zone)
+	return hostname == zone || strings.HasSuffix(hostname, "."+zone)
+}
@@ -0,0 +1,127 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package dns_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/dns"
+	"github.com/juju/juju/testing"
+)
+
+type dnsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&dnsSuite{})
+
+type mockEnviron struct {
+	environs.Environ
+	cfg *config.Config
+}
+
+func (e *mockEnviron) Config() *config.Config {
+	return e.cfg
+}
+
+type mockZone struct {
+	dns.Zone
+	name string
+}
+
+func (s *dnsSuite) newEnviron(c *gc.C, attrs testing.Attrs) environs.Environ {
+	cfg, err := config.New(config.UseDefaults, testing.FakeConfig().Merge(attrs))
+	c.Assert(err, jc.ErrorIsNil)
+	return &mockEnviron{cfg: cfg}
+}
+
+func (s *dnsSuite) TestOpen(c *gc.C) {
+	env := s.newEnviron(c, testing.Attrs{
+		"dns-provider": "test",
+		"dns-zone":     "example.com.",
+	})
+	unregister := dns.Register("test", func(got environs.Environ, zone string) (dns.Zone, error) {
+		c.Check(got, gc.Equals, env)
+		return &mockZone{name: zone}, nil
+	})
+	defer unregister()
+
+	zone, err := dns.Open(env)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zone, jc.DeepEquals, &mockZone{name: "example.com"})
+}
+
+func (s *dnsSuite) TestOpenError(c *gc.C) {
+	env := s.newEnviron(c, testing.Attrs{
+		"dns-provider": "test",
+		"dns-zone":     "example.com",
+	})
+	unregister := dns.Register("test", func(environs.Environ, string) (dns.Zone, error) {
+		return nil, errors.New("no credentials")
+	})
+	defer unregister()
+
+	_, err := dns.Open(env)
+	c.Assert(err, gc.ErrorMatches, `cannot open DNS zone "example.com": no credentials`)
+}
+
+func (s *dnsSuite) TestOpenUnregistered(c *gc.C) {
+	env := s.newEnviron(c, testing.Attrs{
+		"dns-provider": "unknown",
+		"dns-zone":     "example.com",
+	})
+	_, err := dns.Open(env)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `DNS provider "unknown" not found`)
+}
+
+func (s *dnsSuite) TestOpenNoProvider(c *gc.C) {
+	_, err := dns.Open(s.newEnviron(c, nil))
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *dnsSuite) TestRegisterDuplicate(c *gc.C) {
+	newZone := func(environs.Environ, string) (dns.Zone, error) {
+		return nil, nil
+	}
+	unregister := dns.Register("test", newZone)
+	defer unregister()
+	c.Assert(func() { dns.Register("test", newZone) }, gc.PanicMatches, `juju: duplicate DNS provider "test"`)
+}
+
+func (s *dnsSuite) TestValidateProvider(c *gc.C) {
+	unregister := dns.Register("test", func(environs.Environ, string) (dns.Zone, error) {
+		return nil, nil
+	})
+	defer unregister()
+	c.Assert(dns.ValidateProvider("test"), jc.ErrorIsNil)
+	c.Assert(dns.ValidateProvider(""), jc.ErrorIsNil)
+
+	err := dns.ValidateProvider("route53")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, `dns-provider "route53" not supported`)
+}
+
+func (s *dnsSuite) TestInZone(c *gc.C) {
+	for i, test := range []struct {
+		hostname string
+		zone     string
+		expected bool
+	}{
+		{"example.com", "example.com", true},
+		{"blog.example.com", "example.com", true},
+		{"Blog.Example.COM", "example.com", true},
+		{"a.b.example.com", "example.com", true},
+		{"badexample.com", "example.com", false},
+		{"example.org", "example.com", false},
+		{"com", "example.com", false},
+	} {
+		c.Logf("test %d: %s in %s", i, test.hostname, test.zone)
+		c.Check(dns.InZone(test.hostname, test.zone), gc.Equals, test.expected)
+	}
+}
@@ -41,3 +41,47 @@ func IsAvailabilityZoneIndependent(err error) bool {
 	}
 	return false
 }
+
+// CredentialError provides an interface for compute providers to
+// indicate that an operation failed because the cloud credential was
+// rejected, so that attempting it again will fail in the same way
+// until the credential is updated.
+type CredentialError interface {
+	error
+
+	// CredentialNotValid reports whether or not the error was
+	// caused by the cloud credential being rejected.
+	CredentialNotValid() bool
+}
+
+// IsCredentialNotValid reports whether or not the given error, or its
+// cause, was caused by the cloud credential being rejected. Juju uses
+// this to abandon, rather than retry, failed provider operations.
+func IsCredentialNotValid(err error) bool {
+	if err, ok := errors.Cause(err).(CredentialError); ok {
+		return err.CredentialNotValid()
+	}
+	return false
+}
+
+// TransientError provides an interface for compute providers to
+// indicate that an operation failed for a reason, such as rate
+// limiting or a timeout, that is expected to pass.
+type TransientError interface {
+	error
+
+	// Transient reports whether or not the error is expected
+	// to pass if the operation is attempted again.
+	Transient() bool
+}
+
+// IsTransient reports whether or not the given error, or its cause, is
+// expected to pass if the failed operation is attempted again. Juju
+// retries transient failures without counting them against the number
+// of attempts allowed for an operation.
+func IsTransient(err error) bool {
+	if err, ok := errors.Cause(err).(TransientError); ok {
+		return err.Transient()
+	}
+	return false
+}
@@ -4,6 +4,7 @@
 package environs
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/juju/errors"
@@ -83,16 +84,22 @@ func UnregisterImageDataSourceFunc(id string) {
 func ImageMetadataSources(env Environ) ([]simplestreams.DataSource, error) {
 	config := env.Config()
 
-	// Add configured and environment-specific datasources.
+	// Add configured and environment-specific datasources. The
+	// image-metadata-urls mirrors follow image-metadata-url, so
+	// that each is searched in turn should the previous fail.
 	var sources []simplestreams.DataSource
+	verify := utils.VerifySSLHostnames
+	if !config.SSLHostnameVerification() {
+		verify = utils.NoVerifySSLHostnames
+	}
+	publicKey, _ := simplestreams.UserPublicSigningKey()
 	if userURL, ok := config.ImageMetadataURL(); ok {
-		verify := utils.VerifySSLHostnames
-		if !config.SSLHostnameVerification() {
-			verify = utils.NoVerifySSLHostnames
-		}
-		publicKey, _ := simplestreams.UserPublicSigningKey()
 		sources = append(sources, simplestreams.NewURLSignedDataSource("image-metadata-url", userURL, publicKey, verify, simplestreams.SPECIFIC_CLOUD_DATA, false))
 	}
+	for i, mirrorURL := range config.ImageMetadataURLs() {
+		description := fmt.Sprintf("image-metadata-urls[%d]", i)
+		sources = append(sources, simplestreams.NewURLSignedDataSource(description, mirrorURL, publicKey, verify, simplestreams.SPECIFIC_CLOUD_DATA, false))
+	}
 
 	envDataSources, err := environmentDataSources(env)
 	if err != nil {
@@ -81,6 +81,28 @@ func (s *ImageMetadataSuite) TestImageMetadataURLs(c *gc.C) {
 	})
 }
 
+func (s *ImageMetadataSuite) TestImageMetadataURLsMirrors(c *gc.C) {
+	env := s.env(c, "config-image-metadata-url", "")
+	cfg, err := env.Config().Apply(map[string]interface{}{
+		"image-metadata-urls": []interface{}{"mirror-1", "mirror-2"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = env.SetConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	sources, err := environs.ImageMetadataSources(env)
+	c.Assert(err, jc.ErrorIsNil)
+	sstesting.AssertExpectedSources(c, sources, []sstesting.SourceDetails{
+		{"config-image-metadata-url/", ""},
+		{"mirror-1/", ""},
+		{"mirror-2/", ""},
+		{"https://streams.canonical.com/juju/images/releases/", keys.JujuPublicKey},
+		{"http://cloud-images.ubuntu.com/releases/", imagemetadata.SimplestreamsImagesPublicKey},
+	})
+	c.Check(sources[1].Description(), gc.Equals, "image-metadata-urls[0]")
+	c.Check(sources[2].Description(), gc.Equals, "image-metadata-urls[1]")
+}
+
 func (s *ImageMetadataSuite) TestImageMetadataURLsRegisteredFuncs(c *gc.C) {
 	environs.RegisterImageDataSourceFunc("id0", func(environs.Environ) (simplestreams.DataSource, error) {
 		return simplestreams.NewURLDataSource("id0", "betwixt/releases", utils.NoVerifySSLHostnames, simplestreams.DEFAULT_CLOUD_DATA, false), nil
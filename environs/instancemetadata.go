@@ -0,0 +1,71 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+)
+
+// InstanceMetadata holds provider-specific details of an instance,
+// normalised so that they may be reported alike for every provider.
+// Fields the provider does not support are left empty.
+type InstanceMetadata struct {
+	// InstanceId is the provider ID of the instance.
+	InstanceId instance.Id
+
+	// InstanceProfile is the name of the instance profile, role or
+	// service account under which the instance runs.
+	InstanceProfile string
+
+	// ImageId is the provider ID of the image the instance was
+	// started from.
+	ImageId string
+
+	// Tenancy describes how the instance shares its host with other
+	// instances, eg "default", "dedicated" or "host".
+	Tenancy string
+
+	// NetworkInterfaces holds the network interfaces attached to the
+	// instance.
+	NetworkInterfaces []InstanceNetworkInterface
+}
+
+// InstanceNetworkInterface describes a network interface attached to
+// an instance, as reported by the provider.
+type InstanceNetworkInterface struct {
+	// ProviderId is the provider ID of the interface.
+	ProviderId network.Id
+
+	// DeviceIndex is the index of the interface on the instance.
+	DeviceIndex int
+
+	// MACAddress is the hardware address of the interface.
+	MACAddress string
+
+	// ProviderSubnetId is the provider ID of the subnet the interface
+	// is attached to.
+	ProviderSubnetId network.Id
+
+	// Addresses holds the addresses assigned to the interface.
+	Addresses []network.Address
+}
+
+// InstanceMetadataGetter defines the methods of environments that can
+// report provider-specific metadata for their instances.
+type InstanceMetadataGetter interface {
+	// InstanceMetadata returns the metadata of the instances with the
+	// given IDs, in the same order. As with Environ.Instances, an
+	// instance that cannot be found has a nil entry and the error
+	// returned is ErrPartialInstances; if none can be found, the error
+	// is ErrNoInstances.
+	InstanceMetadata(ids []instance.Id) ([]*InstanceMetadata, error)
+}
+
+// SupportsInstanceMetadata returns the environ as an
+// InstanceMetadataGetter, and whether it can report instance metadata.
+func SupportsInstanceMetadata(env Environ) (InstanceMetadataGetter, bool) {
+	getter, ok := env.(InstanceMetadataGetter)
+	return getter, ok
+}
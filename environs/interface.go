@@ -73,6 +73,15 @@ type ProviderSchema interface {
 	Schema() environschema.Fields
 }
 
+// ProviderConfigDocs can be implemented by a provider to document its
+// configuration with guidance that its schema cannot record, such as
+// which of its fields are deprecated.
+type ProviderConfigDocs interface {
+	// ConfigDocs returns documentation for all the fields in the
+	// provider's schema, conventionally by calling config.SchemaDocs.
+	ConfigDocs() config.Docs
+}
+
 // PrepareConfigParams contains the parameters for EnvironProvider.PrepareConfig.
 type PrepareConfigParams struct {
 	// Cloud is the cloud specification to use to connect to the cloud.
@@ -408,6 +417,16 @@ type Firewaller interface {
 	IngressRules() ([]network.IngressRule, error)
 }
 
+// ControllerFirewaller is an interface that may be implemented by an
+// Environ that can manage the ingress rules of the controller's own
+// instances. The Environ of the controller model is used to apply them.
+type ControllerFirewaller interface {
+	// SetControllerIngressRules sets the rules governing ingress to the
+	// instances of the controller with the given UUID, replacing any
+	// rules previously set. Traffic not matched by a rule is refused.
+	SetControllerIngressRules(controllerUUID string, rules []network.IngressRule) error
+}
+
 // InstanceTagger is an interface that can be used for tagging instances.
 type InstanceTagger interface {
 	// TagInstance tags the given instance with the specified tags.
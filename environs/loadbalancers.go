@@ -0,0 +1,118 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+)
+
+// LoadBalancerSpec describes a load balancer that distributes traffic
+// for the ports opened for one endpoint of an exposed application
+// across the instances hosting its units.
+type LoadBalancerSpec struct {
+	// Name uniquely identifies the load balancer within the model.
+	Name string
+
+	// Type is the type of load balancer to provision, as accepted by
+	// the load-balancer-type model config attribute.
+	Type string
+
+	// Instances holds the instances that traffic is distributed
+	// across.
+	Instances []instance.Id
+
+	// PortRanges holds the port ranges the load balancer forwards.
+	PortRanges []network.PortRange
+
+	// SourceCIDRs holds the CIDRs that may access the load balancer.
+	SourceCIDRs []string
+
+	// HealthCheck describes how the load balancer determines which
+	// instances are healthy.
+	HealthCheck HealthCheck
+}
+
+// HealthCheck describes how a load balancer checks the health of the
+// instances it distributes traffic across.
+type HealthCheck struct {
+	// Protocol is either "tcp", to check that a connection can be made
+	// to Port, or "http", to check that requesting Path on Port
+	// succeeds.
+	Protocol string
+
+	// Port is the port checked.
+	Port int
+
+	// Path is the HTTP request path checked, if Protocol is "http".
+	Path string
+
+	// Interval is the time between checks.
+	Interval time.Duration
+
+	// Threshold is the number of consecutive checks that must
+	// succeed or fail to change the health of an instance.
+	Threshold int
+}
+
+// LoadBalancer describes a load balancer provisioned by the provider.
+type LoadBalancer struct {
+	// Name is the name from the load balancer's spec.
+	Name string
+
+	// Addresses holds the addresses that clients of the load balancer
+	// connect to.
+	Addresses []network.Address
+}
+
+// LoadBalancers defines the methods of environments that can
+// provision load balancers for exposed applications.
+type LoadBalancers interface {
+	// EnsureLoadBalancer creates the load balancer described by the
+	// spec, or updates the existing load balancer with the spec's
+	// name to match it. The returned error satisfies
+	// errors.IsNotSupported if the provider cannot provision the
+	// spec's type of load balancer.
+	EnsureLoadBalancer(spec LoadBalancerSpec) (*LoadBalancer, error)
+
+	// LoadBalancers returns the load balancers provisioned for the
+	// model.
+	LoadBalancers() ([]LoadBalancer, error)
+
+	// RemoveLoadBalancer removes the named load balancer. Removing a
+	// load balancer that does not exist is not an error.
+	RemoveLoadBalancer(name string) error
+}
+
+// LoadBalancerEnviron combines the standard Environ interface with the
+// functionality for provisioning load balancers.
+type LoadBalancerEnviron interface {
+	Environ
+	LoadBalancers
+}
+
+// SupportsLoadBalancers returns the environ as a LoadBalancerEnviron,
+// and whether it can provision load balancers.
+func SupportsLoadBalancers(env Environ) (LoadBalancerEnviron, bool) {
+	lbEnv, ok := env.(LoadBalancerEnviron)
+	return lbEnv, ok
+}
+
+// ValidateLoadBalancerType checks that the environ can provision the
+// given type of load balancer. Any type other than "none" is rejected
+// unless the environ supports load balancers.
+func ValidateLoadBalancerType(env Environ, lbType string) error {
+	if lbType == config.LoadBalancerNone {
+		return nil
+	}
+	if _, ok := SupportsLoadBalancers(env); !ok {
+		return errors.NotSupportedf("load-balancer-type %q on %s", lbType, env.Config().Type())
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sshprovisioner
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"github.com/juju/utils/ssh"
+)
+
+// HostProbe holds what was learned about a host's environment by
+// probing it ahead of installing Juju on it.
+type HostProbe struct {
+	// InitSystem is the name of the init system running on the host,
+	// or "unknown" if it could not be determined.
+	InitSystem string
+
+	// CgroupsMounted reports whether the cgroup filesystem is
+	// mounted on the host.
+	CgroupsMounted bool
+
+	// DiskFreeMB is the free space, in megabytes, on the filesystem
+	// holding /var/lib, where Juju keeps its data.
+	DiskFreeMB uint64
+
+	// PortsInUse holds the TCP ports that are being listened on.
+	PortsInUse []int
+
+	// MissingPackages holds those of the probed packages that are
+	// not installed. It is always empty on hosts without dpkg.
+	MissingPackages []string
+}
+
+// ProbeHost probes the environment of the remote machine by connecting
+// to it and executing a bash script, reporting which of the specified
+// packages are not installed.
+var ProbeHost = probeHost
+
+func probeHost(host string, packages []string) (HostProbe, error) {
+	logger.Infof("Probing environment on %s", host)
+	cmd := ssh.Command("ubuntu@"+host, []string{"/bin/bash"}, nil)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Stdin = strings.NewReader(probeScript(packages))
+	if err := cmd.Run(); err != nil {
+		if stderr.Len() != 0 {
+			err = fmt.Errorf("%v (%v)", err, strings.TrimSpace(stderr.String()))
+		}
+		return HostProbe{}, err
+	}
+	probe, err := parseHostProbe(stdout.String())
+	if err != nil {
+		return HostProbe{}, errors.Annotate(err, "parsing probe output")
+	}
+	logger.Infof("init system: %s, cgroups mounted: %v, free disk: %dMB",
+		probe.InitSystem, probe.CgroupsMounted, probe.DiskFreeMB,
+	)
+	return probe, nil
+}
+
+// probeScript returns the script to run on the remote machine to probe
+// its environment. Each line of output holds a "key: value" pair.
+func probeScript(packages []string) string {
+	quoted := make([]string, len(packages))
+	for i, pkg := range packages {
+		quoted[i] = utils.ShQuote(pkg)
+	}
+	return fmt.Sprintf(probeScriptTemplate, strings.Join(quoted, " "))
+}
+
+const probeScriptTemplate = `#!/bin/bash
+if [ -d /run/systemd/system ]; then
+  echo "init: systemd"
+elif /sbin/initctl version 2>/dev/null | grep -q upstart; then
+  echo "init: upstart"
+else
+  echo "init: unknown"
+fi
+if grep -q '^cgroup' /proc/mounts; then
+  echo "cgroups: yes"
+else
+  echo "cgroups: no"
+fi
+echo "disk-free-mb: $(df -Pm /var/lib | awk 'NR == 2 {print $4}')"
+echo "ports-in-use:" $(ss -tln 2>/dev/null | awk 'NR > 1 {n = split($4, a, ":"); print a[n]}' | sort -un)
+missing=""
+if which dpkg-query > /dev/null; then
+  for pkg in %s; do
+    dpkg-query -W -f='${Status}' "$pkg" 2>/dev/null | grep -q 'ok installed' || missing="$missing $pkg"
+  done
+fi
+echo "missing-packages:" $missing`
+
+func parseHostProbe(output string) (HostProbe, error) {
+	var probe HostProbe
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		switch key {
+		case "init":
+			probe.InitSystem = value
+		case "cgroups":
+			probe.CgroupsMounted = value == "yes"
+		case "disk-free-mb":
+			free, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return HostProbe{}, errors.Annotate(err, "free disk space")
+			}
+			probe.DiskFreeMB = free
+		case "ports-in-use":
+			for _, field := range strings.Fields(value) {
+				port, err := strconv.Atoi(field)
+				if err != nil {
+					return HostProbe{}, errors.Annotate(err, "ports in use")
+				}
+				probe.PortsInUse = append(probe.PortsInUse, port)
+			}
+		case "missing-packages":
+			if value != "" {
+				probe.MissingPackages = strings.Fields(value)
+			}
+		}
+	}
+	if probe.InitSystem == "" {
+		return HostProbe{}, errors.New("init system not reported")
+	}
+	return probe, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sshprovisioner_test
+
+import (
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/manual/sshprovisioner"
+	"github.com/juju/juju/testing"
+)
+
+type probeSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&probeSuite{})
+
+func (s *probeSuite) TestProbeHost(c *gc.C) {
+	packages := []string{"curl", "tmux"}
+	response := strings.Join([]string{
+		"init: systemd",
+		"cgroups: yes",
+		"disk-free-mb: 20480",
+		"ports-in-use: 22 17070",
+		"missing-packages: tmux",
+	}, "\n")
+	defer installFakeSSH(c, sshprovisioner.ProbeScript(packages), response, 0)()
+	probe, err := sshprovisioner.ProbeHost("hostname", packages)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(probe, jc.DeepEquals, sshprovisioner.HostProbe{
+		InitSystem:      "systemd",
+		CgroupsMounted:  true,
+		DiskFreeMB:      20480,
+		PortsInUse:      []int{22, 17070},
+		MissingPackages: []string{"tmux"},
+	})
+}
+
+func (s *probeSuite) TestProbeHostNothingMissing(c *gc.C) {
+	response := strings.Join([]string{
+		"init: upstart",
+		"cgroups: no",
+		"disk-free-mb: 100",
+		"ports-in-use:",
+		"missing-packages:",
+	}, "\n")
+	defer installFakeSSH(c, sshprovisioner.ProbeScript(nil), response, 0)()
+	probe, err := sshprovisioner.ProbeHost("hostname", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(probe, jc.DeepEquals, sshprovisioner.HostProbe{
+		InitSystem: "upstart",
+		DiskFreeMB: 100,
+	})
+}
+
+func (s *probeSuite) TestProbeHostInvalidOutput(c *gc.C) {
+	defer installFakeSSH(c, sshprovisioner.ProbeScript(nil), "disk-free-mb: lots", 0)()
+	_, err := sshprovisioner.ProbeHost("hostname", nil)
+	c.Assert(err, gc.ErrorMatches, `parsing probe output: free disk space: .*invalid syntax`)
+
+	defer installFakeSSH(c, sshprovisioner.ProbeScript(nil), "cgroups: yes", 0)()
+	_, err = sshprovisioner.ProbeHost("hostname", nil)
+	c.Assert(err, gc.ErrorMatches, `parsing probe output: init system not reported`)
+}
+
+func (s *probeSuite) TestProbeHostError(c *gc.C) {
+	defer installFakeSSH(c, sshprovisioner.ProbeScript(nil), []string{"", "oh noes"}, 33)()
+	_, err := sshprovisioner.ProbeHost("hostname", nil)
+	c.Assert(err, gc.ErrorMatches, `subprocess encountered error code 33 \(oh noes\)`)
+}
@@ -0,0 +1,265 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package mirror provides an API for maintaining simplestreams mirrors
+// of agent binary and image metadata. It generates the metadata, signs
+// it, validates the signatures and publishes the result to a storage
+// backend, so that site tooling can maintain a mirror without going
+// through the juju-metadata plugin.
+package mirror
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/simplestreams"
+	"github.com/juju/juju/environs/storage"
+	envtools "github.com/juju/juju/environs/tools"
+	coretools "github.com/juju/juju/tools"
+)
+
+var logger = loggo.GetLogger("juju.environs.mirror")
+
+// ToolsParams holds the parameters for generating agent binary
+// metadata.
+type ToolsParams struct {
+	// Stream is the stream the metadata is generated for.
+	Stream string
+
+	// ToolsDir is the directory, relative to the storage's "tools"
+	// directory, that holds the agent binary tarballs. It defaults to
+	// the stream.
+	ToolsDir string
+
+	// Tools holds the agent binaries to add to the metadata. Any whose
+	// size or hash is unknown are fetched from storage to compute it.
+	Tools coretools.List
+
+	// Clean causes any existing metadata for the stream to be
+	// discarded rather than merged with.
+	Clean bool
+
+	// Public causes mirror information to be written, as required for
+	// the metadata of a public cloud.
+	Public bool
+
+	// SigningKey, if set, holds the armored OpenPGP private key with
+	// which each unsigned agent binary tarball is signed, so that
+	// controllers configured with agent-binary-trusted-keys accept it.
+	SigningKey string
+
+	// Passphrase decrypts SigningKey, if it is encrypted.
+	Passphrase string
+}
+
+// GenerateToolsMetadata merges metadata for the given agent binaries
+// with the metadata already in storage, and writes the result back.
+func GenerateToolsMetadata(stor storage.Storage, params ToolsParams) error {
+	toolsDir := params.ToolsDir
+	if toolsDir == "" {
+		toolsDir = params.Stream
+	}
+	existing, err := envtools.ReadAllMetadata(stor)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if params.Clean {
+		delete(existing, params.Stream)
+	}
+	metadata := envtools.MetadataFromTools(params.Tools, toolsDir)
+	merged, err := envtools.MergeMetadata(metadata, existing[params.Stream])
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := envtools.ResolveMetadata(stor, toolsDir, merged); err != nil {
+		return errors.Trace(err)
+	}
+	if params.SigningKey != "" {
+		err := envtools.SignMetadata(stor, toolsDir, merged, params.SigningKey, params.Passphrase)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	existing[params.Stream] = merged
+	writeMirrors := envtools.DoNotWriteMirrors
+	if params.Public {
+		writeMirrors = envtools.WriteMirrors
+	}
+	return errors.Trace(envtools.WriteMetadata(stor, existing, []string{params.Stream}, writeMirrors))
+}
+
+// ImageParams holds the parameters for generating image metadata.
+type ImageParams struct {
+	// Series is the series of the images.
+	Series string
+
+	// Images holds the images to add to the metadata.
+	Images []*imagemetadata.ImageMetadata
+
+	// CloudSpec identifies the cloud region the images are in.
+	CloudSpec simplestreams.CloudSpec
+}
+
+// GenerateImageMetadata merges metadata for the given images with the
+// metadata already in storage, and writes the result back.
+func GenerateImageMetadata(stor storage.Storage, params ImageParams) error {
+	return errors.Trace(imagemetadata.MergeAndWriteMetadata(
+		params.Series, params.Images, &params.CloudSpec, stor,
+	))
+}
+
+// SignMetadata searches the directory tree rooted at dir for unsigned
+// metadata files and inline signs each with the armored private key,
+// decrypted with the passphrase if necessary. For each .json file a
+// corresponding .sjson file is written.
+func SignMetadata(dir, key, passphrase string) error {
+	logger.Debugf("processing directory %q", dir)
+	filenames, err := filepath.Glob(filepath.Join(dir, "*"+simplestreams.UnsignedSuffix))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(filenames) > 0 {
+		logger.Infof("signing %d file(s) in %q", len(filenames), dir)
+	}
+	for _, filename := range filenames {
+		logger.Infof("signing file %q", filename)
+		if err := signFile(filename, key, passphrase); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, f := range files {
+		if f.IsDir() {
+			if err := SignMetadata(filepath.Join(dir, f.Name()), key, passphrase); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func signFile(filename, key, passphrase string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return errors.Errorf("opening file %q: %v", filename, err)
+	}
+	defer f.Close()
+	encoded, err := simplestreams.Encode(f, key, passphrase)
+	if err != nil {
+		return errors.Errorf("encoding file %q: %v", filename, err)
+	}
+	signedFilename := strings.TrimSuffix(filename, simplestreams.UnsignedSuffix) + simplestreams.SignedSuffix
+	if err := ioutil.WriteFile(signedFilename, encoded, 0644); err != nil {
+		return errors.Errorf("writing signed file %q: %v", signedFilename, err)
+	}
+	return nil
+}
+
+// ValidateSignatures checks that every signed metadata file in storage
+// was signed with the armored public key, and that its content matches
+// that of the corresponding unsigned file, if there is one.
+func ValidateSignatures(stor storage.StorageReader, publicKey string) error {
+	names, err := listMirror(stor)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, name := range names {
+		if !strings.HasSuffix(name, simplestreams.SignedSuffix) {
+			continue
+		}
+		signed, err := storage.Get(stor, name)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		content, err := simplestreams.DecodeCheckSignature(signed, publicKey)
+		signed.Close()
+		if err != nil {
+			return errors.Annotatef(err, "invalid signature for %q", name)
+		}
+		unsignedName := strings.TrimSuffix(name, simplestreams.SignedSuffix) + simplestreams.UnsignedSuffix
+		unsigned, err := storage.Get(stor, unsignedName)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		unsignedContent, err := ioutil.ReadAll(unsigned)
+		unsigned.Close()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !bytes.Equal(bytes.TrimSpace(content), bytes.TrimSpace(unsignedContent)) {
+			return errors.Errorf("signed %q does not match %q", name, unsignedName)
+		}
+	}
+	return nil
+}
+
+// Publish copies the agent binaries, images and metadata in the source
+// storage to the destination storage, returning the names of the files
+// copied. Index files are copied last, and metadata after the files it
+// refers to, so that a mirror being published to never refers to files
+// it does not yet hold.
+func Publish(src storage.StorageReader, dst storage.Storage) ([]string, error) {
+	names, err := listMirror(src)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var files, metadata, indexes []string
+	for _, name := range names {
+		switch {
+		case !strings.Contains(name, "/streams/"):
+			files = append(files, name)
+		case strings.HasPrefix(path.Base(name), "index"):
+			indexes = append(indexes, name)
+		default:
+			metadata = append(metadata, name)
+		}
+	}
+	published := append(append(files, metadata...), indexes...)
+	for _, name := range published {
+		logger.Infof("publishing %q", name)
+		if err := copyFile(src, dst, name); err != nil {
+			return nil, errors.Annotatef(err, "cannot publish %q", name)
+		}
+	}
+	return published, nil
+}
+
+func copyFile(src storage.StorageReader, dst storage.Storage, name string) error {
+	r, err := storage.Get(src, name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(dst.Put(name, bytes.NewReader(data), int64(len(data))))
+}
+
+// listMirror returns the names of the agent binaries, images and
+// metadata files in storage.
+func listMirror(stor storage.StorageReader) ([]string, error) {
+	var names []string
+	for _, prefix := range []string{storage.BaseToolsPath, storage.BaseImagesPath} {
+		prefixNames, err := storage.List(stor, prefix+"/")
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		names = append(names, prefixNames...)
+	}
+	return names, nil
+}
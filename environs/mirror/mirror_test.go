@@ -0,0 +1,170 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package mirror_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/filestorage"
+	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/mirror"
+	"github.com/juju/juju/environs/simplestreams"
+	sstesting "github.com/juju/juju/environs/simplestreams/testing"
+	"github.com/juju/juju/environs/storage"
+	envtools "github.com/juju/juju/environs/tools"
+	coretesting "github.com/juju/juju/testing"
+	coretools "github.com/juju/juju/tools"
+)
+
+type mirrorSuite struct {
+	coretesting.BaseSuite
+	dir  string
+	stor storage.Storage
+}
+
+var _ = gc.Suite(&mirrorSuite{})
+
+var testVersion = version.MustParseBinary("2.3.4-xenial-amd64")
+
+func (s *mirrorSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+	var err error
+	s.stor, err = filestorage.NewFileStorageWriter(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *mirrorSuite) generateToolsMetadata(c *gc.C) {
+	tarball := "agent binary"
+	err := s.stor.Put(envtools.StorageName(testVersion, "released"), strings.NewReader(tarball), int64(len(tarball)))
+	c.Assert(err, jc.ErrorIsNil)
+	err = mirror.GenerateToolsMetadata(s.stor, mirror.ToolsParams{
+		Stream: "released",
+		Tools:  coretools.List{{Version: testVersion}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *mirrorSuite) TestGenerateToolsMetadata(c *gc.C) {
+	s.generateToolsMetadata(c)
+
+	metadata, err := envtools.ReadAllMetadata(s.stor)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata["released"], gc.HasLen, 1)
+	md := metadata["released"][0]
+	c.Assert(md.Version, gc.Equals, "2.3.4")
+	c.Assert(md.Path, gc.Equals, "released/juju-2.3.4-xenial-amd64.tgz")
+	c.Assert(md.Size, gc.Equals, int64(len("agent binary")))
+	c.Assert(md.SHA256, gc.Equals, fmt.Sprintf("%x", sha256.Sum256([]byte("agent binary"))))
+}
+
+func (s *mirrorSuite) TestGenerateToolsMetadataSigned(c *gc.C) {
+	tarball := "agent binary"
+	err := s.stor.Put(envtools.StorageName(testVersion, "released"), strings.NewReader(tarball), int64(len(tarball)))
+	c.Assert(err, jc.ErrorIsNil)
+	err = mirror.GenerateToolsMetadata(s.stor, mirror.ToolsParams{
+		Stream:     "released",
+		Tools:      coretools.List{{Version: testVersion}},
+		SigningKey: sstesting.SignedMetadataPrivateKey,
+		Passphrase: sstesting.PrivateKeyPassphrase,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	metadata, err := envtools.ReadAllMetadata(s.stor)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata["released"], gc.HasLen, 1)
+	tools := &coretools.Tools{
+		Version:   testVersion,
+		Signature: metadata["released"][0].Signature,
+	}
+	err = envtools.VerifySignature(strings.NewReader(tarball), tools, sstesting.SignedMetadataPublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *mirrorSuite) TestGenerateImageMetadata(c *gc.C) {
+	err := mirror.GenerateImageMetadata(s.stor, mirror.ImageParams{
+		Series: "xenial",
+		Images: []*imagemetadata.ImageMetadata{{
+			Id:   "ami-1234",
+			Arch: "amd64",
+		}},
+		CloudSpec: simplestreams.CloudSpec{
+			Region:   "region",
+			Endpoint: "https://endpoint",
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	names, err := storage.List(s.stor, "images/streams/v1/")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.SameContents, []string{
+		"images/streams/v1/com.ubuntu.cloud-released-imagemetadata.json",
+		"images/streams/v1/index.json",
+	})
+}
+
+func (s *mirrorSuite) TestSignAndValidate(c *gc.C) {
+	s.generateToolsMetadata(c)
+	err := mirror.SignMetadata(s.dir, sstesting.SignedMetadataPrivateKey, sstesting.PrivateKeyPassphrase)
+	c.Assert(err, jc.ErrorIsNil)
+
+	signed, err := storage.List(s.stor, "tools/streams/v1/index2.sjson")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(signed, gc.HasLen, 1)
+
+	err = mirror.ValidateSignatures(s.stor, sstesting.SignedMetadataPublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *mirrorSuite) TestValidateSignaturesMismatch(c *gc.C) {
+	s.generateToolsMetadata(c)
+	err := mirror.SignMetadata(s.dir, sstesting.SignedMetadataPrivateKey, sstesting.PrivateKeyPassphrase)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Regenerating the unsigned metadata without re-signing leaves
+	// the signed metadata stale.
+	err = ioutil.WriteFile(filepath.Join(s.dir, "tools", "streams", "v1", "index2.json"), []byte("{}"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	err = mirror.ValidateSignatures(s.stor, sstesting.SignedMetadataPublicKey)
+	c.Assert(err, gc.ErrorMatches, `signed "tools/streams/v1/index2.sjson" does not match "tools/streams/v1/index2.json"`)
+}
+
+func (s *mirrorSuite) TestValidateSignaturesUnknownKey(c *gc.C) {
+	s.generateToolsMetadata(c)
+	err := mirror.SignMetadata(s.dir, sstesting.SignedMetadataPrivateKey, sstesting.PrivateKeyPassphrase)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = mirror.ValidateSignatures(s.stor, "not a key")
+	c.Assert(err, gc.ErrorMatches, `invalid signature for ".*\.sjson": .*`)
+}
+
+func (s *mirrorSuite) TestPublish(c *gc.C) {
+	s.generateToolsMetadata(c)
+	dst, err := filestorage.NewFileStorageWriter(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+
+	published, err := mirror.Publish(s.stor, dst)
+	c.Assert(err, jc.ErrorIsNil)
+	// The tarball is published before the metadata referring to it,
+	// and the indexes last of all.
+	c.Assert(published, jc.DeepEquals, []string{
+		"tools/released/juju-2.3.4-xenial-amd64.tgz",
+		"tools/streams/v1/com.ubuntu.juju-released-tools.json",
+		"tools/streams/v1/index.json",
+		"tools/streams/v1/index2.json",
+	})
+	for _, name := range published {
+		r, err := storage.Get(dst, name)
+		c.Assert(err, jc.ErrorIsNil)
+		r.Close()
+	}
+}
@@ -146,6 +146,16 @@ func SupportsContainerAddresses(env Environ) bool {
 	return ok
 }
 
+// ValidateContainerNetworkingMethod checks that the environ can support
+// the given container-networking-method. The "provider" method is
+// rejected unless the environ can allocate container addresses.
+func ValidateContainerNetworkingMethod(env Environ, method string) error {
+	if method == "provider" && !SupportsContainerAddresses(env) {
+		return errors.NotSupportedf("container-networking-method %q on %s", method, env.Config().Type())
+	}
+	return nil
+}
+
 // ProviderSpaceInfo contains all the information about a space needed
 // by another environ to decide whether it can be routed to.
 type ProviderSpaceInfo struct {
@@ -0,0 +1,136 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package providerretry holds the policy that workers apply when
+// retrying failed cloud provider operations. Failures are classified
+// using the provider error types in the environs package: operations
+// that fail because the cloud credential was rejected are abandoned,
+// and transient failures are retried without using up attempts.
+package providerretry
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+)
+
+// ErrStopped is returned by Call when it is stopped before the
+// operation succeeds.
+var ErrStopped = errors.New("retry stopped")
+
+// Strategy describes how a failed provider operation is retried.
+type Strategy struct {
+	// Attempts is the number of times the operation is attempted,
+	// not counting attempts that fail transiently.
+	Attempts int
+
+	// Delay is how long to wait before the first retry. The delay
+	// doubles with each retry after that.
+	Delay time.Duration
+
+	// MaxDelay is the longest to wait between attempts.
+	MaxDelay time.Duration
+
+	// TransientAttempts, if positive, limits the number of times
+	// the operation is attempted when it fails transiently. Zero
+	// leaves transient failures unlimited.
+	TransientAttempts int
+}
+
+// NewStrategy returns the strategy configured for the model.
+func NewStrategy(cfg *config.Config) Strategy {
+	return Strategy{
+		Attempts: cfg.ProviderRetryAttempts(),
+		Delay:    cfg.ProviderRetryDelay(),
+		MaxDelay: cfg.ProviderRetryMaxDelay(),
+	}
+}
+
+// NextDelay returns how long to wait before the next attempt, given the
+// delay before the previous one, which is zero before the first retry.
+func (s Strategy) NextDelay(previous time.Duration) time.Duration {
+	next := s.Delay
+	if previous >= s.Delay {
+		next = previous * 2
+	}
+	if next > s.MaxDelay {
+		next = s.MaxDelay
+	}
+	return next
+}
+
+// ShouldRetry reports whether an operation that failed with the given
+// error should be attempted again. Operations that failed because the
+// cloud credential was rejected will fail in the same way until the
+// credential is updated, so they are not retried.
+func ShouldRetry(err error) bool {
+	return !environs.IsCredentialNotValid(err)
+}
+
+// CountsAsAttempt reports whether an operation that failed with the
+// given error has used up one of the attempts allowed for it.
+func CountsAsAttempt(err error) bool {
+	return !environs.IsTransient(err)
+}
+
+// CallArgs holds the arguments to Call.
+type CallArgs struct {
+	// Strategy describes how the operation is retried.
+	Strategy Strategy
+
+	// Clock is used to wait between attempts.
+	Clock clock.Clock
+
+	// Func performs the operation.
+	Func func() error
+
+	// NotifyFunc, if set, is called after each failed attempt that
+	// will be retried, with the error, the number of attempts used
+	// so far, and the delay before the next attempt.
+	NotifyFunc func(err error, attempts int, delay time.Duration)
+
+	// Stop, when closed, stops Call waiting for the next attempt.
+	Stop <-chan struct{}
+}
+
+// Call calls args.Func until it succeeds, fails with an error that
+// should not be retried, or runs out of attempts, and returns the last
+// error returned by args.Func. If args.Stop is closed first, Call
+// returns ErrStopped.
+func Call(args CallArgs) error {
+	var delay time.Duration
+	attempts, transientAttempts := 0, 0
+	for {
+		err := args.Func()
+		if err == nil {
+			return nil
+		}
+		if !ShouldRetry(err) {
+			return err
+		}
+		if CountsAsAttempt(err) {
+			attempts++
+		} else {
+			transientAttempts++
+		}
+		if attempts >= args.Strategy.Attempts {
+			return err
+		}
+		if limit := args.Strategy.TransientAttempts; limit > 0 && transientAttempts >= limit {
+			return err
+		}
+		delay = args.Strategy.NextDelay(delay)
+		if args.NotifyFunc != nil {
+			args.NotifyFunc(err, attempts, delay)
+		}
+		select {
+		case <-args.Stop:
+			return ErrStopped
+		case <-args.Clock.After(delay):
+		}
+	}
+}
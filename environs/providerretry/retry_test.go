@@ -0,0 +1,177 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package providerretry_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/providerretry"
+	"github.com/juju/juju/provider/common"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type RetrySuite struct {
+	jujutesting.IsolationSuite
+}
+
+var _ = gc.Suite(&RetrySuite{})
+
+func (*RetrySuite) TestNewStrategy(c *gc.C) {
+	cfg, err := config.New(config.UseDefaults, coretesting.FakeConfig().Merge(coretesting.Attrs{
+		"provider-retry-attempts": 3,
+		"provider-retry-delay":    "2s",
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(providerretry.NewStrategy(cfg), jc.DeepEquals, providerretry.Strategy{
+		Attempts: 3,
+		Delay:    2 * time.Second,
+		MaxDelay: 5 * time.Minute,
+	})
+}
+
+func (*RetrySuite) TestNextDelay(c *gc.C) {
+	s := providerretry.Strategy{Delay: time.Second, MaxDelay: 5 * time.Second}
+	var delays []time.Duration
+	var delay time.Duration
+	for i := 0; i < 5; i++ {
+		delay = s.NextDelay(delay)
+		delays = append(delays, delay)
+	}
+	c.Assert(delays, jc.DeepEquals, []time.Duration{
+		time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second,
+	})
+}
+
+func (*RetrySuite) TestClassification(c *gc.C) {
+	err := errors.New("boom")
+	c.Assert(providerretry.ShouldRetry(err), jc.IsTrue)
+	c.Assert(providerretry.CountsAsAttempt(err), jc.IsTrue)
+	c.Assert(providerretry.ShouldRetry(common.CredentialNotValidError(err)), jc.IsFalse)
+	c.Assert(providerretry.CountsAsAttempt(common.TransientError(err)), jc.IsFalse)
+}
+
+type callFixture struct {
+	clock  *jujutesting.Clock
+	errors []error
+	calls  int
+}
+
+func (f *callFixture) args(attempts int) providerretry.CallArgs {
+	return providerretry.CallArgs{
+		Strategy: providerretry.Strategy{
+			Attempts: attempts,
+			Delay:    time.Second,
+			MaxDelay: time.Minute,
+		},
+		Clock: f.clock,
+		Func: func() error {
+			err := f.errors[f.calls]
+			f.calls++
+			return err
+		},
+	}
+}
+
+// call runs providerretry.Call, advancing the clock past each delay.
+func (f *callFixture) call(c *gc.C, args providerretry.CallArgs) error {
+	var notified []time.Duration
+	args.NotifyFunc = func(_ error, _ int, delay time.Duration) {
+		notified = append(notified, delay)
+	}
+	result := make(chan error)
+	go func() {
+		result <- providerretry.Call(args)
+	}()
+	for {
+		select {
+		case err := <-result:
+			return err
+		case <-f.clock.Alarms():
+			f.clock.Advance(notified[len(notified)-1])
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for Call")
+		}
+	}
+}
+
+func (*RetrySuite) TestCallSucceeds(c *gc.C) {
+	f := &callFixture{
+		clock:  jujutesting.NewClock(time.Time{}),
+		errors: []error{errors.New("one"), nil},
+	}
+	err := f.call(c, f.args(3))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(f.calls, gc.Equals, 2)
+}
+
+func (*RetrySuite) TestCallRunsOutOfAttempts(c *gc.C) {
+	f := &callFixture{
+		clock:  jujutesting.NewClock(time.Time{}),
+		errors: []error{errors.New("one"), errors.New("two"), errors.New("three")},
+	}
+	err := f.call(c, f.args(2))
+	c.Assert(err, gc.ErrorMatches, "two")
+	c.Assert(f.calls, gc.Equals, 2)
+}
+
+func (*RetrySuite) TestCallTransientErrorsDoNotCount(c *gc.C) {
+	f := &callFixture{
+		clock: jujutesting.NewClock(time.Time{}),
+		errors: []error{
+			common.TransientError(errors.New("throttled")),
+			common.TransientError(errors.New("throttled")),
+			errors.New("one"),
+			errors.New("two"),
+		},
+	}
+	err := f.call(c, f.args(2))
+	c.Assert(err, gc.ErrorMatches, "two")
+	c.Assert(f.calls, gc.Equals, 4)
+}
+
+func (*RetrySuite) TestCallTransientAttemptsLimited(c *gc.C) {
+	f := &callFixture{
+		clock: jujutesting.NewClock(time.Time{}),
+		errors: []error{
+			common.TransientError(errors.New("throttled")),
+			errors.New("one"),
+			common.TransientError(errors.New("still throttled")),
+			errors.New("two"),
+		},
+	}
+	args := f.args(5)
+	args.Strategy.TransientAttempts = 2
+	err := f.call(c, args)
+	c.Assert(err, gc.ErrorMatches, "still throttled")
+	c.Assert(f.calls, gc.Equals, 3)
+}
+
+func (*RetrySuite) TestCallAbandonsCredentialErrors(c *gc.C) {
+	f := &callFixture{
+		clock:  jujutesting.NewClock(time.Time{}),
+		errors: []error{common.CredentialNotValidError(errors.New("denied"))},
+	}
+	err := f.call(c, f.args(5))
+	c.Assert(err, gc.ErrorMatches, "denied")
+	c.Assert(f.calls, gc.Equals, 1)
+}
+
+func (*RetrySuite) TestCallStopped(c *gc.C) {
+	f := &callFixture{
+		clock:  jujutesting.NewClock(time.Time{}),
+		errors: []error{errors.New("one")},
+	}
+	args := f.args(5)
+	stop := make(chan struct{})
+	close(stop)
+	args.Stop = stop
+	err := providerretry.Call(args)
+	c.Assert(err, gc.Equals, providerretry.ErrStopped)
+}
@@ -71,6 +71,9 @@ type urlDataSource struct {
 	publicSigningKey     string
 	priority             int
 	requireSigned        bool
+
+	// authorize, if not nil, adds credentials to each request.
+	authorize func(*http.Request)
 }
 
 // NewURLDataSource returns a new datasource reading from the specified baseURL.
@@ -96,6 +99,21 @@ func NewURLSignedDataSource(description, baseURL, publicKey string, hostnameVeri
 	}
 }
 
+// NewAuthenticatedURLSignedDataSource is like NewURLSignedDataSource,
+// but each request is passed to authorize, to add credentials, before
+// it is sent.
+func NewAuthenticatedURLSignedDataSource(description, baseURL, publicKey string, hostnameVerification utils.SSLHostnameVerification, priority int, requireSigned bool, authorize func(*http.Request)) DataSource {
+	return &urlDataSource{
+		description:          description,
+		baseURL:              baseURL,
+		publicSigningKey:     publicKey,
+		hostnameVerification: hostnameVerification,
+		priority:             priority,
+		requireSigned:        requireSigned,
+		authorize:            authorize,
+	}
+}
+
 // Description is defined in simplestreams.DataSource.
 func (u *urlDataSource) Description() string {
 	return u.description
@@ -123,7 +141,7 @@ func (h *urlDataSource) Fetch(path string) (io.ReadCloser, string, error) {
 	// dataURL can be http:// or file://
 	// MakeFileURL will only modify the URL if it's a file URL
 	dataURL = utils.MakeFileURL(dataURL)
-	resp, err := client.Get(dataURL)
+	resp, err := h.get(client, dataURL)
 	if err != nil {
 		logger.Tracef("Got error requesting %q: %v", dataURL, err)
 		return nil, dataURL, errors.NotFoundf("invalid URL %q", dataURL)
@@ -141,6 +159,18 @@ func (h *urlDataSource) Fetch(path string) (io.ReadCloser, string, error) {
 	return resp.Body, dataURL, nil
 }
 
+func (h *urlDataSource) get(client *http.Client, dataURL string) (*http.Response, error) {
+	if h.authorize == nil {
+		return client.Get(dataURL)
+	}
+	req, err := http.NewRequest("GET", dataURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	h.authorize(req)
+	return client.Do(req)
+}
+
 // URL is defined in simplestreams.DataSource.
 func (h *urlDataSource) URL(path string) (string, error) {
 	return utils.MakeFileURL(urlJoin(h.baseURL, path)), nil
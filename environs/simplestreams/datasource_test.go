@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
@@ -19,6 +20,7 @@ import (
 
 var _ = gc.Suite(&datasourceSuite{})
 var _ = gc.Suite(&datasourceHTTPSSuite{})
+var _ = gc.Suite(&datasourceAuthSuite{})
 
 type datasourceSuite struct {
 	testing.TestDataSuite
@@ -90,3 +92,31 @@ func (s *datasourceHTTPSSuite) TestNonVerifyingClientSucceeds(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Check(string(byteContent), gc.Equals, "Greetings!\n")
 }
+
+type datasourceAuthSuite struct{}
+
+func (s *datasourceAuthSuite) TestAuthenticatedFetch(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if user, password, ok := req.BasicAuth(); !ok || user != "fred" || password != "secret" {
+			resp.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		resp.Write([]byte("Greetings!\n"))
+	}))
+	defer server.Close()
+
+	ds := simplestreams.NewURLDataSource("test", server.URL, utils.VerifySSLHostnames, simplestreams.DEFAULT_CLOUD_DATA, false)
+	_, _, err := ds.Fetch("bar")
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+
+	authorize := func(req *http.Request) {
+		req.SetBasicAuth("fred", "secret")
+	}
+	ds = simplestreams.NewAuthenticatedURLSignedDataSource("test", server.URL, "", utils.VerifySSLHostnames, simplestreams.DEFAULT_CLOUD_DATA, false, authorize)
+	reader, _, err := ds.Fetch("bar")
+	c.Assert(err, jc.ErrorIsNil)
+	defer reader.Close()
+	byteContent, err := ioutil.ReadAll(reader)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(byteContent), gc.Equals, "Greetings!\n")
+}
@@ -36,6 +36,18 @@ func DecodeCheckSignature(r io.Reader, armoredPublicKey string) ([]byte, error)
 	return b.Plaintext, nil
 }
 
+// CheckDetachedSignature checks that the armored detached signature
+// is a valid signature, by one of the armored public keys, of the data
+// returned by the reader.
+func CheckDetachedSignature(r io.Reader, armoredSignature, armoredPublicKeys string) error {
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewBufferString(armoredPublicKeys))
+	if err != nil {
+		return fmt.Errorf("failed to parse public key: %v", err)
+	}
+	_, err = openpgp.CheckArmoredDetachedSignature(keyring, r, bytes.NewBufferString(armoredSignature))
+	return err
+}
+
 // NotPGPSignedError is used when PGP text does not contain an inline signature.
 type NotPGPSignedError struct{}
 
@@ -10,6 +10,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/environs/simplestreams"
+	sstesting "github.com/juju/juju/environs/simplestreams/testing"
 )
 
 type decodeSuite struct{}
@@ -38,6 +39,29 @@ func (s *decodeSuite) TestDecodeCheckMissingSignature(c *gc.C) {
 	c.Assert(ok, jc.IsTrue)
 }
 
+func (s *decodeSuite) TestCheckDetachedSignature(c *gc.C) {
+	signature, err := simplestreams.SignDetached(
+		bytes.NewReader([]byte("agent binary")),
+		sstesting.SignedMetadataPrivateKey,
+		sstesting.PrivateKeyPassphrase,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	err = simplestreams.CheckDetachedSignature(
+		bytes.NewReader([]byte("agent binary")), signature, sstesting.SignedMetadataPublicKey,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = simplestreams.CheckDetachedSignature(
+		bytes.NewReader([]byte("tampered binary")), signature, sstesting.SignedMetadataPublicKey,
+	)
+	c.Assert(err, gc.ErrorMatches, "openpgp: invalid signature: .*")
+
+	err = simplestreams.CheckDetachedSignature(
+		bytes.NewReader([]byte("agent binary")), signature, testSigningKey,
+	)
+	c.Assert(err, gc.ErrorMatches, "openpgp: signature made by unknown entity")
+}
+
 func (s *decodeSuite) TestDecodeCheckMissingKey(c *gc.C) {
 	r := bytes.NewReader([]byte(signedData))
 	_, err := simplestreams.DecodeCheckSignature(r, "")
@@ -50,3 +50,24 @@ func Encode(r io.Reader, armoredPrivateKey, passphrase string) ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
+
+// SignDetached signs the data returned by the reader and returns an
+// armored detached signature, such as is published alongside agent
+// binaries in simplestreams metadata.
+func SignDetached(r io.Reader, armoredPrivateKey, passphrase string) (string, error) {
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewBufferString(armoredPrivateKey))
+	if err != nil {
+		return "", err
+	}
+	signer := keyring[0]
+	if signer.PrivateKey.Encrypted {
+		if err := signer.PrivateKey.Decrypt([]byte(passphrase)); err != nil {
+			return "", err
+		}
+	}
+	var buf bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&buf, signer, r, nil); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
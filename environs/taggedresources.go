@@ -0,0 +1,146 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/retry"
+)
+
+// The kinds of cloud resource that may be reported by a
+// TaggedResourceLister.
+const (
+	ResourceKindInstance      = "instance"
+	ResourceKindVolume        = "volume"
+	ResourceKindSecurityGroup = "security-group"
+	ResourceKindLoadBalancer  = "load-balancer"
+)
+
+// forceDestroyOrder lists the kinds of resource that ForceDestroyTaggedResources
+// knows how to remove, in the order in which they are removed; resources are
+// removed before the resources they depend on.
+var forceDestroyOrder = []string{
+	ResourceKindInstance,
+	ResourceKindLoadBalancer,
+	ResourceKindVolume,
+	ResourceKindSecurityGroup,
+}
+
+// TaggedResource describes a cloud resource discovered by its tags.
+type TaggedResource struct {
+	// Kind is the kind of resource, eg ResourceKindInstance.
+	Kind string
+
+	// ID is the provider-specific ID of the resource.
+	ID string
+
+	// Tags holds the resource's tags.
+	Tags map[string]string
+}
+
+// String returns a human-readable description of the resource.
+func (r TaggedResource) String() string {
+	return fmt.Sprintf("%s %q", r.Kind, r.ID)
+}
+
+// TaggedResourceLister is an interface that may be implemented by an
+// Environ that can enumerate the cloud resources carrying a given set
+// of tags.
+type TaggedResourceLister interface {
+	// TaggedResources returns all resources carrying every one of the
+	// supplied tags.
+	TaggedResources(tags map[string]string) ([]TaggedResource, error)
+}
+
+// TaggedResourceDestroyer is an interface that may be implemented by an
+// Environ that can remove individual resources discovered by their tags.
+type TaggedResourceDestroyer interface {
+	TaggedResourceLister
+
+	// DestroyTaggedResource removes the supplied resource from the
+	// cloud. It returns an error satisfying errors.IsNotSupported if
+	// the Environ cannot remove resources of the given kind.
+	DestroyTaggedResource(TaggedResource) error
+}
+
+// ForceDestroyReport records the outcome of ForceDestroyTaggedResources.
+type ForceDestroyReport struct {
+	// Destroyed holds the resources that were removed.
+	Destroyed []TaggedResource
+
+	// Failed maps the resources that could not be removed to the
+	// reason for the failure.
+	Failed map[string]error
+
+	// Unidentified holds the resources whose kind was not recognised,
+	// and which were therefore left alone.
+	Unidentified []TaggedResource
+}
+
+// Complete reports whether every discovered resource was removed.
+func (r *ForceDestroyReport) Complete() bool {
+	return len(r.Failed) == 0 && len(r.Unidentified) == 0
+}
+
+// ForceDestroyTaggedResources removes every resource carrying all of the
+// supplied tags, for use when the normal teardown of a model has stalled.
+// Unlike Environ.Destroy it does not stop at the first failure: every
+// resource is attempted, and the outcome for each is recorded in the
+// returned report. An error is returned only if the resources cannot
+// be listed.
+func ForceDestroyTaggedResources(env TaggedResourceDestroyer, tags map[string]string) (*ForceDestroyReport, error) {
+	if len(tags) == 0 {
+		// Refuse to destroy everything in the cloud account.
+		return nil, errors.NotValidf("empty tag filter")
+	}
+	resources, err := env.TaggedResources(tags)
+	if err != nil {
+		return nil, errors.Annotate(err, "listing tagged resources")
+	}
+
+	byKind := make(map[string][]TaggedResource)
+	for _, resource := range resources {
+		byKind[resource.Kind] = append(byKind[resource.Kind], resource)
+	}
+	report := &ForceDestroyReport{
+		Failed: make(map[string]error),
+	}
+	for _, kind := range forceDestroyOrder {
+		for _, resource := range byKind[kind] {
+			if err := env.DestroyTaggedResource(resource); err != nil {
+				report.Failed[resource.String()] = err
+				continue
+			}
+			report.Destroyed = append(report.Destroyed, resource)
+		}
+		delete(byKind, kind)
+	}
+	var unknownKinds []string
+	for kind := range byKind {
+		unknownKinds = append(unknownKinds, kind)
+	}
+	sort.Strings(unknownKinds)
+	for _, kind := range unknownKinds {
+		report.Unidentified = append(report.Unidentified, byKind[kind]...)
+	}
+	return report, nil
+}
+
+// IsTeardownStalled reports whether or not the given error, returned
+// by Environ.Destroy, indicates that the teardown stalled: it timed
+// out, ran out of attempts, or failed for a reason that is expected
+// to pass. Only stalled teardowns should be completed by
+// ForceDestroyTaggedResources; other failures, such as a rejected
+// credential, would recur for each resource in turn.
+func IsTeardownStalled(err error) bool {
+	if IsCredentialNotValid(err) {
+		return false
+	}
+	return errors.IsTimeout(errors.Cause(err)) ||
+		retry.IsAttemptsExceeded(err) ||
+		IsTransient(err)
+}
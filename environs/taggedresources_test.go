@@ -0,0 +1,127 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/retry"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/provider/common"
+)
+
+type taggedResourcesSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&taggedResourcesSuite{})
+
+type fakeTaggedResourceDestroyer struct {
+	testing.Stub
+	resources []environs.TaggedResource
+	failures  map[string]error
+}
+
+func (f *fakeTaggedResourceDestroyer) TaggedResources(tags map[string]string) ([]environs.TaggedResource, error) {
+	f.MethodCall(f, "TaggedResources", tags)
+	return f.resources, f.NextErr()
+}
+
+func (f *fakeTaggedResourceDestroyer) DestroyTaggedResource(r environs.TaggedResource) error {
+	f.MethodCall(f, "DestroyTaggedResource", r.ID)
+	return f.failures[r.ID]
+}
+
+func (s *taggedResourcesSuite) TestForceDestroyOrderAndReport(c *gc.C) {
+	env := &fakeTaggedResourceDestroyer{
+		resources: []environs.TaggedResource{
+			{Kind: environs.ResourceKindSecurityGroup, ID: "sg-1"},
+			{Kind: environs.ResourceKindVolume, ID: "vol-1"},
+			{Kind: "dns-record", ID: "rec-1"},
+			{Kind: environs.ResourceKindInstance, ID: "i-1"},
+			{Kind: environs.ResourceKindLoadBalancer, ID: "lb-1"},
+			{Kind: environs.ResourceKindVolume, ID: "vol-2"},
+		},
+		failures: map[string]error{
+			"vol-2": errors.New("volume in use"),
+		},
+	}
+	tags := map[string]string{"juju-model-uuid": "deadbeef"}
+	report, err := environs.ForceDestroyTaggedResources(env, tags)
+	c.Assert(err, jc.ErrorIsNil)
+
+	env.CheckCalls(c, []testing.StubCall{
+		{"TaggedResources", []interface{}{tags}},
+		{"DestroyTaggedResource", []interface{}{"i-1"}},
+		{"DestroyTaggedResource", []interface{}{"lb-1"}},
+		{"DestroyTaggedResource", []interface{}{"vol-1"}},
+		{"DestroyTaggedResource", []interface{}{"vol-2"}},
+		{"DestroyTaggedResource", []interface{}{"sg-1"}},
+	})
+	c.Assert(report.Destroyed, jc.DeepEquals, []environs.TaggedResource{
+		{Kind: environs.ResourceKindInstance, ID: "i-1"},
+		{Kind: environs.ResourceKindLoadBalancer, ID: "lb-1"},
+		{Kind: environs.ResourceKindVolume, ID: "vol-1"},
+		{Kind: environs.ResourceKindSecurityGroup, ID: "sg-1"},
+	})
+	c.Assert(report.Failed, gc.HasLen, 1)
+	c.Assert(report.Failed[`volume "vol-2"`], gc.ErrorMatches, "volume in use")
+	c.Assert(report.Unidentified, jc.DeepEquals, []environs.TaggedResource{
+		{Kind: "dns-record", ID: "rec-1"},
+	})
+	c.Assert(report.Complete(), jc.IsFalse)
+}
+
+func (s *taggedResourcesSuite) TestForceDestroyListError(c *gc.C) {
+	env := &fakeTaggedResourceDestroyer{}
+	env.SetErrors(errors.New("boom"))
+	_, err := environs.ForceDestroyTaggedResources(env, map[string]string{"k": "v"})
+	c.Assert(err, gc.ErrorMatches, "listing tagged resources: boom")
+}
+
+func (s *taggedResourcesSuite) TestForceDestroyEmptyTags(c *gc.C) {
+	env := &fakeTaggedResourceDestroyer{}
+	_, err := environs.ForceDestroyTaggedResources(env, nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	env.CheckNoCalls(c)
+}
+
+func (s *taggedResourcesSuite) TestIsTeardownStalled(c *gc.C) {
+	attemptsErr := retry.Call(retry.CallArgs{
+		Func:     func() error { return errors.New("in use") },
+		Attempts: 1,
+		Delay:    time.Millisecond,
+		Clock:    clock.WallClock,
+	})
+	c.Assert(attemptsErr, gc.NotNil)
+
+	for i, test := range []struct {
+		err     error
+		stalled bool
+	}{{
+		err:     errors.New("pow"),
+		stalled: false,
+	}, {
+		err:     errors.Annotate(errors.Timeoutf("terminating instances"), "destroying"),
+		stalled: true,
+	}, {
+		err:     errors.Annotate(attemptsErr, "deleting security group"),
+		stalled: true,
+	}, {
+		err:     common.TransientError(errors.New("throttled")),
+		stalled: true,
+	}, {
+		err:     common.CredentialNotValidError(errors.New("denied")),
+		stalled: false,
+	}} {
+		c.Logf("test %d: %v", i, test.err)
+		c.Check(environs.IsTeardownStalled(test.err), gc.Equals, test.stalled)
+	}
+}
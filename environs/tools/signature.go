@@ -0,0 +1,55 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tools
+
+import (
+	"io"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/simplestreams"
+	"github.com/juju/juju/environs/storage"
+	coretools "github.com/juju/juju/tools"
+)
+
+// VerifySignature checks that the agent binary tarball read from r was
+// signed, as recorded in the tools' metadata, with one of the armored
+// OpenPGP public keys in trustedKeys. Unsigned agent binaries are
+// rejected.
+func VerifySignature(r io.Reader, tools *coretools.Tools, trustedKeys string) error {
+	if tools.Signature == "" {
+		return errors.Errorf("agent binaries %s are not signed", tools.Version)
+	}
+	if err := simplestreams.CheckDetachedSignature(r, tools.Signature, trustedKeys); err != nil {
+		return errors.Annotatef(err, "invalid signature for agent binaries %s", tools.Version)
+	}
+	return nil
+}
+
+// SignMetadata signs the agent binary tarballs described by the
+// unsigned entries in metadata with the armored OpenPGP private key,
+// decrypted with the passphrase if necessary, and records the detached
+// signatures in the metadata. The tarballs are read from the toolsDir
+// directory of stor; entries that are already signed are left alone.
+func SignMetadata(stor storage.StorageReader, toolsDir string, metadata []*ToolsMetadata, privateKey, passphrase string) error {
+	for _, md := range metadata {
+		if md.Signature != "" {
+			continue
+		}
+		binary, err := md.binary()
+		if err != nil {
+			return errors.Annotate(err, "cannot sign metadata")
+		}
+		r, err := storage.Get(stor, StorageName(binary, toolsDir))
+		if err != nil {
+			return errors.Annotatef(err, "cannot sign agent binaries %s", binary)
+		}
+		md.Signature, err = simplestreams.SignDetached(r, privateKey, passphrase)
+		r.Close()
+		if err != nil {
+			return errors.Annotatef(err, "cannot sign agent binaries %s", binary)
+		}
+	}
+	return nil
+}
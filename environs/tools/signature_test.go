@@ -0,0 +1,88 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tools_test
+
+import (
+	"bytes"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/filestorage"
+	"github.com/juju/juju/environs/simplestreams"
+	sstesting "github.com/juju/juju/environs/simplestreams/testing"
+	envtools "github.com/juju/juju/environs/tools"
+	coretesting "github.com/juju/juju/testing"
+	coretools "github.com/juju/juju/tools"
+)
+
+type signatureSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&signatureSuite{})
+
+func (s *signatureSuite) signedTools(c *gc.C, data string) *coretools.Tools {
+	signature, err := simplestreams.SignDetached(
+		bytes.NewReader([]byte(data)),
+		sstesting.SignedMetadataPrivateKey,
+		sstesting.PrivateKeyPassphrase,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	return &coretools.Tools{
+		Version:   version.MustParseBinary("2.3.4-xenial-amd64"),
+		Signature: signature,
+	}
+}
+
+func (s *signatureSuite) TestVerifySignature(c *gc.C) {
+	tools := s.signedTools(c, "agent binary")
+	err := envtools.VerifySignature(
+		bytes.NewReader([]byte("agent binary")), tools, sstesting.SignedMetadataPublicKey,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *signatureSuite) TestVerifySignatureTampered(c *gc.C) {
+	tools := s.signedTools(c, "agent binary")
+	err := envtools.VerifySignature(
+		bytes.NewReader([]byte("tampered binary")), tools, sstesting.SignedMetadataPublicKey,
+	)
+	c.Assert(err, gc.ErrorMatches, "invalid signature for agent binaries 2.3.4-xenial-amd64: .*")
+}
+
+func (s *signatureSuite) TestVerifySignatureUnsigned(c *gc.C) {
+	tools := &coretools.Tools{Version: version.MustParseBinary("2.3.4-xenial-amd64")}
+	err := envtools.VerifySignature(
+		bytes.NewReader([]byte("agent binary")), tools, sstesting.SignedMetadataPublicKey,
+	)
+	c.Assert(err, gc.ErrorMatches, "agent binaries 2.3.4-xenial-amd64 are not signed")
+}
+
+func (s *signatureSuite) TestSignMetadata(c *gc.C) {
+	stor, err := filestorage.NewFileStorageWriter(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	vers := version.MustParseBinary("2.3.4-xenial-amd64")
+	err = stor.Put(envtools.StorageName(vers, "released"), strings.NewReader("agent binary"), 12)
+	c.Assert(err, jc.ErrorIsNil)
+
+	metadata := envtools.MetadataFromTools(coretools.List{{Version: vers}}, "released")
+	err = envtools.SignMetadata(stor, "released", metadata, sstesting.SignedMetadataPrivateKey, sstesting.PrivateKeyPassphrase)
+	c.Assert(err, jc.ErrorIsNil)
+
+	tools := &coretools.Tools{Version: vers, Signature: metadata[0].Signature}
+	err = envtools.VerifySignature(strings.NewReader("agent binary"), tools, sstesting.SignedMetadataPublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *signatureSuite) TestSignMetadataMissingTarball(c *gc.C) {
+	stor, err := filestorage.NewFileStorageWriter(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	vers := version.MustParseBinary("2.3.4-xenial-amd64")
+	metadata := envtools.MetadataFromTools(coretools.List{{Version: vers}}, "released")
+	err = envtools.SignMetadata(stor, "released", metadata, sstesting.SignedMetadataPrivateKey, sstesting.PrivateKeyPassphrase)
+	c.Assert(err, gc.ErrorMatches, "cannot sign agent binaries 2.3.4-xenial-amd64: .*not found")
+}
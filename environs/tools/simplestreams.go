@@ -122,6 +122,10 @@ type ToolsMetadata struct {
 	FullPath string `json:"-"`
 	FileType string `json:"ftype"`
 	SHA256   string `json:"sha256"`
+
+	// Signature holds the armored OpenPGP detached signature of the
+	// tarball, if the publisher signed it.
+	Signature string `json:"signature,omitempty"`
 }
 
 func (t *ToolsMetadata) String() string {
@@ -258,13 +262,14 @@ func MetadataFromTools(toolsList coretools.List, toolsDir string) []*ToolsMetada
 	for i, t := range toolsList {
 		path := fmt.Sprintf("%s/juju-%s-%s-%s.tgz", toolsDir, t.Version.Number, t.Version.Series, t.Version.Arch)
 		metadata[i] = &ToolsMetadata{
-			Release:  t.Version.Series,
-			Version:  t.Version.Number.String(),
-			Arch:     t.Version.Arch,
-			Path:     path,
-			FileType: "tar.gz",
-			Size:     t.Size,
-			SHA256:   t.SHA256,
+			Release:   t.Version.Series,
+			Version:   t.Version.Number.String(),
+			Arch:      t.Version.Arch,
+			Path:      path,
+			FileType:  "tar.gz",
+			Size:      t.Size,
+			SHA256:    t.SHA256,
+			Signature: t.Signature,
 		}
 	}
 	return metadata
@@ -158,10 +158,11 @@ func FindToolsForCloud(sources []simplestreams.DataSource, cloudSpec simplestrea
 				continue
 			}
 			list = append(list, &coretools.Tools{
-				Version: binary,
-				URL:     metadata.FullPath,
-				Size:    metadata.Size,
-				SHA256:  metadata.SHA256,
+				Version:   binary,
+				URL:       metadata.FullPath,
+				Size:      metadata.Size,
+				SHA256:    metadata.SHA256,
+				Signature: metadata.Signature,
 			})
 			seenBinary[binary] = true
 		}
@@ -40,3 +40,17 @@ const StrictMigration = "strict-migration"
 
 // CAAS enables creating models on CAAS infrastructure (k8s, etc)
 const CAAS = "caas"
+
+// FaultInjection enables the FaultInjection facade, with which
+// controller administrators can inject failures into a model for
+// bounded periods, and makes the controller act on the faults
+// injected.
+const FaultInjection = "fault-injection"
+
+// ModelFlags holds the feature flags that may be enabled for a single
+// model, by listing them in its "features" config attribute, rather
+// than for a whole controller with an environment variable on its
+// hosts. A controller rejects flags that it does not support.
+var ModelFlags = []string{
+	ImageMetadata,
+}
@@ -51,7 +51,7 @@ func (s *cmdControllerSuite) createModelAdminUser(c *gc.C, modelname string, isS
 	modelManager := modelmanager.NewClient(s.OpenControllerAPI(c))
 	defer modelManager.Close()
 	model, err := modelManager.CreateModel(
-		modelname, s.AdminUserTag(c).Id(), "", "", names.CloudCredentialTag{}, map[string]interface{}{
+		modelname, s.AdminUserTag(c).Id(), "", "", "", names.CloudCredentialTag{}, map[string]interface{}{
 			"controller": isServer,
 		},
 	)
@@ -64,7 +64,7 @@ func (s *cmdControllerSuite) createModelNormalUser(c *gc.C, modelname string, is
 	modelManager := modelmanager.NewClient(s.OpenControllerAPI(c))
 	defer modelManager.Close()
 	_, err := modelManager.CreateModel(
-		modelname, names.NewLocalUserTag("test").Id(), "", "", names.CloudCredentialTag{}, map[string]interface{}{
+		modelname, names.NewLocalUserTag("test").Id(), "", "", "", names.CloudCredentialTag{}, map[string]interface{}{
 			"authorized-keys": "ssh-key",
 			"controller":      isServer,
 		},
@@ -0,0 +1,193 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instance
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+const (
+	// PlacementZone is the key of a placement term that restricts
+	// an instance to an availability zone (e.g. zone=us-east-1a).
+	PlacementZone = "zone"
+
+	// PlacementMachineWith is the name of the placement function that
+	// places an instance alongside the machines hosting an
+	// application, e.g. machine-with(app=haproxy).
+	PlacementMachineWith = "machine-with"
+)
+
+// PlacementTerm is a single term of a placement expression. A term is
+// either key=value, a bare provider-specific token, or a function of
+// key=value arguments such as machine-with(app=haproxy).
+type PlacementTerm struct {
+	// Key is the key of a key=value term, the token of a bare term,
+	// or the name of a function term.
+	Key string
+
+	// Value is the value of a key=value term.
+	Value string
+
+	// Args holds the arguments of a function term. It is nil for
+	// other terms.
+	Args []PlacementTerm
+}
+
+// IsFunction reports whether the term is a function term.
+func (t PlacementTerm) IsFunction() bool {
+	return t.Args != nil
+}
+
+// Arg returns the value of the function term's argument with the
+// specified key, or "" if there is none.
+func (t PlacementTerm) Arg(key string) string {
+	for _, arg := range t.Args {
+		if arg.Key == key {
+			return arg.Value
+		}
+	}
+	return ""
+}
+
+// String returns the term in the form it is parsed from.
+func (t PlacementTerm) String() string {
+	if t.IsFunction() {
+		args := make([]string, len(t.Args))
+		for i, arg := range t.Args {
+			args[i] = arg.String()
+		}
+		return t.Key + "(" + strings.Join(args, ",") + ")"
+	}
+	if t.Value == "" {
+		return t.Key
+	}
+	return t.Key + "=" + t.Value
+}
+
+// PlacementExpr is a placement directive made up of comma-separated
+// terms, all of which must be satisfied, e.g.
+// "zone=us-east-1a,subnet=10.4.0.0/24".
+type PlacementExpr []PlacementTerm
+
+// String returns the expression in the form it is parsed from.
+func (e PlacementExpr) String() string {
+	terms := make([]string, len(e))
+	for i, t := range e {
+		terms[i] = t.String()
+	}
+	return strings.Join(terms, ",")
+}
+
+// IsSimple reports whether the expression consists of a single
+// non-function term, which is the form of directive that providers
+// interpret directly.
+func (e PlacementExpr) IsSimple() bool {
+	return len(e) == 1 && !e[0].IsFunction()
+}
+
+// IsPlacementExpr reports whether the model-scoped placement directive
+// is a compound expression, or uses a placement function, rather than
+// a single provider-specific term.
+func IsPlacementExpr(directive string) bool {
+	return strings.ContainsAny(directive, ",()")
+}
+
+// ParsePlacementExpr parses a model-scoped placement directive into its
+// terms.
+func ParsePlacementExpr(directive string) (PlacementExpr, error) {
+	if directive == "" {
+		return nil, errors.NotValidf("empty placement expression")
+	}
+	var expr PlacementExpr
+	for _, s := range splitPlacementTerms(directive) {
+		term, err := parsePlacementTerm(s)
+		if err != nil {
+			return nil, errors.Annotatef(err, "parsing placement %q", directive)
+		}
+		expr = append(expr, term)
+	}
+	return expr, nil
+}
+
+// splitPlacementTerms splits the directive at the commas that are not
+// within a function's arguments.
+func splitPlacementTerms(directive string) []string {
+	var terms []string
+	depth, start := 0, 0
+	for i, r := range directive {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = append(terms, directive[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(terms, directive[start:])
+}
+
+func parsePlacementTerm(s string) (PlacementTerm, error) {
+	open := strings.IndexRune(s, '(')
+	if open == -1 {
+		if strings.ContainsRune(s, ')') {
+			return PlacementTerm{}, errors.Errorf("unexpected %q in %q", ")", s)
+		}
+		return parseKeyValue(s)
+	}
+	if !strings.HasSuffix(s, ")") {
+		return PlacementTerm{}, errors.Errorf("missing %q in %q", ")", s)
+	}
+	name, body := s[:open], s[open+1:len(s)-1]
+	if strings.ContainsAny(body, "()") {
+		return PlacementTerm{}, errors.Errorf("nested functions not supported in %q", s)
+	}
+	term := PlacementTerm{Key: name, Args: []PlacementTerm{}}
+	if body != "" {
+		for _, a := range strings.Split(body, ",") {
+			arg, err := parseKeyValue(a)
+			if err != nil {
+				return PlacementTerm{}, errors.Trace(err)
+			}
+			if arg.Value == "" {
+				return PlacementTerm{}, errors.Errorf("expected key=value argument in %q, got %q", s, a)
+			}
+			term.Args = append(term.Args, arg)
+		}
+	}
+	if err := validatePlacementFunction(term); err != nil {
+		return PlacementTerm{}, errors.Trace(err)
+	}
+	return term, nil
+}
+
+func parseKeyValue(s string) (PlacementTerm, error) {
+	key, value := s, ""
+	if eq := strings.IndexRune(s, '='); eq != -1 {
+		key, value = s[:eq], s[eq+1:]
+		if value == "" {
+			return PlacementTerm{}, errors.Errorf("missing value in %q", s)
+		}
+	}
+	if key == "" {
+		return PlacementTerm{}, errors.Errorf("missing key in %q", s)
+	}
+	return PlacementTerm{Key: key, Value: value}, nil
+}
+
+func validatePlacementFunction(term PlacementTerm) error {
+	switch term.Key {
+	case PlacementMachineWith:
+		if len(term.Args) != 1 || term.Args[0].Key != "app" {
+			return errors.Errorf("%s expects a single app argument, got %q", term.Key, term.String())
+		}
+		return nil
+	}
+	return errors.NotSupportedf("placement function %q", term.Key)
+}
@@ -0,0 +1,97 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instance_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+)
+
+type PlacementExprSuite struct{}
+
+var _ = gc.Suite(&PlacementExprSuite{})
+
+func (s *PlacementExprSuite) TestParsePlacementExpr(c *gc.C) {
+	for i, test := range []struct {
+		directive string
+		expect    instance.PlacementExpr
+		simple    bool
+		err       string
+	}{{
+		directive: "zone=us-east-1a",
+		expect:    instance.PlacementExpr{{Key: "zone", Value: "us-east-1a"}},
+		simple:    true,
+	}, {
+		directive: "node1.maas",
+		expect:    instance.PlacementExpr{{Key: "node1.maas"}},
+		simple:    true,
+	}, {
+		directive: "zone=us-east-1a,subnet=10.4.0.0/24",
+		expect: instance.PlacementExpr{
+			{Key: "zone", Value: "us-east-1a"},
+			{Key: "subnet", Value: "10.4.0.0/24"},
+		},
+	}, {
+		directive: "machine-with(app=haproxy)",
+		expect: instance.PlacementExpr{{
+			Key:  "machine-with",
+			Args: []instance.PlacementTerm{{Key: "app", Value: "haproxy"}},
+		}},
+	}, {
+		directive: "machine-with(app=haproxy),subnet=10.4.0.0/24",
+		expect: instance.PlacementExpr{{
+			Key:  "machine-with",
+			Args: []instance.PlacementTerm{{Key: "app", Value: "haproxy"}},
+		}, {
+			Key: "subnet", Value: "10.4.0.0/24",
+		}},
+	}, {
+		directive: "",
+		err:       "empty placement expression not valid",
+	}, {
+		directive: "zone=a,,subnet=b",
+		err:       `parsing placement "zone=a,,subnet=b": missing key in ""`,
+	}, {
+		directive: "zone=",
+		err:       `parsing placement "zone=": missing value in "zone="`,
+	}, {
+		directive: "machine-with(app=haproxy",
+		err:       `parsing placement "machine-with\(app=haproxy": missing "\)" in "machine-with\(app=haproxy"`,
+	}, {
+		directive: "machine-with(unit=haproxy/0)",
+		err:       `parsing placement ".*": machine-with expects a single app argument, got "machine-with\(unit=haproxy/0\)"`,
+	}, {
+		directive: "machine-with(app)",
+		err:       `parsing placement ".*": expected key=value argument in "machine-with\(app\)", got "app"`,
+	}, {
+		directive: "far-from(app=haproxy)",
+		err:       `parsing placement ".*": placement function "far-from" not supported`,
+	}, {
+		directive: "machine-with(app=machine-with(app=x))",
+		err:       `parsing placement ".*": nested functions not supported in ".*"`,
+	}} {
+		c.Logf("test %d: %q", i, test.directive)
+		expr, err := instance.ParsePlacementExpr(test.directive)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		if !c.Check(err, jc.ErrorIsNil) {
+			continue
+		}
+		c.Check(expr, jc.DeepEquals, test.expect)
+		c.Check(expr.IsSimple(), gc.Equals, test.simple)
+		c.Check(expr.String(), gc.Equals, test.directive)
+	}
+}
+
+func (s *PlacementExprSuite) TestTermArg(c *gc.C) {
+	expr, err := instance.ParsePlacementExpr("machine-with(app=haproxy)")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(expr[0].IsFunction(), jc.IsTrue)
+	c.Assert(expr[0].Arg("app"), gc.Equals, "haproxy")
+	c.Assert(expr[0].Arg("unit"), gc.Equals, "")
+}
@@ -0,0 +1,59 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package paths
+
+import (
+	"github.com/juju/errors"
+	jujuos "github.com/juju/utils/os"
+	"github.com/juju/utils/series"
+)
+
+// TrustStore describes how CA certificates are added to the system
+// trust store of an OS.
+type TrustStore struct {
+	// Dir is the directory into which CA certificates are written,
+	// one PEM encoded certificate per file, to be added to the
+	// trust store.
+	Dir string
+
+	// FileExtension is the extension the certificate files must have.
+	FileExtension string
+
+	// UpdateCommand is the command that rebuilds the trust store
+	// from the certificates in Dir.
+	UpdateCommand string
+}
+
+var trustStores = map[jujuos.OSType]TrustStore{
+	jujuos.Ubuntu: {
+		Dir:           "/usr/local/share/ca-certificates",
+		FileExtension: ".crt",
+		UpdateCommand: "update-ca-certificates",
+	},
+	jujuos.CentOS: {
+		Dir:           "/etc/pki/ca-trust/source/anchors",
+		FileExtension: ".pem",
+		UpdateCommand: "update-ca-trust extract",
+	},
+	jujuos.OpenSUSE: {
+		Dir:           "/etc/pki/trust/anchors",
+		FileExtension: ".pem",
+		UpdateCommand: "update-ca-certificates",
+	},
+}
+
+// SystemTrustStore returns the system trust store of the given series.
+// It returns an error satisfying errors.IsNotSupported if Juju cannot
+// manage the trust store of the series' OS.
+func SystemTrustStore(ser string) (TrustStore, error) {
+	os, err := series.GetOSFromSeries(ser)
+	if err != nil {
+		return TrustStore{}, errors.Trace(err)
+	}
+	store, ok := trustStores[os]
+	if !ok {
+		return TrustStore{}, errors.NotSupportedf("trust store on %s", os)
+	}
+	return store, nil
+}
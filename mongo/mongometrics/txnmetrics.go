@@ -4,6 +4,8 @@
 package mongometrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"gopkg.in/mgo.v2/txn"
 )
@@ -45,7 +47,7 @@ func NewTxnCollector() *TxnCollector {
 }
 
 // AfterRunTransaction is called when a mgo/txn transaction has run.
-func (c *TxnCollector) AfterRunTransaction(dbName, modelUUID string, ops []txn.Op, err error) {
+func (c *TxnCollector) AfterRunTransaction(dbName, modelUUID string, ops []txn.Op, duration time.Duration, err error) {
 	for _, op := range ops {
 		c.updateMetrics(dbName, op, err)
 	}
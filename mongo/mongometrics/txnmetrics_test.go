@@ -6,6 +6,7 @@ package mongometrics_test
 import (
 	"errors"
 	"reflect"
+	"time"
 
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -56,12 +57,12 @@ func (s *TxnCollectorSuite) TestCollect(c *gc.C) {
 		Remove: true,
 	}, {
 		C: "assert-coll",
-	}}, nil)
+	}}, time.Millisecond, nil)
 
 	s.collector.AfterRunTransaction("dbname", "modeluuid", []txn.Op{{
 		C:      "update-coll",
 		Update: bson.D{},
-	}}, errors.New("bewm"))
+	}}, time.Millisecond, errors.New("bewm"))
 
 	ch := make(chan prometheus.Metric)
 	go func() {
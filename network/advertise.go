@@ -0,0 +1,107 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// AdvertisePolicy controls which API server addresses are advertised to
+// agents, for when the addresses detected on the controllers include
+// some that agents cannot reach, or leave out some that they can.
+type AdvertisePolicy struct {
+	// Pinned holds the addresses that are always advertised, ahead
+	// of any others, eg an external NAT address.
+	Pinned []HostPort
+
+	// Exclude holds the networks whose addresses are never
+	// advertised, eg a management VLAN.
+	Exclude []*net.IPNet
+}
+
+// NewAdvertisePolicy returns an AdvertisePolicy pinning the given
+// addresses, each a host optionally followed by a port, and excluding
+// the addresses in the given CIDRs. Pinned addresses without a port are
+// given defaultPort.
+func NewAdvertisePolicy(pinned, excludeCIDRs []string, defaultPort int) (AdvertisePolicy, error) {
+	var policy AdvertisePolicy
+	for _, value := range pinned {
+		hp, err := ParseAdvertisedAddress(value, defaultPort)
+		if err != nil {
+			return AdvertisePolicy{}, errors.Trace(err)
+		}
+		policy.Pinned = append(policy.Pinned, hp)
+	}
+	for _, cidr := range excludeCIDRs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return AdvertisePolicy{}, errors.Trace(err)
+		}
+		policy.Exclude = append(policy.Exclude, ipNet)
+	}
+	return policy, nil
+}
+
+// ParseAdvertisedAddress parses an address to be advertised to agents,
+// which is a host name or IP address optionally followed by a port, eg
+// "203.0.113.7:17070". An address without a port is given defaultPort.
+func ParseAdvertisedAddress(value string, defaultPort int) (HostPort, error) {
+	host, port := value, defaultPort
+	// A bare IPv6 address contains colons but has no port.
+	if net.ParseIP(value) == nil {
+		if h, p, err := net.SplitHostPort(value); err == nil {
+			n, err := strconv.Atoi(p)
+			if err != nil || n < 1 || n > 65535 {
+				return HostPort{}, errors.NotValidf("port in address %q", value)
+			}
+			host, port = h, n
+		}
+	}
+	if host == "" || strings.ContainsAny(host, " /[]") {
+		return HostPort{}, errors.NotValidf("address %q", value)
+	}
+	return NewHostPorts(port, host)[0], nil
+}
+
+// Apply returns the API server addresses to advertise, given the
+// addresses of each server. Excluded addresses are removed, and servers
+// left without addresses are dropped. The pinned addresses are added as
+// a server of their own, ahead of the others.
+func (p AdvertisePolicy) Apply(servers [][]HostPort) [][]HostPort {
+	result := make([][]HostPort, 0, len(servers)+1)
+	if len(p.Pinned) > 0 {
+		result = append(result, append([]HostPort(nil), p.Pinned...))
+	}
+	for _, hostPorts := range servers {
+		var kept []HostPort
+		for _, hp := range hostPorts {
+			if !p.excludes(hp.Address) {
+				kept = append(kept, hp)
+			}
+		}
+		if len(kept) > 0 {
+			result = append(result, kept)
+		}
+	}
+	return result
+}
+
+// excludes returns whether the address is in an excluded network.
+// Addresses that are not IP addresses are never excluded.
+func (p AdvertisePolicy) excludes(addr Address) bool {
+	ip := net.ParseIP(addr.Value)
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range p.Exclude {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
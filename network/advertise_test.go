@@ -0,0 +1,93 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type AdvertisePolicySuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&AdvertisePolicySuite{})
+
+func (s *AdvertisePolicySuite) TestParseAdvertisedAddress(c *gc.C) {
+	for i, test := range []struct {
+		value    string
+		expected network.HostPort
+	}{
+		{"203.0.113.7", network.NewHostPorts(17070, "203.0.113.7")[0]},
+		{"203.0.113.7:443", network.NewHostPorts(443, "203.0.113.7")[0]},
+		{"api.example.com", network.NewHostPorts(17070, "api.example.com")[0]},
+		{"api.example.com:443", network.NewHostPorts(443, "api.example.com")[0]},
+		{"2001:db8::1", network.NewHostPorts(17070, "2001:db8::1")[0]},
+		{"[2001:db8::1]:443", network.NewHostPorts(443, "2001:db8::1")[0]},
+	} {
+		c.Logf("test %d: %s", i, test.value)
+		hp, err := network.ParseAdvertisedAddress(test.value, 17070)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(hp, jc.DeepEquals, test.expected)
+	}
+}
+
+func (s *AdvertisePolicySuite) TestParseAdvertisedAddressInvalid(c *gc.C) {
+	for i, test := range []struct {
+		value       string
+		expectError string
+	}{
+		{"", `address "" not valid`},
+		{"10.0.0.0/24", `address "10.0.0.0/24" not valid`},
+		{"[2001:db8::1]", `address "\[2001:db8::1\]" not valid`},
+		{"203.0.113.7:0", `port in address "203.0.113.7:0" not valid`},
+		{"203.0.113.7:http", `port in address "203.0.113.7:http" not valid`},
+	} {
+		c.Logf("test %d: %q", i, test.value)
+		_, err := network.ParseAdvertisedAddress(test.value, 17070)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+		c.Check(err, gc.ErrorMatches, test.expectError)
+	}
+}
+
+func (s *AdvertisePolicySuite) TestNewAdvertisePolicyInvalidCIDR(c *gc.C) {
+	_, err := network.NewAdvertisePolicy(nil, []string{"10.0.0.1"}, 17070)
+	c.Assert(err, gc.ErrorMatches, "invalid CIDR address: 10.0.0.1")
+}
+
+func (s *AdvertisePolicySuite) TestApply(c *gc.C) {
+	policy, err := network.NewAdvertisePolicy(
+		[]string{"203.0.113.7", "api.example.com:443"},
+		[]string{"192.168.100.0/24"},
+		17070,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	servers := [][]network.HostPort{
+		network.NewHostPorts(17070, "10.0.0.1", "192.168.100.1", "controller-0.internal"),
+		network.NewHostPorts(17070, "192.168.100.2"),
+		network.NewHostPorts(17070, "10.0.0.3"),
+	}
+	c.Assert(policy.Apply(servers), jc.DeepEquals, [][]network.HostPort{
+		append(
+			network.NewHostPorts(17070, "203.0.113.7"),
+			network.NewHostPorts(443, "api.example.com")...,
+		),
+		network.NewHostPorts(17070, "10.0.0.1", "controller-0.internal"),
+		network.NewHostPorts(17070, "10.0.0.3"),
+	})
+	// The servers passed in are left alone.
+	c.Assert(servers[1], gc.HasLen, 1)
+}
+
+func (s *AdvertisePolicySuite) TestApplyEmptyPolicy(c *gc.C) {
+	servers := [][]network.HostPort{
+		network.NewHostPorts(17070, "10.0.0.1"),
+		network.NewHostPorts(17070, "10.0.0.2"),
+	}
+	c.Assert(network.AdvertisePolicy{}.Apply(servers), jc.DeepEquals, servers)
+}
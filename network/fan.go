@@ -1,8 +1,8 @@
 package network
 
 import (
-	"encoding/binary"
 	"fmt"
+	"math/big"
 	"net"
 	"strings"
 
@@ -20,6 +20,7 @@ type FanConfig []FanConfigEntry
 
 // ParseFanConfig parses fan configuration from model-config in the format:
 // "underlay1=overlay1 underlay2=overlay2" eg. "172.16.0.0/16=253.0.0.0/8 10.0.0.0/12:254.0.0.0/7"
+// or, for IPv6, "2001:db8::/48=fd00::/32".
 func ParseFanConfig(line string) (config FanConfig, err error) {
 	if line == "" {
 		return nil, nil
@@ -37,8 +38,11 @@ func ParseFanConfig(line string) (config FanConfig, err error) {
 		if _, config[i].Overlay, err = net.ParseCIDR(strings.TrimSpace(cidrs[1])); err != nil {
 			return nil, errors.Annotatef(err, "invalid address in FAN config")
 		}
-		underlaySize, _ := config[i].Underlay.Mask.Size()
-		overlaySize, _ := config[i].Overlay.Mask.Size()
+		underlaySize, underlayBits := config[i].Underlay.Mask.Size()
+		overlaySize, overlayBits := config[i].Overlay.Mask.Size()
+		if underlayBits != overlayBits {
+			return nil, fmt.Errorf("invalid FAN config, underlay and overlay must both be IPv4 or both be IPv6: %s", line)
+		}
 		if underlaySize <= overlaySize {
 			return nil, fmt.Errorf("invalid FAN config, underlay mask must be larger than overlay: %s", line)
 		}
@@ -57,30 +61,36 @@ func (fc *FanConfig) String() (line string) {
 // CalculateOverlaySegment takes underlay CIDR and FAN config entry and
 // cuts the segment of overlay that corresponds to this underlay:
 // eg. for FAN 172.31/16 -> 243/8 and physical subnet 172.31.64/20
-// we get FAN subnet 243.64/12.
+// we get FAN subnet 243.64/12. Both IPv4 and IPv6 fans are supported.
 func CalculateOverlaySegment(underlayCIDR string, fan FanConfigEntry) (*net.IPNet, error) {
 	_, underlayNet, err := net.ParseCIDR(underlayCIDR)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	subnetSize, _ := underlayNet.Mask.Size()
-	underlaySize, _ := fan.Underlay.Mask.Size()
-	if underlaySize <= subnetSize && fan.Underlay.Contains(underlayNet.IP) {
-		overlaySize, _ := fan.Overlay.Mask.Size()
-		newOverlaySize := overlaySize + (subnetSize - underlaySize)
-		fanSize := uint(underlaySize - overlaySize)
-		newFanIP := underlayNet.IP.To4()
-		for i := 0; i < 4; i++ {
-			newFanIP[i] &^= fan.Underlay.Mask[i]
-		}
-		numIp := binary.BigEndian.Uint32(newFanIP)
-		numIp <<= fanSize
-		binary.BigEndian.PutUint32(newFanIP, numIp)
-		for i := 0; i < 4; i++ {
-			newFanIP[i] += fan.Overlay.IP[i]
-		}
-		return &net.IPNet{IP: newFanIP, Mask: net.CIDRMask(newOverlaySize, 32)}, nil
-	} else {
+	subnetSize, bits := underlayNet.Mask.Size()
+	underlaySize, underlayBits := fan.Underlay.Mask.Size()
+	if bits != underlayBits || underlaySize > subnetSize || !fan.Underlay.Contains(underlayNet.IP) {
 		return nil, nil
 	}
+	overlaySize, _ := fan.Overlay.Mask.Size()
+	newOverlaySize := overlaySize + (subnetSize - underlaySize)
+	fanSize := uint(underlaySize - overlaySize)
+
+	// The bits of the local underlay beyond the FAN underlay's mask
+	// are moved up to follow the overlay's mask.
+	localBits := make([]byte, len(underlayNet.IP))
+	for i := range localBits {
+		localBits[i] = underlayNet.IP[i] &^ fan.Underlay.Mask[i]
+	}
+	segment := new(big.Int).SetBytes(localBits)
+	segment.Lsh(segment, fanSize)
+	overlayIP := fan.Overlay.IP
+	if bits == 32 {
+		overlayIP = overlayIP.To4()
+	}
+	segment.Or(segment, new(big.Int).SetBytes(overlayIP))
+	newFanIP := make(net.IP, len(localBits))
+	segmentBytes := segment.Bytes()
+	copy(newFanIP[len(newFanIP)-len(segmentBytes):], segmentBytes)
+	return &net.IPNet{IP: newFanIP, Mask: net.CIDRMask(newOverlaySize, bits)}, nil
 }
@@ -64,6 +64,11 @@ func (*FanConfigSuite) TestFanConfigErrors(c *gc.C) {
 	c.Check(config, gc.IsNil)
 	c.Check(err, gc.ErrorMatches, "invalid address in FAN config:.*")
 
+	// Mixed IPv4 and IPv6.
+	config, err = network.ParseFanConfig("172.31.0.0/16=fd00::/8")
+	c.Check(config, gc.IsNil)
+	c.Check(err, gc.ErrorMatches, "invalid FAN config, underlay and overlay must both be IPv4 or both be IPv6:.*")
+
 	// Underlay mask smaller than overlay.
 	config, err = network.ParseFanConfig("1.0.0.0/8=2.0.0.0/16")
 	c.Check(config, gc.IsNil)
@@ -105,3 +110,44 @@ func (*FanConfigSuite) TestCalculateOverlaySegment(c *gc.C) {
 	c.Assert(net, gc.NotNil)
 	c.Check(net.String(), gc.Equals, "252.92.0.0/14")
 }
+
+func (*FanConfigSuite) TestFanConfigParseIPv6(c *gc.C) {
+	input := "2001:db8::/48=fd00::/32 172.31.0.0/16=253.0.0.0/8"
+	config, err := network.ParseFanConfig(input)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config, gc.HasLen, 2)
+	_, underlay, _ := net.ParseCIDR("2001:db8::/48")
+	_, overlay, _ := net.ParseCIDR("fd00::/32")
+	c.Check(config[0].Underlay, gc.DeepEquals, underlay)
+	c.Check(config[0].Overlay, gc.DeepEquals, overlay)
+	c.Check(config.String(), gc.Equals, input)
+
+	// Underlay mask smaller than overlay.
+	_, err = network.ParseFanConfig("2001:db8::/32=fd00::/48")
+	c.Check(err, gc.ErrorMatches, "invalid FAN config, underlay mask must be larger than overlay:.*")
+}
+
+func (*FanConfigSuite) TestCalculateOverlaySegmentIPv6(c *gc.C) {
+	config, err := network.ParseFanConfig("2001:db8::/48=fd00::/32 172.31.0.0/16=253.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The 16 bits of the /64 beyond the underlay's /48 follow the
+	// overlay's /32.
+	net, err := network.CalculateOverlaySegment("2001:db8:0:12::/64", config[0])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(net, gc.NotNil)
+	c.Check(net.String(), gc.Equals, "fd00:0:12::/48")
+
+	// Underlay outside of FAN scope
+	net, err = network.CalculateOverlaySegment("2001:db9::/64", config[0])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(net, gc.IsNil)
+
+	// An IPv4 underlay is never in an IPv6 FAN, nor the reverse.
+	net, err = network.CalculateOverlaySegment("172.31.16.0/20", config[0])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(net, gc.IsNil)
+	net, err = network.CalculateOverlaySegment("2001:db8:0:12::/64", config[1])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(net, gc.IsNil)
+}
@@ -0,0 +1,123 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network
+
+import (
+	"net"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// fanOverlayCandidates holds the first octets of the /8 networks, all
+// reserved for future use and so never routed, that are tried in turn
+// as fan overlays.
+var fanOverlayCandidates = []byte{252, 253, 254, 250, 251, 240, 241, 242, 243, 244, 245, 246, 247, 248, 249}
+
+// ComputeFanConfig returns a fan configuration mapping each of the given
+// underlay CIDRs, as discovered by a provider, to its own /8 overlay.
+//
+// Underlays that are not IPv4, that are too large to map onto a /8, or
+// that lie within another of the underlays are skipped. Overlays are
+// chosen so that they clash with neither the underlays nor any of the
+// networks to avoid, which are CIDRs or IP addresses, eg egress subnets
+// and no-proxy entries. Entries to avoid that are neither, such as
+// host names, are ignored. If the candidate overlays run out, the
+// remaining underlays are left unconfigured.
+func ComputeFanConfig(underlays, avoid []string) (FanConfig, error) {
+	var nets []*net.IPNet
+	for _, cidr := range underlays {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Annotatef(err, "underlay %q", cidr)
+		}
+		nets = append(nets, ipNet)
+	}
+	var clashes []*net.IPNet
+	clashes = append(clashes, nets...)
+	for _, value := range avoid {
+		if ipNet := parseNetOrAddress(value); ipNet != nil {
+			clashes = append(clashes, ipNet)
+		}
+	}
+
+	var config FanConfig
+	next := 0
+	for i, underlay := range nets {
+		if !fanUnderlayUsable(underlay) || containedInOther(underlay, i, nets) {
+			continue
+		}
+		var overlay *net.IPNet
+		for overlay == nil && next < len(fanOverlayCandidates) {
+			candidate := &net.IPNet{
+				IP:   net.IPv4(fanOverlayCandidates[next], 0, 0, 0).To4(),
+				Mask: net.CIDRMask(8, 32),
+			}
+			next++
+			if !overlapsAny(candidate, clashes) {
+				overlay = candidate
+			}
+		}
+		if overlay == nil {
+			logger.Warningf("no fan overlay left for underlay %s", underlay)
+			break
+		}
+		config = append(config, FanConfigEntry{Underlay: underlay, Overlay: overlay})
+	}
+	return config, nil
+}
+
+// fanUnderlayUsable returns whether the network can be mapped onto a
+// /8 fan overlay.
+func fanUnderlayUsable(underlay *net.IPNet) bool {
+	if underlay.IP.To4() == nil {
+		return false
+	}
+	ones, _ := underlay.Mask.Size()
+	return ones > 8
+}
+
+// containedInOther returns whether nets[i] lies within another of the
+// networks. Of identical networks, all but the first are contained.
+func containedInOther(ipNet *net.IPNet, i int, nets []*net.IPNet) bool {
+	ones, _ := ipNet.Mask.Size()
+	for j, other := range nets {
+		if j == i || !other.Contains(ipNet.IP) {
+			continue
+		}
+		otherOnes, _ := other.Mask.Size()
+		if otherOnes < ones || (otherOnes == ones && j < i) {
+			return true
+		}
+	}
+	return false
+}
+
+// overlapsAny returns whether the network overlaps any of the others.
+func overlapsAny(ipNet *net.IPNet, others []*net.IPNet) bool {
+	for _, other := range others {
+		if ipNet.Contains(other.IP) || other.Contains(ipNet.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNetOrAddress parses a CIDR or an IP address, returning the
+// network or the single address network, or nil if the value is
+// neither.
+func parseNetOrAddress(value string) *net.IPNet {
+	value = strings.TrimSpace(value)
+	if _, ipNet, err := net.ParseCIDR(value); err == nil {
+		return ipNet
+	}
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+}
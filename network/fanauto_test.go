@@ -0,0 +1,74 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network_test
+
+import (
+	"fmt"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type ComputeFanConfigSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&ComputeFanConfigSuite{})
+
+func (*ComputeFanConfigSuite) computeFanConfig(c *gc.C, underlays, avoid []string) string {
+	config, err := network.ComputeFanConfig(underlays, avoid)
+	c.Assert(err, jc.ErrorIsNil)
+	return config.String()
+}
+
+func (s *ComputeFanConfigSuite) TestEmpty(c *gc.C) {
+	config, err := network.ComputeFanConfig(nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config, gc.HasLen, 0)
+}
+
+func (s *ComputeFanConfigSuite) TestSkipsUnusableUnderlays(c *gc.C) {
+	config := s.computeFanConfig(c, []string{"172.31.0.0/16", "10.0.0.0/8", "2001:db8::/64", "192.168.1.0/24"}, nil)
+	c.Assert(config, gc.Equals, "172.31.0.0/16=252.0.0.0/8 192.168.1.0/24=253.0.0.0/8")
+}
+
+func (s *ComputeFanConfigSuite) TestSkipsContainedUnderlays(c *gc.C) {
+	// Multi-subnet VPCs report the subnets alongside the VPC's CIDR.
+	config := s.computeFanConfig(c, []string{
+		"172.31.0.0/20", "172.31.0.0/16", "172.31.16.0/20", "172.31.0.0/16", "10.1.0.0/16",
+	}, nil)
+	c.Assert(config, gc.Equals, "172.31.0.0/16=252.0.0.0/8 10.1.0.0/16=253.0.0.0/8")
+}
+
+func (s *ComputeFanConfigSuite) TestAvoidsClashes(c *gc.C) {
+	config := s.computeFanConfig(c,
+		[]string{"172.31.0.0/16", "192.168.1.0/24", "10.2.0.0/16"},
+		[]string{"252.1.0.0/16", "localhost", " 253.0.0.1", "::1", ""},
+	)
+	c.Assert(config, gc.Equals, "172.31.0.0/16=254.0.0.0/8 192.168.1.0/24=250.0.0.0/8 10.2.0.0/16=251.0.0.0/8")
+}
+
+func (s *ComputeFanConfigSuite) TestAvoidsUnderlays(c *gc.C) {
+	config := s.computeFanConfig(c, []string{"252.0.0.0/16", "172.31.0.0/16"}, nil)
+	c.Assert(config, gc.Equals, "252.0.0.0/16=253.0.0.0/8 172.31.0.0/16=254.0.0.0/8")
+}
+
+func (s *ComputeFanConfigSuite) TestRunsOutOfOverlays(c *gc.C) {
+	var underlays []string
+	for i := 0; i < 20; i++ {
+		underlays = append(underlays, fmt.Sprintf("10.%d.0.0/16", i))
+	}
+	config, err := network.ComputeFanConfig(underlays, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config, gc.HasLen, 15)
+	c.Assert(config[14].Overlay.String(), gc.Equals, "249.0.0.0/8")
+}
+
+func (s *ComputeFanConfigSuite) TestInvalidUnderlay(c *gc.C) {
+	_, err := network.ComputeFanConfig([]string{"foo"}, nil)
+	c.Assert(err, gc.ErrorMatches, `underlay "foo": invalid CIDR address: foo`)
+}
@@ -0,0 +1,14 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+//go:build dummy
+// +build dummy
+
+package all
+
+// The dummy provider is only registered in binaries built with the
+// "dummy" tag, for staging workers and facades against a simulated
+// cloud; see the simulation attributes in provider/dummy.
+import (
+	_ "github.com/juju/juju/provider/dummy"
+)
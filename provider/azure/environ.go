@@ -243,6 +243,15 @@ func (env *azureEnviron) Bootstrap(
 	return result, nil
 }
 
+// ResumeBootstrap is part of the environs.BootstrapResumer interface.
+func (env *azureEnviron) ResumeBootstrap(
+	ctx environs.BootstrapContext,
+	args environs.BootstrapParams,
+	state environs.BootstrapResumeState,
+) (*environs.BootstrapResult, error) {
+	return common.ResumeBootstrap(ctx, env, args, state)
+}
+
 // initResourceGroup creates a resource group for this environment.
 func (env *azureEnviron) initResourceGroup(controllerUUID string, controller bool) error {
 	resourceGroupsClient := resources.GroupsClient{env.resources}
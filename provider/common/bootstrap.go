@@ -45,19 +45,62 @@ var logger = loggo.GetLogger("juju.provider.common")
 // when writing a new provider.
 func Bootstrap(ctx environs.BootstrapContext, env environs.Environ, args environs.BootstrapParams,
 ) (*environs.BootstrapResult, error) {
-	result, series, finalizer, err := BootstrapInstance(ctx, env, args)
+	result, series, initialSSHHostKeys, finalizer, err := bootstrapInstance(ctx, env, args)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 
+	resumeState := &environs.BootstrapResumeState{
+		InstanceId:         result.Instance.Id(),
+		Arch:               *result.Hardware.Arch,
+		Series:             series,
+		Hardware:           result.Hardware,
+		InitialSSHHostKeys: initialSSHHostKeys,
+	}
+	if result.Config != nil {
+		resumeState.Config = result.Config.UnknownAttrs()
+	}
 	bsResult := &environs.BootstrapResult{
-		Arch:     *result.Hardware.Arch,
-		Series:   series,
-		Finalize: finalizer,
+		Arch:        *result.Hardware.Arch,
+		Series:      series,
+		Finalize:    finalizer,
+		ResumeState: resumeState,
 	}
 	return bsResult, nil
 }
 
+// ResumeBootstrap is a common implementation of the ResumeBootstrap
+// method defined on environs.BootstrapResumer, for providers that use
+// Bootstrap above. It finds the instance started by the interrupted
+// bootstrap, and returns a result whose finalizer installs the
+// controller on it.
+func ResumeBootstrap(
+	ctx environs.BootstrapContext,
+	env environs.Environ,
+	args environs.BootstrapParams,
+	state environs.BootstrapResumeState,
+) (*environs.BootstrapResult, error) {
+	client := ssh.DefaultClient
+	if client == nil {
+		return nil, fmt.Errorf("no SSH client available")
+	}
+	insts, err := env.Instances([]instance.Id{state.InstanceId})
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot find bootstrap instance %q", state.InstanceId)
+	}
+	ctx.Infof("Resuming bootstrap of controller instance %s", state.InstanceId)
+	finalize := bootstrapFinalizer(
+		env, client, insts[0], state.Hardware,
+		state.InitialSSHHostKeys, state.Config, args.ContainerBridgeName,
+	)
+	return &environs.BootstrapResult{
+		Arch:        state.Arch,
+		Series:      state.Series,
+		Finalize:    finalize,
+		ResumeState: &state,
+	}, nil
+}
+
 // BootstrapInstance creates a new instance with the series of its choice,
 // constrained to those of the available tools, and
 // returns the instance result, series, and a function that
@@ -66,7 +109,15 @@ func Bootstrap(ctx environs.BootstrapContext, env environs.Environ, args environ
 // This method is called by Bootstrap above, which implements environs.Bootstrap, but
 // is also exported so that providers can manipulate the started instance.
 func BootstrapInstance(ctx environs.BootstrapContext, env environs.Environ, args environs.BootstrapParams,
-) (_ *environs.StartInstanceResult, selectedSeries string, _ environs.BootstrapFinalizer, err error) {
+) (*environs.StartInstanceResult, string, environs.BootstrapFinalizer, error) {
+	result, selectedSeries, _, finalize, err := bootstrapInstance(ctx, env, args)
+	return result, selectedSeries, finalize, err
+}
+
+// bootstrapInstance implements BootstrapInstance, additionally
+// returning the SSH host keys the instance was configured with.
+func bootstrapInstance(ctx environs.BootstrapContext, env environs.Environ, args environs.BootstrapParams,
+) (_ *environs.StartInstanceResult, selectedSeries string, _ instancecfg.SSHHostKeys, _ environs.BootstrapFinalizer, err error) {
 	// TODO make safe in the case of racing Bootstraps
 	// If two Bootstraps are called concurrently, there's
 	// no way to make sure that only one succeeds.
@@ -81,14 +132,14 @@ func BootstrapInstance(ctx environs.BootstrapContext, env environs.Environ, args
 		Series: selectedSeries,
 	})
 	if err != nil {
-		return nil, "", nil, err
+		return nil, "", instancecfg.SSHHostKeys{}, nil, err
 	}
 
 	// Filter image metadata to the selected series.
 	var imageMetadata []*imagemetadata.ImageMetadata
 	seriesVersion, err := series.SeriesVersion(selectedSeries)
 	if err != nil {
-		return nil, "", nil, errors.Trace(err)
+		return nil, "", instancecfg.SSHHostKeys{}, nil, errors.Trace(err)
 	}
 	for _, m := range args.ImageMetadata {
 		if m.Version != seriesVersion {
@@ -103,44 +154,32 @@ func BootstrapInstance(ctx environs.BootstrapContext, env environs.Environ, args
 	if client == nil {
 		// This should never happen: if we don't have OpenSSH, then
 		// go.crypto/ssh should be used with an auto-generated key.
-		return nil, "", nil, fmt.Errorf("no SSH client available")
+		return nil, "", instancecfg.SSHHostKeys{}, nil, fmt.Errorf("no SSH client available")
 	}
 
 	publicKey, err := simplestreams.UserPublicSigningKey()
 	if err != nil {
-		return nil, "", nil, err
+		return nil, "", instancecfg.SSHHostKeys{}, nil, err
 	}
 	envCfg := env.Config()
 	instanceConfig, err := instancecfg.NewBootstrapInstanceConfig(
 		args.ControllerConfig, args.BootstrapConstraints, args.ModelConstraints, selectedSeries, publicKey,
 	)
 	if err != nil {
-		return nil, "", nil, err
+		return nil, "", instancecfg.SSHHostKeys{}, nil, err
 	}
 	instanceConfig.EnableOSRefreshUpdate = env.Config().EnableOSRefreshUpdate()
 	instanceConfig.EnableOSUpgrade = env.Config().EnableOSUpgrade()
 	instanceConfig.NetBondReconfigureDelay = env.Config().NetBondReconfigureDelay()
 
 	instanceConfig.Tags = instancecfg.InstanceTags(envCfg.UUID(), args.ControllerConfig.ControllerUUID(), envCfg, instanceConfig.Jobs)
-	maybeSetBridge := func(icfg *instancecfg.InstanceConfig) {
-		// If we need to override the default bridge name, do it now. When
-		// args.ContainerBridgeName is empty, the default names for LXC
-		// (lxcbr0) and KVM (virbr0) will be used.
-		if args.ContainerBridgeName != "" {
-			logger.Debugf("using %q as network bridge for all container types", args.ContainerBridgeName)
-			if icfg.AgentEnvironment == nil {
-				icfg.AgentEnvironment = make(map[string]string)
-			}
-			icfg.AgentEnvironment[agent.LxcBridge] = args.ContainerBridgeName
-		}
-	}
-	maybeSetBridge(instanceConfig)
+	maybeSetBridge(instanceConfig, args.ContainerBridgeName)
 
 	// We're creating a new instance; inject host keys so that we can then
 	// make an SSH connection with known keys.
 	initialSSHHostKeys, err := generateSSHHostKeys()
 	if err != nil {
-		return nil, "", nil, errors.Annotate(err, "generating SSH host keys")
+		return nil, "", instancecfg.SSHHostKeys{}, nil, errors.Annotate(err, "generating SSH host keys")
 	}
 	instanceConfig.Bootstrap.InitialSSHHostKeys = initialSSHHostKeys
 
@@ -188,7 +227,7 @@ func BootstrapInstance(ctx environs.BootstrapContext, env environs.Environ, args
 		// a blank StartInstanceParams.AvailabilityZone.
 		zones = []string{""}
 	} else if err != nil {
-		return nil, "", nil, errors.Annotate(err, "cannot start bootstrap instance")
+		return nil, "", instancecfg.SSHHostKeys{}, nil, errors.Annotate(err, "cannot start bootstrap instance")
 	}
 
 	var result *environs.StartInstanceResult
@@ -199,7 +238,7 @@ func BootstrapInstance(ctx environs.BootstrapContext, env environs.Environ, args
 			break
 		}
 		if zone == "" || environs.IsAvailabilityZoneIndependent(err) {
-			return nil, "", nil, errors.Annotate(err, "cannot start bootstrap instance")
+			return nil, "", instancecfg.SSHHostKeys{}, nil, errors.Annotate(err, "cannot start bootstrap instance")
 		}
 		if i < len(zones)-1 {
 			// Try the next zone.
@@ -208,12 +247,12 @@ func BootstrapInstance(ctx environs.BootstrapContext, env environs.Environ, args
 		}
 		// This is the last zone in the list, error.
 		if len(zones) > 1 {
-			return nil, "", nil, errors.Errorf(
+			return nil, "", instancecfg.SSHHostKeys{}, nil, errors.Errorf(
 				"cannot start bootstrap instance in any availability zone (%s)",
 				strings.Join(zones, ", "),
 			)
 		}
-		return nil, "", nil, errors.Annotatef(err, "cannot start bootstrap instance in availability zone %q", zone)
+		return nil, "", instancecfg.SSHHostKeys{}, nil, errors.Annotatef(err, "cannot start bootstrap instance in availability zone %q", zone)
 	}
 
 	msg := fmt.Sprintf(" - %s (%s)", result.Instance.Id(), formatHardware(result.Hardware))
@@ -224,13 +263,36 @@ func BootstrapInstance(ctx environs.BootstrapContext, env environs.Environ, args
 	}
 	ctx.Infof(msg)
 
-	finalize := func(ctx environs.BootstrapContext, icfg *instancecfg.InstanceConfig, opts environs.BootstrapDialOpts) error {
-		icfg.Bootstrap.BootstrapMachineInstanceId = result.Instance.Id()
-		icfg.Bootstrap.BootstrapMachineHardwareCharacteristics = result.Hardware
+	var cfgAttrs map[string]interface{}
+	if result.Config != nil {
+		cfgAttrs = result.Config.UnknownAttrs()
+	}
+	finalize := bootstrapFinalizer(
+		env, client, result.Instance, result.Hardware,
+		initialSSHHostKeys, cfgAttrs, args.ContainerBridgeName,
+	)
+	return result, selectedSeries, initialSSHHostKeys, finalize, nil
+}
+
+// bootstrapFinalizer returns a function that finalizes the bootstrap
+// process by installing the initial Juju controller on the given
+// instance.
+func bootstrapFinalizer(
+	env environs.Environ,
+	client ssh.Client,
+	inst instance.Instance,
+	hardware *instance.HardwareCharacteristics,
+	initialSSHHostKeys instancecfg.SSHHostKeys,
+	cfgAttrs map[string]interface{},
+	bridgeName string,
+) environs.BootstrapFinalizer {
+	return func(ctx environs.BootstrapContext, icfg *instancecfg.InstanceConfig, opts environs.BootstrapDialOpts) error {
+		icfg.Bootstrap.BootstrapMachineInstanceId = inst.Id()
+		icfg.Bootstrap.BootstrapMachineHardwareCharacteristics = hardware
 		icfg.Bootstrap.InitialSSHHostKeys = initialSSHHostKeys
 		envConfig := env.Config()
-		if result.Config != nil {
-			updated, err := envConfig.Apply(result.Config.UnknownAttrs())
+		if cfgAttrs != nil {
+			updated, err := envConfig.Apply(cfgAttrs)
 			if err != nil {
 				return errors.Trace(err)
 			}
@@ -239,10 +301,22 @@ func BootstrapInstance(ctx environs.BootstrapContext, env environs.Environ, args
 		if err := instancecfg.FinishInstanceConfig(icfg, envConfig); err != nil {
 			return err
 		}
-		maybeSetBridge(icfg)
-		return FinishBootstrap(ctx, client, env, result.Instance, icfg, opts)
+		maybeSetBridge(icfg, bridgeName)
+		return FinishBootstrap(ctx, client, env, inst, icfg, opts)
+	}
+}
+
+// maybeSetBridge overrides the default container bridge name, if a
+// bridge name is specified. When it is empty, the default names for LXC
+// (lxcbr0) and KVM (virbr0) will be used.
+func maybeSetBridge(icfg *instancecfg.InstanceConfig, bridgeName string) {
+	if bridgeName != "" {
+		logger.Debugf("using %q as network bridge for all container types", bridgeName)
+		if icfg.AgentEnvironment == nil {
+			icfg.AgentEnvironment = make(map[string]string)
+		}
+		icfg.AgentEnvironment[agent.LxcBridge] = bridgeName
 	}
-	return result, selectedSeries, finalize, nil
 }
 
 func startInstanceZones(env environs.Environ, args environs.StartInstanceParams) ([]string, error) {
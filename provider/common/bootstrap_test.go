@@ -387,6 +387,13 @@ func (s *BootstrapSuite) TestSuccess(c *gc.C) {
 	c.Assert(result.Arch, gc.Equals, "ppc64el") // based on hardware characteristics
 	c.Assert(result.Series, gc.Equals, config.PreferredSeries(mocksConfig))
 	c.Assert(result.Finalize, gc.NotNil)
+	c.Assert(result.ResumeState, jc.DeepEquals, &environs.BootstrapResumeState{
+		InstanceId:         instance.Id(checkInstanceId),
+		Arch:               "ppc64el",
+		Series:             config.PreferredSeries(mocksConfig),
+		Hardware:           &checkHardware,
+		InitialSSHHostKeys: innerInstanceConfig.Bootstrap.InitialSSHHostKeys,
+	})
 
 	// Check that we make the SSH connection with desired options.
 	var knownHosts string
@@ -433,6 +440,43 @@ func (s *BootstrapSuite) TestSuccess(c *gc.C) {
 	)
 }
 
+func (s *BootstrapSuite) TestResumeBootstrap(c *gc.C) {
+	inst := &mockInstance{id: "i-resumed"}
+	env := &mockEnviron{
+		config: configGetter(c),
+		instances: func(ids []instance.Id) ([]instance.Instance, error) {
+			c.Assert(ids, jc.DeepEquals, []instance.Id{"i-resumed"})
+			return []instance.Instance{inst}, nil
+		},
+	}
+	state := environs.BootstrapResumeState{
+		InstanceId: "i-resumed",
+		Arch:       "amd64",
+		Series:     "xenial",
+	}
+	ctx := envtesting.BootstrapContext(c)
+	result, err := common.ResumeBootstrap(ctx, env, environs.BootstrapParams{}, state)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Arch, gc.Equals, "amd64")
+	c.Assert(result.Series, gc.Equals, "xenial")
+	c.Assert(result.Finalize, gc.NotNil)
+	c.Assert(result.ResumeState, jc.DeepEquals, &state)
+}
+
+func (s *BootstrapSuite) TestResumeBootstrapInstanceMissing(c *gc.C) {
+	env := &mockEnviron{
+		config: configGetter(c),
+		instances: func(ids []instance.Id) ([]instance.Instance, error) {
+			return nil, environs.ErrNoInstances
+		},
+	}
+	ctx := envtesting.BootstrapContext(c)
+	_, err := common.ResumeBootstrap(ctx, env, environs.BootstrapParams{}, environs.BootstrapResumeState{
+		InstanceId: "i-gone",
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot find bootstrap instance "i-gone": instances not found`)
+}
+
 type neverRefreshes struct {
 }
 
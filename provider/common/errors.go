@@ -25,3 +25,57 @@ type zoneIndependentError struct {
 func (zoneIndependentError) AvailabilityZoneIndependent() bool {
 	return true
 }
+
+// CredentialNotValidError wraps the given error such that it
+// satisfies environs.IsCredentialNotValid.
+func CredentialNotValidError(err error) error {
+	if err == nil {
+		return nil
+	}
+	wrapped := errors.Wrap(err, credentialNotValidError{err})
+	wrapped.(*errors.Err).SetLocation(1)
+	return wrapped
+}
+
+type credentialNotValidError struct {
+	error
+}
+
+// CredentialNotValid is part of the environs.CredentialError interface.
+func (credentialNotValidError) CredentialNotValid() bool {
+	return true
+}
+
+// AvailabilityZoneIndependent is part of the
+// environs.AvailabilityZoneError interface. A rejected
+// credential is rejected in every availability zone.
+func (credentialNotValidError) AvailabilityZoneIndependent() bool {
+	return true
+}
+
+// TransientError wraps the given error such that it
+// satisfies environs.IsTransient.
+func TransientError(err error) error {
+	if err == nil {
+		return nil
+	}
+	wrapped := errors.Wrap(err, transientError{err})
+	wrapped.(*errors.Err).SetLocation(1)
+	return wrapped
+}
+
+type transientError struct {
+	error
+}
+
+// Transient is part of the environs.TransientError interface.
+func (transientError) Transient() bool {
+	return true
+}
+
+// AvailabilityZoneIndependent is part of the
+// environs.AvailabilityZoneError interface. Transient
+// errors pass with time rather than in another zone.
+func (transientError) AvailabilityZoneIndependent() bool {
+	return true
+}
@@ -32,3 +32,23 @@ github.com/juju/juju/provider/common/errors_test.go:.*: foo
 github.com/juju/juju/provider/common/errors_test.go:.*: bar
 github.com/juju/juju/provider/common/errors_test.go:.*: bar: foo`[1:])
 }
+
+func (*ErrorsSuite) TestWrapCredentialNotValidError(c *gc.C) {
+	err := errors.Annotate(errors.New("foo"), "bar")
+	wrapped := common.CredentialNotValidError(err)
+	c.Assert(wrapped, jc.Satisfies, environs.IsCredentialNotValid)
+	c.Assert(wrapped, jc.Satisfies, environs.IsAvailabilityZoneIndependent)
+	c.Assert(wrapped, gc.Not(jc.Satisfies), environs.IsTransient)
+	c.Assert(wrapped, gc.ErrorMatches, "bar: foo")
+	c.Assert(err, gc.Not(jc.Satisfies), environs.IsCredentialNotValid)
+}
+
+func (*ErrorsSuite) TestWrapTransientError(c *gc.C) {
+	err := errors.Annotate(errors.New("foo"), "bar")
+	wrapped := common.TransientError(err)
+	c.Assert(wrapped, jc.Satisfies, environs.IsTransient)
+	c.Assert(wrapped, jc.Satisfies, environs.IsAvailabilityZoneIndependent)
+	c.Assert(wrapped, gc.Not(jc.Satisfies), environs.IsCredentialNotValid)
+	c.Assert(wrapped, gc.ErrorMatches, "bar: foo")
+	c.Assert(err, gc.Not(jc.Satisfies), environs.IsTransient)
+}
@@ -0,0 +1,101 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package dummy
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/dns"
+)
+
+// dnsZone implements dns.Zone, recording the records of a dummy
+// environ's model in memory. It is registered as the "dummy" DNS
+// provider, and can only be used with dummy environs.
+type dnsZone struct {
+	env  *environ
+	name string
+}
+
+func newDNSZone(env environs.Environ, zone string) (dns.Zone, error) {
+	e, ok := env.(*environ)
+	if !ok {
+		return nil, errors.NotSupportedf("dummy DNS provider with %T", env)
+	}
+	return &dnsZone{env: e, name: zone}, nil
+}
+
+// EnsureRecord is specified in the dns.Zone interface.
+func (z *dnsZone) EnsureRecord(record dns.Record) error {
+	if err := z.env.checkBroken("EnsureRecord"); err != nil {
+		return err
+	}
+	if !dns.InZone(record.Name, z.name) {
+		return errors.NotValidf("record %q in zone %q", record.Name, z.name)
+	}
+	estate, err := z.env.state()
+	if err != nil {
+		return err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	estate.dnsRecords[record.Name] = record
+	return nil
+}
+
+// Records is specified in the dns.Zone interface.
+func (z *dnsZone) Records() ([]dns.Record, error) {
+	if err := z.env.checkBroken("Records"); err != nil {
+		return nil, err
+	}
+	estate, err := z.env.state()
+	if err != nil {
+		return nil, err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	var records []dns.Record
+	for _, record := range estate.dnsRecords {
+		if dns.InZone(record.Name, z.name) {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Name < records[j].Name
+	})
+	return records, nil
+}
+
+// RemoveRecord is specified in the dns.Zone interface.
+func (z *dnsZone) RemoveRecord(name string) error {
+	if err := z.env.checkBroken("RemoveRecord"); err != nil {
+		return err
+	}
+	estate, err := z.env.state()
+	if err != nil {
+		return err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	delete(estate.dnsRecords, name)
+	return nil
+}
+
+// DNSRecord returns the records for the named host recorded by the
+// dummy DNS provider for the environ's model.
+func (e *environ) DNSRecord(name string) (dns.Record, error) {
+	estate, err := e.state()
+	if err != nil {
+		return dns.Record{}, err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	record, ok := estate.dnsRecords[name]
+	if !ok {
+		return dns.Record{}, errors.NotFoundf("DNS record %q", name)
+	}
+	return record, nil
+}
@@ -16,6 +16,11 @@
 //
 // The DNS name of instances is the same as the Id,
 // with ".dns" appended.
+//
+// Provisioning latency, intermittent failures, availability
+// zones and subnets can be scripted with the simulation
+// attributes described in simulation.go. The provider is
+// registered in jujud binaries built with the "dummy" tag.
 package dummy
 
 import (
@@ -25,6 +30,7 @@ import (
 	"net/http"
 	"os"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -55,6 +61,7 @@ import (
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/dns"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/mongo/mongotest"
@@ -237,20 +244,23 @@ func APIPort(p environs.EnvironProvider) int {
 // It can be shared between several environ values,
 // so that a given environment can be opened several times.
 type environState struct {
-	name           string
-	ops            chan<- Operation
-	newStatePolicy state.NewPolicyFunc
-	mu             sync.Mutex
-	maxId          int // maximum instance id allocated so far.
-	maxAddr        int // maximum allocated address last byte
-	insts          map[instance.Id]*dummyInstance
-	globalRules    network.IngressRuleSlice
-	bootstrapped   bool
-	apiListener    net.Listener
-	apiServer      *apiserver.Server
-	apiState       *state.State
-	apiStatePool   *state.StatePool
-	creator        string
+	name            string
+	ops             chan<- Operation
+	newStatePolicy  state.NewPolicyFunc
+	mu              sync.Mutex
+	maxId           int // maximum instance id allocated so far.
+	maxAddr         int // maximum allocated address last byte
+	insts           map[instance.Id]*dummyInstance
+	globalRules     network.IngressRuleSlice
+	controllerRules map[string]network.IngressRuleSlice
+	loadBalancers   map[string]environs.LoadBalancerSpec
+	dnsRecords      map[string]dns.Record
+	bootstrapped    bool
+	apiListener     net.Listener
+	apiServer       *apiserver.Server
+	apiState        *state.State
+	apiStatePool    *state.StatePool
+	creator         string
 }
 
 // environ represents a client's connection to a given environment's
@@ -273,6 +283,7 @@ var discardOperations = make(chan Operation)
 
 func init() {
 	environs.RegisterProvider("dummy", &dummy)
+	dns.Register("dummy", newDNSZone)
 
 	// Prime the first ops channel, so that naive clients can use
 	// the testing environment by simply importing it.
@@ -420,11 +431,14 @@ func newState(name string, ops chan<- Operation, newStatePolicy state.NewPolicyF
 	buf := make([]byte, 8192)
 	buf = buf[:runtime.Stack(buf, false)]
 	s := &environState{
-		name:           name,
-		ops:            ops,
-		newStatePolicy: newStatePolicy,
-		insts:          make(map[instance.Id]*dummyInstance),
-		creator:        string(buf),
+		name:            name,
+		ops:             ops,
+		newStatePolicy:  newStatePolicy,
+		insts:           make(map[instance.Id]*dummyInstance),
+		loadBalancers:   make(map[string]environs.LoadBalancerSpec),
+		controllerRules: make(map[string]network.IngressRuleSlice),
+		dnsRecords:      make(map[string]dns.Record),
+		creator:         string(buf),
 	}
 	return s
 }
@@ -487,6 +501,27 @@ var configSchema = environschema.Fields{
 	"secret": {
 		Description: "A secret",
 		Type:        environschema.Tstring,
+		Secret:      true,
+	},
+	provisionDelayKey: {
+		Description: "How long StartInstance and StopInstances take, e.g. 30s",
+		Type:        environschema.Tstring,
+	},
+	flakyKey: {
+		Description: "Whitespace-separated Method=probability pairs; calls of each method fail with the given probability",
+		Type:        environschema.Tstring,
+	},
+	zonesKey: {
+		Description: "Whitespace-separated availability zones, replacing the built-in zones",
+		Type:        environschema.Tstring,
+	},
+	unavailableZonesKey: {
+		Description: "Whitespace-separated availability zones that are reported as unavailable",
+		Type:        environschema.Tstring,
+	},
+	subnetsKey: {
+		Description: "Whitespace-separated subnets, each a CIDR optionally followed by @ and comma-separated zones, replacing the built-in subnets",
+		Type:        environschema.Tstring,
 	},
 }
 
@@ -499,14 +534,20 @@ var configFields = func() schema.Fields {
 }()
 
 var configDefaults = schema.Defaults{
-	"broken":     "",
-	"secret":     "pork",
-	"controller": false,
+	"broken":            "",
+	"secret":            "pork",
+	"controller":        false,
+	provisionDelayKey:   "",
+	flakyKey:            "",
+	zonesKey:            "",
+	unavailableZonesKey: "",
+	subnetsKey:          "",
 }
 
 type environConfig struct {
 	*config.Config
 	attrs map[string]interface{}
+	sim   *simulation
 }
 
 func (c *environConfig) controller() bool {
@@ -526,7 +567,12 @@ func (p *environProvider) newConfig(cfg *config.Config) (*environConfig, error)
 	if err != nil {
 		return nil, err
 	}
-	return &environConfig{valid, valid.UnknownAttrs()}, nil
+	attrs := valid.UnknownAttrs()
+	sim, err := parseSimulation(attrs)
+	if err != nil {
+		return nil, err
+	}
+	return &environConfig{valid, attrs, sim}, nil
 }
 
 func (p *environProvider) Schema() environschema.Fields {
@@ -588,6 +634,9 @@ func (p *environProvider) Validate(cfg, old *config.Config) (valid *config.Confi
 	if err != nil {
 		return nil, err
 	}
+	if _, err := parseSimulation(validated); err != nil {
+		return nil, err
+	}
 	// Apply the coerced unknown values back into the config.
 	return cfg.Apply(validated)
 }
@@ -658,12 +707,13 @@ func (e *environ) ecfg() *environConfig {
 }
 
 func (e *environ) checkBroken(method string) error {
-	for _, m := range strings.Fields(e.ecfg().broken()) {
+	ecfg := e.ecfg()
+	for _, m := range strings.Fields(ecfg.broken()) {
 		if m == method {
 			return fmt.Errorf("dummy.%s is broken", method)
 		}
 	}
-	return nil
+	return ecfg.sim.checkFlaky(method)
 }
 
 // PrecheckInstance is specified in the environs.InstancePrechecker interface.
@@ -991,6 +1041,8 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 	if err := e.checkBroken("StartInstance"); err != nil {
 		return nil, err
 	}
+	sim := e.ecfg().sim
+	sim.provision()
 	estate, err := e.state()
 	if err != nil {
 		return nil, err
@@ -1049,6 +1101,11 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 		if zone == "" && args.AvailabilityZone != "" {
 			zone = args.AvailabilityZone
 		}
+		if len(sim.zones) > 0 {
+			if zone, err = sim.pickZone(zone); err != nil {
+				return nil, err
+			}
+		}
 
 		// We will just assume the instance hardware characteristics exactly matches
 		// the supplied constraints (if specified).
@@ -1150,6 +1207,7 @@ func (e *environ) StopInstances(ids ...instance.Id) error {
 	if err := e.checkBroken("StopInstance"); err != nil {
 		return err
 	}
+	e.ecfg().sim.provision()
 	estate, err := e.state()
 	if err != nil {
 		return err
@@ -1197,6 +1255,54 @@ func (e *environ) Instances(ids []instance.Id) (insts []instance.Instance, err e
 	return
 }
 
+var _ environs.InstanceMetadataGetter = (*environ)(nil)
+
+// InstanceMetadata is specified in the environs.InstanceMetadataGetter
+// interface. Every instance is reported with a single interface
+// holding its addresses.
+func (e *environ) InstanceMetadata(ids []instance.Id) ([]*environs.InstanceMetadata, error) {
+	if err := e.checkBroken("InstanceMetadata"); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	estate, err := e.state()
+	if err != nil {
+		return nil, err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	var result []*environs.InstanceMetadata
+	notFound := 0
+	for _, id := range ids {
+		inst := estate.insts[id]
+		if inst == nil {
+			err = environs.ErrPartialInstances
+			notFound++
+			result = append(result, nil)
+			continue
+		}
+		addresses, _ := inst.Addresses()
+		result = append(result, &environs.InstanceMetadata{
+			InstanceId:      id,
+			InstanceProfile: "dummy-profile",
+			ImageId:         "dummy-image-" + inst.series,
+			Tenancy:         "default",
+			NetworkInterfaces: []environs.InstanceNetworkInterface{{
+				ProviderId:       network.Id(fmt.Sprintf("dummy-%s-eth0", id)),
+				MACAddress:       "aa:bb:cc:dd:ee:f0",
+				ProviderSubnetId: "dummy-private",
+				Addresses:        addresses,
+			}},
+		})
+	}
+	if notFound == len(ids) {
+		return nil, environs.ErrNoInstances
+	}
+	return result, err
+}
+
 // SupportsSpaces is specified on environs.Networking.
 func (env *environ) SupportsSpaces() (bool, error) {
 	dummy.mu.Lock()
@@ -1323,6 +1429,9 @@ func (az azShim) Available() bool {
 
 // AvailabilityZones implements environs.ZonedEnviron.
 func (env *environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
+	if zones := env.ecfg().sim.zones; len(zones) > 0 {
+		return zones, nil
+	}
 	// TODO(dimitern): Fix this properly.
 	return []common.AvailabilityZone{
 		azShim{"zone1", true},
@@ -1337,6 +1446,9 @@ func (env *environ) InstanceAvailabilityZoneNames(ids []instance.Id) ([]string,
 	if err := env.checkBroken("InstanceAvailabilityZoneNames"); err != nil {
 		return nil, errors.NotSupportedf("instance availability zones")
 	}
+	if zones := env.ecfg().sim.zones; len(zones) > 0 {
+		return env.ecfg().sim.instanceZoneNames(len(ids))
+	}
 	availabilityZones, err := env.AvailabilityZones()
 	if err != nil {
 		return nil, err
@@ -1383,6 +1495,16 @@ func (env *environ) Subnets(instId instance.Id, subnetIds []network.Id) ([]netwo
 		// Space discovery needs more subnets to work with.
 		return env.subnetsForSpaceDiscovery(estate)
 	}
+	if subnets := env.ecfg().sim.subnets; len(subnets) > 0 {
+		result := filterSubnets(subnets, subnetIds)
+		estate.ops <- OpSubnets{
+			Env:        env.name,
+			InstanceId: instId,
+			SubnetIds:  subnetIds,
+			Info:       result,
+		}
+		return result, nil
+	}
 
 	allSubnets := []network.SubnetInfo{{
 		CIDR:              "0.10.0.0/24",
@@ -1541,6 +1663,124 @@ func (e *environ) IngressRules() (rules []network.IngressRule, err error) {
 	return
 }
 
+var _ environs.ControllerFirewaller = (*environ)(nil)
+
+// SetControllerIngressRules is specified in the environs.ControllerFirewaller
+// interface.
+func (e *environ) SetControllerIngressRules(controllerUUID string, rules []network.IngressRule) error {
+	if err := e.checkBroken("SetControllerIngressRules"); err != nil {
+		return err
+	}
+	estate, err := e.state()
+	if err != nil {
+		return err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	saved := make(network.IngressRuleSlice, len(rules))
+	copy(saved, rules)
+	network.SortIngressRules(saved)
+	estate.controllerRules[controllerUUID] = saved
+	return nil
+}
+
+// ControllerIngressRules returns the rules last set for the controller
+// with the given UUID by SetControllerIngressRules.
+func (e *environ) ControllerIngressRules(controllerUUID string) ([]network.IngressRule, error) {
+	estate, err := e.state()
+	if err != nil {
+		return nil, err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	rules := estate.controllerRules[controllerUUID]
+	if rules == nil {
+		return nil, errors.NotFoundf("ingress rules for controller %q", controllerUUID)
+	}
+	result := make([]network.IngressRule, len(rules))
+	copy(result, rules)
+	return result, nil
+}
+
+var _ environs.LoadBalancers = (*environ)(nil)
+
+// EnsureLoadBalancer is specified in the environs.LoadBalancers interface.
+func (e *environ) EnsureLoadBalancer(spec environs.LoadBalancerSpec) (*environs.LoadBalancer, error) {
+	if err := e.checkBroken("EnsureLoadBalancer"); err != nil {
+		return nil, err
+	}
+	if spec.Type != config.LoadBalancerNetwork && spec.Type != config.LoadBalancerApplication {
+		return nil, errors.NotSupportedf("load balancer type %q", spec.Type)
+	}
+	estate, err := e.state()
+	if err != nil {
+		return nil, err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	estate.loadBalancers[spec.Name] = spec
+	return dummyLoadBalancer(spec.Name), nil
+}
+
+// LoadBalancers is specified in the environs.LoadBalancers interface.
+func (e *environ) LoadBalancers() ([]environs.LoadBalancer, error) {
+	if err := e.checkBroken("LoadBalancers"); err != nil {
+		return nil, err
+	}
+	estate, err := e.state()
+	if err != nil {
+		return nil, err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	var lbs []environs.LoadBalancer
+	for name := range estate.loadBalancers {
+		lbs = append(lbs, *dummyLoadBalancer(name))
+	}
+	sort.Slice(lbs, func(i, j int) bool {
+		return lbs[i].Name < lbs[j].Name
+	})
+	return lbs, nil
+}
+
+// LoadBalancerSpec returns the spec of the named load balancer
+// provisioned in the dummy environ.
+func (e *environ) LoadBalancerSpec(name string) (environs.LoadBalancerSpec, error) {
+	estate, err := e.state()
+	if err != nil {
+		return environs.LoadBalancerSpec{}, err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	spec, ok := estate.loadBalancers[name]
+	if !ok {
+		return environs.LoadBalancerSpec{}, errors.NotFoundf("load balancer %q", name)
+	}
+	return spec, nil
+}
+
+// RemoveLoadBalancer is specified in the environs.LoadBalancers interface.
+func (e *environ) RemoveLoadBalancer(name string) error {
+	if err := e.checkBroken("RemoveLoadBalancer"); err != nil {
+		return err
+	}
+	estate, err := e.state()
+	if err != nil {
+		return err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	delete(estate.loadBalancers, name)
+	return nil
+}
+
+func dummyLoadBalancer(name string) *environs.LoadBalancer {
+	return &environs.LoadBalancer{
+		Name:      name,
+		Addresses: []network.Address{network.NewAddress(name + ".lb.dummy")},
+	}
+}
+
 func (*environ) Provider() environs.EnvironProvider {
 	return &dummy
 }
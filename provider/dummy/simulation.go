@@ -0,0 +1,210 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package dummy
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/common"
+)
+
+// The simulation attributes script the behaviour of the dummy
+// provider, so that it can stand in for a real cloud when staging
+// workers and facades:
+//
+//	provision-delay    how long StartInstance and StopInstances take,
+//	                   as a duration, e.g. "30s".
+//	flaky              whitespace-separated Method=probability pairs;
+//	                   each call of the method fails with the given
+//	                   probability, e.g. "StartInstance=0.25".
+//	zones              whitespace-separated availability zone names,
+//	                   replacing the built-in zones.
+//	unavailable-zones  whitespace-separated zones that are reported
+//	                   as unavailable.
+//	subnets            whitespace-separated subnets, each a CIDR
+//	                   optionally followed by "@" and a comma-separated
+//	                   list of zones, e.g. "10.0.0.0/24@az1,az2".
+//	                   These replace the built-in subnets.
+//
+// Unlike "broken", which fails every call, "flaky" failures may be
+// retried, which is what the provisioner and other workers do.
+const (
+	provisionDelayKey   = "provision-delay"
+	flakyKey            = "flaky"
+	zonesKey            = "zones"
+	unavailableZonesKey = "unavailable-zones"
+	subnetsKey          = "subnets"
+)
+
+// randFloat64 returns the random numbers deciding whether a flaky
+// method fails. It is a variable so that tests can control it.
+var randFloat64 = rand.Float64
+
+// simulation holds the parsed simulation attributes of a model's
+// config.
+type simulation struct {
+	provisionDelay time.Duration
+	flaky          map[string]float64
+	zones          []common.AvailabilityZone
+	subnets        []network.SubnetInfo
+}
+
+// parseSimulation parses the simulation attributes from the validated
+// provider attributes.
+func parseSimulation(attrs map[string]interface{}) (*simulation, error) {
+	var sim simulation
+	if s, _ := attrs[provisionDelayKey].(string); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid %s", provisionDelayKey)
+		}
+		if d < 0 {
+			return nil, errors.NotValidf("negative %s %q", provisionDelayKey, s)
+		}
+		sim.provisionDelay = d
+	}
+
+	if s, _ := attrs[flakyKey].(string); s != "" {
+		sim.flaky = make(map[string]float64)
+		for _, field := range strings.Fields(s) {
+			parts := strings.SplitN(field, "=", 2)
+			if len(parts) != 2 || parts[0] == "" {
+				return nil, errors.NotValidf("%s entry %q (expected Method=probability)", flakyKey, field)
+			}
+			p, err := strconv.ParseFloat(parts[1], 64)
+			if err != nil || p < 0 || p > 1 {
+				return nil, errors.NotValidf("%s probability %q", flakyKey, parts[1])
+			}
+			sim.flaky[parts[0]] = p
+		}
+	}
+
+	unavailable := make(map[string]bool)
+	if s, _ := attrs[unavailableZonesKey].(string); s != "" {
+		for _, name := range strings.Fields(s) {
+			unavailable[name] = true
+		}
+	}
+	if s, _ := attrs[zonesKey].(string); s != "" {
+		known := make(map[string]bool)
+		for _, name := range strings.Fields(s) {
+			if known[name] {
+				return nil, errors.NotValidf("duplicate zone %q", name)
+			}
+			known[name] = true
+			sim.zones = append(sim.zones, azShim{name, !unavailable[name]})
+		}
+		for name := range unavailable {
+			if !known[name] {
+				return nil, errors.NotValidf("unavailable zone %q not in %s", name, zonesKey)
+			}
+		}
+	} else if len(unavailable) > 0 {
+		return nil, errors.Errorf("%s requires %s", unavailableZonesKey, zonesKey)
+	}
+
+	if s, _ := attrs[subnetsKey].(string); s != "" {
+		for i, field := range strings.Fields(s) {
+			parts := strings.SplitN(field, "@", 2)
+			if _, _, err := net.ParseCIDR(parts[0]); err != nil {
+				return nil, errors.Annotatef(err, "invalid %s entry %q", subnetsKey, field)
+			}
+			subnet := network.SubnetInfo{
+				CIDR:       parts[0],
+				ProviderId: network.Id(fmt.Sprintf("dummy-subnet-%d", i)),
+			}
+			if len(parts) == 2 {
+				subnet.AvailabilityZones = strings.Split(parts[1], ",")
+			}
+			sim.subnets = append(sim.subnets, subnet)
+		}
+	}
+	return &sim, nil
+}
+
+// checkFlaky returns an error if the method has been configured to be
+// flaky and the dice say this call should fail.
+func (sim *simulation) checkFlaky(method string) error {
+	p, ok := sim.flaky[method]
+	if !ok || p == 0 {
+		return nil
+	}
+	if randFloat64() < p {
+		return errors.Errorf("dummy.%s failed (injected failure)", method)
+	}
+	return nil
+}
+
+// provision pauses to simulate the time a cloud takes to provision or
+// release an instance.
+func (sim *simulation) provision() {
+	if sim.provisionDelay > 0 {
+		logger.Infof("provisioning for %v", sim.provisionDelay)
+		<-time.After(sim.provisionDelay)
+	}
+}
+
+// pickZone returns the zone an instance should be started in, given
+// the zone requested, if any. Instances may only be started in
+// available zones; the first available zone is used if none is
+// requested.
+func (sim *simulation) pickZone(requested string) (string, error) {
+	for _, zone := range sim.zones {
+		if requested != "" && zone.Name() != requested {
+			continue
+		}
+		if !zone.Available() {
+			return "", errors.Errorf("availability zone %q is unavailable", zone.Name())
+		}
+		return zone.Name(), nil
+	}
+	if requested != "" {
+		return "", errors.NotFoundf("availability zone %q", requested)
+	}
+	return "", errors.New("no availability zones available")
+}
+
+// instanceZoneNames returns the zones of n instances, spread across
+// the available zones in turn.
+func (sim *simulation) instanceZoneNames(n int) ([]string, error) {
+	var available []string
+	for _, zone := range sim.zones {
+		if zone.Available() {
+			available = append(available, zone.Name())
+		}
+	}
+	if len(available) == 0 {
+		return nil, errors.New("no availability zones available")
+	}
+	names := make([]string, n)
+	for i := range names {
+		names[i] = available[i%len(available)]
+	}
+	return names, nil
+}
+
+// filterSubnets returns the subnets with the given provider ids, or
+// all of them if no ids are given.
+func filterSubnets(subnets []network.SubnetInfo, ids []network.Id) []network.SubnetInfo {
+	if len(ids) == 0 {
+		return append([]network.SubnetInfo{}, subnets...)
+	}
+	var result []network.SubnetInfo
+	for _, id := range ids {
+		for _, subnet := range subnets {
+			if subnet.ProviderId == id {
+				result = append(result, subnet)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,135 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package dummy
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/common"
+)
+
+var _ = gc.Suite(&simulationSuite{})
+
+type simulationSuite struct {
+	testing.IsolationSuite
+}
+
+func zones(zs ...azShim) []common.AvailabilityZone {
+	result := make([]common.AvailabilityZone, len(zs))
+	for i, z := range zs {
+		result[i] = z
+	}
+	return result
+}
+
+func (s *simulationSuite) TestParseEmpty(c *gc.C) {
+	sim, err := parseSimulation(map[string]interface{}{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sim, jc.DeepEquals, &simulation{})
+}
+
+func (s *simulationSuite) TestParse(c *gc.C) {
+	sim, err := parseSimulation(map[string]interface{}{
+		provisionDelayKey:   "30s",
+		flakyKey:            "StartInstance=0.25 Subnets=1",
+		zonesKey:            "az1 az2 az3",
+		unavailableZonesKey: "az2",
+		subnetsKey:          "10.0.0.0/24@az1,az3 10.1.0.0/24",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sim, jc.DeepEquals, &simulation{
+		provisionDelay: 30 * time.Second,
+		flaky:          map[string]float64{"StartInstance": 0.25, "Subnets": 1},
+		zones:          zones(azShim{"az1", true}, azShim{"az2", false}, azShim{"az3", true}),
+		subnets: []network.SubnetInfo{{
+			CIDR:              "10.0.0.0/24",
+			ProviderId:        "dummy-subnet-0",
+			AvailabilityZones: []string{"az1", "az3"},
+		}, {
+			CIDR:       "10.1.0.0/24",
+			ProviderId: "dummy-subnet-1",
+		}},
+	})
+}
+
+var parseErrorTests = []struct {
+	attrs map[string]interface{}
+	err   string
+}{{
+	attrs: map[string]interface{}{provisionDelayKey: "soon"},
+	err:   `invalid provision-delay: time: invalid duration "?soon"?`,
+}, {
+	attrs: map[string]interface{}{provisionDelayKey: "-1s"},
+	err:   `negative provision-delay "-1s" not valid`,
+}, {
+	attrs: map[string]interface{}{flakyKey: "StartInstance"},
+	err:   `flaky entry "StartInstance" \(expected Method=probability\) not valid`,
+}, {
+	attrs: map[string]interface{}{flakyKey: "StartInstance=2"},
+	err:   `flaky probability "2" not valid`,
+}, {
+	attrs: map[string]interface{}{zonesKey: "az1 az1"},
+	err:   `duplicate zone "az1" not valid`,
+}, {
+	attrs: map[string]interface{}{zonesKey: "az1", unavailableZonesKey: "az2"},
+	err:   `unavailable zone "az2" not in zones not valid`,
+}, {
+	attrs: map[string]interface{}{unavailableZonesKey: "az2"},
+	err:   `unavailable-zones requires zones`,
+}, {
+	attrs: map[string]interface{}{subnetsKey: "10.0.0.0@az1"},
+	err:   `invalid subnets entry "10.0.0.0@az1": invalid CIDR address: 10.0.0.0`,
+}}
+
+func (s *simulationSuite) TestParseErrors(c *gc.C) {
+	for i, test := range parseErrorTests {
+		c.Logf("test %d: %v", i, test.attrs)
+		_, err := parseSimulation(test.attrs)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *simulationSuite) TestCheckFlaky(c *gc.C) {
+	dice := 0.5
+	s.PatchValue(&randFloat64, func() float64 { return dice })
+	sim := &simulation{flaky: map[string]float64{"StartInstance": 0.6}}
+
+	c.Check(sim.checkFlaky("StartInstance"), gc.ErrorMatches, `dummy.StartInstance failed \(injected failure\)`)
+	c.Check(sim.checkFlaky("StopInstances"), jc.ErrorIsNil)
+	dice = 0.7
+	c.Check(sim.checkFlaky("StartInstance"), jc.ErrorIsNil)
+}
+
+func (s *simulationSuite) TestPickZone(c *gc.C) {
+	sim := &simulation{zones: zones(azShim{"az1", false}, azShim{"az2", true})}
+
+	zone, err := sim.pickZone("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(zone, gc.Equals, "az2")
+	_, err = sim.pickZone("az1")
+	c.Check(err, gc.ErrorMatches, `availability zone "az1" is unavailable`)
+	_, err = sim.pickZone("az3")
+	c.Check(err, gc.ErrorMatches, `availability zone "az3" not found`)
+}
+
+func (s *simulationSuite) TestInstanceZoneNames(c *gc.C) {
+	sim := &simulation{zones: zones(azShim{"az1", true}, azShim{"az2", false}, azShim{"az3", true})}
+	names, err := sim.instanceZoneNames(3)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(names, jc.DeepEquals, []string{"az1", "az3", "az1"})
+}
+
+func (s *simulationSuite) TestFilterSubnets(c *gc.C) {
+	subnets := []network.SubnetInfo{
+		{CIDR: "10.0.0.0/24", ProviderId: "dummy-subnet-0"},
+		{CIDR: "10.1.0.0/24", ProviderId: "dummy-subnet-1"},
+	}
+	c.Check(filterSubnets(subnets, nil), jc.DeepEquals, subnets)
+	c.Check(filterSubnets(subnets, []network.Id{"dummy-subnet-1", "unknown"}), jc.DeepEquals, subnets[1:])
+}
@@ -314,7 +314,7 @@ func (v *ebsVolumeSource) CreateVolumes(params []storage.VolumeParams) (_ []stor
 		}
 		volume, attachment, err := v.createVolume(p, instances)
 		if err != nil {
-			results[i].Error = err
+			results[i].Error = maybeClassifyError(err)
 			continue
 		}
 		results[i].Volume = volume
@@ -683,7 +683,7 @@ func (v *ebsVolumeSource) AttachVolumes(attachParams []storage.VolumeAttachmentP
 		nextDeviceName := blockDeviceNamer(numbers)
 		_, deviceName, err := v.attachOneVolume(nextDeviceName, params.VolumeId, instId)
 		if err != nil {
-			results[i].Error = err
+			results[i].Error = maybeClassifyError(err)
 			continue
 		}
 		results[i].VolumeAttachment = &storage.VolumeAttachment{
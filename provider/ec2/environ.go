@@ -141,6 +141,15 @@ func (e *environ) Bootstrap(ctx environs.BootstrapContext, args environs.Bootstr
 	return common.Bootstrap(ctx, e, args)
 }
 
+// ResumeBootstrap is part of the environs.BootstrapResumer interface.
+func (e *environ) ResumeBootstrap(
+	ctx environs.BootstrapContext,
+	args environs.BootstrapParams,
+	state environs.BootstrapResumeState,
+) (*environs.BootstrapResult, error) {
+	return common.ResumeBootstrap(ctx, e, args, state)
+}
+
 // SupportsSpaces is specified on environs.Networking.
 func (e *environ) SupportsSpaces() (bool, error) {
 	return true, nil
@@ -575,8 +584,8 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (_ *environs.
 	callback(status.Allocating, fmt.Sprintf("Trying to start instance in availability zone %q", availabilityZone), nil)
 	instResp, err = runInstances(e.ec2, runArgs, callback)
 	if err != nil {
-		err := errors.Annotate(err, "cannot run instances")
-		if !isZoneOrSubnetConstrainedError(err) {
+		err := maybeClassifyError(errors.Annotate(err, "cannot run instances"))
+		if !isZoneOrSubnetConstrainedError(err) && !environs.IsAvailabilityZoneIndependent(err) {
 			err = common.ZoneIndependentError(err)
 		}
 		return nil, err
@@ -1364,13 +1373,13 @@ func (e *environ) openPortsInGroup(name string, rules []network.IngressRule) err
 		for i := range ipPerms {
 			_, err := e.ec2.AuthorizeSecurityGroup(g, ipPerms[i:i+1])
 			if err != nil && ec2ErrCode(err) != "InvalidPermission.Duplicate" {
-				return fmt.Errorf("cannot open port %v: %v", ipPerms[i], err)
+				return maybeClassifyError(errors.Annotatef(err, "cannot open port %v", ipPerms[i]))
 			}
 		}
 		return nil
 	}
 	if err != nil {
-		return fmt.Errorf("cannot open ports: %v", err)
+		return maybeClassifyError(errors.Annotate(err, "cannot open ports"))
 	}
 	return nil
 }
@@ -1388,7 +1397,7 @@ func (e *environ) closePortsInGroup(name string, rules []network.IngressRule) er
 	}
 	_, err = e.ec2.RevokeSecurityGroup(g, rulesToIPPerms(rules))
 	if err != nil {
-		return fmt.Errorf("cannot close ports: %v", err)
+		return maybeClassifyError(errors.Annotate(err, "cannot close ports"))
 	}
 	return nil
 }
@@ -1952,6 +1961,20 @@ func ec2ErrCode(err error) string {
 	return ec2err.Code
 }
 
+// maybeClassifyError wraps EC2 errors caused by the cloud credential
+// being rejected, or by conditions expected to pass such as request
+// throttling, so that workers can tell whether and how to retry the
+// failed operation. Other errors are returned unchanged.
+func maybeClassifyError(err error) error {
+	switch ec2ErrCode(err) {
+	case "AuthFailure", "UnauthorizedOperation", "OptInRequired", "Blocked":
+		return common.CredentialNotValidError(err)
+	case "RequestLimitExceeded", "Unavailable", "InternalError":
+		return common.TransientError(err)
+	}
+	return err
+}
+
 func (e *environ) AllocateContainerAddresses(hostInstanceID instance.Id, containerTag names.MachineTag, preparedInfo []network.InterfaceInfo) ([]network.InterfaceInfo, error) {
 	return nil, errors.NotSupportedf("container address allocation")
 }
@@ -707,6 +707,112 @@ func (t *localServerSuite) TestDestroyControllerDestroysHostedModelResources(c *
 	assertGroups("default")
 }
 
+func (t *localServerSuite) TestTaggedResources(c *gc.C) {
+	controllerEnv := t.prepareAndBootstrap(c)
+
+	// Create a hosted model environment with an instance and a volume.
+	hostedModelUUID := "7e386e08-cba7-44a4-a76e-7c1633584210"
+	t.srv.ec2srv.SetInitialInstanceState(ec2test.Running)
+	cfg, err := controllerEnv.Config().Apply(map[string]interface{}{
+		"uuid":          hostedModelUUID,
+		"firewall-mode": "global",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	env, err := environs.New(environs.OpenParams{
+		Cloud:  t.CloudSpec(),
+		Config: cfg,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	inst, _ := testing.AssertStartInstance(c, env, t.ControllerUUID, "0")
+	ebsProvider, err := env.StorageProvider(ec2.EBS_ProviderType)
+	c.Assert(err, jc.ErrorIsNil)
+	vs, err := ebsProvider.VolumeSource(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	volumeResults, err := vs.CreateVolumes([]storage.VolumeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		Size:     1024,
+		Provider: ec2.EBS_ProviderType,
+		ResourceTags: map[string]string{
+			tags.JujuController: t.ControllerUUID,
+			tags.JujuModel:      hostedModelUUID,
+		},
+		Attachment: &storage.VolumeAttachmentParams{
+			AttachmentParams: storage.AttachmentParams{
+				InstanceId: inst.Id(),
+			},
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeResults, gc.HasLen, 1)
+	c.Assert(volumeResults[0].Error, jc.ErrorIsNil)
+	modelGroups, err := ec2.AllModelGroups(env)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(modelGroups, gc.HasLen, 2)
+
+	destroyer := env.(environs.TaggedResourceDestroyer)
+	filter := map[string]string{tags.JujuModel: hostedModelUUID}
+	listResources := func() []string {
+		resources, err := destroyer.TaggedResources(filter)
+		c.Assert(err, jc.ErrorIsNil)
+		result := make([]string, len(resources))
+		for i, resource := range resources {
+			c.Check(resource.Tags[tags.JujuModel], gc.Equals, hostedModelUUID)
+			result[i] = resource.String()
+		}
+		return result
+	}
+	c.Assert(listResources(), jc.SameContents, []string{
+		environs.TaggedResource{Kind: environs.ResourceKindInstance, ID: string(inst.Id())}.String(),
+		environs.TaggedResource{Kind: environs.ResourceKindVolume, ID: volumeResults[0].Volume.VolumeId}.String(),
+		environs.TaggedResource{Kind: environs.ResourceKindSecurityGroup, ID: modelGroups[0]}.String(),
+		environs.TaggedResource{Kind: environs.ResourceKindSecurityGroup, ID: modelGroups[1]}.String(),
+	})
+
+	// The controller's resources are not reported for the hosted model.
+	controllerResources, err := destroyer.TaggedResources(map[string]string{
+		tags.JujuModel: controllerEnv.Config().UUID(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	for _, resource := range controllerResources {
+		c.Check(resource.ID, gc.Not(gc.Equals), string(inst.Id()))
+	}
+
+	var deletedGroups []string
+	t.BaseSuite.PatchValue(ec2.DeleteSecurityGroupInsistently, func(
+		_ ec2.SecurityGroupCleaner, group amzec2.SecurityGroup, _ clock.Clock,
+	) error {
+		deletedGroups = append(deletedGroups, group.Id)
+		return nil
+	})
+	err = destroyer.DestroyTaggedResource(environs.TaggedResource{
+		Kind: environs.ResourceKindInstance,
+		ID:   string(inst.Id()),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = destroyer.DestroyTaggedResource(environs.TaggedResource{
+		Kind: environs.ResourceKindVolume,
+		ID:   volumeResults[0].Volume.VolumeId,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(listResources(), jc.SameContents, []string{
+		environs.TaggedResource{Kind: environs.ResourceKindSecurityGroup, ID: modelGroups[0]}.String(),
+		environs.TaggedResource{Kind: environs.ResourceKindSecurityGroup, ID: modelGroups[1]}.String(),
+	})
+
+	err = destroyer.DestroyTaggedResource(environs.TaggedResource{
+		Kind: environs.ResourceKindSecurityGroup,
+		ID:   modelGroups[0],
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(deletedGroups, jc.DeepEquals, []string{modelGroups[0]})
+
+	err = destroyer.DestroyTaggedResource(environs.TaggedResource{
+		Kind: environs.ResourceKindLoadBalancer,
+		ID:   "lb-1",
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (t *localServerSuite) TestInstanceStatus(c *gc.C) {
 	env := t.Prepare(c)
 	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{
@@ -1115,6 +1221,45 @@ func (t *localServerSuite) testStartInstanceAvailZoneAllConstrained(c *gc.C, run
 	c.Assert(errors.Details(err), jc.Contains, runInstancesError.Message)
 }
 
+func (t *localServerSuite) TestStartInstanceCredentialNotValid(c *gc.C) {
+	err := t.testStartInstanceClassifiedError(c, &amzec2.Error{
+		Code:    "AuthFailure",
+		Message: "AWS was not able to validate the provided access credentials",
+	})
+	c.Assert(err, jc.Satisfies, environs.IsCredentialNotValid)
+	c.Assert(err, gc.Not(jc.Satisfies), environs.IsTransient)
+}
+
+func (t *localServerSuite) TestStartInstanceTransient(c *gc.C) {
+	err := t.testStartInstanceClassifiedError(c, &amzec2.Error{
+		Code:    "RequestLimitExceeded",
+		Message: "Request limit exceeded.",
+	})
+	c.Assert(err, jc.Satisfies, environs.IsTransient)
+	c.Assert(err, gc.Not(jc.Satisfies), environs.IsCredentialNotValid)
+}
+
+func (t *localServerSuite) testStartInstanceClassifiedError(c *gc.C, runInstancesError *amzec2.Error) error {
+	env := t.prepareAndBootstrap(c)
+
+	t.PatchValue(ec2.RunInstances, func(e *amzec2.EC2, ri *amzec2.RunInstances, c environs.StatusCallbackFunc) (*amzec2.RunInstancesResp, error) {
+		return nil, runInstancesError
+	})
+
+	params := environs.StartInstanceParams{
+		ControllerUUID:   t.ControllerUUID,
+		StatusCallback:   fakeCallback,
+		AvailabilityZone: "test-available",
+	}
+
+	_, err := testing.StartInstanceWithParams(env, "1", params)
+	// Credential and transient failures do not depend on the
+	// zone, so there is no point trying another one.
+	c.Assert(err, jc.Satisfies, environs.IsAvailabilityZoneIndependent)
+	c.Assert(err, gc.ErrorMatches, ".*cannot run instances: "+runInstancesError.Message+".*")
+	return err
+}
+
 // addTestingSubnets adds a testing default VPC with 3 subnets in the EC2 test
 // server: 2 of the subnets are in the "test-available" AZ, the remaining - in
 // "test-unavailable". Returns a slice with the IDs of the created subnets and
@@ -0,0 +1,112 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+)
+
+var _ environs.TaggedResourceDestroyer = (*environ)(nil)
+
+// TaggedResources is part of the environs.TaggedResourceLister interface.
+// It reports the instances, volumes and security groups carrying all of
+// the supplied tags. Root disks are not reported, as they are destroyed
+// along with their instances.
+func (e *environ) TaggedResources(filterTags map[string]string) ([]environs.TaggedResource, error) {
+	newFilter := func() *ec2.Filter {
+		filter := ec2.NewFilter()
+		keys := make([]string, 0, len(filterTags))
+		for key := range filterTags {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			filter.Add(fmt.Sprintf("tag:%s", key), filterTags[key])
+		}
+		return filter
+	}
+	var resources []environs.TaggedResource
+
+	instFilter := newFilter()
+	instFilter.Add("instance-state-name", aliveInstanceStates...)
+	instResp, err := e.ec2.Instances(nil, instFilter)
+	if err != nil {
+		return nil, errors.Annotate(err, "listing instances")
+	}
+	for _, r := range instResp.Reservations {
+		for _, inst := range r.Instances {
+			resources = append(resources, environs.TaggedResource{
+				Kind: environs.ResourceKindInstance,
+				ID:   inst.InstanceId,
+				Tags: ec2TagsMap(inst.Tags),
+			})
+		}
+	}
+
+	volResp, err := e.ec2.Volumes(nil, newFilter())
+	if err != nil {
+		return nil, errors.Annotate(err, "listing volumes")
+	}
+	for _, vol := range volResp.Volumes {
+		var isRootDisk bool
+		for _, att := range vol.Attachments {
+			if att.Device == rootDiskDeviceName {
+				isRootDisk = true
+				break
+			}
+		}
+		if isRootDisk {
+			continue
+		}
+		resources = append(resources, environs.TaggedResource{
+			Kind: environs.ResourceKindVolume,
+			ID:   vol.Id,
+			Tags: ec2TagsMap(vol.Tags),
+		})
+	}
+
+	groupResp, err := e.ec2.SecurityGroups(nil, newFilter())
+	if err != nil {
+		return nil, errors.Annotate(err, "listing security groups")
+	}
+	for _, group := range groupResp.Groups {
+		resources = append(resources, environs.TaggedResource{
+			Kind: environs.ResourceKindSecurityGroup,
+			ID:   group.Id,
+			Tags: ec2TagsMap(group.Tags),
+		})
+	}
+	return resources, nil
+}
+
+// DestroyTaggedResource is part of the environs.TaggedResourceDestroyer
+// interface.
+func (e *environ) DestroyTaggedResource(resource environs.TaggedResource) error {
+	switch resource.Kind {
+	case environs.ResourceKindInstance:
+		return errors.Trace(e.terminateInstances([]instance.Id{instance.Id(resource.ID)}))
+	case environs.ResourceKindVolume:
+		return errors.Trace(destroyVolume(e.ec2, resource.ID))
+	case environs.ResourceKindSecurityGroup:
+		group := ec2.SecurityGroup{Id: resource.ID}
+		return errors.Trace(deleteSecurityGroupInsistently(e.ec2, group, clock.WallClock))
+	}
+	return errors.NotSupportedf("destroying %s", resource.Kind)
+}
+
+func ec2TagsMap(ec2Tags []ec2.Tag) map[string]string {
+	result := make(map[string]string, len(ec2Tags))
+	for _, tag := range ec2Tags {
+		result[tag.Key] = tag.Value
+	}
+	return result
+}
@@ -3,7 +3,10 @@
 
 package gce
 
-import "github.com/juju/juju/environs"
+import (
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+)
 
 const (
 	providerType = "gce"
@@ -11,4 +14,9 @@ const (
 
 func init() {
 	environs.RegisterProvider(providerType, providerInstance)
+
+	// The provider cannot allocate addresses to containers.
+	config.RegisterValidator(providerType, config.ContainerNetworkingMethod,
+		config.ContainerNetworkingMethodValidator("local", "fan"),
+	)
 }
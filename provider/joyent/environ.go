@@ -113,6 +113,15 @@ func (env *joyentEnviron) Bootstrap(ctx environs.BootstrapContext, args environs
 	return common.Bootstrap(ctx, env, args)
 }
 
+// ResumeBootstrap is part of the environs.BootstrapResumer interface.
+func (env *joyentEnviron) ResumeBootstrap(
+	ctx environs.BootstrapContext,
+	args environs.BootstrapParams,
+	state environs.BootstrapResumeState,
+) (*environs.BootstrapResult, error) {
+	return common.ResumeBootstrap(ctx, env, args, state)
+}
+
 func (env *joyentEnviron) ControllerInstances(controllerUUID string) ([]instance.Id, error) {
 	instanceIds := []instance.Id{}
 
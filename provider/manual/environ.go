@@ -17,6 +17,8 @@ import (
 	"github.com/juju/utils"
 	"github.com/juju/utils/arch"
 	"github.com/juju/utils/featureflag"
+	jujuos "github.com/juju/utils/os"
+	jujuseries "github.com/juju/utils/series"
 	"github.com/juju/utils/ssh"
 	"github.com/juju/version"
 
@@ -113,12 +115,36 @@ func (e *manualEnviron) Bootstrap(ctx environs.BootstrapContext, args environs.B
 	if err != nil {
 		return nil, err
 	}
-	finalize := func(ctx environs.BootstrapContext, icfg *instancecfg.InstanceConfig, _ environs.BootstrapDialOpts) error {
+	var prerequisites []string
+	if hostOS, err := jujuseries.GetOSFromSeries(series); err == nil && hostOS == jujuos.Ubuntu {
+		prerequisites = bootstrapPrerequisites
+	}
+	probe, err := sshprovisioner.ProbeHost(e.host, prerequisites)
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to probe host")
+	}
+	if err := checkBootstrapHost(probe, series, args.ControllerConfig); err != nil {
+		return nil, errors.Annotatef(err, "cannot bootstrap on %s", e.host)
+	}
+	finalize := func(ctx environs.BootstrapContext, icfg *instancecfg.InstanceConfig, _ environs.BootstrapDialOpts) (err error) {
 		icfg.Bootstrap.BootstrapMachineInstanceId = BootstrapInstanceId
 		icfg.Bootstrap.BootstrapMachineHardwareCharacteristics = hw
 		if err := instancecfg.FinishInstanceConfig(icfg, e.Config()); err != nil {
 			return err
 		}
+		// Nothing has been changed on the host until now; from here
+		// on, a failure rolls back whatever was installed.
+		defer func() {
+			if err == nil || args.KeepBroken {
+				return
+			}
+			if rollbackErr := rollbackBootstrap(e.host, probe.MissingPackages); rollbackErr != nil {
+				logger.Errorf("cannot remove partial install from %s: %v", e.host, rollbackErr)
+			}
+		}()
+		if err := installPrerequisites(e.host, probe.MissingPackages); err != nil {
+			return err
+		}
 		return common.ConfigureMachine(ctx, ssh.DefaultClient, e.host, icfg, nil)
 	}
 
@@ -237,6 +263,21 @@ func (e *manualEnviron) Destroy() error {
 
 // DestroyController implements the Environ interface.
 func (e *manualEnviron) DestroyController(controllerUUID string) error {
+	script := uninstallScript("")
+	logger.Tracef("destroy controller script: %s", script)
+	stdout, stderr, err := runSSHCommand(
+		"ubuntu@"+e.host,
+		[]string{"sudo", "/bin/bash"}, script,
+	)
+	logger.Debugf("script stdout: \n%s", stdout)
+	logger.Debugf("script stderr: \n%s", stderr)
+	return err
+}
+
+// uninstallScript returns a script that stops the agents on a host and
+// removes everything Juju installed there. The extra commands are run
+// just before the script exits successfully.
+func uninstallScript(extra string) string {
 	script := `
 # Signal the jujud process to stop, then check it has done so before cleaning-up
 # after it.
@@ -279,7 +320,7 @@ apt-get -y autoremove
 rm -f /etc/init/juju*
 rm -f /etc/systemd/system{,/multi-user.target.wants}/juju*
 rm -fr %[4]s %[5]s
-exit 0
+%[6]sexit 0
 `
 	var diagnostics string
 	if featureflag.Enabled(feature.DeveloperMode) {
@@ -290,7 +331,7 @@ exit 0
     juju-goroutines
 `
 	}
-	script = fmt.Sprintf(
+	return fmt.Sprintf(
 		script,
 		// WARNING: this is linked with the use of uninstallFile in
 		// the agent package. Don't change it without extreme care,
@@ -303,15 +344,8 @@ exit 0
 		mongo.ServiceName,
 		utils.ShQuote(agent.DefaultPaths.DataDir),
 		utils.ShQuote(agent.DefaultPaths.LogDir),
+		extra,
 	)
-	logger.Tracef("destroy controller script: %s", script)
-	stdout, stderr, err := runSSHCommand(
-		"ubuntu@"+e.host,
-		[]string{"sudo", "/bin/bash"}, script,
-	)
-	logger.Debugf("script stdout: \n%s", stdout)
-	logger.Debugf("script stderr: \n%s", stderr)
-	return err
 }
 
 func (*manualEnviron) PrecheckInstance(environs.PrecheckInstanceParams) error {
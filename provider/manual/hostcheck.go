@@ -0,0 +1,109 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package manual
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/environs/manual/sshprovisioner"
+	"github.com/juju/juju/service"
+)
+
+// minBootstrapDiskMB is the free space, in megabytes, required under
+// /var/lib to host a controller's database and agent binaries.
+const minBootstrapDiskMB = 4 * 1024
+
+// bootstrapPrerequisites holds the packages that are installed on an
+// Ubuntu host before a controller is configured on it, so that a host
+// that cannot install packages fails before anything else changes.
+var bootstrapPrerequisites = []string{
+	"bridge-utils",
+	"cloud-utils",
+	"cpu-checker",
+	"curl",
+	"tmux",
+}
+
+// checkBootstrapHost reports every reason the probed host is not fit
+// to run a controller of the specified series and configuration.
+func checkBootstrapHost(probe sshprovisioner.HostProbe, series string, cfg controller.Config) error {
+	var problems []string
+	initSystem, err := service.VersionInitSystem(series)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if probe.InitSystem != initSystem {
+		problems = append(problems, fmt.Sprintf(
+			"%s requires %s, but the host is running %s",
+			series, initSystem, probe.InitSystem,
+		))
+	}
+	if !probe.CgroupsMounted {
+		problems = append(problems, "cgroups are not mounted")
+	}
+	if probe.DiskFreeMB < minBootstrapDiskMB {
+		problems = append(problems, fmt.Sprintf(
+			"%dMB free under /var/lib, %dMB required",
+			probe.DiskFreeMB, minBootstrapDiskMB,
+		))
+	}
+	inUse := set.NewInts(probe.PortsInUse...)
+	for _, port := range []int{cfg.APIPort(), cfg.StatePort()} {
+		if inUse.Contains(port) {
+			problems = append(problems, fmt.Sprintf("port %d is in use", port))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// installPrerequisites installs the specified packages on the host.
+func installPrerequisites(host string, packages []string) error {
+	if len(packages) == 0 {
+		return nil
+	}
+	logger.Infof("installing %s on %s", strings.Join(packages, ", "), host)
+	script := fmt.Sprintf(`
+set -e
+export DEBIAN_FRONTEND=noninteractive
+apt-get update
+apt-get -y install %s
+`[1:], quotePackages(packages))
+	_, _, err := runSSHCommand(
+		"ubuntu@"+host,
+		[]string{"sudo", "/bin/bash"}, script,
+	)
+	return errors.Annotate(err, "installing prerequisites")
+}
+
+// rollbackBootstrap removes what a failed bootstrap left on the host,
+// including the packages that were installed as prerequisites.
+func rollbackBootstrap(host string, installed []string) error {
+	logger.Infof("removing partial install from %s", host)
+	var extra string
+	if len(installed) > 0 {
+		extra = fmt.Sprintf("apt-get -y purge %s\n", quotePackages(installed))
+	}
+	_, _, err := runSSHCommand(
+		"ubuntu@"+host,
+		[]string{"sudo", "/bin/bash"}, uninstallScript(extra),
+	)
+	return err
+}
+
+func quotePackages(packages []string) string {
+	quoted := make([]string, len(packages))
+	for i, pkg := range packages {
+		quoted[i] = utils.ShQuote(pkg)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,115 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package manual
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/manual/sshprovisioner"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type hostCheckSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&hostCheckSuite{})
+
+func goodProbe() sshprovisioner.HostProbe {
+	return sshprovisioner.HostProbe{
+		InitSystem:     "systemd",
+		CgroupsMounted: true,
+		DiskFreeMB:     minBootstrapDiskMB,
+		PortsInUse:     []int{22},
+	}
+}
+
+func (s *hostCheckSuite) TestCheckBootstrapHost(c *gc.C) {
+	for i, test := range []struct {
+		about  string
+		series string
+		modify func(*sshprovisioner.HostProbe)
+		err    string
+	}{{
+		about:  "suitable",
+		series: "xenial",
+		modify: func(*sshprovisioner.HostProbe) {},
+	}, {
+		about:  "upstart on trusty",
+		series: "trusty",
+		modify: func(p *sshprovisioner.HostProbe) { p.InitSystem = "upstart" },
+	}, {
+		about:  "upstart on xenial",
+		series: "xenial",
+		modify: func(p *sshprovisioner.HostProbe) { p.InitSystem = "upstart" },
+		err:    "xenial requires systemd, but the host is running upstart",
+	}, {
+		about:  "no cgroups",
+		series: "xenial",
+		modify: func(p *sshprovisioner.HostProbe) { p.CgroupsMounted = false },
+		err:    "cgroups are not mounted",
+	}, {
+		about:  "several problems",
+		series: "xenial",
+		modify: func(p *sshprovisioner.HostProbe) {
+			p.DiskFreeMB = 1024
+			p.PortsInUse = append(p.PortsInUse, 1234, 17777)
+		},
+		err: "1024MB free under /var/lib, 4096MB required; port 17777 is in use; port 1234 is in use",
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		probe := goodProbe()
+		test.modify(&probe)
+		err := checkBootstrapHost(probe, test.series, coretesting.FakeControllerConfig())
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *hostCheckSuite) TestInstallPrerequisites(c *gc.C) {
+	var calls int
+	s.PatchValue(&runSSHCommand, func(host string, command []string, stdin string) (string, string, error) {
+		calls++
+		c.Check(host, gc.Equals, "ubuntu@hostname")
+		c.Check(command, gc.DeepEquals, []string{"sudo", "/bin/bash"})
+		c.Check(stdin, gc.Equals, `
+set -e
+export DEBIAN_FRONTEND=noninteractive
+apt-get update
+apt-get -y install 'curl' 'tmux'
+`[1:])
+		return "", "", errors.New("no network")
+	})
+	err := installPrerequisites("hostname", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(calls, gc.Equals, 0)
+
+	err = installPrerequisites("hostname", []string{"curl", "tmux"})
+	c.Assert(err, gc.ErrorMatches, "installing prerequisites: no network")
+	c.Assert(calls, gc.Equals, 1)
+}
+
+func (s *hostCheckSuite) TestRollbackBootstrap(c *gc.C) {
+	var script string
+	s.PatchValue(&runSSHCommand, func(host string, command []string, stdin string) (string, string, error) {
+		c.Check(host, gc.Equals, "ubuntu@hostname")
+		c.Check(command, gc.DeepEquals, []string{"sudo", "/bin/bash"})
+		script = stdin
+		return "", "", nil
+	})
+	err := rollbackBootstrap("hostname", []string{"curl", "tmux"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.HasSuffix(script, "\napt-get -y purge 'curl' 'tmux'\nexit 0\n"), jc.IsTrue)
+
+	err = rollbackBootstrap("hostname", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(script, gc.Equals, uninstallScript(""))
+}
@@ -5,6 +5,7 @@ package openstack
 
 import (
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/tools"
 )
 
@@ -15,6 +16,11 @@ const (
 func init() {
 	environs.RegisterProvider(providerType, providerInstance)
 
+	// The provider cannot allocate addresses to containers.
+	config.RegisterValidator(providerType, config.ContainerNetworkingMethod,
+		config.ContainerNetworkingMethodValidator("local", "fan"),
+	)
+
 	environs.RegisterImageDataSourceFunc("keystone catalog", getKeystoneImageSource)
 	tools.RegisterToolsDataSourceFunc("keystone catalog", getKeystoneToolsSource)
 }
@@ -668,6 +668,18 @@ func (e *Environ) Bootstrap(ctx environs.BootstrapContext, args environs.Bootstr
 	return common.Bootstrap(ctx, e, args)
 }
 
+// ResumeBootstrap is part of the environs.BootstrapResumer interface.
+func (e *Environ) ResumeBootstrap(
+	ctx environs.BootstrapContext,
+	args environs.BootstrapParams,
+	state environs.BootstrapResumeState,
+) (*environs.BootstrapResult, error) {
+	if err := authenticateClient(e.client()); err != nil {
+		return nil, err
+	}
+	return common.ResumeBootstrap(ctx, e, args, state)
+}
+
 func (e *Environ) supportsNeutron() bool {
 	client := e.client()
 	endpointMap := client.EndpointsForRegion(e.cloud.Region)
@@ -146,6 +146,15 @@ func (o *OracleEnviron) Bootstrap(ctx environs.BootstrapContext, args environs.B
 	return common.Bootstrap(ctx, o, args)
 }
 
+// ResumeBootstrap is part of the environs.BootstrapResumer interface.
+func (o *OracleEnviron) ResumeBootstrap(
+	ctx environs.BootstrapContext,
+	args environs.BootstrapParams,
+	state environs.BootstrapResumeState,
+) (*environs.BootstrapResult, error) {
+	return common.ResumeBootstrap(ctx, o, args, state)
+}
+
 // Create is part of the Environ interface.
 func (o *OracleEnviron) Create(params environs.CreateParams) error {
 	if err := o.client.Authenticate(); err != nil {
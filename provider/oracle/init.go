@@ -3,8 +3,16 @@
 
 package oracle
 
-import "github.com/juju/juju/environs"
+import (
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+)
 
 func init() {
 	environs.RegisterProvider(providerType, &EnvironProvider{})
+
+	// The provider cannot allocate addresses to containers.
+	config.RegisterValidator(providerType, config.ContainerNetworkingMethod,
+		config.ContainerNetworkingMethodValidator("local", "fan"),
+	)
 }
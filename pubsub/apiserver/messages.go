@@ -21,3 +21,84 @@ type Details struct {
 	Servers   map[string]APIServer `yaml:"servers"`
 	LocalOnly bool                 `yaml:"local-only"`
 }
+
+// DetailsRequestTopic is the topic name for the published message when
+// a worker wants the current details of the api servers. The
+// peergrouper answers it by publishing the details on DetailsTopic.
+const DetailsRequestTopic = "apiserver.details-request"
+
+// DetailsRequest is the message published on DetailsRequestTopic.
+type DetailsRequest struct {
+	// Requester identifies the worker asking for the details.
+	Requester string `yaml:"requester"`
+	LocalOnly bool   `yaml:"local-only"`
+}
+
+// SessionRevokeTopic is the topic name for the published message when
+// API sessions are revoked. Every API server ends the matching sessions
+// that it holds.
+const SessionRevokeTopic = "apiserver.session.revoke"
+
+// SessionRevocation identifies the API sessions to end.
+type SessionRevocation struct {
+	// SessionIDs holds the IDs of individual sessions to end.
+	SessionIDs []string `yaml:"session-ids,omitempty"`
+
+	// Users holds the names of users all of whose sessions are ended.
+	Users []string `yaml:"users,omitempty"`
+}
+
+// LoginLockoutClearTopic is the topic name for the published message
+// when login lockouts are cleared. Every API server clears the matching
+// lockouts that it holds.
+const LoginLockoutClearTopic = "apiserver.login-lockout.clear"
+
+// LoginLockoutClearance identifies the login lockouts to clear.
+type LoginLockoutClearance struct {
+	// Users holds the names of users whose lockouts are cleared, from
+	// any address.
+	Users []string `yaml:"users,omitempty"`
+
+	// Addresses holds the addresses from which lockouts of any user
+	// are cleared.
+	Addresses []string `yaml:"addresses,omitempty"`
+}
+
+// SessionListTopic is the topic name for the published message
+// requesting the API sessions of every API server. Each API server
+// replies on SessionListReplyTopic.
+const SessionListTopic = "apiserver.session.list"
+
+// SessionListRequest requests the API sessions of every API server.
+type SessionListRequest struct {
+	// RequestID identifies the request in the replies.
+	RequestID string `yaml:"request-id"`
+}
+
+// SessionListReplyTopic is the topic name for the published message
+// holding the API sessions of an API server.
+const SessionListReplyTopic = "apiserver.session.list.reply"
+
+// SessionListReply holds the API sessions of an API server.
+type SessionListReply struct {
+	// RequestID identifies the request replied to.
+	RequestID string `yaml:"request-id"`
+
+	// Server identifies the replying API server.
+	Server string `yaml:"server"`
+
+	// Sessions holds the sessions of the API server.
+	Sessions []SessionDetails `yaml:"sessions,omitempty"`
+}
+
+// SessionDetails describes an API session. Times are in Unix
+// nanoseconds.
+type SessionDetails struct {
+	ID            string         `yaml:"id"`
+	User          string         `yaml:"user"`
+	ModelUUID     string         `yaml:"model-uuid,omitempty"`
+	RemoteAddress string         `yaml:"remote-address"`
+	Started       int64          `yaml:"started"`
+	LastActive    int64          `yaml:"last-active"`
+	FacadeCalls   map[string]int `yaml:"facade-calls,omitempty"`
+}
@@ -18,10 +18,11 @@ func IsShutdownErr(err error) bool {
 // Call represents an active RPC.
 type Call struct {
 	Request
-	Params   interface{}
-	Response interface{}
-	Error    error
-	Done     chan *Call
+	Params        interface{}
+	Response      interface{}
+	Error         error
+	Done          chan *Call
+	CorrelationId string
 }
 
 // RequestError represents an error returned from an RPC request.
@@ -63,9 +64,10 @@ func (conn *Conn) send(call *Call) {
 
 	// Encode and send the request.
 	hdr := &Header{
-		RequestId: reqId,
-		Request:   call.Request,
-		Version:   1,
+		RequestId:     reqId,
+		Request:       call.Request,
+		Version:       1,
+		CorrelationId: call.CorrelationId,
 	}
 	params := call.Params
 	if params == nil {
@@ -148,3 +150,20 @@ func (conn *Conn) Call(req Request, params, response interface{}) error {
 	result := <-call.Done
 	return errors.Trace(result.Error)
 }
+
+// CallCorrelated is like Call, but sends the given correlation ID with
+// the request, so that the server can group the work it does for the
+// request with that done for others made on behalf of the same operator
+// action.
+func (conn *Conn) CallCorrelated(req Request, correlationId string, params, response interface{}) error {
+	call := &Call{
+		Request:       req,
+		Params:        params,
+		Response:      response,
+		Done:          make(chan *Call, 1),
+		CorrelationId: correlationId,
+	}
+	conn.send(call)
+	result := <-call.Done
+	return errors.Trace(result.Error)
+}
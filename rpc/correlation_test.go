@@ -0,0 +1,114 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rpc_test
+
+import (
+	"context"
+	"reflect"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/correlation"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/testing"
+)
+
+type correlationSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&correlationSuite{})
+
+// ContextRoot is a root whose object has methods that take a context.
+type ContextRoot struct{}
+
+func (*ContextRoot) ContextMethods(string) (*ContextMethods, error) {
+	return &ContextMethods{}, nil
+}
+
+// ContextMethods holds methods that take a context.
+type ContextMethods struct{}
+
+func (*ContextMethods) CorrelationId(ctx context.Context) stringVal {
+	return stringVal{correlation.ID(ctx)}
+}
+
+func (*ContextMethods) Echo(ctx context.Context, s stringVal) stringVal {
+	return s
+}
+
+func (*correlationSuite) TestObjTypeOfContextMethods(c *gc.C) {
+	objType := rpcreflect.ObjTypeOf(reflect.TypeOf(&ContextMethods{}))
+	c.Check(objType.DiscardedMethods(), gc.HasLen, 0)
+
+	m, err := objType.Method("CorrelationId")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(m.Params, gc.IsNil)
+	c.Check(m.Result, gc.Equals, reflect.TypeOf(stringVal{}))
+	ctx := correlation.WithID(context.Background(), "deadbeef")
+	ret, err := m.CallContext(ctx, reflect.ValueOf(&ContextMethods{}), reflect.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ret.Interface(), gc.Equals, stringVal{"deadbeef"})
+
+	// Call passes an empty context.
+	ret, err = m.Call(reflect.ValueOf(&ContextMethods{}), reflect.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ret.Interface(), gc.Equals, stringVal{""})
+
+	m, err = objType.Method("Echo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(m.Params, gc.Equals, reflect.TypeOf(stringVal{}))
+	c.Check(m.Result, gc.Equals, reflect.TypeOf(stringVal{}))
+}
+
+func (*correlationSuite) TestCallCorrelated(c *gc.C) {
+	client, srvDone, serverNotifier := newRPCClientServer(c, &ContextRoot{}, nil, false)
+	defer closeClient(c, client, srvDone)
+
+	var r stringVal
+	err := client.CallCorrelated(rpc.Request{"ContextMethods", 0, "", "CorrelationId"}, "deadbeef", nil, &r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.Val, gc.Equals, "deadbeef")
+
+	c.Assert(serverNotifier.serverRequests, gc.HasLen, 1)
+	c.Assert(serverNotifier.serverRequests[0].hdr.CorrelationId, gc.Equals, "deadbeef")
+	c.Assert(serverNotifier.serverReplies, gc.HasLen, 1)
+	c.Assert(serverNotifier.serverReplies[0].hdr.CorrelationId, gc.Equals, "deadbeef")
+
+	err = client.CallCorrelated(rpc.Request{"ContextMethods", 0, "", "Echo"}, "deadbeef", stringVal{"hello"}, &r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.Val, gc.Equals, "hello")
+}
+
+func (*correlationSuite) TestUncorrelatedCallGetsID(c *gc.C) {
+	client, srvDone, serverNotifier := newRPCClientServer(c, &ContextRoot{}, nil, false)
+	defer closeClient(c, client, srvDone)
+
+	var r stringVal
+	err := client.Call(rpc.Request{"ContextMethods", 0, "", "CorrelationId"}, nil, &r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(correlation.IsValidID(r.Val), jc.IsTrue)
+
+	// The ID the server made up is not returned to the client.
+	c.Assert(serverNotifier.serverReplies, gc.HasLen, 1)
+	c.Assert(serverNotifier.serverReplies[0].hdr.CorrelationId, gc.Equals, "")
+}
+
+func (*correlationSuite) TestInvalidCorrelationIdDropped(c *gc.C) {
+	client, srvDone, serverNotifier := newRPCClientServer(c, &ContextRoot{}, nil, false)
+	defer closeClient(c, client, srvDone)
+
+	var r stringVal
+	err := client.CallCorrelated(rpc.Request{"ContextMethods", 0, "", "CorrelationId"}, "not valid!", nil, &r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.Val, gc.Not(gc.Equals), "not valid!")
+	c.Assert(correlation.IsValidID(r.Val), jc.IsTrue)
+
+	c.Assert(serverNotifier.serverRequests, gc.HasLen, 1)
+	c.Assert(serverNotifier.serverRequests[0].hdr.CorrelationId, gc.Equals, "")
+	c.Assert(serverNotifier.serverReplies, gc.HasLen, 1)
+	c.Assert(serverNotifier.serverReplies[0].hdr.CorrelationId, gc.Equals, "")
+}
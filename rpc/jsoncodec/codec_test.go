@@ -115,6 +115,18 @@ func (*suite) TestRead(c *gc.C) {
 			Version: 1,
 		},
 		expectBody: &value{X: "param"},
+	}, {
+		msg: `{"request-id": 5, "type": "foo", "request": "frob", "correlation-id": "deadbeef"}`,
+		expectHdr: rpc.Header{
+			RequestId: 5,
+			Request: rpc.Request{
+				Type:   "foo",
+				Action: "frob",
+			},
+			Version:       1,
+			CorrelationId: "deadbeef",
+		},
+		expectBody: new(map[string]interface{}),
 	}} {
 		c.Logf("test %d", i)
 		codec := jsoncodec.New(&testConn{
@@ -353,6 +365,14 @@ func (*suite) TestDumpRequest(c *gc.C) {
 		},
 		body:   struct{ Arg string }{Arg: "an arg"},
 		expect: `{"request-id":1,"type":"Foo","version":2,"id":"id","request":"Something","params":{"Arg":"an arg"}}`,
+	}, {
+		hdr: rpc.Header{
+			RequestId:     6,
+			Version:       1,
+			CorrelationId: "deadbeef",
+		},
+		body:   struct{ Ret string }{Ret: "return value"},
+		expect: `{"request-id":6,"response":{"Ret":"return value"},"correlation-id":"deadbeef"}`,
 	}} {
 		c.Logf("test %d; %#v", i, test.hdr)
 		data := jsoncodec.DumpRequest(&test.hdr, test.body)
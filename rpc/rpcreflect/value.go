@@ -4,6 +4,7 @@
 package rpcreflect
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 )
@@ -118,11 +119,15 @@ func (v Value) Kill() {
 }
 
 func (caller methodCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+	return caller.CallContext(context.Background(), objId, arg)
+}
+
+func (caller methodCaller) CallContext(ctx context.Context, objId string, arg reflect.Value) (reflect.Value, error) {
 	obj, err := caller.rootMethod.Call(caller.rootValue, objId)
 	if err != nil {
 		return reflect.Value{}, err
 	}
-	return caller.objMethod.Call(obj, arg)
+	return caller.objMethod.CallContext(ctx, obj, arg)
 }
 
 func (caller methodCaller) ParamsType() reflect.Type {
@@ -144,3 +149,13 @@ type MethodCaller interface {
 	// call the method on that instance.
 	Call(objId string, arg reflect.Value) (reflect.Value, error)
 }
+
+// ContextMethodCaller is implemented by MethodCallers that can pass a
+// context to methods that take one as their first argument.
+type ContextMethodCaller interface {
+	MethodCaller
+
+	// CallContext is like Call, but passes the given context to
+	// the method.
+	CallContext(ctx context.Context, objId string, arg reflect.Value) (reflect.Value, error)
+}
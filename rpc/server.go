@@ -4,6 +4,7 @@
 package rpc
 
 import (
+	"context"
 	"io"
 	"reflect"
 	"runtime/debug"
@@ -13,6 +14,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 
+	"github.com/juju/juju/core/correlation"
 	"github.com/juju/juju/rpc/rpcreflect"
 )
 
@@ -66,6 +68,11 @@ type Header struct {
 
 	// Version defines the wire format of the request and response structure.
 	Version int
+
+	// CorrelationId identifies the operator action a request is
+	// made for, if the client supplied one. The server returns it
+	// in the reply.
+	CorrelationId string
 }
 
 // Request represents an RPC to be performed, absent its parameters.
@@ -389,6 +396,11 @@ func (conn *Conn) readBody(resp interface{}, isRequest bool) error {
 }
 
 func (conn *Conn) handleRequest(hdr *Header) error {
+	if hdr.CorrelationId != "" && !correlation.IsValidID(hdr.CorrelationId) {
+		// IDs supplied by clients end up in log lines and hook
+		// environments, so invalid ones are dropped.
+		hdr.CorrelationId = ""
+	}
 	observer := conn.observerFactory.RPCObserver()
 	req, err := conn.bindRequest(hdr)
 	if err != nil {
@@ -448,8 +460,9 @@ func (conn *Conn) writeErrorResponse(reqHdr *Header, err error, observer Observe
 	conn.sending.Lock()
 	defer conn.sending.Unlock()
 	hdr := &Header{
-		RequestId: reqHdr.RequestId,
-		Version:   reqHdr.Version,
+		RequestId:     reqHdr.RequestId,
+		Version:       reqHdr.Version,
+		CorrelationId: reqHdr.CorrelationId,
 	}
 	if err, ok := err.(ErrorCoder); ok {
 		hdr.ErrorCode = err.ErrorCode()
@@ -513,13 +526,28 @@ func (conn *Conn) runRequest(req boundRequest, arg reflect.Value, version int, o
 	}()
 	defer conn.srvPending.Done()
 
-	rv, err := req.Call(req.hdr.Request.Id, arg)
+	var rv reflect.Value
+	var err error
+	if caller, ok := req.MethodCaller.(rpcreflect.ContextMethodCaller); ok {
+		// Requests that the client did not correlate are given
+		// an ID of their own, so that the work done for them
+		// can still be grouped.
+		correlationId := req.hdr.CorrelationId
+		if correlationId == "" {
+			correlationId = correlation.NewID()
+		}
+		ctx := correlation.WithID(context.Background(), correlationId)
+		rv, err = caller.CallContext(ctx, req.hdr.Request.Id, arg)
+	} else {
+		rv, err = req.Call(req.hdr.Request.Id, arg)
+	}
 	if err != nil {
 		err = conn.writeErrorResponse(&req.hdr, req.transformErrors(err), observer)
 	} else {
 		hdr := &Header{
-			RequestId: req.hdr.RequestId,
-			Version:   version,
+			RequestId:     req.hdr.RequestId,
+			Version:       version,
+			CorrelationId: req.hdr.CorrelationId,
 		}
 		var rvi interface{}
 		if rv.IsValid() {
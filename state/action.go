@@ -104,6 +104,10 @@ type actionDoc struct {
 
 	// Results are the structured results from the action.
 	Results map[string]interface{} `bson:"results"`
+
+	// CorrelationId identifies the operator action that queued
+	// the action, if known.
+	CorrelationId string `bson:"correlation-id,omitempty"`
 }
 
 // action represents an instruction to do some "action" and is expected
@@ -142,6 +146,12 @@ func (a *action) Enqueued() time.Time {
 	return a.doc.Enqueued
 }
 
+// CorrelationID returns the ID correlating the action with the operator
+// action that queued it, if any.
+func (a *action) CorrelationID() string {
+	return a.doc.CorrelationId
+}
+
 // Started returns the time that the Action execution began.
 func (a *action) Started() time.Time {
 	return a.doc.Started
@@ -261,7 +271,7 @@ func newAction(st *State, adoc actionDoc) Action {
 }
 
 // newActionDoc builds the actionDoc with the given name and parameters.
-func newActionDoc(mb modelBackend, receiverTag names.Tag, actionName string, parameters map[string]interface{}) (actionDoc, actionNotificationDoc, error) {
+func newActionDoc(mb modelBackend, receiverTag names.Tag, correlationID, actionName string, parameters map[string]interface{}) (actionDoc, actionNotificationDoc, error) {
 	prefix := ensureActionMarker(receiverTag.Id())
 	actionId, err := NewUUID()
 	if err != nil {
@@ -277,6 +287,8 @@ func newActionDoc(mb modelBackend, receiverTag names.Tag, actionName string, par
 			Parameters: parameters,
 			Enqueued:   mb.nowToTheSecond(),
 			Status:     ActionPending,
+
+			CorrelationId: correlationID,
 		}, actionNotificationDoc{
 			DocId:     mb.docID(prefix + actionId.String()),
 			ModelUUID: modelUUID,
@@ -369,8 +381,15 @@ func (m *Model) FindActionsByName(name string) ([]Action, error) {
 	return results, errors.Trace(iter.Close())
 }
 
-// EnqueueAction
+// EnqueueAction queues an action with the given name and payload for
+// the receiver.
 func (m *Model) EnqueueAction(receiver names.Tag, actionName string, payload map[string]interface{}) (Action, error) {
+	return m.EnqueueCorrelatedAction(receiver, "", actionName, payload)
+}
+
+// EnqueueCorrelatedAction is like EnqueueAction, but records the ID
+// correlating the action with the operator action that queued it.
+func (m *Model) EnqueueCorrelatedAction(receiver names.Tag, correlationID, actionName string, payload map[string]interface{}) (Action, error) {
 	if len(actionName) == 0 {
 		return nil, errors.New("action name required")
 	}
@@ -380,7 +399,7 @@ func (m *Model) EnqueueAction(receiver names.Tag, actionName string, payload map
 		return nil, errors.Trace(err)
 	}
 
-	doc, ndoc, err := newActionDoc(m.st, receiver, actionName, payload)
+	doc, ndoc, err := newActionDoc(m.st, receiver, correlationID, actionName, payload)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -352,6 +352,20 @@ func (s *ActionSuite) TestAddActionAcceptsDuplicateNames(c *gc.C) {
 	c.Assert(actions[0].Id(), gc.Equals, a2.Id())
 }
 
+func (s *ActionSuite) TestAddCorrelatedAction(c *gc.C) {
+	action, err := s.unit.AddCorrelatedAction("deadbeef", "snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.CorrelationID(), gc.Equals, "deadbeef")
+
+	action, err = s.model.Action(action.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.CorrelationID(), gc.Equals, "deadbeef")
+
+	action, err = s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.CorrelationID(), gc.Equals, "")
+}
+
 func (s *ActionSuite) TestAddActionLifecycle(c *gc.C) {
 	unit, err := s.State.Unit(s.unit.Name())
 	c.Assert(err, jc.ErrorIsNil)
@@ -917,6 +931,9 @@ var _ state.ActionReceiver = (*mockAR)(nil)
 func (r mockAR) AddAction(name string, payload map[string]interface{}) (state.Action, error) {
 	return nil, nil
 }
+func (r mockAR) AddCorrelatedAction(correlationID, name string, payload map[string]interface{}) (state.Action, error) {
+	return nil, nil
+}
 func (r mockAR) CancelAction(state.Action) (state.Action, error) { return nil, nil }
 func (r mockAR) WatchActionNotifications() state.StringsWatcher  { return nil }
 func (r mockAR) Actions() ([]state.Action, error)                { return nil, nil }
@@ -292,29 +292,35 @@ func (st *State) addMachineOps(template MachineTemplate) (*machineDoc, []txn.Op,
 	prereqOps = append(prereqOps, assertModelActiveOp(st.ModelUUID()))
 	prereqOps = append(prereqOps, insertNewContainerRefOp(st, mdoc.Id))
 	if template.InstanceId != "" {
-		prereqOps = append(prereqOps, txn.Op{
-			C:      instanceDataC,
-			Id:     mdoc.DocID,
-			Assert: txn.DocMissing,
-			Insert: &instanceData{
-				DocID:      mdoc.DocID,
-				MachineId:  mdoc.Id,
-				InstanceId: template.InstanceId,
-				ModelUUID:  mdoc.ModelUUID,
-				Arch:       template.HardwareCharacteristics.Arch,
-				Mem:        template.HardwareCharacteristics.Mem,
-				RootDisk:   template.HardwareCharacteristics.RootDisk,
-				CpuCores:   template.HardwareCharacteristics.CpuCores,
-				CpuPower:   template.HardwareCharacteristics.CpuPower,
-				Tags:       template.HardwareCharacteristics.Tags,
-				AvailZone:  template.HardwareCharacteristics.AvailabilityZone,
-			},
-		})
+		prereqOps = append(prereqOps, insertInstanceDataOp(mdoc, template))
 	}
 
 	return mdoc, append(prereqOps, machineOp), nil
 }
 
+// insertInstanceDataOp returns the operation that records the instance,
+// already provisioned, of a new machine.
+func insertInstanceDataOp(mdoc *machineDoc, template MachineTemplate) txn.Op {
+	return txn.Op{
+		C:      instanceDataC,
+		Id:     mdoc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &instanceData{
+			DocID:      mdoc.DocID,
+			MachineId:  mdoc.Id,
+			InstanceId: template.InstanceId,
+			ModelUUID:  mdoc.ModelUUID,
+			Arch:       template.HardwareCharacteristics.Arch,
+			Mem:        template.HardwareCharacteristics.Mem,
+			RootDisk:   template.HardwareCharacteristics.RootDisk,
+			CpuCores:   template.HardwareCharacteristics.CpuCores,
+			CpuPower:   template.HardwareCharacteristics.CpuPower,
+			Tags:       template.HardwareCharacteristics.Tags,
+			AvailZone:  template.HardwareCharacteristics.AvailabilityZone,
+		},
+	}
+}
+
 // supportsContainerType reports whether the machine supports the given
 // container type. If the machine's supportedContainers attribute is
 // set, this decision can be made right here, otherwise we assume that
@@ -145,6 +145,39 @@ func (st *State) APIHostPorts() ([][]network.HostPort, error) {
 	return networkHostsPorts(doc.APIHostPorts), nil
 }
 
+// APIHostPortsForAgents returns the API addresses advertised to the
+// model's agents. They are the addresses set by SetAPIHostPorts, with
+// those excluded by the model's api-advertise-exclude-cidrs setting
+// removed, and those pinned by its api-advertise-addresses setting
+// added ahead of the others.
+func (st *State) APIHostPortsForAgents() ([][]network.HostPort, error) {
+	hostPorts, err := st.APIHostPorts()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	modelConfig, err := model.ModelConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	controllerConfig, err := st.ControllerConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	policy, err := network.NewAdvertisePolicy(
+		modelConfig.APIAdvertiseAddresses(),
+		modelConfig.APIAdvertiseExcludeCIDRs(),
+		controllerConfig.APIPort(),
+	)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot apply API address policy")
+	}
+	return policy.Apply(hostPorts), nil
+}
+
 // address represents the location of a machine, including metadata
 // about what kind of location the address describes.
 //
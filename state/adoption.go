@@ -0,0 +1,162 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strconv"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/instance"
+)
+
+// AdoptMachineArgs describes a running instance that was started for a
+// machine the model no longer records, such as after the loss of a
+// controller, and which is to be adopted back into the model.
+type AdoptMachineArgs struct {
+	// Id is the id of the machine the instance was started for, as
+	// recorded in its machine agent's configuration. The machine is
+	// recreated with this id so that the agent can reconnect.
+	Id string
+
+	// Series is the series of the instance's operating system.
+	Series string
+
+	// Jobs holds the jobs of the machine. If empty, the machine only
+	// hosts units.
+	Jobs []MachineJob
+
+	// InstanceId is the provider-specific id of the instance.
+	InstanceId instance.Id
+
+	// HardwareCharacteristics holds the characteristics of the
+	// instance, as far as they are known.
+	HardwareCharacteristics instance.HardwareCharacteristics
+
+	// Nonce is the provisioning nonce held by the machine agent.
+	Nonce string
+
+	// Password is the password held by the machine agent.
+	Password string
+}
+
+// VerifyAdoption checks that the instance described can be adopted
+// into the model, without changing anything.
+func (st *State) VerifyAdoption(args AdoptMachineArgs) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot adopt instance %q", args.InstanceId)
+	if _, err := st.adoptionTemplate(args); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := st.Machine(args.Id); err == nil {
+		return errors.AlreadyExistsf("machine %s", args.Id)
+	} else if !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	tracked, err := st.isInstanceTracked(args.InstanceId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if tracked {
+		return errors.AlreadyExistsf("machine with instance %q", args.InstanceId)
+	}
+	return nil
+}
+
+// AdoptMachine recreates the machine that the described instance was
+// started for, recording the instance as already provisioned and
+// accepting the credentials its machine agent holds, so that the agent
+// reconnects to the model. Callers should run VerifyAdoption first to
+// report problems to the operator before anything is changed.
+func (st *State) AdoptMachine(args AdoptMachineArgs) (_ *Machine, err error) {
+	if err := st.VerifyAdoption(args); err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer errors.DeferredAnnotatef(&err, "cannot adopt instance %q", args.InstanceId)
+	template, err := st.adoptionTemplate(args)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	template, err = st.effectiveMachineTemplate(template, false)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Machines added from now on must not reuse the adopted
+	// machine's id.
+	seq, err := strconv.Atoi(args.Id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err := sequenceWithMin(st, "machine", seq+1); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	mdoc := st.machineDocForTemplate(template, args.Id)
+	mdoc.PasswordHash = utils.AgentPasswordHash(args.Password)
+	prereqOps, machineOp, err := st.insertNewMachineOps(mdoc, template)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := append(prereqOps,
+		insertNewContainerRefOp(st, mdoc.Id),
+		insertInstanceDataOp(mdoc, template),
+		machineOp,
+	)
+	m, err := st.addMachine(mdoc, ops)
+	if errors.Cause(err) == txn.ErrAborted {
+		return nil, errors.Errorf("machine %s or instance %q added concurrently", args.Id, args.InstanceId)
+	}
+	return m, errors.Trace(err)
+}
+
+// adoptionTemplate validates the adoption arguments and returns the
+// template of the machine to recreate.
+func (st *State) adoptionTemplate(args AdoptMachineArgs) (MachineTemplate, error) {
+	if !names.IsValidMachine(args.Id) {
+		return MachineTemplate{}, errors.NotValidf("machine id %q", args.Id)
+	}
+	if names.IsContainerMachine(args.Id) {
+		return MachineTemplate{}, errors.NotSupportedf("adopting container %s", args.Id)
+	}
+	switch {
+	case args.InstanceId == "":
+		return MachineTemplate{}, errors.NotValidf("empty instance id")
+	case args.Series == "":
+		return MachineTemplate{}, errors.NotValidf("empty series")
+	case args.Nonce == "":
+		return MachineTemplate{}, errors.NotValidf("empty nonce")
+	case len(args.Password) < utils.MinAgentPasswordLength:
+		return MachineTemplate{}, errors.NotValidf("agent password of %d bytes", len(args.Password))
+	}
+	jobs := args.Jobs
+	if len(jobs) == 0 {
+		jobs = []MachineJob{JobHostUnits}
+	}
+	if hasJob(jobs, JobManageModel) {
+		return MachineTemplate{}, errors.NotSupportedf("adopting a controller machine")
+	}
+	return MachineTemplate{
+		Series:                  args.Series,
+		Jobs:                    jobs,
+		InstanceId:              args.InstanceId,
+		HardwareCharacteristics: args.HardwareCharacteristics,
+		Nonce:                   args.Nonce,
+	}, nil
+}
+
+// isInstanceTracked reports whether a machine in the model records the
+// instance with the supplied id.
+func (st *State) isInstanceTracked(id instance.Id) (bool, error) {
+	coll, closer := st.db().GetCollection(instanceDataC)
+	defer closer()
+	count, err := coll.Find(bson.D{{"instanceid", id}}).Count()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return count > 0, nil
+}
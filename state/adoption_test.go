@@ -0,0 +1,106 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+type AdoptionSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&AdoptionSuite{})
+
+func adoptArgs(id string, instId instance.Id) state.AdoptMachineArgs {
+	return state.AdoptMachineArgs{
+		Id:         id,
+		Series:     "quantal",
+		InstanceId: instId,
+		Nonce:      "agent-nonce",
+		Password:   "agent-password-0123456789",
+	}
+}
+
+func (s *AdoptionSuite) TestAdoptMachine(c *gc.C) {
+	m, err := s.State.AdoptMachine(adoptArgs("4", "inst-4"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Id(), gc.Equals, "4")
+	c.Assert(m.Series(), gc.Equals, "quantal")
+	c.Assert(m.Jobs(), jc.DeepEquals, []state.MachineJob{state.JobHostUnits})
+	c.Assert(m.CheckProvisioned("agent-nonce"), jc.IsTrue)
+	c.Assert(m.PasswordValid("agent-password-0123456789"), jc.IsTrue)
+	instId, err := m.InstanceId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instId, gc.Equals, instance.Id("inst-4"))
+
+	// New machines do not reuse the adopted machine's id.
+	next, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(next.Id(), gc.Equals, "5")
+}
+
+func (s *AdoptionSuite) TestVerifyAdoptionMachineExists(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.VerifyAdoption(adoptArgs(m.Id(), "inst-x"))
+	c.Assert(err, gc.ErrorMatches, `cannot adopt instance "inst-x": machine 0 already exists`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *AdoptionSuite) TestVerifyAdoptionInstanceTracked(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetProvisioned("inst-0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.VerifyAdoption(adoptArgs("3", "inst-0"))
+	c.Assert(err, gc.ErrorMatches, `cannot adopt instance "inst-0": machine with instance "inst-0" already exists`)
+}
+
+func (s *AdoptionSuite) TestVerifyAdoptionInvalid(c *gc.C) {
+	for i, test := range []struct {
+		about  string
+		modify func(*state.AdoptMachineArgs)
+		err    string
+	}{{
+		about:  "invalid machine id",
+		modify: func(a *state.AdoptMachineArgs) { a.Id = "foo" },
+		err:    `machine id "foo" not valid`,
+	}, {
+		about:  "container",
+		modify: func(a *state.AdoptMachineArgs) { a.Id = "0/lxd/0" },
+		err:    `adopting container 0/lxd/0 not supported`,
+	}, {
+		about:  "no series",
+		modify: func(a *state.AdoptMachineArgs) { a.Series = "" },
+		err:    `empty series not valid`,
+	}, {
+		about:  "no nonce",
+		modify: func(a *state.AdoptMachineArgs) { a.Nonce = "" },
+		err:    `empty nonce not valid`,
+	}, {
+		about:  "short password",
+		modify: func(a *state.AdoptMachineArgs) { a.Password = "short" },
+		err:    `agent password of 5 bytes not valid`,
+	}, {
+		about:  "controller",
+		modify: func(a *state.AdoptMachineArgs) { a.Jobs = []state.MachineJob{state.JobManageModel} },
+		err:    `adopting a controller machine not supported`,
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		args := adoptArgs("3", "inst-3")
+		test.modify(&args)
+		err := s.State.VerifyAdoption(args)
+		c.Check(err, gc.ErrorMatches, `cannot adopt instance ".*": `+test.err)
+		_, err = s.State.AdoptMachine(args)
+		c.Check(err, gc.ErrorMatches, `cannot adopt instance ".*": `+test.err)
+	}
+	_, err := s.State.Machine("3")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -0,0 +1,462 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/instance"
+)
+
+// AffinityPolicy describes where the units of an application are
+// placed relative to the units of other applications.
+type AffinityPolicy struct {
+	// NotWith holds the names of the applications whose units must
+	// never share a host machine with the application's units.
+	NotWith []string
+
+	// SameZoneAs holds the names of the applications whose
+	// availability zones the application's units are placed in, where
+	// possible.
+	SameZoneAs []string
+}
+
+// IsEmpty reports whether the policy places no restrictions on the
+// application's units.
+func (p AffinityPolicy) IsEmpty() bool {
+	return len(p.NotWith) == 0 && len(p.SameZoneAs) == 0
+}
+
+// affinityDoc records either an application's affinity policy, keyed
+// on the application's global key, or the ways in which a unit's
+// placement breaks affinity policy, keyed on the unit's global key.
+type affinityDoc struct {
+	DocID      string   `bson:"_id"`
+	ModelUUID  string   `bson:"model-uuid"`
+	NotWith    []string `bson:"not-with,omitempty"`
+	SameZoneAs []string `bson:"same-zone-as,omitempty"`
+	Violations []string `bson:"violations,omitempty"`
+}
+
+func readAffinityDoc(mb modelBackend, key string) (*affinityDoc, error) {
+	coll, closer := mb.db().GetCollection(affinityC)
+	defer closer()
+
+	var doc affinityDoc
+	err := coll.FindId(key).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("affinity policy for %q", key)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &doc, nil
+}
+
+// applicationAffinity returns the affinity policy of the named
+// application, which is empty if none has been set.
+func applicationAffinity(mb modelBackend, appName string) (AffinityPolicy, error) {
+	doc, err := readAffinityDoc(mb, applicationGlobalKey(appName))
+	if errors.IsNotFound(err) {
+		return AffinityPolicy{}, nil
+	} else if err != nil {
+		return AffinityPolicy{}, errors.Trace(err)
+	}
+	return AffinityPolicy{
+		NotWith:    doc.NotWith,
+		SameZoneAs: doc.SameZoneAs,
+	}, nil
+}
+
+// Affinity returns the application's affinity policy.
+func (a *Application) Affinity() (AffinityPolicy, error) {
+	return applicationAffinity(a.st, a.doc.Name)
+}
+
+// SetAffinity replaces the application's affinity policy. The policy
+// applies to units assigned to machines from then on; units already
+// assigned are not moved. Applications named in the policy need not
+// be deployed yet.
+func (a *Application) SetAffinity(policy AffinityPolicy) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set affinity for application %q", a)
+	for _, appNames := range [][]string{policy.NotWith, policy.SameZoneAs} {
+		if err := validateAffinityApplications(appNames); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if set.NewStrings(policy.SameZoneAs...).Contains(a.doc.Name) {
+		return errors.New("application cannot have zone affinity with itself")
+	}
+	key := a.globalKey()
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := a.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if a.doc.Life != Alive {
+			return nil, errors.New("application is not alive")
+		}
+		_, err := readAffinityDoc(a.st, key)
+		exists := err == nil
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:      applicationsC,
+			Id:     a.doc.DocID,
+			Assert: isAliveDoc,
+		}}
+		switch {
+		case policy.IsEmpty() && !exists:
+			return nil, jujutxn.ErrNoOperations
+		case policy.IsEmpty():
+			ops = append(ops, txn.Op{
+				C:      affinityC,
+				Id:     a.st.docID(key),
+				Assert: txn.DocExists,
+				Remove: true,
+			})
+		case exists:
+			ops = append(ops, txn.Op{
+				C:      affinityC,
+				Id:     a.st.docID(key),
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{
+					{"not-with", policy.NotWith},
+					{"same-zone-as", policy.SameZoneAs},
+				}}},
+			})
+		default:
+			ops = append(ops, txn.Op{
+				C:      affinityC,
+				Id:     a.st.docID(key),
+				Assert: txn.DocMissing,
+				Insert: &affinityDoc{
+					NotWith:    policy.NotWith,
+					SameZoneAs: policy.SameZoneAs,
+				},
+			})
+		}
+		return ops, nil
+	}
+	return a.st.db().Run(buildTxn)
+}
+
+func validateAffinityApplications(appNames []string) error {
+	for _, name := range appNames {
+		if !names.IsValidApplication(name) {
+			return errors.NotValidf("application name %q", name)
+		}
+	}
+	return nil
+}
+
+// removeAffinityOp returns the operation that removes the affinity
+// policy of the entity with the supplied global key, if it has one.
+func removeAffinityOp(mb modelBackend, globalKey string) txn.Op {
+	return txn.Op{
+		C:      affinityC,
+		Id:     mb.docID(globalKey),
+		Remove: true,
+	}
+}
+
+// ApplicationZones returns the sorted availability zones of the
+// provisioned machines hosting units of the named application.
+func (st *State) ApplicationZones(appName string) ([]string, error) {
+	if _, err := st.Application(appName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	machineIds, err := ApplicationMachines(st, appName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	zones := set.NewStrings()
+	for _, id := range machineIds {
+		zone, err := hostAvailabilityZone(st, id)
+		if errors.IsNotProvisioned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if zone != "" {
+			zones.Add(zone)
+		}
+	}
+	return zones.SortedValues(), nil
+}
+
+// hostAvailabilityZone returns the availability zone of the top level
+// host of the machine with the supplied id.
+func hostAvailabilityZone(st *State, machineId string) (string, error) {
+	instData, err := getInstanceData(st, TopParentId(machineId))
+	if errors.IsNotFound(err) {
+		return "", errors.NotProvisionedf("machine %v", machineId)
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	if instData.AvailZone == nil {
+		return "", nil
+	}
+	return *instData.AvailZone, nil
+}
+
+// preferredZones returns the availability zones that the unit's
+// application has affinity with, or nil if it has none or none of the
+// applications concerned have provisioned machines yet.
+func (u *Unit) preferredZones() (set.Strings, error) {
+	policy, err := applicationAffinity(u.st, u.doc.Application)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	zones := set.NewStrings()
+	for _, appName := range policy.SameZoneAs {
+		appZones, err := u.st.ApplicationZones(appName)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		zones = zones.Union(set.NewStrings(appZones...))
+	}
+	if zones.IsEmpty() {
+		return nil, nil
+	}
+	return zones, nil
+}
+
+// zoneAffinityPlacement returns a placement directive that starts a new
+// machine for the unit alongside the first of the applications its
+// application has zone affinity with that has provisioned machines, or
+// "" if there is none.
+func (u *Unit) zoneAffinityPlacement() (string, error) {
+	policy, err := applicationAffinity(u.st, u.doc.Application)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	for _, appName := range policy.SameZoneAs {
+		zones, err := u.st.ApplicationZones(appName)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return "", errors.Trace(err)
+		}
+		if len(zones) > 0 {
+			return instance.PlacementExpr{{
+				Key:  instance.PlacementMachineWith,
+				Args: []instance.PlacementTerm{{Key: "app", Value: appName}},
+			}}.String(), nil
+		}
+	}
+	return "", nil
+}
+
+// preferZones returns the machines with those in the supplied zones
+// first, keeping the order of the machines otherwise.
+func preferZones(st *State, machines []*Machine, zones set.Strings) ([]*Machine, error) {
+	if zones == nil {
+		return machines, nil
+	}
+	var preferred, others []*Machine
+	for _, m := range machines {
+		zone, err := hostAvailabilityZone(st, m.Id())
+		if err != nil && !errors.IsNotProvisioned(err) {
+			return nil, errors.Trace(err)
+		}
+		if zones.Contains(zone) {
+			preferred = append(preferred, m)
+		} else {
+			others = append(others, m)
+		}
+	}
+	return append(preferred, others...), nil
+}
+
+// affinityViolations returns descriptions of the ways in which placing
+// the unit on the machine breaks the affinity policy of the unit's
+// application, or the anti-affinity policies of the applications whose
+// units already share the machine's host.
+func (u *Unit) affinityViolations(m *Machine) ([]string, error) {
+	violations, err := u.hostConflicts(m)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	policy, err := applicationAffinity(u.st, u.doc.Application)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	zones, err := u.preferredZones()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if zones != nil {
+		zone, err := hostAvailabilityZone(u.st, m.Id())
+		if err != nil && !errors.IsNotProvisioned(err) {
+			return nil, errors.Trace(err)
+		}
+		if zone != "" && !zones.Contains(zone) {
+			violations = append(violations, fmt.Sprintf(
+				"zone %s is not a zone of %s", zone, strings.Join(policy.SameZoneAs, ", "),
+			))
+		}
+	}
+	return violations, nil
+}
+
+// hostConflicts returns descriptions of the units on the machine's host
+// that the unit must not share it with, under either the policy of the
+// unit's application or the policies of the units' applications.
+func (u *Unit) hostConflicts(m *Machine) ([]string, error) {
+	appName := u.doc.Application
+	policy, err := applicationAffinity(u.st, appName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	notWith := set.NewStrings(policy.NotWith...)
+
+	hostId := TopParentId(m.Id())
+	machines, closer := u.st.db().GetCollection(machinesC)
+	defer closer()
+	var mdocs []machineDoc
+	if err := machines.Find(bson.D{{"$or", []bson.D{
+		{{"machineid", hostId}},
+		{{"machineid", bson.D{{"$regex", "^" + hostId + "/"}}}},
+	}}}).Select(bson.D{{"principals", 1}}).All(&mdocs); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var conflicts []string
+	checked := make(map[string]bool)
+	for _, mdoc := range mdocs {
+		for _, unitName := range mdoc.Principals {
+			if unitName == u.doc.Name {
+				continue
+			}
+			otherApp := unitAppName(unitName)
+			if checked[otherApp] {
+				continue
+			}
+			checked[otherApp] = true
+			conflict := notWith.Contains(otherApp)
+			if !conflict {
+				otherPolicy, err := applicationAffinity(u.st, otherApp)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				conflict = set.NewStrings(otherPolicy.NotWith...).Contains(appName)
+			}
+			if conflict {
+				conflicts = append(conflicts, fmt.Sprintf(
+					"shares machine %s with %s", hostId, unitName,
+				))
+			}
+		}
+	}
+	return conflicts, nil
+}
+
+// withoutHostConflicts returns the machines the unit can be placed on
+// without sharing a host with units it must not be placed with.
+func (u *Unit) withoutHostConflicts(machines []*Machine) ([]*Machine, error) {
+	var result []*Machine
+	for _, m := range machines {
+		conflicts, err := u.hostConflicts(m)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(conflicts) == 0 {
+			result = append(result, m)
+		}
+	}
+	return result, nil
+}
+
+// recordAffinityViolations records the ways in which the unit's
+// placement breaks affinity policy, replacing any recorded before.
+// The record is kept apart from the unit's status, which the unit's
+// charm is free to change.
+func (u *Unit) recordAffinityViolations(violations []string) error {
+	key := u.globalKey()
+	buildTxn := func(int) ([]txn.Op, error) {
+		_, err := readAffinityDoc(u.st, key)
+		exists := err == nil
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		switch {
+		case len(violations) == 0 && !exists:
+			return nil, jujutxn.ErrNoOperations
+		case len(violations) == 0:
+			return []txn.Op{{
+				C:      affinityC,
+				Id:     u.st.docID(key),
+				Assert: txn.DocExists,
+				Remove: true,
+			}}, nil
+		}
+		ops := []txn.Op{{
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: notDeadDoc,
+		}}
+		if exists {
+			return append(ops, txn.Op{
+				C:      affinityC,
+				Id:     u.st.docID(key),
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{{"violations", violations}}}},
+			}), nil
+		}
+		return append(ops, txn.Op{
+			C:      affinityC,
+			Id:     u.st.docID(key),
+			Assert: txn.DocMissing,
+			Insert: &affinityDoc{Violations: violations},
+		}), nil
+	}
+	return errors.Trace(u.st.db().Run(buildTxn))
+}
+
+// AffinityViolations returns descriptions of the ways in which the
+// unit's placement breaks affinity policy, as found when the unit was
+// assigned to its machine.
+func (u *Unit) AffinityViolations() ([]string, error) {
+	doc, err := readAffinityDoc(u.st, u.globalKey())
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return doc.Violations, nil
+}
+
+// AffinityViolations returns the affinity violations of those of the
+// application's units whose placement breaks affinity policy, keyed
+// on unit name.
+func (a *Application) AffinityViolations() (map[string][]string, error) {
+	units, err := a.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make(map[string][]string)
+	for _, u := range units {
+		violations, err := u.AffinityViolations()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(violations) > 0 {
+			result[u.Name()] = violations
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,300 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/testing"
+)
+
+type AffinitySuite struct {
+	ConnSuite
+	mysql     *state.Application
+	wordpress *state.Application
+}
+
+var _ = gc.Suite(&AffinitySuite{})
+
+func (s *AffinitySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.mysql = s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	s.wordpress = s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+func (s *AffinitySuite) addMachineInZone(c *gc.C, zone string) *state.Machine {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetProvisioned(instance.Id("inst-"+m.Id()), "fake_nonce", &instance.HardwareCharacteristics{
+		AvailabilityZone: &zone,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return m
+}
+
+func (s *AffinitySuite) TestAffinityDefault(c *gc.C) {
+	policy, err := s.wordpress.Affinity()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy.IsEmpty(), jc.IsTrue)
+}
+
+func (s *AffinitySuite) TestSetAffinity(c *gc.C) {
+	err := s.wordpress.SetAffinity(state.AffinityPolicy{NotWith: []string{"mysql"}})
+	c.Assert(err, jc.ErrorIsNil)
+	policy, err := s.wordpress.Affinity()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, jc.DeepEquals, state.AffinityPolicy{NotWith: []string{"mysql"}})
+
+	err = s.wordpress.SetAffinity(state.AffinityPolicy{SameZoneAs: []string{"mysql"}})
+	c.Assert(err, jc.ErrorIsNil)
+	policy, err = s.wordpress.Affinity()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy.NotWith, gc.HasLen, 0)
+	c.Assert(policy.SameZoneAs, jc.DeepEquals, []string{"mysql"})
+
+	err = s.wordpress.SetAffinity(state.AffinityPolicy{})
+	c.Assert(err, jc.ErrorIsNil)
+	policy, err = s.wordpress.Affinity()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy.IsEmpty(), jc.IsTrue)
+}
+
+func (s *AffinitySuite) TestSetAffinityInvalid(c *gc.C) {
+	err := s.wordpress.SetAffinity(state.AffinityPolicy{NotWith: []string{"my_sql"}})
+	c.Assert(err, gc.ErrorMatches, `cannot set affinity for application "wordpress": application name "my_sql" not valid`)
+	err = s.wordpress.SetAffinity(state.AffinityPolicy{SameZoneAs: []string{"wordpress"}})
+	c.Assert(err, gc.ErrorMatches, `cannot set affinity for application "wordpress": application cannot have zone affinity with itself`)
+}
+
+func (s *AffinitySuite) TestSetAffinityNotAlive(c *gc.C) {
+	err := s.wordpress.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpress.SetAffinity(state.AffinityPolicy{NotWith: []string{"mysql"}})
+	c.Assert(err, gc.ErrorMatches, `cannot set affinity for application "wordpress": application "wordpress" not found`)
+}
+
+func (s *AffinitySuite) TestAssignToMachineReportsViolation(c *gc.C) {
+	err := s.wordpress.SetAffinity(state.AffinityPolicy{NotWith: []string{"mysql"}})
+	c.Assert(err, jc.ErrorIsNil)
+	m := s.addMachineInZone(c, "zone-a")
+	mysql0, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysql0.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+
+	wordpress0, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpress0.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+	violations, err := wordpress0.AffinityViolations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(violations, jc.DeepEquals, []string{"shares machine " + m.Id() + " with mysql/0"})
+
+	// The charm cannot hide the violation.
+	now := testing.ZeroTime()
+	err = wordpress0.SetStatus(status.StatusInfo{Status: status.Active, Since: &now})
+	c.Assert(err, jc.ErrorIsNil)
+	byUnit, err := s.wordpress.AffinityViolations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(byUnit, jc.DeepEquals, map[string][]string{"wordpress/0": violations})
+}
+
+func (s *AffinitySuite) TestAssignToMachineReportsReverseViolation(c *gc.C) {
+	// mysql's policy forbids wordpress from joining it as well.
+	err := s.mysql.SetAffinity(state.AffinityPolicy{NotWith: []string{"wordpress"}})
+	c.Assert(err, jc.ErrorIsNil)
+	m := s.addMachineInZone(c, "zone-a")
+	mysql0, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysql0.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+
+	wordpress0, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpress0.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+	violations, err := wordpress0.AffinityViolations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(violations, jc.DeepEquals, []string{"shares machine " + m.Id() + " with mysql/0"})
+}
+
+func (s *AffinitySuite) TestAssignToMachineWithinPolicy(c *gc.C) {
+	err := s.wordpress.SetAffinity(state.AffinityPolicy{
+		NotWith:    []string{"mysql"},
+		SameZoneAs: []string{"mysql"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	m0 := s.addMachineInZone(c, "zone-a")
+	mysql0, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysql0.AssignToMachine(m0)
+	c.Assert(err, jc.ErrorIsNil)
+
+	m1 := s.addMachineInZone(c, "zone-a")
+	wordpress0, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpress0.AssignToMachine(m1)
+	c.Assert(err, jc.ErrorIsNil)
+	violations, err := wordpress0.AffinityViolations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(violations, gc.HasLen, 0)
+}
+
+func (s *AffinitySuite) TestAssignToMachineReportsZoneViolation(c *gc.C) {
+	err := s.wordpress.SetAffinity(state.AffinityPolicy{SameZoneAs: []string{"mysql"}})
+	c.Assert(err, jc.ErrorIsNil)
+	mysql0, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysql0.AssignToMachine(s.addMachineInZone(c, "zone-a"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	wordpress0, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpress0.AssignToMachine(s.addMachineInZone(c, "zone-b"))
+	c.Assert(err, jc.ErrorIsNil)
+	violations, err := wordpress0.AffinityViolations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(violations, jc.DeepEquals, []string{"zone zone-b is not a zone of mysql"})
+}
+
+func (s *AffinitySuite) TestRemoveUnitRemovesViolations(c *gc.C) {
+	err := s.wordpress.SetAffinity(state.AffinityPolicy{NotWith: []string{"mysql"}})
+	c.Assert(err, jc.ErrorIsNil)
+	m := s.addMachineInZone(c, "zone-a")
+	mysql0, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysql0.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+	wordpress0, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpress0.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = wordpress0.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpress0.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	violations, err := wordpress0.AffinityViolations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(violations, gc.HasLen, 0)
+}
+
+func (s *AffinitySuite) TestAssignToNewMachinePlacedWithZoneAffinity(c *gc.C) {
+	err := s.wordpress.SetAffinity(state.AffinityPolicy{SameZoneAs: []string{"mysql"}})
+	c.Assert(err, jc.ErrorIsNil)
+	mysql0, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysql0.AssignToMachine(s.addMachineInZone(c, "zone-a"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	wordpress0, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpress0.AssignToNewMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := wordpress0.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := s.State.Machine(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Placement(), gc.Equals, "machine-with(app=mysql)")
+}
+
+func (s *AffinitySuite) TestAssignToNewMachineZoneAffinityUnknown(c *gc.C) {
+	// Until mysql has provisioned machines, there is no zone to
+	// prefer.
+	err := s.wordpress.SetAffinity(state.AffinityPolicy{SameZoneAs: []string{"mysql"}})
+	c.Assert(err, jc.ErrorIsNil)
+	wordpress0, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpress0.AssignToNewMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := wordpress0.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := s.State.Machine(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Placement(), gc.Equals, "")
+}
+
+func (s *AffinitySuite) TestAssignToPoolMachinePrefersZone(c *gc.C) {
+	err := s.wordpress.SetAffinity(state.AffinityPolicy{SameZoneAs: []string{"mysql"}})
+	c.Assert(err, jc.ErrorIsNil)
+	mysql0, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysql0.AssignToMachine(s.addMachineInZone(c, "zone-b"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	for _, zone := range []string{"zone-a", "zone-b"} {
+		err := s.addMachineInZone(c, zone).AddToPool()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	wordpress0, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := wordpress0.AssignToPoolMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	zone, err := m.AvailabilityZone()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zone, gc.Equals, "zone-b")
+}
+
+func (s *AffinitySuite) addMySQLContainer(c *gc.C, host *state.Machine) {
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, host.Id(), instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+	mysql0, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysql0.AssignToMachine(container)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AffinitySuite) TestAssignToPoolMachineAvoidsConflicts(c *gc.C) {
+	err := s.wordpress.SetAffinity(state.AffinityPolicy{NotWith: []string{"mysql"}})
+	c.Assert(err, jc.ErrorIsNil)
+	m0 := s.addMachineInZone(c, "zone-a")
+	err = m0.AddToPool()
+	c.Assert(err, jc.ErrorIsNil)
+	s.addMySQLContainer(c, m0)
+	m1 := s.addMachineInZone(c, "zone-a")
+	err = m1.AddToPool()
+	c.Assert(err, jc.ErrorIsNil)
+
+	wordpress0, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := wordpress0.AssignToPoolMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Id(), gc.Equals, m1.Id())
+}
+
+func (s *AffinitySuite) TestAssignToCleanMachineAvoidsConflicts(c *gc.C) {
+	err := s.wordpress.SetAffinity(state.AffinityPolicy{NotWith: []string{"mysql"}})
+	c.Assert(err, jc.ErrorIsNil)
+	s.addMySQLContainer(c, s.addMachineInZone(c, "zone-a"))
+	m1 := s.addMachineInZone(c, "zone-a")
+
+	wordpress0, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := wordpress0.AssignToCleanMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Id(), gc.Equals, m1.Id())
+}
+
+func (s *AffinitySuite) TestApplicationZones(c *gc.C) {
+	for _, zone := range []string{"zone-b", "zone-a", "zone-b"} {
+		u, err := s.mysql.AddUnit(state.AddUnitParams{})
+		c.Assert(err, jc.ErrorIsNil)
+		err = u.AssignToMachine(s.addMachineInZone(c, zone))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	zones, err := s.State.ApplicationZones("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, jc.DeepEquals, []string{"zone-a", "zone-b"})
+
+	zones, err = s.State.ApplicationZones("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, gc.HasLen, 0)
+}
@@ -0,0 +1,293 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"github.com/juju/version"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// AgentVersionPin restricts the agent versions that the agents of a
+// machine, or of the machines hosting an application's units, may be
+// upgraded to. A zero bound leaves that end of the range open.
+type AgentVersionPin struct {
+	// Min is the lowest version the agents may be upgraded to.
+	Min version.Number
+
+	// Max is the highest version the agents may be upgraded to.
+	Max version.Number
+}
+
+// IsEmpty reports whether the pin places no restriction on the
+// agent version.
+func (p AgentVersionPin) IsEmpty() bool {
+	return p.Min == version.Zero && p.Max == version.Zero
+}
+
+// Allows reports whether the pin allows agents to run the supplied
+// version.
+func (p AgentVersionPin) Allows(v version.Number) bool {
+	if p.Min != version.Zero && v.Compare(p.Min) < 0 {
+		return false
+	}
+	if p.Max != version.Zero && v.Compare(p.Max) > 0 {
+		return false
+	}
+	return true
+}
+
+// Validate returns an error if the pin's range is empty.
+func (p AgentVersionPin) Validate() error {
+	if p.Min != version.Zero && p.Max != version.Zero && p.Min.Compare(p.Max) > 0 {
+		return errors.NotValidf("agent version range %s", p)
+	}
+	return nil
+}
+
+// String returns the pin's range in the form "MIN..MAX", omitting
+// a bound that is not set.
+func (p AgentVersionPin) String() string {
+	var min, max string
+	if p.Min != version.Zero {
+		min = p.Min.String()
+	}
+	if p.Max != version.Zero {
+		max = p.Max.String()
+	}
+	return min + ".." + max
+}
+
+// ParseAgentVersionPin parses an agent version range in the form
+// returned by AgentVersionPin.String. A single version pins agents to
+// exactly that version.
+func ParseAgentVersionPin(s string) (AgentVersionPin, error) {
+	var pin AgentVersionPin
+	min, max := s, s
+	if i := strings.Index(s, ".."); i >= 0 {
+		min, max = s[:i], s[i+2:]
+	}
+	if min == "" && max == "" {
+		return pin, errors.NotValidf("agent version range %q", s)
+	}
+	var err error
+	if min != "" {
+		if pin.Min, err = version.Parse(min); err != nil {
+			return pin, errors.Annotatef(err, "invalid agent version range %q", s)
+		}
+	}
+	if max != "" {
+		if pin.Max, err = version.Parse(max); err != nil {
+			return pin, errors.Annotatef(err, "invalid agent version range %q", s)
+		}
+	}
+	if err := pin.Validate(); err != nil {
+		return AgentVersionPin{}, errors.Trace(err)
+	}
+	return pin, nil
+}
+
+// agentVersionPinDoc records the agent version pin of a machine or
+// application. It is keyed on the entity's global key.
+type agentVersionPinDoc struct {
+	DocID     string `bson:"_id"`
+	ModelUUID string `bson:"model-uuid"`
+	Entity    string `bson:"entity"`
+	Min       string `bson:"min,omitempty"`
+	Max       string `bson:"max,omitempty"`
+}
+
+func (doc agentVersionPinDoc) pin() (AgentVersionPin, error) {
+	var pin AgentVersionPin
+	var err error
+	if doc.Min != "" {
+		if pin.Min, err = version.Parse(doc.Min); err != nil {
+			return AgentVersionPin{}, errors.Trace(err)
+		}
+	}
+	if doc.Max != "" {
+		if pin.Max, err = version.Parse(doc.Max); err != nil {
+			return AgentVersionPin{}, errors.Trace(err)
+		}
+	}
+	return pin, nil
+}
+
+func versionString(v version.Number) string {
+	if v == version.Zero {
+		return ""
+	}
+	return v.String()
+}
+
+// agentVersionPin returns the agent version pin of the entity with
+// the supplied global key, which is empty if none has been set.
+func agentVersionPin(mb modelBackend, key string) (AgentVersionPin, error) {
+	coll, closer := mb.db().GetCollection(agentVersionPinsC)
+	defer closer()
+
+	var doc agentVersionPinDoc
+	err := coll.FindId(key).One(&doc)
+	if err == mgo.ErrNotFound {
+		return AgentVersionPin{}, nil
+	} else if err != nil {
+		return AgentVersionPin{}, errors.Trace(err)
+	}
+	return doc.pin()
+}
+
+// setAgentVersionPinOps returns the operations that replace the agent
+// version pin of the entity with the supplied global key and tag.
+func setAgentVersionPinOps(mb modelBackend, key, tag string, pin AgentVersionPin) ([]txn.Op, error) {
+	coll, closer := mb.db().GetCollection(agentVersionPinsC)
+	defer closer()
+	n, err := coll.FindId(key).Count()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	exists := n > 0
+	switch {
+	case pin.IsEmpty() && !exists:
+		return nil, jujutxn.ErrNoOperations
+	case pin.IsEmpty():
+		return []txn.Op{{
+			C:      agentVersionPinsC,
+			Id:     mb.docID(key),
+			Assert: txn.DocExists,
+			Remove: true,
+		}}, nil
+	case exists:
+		return []txn.Op{{
+			C:      agentVersionPinsC,
+			Id:     mb.docID(key),
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{
+				{"min", versionString(pin.Min)},
+				{"max", versionString(pin.Max)},
+			}}},
+		}}, nil
+	}
+	return []txn.Op{{
+		C:      agentVersionPinsC,
+		Id:     mb.docID(key),
+		Assert: txn.DocMissing,
+		Insert: &agentVersionPinDoc{
+			Entity: tag,
+			Min:    versionString(pin.Min),
+			Max:    versionString(pin.Max),
+		},
+	}}, nil
+}
+
+// removeAgentVersionPinOp returns the operation that removes the agent
+// version pin of the entity with the supplied global key, if it has one.
+func removeAgentVersionPinOp(mb modelBackend, globalKey string) txn.Op {
+	return txn.Op{
+		C:      agentVersionPinsC,
+		Id:     mb.docID(globalKey),
+		Remove: true,
+	}
+}
+
+// AgentVersionPin returns the machine's agent version pin.
+func (m *Machine) AgentVersionPin() (AgentVersionPin, error) {
+	return agentVersionPin(m.st, m.globalKey())
+}
+
+// SetAgentVersionPin replaces the machine's agent version pin. An
+// empty pin removes any restriction on the machine's agent version.
+func (m *Machine) SetAgentVersionPin(pin AgentVersionPin) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot pin agent version of machine %s", m)
+	if err := pin.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if m.doc.Life == Dead {
+			return nil, ErrDead
+		}
+		ops, err := setAgentVersionPinOps(m.st, m.globalKey(), m.Tag().String(), pin)
+		if err != nil {
+			return nil, err
+		}
+		return append([]txn.Op{{
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: notDeadDoc,
+		}}, ops...), nil
+	}
+	return m.st.db().Run(buildTxn)
+}
+
+// AgentVersionPin returns the application's agent version pin, which
+// applies to the machines hosting the application's units.
+func (a *Application) AgentVersionPin() (AgentVersionPin, error) {
+	return agentVersionPin(a.st, a.globalKey())
+}
+
+// SetAgentVersionPin replaces the application's agent version pin. An
+// empty pin removes any restriction on the agent version of the
+// machines hosting the application's units.
+func (a *Application) SetAgentVersionPin(pin AgentVersionPin) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot pin agent version of application %q", a)
+	if err := pin.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := a.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if a.doc.Life != Alive {
+			return nil, errors.New("application is not alive")
+		}
+		ops, err := setAgentVersionPinOps(a.st, a.globalKey(), a.Tag().String(), pin)
+		if err != nil {
+			return nil, err
+		}
+		return append([]txn.Op{{
+			C:      applicationsC,
+			Id:     a.doc.DocID,
+			Assert: isAliveDoc,
+		}}, ops...), nil
+	}
+	return a.st.db().Run(buildTxn)
+}
+
+// AgentVersionPins returns the agent version pins of all the machines
+// and applications in the model, keyed on the entity's tag string.
+func (st *State) AgentVersionPins() (map[string]AgentVersionPin, error) {
+	coll, closer := st.db().GetCollection(agentVersionPinsC)
+	defer closer()
+
+	var docs []agentVersionPinDoc
+	if err := coll.Find(nil).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get agent version pins")
+	}
+	pins := make(map[string]AgentVersionPin, len(docs))
+	for _, doc := range docs {
+		pin, err := doc.pin()
+		if err != nil {
+			return nil, errors.Annotatef(err, "agent version pin of %q", doc.Entity)
+		}
+		pins[doc.Entity] = pin
+	}
+	return pins, nil
+}
+
+// WatchAgentVersionPins returns a NotifyWatcher that notifies of
+// changes to the agent version pins in the model.
+func (st *State) WatchAgentVersionPins() NotifyWatcher {
+	return newNotifyCollWatcher(st, agentVersionPinsC, isLocalID(st))
+}
@@ -0,0 +1,180 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type AgentVersionPinSuite struct {
+	ConnSuite
+	machine *state.Machine
+	mysql   *state.Application
+}
+
+var _ = gc.Suite(&AgentVersionPinSuite{})
+
+func (s *AgentVersionPinSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	var err error
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.mysql = s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+}
+
+func (s *AgentVersionPinSuite) TestParseAgentVersionPin(c *gc.C) {
+	for i, test := range []struct {
+		in  string
+		pin state.AgentVersionPin
+		str string
+		err string
+	}{{
+		in:  "2.3.1..2.3.4",
+		pin: state.AgentVersionPin{Min: version.MustParse("2.3.1"), Max: version.MustParse("2.3.4")},
+	}, {
+		in:  "2.3.1..",
+		pin: state.AgentVersionPin{Min: version.MustParse("2.3.1")},
+	}, {
+		in:  "..2.3.4",
+		pin: state.AgentVersionPin{Max: version.MustParse("2.3.4")},
+	}, {
+		in:  "2.3.2",
+		pin: state.AgentVersionPin{Min: version.MustParse("2.3.2"), Max: version.MustParse("2.3.2")},
+		str: "2.3.2..2.3.2",
+	}, {
+		in:  "..",
+		err: `agent version range ".." not valid`,
+	}, {
+		in:  "2.4.0..2.3.0",
+		err: `agent version range 2.4.0..2.3.0 not valid`,
+	}, {
+		in:  "2.x..",
+		err: `invalid agent version range "2.x..": .*`,
+	}} {
+		c.Logf("test %d: %s", i, test.in)
+		pin, err := state.ParseAgentVersionPin(test.in)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(pin, jc.DeepEquals, test.pin)
+		str := test.str
+		if str == "" {
+			str = test.in
+		}
+		c.Check(pin.String(), gc.Equals, str)
+	}
+}
+
+func (s *AgentVersionPinSuite) TestAllows(c *gc.C) {
+	pin := state.AgentVersionPin{Min: version.MustParse("2.3.1"), Max: version.MustParse("2.3.4")}
+	c.Check(pin.Allows(version.MustParse("2.3.0")), jc.IsFalse)
+	c.Check(pin.Allows(version.MustParse("2.3.1")), jc.IsTrue)
+	c.Check(pin.Allows(version.MustParse("2.3.4")), jc.IsTrue)
+	c.Check(pin.Allows(version.MustParse("2.3.5")), jc.IsFalse)
+	c.Check(state.AgentVersionPin{}.Allows(version.MustParse("9.9.9")), jc.IsTrue)
+}
+
+func (s *AgentVersionPinSuite) TestMachineAgentVersionPin(c *gc.C) {
+	pin, err := s.machine.AgentVersionPin()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pin.IsEmpty(), jc.IsTrue)
+
+	want := state.AgentVersionPin{Max: version.MustParse("2.3.4")}
+	err = s.machine.SetAgentVersionPin(want)
+	c.Assert(err, jc.ErrorIsNil)
+	pin, err = s.machine.AgentVersionPin()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pin, jc.DeepEquals, want)
+
+	want = state.AgentVersionPin{Min: version.MustParse("2.3.0")}
+	err = s.machine.SetAgentVersionPin(want)
+	c.Assert(err, jc.ErrorIsNil)
+	pin, err = s.machine.AgentVersionPin()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pin, jc.DeepEquals, want)
+
+	err = s.machine.SetAgentVersionPin(state.AgentVersionPin{})
+	c.Assert(err, jc.ErrorIsNil)
+	pin, err = s.machine.AgentVersionPin()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pin.IsEmpty(), jc.IsTrue)
+
+	// Removing a pin that is not set is not an error.
+	err = s.machine.SetAgentVersionPin(state.AgentVersionPin{})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AgentVersionPinSuite) TestSetAgentVersionPinInvalid(c *gc.C) {
+	err := s.machine.SetAgentVersionPin(state.AgentVersionPin{
+		Min: version.MustParse("2.4.0"),
+		Max: version.MustParse("2.3.0"),
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot pin agent version of machine 0: agent version range 2.4.0..2.3.0 not valid`)
+}
+
+func (s *AgentVersionPinSuite) TestApplicationAgentVersionPin(c *gc.C) {
+	want := state.AgentVersionPin{Max: version.MustParse("2.3.4")}
+	err := s.mysql.SetAgentVersionPin(want)
+	c.Assert(err, jc.ErrorIsNil)
+	pin, err := s.mysql.AgentVersionPin()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pin, jc.DeepEquals, want)
+
+	err = s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	pins, err := s.State.AgentVersionPins()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pins, gc.HasLen, 0)
+}
+
+func (s *AgentVersionPinSuite) TestAgentVersionPins(c *gc.C) {
+	machinePin := state.AgentVersionPin{Max: version.MustParse("2.3.4")}
+	err := s.machine.SetAgentVersionPin(machinePin)
+	c.Assert(err, jc.ErrorIsNil)
+	appPin := state.AgentVersionPin{Min: version.MustParse("2.3.1")}
+	err = s.mysql.SetAgentVersionPin(appPin)
+	c.Assert(err, jc.ErrorIsNil)
+
+	pins, err := s.State.AgentVersionPins()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pins, jc.DeepEquals, map[string]state.AgentVersionPin{
+		"machine-0":         machinePin,
+		"application-mysql": appPin,
+	})
+}
+
+func (s *AgentVersionPinSuite) TestMachineRemovalRemovesPin(c *gc.C) {
+	err := s.machine.SetAgentVersionPin(state.AgentVersionPin{Max: version.MustParse("2.3.4")})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	pins, err := s.State.AgentVersionPins()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pins, gc.HasLen, 0)
+}
+
+func (s *AgentVersionPinSuite) TestWatchAgentVersionPins(c *gc.C) {
+	w := s.State.WatchAgentVersionPins()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err := s.mysql.SetAgentVersionPin(state.AgentVersionPin{Max: version.MustParse("2.3.4")})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.mysql.SetAgentVersionPin(state.AgentVersionPin{})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
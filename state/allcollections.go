@@ -136,6 +136,12 @@ func allCollections() collectionSchema {
 		// migration minions.
 		migrationsMinionSyncC: {global: true},
 
+		// This collection records the fingerprint of each entity in
+		// the controller's inventory, and the inventory generation at
+		// which it last changed, so that the inventory can be exported
+		// incrementally.
+		inventoryC: {global: true},
+
 		// This collection holds user information that's not specific to any
 		// one model.
 		usersC: {
@@ -153,6 +159,13 @@ func allCollections() collectionSchema {
 			rawAccess: true,
 		},
 
+		// This collection holds when the API sessions of each user
+		// were last revoked.
+		sessionRevocationsC: {
+			global:    true,
+			rawAccess: true,
+		},
+
 		// This collection is used as a unique key restraint. The _id field is
 		// a concatenation of multiple fields that form a compound index,
 		// allowing us to ensure users cannot have the same name for two
@@ -174,6 +187,18 @@ func allCollections() collectionSchema {
 		// are inherited and then forked by new models.
 		globalSettingsC: {global: true},
 
+		// This collection holds named sets of model config values
+		// that may be applied to models as they are added.
+		configProfilesC: {global: true},
+
+		// This collection holds teams: named groups of users that
+		// may own models.
+		teamsC: {global: true},
+
+		// This collection holds the offers that other controllers
+		// have registered with this controller's offer catalog.
+		offerCatalogC: {global: true},
+
 		// This collection holds workload metrics reported by certain charms
 		// for passing onward to other tools.
 		metricsC: {global: true},
@@ -224,6 +249,10 @@ func allCollections() collectionSchema {
 			}},
 		},
 
+		// This collection records the transfers of a model's
+		// ownership, in the transactions that make them.
+		modelOwnershipTransfersC: {},
+
 		// This collection contains governors that prevent certain kinds of
 		// changes from being accepted.
 		blocksC: {},
@@ -268,11 +297,23 @@ func allCollections() collectionSchema {
 		},
 		minUnitsC: {},
 
+		// This collection holds the affinity policies that govern
+		// which machines units of an application are assigned to.
+		affinityC: {},
+
 		// This collection holds documents that indicate units which are queued
 		// to be assigned to machines. It is used exclusively by the
 		// AssignUnitWorker.
 		assignUnitC: {},
 
+		// This collection records the progress of units being moved
+		// to other machines, and the outcome of finished moves.
+		unitMovesC: {},
+
+		// This collection records machines being evacuated, so that
+		// they can be removed once their units have gone.
+		machineEvacuationsC: {},
+
 		// meterStatusC is the collection used to store meter status information.
 		meterStatusC: {},
 		refcountsC:   {},
@@ -302,10 +343,28 @@ func allCollections() collectionSchema {
 		rebootC:      {},
 		sshHostKeysC: {},
 
+		// This collection holds the key with which the controller's
+		// ssh bastion connects to the model's machines.
+		bastionKeysC: {},
+
+		// This collection holds the machines that operators have
+		// registered in the model's machine pool; units are assigned
+		// to available pool machines before new machines are added.
+		machinePoolC: {},
+
 		// This collection contains information from removed machines
 		// that needs to be cleaned up in the provider.
 		machineRemovalsC: {},
 
+		// This collection holds the agent version ranges that the
+		// agents of machines, or of machines hosting an application's
+		// units, are pinned to.
+		agentVersionPinsC: {},
+
+		// This collection holds the package updates pending on
+		// machines, as last reported by their agents.
+		machinePackageUpdatesC: {},
+
 		// -----
 
 		// These collections hold information associated with storage.
@@ -401,6 +460,10 @@ func allCollections() collectionSchema {
 		// unit relation settings, model config, etc etc etc.
 		settingsC: {},
 
+		// This collection records the config profile applied to a
+		// model when it was added, and the values the profile set.
+		modelConfigProfileC: {},
+
 		constraintsC:        {},
 		storageConstraintsC: {},
 		statusesC: {
@@ -408,6 +471,25 @@ func allCollections() collectionSchema {
 				Key: []string{"model-uuid", "_id"},
 			}},
 		},
+		// This collection holds the history of changes to model
+		// config. It is pruned by age as changes are recorded.
+		modelConfigHistoryC: {
+			rawAccess: true,
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "key", "-time"},
+			}, {
+				Key: []string{"model-uuid", "-time"},
+			}},
+		},
+		// This collection holds the spans of slow operations
+		// recorded by agents. It is pruned by age as spans are
+		// recorded.
+		slowSpansC: {
+			rawAccess: true,
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "start"},
+			}},
+		},
 		statusesHistoryC: {
 			rawAccess: true,
 			indexes: []mgo.Index{{
@@ -457,6 +539,10 @@ func allCollections() collectionSchema {
 		// firewallRulesC holds firewall rules for defined service types.
 		firewallRulesC: {},
 
+		// faultsC holds the faults injected into a model for
+		// resilience testing.
+		faultsC: {},
+
 		// ----------------------
 
 		// Raw-access collections
@@ -480,11 +566,14 @@ const (
 	actionNotificationsC     = "actionnotifications"
 	actionresultsC           = "actionresults"
 	actionsC                 = "actions"
+	affinityC                = "affinity"
+	agentVersionPinsC        = "agentversionpins"
 	annotationsC             = "annotations"
 	autocertCacheC           = "autocertCache"
 	assignUnitC              = "assignUnits"
 	auditingC                = "audit.log"
 	bakeryStorageItemsC      = "bakeryStorageItems"
+	bastionKeysC             = "bastionkeys"
 	blockDevicesC            = "blockdevices"
 	blocksC                  = "blocks"
 	charmsC                  = "charms"
@@ -492,6 +581,7 @@ const (
 	cloudimagemetadataC      = "cloudimagemetadata"
 	cloudsC                  = "clouds"
 	cloudCredentialsC        = "cloudCredentials"
+	configProfilesC          = "configprofiles"
 	constraintsC             = "constraints"
 	containerRefsC           = "containerRefs"
 	controllersC             = "controllers"
@@ -503,8 +593,12 @@ const (
 	guimetadataC             = "guimetadata"
 	guisettingsC             = "guisettings"
 	instanceDataC            = "instanceData"
+	inventoryC               = "inventory"
 	leasesC                  = "leases"
 	machinesC                = "machines"
+	machineEvacuationsC      = "machineevacuations"
+	machinePackageUpdatesC   = "machinepackageupdates"
+	machinePoolC             = "machinepool"
 	machineRemovalsC         = "machineremovals"
 	meterStatusC             = "meterStatus"
 	metricsC                 = "metrics"
@@ -514,10 +608,14 @@ const (
 	migrationsC              = "migrations"
 	migrationsMinionSyncC    = "migrations.minionsync"
 	migrationsStatusC        = "migrations.status"
+	modelConfigHistoryC      = "modelconfighistory"
+	modelConfigProfileC      = "modelconfigprofile"
+	modelOwnershipTransfersC = "modelownershiptransfers"
 	modelUserLastConnectionC = "modelUserLastConnection"
 	modelUsersC              = "modelusers"
 	modelsC                  = "models"
 	modelEntityRefsC         = "modelEntityRefs"
+	offerCatalogC            = "offercatalog"
 	openedPortsC             = "openedPorts"
 	payloadsC                = "payloads"
 	permissionsC             = "permissions"
@@ -527,11 +625,13 @@ const (
 	relationsC               = "relations"
 	restoreInfoC             = "restoreInfo"
 	sequenceC                = "sequence"
+	sessionRevocationsC      = "sessionrevocations"
 	applicationsC            = "applications"
 	endpointBindingsC        = "endpointbindings"
 	settingsC                = "settings"
 	refcountsC               = "refcounts"
 	sshHostKeysC             = "sshhostkeys"
+	slowSpansC               = "slowspans"
 	spacesC                  = "spaces"
 	statusesC                = "statuses"
 	statusesHistoryC         = "statuseshistory"
@@ -539,6 +639,7 @@ const (
 	storageConstraintsC      = "storageconstraints"
 	storageInstancesC        = "storageinstances"
 	subnetsC                 = "subnets"
+	teamsC                   = "teams"
 	linkLayerDevicesC        = "linklayerdevices"
 	linkLayerDevicesRefsC    = "linklayerdevicesrefs"
 	ipAddressesC             = "ip.addresses"
@@ -546,6 +647,7 @@ const (
 	txnLogC                  = "txns.log"
 	txnsC                    = "txns"
 	unitsC                   = "units"
+	unitMovesC               = "unitmoves"
 	upgradeInfoC             = "upgradeInfo"
 	userLastLoginC           = "userLastLogin"
 	usermodelnameC           = "usermodelname"
@@ -563,3 +665,8 @@ const (
 	relationNetworksC    = "relationNetworks"
 	firewallRulesC       = "firewallRules"
 )
+
+const (
+	// Resilience testing
+	faultsC = "faults"
+)
@@ -1298,7 +1298,7 @@ func (b *allWatcherStateBacking) Release() error {
 }
 
 func NewAllModelWatcherStateBacking(st *State, pool *StatePool) Backing {
-	collections := makeAllWatcherCollectionInfo(
+	return newAllModelWatcherStateBacking(st, pool,
 		modelsC,
 		machinesC,
 		unitsC,
@@ -1311,11 +1311,20 @@ func NewAllModelWatcherStateBacking(st *State, pool *StatePool) Backing {
 		openedPortsC,
 		remoteApplicationsC,
 	)
+}
+
+// newModelAnnotationsWatcherStateBacking returns a backing that
+// reports only the models of the controller and their annotations.
+func newModelAnnotationsWatcherStateBacking(st *State, pool *StatePool) Backing {
+	return newAllModelWatcherStateBacking(st, pool, modelsC, annotationsC)
+}
+
+func newAllModelWatcherStateBacking(st *State, pool *StatePool, collNames ...string) Backing {
 	return &allModelWatcherStateBacking{
 		st:               st,
 		watcher:          st.workers.txnLogWatcher(),
 		stPool:           pool,
-		collectionByName: collections,
+		collectionByName: makeAllWatcherCollectionInfo(collNames...),
 	}
 }
 
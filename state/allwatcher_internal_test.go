@@ -1633,6 +1633,44 @@ func (s *allModelWatcherStateSuite) TestGetAll(c *gc.C) {
 	assertEntitiesEqual(c, gotEntities, expectedEntities)
 }
 
+func (s *allModelWatcherStateSuite) TestModelAnnotationsGetAll(c *gc.C) {
+	entities0 := s.setUpScenario(c, s.state, 2, false)
+	entities1 := s.setUpScenario(c, s.state1, 4, false)
+
+	var expectedAnnotations entityInfoSlice
+	for _, entity := range append(entities0, entities1...) {
+		if _, ok := entity.(*multiwatcher.AnnotationInfo); ok {
+			expectedAnnotations = append(expectedAnnotations, entity)
+		}
+	}
+	c.Assert(expectedAnnotations, gc.Not(gc.HasLen), 0)
+
+	pool := NewStatePool(s.state)
+	defer pool.Close()
+	b := newModelAnnotationsWatcherStateBacking(s.state, pool)
+	defer b.Release()
+	all := newStore()
+	err := b.GetAll(all)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var gotAnnotations entityInfoSlice
+	var modelUUIDs []string
+	for _, entity := range all.All() {
+		switch info := entity.(type) {
+		case *multiwatcher.ModelInfo:
+			modelUUIDs = append(modelUUIDs, info.ModelUUID)
+		case *multiwatcher.AnnotationInfo:
+			gotAnnotations = append(gotAnnotations, info)
+		default:
+			c.Fatalf("unexpected entity %#v", entity)
+		}
+	}
+	c.Assert(modelUUIDs, jc.SameContents, []string{s.state.ModelUUID(), s.state1.ModelUUID()})
+	sort.Sort(gotAnnotations)
+	sort.Sort(expectedAnnotations)
+	assertEntitiesEqual(c, gotAnnotations, expectedAnnotations)
+}
+
 func (s *allModelWatcherStateSuite) TestModelSettings(c *gc.C) {
 	// Init the test model.
 	b := s.NewAllModelWatcherStateBacking()
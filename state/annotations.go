@@ -9,7 +9,6 @@ import (
 
 	"github.com/juju/errors"
 	"gopkg.in/juju/names.v2"
-	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
 )
@@ -40,6 +39,9 @@ func (m *Model) SetAnnotations(entity GlobalEntity, annotations map[string]strin
 		if strings.Contains(key, ".") {
 			return fmt.Errorf("invalid key %q", key)
 		}
+		if strings.HasPrefix(key, migrationAnnotationPrefix) {
+			return fmt.Errorf("invalid key %q: prefix %q is reserved", key, migrationAnnotationPrefix)
+		}
 		if value == "" {
 			toRemove[key] = true
 		} else {
@@ -54,16 +56,15 @@ func (m *Model) SetAnnotations(entity GlobalEntity, annotations map[string]strin
 	// annotations in the meantime, we consider that worthy of an error
 	// (will be fixed when new entities can never share names with old ones).
 	buildTxn := func(attempt int) ([]txn.Op, error) {
-		annotations, closer := m.st.db().GetCollection(annotationsC)
-		defer closer()
-		if count, err := annotations.FindId(entity.globalKey()).Count(); err != nil {
-			return nil, err
-		} else if count == 0 {
+		_, err := m.st.db().DocumentStore().readAnnotationsDoc(entity.globalKey())
+		if errors.IsNotFound(err) {
 			// Check that the annotator entity was not previously destroyed.
 			if attempt != 0 {
 				return nil, fmt.Errorf("%s no longer exists", entity.Tag())
 			}
 			return insertAnnotationsOps(m.st, entity, toInsert)
+		} else if err != nil {
+			return nil, err
 		}
 		return updateAnnotations(m.st, entity, toUpdate, toRemove), nil
 	}
@@ -72,11 +73,8 @@ func (m *Model) SetAnnotations(entity GlobalEntity, annotations map[string]strin
 
 // Annotations returns all the annotations corresponding to an entity.
 func (m *Model) Annotations(entity GlobalEntity) (map[string]string, error) {
-	doc := new(annotatorDoc)
-	annotations, closer := m.st.db().GetCollection(annotationsC)
-	defer closer()
-	err := annotations.FindId(entity.globalKey()).One(doc)
-	if err == mgo.ErrNotFound {
+	doc, err := m.st.db().DocumentStore().readAnnotationsDoc(entity.globalKey())
+	if errors.IsNotFound(err) {
 		// Returning an empty map if there are no annotations.
 		return make(map[string]string), nil
 	}
@@ -96,6 +94,12 @@ func (m *Model) Annotation(entity GlobalEntity, key string) (string, error) {
 	return ann[key], nil
 }
 
+// WatchAnnotations returns a NotifyWatcher that notifies of changes
+// to the annotations of the entities in the model.
+func (st *State) WatchAnnotations() NotifyWatcher {
+	return newNotifyCollWatcher(st, annotationsC, isLocalID(st))
+}
+
 // insertAnnotationsOps returns the operations required to insert annotations in MongoDB.
 func insertAnnotationsOps(st *State, entity GlobalEntity, toInsert map[string]string) ([]txn.Op, error) {
 	tag := entity.Tag()
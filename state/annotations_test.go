@@ -11,6 +11,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/storage"
 	"github.com/juju/juju/testing"
 )
@@ -39,6 +40,11 @@ func (s *AnnotationsSuite) TestSetAnnotationsInvalidKey(c *gc.C) {
 	c.Assert(errors.Cause(err), gc.ErrorMatches, ".*invalid key.*")
 }
 
+func (s *AnnotationsSuite) TestSetAnnotationsReservedKey(c *gc.C) {
+	err := s.setAnnotationResult(c, "juju-migration-affinity", "{}")
+	c.Assert(errors.Cause(err), gc.ErrorMatches, `invalid key "juju-migration-affinity": prefix "juju-migration-" is reserved`)
+}
+
 func (s *AnnotationsSuite) TestSetAnnotationsCreate(c *gc.C) {
 	s.createTestAnnotation(c)
 }
@@ -134,6 +140,28 @@ func (s *AnnotationsSuite) TestSetAnnotationsConcurrently(c *gc.C) {
 	assertAnnotation(c, s.Model, s.testEntity, key, last)
 }
 
+func (s *AnnotationsSuite) TestWatchAnnotations(c *gc.C) {
+	w := s.State.WatchAnnotations()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	s.assertSetAnnotation(c, "key", "value")
+	wc.AssertOneChange()
+
+	s.assertSetAnnotation(c, "key", "")
+	wc.AssertOneChange()
+
+	// Annotations of other models are not reported.
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	m, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetAnnotations(m, map[string]string{"key": "value"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+}
+
 type AnnotationsEnvSuite struct {
 	ConnSuite
 }
@@ -6,6 +6,7 @@ package state
 import (
 	stderrors "errors"
 	"fmt"
+	"net"
 	"sort"
 	"strconv"
 	"strings"
@@ -13,6 +14,7 @@ import (
 	"github.com/juju/errors"
 	jujutxn "github.com/juju/txn"
 	"github.com/juju/utils/series"
+	"github.com/juju/utils/set"
 	"gopkg.in/juju/charm.v6"
 	csparams "gopkg.in/juju/charmrepo.v2/csclient/params"
 	"gopkg.in/juju/names.v2"
@@ -50,6 +52,10 @@ type applicationDoc struct {
 	MinUnits             int        `bson:"minunits"`
 	TxnRevno             int64      `bson:"txn-revno"`
 	MetricCredentials    []byte     `bson:"metric-credentials"`
+
+	// ExposedEndpoints holds the expose settings of the
+	// application's endpoints, keyed on endpoint name.
+	ExposedEndpoints map[string]ExposedEndpoint `bson:"exposed-endpoints,omitempty"`
 }
 
 func newApplication(st *State, doc *applicationDoc) *Application {
@@ -157,6 +163,13 @@ type DestroyApplicationOperation struct {
 	// then detachable storage will be detached and left in the model.
 	DestroyStorage bool
 
+	// RetainStorage controls whether or not storage attached to
+	// units of the application is marked as retained from the
+	// application, so that it can be identified and attached to
+	// another application once this one is gone. It cannot be
+	// combined with DestroyStorage.
+	RetainStorage bool
+
 	// RemoveOffers controls whether or not application offers
 	// are removed. If this is false, then the operation will
 	// fail if there are any offers remaining.
@@ -172,6 +185,9 @@ func (op *DestroyApplicationOperation) Build(attempt int) ([]txn.Op, error) {
 			return nil, err
 		}
 	}
+	if op.DestroyStorage && op.RetainStorage {
+		return nil, errors.New("cannot both destroy and retain storage")
+	}
 	ops, err := op.app.destroyOps(op.DestroyStorage, op.RemoveOffers)
 	switch err {
 	case errRefresh:
@@ -179,11 +195,47 @@ func (op *DestroyApplicationOperation) Build(attempt int) ([]txn.Op, error) {
 	case errAlreadyDying:
 		return nil, jujutxn.ErrNoOperations
 	case nil:
+		if op.RetainStorage {
+			retainOps, err := op.app.retainStorageOps()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, retainOps...)
+		}
 		return ops, nil
 	}
 	return nil, err
 }
 
+// retainStorageOps returns the operations required to mark the storage
+// owned by the application and its units as retained from it.
+func (a *Application) retainStorageOps() ([]txn.Op, error) {
+	units, err := a.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	owners := []string{a.Tag().String()}
+	for _, unit := range units {
+		owners = append(owners, unit.Tag().String())
+	}
+	coll, closer := a.st.db().GetCollection(storageInstancesC)
+	defer closer()
+	var docs []storageInstanceDoc
+	if err := coll.Find(bson.D{{"owner", bson.D{{"$in", owners}}}}).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get storage instances")
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      storageInstancesC,
+			Id:     doc.Id,
+			Assert: isAliveDoc,
+			Update: bson.D{{"$set", bson.D{{"retained-from", a.doc.Name}}}},
+		}
+	}
+	return ops, nil
+}
+
 // Done is part of the ModelOperation interface.
 func (op *DestroyApplicationOperation) Done(err error) error {
 	return errors.Annotatef(err, "cannot destroy application %q", op.app)
@@ -343,6 +395,8 @@ func (a *Application) removeOps(asserts bson.D) ([]txn.Op, error) {
 	ops = append(ops,
 		removeEndpointBindingsOp(globalKey),
 		removeConstraintsOp(globalKey),
+		removeAffinityOp(a.st, globalKey),
+		removeAgentVersionPinOp(a.st, globalKey),
 		annotationRemoveOp(a.st, globalKey),
 		removeLeadershipSettingsOp(name),
 		removeStatusOp(a.st, globalKey),
@@ -351,6 +405,22 @@ func (a *Application) removeOps(asserts bson.D) ([]txn.Op, error) {
 	return ops, nil
 }
 
+// ExposedEndpoint holds the expose settings of an application endpoint:
+// the sources that may access the ports opened for the endpoint.
+type ExposedEndpoint struct {
+	// ExposeToSpaces holds the names of the spaces whose subnets may
+	// access the endpoint's ports.
+	ExposeToSpaces []string `bson:"to-spaces,omitempty"`
+
+	// ExposeToCIDRs holds the CIDRs that may access the endpoint's
+	// ports.
+	ExposeToCIDRs []string `bson:"to-cidrs,omitempty"`
+}
+
+// anyCIDR is the CIDR that an endpoint exposed without any spaces or
+// CIDRs is exposed to.
+const anyCIDR = "0.0.0.0/0"
+
 // IsExposed returns whether this application is exposed. The explicitly open
 // ports (with open-port) for exposed applications may be accessed from machines
 // outside of the local deployment network. See SetExposed and ClearExposed.
@@ -358,29 +428,205 @@ func (a *Application) IsExposed() bool {
 	return a.doc.Exposed
 }
 
-// SetExposed marks the application as exposed.
+// ExposedEndpoints returns the expose settings of the application's
+// endpoints, keyed on endpoint name. The settings for the empty endpoint
+// name apply to every endpoint without settings of its own, and to
+// ports not opened for any endpoint. An application exposed without
+// any settings is exposed to 0.0.0.0/0 on all its endpoints.
+func (a *Application) ExposedEndpoints() map[string]ExposedEndpoint {
+	if !a.doc.Exposed {
+		return nil
+	}
+	if len(a.doc.ExposedEndpoints) == 0 {
+		return map[string]ExposedEndpoint{
+			"": {ExposeToCIDRs: []string{anyCIDR}},
+		}
+	}
+	result := make(map[string]ExposedEndpoint, len(a.doc.ExposedEndpoints))
+	for name, exposed := range a.doc.ExposedEndpoints {
+		result[name] = exposed
+	}
+	return result
+}
+
+// ExposedEndpointCIDRs returns, keyed on endpoint name, the CIDRs that
+// may access the ports of each exposed endpoint: the endpoint's exposed
+// CIDRs, and the CIDRs of the subnets in its exposed spaces.
+func (a *Application) ExposedEndpointCIDRs() (map[string][]string, error) {
+	exposed := a.ExposedEndpoints()
+	if len(exposed) == 0 {
+		return nil, nil
+	}
+	result := make(map[string][]string, len(exposed))
+	for name, settings := range exposed {
+		cidrs := set.NewStrings(settings.ExposeToCIDRs...)
+		for _, spaceName := range settings.ExposeToSpaces {
+			space, err := a.st.Space(spaceName)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			subnets, err := space.Subnets()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			for _, subnet := range subnets {
+				cidrs.Add(subnet.CIDR())
+			}
+		}
+		result[name] = cidrs.SortedValues()
+	}
+	return result, nil
+}
+
+// SetExposed marks the application as exposed to 0.0.0.0/0 on all
+// its endpoints without expose settings of their own.
 // See ClearExposed and IsExposed.
 func (a *Application) SetExposed() error {
-	return a.setExposed(true)
+	return a.MergeExposeSettings(nil)
 }
 
-// ClearExposed removes the exposed flag from the application.
-// See SetExposed and IsExposed.
-func (a *Application) ClearExposed() error {
-	return a.setExposed(false)
+// MergeExposeSettings marks the application as exposed, and merges the
+// supplied expose settings into the application's existing settings,
+// replacing the settings of any endpoint named in both. Settings with
+// the empty endpoint name apply to every endpoint without settings of
+// its own. Settings without any spaces or CIDRs expose the endpoint to
+// 0.0.0.0/0. If no settings are supplied, all endpoints without
+// settings of their own are exposed to 0.0.0.0/0.
+func (a *Application) MergeExposeSettings(exposedEndpoints map[string]ExposedEndpoint) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot expose application %q", a)
+	if len(exposedEndpoints) == 0 {
+		exposedEndpoints = map[string]ExposedEndpoint{"": {}}
+	}
+	if err := a.validateExposeSettings(exposedEndpoints); err != nil {
+		return errors.Trace(err)
+	}
+	var merged map[string]ExposedEndpoint
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := a.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if a.doc.Life != Alive {
+			return nil, errNotAlive
+		}
+		merged = a.ExposedEndpoints()
+		if merged == nil {
+			merged = make(map[string]ExposedEndpoint)
+		}
+		for name, exposed := range exposedEndpoints {
+			if len(exposed.ExposeToSpaces) == 0 && len(exposed.ExposeToCIDRs) == 0 {
+				exposed.ExposeToCIDRs = []string{anyCIDR}
+			}
+			merged[name] = exposed
+		}
+		return []txn.Op{{
+			C:      applicationsC,
+			Id:     a.doc.DocID,
+			Assert: bson.D{{"life", Alive}, {"txn-revno", a.doc.TxnRevno}},
+			Update: bson.D{{"$set", bson.D{
+				{"exposed", true},
+				{"exposed-endpoints", merged},
+			}}},
+		}}, nil
+	}
+	if err := a.st.db().Run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	a.doc.Exposed = true
+	a.doc.ExposedEndpoints = merged
+	return nil
+}
+
+// validateExposeSettings checks that the supplied expose settings name
+// endpoints of the application's charm, existing spaces and valid CIDRs.
+func (a *Application) validateExposeSettings(exposedEndpoints map[string]ExposedEndpoint) error {
+	for name, exposed := range exposedEndpoints {
+		if name != "" {
+			if _, err := a.Endpoint(name); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		for _, spaceName := range exposed.ExposeToSpaces {
+			if _, err := a.st.Space(spaceName); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		for _, cidr := range exposed.ExposeToCIDRs {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return errors.NotValidf("CIDR %q", cidr)
+			}
+		}
+	}
+	return nil
 }
 
-func (a *Application) setExposed(exposed bool) (err error) {
+// UnsetExposeSettings removes the expose settings of the named
+// endpoints. The empty endpoint name refers to the settings that apply
+// to every endpoint without settings of its own. If no settings remain,
+// the application is no longer exposed.
+func (a *Application) UnsetExposeSettings(endpoints []string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot unexpose application %q", a)
+	var remaining map[string]ExposedEndpoint
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := a.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if a.doc.Life != Alive {
+			return nil, errNotAlive
+		}
+		remaining = a.ExposedEndpoints()
+		for _, name := range endpoints {
+			if _, ok := remaining[name]; !ok {
+				return nil, errors.NotFoundf("expose settings for endpoint %q", name)
+			}
+			delete(remaining, name)
+		}
+		update := bson.D{{"$set", bson.D{
+			{"exposed", true},
+			{"exposed-endpoints", remaining},
+		}}}
+		if len(remaining) == 0 {
+			remaining = nil
+			update = bson.D{
+				{"$set", bson.D{{"exposed", false}}},
+				{"$unset", bson.D{{"exposed-endpoints", nil}}},
+			}
+		}
+		return []txn.Op{{
+			C:      applicationsC,
+			Id:     a.doc.DocID,
+			Assert: bson.D{{"life", Alive}, {"txn-revno", a.doc.TxnRevno}},
+			Update: update,
+		}}, nil
+	}
+	if err := a.st.db().Run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	a.doc.Exposed = len(remaining) > 0
+	a.doc.ExposedEndpoints = remaining
+	return nil
+}
+
+// ClearExposed removes the exposed flag, and all expose settings, from
+// the application. See SetExposed and IsExposed.
+func (a *Application) ClearExposed() error {
 	ops := []txn.Op{{
 		C:      applicationsC,
 		Id:     a.doc.DocID,
 		Assert: isAliveDoc,
-		Update: bson.D{{"$set", bson.D{{"exposed", exposed}}}},
+		Update: bson.D{
+			{"$set", bson.D{{"exposed", false}}},
+			{"$unset", bson.D{{"exposed-endpoints", nil}}},
+		},
 	}}
 	if err := a.st.db().RunTransaction(ops); err != nil {
-		return errors.Errorf("cannot set exposed flag for application %q to %v: %v", a, exposed, onAbort(err, errNotAlive))
+		return errors.Errorf("cannot set exposed flag for application %q to false: %v", a, onAbort(err, errNotAlive))
 	}
-	a.doc.Exposed = exposed
+	a.doc.Exposed = false
+	a.doc.ExposedEndpoints = nil
 	return nil
 }
 
@@ -1549,6 +1795,7 @@ func (a *Application) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 		removeStatusOp(a.st, u.globalKey()),
 		removeConstraintsOp(u.globalAgentKey()),
 		annotationRemoveOp(a.st, u.globalKey()),
+		removeAffinityOp(a.st, u.globalKey()),
 		newCleanupOp(cleanupRemovedUnit, u.doc.Name),
 	}
 	ops = append(ops, portsOps...)
@@ -1638,6 +1885,20 @@ func (a *Application) Relations() (relations []*Relation, err error) {
 	return applicationRelations(a.st, a.doc.Name)
 }
 
+// SetRelationStatus sets the status of the relation with the given id,
+// which the application must take part in. It implements
+// status.RelationStatusSetter.
+func (a *Application) SetRelationStatus(relationId int, info status.StatusInfo) error {
+	rel, err := a.st.Relation(relationId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := rel.Endpoint(a.doc.Name); err != nil {
+		return errors.NotFoundf("application %q in relation %d", a.doc.Name, relationId)
+	}
+	return errors.Annotatef(rel.SetStatus(info), "relation %d", relationId)
+}
+
 func applicationRelations(st *State, name string) (relations []*Relation, err error) {
 	defer errors.DeferredAnnotatef(&err, "can't get relations for application %q", name)
 	relationsCollection, closer := st.db().GetCollection(relationsC)
@@ -1966,6 +2227,31 @@ func (a *Application) StatusHistory(filter status.StatusHistoryFilter) ([]status
 	return statusHistory(args)
 }
 
+// StatusHistoryPage implements status.StatusHistoryPager. The
+// application's history is read by KindApplication.
+func (a *Application) StatusHistoryPage(kind status.HistoryKind, filter status.StatusHistoryFilter, pageSize int, token string) (status.StatusHistoryPage, error) {
+	if kind != status.KindApplication {
+		return status.StatusHistoryPage{}, errors.NotValidf("%q status history for an application", kind)
+	}
+	return statusHistoryPage(&statusHistoryPageArgs{
+		db:       a.st.db(),
+		kinds:    map[string]status.HistoryKind{a.globalKey(): kind},
+		filter:   filter,
+		pageSize: pageSize,
+		token:    token,
+	})
+}
+
+// WatchStatusHistory returns a watcher reporting the entries added to
+// the application's status history, which is selected by
+// KindApplication.
+func (a *Application) WatchStatusHistory(kind status.HistoryKind) (StatusHistoryWatcher, error) {
+	if kind != status.KindApplication {
+		return nil, errors.NotValidf("%q status history for an application", kind)
+	}
+	return newStatusHistoryWatcher(a.st, map[string]status.HistoryKind{a.globalKey(): kind}), nil
+}
+
 // ApplicationAndUnitsStatus returns the status for this application and all its units.
 func (a *Application) ApplicationAndUnitsStatus() (status.StatusInfo, map[string]status.StatusInfo, error) {
 	applicationStatus, err := a.Status()
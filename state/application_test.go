@@ -1770,6 +1770,92 @@ func (s *ApplicationSuite) TestServiceExposed(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, notAliveErr)
 }
 
+func (s *ApplicationSuite) TestMergeExposeSettings(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.0.0/24", SpaceName: "admin"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("admin", "", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"server-admin": {
+			ExposeToSpaces: []string{"admin"},
+			ExposeToCIDRs:  []string{"192.168.1.0/24"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsTrue)
+
+	// Exposing without settings exposes the remaining endpoints to all.
+	err = s.mysql.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.ExposedEndpoints(), jc.DeepEquals, map[string]state.ExposedEndpoint{
+		"": {ExposeToCIDRs: []string{"0.0.0.0/0"}},
+		"server-admin": {
+			ExposeToSpaces: []string{"admin"},
+			ExposeToCIDRs:  []string{"192.168.1.0/24"},
+		},
+	})
+	cidrs, err := s.mysql.ExposedEndpointCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, jc.DeepEquals, map[string][]string{
+		"":             {"0.0.0.0/0"},
+		"server-admin": {"10.0.0.0/24", "192.168.1.0/24"},
+	})
+
+	err = s.mysql.UnsetExposeSettings([]string{""})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsTrue)
+	err = s.mysql.UnsetExposeSettings([]string{"server"})
+	c.Assert(err, gc.ErrorMatches, `cannot unexpose application "mysql": expose settings for endpoint "server" not found`)
+	err = s.mysql.UnsetExposeSettings([]string{"server-admin"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsFalse)
+	c.Assert(s.mysql.ExposedEndpoints(), gc.HasLen, 0)
+}
+
+func (s *ApplicationSuite) TestMergeExposeSettingsInvalid(c *gc.C) {
+	for i, test := range []struct {
+		exposed map[string]state.ExposedEndpoint
+		err     string
+	}{{
+		exposed: map[string]state.ExposedEndpoint{"foo": {}},
+		err:     `cannot expose application "mysql": application "mysql" has no "foo" relation`,
+	}, {
+		exposed: map[string]state.ExposedEndpoint{"server": {ExposeToSpaces: []string{"missing"}}},
+		err:     `cannot expose application "mysql": space "missing" not found`,
+	}, {
+		exposed: map[string]state.ExposedEndpoint{"": {ExposeToCIDRs: []string{"10.0.0.0"}}},
+		err:     `cannot expose application "mysql": CIDR "10.0.0.0" not valid`,
+	}} {
+		c.Logf("test %d", i)
+		err := s.mysql.MergeExposeSettings(test.exposed)
+		c.Check(err, gc.ErrorMatches, test.err)
+		c.Check(s.mysql.IsExposed(), jc.IsFalse)
+	}
+}
+
+func (s *ApplicationSuite) TestExposedWithoutSettings(c *gc.C) {
+	// Applications exposed before expose settings were introduced
+	// are exposed to all on every endpoint.
+	err := state.SetApplicationExposedFlag(s.mysql)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.ExposedEndpoints(), jc.DeepEquals, map[string]state.ExposedEndpoint{
+		"": {ExposeToCIDRs: []string{"0.0.0.0/0"}},
+	})
+
+	err = s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"server-admin": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.ExposedEndpoints(), jc.DeepEquals, map[string]state.ExposedEndpoint{
+		"":             {ExposeToCIDRs: []string{"0.0.0.0/0"}},
+		"server-admin": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+}
+
 func (s *ApplicationSuite) TestAddUnit(c *gc.C) {
 	// Check that principal units can be added on their own.
 	unitZero, err := s.mysql.AddUnit(state.AddUnitParams{})
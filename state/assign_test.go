@@ -244,6 +244,46 @@ func (s *AssignSuite) TestDirectAssignIgnoresConstraints(c *gc.C) {
 	c.Assert(mcons, gc.DeepEquals, econs)
 }
 
+func (s *AssignSuite) TestDirectAssignIgnoresArch(c *gc.C) {
+	err := s.State.SetModelConstraints(constraints.MustParse("arch=arm64"))
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A machine chosen explicitly is used whatever its architecture.
+	machine, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:      "quantal",
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: constraints.MustParse("arch=amd64"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AssignSuite) TestAssignToCleanMachineChecksArch(c *gc.C) {
+	err := s.wordpress.SetConstraints(constraints.MustParse("arch=arm64"))
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A provisioned machine's architecture is its actual one.
+	amd64Machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	amd64 := arch.AMD64
+	err = amd64Machine.SetProvisioned("i-amd", "fake-nonce", &instance.HardwareCharacteristics{Arch: &amd64})
+	c.Assert(err, jc.ErrorIsNil)
+	arm64Machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	arm64 := arch.ARM64
+	err = arm64Machine.SetProvisioned("i-arm", "fake-nonce", &instance.HardwareCharacteristics{Arch: &arm64})
+	c.Assert(err, jc.ErrorIsNil)
+
+	m, err := unit.AssignToCleanMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Id(), gc.Equals, arm64Machine.Id())
+}
+
 func (s *AssignSuite) TestAssignBadSeries(c *gc.C) {
 	machine, err := s.State.AddMachine("burble", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -43,6 +43,7 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/utils/filestorage"
 	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
 )
 
 const (
@@ -103,6 +104,10 @@ type Backups interface {
 	// it returns the tag string for the machine where the backup originated
 	// or error if the process fails.
 	Restore(backupId string, dbInfo *DBInfo, args RestoreArgs) (names.Tag, error)
+
+	// Verify restores the backup archive into a scratch mongod server
+	// and reports how it differs from the live controller.
+	Verify(backupId string, session *mgo.Session) (*VerifyResult, error)
 }
 
 type backups struct {
@@ -12,13 +12,15 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	"github.com/juju/utils/filestorage"
+	"github.com/juju/utils/set"
 
 	"github.com/juju/juju/state"
 )
 
 var (
-	Create        = create
-	FileTimestamp = fileTimestamp
+	Create           = create
+	FileTimestamp    = fileTimestamp
+	RestoreScratchDB = restoreScratchDB
 
 	TestGetFilesToBackUp  = &getFilesToBackUp
 	GetDBDumper           = &getDBDumper
@@ -32,6 +34,18 @@ var (
 	MongoInstalledVersion = &mongoInstalledVersion
 )
 
+// CompareSnapshots exposes compareSnapshots for testing, building the
+// snapshots from the supplied collection names and models.
+func CompareSnapshots(
+	restoredCollections []string, restoredModels map[string]string,
+	liveCollections []string, liveModels map[string]string,
+) []string {
+	return compareSnapshots(
+		dbSnapshot{set.NewStrings(restoredCollections...), restoredModels},
+		dbSnapshot{set.NewStrings(liveCollections...), liveModels},
+	)
+}
+
 var _ filestorage.DocStorage = (*backupsDocStorage)(nil)
 var _ filestorage.RawFileStorage = (*backupBlobStorage)(nil)
 
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/utils/filestorage"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
 
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state/backups"
@@ -44,6 +45,8 @@ type FakeBackups struct {
 	InstanceId instance.Id
 	// ArchiveArg holds the backup archive that was passed in.
 	ArchiveArg io.Reader
+	// VerifyResult holds the verification result to return.
+	VerifyResult *backups.VerifyResult
 }
 
 var _ backups.Backups = (*FakeBackups)(nil)
@@ -104,6 +107,16 @@ func (b *FakeBackups) Restore(bkpId string, dbInfo *backups.DBInfo, args backups
 	return nil, errors.Trace(b.Error)
 }
 
+// Verify verifies a backup against the live controller.
+func (b *FakeBackups) Verify(bkpId string, session *mgo.Session) (*backups.VerifyResult, error) {
+	b.Calls = append(b.Calls, "Verify")
+	b.IDArg = bkpId
+	if b.Error != nil {
+		return nil, errors.Trace(b.Error)
+	}
+	return b.VerifyResult, nil
+}
+
 // TODO(ericsnow) FakeStorage should probably move over to the utils repo.
 
 // FakeStorage is a FileStorage implementation to use when testing
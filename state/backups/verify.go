@@ -0,0 +1,272 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"gopkg.in/mgo.v2"
+)
+
+const (
+	// jujuDBName is the name of the database holding juju state.
+	jujuDBName = "juju"
+
+	modelsCollection = "models"
+)
+
+// VerifyResult describes the outcome of verifying a backup archive
+// against the live controller.
+type VerifyResult struct {
+	// Models holds the UUIDs of the models found in the backup.
+	Models []string
+
+	// Discrepancies describes each difference found between the
+	// restored backup and the live controller.
+	Discrepancies []string
+}
+
+// dbSnapshot summarises the contents of a juju state database for the
+// purposes of comparing a restored backup with the live database.
+type dbSnapshot struct {
+	// Collections holds the names of the database's collections.
+	Collections set.Strings
+
+	// Models maps model UUIDs to model names.
+	Models map[string]string
+}
+
+// Verify restores the backup archive with the supplied ID into a
+// scratch mongod server, compares it with the live juju database and
+// reports any discrepancies found. The scratch server is started for
+// the verification alone, so the live server never holds restored
+// data, and it is stopped and its data removed before Verify returns.
+func (b *backups) Verify(backupId string, session *mgo.Session) (*VerifyResult, error) {
+	_, archive, err := b.Get(backupId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer archive.Close()
+
+	workspace, err := NewArchiveWorkspaceReader(archive)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot unpack backup archive")
+	}
+	defer workspace.Close()
+
+	dumpDir := filepath.Join(workspace.DBDumpDir, jujuDBName)
+	if _, err := os.Stat(dumpDir); err != nil {
+		return nil, errors.Annotatef(err, "backup does not contain the %q database", jujuDBName)
+	}
+
+	addr, stop, err := startScratchMongod()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot start scratch mongod")
+	}
+	defer stop()
+	if err := restoreScratchDB(addr, dumpDir); err != nil {
+		return nil, errors.Trace(err)
+	}
+	scratch, err := mgo.DialWithTimeout(addr, scratchMongodTimeout)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot connect to scratch mongod")
+	}
+	defer scratch.Close()
+
+	restored, err := snapshotDB(scratch.DB(jujuDBName))
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read restored backup")
+	}
+	live, err := snapshotDB(session.DB(jujuDBName))
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read live database")
+	}
+
+	result := &VerifyResult{
+		Discrepancies: compareSnapshots(restored, live),
+	}
+	for uuid := range restored.Models {
+		result.Models = append(result.Models, uuid)
+	}
+	sort.Strings(result.Models)
+	return result, nil
+}
+
+// scratchMongodTimeout is how long to wait for the scratch mongod
+// server to accept connections.
+const scratchMongodTimeout = 30 * time.Second
+
+// processFinishedMessage is the message of the error returned by
+// os.Process.Kill when the process has already been waited for.
+const processFinishedMessage = "os: process already finished"
+
+// startScratchMongod starts a mongod server listening only on the
+// loopback interface, without authentication, and keeping its data in
+// a new temporary directory. It returns the server's address once it
+// accepts connections, and a function that stops the server and
+// removes its data.
+var startScratchMongod = func() (string, func(), error) {
+	mongodPath, err := getMongodPath()
+	if err != nil {
+		return "", nil, errors.Annotate(err, "mongod not available")
+	}
+	dir, err := ioutil.TempDir("", "juju-verify-backup")
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	port, err := freeLoopbackPort()
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", nil, errors.Trace(err)
+	}
+	cmd := exec.Command(mongodPath,
+		"--dbpath", dir,
+		"--bind_ip", "127.0.0.1",
+		"--port", strconv.Itoa(port),
+		"--nounixsocket",
+		"--quiet",
+	)
+	if err := cmd.Start(); err != nil {
+		os.RemoveAll(dir)
+		return "", nil, errors.Trace(err)
+	}
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+	stop := func() {
+		// mongod may already have exited, in which case there is
+		// nothing to kill.
+		if err := cmd.Process.Kill(); err != nil && err.Error() != processFinishedMessage {
+			logger.Errorf("cannot stop scratch mongod: %v", err)
+		}
+		<-exited
+		if err := os.RemoveAll(dir); err != nil {
+			logger.Errorf("cannot remove scratch mongod data: %v", err)
+		}
+	}
+
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+	deadline := time.Now().Add(scratchMongodTimeout)
+	for {
+		session, err := mgo.DialWithTimeout(addr, time.Second)
+		if err == nil {
+			session.Close()
+			return addr, stop, nil
+		}
+		select {
+		case err := <-exited:
+			exited <- err
+			stop()
+			return "", nil, errors.Annotate(err, "mongod exited")
+		default:
+		}
+		if time.Now().After(deadline) {
+			stop()
+			return "", nil, errors.Annotate(err, "mongod not accepting connections")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// freeLoopbackPort returns a port on the loopback interface that
+// nothing was listening on when it was chosen.
+func freeLoopbackPort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// restoreScratchDB restores the juju database dumped in dumpDir into
+// the scratch mongod server at the supplied address.
+func restoreScratchDB(addr, dumpDir string) error {
+	mongorestorePath, err := getMongorestorePath()
+	if err != nil {
+		return errors.Annotate(err, "mongorestore not available")
+	}
+	options := []string{
+		"--host", addr,
+		"--db", jujuDBName,
+		"--batchSize", "10",
+		dumpDir,
+	}
+	if err := runCommandFn(mongorestorePath, options...); err != nil {
+		return errors.Annotate(err, "cannot restore backup into scratch mongod")
+	}
+	return nil
+}
+
+// snapshotDB reads the collection names and models from db.
+func snapshotDB(db *mgo.Database) (dbSnapshot, error) {
+	names, err := db.CollectionNames()
+	if err != nil {
+		return dbSnapshot{}, errors.Trace(err)
+	}
+	var docs []struct {
+		UUID string `bson:"_id"`
+		Name string `bson:"name"`
+	}
+	if err := db.C(modelsCollection).Find(nil).All(&docs); err != nil {
+		return dbSnapshot{}, errors.Trace(err)
+	}
+	snapshot := dbSnapshot{
+		Collections: set.NewStrings(names...),
+		Models:      make(map[string]string),
+	}
+	for _, doc := range docs {
+		snapshot.Models[doc.UUID] = doc.Name
+	}
+	return snapshot, nil
+}
+
+// compareSnapshots returns a description of every difference between
+// the restored and live database snapshots that an operator would need
+// to know about before relying on the backup.
+func compareSnapshots(restored, live dbSnapshot) []string {
+	var discrepancies []string
+	if len(restored.Models) == 0 {
+		discrepancies = append(discrepancies, "backup contains no models")
+	}
+	for _, name := range live.Collections.Difference(restored.Collections).SortedValues() {
+		discrepancies = append(discrepancies, fmt.Sprintf("collection %q missing from backup", name))
+	}
+	var uuids []string
+	for uuid := range live.Models {
+		uuids = append(uuids, uuid)
+	}
+	sort.Strings(uuids)
+	for _, uuid := range uuids {
+		if _, ok := restored.Models[uuid]; !ok {
+			discrepancies = append(discrepancies, fmt.Sprintf(
+				"model %q (%s) missing from backup", live.Models[uuid], uuid,
+			))
+		}
+	}
+	uuids = uuids[:0]
+	for uuid := range restored.Models {
+		uuids = append(uuids, uuid)
+	}
+	sort.Strings(uuids)
+	for _, uuid := range uuids {
+		if _, ok := live.Models[uuid]; !ok {
+			discrepancies = append(discrepancies, fmt.Sprintf(
+				"model %q (%s) in backup no longer exists", restored.Models[uuid], uuid,
+			))
+		}
+	}
+	return discrepancies
+}
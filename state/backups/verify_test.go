@@ -0,0 +1,69 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups"
+)
+
+type verifySuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&verifySuite{})
+
+func (s *verifySuite) TestCompareSnapshotsIdentical(c *gc.C) {
+	models := map[string]string{"uuid-1": "controller"}
+	collections := []string{"models", "machines"}
+	discrepancies := backups.CompareSnapshots(collections, models, collections, models)
+	c.Assert(discrepancies, gc.HasLen, 0)
+}
+
+func (s *verifySuite) TestCompareSnapshotsEmptyBackup(c *gc.C) {
+	discrepancies := backups.CompareSnapshots(
+		nil, map[string]string{},
+		nil, map[string]string{},
+	)
+	c.Assert(discrepancies, jc.DeepEquals, []string{"backup contains no models"})
+}
+
+func (s *verifySuite) TestCompareSnapshotsDifferences(c *gc.C) {
+	discrepancies := backups.CompareSnapshots(
+		[]string{"models", "machines"},
+		map[string]string{"uuid-1": "controller", "uuid-2": "old"},
+		[]string{"models", "machines", "units", "applications"},
+		map[string]string{"uuid-1": "controller", "uuid-3": "new"},
+	)
+	c.Assert(discrepancies, jc.DeepEquals, []string{
+		`collection "applications" missing from backup`,
+		`collection "units" missing from backup`,
+		`model "new" (uuid-3) missing from backup`,
+		`model "old" (uuid-2) in backup no longer exists`,
+	})
+}
+
+func (s *verifySuite) TestRestoreScratchDB(c *gc.C) {
+	s.PatchValue(backups.GetMongorestorePath, func() (string, error) { return "/a/fake/mongorestore", nil })
+	var ranCommand string
+	var ranArgs []string
+	s.PatchValue(backups.RunCommand, func(command string, args ...string) error {
+		ranCommand, ranArgs = command, args
+		return nil
+	})
+	err := backups.RestoreScratchDB("127.0.0.1:37017", "/dump/juju")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ranCommand, gc.Equals, "/a/fake/mongorestore")
+	// The scratch server runs without ssl or authentication, and
+	// the backup is restored into its juju database.
+	c.Assert(ranArgs, jc.DeepEquals, []string{
+		"--host", "127.0.0.1:37017",
+		"--db", "juju",
+		"--batchSize", "10",
+		"/dump/juju",
+	})
+}
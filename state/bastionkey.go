@@ -0,0 +1,89 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/utils/ssh"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// bastionKeyDocId is the id of the single bastion key document
+// held for each model.
+const bastionKeyDocId = "bastion"
+
+// bastionKeyComment is the comment recorded against the bastion's
+// public key in the machines' authorized_keys files.
+const bastionKeyComment = "juju-bastion"
+
+// BastionKey holds the ssh key pair with which the controller's ssh
+// bastion connects to the model's machines.
+type BastionKey struct {
+	// PrivateKey is the PEM-encoded private key.
+	PrivateKey string
+
+	// PublicKey is the public key, in authorized_keys format.
+	PublicKey string
+}
+
+// bastionKeyDoc represents the MongoDB document that stores a model's
+// bastion key pair.
+type bastionKeyDoc struct {
+	DocID      string `bson:"_id"`
+	ModelUUID  string `bson:"model-uuid"`
+	PrivateKey string `bson:"private-key"`
+	PublicKey  string `bson:"public-key"`
+}
+
+// BastionKey returns the key pair with which the ssh bastion connects
+// to the model's machines, generating it the first time it is asked
+// for. Each model has its own key, so that access to one model's
+// machines grants nothing on another's.
+func (st *State) BastionKey() (BastionKey, error) {
+	key, err := st.bastionKey()
+	if !errors.IsNotFound(err) {
+		return key, errors.Trace(err)
+	}
+	private, public, err := ssh.GenerateKey(bastionKeyComment)
+	if err != nil {
+		return BastionKey{}, errors.Annotate(err, "generating bastion key")
+	}
+	docID := st.docID(bastionKeyDocId)
+	err = st.db().RunTransaction([]txn.Op{{
+		C:      bastionKeysC,
+		Id:     docID,
+		Assert: txn.DocMissing,
+		Insert: &bastionKeyDoc{
+			DocID:      docID,
+			ModelUUID:  st.ModelUUID(),
+			PrivateKey: private,
+			PublicKey:  public,
+		},
+	}})
+	switch err {
+	case nil:
+		return BastionKey{PrivateKey: private, PublicKey: public}, nil
+	case txn.ErrAborted:
+		// Another controller generated the key first; use theirs.
+		key, err := st.bastionKey()
+		return key, errors.Trace(err)
+	default:
+		return BastionKey{}, errors.Annotate(err, "saving bastion key")
+	}
+}
+
+func (st *State) bastionKey() (BastionKey, error) {
+	coll, closer := st.db().GetCollection(bastionKeysC)
+	defer closer()
+
+	var doc bastionKeyDoc
+	err := coll.FindId(bastionKeyDocId).One(&doc)
+	if err == mgo.ErrNotFound {
+		return BastionKey{}, errors.NotFoundf("bastion key")
+	} else if err != nil {
+		return BastionKey{}, errors.Annotate(err, "bastion key lookup failed")
+	}
+	return BastionKey{PrivateKey: doc.PrivateKey, PublicKey: doc.PublicKey}, nil
+}
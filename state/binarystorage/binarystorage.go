@@ -64,11 +64,12 @@ func (s *binaryStorage) Add(r io.Reader, metadata Metadata) (resultErr error) {
 	}()
 
 	newDoc := metadataDoc{
-		Id:      metadata.Version,
-		Version: metadata.Version,
-		Size:    metadata.Size,
-		SHA256:  metadata.SHA256,
-		Path:    path,
+		Id:        metadata.Version,
+		Version:   metadata.Version,
+		Size:      metadata.Size,
+		SHA256:    metadata.SHA256,
+		Signature: metadata.Signature,
+		Path:      path,
 	}
 
 	// Add or replace metadata. If replacing, record the existing path so we
@@ -83,7 +84,7 @@ func (s *binaryStorage) Add(r io.Reader, metadata Metadata) (resultErr error) {
 		// On the first attempt we assume we're adding new binary files.
 		// Subsequent attempts to add files will fetch the existing
 		// doc, record the old path, and attempt to update the
-		// size, path and hash fields.
+		// size, path, hash and signature fields.
 		if attempt == 0 {
 			op.Assert = txn.DocMissing
 			op.Insert = &newDoc
@@ -94,11 +95,12 @@ func (s *binaryStorage) Add(r io.Reader, metadata Metadata) (resultErr error) {
 			}
 			oldPath = oldDoc.Path
 			op.Assert = bson.D{{"path", oldPath}}
-			if oldPath != path {
+			if oldPath != path || oldDoc.Signature != metadata.Signature {
 				op.Update = bson.D{{
 					"$set", bson.D{
 						{"size", metadata.Size},
 						{"sha256", metadata.SHA256},
+						{"signature", metadata.Signature},
 						{"path", path},
 					},
 				}}
@@ -133,9 +135,10 @@ func (s *binaryStorage) Open(version string) (Metadata, io.ReadCloser, error) {
 		return Metadata{}, nil, err
 	}
 	metadata := Metadata{
-		Version: metadataDoc.Version,
-		Size:    metadataDoc.Size,
-		SHA256:  metadataDoc.SHA256,
+		Version:   metadataDoc.Version,
+		Size:      metadataDoc.Size,
+		SHA256:    metadataDoc.SHA256,
+		Signature: metadataDoc.Signature,
 	}
 	return metadata, r, nil
 }
@@ -146,9 +149,10 @@ func (s *binaryStorage) Metadata(version string) (Metadata, error) {
 		return Metadata{}, err
 	}
 	return Metadata{
-		Version: metadataDoc.Version,
-		Size:    metadataDoc.Size,
-		SHA256:  metadataDoc.SHA256,
+		Version:   metadataDoc.Version,
+		Size:      metadataDoc.Size,
+		SHA256:    metadataDoc.SHA256,
+		Signature: metadataDoc.Signature,
 	}, nil
 }
 
@@ -160,20 +164,22 @@ func (s *binaryStorage) AllMetadata() ([]Metadata, error) {
 	list := make([]Metadata, len(docs))
 	for i, doc := range docs {
 		list[i] = Metadata{
-			Version: doc.Version,
-			Size:    doc.Size,
-			SHA256:  doc.SHA256,
+			Version:   doc.Version,
+			Size:      doc.Size,
+			SHA256:    doc.SHA256,
+			Signature: doc.Signature,
 		}
 	}
 	return list, nil
 }
 
 type metadataDoc struct {
-	Id      string `bson:"_id"`
-	Version string `bson:"version"`
-	Size    int64  `bson:"size"`
-	SHA256  string `bson:"sha256,omitempty"`
-	Path    string `bson:"path"`
+	Id        string `bson:"_id"`
+	Version   string `bson:"version"`
+	Size      int64  `bson:"size"`
+	SHA256    string `bson:"sha256,omitempty"`
+	Signature string `bson:"signature,omitempty"`
+	Path      string `bson:"path"`
 }
 
 func (s *binaryStorage) findMetadata(version string) (metadataDoc, error) {
@@ -278,6 +278,21 @@ func (s *binaryStorageSuite) TestAddSame(c *gc.C) {
 	}
 }
 
+func (s *binaryStorageSuite) TestAddSameSigned(c *gc.C) {
+	metadata := binarystorage.Metadata{Version: current, Size: 1, SHA256: "0"}
+	err := s.storage.Add(strings.NewReader("0"), metadata)
+	c.Assert(err, jc.ErrorIsNil)
+
+	metadata.Signature = "signature(0)"
+	err = s.storage.Add(strings.NewReader("0"), metadata)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMetadataAndContent(c, metadata, "0")
+
+	all, err := s.storage.AllMetadata()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, jc.DeepEquals, []binarystorage.Metadata{metadata})
+}
+
 func (s *binaryStorageSuite) TestAddConcurrent(c *gc.C) {
 	metadata0 := binarystorage.Metadata{Version: current, Size: 1, SHA256: "0"}
 	metadata1 := binarystorage.Metadata{Version: current, Size: 1, SHA256: "1"}
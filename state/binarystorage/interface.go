@@ -12,6 +12,10 @@ type Metadata struct {
 	Version string
 	Size    int64
 	SHA256  string
+
+	// Signature holds the armored detached OpenPGP signature of
+	// the binary file, if it was signed.
+	Signature string
 }
 
 // Storage provides methods for storing and retrieving binary files by version.
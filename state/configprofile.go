@@ -0,0 +1,218 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/environs/config"
+)
+
+// ConfigProfile is a named set of model config values, stored on the
+// controller, that may be applied to models as they are added.
+type ConfigProfile struct {
+	// Name is the name of the profile.
+	Name string
+
+	// Config holds the model config values the profile sets.
+	Config map[string]interface{}
+}
+
+// configProfileDoc is the mongo document representation of a
+// ConfigProfile. The same representation is used both for the profiles
+// stored on the controller, keyed on profile name, and for the profile
+// applied to a model, which records the values it set.
+type configProfileDoc struct {
+	DocID     string      `bson:"_id"`
+	ModelUUID string      `bson:"model-uuid,omitempty"`
+	Name      string      `bson:"name"`
+	Config    settingsMap `bson:"config"`
+}
+
+func (doc configProfileDoc) profile() ConfigProfile {
+	return ConfigProfile{
+		Name:   doc.Name,
+		Config: copyMap(doc.Config, nil),
+	}
+}
+
+// modelConfigProfileKey is the key of the document recording the
+// profile applied to a model.
+const modelConfigProfileKey = "profile"
+
+var validConfigProfileName = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+
+// IsValidConfigProfileName reports whether name is a valid config
+// profile name.
+func IsValidConfigProfileName(name string) bool {
+	return validConfigProfileName.MatchString(name)
+}
+
+// validateConfigProfile returns an error if the profile cannot be
+// stored. The types of values are checked when the profile is applied
+// to a model, along with the rest of the model's config.
+func validateConfigProfile(name string, attrs map[string]interface{}) error {
+	if !IsValidConfigProfileName(name) {
+		return errors.NotValidf("config profile name %q", name)
+	}
+	if len(attrs) == 0 {
+		return errors.NotValidf("empty config profile")
+	}
+	for attr := range attrs {
+		switch attr {
+		case config.NameKey, config.UUIDKey, config.TypeKey, config.AgentVersionKey:
+			return errors.NotValidf("config profile setting %q", attr)
+		}
+		if controller.ControllerOnlyAttribute(attr) {
+			return errors.NotValidf("config profile setting controller attribute %q", attr)
+		}
+	}
+	for _, attr := range disallowedModelConfigAttrs {
+		if _, ok := attrs[attr]; ok {
+			return errors.NotValidf("config profile setting %q", attr)
+		}
+	}
+	return nil
+}
+
+// AddConfigProfile stores a new config profile on the controller.
+func (st *State) AddConfigProfile(name string, attrs map[string]interface{}) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add config profile %q", name)
+	if err := validateConfigProfile(name, attrs); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      configProfilesC,
+		Id:     name,
+		Assert: txn.DocMissing,
+		Insert: &configProfileDoc{
+			DocID:  name,
+			Name:   name,
+			Config: copyMap(attrs, escapeReplacer.Replace),
+		},
+	}}
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.AlreadyExistsf("config profile %q", name)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// UpdateConfigProfile replaces the values set by the named config
+// profile. Models that the profile was applied to are not changed.
+func (st *State) UpdateConfigProfile(name string, attrs map[string]interface{}) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot update config profile %q", name)
+	if err := validateConfigProfile(name, attrs); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      configProfilesC,
+		Id:     name,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{
+			{"config", copyMap(attrs, escapeReplacer.Replace)},
+		}}},
+	}}
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("config profile %q", name)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// RemoveConfigProfile removes the named config profile from the
+// controller. Models that the profile was applied to are not changed.
+func (st *State) RemoveConfigProfile(name string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot remove config profile %q", name)
+	ops := []txn.Op{{
+		C:      configProfilesC,
+		Id:     name,
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("config profile %q", name)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// ConfigProfile returns the named config profile.
+func (st *State) ConfigProfile(name string) (ConfigProfile, error) {
+	coll, closer := st.db().GetCollection(configProfilesC)
+	defer closer()
+	var doc configProfileDoc
+	if err := coll.FindId(name).One(&doc); err == mgo.ErrNotFound {
+		return ConfigProfile{}, errors.NotFoundf("config profile %q", name)
+	} else if err != nil {
+		return ConfigProfile{}, errors.Annotatef(err, "cannot get config profile %q", name)
+	}
+	return doc.profile(), nil
+}
+
+// ConfigProfiles returns all the config profiles stored on the
+// controller, sorted by name.
+func (st *State) ConfigProfiles() ([]ConfigProfile, error) {
+	coll, closer := st.db().GetCollection(configProfilesC)
+	defer closer()
+	var docs []configProfileDoc
+	if err := coll.Find(nil).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get config profiles")
+	}
+	profiles := make([]ConfigProfile, len(docs))
+	for i, doc := range docs {
+		profiles[i] = doc.profile()
+	}
+	return profiles, nil
+}
+
+// ConfigProfile returns the config profile that was applied to the
+// model when it was added, holding only the values the profile set.
+// It returns a NotFound error if no profile was applied.
+func (m *Model) ConfigProfile() (ConfigProfile, error) {
+	return m.st.appliedConfigProfile()
+}
+
+func (st *State) appliedConfigProfile() (ConfigProfile, error) {
+	coll, closer := st.db().GetCollection(modelConfigProfileC)
+	defer closer()
+	var doc configProfileDoc
+	if err := coll.FindId(modelConfigProfileKey).One(&doc); err == mgo.ErrNotFound {
+		return ConfigProfile{}, errors.NotFoundf("config profile for model")
+	} else if err != nil {
+		return ConfigProfile{}, errors.Annotate(err, "cannot get model config profile")
+	}
+	return doc.profile(), nil
+}
+
+// createModelConfigProfileOp returns the op that records the profile
+// applied to a new model, along with the values of the model config
+// that the profile set.
+func createModelConfigProfileOp(st *State, profile ConfigProfile, modelCfg map[string]interface{}) txn.Op {
+	applied := make(map[string]interface{})
+	for attr := range profile.Config {
+		if value, ok := modelCfg[attr]; ok {
+			applied[attr] = value
+		}
+	}
+	return txn.Op{
+		C:      modelConfigProfileC,
+		Id:     modelConfigProfileKey,
+		Assert: txn.DocMissing,
+		Insert: &configProfileDoc{
+			DocID:  st.docID(modelConfigProfileKey),
+			Name:   profile.Name,
+			Config: copyMap(applied, escapeReplacer.Replace),
+		},
+	}
+}
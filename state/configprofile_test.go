@@ -0,0 +1,174 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/testing"
+)
+
+type ConfigProfileSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&ConfigProfileSuite{})
+
+func (s *ConfigProfileSuite) TestAddConfigProfile(c *gc.C) {
+	err := s.State.AddConfigProfile("hardened", map[string]interface{}{
+		"automatically-retry-hooks": false,
+		"logging-config":            "<root>=WARNING",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AddConfigProfile("dev", map[string]interface{}{
+		"development": true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	profile, err := s.State.ConfigProfile("hardened")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profile, jc.DeepEquals, state.ConfigProfile{
+		Name: "hardened",
+		Config: map[string]interface{}{
+			"automatically-retry-hooks": false,
+			"logging-config":            "<root>=WARNING",
+		},
+	})
+
+	profiles, err := s.State.ConfigProfiles()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profiles, gc.HasLen, 2)
+	c.Assert(profiles[0].Name, gc.Equals, "dev")
+	c.Assert(profiles[1].Name, gc.Equals, "hardened")
+}
+
+func (s *ConfigProfileSuite) TestAddConfigProfileExists(c *gc.C) {
+	attrs := map[string]interface{}{"development": true}
+	err := s.State.AddConfigProfile("dev", attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AddConfigProfile("dev", attrs)
+	c.Assert(err, gc.ErrorMatches, `cannot add config profile "dev": config profile "dev" already exists`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *ConfigProfileSuite) TestAddConfigProfileInvalid(c *gc.C) {
+	for i, test := range []struct {
+		name  string
+		attrs map[string]interface{}
+		err   string
+	}{{
+		name:  "Bad_Name",
+		attrs: map[string]interface{}{"development": true},
+		err:   `config profile name "Bad_Name" not valid`,
+	}, {
+		name: "empty",
+		err:  `empty config profile not valid`,
+	}, {
+		name:  "named",
+		attrs: map[string]interface{}{"name": "foo"},
+		err:   `config profile setting "name" not valid`,
+	}, {
+		name:  "controller",
+		attrs: map[string]interface{}{"api-port": 1234},
+		err:   `config profile setting controller attribute "api-port" not valid`,
+	}} {
+		c.Logf("test %d: %s", i, test.name)
+		err := s.State.AddConfigProfile(test.name, test.attrs)
+		c.Check(err, gc.ErrorMatches, `cannot add config profile ".*": `+test.err)
+	}
+}
+
+func (s *ConfigProfileSuite) TestUpdateConfigProfile(c *gc.C) {
+	err := s.State.AddConfigProfile("dev", map[string]interface{}{"development": true})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.UpdateConfigProfile("dev", map[string]interface{}{"test-mode": true})
+	c.Assert(err, jc.ErrorIsNil)
+	profile, err := s.State.ConfigProfile("dev")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profile.Config, jc.DeepEquals, map[string]interface{}{"test-mode": true})
+
+	err = s.State.UpdateConfigProfile("missing", map[string]interface{}{"test-mode": true})
+	c.Assert(err, gc.ErrorMatches, `cannot update config profile "missing": config profile "missing" not found`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ConfigProfileSuite) TestRemoveConfigProfile(c *gc.C) {
+	err := s.State.AddConfigProfile("dev", map[string]interface{}{"development": true})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveConfigProfile("dev")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.ConfigProfile("dev")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.RemoveConfigProfile("dev")
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ConfigProfileSuite) TestNewModelRecordsConfigProfile(c *gc.C) {
+	uuid, err := utils.NewUUID()
+	c.Assert(err, jc.ErrorIsNil)
+	// The profile's development value was overridden when the model
+	// was added, so only apt-mirror was applied.
+	cfg := testing.CustomModelConfig(c, testing.Attrs{
+		"name":        "another",
+		"uuid":        uuid.String(),
+		"apt-mirror":  "http://profile-mirror",
+		"development": true,
+	})
+	model, st, err := s.State.NewModel(state.ModelArgs{
+		Type:                    state.ModelTypeIAAS,
+		Config:                  cfg,
+		Owner:                   names.NewUserTag("test@remote"),
+		CloudName:               "dummy",
+		CloudRegion:             "dummy-region",
+		StorageProviderRegistry: storage.StaticProviderRegistry{},
+		ConfigProfile: &state.ConfigProfile{
+			Name:   "hardened",
+			Config: map[string]interface{}{"apt-mirror": "http://profile-mirror"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	profile, err := model.ConfigProfile()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profile, jc.DeepEquals, state.ConfigProfile{
+		Name:   "hardened",
+		Config: map[string]interface{}{"apt-mirror": "http://profile-mirror"},
+	})
+
+	values, err := model.ModelConfigValues()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(values["apt-mirror"].Source, gc.Equals, "profile:hardened")
+	c.Assert(values["development"].Source, gc.Equals, "model")
+
+	// Once the value is changed, it no longer comes from the profile;
+	// once it is unset, the profile's value is restored.
+	err = model.UpdateModelConfig(map[string]interface{}{"apt-mirror": "http://other-mirror"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	values, err = model.ModelConfigValues()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(values["apt-mirror"].Source, gc.Equals, "model")
+
+	err = model.UpdateModelConfig(nil, []string{"apt-mirror"})
+	c.Assert(err, jc.ErrorIsNil)
+	values, err = model.ModelConfigValues()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(values["apt-mirror"], jc.DeepEquals, config.ConfigValue{
+		Value:  "http://profile-mirror",
+		Source: "profile:hardened",
+	})
+}
+
+func (s *ConfigProfileSuite) TestModelWithoutConfigProfile(c *gc.C) {
+	_, err := s.IAASModel.ConfigProfile()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
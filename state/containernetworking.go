@@ -4,14 +4,11 @@
 package state
 
 import (
-	"fmt"
-	"net"
-	"strings"
-
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/network"
 )
 
 // AutoConfigureContainerNetworking tries to set up best container networking available
@@ -25,15 +22,17 @@ func (m *Model) AutoConfigureContainerNetworking(environ environs.Environ) error
 	if err != nil {
 		return err
 	}
+	if modelConfig.ContainerNetworkingMethod() != "" {
+		// Do nothing, user has decided what to do
+		return nil
+	}
 	updateAttrs := make(map[string]interface{})
 	fanConfigured, err := m.discoverFan(netEnviron, modelConfig, updateAttrs)
 	if err != nil {
 		return err
 	}
 
-	if modelConfig.ContainerNetworkingMethod() != "" {
-		// Do nothing, user has decided what to do
-	} else if ok, _ := netEnviron.SupportsContainerAddresses(); ok {
+	if ok, _ := netEnviron.SupportsContainerAddresses(); ok {
 		updateAttrs["container-networking-method"] = "provider"
 	} else if fanConfigured {
 		updateAttrs["container-networking-method"] = "fan"
@@ -44,6 +43,9 @@ func (m *Model) AutoConfigureContainerNetworking(environ environs.Environ) error
 	return err
 }
 
+// discoverFan computes a fan configuration from the provider's super
+// subnets, with overlays that clash with neither the model's egress
+// subnets nor its no-proxy addresses, and adds it to updateAttrs.
 func (m *Model) discoverFan(netEnviron environs.NetworkingEnviron, modelConfig *config.Config, updateAttrs map[string]interface{}) (bool, error) {
 	fanConfig, err := modelConfig.FanConfig()
 	if err != nil {
@@ -51,7 +53,7 @@ func (m *Model) discoverFan(netEnviron environs.NetworkingEnviron, modelConfig *
 	}
 	if len(fanConfig) != 0 {
 		logger.Debugf("Not trying to autoconfigure FAN - configured already")
-		return false, nil
+		return true, nil
 	}
 	subnets, err := netEnviron.SuperSubnets()
 	if errors.IsNotSupported(err) || (err == nil && len(subnets) == 0) {
@@ -61,32 +63,20 @@ func (m *Model) discoverFan(netEnviron environs.NetworkingEnviron, modelConfig *
 	if err != nil {
 		return false, err
 	}
-	var outputTable []string
-
-	fanOverlays := []string{"252.0.0.0/8", "253.0.0.0/8", "254.0.0.0/8", "250.0.0.0/8", "251.0.0.0/8"}
-	fanOverlayForUnderlay := func(underlay string) string {
-		_, ipNet, err := net.ParseCIDR(underlay)
-		if err != nil {
-			return ""
-		}
-		if ones, _ := ipNet.Mask.Size(); ones <= 8 {
-			return ""
-		}
-		if len(fanOverlays) == 0 {
-			return ""
-		}
-		var overlay string
-		overlay, fanOverlays = fanOverlays[0], fanOverlays[1:]
-		return overlay
+	avoid := modelConfig.EgressSubnets()
+	noProxy := modelConfig.NoProxyEntries()
+	for _, ip := range noProxy.IPs {
+		avoid = append(avoid, ip.String())
 	}
-	for _, subnet := range subnets {
-		overlay := fanOverlayForUnderlay(subnet)
-		if overlay != "" {
-			outputTable = append(outputTable, fmt.Sprintf("%s=%s", subnet, overlay))
-		}
+	for _, ipNet := range noProxy.CIDRs {
+		avoid = append(avoid, ipNet.String())
+	}
+	fanConfig, err = network.ComputeFanConfig(subnets, avoid)
+	if err != nil {
+		return false, errors.Annotate(err, "computing fan configuration")
 	}
-	if len(outputTable) > 0 {
-		updateAttrs["fan-config"] = strings.Join(outputTable, " ")
+	if len(fanConfig) > 0 {
+		updateAttrs["fan-config"] = fanConfig.String()
 		return true, nil
 	}
 	return false, nil
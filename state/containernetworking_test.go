@@ -107,3 +107,59 @@ func (s *ContainerNetworkingSuite) TestAutoConfigureContainerNetworkingDefault(c
 	c.Check(attrs["container-networking-method"], gc.Equals, "fan")
 	c.Check(attrs["fan-config"], gc.Equals, "172.31.0.0/16=252.0.0.0/8 192.168.1.0/24=253.0.0.0/8")
 }
+
+func (s *ContainerNetworkingSuite) TestAutoConfigureContainerNetworkingMethodSet(c *gc.C) {
+	environ := containerTestNetworkedEnviron{
+		stub:         &testing.Stub{},
+		superSubnets: []string{"172.31.0.0/16"},
+	}
+	err := s.IAASModel.UpdateModelConfig(map[string]interface{}{
+		"container-networking-method": "local",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.Model.AutoConfigureContainerNetworking(&environ)
+	c.Check(err, jc.ErrorIsNil)
+	environ.stub.CheckNoCalls(c)
+	config, err := s.Model.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	attrs := config.AllAttrs()
+	c.Check(attrs["container-networking-method"], gc.Equals, "local")
+	c.Check(attrs["fan-config"], gc.Equals, "")
+}
+
+func (s *ContainerNetworkingSuite) TestAutoConfigureContainerNetworkingFanConfigSet(c *gc.C) {
+	environ := containerTestNetworkedEnviron{
+		stub:         &testing.Stub{},
+		superSubnets: []string{"172.31.0.0/16"},
+	}
+	err := s.IAASModel.UpdateModelConfig(map[string]interface{}{
+		"fan-config": "10.0.0.0/16=250.0.0.0/8",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.Model.AutoConfigureContainerNetworking(&environ)
+	c.Check(err, jc.ErrorIsNil)
+	config, err := s.Model.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	attrs := config.AllAttrs()
+	c.Check(attrs["container-networking-method"], gc.Equals, "fan")
+	c.Check(attrs["fan-config"], gc.Equals, "10.0.0.0/16=250.0.0.0/8")
+}
+
+func (s *ContainerNetworkingSuite) TestAutoConfigureContainerNetworkingAvoidsClashes(c *gc.C) {
+	environ := containerTestNetworkedEnviron{
+		stub:         &testing.Stub{},
+		superSubnets: []string{"172.31.0.0/16", "172.31.0.0/20", "172.31.16.0/20", "192.168.1.0/24"},
+	}
+	err := s.IAASModel.UpdateModelConfig(map[string]interface{}{
+		"egress-subnets": "252.0.0.0/16",
+		"no-proxy":       "localhost,253.0.0.1",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.Model.AutoConfigureContainerNetworking(&environ)
+	c.Check(err, jc.ErrorIsNil)
+	config, err := s.Model.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	attrs := config.AllAttrs()
+	c.Check(attrs["container-networking-method"], gc.Equals, "fan")
+	c.Check(attrs["fan-config"], gc.Equals, "172.31.0.0/16=254.0.0.0/8 192.168.1.0/24=250.0.0.0/8")
+}
@@ -43,6 +43,7 @@ type Controller struct {
 	policy                 Policy
 	newPolicy              NewPolicyFunc
 	runTransactionObserver RunTransactionObserverFunc
+	databaseBackend        string
 }
 
 // Close the connection to the database.
@@ -63,6 +64,7 @@ func (ctlr *Controller) NewState(modelTag names.ModelTag) (*State, error) {
 		ctlr.newPolicy,
 		ctlr.clock,
 		ctlr.runTransactionObserver,
+		ctlr.databaseBackend,
 	)
 	if err != nil {
 		return nil, errors.Trace(err)
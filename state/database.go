@@ -6,6 +6,7 @@ package state
 import (
 	"fmt"
 	"runtime/debug"
+	"time"
 
 	"github.com/juju/errors"
 	jujutxn "github.com/juju/txn"
@@ -91,6 +92,10 @@ type Database interface {
 	// Schema returns the schema used to load the database. The returned schema
 	// is not a copy and must not be modified.
 	Schema() collectionSchema
+
+	// DocumentStore returns the store from which the documents of the
+	// settings, status and annotations collections are read.
+	DocumentStore() documentStore
 }
 
 // Change represents any mgo/txn-representable change to a Database.
@@ -236,21 +241,36 @@ type database struct {
 	// runTransactionObserver is passed on to txn.TransactionRunner, to be
 	// invoked after calls to Run and RunTransaction.
 	runTransactionObserver RunTransactionObserverFunc
+
+	// newDocumentStore creates the store returned by DocumentStore. If
+	// nil, documents are read from MongoDB.
+	newDocumentStore newDocumentStoreFunc
+
+	// slowTxns, if non-nil, supplies the delay imposed on each
+	// transaction by a FaultSlowTransactions fault.
+	slowTxns *slowTxnFault
 }
 
 // RunTransactionObserverFunc is the type of a function to be called
-// after an mgo/txn transaction is run.
-type RunTransactionObserverFunc func(dbName, modelUUID string, ops []txn.Op, err error)
+// after an mgo/txn transaction is run. The duration is the time taken
+// to run the transaction, from when its operations were ready.
+type RunTransactionObserverFunc func(dbName, modelUUID string, ops []txn.Op, duration time.Duration, err error)
 
 func (db *database) copySession(modelUUID string) (*database, SessionCloser) {
 	session := db.raw.Session.Copy()
-	return &database{
-		raw:        db.raw.With(session),
-		schema:     db.schema,
-		modelUUID:  modelUUID,
-		runner:     db.runner,
-		ownSession: true,
-	}, session.Close
+	copied := &database{
+		raw:              db.raw.With(session),
+		schema:           db.schema,
+		modelUUID:        modelUUID,
+		runner:           db.runner,
+		ownSession:       true,
+		newDocumentStore: db.newDocumentStore,
+	}
+	if modelUUID == db.modelUUID {
+		// The cached fault only applies to the model it was read for.
+		copied.slowTxns = db.slowTxns
+	}
+	return copied, session.Close
 }
 
 // Copy is part of the Database interface.
@@ -318,6 +338,7 @@ func (db *database) GetRawCollection(name string) (*mgo.Collection, SessionClose
 func (db *database) TransactionRunner() (runner jujutxn.Runner, closer SessionCloser) {
 	runner = db.runner
 	closer = dontCloseAnything
+	var attemptStarted func()
 	if runner == nil {
 		raw := db.raw
 		if !db.ownSession {
@@ -327,10 +348,18 @@ func (db *database) TransactionRunner() (runner jujutxn.Runner, closer SessionCl
 		}
 		var observer func([]txn.Op, error)
 		if db.runTransactionObserver != nil {
+			// The runner is used for a single Run or
+			// RunTransaction call, whose attempts are made
+			// one after another, so they can share a start
+			// time.
+			var start time.Time
+			attemptStarted = func() {
+				start = time.Now()
+			}
 			observer = func(ops []txn.Op, err error) {
 				db.runTransactionObserver(
 					db.raw.Name, db.modelUUID,
-					ops, err,
+					ops, time.Since(start), err,
 				)
 			}
 		}
@@ -340,11 +369,17 @@ func (db *database) TransactionRunner() (runner jujutxn.Runner, closer SessionCl
 		}
 		runner = jujutxn.NewRunner(params)
 	}
-	return &multiModelRunner{
-		rawRunner: runner,
-		modelUUID: db.modelUUID,
-		schema:    db.schema,
-	}, closer
+	multiRunner := &multiModelRunner{
+		rawRunner:      runner,
+		modelUUID:      db.modelUUID,
+		schema:         db.schema,
+		attemptStarted: attemptStarted,
+	}
+	if db.slowTxns != nil {
+		multiRunner.delay = db.slowTxns.delay(db)
+		multiRunner.clock = db.slowTxns.clock()
+	}
+	return multiRunner, closer
 }
 
 // RunTransaction is part of the Database interface.
@@ -384,3 +419,11 @@ func (db *database) Run(transactions jujutxn.TransactionSource) error {
 func (db *database) Schema() collectionSchema {
 	return db.schema
 }
+
+// DocumentStore is part of the Database interface.
+func (db *database) DocumentStore() documentStore {
+	if db.newDocumentStore == nil {
+		return newMongoDocumentStore(db)
+	}
+	return db.newDocumentStore(db)
+}
@@ -0,0 +1,182 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/controller"
+)
+
+// documentStore reads the documents of the collections that may be
+// held by a database backend other than MongoDB: for now, the settings,
+// status and annotations collections. A documentStore is scoped to the
+// model of the Database it was obtained from.
+//
+// Changes to the documents are still expressed as txn.Ops on the
+// collections, so that they can be made in the same transaction as
+// changes to the documents they describe.
+type documentStore interface {
+	// readSettingsDoc reads the document with the given key in the
+	// named settings collection into out. It returns a NotFound error
+	// if there is no such document.
+	readSettingsDoc(collection, key string, out interface{}) error
+
+	// listSettingsDocs returns the documents in the named settings
+	// collection whose ids start with the supplied prefix. Unlike
+	// keys, ids include the model UUID.
+	listSettingsDocs(collection, idPrefix string) ([]settingsDoc, error)
+
+	// readStatusDoc reads the status document with the given global
+	// key into out. It returns a NotFound error if there is no such
+	// document.
+	readStatusDoc(globalKey string, out interface{}) error
+
+	// listStatusDocs reads all of the model's status documents into
+	// out, which must be a pointer to a slice.
+	listStatusDocs(out interface{}) error
+
+	// readAnnotationsDoc returns the annotations document for the
+	// entity with the given global key. It returns a NotFound error if
+	// the entity has no annotations.
+	readAnnotationsDoc(globalKey string) (annotatorDoc, error)
+
+	// listAnnotationsDocs returns all of the model's annotations
+	// documents.
+	listAnnotationsDocs() ([]annotatorDoc, error)
+}
+
+// newDocumentStoreFunc returns a documentStore that reads documents
+// from the supplied Database's model.
+type newDocumentStoreFunc func(Database) documentStore
+
+// documentStores holds the functions that create a documentStore for
+// each supported database backend.
+var documentStores = map[string]newDocumentStoreFunc{
+	controller.MongoDatabaseBackend: newMongoDocumentStore,
+}
+
+// documentStoreFunc returns the function that creates a documentStore
+// for the named database backend; an empty name selects the default.
+func documentStoreFunc(backend string) (newDocumentStoreFunc, error) {
+	if backend == "" {
+		backend = controller.DefaultDatabaseBackend
+	}
+	newStore, ok := documentStores[backend]
+	if !ok {
+		return nil, errors.NotSupportedf("database backend %q", backend)
+	}
+	return newStore, nil
+}
+
+// resolveDatabaseBackend returns the database backend recorded in the
+// controller config. The controller config is always held in MongoDB,
+// so it is read directly from the session. If the controller config
+// has not been written yet, as during Initialize, the requested backend
+// is returned; a request that conflicts with the recorded backend is an
+// error.
+func resolveDatabaseBackend(session *mgo.Session, requested string) (string, error) {
+	var doc settingsDoc
+	err := session.DB(jujuDB).C(controllersC).FindId(controllerSettingsGlobalKey).One(&doc)
+	if err == mgo.ErrNotFound {
+		return requested, nil
+	} else if err != nil {
+		return "", errors.Annotate(err, "reading database backend")
+	}
+	recorded, _ := doc.Settings[controller.DatabaseBackend].(string)
+	if recorded == "" {
+		// Controllers bootstrapped before the backend was
+		// recorded all use the default.
+		recorded = controller.DefaultDatabaseBackend
+	}
+	if requested != "" && requested != recorded {
+		return "", errors.Errorf(
+			"controller uses database backend %q, not %q", recorded, requested)
+	}
+	return recorded, nil
+}
+
+// mongoDocumentStore is a documentStore that reads documents from
+// MongoDB.
+type mongoDocumentStore struct {
+	db Database
+}
+
+func newMongoDocumentStore(db Database) documentStore {
+	return mongoDocumentStore{db}
+}
+
+// readSettingsDoc is part of the documentStore interface.
+func (s mongoDocumentStore) readSettingsDoc(collection, key string, out interface{}) error {
+	settings, closer := s.db.GetCollection(collection)
+	defer closer()
+
+	err := settings.FindId(key).One(out)
+	if err == mgo.ErrNotFound {
+		err = errors.NotFoundf("settings")
+	}
+	return err
+}
+
+// listSettingsDocs is part of the documentStore interface.
+func (s mongoDocumentStore) listSettingsDocs(collection, idPrefix string) ([]settingsDoc, error) {
+	settings, closer := s.db.GetRawCollection(collection)
+	defer closer()
+
+	var docs []settingsDoc
+	findExpr := fmt.Sprintf("^%s.*$", idPrefix)
+	if err := settings.Find(bson.D{{"_id", bson.D{{"$regex", findExpr}}}}).All(&docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// readStatusDoc is part of the documentStore interface.
+func (s mongoDocumentStore) readStatusDoc(globalKey string, out interface{}) error {
+	statuses, closer := s.db.GetCollection(statusesC)
+	defer closer()
+
+	err := statuses.FindId(globalKey).One(out)
+	if err == mgo.ErrNotFound {
+		return errors.NotFoundf("status for %q", globalKey)
+	}
+	return errors.Trace(err)
+}
+
+// listStatusDocs is part of the documentStore interface.
+func (s mongoDocumentStore) listStatusDocs(out interface{}) error {
+	statuses, closer := s.db.GetCollection(statusesC)
+	defer closer()
+
+	return errors.Trace(statuses.Find(nil).All(out))
+}
+
+// readAnnotationsDoc is part of the documentStore interface.
+func (s mongoDocumentStore) readAnnotationsDoc(globalKey string) (annotatorDoc, error) {
+	annotations, closer := s.db.GetCollection(annotationsC)
+	defer closer()
+
+	var doc annotatorDoc
+	err := annotations.FindId(globalKey).One(&doc)
+	if err == mgo.ErrNotFound {
+		return annotatorDoc{}, errors.NotFoundf("annotations for %q", globalKey)
+	}
+	return doc, errors.Trace(err)
+}
+
+// listAnnotationsDocs is part of the documentStore interface.
+func (s mongoDocumentStore) listAnnotationsDocs() ([]annotatorDoc, error) {
+	annotations, closer := s.db.GetCollection(annotationsC)
+	defer closer()
+
+	var docs []annotatorDoc
+	if err := annotations.Find(nil).All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return docs, nil
+}
@@ -13,6 +13,7 @@ import (
 	"gopkg.in/mgo.v2/txn"
 
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 )
 
 // defaultEndpointName is the key in the bindings map that stores the
@@ -141,8 +142,17 @@ func mergeBindings(newMap, oldMap map[string]string, meta *charm.Meta) (map[stri
 
 // createEndpointBindingsOp returns the op needed to create new endpoint
 // bindings using the optional givenMap and the specified charm metadata to for
-// determining defaults and to validate the effective bindings.
+// determining defaults and to validate the effective bindings. Endpoints not
+// in givenMap are bound to its default binding if it has one, or to the
+// model's default-space otherwise.
 func createEndpointBindingsOp(st *State, key string, givenMap map[string]string, meta *charm.Meta) (txn.Op, error) {
+	if _, ok := givenMap[defaultEndpointName]; !ok {
+		var err error
+		givenMap, err = withModelDefaultSpace(st, givenMap)
+		if err != nil {
+			return txn.Op{}, errors.Trace(err)
+		}
+	}
 
 	// No existing map to merge, just use the defaults.
 	initialMap, _, err := mergeBindings(givenMap, nil, meta)
@@ -165,6 +175,39 @@ func createEndpointBindingsOp(st *State, key string, givenMap map[string]string,
 	}, nil
 }
 
+// withModelDefaultSpace returns the given bindings with the model's
+// default-space, if it is set, as their default binding.
+func withModelDefaultSpace(st *State, givenMap map[string]string) (map[string]string, error) {
+	cfg, err := getModelConfig(st.db())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	space := cfg.DefaultSpace()
+	if space == environs.DefaultSpaceName {
+		return givenMap, nil
+	}
+	result := map[string]string{defaultEndpointName: space}
+	for endpoint, name := range givenMap {
+		result[endpoint] = name
+	}
+	return result, nil
+}
+
+// validateDefaultSpace checks that the model's default-space, if it
+// has been changed from the old config, names a known space.
+func (st *State) validateDefaultSpace(cfg, old *config.Config) error {
+	space := cfg.DefaultSpace()
+	if space == environs.DefaultSpaceName || (old != nil && old.DefaultSpace() == space) {
+		return nil
+	}
+	if _, err := st.Space(space); errors.IsNotFound(err) {
+		return errors.NotValidf("%s %q: unknown space", config.DefaultSpaceKey, space)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
 // updateEndpointBindingsOp returns an op that merges the existing bindings with
 // givenMap, using newMeta to validate the merged bindings, and asserting the
 // existing ones haven't changed in the since we fetched them.
@@ -0,0 +1,365 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"github.com/juju/utils/set"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/instance"
+)
+
+// MachineEvacuation describes the evacuation of a machine's units, so
+// that the machine can be removed.
+type MachineEvacuation struct {
+	// Machine is the id of the machine being evacuated.
+	Machine string
+
+	// Moves holds the names of the units that are being moved off the
+	// machine with MoveUnit.
+	Moves []string
+
+	// Manual holds the units that must be moved off the machine by
+	// hand.
+	Manual []EvacuatingUnit
+
+	// Started is when the evacuation was started.
+	Started time.Time
+
+	// Done reports whether all the units have left the machine, and
+	// the machine has been destroyed.
+	Done bool
+}
+
+// EvacuatingUnit describes a unit that could not be moved off an
+// evacuating machine automatically.
+type EvacuatingUnit struct {
+	// Unit is the name of the unit.
+	Unit string
+
+	// Storage holds the ids of the storage instances attached to the
+	// unit. Units with storage are stateful, and are never moved
+	// automatically.
+	Storage []string
+
+	// Reason says why the unit must be moved by hand.
+	Reason string
+}
+
+// machineEvacuationDoc records the evacuation of a machine. It is
+// keyed by the id of the machine, and kept once the evacuation is done
+// so that its outcome can be reported.
+type machineEvacuationDoc struct {
+	DocID     string              `bson:"_id"`
+	ModelUUID string              `bson:"model-uuid"`
+	Machine   string              `bson:"machine"`
+	Moves     []string            `bson:"moves,omitempty"`
+	Manual    []evacuatingUnitDoc `bson:"manual,omitempty"`
+	Started   int64               `bson:"started"`
+	Done      bool                `bson:"done"`
+}
+
+type evacuatingUnitDoc struct {
+	Unit    string   `bson:"unit"`
+	Storage []string `bson:"storage,omitempty"`
+	Reason  string   `bson:"reason"`
+}
+
+func (doc *machineEvacuationDoc) evacuation() MachineEvacuation {
+	evacuation := MachineEvacuation{
+		Machine: doc.Machine,
+		Moves:   doc.Moves,
+		Started: time.Unix(0, doc.Started).UTC(),
+		Done:    doc.Done,
+	}
+	for _, unit := range doc.Manual {
+		evacuation.Manual = append(evacuation.Manual, EvacuatingUnit{
+			Unit:    unit.Unit,
+			Storage: unit.Storage,
+			Reason:  unit.Reason,
+		})
+	}
+	return evacuation
+}
+
+// MachineEvacuation returns the evacuation of the machine with the
+// given id.
+func (st *State) MachineEvacuation(machineId string) (MachineEvacuation, error) {
+	doc, err := st.machineEvacuationDoc(machineId)
+	if err != nil {
+		return MachineEvacuation{}, errors.Trace(err)
+	}
+	return doc.evacuation(), nil
+}
+
+func (st *State) machineEvacuationDoc(machineId string) (*machineEvacuationDoc, error) {
+	coll, closer := st.db().GetCollection(machineEvacuationsC)
+	defer closer()
+
+	var doc machineEvacuationDoc
+	err := coll.FindId(machineId).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("evacuation of machine %s", machineId)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot read evacuation of machine %s", machineId)
+	}
+	return &doc, nil
+}
+
+// EvacuateMachine starts evacuating the units of the machine with the
+// given id, so that it can be removed for maintenance. Units without
+// storage are moved to the machines chosen by the placement directive,
+// or to new machines if placement is nil. Units with storage hold state
+// that juju cannot move for them, so they are left for the operator to
+// move by hand. The machine is destroyed by AdvanceMachineEvacuations
+// once all of its units have gone.
+//
+// Evacuating a machine whose evacuation was interrupted resumes it,
+// dealing with the units not yet moved or listed for moving by hand.
+// Units assigned to the machine while it is being evacuated are dealt
+// with too.
+func (st *State) EvacuateMachine(machineId string, placement *instance.Placement) (_ MachineEvacuation, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot evacuate machine %s", machineId)
+
+	im, err := st.IAASModel()
+	if err != nil {
+		return MachineEvacuation{}, errors.Trace(err)
+	}
+	m, err := st.Machine(machineId)
+	if err != nil {
+		return MachineEvacuation{}, errors.Trace(err)
+	}
+	if m.IsManager() {
+		return MachineEvacuation{}, errors.NotSupportedf("evacuating controller machines")
+	}
+	doc, err := st.startMachineEvacuation(m)
+	if err != nil {
+		return MachineEvacuation{}, errors.Trace(err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt >= maxEvacuationAttempts {
+			return MachineEvacuation{}, jujutxn.ErrExcessiveContention
+		}
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return MachineEvacuation{}, errors.Trace(err)
+			}
+		}
+		if m.Life() != Alive {
+			return MachineEvacuation{}, errors.New("machine is not alive")
+		}
+		handled := doc.units()
+		for _, unitName := range m.Principals() {
+			if handled.Contains(unitName) {
+				continue
+			}
+			if err := st.evacuateUnit(im, doc, unitName, placement); err != nil {
+				return MachineEvacuation{}, errors.Trace(err)
+			}
+		}
+
+		// The machine must not have gained units that were not
+		// dealt with.
+		handled = doc.units()
+		ops := []txn.Op{{
+			C:  machinesC,
+			Id: m.doc.DocID,
+			Assert: bson.D{
+				{"life", Alive},
+				{"principals", bson.D{{"$not", bson.D{{
+					"$elemMatch", bson.D{{"$nin", handled.Values()}},
+				}}}}},
+			},
+		}, {
+			C:      machineEvacuationsC,
+			Id:     machineId,
+			Assert: bson.D{{"done", false}},
+			Update: bson.D{{"$set", bson.D{
+				{"moves", doc.Moves},
+				{"manual", doc.Manual},
+			}}},
+		}}
+		err := st.db().RunTransaction(ops)
+		if err == nil {
+			return doc.evacuation(), nil
+		} else if err != txn.ErrAborted {
+			return MachineEvacuation{}, errors.Trace(err)
+		}
+	}
+}
+
+// maxEvacuationAttempts bounds the attempts to record the evacuation of
+// a machine's units while units are being assigned to it.
+const maxEvacuationAttempts = 3
+
+// startMachineEvacuation records the evacuation of the machine, or
+// returns the evacuation already recorded if it is not done. The
+// evacuation is recorded before any units are moved, so that units
+// removed quickly still leave the machine to be destroyed.
+func (st *State) startMachineEvacuation(m *Machine) (*machineEvacuationDoc, error) {
+	machineId := m.Id()
+	var doc *machineEvacuationDoc
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		existing, err := st.machineEvacuationDoc(machineId)
+		if err == nil {
+			if existing.Done {
+				return nil, errors.AlreadyExistsf("evacuation of machine %s", machineId)
+			}
+			doc = existing
+			return nil, jujutxn.ErrNoOperations
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		if m.Life() != Alive {
+			return nil, errors.New("machine is not alive")
+		}
+		containers, err := m.Containers()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(containers) > 0 {
+			return nil, errors.Errorf("machine hosts containers %v; evacuate them first", containers)
+		}
+		doc = &machineEvacuationDoc{
+			DocID:   machineId,
+			Machine: machineId,
+			Started: st.clock().Now().UnixNano(),
+		}
+		return []txn.Op{{
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: isAliveDoc,
+		}, {
+			C:      machineEvacuationsC,
+			Id:     machineId,
+			Assert: txn.DocMissing,
+			Insert: doc,
+		}}, nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return doc, nil
+}
+
+// evacuateUnit moves the unit off the evacuating machine, or lists it
+// to be moved by hand, and records which in the evacuation doc.
+func (st *State) evacuateUnit(im *IAASModel, doc *machineEvacuationDoc, unitName string, placement *instance.Placement) error {
+	unit, err := st.Unit(unitName)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	attachments, err := im.UnitStorageAttachments(unit.UnitTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(attachments) > 0 {
+		manual := evacuatingUnitDoc{Unit: unitName, Reason: "unit has storage"}
+		for _, attachment := range attachments {
+			manual.Storage = append(manual.Storage, attachment.StorageInstance().Id())
+		}
+		doc.Manual = append(doc.Manual, manual)
+		return nil
+	}
+	// An interrupted evacuation may already have started moving
+	// the unit.
+	if move, err := st.unitMoveDoc(unitName); err == nil && !UnitMovePhase(move.Phase).Finished() {
+		doc.Moves = append(doc.Moves, unitName)
+		return nil
+	} else if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	if _, err := st.MoveUnit(unitName, placement); err != nil {
+		doc.Manual = append(doc.Manual, evacuatingUnitDoc{Unit: unitName, Reason: err.Error()})
+		return nil
+	}
+	doc.Moves = append(doc.Moves, unitName)
+	return nil
+}
+
+// units returns the names of the units the evacuation has dealt with.
+func (doc *machineEvacuationDoc) units() set.Strings {
+	units := set.NewStrings(doc.Moves...)
+	for _, unit := range doc.Manual {
+		units.Add(unit.Unit)
+	}
+	return units
+}
+
+// WatchMachineEvacuations returns a NotifyWatcher that notifies of
+// changes to the model's machine evacuations.
+func (st *State) WatchMachineEvacuations() NotifyWatcher {
+	return newNotifyCollWatcher(st, machineEvacuationsC, isLocalID(st))
+}
+
+// AdvanceMachineEvacuations destroys each evacuating machine that no
+// longer hosts any units. It returns the number of evacuations that
+// are still waiting for units to leave their machines.
+func (st *State) AdvanceMachineEvacuations() (int, error) {
+	coll, closer := st.db().GetCollection(machineEvacuationsC)
+	defer closer()
+
+	var docs []machineEvacuationDoc
+	if err := coll.Find(bson.D{{"done", false}}).All(&docs); err != nil {
+		return 0, errors.Annotate(err, "cannot read machine evacuations")
+	}
+	active := 0
+	for _, doc := range docs {
+		done, err := st.advanceMachineEvacuation(doc.Machine)
+		if err != nil {
+			return 0, errors.Annotatef(err, "cannot advance evacuation of machine %s", doc.Machine)
+		}
+		if !done {
+			active++
+		}
+	}
+	return active, nil
+}
+
+// advanceMachineEvacuation destroys the evacuating machine if it no
+// longer hosts any units, and reports whether the evacuation is done.
+func (st *State) advanceMachineEvacuation(machineId string) (bool, error) {
+	m, err := st.Machine(machineId)
+	if errors.IsNotFound(err) {
+		return true, errors.Trace(st.finishMachineEvacuation(machineId))
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	if m.Life() == Alive {
+		if len(m.Principals()) > 0 {
+			return false, nil
+		}
+		if err := m.Destroy(); IsHasAssignedUnitsError(err) {
+			// A unit was assigned to the machine since it was
+			// read; it must be moved off too.
+			return false, nil
+		} else if err != nil {
+			return false, errors.Trace(err)
+		}
+	}
+	return true, errors.Trace(st.finishMachineEvacuation(machineId))
+}
+
+func (st *State) finishMachineEvacuation(machineId string) error {
+	return st.db().RunTransaction([]txn.Op{{
+		C:      machineEvacuationsC,
+		Id:     machineId,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"done", true}}}},
+	}})
+}
@@ -0,0 +1,190 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/testing/factory"
+)
+
+type MachineEvacuationSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&MachineEvacuationSuite{})
+
+func (s *MachineEvacuationSuite) advanceEvacuations(c *gc.C, expectActive int) {
+	active, err := s.State.AdvanceMachineEvacuations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(active, gc.Equals, expectActive)
+}
+
+func (s *MachineEvacuationSuite) TestEvacuateMachine(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, nil)
+	target := s.Factory.MakeMachine(c, nil)
+	app := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+	})
+	s.Factory.MakeUnit(c, &factory.UnitParams{Application: app, Machine: machine})
+
+	placement := &instance.Placement{Scope: instance.MachineScope, Directive: target.Id()}
+	evacuation, err := s.State.EvacuateMachine(machine.Id(), placement)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(evacuation.Machine, gc.Equals, machine.Id())
+	c.Assert(evacuation.Moves, jc.DeepEquals, []string{"wordpress/0"})
+	c.Assert(evacuation.Manual, gc.HasLen, 0)
+	c.Assert(evacuation.Done, jc.IsFalse)
+
+	move, err := s.State.UnitMove("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(move.Phase, gc.Equals, state.UnitMoveProvisioning)
+	s.advanceEvacuations(c, 1)
+
+	// Drive the move to completion.
+	replacement, err := s.State.Unit(move.Replacement)
+	c.Assert(err, jc.ErrorIsNil)
+	now := time.Now()
+	err = replacement.SetAgentStatus(status.StatusInfo{Status: status.Idle, Since: &now})
+	c.Assert(err, jc.ErrorIsNil)
+	for i := 0; i < 10 && !move.Phase.Finished(); i++ {
+		_, err := s.State.AdvanceUnitMoves()
+		c.Assert(err, jc.ErrorIsNil)
+		move, err = s.State.UnitMove("wordpress/0")
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(move.Phase, gc.Equals, state.UnitMoveDone)
+
+	s.advanceEvacuations(c, 0)
+	err = machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.Life(), gc.Equals, state.Dying)
+	evacuation, err = s.State.MachineEvacuation(machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(evacuation.Done, jc.IsTrue)
+
+	// The replacement is left on the target machine.
+	err = target.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(target.Life(), gc.Equals, state.Alive)
+	c.Assert(target.Principals(), jc.DeepEquals, []string{"wordpress/1"})
+}
+
+func (s *MachineEvacuationSuite) TestEvacuateMachineWithStorage(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, nil)
+	app := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{
+			Name: "storage-block",
+			URL:  "cs:quantal/storage-block-1",
+		}),
+		Storage: map[string]state.StorageConstraints{
+			"data": {Count: 1, Size: 1024, Pool: "modelscoped"},
+		},
+	})
+	s.Factory.MakeUnit(c, &factory.UnitParams{Application: app, Machine: machine})
+
+	evacuation, err := s.State.EvacuateMachine(machine.Id(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(evacuation.Moves, gc.HasLen, 0)
+	c.Assert(evacuation.Manual, jc.DeepEquals, []state.EvacuatingUnit{{
+		Unit:    "storage-block/0",
+		Storage: []string{"data/0"},
+		Reason:  "unit has storage",
+	}})
+	_, err = s.State.UnitMove("storage-block/0")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// The machine is kept until the unit has been moved by hand.
+	s.advanceEvacuations(c, 1)
+	err = machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.Life(), gc.Equals, state.Alive)
+}
+
+func (s *MachineEvacuationSuite) TestEvacuateEmptyMachine(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, nil)
+	evacuation, err := s.State.EvacuateMachine(machine.Id(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(evacuation.Moves, gc.HasLen, 0)
+	c.Assert(evacuation.Manual, gc.HasLen, 0)
+
+	s.advanceEvacuations(c, 0)
+	err = machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.Life(), gc.Equals, state.Dying)
+}
+
+func (s *MachineEvacuationSuite) TestEvacuateMachineResumes(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, nil)
+	app := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+	})
+	s.Factory.MakeUnit(c, &factory.UnitParams{Application: app, Machine: machine})
+	_, err := s.State.EvacuateMachine(machine.Id(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	first, err := s.State.UnitMove("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A unit assigned to the machine since is moved when the
+	// evacuation is run again; the unit already being moved is
+	// left alone.
+	s.Factory.MakeUnit(c, &factory.UnitParams{Application: app, Machine: machine})
+	evacuation, err := s.State.EvacuateMachine(machine.Id(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(evacuation.Moves, jc.DeepEquals, []string{"wordpress/0", "wordpress/2"})
+	c.Assert(evacuation.Done, jc.IsFalse)
+	again, err := s.State.UnitMove("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(again.Replacement, gc.Equals, first.Replacement)
+	_, err = s.State.UnitMove("wordpress/2")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MachineEvacuationSuite) TestEvacuateMachineUnitAssignedMeanwhile(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, nil)
+	app := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+	})
+	defer state.SetBeforeHooks(c, s.State, nil, func() {
+		s.Factory.MakeUnit(c, &factory.UnitParams{Application: app, Machine: machine})
+	}).Check()
+
+	evacuation, err := s.State.EvacuateMachine(machine.Id(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(evacuation.Moves, jc.DeepEquals, []string{"wordpress/0"})
+	_, err = s.State.UnitMove("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MachineEvacuationSuite) TestEvacuateMachineAlreadyEvacuated(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, nil)
+	_, err := s.State.EvacuateMachine(machine.Id(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.advanceEvacuations(c, 0)
+
+	_, err = s.State.EvacuateMachine(machine.Id(), nil)
+	c.Assert(err, gc.ErrorMatches, `cannot evacuate machine .*: evacuation of machine .* already exists`)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *MachineEvacuationSuite) TestEvacuateMachineWithContainers(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, nil)
+	container := s.Factory.MakeMachineNested(c, machine.Id(), nil)
+	_, err := s.State.EvacuateMachine(machine.Id(), nil)
+	c.Assert(err, gc.ErrorMatches, `cannot evacuate machine .*: machine hosts containers \[`+container.Id()+`\]; evacuate them first`)
+}
+
+func (s *MachineEvacuationSuite) TestEvacuateMachineNotFound(c *gc.C) {
+	_, err := s.State.EvacuateMachine("42", nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.MachineEvacuation("42")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -516,6 +516,21 @@ func RemoveOfferConnectionsForRelation(c *gc.C, rel *Relation) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+// SetApplicationExposedFlag sets the exposed flag of the application
+// without any expose settings, as applications were exposed before
+// expose settings were introduced.
+func SetApplicationExposedFlag(app *Application) error {
+	err := app.st.db().RunTransaction([]txn.Op{{
+		C:      applicationsC,
+		Id:     app.doc.DocID,
+		Update: bson.D{{"$set", bson.D{{"exposed", true}}}},
+	}})
+	if err != nil {
+		return err
+	}
+	return app.Refresh()
+}
+
 func RelationCount(app *Application) int {
 	return app.doc.RelationCount
 }
@@ -536,6 +551,15 @@ func LeadershipLeases(st *State) (map[string]lease.Info, error) {
 	return client.Leases(), nil
 }
 
+func SetStorageRetainedFrom(st *State, tag names.StorageTag, appName string) error {
+	return st.db().RunTransaction([]txn.Op{{
+		C:      storageInstancesC,
+		Id:     tag.Id(),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"retained-from", appName}}}},
+	}})
+}
+
 func StorageAttachmentCount(instance StorageInstance) int {
 	internal, ok := instance.(*storageInstance)
 	if !ok {
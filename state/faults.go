@@ -0,0 +1,255 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/featureflag"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/feature"
+)
+
+// FaultKind identifies a kind of failure that can be injected into a
+// model, to check how its agents and the controller cope with it.
+type FaultKind string
+
+const (
+	// FaultProviderAPI makes opening the model's environ fail, as if
+	// the provider's API were unavailable. This affects the
+	// controller and the model workers, such as the provisioner,
+	// firewaller and instance poller, that open the environ with
+	// the cloud spec the API server gives them.
+	FaultProviderAPI FaultKind = "provider-api"
+
+	// FaultAgentDisconnect makes the API server refuse logins by the
+	// model's agents, so that agents that lose their connections
+	// cannot reconnect until the fault expires.
+	FaultAgentDisconnect FaultKind = "agent-disconnect"
+
+	// FaultSlowTransactions delays every transaction run against the
+	// model's state. Each State caches the fault for a few seconds,
+	// so a fault injected through one State takes that long to reach
+	// the others.
+	FaultSlowTransactions FaultKind = "slow-transactions"
+)
+
+// Validate returns an error if the kind of fault is not known.
+func (k FaultKind) Validate() error {
+	switch k {
+	case FaultProviderAPI, FaultAgentDisconnect, FaultSlowTransactions:
+		return nil
+	}
+	return errors.NotValidf("fault kind %q", k)
+}
+
+// Fault describes a fault injected into a model. Faults are only acted
+// on while the fault-injection feature flag is enabled.
+type Fault struct {
+	// Kind is the kind of fault.
+	Kind FaultKind
+
+	// Expires is when the fault stops having any effect.
+	Expires time.Time
+
+	// Delay is how long each transaction is held up by a
+	// FaultSlowTransactions fault.
+	Delay time.Duration
+}
+
+// faultDoc records a fault injected into a model.
+type faultDoc struct {
+	DocID     string `bson:"_id"`
+	ModelUUID string `bson:"model-uuid"`
+	Kind      string `bson:"kind"`
+	Expires   int64  `bson:"expires"`
+	Delay     int64  `bson:"delay"`
+}
+
+func (doc *faultDoc) fault() Fault {
+	return Fault{
+		Kind:    FaultKind(doc.Kind),
+		Expires: time.Unix(0, doc.Expires).UTC(),
+		Delay:   time.Duration(doc.Delay),
+	}
+}
+
+// InjectFault injects the fault into the model, replacing any fault of
+// the same kind.
+func (st *State) InjectFault(fault Fault) error {
+	if err := fault.Kind.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if !fault.Expires.After(st.clock().Now()) {
+		return errors.NotValidf("fault expiry %v in the past", fault.Expires)
+	}
+	if fault.Delay < 0 {
+		return errors.NotValidf("negative fault delay")
+	}
+	doc := faultDoc{
+		DocID:   string(fault.Kind),
+		Kind:    string(fault.Kind),
+		Expires: fault.Expires.UnixNano(),
+		Delay:   int64(fault.Delay),
+	}
+	faults, closer := st.db().GetCollection(faultsC)
+	defer closer()
+	buildTxn := func(int) ([]txn.Op, error) {
+		n, err := faults.FindId(doc.DocID).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var ops []txn.Op
+		if n == 0 {
+			ops = []txn.Op{{
+				C:      faultsC,
+				Id:     doc.DocID,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			}}
+		} else {
+			ops = []txn.Op{{
+				C:      faultsC,
+				Id:     doc.DocID,
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{
+					{"expires", doc.Expires},
+					{"delay", doc.Delay},
+				}}},
+			}}
+		}
+		if fault.Kind == FaultProviderAPI {
+			ops = append(ops, st.touchModelConfigOp())
+		}
+		return ops, nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot inject %s fault", fault.Kind)
+	}
+	st.slowTxns.reset()
+	return nil
+}
+
+// ClearFault removes any fault of the given kind from the model.
+func (st *State) ClearFault(kind FaultKind) error {
+	if err := kind.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      faultsC,
+		Id:     string(kind),
+		Remove: true,
+	}}
+	if kind == FaultProviderAPI {
+		ops = append(ops, st.touchModelConfigOp())
+	}
+	if err := st.db().RunTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot clear %s fault", kind)
+	}
+	st.slowTxns.reset()
+	return nil
+}
+
+// Faults returns the faults currently in effect in the model.
+func (st *State) Faults() ([]Fault, error) {
+	faults, closer := st.db().GetCollection(faultsC)
+	defer closer()
+
+	var docs []faultDoc
+	query := bson.D{{"expires", bson.D{{"$gt", st.clock().Now().UnixNano()}}}}
+	if err := faults.Find(query).Sort("kind").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot read faults")
+	}
+	result := make([]Fault, len(docs))
+	for i, doc := range docs {
+		result[i] = doc.fault()
+	}
+	return result, nil
+}
+
+// ActiveFault returns the fault of the given kind in effect in the
+// model, or an error satisfying errors.IsNotFound if there is none.
+func (st *State) ActiveFault(kind FaultKind) (Fault, error) {
+	return activeFault(st.db(), kind, st.clock().Now())
+}
+
+func activeFault(db Database, kind FaultKind, now time.Time) (Fault, error) {
+	faults, closer := db.GetCollection(faultsC)
+	defer closer()
+
+	var doc faultDoc
+	err := faults.FindId(string(kind)).One(&doc)
+	if err == mgo.ErrNotFound || err == nil && doc.Expires <= now.UnixNano() {
+		return Fault{}, errors.NotFoundf("%s fault", kind)
+	} else if err != nil {
+		return Fault{}, errors.Annotatef(err, "cannot read %s fault", kind)
+	}
+	return doc.fault(), nil
+}
+
+// touchModelConfigOp returns an operation that bumps the version of
+// the model's config without changing it. The agents' environ trackers
+// re-read the model's cloud spec when the config changes, so this
+// makes the workers using the environ notice a provider fault being
+// injected or cleared.
+func (st *State) touchModelConfigOp() txn.Op {
+	return txn.Op{
+		C:      settingsC,
+		Id:     st.docID(modelGlobalKey),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$inc", bson.D{{"version", 1}}}},
+	}
+}
+
+// slowTxnFaultInterval is how long a model's slow-transactions fault
+// is cached before the faults collection is read again.
+const slowTxnFaultInterval = 10 * time.Second
+
+// slowTxnFault caches a model's FaultSlowTransactions fault, so that
+// running a transaction does not read the faults collection each time.
+type slowTxnFault struct {
+	clock func() clock.Clock
+
+	mu      sync.Mutex
+	checked time.Time
+	fault   Fault
+}
+
+// delay returns how long each attempt to run a transaction against
+// the database should be held up.
+func (f *slowTxnFault) delay(db Database) time.Duration {
+	if !featureflag.Enabled(feature.FaultInjection) {
+		return 0
+	}
+	now := f.clock().Now()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.checked.IsZero() || now.Sub(f.checked) >= slowTxnFaultInterval {
+		fault, err := activeFault(db, FaultSlowTransactions, now)
+		if err != nil && !errors.IsNotFound(err) {
+			logger.Warningf("%v", err)
+		}
+		f.fault, f.checked = fault, now
+	}
+	if !f.fault.Expires.After(now) {
+		return 0
+	}
+	return f.fault.Delay
+}
+
+// reset makes the next call to delay read the fault afresh.
+func (f *slowTxnFault) reset() {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.checked = time.Time{}
+}
@@ -0,0 +1,243 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/feature"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type FaultsSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&FaultsSuite{})
+
+func (s *FaultsSuite) TestNoFaults(c *gc.C) {
+	faults, err := s.State.Faults()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(faults, gc.HasLen, 0)
+	_, err = s.State.ActiveFault(state.FaultProviderAPI)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *FaultsSuite) TestInjectFault(c *gc.C) {
+	expires := s.Clock.Now().Add(time.Minute).UTC()
+	err := s.State.InjectFault(state.Fault{
+		Kind:    state.FaultSlowTransactions,
+		Expires: expires,
+		Delay:   time.Second,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.InjectFault(state.Fault{
+		Kind:    state.FaultAgentDisconnect,
+		Expires: expires,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	faults, err := s.State.Faults()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(faults, jc.DeepEquals, []state.Fault{{
+		Kind:    state.FaultAgentDisconnect,
+		Expires: expires,
+	}, {
+		Kind:    state.FaultSlowTransactions,
+		Expires: expires,
+		Delay:   time.Second,
+	}})
+	fault, err := s.State.ActiveFault(state.FaultSlowTransactions)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fault.Delay, gc.Equals, time.Second)
+}
+
+func (s *FaultsSuite) TestInjectFaultReplaces(c *gc.C) {
+	err := s.State.InjectFault(state.Fault{
+		Kind:    state.FaultSlowTransactions,
+		Expires: s.Clock.Now().Add(time.Minute),
+		Delay:   time.Second,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	expires := s.Clock.Now().Add(time.Hour).UTC()
+	err = s.State.InjectFault(state.Fault{
+		Kind:    state.FaultSlowTransactions,
+		Expires: expires,
+		Delay:   time.Millisecond,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	fault, err := s.State.ActiveFault(state.FaultSlowTransactions)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fault, jc.DeepEquals, state.Fault{
+		Kind:    state.FaultSlowTransactions,
+		Expires: expires,
+		Delay:   time.Millisecond,
+	})
+}
+
+func (s *FaultsSuite) TestInjectFaultInvalid(c *gc.C) {
+	now := s.Clock.Now()
+	err := s.State.InjectFault(state.Fault{Kind: "meteor-strike", Expires: now.Add(time.Minute)})
+	c.Check(err, gc.ErrorMatches, `fault kind "meteor-strike" not valid`)
+	err = s.State.InjectFault(state.Fault{Kind: state.FaultProviderAPI, Expires: now})
+	c.Check(err, gc.ErrorMatches, `fault expiry .* in the past not valid`)
+	err = s.State.InjectFault(state.Fault{Kind: state.FaultProviderAPI, Expires: now.Add(time.Minute), Delay: -1})
+	c.Check(err, gc.ErrorMatches, `negative fault delay not valid`)
+}
+
+func (s *FaultsSuite) TestFaultExpires(c *gc.C) {
+	err := s.State.InjectFault(state.Fault{
+		Kind:    state.FaultProviderAPI,
+		Expires: s.Clock.Now().Add(time.Minute),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.Clock.Advance(time.Minute)
+
+	_, err = s.State.ActiveFault(state.FaultProviderAPI)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	faults, err := s.State.Faults()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(faults, gc.HasLen, 0)
+}
+
+func (s *FaultsSuite) TestClearFault(c *gc.C) {
+	err := s.State.InjectFault(state.Fault{
+		Kind:    state.FaultProviderAPI,
+		Expires: s.Clock.Now().Add(time.Minute),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.ClearFault(state.FaultProviderAPI)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.ActiveFault(state.FaultProviderAPI)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Clearing a fault that is not there is not an error.
+	err = s.State.ClearFault(state.FaultProviderAPI)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *FaultsSuite) TestProviderFaultTouchesModelConfig(c *gc.C) {
+	w := s.Model.WatchForModelConfigChanges()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err := s.State.InjectFault(state.Fault{
+		Kind:    state.FaultProviderAPI,
+		Expires: s.Clock.Now().Add(time.Minute),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.State.ClearFault(state.FaultProviderAPI)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Other kinds of fault leave the model config alone.
+	err = s.State.InjectFault(state.Fault{
+		Kind:    state.FaultAgentDisconnect,
+		Expires: s.Clock.Now().Add(time.Minute),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+}
+
+func (s *FaultsSuite) TestSlowTransactions(c *gc.C) {
+	s.SetFeatureFlags(feature.FaultInjection)
+	err := s.State.InjectFault(state.Fault{
+		Kind:    state.FaultSlowTransactions,
+		Expires: s.Clock.Now().Add(time.Minute),
+		Delay:   time.Second,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	done := s.setModelConstraints(s.State)
+	select {
+	case err := <-done:
+		c.Fatalf("transaction not delayed (err %v)", err)
+	case <-time.After(coretesting.ShortWait):
+	}
+	s.advanceUntilDone(c, time.Second, done)
+}
+
+func (s *FaultsSuite) TestSlowTransactionsIgnoredWithoutFeatureFlag(c *gc.C) {
+	err := s.State.InjectFault(state.Fault{
+		Kind:    state.FaultSlowTransactions,
+		Expires: s.Clock.Now().Add(time.Minute),
+		Delay:   time.Hour,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitDone(c, s.setModelConstraints(s.State))
+}
+
+func (s *FaultsSuite) TestSlowTransactionsFaultCached(c *gc.C) {
+	s.SetFeatureFlags(feature.FaultInjection)
+	s.waitDone(c, s.setModelConstraints(s.State))
+
+	// A fault injected through another State is only noticed once
+	// the cached fault is read again.
+	other, err := s.State.ForModel(s.State.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	defer other.Close()
+	err = other.InjectFault(state.Fault{
+		Kind:    state.FaultSlowTransactions,
+		Expires: s.Clock.Now().Add(time.Minute),
+		Delay:   time.Second,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitDone(c, s.setModelConstraints(s.State))
+
+	s.Clock.Advance(10 * time.Second)
+	done := s.setModelConstraints(s.State)
+	select {
+	case err := <-done:
+		c.Fatalf("transaction not delayed (err %v)", err)
+	case <-time.After(coretesting.ShortWait):
+	}
+	s.advanceUntilDone(c, time.Second, done)
+}
+
+func (s *FaultsSuite) setModelConstraints(st *state.State) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		done <- st.SetModelConstraints(constraints.MustParse("mem=4G"))
+	}()
+	return done
+}
+
+func (s *FaultsSuite) waitDone(c *gc.C, done <-chan error) {
+	select {
+	case err := <-done:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for transaction")
+	}
+}
+
+// advanceUntilDone advances the clock by the given step until the
+// transaction completes; other users of the clock may be waiting on
+// it too, so a single step is not certain to release the transaction.
+func (s *FaultsSuite) advanceUntilDone(c *gc.C, step time.Duration, done <-chan error) {
+	timeout := time.After(coretesting.LongWait)
+	for {
+		s.Clock.Advance(step)
+		select {
+		case err := <-done:
+			c.Assert(err, jc.ErrorIsNil)
+			return
+		case <-time.After(coretesting.ShortWait):
+		case <-timeout:
+			c.Fatalf("timed out waiting for delayed transaction")
+		}
+	}
+}
@@ -107,6 +107,18 @@ func (p InitializeParams) Validate() error {
 	return nil
 }
 
+// controllerSettings returns the controller config to be recorded at
+// bootstrap. The database backend is always recorded, so that State
+// can be opened with the backend the controller was bootstrapped with.
+func controllerSettings(cfg controller.Config) map[string]interface{} {
+	settings := make(map[string]interface{}, len(cfg)+1)
+	for key, value := range cfg {
+		settings[key] = value
+	}
+	settings[controller.DatabaseBackend] = cfg.DatabaseBackend()
+	return settings
+}
+
 // InitDatabaseFunc defines a function used to
 // create the collections and indices in a Juju database.
 type InitDatabaseFunc func(*mgo.Session, string, *controller.Config) error
@@ -135,6 +147,7 @@ func Initialize(args InitializeParams) (_ *Controller, _ *State, err error) {
 		MongoDialOpts:      args.MongoDialOpts,
 		NewPolicy:          args.NewPolicy,
 		InitDatabaseFunc:   InitDatabase,
+		DatabaseBackend:    args.ControllerConfig.DatabaseBackend(),
 	})
 	if err != nil {
 		return nil, nil, errors.Annotate(err, "opening controller")
@@ -223,7 +236,7 @@ func Initialize(args InitializeParams) (_ *Controller, _ *State, err error) {
 			Assert: txn.DocMissing,
 			Insert: &hostedModelCountDoc{},
 		},
-		createSettingsOp(controllersC, controllerSettingsGlobalKey, args.ControllerConfig),
+		createSettingsOp(controllersC, controllerSettingsGlobalKey, controllerSettings(args.ControllerConfig)),
 		createSettingsOp(globalSettingsC, controllerInheritedSettingsGlobalKey, args.ControllerInheritedConfig),
 	)
 	for k, v := range args.Cloud.RegionConfig {
@@ -339,6 +352,9 @@ func (st *State) modelSetupOps(controllerUUID string, args ModelArgs, inherited
 	}
 	// Some values require marshalling before storage.
 	modelCfg = config.CoerceForStorage(modelCfg)
+	if args.ConfigProfile != nil {
+		ops = append(ops, createModelConfigProfileOp(st, *args.ConfigProfile, modelCfg))
+	}
 	ops = append(ops,
 		createSettingsOp(settingsC, modelGlobalKey, modelCfg),
 		createModelEntityRefsOp(modelUUID),
@@ -350,6 +366,7 @@ func (st *State) modelSetupOps(controllerUUID string, args ModelArgs, inherited
 			args.CloudName, args.CloudRegion, args.CloudCredential,
 			args.MigrationMode,
 			args.EnvironVersion,
+			args.EphemeralExpiry,
 		),
 		createUniqueOwnerModelNameOp(args.Owner, args.Config.Name()),
 	)
@@ -143,6 +143,10 @@ type ActionReceiver interface {
 	// ActionReceiver.
 	AddAction(name string, payload map[string]interface{}) (Action, error)
 
+	// AddCorrelatedAction is like AddAction, but records the ID
+	// correlating the action with the operator action that queued it.
+	AddCorrelatedAction(correlationID, name string, payload map[string]interface{}) (Action, error)
+
 	// CancelAction removes a pending Action from the queue for this
 	// ActionReceiver and marks it as cancelled.
 	CancelAction(action Action) (Action, error)
@@ -198,6 +202,10 @@ type Action interface {
 	// Action.
 	Enqueued() time.Time
 
+	// CorrelationID returns the ID correlating the action with the
+	// operator action that queued it, if any.
+	CorrelationID() string
+
 	// Started returns the time that the Action execution began.
 	Started() time.Time
 
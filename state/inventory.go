@@ -0,0 +1,259 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// inventoryGenerationKey is the id of the document in the inventory
+// collection that holds the controller's current inventory generation.
+const inventoryGenerationKey = "generation"
+
+// inventoryTxnBatchSize is the largest number of inventory documents
+// written by a single transaction.
+const inventoryTxnBatchSize = 100
+
+// inventoryRemovalMaxAge is how long the removal of an entity from the
+// inventory is remembered.
+const inventoryRemovalMaxAge = 30 * 24 * time.Hour
+
+// maxInventoryAttempts bounds the attempts to record the inventory
+// while others are recording it too.
+const maxInventoryAttempts = 3
+
+// errInventoryChanged is returned by recordInventory when the
+// inventory changed after it was read.
+var errInventoryChanged = errors.New("inventory changed")
+
+// inventoryDoc records the fingerprint of an entity in the controller's
+// inventory, and the generation at which it was last added, changed or
+// removed. The generation document uses only the generation and pruned
+// fields.
+type inventoryDoc struct {
+	DocID       string `bson:"_id"`
+	Fingerprint string `bson:"fingerprint,omitempty"`
+	Generation  int64  `bson:"generation"`
+	Removed     bool   `bson:"removed,omitempty"`
+	RemovedAt   int64  `bson:"removed-at,omitempty"`
+	Pruned      int64  `bson:"pruned,omitempty"`
+}
+
+// InventoryGenerations holds the generations at which the entities in
+// the controller's inventory last changed.
+type InventoryGenerations struct {
+	// Generation is the controller's current inventory generation.
+	Generation int64
+
+	// Pruned is the latest generation at which an entity was removed
+	// whose removal has since been forgotten. The changes since an
+	// earlier generation cannot all be reported.
+	Pruned int64
+
+	// Changed holds, keyed on entity, the generation at which each
+	// entity in the inventory was added or last changed.
+	Changed map[string]int64
+
+	// Removed holds, keyed on entity, the generation at which each
+	// entity that is no longer in the inventory was removed.
+	Removed map[string]int64
+}
+
+// RecordInventory records the fingerprints of the entities currently
+// in the controller's inventory, keyed on identifiers that are stable
+// for the lifetime of each entity. If any entity has been added,
+// changed or removed since the inventory was last recorded, the
+// inventory generation is advanced and those entities are stamped with
+// the new generation, so that exports of the inventory may include
+// only the entities changed since an earlier generation. Removals are
+// remembered for 30 days.
+//
+// The entities are written in batches, and the generation is advanced
+// once they all have been, so an export never misses a change.
+func (st *State) RecordInventory(fingerprints map[string]string) (InventoryGenerations, error) {
+	for attempt := 0; attempt < maxInventoryAttempts; attempt++ {
+		result, err := st.recordInventory(fingerprints)
+		if err == errInventoryChanged {
+			continue
+		} else if err != nil {
+			return InventoryGenerations{}, errors.Annotate(err, "cannot record inventory")
+		}
+		return result, nil
+	}
+	return InventoryGenerations{}, errors.Annotate(jujutxn.ErrExcessiveContention, "cannot record inventory")
+}
+
+func (st *State) recordInventory(fingerprints map[string]string) (InventoryGenerations, error) {
+	coll, closer := st.db().GetCollection(inventoryC)
+	defer closer()
+
+	var docs []inventoryDoc
+	if err := coll.Find(nil).All(&docs); err != nil {
+		return InventoryGenerations{}, errors.Trace(err)
+	}
+	var current *inventoryDoc
+	existing := make(map[string]inventoryDoc)
+	for i, doc := range docs {
+		if doc.DocID == inventoryGenerationKey {
+			current = &docs[i]
+			continue
+		}
+		existing[doc.DocID] = doc
+	}
+	var generation, pruned int64
+	if current != nil {
+		generation, pruned = current.Generation, current.Pruned
+	}
+	next := generation + 1
+	now := st.clock().Now()
+	forgetBefore := now.Add(-inventoryRemovalMaxAge).UnixNano()
+
+	result := InventoryGenerations{
+		Generation: generation,
+		Changed:    make(map[string]int64),
+		Removed:    make(map[string]int64),
+	}
+	var ops []txn.Op
+	changed := false
+	for key, fingerprint := range fingerprints {
+		doc, ok := existing[key]
+		switch {
+		case !ok:
+			ops = append(ops, txn.Op{
+				C:      inventoryC,
+				Id:     key,
+				Assert: txn.DocMissing,
+				Insert: &inventoryDoc{
+					DocID:       key,
+					Fingerprint: fingerprint,
+					Generation:  next,
+				},
+			})
+			result.Changed[key] = next
+			changed = true
+		case doc.Removed || doc.Fingerprint != fingerprint:
+			ops = append(ops, txn.Op{
+				C:      inventoryC,
+				Id:     key,
+				Assert: bson.D{{"generation", doc.Generation}},
+				Update: bson.D{
+					{"$set", bson.D{
+						{"fingerprint", fingerprint},
+						{"generation", next},
+					}},
+					{"$unset", bson.D{{"removed", 1}, {"removed-at", 1}}},
+				},
+			})
+			result.Changed[key] = next
+			changed = true
+		default:
+			result.Changed[key] = doc.Generation
+		}
+	}
+	for key, doc := range existing {
+		if _, ok := fingerprints[key]; ok {
+			continue
+		}
+		switch {
+		case doc.Removed && doc.RemovedAt < forgetBefore:
+			// The removal is forgotten; exports since an
+			// earlier generation cannot be made.
+			ops = append(ops, txn.Op{
+				C:      inventoryC,
+				Id:     key,
+				Assert: bson.D{{"generation", doc.Generation}},
+				Remove: true,
+			})
+			if doc.Generation > pruned {
+				pruned = doc.Generation
+			}
+		case doc.Removed:
+			result.Removed[key] = doc.Generation
+		default:
+			// The document is kept, so that exports since an
+			// earlier generation report the removal.
+			ops = append(ops, txn.Op{
+				C:      inventoryC,
+				Id:     key,
+				Assert: bson.D{{"generation", doc.Generation}},
+				Update: bson.D{{"$set", bson.D{
+					{"fingerprint", ""},
+					{"generation", next},
+					{"removed", true},
+					{"removed-at", now.UnixNano()},
+				}}},
+			})
+			result.Removed[key] = next
+			changed = true
+		}
+	}
+	for _, doc := range existing {
+		if doc.Generation > generation {
+			// An earlier recording was interrupted before it
+			// advanced the generation.
+			changed = true
+		}
+	}
+	result.Pruned = pruned
+	if len(ops) == 0 && !changed {
+		return result, nil
+	}
+
+	for len(ops) > 0 {
+		n := len(ops)
+		if n > inventoryTxnBatchSize {
+			n = inventoryTxnBatchSize
+		}
+		if err := st.runInventoryTransaction(ops[:n]); err != nil {
+			return InventoryGenerations{}, err
+		}
+		ops = ops[n:]
+	}
+
+	if changed {
+		result.Generation = next
+	}
+	var generationOp txn.Op
+	if current == nil {
+		generationOp = txn.Op{
+			C:      inventoryC,
+			Id:     inventoryGenerationKey,
+			Assert: txn.DocMissing,
+			Insert: &inventoryDoc{
+				DocID:      inventoryGenerationKey,
+				Generation: result.Generation,
+				Pruned:     pruned,
+			},
+		}
+	} else {
+		generationOp = txn.Op{
+			C:      inventoryC,
+			Id:     inventoryGenerationKey,
+			Assert: bson.D{{"generation", generation}},
+			Update: bson.D{{"$set", bson.D{
+				{"generation", result.Generation},
+				{"pruned", pruned},
+			}}},
+		}
+	}
+	if err := st.runInventoryTransaction([]txn.Op{generationOp}); err != nil {
+		return InventoryGenerations{}, err
+	}
+	return result, nil
+}
+
+// runInventoryTransaction runs the transaction, returning
+// errInventoryChanged if it is aborted.
+func (st *State) runInventoryTransaction(ops []txn.Op) error {
+	err := st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		return errInventoryChanged
+	}
+	return errors.Trace(err)
+}
@@ -0,0 +1,157 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"fmt"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type InventorySuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&InventorySuite{})
+
+func (s *InventorySuite) TestRecordInventoryEmpty(c *gc.C) {
+	generations, err := s.State.RecordInventory(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(generations, jc.DeepEquals, state.InventoryGenerations{
+		Changed: map[string]int64{},
+		Removed: map[string]int64{},
+	})
+}
+
+func (s *InventorySuite) TestRecordInventory(c *gc.C) {
+	generations, err := s.State.RecordInventory(map[string]string{
+		"a": "fingerprint-a",
+		"b": "fingerprint-b",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(generations, jc.DeepEquals, state.InventoryGenerations{
+		Generation: 1,
+		Changed:    map[string]int64{"a": 1, "b": 1},
+		Removed:    map[string]int64{},
+	})
+
+	// Recording the same fingerprints does not advance the generation.
+	generations, err = s.State.RecordInventory(map[string]string{
+		"a": "fingerprint-a",
+		"b": "fingerprint-b",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(generations.Generation, gc.Equals, int64(1))
+
+	generations, err = s.State.RecordInventory(map[string]string{
+		"a": "fingerprint-a",
+		"b": "fingerprint-b2",
+		"c": "fingerprint-c",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(generations, jc.DeepEquals, state.InventoryGenerations{
+		Generation: 2,
+		Changed:    map[string]int64{"a": 1, "b": 2, "c": 2},
+		Removed:    map[string]int64{},
+	})
+}
+
+func (s *InventorySuite) TestRecordInventoryRemoved(c *gc.C) {
+	_, err := s.State.RecordInventory(map[string]string{
+		"a": "fingerprint-a",
+		"b": "fingerprint-b",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	generations, err := s.State.RecordInventory(map[string]string{
+		"a": "fingerprint-a",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(generations, jc.DeepEquals, state.InventoryGenerations{
+		Generation: 2,
+		Changed:    map[string]int64{"a": 1},
+		Removed:    map[string]int64{"b": 2},
+	})
+
+	// The removal is remembered.
+	generations, err = s.State.RecordInventory(map[string]string{
+		"a": "fingerprint-a",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(generations.Removed, jc.DeepEquals, map[string]int64{"b": 2})
+
+	// An entity may be added again with the same identifier.
+	generations, err = s.State.RecordInventory(map[string]string{
+		"a": "fingerprint-a",
+		"b": "fingerprint-b",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(generations, jc.DeepEquals, state.InventoryGenerations{
+		Generation: 3,
+		Changed:    map[string]int64{"a": 1, "b": 3},
+		Removed:    map[string]int64{},
+	})
+}
+
+func (s *InventorySuite) TestRecordInventoryBatches(c *gc.C) {
+	fingerprints := make(map[string]string)
+	for i := 0; i < 250; i++ {
+		fingerprints[fmt.Sprintf("entity-%d", i)] = "fingerprint"
+	}
+	generations, err := s.State.RecordInventory(fingerprints)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(generations.Generation, gc.Equals, int64(1))
+	c.Assert(generations.Changed, gc.HasLen, 250)
+
+	fingerprints["entity-249"] = "changed"
+	generations, err = s.State.RecordInventory(fingerprints)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(generations.Generation, gc.Equals, int64(2))
+	c.Assert(generations.Changed["entity-0"], gc.Equals, int64(1))
+	c.Assert(generations.Changed["entity-249"], gc.Equals, int64(2))
+}
+
+func (s *InventorySuite) TestRecordInventoryPrunesRemovals(c *gc.C) {
+	_, err := s.State.RecordInventory(map[string]string{
+		"a": "fingerprint-a",
+		"b": "fingerprint-b",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.RecordInventory(map[string]string{
+		"a": "fingerprint-a",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The removal is remembered for 30 days.
+	s.Clock.Advance(29 * 24 * time.Hour)
+	generations, err := s.State.RecordInventory(map[string]string{
+		"a": "fingerprint-a",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(generations.Removed, jc.DeepEquals, map[string]int64{"b": 2})
+	c.Assert(generations.Pruned, gc.Equals, int64(0))
+
+	s.Clock.Advance(2 * 24 * time.Hour)
+	generations, err = s.State.RecordInventory(map[string]string{
+		"a": "fingerprint-a",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(generations, jc.DeepEquals, state.InventoryGenerations{
+		Generation: 2,
+		Pruned:     2,
+		Changed:    map[string]int64{"a": 1},
+		Removed:    map[string]int64{},
+	})
+
+	// The pruned generation is remembered.
+	generations, err = s.State.RecordInventory(map[string]string{
+		"a": "fingerprint-a",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(generations.Pruned, gc.Equals, int64(2))
+}
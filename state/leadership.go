@@ -14,6 +14,7 @@ import (
 
 	"github.com/juju/juju/core/leadership"
 	corelease "github.com/juju/juju/core/lease"
+	"github.com/juju/juju/status"
 	"github.com/juju/juju/worker/lease"
 )
 
@@ -25,6 +26,64 @@ func leadershipSettingsKey(applicationId string) string {
 	return fmt.Sprintf("a#%s#leader", applicationId)
 }
 
+// leadershipHistoryGlobalKey returns the key under which changes of
+// leadership for the supplied application are recorded in status history.
+func leadershipHistoryGlobalKey(applicationId string) string {
+	return applicationGlobalKey(applicationId) + "#leadership"
+}
+
+// ApplicationLeadership describes the current leadership of an application.
+type ApplicationLeadership struct {
+	// Leader is the name of the unit holding leadership.
+	Leader string
+
+	// Expiry is the latest time at which the leadership lease might
+	// still be valid if it is not renewed.
+	Expiry time.Time
+}
+
+// ApplicationLeadershipDetails returns the current leader and leadership
+// lease expiry time for every application in the model that has a leader.
+func (st *State) ApplicationLeadershipDetails() (map[string]ApplicationLeadership, error) {
+	client, err := st.getLeadershipLeaseClient()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	leases := client.Leases()
+	result := make(map[string]ApplicationLeadership, len(leases))
+	for key, value := range leases {
+		result[key] = ApplicationLeadership{
+			Leader: value.Holder,
+			Expiry: value.Expiry,
+		}
+	}
+	return result, nil
+}
+
+// LeadershipHistory returns the recorded changes of leadership for the
+// supplied application, most recent first. Each entry's data holds the
+// name of the newly elected unit under the "leader" key.
+func (st *State) LeadershipHistory(applicationName string, filter status.StatusHistoryFilter) ([]status.StatusInfo, error) {
+	args := &statusHistoryArgs{
+		db:        st.db(),
+		globalKey: leadershipHistoryGlobalKey(applicationName),
+		filter:    filter,
+	}
+	return statusHistory(args)
+}
+
+// recordLeadershipChange writes an entry into the status history of the
+// supplied application noting that the unit has become its leader. It
+// is used as the leadership lease manager's ClaimObserver.
+func (st *State) recordLeadershipChange(applicationName, unitName string) {
+	probablyUpdateStatusHistory(st.db(), leadershipHistoryGlobalKey(applicationName), statusDoc{
+		Status:     status.Active,
+		StatusInfo: fmt.Sprintf("%s elected leader", unitName),
+		StatusData: map[string]interface{}{"leader": unitName},
+		Updated:    st.clock().Now().UnixNano(),
+	})
+}
+
 // LeadershipClaimer returns a leadership.Claimer for units and services in the
 // state's model.
 func (st *State) LeadershipClaimer() leadership.Claimer {
@@ -893,6 +893,9 @@ func (m *Machine) removeOps() ([]txn.Op, error) {
 		removeMachineBlockDevicesOp(m.Id()),
 		removeModelMachineRefOp(m.st, m.Id()),
 		removeSSHHostKeyOp(m.globalKey()),
+		removeMachinePoolOp(m.st, m.Id()),
+		removeAgentVersionPinOp(m.st, m.globalKey()),
+		removeMachinePackageUpdatesOp(m.st, m.Id()),
 	}
 	linkLayerDevicesOps, err := m.removeAllLinkLayerDevicesOps()
 	if err != nil {
@@ -1717,6 +1720,48 @@ func (m *Machine) StatusHistory(filter status.StatusHistoryFilter) ([]status.Sta
 	return statusHistory(args)
 }
 
+// statusHistoryKey returns the global key of the machine's status
+// history of the given kind: the machine's own for KindMachine or
+// KindContainer, and its instance's for KindMachineInstance or
+// KindContainerInstance.
+func (m *Machine) statusHistoryKey(kind status.HistoryKind) (string, error) {
+	switch kind {
+	case status.KindMachine, status.KindContainer:
+		return m.globalKey(), nil
+	case status.KindMachineInstance, status.KindContainerInstance:
+		return m.globalInstanceKey(), nil
+	}
+	return "", errors.NotValidf("%q status history for a machine", kind)
+}
+
+// StatusHistoryPage implements status.StatusHistoryPager. The
+// machine's history is read by KindMachine or KindContainer, and its
+// instance's by KindMachineInstance or KindContainerInstance.
+func (m *Machine) StatusHistoryPage(kind status.HistoryKind, filter status.StatusHistoryFilter, pageSize int, token string) (status.StatusHistoryPage, error) {
+	key, err := m.statusHistoryKey(kind)
+	if err != nil {
+		return status.StatusHistoryPage{}, errors.Trace(err)
+	}
+	return statusHistoryPage(&statusHistoryPageArgs{
+		db:       m.st.db(),
+		kinds:    map[string]status.HistoryKind{key: kind},
+		filter:   filter,
+		pageSize: pageSize,
+		token:    token,
+	})
+}
+
+// WatchStatusHistory returns a watcher reporting the entries added to
+// the machine's status history of the given kind, as for
+// StatusHistoryPage.
+func (m *Machine) WatchStatusHistory(kind status.HistoryKind) (StatusHistoryWatcher, error) {
+	key, err := m.statusHistoryKey(kind)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newStatusHistoryWatcher(m.st, map[string]status.HistoryKind{key: kind}), nil
+}
+
 // Clean returns true if the machine does not have any deployed units or containers.
 func (m *Machine) Clean() bool {
 	return m.doc.Clean
@@ -1840,6 +1885,11 @@ func (m *Machine) VolumeAttachments() ([]VolumeAttachment, error) {
 
 // AddAction is part of the ActionReceiver interface.
 func (m *Machine) AddAction(name string, payload map[string]interface{}) (Action, error) {
+	return m.AddCorrelatedAction("", name, payload)
+}
+
+// AddCorrelatedAction is part of the ActionReceiver interface.
+func (m *Machine) AddCorrelatedAction(correlationID, name string, payload map[string]interface{}) (Action, error) {
 	spec, ok := actions.PredefinedActionsSpec[name]
 	if !ok {
 		return nil, errors.Errorf("cannot add action %q to a machine; only predefined actions allowed", name)
@@ -1860,7 +1910,7 @@ func (m *Machine) AddAction(name string, payload map[string]interface{}) (Action
 		return nil, errors.Trace(err)
 	}
 
-	return model.EnqueueAction(m.Tag(), name, payloadWithDefaults)
+	return model.EnqueueCorrelatedAction(m.Tag(), correlationID, name, payloadWithDefaults)
 }
 
 // CancelAction is part of the ActionReceiver interface.
@@ -0,0 +1,341 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+)
+
+// machinePoolDoc records that a machine is in the model's machine pool.
+type machinePoolDoc struct {
+	DocID     string `bson:"_id"`
+	ModelUUID string `bson:"model-uuid"`
+	MachineId string `bson:"machine-id"`
+
+	// Unit holds the name of the unit the machine was allocated to
+	// from the pool, or "" if the machine is available.
+	Unit string `bson:"unit"`
+}
+
+// PoolMachine describes a machine in the model's machine pool.
+type PoolMachine struct {
+	// MachineId is the id of the machine.
+	MachineId string
+
+	// Unit is the name of the unit the machine has been allocated
+	// to, or "" if the machine is available.
+	Unit string
+}
+
+// Available reports whether the machine may be allocated to a unit.
+func (p PoolMachine) Available() bool {
+	return p.Unit == ""
+}
+
+// noPoolMachines is returned when no machine in the pool can host a
+// unit.
+var noPoolMachines = errors.New("no suitable machines available in the pool")
+
+// PoolMachines returns the machines in the model's machine pool,
+// ordered by machine id.
+func (st *State) PoolMachines() ([]PoolMachine, error) {
+	coll, closer := st.db().GetCollection(machinePoolC)
+	defer closer()
+
+	var docs []machinePoolDoc
+	if err := coll.Find(nil).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot read machine pool")
+	}
+	ids := make([]string, len(docs))
+	byId := make(map[string]machinePoolDoc, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.MachineId
+		byId[doc.MachineId] = doc
+	}
+	utils.SortStringsNaturally(ids)
+	result := make([]PoolMachine, len(ids))
+	for i, id := range ids {
+		result[i] = PoolMachine{
+			MachineId: id,
+			Unit:      byId[id].Unit,
+		}
+	}
+	return result, nil
+}
+
+// machinePoolDoc returns the pool document for the machine with the
+// supplied id. It returns a NotFound error if the machine is not in
+// the pool.
+func (st *State) machinePoolDoc(machineId string) (*machinePoolDoc, error) {
+	coll, closer := st.db().GetCollection(machinePoolC)
+	defer closer()
+
+	var doc machinePoolDoc
+	err := coll.FindId(machineId).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("machine %s in pool", machineId)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &doc, nil
+}
+
+// AddToPool registers the machine in the model's machine pool, so that
+// units being deployed are assigned to it in preference to new
+// machines. Only provisioned machines that may host units, and do not
+// yet host any, can be added to the pool.
+func (m *Machine) AddToPool() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add machine %s to pool", m)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if m.Life() != Alive {
+			return nil, errors.New("machine is not alive")
+		}
+		if !hasJob(m.doc.Jobs, JobHostUnits) || hasJob(m.doc.Jobs, JobManageModel) {
+			return nil, errors.New("machine cannot host units")
+		}
+		if len(m.doc.Principals) > 0 {
+			return nil, errors.New("machine already hosts units")
+		}
+		if _, err := m.InstanceId(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if _, err := m.st.machinePoolDoc(m.Id()); err == nil {
+			return nil, errors.AlreadyExistsf("machine %s in pool", m.Id())
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: bson.D{{"life", Alive}, noPrincipalsTerm},
+		}, {
+			C:      machinePoolC,
+			Id:     m.doc.DocID,
+			Assert: txn.DocMissing,
+			Insert: &machinePoolDoc{
+				MachineId: m.Id(),
+			},
+		}}, nil
+	}
+	return m.st.db().Run(buildTxn)
+}
+
+// RemoveFromPool removes the machine from the model's machine pool. A
+// unit to which the machine has been allocated remains on the machine.
+func (m *Machine) RemoveFromPool() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot remove machine %s from pool", m)
+	if _, err := m.st.machinePoolDoc(m.Id()); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      machinePoolC,
+		Id:     m.doc.DocID,
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	err = m.st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		return errors.NotFoundf("machine %s in pool", m.Id())
+	}
+	return errors.Trace(err)
+}
+
+// removeMachinePoolOp returns the operation that removes the machine
+// from the pool, if it is in it.
+func removeMachinePoolOp(mb modelBackend, machineId string) txn.Op {
+	return txn.Op{
+		C:      machinePoolC,
+		Id:     mb.docID(machineId),
+		Remove: true,
+	}
+}
+
+var noPrincipalsTerm = bson.DocElem{
+	"$or", []bson.D{
+		{{"principals", bson.D{{"$size", 0}}}},
+		{{"principals", bson.D{{"$exists", false}}}},
+	},
+}
+
+// AssignToPoolMachine assigns the unit to an available machine in the
+// model's machine pool that satisfies the unit's series and
+// constraints, and allocates the machine to the unit.
+func (u *Unit) AssignToPoolMachine() (m *Machine, err error) {
+	defer assignContextf(&err, u.Name(), "pool machine")
+	if u.doc.Principal != "" {
+		return nil, fmt.Errorf("unit is a subordinate")
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		var err error
+		u := u // don't change outer var
+		if attempt > 0 {
+			u, err = u.st.Unit(u.Name())
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		var ops []txn.Op
+		m, ops, err = u.assignToPoolMachineOps()
+		return ops, err
+	}
+	if err := u.st.db().Run(buildTxn); err != nil {
+		return nil, errors.Trace(err)
+	}
+	u.doc.MachineId = m.doc.Id
+	m.doc.Clean = false
+	return m, nil
+}
+
+func (u *Unit) assignToPoolMachineOps() (*Machine, []txn.Op, error) {
+	cons, err := u.Constraints()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if cons.HasContainer() {
+		// Pool machines host units directly.
+		return nil, nil, noPoolMachines
+	}
+	poolMachines, err := u.st.PoolMachines()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	var candidates []*Machine
+	for _, p := range poolMachines {
+		if !p.Available() {
+			continue
+		}
+		m, err := u.st.Machine(p.MachineId)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		candidates = append(candidates, m)
+	}
+	zones, err := u.preferredZones()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if candidates, err = preferZones(u.st, candidates, zones); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if candidates, err = u.withoutHostConflicts(candidates); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	for _, m := range candidates {
+		if m.Life() != Alive || m.Series() != u.doc.Series || len(m.doc.Principals) > 0 {
+			continue
+		}
+		hc, err := m.HardwareCharacteristics()
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		if !hardwareSatisfies(hc, *cons) {
+			continue
+		}
+		// Check that the unit storage is compatible with
+		// the machine in question.
+		storageParams, err := u.machineStorageParams()
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		if err := validateDynamicMachineStorageParams(m, storageParams); err != nil {
+			if errors.IsNotSupported(err) {
+				continue
+			}
+			return nil, nil, errors.Trace(err)
+		}
+		ops, err := u.assignToMachineOps(m, false, false)
+		switch errors.Cause(err) {
+		case nil:
+		case machineNotAliveErr:
+			continue
+		default:
+			return nil, nil, errors.Trace(err)
+		}
+		ops = append(ops, txn.Op{
+			C:      machinePoolC,
+			Id:     m.doc.DocID,
+			Assert: bson.D{{"unit", ""}},
+			Update: bson.D{{"$set", bson.D{{"unit", u.doc.Name}}}},
+		})
+		return m, ops, nil
+	}
+	return nil, nil, noPoolMachines
+}
+
+// releasePoolMachineOps returns the operations that return the machine
+// with the supplied id to the pool when the unit leaves it, and whether
+// the machine is in the pool at all.
+func (u *Unit) releasePoolMachineOps(machineId string) ([]txn.Op, bool, error) {
+	doc, err := u.st.machinePoolDoc(machineId)
+	if errors.IsNotFound(err) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	op := txn.Op{
+		C:      machinePoolC,
+		Id:     u.st.docID(machineId),
+		Assert: bson.D{{"unit", doc.Unit}},
+	}
+	if doc.Unit == u.doc.Name {
+		op.Update = bson.D{{"$set", bson.D{{"unit", ""}}}}
+	}
+	return []txn.Op{op}, true, nil
+}
+
+// hardwareSatisfies reports whether the hardware characteristics meet
+// the constraints. Characteristics that are unknown do not meet any
+// constraint on them.
+func hardwareSatisfies(hc *instance.HardwareCharacteristics, cons constraints.Value) bool {
+	if cons.Arch != nil && *cons.Arch != "" {
+		if hc.Arch == nil || *hc.Arch != *cons.Arch {
+			return false
+		}
+	}
+	atLeast := func(have, want *uint64) bool {
+		if want == nil || *want == 0 {
+			return true
+		}
+		return have != nil && *have >= *want
+	}
+	if !atLeast(hc.Mem, cons.Mem) ||
+		!atLeast(hc.RootDisk, cons.RootDisk) ||
+		!atLeast(hc.CpuCores, cons.CpuCores) ||
+		!atLeast(hc.CpuPower, cons.CpuPower) {
+		return false
+	}
+	if cons.Tags != nil && len(*cons.Tags) > 0 {
+		if hc.Tags == nil {
+			return false
+		}
+		have := make(map[string]bool)
+		for _, tag := range *hc.Tags {
+			have[tag] = true
+		}
+		for _, tag := range *cons.Tags {
+			if !have[tag] {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,209 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+type MachinePoolSuite struct {
+	ConnSuite
+	wordpress *state.Application
+}
+
+var _ = gc.Suite(&MachinePoolSuite{})
+
+func (s *MachinePoolSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.wordpress = s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+func (s *MachinePoolSuite) addPoolMachine(c *gc.C, hardware string) *state.Machine {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	hc := instance.MustParseHardware(hardware)
+	err = m.SetProvisioned(instance.Id("inst-"+m.Id()), "fake_nonce", &hc)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.AddToPool()
+	c.Assert(err, jc.ErrorIsNil)
+	return m
+}
+
+func (s *MachinePoolSuite) TestAddToPool(c *gc.C) {
+	m0 := s.addPoolMachine(c, "arch=amd64")
+	m1 := s.addPoolMachine(c, "arch=amd64")
+
+	pool, err := s.State.PoolMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pool, jc.DeepEquals, []state.PoolMachine{
+		{MachineId: m0.Id()},
+		{MachineId: m1.Id()},
+	})
+	c.Assert(pool[0].Available(), jc.IsTrue)
+}
+
+func (s *MachinePoolSuite) TestAddToPoolTwice(c *gc.C) {
+	m := s.addPoolMachine(c, "arch=amd64")
+	err := m.AddToPool()
+	c.Assert(err, gc.ErrorMatches, `cannot add machine 0 to pool: machine 0 in pool already exists`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *MachinePoolSuite) TestAddToPoolNotProvisioned(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.AddToPool()
+	c.Assert(err, gc.ErrorMatches, `cannot add machine 0 to pool: machine 0 not provisioned`)
+}
+
+func (s *MachinePoolSuite) TestAddToPoolController(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobManageModel)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.AddToPool()
+	c.Assert(err, gc.ErrorMatches, `cannot add machine 0 to pool: machine cannot host units`)
+}
+
+func (s *MachinePoolSuite) TestAddToPoolWithUnits(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = m.AddToPool()
+	c.Assert(err, gc.ErrorMatches, `cannot add machine 0 to pool: machine already hosts units`)
+}
+
+func (s *MachinePoolSuite) TestRemoveFromPool(c *gc.C) {
+	m := s.addPoolMachine(c, "arch=amd64")
+	err := m.RemoveFromPool()
+	c.Assert(err, jc.ErrorIsNil)
+
+	pool, err := s.State.PoolMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pool, gc.HasLen, 0)
+
+	err = m.RemoveFromPool()
+	c.Assert(err, gc.ErrorMatches, `cannot remove machine 0 from pool: machine 0 in pool not found`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *MachinePoolSuite) TestAssignUnitPrefersPool(c *gc.C) {
+	// A clean, empty machine that would otherwise be chosen.
+	_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	m := s.addPoolMachine(c, "arch=amd64")
+
+	unit, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Equals, m.Id())
+
+	pool, err := s.State.PoolMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pool, jc.DeepEquals, []state.PoolMachine{
+		{MachineId: m.Id(), Unit: unit.Name()},
+	})
+}
+
+func (s *MachinePoolSuite) TestAssignUnitNewMachineWhenPoolExhausted(c *gc.C) {
+	m := s.addPoolMachine(c, "arch=amd64")
+	unit0, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit0, state.AssignNew)
+	c.Assert(err, jc.ErrorIsNil)
+
+	unit1, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit1, state.AssignNew)
+	c.Assert(err, jc.ErrorIsNil)
+
+	machineId0, err := unit0.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId0, gc.Equals, m.Id())
+	machineId1, err := unit1.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId1, gc.Not(gc.Equals), m.Id())
+}
+
+func (s *MachinePoolSuite) TestAssignUnitPoolConstraints(c *gc.C) {
+	err := s.wordpress.SetConstraints(constraints.MustParse("mem=4G"))
+	c.Assert(err, jc.ErrorIsNil)
+	small := s.addPoolMachine(c, "arch=amd64 mem=2G")
+	big := s.addPoolMachine(c, "arch=amd64 mem=8G")
+
+	unit, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := unit.AssignToPoolMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Id(), gc.Equals, big.Id())
+
+	unit, err = s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = unit.AssignToPoolMachine()
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/1" to pool machine: no suitable machines available in the pool`)
+
+	pool, err := s.State.PoolMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pool, jc.DeepEquals, []state.PoolMachine{
+		{MachineId: small.Id()},
+		{MachineId: big.Id(), Unit: "wordpress/0"},
+	})
+}
+
+func (s *MachinePoolSuite) TestDestroyUnitReturnsMachineToPool(c *gc.C) {
+	m := s.addPoolMachine(c, "arch=amd64")
+	unit, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = unit.AssignToPoolMachine()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = unit.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	assertLife(c, m, state.Alive)
+
+	pool, err := s.State.PoolMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pool, jc.DeepEquals, []state.PoolMachine{{MachineId: m.Id()}})
+}
+
+func (s *MachinePoolSuite) TestUnassignReturnsMachineToPool(c *gc.C) {
+	m := s.addPoolMachine(c, "arch=amd64")
+	unit, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = unit.AssignToPoolMachine()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = unit.UnassignFromMachine()
+	c.Assert(err, jc.ErrorIsNil)
+
+	pool, err := s.State.PoolMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pool, jc.DeepEquals, []state.PoolMachine{{MachineId: m.Id()}})
+}
+
+func (s *MachinePoolSuite) TestRemoveMachineRemovesFromPool(c *gc.C) {
+	m := s.addPoolMachine(c, "arch=amd64")
+	err := m.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	pool, err := s.State.PoolMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pool, gc.HasLen, 0)
+}
@@ -0,0 +1,153 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// migrationAnnotationPrefix prefixes the annotation keys under which
+// data that the model description has no place for is carried across
+// a migration. The exporter adds the keys to the annotations of the
+// entities the data belongs to, and the importer takes them out again
+// before setting the annotations, so they are never seen by users.
+const migrationAnnotationPrefix = "juju-migration-"
+
+const (
+	// affinityAnnotation names the migration annotation that carries
+	// the affinity policy of an application, or the affinity
+	// violations of a unit, as an affinityRecord.
+	affinityAnnotation = "affinity"
+
+	// departureBarriersAnnotation names the migration annotation that
+	// carries, on the model, the departure barriers of relations, as
+	// a map of departureBarrierRecords keyed on relation key.
+	departureBarriersAnnotation = "departure-barriers"
+
+	// exposedEndpointsAnnotation names the migration annotation that
+	// carries the expose settings of an application's endpoints, as a
+	// map of exposedEndpointRecords keyed on endpoint name.
+	exposedEndpointsAnnotation = "exposed-endpoints"
+
+	// portEndpointsAnnotation names the migration annotation that
+	// carries the endpoints that a machine's port ranges were opened
+	// for, as a slice of portEndpointRecords.
+	portEndpointsAnnotation = "port-endpoints"
+
+	// agentVersionPinAnnotation names the migration annotation that
+	// carries the agent version pin of a machine or application, as
+	// an agentVersionPinRecord.
+	agentVersionPinAnnotation = "agent-version-pin"
+
+	// machinePoolAnnotation names the migration annotation that
+	// carries a machine's membership of the model's machine pool, as
+	// a machinePoolRecord.
+	machinePoolAnnotation = "machine-pool"
+
+	// retainedStorageAnnotation names the migration annotation that
+	// carries, on the model, the application that each retained
+	// storage instance was retained from, keyed on storage id.
+	retainedStorageAnnotation = "retained-storage"
+)
+
+// affinityRecord is the form in which an affinity record is carried
+// across a migration.
+type affinityRecord struct {
+	NotWith    []string `json:"not-with,omitempty"`
+	SameZoneAs []string `json:"same-zone-as,omitempty"`
+	Violations []string `json:"violations,omitempty"`
+}
+
+// departureBarrierRecord is the form in which a relation's departure
+// barrier is carried across a migration. Times are in nanoseconds.
+type departureBarrierRecord struct {
+	Timeout   int64    `json:"timeout"`
+	Set       int64    `json:"set"`
+	Confirmed []string `json:"confirmed,omitempty"`
+}
+
+// exposedEndpointRecord is the form in which the expose settings of an
+// application endpoint are carried across a migration.
+type exposedEndpointRecord struct {
+	ExposeToSpaces []string `json:"to-spaces,omitempty"`
+	ExposeToCIDRs  []string `json:"to-cidrs,omitempty"`
+}
+
+// portEndpointRecord is the form in which the endpoint that a port
+// range was opened for is carried across a migration.
+type portEndpointRecord struct {
+	SubnetID string `json:"subnet-id,omitempty"`
+	UnitName string `json:"unit"`
+	FromPort int    `json:"from-port"`
+	ToPort   int    `json:"to-port"`
+	Protocol string `json:"protocol"`
+	Endpoint string `json:"endpoint"`
+}
+
+// agentVersionPinRecord is the form in which an agent version pin is
+// carried across a migration.
+type agentVersionPinRecord struct {
+	Min string `json:"min,omitempty"`
+	Max string `json:"max,omitempty"`
+}
+
+// machinePoolRecord is the form in which a machine's membership of the
+// model's machine pool is carried across a migration.
+type machinePoolRecord struct {
+	Unit string `json:"unit,omitempty"`
+}
+
+// addMigrationAnnotation returns a copy of the supplied annotations
+// with the JSON encoding of value stored under the named migration
+// key.
+func addMigrationAnnotation(annotations map[string]string, name string, value interface{}) (map[string]string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.Annotatef(err, "encoding %s", name)
+	}
+	result := make(map[string]string, len(annotations)+1)
+	for key, value := range annotations {
+		result[key] = value
+	}
+	result[migrationAnnotationPrefix+name] = string(data)
+	return result, nil
+}
+
+// splitMigrationAnnotations separates the migration keys from the
+// supplied annotations. It returns the remaining annotations, and the
+// values of the migration keys by name.
+func splitMigrationAnnotations(annotations map[string]string) (map[string]string, map[string]string) {
+	var remaining, carried map[string]string
+	for key, value := range annotations {
+		if !strings.HasPrefix(key, migrationAnnotationPrefix) {
+			if remaining == nil {
+				remaining = make(map[string]string)
+			}
+			remaining[key] = value
+			continue
+		}
+		if carried == nil {
+			carried = make(map[string]string)
+		}
+		carried[strings.TrimPrefix(key, migrationAnnotationPrefix)] = value
+	}
+	return remaining, carried
+}
+
+// decodeMigrationAnnotation decodes the named value, as returned by
+// splitMigrationAnnotations, into value. It reports whether the value
+// was carried at all.
+func decodeMigrationAnnotation(carried map[string]string, name string, value interface{}) (bool, error) {
+	data, ok := carried[name]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal([]byte(data), value); err != nil {
+		return false, errors.Annotatef(err, "decoding %s", name)
+	}
+	return true, nil
+}
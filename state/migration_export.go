@@ -84,6 +84,15 @@ func (st *State) exportImpl(cfg ExportConfig) (description.Model, error) {
 	if err := export.readAllConstraints(); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err := export.readAllAffinity(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := export.readMachinePool(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := export.readAllAgentVersionPins(); err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	modelConfig, found := export.modelSettings[modelGlobalKey]
 	if !found && !cfg.SkipSettings {
@@ -207,7 +216,10 @@ type exporter struct {
 	model   description.Model
 	logger  loggo.Logger
 
+	affinity                map[string]affinityDoc
+	agentVersionPins        map[string]agentVersionPinDoc
 	annotations             map[string]annotatorDoc
+	machinePool             map[string]machinePoolDoc
 	constraints             map[string]bson.M
 	modelSettings           map[string]settingsDoc
 	modelStorageConstraints map[string]storageConstraintsDoc
@@ -469,7 +481,27 @@ func (e *exporter) newMachine(exParent description.Machine, machine *Machine, in
 		exMachine.AddOpenedPorts(args)
 	}
 
-	exMachine.SetAnnotations(e.getAnnotations(globalKey))
+	annotations, err := e.withAgentVersionPin(globalKey, e.getAnnotations(globalKey))
+	if err != nil {
+		return nil, errors.Annotatef(err, "agent version pin for machine %s", machine.Id())
+	}
+	if portEndpoints := portEndpointsForMachine(machine.Id(), portsData); len(portEndpoints) > 0 {
+		// The model description has no place for the endpoints
+		// that port ranges were opened for.
+		annotations, err = addMigrationAnnotation(annotations, portEndpointsAnnotation, portEndpoints)
+		if err != nil {
+			return nil, errors.Annotatef(err, "port endpoints for machine %s", machine.Id())
+		}
+	}
+	if doc, found := e.machinePool[machine.Id()]; found {
+		annotations, err = addMigrationAnnotation(annotations, machinePoolAnnotation, machinePoolRecord{
+			Unit: doc.Unit,
+		})
+		if err != nil {
+			return nil, errors.Annotatef(err, "pool membership of machine %s", machine.Id())
+		}
+	}
+	exMachine.SetAnnotations(annotations)
 
 	constraintsArgs, err := e.constraintsArgs(globalKey)
 	if err != nil {
@@ -500,6 +532,31 @@ func (e *exporter) openedPortsArgsForMachine(machineId string, portsData []ports
 	return result
 }
 
+// portEndpointsForMachine returns the port ranges opened on the machine
+// for specific endpoints.
+func portEndpointsForMachine(machineId string, portsData []portsDoc) []portEndpointRecord {
+	var result []portEndpointRecord
+	for _, doc := range portsData {
+		if doc.MachineID != machineId {
+			continue
+		}
+		for _, p := range doc.Ports {
+			if p.Endpoint == "" {
+				continue
+			}
+			result = append(result, portEndpointRecord{
+				SubnetID: doc.SubnetID,
+				UnitName: p.UnitName,
+				FromPort: p.FromPort,
+				ToPort:   p.ToPort,
+				Protocol: p.Protocol,
+				Endpoint: p.Endpoint,
+			})
+		}
+	}
+	return result
+}
+
 func (e *exporter) newAddressArgsSlice(a []address) []description.AddressArgs {
 	result := []description.AddressArgs{}
 	for _, addr := range a {
@@ -703,7 +760,30 @@ func (e *exporter) addApplication(ctx addApplicationContext) error {
 	}
 	exApplication.SetStatus(statusArgs)
 	exApplication.SetStatusHistory(e.statusHistoryArgs(globalKey))
-	exApplication.SetAnnotations(e.getAnnotations(globalKey))
+	annotations, err := e.withAffinity(globalKey, e.getAnnotations(globalKey))
+	if err != nil {
+		return errors.Annotatef(err, "affinity for application %s", appName)
+	}
+	annotations, err = e.withAgentVersionPin(globalKey, annotations)
+	if err != nil {
+		return errors.Annotatef(err, "agent version pin for application %s", appName)
+	}
+	if len(application.doc.ExposedEndpoints) > 0 {
+		// The model description only records whether the
+		// application is exposed.
+		exposed := make(map[string]exposedEndpointRecord)
+		for name, settings := range application.doc.ExposedEndpoints {
+			exposed[name] = exposedEndpointRecord{
+				ExposeToSpaces: settings.ExposeToSpaces,
+				ExposeToCIDRs:  settings.ExposeToCIDRs,
+			}
+		}
+		annotations, err = addMigrationAnnotation(annotations, exposedEndpointsAnnotation, exposed)
+		if err != nil {
+			return errors.Annotatef(err, "expose settings for application %s", appName)
+		}
+	}
+	exApplication.SetAnnotations(annotations)
 
 	constraintsArgs, err := e.constraintsArgs(globalKey)
 	if err != nil {
@@ -781,7 +861,11 @@ func (e *exporter) addApplication(ctx addApplicationContext) error {
 			SHA256:  tools.SHA256,
 			Size:    tools.Size,
 		})
-		exUnit.SetAnnotations(e.getAnnotations(globalKey))
+		annotations, err := e.withAffinity(globalKey, e.getAnnotations(globalKey))
+		if err != nil {
+			return errors.Annotatef(err, "affinity for unit %s", unit.Name())
+		}
+		exUnit.SetAnnotations(annotations)
 
 		constraintsArgs, err := e.constraintsArgs(agentKey)
 		if err != nil {
@@ -902,11 +986,21 @@ func (e *exporter) relations() error {
 	for _, a := range e.model.RemoteApplications() {
 		remoteApps.Add(a.Name())
 	}
+	barriers := make(map[string]departureBarrierRecord)
 	for _, relation := range rels {
 		exRelation := e.model.AddRelation(description.RelationArgs{
 			Id:  relation.Id(),
 			Key: relation.String(),
 		})
+		if doc := relation.doc.DepartureBarrier; doc != nil {
+			// The model description has no place for
+			// departure barriers.
+			barriers[relation.String()] = departureBarrierRecord{
+				Timeout:   doc.Timeout,
+				Set:       doc.Set,
+				Confirmed: doc.Confirmed,
+			}
+		}
 		globalKey := relation.globalScope()
 		statusArgs, err := e.statusArgs(globalKey)
 		if err == nil {
@@ -968,6 +1062,9 @@ func (e *exporter) relations() error {
 			}
 		}
 	}
+	if len(barriers) > 0 {
+		return errors.Trace(e.addModelMigrationAnnotation(departureBarriersAnnotation, barriers))
+	}
 	return nil
 }
 
@@ -1245,11 +1342,8 @@ func (e *exporter) readAllAnnotations() error {
 		return nil
 	}
 
-	annotations, closer := e.st.db().GetCollection(annotationsC)
-	defer closer()
-
-	var docs []annotatorDoc
-	if err := annotations.Find(nil).All(&docs); err != nil {
+	docs, err := e.st.db().DocumentStore().listAnnotationsDocs()
+	if err != nil {
 		return errors.Trace(err)
 	}
 	e.logger.Debugf("read %d annotations docs", len(docs))
@@ -1260,6 +1354,84 @@ func (e *exporter) readAllAnnotations() error {
 	return nil
 }
 
+func (e *exporter) readAllAffinity() error {
+	coll, closer := e.st.db().GetCollection(affinityC)
+	defer closer()
+
+	var docs []affinityDoc
+	if err := coll.Find(nil).All(&docs); err != nil {
+		return errors.Annotate(err, "failed to read affinity collection")
+	}
+	e.logger.Debugf("read %d affinity docs", len(docs))
+	e.affinity = make(map[string]affinityDoc)
+	for _, doc := range docs {
+		e.affinity[e.st.localID(doc.DocID)] = doc
+	}
+	return nil
+}
+
+func (e *exporter) readMachinePool() error {
+	coll, closer := e.st.db().GetCollection(machinePoolC)
+	defer closer()
+
+	var docs []machinePoolDoc
+	if err := coll.Find(nil).All(&docs); err != nil {
+		return errors.Annotate(err, "failed to read machine pool collection")
+	}
+	e.logger.Debugf("read %d machine pool docs", len(docs))
+	e.machinePool = make(map[string]machinePoolDoc)
+	for _, doc := range docs {
+		e.machinePool[doc.MachineId] = doc
+	}
+	return nil
+}
+
+// withAffinity returns the supplied annotations of the entity with the
+// supplied global key, with its affinity record added to them.
+func (e *exporter) withAffinity(globalKey string, annotations map[string]string) (map[string]string, error) {
+	doc, found := e.affinity[globalKey]
+	if !found {
+		return annotations, nil
+	}
+	delete(e.affinity, globalKey)
+	return addMigrationAnnotation(annotations, affinityAnnotation, affinityRecord{
+		NotWith:    doc.NotWith,
+		SameZoneAs: doc.SameZoneAs,
+		Violations: doc.Violations,
+	})
+}
+
+func (e *exporter) readAllAgentVersionPins() error {
+	coll, closer := e.st.db().GetCollection(agentVersionPinsC)
+	defer closer()
+
+	var docs []agentVersionPinDoc
+	if err := coll.Find(nil).All(&docs); err != nil {
+		return errors.Annotate(err, "failed to read agent version pins collection")
+	}
+	e.logger.Debugf("read %d agent version pin docs", len(docs))
+	e.agentVersionPins = make(map[string]agentVersionPinDoc)
+	for _, doc := range docs {
+		e.agentVersionPins[e.st.localID(doc.DocID)] = doc
+	}
+	return nil
+}
+
+// withAgentVersionPin returns the supplied annotations of the machine
+// or application with the supplied global key, with its agent version
+// pin added to them.
+func (e *exporter) withAgentVersionPin(globalKey string, annotations map[string]string) (map[string]string, error) {
+	doc, found := e.agentVersionPins[globalKey]
+	if !found {
+		return annotations, nil
+	}
+	delete(e.agentVersionPins, globalKey)
+	return addMigrationAnnotation(annotations, agentVersionPinAnnotation, agentVersionPinRecord{
+		Min: doc.Min,
+		Max: doc.Max,
+	})
+}
+
 func (e *exporter) readAllConstraints() error {
 	constraintsCollection, closer := e.st.db().GetCollection(constraintsC)
 	defer closer()
@@ -1304,11 +1476,8 @@ func (e *exporter) readAllSettings() error {
 		return nil
 	}
 
-	settings, closer := e.st.db().GetCollection(settingsC)
-	defer closer()
-
-	var docs []settingsDoc
-	if err := settings.Find(nil).All(&docs); err != nil {
+	docs, err := e.st.db().DocumentStore().listSettingsDocs(settingsC, e.st.docID(""))
+	if err != nil {
 		return errors.Trace(err)
 	}
 
@@ -1320,11 +1489,8 @@ func (e *exporter) readAllSettings() error {
 }
 
 func (e *exporter) readAllStatuses() error {
-	statuses, closer := e.st.db().GetCollection(statusesC)
-	defer closer()
-
 	var docs []bson.M
-	err := statuses.Find(nil).All(&docs)
+	err := e.st.db().DocumentStore().listStatusDocs(&docs)
 	if err != nil {
 		return errors.Annotate(err, "failed to read status collection")
 	}
@@ -1359,6 +1525,9 @@ func (e *exporter) readAllStatusHistory() error {
 	iter := statuses.Find(nil).Sort("-updated", "-_id").Iter()
 	defer iter.Close()
 	for iter.Next(&doc) {
+		if err := doc.decompress(); err != nil {
+			return errors.Trace(err)
+		}
 		history := e.statusHistory[doc.GlobalKey]
 		e.statusHistory[doc.GlobalKey] = append(history, doc)
 		count++
@@ -1513,6 +1682,14 @@ func (e *exporter) checkUnexportedValues() error {
 		missing = append(missing, fmt.Sprintf("unexported settings for %s", key))
 	}
 
+	for key := range e.affinity {
+		missing = append(missing, fmt.Sprintf("unexported affinity for %s", key))
+	}
+
+	for key := range e.agentVersionPins {
+		missing = append(missing, fmt.Sprintf("unexported agent version pin for %s", key))
+	}
+
 	for key := range e.status {
 		missing = append(missing, fmt.Sprintf("unexported status for %s", key))
 	}
@@ -1833,6 +2010,7 @@ func (e *exporter) storageInstances() error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	retained := make(map[string]string)
 	var doc storageInstanceDoc
 	iter := coll.Find(nil).Sort("_id").Iter()
 	defer iter.Close()
@@ -1841,10 +2019,27 @@ func (e *exporter) storageInstances() error {
 		if err := e.addStorage(instance, attachments[doc.Id]); err != nil {
 			return errors.Trace(err)
 		}
+		if doc.RetainedFrom != "" {
+			retained[doc.Id] = doc.RetainedFrom
+		}
 	}
 	if err := iter.Err(); err != nil {
 		return errors.Annotate(err, "failed to read storage instances")
 	}
+	if len(retained) > 0 {
+		return errors.Trace(e.addModelMigrationAnnotation(retainedStorageAnnotation, retained))
+	}
+	return nil
+}
+
+// addModelMigrationAnnotation carries the value across the migration
+// in the annotations of the model.
+func (e *exporter) addModelMigrationAnnotation(name string, value interface{}) error {
+	annotations, err := addMigrationAnnotation(e.model.Annotations(), name, value)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	e.model.SetAnnotations(annotations)
 	return nil
 }
 
@@ -241,6 +241,9 @@ type importer struct {
 	// applicationUnits is populated at the end of loading the applications, and is a
 	// map of application name to the units of that application.
 	applicationUnits map[string]map[string]*Unit
+	// modelCarried holds the values carried across the migration in
+	// the model's annotations, as returned by splitMigrationAnnotations.
+	modelCarried map[string]string
 }
 
 func (i *importer) modelExtras() error {
@@ -250,7 +253,9 @@ func (i *importer) modelExtras() error {
 		}
 	}
 
-	if annotations := i.model.Annotations(); len(annotations) > 0 {
+	annotations, carried := splitMigrationAnnotations(i.model.Annotations())
+	i.modelCarried = carried
+	if len(annotations) > 0 {
 		if err := i.im.SetAnnotations(i.dbModel, annotations); err != nil {
 			return errors.Trace(err)
 		}
@@ -422,14 +427,38 @@ func (i *importer) machine(m description.Machine) error {
 	ops := append(prereqOps, machineOp)
 
 	// 5. add any ops that we may need to add the opened ports information.
-	ops = append(ops, i.machinePortsOps(m)...)
+	annotations, carried := splitMigrationAnnotations(m.Annotations())
+	var portEndpoints []portEndpointRecord
+	if _, err := decodeMigrationAnnotation(carried, portEndpointsAnnotation, &portEndpoints); err != nil {
+		return errors.Annotatef(err, "port endpoints for machine %s", m.Id())
+	}
+	ops = append(ops, i.machinePortsOps(m, portEndpoints)...)
+	pinOps, err := i.agentVersionPinOps(machineGlobalKey(mdoc.Id), m.Tag().String(), carried)
+	if err != nil {
+		return errors.Annotatef(err, "agent version pin for machine %s", m.Id())
+	}
+	ops = append(ops, pinOps...)
+	var pool machinePoolRecord
+	if found, err := decodeMigrationAnnotation(carried, machinePoolAnnotation, &pool); err != nil {
+		return errors.Annotatef(err, "pool membership of machine %s", m.Id())
+	} else if found {
+		ops = append(ops, txn.Op{
+			C:      machinePoolC,
+			Id:     mdoc.DocID,
+			Assert: txn.DocMissing,
+			Insert: &machinePoolDoc{
+				MachineId: mdoc.Id,
+				Unit:      pool.Unit,
+			},
+		})
+	}
 
 	if err := i.st.db().RunTransaction(ops); err != nil {
 		return errors.Trace(err)
 	}
 
 	machine := newMachine(i.st, mdoc)
-	if annotations := m.Annotations(); len(annotations) > 0 {
+	if len(annotations) > 0 {
 		if err := i.im.SetAnnotations(machine, annotations); err != nil {
 			return errors.Trace(err)
 		}
@@ -478,10 +507,19 @@ func (i *importer) importMachineBlockDevices(machine *Machine, m description.Mac
 	return nil
 }
 
-func (i *importer) machinePortsOps(m description.Machine) []txn.Op {
+// machinePortsOps returns the operations that record the machine's
+// opened ports, with the endpoints they were opened for as carried in
+// portEndpoints.
+func (i *importer) machinePortsOps(m description.Machine, portEndpoints []portEndpointRecord) []txn.Op {
 	var result []txn.Op
 	machineID := m.Id()
 
+	endpoints := make(map[portEndpointRecord]string)
+	for _, record := range portEndpoints {
+		endpoint := record.Endpoint
+		record.Endpoint = ""
+		endpoints[record] = endpoint
+	}
 	for _, ports := range m.OpenedPorts() {
 		subnetID := ports.SubnetID()
 		doc := &portsDoc{
@@ -494,6 +532,13 @@ func (i *importer) machinePortsOps(m description.Machine) []txn.Op {
 				FromPort: opened.FromPort(),
 				ToPort:   opened.ToPort(),
 				Protocol: opened.Protocol(),
+				Endpoint: endpoints[portEndpointRecord{
+					SubnetID: subnetID,
+					UnitName: opened.UnitName(),
+					FromPort: opened.FromPort(),
+					ToPort:   opened.ToPort(),
+					Protocol: opened.Protocol(),
+				}],
 			})
 		}
 		result = append(result, txn.Op{
@@ -740,6 +785,20 @@ func (i *importer) application(a description.Application) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	annotations, carried := splitMigrationAnnotations(a.Annotations())
+	var exposed map[string]exposedEndpointRecord
+	if _, err := decodeMigrationAnnotation(carried, exposedEndpointsAnnotation, &exposed); err != nil {
+		return errors.Annotatef(err, "expose settings for application %s", a.Name())
+	}
+	for name, settings := range exposed {
+		if appDoc.ExposedEndpoints == nil {
+			appDoc.ExposedEndpoints = make(map[string]ExposedEndpoint)
+		}
+		appDoc.ExposedEndpoints[name] = ExposedEndpoint{
+			ExposeToSpaces: settings.ExposeToSpaces,
+			ExposeToCIDRs:  settings.ExposeToCIDRs,
+		}
+	}
 	app := newApplication(i.st, appDoc)
 
 	// 2. construct a statusDoc
@@ -780,11 +839,22 @@ func (i *importer) application(a description.Application) error {
 
 	ops = append(ops, i.appResourceOps(a)...)
 
+	affinityOps, err := i.affinityOps(app.globalKey(), carried)
+	if err != nil {
+		return errors.Annotatef(err, "affinity for application %s", a.Name())
+	}
+	ops = append(ops, affinityOps...)
+	pinOps, err := i.agentVersionPinOps(app.globalKey(), app.Tag().String(), carried)
+	if err != nil {
+		return errors.Annotatef(err, "agent version pin for application %s", a.Name())
+	}
+	ops = append(ops, pinOps...)
+
 	if err := i.st.db().RunTransaction(ops); err != nil {
 		return errors.Trace(err)
 	}
 
-	if annotations := a.Annotations(); len(annotations) > 0 {
+	if len(annotations) > 0 {
 		if err := i.im.SetAnnotations(app, annotations); err != nil {
 			return errors.Trace(err)
 		}
@@ -881,6 +951,48 @@ func (i *importer) storageConstraints(cons map[string]description.StorageConstra
 	return result
 }
 
+// affinityOps returns the operations that record the affinity policy
+// or violations carried, as returned by splitMigrationAnnotations, in
+// the annotations of the entity with the supplied global key.
+func (i *importer) affinityOps(globalKey string, carried map[string]string) ([]txn.Op, error) {
+	var record affinityRecord
+	found, err := decodeMigrationAnnotation(carried, affinityAnnotation, &record)
+	if err != nil || !found {
+		return nil, errors.Trace(err)
+	}
+	return []txn.Op{{
+		C:      affinityC,
+		Id:     globalKey,
+		Assert: txn.DocMissing,
+		Insert: &affinityDoc{
+			NotWith:    record.NotWith,
+			SameZoneAs: record.SameZoneAs,
+			Violations: record.Violations,
+		},
+	}}, nil
+}
+
+// agentVersionPinOps returns the operations that set the agent version
+// pin carried, as returned by splitMigrationAnnotations, for the machine
+// or application with the supplied global key and tag.
+func (i *importer) agentVersionPinOps(globalKey, tag string, carried map[string]string) ([]txn.Op, error) {
+	var record agentVersionPinRecord
+	found, err := decodeMigrationAnnotation(carried, agentVersionPinAnnotation, &record)
+	if err != nil || !found {
+		return nil, errors.Trace(err)
+	}
+	return []txn.Op{{
+		C:      agentVersionPinsC,
+		Id:     globalKey,
+		Assert: txn.DocMissing,
+		Insert: &agentVersionPinDoc{
+			Entity: tag,
+			Min:    record.Min,
+			Max:    record.Max,
+		},
+	}}, nil
+}
+
 func (i *importer) unit(s description.Application, u description.Unit) error {
 	i.logger.Debugf("importing unit %s", u.Name())
 
@@ -945,13 +1057,20 @@ func (i *importer) unit(s description.Application, u description.Unit) error {
 		ops = append(ops, createConstraintsOp(agentGlobalKey, i.constraints(cons)))
 	}
 
+	annotations, carried := splitMigrationAnnotations(u.Annotations())
+	affinityOps, err := i.affinityOps(unitGlobalKey(u.Name()), carried)
+	if err != nil {
+		return errors.Annotatef(err, "affinity for unit %s", u.Name())
+	}
+	ops = append(ops, affinityOps...)
+
 	if err := i.st.db().RunTransaction(ops); err != nil {
 		i.logger.Debugf("failed ops: %#v", ops)
 		return errors.Trace(err)
 	}
 
 	unit := newUnit(i.st, udoc)
-	if annotations := u.Annotations(); len(annotations) > 0 {
+	if len(annotations) > 0 {
 		if err := i.im.SetAnnotations(unit, annotations); err != nil {
 			return errors.Trace(err)
 		}
@@ -1079,8 +1198,12 @@ func (i *importer) unitStorageAttachmentCount(unit names.UnitTag) int {
 
 func (i *importer) relations() error {
 	i.logger.Debugf("importing relations")
+	var barriers map[string]departureBarrierRecord
+	if _, err := decodeMigrationAnnotation(i.modelCarried, departureBarriersAnnotation, &barriers); err != nil {
+		return errors.Trace(err)
+	}
 	for _, r := range i.model.Relations() {
-		if err := i.relation(r); err != nil {
+		if err := i.relation(r, barriers); err != nil {
 			i.logger.Errorf("error importing relation %s: %s", r.Key(), err)
 			return errors.Annotate(err, r.Key())
 		}
@@ -1090,8 +1213,15 @@ func (i *importer) relations() error {
 	return nil
 }
 
-func (i *importer) relation(rel description.Relation) error {
+func (i *importer) relation(rel description.Relation, barriers map[string]departureBarrierRecord) error {
 	relationDoc := i.makeRelationDoc(rel)
+	if barrier, ok := barriers[rel.Key()]; ok {
+		relationDoc.DepartureBarrier = &departureBarrierDoc{
+			Timeout:   barrier.Timeout,
+			Set:       barrier.Set,
+			Confirmed: barrier.Confirmed,
+		}
+	}
 	ops := []txn.Op{
 		{
 			C:      relationsC,
@@ -1465,13 +1595,17 @@ func (i *importer) addAction(action description.Action) error {
 func (i *importer) importStatusHistory(globalKey string, history []description.Status) error {
 	docs := make([]interface{}, len(history))
 	for i, statusVal := range history {
-		docs[i] = historicalStatusDoc{
+		doc := historicalStatusDoc{
 			GlobalKey:  globalKey,
 			Status:     status.Status(statusVal.Value()),
 			StatusInfo: statusVal.Message(),
 			StatusData: statusVal.Data(),
 			Updated:    statusVal.Updated().UnixNano(),
 		}
+		if err := doc.compress(); err != nil {
+			return errors.Trace(err)
+		}
+		docs[i] = doc
 	}
 	if len(docs) == 0 {
 		return nil
@@ -1543,8 +1677,12 @@ func (i *importer) storage() error {
 
 func (i *importer) storageInstances() error {
 	i.logger.Debugf("importing storage instances")
+	var retained map[string]string
+	if _, err := decodeMigrationAnnotation(i.modelCarried, retainedStorageAnnotation, &retained); err != nil {
+		return errors.Trace(err)
+	}
 	for _, storage := range i.model.Storages() {
-		err := i.addStorageInstance(storage)
+		err := i.addStorageInstance(storage, retained[storage.Tag().Id()])
 		if err != nil {
 			i.logger.Errorf("error importing storage %s: %s", storage.Tag(), err)
 			return errors.Trace(err)
@@ -1554,7 +1692,7 @@ func (i *importer) storageInstances() error {
 	return nil
 }
 
-func (i *importer) addStorageInstance(storage description.Storage) error {
+func (i *importer) addStorageInstance(storage description.Storage, retainedFrom string) error {
 	kind := parseStorageKind(storage.Kind())
 	if kind == StorageKindUnknown {
 		return errors.Errorf("storage kind %q is unknown", storage.Kind())
@@ -1580,6 +1718,7 @@ func (i *importer) addStorageInstance(storage description.Storage) error {
 		StorageName:     storage.Name(),
 		AttachmentCount: len(attachments),
 		Constraints:     i.storageInstanceConstraints(storage),
+		RetainedFrom:    retainedFrom,
 	}
 	ops = append(ops, txn.Op{
 		C:      storageInstancesC,
@@ -379,6 +379,49 @@ func (s *MigrationImportSuite) TestMachines(c *gc.C) {
 	c.Assert(newCons.String(), gc.Equals, cons.String())
 }
 
+func (s *MigrationImportSuite) TestMachinePool(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, nil)
+	err := machine.AddToPool()
+	c.Assert(err, jc.ErrorIsNil)
+
+	newModel, newSt := s.importModel(c)
+
+	pool, err := newSt.PoolMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pool, jc.DeepEquals, []state.PoolMachine{{MachineId: machine.Id()}})
+
+	// The carrier annotation is not left on the machine.
+	newMachine, err := newSt.Machine(machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	annotations, err := newModel.Annotations(newMachine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(annotations, gc.HasLen, 0)
+}
+
+func (s *MigrationImportSuite) TestAgentVersionPins(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, nil)
+	machinePin := state.AgentVersionPin{Max: version.MustParse("2.4.1")}
+	err := machine.SetAgentVersionPin(machinePin)
+	c.Assert(err, jc.ErrorIsNil)
+	app := s.Factory.MakeApplication(c, nil)
+	appPin := state.AgentVersionPin{Min: version.MustParse("2.3.0"), Max: version.MustParse("2.4.0")}
+	err = app.SetAgentVersionPin(appPin)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, newSt := s.importModel(c)
+
+	newMachine, err := newSt.Machine(machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	pin, err := newMachine.AgentVersionPin()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pin, gc.Equals, machinePin)
+	newApp, err := newSt.Application(app.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	pin, err = newApp.AgentVersionPin()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pin, gc.Equals, appPin)
+}
+
 func (s *MigrationImportSuite) TestMachineDevices(c *gc.C) {
 	machine := s.Factory.MakeMachine(c, nil)
 	// Create two devices, first with all fields set, second just to show that
@@ -726,6 +769,36 @@ func (s *MigrationImportSuite) TestEndpointBindings(c *gc.C) {
 	c.Assert(bindings["db"], gc.Equals, "one")
 }
 
+func (s *MigrationImportSuite) TestAffinity(c *gc.C) {
+	mysql := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "mysql"}),
+	})
+	wordpress := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+	})
+	err := wordpress.SetAffinity(state.AffinityPolicy{NotWith: []string{"mysql"}})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.Model.SetAnnotations(wordpress, testAnnotations)
+	c.Assert(err, jc.ErrorIsNil)
+	machine := s.Factory.MakeMachine(c, nil)
+	s.Factory.MakeUnit(c, &factory.UnitParams{Application: mysql, Machine: machine})
+	s.Factory.MakeUnit(c, &factory.UnitParams{Application: wordpress, Machine: machine})
+
+	newModel, newSt := s.importModel(c)
+
+	newWordpress, err := newSt.Application("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	policy, err := newWordpress.Affinity()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, jc.DeepEquals, state.AffinityPolicy{NotWith: []string{"mysql"}})
+	violations, err := newWordpress.AffinityViolations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(violations, jc.DeepEquals, map[string][]string{
+		"wordpress/0": {"shares machine " + machine.Id() + " with mysql/0"},
+	})
+	s.assertAnnotations(c, newModel, newWordpress)
+}
+
 func (s *MigrationImportSuite) TestUnitsOpenPorts(c *gc.C) {
 	unit := s.Factory.MakeUnit(c, nil)
 	err := unit.OpenPorts("tcp", 1234, 2345)
@@ -748,6 +821,75 @@ func (s *MigrationImportSuite) TestUnitsOpenPorts(c *gc.C) {
 	})
 }
 
+func (s *MigrationImportSuite) TestRelationDepartureBarrier(c *gc.C) {
+	wordpress := state.AddTestingApplication(c, s.State, "wordpress", state.AddTestingCharm(c, s.State, "wordpress"))
+	state.AddTestingApplication(c, s.State, "mysql", state.AddTestingCharm(c, s.State, "mysql"))
+	eps, err := s.State.InferEndpoints("mysql", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	wordpress_0 := s.Factory.MakeUnit(c, &factory.UnitParams{Application: wordpress})
+	ru, err := rel.Unit(wordpress_0)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.SetDepartureBarrier(10 * time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.ConfirmDeparture(wordpress_0.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	barrier, err := rel.DepartureBarrier()
+	c.Assert(err, jc.ErrorIsNil)
+
+	newModel, newSt := s.importModel(c)
+
+	newRel, err := newSt.KeyRelation(rel.String())
+	c.Assert(err, jc.ErrorIsNil)
+	newBarrier, err := newRel.DepartureBarrier()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newBarrier, jc.DeepEquals, barrier)
+
+	// The carrier annotation is not left on the model.
+	annotations, err := newModel.Annotations(newModel)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(annotations, gc.HasLen, 0)
+}
+
+func (s *MigrationImportSuite) TestExposedEndpoints(c *gc.C) {
+	wordpress := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+	})
+	exposed := map[string]state.ExposedEndpoint{
+		"url": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	}
+	err := wordpress.MergeExposeSettings(exposed)
+	c.Assert(err, jc.ErrorIsNil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: wordpress})
+	err = unit.OpenPortsForEndpoint("url", "tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.OpenPorts("tcp", 8080, 8080)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+
+	newModel, newSt := s.importModel(c)
+
+	newWordpress, err := newSt.Application("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newWordpress.IsExposed(), jc.IsTrue)
+	c.Assert(newWordpress.ExposedEndpoints(), jc.DeepEquals, exposed)
+	machine, err := newSt.Machine(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	ports, err := machine.OpenedPorts("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports.AllPortRangeInfo(), jc.DeepEquals, []state.PortRange{
+		{UnitName: unit.Name(), FromPort: 80, ToPort: 80, Protocol: "tcp", Endpoint: "url"},
+		{UnitName: unit.Name(), FromPort: 8080, ToPort: 8080, Protocol: "tcp"},
+	})
+	annotations, err := newModel.Annotations(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(annotations, gc.HasLen, 0)
+}
+
 func (s *MigrationImportSuite) TestSpaces(c *gc.C) {
 	space := s.Factory.MakeSpace(c, &factory.SpaceParams{
 		Name: "one", ProviderID: network.Id("provider"), IsPublic: true})
@@ -1220,6 +1362,25 @@ func (s *MigrationImportSuite) TestStorage(c *gc.C) {
 	c.Assert(attachments[0].Unit(), gc.Equals, u.UnitTag())
 }
 
+func (s *MigrationImportSuite) TestStorageRetainedFrom(c *gc.C) {
+	_, _, storageTag := s.makeUnitWithStorage(c)
+	err := state.SetStorageRetainedFrom(s.State, storageTag, "old-app")
+	c.Assert(err, jc.ErrorIsNil)
+
+	newModel, newSt := s.importModel(c)
+
+	newIM, err := newSt.IAASModel()
+	c.Assert(err, jc.ErrorIsNil)
+	instance, err := newIM.StorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(instance.RetainedFrom(), gc.Equals, "old-app")
+
+	// The carrier annotation is not left on the model.
+	annotations, err := newModel.Annotations(newModel)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(annotations, gc.HasLen, 0)
+}
+
 func (s *MigrationImportSuite) TestStorageDetached(c *gc.C) {
 	_, u, storageTag := s.makeUnitWithStorage(c)
 	err := u.Destroy()
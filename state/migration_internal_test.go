@@ -35,6 +35,10 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		instanceDataC,
 		machinesC,
 		openedPortsC,
+		// Carried in the annotations of machines.
+		machinePoolC,
+		// Carried in the annotations of machines and applications.
+		agentVersionPinsC,
 
 		// application / unit
 		applicationsC,
@@ -42,6 +46,8 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		meterStatusC, // red / green status for metrics of units
 		payloadsC,
 		"resources",
+		// Carried in the annotations of applications and units.
+		affinityC,
 
 		// relation
 		relationsC,
@@ -97,6 +103,9 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		// Users aren't migrated.
 		usersC,
 		userLastLoginC,
+		// Session revocations are controller global, and belong
+		// with the users.
+		sessionRevocationsC,
 		// Controller users contain extra data about users therefore
 		// are not migrated either.
 		controllerUsersC,
@@ -161,6 +170,26 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		// and are not to be migrated.
 		globalSettingsC,
 
+		// Config profiles are stored on the controller, and are not
+		// migrated.
+		configProfilesC,
+
+		// The offer catalog describes offers hosted by other
+		// controllers, and is not migrated.
+		offerCatalogC,
+
+		// The config profile applied to a model is not migrated; the
+		// values it set are migrated as part of the model config.
+		modelConfigProfileC,
+
+		// Teams are stored on the controller, and are not migrated.
+		// A model owned by a team is migrated with the access its
+		// members were granted, and is owned by its user owner.
+		teamsC,
+
+		// Ownership transfers were made in the source controller.
+		modelOwnershipTransfersC,
+
 		// The auditing collection stores a large amount of historical data
 		// and will be streamed across after migration in a similar way to
 		// logging.
@@ -183,6 +212,39 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		// independent global clock.
 		globalClockC,
 
+		// Unit moves are carried out by the source controller; the
+		// replacement units they add are migrated as ordinary
+		// units, but moves still in progress are not resumed.
+		unitMovesC,
+		// Likewise, evacuations of machines are not resumed.
+		machineEvacuationsC,
+
+		// Pending package updates are reported again by the
+		// machine agents once they run against the target.
+		machinePackageUpdatesC,
+
+		// Model config history is an audit trail of changes made
+		// in the source controller; the migrated config is the
+		// starting point for the target's history.
+		modelConfigHistoryC,
+
+		// Slow operation spans describe operations performed in
+		// the source controller.
+		slowSpansC,
+
+		// The inventory records the generations of the controller's
+		// exports of its inventory, and is not migrated.
+		inventoryC,
+
+		// The bastion key is generated again by the target
+		// controller, and installed on the machines by their
+		// agents.
+		bastionKeysC,
+
+		// Injected faults are for testing the source controller
+		// and are not migrated.
+		faultsC,
+
 		// Leases are not migrated either. When an application is migrated,
 		// we include the name of the leader unit. On import, a new lease
 		// is created for the leader unit.
@@ -234,6 +296,8 @@ func (s *MigrationSuite) TestModelDocFields(c *gc.C) {
 		"Type",
 		"MigrationMode",
 		"Owner",
+		// OwnerTeam is not migrated, as teams are not.
+		"OwnerTeam",
 		"Cloud",
 		"CloudRegion",
 		"CloudCredential",
@@ -241,6 +305,9 @@ func (s *MigrationSuite) TestModelDocFields(c *gc.C) {
 		"SLA",
 		"MeterStatus",
 		"EnvironVersion",
+		// EphemeralExpiry is not migrated; a migrated model is
+		// kept until its new controller's admins decide otherwise.
+		"EphemeralExpiry",
 	)
 	s.AssertExportedFields(c, modelDoc{}, fields)
 }
@@ -326,6 +393,31 @@ func (s *MigrationSuite) TestMachineDocFields(c *gc.C) {
 	s.AssertExportedFields(c, machineDoc{}, migrated.Union(ignored))
 }
 
+func (s *MigrationSuite) TestMachinePoolDocFields(c *gc.C) {
+	ignored := set.NewStrings(
+		"DocID",
+		"ModelUUID",
+	)
+	migrated := set.NewStrings(
+		"MachineId",
+		"Unit",
+	)
+	s.AssertExportedFields(c, machinePoolDoc{}, migrated.Union(ignored))
+}
+
+func (s *MigrationSuite) TestAgentVersionPinDocFields(c *gc.C) {
+	ignored := set.NewStrings(
+		"DocID",
+		"ModelUUID",
+		"Entity", // the tag of the machine or application
+	)
+	migrated := set.NewStrings(
+		"Min",
+		"Max",
+	)
+	s.AssertExportedFields(c, agentVersionPinDoc{}, migrated.Union(ignored))
+}
+
 func (s *MigrationSuite) TestInstanceDataFields(c *gc.C) {
 	ignored := set.NewStrings(
 		// KeepInstance is only set when a machine is
@@ -368,6 +460,10 @@ func (s *MigrationSuite) TestApplicationDocFields(c *gc.C) {
 		// RelationCount is handled by the number of times the application name
 		// appears in relation endpoints.
 		"RelationCount",
+		// ExposedEndpoints cannot be represented in the model
+		// description; export fails unless they expose the
+		// application to all, as Exposed does.
+		"ExposedEndpoints",
 	)
 	migrated := set.NewStrings(
 		"Name",
@@ -410,6 +506,15 @@ func (s *MigrationSuite) TestUnitDocFields(c *gc.C) {
 	s.AssertExportedFields(c, unitDoc{}, migrated.Union(ignored))
 }
 
+func (s *MigrationSuite) TestDepartureBarrierDocFields(c *gc.C) {
+	migrated := set.NewStrings(
+		"Timeout",
+		"Set",
+		"Confirmed",
+	)
+	s.AssertExportedFields(c, departureBarrierDoc{}, migrated)
+}
+
 func (s *MigrationSuite) TestPortsDocFields(c *gc.C) {
 	fields := set.NewStrings(
 		// DocID itself isn't migrated
@@ -457,6 +562,10 @@ func (s *MigrationSuite) TestRelationDocFields(c *gc.C) {
 		// UnitCount isn't explicitly exported, but defined by the stored
 		// unit settings data for the relation endpoint.
 		"UnitCount",
+		// DyingSince isn't exported, as only alive relations are.
+		"DyingSince",
+		// The departure barrier is carried in the model's annotations.
+		"DepartureBarrier",
 	)
 	s.AssertExportedFields(c, relationDoc{}, fields)
 	// We also need to check the Endpoint and nested charm.Relation field.
@@ -680,6 +789,9 @@ func (s *MigrationSuite) TestSSHHostKeyDocFields(c *gc.C) {
 func (s *MigrationSuite) TestActionDocFields(c *gc.C) {
 	ignored := set.NewStrings(
 		"ModelUUID",
+		// Correlation IDs only group the work done in
+		// the source model.
+		"CorrelationId",
 	)
 	migrated := set.NewStrings(
 		"DocId",
@@ -797,6 +909,7 @@ func (s *MigrationSuite) TestStorageInstanceDocFields(c *gc.C) {
 		"StorageName",
 		"AttachmentCount", // through count of attachment instances
 		"Constraints",
+		"RetainedFrom", // through the model's annotations
 	)
 	s.AssertExportedFields(c, storageInstanceDoc{}, migrated.Union(ignored))
 }
@@ -6,6 +6,7 @@ package state
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	jujutxn "github.com/juju/txn"
@@ -84,6 +85,7 @@ type modelDoc struct {
 	Type           ModelType     `bson:"type"`
 	Life           Life          `bson:"life"`
 	Owner          string        `bson:"owner"`
+	OwnerTeam      string        `bson:"owner-team,omitempty"`
 	ControllerUUID string        `bson:"controller-uuid"`
 	MigrationMode  MigrationMode `bson:"migration-mode"`
 
@@ -113,6 +115,10 @@ type modelDoc struct {
 
 	// MeterStatus is the current meter status of the model.
 	MeterStatus modelMeterStatusdoc `bson:"meter-status"`
+
+	// EphemeralExpiry, if non-zero, is the time in Unix nanoseconds
+	// after which the model is destroyed by the ephemeral reaper.
+	EphemeralExpiry int64 `bson:"ephemeral-expiry,omitempty"`
 }
 
 // slaLevel enumerates the support levels available to a model.
@@ -279,6 +285,16 @@ type ModelArgs struct {
 
 	// EnvironVersion is the initial version of the Environ for the model.
 	EnvironVersion int
+
+	// ConfigProfile, if not nil, is the config profile that was
+	// applied to the model config, holding only the values that were
+	// not overridden. It is recorded so that the model config reports
+	// which values the profile set.
+	ConfigProfile *ConfigProfile
+
+	// EphemeralExpiry, if not zero, is the time after which the
+	// model is destroyed, along with its storage.
+	EphemeralExpiry time.Time
 }
 
 // Validate validates the ModelArgs.
@@ -389,6 +405,7 @@ func (st *State) NewModel(args ModelArgs) (_ *Model, _ *State, err error) {
 		st.newPolicy,
 		st.clock(),
 		st.runTransactionObserver,
+		st.databaseBackend,
 	)
 	if err != nil {
 		return nil, nil, errors.Annotate(err, "could not create state for new model")
@@ -400,6 +417,14 @@ func (st *State) NewModel(args ModelArgs) (_ *Model, _ *State, err error) {
 	}()
 	newSt.controllerModelTag = st.controllerModelTag
 
+	// The spaces of an imported model are added after the model, so
+	// its default-space cannot be checked here.
+	if args.MigrationMode != MigrationModeImporting {
+		if err := newSt.validateDefaultSpace(args.Config, nil); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+
 	modelOps, modelStatusDoc, err := newSt.modelSetupOps(st.controllerTag.Id(), args, nil)
 	if err != nil {
 		return nil, nil, errors.Annotate(err, "failed to create new model")
@@ -620,7 +645,8 @@ func (m *Model) Life() Life {
 }
 
 // Owner returns tag representing the owner of the model.
-// The owner is the user that created the model.
+// The owner is the user that created the model, unless ownership
+// has since been transferred; see TransferOwnership.
 func (m *Model) Owner() names.UserTag {
 	return names.NewUserTag(m.doc.Owner)
 }
@@ -729,6 +755,42 @@ func (m *Model) SLACredential() []byte {
 	return m.doc.SLA.Credentials
 }
 
+// EphemeralExpiry returns the time after which the model is destroyed,
+// and whether the model has one at all.
+func (m *Model) EphemeralExpiry() (time.Time, bool) {
+	if m.doc.EphemeralExpiry == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(0, m.doc.EphemeralExpiry).UTC(), true
+}
+
+// SetEphemeralExpiry sets the time after which the model is destroyed,
+// along with its storage. A zero time makes the model persistent. The
+// controller model cannot be made ephemeral.
+func (m *Model) SetEphemeralExpiry(expiry time.Time) error {
+	if m.isControllerModel() && !expiry.IsZero() {
+		return errors.New("cannot make the controller model ephemeral")
+	}
+	var update bson.D
+	if expiry.IsZero() {
+		update = bson.D{{"$unset", bson.D{{"ephemeral-expiry", nil}}}}
+	} else {
+		update = bson.D{{"$set", bson.D{{"ephemeral-expiry", expiry.UnixNano()}}}}
+	}
+	ops := []txn.Op{{
+		C:      modelsC,
+		Id:     m.doc.UUID,
+		Assert: isAliveDoc,
+		Update: update,
+	}}
+	if err := m.st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.Errorf("model %q is no longer alive", m.Name())
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return m.Refresh()
+}
+
 // SetSLA sets the SLA on the model.
 func (m *Model) SetSLA(level, owner string, credentials []byte) error {
 	l, err := newSLALevel(level)
@@ -1490,6 +1552,7 @@ func createModelOp(
 	cloudCredential names.CloudCredentialTag,
 	migrationMode MigrationMode,
 	environVersion int,
+	ephemeralExpiry time.Time,
 ) txn.Op {
 	doc := &modelDoc{
 		Type:            modelType,
@@ -1504,6 +1567,9 @@ func createModelOp(
 		CloudRegion:     cloudRegion,
 		CloudCredential: cloudCredential.Id(),
 	}
+	if !ephemeralExpiry.IsZero() {
+		doc.EphemeralExpiry = ephemeralExpiry.UnixNano()
+	}
 	return txn.Op{
 		C:      modelsC,
 		Id:     uuid,
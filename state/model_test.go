@@ -6,6 +6,7 @@ package state_test
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/juju/errors"
 	gitjujutesting "github.com/juju/testing"
@@ -73,6 +74,22 @@ func (s *ModelSuite) TestNewModelNonExistentLocalUser(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `cannot create model: user "non-existent" not found`)
 }
 
+func (s *ModelSuite) TestNewModelUnknownDefaultSpace(c *gc.C) {
+	cfg, _ := s.createTestModelConfig(c)
+	cfg, err := cfg.Apply(map[string]interface{}{"default-space": "dmz"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, _, err = s.State.NewModel(state.ModelArgs{
+		Type:        state.ModelTypeIAAS,
+		CloudName:   "dummy",
+		CloudRegion: "dummy-region",
+		Config:      cfg,
+		Owner:       s.Owner,
+		StorageProviderRegistry: storage.StaticProviderRegistry{},
+	})
+	c.Assert(err, gc.ErrorMatches, `default-space "dmz": unknown space not valid`)
+}
+
 func (s *ModelSuite) TestNewModelSameUserSameNameFails(c *gc.C) {
 	cfg, _ := s.createTestModelConfig(c)
 	owner := s.Factory.MakeUser(c, nil).UserTag()
@@ -402,6 +419,47 @@ func (s *ModelSuite) TestSLA(c *gc.C) {
 	c.Assert(slaCreds, gc.DeepEquals, []byte("auth advanced"))
 }
 
+func (s *ModelSuite) TestEphemeralExpiry(c *gc.C) {
+	cfg, _ := s.createTestModelConfig(c)
+	expiry := time.Date(2018, 4, 1, 12, 0, 0, 0, time.UTC)
+	model, st, err := s.State.NewModel(state.ModelArgs{
+		Type:        state.ModelTypeIAAS,
+		CloudName:   "dummy",
+		CloudRegion: "dummy-region",
+		Config:      cfg,
+		Owner:       names.NewUserTag("test@remote"),
+		StorageProviderRegistry: storage.StaticProviderRegistry{},
+		EphemeralExpiry:         expiry,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	got, ok := model.EphemeralExpiry()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(got, gc.Equals, expiry)
+
+	later := expiry.Add(time.Hour)
+	err = model.SetEphemeralExpiry(later)
+	c.Assert(err, jc.ErrorIsNil)
+	got, ok = model.EphemeralExpiry()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(got, gc.Equals, later)
+
+	err = model.SetEphemeralExpiry(time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = model.EphemeralExpiry()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *ModelSuite) TestSetEphemeralExpiryControllerModel(c *gc.C) {
+	model, err := s.State.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	err = model.SetEphemeralExpiry(time.Now())
+	c.Assert(err, gc.ErrorMatches, "cannot make the controller model ephemeral")
+	_, ok := model.EphemeralExpiry()
+	c.Assert(ok, jc.IsFalse)
+}
+
 func (s *ModelSuite) TestMeterStatus(c *gc.C) {
 	cfg, _ := s.createTestModelConfig(c)
 	owner := names.NewUserTag("test@remote")
@@ -6,6 +6,7 @@ package state
 import (
 	"github.com/juju/errors"
 	"github.com/juju/schema"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs"
@@ -58,7 +59,10 @@ func (st *State) inheritedConfigAttributes() (map[string]interface{}, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	configSources := modelConfigSources(st, rspec)
+	configSources, err := st.inheritedModelConfigSources(rspec)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	values := make(attrValues)
 	for _, src := range configSources {
 		cfg, err := src.sourceFunc()
@@ -89,7 +93,10 @@ func (model *Model) modelConfigValues(modelCfg attrValues) (config.ConfigValues,
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	configSources := modelConfigSources(model.st, rspec)
+	configSources, err := model.st.inheritedModelConfigSources(rspec)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	sourceNames := make([]string, 0, len(configSources))
 	sourceAttrs := make([]attrValues, 0, len(configSources))
 	for _, src := range configSources {
@@ -138,6 +145,11 @@ func (model *Model) modelConfigValues(modelCfg attrValues) (config.ConfigValues,
 
 // UpdateModelConfigDefaultValues updates the inherited settings used when creating a new model.
 func (model *Model) UpdateModelConfigDefaultValues(attrs map[string]interface{}, removed []string, regionSpec *environs.RegionSpec) error {
+	// Spaces belong to a single model, so a default-space could not
+	// be checked against the spaces of every model it applies to.
+	if _, ok := attrs[config.DefaultSpaceKey]; ok {
+		return errors.NotValidf("%s in model defaults", config.DefaultSpaceKey)
+	}
 	var key string
 
 	if regionSpec != nil {
@@ -175,11 +187,27 @@ func (model *Model) UpdateModelConfigDefaultValues(attrs map[string]interface{},
 // ModelConfigValues returns the config values for the model represented
 // by this state.
 func (model *Model) ModelConfigValues() (config.ConfigValues, error) {
+	cfg, err := model.ModelConfigWithSources()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cfg.AllAttrsWithSource(), nil
+}
+
+// ModelConfigWithSources returns the complete config for the model,
+// recording the source that supplied each value; see
+// config.Config.AllAttrsWithSource. Unlike ModelConfig, it reads the
+// inherited config sources to find them.
+func (model *Model) ModelConfigWithSources() (*config.Config, error) {
 	cfg, err := model.ModelConfig()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return model.modelConfigValues(cfg.AllAttrs())
+	values, err := model.modelConfigValues(cfg.AllAttrs())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return config.NewWithSources(values)
 }
 
 // ModelConfigDefaultValues returns the default config values to be used
@@ -267,6 +295,12 @@ func (st *State) buildAndValidateModelConfig(updateAttrs attrValues, removeAttrs
 	if err := checkModelConfig(newConfig); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err := st.validateStorageDefaultPoolAttrs(newConfig); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := st.validateDefaultSpace(newConfig, oldConfig); err != nil {
+		return nil, errors.Trace(err)
+	}
 	return st.validate(newConfig, oldConfig)
 }
 
@@ -274,8 +308,14 @@ type ValidateConfigFunc func(updateAttrs map[string]interface{}, removeAttrs []s
 
 // UpdateModelConfig adds, updates or removes attributes in the current
 // configuration of the model with the provided updateAttrs and
-// removeAttrs.
+// removeAttrs. The changes are recorded in the model config history
+// without a user; use UpdateModelConfigBy for changes made on behalf
+// of a user.
 func (m *Model) UpdateModelConfig(updateAttrs map[string]interface{}, removeAttrs []string, additionalValidation ...ValidateConfigFunc) error {
+	return m.updateModelConfig("", updateAttrs, removeAttrs, additionalValidation...)
+}
+
+func (m *Model) updateModelConfig(user string, updateAttrs map[string]interface{}, removeAttrs []string, additionalValidation ...ValidateConfigFunc) error {
 	if len(updateAttrs)+len(removeAttrs) == 0 {
 		return nil
 	}
@@ -333,18 +373,60 @@ func (m *Model) UpdateModelConfig(updateAttrs map[string]interface{}, removeAttr
 		return errors.Trace(err)
 	}
 
-	validAttrs := validCfg.AllAttrs()
-	for k := range oldConfig.AllAttrs() {
-		if _, ok := validAttrs[k]; !ok {
-			modelSettings.Delete(k)
+	providerFields, err := st.providerConfigFields()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	changes, err := validCfg.DiffSchema(oldConfig, providerFields)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, change := range changes {
+		if change.Type == config.ConfigRemoved {
+			modelSettings.Delete(change.Key)
 		}
 	}
+	// Renamed attributes are stored under their new names only.
+	for _, attr := range config.DeprecatedAttributes() {
+		modelSettings.Delete(attr.OldKey)
+	}
 	// Some values require marshalling before storage.
-	validAttrs = config.CoerceForStorage(validAttrs)
+	validAttrs := config.CoerceForStorage(validCfg.AllAttrs())
 
 	modelSettings.Update(validAttrs)
 	_, ops := modelSettings.settingsUpdateOps()
-	return modelSettings.write(ops)
+	if err := modelSettings.write(ops); err != nil {
+		return err
+	}
+	recordModelConfigChanges(st, user, changes, validCfg.MaxConfigHistoryAge())
+	return nil
+}
+
+// providerConfigFields returns the config fields that the model's
+// provider defines in addition to those defined by the config package,
+// or nil if the provider has no schema.
+func (st *State) providerConfigFields() (environschema.Fields, error) {
+	source, err := st.environsProviderConfigSchemaSource()
+	if errors.IsNotImplemented(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	provider, ok := source.(environs.ProviderSchema)
+	if !ok {
+		return nil, nil
+	}
+	global, err := config.Schema(nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	fields := make(environschema.Fields)
+	for name, field := range provider.Schema() {
+		if _, ok := global[name]; !ok {
+			fields[name] = field
+		}
+	}
+	return fields, nil
 }
 
 type modelConfigSourceFunc func() (attrValues, error)
@@ -366,6 +448,25 @@ func modelConfigSources(st *State, regionSpec *environs.RegionSpec) []modelConfi
 	}
 }
 
+// inheritedModelConfigSources returns the config sources from which an
+// existing model inherits values: the sources shared with new models,
+// followed by the config profile applied to the model, if any.
+func (st *State) inheritedModelConfigSources(regionSpec *environs.RegionSpec) ([]modelConfigSource, error) {
+	sources := modelConfigSources(st, regionSpec)
+	profile, err := st.appliedConfigProfile()
+	if errors.IsNotFound(err) {
+		return sources, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return append(sources, modelConfigSource{
+		name: config.ProfileSource(profile.Name),
+		sourceFunc: modelConfigSourceFunc(func() (attrValues, error) {
+			return profile.Config, nil
+		}),
+	}), nil
+}
+
 const (
 	// controllerInheritedSettingsGlobalKey is the key for default settings shared across models.
 	controllerInheritedSettingsGlobalKey = "controller"
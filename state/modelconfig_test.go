@@ -5,6 +5,7 @@ package state_test
 
 import (
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -12,6 +13,7 @@ import (
 	"github.com/juju/utils/clock"
 	"github.com/juju/utils/set"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/environschema.v1"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/cloud"
@@ -191,6 +193,56 @@ func (s *ModelConfigSuite) TestUpdateModelConfigRejectsControllerConfig(c *gc.C)
 	c.Assert(err, gc.ErrorMatches, `cannot set controller attribute "api-port" on a model`)
 }
 
+func (s *ModelConfigSuite) TestUpdateModelConfigStorageDefaultPoolAttrs(c *gc.C) {
+	err := s.IAASModel.UpdateModelConfig(map[string]interface{}{
+		"storage-default-block-source": "loop",
+		"storage-default-pool-attrs":   "foo=bar",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err := s.IAASModel.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.StorageDefaultPoolAttrs(), jc.DeepEquals, map[string]interface{}{"foo": "bar"})
+
+	err = s.IAASModel.UpdateModelConfig(map[string]interface{}{
+		"storage-default-block-source": "no-such-pool",
+	}, nil)
+	c.Assert(err, gc.ErrorMatches, `invalid storage-default-pool-attrs for pool "no-such-pool": .*`)
+}
+
+func (s *ModelConfigSuite) TestUpdateModelConfigStorageDefaultPoolAttrsRequiresDefaultSource(c *gc.C) {
+	err := s.IAASModel.UpdateModelConfig(map[string]interface{}{
+		"storage-default-pool-attrs": "foo=bar",
+	}, nil)
+	c.Assert(err, gc.ErrorMatches, "storage-default-pool-attrs requires storage-default-block-source or storage-default-filesystem-source to be set")
+}
+
+func (s *ModelConfigSuite) TestUpdateModelConfigDefaultSpace(c *gc.C) {
+	err := s.IAASModel.UpdateModelConfig(map[string]interface{}{
+		"default-space": "dmz",
+	}, nil)
+	c.Assert(err, gc.ErrorMatches, `default-space "dmz": unknown space not valid`)
+
+	_, err = s.State.AddSpace("dmz", "", nil, true)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.IAASModel.UpdateModelConfig(map[string]interface{}{
+		"default-space": "dmz",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err := s.IAASModel.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.DefaultSpace(), gc.Equals, "dmz")
+}
+
+func (s *ModelConfigSuite) TestUpdateModelConfigDefaultValuesRejectsDefaultSpace(c *gc.C) {
+	_, err := s.State.AddSpace("dmz", "", nil, true)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.IAASModel.UpdateModelConfigDefaultValues(map[string]interface{}{
+		"default-space": "dmz",
+	}, nil, nil)
+	c.Assert(err, gc.ErrorMatches, `default-space in model defaults not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
 func (s *ModelConfigSuite) TestUpdateModelConfigRemoveInherited(c *gc.C) {
 	attrs := map[string]interface{}{
 		"apt-mirror":    "http://different-mirror", // controller
@@ -260,6 +312,103 @@ func (s *ModelConfigSuite) TestUpdateModelConfigPreferredOverRemove(c *gc.C) {
 	c.Assert(ok, jc.IsFalse)
 }
 
+func (s *ModelConfigSuite) TestModelConfigHistory(c *gc.C) {
+	start := s.Clock.Now()
+	err := s.IAASModel.UpdateModelConfig(map[string]interface{}{"arbitrary-key": "shazam!"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Clock.Advance(time.Minute)
+	bob := names.NewUserTag("bob")
+	err = s.IAASModel.UpdateModelConfigBy(bob, map[string]interface{}{
+		"arbitrary-key": "kazam!",
+		"no-proxy":      "internal.example.com",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Clock.Advance(time.Minute)
+	err = s.IAASModel.UpdateModelConfigBy(bob, nil, []string{"arbitrary-key"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.IAASModel.ModelConfigHistory("arbitrary-key")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, jc.DeepEquals, []state.ModelConfigChange{{
+		Time: start.Add(2 * time.Minute).UTC(),
+		User: "bob",
+		Key:  "arbitrary-key",
+		Old:  "kazam!",
+	}, {
+		Time: start.Add(time.Minute).UTC(),
+		User: "bob",
+		Key:  "arbitrary-key",
+		Old:  "shazam!",
+		New:  "kazam!",
+	}, {
+		Time: start.UTC(),
+		Key:  "arbitrary-key",
+		New:  "shazam!",
+	}})
+
+	history, err = s.IAASModel.ModelConfigHistory("no-proxy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].User, gc.Equals, "bob")
+	c.Assert(history[0].New, gc.Equals, "internal.example.com")
+}
+
+// secretConfigSchemaSource is a config schema source whose provider
+// schema marks an attribute as secret.
+type secretConfigSchemaSource struct {
+	statetesting.MockConfigSchemaSource
+}
+
+func (*secretConfigSchemaSource) Schema() environschema.Fields {
+	fields, err := config.Schema(environschema.Fields{
+		"providerAttr": {Type: environschema.Tstring},
+		"api-token":    {Type: environschema.Tstring, Secret: true},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return fields
+}
+
+func (s *ModelConfigSuite) TestModelConfigHistorySecretNotRecorded(c *gc.C) {
+	s.policy.GetProviderConfigSchemaSource = func() (config.ConfigSchemaSource, error) {
+		return &secretConfigSchemaSource{}, nil
+	}
+	err := s.IAASModel.UpdateModelConfig(map[string]interface{}{"api-token": "hunter2"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfg, err := s.IAASModel.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AllAttrs()["api-token"], gc.Equals, "hunter2")
+
+	history, err := s.IAASModel.ModelConfigHistory("api-token")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].Old, gc.IsNil)
+	c.Assert(history[0].New, gc.IsNil)
+}
+
+func (s *ModelConfigSuite) TestModelConfigHistoryPruned(c *gc.C) {
+	err := s.IAASModel.UpdateModelConfig(map[string]interface{}{
+		"max-config-history-age": "1h",
+		"arbitrary-key":          "shazam!",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Entries older than the maximum age are pruned as the next
+	// change is recorded.
+	s.Clock.Advance(2 * time.Hour)
+	err = s.IAASModel.UpdateModelConfig(map[string]interface{}{"arbitrary-key": "kazam!"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.IAASModel.ModelConfigHistory("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].New, gc.Equals, "kazam!")
+}
+
 type ModelConfigSourceSuite struct {
 	ConnSuite
 }
@@ -378,6 +527,10 @@ func (s *ModelConfigSourceSuite) assertModelConfigValues(c *gc.C, modelCfg *conf
 	sources, err := s.IAASModel.ModelConfigValues()
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(sources, jc.DeepEquals, expectedValues)
+
+	cfg, err := s.IAASModel.ModelConfigWithSources()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AllAttrsWithSource(), jc.DeepEquals, expectedValues)
 }
 
 func (s *ModelConfigSourceSuite) TestModelConfigValues(c *gc.C) {
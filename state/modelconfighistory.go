@@ -0,0 +1,117 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/environs/config"
+)
+
+// ModelConfigChange records a change to a model config attribute.
+type ModelConfigChange struct {
+	// Time is when the change was made.
+	Time time.Time
+
+	// User is the name of the user that made the change. It is
+	// empty if the change was made by the controller.
+	User string
+
+	// Key is the name of the changed attribute.
+	Key string
+
+	// Old is the attribute's previous value, or nil if it was not
+	// set.
+	Old interface{}
+
+	// New is the attribute's new value, or nil if it was removed.
+	New interface{}
+}
+
+// modelConfigHistoryDoc records a change to a model config attribute.
+type modelConfigHistoryDoc struct {
+	ModelUUID string      `bson:"model-uuid"`
+	Time      int64       `bson:"time"`
+	User      string      `bson:"user,omitempty"`
+	Key       string      `bson:"key"`
+	Old       interface{} `bson:"old,omitempty"`
+	New       interface{} `bson:"new,omitempty"`
+}
+
+// recordModelConfigChanges adds the supplied changes to the model's
+// config history, and prunes entries older than maxAge. The values of
+// secret attributes are not recorded. As with status history, failing
+// to record the history does not fail the change.
+func recordModelConfigChanges(st *State, user string, changes []config.ConfigChange, maxAge time.Duration) {
+	if len(changes) == 0 {
+		return
+	}
+	history, closer := st.db().GetCollection(modelConfigHistoryC)
+	defer closer()
+	historyW := history.Writeable()
+
+	now := st.clock().Now().UnixNano()
+	for _, change := range changes {
+		change = change.Masked()
+		doc := &modelConfigHistoryDoc{
+			Time: now,
+			User: user,
+			Key:  change.Key,
+			Old:  change.Old,
+			New:  change.New,
+		}
+		if err := historyW.Insert(doc); err != nil {
+			logger.Errorf("failed to write model config history: %v", err)
+			return
+		}
+	}
+	if maxAge > 0 {
+		if err := pruneCollection(st, maxAge, 0, modelConfigHistoryC, "time", NanoSeconds); err != nil {
+			logger.Errorf("failed to prune model config history: %v", err)
+		}
+	}
+}
+
+// ModelConfigHistory returns the recorded changes to the model's config,
+// most recent first. If key is not empty, only the changes to that
+// attribute are returned.
+func (m *Model) ModelConfigHistory(key string) ([]ModelConfigChange, error) {
+	history, closer := m.st.db().GetCollection(modelConfigHistoryC)
+	defer closer()
+
+	sel := bson.D{}
+	if key != "" {
+		sel = append(sel, bson.DocElem{"key", key})
+	}
+	var docs []modelConfigHistoryDoc
+	if err := history.Find(sel).Sort("-time").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get model config history")
+	}
+	changes := make([]ModelConfigChange, len(docs))
+	for i, doc := range docs {
+		changes[i] = ModelConfigChange{
+			Time: time.Unix(0, doc.Time).UTC(),
+			User: doc.User,
+			Key:  doc.Key,
+			Old:  doc.Old,
+			New:  doc.New,
+		}
+	}
+	return changes, nil
+}
+
+// UpdateModelConfigBy is like UpdateModelConfig, but records the user
+// making the change in the model config history.
+func (m *Model) UpdateModelConfigBy(
+	user names.UserTag,
+	updateAttrs map[string]interface{},
+	removeAttrs []string,
+	additionalValidation ...ValidateConfigFunc,
+) error {
+	return m.updateModelConfig(user.Id(), updateAttrs, removeAttrs, additionalValidation...)
+}
@@ -0,0 +1,310 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/permission"
+)
+
+// ModelOwnershipTransfer records a change of a model's owner.
+type ModelOwnershipTransfer struct {
+	// Time is when the transfer was made.
+	Time time.Time
+
+	// By is the user that made the transfer.
+	By names.UserTag
+
+	// FromUser and FromTeam identify the previous owner. FromTeam
+	// is empty if the model was not owned by a team.
+	FromUser names.UserTag
+	FromTeam string
+
+	// ToUser and ToTeam identify the new owner. ToTeam is empty if
+	// the model was transferred to a user.
+	ToUser names.UserTag
+	ToTeam string
+}
+
+// modelOwnershipTransferDoc is the mongo document representation of a
+// ModelOwnershipTransfer. It is written in the same transaction as the
+// transfer itself, so the record of transfers is always complete.
+type modelOwnershipTransferDoc struct {
+	DocID     string    `bson:"_id"`
+	ModelUUID string    `bson:"model-uuid"`
+	Sequence  int       `bson:"sequence"`
+	Time      time.Time `bson:"time"`
+	By        string    `bson:"by"`
+	FromUser  string    `bson:"from-user"`
+	FromTeam  string    `bson:"from-team,omitempty"`
+	ToUser    string    `bson:"to-user"`
+	ToTeam    string    `bson:"to-team,omitempty"`
+}
+
+// OwnerTeam returns the name of the team that owns the model, and
+// whether the model is owned by a team. When a model is owned by a
+// team, Owner returns the user that owned it before it was
+// transferred to the team.
+func (m *Model) OwnerTeam() (string, bool) {
+	return m.doc.OwnerTeam, m.doc.OwnerTeam != ""
+}
+
+// TransferOwnership makes the given local user the owner of the model,
+// and grants them admin access to it. The previous owner's access is
+// left unchanged. The user must not already own a model with the same
+// name.
+func (m *Model) TransferOwnership(owner, by names.UserTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot transfer model %q to user %q", m.Name(), owner.Id())
+	user, err := m.st.User(owner)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if user.IsDisabled() {
+		return errors.Errorf("user %q is disabled", owner.Id())
+	}
+	owner = user.UserTag()
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if m.doc.Life != Alive {
+			return nil, errors.New("model is no longer alive")
+		}
+		if m.doc.MigrationMode != MigrationModeNone {
+			return nil, errors.New("model is being migrated")
+		}
+		sameUser := strings.EqualFold(m.doc.Owner, owner.Id())
+		if sameUser && m.doc.OwnerTeam == "" {
+			return nil, jujutxn.ErrNoOperations
+		}
+		ops := []txn.Op{m.assertOwnerOp(bson.D{
+			{"$set", bson.D{{"owner", owner.Id()}}},
+			{"$unset", bson.D{{"owner-team", nil}}},
+		})}
+		if !sameUser {
+			exists, err := m.st.modelNameExists(owner, m.Name())
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if exists {
+				return nil, errors.AlreadyExistsf("model %q for user %q", m.Name(), owner.Id())
+			}
+			ops = append(ops, txn.Op{
+				C:      usermodelnameC,
+				Id:     m.uniqueIndexID(),
+				Assert: txn.DocExists,
+				Remove: true,
+			}, createUniqueOwnerModelNameOp(owner, m.Name()))
+		}
+		grantOps, err := grantModelAdminOps(m.st, m.UUID(), owner, by)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, grantOps...)
+		transferOp, err := m.recordOwnershipTransferOp(by, owner, "")
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, transferOp), nil
+	}
+	if err := m.st.db().Run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	return m.Refresh()
+}
+
+// TransferOwnershipToTeam makes the named team the owner of the model,
+// and grants each of its members admin access to it. Users later
+// added to the team are granted admin access as they are added. The
+// previous owner's access is left unchanged.
+func (m *Model) TransferOwnershipToTeam(teamName string, by names.UserTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot transfer model %q to team %q", m.Name(), teamName)
+	team, err := m.st.Team(teamName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+			if err := team.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if m.doc.Life != Alive {
+			return nil, errors.New("model is no longer alive")
+		}
+		if m.doc.MigrationMode != MigrationModeNone {
+			return nil, errors.New("model is being migrated")
+		}
+		if m.doc.OwnerTeam == team.doc.DocID {
+			return nil, jujutxn.ErrNoOperations
+		}
+		ops := []txn.Op{
+			m.assertOwnerOp(bson.D{
+				{"$set", bson.D{{"owner-team", team.doc.DocID}}},
+			}),
+			// The members granted access must be those of the
+			// team when the transfer is made. The team records
+			// the transfer, so that it cannot be removed, or gain
+			// members, without seeing the model.
+			{
+				C:  teamsC,
+				Id: team.doc.DocID,
+				Assert: bson.D{
+					{"members", team.doc.Members},
+					{"model-transfers", team.doc.ModelTransfers},
+				},
+				Update: bson.D{{"$inc", bson.D{{"model-transfers", 1}}}},
+			},
+		}
+		for _, member := range team.Members() {
+			grantOps, err := grantModelAdminOps(m.st, m.UUID(), member, by)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, grantOps...)
+		}
+		transferOp, err := m.recordOwnershipTransferOp(by, m.Owner(), team.doc.DocID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, transferOp), nil
+	}
+	if err := m.st.db().Run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	return m.Refresh()
+}
+
+// assertOwnerOp returns an op that applies the update to the model
+// document, asserting that the model is alive, not being migrated, and
+// still has the owner it was read with.
+func (m *Model) assertOwnerOp(update bson.D) txn.Op {
+	assert := append(isAliveDoc,
+		bson.DocElem{"migration-mode", MigrationModeNone},
+		bson.DocElem{"owner", m.doc.Owner},
+	)
+	if m.doc.OwnerTeam == "" {
+		assert = append(assert, bson.DocElem{"owner-team", bson.D{{"$exists", false}}})
+	} else {
+		assert = append(assert, bson.DocElem{"owner-team", m.doc.OwnerTeam})
+	}
+	return txn.Op{
+		C:      modelsC,
+		Id:     m.UUID(),
+		Assert: assert,
+		Update: update,
+	}
+}
+
+// recordOwnershipTransferOp returns an op that records the transfer of
+// the model from its current owner to the given user and team.
+func (m *Model) recordOwnershipTransferOp(by, toUser names.UserTag, toTeam string) (txn.Op, error) {
+	seq, err := sequence(m.st, "modelownership")
+	if err != nil {
+		return txn.Op{}, errors.Trace(err)
+	}
+	id := fmt.Sprint(seq)
+	return txn.Op{
+		C:      modelOwnershipTransfersC,
+		Id:     id,
+		Assert: txn.DocMissing,
+		Insert: &modelOwnershipTransferDoc{
+			DocID:    m.st.docID(id),
+			Sequence: seq,
+			Time:     m.st.nowToTheSecond(),
+			By:       by.Id(),
+			FromUser: m.doc.Owner,
+			FromTeam: m.doc.OwnerTeam,
+			ToUser:   toUser.Id(),
+			ToTeam:   toTeam,
+		},
+	}, nil
+}
+
+// OwnershipTransfers returns the record of the model's ownership
+// transfers, oldest first.
+func (m *Model) OwnershipTransfers() ([]ModelOwnershipTransfer, error) {
+	coll, closer := m.st.db().GetCollection(modelOwnershipTransfersC)
+	defer closer()
+	var docs []modelOwnershipTransferDoc
+	if err := coll.Find(nil).Sort("sequence").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get model ownership transfers")
+	}
+	transfers := make([]ModelOwnershipTransfer, len(docs))
+	for i, doc := range docs {
+		transfers[i] = ModelOwnershipTransfer{
+			Time:     doc.Time.UTC(),
+			By:       names.NewUserTag(doc.By),
+			FromUser: names.NewUserTag(doc.FromUser),
+			FromTeam: doc.FromTeam,
+			ToUser:   names.NewUserTag(doc.ToUser),
+			ToTeam:   doc.ToTeam,
+		}
+	}
+	return transfers, nil
+}
+
+// modelNameExists reports whether the user owns a model with the
+// given name.
+func (st *State) modelNameExists(owner names.UserTag, name string) (bool, error) {
+	coll, closer := st.db().GetCollection(usermodelnameC)
+	defer closer()
+	n, err := coll.FindId(userModelNameIndex(owner.Id(), name)).Count()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return n > 0, nil
+}
+
+// grantModelAdminOps returns the ops needed to give the user admin
+// access to the model, asserting that the user's access is unchanged.
+// It returns no ops if the user is already a model admin.
+func grantModelAdminOps(st *State, modelUUID string, user, createdBy names.UserTag) ([]txn.Op, error) {
+	access, err := st.UserAccess(user, names.NewModelTag(modelUUID))
+	if errors.IsNotFound(err) {
+		localUser, err := st.User(user)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return createModelUserOps(
+			modelUUID,
+			user,
+			createdBy,
+			localUser.DisplayName(),
+			st.nowToTheSecond(),
+			permission.AdminAccess,
+		), nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if access.Access == permission.AdminAccess {
+		return []txn.Op{{
+			C:      permissionsC,
+			Id:     permissionID(modelKey(modelUUID), userGlobalKey(userAccessID(user))),
+			Assert: bson.D{{"access", accessToString(permission.AdminAccess)}},
+		}}, nil
+	}
+	return []txn.Op{{
+		C:      permissionsC,
+		Id:     permissionID(modelKey(modelUUID), userGlobalKey(userAccessID(user))),
+		Assert: bson.D{{"access", accessToString(access.Access)}},
+		Update: bson.D{{"$set", bson.D{{"access", accessToString(permission.AdminAccess)}}}},
+	}}, nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type ModelOwnershipSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&ModelOwnershipSuite{})
+
+func (s *ModelOwnershipSuite) TestTransferOwnership(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoModelUser: true})
+	oldOwner := s.Model.Owner()
+
+	err := s.Model.TransferOwnership(bob.UserTag(), oldOwner)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.Model.Owner(), gc.Equals, bob.UserTag())
+	_, ok := s.Model.OwnerTeam()
+	c.Assert(ok, jc.IsFalse)
+
+	access, err := s.State.UserPermission(bob.UserTag(), s.Model.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.AdminAccess)
+	access, err = s.State.UserPermission(oldOwner, s.Model.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.AdminAccess)
+
+	transfers, err := s.Model.OwnershipTransfers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(transfers, gc.HasLen, 1)
+	c.Assert(transfers[0].Time.IsZero(), jc.IsFalse)
+	c.Assert(transfers[0].By, gc.Equals, oldOwner)
+	c.Assert(transfers[0].FromUser, gc.Equals, oldOwner)
+	c.Assert(transfers[0].FromTeam, gc.Equals, "")
+	c.Assert(transfers[0].ToUser, gc.Equals, bob.UserTag())
+	c.Assert(transfers[0].ToTeam, gc.Equals, "")
+
+	// The model name is now reserved for the new owner.
+	models, err := s.State.ModelUUIDsForUser(bob.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(models, jc.DeepEquals, []string{s.Model.UUID()})
+}
+
+func (s *ModelOwnershipSuite) TestTransferOwnershipUpgradesAccess(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", Access: permission.ReadAccess})
+
+	err := s.Model.TransferOwnership(bob.UserTag(), s.Model.Owner())
+	c.Assert(err, jc.ErrorIsNil)
+
+	access, err := s.State.UserPermission(bob.UserTag(), s.Model.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.AdminAccess)
+}
+
+func (s *ModelOwnershipSuite) TestTransferOwnershipToOwner(c *gc.C) {
+	err := s.Model.TransferOwnership(s.Model.Owner(), s.Model.Owner())
+	c.Assert(err, jc.ErrorIsNil)
+
+	transfers, err := s.Model.OwnershipTransfers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(transfers, gc.HasLen, 0)
+}
+
+func (s *ModelOwnershipSuite) TestTransferOwnershipNameClash(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	st := s.Factory.MakeModel(c, &factory.ModelParams{
+		Name:  s.Model.Name(),
+		Owner: bob.UserTag(),
+	})
+	defer st.Close()
+
+	err := s.Model.TransferOwnership(bob.UserTag(), s.Model.Owner())
+	c.Assert(err, gc.ErrorMatches, `cannot transfer model "testenv" to user "bob": model "testenv" for user "bob" already exists`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *ModelOwnershipSuite) TestTransferOwnershipUnknownUser(c *gc.C) {
+	err := s.Model.TransferOwnership(names.NewUserTag("bob"), s.Model.Owner())
+	c.Assert(err, gc.ErrorMatches, `cannot transfer model "testenv" to user "bob": user "bob" not found`)
+}
+
+func (s *ModelOwnershipSuite) TestTransferOwnershipToTeam(c *gc.C) {
+	team, err := s.State.AddTeam("ops", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoModelUser: true})
+	alice := s.Factory.MakeUser(c, &factory.UserParams{Name: "alice", Access: permission.WriteAccess})
+	c.Assert(team.AddMember(bob.UserTag()), jc.ErrorIsNil)
+	c.Assert(team.AddMember(alice.UserTag()), jc.ErrorIsNil)
+	owner := s.Model.Owner()
+
+	err = s.Model.TransferOwnershipToTeam("ops", owner)
+	c.Assert(err, jc.ErrorIsNil)
+	teamName, ok := s.Model.OwnerTeam()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(teamName, gc.Equals, "ops")
+	c.Assert(s.Model.Owner(), gc.Equals, owner)
+
+	for _, user := range []names.UserTag{bob.UserTag(), alice.UserTag()} {
+		access, err := s.State.UserPermission(user, s.Model.ModelTag())
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(access, gc.Equals, permission.AdminAccess)
+	}
+
+	// Transferring back to a user ends the team's ownership.
+	err = s.Model.TransferOwnership(bob.UserTag(), owner)
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = s.Model.OwnerTeam()
+	c.Assert(ok, jc.IsFalse)
+
+	transfers, err := s.Model.OwnershipTransfers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(transfers, gc.HasLen, 2)
+	c.Assert(transfers[0].FromUser, gc.Equals, owner)
+	c.Assert(transfers[0].ToUser, gc.Equals, owner)
+	c.Assert(transfers[0].ToTeam, gc.Equals, "ops")
+	c.Assert(transfers[1].FromTeam, gc.Equals, "ops")
+	c.Assert(transfers[1].ToUser, gc.Equals, bob.UserTag())
+	c.Assert(transfers[1].ToTeam, gc.Equals, "")
+}
+
+func (s *ModelOwnershipSuite) TestTransferOwnershipToUnknownTeam(c *gc.C) {
+	err := s.Model.TransferOwnershipToTeam("ops", s.Model.Owner())
+	c.Assert(err, gc.ErrorMatches, `cannot transfer model "testenv" to team "ops": team "ops" not found`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ModelOwnershipSuite) TestTransferOwnershipDeadModel(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	model, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model.Destroy(state.DestroyModelParams{}), jc.ErrorIsNil)
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+
+	err = model.TransferOwnership(bob.UserTag(), s.Owner)
+	c.Assert(err, gc.ErrorMatches, `cannot transfer model ".*" to user "bob": model is no longer alive`)
+}
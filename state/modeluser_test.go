@@ -14,6 +14,7 @@ import (
 
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/storage"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
@@ -183,6 +184,51 @@ func (s *ModelUserSuite) TestSetAccessModelUser(c *gc.C) {
 	c.Assert(modelUser.Access, gc.Equals, permission.ReadAccess)
 }
 
+func (s *ModelUserSuite) TestWatchUserAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c,
+		&factory.UserParams{
+			Name:        "validusername",
+			NoModelUser: true,
+		})
+	w := s.Model.WatchUserAccess()
+	defer statetesting.AssertStop(c, w)
+
+	// Initial event.
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	_, err := s.Model.AddUser(
+		state.UserAccessSpec{
+			User:      user.UserTag(),
+			CreatedBy: s.Model.Owner(),
+			Access:    permission.AdminAccess,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	_, err = s.State.SetUserAccess(user.UserTag(), s.Model.ModelTag(), permission.ReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.State.RemoveUserAccess(user.UserTag(), s.Model.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Access to other models is not reported.
+	otherState := s.Factory.MakeModel(c, nil)
+	defer otherState.Close()
+	otherModel, err := otherState.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = otherModel.AddUser(
+		state.UserAccessSpec{
+			User:      user.UserTag(),
+			CreatedBy: s.Model.Owner(),
+			Access:    permission.AdminAccess,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+}
+
 func (s *ModelUserSuite) TestCaseUserNameVsId(c *gc.C) {
 	model, err := s.State.Model()
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,294 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/crossmodel"
+)
+
+// OfferCatalogEntry describes an application offer hosted by a
+// controller that has registered its offers with this controller's
+// offer catalog. Users can find the offer in the catalog without
+// having access to the offering controller; whether they can consume
+// it is decided by the offering controller when they do so.
+type OfferCatalogEntry struct {
+	// ControllerUUID is the UUID of the controller hosting the offer.
+	ControllerUUID string
+
+	// OfferURL is the URL of the offer, whose source is the name of
+	// the controller hosting it.
+	OfferURL string
+
+	// OfferName is the name of the offer.
+	OfferName string
+
+	// ApplicationDescription is the description of the offered
+	// application.
+	ApplicationDescription string
+
+	// Endpoints holds the offered endpoints.
+	Endpoints []charm.Relation
+}
+
+// OfferCatalogRegistration holds the offers registered with the offer
+// catalog by a single controller.
+type OfferCatalogRegistration struct {
+	// ControllerUUID is the UUID of the registering controller.
+	ControllerUUID string
+
+	// ControllerName is the name by which the registering controller
+	// is known; it is the source of the offers' URLs.
+	ControllerName string
+
+	// Offers holds the controller's offers.
+	Offers []OfferCatalogEntry
+
+	// Updated is when the offers were last registered.
+	Updated time.Time
+}
+
+// OfferCatalogFilter selects entries from the offer catalog. Empty
+// fields match all entries.
+type OfferCatalogFilter struct {
+	// Interface matches offers with an endpoint using the interface.
+	Interface string
+
+	// OfferName matches offers with the name.
+	OfferName string
+}
+
+// offerCatalogDoc records the offers registered by one controller. It
+// is keyed on the controller's UUID, and replaced whenever the
+// controller registers its offers again.
+type offerCatalogDoc struct {
+	DocID          string                 `bson:"_id"`
+	ControllerName string                 `bson:"controller-name"`
+	Offers         []offerCatalogEntryDoc `bson:"offers"`
+	Updated        int64                  `bson:"updated"`
+}
+
+type offerCatalogEntryDoc struct {
+	OfferURL               string                    `bson:"offer-url"`
+	OfferName              string                    `bson:"offer-name"`
+	ApplicationDescription string                    `bson:"application-description,omitempty"`
+	Endpoints              []offerCatalogEndpointDoc `bson:"endpoints"`
+}
+
+type offerCatalogEndpointDoc struct {
+	Name      string `bson:"name"`
+	Interface string `bson:"interface"`
+	Role      string `bson:"role"`
+	Limit     int    `bson:"limit,omitempty"`
+}
+
+func (doc *offerCatalogDoc) registration() OfferCatalogRegistration {
+	reg := OfferCatalogRegistration{
+		ControllerUUID: doc.DocID,
+		ControllerName: doc.ControllerName,
+		Updated:        time.Unix(0, doc.Updated).UTC(),
+	}
+	for _, offer := range doc.Offers {
+		reg.Offers = append(reg.Offers, offer.entry(doc.DocID))
+	}
+	return reg
+}
+
+func (doc *offerCatalogEntryDoc) entry(controllerUUID string) OfferCatalogEntry {
+	entry := OfferCatalogEntry{
+		ControllerUUID:         controllerUUID,
+		OfferURL:               doc.OfferURL,
+		OfferName:              doc.OfferName,
+		ApplicationDescription: doc.ApplicationDescription,
+	}
+	for _, ep := range doc.Endpoints {
+		entry.Endpoints = append(entry.Endpoints, charm.Relation{
+			Name:      ep.Name,
+			Interface: ep.Interface,
+			Role:      charm.RelationRole(ep.Role),
+			Limit:     ep.Limit,
+		})
+	}
+	return entry
+}
+
+func (doc *offerCatalogEntryDoc) matches(filter OfferCatalogFilter) bool {
+	if filter.OfferName != "" && doc.OfferName != filter.OfferName {
+		return false
+	}
+	if filter.Interface == "" {
+		return true
+	}
+	for _, ep := range doc.Endpoints {
+		if ep.Interface == filter.Interface {
+			return true
+		}
+	}
+	return false
+}
+
+// validateOfferCatalogRegistration returns an error if the
+// registration cannot be stored in the catalog.
+func validateOfferCatalogRegistration(reg OfferCatalogRegistration) error {
+	if !utils.IsValidUUIDString(reg.ControllerUUID) {
+		return errors.NotValidf("controller UUID %q", reg.ControllerUUID)
+	}
+	if reg.ControllerName == "" {
+		return errors.NotValidf("empty controller name")
+	}
+	for _, offer := range reg.Offers {
+		url, err := crossmodel.ParseOfferURL(offer.OfferURL)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if url.Source != reg.ControllerName {
+			return errors.NotValidf("offer URL %q not hosted by controller %q", offer.OfferURL, reg.ControllerName)
+		}
+		if offer.OfferName == "" {
+			return errors.NotValidf("offer %q without a name", offer.OfferURL)
+		}
+	}
+	return nil
+}
+
+// RegisterOfferCatalog records the offers of another controller in
+// this controller's offer catalog, replacing any offers that the
+// controller registered before.
+func (st *State) RegisterOfferCatalog(reg OfferCatalogRegistration) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot register offers of controller %q", reg.ControllerName)
+	if err := validateOfferCatalogRegistration(reg); err != nil {
+		return errors.Trace(err)
+	}
+	if reg.ControllerUUID == st.ControllerUUID() {
+		return errors.NotValidf("registering the controller's own offers")
+	}
+	doc := offerCatalogDoc{
+		DocID:          reg.ControllerUUID,
+		ControllerName: reg.ControllerName,
+		Updated:        st.clock().Now().UnixNano(),
+	}
+	for _, offer := range reg.Offers {
+		offerDoc := offerCatalogEntryDoc{
+			OfferURL:               offer.OfferURL,
+			OfferName:              offer.OfferName,
+			ApplicationDescription: offer.ApplicationDescription,
+		}
+		for _, ep := range offer.Endpoints {
+			offerDoc.Endpoints = append(offerDoc.Endpoints, offerCatalogEndpointDoc{
+				Name:      ep.Name,
+				Interface: ep.Interface,
+				Role:      string(ep.Role),
+				Limit:     ep.Limit,
+			})
+		}
+		doc.Offers = append(doc.Offers, offerDoc)
+	}
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if _, err := st.OfferCatalogRegistration(reg.ControllerUUID); errors.IsNotFound(err) {
+			return []txn.Op{{
+				C:      offerCatalogC,
+				Id:     doc.DocID,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			}}, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:      offerCatalogC,
+			Id:     doc.DocID,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{
+				{"controller-name", doc.ControllerName},
+				{"offers", doc.Offers},
+				{"updated", doc.Updated},
+			}}},
+		}}, nil
+	}
+	return st.db().Run(buildTxn)
+}
+
+// UnregisterOfferCatalog removes the offers registered by the
+// controller with the given UUID from the offer catalog.
+func (st *State) UnregisterOfferCatalog(controllerUUID string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot unregister offers of controller %q", controllerUUID)
+	ops := []txn.Op{{
+		C:      offerCatalogC,
+		Id:     controllerUUID,
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("offer catalog registration for controller %q", controllerUUID)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// OfferCatalogRegistration returns the offers registered with the
+// offer catalog by the controller with the given UUID.
+func (st *State) OfferCatalogRegistration(controllerUUID string) (OfferCatalogRegistration, error) {
+	coll, closer := st.db().GetCollection(offerCatalogC)
+	defer closer()
+	var doc offerCatalogDoc
+	if err := coll.FindId(controllerUUID).One(&doc); err == mgo.ErrNotFound {
+		return OfferCatalogRegistration{}, errors.NotFoundf("offer catalog registration for controller %q", controllerUUID)
+	} else if err != nil {
+		return OfferCatalogRegistration{}, errors.Annotatef(err, "cannot get offer catalog registration for controller %q", controllerUUID)
+	}
+	return doc.registration(), nil
+}
+
+// OfferCatalogRegistrations returns the offers registered with the
+// offer catalog by each controller, sorted by controller name.
+func (st *State) OfferCatalogRegistrations() ([]OfferCatalogRegistration, error) {
+	docs, err := st.offerCatalogDocs()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	regs := make([]OfferCatalogRegistration, len(docs))
+	for i, doc := range docs {
+		regs[i] = doc.registration()
+	}
+	return regs, nil
+}
+
+// FindOfferCatalogEntries returns the offers in the offer catalog that
+// match the filter, ordered by controller name and then by the order
+// in which each controller registered them.
+func (st *State) FindOfferCatalogEntries(filter OfferCatalogFilter) ([]OfferCatalogEntry, error) {
+	docs, err := st.offerCatalogDocs()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var entries []OfferCatalogEntry
+	for _, doc := range docs {
+		for _, offer := range doc.Offers {
+			if offer.matches(filter) {
+				entries = append(entries, offer.entry(doc.DocID))
+			}
+		}
+	}
+	return entries, nil
+}
+
+func (st *State) offerCatalogDocs() ([]offerCatalogDoc, error) {
+	coll, closer := st.db().GetCollection(offerCatalogC)
+	defer closer()
+	var docs []offerCatalogDoc
+	if err := coll.Find(nil).Sort("controller-name").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get offer catalog")
+	}
+	return docs, nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6"
+
+	"github.com/juju/juju/state"
+)
+
+type OfferCatalogSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&OfferCatalogSuite{})
+
+const (
+	catalogControllerUUID      = "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+	otherCatalogControllerUUID = "7b9e2c1a-3d4f-4a5b-8c6d-1e2f3a4b5c6d"
+)
+
+func catalogRegistration() state.OfferCatalogRegistration {
+	return state.OfferCatalogRegistration{
+		ControllerUUID: catalogControllerUUID,
+		ControllerName: "data",
+		Offers: []state.OfferCatalogEntry{{
+			OfferURL:               "data:fred/prod.postgresql",
+			OfferName:              "postgresql",
+			ApplicationDescription: "object-relational database",
+			Endpoints: []charm.Relation{
+				{Name: "db", Interface: "pgsql", Role: charm.RoleProvider},
+			},
+		}, {
+			OfferURL:  "data:fred/prod.mysql",
+			OfferName: "mysql",
+			Endpoints: []charm.Relation{
+				{Name: "db", Interface: "mysql", Role: charm.RoleProvider},
+			},
+		}},
+	}
+}
+
+func (s *OfferCatalogSuite) TestRegisterOfferCatalog(c *gc.C) {
+	reg := catalogRegistration()
+	err := s.State.RegisterOfferCatalog(reg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	got, err := s.State.OfferCatalogRegistration(catalogControllerUUID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got.Updated.IsZero(), jc.IsFalse)
+	got.Updated = reg.Updated
+	for i := range reg.Offers {
+		reg.Offers[i].ControllerUUID = catalogControllerUUID
+	}
+	c.Assert(got, jc.DeepEquals, reg)
+}
+
+func (s *OfferCatalogSuite) TestRegisterOfferCatalogReplaces(c *gc.C) {
+	reg := catalogRegistration()
+	err := s.State.RegisterOfferCatalog(reg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	reg.Offers = reg.Offers[1:]
+	err = s.State.RegisterOfferCatalog(reg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	got, err := s.State.OfferCatalogRegistration(catalogControllerUUID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got.Offers, gc.HasLen, 1)
+	c.Assert(got.Offers[0].OfferName, gc.Equals, "mysql")
+}
+
+func (s *OfferCatalogSuite) TestRegisterOfferCatalogInvalid(c *gc.C) {
+	reg := catalogRegistration()
+	reg.ControllerUUID = "bad"
+	err := s.State.RegisterOfferCatalog(reg)
+	c.Assert(err, gc.ErrorMatches, `cannot register offers of controller "data": controller UUID "bad" not valid`)
+
+	reg = catalogRegistration()
+	reg.Offers[0].OfferURL = "other:fred/prod.postgresql"
+	err = s.State.RegisterOfferCatalog(reg)
+	c.Assert(err, gc.ErrorMatches, `.*offer URL "other:fred/prod.postgresql" not hosted by controller "data" not valid`)
+
+	reg = catalogRegistration()
+	reg.ControllerUUID = s.State.ControllerUUID()
+	err = s.State.RegisterOfferCatalog(reg)
+	c.Assert(err, gc.ErrorMatches, `.*registering the controller's own offers not valid`)
+}
+
+func (s *OfferCatalogSuite) TestFindOfferCatalogEntries(c *gc.C) {
+	err := s.State.RegisterOfferCatalog(catalogRegistration())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RegisterOfferCatalog(state.OfferCatalogRegistration{
+		ControllerUUID: otherCatalogControllerUUID,
+		ControllerName: "apps",
+		Offers: []state.OfferCatalogEntry{{
+			OfferURL:  "apps:mary/test.pg",
+			OfferName: "pg",
+			Endpoints: []charm.Relation{
+				{Name: "db", Interface: "pgsql", Role: charm.RoleProvider},
+			},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	entries, err := s.State.FindOfferCatalogEntries(state.OfferCatalogFilter{Interface: "pgsql"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 2)
+	c.Assert(entries[0].OfferURL, gc.Equals, "apps:mary/test.pg")
+	c.Assert(entries[0].ControllerUUID, gc.Equals, otherCatalogControllerUUID)
+	c.Assert(entries[1].OfferURL, gc.Equals, "data:fred/prod.postgresql")
+
+	entries, err = s.State.FindOfferCatalogEntries(state.OfferCatalogFilter{OfferName: "mysql"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 1)
+	c.Assert(entries[0].OfferURL, gc.Equals, "data:fred/prod.mysql")
+
+	entries, err = s.State.FindOfferCatalogEntries(state.OfferCatalogFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 3)
+}
+
+func (s *OfferCatalogSuite) TestUnregisterOfferCatalog(c *gc.C) {
+	err := s.State.RegisterOfferCatalog(catalogRegistration())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.UnregisterOfferCatalog(catalogControllerUUID)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.OfferCatalogRegistration(catalogControllerUUID)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	regs, err := s.State.OfferCatalogRegistrations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(regs, gc.HasLen, 0)
+
+	err = s.State.UnregisterOfferCatalog(catalogControllerUUID)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -52,6 +52,12 @@ type OpenParams struct {
 	// InitDatabaseFunc, if non-nil, is a function that will be called
 	// just after the state database is opened.
 	InitDatabaseFunc InitDatabaseFunc
+
+	// DatabaseBackend is the database backend chosen when the
+	// controller is bootstrapped. Once the controller config has been
+	// written, the backend recorded there is used; if this is not
+	// empty it must match.
+	DatabaseBackend string
 }
 
 // Validate validates the OpenParams.
@@ -68,6 +74,9 @@ func (p OpenParams) Validate() error {
 	if p.MongoInfo == nil {
 		return errors.NotValidf("nil MongoInfo")
 	}
+	if _, err := documentStoreFunc(p.DatabaseBackend); err != nil {
+		return errors.Trace(err)
+	}
 	return nil
 }
 
@@ -103,6 +112,11 @@ func OpenController(args OpenParams) (*Controller, error) {
 		logger.Debugf("mongodb initialised")
 	}
 
+	databaseBackend, err := resolveDatabaseBackend(session, args.DatabaseBackend)
+	if err != nil {
+		session.Close()
+		return nil, errors.Trace(err)
+	}
 	return &Controller{
 		clock:                  args.Clock,
 		controllerTag:          args.ControllerTag,
@@ -111,6 +125,7 @@ func OpenController(args OpenParams) (*Controller, error) {
 		session:                session,
 		newPolicy:              args.NewPolicy,
 		runTransactionObserver: args.RunTransactionObserver,
+		databaseBackend:        databaseBackend,
 	}, nil
 }
 
@@ -132,6 +147,7 @@ func Open(args OpenParams) (*State, error) {
 		args.NewPolicy,
 		args.Clock,
 		args.RunTransactionObserver,
+		args.DatabaseBackend,
 	)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -159,6 +175,7 @@ func open(
 	newPolicy NewPolicyFunc,
 	clock clock.Clock,
 	runTransactionObserver RunTransactionObserverFunc,
+	databaseBackend string,
 ) (*State, error) {
 	logger.Infof("opening state, mongo addresses: %q; entity %v", info.Addrs, info.Tag)
 	logger.Debugf("dialing mongo")
@@ -175,7 +192,12 @@ func open(
 	}
 	logger.Debugf("mongodb login successful")
 
-	st, err := newState(controllerModelTag, controllerModelTag, session, info, newPolicy, clock, runTransactionObserver)
+	databaseBackend, err = resolveDatabaseBackend(session, databaseBackend)
+	if err != nil {
+		session.Close()
+		return nil, errors.Trace(err)
+	}
+	st, err := newState(controllerModelTag, controllerModelTag, session, info, newPolicy, clock, runTransactionObserver, databaseBackend)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -249,6 +271,7 @@ func newState(
 	newPolicy NewPolicyFunc,
 	clock clock.Clock,
 	runTransactionObserver RunTransactionObserverFunc,
+	databaseBackend string,
 ) (_ *State, err error) {
 
 	defer func() {
@@ -257,11 +280,16 @@ func newState(
 		}
 	}()
 
+	newDocumentStore, err := documentStoreFunc(databaseBackend)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	db := &database{
 		raw:                    session.DB(jujuDB),
 		schema:                 allCollections(),
 		modelUUID:              modelTag.Id(),
 		runTransactionObserver: runTransactionObserver,
+		newDocumentStore:       newDocumentStore,
 	}
 
 	// Create State.
@@ -274,7 +302,10 @@ func newState(
 		database:               db,
 		newPolicy:              newPolicy,
 		runTransactionObserver: runTransactionObserver,
+		databaseBackend:        databaseBackend,
 	}
+	st.slowTxns = &slowTxnFault{clock: st.clock}
+	db.slowTxns = st.slowTxns
 	if newPolicy != nil {
 		st.policy = newPolicy(st)
 	}
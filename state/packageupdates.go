@@ -0,0 +1,160 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// MachinePackageUpdates describes the package updates pending on a
+// machine, as last reported by its agent.
+type MachinePackageUpdates struct {
+	// Pending is the number of package updates available.
+	Pending int
+
+	// Security is the number of the pending updates that are security
+	// updates.
+	Security int
+
+	// RebootRequired reports whether updates already installed need
+	// the machine to be rebooted to take effect.
+	RebootRequired bool
+
+	// Updated is when the agent last reported.
+	Updated time.Time
+}
+
+// machinePackageUpdatesDoc records the package updates pending on a
+// machine. It is keyed by the id of the machine.
+type machinePackageUpdatesDoc struct {
+	DocID          string `bson:"_id"`
+	ModelUUID      string `bson:"model-uuid"`
+	MachineId      string `bson:"machine-id"`
+	Pending        int    `bson:"pending"`
+	Security       int    `bson:"security"`
+	RebootRequired bool   `bson:"reboot-required"`
+	Updated        int64  `bson:"updated"`
+}
+
+func (doc *machinePackageUpdatesDoc) updates() MachinePackageUpdates {
+	return MachinePackageUpdates{
+		Pending:        doc.Pending,
+		Security:       doc.Security,
+		RebootRequired: doc.RebootRequired,
+		Updated:        time.Unix(0, doc.Updated).UTC(),
+	}
+}
+
+// PackageUpdates returns the package updates last reported as pending
+// on the machine.
+func (m *Machine) PackageUpdates() (MachinePackageUpdates, error) {
+	coll, closer := m.st.db().GetCollection(machinePackageUpdatesC)
+	defer closer()
+
+	var doc machinePackageUpdatesDoc
+	err := coll.FindId(m.Id()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return MachinePackageUpdates{}, errors.NotFoundf("package updates for machine %s", m.Id())
+	} else if err != nil {
+		return MachinePackageUpdates{}, errors.Annotatef(err, "cannot read package updates for machine %s", m.Id())
+	}
+	return doc.updates(), nil
+}
+
+// SetPackageUpdates records the package updates pending on the
+// machine, replacing any reported before.
+func (m *Machine) SetPackageUpdates(updates MachinePackageUpdates) error {
+	if updates.Pending < 0 || updates.Security < 0 || updates.Security > updates.Pending {
+		return errors.NotValidf("%d pending updates with %d security updates", updates.Pending, updates.Security)
+	}
+	id := m.st.docID(m.Id())
+	doc := machinePackageUpdatesDoc{
+		DocID:          id,
+		ModelUUID:      m.st.ModelUUID(),
+		MachineId:      m.Id(),
+		Pending:        updates.Pending,
+		Security:       updates.Security,
+		RebootRequired: updates.RebootRequired,
+		Updated:        updates.Updated.UnixNano(),
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if m.Life() == Dead {
+			return nil, errors.Errorf("machine %s is dead", m.Id())
+		}
+		ops := []txn.Op{{
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: notDeadDoc,
+		}}
+		coll, closer := m.st.db().GetCollection(machinePackageUpdatesC)
+		defer closer()
+		n, err := coll.FindId(m.Id()).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if n == 0 {
+			return append(ops, txn.Op{
+				C:      machinePackageUpdatesC,
+				Id:     id,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			}), nil
+		}
+		return append(ops, txn.Op{
+			C:      machinePackageUpdatesC,
+			Id:     id,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{
+				{"pending", doc.Pending},
+				{"security", doc.Security},
+				{"reboot-required", doc.RebootRequired},
+				{"updated", doc.Updated},
+			}}},
+		}), nil
+	}
+	err := m.st.db().Run(buildTxn)
+	if err == jujutxn.ErrExcessiveContention {
+		err = errors.New("state changing too quickly; try again soon")
+	}
+	return errors.Annotatef(err, "cannot set package updates for machine %s", m.Id())
+}
+
+// AllMachinePackageUpdates returns the package updates last reported
+// by the model's machines, keyed by machine id. Machines that have not
+// reported are omitted.
+func (st *State) AllMachinePackageUpdates() (map[string]MachinePackageUpdates, error) {
+	coll, closer := st.db().GetCollection(machinePackageUpdatesC)
+	defer closer()
+
+	var docs []machinePackageUpdatesDoc
+	if err := coll.Find(nil).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot read package updates")
+	}
+	result := make(map[string]MachinePackageUpdates, len(docs))
+	for _, doc := range docs {
+		result[doc.MachineId] = doc.updates()
+	}
+	return result, nil
+}
+
+// removeMachinePackageUpdatesOp returns the operation needed to remove
+// the package updates reported by the machine with the given id.
+func removeMachinePackageUpdatesOp(mb modelBackend, machineId string) txn.Op {
+	return txn.Op{
+		C:      machinePackageUpdatesC,
+		Id:     mb.docID(machineId),
+		Remove: true,
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type PackageUpdatesSuite struct {
+	ConnSuite
+	machine *state.Machine
+}
+
+var _ = gc.Suite(&PackageUpdatesSuite{})
+
+func (s *PackageUpdatesSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.machine = s.Factory.MakeMachine(c, nil)
+}
+
+func (s *PackageUpdatesSuite) TestPackageUpdatesNotReported(c *gc.C) {
+	_, err := s.machine.PackageUpdates()
+	c.Check(errors.IsNotFound(err), jc.IsTrue)
+	c.Check(err, gc.ErrorMatches, "package updates for machine 0 not found")
+}
+
+func (s *PackageUpdatesSuite) TestSetPackageUpdates(c *gc.C) {
+	now := time.Date(2018, 4, 3, 12, 0, 0, 0, time.UTC)
+	for i, updates := range []state.MachinePackageUpdates{{
+		Pending:        12,
+		Security:       3,
+		RebootRequired: true,
+		Updated:        now,
+	}, {
+		Updated: now.Add(time.Hour),
+	}} {
+		c.Logf("test %d", i)
+		err := s.machine.SetPackageUpdates(updates)
+		c.Assert(err, jc.ErrorIsNil)
+
+		got, err := s.machine.PackageUpdates()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(got, jc.DeepEquals, updates)
+	}
+}
+
+func (s *PackageUpdatesSuite) TestSetPackageUpdatesInvalid(c *gc.C) {
+	err := s.machine.SetPackageUpdates(state.MachinePackageUpdates{Pending: 1, Security: 2})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, "1 pending updates with 2 security updates not valid")
+}
+
+func (s *PackageUpdatesSuite) TestSetPackageUpdatesDeadMachine(c *gc.C) {
+	err := s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.SetPackageUpdates(state.MachinePackageUpdates{Pending: 1})
+	c.Assert(err, gc.ErrorMatches, "cannot set package updates for machine 0: machine 0 is dead")
+}
+
+func (s *PackageUpdatesSuite) TestAllMachinePackageUpdates(c *gc.C) {
+	now := time.Date(2018, 4, 3, 12, 0, 0, 0, time.UTC)
+	m1 := s.Factory.MakeMachine(c, nil)
+	s.Factory.MakeMachine(c, nil)
+	err := s.machine.SetPackageUpdates(state.MachinePackageUpdates{Pending: 2, Updated: now})
+	c.Assert(err, jc.ErrorIsNil)
+	err = m1.SetPackageUpdates(state.MachinePackageUpdates{Pending: 5, Security: 1, RebootRequired: true, Updated: now})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Reports from other models are not included.
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	other := factory.NewFactory(st).MakeMachine(c, nil)
+	err = other.SetPackageUpdates(state.MachinePackageUpdates{Pending: 9, Updated: now})
+	c.Assert(err, jc.ErrorIsNil)
+
+	all, err := s.State.AllMachinePackageUpdates()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, jc.DeepEquals, map[string]state.MachinePackageUpdates{
+		s.machine.Id(): {Pending: 2, Updated: now},
+		m1.Id():        {Pending: 5, Security: 1, RebootRequired: true, Updated: now},
+	})
+}
+
+func (s *PackageUpdatesSuite) TestRemoveMachineRemovesPackageUpdates(c *gc.C) {
+	err := s.machine.SetPackageUpdates(state.MachinePackageUpdates{Pending: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	all, err := s.State.AllMachinePackageUpdates()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 0)
+}
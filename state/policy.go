@@ -68,12 +68,35 @@ func (st *State) precheckInstance(
 	if prechecker == nil {
 		return errors.New("policy returned nil prechecker without an error")
 	}
-	return prechecker.PrecheckInstance(environs.PrecheckInstanceParams{
-		Series:            series,
-		Constraints:       cons,
-		Placement:         placement,
-		VolumeAttachments: volumeAttachments,
-	})
+	if !instance.IsPlacementExpr(placement) {
+		return prechecker.PrecheckInstance(environs.PrecheckInstanceParams{
+			Series:            series,
+			Constraints:       cons,
+			Placement:         placement,
+			VolumeAttachments: volumeAttachments,
+		})
+	}
+	// Providers interpret single placement terms; the terms of a
+	// compound expression are checked individually, and functions
+	// are left for the provisioner to resolve.
+	expr, err := instance.ParsePlacementExpr(placement)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, term := range expr {
+		if term.IsFunction() {
+			continue
+		}
+		if err := prechecker.PrecheckInstance(environs.PrecheckInstanceParams{
+			Series:            series,
+			Constraints:       cons,
+			Placement:         term.String(),
+			VolumeAttachments: volumeAttachments,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (st *State) constraintsValidator() (constraints.Validator, error) {
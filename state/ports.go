@@ -37,6 +37,10 @@ type PortRange struct {
 	FromPort int
 	ToPort   int
 	Protocol string
+
+	// Endpoint is the name of the charm endpoint the range was
+	// opened for, if any.
+	Endpoint string `bson:"endpoint,omitempty"`
 }
 
 // NewPortRange create a new port range and validate it.
@@ -137,10 +141,14 @@ func (prA PortRange) CheckConflicts(prB PortRange) error {
 // Strings returns the port range as a string.
 func (p PortRange) String() string {
 	proto := strings.ToLower(p.Protocol)
+	owner := fmt.Sprintf("%q", p.UnitName)
+	if p.Endpoint != "" {
+		owner += fmt.Sprintf(", endpoint %q", p.Endpoint)
+	}
 	if proto == "icmp" {
-		return fmt.Sprintf("%s (%q)", proto, p.UnitName)
+		return fmt.Sprintf("%s (%s)", proto, owner)
 	}
-	return fmt.Sprintf("%d-%d/%s (%q)", p.FromPort, p.ToPort, proto, p.UnitName)
+	return fmt.Sprintf("%d-%d/%s (%s)", p.FromPort, p.ToPort, proto, owner)
 }
 
 // portsDoc represents the state of ports opened on machines for networks
@@ -349,6 +357,14 @@ func (p *Ports) Refresh() error {
 	return nil
 }
 
+// AllPortRangeInfo returns all the port ranges maintained by this
+// document, including the units and endpoints they were opened for.
+func (p *Ports) AllPortRangeInfo() []PortRange {
+	result := make([]PortRange, len(p.doc.Ports))
+	copy(result, p.doc.Ports)
+	return result
+}
+
 // AllPortRanges returns a map with network.PortRange as keys and unit
 // names as values.
 func (p *Ports) AllPortRanges() map[network.PortRange]string {
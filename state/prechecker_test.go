@@ -76,6 +76,32 @@ func (s *PrecheckerSuite) TestPrecheckInstanceWithPlacement(c *gc.C) {
 	c.Assert(s.prechecker.precheckInstanceArgs.Constraints, gc.DeepEquals, template.Constraints)
 }
 
+func (s *PrecheckerSuite) TestPrecheckInstanceWithPlacementExpr(c *gc.C) {
+	// Each provider term of a placement expression is prechecked on
+	// its own; placement functions are left to the provisioner.
+	var placements []string
+	s.policy.GetPrechecker = func() (environs.InstancePrechecker, error) {
+		return precheckerFunc(func(args environs.PrecheckInstanceParams) error {
+			placements = append(placements, args.Placement)
+			return nil
+		}), nil
+	}
+	_, err := s.addOneMachine(c, constraints.Value{}, "zone=a,machine-with(app=haproxy),subnet=10.4.0.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(placements, jc.DeepEquals, []string{"zone=a", "subnet=10.4.0.0/24"})
+}
+
+func (s *PrecheckerSuite) TestPrecheckInstanceWithInvalidPlacementExpr(c *gc.C) {
+	_, err := s.addOneMachine(c, constraints.Value{}, "zone=a,far-from(app=haproxy)")
+	c.Assert(err, gc.ErrorMatches, `.*placement function "far-from" not supported`)
+}
+
+type precheckerFunc func(environs.PrecheckInstanceParams) error
+
+func (f precheckerFunc) PrecheckInstance(args environs.PrecheckInstanceParams) error {
+	return f(args)
+}
+
 func (s *PrecheckerSuite) TestPrecheckErrors(c *gc.C) {
 	// Ensure that AddOneMachine fails when PrecheckInstance returns an error.
 	s.prechecker.precheckInstanceError = fmt.Errorf("no instance for you")
@@ -41,6 +41,24 @@ func pruneCollection(mb modelBackend, maxHistoryTime time.Duration, maxHistoryMB
 	return errors.Trace(p.pruneBySize())
 }
 
+// pruneCollectionByEntity removes the oldest entries recorded for each
+// entity in the model, as identified by <entityField>, until none has
+// more than <maxEntries> entries.
+func pruneCollectionByEntity(mb modelBackend, maxEntries int, collectionName string, entityField string, ageField string) error {
+	if maxEntries <= 0 {
+		return errors.NotValidf("non-positive max entries per entity")
+	}
+	entries, closer := mb.db().GetRawCollection(collectionName)
+	defer closer()
+
+	p := collectionPruner{
+		st:       mb,
+		coll:     entries,
+		ageField: ageField,
+	}
+	return errors.Trace(p.pruneByEntity(maxEntries, entityField))
+}
+
 const historyPruneBatchSize = 1000
 const historyPruneProgressSeconds = 15
 
@@ -176,6 +194,48 @@ func (p *collectionPruner) pruneBySize() error {
 	return nil
 }
 
+func (p *collectionPruner) pruneByEntity(maxEntries int, entityField string) error {
+	var counts []struct {
+		Entity string `bson:"_id"`
+		Count  int    `bson:"count"`
+	}
+	pipe := p.coll.Pipe([]bson.M{
+		{"$match": bson.M{"model-uuid": p.st.modelUUID()}},
+		{"$group": bson.M{"_id": "$" + entityField, "count": bson.M{"$sum": 1}}},
+		{"$match": bson.M{"count": bson.M{"$gt": maxEntries}}},
+	})
+	if err := pipe.All(&counts); err != nil {
+		return errors.Annotatef(err, "counting %s records by entity", p.coll.Name)
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+
+	modelName, err := p.st.modelName()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	total := 0
+	for _, count := range counts {
+		iter := p.coll.Find(bson.D{
+			{"model-uuid", p.st.modelUUID()},
+			{entityField, count.Entity},
+		}).Sort("-"+p.ageField, "-_id").Skip(maxEntries).Select(bson.M{"_id": 1}).Iter()
+
+		logTemplate := fmt.Sprintf("%s entity pruning (%s, %s): %%d rows deleted", p.coll.Name, modelName, count.Entity)
+		deleted, err := p.deleteInBatches(iter, logTemplate, noEarlyFinish)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := iter.Close(); err != nil {
+			return errors.Annotatef(err, "reading %s records of %q", p.coll.Name, count.Entity)
+		}
+		total += deleted
+	}
+	logger.Infof("%s entity pruning (%s): %d rows deleted from %d entities", p.coll.Name, modelName, total, len(counts))
+	return nil
+}
+
 func (p *collectionPruner) deleteInBatches(iter *mgo.Iter, logTemplate string, shouldStop doneCheck) (int, error) {
 	var doc bson.M
 	chunk := p.coll.Bulk()
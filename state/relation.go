@@ -47,6 +47,14 @@ type relationDoc struct {
 	UnitCount       int        `bson:"unitcount"`
 	Suspended       bool       `bson:"suspended"`
 	SuspendedReason string     `bson:"suspended-reason"`
+
+	// DyingSince records when the relation became Dying, in
+	// nanoseconds since the epoch.
+	DyingSince int64 `bson:"dying-since,omitempty"`
+
+	// DepartureBarrier holds the relation's departure barrier, if
+	// one has been set.
+	DepartureBarrier *departureBarrierDoc `bson:"departure-barrier,omitempty"`
 }
 
 // Relation represents a relation between one or two service endpoints.
@@ -127,6 +135,9 @@ func (r *Relation) SetStatus(statusInfo status.StatusInfo) error {
 		return errors.Trace(err)
 	}
 
+	if !status.ValidRelationStatus(statusInfo.Status) {
+		return errors.NewNotValid(nil, fmt.Sprintf("cannot set invalid status %q", statusInfo.Status))
+	}
 	if currentStatus.Status != statusInfo.Status {
 		validTransition := true
 		switch statusInfo.Status {
@@ -141,8 +152,6 @@ func (r *Relation) SetStatus(statusInfo status.StatusInfo) error {
 			if statusInfo.Message == "" {
 				return errors.Errorf("cannot set status %q without info", statusInfo.Status)
 			}
-		default:
-			return errors.NewNotValid(nil, fmt.Sprintf("cannot set invalid status %q", statusInfo.Status))
 		}
 		if !validTransition {
 			return errors.NewNotValid(nil, fmt.Sprintf(
@@ -159,6 +168,42 @@ func (r *Relation) SetStatus(statusInfo status.StatusInfo) error {
 	})
 }
 
+// StatusHistory returns a slice of at most filter.Size StatusInfo items
+// or items as old as filter.Date or items newer than now - filter.Delta time
+// representing past statuses for this relation.
+func (r *Relation) StatusHistory(filter status.StatusHistoryFilter) ([]status.StatusInfo, error) {
+	args := &statusHistoryArgs{
+		db:        r.st.db(),
+		globalKey: r.globalScope(),
+		filter:    filter,
+	}
+	return statusHistory(args)
+}
+
+// StatusHistoryPage implements status.StatusHistoryPager. The
+// relation's history is read by KindRelation.
+func (r *Relation) StatusHistoryPage(kind status.HistoryKind, filter status.StatusHistoryFilter, pageSize int, token string) (status.StatusHistoryPage, error) {
+	if kind != status.KindRelation {
+		return status.StatusHistoryPage{}, errors.NotValidf("%q status history for a relation", kind)
+	}
+	return statusHistoryPage(&statusHistoryPageArgs{
+		db:       r.st.db(),
+		kinds:    map[string]status.HistoryKind{r.globalScope(): kind},
+		filter:   filter,
+		pageSize: pageSize,
+		token:    token,
+	})
+}
+
+// WatchStatusHistory returns a watcher reporting the entries added to
+// the relation's status history, which is selected by KindRelation.
+func (r *Relation) WatchStatusHistory(kind status.HistoryKind) (StatusHistoryWatcher, error) {
+	if kind != status.KindRelation {
+		return nil, errors.NotValidf("%q status history for a relation", kind)
+	}
+	return newStatusHistoryWatcher(r.st, map[string]status.HistoryKind{r.globalScope(): kind}), nil
+}
+
 // SetSuspended sets whether the relation is suspended.
 func (r *Relation) SetSuspended(suspended bool, suspendedReason string) error {
 	if r.doc.Suspended == suspended {
@@ -306,7 +351,10 @@ func (r *Relation) destroyOps(ignoreService string) (ops []txn.Op, isRemove bool
 		C:      relationsC,
 		Id:     r.doc.DocID,
 		Assert: bson.D{{"life", Alive}, {"unitcount", bson.D{{"$gt", 0}}}},
-		Update: bson.D{{"$set", bson.D{{"life", Dying}}}},
+		Update: bson.D{{"$set", bson.D{
+			{"life", Dying},
+			{"dying-since", r.st.clock().Now().UnixNano()},
+		}}},
 	}}, false, nil
 }
 
@@ -531,6 +579,30 @@ func (r *Relation) IsCrossModel() (bool, error) {
 	return false, nil
 }
 
+// UnitSettings returns the settings of every unit that is in scope
+// in the relation, keyed on unit name. Units that have left the
+// relation's scope are not included, even though their settings are
+// retained for the lifetime of the relation.
+func (r *Relation) UnitSettings() (map[string]map[string]interface{}, error) {
+	relationScopes, closer := r.st.db().GetCollection(relationScopesC)
+	defer closer()
+
+	var docs []relationScopeDoc
+	sel := bson.D{{"key", bson.D{{"$regex", "^" + r.globalScope() + "#"}}}}
+	if err := relationScopes.Find(sel).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get units in scope of relation %q", r)
+	}
+	result := make(map[string]map[string]interface{}, len(docs))
+	for _, doc := range docs {
+		settings, err := readSettings(r.st.db(), settingsC, doc.Key)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot read settings for unit %q in relation %q", doc.unitName(), r)
+		}
+		result[doc.unitName()] = settings.Map()
+	}
+	return result, nil
+}
+
 func (r *Relation) unit(
 	unitName string,
 	principal string,
@@ -4,6 +4,8 @@
 package state_test
 
 import (
+	"fmt"
+
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
@@ -589,6 +591,38 @@ func (s *RelationSuite) TestInvalidStatus(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `cannot set invalid status "invalid"`)
 }
 
+func (s *RelationSuite) TestApplicationSetRelationStatus(c *gc.C) {
+	rel := s.setupRelationStatus(c)
+	mysql, err := s.State.Application("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysql.SetRelationStatus(rel.Id(), status.StatusInfo{
+		Status:  status.Error,
+		Message: "database unreachable",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	relStatus, err := rel.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(relStatus.Status, gc.Equals, status.Error)
+	c.Assert(relStatus.Message, gc.Equals, "database unreachable")
+}
+
+func (s *RelationSuite) TestApplicationSetRelationStatusNotInRelation(c *gc.C) {
+	rel := s.setupRelationStatus(c)
+	riak := s.AddTestingApplication(c, "riak", s.AddTestingCharm(c, "riak"))
+	err := riak.SetRelationStatus(rel.Id(), status.StatusInfo{Status: status.Joined})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, fmt.Sprintf(`application "riak" in relation %d not found`, rel.Id()))
+}
+
+func (s *RelationSuite) TestApplicationSetRelationStatusInvalid(c *gc.C) {
+	rel := s.setupRelationStatus(c)
+	mysql, err := s.State.Application("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysql.SetRelationStatus(rel.Id(), status.StatusInfo{Status: status.Active})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, fmt.Sprintf(`relation %d: cannot set invalid status "active"`, rel.Id()))
+}
+
 func (s *RelationSuite) TestSetSuspend(c *gc.C) {
 	rel := s.setupRelationStatus(c)
 	// Suspend doesn't need an offer connection to be there.
@@ -0,0 +1,209 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// departureBarrierDoc records a relation's departure barrier, and the
+// requirer units that have confirmed they have cleaned up.
+type departureBarrierDoc struct {
+	// Timeout is how long, in nanoseconds, the barrier holds after
+	// the relation becomes Dying.
+	Timeout int64 `bson:"timeout"`
+
+	// Set records when the barrier was first set, in nanoseconds
+	// since the epoch.
+	Set int64 `bson:"set"`
+
+	// Confirmed holds the names of the requirer units that have
+	// confirmed their departure.
+	Confirmed []string `bson:"confirmed,omitempty"`
+}
+
+// DepartureBarrier describes a relation's departure barrier. While a
+// Dying relation's barrier holds, units on the provider side of the
+// relation do not run their relation-broken hooks. The barrier is
+// released once every local requirer unit in the relation's scope has
+// confirmed its departure, or once Timeout has passed since the
+// relation became Dying (or since the barrier was set, if later).
+type DepartureBarrier struct {
+	// Timeout is how long the barrier holds before it is released
+	// regardless of the requirer units.
+	Timeout time.Duration
+
+	// Set is when the barrier was first set.
+	Set time.Time
+
+	// Confirmed holds the names of the requirer units that have
+	// confirmed their departure.
+	Confirmed []string
+}
+
+// DepartureBarrier returns the relation's departure barrier, or a
+// NotFound error if none has been set.
+func (r *Relation) DepartureBarrier() (DepartureBarrier, error) {
+	doc := r.doc.DepartureBarrier
+	if doc == nil {
+		return DepartureBarrier{}, errors.NotFoundf("departure barrier for relation %q", r)
+	}
+	return DepartureBarrier{
+		Timeout:   time.Duration(doc.Timeout),
+		Set:       time.Unix(0, doc.Set).UTC(),
+		Confirmed: doc.Confirmed,
+	}, nil
+}
+
+// SetDepartureBarrier sets a departure barrier on the relation, so
+// that provider units wait for the requirer units to confirm their
+// departure, for up to the given timeout, before breaking the relation.
+// Setting the barrier again only changes its timeout.
+func (r *Relation) SetDepartureBarrier(timeout time.Duration) error {
+	if timeout <= 0 {
+		return errors.NotValidf("departure barrier timeout %v", timeout)
+	}
+	if !r.hasRole(charm.RoleProvider) || !r.hasRole(charm.RoleRequirer) {
+		return errors.Errorf("cannot set departure barrier on relation %q: not a provider/requirer relation", r)
+	}
+	set := bson.D{{"departure-barrier.timeout", int64(timeout)}}
+	if r.doc.DepartureBarrier == nil {
+		set = append(set, bson.DocElem{"departure-barrier.set", r.st.clock().Now().UnixNano()})
+	}
+	ops := []txn.Op{{
+		C:      relationsC,
+		Id:     r.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", set}},
+	}}
+	if err := r.st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("relation %v", r)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot set departure barrier on relation %q", r)
+	}
+	return r.Refresh()
+}
+
+// ConfirmDeparture records that the named unit, on the requirer side
+// of the relation, has cleaned up after the relation, so that the
+// relation's departure barrier need not wait for it.
+func (r *Relation) ConfirmDeparture(unitName string) error {
+	appName, err := names.UnitApplication(unitName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ep, err := r.Endpoint(appName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if ep.Role != charm.RoleRequirer {
+		return errors.Errorf("cannot confirm departure of unit %q from relation %q: not a requirer", unitName, r)
+	}
+	ops := []txn.Op{{
+		C:      relationsC,
+		Id:     r.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$addToSet", bson.D{{"departure-barrier.confirmed", unitName}}}},
+	}}
+	if err := r.st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("relation %v", r)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot confirm departure of unit %q from relation %q", unitName, r)
+	}
+	return r.Refresh()
+}
+
+// DepartureBarrierReleased reports whether the provider units of the
+// relation may break it. That is the case if the relation has no
+// departure barrier, or if it is Dying and the barrier has been
+// released. See DepartureBarrier for when that happens.
+func (r *Relation) DepartureBarrierReleased() (bool, error) {
+	doc := r.doc.DepartureBarrier
+	if doc == nil {
+		return true, nil
+	}
+	if r.doc.Life == Alive {
+		return false, nil
+	}
+	since := r.doc.DyingSince
+	if doc.Set > since {
+		since = doc.Set
+	}
+	if since > 0 && !r.st.clock().Now().Before(time.Unix(0, since+doc.Timeout)) {
+		return true, nil
+	}
+
+	waiting, err := r.unconfirmedRequirers()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return len(waiting) == 0, nil
+}
+
+// unconfirmedRequirers returns the names of the local requirer units
+// in the relation's scope that have not confirmed their departure.
+// Units of remote applications cannot confirm their departure, so they
+// are not included.
+func (r *Relation) unconfirmedRequirers() ([]string, error) {
+	relationScopes, closer := r.st.db().GetCollection(relationScopesC)
+	defer closer()
+
+	var docs []relationScopeDoc
+	sel := bson.D{{"key", bson.D{{"$regex", "^" + r.globalScope() + "#"}}}}
+	if err := relationScopes.Find(sel).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get units in scope of relation %q", r)
+	}
+	confirmed := make(map[string]bool)
+	if r.doc.DepartureBarrier != nil {
+		for _, name := range r.doc.DepartureBarrier.Confirmed {
+			confirmed[name] = true
+		}
+	}
+	remote := make(map[string]bool)
+	var waiting []string
+	for _, doc := range docs {
+		parts := strings.Split(doc.Key, "#")
+		if len(parts) < 2 || parts[len(parts)-2] != string(charm.RoleRequirer) {
+			continue
+		}
+		unitName := doc.unitName()
+		if confirmed[unitName] {
+			continue
+		}
+		appName, err := names.UnitApplication(unitName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		isRemote, ok := remote[appName]
+		if !ok {
+			_, err := r.st.RemoteApplication(appName)
+			if err != nil && !errors.IsNotFound(err) {
+				return nil, errors.Trace(err)
+			}
+			isRemote = err == nil
+			remote[appName] = isRemote
+		}
+		if !isRemote {
+			waiting = append(waiting, unitName)
+		}
+	}
+	return waiting, nil
+}
+
+// hasRole reports whether one of the relation's endpoints has the role.
+func (r *Relation) hasRole(role charm.RelationRole) bool {
+	for _, ep := range r.doc.Endpoints {
+		if ep.Role == role {
+			return true
+		}
+	}
+	return false
+}
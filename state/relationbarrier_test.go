@@ -0,0 +1,144 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6"
+
+	"github.com/juju/juju/state"
+)
+
+type relationBarrierSuite struct {
+	ConnSuite
+	prr *ProReqRelation
+}
+
+var _ = gc.Suite(&relationBarrierSuite{})
+
+func (s *relationBarrierSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.prr = newProReqRelation(c, &s.ConnSuite, charm.ScopeGlobal)
+	for _, ru := range []*state.RelationUnit{s.prr.pru0, s.prr.rru0, s.prr.rru1} {
+		err := ru.EnterScope(nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *relationBarrierSuite) TestSetDepartureBarrier(c *gc.C) {
+	rel := s.prr.rel
+	_, err := rel.DepartureBarrier()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	set := s.Clock.Now()
+	err = rel.SetDepartureBarrier(10 * time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Setting the barrier again changes only the timeout.
+	s.Clock.Advance(time.Minute)
+	err = rel.SetDepartureBarrier(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = rel.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	barrier, err := rel.DepartureBarrier()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(barrier.Timeout, gc.Equals, time.Hour)
+	c.Assert(barrier.Set.Equal(set), jc.IsTrue)
+	c.Assert(barrier.Confirmed, gc.HasLen, 0)
+}
+
+func (s *relationBarrierSuite) TestSetDepartureBarrierInvalidTimeout(c *gc.C) {
+	err := s.prr.rel.SetDepartureBarrier(0)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *relationBarrierSuite) TestSetDepartureBarrierPeerRelation(c *gc.C) {
+	pr := newPeerRelation(c, s.State)
+	err := pr.ru0.Relation().SetDepartureBarrier(time.Minute)
+	c.Assert(err, gc.ErrorMatches, `cannot set departure barrier on relation "riak:ring": not a provider/requirer relation`)
+}
+
+func (s *relationBarrierSuite) TestConfirmDeparture(c *gc.C) {
+	rel := s.prr.rel
+	err := rel.SetDepartureBarrier(time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.ConfirmDeparture("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.ConfirmDeparture("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	barrier, err := rel.DepartureBarrier()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(barrier.Confirmed, jc.DeepEquals, []string{"wordpress/0"})
+}
+
+func (s *relationBarrierSuite) TestConfirmDepartureNotRequirer(c *gc.C) {
+	err := s.prr.rel.ConfirmDeparture("mysql/0")
+	c.Assert(err, gc.ErrorMatches, `cannot confirm departure of unit "mysql/0" from relation "wordpress:db mysql:server": not a requirer`)
+}
+
+func (s *relationBarrierSuite) TestReleasedWithoutBarrier(c *gc.C) {
+	released, err := s.prr.rel.DepartureBarrierReleased()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(released, jc.IsTrue)
+}
+
+func (s *relationBarrierSuite) TestNotReleasedWhileAlive(c *gc.C) {
+	rel := s.prr.rel
+	err := rel.SetDepartureBarrier(time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	s.Clock.Advance(time.Hour)
+
+	released, err := rel.DepartureBarrierReleased()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(released, jc.IsFalse)
+}
+
+func (s *relationBarrierSuite) TestReleasedWhenRequirersConfirm(c *gc.C) {
+	rel := s.prr.rel
+	err := rel.SetDepartureBarrier(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = rel.ConfirmDeparture("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	released, err := rel.DepartureBarrierReleased()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(released, jc.IsFalse)
+
+	// A requirer unit leaving scope no longer holds the barrier.
+	err = s.prr.rru1.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	released, err = rel.DepartureBarrierReleased()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(released, jc.IsTrue)
+}
+
+func (s *relationBarrierSuite) TestReleasedAfterTimeout(c *gc.C) {
+	rel := s.prr.rel
+	err := rel.SetDepartureBarrier(10 * time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	s.Clock.Advance(time.Hour)
+	err = rel.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The timeout runs from when the relation became Dying.
+	s.Clock.Advance(10*time.Minute - time.Second)
+	released, err := rel.DepartureBarrierReleased()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(released, jc.IsFalse)
+
+	s.Clock.Advance(time.Second)
+	released, err = rel.DepartureBarrierReleased()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(released, jc.IsTrue)
+}
@@ -6,6 +6,7 @@ package state
 import (
 	"fmt"
 	"net"
+	"time"
 
 	"github.com/juju/errors"
 	"gopkg.in/mgo.v2"
@@ -19,12 +20,17 @@ type RelationNetworks interface {
 	Id() string
 	RelationKey() string
 	CIDRS() []string
+
+	// Updated returns when the networks were last saved, or the zero
+	// time if that was not recorded.
+	Updated() time.Time
 }
 
 type relationNetworksDoc struct {
 	Id          string   `bson:"_id"`
 	RelationKey string   `bson:"relation-key"`
 	CIDRS       []string `bson:"cidrs"`
+	Updated     int64    `bson:"updated,omitempty"`
 }
 
 type relationNetworks struct {
@@ -52,6 +58,14 @@ func (r *relationNetworks) CIDRS() []string {
 	return r.doc.CIDRS
 }
 
+// Updated returns when the networks were last saved.
+func (r *relationNetworks) Updated() time.Time {
+	if r.doc.Updated == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, r.doc.Updated).UTC()
+}
+
 // RelationNetworker instances provide access to relation networks in state.
 type RelationNetworker interface {
 	Save(relationKey string, adminOverride bool, cidrs []string) (RelationNetworks, error)
@@ -108,6 +122,7 @@ func (rin *relationNetworksState) Save(relationKey string, adminOverride bool, c
 		Id:          rin.st.docID(relationNetworkDocID(relationKey, rin.direction, label)),
 		RelationKey: relationKey,
 		CIDRS:       cidrs,
+		Updated:     rin.st.clock().Now().UnixNano(),
 	}
 	buildTxn := func(int) ([]txn.Op, error) {
 		model, err := rin.st.Model()
@@ -138,7 +153,10 @@ func (rin *relationNetworksState) Save(relationKey string, adminOverride bool, c
 				Id:     existing.Id(),
 				Assert: txn.DocExists,
 				Update: bson.D{
-					{"$set", bson.D{{"cidrs", cidrs}}},
+					{"$set", bson.D{
+						{"cidrs", cidrs},
+						{"updated", doc.Updated},
+					}},
 				},
 			}, model.assertActiveOp(), relationExistsAssert}
 		} else {
@@ -6,6 +6,7 @@ package state_test
 import (
 	"fmt"
 	"regexp"
+	"time"
 
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -130,6 +131,20 @@ func (s *relationNetworksSuite) TestUpdateCIDRs(c *gc.C) {
 	s.assertSavedIngressInfo(c, "wordpress:db mysql:server", "10.0.0.1/16")
 }
 
+func (s *relationNetworksSuite) TestUpdated(c *gc.C) {
+	saved := s.Clock.Now()
+	rin, err := s.relationNetworks.Save("wordpress:db mysql:server", false, []string{"192.168.1.0/16"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rin.Updated().Equal(saved), jc.IsTrue)
+
+	s.Clock.Advance(time.Hour)
+	_, err = s.relationNetworks.Save("wordpress:db mysql:server", false, []string{"10.0.0.1/16"})
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := s.relationNetworks.Networks("wordpress:db mysql:server")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Updated().Equal(saved.Add(time.Hour)), jc.IsTrue)
+}
+
 func (s *relationIngressNetworksSuite) TestCrossContanination(c *gc.C) {
 	_, err := s.relationNetworks.Save("wordpress:db mysql:server", false, []string{"192.168.1.0/16"})
 	c.Assert(err, jc.ErrorIsNil)
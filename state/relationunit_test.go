@@ -153,6 +153,33 @@ func (s *RelationUnitSuite) TestPeerSettings(c *gc.C) {
 	assertJoined(c, pr.ru1)
 }
 
+func (s *RelationUnitSuite) TestRelationUnitSettings(c *gc.C) {
+	pr := newPeerRelation(c, s.State)
+	settings, err := pr.rel.UnitSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.HasLen, 0)
+
+	err = pr.ru0.EnterScope(map[string]interface{}{"gene": "kelly"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = pr.ru1.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err = pr.rel.UnitSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, map[string]map[string]interface{}{
+		"riak/0": {"gene": "kelly"},
+		"riak/1": {},
+	})
+
+	// Units that leave scope are no longer reported.
+	err = pr.ru0.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err = pr.rel.UnitSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, map[string]map[string]interface{}{
+		"riak/1": {},
+	})
+}
+
 func (s *RelationUnitSuite) TestRemoteUnitErrors(c *gc.C) {
 	_, err := s.State.AddRemoteApplication(state.AddRemoteApplicationParams{
 		Name:        "mysql",
@@ -0,0 +1,54 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
+)
+
+// sessionRevocationDoc records when the API sessions of a user were
+// last revoked.
+type sessionRevocationDoc struct {
+	DocID string `bson:"_id"`
+
+	// RevokedAt is when the sessions were revoked, in Unix
+	// nanoseconds.
+	RevokedAt int64 `bson:"revoked-at"`
+}
+
+// RevokeUserSessions records that the API sessions of the given user
+// have been revoked now. API servers refuse login macaroons that the
+// user obtained before then, so the user must authenticate again.
+func (st *State) RevokeUserSessions(user names.UserTag) error {
+	coll, closer := st.db().GetRawCollection(sessionRevocationsC)
+	defer closer()
+
+	doc := sessionRevocationDoc{
+		DocID:     strings.ToLower(user.Id()),
+		RevokedAt: st.clock().Now().UnixNano(),
+	}
+	_, err := coll.UpsertId(doc.DocID, doc)
+	return errors.Annotatef(err, "cannot revoke sessions of %q", user.Id())
+}
+
+// UserSessionsRevoked returns when the API sessions of the given user
+// were last revoked, or the zero time if they never were.
+func (st *State) UserSessionsRevoked(user names.UserTag) (time.Time, error) {
+	coll, closer := st.db().GetRawCollection(sessionRevocationsC)
+	defer closer()
+
+	var doc sessionRevocationDoc
+	err := coll.FindId(strings.ToLower(user.Id())).One(&doc)
+	if err == mgo.ErrNotFound {
+		return time.Time{}, nil
+	} else if err != nil {
+		return time.Time{}, errors.Annotatef(err, "cannot get session revocation of %q", user.Id())
+	}
+	return time.Unix(0, doc.RevokedAt).UTC(), nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+)
+
+type SessionRevocationSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&SessionRevocationSuite{})
+
+func (s *SessionRevocationSuite) TestNeverRevoked(c *gc.C) {
+	revoked, err := s.State.UserSessionsRevoked(names.NewUserTag("bob"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(revoked.IsZero(), jc.IsTrue)
+}
+
+func (s *SessionRevocationSuite) TestRevokeUserSessions(c *gc.C) {
+	now := time.Date(2018, 6, 1, 12, 0, 0, 500, time.UTC)
+	clock := testing.NewClock(now)
+	err := s.State.SetClockForTesting(clock)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RevokeUserSessions(names.NewUserTag("Bob"))
+	c.Assert(err, jc.ErrorIsNil)
+	revoked, err := s.State.UserSessionsRevoked(names.NewUserTag("bob"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(revoked, gc.Equals, now)
+
+	clock.Advance(time.Hour)
+	err = s.State.RevokeUserSessions(names.NewUserTag("bob"))
+	c.Assert(err, jc.ErrorIsNil)
+	revoked, err = s.State.UserSessionsRevoked(names.NewUserTag("bob"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(revoked, gc.Equals, now.Add(time.Hour))
+
+	revoked, err = s.State.UserSessionsRevoked(names.NewUserTag("alice"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(revoked.IsZero(), jc.IsTrue)
+}
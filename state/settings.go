@@ -9,7 +9,6 @@ import (
 	"strings"
 
 	"github.com/juju/errors"
-	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
 )
@@ -292,14 +291,7 @@ func readSettingsDoc(db Database, collection, key string) (*settingsDoc, error)
 // readSettingsDocInto reads the settings doc with the given key
 // into the provided output structure.
 func readSettingsDocInto(db Database, collection, key string, out interface{}) error {
-	settings, closer := db.GetCollection(collection)
-	defer closer()
-
-	err := settings.FindId(key).One(out)
-	if err == mgo.ErrNotFound {
-		err = errors.NotFoundf("settings")
-	}
-	return err
+	return db.DocumentStore().readSettingsDoc(collection, key, out)
 }
 
 // ReadSettings returns the settings for the given key.
@@ -367,12 +359,8 @@ func removeSettingsOp(collection, key string) txn.Op {
 
 // listSettings returns all the settings with the specified key prefix.
 func listSettings(backend modelBackend, collection, keyPrefix string) (map[string]map[string]interface{}, error) {
-	settings, closer := backend.db().GetRawCollection(collection)
-	defer closer()
-
-	var matchingSettings []settingsDoc
-	findExpr := fmt.Sprintf("^%s.*$", backend.docID(keyPrefix))
-	if err := settings.Find(bson.D{{"_id", bson.D{{"$regex", findExpr}}}}).All(&matchingSettings); err != nil {
+	matchingSettings, err := backend.db().DocumentStore().listSettingsDocs(collection, backend.docID(keyPrefix))
+	if err != nil {
 		return nil, err
 	}
 	result := make(map[string]map[string]interface{})
@@ -0,0 +1,97 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/core/tracing"
+)
+
+// slowSpanDoc records a slow operation performed for an entity in the
+// model.
+type slowSpanDoc struct {
+	ModelUUID string `bson:"model-uuid"`
+	Kind      string `bson:"kind"`
+	Entity    string `bson:"entity"`
+	Name      string `bson:"name"`
+	Start     int64  `bson:"start"`
+	Duration  int64  `bson:"duration"`
+	Error     string `bson:"error,omitempty"`
+}
+
+// RecordSlowSpans records the spans of slow operations performed in
+// the model, and prunes spans that started longer than maxAge ago.
+func (m *Model) RecordSlowSpans(spans []tracing.Span, maxAge time.Duration) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	coll, closer := m.st.db().GetCollection(slowSpansC)
+	defer closer()
+	collW := coll.Writeable()
+
+	for _, span := range spans {
+		doc := &slowSpanDoc{
+			Kind:     string(span.Kind),
+			Entity:   span.Entity,
+			Name:     span.Name,
+			Start:    span.Start.UnixNano(),
+			Duration: int64(span.Duration),
+			Error:    span.Error,
+		}
+		if err := collW.Insert(doc); err != nil {
+			return errors.Annotate(err, "cannot record slow operation")
+		}
+	}
+	if maxAge > 0 {
+		if err := pruneCollection(m.st, maxAge, 0, slowSpansC, "start", NanoSeconds); err != nil {
+			logger.Errorf("failed to prune slow operations: %v", err)
+		}
+	}
+	return nil
+}
+
+// SlowSpansFilter restricts the spans returned by SlowSpans.
+type SlowSpansFilter struct {
+	// Kind, if not empty, restricts the spans to those of the kind.
+	Kind tracing.Kind
+
+	// Entity, if not empty, restricts the spans to those of the
+	// entity with the tag.
+	Entity string
+}
+
+// SlowSpans returns the recorded spans of slow operations performed in
+// the model that match the filter, in the order they started.
+func (m *Model) SlowSpans(filter SlowSpansFilter) ([]tracing.Span, error) {
+	coll, closer := m.st.db().GetCollection(slowSpansC)
+	defer closer()
+
+	sel := bson.D{}
+	if filter.Kind != "" {
+		sel = append(sel, bson.DocElem{"kind", string(filter.Kind)})
+	}
+	if filter.Entity != "" {
+		sel = append(sel, bson.DocElem{"entity", filter.Entity})
+	}
+	var docs []slowSpanDoc
+	if err := coll.Find(sel).Sort("start").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get slow operations")
+	}
+	spans := make([]tracing.Span, len(docs))
+	for i, doc := range docs {
+		spans[i] = tracing.Span{
+			Kind:     tracing.Kind(doc.Kind),
+			Entity:   doc.Entity,
+			Name:     doc.Name,
+			Start:    time.Unix(0, doc.Start).UTC(),
+			Duration: time.Duration(doc.Duration),
+			Error:    doc.Error,
+		}
+	}
+	return spans, nil
+}
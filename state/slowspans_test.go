@@ -0,0 +1,78 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/tracing"
+	"github.com/juju/juju/state"
+)
+
+type SlowSpansSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&SlowSpansSuite{})
+
+func (s *SlowSpansSuite) TestRecordSlowSpans(c *gc.C) {
+	start := s.Clock.Now().UTC()
+	hook := tracing.Span{
+		Kind:     tracing.KindHook,
+		Entity:   "unit-mysql-0",
+		Name:     "install",
+		Start:    start.Add(time.Minute),
+		Duration: 5 * time.Minute,
+		Error:    `exit status 1`,
+	}
+	call := tracing.Span{
+		Kind:     tracing.KindProviderCall,
+		Entity:   "machine-0",
+		Name:     "StartInstance",
+		Start:    start,
+		Duration: time.Minute,
+	}
+	err := s.Model.RecordSlowSpans([]tracing.Span{hook, call}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+
+	spans, err := s.Model.SlowSpans(state.SlowSpansFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spans, jc.DeepEquals, []tracing.Span{call, hook})
+
+	spans, err = s.Model.SlowSpans(state.SlowSpansFilter{Kind: tracing.KindHook})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spans, jc.DeepEquals, []tracing.Span{hook})
+
+	spans, err = s.Model.SlowSpans(state.SlowSpansFilter{Entity: "unit-mysql-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spans, gc.HasLen, 0)
+}
+
+func (s *SlowSpansSuite) TestRecordSlowSpansPruned(c *gc.C) {
+	old := tracing.Span{
+		Kind:     tracing.KindHook,
+		Entity:   "unit-mysql-0",
+		Name:     "install",
+		Start:    s.Clock.Now().UTC(),
+		Duration: time.Minute,
+	}
+	err := s.Model.RecordSlowSpans([]tracing.Span{old}, time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Spans older than the maximum age are pruned as the next spans
+	// are recorded.
+	s.Clock.Advance(2 * time.Hour)
+	recent := old
+	recent.Name = "start"
+	recent.Start = s.Clock.Now().UTC()
+	err = s.Model.RecordSlowSpans([]tracing.Span{recent}, time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+
+	spans, err := s.Model.SlowSpans(state.SlowSpansFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spans, jc.DeepEquals, []tracing.Span{recent})
+}
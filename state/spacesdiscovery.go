@@ -109,7 +109,7 @@ func (st *State) SaveSubnetsFromProvider(subnets []network.SubnetInfo, spaceName
 			if err != nil {
 				return errors.Trace(err)
 			}
-			subnetWithDashes := strings.Replace(strings.Replace(subnetNet.String(), ".", "-", -1), "/", "-", -1)
+			subnetWithDashes := strings.NewReplacer(".", "-", ":", "-", "/", "-").Replace(subnetNet.String())
 			id := fmt.Sprintf("%s-INFAN-%s", subnet.ProviderId, subnetWithDashes)
 			if modelSubnetIds.Contains(id) {
 				continue
@@ -88,6 +88,11 @@ type State struct {
 	policy                 Policy
 	newPolicy              NewPolicyFunc
 	runTransactionObserver RunTransactionObserverFunc
+	databaseBackend        string
+
+	// slowTxns caches the model's slow-transactions fault for the
+	// transaction runners of its database.
+	slowTxns *slowTxnFault
 
 	// cloudName is the name of the cloud on which the model
 	// represented by this state runs.
@@ -339,7 +344,7 @@ func (st *State) ForModel(modelTag names.ModelTag) (*State, error) {
 	session := st.session.Copy()
 	newSt, err := newState(
 		modelTag, st.controllerModelTag, session, st.mongoInfo, st.newPolicy, st.stateClock,
-		st.runTransactionObserver,
+		st.runTransactionObserver, st.databaseBackend,
 	)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -599,6 +604,13 @@ func (st *State) WatchAllModels(pool *StatePool) *Multiwatcher {
 	return NewMultiwatcher(st.workers.allModelManager(pool))
 }
 
+// WatchModelAnnotations returns a Multiwatcher that reports only the
+// models of the controller and the annotations within them. It is
+// much cheaper to run than WatchAllModels.
+func (st *State) WatchModelAnnotations(pool *StatePool) *Multiwatcher {
+	return NewMultiwatcher(st.workers.modelAnnotationsManager(pool))
+}
+
 // versionInconsistentError indicates one or more agents have a
 // different version from the current one (even empty, when not yet
 // set).
@@ -1145,9 +1157,11 @@ func (st *State) AddApplication(args AddApplicationArgs) (_ *Application, err er
 			if err != nil {
 				return nil, errors.Trace(err)
 			}
+			// The machine was chosen explicitly, so the
+			// architecture constraint is not enforced.
 			subordinate := args.Charm.Meta().Subordinate
 			if err := validateUnitMachineAssignment(
-				m, args.Series, subordinate, storagePools,
+				m, args.Series, constraints.Value{}, subordinate, storagePools,
 			); err != nil {
 				return nil, errors.Annotatef(
 					err, "cannot deploy to machine %s", m,
@@ -1987,16 +2001,25 @@ func (st *State) AssignUnit(u *Unit, policy AssignmentPolicy) (err error) {
 		}
 		return u.AssignToMachine(m)
 	case AssignClean:
+		if _, err = u.AssignToPoolMachine(); errors.Cause(err) != noPoolMachines {
+			return errors.Trace(err)
+		}
 		if _, err = u.AssignToCleanMachine(); errors.Cause(err) != noCleanMachines {
 			return errors.Trace(err)
 		}
 		return u.AssignToNewMachineOrContainer()
 	case AssignCleanEmpty:
+		if _, err = u.AssignToPoolMachine(); errors.Cause(err) != noPoolMachines {
+			return errors.Trace(err)
+		}
 		if _, err = u.AssignToCleanEmptyMachine(); errors.Cause(err) != noCleanMachines {
 			return errors.Trace(err)
 		}
 		return u.AssignToNewMachineOrContainer()
 	case AssignNew:
+		if _, err = u.AssignToPoolMachine(); errors.Cause(err) != noPoolMachines {
+			return errors.Trace(err)
+		}
 		return errors.Trace(u.AssignToNewMachine())
 	}
 	return errors.Errorf("unknown unit assignment policy: %q", policy)
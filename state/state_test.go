@@ -30,6 +30,7 @@ import (
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/mongo"
@@ -198,6 +199,54 @@ func (s *StateSuite) TestOpenRequiresExtantModelTag(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, expect)
 }
 
+func (s *StateSuite) TestOpenUnsupportedDatabaseBackend(c *gc.C) {
+	params := s.testOpenParams()
+	params.DatabaseBackend = "bbolt"
+	st, err := state.Open(params)
+	if !c.Check(st, gc.IsNil) {
+		c.Check(st.Close(), jc.ErrorIsNil)
+	}
+	c.Check(err, gc.ErrorMatches, `validating args: database backend "bbolt" not supported`)
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *StateSuite) TestControllerConfigRecordsDatabaseBackend(c *gc.C) {
+	cfg, err := s.State.ControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg[controller.DatabaseBackend], gc.Equals, controller.MongoDatabaseBackend)
+}
+
+func (s *StateSuite) TestOpenReadsDatabaseBackend(c *gc.C) {
+	controllers := s.State.MongoSession().DB("juju").C("controllers")
+	err := controllers.UpdateId("controllerSettings", bson.M{
+		"$set": bson.M{"settings.database-backend": "bbolt"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// No backend is requested, so the recorded one is used.
+	st, err := state.Open(s.testOpenParams())
+	if !c.Check(st, gc.IsNil) {
+		c.Check(st.Close(), jc.ErrorIsNil)
+	}
+	c.Check(err, gc.ErrorMatches, `database backend "bbolt" not supported`)
+}
+
+func (s *StateSuite) TestOpenConflictingDatabaseBackend(c *gc.C) {
+	controllers := s.State.MongoSession().DB("juju").C("controllers")
+	err := controllers.UpdateId("controllerSettings", bson.M{
+		"$set": bson.M{"settings.database-backend": "bbolt"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	params := s.testOpenParams()
+	params.DatabaseBackend = controller.MongoDatabaseBackend
+	st, err := state.Open(params)
+	if !c.Check(st, gc.IsNil) {
+		c.Check(st.Close(), jc.ErrorIsNil)
+	}
+	c.Check(err, gc.ErrorMatches, `controller uses database backend "bbolt", not "mongodb"`)
+}
+
 func (s *StateSuite) TestOpenSetsModelTag(c *gc.C) {
 	st, err := state.Open(s.testOpenParams())
 	c.Assert(err, jc.ErrorIsNil)
@@ -1676,6 +1725,52 @@ func (s *StateSuite) TestAddServiceWithSpecifiedBindings(c *gc.C) {
 	})
 }
 
+func (s *StateSuite) TestAddServiceWithModelDefaultSpace(c *gc.C) {
+	_, err := s.State.AddSpace("db", "", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("client", "", nil, true)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.IAASModel.UpdateModelConfig(map[string]interface{}{"default-space": "db"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ch := s.AddMetaCharm(c, "mysql", metaBase, 45)
+	svc, err := s.State.AddApplication(state.AddApplicationArgs{
+		Name:  "yoursql",
+		Charm: ch,
+		EndpointBindings: map[string]string{
+			"client": "client",
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	bindings, err := svc.EndpointBindings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bindings, jc.DeepEquals, map[string]string{
+		"":        "db",
+		"server":  "db",
+		"client":  "client",
+		"cluster": "db",
+	})
+
+	// A default binding given for the application overrides the
+	// model's default space.
+	svc, err = s.State.AddApplication(state.AddApplicationArgs{
+		Name:  "oursql",
+		Charm: ch,
+		EndpointBindings: map[string]string{
+			"": "client",
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	bindings, err = svc.EndpointBindings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bindings, jc.DeepEquals, map[string]string{
+		"":        "client",
+		"server":  "client",
+		"client":  "client",
+		"cluster": "client",
+	})
+}
+
 func (s *StateSuite) TestAddServiceWithInvalidBindings(c *gc.C) {
 	charm := s.AddMetaCharm(c, "mysql", metaBase, 44)
 	// Add extra spaces to use in bindings.
@@ -4484,6 +4579,69 @@ func (s *StateSuite) TestWatchAPIHostPorts(c *gc.C) {
 	wc.AssertClosed()
 }
 
+func (s *StateSuite) TestAPIHostPortsForAgents(c *gc.C) {
+	err := s.State.SetAPIHostPorts([][]network.HostPort{
+		network.NewHostPorts(17777, "10.0.0.1", "192.168.100.1"),
+		network.NewHostPorts(17777, "192.168.100.2"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// With no policy configured, agents are given every address.
+	hostPorts, err := s.State.APIHostPortsForAgents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hostPorts, jc.DeepEquals, [][]network.HostPort{
+		network.NewHostPorts(17777, "10.0.0.1", "192.168.100.1"),
+		network.NewHostPorts(17777, "192.168.100.2"),
+	})
+
+	err = s.IAASModel.UpdateModelConfig(map[string]interface{}{
+		"api-advertise-addresses":     "203.0.113.7,203.0.113.8:443",
+		"api-advertise-exclude-cidrs": "192.168.100.0/24",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	controllerConfig, err := s.State.ControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	hostPorts, err = s.State.APIHostPortsForAgents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hostPorts, jc.DeepEquals, [][]network.HostPort{
+		append(
+			network.NewHostPorts(controllerConfig.APIPort(), "203.0.113.7"),
+			network.NewHostPorts(443, "203.0.113.8")...,
+		),
+		network.NewHostPorts(17777, "10.0.0.1"),
+	})
+
+	// The addresses themselves are unchanged.
+	hostPorts, err = s.State.APIHostPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hostPorts, gc.HasLen, 2)
+}
+
+func (s *StateSuite) TestWatchAPIHostPortsForAgents(c *gc.C) {
+	w := s.State.WatchAPIHostPortsForAgents()
+	defer statetesting.AssertStop(c, w)
+
+	// Initial event.
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err := s.State.SetAPIHostPorts([][]network.HostPort{
+		network.NewHostPorts(99, "0.1.2.3"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.IAASModel.UpdateModelConfig(map[string]interface{}{
+		"api-advertise-exclude-cidrs": "0.1.2.0/24",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Stop, check closed.
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
 func (s *StateSuite) TestWatchMachineAddresses(c *gc.C) {
 	// Add a machine: reported.
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
@@ -4626,6 +4784,7 @@ func (s *StateSuite) TestRunTransactionObserver(c *gc.C) {
 		dbName    string
 		modelUUID string
 		ops       []mgotxn.Op
+		duration  time.Duration
 		err       error
 	}
 	var mu sync.Mutex
@@ -4637,13 +4796,14 @@ func (s *StateSuite) TestRunTransactionObserver(c *gc.C) {
 	}
 
 	params := s.testOpenParams()
-	params.RunTransactionObserver = func(dbName, modelUUID string, ops []mgotxn.Op, err error) {
+	params.RunTransactionObserver = func(dbName, modelUUID string, ops []mgotxn.Op, duration time.Duration, err error) {
 		mu.Lock()
 		defer mu.Unlock()
 		recordedCalls = append(recordedCalls, args{
 			dbName:    dbName,
 			modelUUID: modelUUID,
 			ops:       ops,
+			duration:  duration,
 			err:       err,
 		})
 	}
@@ -4668,6 +4828,7 @@ func (s *StateSuite) TestRunTransactionObserver(c *gc.C) {
 		c.Check(call.modelUUID, gc.Equals, s.modelTag.Id())
 		c.Check(call.err, gc.IsNil)
 		c.Check(call.ops, gc.HasLen, 1)
+		c.Check(call.duration > 0, jc.IsTrue)
 		c.Check(call.ops[0].Update, gc.NotNil)
 		found = true
 		break
@@ -5,10 +5,12 @@ package stateenvirons
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/utils/featureflag"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/state"
 )
 
@@ -62,6 +64,9 @@ type NewEnvironFunc func(*state.State) (environs.Environ, error)
 // using the given environs.NewEnvironFunc.
 func GetNewEnvironFunc(newEnviron environs.NewEnvironFunc) NewEnvironFunc {
 	return func(st *state.State) (environs.Environ, error) {
+		if err := CheckProviderFault(st); err != nil {
+			return nil, errors.Trace(err)
+		}
 		m, err := st.Model()
 		if err != nil {
 			return nil, errors.Trace(err)
@@ -70,3 +75,19 @@ func GetNewEnvironFunc(newEnviron environs.NewEnvironFunc) NewEnvironFunc {
 		return environs.GetEnviron(g, newEnviron)
 	}
 }
+
+// CheckProviderFault returns an error while a provider API fault is
+// injected into the model, so that opening the model's environ fails
+// as it would if the provider's API were unavailable.
+func CheckProviderFault(st *state.State) error {
+	if !featureflag.Enabled(feature.FaultInjection) {
+		return nil
+	}
+	fault, err := st.ActiveFault(state.FaultProviderAPI)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Errorf("provider API unavailable (fault injected until %v)", fault.Expires)
+}
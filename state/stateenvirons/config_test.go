@@ -4,12 +4,16 @@
 package stateenvirons_test
 
 import (
+	"time"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/feature"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/stateenvirons"
 	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/testing/factory"
@@ -37,6 +41,45 @@ func (s *environSuite) TestGetNewEnvironFunc(c *gc.C) {
 	c.Assert(callArgs.Config, jc.DeepEquals, cfg)
 }
 
+func (s *environSuite) TestGetNewEnvironFuncProviderFault(c *gc.C) {
+	s.SetFeatureFlags(feature.FaultInjection)
+	err := s.State.InjectFault(state.Fault{
+		Kind:    state.FaultProviderAPI,
+		Expires: s.Clock.Now().Add(time.Minute),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	var calls int
+	newEnviron := func(args environs.OpenParams) (environs.Environ, error) {
+		calls++
+		return nil, nil
+	}
+	_, err = stateenvirons.GetNewEnvironFunc(newEnviron)(s.State)
+	c.Assert(err, gc.ErrorMatches, `provider API unavailable \(fault injected until .*\)`)
+	c.Assert(calls, gc.Equals, 0)
+}
+
+func (s *environSuite) TestCheckProviderFault(c *gc.C) {
+	err := s.State.InjectFault(state.Fault{
+		Kind:    state.FaultProviderAPI,
+		Expires: s.Clock.Now().Add(time.Minute),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Faults are ignored unless the feature flag is set.
+	err = stateenvirons.CheckProviderFault(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.SetFeatureFlags(feature.FaultInjection)
+	err = stateenvirons.CheckProviderFault(s.State)
+	c.Assert(err, gc.ErrorMatches, `provider API unavailable \(fault injected until .*\)`)
+
+	err = s.State.ClearFault(state.FaultProviderAPI)
+	c.Assert(err, jc.ErrorIsNil)
+	err = stateenvirons.CheckProviderFault(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *environSuite) TestCloudSpec(c *gc.C) {
 	owner := s.Factory.MakeUser(c, nil).UserTag()
 	emptyCredential := cloud.NewEmptyCredential()
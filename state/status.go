@@ -4,7 +4,10 @@
 package state
 
 import (
-	"reflect"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
@@ -30,11 +33,8 @@ type ModelStatus struct {
 // LoadModelStatus retrieves all the status documents for the model
 // at once. Used to primarily speed up status.
 func (m *Model) LoadModelStatus() (*ModelStatus, error) {
-	statuses, closer := m.st.db().GetCollection(statusesC)
-	defer closer()
-
 	var docs []statusDocWithID
-	err := statuses.Find(nil).All(&docs)
+	err := m.st.db().DocumentStore().listStatusDocs(&docs)
 	if err != nil {
 		return nil, errors.Annotate(err, "failed to read status collection")
 	}
@@ -210,12 +210,10 @@ func unixNanoToTime(i int64) *time.Time {
 // is not found, a NotFoundError referencing badge will be returned.
 func getStatus(db Database, globalKey, badge string) (_ status.StatusInfo, err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot get status")
-	statuses, closer := db.GetCollection(statusesC)
-	defer closer()
 
 	var doc statusDoc
-	err = statuses.FindId(globalKey).One(&doc)
-	if err == mgo.ErrNotFound {
+	err = db.DocumentStore().readStatusDoc(globalKey, &doc)
+	if errors.IsNotFound(err) {
 		return status.StatusInfo{}, errors.NotFoundf(badge)
 	} else if err != nil {
 		return status.StatusInfo{}, errors.Trace(err)
@@ -339,15 +337,65 @@ type historicalStatusDoc struct {
 	GlobalKey  string                 `bson:"globalkey"`
 	Status     status.Status          `bson:"status"`
 	StatusInfo string                 `bson:"statusinfo"`
-	StatusData map[string]interface{} `bson:"statusdata"`
+	StatusData map[string]interface{} `bson:"statusdata,omitempty"`
+
+	// Payload holds the snappy-compressed StatusData, along with
+	// StatusInfo if it is long, of entries written since history
+	// was compressed at rest. See compress and decompress.
+	Payload []byte `bson:"payload,omitempty"`
 
 	// Updated might not be present on statuses copied by old
 	// versions of juju from yet older versions of juju.
 	Updated int64 `bson:"updated"`
+
+	// RepeatCycle, RepeatPending and Repeats record the repetitions of
+	// the entries up to and including this one that were folded into
+	// it, rather than written, and Repeated is the time of the last of
+	// them. See status.FoldRepeat.
+	RepeatCycle   int   `bson:"repeat-cycle,omitempty"`
+	RepeatPending int   `bson:"repeat-pending,omitempty"`
+	Repeats       int   `bson:"repeats,omitempty"`
+	Repeated      int64 `bson:"repeated,omitempty"`
+
+	// Superseded is set once a later entry is written, so that a
+	// writer folding a repetition into this entry can tell that it
+	// has lost a race with another writer.
+	Superseded bool `bson:"superseded,omitempty"`
+}
+
+// detailedStatus returns the status, message and data of the entry,
+// which must not be compressed.
+func (doc *historicalStatusDoc) detailedStatus() status.DetailedStatus {
+	return status.DetailedStatus{
+		Status: doc.Status,
+		Info:   doc.StatusInfo,
+		Data:   doc.StatusData,
+	}
+}
+
+// repetition returns the repetitions folded into the entry.
+func (doc *historicalStatusDoc) repetition() status.Repetition {
+	return status.Repetition{
+		Cycle:   doc.RepeatCycle,
+		Pending: doc.RepeatPending,
+		Count:   doc.Repeats,
+	}
 }
 
+// maxStatusHistoryAttempts bounds the attempts to fold a status into
+// an entity's latest status history entries while other writers are
+// changing them.
+const maxStatusHistoryAttempts = 3
+
+// errStatusHistoryChanged is returned by foldStatusHistory when the
+// latest entries changed after they were read.
+var errStatusHistoryChanged = errors.New("status history changed")
+
+// probablyUpdateStatusHistory records the status in the entity's status
+// history, unless it repeats the most recent entries, in which case
+// the repetition is counted against the latest entry instead.
 func probablyUpdateStatusHistory(db Database, globalKey string, doc statusDoc) {
-	historyDoc := &historicalStatusDoc{
+	historyDoc := historicalStatusDoc{
 		Status:     doc.Status,
 		StatusInfo: doc.StatusInfo,
 		StatusData: doc.StatusData, // coming from a statusDoc, already escaped
@@ -357,41 +405,121 @@ func probablyUpdateStatusHistory(db Database, globalKey string, doc statusDoc) {
 	history, closer := db.GetCollection(statusesHistoryC)
 	defer closer()
 
-	// Find the current value to see if it is worthwhile adding the new
-	// status value.
-	var latest []historicalStatusDoc
-	query := history.Find(bson.D{{globalKeyField, globalKey}})
-	query = query.Sort("-updated").Limit(1)
-	err := query.All(&latest)
-	if err == nil && len(latest) == 1 {
-		current := latest[0]
-		// Short circuit the writing to the DB if the status, message,
-		// and data match.
-		dataSame := func(left, right map[string]interface{}) bool {
-			// If they are both empty, then it is the same.
-			if len(left) == 0 && len(right) == 0 {
-				return true
-			}
-			// If either are now empty, they aren't the same.
-			if len(left) == 0 || len(right) == 0 {
-				return false
-			}
-			// Failing that, use reflect.
-			return reflect.DeepEqual(left, right)
-		}
-		// Check the data last as the short circuit evaluation may mean
-		// we rarely need to drop down into the reflect library.
-		if current.Status == doc.Status &&
-			current.StatusInfo == doc.StatusInfo &&
-			dataSame(current.StatusData, doc.StatusData) {
-			return
+	for attempt := 0; attempt < maxStatusHistoryAttempts; attempt++ {
+		err := foldStatusHistory(history, historyDoc)
+		if err == errStatusHistoryChanged {
+			continue
+		} else if err != nil {
+			logger.Errorf("failed to write status history: %v", err)
 		}
+		return
+	}
+	// Others keep changing the history; record the status without
+	// folding it rather than losing it.
+	logger.Warningf("status history of %q keeps changing; writing without folding repetitions", globalKey)
+	if err := insertStatusHistory(history.Writeable(), historyDoc); err != nil {
+		logger.Errorf("failed to write status history: %v", err)
 	}
+}
 
+// foldStatusHistory folds the status into the latest status history
+// entries, or writes it after them if it does not repeat them. The
+// latest entry is only changed if it is still as it was read, and is
+// marked superseded before any later entry is written, so concurrent
+// writers cannot both extend the history from the same entry; the
+// loser gets errStatusHistoryChanged.
+func foldStatusHistory(history mongo.Collection, historyDoc historicalStatusDoc) error {
 	historyW := history.Writeable()
-	if err := historyW.Insert(historyDoc); err != nil {
-		logger.Errorf("failed to write status history: %v", err)
+
+	// Find the most recent entries to see if the new status repeats
+	// them.
+	var latest []struct {
+		Id                  bson.ObjectId `bson:"_id"`
+		historicalStatusDoc `bson:",inline"`
+	}
+	query := history.Find(bson.D{{globalKeyField, historyDoc.GlobalKey}})
+	query = query.Sort("-updated").Limit(status.MaxRepeatCycle)
+	if err := query.All(&latest); err != nil {
+		logger.Warningf("cannot read latest status history: %v", err)
+		latest = nil
+	}
+	if len(latest) == 0 {
+		return insertStatusHistory(historyW, historyDoc)
+	}
+
+	recent := make(status.History, len(latest))
+	for i := range latest {
+		if err := latest[i].decompress(); err != nil {
+			logger.Warningf("cannot read latest status history: %v", err)
+		}
+		recent[i] = latest[i].detailedStatus()
+	}
+	head := latest[0]
+	if head.Superseded {
+		// A later entry is being written.
+		return errStatusHistoryChanged
+	}
+	rep, toFlush, folded := status.FoldRepeat(recent, head.repetition(), historyDoc.detailedStatus())
+	set := bson.D{
+		{"repeat-cycle", rep.Cycle},
+		{"repeat-pending", rep.Pending},
+		{"repeats", rep.Count},
+	}
+	if folded {
+		set = append(set, bson.DocElem{"repeated", historyDoc.Updated})
+	} else {
+		set = append(set, bson.DocElem{"superseded", true})
+	}
+	err := historyW.Update(bson.D{
+		{"_id", head.Id},
+		{"repeat-cycle", intValue(head.RepeatCycle)},
+		{"repeat-pending", intValue(head.RepeatPending)},
+		{"repeats", intValue(head.Repeats)},
+		{"superseded", bson.D{{"$exists", false}}},
+	}, bson.D{{"$set", set}})
+	if err == mgo.ErrNotFound {
+		return errStatusHistoryChanged
+	} else if err != nil {
+		return errors.Annotate(err, "cannot update status history repetition")
+	}
+	if folded {
+		return nil
+	}
+
+	// The entries of a broken repetition were last seen just before
+	// the last repetition was recorded; they are spaced a nanosecond
+	// apart to keep them in order.
+	for i, entry := range toFlush {
+		flushDoc := historicalStatusDoc{
+			Status:     entry.Status,
+			StatusInfo: entry.Info,
+			StatusData: entry.Data,
+			Updated:    head.Repeated - int64(len(toFlush)-1-i),
+			GlobalKey:  historyDoc.GlobalKey,
+			Superseded: true,
+		}
+		if err := insertStatusHistory(historyW, flushDoc); err != nil {
+			return errors.Trace(err)
+		}
 	}
+	return insertStatusHistory(historyW, historyDoc)
+}
+
+// intValue returns a query value matching an int field that may have
+// been omitted when zero.
+func intValue(v int) interface{} {
+	if v == 0 {
+		return bson.D{{"$in", []interface{}{0, nil}}}
+	}
+	return v
+}
+
+// insertStatusHistory compresses and writes the status history entry.
+func insertStatusHistory(historyW mongo.WriteCollection, doc historicalStatusDoc) error {
+	if err := doc.compress(); err != nil {
+		return errors.Annotate(err, "cannot compress status history")
+	}
+	return errors.Annotate(historyW.Insert(&doc), "cannot write status history")
 }
 
 func eraseStatusHistory(mb modelBackend, globalKey string) error {
@@ -412,43 +540,91 @@ type statusHistoryArgs struct {
 	filter    status.StatusHistoryFilter
 }
 
-// fetchNStatusResults will return status for the given key filtered with the
-// given filter or error.
-func fetchNStatusResults(col mongo.Collection, key string,
-	filter status.StatusHistoryFilter) ([]historicalStatusDoc, error) {
-	var (
-		docs  []historicalStatusDoc
-		query mongo.Query
-	)
-	baseQuery := bson.M{"globalkey": key}
+// statusHistoryQuery returns the query selecting the status history
+// entries that pass the given filter's date and status criteria. The
+// filter's message criteria are not part of the query: long messages
+// are compressed at rest, and the database's regular expressions
+// differ from Go's, so statusHistoryMatcher checks every entry once it
+// is read.
+func statusHistoryQuery(filter status.StatusHistoryFilter) bson.M {
+	query := bson.M{}
 	if filter.Delta != nil {
 		delta := *filter.Delta
 		// TODO(perrito666) 2016-10-06 lp:1558657
 		updated := time.Now().Add(-delta)
-		baseQuery["updated"] = bson.M{"$gt": updated.UnixNano()}
+		query["updated"] = bson.M{"$gt": updated.UnixNano()}
 	}
 	if filter.FromDate != nil {
-		baseQuery["updated"] = bson.M{"$gt": filter.FromDate.UnixNano()}
+		query["updated"] = bson.M{"$gt": filter.FromDate.UnixNano()}
+	}
+	if !filter.IncludeStatus.IsEmpty() {
+		query["status"] = bson.M{"$in": filter.IncludeStatus.Values()}
 	}
-	excludes := []string{}
-	excludes = append(excludes, filter.Exclude.Values()...)
-	if len(excludes) > 0 {
-		baseQuery["statusinfo"] = bson.M{"$nin": excludes}
+	if !filter.ExcludeStatus.IsEmpty() {
+		query["status"] = bson.M{"$nin": filter.ExcludeStatus.Values()}
 	}
+	return query
+}
 
-	query = col.Find(baseQuery).Sort("-updated")
-	if filter.Size > 0 {
-		query = query.Limit(filter.Size)
+// statusHistoryMatcher returns a function that reports whether a
+// status history message passes the filter's excluded messages and
+// message regex.
+func statusHistoryMatcher(filter status.StatusHistoryFilter) (func(string) bool, error) {
+	if !filtersMessages(filter) {
+		return func(string) bool { return true }, nil
+	}
+	matchesRegex := func(string) bool { return true }
+	if filter.MessageRegex != "" {
+		re, err := regexp.Compile(filter.MessageRegex)
+		if err != nil {
+			return nil, errors.NewNotValid(err, "MessageRegex")
+		}
+		matchesRegex = re.MatchString
 	}
-	err := query.All(&docs)
+	return func(message string) bool {
+		return !filter.Exclude.Contains(message) && matchesRegex(message)
+	}, nil
+}
 
-	if err == mgo.ErrNotFound {
-		return []historicalStatusDoc{}, errors.NotFoundf("status history")
-	} else if err != nil {
+// filtersMessages reports whether the filter rejects some entries by
+// their message, so that entries must be read past the filter's size
+// to find enough that match.
+func filtersMessages(filter status.StatusHistoryFilter) bool {
+	return filter.MessageRegex != "" || !filter.Exclude.IsEmpty()
+}
+
+// fetchNStatusResults will return status for the given key filtered with the
+// given filter or error. Entries are read, most recent first, until
+// filter.Size of them have messages that pass matches.
+func fetchNStatusResults(col mongo.Collection, key string,
+	filter status.StatusHistoryFilter, matches func(string) bool) ([]historicalStatusDoc, error) {
+	baseQuery := statusHistoryQuery(filter)
+	baseQuery["globalkey"] = key
+
+	query := col.Find(baseQuery).Sort("-updated")
+	if filter.Size > 0 && !filtersMessages(filter) {
+		query = query.Limit(filter.Size)
+	}
+	iter := query.Iter()
+	docs := []historicalStatusDoc{}
+	for filter.Size <= 0 || len(docs) < filter.Size {
+		var doc historicalStatusDoc
+		if !iter.Next(&doc) {
+			break
+		}
+		if err := doc.decompress(); err != nil {
+			iter.Close()
+			return []historicalStatusDoc{}, errors.Trace(err)
+		}
+		if !matches(doc.StatusInfo) {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+	if err := iter.Close(); err != nil {
 		return []historicalStatusDoc{}, errors.Annotatef(err, "cannot get status history")
 	}
 	return docs, nil
-
 }
 
 func statusHistory(args *statusHistoryArgs) ([]status.StatusInfo, error) {
@@ -458,13 +634,27 @@ func statusHistory(args *statusHistoryArgs) ([]status.StatusInfo, error) {
 	statusHistory, closer := args.db.GetCollection(statusesHistoryC)
 	defer closer()
 
+	matches, err := statusHistoryMatcher(args.filter)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	var results []status.StatusInfo
-	docs, err := fetchNStatusResults(statusHistory, args.globalKey, args.filter)
+	docs, err := fetchNStatusResults(statusHistory, args.globalKey, args.filter, matches)
 	partial := []status.StatusInfo{}
 	if err != nil {
 		return []status.StatusInfo{}, errors.Trace(err)
 	}
 	for _, doc := range docs {
+		if doc.Repeats > 0 {
+			// Repetitions folded into the entry when written are
+			// shown as they are by status.SquashLogs.
+			repeated := status.RepeatedEntry(doc.repetition(), unixNanoToTime(doc.Repeated))
+			partial = append(partial, status.StatusInfo{
+				Status:  repeated.Status,
+				Message: repeated.Info,
+				Since:   repeated.Since,
+			})
+		}
 		partial = append(partial, status.StatusInfo{
 			Status:  doc.Status,
 			Message: doc.StatusInfo,
@@ -472,6 +662,9 @@ func statusHistory(args *statusHistoryArgs) ([]status.StatusInfo, error) {
 			Since:   unixNanoToTime(doc.Updated),
 		})
 	}
+	if args.filter.Size > 0 && len(partial) > args.filter.Size {
+		partial = partial[:args.filter.Size]
+	}
 	results = partial
 	return results, nil
 }
@@ -480,3 +673,159 @@ func PruneStatusHistory(st *State, maxHistoryTime time.Duration, maxHistoryMB in
 	err := pruneCollection(st, maxHistoryTime, maxHistoryMB, statusesHistoryC, "updated", NanoSeconds)
 	return errors.Trace(err)
 }
+
+// PruneStatusHistoryByEntity removes the oldest entries of each status
+// history in the model until none holds more than maxEntries entries,
+// so that a single chatty entity cannot force everyone else's history
+// out when the history is pruned by size.
+func PruneStatusHistoryByEntity(st *State, maxEntries int) error {
+	err := pruneCollectionByEntity(st, maxEntries, statusesHistoryC, "globalkey", "updated")
+	return errors.Trace(err)
+}
+
+// statusHistoryCursor identifies the entry of a status history after
+// which the next page starts, and how many entries remain to be read
+// when the history is limited by size. It is handed to clients as an
+// opaque token.
+type statusHistoryCursor struct {
+	updated   int64
+	id        bson.ObjectId
+	remaining int
+}
+
+func (c statusHistoryCursor) token() string {
+	return fmt.Sprintf("%d.%s.%d", c.updated, c.id.Hex(), c.remaining)
+}
+
+func parseStatusHistoryToken(token string) (statusHistoryCursor, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || !bson.IsObjectIdHex(parts[1]) {
+		return statusHistoryCursor{}, errors.NotValidf("status history token %q", token)
+	}
+	updated, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return statusHistoryCursor{}, errors.NotValidf("status history token %q", token)
+	}
+	remaining, err := strconv.Atoi(parts[2])
+	if err != nil || remaining < 0 {
+		return statusHistoryCursor{}, errors.NotValidf("status history token %q", token)
+	}
+	return statusHistoryCursor{
+		updated:   updated,
+		id:        bson.ObjectIdHex(parts[1]),
+		remaining: remaining,
+	}, nil
+}
+
+// statusHistoryPageArgs hold the arguments to call statusHistoryPage.
+type statusHistoryPageArgs struct {
+	db Database
+	// kinds holds the global keys of the status histories to read,
+	// and the kind of the entries in each.
+	kinds    map[string]status.HistoryKind
+	filter   status.StatusHistoryFilter
+	pageSize int
+	token    string
+}
+
+// statusHistoryPage returns a page of the status histories with the
+// given keys, merged and most recent first.
+func statusHistoryPage(args *statusHistoryPageArgs) (status.StatusHistoryPage, error) {
+	if err := args.filter.Validate(); err != nil {
+		return status.StatusHistoryPage{}, errors.Annotate(err, "validating arguments")
+	}
+	if args.pageSize <= 0 {
+		return status.StatusHistoryPage{}, errors.NotValidf("page size %d", args.pageSize)
+	}
+	matches, err := statusHistoryMatcher(args.filter)
+	if err != nil {
+		return status.StatusHistoryPage{}, errors.Trace(err)
+	}
+	cursor := statusHistoryCursor{remaining: args.filter.Size}
+	query := statusHistoryQuery(args.filter)
+	if args.token != "" {
+		var err error
+		if cursor, err = parseStatusHistoryToken(args.token); err != nil {
+			return status.StatusHistoryPage{}, errors.Trace(err)
+		}
+		query["$or"] = []bson.M{
+			{"updated": bson.M{"$lt": cursor.updated}},
+			{"updated": cursor.updated, "_id": bson.M{"$lt": cursor.id}},
+		}
+	}
+	keys := make([]string, 0, len(args.kinds))
+	for key := range args.kinds {
+		keys = append(keys, key)
+	}
+	query["globalkey"] = bson.M{"$in": keys}
+
+	limit := args.pageSize
+	if args.filter.Size > 0 && cursor.remaining < limit {
+		limit = cursor.remaining
+	}
+	type pageDoc struct {
+		Id                  bson.ObjectId `bson:"_id"`
+		historicalStatusDoc `bson:",inline"`
+	}
+	var docs []pageDoc
+	var more bool
+	if limit > 0 {
+		col, closer := args.db.GetCollection(statusesHistoryC)
+		defer closer()
+		find := col.Find(query).Sort("-updated", "-_id")
+		if !filtersMessages(args.filter) {
+			// Every entry read matches, so only one more than
+			// needed is read, to tell whether there is another page.
+			find = find.Limit(limit + 1)
+		}
+		iter := find.Iter()
+		for {
+			var doc pageDoc
+			if !iter.Next(&doc) {
+				break
+			}
+			if err := doc.decompress(); err != nil {
+				iter.Close()
+				return status.StatusHistoryPage{}, errors.Trace(err)
+			}
+			if !matches(doc.StatusInfo) {
+				continue
+			}
+			if len(docs) == limit {
+				more = true
+				break
+			}
+			docs = append(docs, doc)
+		}
+		if err := iter.Close(); err != nil {
+			return status.StatusHistoryPage{}, errors.Annotatef(err, "cannot get status history")
+		}
+	}
+
+	var page status.StatusHistoryPage
+	for i := range docs {
+		doc := &docs[i]
+		kind := args.kinds[doc.GlobalKey]
+		if doc.Repeats > 0 {
+			repeated := status.RepeatedEntry(doc.repetition(), unixNanoToTime(doc.Repeated))
+			repeated.Kind = kind
+			page.Statuses = append(page.Statuses, repeated)
+		}
+		page.Statuses = append(page.Statuses, status.DetailedStatus{
+			Status: doc.Status,
+			Info:   doc.StatusInfo,
+			Data:   utils.UnescapeKeys(doc.StatusData),
+			Since:  unixNanoToTime(doc.Updated),
+			Kind:   kind,
+		})
+	}
+	if more {
+		last := docs[len(docs)-1]
+		page.NextToken = statusHistoryCursor{
+			updated:   last.Updated,
+			id:        last.Id,
+			remaining: cursor.remaining - len(docs),
+		}.token()
+	}
+	return page, nil
+}
@@ -4,13 +4,16 @@
 package state_test
 
 import (
+	"fmt"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/set"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
 
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
@@ -172,6 +175,38 @@ func (s *StatusHistorySuite) TestPruneStatusHistoryByDate(c *gc.C) {
 	}
 }
 
+func (s *StatusHistorySuite) TestPruneStatusHistoryByEntity(c *gc.C) {
+	clock := testing.NewClock(coretesting.NonZeroTime())
+	err := s.State.SetClockForTesting(clock)
+	c.Assert(err, jc.ErrorIsNil)
+	application := s.Factory.MakeApplication(c, nil)
+	chatty := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+	quiet := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+	state.PrimeUnitStatusHistory(c, clock, chatty, status.Active, 100, 100, func(i int) map[string]interface{} {
+		return map[string]interface{}{"index": i}
+	})
+	state.PrimeUnitStatusHistory(c, clock, quiet, status.Active, 5, 5, nil)
+
+	err = state.PruneStatusHistoryByEntity(s.State, 10)
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := chatty.StatusHistory(status.StatusHistoryFilter{Size: 200})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 10)
+	for i, entry := range history {
+		c.Assert(entry.Data["index"], gc.Equals, 99-i)
+	}
+
+	history, err = quiet.StatusHistory(status.StatusHistoryFilter{Size: 200})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 6)
+}
+
+func (s *StatusHistorySuite) TestPruneStatusHistoryByEntityInvalid(c *gc.C) {
+	err := state.PruneStatusHistoryByEntity(s.State, 0)
+	c.Assert(err, gc.ErrorMatches, "non-positive max entries per entity not valid")
+}
+
 func (s *StatusHistorySuite) TestStatusHistoryFilterRunningUpdateStatusHook(c *gc.C) {
 
 	application := s.Factory.MakeApplication(c, nil)
@@ -293,6 +328,20 @@ func (s *StatusHistorySuite) TestStatusHistoryFiltersByDateAndDelta(c *gc.C) {
 	c.Assert(history[0].Message, gc.Equals, "current status")
 	c.Assert(history[1].Message, gc.Equals, "waiting for machine")
 	c.Assert(history[2].Message, gc.Equals, "2 days ago")
+
+	// The most recent 2 logs up to three days ago, using delta.
+	history, err = unit.StatusHistory(status.StatusHistoryFilter{Size: 2, Delta: &threeDaysBack})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[0].Message, gc.Equals, "current status")
+	c.Assert(history[1].Message, gc.Equals, "waiting for machine")
+
+	// At most 5 logs up to one day back, using date.
+	history, err = unit.StatusHistory(status.StatusHistoryFilter{Size: 5, FromDate: &yesterday})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[0].Message, gc.Equals, "current status")
+	c.Assert(history[1].Message, gc.Equals, "waiting for machine")
 }
 
 func (s *StatusHistorySuite) TestSameValueNotRepeated(c *gc.C) {
@@ -312,7 +361,541 @@ func (s *StatusHistorySuite) TestSameValueNotRepeated(c *gc.C) {
 
 	history, err := unit.StatusHistory(status.StatusHistoryFilter{Size: 50})
 	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 3)
+	c.Assert(history[0].Message, gc.Equals, "last 1 statuses repeated 9 times")
+	c.Assert(history[0].Since.Equal(now.Add(9*time.Second)), jc.IsTrue)
+	c.Assert(history[1].Message, gc.Equals, "current status")
+	c.Assert(history[2].Message, gc.Equals, "waiting for machine")
+
+	// Only the two entries are stored.
+	raw, closer := state.GetRawCollection(s.State, "statuseshistory")
+	defer closer()
+	count, err := raw.Find(bson.D{{"globalkey", "u#" + unit.Name() + "#charm"}}).Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 2)
+}
+
+func (s *StatusHistorySuite) setStatuses(c *gc.C, unit *state.Unit, messages ...string) {
+	now := time.Now()
+	for i, message := range messages {
+		when := now.Add(time.Duration(i) * time.Second)
+		err := unit.SetStatus(status.StatusInfo{
+			Status:  status.Active,
+			Message: message,
+			Since:   &when,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *StatusHistorySuite) historyMessages(c *gc.C, unit *state.Unit) []string {
+	history, err := unit.StatusHistory(status.StatusHistoryFilter{Size: 50})
+	c.Assert(err, jc.ErrorIsNil)
+	var messages []string
+	for _, h := range history {
+		messages = append(messages, h.Message)
+	}
+	return messages
+}
+
+func (s *StatusHistorySuite) TestFlappingValuesFolded(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+
+	s.setStatuses(c, unit, "up", "down", "up", "down", "up", "down", "up", "down")
+	c.Assert(s.historyMessages(c, unit), jc.DeepEquals, []string{
+		"last 2 statuses repeated 3 times",
+		"down",
+		"up",
+		"waiting for machine",
+	})
+}
+
+func (s *StatusHistorySuite) TestBrokenRepetitionWritten(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+
+	s.setStatuses(c, unit, "up", "down", "up", "down", "up", "gone")
+	c.Assert(s.historyMessages(c, unit), jc.DeepEquals, []string{
+		"gone",
+		"up",
+		"last 2 statuses repeated 1 times",
+		"down",
+		"up",
+		"waiting for machine",
+	})
+}
+
+func (s *StatusHistorySuite) TestRepeatedSizeLimit(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+
+	s.setStatuses(c, unit, "up", "up")
+	history, err := unit.StatusHistory(status.StatusHistoryFilter{Size: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].Message, gc.Equals, "last 1 statuses repeated 1 times")
+}
+
+func (s *StatusHistorySuite) TestSupersededEntryNotFoldedInto(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+	s.setStatuses(c, unit, "up")
+
+	// Writing "up" marked the entry before it superseded.
+	history, closer := state.GetRawCollection(s.State, "statuseshistory")
+	defer closer()
+	globalKey := "u#" + unit.Name() + "#charm"
+	var doc bson.M
+	err := history.Find(bson.D{
+		{"globalkey", globalKey},
+		{"statusinfo", "waiting for machine"},
+	}).One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(doc["superseded"], jc.IsTrue)
+
+	// A writer that finds the latest entry superseded has lost a
+	// race with another writer; the repetition is written out rather
+	// than folded into an entry that is no longer the latest.
+	err = history.Update(bson.D{
+		{"globalkey", globalKey},
+		{"statusinfo", "up"},
+	}, bson.D{{"$set", bson.D{{"superseded", true}}}})
+	c.Assert(err, jc.ErrorIsNil)
+	s.setStatuses(c, unit, "up")
+	c.Assert(s.historyMessages(c, unit), jc.DeepEquals, []string{
+		"up",
+		"up",
+		"waiting for machine",
+	})
+}
+
+func (s *StatusHistorySuite) TestStatusHistoryFilterByStatus(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+	now := time.Now()
+	for i, st := range []status.Status{status.Active, status.Maintenance, status.Blocked, status.Active} {
+		when := now.Add(time.Duration(i) * time.Second)
+		err := unit.SetStatus(status.StatusInfo{
+			Status:  st,
+			Message: fmt.Sprintf("status %d", i),
+			Since:   &when,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	history, err := unit.StatusHistory(status.StatusHistoryFilter{
+		Size:          10,
+		IncludeStatus: set.NewStrings("maintenance", "blocked"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(history, gc.HasLen, 2)
-	c.Assert(history[0].Message, gc.Equals, "current status")
-	c.Assert(history[1].Message, gc.Equals, "waiting for machine")
+	c.Assert(history[0].Message, gc.Equals, "status 2")
+	c.Assert(history[1].Message, gc.Equals, "status 1")
+
+	history, err = unit.StatusHistory(status.StatusHistoryFilter{
+		Size:          10,
+		ExcludeStatus: set.NewStrings("active", "waiting"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[0].Status, gc.Equals, status.Blocked)
+	c.Assert(history[1].Status, gc.Equals, status.Maintenance)
+}
+
+func (s *StatusHistorySuite) TestStatusHistoryFilterByMessage(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+	// The long messages are compressed at rest; all messages are
+	// matched once read rather than by the database.
+	long := strings.Repeat("x", 600)
+	s.setStatuses(c, unit, "hook failed: install", "ready", "hook failed: "+long, long)
+
+	filter := status.StatusHistoryFilter{Size: 10, MessageRegex: "^hook failed"}
+	history, err := unit.StatusHistory(filter)
+	c.Assert(err, jc.ErrorIsNil)
+	var messages []string
+	for _, h := range history {
+		messages = append(messages, h.Message)
+	}
+	c.Assert(messages, jc.DeepEquals, []string{"hook failed: " + long, "hook failed: install"})
+
+	page, err := unit.StatusHistoryPage(status.KindWorkload, filter, 10, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pageMessages(page), jc.DeepEquals, messages)
+}
+
+func (s *StatusHistorySuite) TestStatusHistoryFilterByMessageSize(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+	s.setStatuses(c, unit, "hook failed: install", "hook failed: start", "ready", "idle", "waiting")
+
+	// The most recent entries do not match, so reading must carry on
+	// past them until enough entries do.
+	filter := status.StatusHistoryFilter{Size: 2, MessageRegex: "^hook failed"}
+	history, err := unit.StatusHistory(filter)
+	c.Assert(err, jc.ErrorIsNil)
+	var messages []string
+	for _, h := range history {
+		messages = append(messages, h.Message)
+	}
+	c.Assert(messages, jc.DeepEquals, []string{"hook failed: start", "hook failed: install"})
+
+	page, err := unit.StatusHistoryPage(status.KindWorkload, filter, 1, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pageMessages(page), jc.DeepEquals, []string{"hook failed: start"})
+	c.Assert(page.NextToken, gc.Not(gc.Equals), "")
+
+	page, err = unit.StatusHistoryPage(status.KindWorkload, filter, 1, page.NextToken)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pageMessages(page), jc.DeepEquals, []string{"hook failed: install"})
+}
+
+func (s *StatusHistorySuite) TestStatusHistoryExcludeLongMessage(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+	// Only the start of the long message is kept uncompressed, so it
+	// must be excluded once read rather than by the database.
+	long := strings.Repeat("x", 600)
+	s.setStatuses(c, unit, "ready", long, "idle")
+
+	filter := status.StatusHistoryFilter{Size: 2, Exclude: set.NewStrings(long)}
+	history, err := unit.StatusHistory(filter)
+	c.Assert(err, jc.ErrorIsNil)
+	var messages []string
+	for _, h := range history {
+		messages = append(messages, h.Message)
+	}
+	c.Assert(messages, jc.DeepEquals, []string{"idle", "ready"})
+
+	page, err := unit.StatusHistoryPage(status.KindWorkload, filter, 10, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pageMessages(page), jc.DeepEquals, messages)
+}
+
+func pageMessages(page status.StatusHistoryPage) []string {
+	var messages []string
+	for _, h := range page.Statuses {
+		messages = append(messages, h.Info)
+	}
+	return messages
+}
+
+func (s *StatusHistorySuite) TestStatusHistoryPage(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+	s.setStatuses(c, unit, "one", "two", "three", "four")
+
+	filter := status.StatusHistoryFilter{Size: 10}
+	page, err := unit.StatusHistoryPage(status.KindWorkload, filter, 2, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pageMessages(page), jc.DeepEquals, []string{"four", "three"})
+	c.Assert(page.Statuses[0].Kind, gc.Equals, status.KindWorkload)
+	c.Assert(page.NextToken, gc.Not(gc.Equals), "")
+
+	page, err = unit.StatusHistoryPage(status.KindWorkload, filter, 2, page.NextToken)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pageMessages(page), jc.DeepEquals, []string{"two", "one"})
+	c.Assert(page.NextToken, gc.Not(gc.Equals), "")
+
+	page, err = unit.StatusHistoryPage(status.KindWorkload, filter, 2, page.NextToken)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pageMessages(page), jc.DeepEquals, []string{"waiting for machine"})
+	c.Assert(page.NextToken, gc.Equals, "")
+}
+
+func (s *StatusHistorySuite) TestStatusHistoryPageSizeLimit(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+	s.setStatuses(c, unit, "one", "two", "three", "four")
+
+	filter := status.StatusHistoryFilter{Size: 3}
+	page, err := unit.StatusHistoryPage(status.KindWorkload, filter, 2, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pageMessages(page), jc.DeepEquals, []string{"four", "three"})
+
+	page, err = unit.StatusHistoryPage(status.KindWorkload, filter, 2, page.NextToken)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pageMessages(page), jc.DeepEquals, []string{"two"})
+	c.Assert(page.NextToken, gc.Equals, "")
+}
+
+func (s *StatusHistorySuite) TestStatusHistoryPageCombined(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+	s.setStatuses(c, unit, "one")
+	now := time.Now().Add(time.Minute)
+	err := unit.SetAgentStatus(status.StatusInfo{
+		Status: status.Idle,
+		Since:  &now,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	page, err := unit.StatusHistoryPage(status.KindUnit, status.StatusHistoryFilter{Size: 2}, 10, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(page.Statuses, gc.HasLen, 2)
+	c.Assert(page.Statuses[0].Status, gc.Equals, status.Idle)
+	c.Assert(page.Statuses[0].Kind, gc.Equals, status.KindUnitAgent)
+	c.Assert(page.Statuses[1].Info, gc.Equals, "one")
+	c.Assert(page.Statuses[1].Kind, gc.Equals, status.KindWorkload)
+}
+
+func (s *StatusHistorySuite) TestStatusHistoryPageInvalid(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+	filter := status.StatusHistoryFilter{Size: 10}
+
+	_, err := unit.StatusHistoryPage(status.KindWorkload, filter, 10, "bad")
+	c.Assert(err, gc.ErrorMatches, `status history token "bad" not valid`)
+	_, err = unit.StatusHistoryPage(status.KindWorkload, filter, 0, "")
+	c.Assert(err, gc.ErrorMatches, `page size 0 not valid`)
+	_, err = unit.StatusHistoryPage(status.KindMachine, filter, 10, "")
+	c.Assert(err, gc.ErrorMatches, `"juju-machine" status history for a unit not valid`)
+}
+
+func (s *StatusHistorySuite) TestApplicationStatusHistory(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	now := time.Now()
+	for i, message := range []string{"one", "two"} {
+		when := now.Add(time.Duration(i) * time.Second)
+		err := application.SetStatus(status.StatusInfo{
+			Status:  status.Active,
+			Message: message,
+			Since:   &when,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	filter := status.StatusHistoryFilter{Size: 10}
+	page, err := application.StatusHistoryPage(status.KindApplication, filter, 1, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pageMessages(page), jc.DeepEquals, []string{"two"})
+	c.Assert(page.Statuses[0].Kind, gc.Equals, status.KindApplication)
+	c.Assert(page.NextToken, gc.Not(gc.Equals), "")
+
+	_, err = application.StatusHistoryPage(status.KindWorkload, filter, 10, "")
+	c.Assert(err, gc.ErrorMatches, `"workload" status history for an application not valid`)
+}
+
+func (s *StatusHistorySuite) TestRelationStatusHistory(c *gc.C) {
+	relation := s.Factory.MakeRelation(c, nil)
+	now := time.Now()
+	err := relation.SetStatus(status.StatusInfo{Status: status.Joined, Since: &now})
+	c.Assert(err, jc.ErrorIsNil)
+	later := now.Add(time.Second)
+	err = relation.SetStatus(status.StatusInfo{
+		Status:  status.Error,
+		Message: "cannot reach remote",
+		Since:   &later,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	filter := status.StatusHistoryFilter{Size: 10}
+	history, err := relation.StatusHistory(filter)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[0].Status, gc.Equals, status.Error)
+	c.Assert(history[0].Message, gc.Equals, "cannot reach remote")
+	c.Assert(history[1].Status, gc.Equals, status.Joined)
+
+	page, err := relation.StatusHistoryPage(status.KindRelation, filter, 10, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(page.Statuses, gc.HasLen, 2)
+	c.Assert(page.Statuses[0].Kind, gc.Equals, status.KindRelation)
+	c.Assert(page.NextToken, gc.Equals, "")
+
+	_, err = relation.StatusHistoryPage(status.KindApplication, filter, 10, "")
+	c.Assert(err, gc.ErrorMatches, `"application" status history for a relation not valid`)
+}
+
+func (s *StatusHistorySuite) TestStatusHistoryCompressedAtRest(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+
+	message := strings.Repeat("a long message ", 50)
+	now := time.Now()
+	err := unit.SetStatus(status.StatusInfo{
+		Status:  status.Blocked,
+		Message: message,
+		Data:    map[string]interface{}{"$foo": "bar"},
+		Since:   &now,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, closer := state.GetRawCollection(s.State, "statuseshistory")
+	defer closer()
+	var doc bson.M
+	err = history.Find(bson.D{{"status", "blocked"}}).One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(doc["statusinfo"], gc.Equals, message[:256])
+	c.Assert(doc["payload"], gc.NotNil)
+	c.Assert(doc["statusdata"], gc.IsNil)
+
+	statuses, err := unit.StatusHistory(status.StatusHistoryFilter{Size: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statuses, gc.HasLen, 1)
+	c.Assert(statuses[0].Message, gc.Equals, message)
+	c.Assert(statuses[0].Data, jc.DeepEquals, map[string]interface{}{"$foo": "bar"})
+}
+
+func (s *StatusHistorySuite) TestStatusHistorySmallEntriesNotCompressed(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+
+	now := time.Now()
+	err := unit.SetStatus(status.StatusInfo{
+		Status:  status.Blocked,
+		Message: "waiting",
+		Data:    map[string]interface{}{"foo": "bar"},
+		Since:   &now,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, closer := state.GetRawCollection(s.State, "statuseshistory")
+	defer closer()
+	var doc bson.M
+	err = history.Find(bson.D{{"status", "blocked"}}).One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(doc["statusinfo"], gc.Equals, "waiting")
+	c.Assert(doc["payload"], gc.IsNil)
+	c.Assert(doc["statusdata"], jc.DeepEquals, bson.M{"foo": "bar"})
+}
+
+func (s *StatusHistorySuite) TestCompressStatusHistory(c *gc.C) {
+	clock := testing.NewClock(coretesting.NonZeroTime())
+	err := s.State.SetClockForTesting(clock)
+	c.Assert(err, jc.ErrorIsNil)
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+	// Primed history is written uncompressed, as it was by older
+	// versions of juju; only the larger entries are worth compressing.
+	state.PrimeUnitStatusHistory(c, clock, unit, status.Active, 10, 10, func(i int) map[string]interface{} {
+		if i%2 == 0 {
+			return map[string]interface{}{"$foo": i}
+		}
+		return map[string]interface{}{"$foo": strings.Repeat("x", 1000)}
+	})
+	before, err := unit.StatusHistory(status.StatusHistoryFilter{Size: 20})
+	c.Assert(err, jc.ErrorIsNil)
+
+	count, err := state.CountUncompressedStatusHistory(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, jc.GreaterThan, 9)
+
+	err = state.CompressStatusHistory(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, closer := state.GetRawCollection(s.State, "statuseshistory")
+	defer closer()
+	compressed, err := history.Find(bson.D{{"payload", bson.D{{"$exists", true}}}}).Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(compressed, gc.Equals, 5)
+
+	after, err := unit.StatusHistory(status.StatusHistoryFilter{Size: 20})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(after, jc.DeepEquals, before)
+}
+
+// nextHistoryEntries reads events from the status history watcher until
+// it has reported n entries.
+func (s *StatusHistorySuite) nextHistoryEntries(c *gc.C, w state.StatusHistoryWatcher, n int) []status.DetailedStatus {
+	var entries []status.DetailedStatus
+	timeout := time.After(coretesting.LongWait)
+	for len(entries) < n {
+		s.State.StartSync()
+		select {
+		case added, ok := <-w.Changes():
+			c.Assert(ok, jc.IsTrue)
+			entries = append(entries, added...)
+		case <-timeout:
+			c.Fatalf("watcher reported %d of %d entries", len(entries), n)
+		}
+	}
+	return entries
+}
+
+// assertInitialHistoryChange checks that the status history watcher
+// reports no entries in its initial event.
+func (s *StatusHistorySuite) assertInitialHistoryChange(c *gc.C, w state.StatusHistoryWatcher) {
+	s.State.StartSync()
+	select {
+	case added, ok := <-w.Changes():
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(added, gc.HasLen, 0)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("watcher did not send initial event")
+	}
+}
+
+func (s *StatusHistorySuite) assertNoHistoryChange(c *gc.C, w state.StatusHistoryWatcher) {
+	s.State.StartSync()
+	select {
+	case added, ok := <-w.Changes():
+		c.Fatalf("unexpected change: %v, %v", added, ok)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *StatusHistorySuite) TestWatchStatusHistory(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+	s.setStatuses(c, unit, "before")
+
+	w, err := unit.WatchStatusHistory(status.KindWorkload)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, w)
+
+	s.assertInitialHistoryChange(c, w)
+	s.assertNoHistoryChange(c, w)
+
+	s.setStatuses(c, unit, "one", "two")
+	entries := s.nextHistoryEntries(c, w, 2)
+	c.Assert(entries, gc.HasLen, 2)
+	c.Assert(entries[0].Info, gc.Equals, "one")
+	c.Assert(entries[0].Kind, gc.Equals, status.KindWorkload)
+	c.Assert(entries[1].Info, gc.Equals, "two")
+	s.assertNoHistoryChange(c, w)
+
+	// The agent's history is not watched.
+	now := time.Now().Add(time.Minute)
+	err = unit.SetAgentStatus(status.StatusInfo{
+		Status: status.Idle,
+		Since:  &now,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNoHistoryChange(c, w)
+}
+
+func (s *StatusHistorySuite) TestWatchStatusHistoryCombined(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+
+	w, err := unit.WatchStatusHistory(status.KindUnit)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, w)
+	s.assertInitialHistoryChange(c, w)
+
+	s.setStatuses(c, unit, "one")
+	now := time.Now().Add(time.Minute)
+	err = unit.SetAgentStatus(status.StatusInfo{
+		Status: status.Idle,
+		Since:  &now,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	entries := s.nextHistoryEntries(c, w, 2)
+	c.Assert(entries, gc.HasLen, 2)
+	c.Assert(entries[0].Info, gc.Equals, "one")
+	c.Assert(entries[0].Kind, gc.Equals, status.KindWorkload)
+	c.Assert(entries[1].Status, gc.Equals, status.Idle)
+	c.Assert(entries[1].Kind, gc.Equals, status.KindUnitAgent)
+}
+
+func (s *StatusHistorySuite) TestWatchStatusHistoryInvalidKind(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+	_, err := unit.WatchStatusHistory(status.KindMachine)
+	c.Assert(err, gc.ErrorMatches, `"juju-machine" status history for a unit not valid`)
+	_, err = application.WatchStatusHistory(status.KindWorkload)
+	c.Assert(err, gc.ErrorMatches, `"workload" status history for an application not valid`)
 }
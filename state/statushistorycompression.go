@@ -0,0 +1,145 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"unicode/utf8"
+
+	"github.com/golang/snappy"
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// maxPlainStatusInfo is the length, in bytes, above which status
+// history messages are compressed along with the data. Shorter messages
+// are stored as they are; longer ones keep this much of their start
+// uncompressed, so that they can still be searched for in the
+// database. Status history filters match messages once they are
+// decompressed.
+const maxPlainStatusInfo = 256
+
+// minCompressedPayload is the marshalled size, in bytes, below which a
+// status history payload is not worth compressing.
+const minCompressedPayload = 512
+
+// statusHistoryPayload holds the parts of a status history entry that
+// are compressed at rest.
+type statusHistoryPayload struct {
+	Info string                 `bson:"info,omitempty"`
+	Data map[string]interface{} `bson:"data,omitempty"`
+}
+
+// compress moves the data, and the message if it is long, of the
+// history document into its snappy-compressed payload, unless they
+// are too small to be worth compressing. Compressing an already
+// compressed document has no effect.
+func (doc *historicalStatusDoc) compress() error {
+	if doc.Payload != nil {
+		return nil
+	}
+	var payload statusHistoryPayload
+	payload.Data = doc.StatusData
+	if len(doc.StatusInfo) > maxPlainStatusInfo {
+		payload.Info = doc.StatusInfo
+	}
+	raw, err := bson.Marshal(payload)
+	if err != nil {
+		return errors.Annotate(err, "cannot marshal status history payload")
+	}
+	if len(raw) < minCompressedPayload {
+		return nil
+	}
+	doc.Payload = snappy.Encode(nil, raw)
+	doc.StatusData = nil
+	if payload.Info != "" {
+		doc.StatusInfo = statusInfoPrefix(payload.Info)
+	}
+	return nil
+}
+
+// statusInfoPrefix returns the start of the supplied message, cut at
+// a character boundary no more than maxPlainStatusInfo bytes in.
+func statusInfoPrefix(info string) string {
+	n := maxPlainStatusInfo
+	for n > 0 && !utf8.RuneStart(info[n]) {
+		n--
+	}
+	return info[:n]
+}
+
+// decompress restores the data and message of the history document
+// from its compressed payload, if it has one.
+func (doc *historicalStatusDoc) decompress() error {
+	if doc.Payload == nil {
+		return nil
+	}
+	raw, err := snappy.Decode(nil, doc.Payload)
+	if err != nil {
+		return errors.Annotate(err, "cannot decompress status history payload")
+	}
+	var payload statusHistoryPayload
+	if err := bson.Unmarshal(raw, &payload); err != nil {
+		return errors.Annotate(err, "cannot unmarshal status history payload")
+	}
+	doc.StatusData = payload.Data
+	if payload.Info != "" {
+		doc.StatusInfo = payload.Info
+	}
+	doc.Payload = nil
+	return nil
+}
+
+// CompressStatusHistory compresses the status history entries, across
+// all models, that were written before history was compressed at rest.
+// It is run as an upgrade step.
+func CompressStatusHistory(st *State) error {
+	history, closer := st.db().GetRawCollection(statusesHistoryC)
+	defer closer()
+
+	type historyDoc struct {
+		Id                  bson.ObjectId `bson:"_id"`
+		historicalStatusDoc `bson:",inline"`
+	}
+	iter := history.Find(bson.D{{"payload", bson.D{{"$exists", false}}}}).Iter()
+	defer iter.Close()
+	count := 0
+	for {
+		var doc historyDoc
+		if !iter.Next(&doc) {
+			break
+		}
+		entry := doc.historicalStatusDoc
+		if err := entry.compress(); err != nil {
+			return errors.Trace(err)
+		}
+		if entry.Payload == nil {
+			continue
+		}
+		update := bson.D{
+			{"$set", bson.D{
+				{"statusinfo", entry.StatusInfo},
+				{"payload", entry.Payload},
+			}},
+			{"$unset", bson.D{{"statusdata", nil}}},
+		}
+		if err := history.UpdateId(doc.Id, update); err != nil {
+			return errors.Annotate(err, "cannot compress status history")
+		}
+		count++
+	}
+	if err := iter.Close(); err != nil {
+		return errors.Annotate(err, "cannot read status history")
+	}
+	logger.Infof("compressed %d status history entries", count)
+	return nil
+}
+
+// CountUncompressedStatusHistory returns the number of status history
+// entries, across all models, that CompressStatusHistory will examine.
+func CountUncompressedStatusHistory(st *State) (int, error) {
+	history, closer := st.db().GetRawCollection(statusesHistoryC)
+	defer closer()
+	count, err := history.Find(bson.D{{"payload", bson.D{{"$exists", false}}}}).Count()
+	return count, errors.Trace(err)
+}
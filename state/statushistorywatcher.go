@@ -0,0 +1,175 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/tomb.v1"
+
+	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/status"
+)
+
+// StatusHistoryWatcher reports the entries added to one or more status
+// histories, oldest first, as they are recorded.
+type StatusHistoryWatcher interface {
+	Watcher
+	Changes() <-chan []status.DetailedStatus
+}
+
+// statusHistoryWatcher reports the entries added to the status
+// histories with the given global keys. Status history is written
+// without transactions, but always just before the entity's status
+// document, so the watcher watches the status documents and reads the
+// entries added since it last looked whenever they change.
+//
+// The first event is empty, and marks the point from which entries are
+// reported. Entries that have not been read are accumulated into the
+// next event. Repetitions folded into the latest entry of a history
+// are not reported, as no entry is added for them.
+type statusHistoryWatcher struct {
+	commonWatcher
+	kinds map[string]status.HistoryKind
+	out   chan []status.DetailedStatus
+
+	// updated and id identify the most recent entry reported, or
+	// present when the watcher started.
+	updated int64
+	id      bson.ObjectId
+}
+
+// newStatusHistoryWatcher returns a watcher reporting the entries added
+// to the status histories with the given global keys, which it reports
+// with the corresponding kinds.
+func newStatusHistoryWatcher(backend modelBackend, kinds map[string]status.HistoryKind) StatusHistoryWatcher {
+	w := &statusHistoryWatcher{
+		commonWatcher: newCommonWatcher(backend),
+		kinds:         kinds,
+		out:           make(chan []status.DetailedStatus),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for the watcher.
+func (w *statusHistoryWatcher) Changes() <-chan []status.DetailedStatus {
+	return w.out
+}
+
+func (w *statusHistoryWatcher) globalKeys() []string {
+	keys := make([]string, 0, len(w.kinds))
+	for key := range w.kinds {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// initial records the most recent entry of the histories, after which
+// entries are reported.
+func (w *statusHistoryWatcher) initial() error {
+	history, closer := w.db.GetCollection(statusesHistoryC)
+	defer closer()
+	var doc struct {
+		Id      bson.ObjectId `bson:"_id"`
+		Updated int64         `bson:"updated"`
+	}
+	query := history.Find(bson.D{{"globalkey", bson.D{{"$in", w.globalKeys()}}}})
+	err := query.Select(bson.D{{"updated", 1}}).Sort("-updated", "-_id").One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return errors.Annotate(err, "cannot get status history")
+	}
+	w.updated, w.id = doc.Updated, doc.Id
+	return nil
+}
+
+// added returns the entries added to the histories since the most
+// recent one reported, oldest first.
+func (w *statusHistoryWatcher) added() ([]status.DetailedStatus, error) {
+	history, closer := w.db.GetCollection(statusesHistoryC)
+	defer closer()
+	query := bson.M{"globalkey": bson.M{"$in": w.globalKeys()}}
+	if w.id != "" {
+		query["$or"] = []bson.M{
+			{"updated": bson.M{"$gt": w.updated}},
+			{"updated": w.updated, "_id": bson.M{"$gt": w.id}},
+		}
+	}
+	var docs []struct {
+		Id                  bson.ObjectId `bson:"_id"`
+		historicalStatusDoc `bson:",inline"`
+	}
+	if err := history.Find(query).Sort("updated", "_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get status history")
+	}
+	entries := make([]status.DetailedStatus, len(docs))
+	for i := range docs {
+		doc := &docs[i]
+		if err := doc.decompress(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		entries[i] = status.DetailedStatus{
+			Status: doc.Status,
+			Info:   doc.StatusInfo,
+			Data:   utils.UnescapeKeys(doc.StatusData),
+			Since:  unixNanoToTime(doc.Updated),
+			Kind:   w.kinds[doc.GlobalKey],
+		}
+		w.updated, w.id = doc.Updated, doc.Id
+	}
+	return entries, nil
+}
+
+func (w *statusHistoryWatcher) loop() error {
+	in := make(chan watcher.Change)
+	statuses, closer := w.db.GetCollection(statusesC)
+	for key := range w.kinds {
+		docID := w.backend.docID(key)
+		txnRevno, err := getTxnRevno(statuses, docID)
+		if err != nil {
+			closer()
+			return errors.Trace(err)
+		}
+		w.watcher.Watch(statuses.Name(), docID, txnRevno, in)
+		defer w.watcher.Unwatch(statuses.Name(), docID, in)
+	}
+	closer()
+	if err := w.initial(); err != nil {
+		return errors.Trace(err)
+	}
+
+	pending := []status.DetailedStatus{}
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.watcher.Dead():
+			return stateWatcherDeadError(w.watcher.Err())
+		case ch := <-in:
+			if _, ok := collect(ch, in, w.tomb.Dying()); !ok {
+				return tomb.ErrDying
+			}
+			entries, err := w.added()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if len(entries) > 0 {
+				pending = append(pending, entries...)
+				out = w.out
+			}
+		case out <- pending:
+			pending = nil
+			out = nil
+		}
+	}
+}
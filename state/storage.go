@@ -52,6 +52,12 @@ type StorageInstance interface {
 	// Pool returns the name of the storage pool from which the storage
 	// instance has been or will be provisioned.
 	Pool() string
+
+	// RetainedFrom returns the name of the application that the storage
+	// instance was retained from when the application was destroyed, or
+	// the empty string if it was not. The mark is cleared when the
+	// storage instance is next attached.
+	RetainedFrom() string
 }
 
 // StorageAttachment represents the state of a unit's attachment to a storage
@@ -152,6 +158,10 @@ func (s *storageInstance) Pool() string {
 	return s.doc.Constraints.Pool
 }
 
+func (s *storageInstance) RetainedFrom() string {
+	return s.doc.RetainedFrom
+}
+
 // entityStorageRefcountKey returns a key for refcounting charm storage
 // for a specific entity. Each time a storage instance is created, the
 // named store's refcount is incremented; and decremented when removed.
@@ -172,6 +182,7 @@ type storageInstanceDoc struct {
 	StorageName     string                     `bson:"storagename"`
 	AttachmentCount int                        `bson:"attachmentcount"`
 	Constraints     storageInstanceConstraints `bson:"constraints"`
+	RetainedFrom    string                     `bson:"retained-from,omitempty"`
 }
 
 // storageInstanceConstraints contains a subset of StorageConstraints,
@@ -1024,6 +1035,11 @@ func (im *IAASModel) attachStorageOps(
 			"$set", bson.D{{"owner", unitTag.String()}},
 		})
 	}
+	if si.doc.RetainedFrom != "" {
+		siUpdate = append(siUpdate, bson.DocElem{
+			"$unset", bson.D{{"retained-from", nil}},
+		})
+	}
 	ops := []txn.Op{{
 		C:      storageInstancesC,
 		Id:     si.doc.Id,
@@ -1860,6 +1876,65 @@ func defaultStoragePool(cfg *config.Config, kind storage.StorageKind, cons Stora
 	return "", ErrNoDefaultStoragePool
 }
 
+// validateStorageDefaultPoolAttrs checks that the default pool
+// attributes in the model config are valid for the storage providers of
+// each of the model's default storage pools.
+func (st *State) validateStorageDefaultPoolAttrs(cfg *config.Config) error {
+	defaults := cfg.StorageDefaultPoolAttrs()
+	if len(defaults) == 0 {
+		return nil
+	}
+	var poolNames []string
+	if name, ok := cfg.StorageDefaultBlockSource(); ok {
+		poolNames = append(poolNames, name)
+	}
+	if name, ok := cfg.StorageDefaultFilesystemSource(); ok {
+		poolNames = append(poolNames, name)
+	}
+	if len(poolNames) == 0 {
+		return errors.Errorf(
+			"%s requires %s or %s to be set",
+			config.StorageDefaultPoolAttrsKey,
+			config.StorageDefaultBlockSourceKey,
+			config.StorageDefaultFilesystemSourceKey,
+		)
+	}
+
+	registry, err := st.storageProviderRegistry()
+	if err != nil {
+		return errors.Annotate(err, "getting storage provider registry")
+	}
+	poolManager := poolmanager.New(NewStateSettings(st), registry)
+	for _, poolName := range poolNames {
+		providerType := storage.ProviderType(poolName)
+		attrs := make(map[string]interface{})
+		for k, v := range defaults {
+			attrs[k] = v
+		}
+		pool, err := poolManager.Get(poolName)
+		if err == nil {
+			providerType = pool.Provider()
+			for k, v := range pool.Attrs() {
+				attrs[k] = v
+			}
+		} else if !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+		p, err := registry.StorageProvider(providerType)
+		if err != nil {
+			return errors.Annotatef(err, "invalid %s for pool %q", config.StorageDefaultPoolAttrsKey, poolName)
+		}
+		poolConfig, err := storage.NewConfig(poolName, providerType, attrs)
+		if err == nil {
+			err = provider.ValidateConfig(p, poolConfig)
+		}
+		if err != nil {
+			return errors.Annotatef(err, "invalid %s for pool %q", config.StorageDefaultPoolAttrsKey, poolName)
+		}
+	}
+	return nil
+}
+
 // AddStorageForUnit adds storage instances to given unit as specified.
 //
 // Missing storage constraints are populated based on model defaults.
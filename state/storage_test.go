@@ -732,6 +732,38 @@ func (s *StorageStateSuite) TestAttachStorageTakesOwnership(c *gc.C) {
 	c.Assert(owner, gc.Equals, u2.Tag())
 }
 
+func (s *StorageStateSuite) TestDestroyApplicationRetainStorage(c *gc.C) {
+	app, u, storageTag := s.setupSingleStorageDetachable(c, "block", "modelscoped")
+	u2, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	op := app.DestroyOperation()
+	op.RetainStorage = true
+	err = s.State.ApplyOperation(op)
+	c.Assert(err, jc.ErrorIsNil)
+	storageInstance, err := s.IAASModel.StorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(storageInstance.RetainedFrom(), gc.Equals, app.Name())
+
+	// The mark is cleared once the storage is attached again.
+	err = s.IAASModel.DetachStorage(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.IAASModel.AttachStorage(storageTag, u2.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	storageInstance, err = s.IAASModel.StorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(storageInstance.RetainedFrom(), gc.Equals, "")
+}
+
+func (s *StorageStateSuite) TestDestroyApplicationRetainAndDestroyStorage(c *gc.C) {
+	app, _, _ := s.setupSingleStorageDetachable(c, "block", "modelscoped")
+	op := app.DestroyOperation()
+	op.RetainStorage = true
+	op.DestroyStorage = true
+	err := s.State.ApplyOperation(op)
+	c.Assert(err, gc.ErrorMatches, `cannot destroy application "storage-block": cannot both destroy and retain storage`)
+}
+
 func (s *StorageStateSuite) TestAttachStorageAssignedMachine(c *gc.C) {
 	app, u, storageTag := s.setupSingleStorageDetachable(c, "block", "modelscoped")
 	u2, err := app.AddUnit(state.AddUnitParams{})
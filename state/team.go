@@ -0,0 +1,296 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// Team is a named group of local users, stored on the controller. A
+// team may own models, in which case its members are granted admin
+// access to them.
+type Team struct {
+	st  *State
+	doc teamDoc
+}
+
+// teamDoc is the mongo document representation of a Team. Members
+// holds the lower-cased names of the team's users, in order.
+// ModelTransfers counts the transfers of models to the team; each
+// transfer increments it, so that transactions depending on the models
+// the team owns can assert that none has been transferred since.
+type teamDoc struct {
+	DocID          string    `bson:"_id"`
+	Name           string    `bson:"name"`
+	Members        []string  `bson:"members"`
+	ModelTransfers int       `bson:"model-transfers"`
+	CreatedBy      string    `bson:"created-by"`
+	DateCreated    time.Time `bson:"date-created"`
+}
+
+// Name returns the name of the team.
+func (t *Team) Name() string {
+	return t.doc.Name
+}
+
+// Members returns the users in the team, sorted by name.
+func (t *Team) Members() []names.UserTag {
+	members := make([]names.UserTag, len(t.doc.Members))
+	for i, member := range t.doc.Members {
+		members[i] = names.NewUserTag(member)
+	}
+	return members
+}
+
+// CreatedBy returns the name of the user that added the team.
+func (t *Team) CreatedBy() string {
+	return t.doc.CreatedBy
+}
+
+// DateCreated returns when the team was added.
+func (t *Team) DateCreated() time.Time {
+	return t.doc.DateCreated.UTC()
+}
+
+// Refresh reloads the team from the database.
+func (t *Team) Refresh() error {
+	return t.st.getTeam(t.doc.Name, &t.doc)
+}
+
+// teamDocID returns the document id of the named team. Team names,
+// like user names, are case insensitive.
+func teamDocID(name string) string {
+	return strings.ToLower(name)
+}
+
+// AddTeam adds a team, with no members, to the controller. Team names
+// follow the same rules as local user names, but the two do not
+// clash.
+func (st *State) AddTeam(name string, createdBy names.UserTag) (_ *Team, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add team %q", name)
+	if !names.IsValidUserName(name) {
+		return nil, errors.NotValidf("team name %q", name)
+	}
+	doc := teamDoc{
+		DocID:       teamDocID(name),
+		Name:        name,
+		Members:     []string{},
+		CreatedBy:   createdBy.Id(),
+		DateCreated: st.nowToTheSecond(),
+	}
+	ops := []txn.Op{{
+		C:      teamsC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return nil, errors.AlreadyExistsf("team %q", name)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Team{st: st, doc: doc}, nil
+}
+
+// Team returns the named team.
+func (st *State) Team(name string) (*Team, error) {
+	team := &Team{st: st}
+	if err := st.getTeam(name, &team.doc); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return team, nil
+}
+
+func (st *State) getTeam(name string, doc *teamDoc) error {
+	teams, closer := st.db().GetCollection(teamsC)
+	defer closer()
+	err := teams.FindId(teamDocID(name)).One(doc)
+	if err == mgo.ErrNotFound {
+		return errors.NotFoundf("team %q", name)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot get team %q", name)
+	}
+	doc.DateCreated = doc.DateCreated.UTC()
+	return nil
+}
+
+// AllTeams returns all the teams on the controller, sorted by name.
+func (st *State) AllTeams() ([]*Team, error) {
+	teams, closer := st.db().GetCollection(teamsC)
+	defer closer()
+	var docs []teamDoc
+	if err := teams.Find(nil).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get teams")
+	}
+	result := make([]*Team, len(docs))
+	for i, doc := range docs {
+		doc.DateCreated = doc.DateCreated.UTC()
+		result[i] = &Team{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// RemoveTeam removes the named team from the controller. A team that
+// owns models cannot be removed; the models must first be transferred
+// to another owner.
+func (st *State) RemoveTeam(name string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot remove team %q", name)
+	buildTxn := func(int) ([]txn.Op, error) {
+		// The team's models are checked again on each attempt,
+		// and the transaction asserts that no model has been
+		// transferred to the team since.
+		var doc teamDoc
+		if err := st.getTeam(name, &doc); err != nil {
+			return nil, errors.Trace(err)
+		}
+		owned, err := st.teamModelUUIDs(doc.Name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(owned) > 0 {
+			return nil, errors.Errorf("team owns %d model(s)", len(owned))
+		}
+		return []txn.Op{{
+			C:      teamsC,
+			Id:     doc.DocID,
+			Assert: bson.D{{"model-transfers", doc.ModelTransfers}},
+			Remove: true,
+		}}, nil
+	}
+	return errors.Trace(st.db().Run(buildTxn))
+}
+
+// AddMember adds a local user to the team, and grants them admin
+// access to the models the team owns, in a single transaction.
+func (t *Team) AddMember(user names.UserTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add %q to team %q", user.Id(), t.doc.Name)
+	if _, err := t.st.User(user); err != nil {
+		return errors.Trace(err)
+	}
+	member := strings.ToLower(user.Id())
+	createdBy := names.NewUserTag(t.doc.CreatedBy)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := t.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		for _, existing := range t.doc.Members {
+			if existing == member {
+				return nil, jujutxn.ErrNoOperations
+			}
+		}
+		ops := []txn.Op{{
+			C:  teamsC,
+			Id: t.doc.DocID,
+			Assert: bson.D{
+				{"members", t.doc.Members},
+				{"model-transfers", t.doc.ModelTransfers},
+			},
+			Update: bson.D{{"$set", bson.D{
+				{"members", sortedMembers(append(t.doc.Members, member))},
+			}}},
+		}}
+		owned, err := t.st.teamModelUUIDs(t.doc.Name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, modelUUID := range owned {
+			grantOps, err := grantModelAdminOps(t.st, modelUUID, user, createdBy)
+			if err != nil {
+				return nil, errors.Annotatef(err, "granting access to model %q", modelUUID)
+			}
+			grantOps, err = t.st.opsForModel(modelUUID, grantOps)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, grantOps...)
+			ops = append(ops, txn.Op{
+				C:      modelsC,
+				Id:     modelUUID,
+				Assert: bson.D{{"owner-team", t.doc.DocID}},
+			})
+		}
+		return ops, nil
+	}
+	if err := t.st.runRaw(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	return t.Refresh()
+}
+
+// RemoveMember removes a user from the team. Access to the team's
+// models granted to the user is not revoked, as the user may have
+// been granted it independently of the team; model admins may revoke
+// it as with any other user.
+func (t *Team) RemoveMember(user names.UserTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot remove %q from team %q", user.Id(), t.doc.Name)
+	member := strings.ToLower(user.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := t.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		var remaining []string
+		for _, existing := range t.doc.Members {
+			if existing != member {
+				remaining = append(remaining, existing)
+			}
+		}
+		if len(remaining) == len(t.doc.Members) {
+			return nil, errors.NotFoundf("team member %q", user.Id())
+		}
+		return []txn.Op{{
+			C:      teamsC,
+			Id:     t.doc.DocID,
+			Assert: bson.D{{"members", t.doc.Members}},
+			Update: bson.D{{"$set", bson.D{
+				{"members", sortedMembers(remaining)},
+			}}},
+		}}, nil
+	}
+	if err := t.st.db().Run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	return t.Refresh()
+}
+
+// teamModelUUIDs returns the UUIDs of the models that are not dead
+// and are owned by the named team.
+func (st *State) teamModelUUIDs(team string) ([]string, error) {
+	models, closer := st.db().GetCollection(modelsC)
+	defer closer()
+	var docs []struct {
+		UUID string `bson:"_id"`
+	}
+	query := bson.D{
+		{"owner-team", teamDocID(team)},
+		{"life", bson.D{{"$ne", Dead}}},
+	}
+	if err := models.Find(query).Select(bson.D{{"_id", 1}}).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get models owned by team %q", team)
+	}
+	uuids := make([]string, len(docs))
+	for i, doc := range docs {
+		uuids[i] = doc.UUID
+	}
+	return uuids, nil
+}
+
+func sortedMembers(members []string) []string {
+	result := make([]string, len(members))
+	copy(result, members)
+	sort.Strings(result)
+	return result
+}
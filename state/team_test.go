@@ -0,0 +1,173 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type TeamSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&TeamSuite{})
+
+func (s *TeamSuite) TestAddTeam(c *gc.C) {
+	team, err := s.State.AddTeam("ops", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(team.Name(), gc.Equals, "ops")
+	c.Assert(team.Members(), gc.HasLen, 0)
+	c.Assert(team.CreatedBy(), gc.Equals, s.Owner.Id())
+
+	_, err = s.State.AddTeam("dev", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	teams, err := s.State.AllTeams()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(teams, gc.HasLen, 2)
+	c.Assert(teams[0].Name(), gc.Equals, "dev")
+	c.Assert(teams[1].Name(), gc.Equals, "ops")
+}
+
+func (s *TeamSuite) TestAddTeamExists(c *gc.C) {
+	_, err := s.State.AddTeam("ops", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddTeam("OPS", s.Owner)
+	c.Assert(err, gc.ErrorMatches, `cannot add team "OPS": team "OPS" already exists`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *TeamSuite) TestAddTeamInvalidName(c *gc.C) {
+	_, err := s.State.AddTeam("not valid", s.Owner)
+	c.Assert(err, gc.ErrorMatches, `cannot add team "not valid": team name "not valid" not valid`)
+}
+
+func (s *TeamSuite) TestTeamNotFound(c *gc.C) {
+	_, err := s.State.Team("ops")
+	c.Assert(err, gc.ErrorMatches, `team "ops" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *TeamSuite) TestMembers(c *gc.C) {
+	team, err := s.State.AddTeam("ops", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	alice := s.Factory.MakeUser(c, &factory.UserParams{Name: "alice"})
+
+	c.Assert(team.AddMember(bob.UserTag()), jc.ErrorIsNil)
+	c.Assert(team.AddMember(alice.UserTag()), jc.ErrorIsNil)
+	c.Assert(team.AddMember(bob.UserTag()), jc.ErrorIsNil)
+	c.Assert(team.Members(), jc.DeepEquals, []names.UserTag{alice.UserTag(), bob.UserTag()})
+
+	c.Assert(team.RemoveMember(alice.UserTag()), jc.ErrorIsNil)
+	team, err = s.State.Team("ops")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(team.Members(), jc.DeepEquals, []names.UserTag{bob.UserTag()})
+
+	err = team.RemoveMember(alice.UserTag())
+	c.Assert(err, gc.ErrorMatches, `cannot remove "alice" from team "ops": team member "alice" not found`)
+}
+
+func (s *TeamSuite) TestAddMemberUnknownUser(c *gc.C) {
+	team, err := s.State.AddTeam("ops", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	err = team.AddMember(names.NewUserTag("bob"))
+	c.Assert(err, gc.ErrorMatches, `cannot add "bob" to team "ops": user "bob" not found`)
+}
+
+func (s *TeamSuite) TestAddMemberGrantsModelAccess(c *gc.C) {
+	team, err := s.State.AddTeam("ops", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.Model.TransferOwnershipToTeam("ops", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoModelUser: true})
+	err = team.AddMember(bob.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	access, err := s.State.UserPermission(bob.UserTag(), s.Model.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.AdminAccess)
+}
+
+func (s *TeamSuite) TestRemoveTeam(c *gc.C) {
+	_, err := s.State.AddTeam("ops", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveTeam("ops")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.Team("ops")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.RemoveTeam("ops")
+	c.Assert(err, gc.ErrorMatches, `cannot remove team "ops": team "ops" not found`)
+}
+
+func (s *TeamSuite) TestRemoveTeamOwningModels(c *gc.C) {
+	_, err := s.State.AddTeam("ops", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.Model.TransferOwnershipToTeam("ops", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveTeam("ops")
+	c.Assert(err, gc.ErrorMatches, `cannot remove team "ops": team owns 1 model\(s\)`)
+}
+
+func (s *TeamSuite) TestAddMemberGrantsHostedModelAccess(c *gc.C) {
+	team, err := s.State.AddTeam("ops", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	model, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	err = model.TransferOwnershipToTeam("ops", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoModelUser: true})
+	err = team.AddMember(bob.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	access, err := st.UserAccess(bob.UserTag(), model.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access.Access, gc.Equals, permission.AdminAccess)
+	_, err = s.State.UserAccess(bob.UserTag(), s.Model.ModelTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *TeamSuite) TestAddMemberWhileModelTransferred(c *gc.C) {
+	team, err := s.State.AddTeam("ops", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoModelUser: true})
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		err := s.Model.TransferOwnershipToTeam("ops", s.Owner)
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	err = team.AddMember(bob.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	access, err := s.State.UserPermission(bob.UserTag(), s.Model.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.AdminAccess)
+}
+
+func (s *TeamSuite) TestRemoveTeamWhileModelTransferred(c *gc.C) {
+	_, err := s.State.AddTeam("ops", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		err := s.Model.TransferOwnershipToTeam("ops", s.Owner)
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	err = s.State.RemoveTeam("ops")
+	c.Assert(err, gc.ErrorMatches, `cannot remove team "ops": team owns 1 model\(s\)`)
+	_, err = s.State.Team("ops")
+	c.Assert(err, jc.ErrorIsNil)
+}
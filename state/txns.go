@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/errors"
 	jujutxn "github.com/juju/txn"
+	"github.com/juju/utils/clock"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
 )
@@ -27,6 +28,30 @@ func (st *State) runRawTransaction(ops []txn.Op) error {
 	return st.database.RunRawTransaction(ops)
 }
 
+// runRaw runs the transactions built by the given function using a
+// "raw" transaction runner that won't perform model filtering, so that
+// a single transaction may affect the documents of several models. Ops
+// on per-model collections must first be passed through opsForModel.
+func (st *State) runRaw(transactions jujutxn.TransactionSource) error {
+	runner, closer := st.database.TransactionRunner()
+	defer closer()
+	if multiRunner, ok := runner.(*multiModelRunner); ok {
+		runner = multiRunner.rawRunner
+	}
+	return runner.Run(transactions)
+}
+
+// opsForModel returns the ops modified, as the model's transaction
+// runner would modify them, to affect the documents of the model with
+// the given UUID.
+func (st *State) opsForModel(modelUUID string, ops []txn.Op) ([]txn.Op, error) {
+	runner := &multiModelRunner{
+		schema:    st.database.Schema(),
+		modelUUID: modelUUID,
+	}
+	return runner.updateOps(ops)
+}
+
 // ResumeTransactions resumes all pending transactions.
 func (st *State) ResumeTransactions() error {
 	runner, closer := st.database.TransactionRunner()
@@ -51,6 +76,17 @@ type multiModelRunner struct {
 	rawRunner jujutxn.Runner
 	schema    collectionSchema
 	modelUUID string
+
+	// attemptStarted, if non-nil, is called when the operations
+	// of an attempt to run a transaction are ready to be run.
+	attemptStarted func()
+
+	// delay, if positive, holds up each attempt to run a
+	// transaction; see FaultSlowTransactions.
+	delay time.Duration
+
+	// clock is used to wait out the delay.
+	clock clock.Clock
 }
 
 // RunTransaction is part of the jujutxn.Runner interface. Operations
@@ -61,6 +97,7 @@ func (r *multiModelRunner) RunTransaction(ops []txn.Op) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	r.startAttempt()
 	return r.rawRunner.RunTransaction(newOps)
 }
 
@@ -80,10 +117,20 @@ func (r *multiModelRunner) Run(transactions jujutxn.TransactionSource) error {
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
+		r.startAttempt()
 		return newOps, nil
 	})
 }
 
+func (r *multiModelRunner) startAttempt() {
+	if r.attemptStarted != nil {
+		r.attemptStarted()
+	}
+	if r.delay > 0 {
+		<-r.clock.After(r.delay)
+	}
+}
+
 // ResumeTransactions is part of the jujutxn.Runner interface.
 func (r *multiModelRunner) ResumeTransactions() error {
 	return r.rawRunner.ResumeTransactions()
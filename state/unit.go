@@ -484,6 +484,21 @@ func (u *Unit) destroyHostOps(a *Application) (ops []txn.Op, err error) {
 		return nil, err
 	}
 
+	// Machines in the pool outlive the units placed on them; they are
+	// returned to the pool rather than destroyed.
+	poolOps, pooled, err := u.releasePoolMachineOps(m.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if pooled {
+		return append(poolOps, txn.Op{
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: txn.DocExists,
+			Update: machineUpdate,
+		}), nil
+	}
+
 	containerCheck := true // whether container conditions allow destroying the host machine
 	containers, err := m.Containers()
 	if err != nil {
@@ -882,6 +897,54 @@ func (u *Unit) StatusHistory(filter status.StatusHistoryFilter) ([]status.Status
 	return statusHistory(args)
 }
 
+// statusHistoryKinds returns the global keys of the unit's status
+// histories of the given kind, and the kind of the entries in each.
+// The workload and agent histories are selected by KindWorkload and
+// KindUnitAgent respectively, and together by KindUnit.
+func (u *Unit) statusHistoryKinds(kind status.HistoryKind) (map[string]status.HistoryKind, error) {
+	kinds := make(map[string]status.HistoryKind)
+	switch kind {
+	case status.KindUnit:
+		kinds[u.globalKey()] = status.KindWorkload
+		kinds[u.globalAgentKey()] = status.KindUnitAgent
+	case status.KindWorkload:
+		kinds[u.globalKey()] = status.KindWorkload
+	case status.KindUnitAgent:
+		kinds[u.globalAgentKey()] = status.KindUnitAgent
+	default:
+		return nil, errors.NotValidf("%q status history for a unit", kind)
+	}
+	return kinds, nil
+}
+
+// StatusHistoryPage implements status.StatusHistoryPager. The unit's
+// workload and agent histories are read by KindWorkload and
+// KindUnitAgent respectively, and together by KindUnit.
+func (u *Unit) StatusHistoryPage(kind status.HistoryKind, filter status.StatusHistoryFilter, pageSize int, token string) (status.StatusHistoryPage, error) {
+	kinds, err := u.statusHistoryKinds(kind)
+	if err != nil {
+		return status.StatusHistoryPage{}, errors.Trace(err)
+	}
+	return statusHistoryPage(&statusHistoryPageArgs{
+		db:       u.st.db(),
+		kinds:    kinds,
+		filter:   filter,
+		pageSize: pageSize,
+		token:    token,
+	})
+}
+
+// WatchStatusHistory returns a watcher reporting the entries added to
+// the unit's status history of the given kind, as for
+// StatusHistoryPage.
+func (u *Unit) WatchStatusHistory(kind status.HistoryKind) (StatusHistoryWatcher, error) {
+	kinds, err := u.statusHistoryKinds(kind)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newStatusHistoryWatcher(u.st, kinds), nil
+}
+
 // Status returns the status of the unit.
 // This method relies on globalKey instead of globalAgentKey since it is part of
 // the effort to separate Unit from UnitAgent. Now the Status for UnitAgent is in
@@ -929,13 +992,29 @@ func (u *Unit) SetStatus(unitStatus status.StatusInfo) error {
 // existing, alive subnet, otherwise an error is returned. Returns an error if
 // opening the requested range conflicts with another already opened range on
 // the same subnet and and the unit's assigned machine.
-func (u *Unit) OpenPortsOnSubnet(subnetID, protocol string, fromPort, toPort int) (err error) {
+func (u *Unit) OpenPortsOnSubnet(subnetID, protocol string, fromPort, toPort int) error {
+	return u.openPorts(subnetID, "", protocol, fromPort, toPort)
+}
+
+// OpenPortsForEndpoint opens the given port range and protocol for the
+// unit, for the named endpoint of the unit's charm. The port range is
+// then exposed according to the expose settings of that endpoint.
+func (u *Unit) OpenPortsForEndpoint(endpoint, protocol string, fromPort, toPort int) error {
+	return u.openPorts("", endpoint, protocol, fromPort, toPort)
+}
+
+func (u *Unit) openPorts(subnetID, endpoint, protocol string, fromPort, toPort int) (err error) {
 	ports, err := NewPortRange(u.Name(), fromPort, toPort, protocol)
 	if err != nil {
 		return errors.Annotatef(err, "invalid port range %v-%v/%v", fromPort, toPort, protocol)
 	}
 	defer errors.DeferredAnnotatef(&err, "cannot open ports %v for unit %q on subnet %q", ports, u, subnetID)
 
+	if err := u.checkEndpointWhenSet(endpoint); err != nil {
+		return errors.Trace(err)
+	}
+	ports.Endpoint = endpoint
+
 	machineID, err := u.AssignedMachineId()
 	if err != nil {
 		return errors.Annotatef(err, "unit %q has no assigned machine", u)
@@ -953,6 +1032,18 @@ func (u *Unit) OpenPortsOnSubnet(subnetID, protocol string, fromPort, toPort int
 	return machinePorts.OpenPorts(ports)
 }
 
+func (u *Unit) checkEndpointWhenSet(endpoint string) error {
+	if endpoint == "" {
+		return nil
+	}
+	app, err := u.Application()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = app.Endpoint(endpoint)
+	return errors.Trace(err)
+}
+
 func (u *Unit) checkSubnetAliveWhenSet(subnetID string) error {
 	if subnetID == "" {
 		return nil
@@ -972,12 +1063,23 @@ func (u *Unit) checkSubnetAliveWhenSet(subnetID string) error {
 // ClosePortsOnSubnet closes the given port range and protocol for the unit on
 // the given subnet, which can be empty. When non-empty, subnetID must refer to
 // an existing, alive subnet, otherwise an error is returned.
-func (u *Unit) ClosePortsOnSubnet(subnetID, protocol string, fromPort, toPort int) (err error) {
+func (u *Unit) ClosePortsOnSubnet(subnetID, protocol string, fromPort, toPort int) error {
+	return u.closePorts(subnetID, "", protocol, fromPort, toPort)
+}
+
+// ClosePortsForEndpoint closes the given port range and protocol,
+// opened for the named endpoint of the unit's charm, for the unit.
+func (u *Unit) ClosePortsForEndpoint(endpoint, protocol string, fromPort, toPort int) error {
+	return u.closePorts("", endpoint, protocol, fromPort, toPort)
+}
+
+func (u *Unit) closePorts(subnetID, endpoint, protocol string, fromPort, toPort int) (err error) {
 	ports, err := NewPortRange(u.Name(), fromPort, toPort, protocol)
 	if err != nil {
 		return errors.Annotatef(err, "invalid port range %v-%v/%v", fromPort, toPort, protocol)
 	}
 	defer errors.DeferredAnnotatef(&err, "cannot close ports %v for unit %q on subnet %q", ports, u, subnetID)
+	ports.Endpoint = endpoint
 
 	machineID, err := u.AssignedMachineId()
 	if err != nil {
@@ -1322,7 +1424,7 @@ func (u *Unit) assignToMachine(m *Machine, unused bool) (err error) {
 				return nil, errors.Trace(err)
 			}
 		}
-		return u.assignToMachineOps(m, unused)
+		return u.assignToMachineOps(m, unused, true)
 	}
 	if err := u.st.db().Run(buildTxn); err != nil {
 		return errors.Trace(err)
@@ -1338,7 +1440,9 @@ func (u *Unit) assignToMachine(m *Machine, unused bool) (err error) {
 // - unitNotAliveErr when the unit is not alive.
 // - alreadyAssignedErr when the unit has already been assigned
 // - inUseErr when the machine already has a unit assigned (if unused is true)
-func (u *Unit) assignToMachineOps(m *Machine, unused bool) ([]txn.Op, error) {
+// If placed is true, the machine was chosen explicitly, and the unit's
+// architecture constraint is not enforced against it.
+func (u *Unit) assignToMachineOps(m *Machine, unused, placed bool) ([]txn.Op, error) {
 	if u.Life() != Alive {
 		return nil, unitNotAliveErr
 	}
@@ -1363,8 +1467,17 @@ func (u *Unit) assignToMachineOps(m *Machine, unused bool) ([]txn.Op, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	// Subordinate units have no constraints of their own.
+	var cons constraints.Value
+	if u.doc.Principal == "" && !placed {
+		unitCons, err := u.Constraints()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		cons = *unitCons
+	}
 	if err := validateUnitMachineAssignment(
-		m, u.doc.Series, u.doc.Principal != "", storagePools,
+		m, u.doc.Series, cons, u.doc.Principal != "", storagePools,
 	); err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -1422,6 +1535,7 @@ func (u *Unit) assignToMachineOps(m *Machine, unused bool) ([]txn.Op, error) {
 func validateUnitMachineAssignment(
 	m *Machine,
 	series string,
+	cons constraints.Value,
 	isSubordinate bool,
 	storagePools set.Strings,
 ) (err error) {
@@ -1444,12 +1558,48 @@ func validateUnitMachineAssignment(
 	if !canHost {
 		return fmt.Errorf("machine %q cannot host units", m)
 	}
+	if err := validateMachineArch(m, cons); err != nil {
+		return errors.Trace(err)
+	}
 	if err := validateDynamicMachineStoragePools(m, storagePools); err != nil {
 		return errors.Trace(err)
 	}
 	return nil
 }
 
+// validateMachineArch returns an error if the architecture of the
+// machine does not match that required by the constraints. The
+// machine's architecture is taken from its hardware characteristics
+// if it has been provisioned, and from its constraints otherwise.
+func validateMachineArch(m *Machine, cons constraints.Value) error {
+	if !cons.HasArch() {
+		return nil
+	}
+	var machineArch string
+	hc, err := m.HardwareCharacteristics()
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	if hc != nil && hc.Arch != nil {
+		machineArch = *hc.Arch
+	} else {
+		machineCons, err := m.Constraints()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if machineCons.HasArch() {
+			machineArch = *machineCons.Arch
+		}
+	}
+	if machineArch != "" && machineArch != *cons.Arch {
+		return errors.Errorf(
+			"machine architecture %q does not match required architecture %q",
+			machineArch, *cons.Arch,
+		)
+	}
+	return nil
+}
+
 // validateDynamicMachineStorageParams validates that the provided machine
 // storage parameters are compatible with the specified machine.
 func validateDynamicMachineStorageParams(m *Machine, params *machineStorageParams) error {
@@ -1597,9 +1747,19 @@ func assignContextf(err *error, unitName string, target string) {
 }
 
 // AssignToMachine assigns this unit to a given machine.
+// The unit is assigned even if that breaks the affinity policy of its
+// application, or of applications with units on the machine; the
+// violations are then recorded, and returned by AffinityViolations.
 func (u *Unit) AssignToMachine(m *Machine) (err error) {
 	defer assignContextf(&err, u.Name(), fmt.Sprintf("machine %s", m))
-	return u.assignToMachine(m, false)
+	violations, err := u.affinityViolations(m)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := u.assignToMachine(m, false); err != nil {
+		return err
+	}
+	return u.recordAffinityViolations(violations)
 }
 
 // assignToNewMachineOps returns txn.Ops to assign the unit to a machine
@@ -1812,6 +1972,14 @@ func (u *Unit) AssignToNewMachine() (err error) {
 			Filesystems:           storageParams.filesystems,
 			FilesystemAttachments: storageParams.filesystemAttachments,
 		}
+		if containerType == "" {
+			// Start the machine alongside an application that
+			// the unit's application has zone affinity with.
+			template.Placement, err = u.zoneAffinityPlacement()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
 		// Get the ops necessary to create a new machine, and the
 		// machine doc that will be added with those operations
 		// (which includes the machine id).
@@ -2293,6 +2461,19 @@ func (u *Unit) assignToCleanMaybeEmptyMachineOps(requireEmpty bool) (_ *Machine,
 		machines[i] = m
 	}
 	machines = append(machines, unprovisioned...)
+	zones, err := u.preferredZones()
+	if err != nil {
+		assignContextf(&err, u.Name(), context)
+		return failure(err)
+	}
+	if machines, err = preferZones(u.st, machines, zones); err != nil {
+		assignContextf(&err, u.Name(), context)
+		return failure(err)
+	}
+	if machines, err = u.withoutHostConflicts(machines); err != nil {
+		assignContextf(&err, u.Name(), context)
+		return failure(err)
+	}
 
 	// TODO(axw) 2014-05-30 #1253704
 	// We should not select a machine that is in the process
@@ -2311,7 +2492,7 @@ func (u *Unit) assignToCleanMaybeEmptyMachineOps(requireEmpty bool) (_ *Machine,
 			assignContextf(&err, u.Name(), context)
 			return failure(err)
 		}
-		ops, err := u.assignToMachineOps(m, true)
+		ops, err := u.assignToMachineOps(m, true, false)
 		if err == nil {
 			return m, ops, nil
 		}
@@ -2343,6 +2524,11 @@ func (u *Unit) UnassignFromMachine() (err error) {
 			Assert: txn.DocExists,
 			Update: bson.D{{"$pull", bson.D{{"principals", u.doc.Name}}}},
 		})
+		poolOps, _, err := u.releasePoolMachineOps(u.doc.MachineId)
+		if err != nil {
+			return errors.Annotatef(err, "cannot unassign unit %q from machine", u)
+		}
+		ops = append(ops, poolOps...)
 	}
 	err = u.st.db().RunTransaction(ops)
 	if err != nil {
@@ -2359,6 +2545,11 @@ type ActionSpecsByName map[string]charm.ActionSpec
 // this Unit, and returns its ID.  Note that the use of spec.InsertDefaults
 // mutates payload.
 func (u *Unit) AddAction(name string, payload map[string]interface{}) (Action, error) {
+	return u.AddCorrelatedAction("", name, payload)
+}
+
+// AddCorrelatedAction is part of the ActionReceiver interface.
+func (u *Unit) AddCorrelatedAction(correlationID, name string, payload map[string]interface{}) (Action, error) {
 	if len(name) == 0 {
 		return nil, errors.New("no action name given")
 	}
@@ -2390,7 +2581,7 @@ func (u *Unit) AddAction(name string, payload map[string]interface{}) (Action, e
 		return nil, errors.Trace(err)
 	}
 
-	return model.EnqueueAction(u.Tag(), name, payloadWithDefaults)
+	return model.EnqueueCorrelatedAction(u.Tag(), correlationID, name, payloadWithDefaults)
 }
 
 // ActionSpecs gets the ActionSpec map for the Unit's charm.
@@ -1251,6 +1251,38 @@ func (s *UnitSuite) testOpenedPorts(c *gc.C, subnetID, expectedErrorCauseMatches
 	}
 }
 
+func (s *UnitSuite) TestOpenPortsForEndpoint(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.unit.OpenPortsForEndpoint("url", "tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.OpenPortsForEndpoint("db", "tcp", 8080, 8080)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.OpenPortsForEndpoint("nonexistent", "tcp", 443, 443)
+	c.Assert(err, gc.ErrorMatches, `cannot open ports 443-443/tcp \("wordpress/0"\) for unit "wordpress/0" on subnet "": application "wordpress" has no "nonexistent" relation`)
+
+	ports, err := machine.OpenedPorts("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports.AllPortRangeInfo(), jc.DeepEquals, []state.PortRange{
+		{UnitName: "wordpress/0", FromPort: 80, ToPort: 80, Protocol: "tcp", Endpoint: "url"},
+		{UnitName: "wordpress/0", FromPort: 8080, ToPort: 8080, Protocol: "tcp", Endpoint: "db"},
+	})
+
+	// Closing a range needs the endpoint it was opened for.
+	err = s.unit.ClosePorts("tcp", 80, 80)
+	c.Assert(err, gc.ErrorMatches, `.*port ranges 80-80/tcp \("wordpress/0", endpoint "url"\) and 80-80/tcp \("wordpress/0"\) conflict`)
+	err = s.unit.ClosePortsForEndpoint("url", "tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ports.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports.AllPortRangeInfo(), jc.DeepEquals, []state.PortRange{
+		{UnitName: "wordpress/0", FromPort: 8080, ToPort: 8080, Protocol: "tcp", Endpoint: "db"},
+	})
+}
+
 func (s *UnitSuite) TestOpenClosePortWhenDying(c *gc.C) {
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,496 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/status"
+)
+
+// UnitMovePhase identifies how far a unit move has progressed.
+//
+// A unit cannot change machines, so a unit is moved by adding a
+// replacement unit at the new placement, waiting for it to start and
+// join the relations the original unit is in, and then removing the
+// original unit. Detachable storage of the original unit is attached
+// to the replacement once the original unit has gone.
+type UnitMovePhase string
+
+const (
+	// UnitMoveProvisioning is the phase in which the replacement
+	// unit's machine is being provisioned.
+	UnitMoveProvisioning UnitMovePhase = "provisioning"
+
+	// UnitMoveDeploying is the phase in which the replacement unit is
+	// being deployed to its machine.
+	UnitMoveDeploying UnitMovePhase = "deploying"
+
+	// UnitMoveJoining is the phase in which the replacement unit is
+	// joining the relations that the original unit is in.
+	UnitMoveJoining UnitMovePhase = "joining"
+
+	// UnitMoveRetiring is the phase in which the original unit is
+	// departing its relations and being removed.
+	UnitMoveRetiring UnitMovePhase = "retiring"
+
+	// UnitMoveDone is the phase of a move that has completed.
+	UnitMoveDone UnitMovePhase = "done"
+
+	// UnitMoveFailed is the phase of a move that could not be
+	// completed. The replacement unit, if any, is left in place.
+	UnitMoveFailed UnitMovePhase = "failed"
+)
+
+// Finished reports whether a move in the phase has stopped progressing.
+func (p UnitMovePhase) Finished() bool {
+	return p == UnitMoveDone || p == UnitMoveFailed
+}
+
+// UnitMove describes the move of a unit to another machine.
+type UnitMove struct {
+	// Unit is the name of the unit being moved.
+	Unit string
+
+	// Replacement is the name of the unit that replaces it.
+	Replacement string
+
+	// Phase is how far the move has progressed.
+	Phase UnitMovePhase
+
+	// Message describes what the move is waiting for, or how it
+	// finished.
+	Message string
+
+	// Started is when the move was started.
+	Started time.Time
+
+	// Updated is when the move last changed phase.
+	Updated time.Time
+}
+
+// unitMoveDoc records the progress of a unit move. Moves are keyed by
+// the name of the unit being moved, and are kept once finished so that
+// their outcome can be reported.
+type unitMoveDoc struct {
+	DocID       string `bson:"_id"`
+	ModelUUID   string `bson:"model-uuid"`
+	Unit        string `bson:"unit"`
+	Replacement string `bson:"replacement"`
+	Phase       string `bson:"phase"`
+	Message     string `bson:"message"`
+	Started     int64  `bson:"started"`
+	Updated     int64  `bson:"updated"`
+
+	// PlacementScope and PlacementDirective hold the placement of the
+	// replacement unit. If both are empty, it is placed on a new
+	// machine.
+	PlacementScope     string `bson:"placement-scope,omitempty"`
+	PlacementDirective string `bson:"placement-directive,omitempty"`
+
+	// Storage holds the ids of the storage instances attached to the
+	// unit when the move started, which are attached to the
+	// replacement where possible.
+	Storage []string `bson:"storage,omitempty"`
+}
+
+func (doc *unitMoveDoc) move() UnitMove {
+	return UnitMove{
+		Unit:        doc.Unit,
+		Replacement: doc.Replacement,
+		Phase:       UnitMovePhase(doc.Phase),
+		Message:     doc.Message,
+		Started:     time.Unix(0, doc.Started).UTC(),
+		Updated:     time.Unix(0, doc.Updated).UTC(),
+	}
+}
+
+func (st *State) unitMoveDoc(unitName string) (*unitMoveDoc, error) {
+	coll, closer := st.db().GetCollection(unitMovesC)
+	defer closer()
+
+	var doc unitMoveDoc
+	err := coll.FindId(unitName).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("move of unit %q", unitName)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot read move of unit %q", unitName)
+	}
+	return &doc, nil
+}
+
+// UnitMove returns the most recent move of the named unit.
+func (st *State) UnitMove(unitName string) (UnitMove, error) {
+	doc, err := st.unitMoveDoc(unitName)
+	if err != nil {
+		return UnitMove{}, errors.Trace(err)
+	}
+	return doc.move(), nil
+}
+
+// MoveUnit starts moving the named principal unit to a machine chosen
+// by the placement directive, or to a new machine if placement is nil.
+// It adds the replacement unit straight away, in the same transaction
+// that records the move; the rest of the move, starting with the
+// assignment of the replacement, is carried out by AdvanceUnitMoves.
+func (st *State) MoveUnit(unitName string, placement *instance.Placement) (_ UnitMove, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot move unit %q", unitName)
+
+	im, err := st.IAASModel()
+	if err != nil {
+		return UnitMove{}, errors.Trace(err)
+	}
+	unit, err := st.Unit(unitName)
+	if err != nil {
+		return UnitMove{}, errors.Trace(err)
+	}
+	if unit.Life() != Alive {
+		return UnitMove{}, errors.New("unit is not alive")
+	}
+	if !unit.IsPrincipal() {
+		return UnitMove{}, errors.NotSupportedf("moving subordinate units")
+	}
+	machineId, err := unit.AssignedMachineId()
+	if err != nil {
+		return UnitMove{}, errors.Trace(err)
+	}
+	if placement != nil && placement.Scope == instance.MachineScope && placement.Directive == machineId {
+		return UnitMove{}, errors.Errorf("unit is already on machine %s", machineId)
+	}
+	existing, err := st.unitMoveDoc(unitName)
+	if err == nil && !UnitMovePhase(existing.Phase).Finished() {
+		return UnitMove{}, errors.AlreadyExistsf("move of unit %q", unitName)
+	} else if err != nil && !errors.IsNotFound(err) {
+		return UnitMove{}, errors.Trace(err)
+	}
+	attachments, err := im.UnitStorageAttachments(unit.UnitTag())
+	if err != nil {
+		return UnitMove{}, errors.Trace(err)
+	}
+
+	app, err := unit.Application()
+	if err != nil {
+		return UnitMove{}, errors.Trace(err)
+	}
+
+	var doc unitMoveDoc
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := unit.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+			if unit.Life() != Alive {
+				return nil, errors.New("unit is not alive")
+			}
+			if err := app.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+			existing, err = st.unitMoveDoc(unitName)
+			if err == nil && !UnitMovePhase(existing.Phase).Finished() {
+				return nil, errors.AlreadyExistsf("move of unit %q", unitName)
+			} else if errors.IsNotFound(err) {
+				existing = nil
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		// The replacement is assigned by AdvanceUnitMoves, so that
+		// it is added along with the record of the move.
+		replacementName, ops, err := app.addUnitOps("", AddUnitParams{}, nil)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		now := st.clock().Now().UnixNano()
+		doc = unitMoveDoc{
+			DocID:       unitName,
+			Unit:        unitName,
+			Replacement: replacementName,
+			Phase:       string(UnitMoveProvisioning),
+			Message:     "waiting for replacement unit to be assigned",
+			Started:     now,
+			Updated:     now,
+		}
+		if placement != nil {
+			doc.PlacementScope = placement.Scope
+			doc.PlacementDirective = placement.Directive
+		}
+		for _, attachment := range attachments {
+			doc.Storage = append(doc.Storage, attachment.StorageInstance().Id())
+		}
+		ops = append(ops, txn.Op{
+			C:      unitsC,
+			Id:     unit.doc.DocID,
+			Assert: isAliveDoc,
+		})
+		if existing == nil {
+			ops = append(ops, txn.Op{
+				C:      unitMovesC,
+				Id:     unitName,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			})
+		} else {
+			// Only the outcome of the most recent move is kept.
+			ops = append(ops, txn.Op{
+				C:      unitMovesC,
+				Id:     unitName,
+				Assert: bson.D{{"phase", existing.Phase}},
+				Update: bson.D{{"$set", bson.D{
+					{"replacement", doc.Replacement},
+					{"phase", doc.Phase},
+					{"message", doc.Message},
+					{"started", doc.Started},
+					{"updated", doc.Updated},
+					{"placement-scope", doc.PlacementScope},
+					{"placement-directive", doc.PlacementDirective},
+					{"storage", doc.Storage},
+				}}},
+			})
+		}
+		return ops, nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
+		return UnitMove{}, errors.Trace(err)
+	}
+	return doc.move(), nil
+}
+
+// WatchUnitMoves returns a NotifyWatcher that notifies of changes to
+// the model's unit moves.
+func (st *State) WatchUnitMoves() NotifyWatcher {
+	return newNotifyCollWatcher(st, unitMovesC, isLocalID(st))
+}
+
+// AdvanceUnitMoves moves each of the model's unfinished unit moves on
+// to its next phase, if it is ready for it. It returns the number of
+// moves that are still unfinished, which need to be advanced again
+// later.
+func (st *State) AdvanceUnitMoves() (int, error) {
+	coll, closer := st.db().GetCollection(unitMovesC)
+	defer closer()
+
+	var docs []unitMoveDoc
+	query := bson.D{{"phase", bson.D{{"$nin", []string{string(UnitMoveDone), string(UnitMoveFailed)}}}}}
+	if err := coll.Find(query).All(&docs); err != nil {
+		return 0, errors.Annotate(err, "cannot read unit moves")
+	}
+	active := 0
+	for _, doc := range docs {
+		phase, err := st.advanceUnitMove(doc)
+		if err != nil {
+			return 0, errors.Annotatef(err, "cannot advance move of unit %q", doc.Unit)
+		}
+		if !phase.Finished() {
+			active++
+		}
+	}
+	return active, nil
+}
+
+// advanceUnitMove moves the unit move on to its next phase, if it is
+// ready, and returns the phase it is then in.
+func (st *State) advanceUnitMove(doc unitMoveDoc) (UnitMovePhase, error) {
+	phase := UnitMovePhase(doc.Phase)
+	next, message, err := st.nextUnitMovePhase(&doc)
+	if err != nil {
+		return phase, errors.Trace(err)
+	}
+	if next == phase && message == doc.Message {
+		return phase, nil
+	}
+	if next != phase {
+		logger.Infof("move of unit %q: %s (%s)", doc.Unit, next, message)
+	}
+	ops := []txn.Op{{
+		C:      unitMovesC,
+		Id:     doc.DocID,
+		Assert: bson.D{{"phase", doc.Phase}},
+		Update: bson.D{{"$set", bson.D{
+			{"phase", string(next)},
+			{"message", message},
+			{"updated", st.clock().Now().UnixNano()},
+		}}},
+	}}
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		// Another controller advanced the move first.
+		return phase, nil
+	} else if err != nil {
+		return phase, errors.Trace(err)
+	}
+	return next, nil
+}
+
+// nextUnitMovePhase returns the phase the move should be in, and a
+// message describing it, taking any action needed to get there.
+func (st *State) nextUnitMovePhase(doc *unitMoveDoc) (UnitMovePhase, string, error) {
+	phase := UnitMovePhase(doc.Phase)
+	unit, err := st.Unit(doc.Unit)
+	if errors.IsNotFound(err) {
+		if phase == UnitMoveRetiring {
+			return st.finishUnitMove(doc)
+		}
+		return UnitMoveFailed, "unit removed before the move completed", nil
+	} else if err != nil {
+		return phase, "", errors.Trace(err)
+	}
+	replacement, err := st.Unit(doc.Replacement)
+	if errors.IsNotFound(err) {
+		return UnitMoveFailed, fmt.Sprintf("replacement unit %q removed", doc.Replacement), nil
+	} else if err != nil {
+		return phase, "", errors.Trace(err)
+	}
+	if replacement.Life() != Alive && phase != UnitMoveRetiring {
+		return UnitMoveFailed, fmt.Sprintf("replacement unit %q is no longer alive", doc.Replacement), nil
+	}
+
+	switch phase {
+	case UnitMoveProvisioning:
+		machineId, err := replacement.AssignedMachineId()
+		if errors.IsNotAssigned(err) {
+			if err := st.assignUnitMoveReplacement(doc, replacement); err != nil {
+				logger.Warningf("move of unit %q: %v", doc.Unit, err)
+				if err := replacement.Destroy(); err != nil {
+					logger.Warningf("cannot destroy unassigned replacement unit %q: %v", replacement, err)
+				}
+				return UnitMoveFailed, fmt.Sprintf("cannot assign replacement unit: %v", err), nil
+			}
+			machineId, err = replacement.AssignedMachineId()
+		}
+		if err != nil {
+			return phase, "", errors.Trace(err)
+		}
+		machine, err := st.Machine(machineId)
+		if err != nil {
+			return phase, "", errors.Trace(err)
+		}
+		if _, err := machine.InstanceId(); errors.IsNotProvisioned(err) {
+			instanceStatus, err := machine.InstanceStatus()
+			if err != nil {
+				return phase, "", errors.Trace(err)
+			}
+			if instanceStatus.Status == status.ProvisioningError {
+				return UnitMoveFailed, fmt.Sprintf("cannot provision machine %s: %s", machineId, instanceStatus.Message), nil
+			}
+			return phase, fmt.Sprintf("waiting for machine %s", machineId), nil
+		} else if err != nil {
+			return phase, "", errors.Trace(err)
+		}
+		return UnitMoveDeploying, fmt.Sprintf("waiting for unit %s to start", doc.Replacement), nil
+
+	case UnitMoveDeploying:
+		agentStatus, err := replacement.AgentStatus()
+		if err != nil {
+			return phase, "", errors.Trace(err)
+		}
+		if agentStatus.Status == status.Allocating {
+			return phase, fmt.Sprintf("waiting for unit %s to start", doc.Replacement), nil
+		}
+		return UnitMoveJoining, fmt.Sprintf("waiting for unit %s to join relations", doc.Replacement), nil
+
+	case UnitMoveJoining:
+		pending, err := relationsToJoin(unit, replacement)
+		if err != nil {
+			return phase, "", errors.Trace(err)
+		}
+		if len(pending) > 0 {
+			return phase, fmt.Sprintf("waiting for unit %s to join %s", doc.Replacement, strings.Join(pending, ", ")), nil
+		}
+		// Detachable storage is detached rather than destroyed, so
+		// that it can be attached to the replacement.
+		op := unit.DestroyOperation()
+		if err := st.ApplyOperation(op); err != nil {
+			return phase, "", errors.Annotatef(err, "cannot destroy unit %q", doc.Unit)
+		}
+		return UnitMoveRetiring, fmt.Sprintf("waiting for unit %s to be removed", doc.Unit), nil
+
+	case UnitMoveRetiring:
+		return phase, fmt.Sprintf("waiting for unit %s to be removed", doc.Unit), nil
+	}
+	return UnitMoveFailed, fmt.Sprintf("unknown phase %q", phase), nil
+}
+
+// assignUnitMoveReplacement assigns the replacement unit to the machine
+// chosen by the move's placement, or to a new machine.
+func (st *State) assignUnitMoveReplacement(doc *unitMoveDoc, replacement *Unit) error {
+	if doc.PlacementScope == "" && doc.PlacementDirective == "" {
+		return errors.Trace(st.AssignUnit(replacement, AssignNew))
+	}
+	placement := &instance.Placement{
+		Scope:     doc.PlacementScope,
+		Directive: doc.PlacementDirective,
+	}
+	return errors.Trace(st.AssignUnitWithPlacement(replacement, placement))
+}
+
+// relationsToJoin returns the keys of the relations that the unit is in
+// scope of, but the replacement unit is not.
+func relationsToJoin(unit, replacement *Unit) ([]string, error) {
+	relations, err := unit.RelationsInScope()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var pending []string
+	for _, rel := range relations {
+		ru, err := rel.Unit(replacement)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		inScope, err := ru.InScope()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !inScope {
+			pending = append(pending, rel.String())
+		}
+	}
+	return pending, nil
+}
+
+// finishUnitMove attaches the moved unit's storage to its replacement,
+// where possible, now that the moved unit has gone.
+func (st *State) finishUnitMove(doc *unitMoveDoc) (UnitMovePhase, string, error) {
+	replacement, err := st.Unit(doc.Replacement)
+	if err != nil {
+		return UnitMoveFailed, fmt.Sprintf("cannot find replacement unit %q: %v", doc.Replacement, err), nil
+	}
+	machineId, err := replacement.AssignedMachineId()
+	if err != nil {
+		return UnitMoveRetiring, "", errors.Trace(err)
+	}
+	message := fmt.Sprintf("moved to unit %s on machine %s", doc.Replacement, machineId)
+	if len(doc.Storage) == 0 {
+		return UnitMoveDone, message, nil
+	}
+	im, err := st.IAASModel()
+	if err != nil {
+		return UnitMoveRetiring, "", errors.Trace(err)
+	}
+	var notMoved []string
+	for _, id := range doc.Storage {
+		tag := names.NewStorageTag(id)
+		if _, err := im.StorageInstance(tag); errors.IsNotFound(err) {
+			// The storage was removed along with the unit.
+			continue
+		} else if err != nil {
+			return UnitMoveRetiring, "", errors.Trace(err)
+		}
+		if err := im.AttachStorage(tag, replacement.UnitTag()); err != nil {
+			logger.Warningf("move of unit %q: %v", doc.Unit, err)
+			notMoved = append(notMoved, id)
+		}
+	}
+	if len(notMoved) > 0 {
+		message += fmt.Sprintf("; storage %s could not be attached to the replacement", strings.Join(notMoved, ", "))
+	}
+	return UnitMoveDone, message, nil
+}
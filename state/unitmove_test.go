@@ -0,0 +1,224 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/testing/factory"
+)
+
+type UnitMoveSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&UnitMoveSuite{})
+
+func (s *UnitMoveSuite) advance(c *gc.C, expectActive int) state.UnitMove {
+	active, err := s.State.AdvanceUnitMoves()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(active, gc.Equals, expectActive)
+	move, err := s.State.UnitMove("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	return move
+}
+
+func (s *UnitMoveSuite) makeUnit(c *gc.C) *state.Unit {
+	app := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+	})
+	return s.Factory.MakeUnit(c, &factory.UnitParams{Application: app})
+}
+
+func (s *UnitMoveSuite) startAgent(c *gc.C, unit *state.Unit) {
+	now := time.Now()
+	err := unit.SetAgentStatus(status.StatusInfo{Status: status.Idle, Since: &now})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UnitMoveSuite) TestMoveUnit(c *gc.C) {
+	unit := s.makeUnit(c)
+	target := s.Factory.MakeMachine(c, nil)
+
+	move, err := s.State.MoveUnit(unit.Name(), &instance.Placement{Scope: instance.MachineScope, Directive: target.Id()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(move.Unit, gc.Equals, "wordpress/0")
+	c.Assert(move.Replacement, gc.Equals, "wordpress/1")
+	c.Assert(move.Phase, gc.Equals, state.UnitMoveProvisioning)
+
+	// The replacement is added along with the move, and assigned
+	// when the move is first advanced.
+	replacement, err := s.State.Unit(move.Replacement)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = replacement.AssignedMachineId()
+	c.Assert(err, jc.Satisfies, errors.IsNotAssigned)
+
+	// The target machine is already provisioned.
+	move = s.advance(c, 1)
+	c.Assert(move.Phase, gc.Equals, state.UnitMoveDeploying)
+	err = replacement.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := replacement.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Equals, target.Id())
+	c.Assert(move.Message, gc.Equals, "waiting for unit wordpress/1 to start")
+	move = s.advance(c, 1)
+	c.Assert(move.Phase, gc.Equals, state.UnitMoveDeploying)
+
+	s.startAgent(c, replacement)
+	move = s.advance(c, 1)
+	c.Assert(move.Phase, gc.Equals, state.UnitMoveJoining)
+
+	// The unit is in no relations, so it is retired straight away.
+	move = s.advance(c, 1)
+	c.Assert(move.Phase, gc.Equals, state.UnitMoveRetiring)
+	err = unit.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	move = s.advance(c, 0)
+	c.Assert(move.Phase, gc.Equals, state.UnitMoveDone)
+	c.Assert(move.Message, gc.Equals, "moved to unit wordpress/1 on machine "+target.Id())
+}
+
+func (s *UnitMoveSuite) TestMoveUnitWaitsForRelations(c *gc.C) {
+	unit := s.makeUnit(c)
+	mysql := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "mysql"}),
+	})
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel := s.Factory.MakeRelation(c, &factory.RelationParams{Endpoints: eps})
+	mysqlUnit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: mysql})
+	for _, u := range []*state.Unit{unit, mysqlUnit} {
+		ru, err := rel.Unit(u)
+		c.Assert(err, jc.ErrorIsNil)
+		err = ru.EnterScope(nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	// The unit's agent is running, so it cannot be removed until
+	// it has departed its relations.
+	s.startAgent(c, unit)
+
+	move, err := s.State.MoveUnit(unit.Name(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	move = s.advance(c, 1)
+	replacement, err := s.State.Unit(move.Replacement)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := replacement.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.Machine(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(move.Phase, gc.Equals, state.UnitMoveProvisioning)
+	c.Assert(move.Message, gc.Equals, "waiting for machine "+machineId)
+	err = machine.SetProvisioned("inst-id", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.advance(c, 1)
+	s.startAgent(c, replacement)
+	s.advance(c, 1)
+
+	move = s.advance(c, 1)
+	c.Assert(move.Phase, gc.Equals, state.UnitMoveJoining)
+	c.Assert(move.Message, gc.Equals, "waiting for unit wordpress/1 to join "+rel.String())
+
+	ru, err := rel.Unit(replacement)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	move = s.advance(c, 1)
+	c.Assert(move.Phase, gc.Equals, state.UnitMoveRetiring)
+	err = unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit.Life(), gc.Equals, state.Dying)
+}
+
+func (s *UnitMoveSuite) TestMoveUnitProvisioningError(c *gc.C) {
+	unit := s.makeUnit(c)
+	move, err := s.State.MoveUnit(unit.Name(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.advance(c, 1)
+	replacement, err := s.State.Unit(move.Replacement)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := replacement.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.Machine(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+
+	now := time.Now()
+	err = machine.SetInstanceStatus(status.StatusInfo{
+		Status:  status.ProvisioningError,
+		Message: "no capacity",
+		Since:   &now,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	move = s.advance(c, 0)
+	c.Assert(move.Phase, gc.Equals, state.UnitMoveFailed)
+	c.Assert(move.Message, gc.Equals, "cannot provision machine "+machineId+": no capacity")
+
+	// The unit can be moved again once the move has failed.
+	move, err = s.State.MoveUnit(unit.Name(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(move.Phase, gc.Equals, state.UnitMoveProvisioning)
+}
+
+func (s *UnitMoveSuite) TestMoveUnitAlreadyMoving(c *gc.C) {
+	unit := s.makeUnit(c)
+	_, err := s.State.MoveUnit(unit.Name(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.MoveUnit(unit.Name(), nil)
+	c.Assert(err, gc.ErrorMatches, `cannot move unit "wordpress/0": move of unit "wordpress/0" already exists`)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+
+	// No second replacement was added.
+	_, err = s.State.Unit("wordpress/2")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UnitMoveSuite) TestMoveUnitAssignmentFails(c *gc.C) {
+	unit := s.makeUnit(c)
+	move, err := s.State.MoveUnit(unit.Name(), &instance.Placement{Scope: instance.MachineScope, Directive: "42"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	move = s.advance(c, 0)
+	c.Assert(move.Phase, gc.Equals, state.UnitMoveFailed)
+	c.Assert(move.Message, gc.Matches, "cannot assign replacement unit: .*machine 42 not found")
+	// The replacement was never assigned, nor did its agent start, so
+	// it is removed outright.
+	_, err = s.State.Unit(move.Replacement)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UnitMoveSuite) TestMoveUnitSameMachine(c *gc.C) {
+	unit := s.makeUnit(c)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.MoveUnit(unit.Name(), &instance.Placement{Scope: instance.MachineScope, Directive: machineId})
+	c.Assert(err, gc.ErrorMatches, `cannot move unit "wordpress/0": unit is already on machine `+machineId)
+}
+
+func (s *UnitMoveSuite) TestMoveUnitNotFound(c *gc.C) {
+	_, err := s.State.MoveUnit("wordpress/0", nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.UnitMove("wordpress/0")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UnitMoveSuite) TestMoveUnitRemovedDuringMove(c *gc.C) {
+	unit := s.makeUnit(c)
+	_, err := s.State.MoveUnit(unit.Name(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	move := s.advance(c, 0)
+	c.Assert(move.Phase, gc.Equals, state.UnitMoveFailed)
+	c.Assert(move.Message, gc.Equals, "unit removed before the move completed")
+}
@@ -77,8 +77,41 @@ func replaceBsonDField(d bson.D, name string, value interface{}) error {
 	return errors.NotFoundf("field %q", name)
 }
 
+// countChangedDocs returns the number of documents that the supplied
+// transaction operations would insert, update or remove.
+func countChangedDocs(ops []txn.Op) int {
+	var count int
+	for _, op := range ops {
+		if op.Insert != nil || op.Update != nil || op.Remove {
+			count++
+		}
+	}
+	return count
+}
+
 // RenameAddModelPermission renames any permissions called addmodel to add-model.
 func RenameAddModelPermission(st *State) error {
+	ops, err := renameAddModelPermissionOps(st)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(ops) > 0 {
+		return errors.Trace(st.runRawTransaction(ops))
+	}
+	return nil
+}
+
+// CountAddModelPermissions returns the number of documents that
+// RenameAddModelPermission would change.
+func CountAddModelPermissions(st *State) (int, error) {
+	ops, err := renameAddModelPermissionOps(st)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return countChangedDocs(ops), nil
+}
+
+func renameAddModelPermissionOps(st *State) ([]txn.Op, error) {
 	coll, closer := st.db().GetRawCollection(permissionsC)
 	defer closer()
 	upgradesLogger.Infof("migrating addmodel permission")
@@ -90,7 +123,7 @@ func RenameAddModelPermission(st *State) error {
 	for iter.Next(&doc) {
 		id, ok := doc["_id"]
 		if !ok {
-			return errors.New("no id found in permission doc")
+			return nil, errors.New("no id found in permission doc")
 		}
 
 		ops = append(ops, txn.Op{
@@ -101,56 +134,77 @@ func RenameAddModelPermission(st *State) error {
 		})
 	}
 	if err := iter.Err(); err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
-	return st.runRawTransaction(ops)
+	return ops, nil
 }
 
 // StripLocalUserDomain removes any @local suffix from any relevant document field values.
 func StripLocalUserDomain(st *State) error {
+	ops, err := stripLocalUserDomainOps(st)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(ops) > 0 {
+		return errors.Trace(st.runRawTransaction(ops))
+	}
+	return nil
+}
+
+// CountLocalUserDomainDocs returns the number of documents that
+// StripLocalUserDomain would change.
+func CountLocalUserDomainDocs(st *State) (int, error) {
+	ops, err := stripLocalUserDomainOps(st)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return countChangedDocs(ops), nil
+}
+
+func stripLocalUserDomainOps(st *State) ([]txn.Op, error) {
 	var ops []txn.Op
 	more, err := stripLocalFromFields(st, cloudCredentialsC, "_id", "owner")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	ops = append(ops, more...)
 
 	more, err = stripLocalFromFields(st, modelsC, "owner", "cloud-credential")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	ops = append(ops, more...)
 
 	more, err = stripLocalFromFields(st, usermodelnameC, "_id")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	ops = append(ops, more...)
 
 	more, err = stripLocalFromFields(st, controllerUsersC, "_id", "user", "createdby")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	ops = append(ops, more...)
 
 	more, err = stripLocalFromFields(st, modelUsersC, "_id", "user", "createdby")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	ops = append(ops, more...)
 
 	more, err = stripLocalFromFields(st, permissionsC, "_id", "subject-global-key")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	ops = append(ops, more...)
 
 	more, err = stripLocalFromFields(st, modelUserLastConnectionC, "_id", "user")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	ops = append(ops, more...)
-	return st.runRawTransaction(ops)
+	return ops, nil
 }
 
 func stripLocalFromFields(st *State, collName string, fields ...string) ([]txn.Op, error) {
@@ -236,6 +290,27 @@ func stripLocalFromFields(st *State, collName string, fields ...string) ([]txn.O
 // AddMigrationAttempt adds an "attempt" field to migration documents
 // which are missing one.
 func AddMigrationAttempt(st *State) error {
+	ops, err := addMigrationAttemptOps(st)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(ops) > 0 {
+		return errors.Trace(st.runRawTransaction(ops))
+	}
+	return nil
+}
+
+// CountMigrationsWithoutAttempt returns the number of documents that
+// AddMigrationAttempt would change.
+func CountMigrationsWithoutAttempt(st *State) (int, error) {
+	ops, err := addMigrationAttemptOps(st)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return countChangedDocs(ops), nil
+}
+
+func addMigrationAttemptOps(st *State) ([]txn.Op, error) {
 	coll, closer := st.db().GetRawCollection(migrationsC)
 	defer closer()
 
@@ -261,10 +336,10 @@ func AddMigrationAttempt(st *State) error {
 		})
 	}
 	if err := iter.Err(); err != nil {
-		return errors.Annotate(err, "iterating migrations")
+		return nil, errors.Annotate(err, "iterating migrations")
 	}
 
-	return errors.Trace(st.runRawTransaction(ops))
+	return ops, nil
 }
 
 func extractMigrationAttempt(id interface{}) (int, error) {
@@ -289,6 +364,69 @@ func extractMigrationAttempt(id interface{}) (int, error) {
 // AddLocalCharmSequences creates any missing sequences in the
 // database for tracking already used local charm revisions.
 func AddLocalCharmSequences(st *State) error {
+	maxRevs, deadIds, err := scanLocalCharms(st)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	sequences, closer := st.db().GetRawCollection(sequenceC)
+	defer closer()
+	for modelUUID, modelRevs := range maxRevs {
+		for baseURL, maxRevision := range modelRevs {
+			name := charmRevSeqName(baseURL)
+			updater := newDbSeqUpdater(sequences, modelUUID, name)
+			err := updater.ensure(maxRevision + 1)
+			if err != nil {
+				return errors.Annotatef(err, "setting sequence %s", name)
+			}
+		}
+
+	}
+
+	// Remove dead charm documents
+	var ops []txn.Op
+	for _, id := range deadIds {
+		ops = append(ops, txn.Op{
+			C:      charmsC,
+			Id:     id,
+			Remove: true,
+		})
+	}
+	err = st.runRawTransaction(ops)
+	return errors.Annotate(err, "removing dead charms")
+}
+
+// CountLocalCharmSequenceChanges returns the number of documents that
+// AddLocalCharmSequences would change: the sequences it would create
+// or advance, and the dead local charms it would remove.
+func CountLocalCharmSequenceChanges(st *State) (int, error) {
+	maxRevs, deadIds, err := scanLocalCharms(st)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+
+	sequences, closer := st.db().GetRawCollection(sequenceC)
+	defer closer()
+	count := len(deadIds)
+	for modelUUID, modelRevs := range maxRevs {
+		for baseURL, maxRevision := range modelRevs {
+			name := charmRevSeqName(baseURL)
+			curVal, err := newDbSeqUpdater(sequences, modelUUID, name).read()
+			if err != nil {
+				return 0, errors.Annotatef(err, "reading sequence %s", name)
+			}
+			if maxRevision+1 > curVal {
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+// scanLocalCharms returns the highest revision of each local charm
+// URL, keyed on model UUID and then on the URL without its revision,
+// along with the ids of the dead local charm documents.
+func scanLocalCharms(st *State) (map[string]map[string]int, []string, error) {
 	charmsColl, closer := st.db().GetRawCollection(charmsC)
 	defer closer()
 
@@ -301,7 +439,7 @@ func AddLocalCharmSequences(st *State) error {
 		"life": 1,
 	}).All(&docs)
 	if err != nil {
-		return errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
 
 	// model UUID -> charm URL base -> max revision
@@ -342,32 +480,7 @@ func AddLocalCharmSequences(st *State) error {
 		}
 
 	}
-
-	sequences, closer := st.db().GetRawCollection(sequenceC)
-	defer closer()
-	for modelUUID, modelRevs := range maxRevs {
-		for baseURL, maxRevision := range modelRevs {
-			name := charmRevSeqName(baseURL)
-			updater := newDbSeqUpdater(sequences, modelUUID, name)
-			err := updater.ensure(maxRevision + 1)
-			if err != nil {
-				return errors.Annotatef(err, "setting sequence %s", name)
-			}
-		}
-
-	}
-
-	// Remove dead charm documents
-	var ops []txn.Op
-	for _, id := range deadIds {
-		ops = append(ops, txn.Op{
-			C:      charmsC,
-			Id:     id,
-			Remove: true,
-		})
-	}
-	err = st.runRawTransaction(ops)
-	return errors.Annotate(err, "removing dead charms")
+	return maxRevs, deadIds, nil
 }
 
 // UpdateLegacyLXDCloudCredentials updates the cloud credentials for the
@@ -389,6 +502,21 @@ func UpdateLegacyLXDCloudCredentials(
 	return st.db().RunTransaction(append(cloudOps, credOps...))
 }
 
+// CountLegacyLXDCloudCredentials returns the number of cloud and
+// credential documents that UpdateLegacyLXDCloudCredentials would
+// change.
+func CountLegacyLXDCloudCredentials(st *State) (int, error) {
+	cloudOps, err := updateLegacyLXDCloudsOps(st, "")
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	credOps, err := updateLegacyLXDCredentialsOps(st, cloud.Credential{})
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return countChangedDocs(append(cloudOps, credOps...)), nil
+}
+
 func updateLegacyLXDCloudsOps(st *State, endpoint string) ([]txn.Op, error) {
 	clouds, err := st.Clouds()
 	if err != nil {
@@ -501,17 +629,47 @@ func AddNonDetachableStorageMachineId(st *State) error {
 	return runForAllModelStates(st, addNonDetachableStorageMachineId)
 }
 
+// CountNonDetachableStorageWithoutMachineId returns the number of
+// documents, across all models, that AddNonDetachableStorageMachineId
+// would change.
+func CountNonDetachableStorageWithoutMachineId(st *State) (int, error) {
+	var count int
+	err := runForAllModelStates(st, func(st *State) error {
+		ops, err := addNonDetachableStorageMachineIdOps(st)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		count += countChangedDocs(ops)
+		return nil
+	})
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return count, nil
+}
+
 func addNonDetachableStorageMachineId(st *State) error {
-	im, err := st.IAASModel()
+	ops, err := addNonDetachableStorageMachineIdOps(st)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if len(ops) > 0 {
+		return errors.Trace(st.db().RunTransaction(ops))
+	}
+	return nil
+}
+
+func addNonDetachableStorageMachineIdOps(st *State) ([]txn.Op, error) {
+	im, err := st.IAASModel()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	var ops []txn.Op
 	volumes, err := im.volumes(
 		bson.D{{"machineid", bson.D{{"$exists", false}}}},
 	)
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 	for _, v := range volumes {
 		var pool string
@@ -522,14 +680,14 @@ func addNonDetachableStorageMachineId(st *State) error {
 		}
 		detachable, err := isDetachableVolumePool(im, pool)
 		if err != nil {
-			return errors.Trace(err)
+			return nil, errors.Trace(err)
 		}
 		if detachable {
 			continue
 		}
 		attachments, err := im.VolumeAttachments(v.VolumeTag())
 		if err != nil {
-			return errors.Trace(err)
+			return nil, errors.Trace(err)
 		}
 		if len(attachments) != 1 {
 			// There should be exactly one attachment since the
@@ -552,7 +710,7 @@ func addNonDetachableStorageMachineId(st *State) error {
 		bson.D{{"machineid", bson.D{{"$exists", false}}}},
 	)
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 	for _, f := range filesystems {
 		var pool string
@@ -562,13 +720,13 @@ func addNonDetachableStorageMachineId(st *State) error {
 			pool = f.doc.Params.Pool
 		}
 		if detachable, err := isDetachableFilesystemPool(im, pool); err != nil {
-			return errors.Trace(err)
+			return nil, errors.Trace(err)
 		} else if detachable {
 			continue
 		}
 		attachments, err := im.FilesystemAttachments(f.FilesystemTag())
 		if err != nil {
-			return errors.Trace(err)
+			return nil, errors.Trace(err)
 		}
 		if len(attachments) != 1 {
 			// There should be exactly one attachment since the
@@ -587,15 +745,33 @@ func addNonDetachableStorageMachineId(st *State) error {
 			}}},
 		})
 	}
-	if len(ops) > 0 {
-		return errors.Trace(st.db().RunTransaction(ops))
-	}
-	return nil
+	return ops, nil
 }
 
 // RemoveNilValueApplicationSettings removes any application setting
 // key-value pairs from "settings" where value is nil.
 func RemoveNilValueApplicationSettings(st *State) error {
+	ops, err := removeNilValueApplicationSettingsOps(st)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(ops) > 0 {
+		return errors.Trace(st.runRawTransaction(ops))
+	}
+	return nil
+}
+
+// CountNilValueApplicationSettings returns the number of documents that
+// RemoveNilValueApplicationSettings would change.
+func CountNilValueApplicationSettings(st *State) (int, error) {
+	ops, err := removeNilValueApplicationSettingsOps(st)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return countChangedDocs(ops), nil
+}
+
+func removeNilValueApplicationSettingsOps(st *State) ([]txn.Op, error) {
 	coll, closer := st.db().GetRawCollection(settingsC)
 	defer closer()
 	iter := coll.Find(bson.M{"_id": bson.M{"$regex": "^.*:a#.*"}}).Iter()
@@ -619,23 +795,42 @@ func RemoveNilValueApplicationSettings(st *State) error {
 			})
 		}
 	}
+	return ops, nil
+}
+
+// AddControllerLogCollectionsSizeSettings adds the controller
+// settings to control log pruning and txn log size if they are missing.
+func AddControllerLogCollectionsSizeSettings(st *State) error {
+	ops, err := addControllerLogCollectionsSizeSettingsOps(st)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	if len(ops) > 0 {
 		return errors.Trace(st.runRawTransaction(ops))
 	}
 	return nil
 }
 
-// AddControllerLogCollectionsSizeSettings adds the controller
-// settings to control log pruning and txn log size if they are missing.
-func AddControllerLogCollectionsSizeSettings(st *State) error {
+// CountMissingControllerLogCollectionsSizeSettings returns the number
+// of documents that AddControllerLogCollectionsSizeSettings would
+// change.
+func CountMissingControllerLogCollectionsSizeSettings(st *State) (int, error) {
+	ops, err := addControllerLogCollectionsSizeSettingsOps(st)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return countChangedDocs(ops), nil
+}
+
+func addControllerLogCollectionsSizeSettingsOps(st *State) ([]txn.Op, error) {
 	coll, closer := st.db().GetRawCollection(controllersC)
 	defer closer()
 	var doc settingsDoc
 	if err := coll.FindId(controllerSettingsGlobalKey).One(&doc); err != nil {
 		if err == mgo.ErrNotFound {
-			return nil
+			return nil, nil
 		}
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 
 	var ops []txn.Op
@@ -652,10 +847,7 @@ func AddControllerLogCollectionsSizeSettings(st *State) error {
 			Update: bson.M{"$set": bson.M{"settings": doc.Settings}},
 		})
 	}
-	if len(ops) > 0 {
-		return errors.Trace(st.runRawTransaction(ops))
-	}
-	return nil
+	return ops, nil
 }
 
 func maybeUpdateSettings(settings map[string]interface{}, key string, value interface{}) bool {
@@ -669,12 +861,33 @@ func maybeUpdateSettings(settings map[string]interface{}, key string, value inte
 // AddStatusHistoryPruneSettings adds the model settings
 // to control log pruning if they are missing.
 func AddStatusHistoryPruneSettings(st *State) error {
+	ops, err := addStatusHistoryPruneSettingsOps(st)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(ops) > 0 {
+		return errors.Trace(st.runRawTransaction(ops))
+	}
+	return nil
+}
+
+// CountMissingStatusHistoryPruneSettings returns the number of documents that
+// AddStatusHistoryPruneSettings would change.
+func CountMissingStatusHistoryPruneSettings(st *State) (int, error) {
+	ops, err := addStatusHistoryPruneSettingsOps(st)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return countChangedDocs(ops), nil
+}
+
+func addStatusHistoryPruneSettingsOps(st *State) ([]txn.Op, error) {
 	coll, closer := st.db().GetRawCollection(settingsC)
 	defer closer()
 
 	uuids, err := st.AllModelUUIDs()
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 	var ids []string
 	for _, uuid := range uuids {
@@ -698,21 +911,39 @@ func AddStatusHistoryPruneSettings(st *State) error {
 			})
 		}
 	}
+	return ops, nil
+}
+
+// AddActionPruneSettings adds the model settings
+// to control log pruning if they are missing.
+func AddActionPruneSettings(st *State) error {
+	ops, err := addActionPruneSettingsOps(st)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	if len(ops) > 0 {
 		return errors.Trace(st.runRawTransaction(ops))
 	}
 	return nil
 }
 
-// AddActionPruneSettings adds the model settings
-// to control log pruning if they are missing.
-func AddActionPruneSettings(st *State) error {
+// CountMissingActionPruneSettings returns the number of documents that
+// AddActionPruneSettings would change.
+func CountMissingActionPruneSettings(st *State) (int, error) {
+	ops, err := addActionPruneSettingsOps(st)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return countChangedDocs(ops), nil
+}
+
+func addActionPruneSettingsOps(st *State) ([]txn.Op, error) {
 	coll, closer := st.db().GetRawCollection(settingsC)
 	defer closer()
 
 	uuids, err := st.AllModelUUIDs()
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 	var ids []string
 	for _, uuid := range uuids {
@@ -736,22 +967,40 @@ func AddActionPruneSettings(st *State) error {
 			})
 		}
 	}
-	if len(ops) > 0 {
-		return errors.Trace(st.runRawTransaction(ops))
-	}
-	return nil
+	return ops, nil
 }
 
 // AddUpdateStatusHookSettings adds the model settings
 // to control how often to run the update-status hook
 // if they are missing.
 func AddUpdateStatusHookSettings(st *State) error {
+	ops, err := addUpdateStatusHookSettingsOps(st)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(ops) > 0 {
+		return errors.Trace(st.runRawTransaction(ops))
+	}
+	return nil
+}
+
+// CountMissingUpdateStatusHookSettings returns the number of documents that
+// AddUpdateStatusHookSettings would change.
+func CountMissingUpdateStatusHookSettings(st *State) (int, error) {
+	ops, err := addUpdateStatusHookSettingsOps(st)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return countChangedDocs(ops), nil
+}
+
+func addUpdateStatusHookSettingsOps(st *State) ([]txn.Op, error) {
 	coll, closer := st.db().GetRawCollection(settingsC)
 	defer closer()
 
 	uuids, err := st.AllModelUUIDs()
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 	var ids []string
 	for _, uuid := range uuids {
@@ -773,10 +1022,7 @@ func AddUpdateStatusHookSettings(st *State) error {
 			})
 		}
 	}
-	if len(ops) > 0 {
-		return errors.Trace(st.runRawTransaction(ops))
-	}
-	return nil
+	return ops, nil
 }
 
 // AddStorageInstanceConstraints sets the "constraints" field on
@@ -785,16 +1031,46 @@ func AddStorageInstanceConstraints(st *State) error {
 	return runForAllModelStates(st, addStorageInstanceConstraints)
 }
 
+// CountStorageInstancesWithoutConstraints returns the number of
+// documents, across all models, that AddStorageInstanceConstraints
+// would change.
+func CountStorageInstancesWithoutConstraints(st *State) (int, error) {
+	var count int
+	err := runForAllModelStates(st, func(st *State) error {
+		ops, err := addStorageInstanceConstraintsOps(st)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		count += countChangedDocs(ops)
+		return nil
+	})
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return count, nil
+}
+
 func addStorageInstanceConstraints(st *State) error {
-	im, err := st.IAASModel()
+	ops, err := addStorageInstanceConstraintsOps(st)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if len(ops) > 0 {
+		return errors.Trace(st.db().RunTransaction(ops))
+	}
+	return nil
+}
+
+func addStorageInstanceConstraintsOps(st *State) ([]txn.Op, error) {
+	im, err := st.IAASModel()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	storageInstances, err := im.storageInstances(bson.D{
 		{"constraints", bson.D{{"$exists", false}}},
 	})
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 	var ops []txn.Op
 	for _, s := range storageInstances {
@@ -814,7 +1090,7 @@ func addStorageInstanceConstraints(st *State) error {
 			} else if errors.IsNotFound(err) {
 				defaultPool = string(provider.LoopProviderType)
 			} else {
-				return errors.Trace(err)
+				return nil, errors.Trace(err)
 			}
 		case StorageKindFilesystem:
 			f, err := im.storageInstanceFilesystem(s.StorageTag())
@@ -829,7 +1105,7 @@ func addStorageInstanceConstraints(st *State) error {
 			} else if errors.IsNotFound(err) {
 				defaultPool = string(provider.RootfsProviderType)
 			} else {
-				return errors.Trace(err)
+				return nil, errors.Trace(err)
 			}
 		default:
 			// Unknown storage kind, ignore.
@@ -851,12 +1127,12 @@ func addStorageInstanceConstraints(st *State) error {
 				}
 				owner, err := st.FindEntity(ownerTag)
 				if err != nil {
-					return errors.Trace(err)
+					return nil, errors.Trace(err)
 				}
 				if owner, ok := owner.(withStorageConstraints); ok {
 					allCons, err := owner.StorageConstraints()
 					if err != nil {
-						return errors.Trace(err)
+						return nil, errors.Trace(err)
 					}
 					if cons, ok := allCons[s.StorageName()]; ok {
 						siCons.Pool = cons.Pool
@@ -878,10 +1154,7 @@ func addStorageInstanceConstraints(st *State) error {
 			}}},
 		})
 	}
-	if len(ops) > 0 {
-		return errors.Trace(st.db().RunTransaction(ops))
-	}
-	return nil
+	return ops, nil
 }
 
 // SplitLogCollections moves log entries from the old single log collection
@@ -934,6 +1207,13 @@ func SplitLogCollections(st *State) error {
 	return nil
 }
 
+// CountUnsplitLogs returns the number of log entries that
+// SplitLogCollections would move out of the old single log collection.
+func CountUnsplitLogs(st *State) (int, error) {
+	count, err := st.MongoSession().DB(logsDB).C("logs").Count()
+	return count, errors.Trace(err)
+}
+
 func isMgoNamespaceNotFound(err error) bool {
 	// Check for &mgo.QueryError{Code:26, Message:"ns not found"}
 	if qerr, ok := err.(*mgo.QueryError); ok {
@@ -969,6 +1249,27 @@ func (i *relationUnitCountInfo) otherEnd(appName string) (string, error) {
 // relationscopes for applications that shouldn't be there. Fix for
 // https://bugs.launchpad.net/juju/+bug/1699050
 func CorrectRelationUnitCounts(st *State) error {
+	ops, err := correctRelationUnitCountsOps(st)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(ops) > 0 {
+		return errors.Trace(st.runRawTransaction(ops))
+	}
+	return nil
+}
+
+// CountIncorrectRelationUnitCounts returns the number of documents that
+// CorrectRelationUnitCounts would change.
+func CountIncorrectRelationUnitCounts(st *State) (int, error) {
+	ops, err := correctRelationUnitCountsOps(st)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return countChangedDocs(ops), nil
+}
+
+func correctRelationUnitCountsOps(st *State) ([]txn.Op, error) {
 	applicationsColl, aCloser := st.db().GetRawCollection(applicationsC)
 	defer aCloser()
 
@@ -980,11 +1281,11 @@ func CorrectRelationUnitCounts(st *State) error {
 
 	applications, err := collectApplicationInfo(applicationsColl)
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 	relations, err := collectRelationInfo(relationsColl)
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 
 	var ops []txn.Op
@@ -1024,7 +1325,7 @@ func CorrectRelationUnitCounts(st *State) error {
 		unit := keyParts[len(keyParts)-1]
 		subordinate, err := otherEndIsSubordinate(relation, unit, scope.ModelUUID, applications)
 		if err != nil {
-			return errors.Trace(err)
+			return nil, errors.Trace(err)
 		}
 		if subordinate {
 			// The other end for this unit is for a subordinate
@@ -1043,7 +1344,7 @@ func CorrectRelationUnitCounts(st *State) error {
 		})
 	}
 	if err := iter.Close(); err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 
 	// Add in the updated unit counts.
@@ -1056,10 +1357,7 @@ func CorrectRelationUnitCounts(st *State) error {
 			Update: bson.M{"$set": bson.M{"unitcount": relation.unitCount}},
 		})
 	}
-	if len(ops) > 0 {
-		return errors.Trace(st.runRawTransaction(ops))
-	}
-	return nil
+	return ops, nil
 }
 
 func collectApplicationInfo(coll *mgo.Collection) (map[string]bool, error) {
@@ -1171,6 +1469,15 @@ func AddModelEnvironVersion(st *State) error {
 	return st.db().RunTransaction(ops)
 }
 
+// CountModelsWithoutEnvironVersion returns the number of model
+// documents that AddModelEnvironVersion would update.
+func CountModelsWithoutEnvironVersion(st *State) (int, error) {
+	coll, closer := st.db().GetCollection(modelsC)
+	defer closer()
+	count, err := coll.Find(bson.D{{"environ-version", bson.D{{"$exists", false}}}}).Count()
+	return count, errors.Trace(err)
+}
+
 // AddModelType adds a "type" field to model documents which don't
 // have one. The "iaas" type is used.
 func AddModelType(st *State) error {
@@ -1201,6 +1508,15 @@ func AddModelType(st *State) error {
 	return st.db().RunTransaction(ops)
 }
 
+// CountModelsWithoutType returns the number of model documents that
+// AddModelType would update.
+func CountModelsWithoutType(st *State) (int, error) {
+	coll, closer := st.db().GetCollection(modelsC)
+	defer closer()
+	count, err := coll.Find(bson.D{{"type", bson.D{{"$in", []interface{}{nil, ""}}}}}).Count()
+	return count, errors.Trace(err)
+}
+
 // MigrateLeasesToGlobalTime removes old (<2.3-beta2) lease/clock-skew
 // documents, replacing the lease documents with new ones for the
 // existing lease holders.
@@ -1262,3 +1578,13 @@ func migrateModelLeasesToGlobalTime(st *State) error {
 	})
 	return errors.Annotate(err, "upgrading legacy lease documents")
 }
+
+// CountLegacyLeases returns the number of old lease/clock-skew
+// documents, across all models, that MigrateLeasesToGlobalTime would
+// remove.
+func CountLegacyLeases(st *State) (int, error) {
+	coll, closer := st.db().GetRawCollection(leasesC)
+	defer closer()
+	count, err := coll.Find(bson.D{{"type", bson.D{{"$exists", true}}}}).Count()
+	return count, errors.Trace(err)
+}
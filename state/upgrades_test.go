@@ -347,6 +347,27 @@ func (s *upgradesSuite) assertUpgradedData(c *gc.C, upgrade func(*State) error,
 	}
 }
 
+// assertUpgradeCount checks that, before an upgrade, count reports the
+// expected number of documents, and that after the upgrade it reports
+// none.
+func (s *upgradesSuite) assertUpgradeCount(
+	c *gc.C,
+	count func(*State) (int, error),
+	upgrade func(*State) error,
+	expected int,
+	expect ...expectUpgradedData,
+) {
+	n, err := count(s.state)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(n, gc.Equals, expected)
+
+	s.assertUpgradedData(c, upgrade, expect...)
+
+	n, err = count(s.state)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(n, gc.Equals, 0)
+}
+
 func (s *upgradesSuite) TestRenameAddModelPermission(c *gc.C) {
 	coll, closer := s.state.db().GetRawCollection(permissionsC)
 	defer closer()
@@ -390,7 +411,9 @@ func (s *upgradesSuite) TestRenameAddModelPermission(c *gc.C) {
 		"subject-global-key": "mary@external",
 		"access":             "add-model",
 	}}
-	s.assertUpgradedData(c, RenameAddModelPermission, expectUpgradedData{coll, expected})
+	s.assertUpgradeCount(c, CountAddModelPermissions, RenameAddModelPermission, 1,
+		expectUpgradedData{coll, expected},
+	)
 }
 
 func (s *upgradesSuite) TestAddMigrationAttempt(c *gc.C) {
@@ -421,7 +444,9 @@ func (s *upgradesSuite) TestAddMigrationAttempt(c *gc.C) {
 			"attempt": 2,
 		},
 	}
-	s.assertUpgradedData(c, AddMigrationAttempt, expectUpgradedData{coll, expected})
+	s.assertUpgradeCount(c, CountMigrationsWithoutAttempt, AddMigrationAttempt, 2,
+		expectUpgradedData{coll, expected},
+	)
 }
 
 func (s *upgradesSuite) TestAddLocalCharmSequences(c *gc.C) {
@@ -473,8 +498,9 @@ func (s *upgradesSuite) TestAddLocalCharmSequences(c *gc.C) {
 		mkExpected(uuid1, "local:trusty/aaa", 4),
 		mkExpected(uuid1, "local:xenial/bbb", 6),
 	}
-	s.assertUpgradedData(
-		c, AddLocalCharmSequences,
+	// Four sequences to create, and two dead charms to remove.
+	s.assertUpgradeCount(
+		c, CountLocalCharmSequenceChanges, AddLocalCharmSequences, 6,
 		expectUpgradedData{sequences, expected},
 	)
 
@@ -896,7 +922,7 @@ func (s *upgradesSuite) TestRemoveNilValueApplicationSettings(c *gc.C) {
 			},
 		}}
 
-	s.assertUpgradedData(c, RemoveNilValueApplicationSettings,
+	s.assertUpgradeCount(c, CountNilValueApplicationSettings, RemoveNilValueApplicationSettings, 2,
 		expectUpgradedData{settingsColl, expectedSettings},
 	)
 }
@@ -936,7 +962,7 @@ func (s *upgradesSuite) TestAddControllerLogCollectionsSizeSettingsKeepExisting(
 		},
 	}
 
-	s.assertUpgradedData(c, AddControllerLogCollectionsSizeSettings,
+	s.assertUpgradeCount(c, CountMissingControllerLogCollectionsSizeSettings, AddControllerLogCollectionsSizeSettings, 0,
 		expectUpgradedData{settingsColl, expectedSettings},
 	)
 }
@@ -971,7 +997,7 @@ func (s *upgradesSuite) TestAddControllerLogCollectionsSizeSettings(c *gc.C) {
 		},
 	}
 
-	s.assertUpgradedData(c, AddControllerLogCollectionsSizeSettings,
+	s.assertUpgradeCount(c, CountMissingControllerLogCollectionsSizeSettings, AddControllerLogCollectionsSizeSettings, 1,
 		expectUpgradedData{settingsColl, expectedSettings},
 	)
 }
@@ -1065,7 +1091,7 @@ func (s *upgradesSuite) TestAddUpdateStatusHookSettings(c *gc.C) {
 	}
 	sort.Sort(expectedSettings)
 
-	s.assertUpgradedData(c, AddUpdateStatusHookSettings,
+	s.assertUpgradeCount(c, CountMissingUpdateStatusHookSettings, AddUpdateStatusHookSettings, 1,
 		expectUpgradedData{settingsColl, expectedSettings},
 	)
 }
@@ -1331,9 +1357,17 @@ func (s *upgradesSuite) TestSplitLogCollection(c *gc.C) {
 		expected[modelUUID] = append(vals, logRow)
 	}
 
-	err := SplitLogCollections(s.state)
+	count, err := CountUnsplitLogs(s.state)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 15)
+
+	err = SplitLogCollections(s.state)
 	c.Assert(err, jc.ErrorIsNil)
 
+	count, err = CountUnsplitLogs(s.state)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 0)
+
 	// Now check the logs.
 	for _, uuid := range uuids {
 		newLogs := db.C(fmt.Sprintf("logs.%s", uuid))
@@ -1764,6 +1798,10 @@ func (s *upgradesSuite) TestAddModelEnvironVersion(c *gc.C) {
 		"_id":             "deadbeef-0bad-400d-8000-4b1d0d06f00e",
 		"environ-version": 1,
 	}}
+	count, err := CountModelsWithoutEnvironVersion(s.state)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 1)
+
 	s.assertUpgradedData(c, AddModelEnvironVersion,
 		expectUpgradedData{models, expectedModels},
 	)
@@ -1792,6 +1830,10 @@ func (s *upgradesSuite) TestAddModelType(c *gc.C) {
 		"_id":  "deadbeef-0bad-400d-8000-4b1d0d06f00e",
 		"type": "caas",
 	}}
+	count, err := CountModelsWithoutType(s.state)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 1)
+
 	s.assertUpgradedData(c, AddModelType,
 		expectUpgradedData{models, expectedModels})
 }
@@ -1901,6 +1943,10 @@ func (s *upgradesSuite) TestMigrateLeasesToGlobalTime(c *gc.C) {
 		"duration":   int64(time.Minute),
 		"writer":     "ghost",
 	}}
+	count, err := CountLegacyLeases(s.state)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 2)
+
 	s.assertUpgradedData(c, MigrateLeasesToGlobalTime,
 		expectUpgradedData{leases, expectedLeases},
 	)
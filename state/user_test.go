@@ -16,6 +16,7 @@ import (
 
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
 )
@@ -137,6 +138,26 @@ func (s *UserSuite) TestRemoveUserNonExistent(c *gc.C) {
 	c.Assert(errors.IsNotFound(err), jc.IsTrue)
 }
 
+func (s *UserSuite) TestWatchUsers(c *gc.C) {
+	w := s.State.WatchUsers()
+	defer statetesting.AssertStop(c, w)
+
+	// Initial event.
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "harvey"})
+	wc.AssertOneChange()
+
+	err := user.Disable()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.State.RemoveUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
 func isDeletedUserError(err error) bool {
 	_, ok := errors.Cause(err).(state.DeletedUserError)
 	return ok
@@ -1536,6 +1536,34 @@ func (m *Model) Watch() NotifyWatcher {
 	return newEntityWatcher(m.st, modelsC, m.doc.UUID)
 }
 
+// WatchUserAccess returns a NotifyWatcher that triggers whenever any
+// user's access to the model, or to the model's controller, changes.
+func (m *Model) WatchUserAccess() NotifyWatcher {
+	prefixes := []string{
+		modelKey(m.UUID()) + "#",
+		controllerKey(m.ControllerUUID()) + "#",
+	}
+	filter := func(id interface{}) bool {
+		key, ok := id.(string)
+		if !ok {
+			return false
+		}
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	return newNotifyCollWatcher(m.st, permissionsC, filter)
+}
+
+// WatchUsers returns a NotifyWatcher that triggers whenever a user is
+// added, changed, disabled or removed.
+func (st *State) WatchUsers() NotifyWatcher {
+	return newNotifyCollWatcher(st, usersC, nil)
+}
+
 // WatchUpgradeInfo returns a watcher for observing changes to upgrade
 // synchronisation state.
 func (st *State) WatchUpgradeInfo() NotifyWatcher {
@@ -1572,6 +1600,17 @@ func (st *State) WatchAPIHostPorts() NotifyWatcher {
 	return newEntityWatcher(st, controllersC, apiHostPortsKey)
 }
 
+// WatchAPIHostPortsForAgents returns a NotifyWatcher that notifies
+// when the API addresses advertised to the model's agents may have
+// changed: when the set of API addresses changes, or the model's config
+// does.
+func (st *State) WatchAPIHostPortsForAgents() NotifyWatcher {
+	return newDocWatcher(st, []docKey{
+		{controllersC, apiHostPortsKey},
+		{settingsC, st.docID(modelGlobalKey)},
+	})
+}
+
 // WatchStorageAttachment returns a watcher for observing changes
 // to a storage attachment.
 func (im *IAASModel) WatchStorageAttachment(s names.StorageTag, u names.UnitTag) NotifyWatcher {
@@ -17,13 +17,14 @@ import (
 )
 
 const (
-	txnLogWorker          = "txnlog"
-	presenceWorker        = "presence"
-	leadershipWorker      = "leadership"
-	singularWorker        = "singular"
-	allManagerWorker      = "allmanager"
-	allModelManagerWorker = "allmodelmanager"
-	pingBatcherWorker     = "pingbatcher"
+	txnLogWorker                  = "txnlog"
+	presenceWorker                = "presence"
+	leadershipWorker              = "leadership"
+	singularWorker                = "singular"
+	allManagerWorker              = "allmanager"
+	allModelManagerWorker         = "allmodelmanager"
+	modelAnnotationsManagerWorker = "modelannotationsmanager"
+	pingBatcherWorker             = "pingbatcher"
 )
 
 // workers runs the workers that a State instance requires.
@@ -58,7 +59,17 @@ func newWorkers(st *State) (*workers, error) {
 		return presence.NewPingBatcher(st.getPresenceCollection(), pingFlushInterval), nil
 	})
 	ws.StartWorker(leadershipWorker, func() (worker.Worker, error) {
-		manager, err := st.newLeaseManager(st.getLeadershipLeaseClient, leadershipSecretary{}, st.ModelUUID())
+		controllerConfig, err := st.ControllerConfig()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		manager, err := st.newLeaseManager(
+			st.getLeadershipLeaseClient,
+			leadershipSecretary{},
+			st.ModelUUID(),
+			controllerConfig.LeadershipLeaseDuration(),
+			st.recordLeadershipChange,
+		)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -71,6 +82,8 @@ func newWorkers(st *State) (*workers, error) {
 				modelUUID:      st.ModelUUID(),
 			},
 			st.ControllerUUID(),
+			0,
+			nil,
 		)
 		if err != nil {
 			return nil, errors.Trace(err)
@@ -84,17 +97,21 @@ func (st *State) newLeaseManager(
 	getClient func() (corelease.Client, error),
 	secretary lease.Secretary,
 	entityUUID string,
+	minLeaseDuration time.Duration,
+	claimObserver func(leaseName, holderName string),
 ) (worker.Worker, error) {
 	client, err := getClient()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	manager, err := lease.NewManager(lease.ManagerConfig{
-		Secretary:  secretary,
-		Client:     client,
-		Clock:      st.clock(),
-		MaxSleep:   time.Minute,
-		EntityUUID: entityUUID,
+		Secretary:        secretary,
+		Client:           client,
+		Clock:            st.clock(),
+		MaxSleep:         time.Minute,
+		EntityUUID:       entityUUID,
+		MinLeaseDuration: minLeaseDuration,
+		ClaimObserver:    claimObserver,
 	})
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -171,6 +188,20 @@ func (ws *workers) allModelManager(pool *StatePool) *storeManager {
 	return ws.allModelManager(pool)
 }
 
+func (ws *workers) modelAnnotationsManager(pool *StatePool) *storeManager {
+	w, err := ws.Worker(modelAnnotationsManagerWorker, nil)
+	if err == nil {
+		return w.(*storeManager)
+	}
+	if errors.Cause(err) != worker.ErrNotFound {
+		return newDeadStoreManager(errors.Trace(err))
+	}
+	ws.StartWorker(modelAnnotationsManagerWorker, func() (worker.Worker, error) {
+		return newStoreManager(newModelAnnotationsWatcherStateBacking(ws.state, pool)), nil
+	})
+	return ws.modelAnnotationsManager(pool)
+}
+
 // lazyLeaseManager wraps one of workers.singularManager or
 // workers.leadershipManager, and calls it in the method calls.
 // This enables the manager to use restarted lease managers.
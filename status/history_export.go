@@ -0,0 +1,107 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// HistoryFields holds the names of the fields of an exported status
+// history entry, in the order they are written. The names are stable,
+// so that exported history can be analysed offline.
+var HistoryFields = []string{"time", "kind", "status", "message", "data"}
+
+// HistoryRecord is the exported form of a status history entry.
+type HistoryRecord struct {
+	Time    string                 `json:"time" yaml:"time"`
+	Kind    string                 `json:"kind" yaml:"kind"`
+	Status  string                 `json:"status" yaml:"status"`
+	Message string                 `json:"message" yaml:"message"`
+	Data    map[string]interface{} `json:"data,omitempty" yaml:"data,omitempty"`
+}
+
+// Records returns the exported form of the history's entries. Times
+// are written in RFC3339 format, in UTC.
+func (h History) Records() []HistoryRecord {
+	records := make([]HistoryRecord, len(h))
+	for i, s := range h {
+		records[i] = HistoryRecord{
+			Time:    formatHistoryTime(s.Since),
+			Kind:    string(s.Kind),
+			Status:  string(s.Status),
+			Message: s.Info,
+			Data:    s.Data,
+		}
+	}
+	return records
+}
+
+func formatHistoryTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// WriteHistoryJSONLines writes the history to w as JSON lines: one JSON
+// object per entry, each on a line of its own.
+func WriteHistoryJSONLines(w io.Writer, h History) error {
+	encoder := json.NewEncoder(w)
+	for _, record := range h.Records() {
+		if err := encoder.Encode(record); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// WriteHistoryCSV writes the history to w as CSV, under a header row
+// naming HistoryFields. The data of each entry is written as a JSON
+// object, or left empty if the entry has none.
+func WriteHistoryCSV(w io.Writer, h History) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(HistoryFields); err != nil {
+		return errors.Trace(err)
+	}
+	for _, record := range h.Records() {
+		var data string
+		if len(record.Data) > 0 {
+			encoded, err := json.Marshal(record.Data)
+			if err != nil {
+				return errors.Annotatef(err, "cannot encode data for %q status", record.Status)
+			}
+			data = string(encoded)
+		}
+		if err := cw.Write([]string{record.Time, record.Kind, record.Status, record.Message, data}); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	cw.Flush()
+	return errors.Trace(cw.Error())
+}
+
+// WriteHistoryTabular writes the history to w as a table of its
+// entries' time, kind, status and message, under a heading naming
+// them. formatTime formats the time of each entry; if it is nil, times
+// are written as they are by Records.
+func WriteHistoryTabular(w io.Writer, h History, formatTime func(time.Time) string) error {
+	tw := tabwriter.NewWriter(w, 0, 1, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(HistoryFields[:4], "\t")))
+	for _, s := range h {
+		when := formatHistoryTime(s.Since)
+		if formatTime != nil && s.Since != nil {
+			when = formatTime(*s.Since)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", when, s.Kind, s.Status, s.Info)
+	}
+	return errors.Trace(tw.Flush())
+}
@@ -0,0 +1,94 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status_test
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/status"
+)
+
+type historyExportSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&historyExportSuite{})
+
+func exportHistory() status.History {
+	first := time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC)
+	second := first.Add(90 * time.Second)
+	return status.History{{
+		Status: status.Maintenance,
+		Info:   "installing charm software",
+		Since:  &first,
+		Kind:   status.KindWorkload,
+	}, {
+		Status: status.Error,
+		Info:   `hook failed: "install"`,
+		Data:   map[string]interface{}{"hook": "install"},
+		Since:  &second,
+		Kind:   status.KindUnitAgent,
+	}}
+}
+
+func (s *historyExportSuite) TestRecords(c *gc.C) {
+	c.Assert(exportHistory().Records(), jc.DeepEquals, []status.HistoryRecord{{
+		Time:    "2018-03-01T10:00:00Z",
+		Kind:    "workload",
+		Status:  "maintenance",
+		Message: "installing charm software",
+	}, {
+		Time:    "2018-03-01T10:01:30Z",
+		Kind:    "juju-unit",
+		Status:  "error",
+		Message: `hook failed: "install"`,
+		Data:    map[string]interface{}{"hook": "install"},
+	}})
+}
+
+func (s *historyExportSuite) TestWriteHistoryJSONLines(c *gc.C) {
+	var buf bytes.Buffer
+	err := status.WriteHistoryJSONLines(&buf, exportHistory())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, ""+
+		`{"time":"2018-03-01T10:00:00Z","kind":"workload","status":"maintenance","message":"installing charm software"}`+"\n"+
+		`{"time":"2018-03-01T10:01:30Z","kind":"juju-unit","status":"error","message":"hook failed: \"install\"","data":{"hook":"install"}}`+"\n")
+}
+
+func (s *historyExportSuite) TestWriteHistoryCSV(c *gc.C) {
+	var buf bytes.Buffer
+	err := status.WriteHistoryCSV(&buf, exportHistory())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, ""+
+		"time,kind,status,message,data\n"+
+		"2018-03-01T10:00:00Z,workload,maintenance,installing charm software,\n"+
+		`2018-03-01T10:01:30Z,juju-unit,error,"hook failed: ""install""","{""hook"":""install""}"`+"\n")
+}
+
+func (s *historyExportSuite) TestWriteHistoryTabular(c *gc.C) {
+	var buf bytes.Buffer
+	err := status.WriteHistoryTabular(&buf, exportHistory(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, ""+
+		"TIME                  KIND       STATUS       MESSAGE\n"+
+		"2018-03-01T10:00:00Z  workload   maintenance  installing charm software\n"+
+		`2018-03-01T10:01:30Z  juju-unit  error        hook failed: "install"`+"\n")
+}
+
+func (s *historyExportSuite) TestWriteHistoryTabularFormatTime(c *gc.C) {
+	var buf bytes.Buffer
+	formatTime := func(t time.Time) string {
+		return t.Format("15:04:05")
+	}
+	err := status.WriteHistoryTabular(&buf, exportHistory()[:1], formatTime)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, ""+
+		"TIME      KIND      STATUS       MESSAGE\n"+
+		"10:00:00  workload  maintenance  installing charm software\n")
+}
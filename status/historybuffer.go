@@ -0,0 +1,55 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+// HistoryBuffer is a ring buffer holding the most recent status history
+// entries pushed to it, up to a fixed capacity. Pushing an entry to a
+// full buffer evicts the oldest entry rather than moving the others.
+type HistoryBuffer struct {
+	entries []DetailedStatus
+	start   int
+	len     int
+}
+
+// NewHistoryBuffer returns an empty HistoryBuffer that holds at most
+// capacity entries.
+func NewHistoryBuffer(capacity int) *HistoryBuffer {
+	if capacity < 0 {
+		capacity = 0
+	}
+	return &HistoryBuffer{entries: make([]DetailedStatus, capacity)}
+}
+
+// Push adds the entry to the buffer. If the buffer was full, the oldest
+// entry is evicted to make room and returned, along with true. A buffer
+// with no capacity evicts the entry pushed.
+func (b *HistoryBuffer) Push(entry DetailedStatus) (DetailedStatus, bool) {
+	capacity := len(b.entries)
+	if capacity == 0 {
+		return entry, true
+	}
+	if b.len < capacity {
+		b.entries[(b.start+b.len)%capacity] = entry
+		b.len++
+		return DetailedStatus{}, false
+	}
+	evicted := b.entries[b.start]
+	b.entries[b.start] = entry
+	b.start = (b.start + 1) % capacity
+	return evicted, true
+}
+
+// Len returns the number of entries in the buffer.
+func (b *HistoryBuffer) Len() int {
+	return b.len
+}
+
+// Snapshot returns a copy of the entries in the buffer, oldest first.
+func (b *HistoryBuffer) Snapshot() History {
+	result := make(History, b.len)
+	for i := range result {
+		result[i] = b.entries[(b.start+i)%len(b.entries)]
+	}
+	return result
+}
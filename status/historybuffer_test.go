@@ -0,0 +1,57 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/status"
+)
+
+type historyBufferSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&historyBufferSuite{})
+
+func (s *historyBufferSuite) TestPush(c *gc.C) {
+	buffer := status.NewHistoryBuffer(2)
+	c.Assert(buffer.Len(), gc.Equals, 0)
+	c.Assert(buffer.Snapshot(), gc.HasLen, 0)
+
+	_, evicted := buffer.Push(entry("one"))
+	c.Assert(evicted, jc.IsFalse)
+	_, evicted = buffer.Push(entry("two"))
+	c.Assert(evicted, jc.IsFalse)
+	c.Assert(buffer.Len(), gc.Equals, 2)
+	c.Assert(buffer.Snapshot(), jc.DeepEquals, status.History{entry("one"), entry("two")})
+
+	old, evicted := buffer.Push(entry("three"))
+	c.Assert(evicted, jc.IsTrue)
+	c.Assert(old, jc.DeepEquals, entry("one"))
+	old, evicted = buffer.Push(entry("four"))
+	c.Assert(evicted, jc.IsTrue)
+	c.Assert(old, jc.DeepEquals, entry("two"))
+	c.Assert(buffer.Len(), gc.Equals, 2)
+	c.Assert(buffer.Snapshot(), jc.DeepEquals, status.History{entry("three"), entry("four")})
+}
+
+func (s *historyBufferSuite) TestSnapshotIsCopy(c *gc.C) {
+	buffer := status.NewHistoryBuffer(1)
+	buffer.Push(entry("one"))
+	snapshot := buffer.Snapshot()
+	buffer.Push(entry("two"))
+	c.Assert(snapshot, jc.DeepEquals, status.History{entry("one")})
+}
+
+func (s *historyBufferSuite) TestNoCapacity(c *gc.C) {
+	buffer := status.NewHistoryBuffer(0)
+	old, evicted := buffer.Push(entry("one"))
+	c.Assert(evicted, jc.IsTrue)
+	c.Assert(old, jc.DeepEquals, entry("one"))
+	c.Assert(buffer.Len(), gc.Equals, 0)
+	c.Assert(buffer.Snapshot(), gc.HasLen, 0)
+}
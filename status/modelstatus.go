@@ -0,0 +1,107 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// rollupRank orders the statuses a model's status is rolled up to. When
+// entities call for different statuses, the one ranked highest wins.
+var rollupRank = map[Status]int{
+	Available: 0,
+	Busy:      1,
+	Blocked:   2,
+	Error:     3,
+}
+
+// applicationRollup maps application statuses to the model statuses they
+// call for. Statuses not listed leave the model available.
+var applicationRollup = map[Status]Status{
+	Error:       Error,
+	Blocked:     Blocked,
+	Waiting:     Busy,
+	Maintenance: Busy,
+}
+
+// machineRollup maps machine agent and instance statuses to the model
+// statuses they call for. Statuses not listed leave the model available.
+var machineRollup = map[Status]Status{
+	Error:             Error,
+	Down:              Error,
+	ProvisioningError: Error,
+	Pending:           Busy,
+	Allocating:        Busy,
+	Rebooting:         Busy,
+}
+
+// DeriveModelStatus returns the status of a model rolled up from the
+// statuses of its applications and the agent statuses of its machines,
+// each keyed by name. The model is:
+//
+//   - error, if any application is in error, or any machine is in error,
+//     down or failed to provision;
+//   - blocked, failing that, if any application is blocked;
+//   - busy, failing that, if any application is waiting or in
+//     maintenance, or any machine is pending, allocating or rebooting;
+//   - available otherwise.
+//
+// The message describes the entity responsible for the status, with
+// applications taking precedence over machines and each ordered by
+// name, so that the same statuses always give the same result. Since is
+// the latest time any of the responsible entities changed status.
+func DeriveModelStatus(applications, machines map[string]StatusInfo) StatusInfo {
+	result := StatusInfo{Status: Available}
+	var culprits []string
+	consider := func(kind string, entities map[string]StatusInfo, rollup map[Status]Status) {
+		names := make([]string, 0, len(entities))
+		for name := range entities {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			info := entities[name]
+			derived, ok := rollup[info.Status]
+			if !ok || rollupRank[derived] < rollupRank[result.Status] {
+				continue
+			}
+			if rollupRank[derived] > rollupRank[result.Status] {
+				result = StatusInfo{
+					Status:  derived,
+					Message: describeCulprit(kind, name, info),
+					Since:   info.Since,
+				}
+				culprits = nil
+			}
+			culprits = append(culprits, name)
+			result.Since = latest(result.Since, info.Since)
+		}
+	}
+	consider("application", applications, applicationRollup)
+	consider("machine", machines, machineRollup)
+	if n := len(culprits); n > 1 {
+		result.Message += fmt.Sprintf(" (and %d more)", n-1)
+	}
+	return result
+}
+
+// describeCulprit returns a message describing an entity responsible for
+// a model's status.
+func describeCulprit(kind, name string, info StatusInfo) string {
+	message := fmt.Sprintf("%s %q is %s", kind, name, info.Status)
+	if info.Message != "" {
+		message += ": " + info.Message
+	}
+	return message
+}
+
+// latest returns the later of the times, either of which may be nil.
+func latest(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.After(*a)) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,127 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/status"
+)
+
+type modelStatusSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&modelStatusSuite{})
+
+func (s *modelStatusSuite) TestDeriveModelStatusEmpty(c *gc.C) {
+	c.Assert(status.DeriveModelStatus(nil, nil), jc.DeepEquals, status.StatusInfo{
+		Status: status.Available,
+	})
+}
+
+func (s *modelStatusSuite) TestDeriveModelStatusAvailable(c *gc.C) {
+	result := status.DeriveModelStatus(
+		map[string]status.StatusInfo{
+			"mysql":     {Status: status.Active},
+			"wordpress": {Status: status.Unknown},
+		},
+		map[string]status.StatusInfo{
+			"0": {Status: status.Started},
+		},
+	)
+	c.Assert(result, jc.DeepEquals, status.StatusInfo{Status: status.Available})
+}
+
+func (s *modelStatusSuite) TestDeriveModelStatusPrecedence(c *gc.C) {
+	for i, test := range []struct {
+		about        string
+		applications map[string]status.StatusInfo
+		machines     map[string]status.StatusInfo
+		expected     status.Status
+		message      string
+	}{{
+		about:        "waiting application",
+		applications: map[string]status.StatusInfo{"mysql": {Status: status.Waiting}},
+		expected:     status.Busy,
+		message:      `application "mysql" is waiting`,
+	}, {
+		about:    "allocating machine",
+		machines: map[string]status.StatusInfo{"0": {Status: status.Allocating}},
+		expected: status.Busy,
+		message:  `machine "0" is allocating`,
+	}, {
+		about: "blocked beats busy",
+		applications: map[string]status.StatusInfo{
+			"a": {Status: status.Maintenance},
+			"b": {Status: status.Blocked, Message: "needs a relation"},
+		},
+		machines: map[string]status.StatusInfo{"0": {Status: status.Pending}},
+		expected: status.Blocked,
+		message:  `application "b" is blocked: needs a relation`,
+	}, {
+		about: "error beats blocked",
+		applications: map[string]status.StatusInfo{
+			"a": {Status: status.Blocked},
+		},
+		machines: map[string]status.StatusInfo{"0": {Status: status.Down, Message: "agent is not communicating"}},
+		expected: status.Error,
+		message:  `machine "0" is down: agent is not communicating`,
+	}, {
+		about:    "provisioning error",
+		machines: map[string]status.StatusInfo{"3": {Status: status.ProvisioningError}},
+		expected: status.Error,
+		message:  `machine "3" is provisioning error`,
+	}, {
+		about: "applications before machines, then by name",
+		applications: map[string]status.StatusInfo{
+			"zookeeper": {Status: status.Error, Message: "hook failed"},
+			"kafka":     {Status: status.Error, Message: "hook failed"},
+		},
+		machines: map[string]status.StatusInfo{
+			"0": {Status: status.Error},
+			"1": {Status: status.Pending},
+		},
+		expected: status.Error,
+		message:  `application "kafka" is error: hook failed (and 2 more)`,
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		result := status.DeriveModelStatus(test.applications, test.machines)
+		c.Check(result.Status, gc.Equals, test.expected)
+		c.Check(result.Message, gc.Equals, test.message)
+	}
+}
+
+func (s *modelStatusSuite) TestDeriveModelStatusDeterministic(c *gc.C) {
+	applications := make(map[string]status.StatusInfo)
+	for _, name := range []string{"e", "d", "c", "b", "a"} {
+		applications[name] = status.StatusInfo{Status: status.Blocked, Message: name}
+	}
+	expected := status.DeriveModelStatus(applications, nil)
+	c.Assert(expected.Message, gc.Equals, `application "a" is blocked: a (and 4 more)`)
+	for i := 0; i < 10; i++ {
+		c.Assert(status.DeriveModelStatus(applications, nil), jc.DeepEquals, expected)
+	}
+}
+
+func (s *modelStatusSuite) TestDeriveModelStatusSince(c *gc.C) {
+	earlier := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+	result := status.DeriveModelStatus(
+		map[string]status.StatusInfo{
+			"a": {Status: status.Error, Since: &earlier},
+			"b": {Status: status.Error, Since: &later},
+			"c": {Status: status.Waiting, Since: &later},
+		},
+		map[string]status.StatusInfo{
+			"0": {Status: status.Error},
+		},
+	)
+	c.Assert(result.Status, gc.Equals, status.Error)
+	c.Assert(result.Since, gc.Equals, &later)
+}
@@ -0,0 +1,109 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// MaxRepeatCycle is the length of the longest cycle of status history
+// entries that is folded into a repeat count as it is written; a
+// status flapping between two or three values is a cycle of that
+// length, while an entry set again and again is a cycle of one.
+const MaxRepeatCycle = 3
+
+// Repetition records how the entries at the head of a status history
+// have been repeated since they were written. Entries that repeat a
+// cycle are counted rather than stored.
+type Repetition struct {
+	// Cycle is the number of entries, ending with the most recent,
+	// that are being repeated. It is zero if they are not.
+	Cycle int
+
+	// Pending is the number of entries of the next repetition of
+	// the cycle that have been seen so far.
+	Pending int
+
+	// Count is the number of complete repetitions of the cycle.
+	Count int
+}
+
+// SameEntry reports whether two status history entries have the same
+// status, message and data.
+func SameEntry(a, b DetailedStatus) bool {
+	if a.Status != b.Status || a.Info != b.Info {
+		return false
+	}
+	// Check the data last as the short circuit evaluation may mean
+	// we rarely need to drop down into the reflect library.
+	if len(a.Data) == 0 || len(b.Data) == 0 {
+		return len(a.Data) == len(b.Data)
+	}
+	return reflect.DeepEqual(a.Data, b.Data)
+}
+
+// FoldRepeat works out whether next, about to be written to a status
+// history, repeats its recent entries. The recent entries are given
+// most recent first, along with the repetition recorded against the
+// most recent of them.
+//
+// If next continues or starts a repeating cycle, folded is true and
+// next should not be written; instead rep becomes the repetition of
+// the most recent entry. Otherwise next should be written, after the
+// flush entries, which are those of an incomplete repetition that next
+// has broken, in the order they were seen; the most recent entry's
+// repetition becomes rep, and next starts with none.
+func FoldRepeat(recent History, current Repetition, next DetailedStatus) (rep Repetition, flush History, folded bool) {
+	if current.Cycle > 0 && current.Cycle <= len(recent) {
+		expected := recent[current.Cycle-1-current.Pending]
+		if SameEntry(expected, next) {
+			current.Pending++
+			if current.Pending == current.Cycle {
+				current.Count++
+				current.Pending = 0
+			}
+			return current, nil, true
+		}
+		for i := 0; i < current.Pending; i++ {
+			flush = append(flush, recent[current.Cycle-1-i])
+		}
+		current.Pending = 0
+		if current.Count == 0 {
+			current = Repetition{}
+		}
+		return current, flush, false
+	}
+	if current.Count > 0 {
+		// A completed repetition is never restarted.
+		return current, nil, false
+	}
+	for cycle := 1; cycle <= MaxRepeatCycle && cycle <= len(recent); cycle++ {
+		if SameEntry(recent[cycle-1], next) {
+			rep := Repetition{Cycle: cycle, Pending: 1}
+			if cycle == 1 {
+				rep = Repetition{Cycle: 1, Count: 1}
+			}
+			return rep, nil, true
+		}
+	}
+	return current, nil, false
+}
+
+// RepeatedEntry returns the entry shown in a status history, after
+// the entries of a cycle, for the repetitions of the cycle that were
+// folded when written. Since is the time of the last repetition.
+func RepeatedEntry(rep Repetition, since *time.Time) DetailedStatus {
+	return DetailedStatus{
+		Status: Idle,
+		Info:   repeatMessage(rep.Cycle, rep.Count),
+		Since:  since,
+	}
+}
+
+// repeatMessage describes count repetitions of the last cycle entries.
+func repeatMessage(cycle, count int) string {
+	return fmt.Sprintf("last %d statuses repeated %d times", cycle, count)
+}
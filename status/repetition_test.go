@@ -0,0 +1,130 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/status"
+)
+
+type repetitionSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&repetitionSuite{})
+
+func entry(info string) status.DetailedStatus {
+	return status.DetailedStatus{Status: status.Active, Info: info}
+}
+
+func (s *repetitionSuite) TestSameEntry(c *gc.C) {
+	a := entry("one")
+	c.Check(status.SameEntry(a, entry("one")), jc.IsTrue)
+	c.Check(status.SameEntry(a, entry("two")), jc.IsFalse)
+
+	b := entry("one")
+	b.Status = status.Blocked
+	c.Check(status.SameEntry(a, b), jc.IsFalse)
+
+	b = entry("one")
+	b.Data = map[string]interface{}{}
+	c.Check(status.SameEntry(a, b), jc.IsTrue)
+	b.Data = map[string]interface{}{"foo": "bar"}
+	c.Check(status.SameEntry(a, b), jc.IsFalse)
+	a.Data = map[string]interface{}{"foo": "bar"}
+	c.Check(status.SameEntry(a, b), jc.IsTrue)
+}
+
+func (s *repetitionSuite) TestNoRepeat(c *gc.C) {
+	recent := status.History{entry("two"), entry("one")}
+	rep, flush, folded := status.FoldRepeat(recent, status.Repetition{}, entry("three"))
+	c.Assert(folded, jc.IsFalse)
+	c.Assert(flush, gc.HasLen, 0)
+	c.Assert(rep, gc.Equals, status.Repetition{})
+}
+
+func (s *repetitionSuite) TestSameEntryRepeated(c *gc.C) {
+	recent := status.History{entry("one")}
+	rep, flush, folded := status.FoldRepeat(recent, status.Repetition{}, entry("one"))
+	c.Assert(folded, jc.IsTrue)
+	c.Assert(flush, gc.HasLen, 0)
+	c.Assert(rep, gc.Equals, status.Repetition{Cycle: 1, Count: 1})
+
+	rep, flush, folded = status.FoldRepeat(recent, rep, entry("one"))
+	c.Assert(folded, jc.IsTrue)
+	c.Assert(flush, gc.HasLen, 0)
+	c.Assert(rep, gc.Equals, status.Repetition{Cycle: 1, Count: 2})
+}
+
+func (s *repetitionSuite) TestCycleRepeated(c *gc.C) {
+	recent := status.History{entry("down"), entry("up")}
+	rep, _, folded := status.FoldRepeat(recent, status.Repetition{}, entry("up"))
+	c.Assert(folded, jc.IsTrue)
+	c.Assert(rep, gc.Equals, status.Repetition{Cycle: 2, Pending: 1})
+
+	rep, _, folded = status.FoldRepeat(recent, rep, entry("down"))
+	c.Assert(folded, jc.IsTrue)
+	c.Assert(rep, gc.Equals, status.Repetition{Cycle: 2, Count: 1})
+
+	rep, _, folded = status.FoldRepeat(recent, rep, entry("up"))
+	c.Assert(folded, jc.IsTrue)
+	c.Assert(rep, gc.Equals, status.Repetition{Cycle: 2, Pending: 1, Count: 1})
+}
+
+func (s *repetitionSuite) TestLongestCycle(c *gc.C) {
+	recent := status.History{entry("four"), entry("three"), entry("two"), entry("one")}
+	_, _, folded := status.FoldRepeat(recent, status.Repetition{}, entry("one"))
+	c.Assert(folded, jc.IsFalse)
+
+	rep, _, folded := status.FoldRepeat(recent[:3], status.Repetition{}, entry("two"))
+	c.Assert(folded, jc.IsTrue)
+	c.Assert(rep, gc.Equals, status.Repetition{Cycle: 3, Pending: 1})
+}
+
+func (s *repetitionSuite) TestBrokenRepetitionFlushed(c *gc.C) {
+	recent := status.History{entry("three"), entry("two"), entry("one")}
+	current := status.Repetition{Cycle: 3, Pending: 2, Count: 1}
+	rep, flush, folded := status.FoldRepeat(recent, current, entry("other"))
+	c.Assert(folded, jc.IsFalse)
+	c.Assert(flush, jc.DeepEquals, status.History{entry("one"), entry("two")})
+	c.Assert(rep, gc.Equals, status.Repetition{Cycle: 3, Count: 1})
+}
+
+func (s *repetitionSuite) TestIncompleteRepetitionDropped(c *gc.C) {
+	recent := status.History{entry("down"), entry("up")}
+	current := status.Repetition{Cycle: 2, Pending: 1}
+	rep, flush, folded := status.FoldRepeat(recent, current, entry("gone"))
+	c.Assert(folded, jc.IsFalse)
+	c.Assert(flush, jc.DeepEquals, status.History{entry("up")})
+	c.Assert(rep, gc.Equals, status.Repetition{})
+}
+
+func (s *repetitionSuite) TestCompletedRepetitionNotRestarted(c *gc.C) {
+	recent := status.History{entry("down"), entry("up")}
+	current := status.Repetition{Cycle: 2, Count: 3}
+	rep, flush, folded := status.FoldRepeat(recent, current, entry("gone"))
+	c.Assert(folded, jc.IsFalse)
+	c.Assert(flush, gc.HasLen, 0)
+	c.Assert(rep, gc.Equals, current)
+
+	// The next entry starts a fresh history, so the repetition
+	// recorded against the previous entry stays as it is.
+	recent = status.History{entry("gone"), entry("down"), entry("up")}
+	rep, _, folded = status.FoldRepeat(recent, status.Repetition{}, entry("down"))
+	c.Assert(folded, jc.IsTrue)
+	c.Assert(rep, gc.Equals, status.Repetition{Cycle: 2, Pending: 1})
+}
+
+func (s *repetitionSuite) TestRepeatedEntry(c *gc.C) {
+	since := time.Now()
+	repeated := status.RepeatedEntry(status.Repetition{Cycle: 2, Count: 5}, &since)
+	c.Assert(repeated.Status, gc.Equals, status.Idle)
+	c.Assert(repeated.Info, gc.Equals, "last 2 statuses repeated 5 times")
+	c.Assert(repeated.Since, gc.Equals, &since)
+}
@@ -12,7 +12,8 @@ import (
 // very clear reason.
 //
 // Status values currently apply to machine (agents), unit (agents), unit
-// (workloads), service (workloads), volumes, filesystems, and models.
+// (workloads), service (workloads), volumes, filesystems, models and
+// relations.
 type Status string
 
 // String returns a string representation of the Status.
@@ -38,6 +39,12 @@ type StatusGetter interface {
 	Status() (StatusInfo, error)
 }
 
+// RelationStatusSetter represents a type which can set the status of the
+// relations it takes part in, identified by relation id.
+type RelationStatusSetter interface {
+	SetRelationStatus(relationId int, info StatusInfo) error
+}
+
 // InstanceStatusGetter represents a type whose instance status can be read.
 type InstanceStatusGetter interface {
 	InstanceStatus() (StatusInfo, error)
@@ -312,6 +319,23 @@ func ValidModelStatus(status Status) bool {
 	}
 }
 
+// ValidRelationStatus returns true if status has a valid value (that is to
+// say, a value that it's OK to set) for relations.
+func ValidRelationStatus(status Status) bool {
+	switch status {
+	case
+		Joining,
+		Joined,
+		Suspending,
+		Suspended,
+		Broken,
+		Error:
+		return true
+	default:
+		return false
+	}
+}
+
 // Matches returns true if the candidate matches status,
 // taking into account that the candidate may be a legacy
 // status value which has been deprecated.
@@ -4,7 +4,7 @@
 package status
 
 import (
-	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/juju/errors"
@@ -22,9 +22,23 @@ type StatusHistoryFilter struct {
 	// Exclude indicates the status messages that should be excluded
 	// from the returned result.
 	Exclude set.Strings
+	// IncludeStatus, if not empty, restricts the returned result to
+	// entries with one of these status values.
+	IncludeStatus set.Strings
+	// ExcludeStatus indicates the status values whose entries should
+	// be excluded from the returned result.
+	ExcludeStatus set.Strings
+	// MessageRegex, if not empty, restricts the returned result to
+	// entries whose message matches this regular expression.
+	MessageRegex string
+	// Squash, if not nil, asks for repeated entries in the returned
+	// result to be squashed, as History.Squash does.
+	Squash *SquashParams
 }
 
 // Validate checks that the minimum requirements of a StatusHistoryFilter are met.
+// Size may be combined with either FromDate or Delta, to ask for at most
+// Size of the entries in that time.
 func (f *StatusHistoryFilter) Validate() error {
 	s := f.Size > 0
 	t := f.FromDate != nil
@@ -33,12 +47,20 @@ func (f *StatusHistoryFilter) Validate() error {
 	switch {
 	case !(s || t || d):
 		return errors.NotValidf("missing filter parameters")
-	case s && t:
-		return errors.NotValidf("Size and Date together")
-	case s && d:
-		return errors.NotValidf("Size and Delta together")
 	case t && d:
 		return errors.NotValidf("Date and Delta together")
+	case !f.IncludeStatus.IsEmpty() && !f.ExcludeStatus.IsEmpty():
+		return errors.NotValidf("IncludeStatus and ExcludeStatus together")
+	}
+	if f.MessageRegex != "" {
+		if _, err := regexp.Compile(f.MessageRegex); err != nil {
+			return errors.NewNotValid(err, "MessageRegex")
+		}
+	}
+	if f.Squash != nil {
+		if err := f.Squash.Validate(); err != nil {
+			return errors.Trace(err)
+		}
 	}
 	return nil
 }
@@ -48,6 +70,24 @@ type StatusHistoryGetter interface {
 	StatusHistory(filter StatusHistoryFilter) ([]StatusInfo, error)
 }
 
+// StatusHistoryPage holds a page of status history entries, most
+// recent first.
+type StatusHistoryPage struct {
+	Statuses History
+	// NextToken identifies the page following this one. It is empty
+	// if there are no more entries.
+	NextToken string
+}
+
+// StatusHistoryPager instances can fetch their status history a page at
+// a time, so that long histories need not be held in memory at once.
+type StatusHistoryPager interface {
+	// StatusHistoryPage returns at most pageSize entries of the given
+	// kind of status history, starting with the most recent, or with
+	// the page identified by token if it is not empty.
+	StatusHistoryPage(kind HistoryKind, filter StatusHistoryFilter, pageSize int, token string) (StatusHistoryPage, error)
+}
+
 // InstanceStatusHistoryGetter instances can fetch their instance status history.
 type InstanceStatusHistoryGetter interface {
 	InstanceStatusHistory(filter StatusHistoryFilter) ([]StatusInfo, error)
@@ -69,31 +109,35 @@ type DetailedStatus struct {
 // History holds many DetailedStatus,
 type History []DetailedStatus
 
-// push will take an input DetailedStatus and put it as element
-// 0 of the current history pushing all other elements by 1
-// and return the last element, now out of the slice.
-func (h *History) push(new DetailedStatus) DetailedStatus {
-	ch := *h
-	old := ch[0]
-	for i := 0; i < len(ch)-1; i++ {
-		ch[i] = ch[i+1]
+// SquashLogs finds runs of status history entries that repeat a cycle
+// of entries, for each of the given cycle sizes in turn, and replaces
+// each run with one appearance of the cycle followed by an entry
+// recording the number of repetitions.
+func (h *History) SquashLogs(cycleSizes ...int) History {
+	result := *h
+	for _, cycleSize := range cycleSizes {
+		result = squashCycles(result, cycleSize)
 	}
-	ch[len(ch)-1] = new
-	return old
+	return result
 }
 
-// SquashLogs will find repetitions of N consequent status log entries into just
-// one appearance of them and information about repetition.
-func (h *History) SquashLogs(cycleSize int) History {
-	statuses := *h
-	if len(statuses) <= cycleSize {
+// squashCycles squashes the runs of statuses that repeat a cycle of
+// cycleSize entries.
+func squashCycles(statuses History, cycleSize int) History {
+	if cycleSize < 1 || len(statuses) <= cycleSize {
 		return statuses
 	}
-	buffer := History{}
+	buffer := NewHistoryBuffer(cycleSize)
 	for i := 0; i < cycleSize; i++ {
-		buffer = append(buffer, statuses[i])
+		buffer.Push(statuses[i])
 	}
 	result := []DetailedStatus{}
+	// push adds the entry to the cycle, and the entry it evicts to
+	// the result.
+	push := func(entry DetailedStatus) {
+		evicted, _ := buffer.Push(entry)
+		result = append(result, evicted)
+	}
 	// TODO(perrito666) 2016-05-02 lp:1558657
 	now := time.Now()
 	var repeat int
@@ -104,48 +148,100 @@ func (h *History) SquashLogs(cycleSize int) History {
 		Since:  &now,
 	}
 
-	for i = cycleSize; i < len(statuses); {
-		if i+cycleSize > len(statuses) {
-			break
-		}
+	for i = cycleSize; i+cycleSize <= len(statuses); {
 		subset := statuses[i : i+cycleSize]
-		repetition := true
-		for j := range subset {
-			if subset[j].Status != buffer[j].Status || subset[j].Info != buffer[j].Info {
-				repetition = false
-			}
-		}
-		if repetition {
+		if repeatsCycle(subset, buffer) {
 			repeat++
 			i = i + cycleSize
 			continue
 		}
 		if repeat > 0 {
-			rstatus := fmt.Sprintf("last %d statuses repeated %d times", cycleSize, repeat)
+			repeatStatus.Info = repeatMessage(cycleSize, repeat)
 			repeat = 0
-			repeatStatus.Info = rstatus
 			for j := 0; j < cycleSize; j++ {
-				result = append(result, buffer.push(subset[j]))
+				push(subset[j])
 			}
 			result = append(result, repeatStatus)
 			i = i + cycleSize
 			continue
 		}
-		result = append(result, buffer.push(statuses[i]))
+		push(statuses[i])
 		i++
 	}
-	for j := 0; j < cycleSize; j++ {
-		result = append(result, buffer[j])
-	}
+	result = append(result, buffer.Snapshot()...)
 	if repeat > 0 {
-		rstatus := fmt.Sprintf("last %d statuses repeated %d times", cycleSize, repeat)
-		repeatStatus.Info = rstatus
+		repeatStatus.Info = repeatMessage(cycleSize, repeat)
 		result = append(result, repeatStatus)
 	}
-	if i < len(statuses)-1 {
-		result = append(result, statuses[i+1:]...)
+	if i < len(statuses) {
+		result = append(result, statuses[i:]...)
+	}
+	return result
+}
+
+// repeatsCycle reports whether the entries have the same statuses and
+// messages as those of the cycle, in order.
+func repeatsCycle(entries History, cycle *HistoryBuffer) bool {
+	for j, entry := range cycle.Snapshot() {
+		if entries[j].Status != entry.Status || entries[j].Info != entry.Info {
+			return false
+		}
+	}
+	return true
+}
+
+// MaxSquashCycleSize is the length of the longest cycle of repeated
+// status history entries that can be squashed.
+const MaxSquashCycleSize = 10
+
+// DefaultSquashCycleSizes holds the sizes of the cycles of repeated
+// entries that are squashed when no others are given.
+var DefaultSquashCycleSizes = []int{1, 2, 3}
+
+// SquashParams holds the parameters for squashing repeated status
+// history entries.
+type SquashParams struct {
+	// CycleSizes holds the sizes of the cycles of repeated entries
+	// to squash, in the order they are tried. If it is empty,
+	// DefaultSquashCycleSizes is used.
+	CycleSizes []int
+	// MaxLength, if positive, limits the squashed history to its
+	// last MaxLength entries.
+	MaxLength int
+}
+
+// Validate checks that the cycle sizes and maximum length are usable.
+func (p *SquashParams) Validate() error {
+	for _, size := range p.CycleSizes {
+		if size < 1 || size > MaxSquashCycleSize {
+			return errors.NotValidf("squash cycle size %d (must be between 1 and %d)", size, MaxSquashCycleSize)
+		}
+	}
+	if p.MaxLength < 0 {
+		return errors.NotValidf("negative squash max length %d", p.MaxLength)
 	}
+	return nil
+}
 
+// Squash squashes the repeated entries in the history, oldest first,
+// with SquashLogs for each of the cycle sizes in turn, and then drops
+// all but the last MaxLength entries. The entries that report the
+// repetitions take the kind of the entry before them.
+func (h History) Squash(p SquashParams) History {
+	sizes := p.CycleSizes
+	if len(sizes) == 0 {
+		sizes = DefaultSquashCycleSizes
+	}
+	result := append(History(nil), h...)
+	result = result.SquashLogs(sizes...)
+	for i := 1; i < len(result); i++ {
+		if result[i].Kind == "" {
+			result[i].Kind = result[i-1].Kind
+		}
+	}
+	if p.MaxLength > 0 && len(result) > p.MaxLength {
+		result = result[len(result)-p.MaxLength:]
+	}
 	return result
 }
 
@@ -174,6 +270,10 @@ const (
 	KindContainerInstance HistoryKind = "container"
 	// KindContainer represents an entry for a container agent.
 	KindContainer HistoryKind = "juju-container"
+	// KindApplication represents an entry for an application.
+	KindApplication HistoryKind = "application"
+	// KindRelation represents an entry for a relation.
+	KindRelation HistoryKind = "relation"
 )
 
 // String returns a string representation of the HistoryKind.
@@ -186,7 +286,8 @@ func (k HistoryKind) Valid() bool {
 	switch k {
 	case KindUnit, KindUnitAgent, KindWorkload,
 		KindMachineInstance, KindMachine,
-		KindContainerInstance, KindContainer:
+		KindContainerInstance, KindContainer,
+		KindApplication, KindRelation:
 		return true
 	}
 	return false
@@ -202,5 +303,7 @@ func AllHistoryKind() map[HistoryKind]string {
 		KindMachine:           "status of the agent that is managing a machine",
 		KindContainerInstance: "statuses from the agent that is managing containers",
 		KindContainer:         "statuses from the containers only and not their host machines",
+		KindApplication:       "statuses set for an application by its leader",
+		KindRelation:          "statuses of a relation between applications",
 	}
 }
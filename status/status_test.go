@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/set"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/status"
@@ -106,3 +108,120 @@ func (h *statusHistorySuite) TestStatusSquashing(c *gc.C) {
 
 	c.Assert(newStatuses, gc.DeepEquals, expectedStatuses)
 }
+
+func (h *statusHistorySuite) TestStatusSquashingKeepsTrailingEntries(c *gc.C) {
+	statuses := status.History{
+		{Status: status.Active, Info: "one"},
+		{Status: status.Idle, Info: "two"},
+		{Status: status.Idle, Info: "last 1 statuses repeated 3 times"},
+	}
+	c.Assert(statuses.SquashLogs(2), jc.DeepEquals, statuses)
+}
+
+func (h *statusHistorySuite) TestStatusSquashingCycleSizes(c *gc.C) {
+	var statuses status.History
+	for _, info := range []string{"start", "a", "b", "c", "a", "b", "c", "x", "x", "x", "end"} {
+		statuses = append(statuses, status.DetailedStatus{Status: status.Active, Info: info})
+	}
+	squashed := statuses.SquashLogs(1, 3)
+	infos := make([]string, len(squashed))
+	for i, s := range squashed {
+		infos[i] = s.Info
+	}
+	c.Assert(infos, jc.DeepEquals, []string{
+		"start",
+		"a", "b", "c",
+		"last 3 statuses repeated 1 times",
+		"x",
+		"last 1 statuses repeated 2 times",
+		"end",
+	})
+}
+
+func (h *statusHistorySuite) TestStatusSquashingInvalidCycleSize(c *gc.C) {
+	statuses := status.History{{Info: "one"}, {Info: "one"}}
+	c.Assert(statuses.SquashLogs(0), jc.DeepEquals, statuses)
+}
+
+func (h *statusHistorySuite) TestSquash(c *gc.C) {
+	statuses := status.History{
+		{Status: status.Active, Info: "one", Kind: status.KindWorkload},
+		{Status: status.Idle, Kind: status.KindUnitAgent},
+		{Status: status.Idle, Kind: status.KindUnitAgent},
+		{Status: status.Idle, Kind: status.KindUnitAgent},
+		{Status: status.Idle, Kind: status.KindUnitAgent},
+	}
+	squashed := statuses.Squash(status.SquashParams{})
+	c.Assert(squashed, gc.HasLen, 3)
+	c.Assert(squashed[0].Info, gc.Equals, "one")
+	c.Assert(squashed[1].Status, gc.Equals, status.Idle)
+	c.Assert(squashed[2].Info, gc.Equals, "last 1 statuses repeated 3 times")
+	c.Assert(squashed[2].Kind, gc.Equals, status.KindUnitAgent)
+
+	squashed = statuses.Squash(status.SquashParams{CycleSizes: []int{2}, MaxLength: 2})
+	c.Assert(squashed, gc.HasLen, 2)
+	c.Assert(squashed[0].Status, gc.Equals, status.Idle)
+	c.Assert(squashed[1].Info, gc.Equals, "last 2 statuses repeated 1 times")
+
+	// The history squashed is left as it was.
+	c.Assert(statuses, gc.HasLen, 5)
+	c.Assert(statuses[4].Kind, gc.Equals, status.KindUnitAgent)
+}
+
+func (h *statusHistorySuite) TestSquashParamsValidate(c *gc.C) {
+	params := status.SquashParams{CycleSizes: []int{1, 10}, MaxLength: 5}
+	c.Assert(params.Validate(), jc.ErrorIsNil)
+
+	params.CycleSizes = []int{0}
+	c.Assert(params.Validate(), gc.ErrorMatches, `squash cycle size 0 \(must be between 1 and 10\) not valid`)
+
+	params.CycleSizes = nil
+	params.MaxLength = -1
+	c.Assert(params.Validate(), gc.ErrorMatches, "negative squash max length -1 not valid")
+
+	filter := status.StatusHistoryFilter{Size: 10, Squash: &params}
+	c.Assert(filter.Validate(), gc.ErrorMatches, "negative squash max length -1 not valid")
+}
+
+func (h *statusHistorySuite) TestFilterValidateStatus(c *gc.C) {
+	filter := status.StatusHistoryFilter{
+		Size:          10,
+		IncludeStatus: set.NewStrings("error", "blocked"),
+		MessageRegex:  "^hook failed",
+	}
+	c.Assert(filter.Validate(), jc.ErrorIsNil)
+
+	filter.ExcludeStatus = set.NewStrings("idle")
+	c.Assert(filter.Validate(), gc.ErrorMatches, "IncludeStatus and ExcludeStatus together not valid")
+
+	filter.IncludeStatus = nil
+	filter.MessageRegex = "hook (failed"
+	c.Assert(filter.Validate(), gc.ErrorMatches, "MessageRegex: error parsing regexp: .*")
+}
+
+type relationStatusSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&relationStatusSuite{})
+
+func (s *relationStatusSuite) TestValidRelationStatus(c *gc.C) {
+	for _, valid := range []status.Status{
+		status.Joining,
+		status.Joined,
+		status.Suspending,
+		status.Suspended,
+		status.Broken,
+		status.Error,
+	} {
+		c.Check(status.ValidRelationStatus(valid), jc.IsTrue, gc.Commentf("%q", valid))
+	}
+	for _, invalid := range []status.Status{
+		status.Active,
+		status.Unknown,
+		status.Idle,
+		status.Empty,
+	} {
+		c.Check(status.ValidRelationStatus(invalid), jc.IsFalse, gc.Commentf("%q", invalid))
+	}
+}
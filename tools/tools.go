@@ -13,6 +13,10 @@ type Tools struct {
 	URL     string         `json:"url"`
 	SHA256  string         `json:"sha256,omitempty"`
 	Size    int64          `json:"size"`
+
+	// Signature holds the armored OpenPGP detached signature of the
+	// tarball, if it was published with one.
+	Signature string `json:"signature,omitempty"`
 }
 
 // GUI represents the location and version of a GUI release archive.
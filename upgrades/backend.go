@@ -33,6 +33,26 @@ type StateBackend interface {
 	AddModelEnvironVersion() error
 	AddModelType() error
 	MigrateLeasesToGlobalTime() error
+	CompressStatusHistory() error
+
+	CountLocalUserDomainDocs() (int, error)
+	CountAddModelPermissions() (int, error)
+	CountMigrationsWithoutAttempt() (int, error)
+	CountLocalCharmSequenceChanges() (int, error)
+	CountLegacyLXDCloudCredentials() (int, error)
+	CountNonDetachableStorageWithoutMachineId() (int, error)
+	CountNilValueApplicationSettings() (int, error)
+	CountMissingControllerLogCollectionsSizeSettings() (int, error)
+	CountMissingStatusHistoryPruneSettings() (int, error)
+	CountMissingActionPruneSettings() (int, error)
+	CountStorageInstancesWithoutConstraints() (int, error)
+	CountUnsplitLogs() (int, error)
+	CountMissingUpdateStatusHookSettings() (int, error)
+	CountIncorrectRelationUnitCounts() (int, error)
+	CountModelsWithoutEnvironVersion() (int, error)
+	CountModelsWithoutType() (int, error)
+	CountLegacyLeases() (int, error)
+	CountUncompressedStatusHistory() (int, error)
 }
 
 // Model is an interface providing access to the details of a model within the
@@ -127,6 +147,82 @@ func (s stateBackend) MigrateLeasesToGlobalTime() error {
 	return state.MigrateLeasesToGlobalTime(s.st)
 }
 
+func (s stateBackend) CompressStatusHistory() error {
+	return state.CompressStatusHistory(s.st)
+}
+
+func (s stateBackend) CountLocalUserDomainDocs() (int, error) {
+	return state.CountLocalUserDomainDocs(s.st)
+}
+
+func (s stateBackend) CountAddModelPermissions() (int, error) {
+	return state.CountAddModelPermissions(s.st)
+}
+
+func (s stateBackend) CountMigrationsWithoutAttempt() (int, error) {
+	return state.CountMigrationsWithoutAttempt(s.st)
+}
+
+func (s stateBackend) CountLocalCharmSequenceChanges() (int, error) {
+	return state.CountLocalCharmSequenceChanges(s.st)
+}
+
+func (s stateBackend) CountLegacyLXDCloudCredentials() (int, error) {
+	return state.CountLegacyLXDCloudCredentials(s.st)
+}
+
+func (s stateBackend) CountNonDetachableStorageWithoutMachineId() (int, error) {
+	return state.CountNonDetachableStorageWithoutMachineId(s.st)
+}
+
+func (s stateBackend) CountNilValueApplicationSettings() (int, error) {
+	return state.CountNilValueApplicationSettings(s.st)
+}
+
+func (s stateBackend) CountMissingControllerLogCollectionsSizeSettings() (int, error) {
+	return state.CountMissingControllerLogCollectionsSizeSettings(s.st)
+}
+
+func (s stateBackend) CountMissingStatusHistoryPruneSettings() (int, error) {
+	return state.CountMissingStatusHistoryPruneSettings(s.st)
+}
+
+func (s stateBackend) CountMissingActionPruneSettings() (int, error) {
+	return state.CountMissingActionPruneSettings(s.st)
+}
+
+func (s stateBackend) CountStorageInstancesWithoutConstraints() (int, error) {
+	return state.CountStorageInstancesWithoutConstraints(s.st)
+}
+
+func (s stateBackend) CountUnsplitLogs() (int, error) {
+	return state.CountUnsplitLogs(s.st)
+}
+
+func (s stateBackend) CountMissingUpdateStatusHookSettings() (int, error) {
+	return state.CountMissingUpdateStatusHookSettings(s.st)
+}
+
+func (s stateBackend) CountIncorrectRelationUnitCounts() (int, error) {
+	return state.CountIncorrectRelationUnitCounts(s.st)
+}
+
+func (s stateBackend) CountModelsWithoutEnvironVersion() (int, error) {
+	return state.CountModelsWithoutEnvironVersion(s.st)
+}
+
+func (s stateBackend) CountModelsWithoutType() (int, error) {
+	return state.CountModelsWithoutType(s.st)
+}
+
+func (s stateBackend) CountLegacyLeases() (int, error) {
+	return state.CountLegacyLeases(s.st)
+}
+
+func (s stateBackend) CountUncompressedStatusHistory() (int, error) {
+	return state.CountUncompressedStatusHistory(s.st)
+}
+
 type modelShim struct {
 	st *state.State
 	m  *state.Model
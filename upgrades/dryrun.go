@@ -0,0 +1,139 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"github.com/juju/version"
+
+	jujuversion "github.com/juju/juju/version"
+)
+
+// StepEstimator is implemented by upgrade steps that can estimate,
+// without changing anything, how many documents they will change.
+type StepEstimator interface {
+	// EstimateDocs returns the number of documents the step is
+	// expected to change. It returns an error satisfying
+	// errors.IsNotImplemented if the step cannot tell.
+	EstimateDocs(Context) (int, error)
+}
+
+// PlannedStep describes an upgrade step that would be run by an
+// upgrade, without it having been run.
+type PlannedStep struct {
+	// TargetVersion is the version of the operation holding the step.
+	TargetVersion version.Number
+
+	// Description is the step's description.
+	Description string
+
+	// EstimatedDocs is the estimated number of documents the step
+	// will change, or -1 if the step cannot estimate it.
+	EstimatedDocs int
+
+	// Err, if non-nil, holds the error encountered while validating
+	// the step against the current state.
+	Err error
+}
+
+// PlanStateUpgrade returns, without running them, the state upgrade
+// steps that the master controller would run when upgrading from one
+// version to another. Each step is validated against the current
+// state and, where possible, the number of documents it will change
+// is estimated.
+//
+// Only the steps known to this version of Juju can be planned, so the
+// target version may not be later than the current version.
+func PlanStateUpgrade(from, to version.Number, st StateBackend) ([]PlannedStep, error) {
+	if from.Compare(to) > 0 {
+		return nil, errors.Errorf("cannot plan downgrade from %s to %s", from, to)
+	}
+	current := jujuversion.Current
+	current.Tag = ""
+	if to.Compare(current) > 0 {
+		return nil, errors.Errorf(
+			"cannot plan upgrade to %s with version %s; use the agent binaries of the target version",
+			to, jujuversion.Current,
+		)
+	}
+	ops := stateUpgradeOperations()
+	if err := validateOperations(ops); err != nil {
+		return nil, errors.Trace(err)
+	}
+	context := NewContext(nil, nil, st).StateContext()
+	targets := []Target{Controller, DatabaseMaster}
+
+	var planned []PlannedStep
+	it := newOpsIterator(from, to, ops)
+	for it.Next() {
+		op := it.Get()
+		for _, step := range op.Steps() {
+			if !targetsMatch(targets, step.Targets()) {
+				continue
+			}
+			plannedStep := PlannedStep{
+				TargetVersion: op.TargetVersion(),
+				Description:   step.Description(),
+				EstimatedDocs: -1,
+			}
+			if estimator, ok := step.(StepEstimator); ok {
+				count, err := estimator.EstimateDocs(context)
+				if err == nil {
+					plannedStep.EstimatedDocs = count
+				} else if !errors.IsNotImplemented(err) {
+					plannedStep.Err = err
+				}
+			}
+			planned = append(planned, plannedStep)
+		}
+	}
+	return planned, nil
+}
+
+// knownTargets holds the targets that upgrade steps may be run on.
+var knownTargets = set.NewStrings(
+	string(AllMachines),
+	string(HostMachine),
+	string(Controller),
+	string(DatabaseMaster),
+)
+
+// validateOperations returns an error if the operations are not
+// ordered by target version, or hold steps that could not be run:
+// steps without a description, with a description repeated within
+// the operation, or without known targets.
+func validateOperations(ops []Operation) error {
+	var previous version.Number
+	for i, op := range ops {
+		targetVersion := op.TargetVersion()
+		if i > 0 && targetVersion.Compare(previous) <= 0 {
+			return errors.Errorf(
+				"upgrade operation for %s follows operation for %s",
+				targetVersion, previous,
+			)
+		}
+		previous = targetVersion
+		descriptions := set.NewStrings()
+		for _, step := range op.Steps() {
+			description := step.Description()
+			if description == "" {
+				return errors.Errorf("upgrade step for %s has no description", targetVersion)
+			}
+			if descriptions.Contains(description) {
+				return errors.Errorf("upgrade step %q repeated for %s", description, targetVersion)
+			}
+			descriptions.Add(description)
+			if len(step.Targets()) == 0 {
+				return errors.Errorf("upgrade step %q has no targets", description)
+			}
+			for _, target := range step.Targets() {
+				if !knownTargets.Contains(string(target)) {
+					return errors.Errorf("upgrade step %q has unknown target %q", description, target)
+				}
+			}
+		}
+	}
+	return nil
+}
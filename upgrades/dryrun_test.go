@@ -0,0 +1,301 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/upgrades"
+	jujuversion "github.com/juju/juju/version"
+)
+
+type dryRunSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&dryRunSuite{})
+
+type estimatingStep struct {
+	*mockUpgradeStep
+	count int
+	err   error
+}
+
+func (s *estimatingStep) EstimateDocs(upgrades.Context) (int, error) {
+	return s.count, s.err
+}
+
+func (s *dryRunSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.PatchValue(&jujuversion.Current, version.MustParse("1.22.0"))
+	s.PatchValue(upgrades.StateUpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.20.0"),
+				steps: []upgrades.Step{
+					newUpgradeStep("already run", upgrades.DatabaseMaster),
+				},
+			},
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.21.0"),
+				steps: []upgrades.Step{
+					&estimatingStep{
+						mockUpgradeStep: newUpgradeStep("estimated", upgrades.DatabaseMaster),
+						count:           42,
+					},
+					newUpgradeStep("not estimated", upgrades.Controller),
+					newUpgradeStep("not for controllers", upgrades.HostMachine),
+				},
+			},
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.22.0"),
+				steps: []upgrades.Step{
+					&estimatingStep{
+						mockUpgradeStep: newUpgradeStep("not implemented", upgrades.DatabaseMaster),
+						err:             errors.NotImplementedf("estimate"),
+					},
+					&estimatingStep{
+						mockUpgradeStep: newUpgradeStep("invalid", upgrades.DatabaseMaster),
+						err:             errors.New("bad document"),
+					},
+				},
+			},
+		}
+	})
+}
+
+func (s *dryRunSuite) TestPlanStateUpgrade(c *gc.C) {
+	planned, err := upgrades.PlanStateUpgrade(
+		version.MustParse("1.20.0"),
+		version.MustParse("1.22.0"),
+		&mockStateBackend{},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(planned, gc.HasLen, 4)
+
+	v121 := version.MustParse("1.21.0")
+	v122 := version.MustParse("1.22.0")
+	c.Check(planned[0], jc.DeepEquals, upgrades.PlannedStep{
+		TargetVersion: v121, Description: "estimated", EstimatedDocs: 42,
+	})
+	c.Check(planned[1], jc.DeepEquals, upgrades.PlannedStep{
+		TargetVersion: v121, Description: "not estimated", EstimatedDocs: -1,
+	})
+	c.Check(planned[2], jc.DeepEquals, upgrades.PlannedStep{
+		TargetVersion: v122, Description: "not implemented", EstimatedDocs: -1,
+	})
+	c.Check(planned[3].Description, gc.Equals, "invalid")
+	c.Check(planned[3].EstimatedDocs, gc.Equals, -1)
+	c.Check(planned[3].Err, gc.ErrorMatches, "bad document")
+}
+
+func (s *dryRunSuite) TestPlanStateUpgradeLaterVersion(c *gc.C) {
+	_, err := upgrades.PlanStateUpgrade(
+		version.MustParse("1.20.0"),
+		version.MustParse("1.23.0"),
+		&mockStateBackend{},
+	)
+	c.Assert(err, gc.ErrorMatches, `cannot plan upgrade to 1.23.0 with version 1.22.0; .*`)
+}
+
+func (s *dryRunSuite) TestPlanStateUpgradeUnorderedOperations(c *gc.C) {
+	s.PatchValue(upgrades.StateUpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{targetVersion: version.MustParse("1.21.0")},
+			&mockUpgradeOperation{targetVersion: version.MustParse("1.20.0")},
+		}
+	})
+	_, err := upgrades.PlanStateUpgrade(
+		version.MustParse("1.20.0"),
+		version.MustParse("1.22.0"),
+		&mockStateBackend{},
+	)
+	c.Assert(err, gc.ErrorMatches, "upgrade operation for 1.20.0 follows operation for 1.21.0")
+}
+
+func (s *dryRunSuite) TestPlanStateUpgradeDowngrade(c *gc.C) {
+	_, err := upgrades.PlanStateUpgrade(
+		version.MustParse("1.22.0"),
+		version.MustParse("1.21.0"),
+		&mockStateBackend{},
+	)
+	c.Assert(err, gc.ErrorMatches, "cannot plan downgrade from 1.22.0 to 1.21.0")
+}
+
+func (s *dryRunSuite) TestPlanStateUpgradeSameVersion(c *gc.C) {
+	planned, err := upgrades.PlanStateUpgrade(
+		version.MustParse("1.22.0"),
+		version.MustParse("1.22.0"),
+		&mockStateBackend{},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(planned, gc.HasLen, 0)
+}
+
+func (s *dryRunSuite) TestPlanStateUpgradeRepeatedStep(c *gc.C) {
+	s.PatchValue(upgrades.StateUpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.21.0"),
+				steps: []upgrades.Step{
+					newUpgradeStep("step", upgrades.DatabaseMaster),
+					newUpgradeStep("step", upgrades.Controller),
+				},
+			},
+		}
+	})
+	_, err := upgrades.PlanStateUpgrade(
+		version.MustParse("1.20.0"),
+		version.MustParse("1.22.0"),
+		&mockStateBackend{},
+	)
+	c.Assert(err, gc.ErrorMatches, `upgrade step "step" repeated for 1.21.0`)
+}
+
+func (s *dryRunSuite) TestPlanStateUpgradeUnknownTarget(c *gc.C) {
+	s.PatchValue(upgrades.StateUpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.21.0"),
+				steps: []upgrades.Step{
+					newUpgradeStep("step", upgrades.Target("databaseSlave")),
+				},
+			},
+		}
+	})
+	_, err := upgrades.PlanStateUpgrade(
+		version.MustParse("1.20.0"),
+		version.MustParse("1.22.0"),
+		&mockStateBackend{},
+	)
+	c.Assert(err, gc.ErrorMatches, `upgrade step "step" has unknown target "databaseSlave"`)
+}
+
+type countingStateBackend struct {
+	mockStateBackend
+}
+
+func (b *countingStateBackend) CountUnsplitLogs() (int, error) {
+	b.MethodCall(b, "CountUnsplitLogs")
+	return 1, b.NextErr()
+}
+
+func (b *countingStateBackend) CountModelsWithoutEnvironVersion() (int, error) {
+	b.MethodCall(b, "CountModelsWithoutEnvironVersion")
+	return 2, b.NextErr()
+}
+
+func (b *countingStateBackend) CountModelsWithoutType() (int, error) {
+	b.MethodCall(b, "CountModelsWithoutType")
+	return 3, b.NextErr()
+}
+
+func (b *countingStateBackend) CountLegacyLeases() (int, error) {
+	b.MethodCall(b, "CountLegacyLeases")
+	return 4, b.NextErr()
+}
+
+func (b *countingStateBackend) CountUncompressedStatusHistory() (int, error) {
+	b.MethodCall(b, "CountUncompressedStatusHistory")
+	return 5, b.NextErr()
+}
+
+func (b *countingStateBackend) CountLocalUserDomainDocs() (int, error) {
+	b.MethodCall(b, "CountLocalUserDomainDocs")
+	return 6, b.NextErr()
+}
+
+func (b *countingStateBackend) CountAddModelPermissions() (int, error) {
+	b.MethodCall(b, "CountAddModelPermissions")
+	return 7, b.NextErr()
+}
+
+func (b *countingStateBackend) CountMigrationsWithoutAttempt() (int, error) {
+	b.MethodCall(b, "CountMigrationsWithoutAttempt")
+	return 8, b.NextErr()
+}
+
+func (b *countingStateBackend) CountLocalCharmSequenceChanges() (int, error) {
+	b.MethodCall(b, "CountLocalCharmSequenceChanges")
+	return 9, b.NextErr()
+}
+
+func (b *countingStateBackend) CountNonDetachableStorageWithoutMachineId() (int, error) {
+	b.MethodCall(b, "CountNonDetachableStorageWithoutMachineId")
+	return 10, b.NextErr()
+}
+
+func (b *countingStateBackend) CountNilValueApplicationSettings() (int, error) {
+	b.MethodCall(b, "CountNilValueApplicationSettings")
+	return 11, b.NextErr()
+}
+
+func (b *countingStateBackend) CountMissingControllerLogCollectionsSizeSettings() (int, error) {
+	b.MethodCall(b, "CountMissingControllerLogCollectionsSizeSettings")
+	return 12, b.NextErr()
+}
+
+func (b *countingStateBackend) CountMissingStatusHistoryPruneSettings() (int, error) {
+	b.MethodCall(b, "CountMissingStatusHistoryPruneSettings")
+	return 13, b.NextErr()
+}
+
+func (b *countingStateBackend) CountStorageInstancesWithoutConstraints() (int, error) {
+	b.MethodCall(b, "CountStorageInstancesWithoutConstraints")
+	return 14, b.NextErr()
+}
+
+func (b *countingStateBackend) CountMissingUpdateStatusHookSettings() (int, error) {
+	b.MethodCall(b, "CountMissingUpdateStatusHookSettings")
+	return 15, b.NextErr()
+}
+
+func (b *countingStateBackend) CountIncorrectRelationUnitCounts() (int, error) {
+	b.MethodCall(b, "CountIncorrectRelationUnitCounts")
+	return 16, b.NextErr()
+}
+
+func (b *countingStateBackend) CountMissingActionPruneSettings() (int, error) {
+	b.MethodCall(b, "CountMissingActionPruneSettings")
+	return 17, b.NextErr()
+}
+
+func (s *upgradeSuite) TestStateStepEstimates(c *gc.C) {
+	for _, test := range []struct {
+		version     string
+		description string
+		method      string
+		count       int
+	}{
+		{"2.0.0", "strip @local from local user names", "CountLocalUserDomainDocs", 6},
+		{"2.0.0", "rename addmodel permission to add-model", "CountAddModelPermissions", 7},
+		{"2.1.0", "add attempt to migration docs", "CountMigrationsWithoutAttempt", 8},
+		{"2.1.0", "add sequences to track used local charm revisions", "CountLocalCharmSequenceChanges", 9},
+		{"2.2.0", "split log collections", "CountUnsplitLogs", 1},
+		{"2.2.0", "add machineid to non-detachable storage docs", "CountNonDetachableStorageWithoutMachineId", 10},
+		{"2.2.0", "remove application config settings with nil value", "CountNilValueApplicationSettings", 11},
+		{"2.2.0", "add controller log collection sizing config settings", "CountMissingControllerLogCollectionsSizeSettings", 12},
+		{"2.2.0", "add status history pruning config settings", "CountMissingStatusHistoryPruneSettings", 13},
+		{"2.2.0", "add storage constraints to storage instance docs", "CountStorageInstancesWithoutConstraints", 14},
+		{"2.2.1", "add update-status hook config settings", "CountMissingUpdateStatusHookSettings", 15},
+		{"2.2.1", "correct relation unit counts for subordinates", "CountIncorrectRelationUnitCounts", 16},
+		{"2.2.2", "add environ-version to model docs", "CountModelsWithoutEnvironVersion", 2},
+		{"2.2.3", "add max-action-age and max-action-size config settings", "CountMissingActionPruneSettings", 17},
+		{"2.3.0", "add a 'type' field to model documents", "CountModelsWithoutType", 3},
+		{"2.3.0", "migrate old leases", "CountLegacyLeases", 4},
+		{"2.3.0", "compress status history", "CountUncompressedStatusHistory", 5},
+	} {
+		c.Logf("%s: %s", test.version, test.description)
+		backend := &countingStateBackend{}
+		step := findStateStep(c, version.MustParse(test.version), test.description)
+		count, err := step.(upgrades.StepEstimator).EstimateDocs(&mockContext{state: backend})
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(count, gc.Equals, test.count)
+		backend.CheckCallNames(c, test.method)
+	}
+}
@@ -31,6 +31,17 @@ func updateLXDCloudCredentials(st StateBackend) error {
 	return st.UpdateLegacyLXDCloudCredentials(gatewayAddress, creds)
 }
 
+func countLXDCloudCredentials(st StateBackend) (int, error) {
+	if _, err := lxd.ReadLegacyCloudCredentials(ioutil.ReadFile); err != nil {
+		if errors.IsNotFound(err) {
+			// Not running a LXD controller.
+			return 0, nil
+		}
+		return 0, errors.Annotate(err, "reading credentials from disk")
+	}
+	return st.CountLegacyLXDCloudCredentials()
+}
+
 func getDefaultGateway() (string, error) {
 	out, err := utils.RunCommand("ip", "route", "list", "match", "0/0")
 	if err != nil {
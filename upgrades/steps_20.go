@@ -17,6 +17,9 @@ func stateStepsFor20() []Step {
 			run: func(context Context) error {
 				return context.State().StripLocalUserDomain()
 			},
+			estimate: func(context Context) (int, error) {
+				return context.State().CountLocalUserDomainDocs()
+			},
 		},
 		&upgradeStep{
 			description: "rename addmodel permission to add-model",
@@ -24,6 +27,9 @@ func stateStepsFor20() []Step {
 			run: func(context Context) error {
 				return context.State().RenameAddModelPermission()
 			},
+			estimate: func(context Context) (int, error) {
+				return context.State().CountAddModelPermissions()
+			},
 		},
 	}
 }
@@ -12,6 +12,9 @@ func stateStepsFor21() []Step {
 			run: func(context Context) error {
 				return context.State().AddMigrationAttempt()
 			},
+			estimate: func(context Context) (int, error) {
+				return context.State().CountMigrationsWithoutAttempt()
+			},
 		},
 		&upgradeStep{
 			description: "add sequences to track used local charm revisions",
@@ -19,6 +22,9 @@ func stateStepsFor21() []Step {
 			run: func(context Context) error {
 				return context.State().AddLocalCharmSequences()
 			},
+			estimate: func(context Context) (int, error) {
+				return context.State().CountLocalCharmSequenceChanges()
+			},
 		},
 		&upgradeStep{
 			description: "update lxd cloud/credentials",
@@ -26,6 +32,9 @@ func stateStepsFor21() []Step {
 			run: func(context Context) error {
 				return updateLXDCloudCredentials(context.State())
 			},
+			estimate: func(context Context) (int, error) {
+				return countLXDCloudCredentials(context.State())
+			},
 		},
 	}
 }
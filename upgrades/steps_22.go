@@ -17,6 +17,9 @@ func stateStepsFor22() []Step {
 			run: func(context Context) error {
 				return context.State().AddNonDetachableStorageMachineId()
 			},
+			estimate: func(context Context) (int, error) {
+				return context.State().CountNonDetachableStorageWithoutMachineId()
+			},
 		},
 		&upgradeStep{
 			description: "remove application config settings with nil value",
@@ -24,6 +27,9 @@ func stateStepsFor22() []Step {
 			run: func(context Context) error {
 				return context.State().RemoveNilValueApplicationSettings()
 			},
+			estimate: func(context Context) (int, error) {
+				return context.State().CountNilValueApplicationSettings()
+			},
 		},
 		&upgradeStep{
 			description: "add controller log collection sizing config settings",
@@ -31,6 +37,9 @@ func stateStepsFor22() []Step {
 			run: func(context Context) error {
 				return context.State().AddControllerLogCollectionsSizeSettings()
 			},
+			estimate: func(context Context) (int, error) {
+				return context.State().CountMissingControllerLogCollectionsSizeSettings()
+			},
 		},
 		&upgradeStep{
 			description: "add status history pruning config settings",
@@ -38,6 +47,9 @@ func stateStepsFor22() []Step {
 			run: func(context Context) error {
 				return context.State().AddStatusHistoryPruneSettings()
 			},
+			estimate: func(context Context) (int, error) {
+				return context.State().CountMissingStatusHistoryPruneSettings()
+			},
 		},
 		&upgradeStep{
 			description: "add storage constraints to storage instance docs",
@@ -45,6 +57,9 @@ func stateStepsFor22() []Step {
 			run: func(context Context) error {
 				return context.State().AddStorageInstanceConstraints()
 			},
+			estimate: func(context Context) (int, error) {
+				return context.State().CountStorageInstancesWithoutConstraints()
+			},
 		},
 		&upgradeStep{
 			description: "split log collections",
@@ -52,6 +67,9 @@ func stateStepsFor22() []Step {
 			run: func(context Context) error {
 				return context.State().SplitLogCollections()
 			},
+			estimate: func(context Context) (int, error) {
+				return context.State().CountUnsplitLogs()
+			},
 		},
 	}
 }
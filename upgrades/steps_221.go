@@ -12,6 +12,9 @@ func stateStepsFor221() []Step {
 			run: func(context Context) error {
 				return context.State().AddUpdateStatusHookSettings()
 			},
+			estimate: func(context Context) (int, error) {
+				return context.State().CountMissingUpdateStatusHookSettings()
+			},
 		},
 		&upgradeStep{
 			description: "correct relation unit counts for subordinates",
@@ -19,6 +22,9 @@ func stateStepsFor221() []Step {
 			run: func(context Context) error {
 				return context.State().CorrectRelationUnitCounts()
 			},
+			estimate: func(context Context) (int, error) {
+				return context.State().CountIncorrectRelationUnitCounts()
+			},
 		},
 	}
 }
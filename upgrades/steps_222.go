@@ -12,6 +12,9 @@ func stateStepsFor222() []Step {
 			run: func(context Context) error {
 				return context.State().AddModelEnvironVersion()
 			},
+			estimate: func(context Context) (int, error) {
+				return context.State().CountModelsWithoutEnvironVersion()
+			},
 		},
 	}
 }
@@ -12,6 +12,9 @@ func stateStepsFor223() []Step {
 			run: func(context Context) error {
 				return context.State().AddActionPruneSettings()
 			},
+			estimate: func(context Context) (int, error) {
+				return context.State().CountMissingActionPruneSettings()
+			},
 		},
 	}
 }
@@ -12,6 +12,9 @@ func stateStepsFor23() []Step {
 			run: func(context Context) error {
 				return context.State().AddModelType()
 			},
+			estimate: func(context Context) (int, error) {
+				return context.State().CountModelsWithoutType()
+			},
 		},
 		&upgradeStep{
 			description: "migrate old leases",
@@ -19,6 +22,19 @@ func stateStepsFor23() []Step {
 			run: func(context Context) error {
 				return context.State().MigrateLeasesToGlobalTime()
 			},
+			estimate: func(context Context) (int, error) {
+				return context.State().CountLegacyLeases()
+			},
+		},
+		&upgradeStep{
+			description: "compress status history",
+			targets:     []Target{DatabaseMaster},
+			run: func(context Context) error {
+				return context.State().CompressStatusHistory()
+			},
+			estimate: func(context Context) (int, error) {
+				return context.State().CountUncompressedStatusHistory()
+			},
 		},
 	}
 }
@@ -31,3 +31,9 @@ func (s *steps23Suite) TestMigrateLeases(c *gc.C) {
 	// Logic for step itself is tested in state package.
 	c.Assert(step.Targets(), jc.DeepEquals, []upgrades.Target{upgrades.DatabaseMaster})
 }
+
+func (s *steps23Suite) TestCompressStatusHistory(c *gc.C) {
+	step := findStateStep(c, v23, "compress status history")
+	// Logic for step itself is tested in state package.
+	c.Assert(step.Targets(), jc.DeepEquals, []upgrades.Target{upgrades.DatabaseMaster})
+}
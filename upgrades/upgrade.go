@@ -6,6 +6,7 @@ package upgrades
 import (
 	"fmt"
 
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/version"
 )
@@ -167,9 +168,14 @@ type upgradeStep struct {
 	description string
 	targets     []Target
 	run         func(Context) error
+
+	// estimate, if non-nil, returns the number of documents that
+	// run is expected to change, without changing anything.
+	estimate func(Context) (int, error)
 }
 
 var _ Step = (*upgradeStep)(nil)
+var _ StepEstimator = (*upgradeStep)(nil)
 
 // Description is defined on the Step interface.
 func (step *upgradeStep) Description() string {
@@ -185,3 +191,11 @@ func (step *upgradeStep) Targets() []Target {
 func (step *upgradeStep) Run(context Context) error {
 	return step.run(context)
 }
+
+// EstimateDocs is defined on the StepEstimator interface.
+func (step *upgradeStep) EstimateDocs(context Context) (int, error) {
+	if step.estimate == nil {
+		return 0, errors.NotImplementedf("estimating documents changed by %q", step.description)
+	}
+	return step.estimate(context)
+}
@@ -12,6 +12,8 @@ import (
 
 	"github.com/juju/errors"
 	proxyutils "github.com/juju/utils/proxy"
+
+	"github.com/juju/juju/environs/config"
 )
 
 // ProxyConfig stores the proxy settings that should be used for web
@@ -19,7 +21,7 @@ import (
 type ProxyConfig struct {
 	mu          sync.Mutex
 	http, https *url.URL
-	noProxy     string
+	noProxy     config.NoProxyEntries
 }
 
 // Set updates the stored settings to the new ones passed in.
@@ -34,9 +36,13 @@ func (pc *ProxyConfig) Set(newSettings proxyutils.Settings) error {
 	if err != nil {
 		return errors.Annotate(err, "https proxy")
 	}
+	noProxy, err := config.ParseNoProxy(newSettings.FullNoProxy())
+	if err != nil {
+		return errors.Annotate(err, "no proxy")
+	}
 	pc.http = httpUrl
 	pc.https = httpsUrl
-	pc.noProxy = newSettings.FullNoProxy()
+	pc.noProxy = noProxy
 	return nil
 }
 
@@ -69,7 +75,6 @@ func (pc *ProxyConfig) GetProxy(req *http.Request) (*url.URL, error) {
 // useProxy reports whether requests to addr should use a proxy,
 // according to the NoProxy value of the proxy setting.
 // addr is always a canonicalAddr with a host and port.
-// (Implementation copied from net/http.useProxy.)
 func (pc *ProxyConfig) useProxy(addr string) bool {
 	if len(addr) == 0 {
 		return true
@@ -81,44 +86,10 @@ func (pc *ProxyConfig) useProxy(addr string) bool {
 	if host == "localhost" {
 		return false
 	}
-	ip := net.ParseIP(host)
-	if ip != nil && ip.IsLoopback() {
-		return false
-	}
-
-	if pc.noProxy == "*" {
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
 		return false
 	}
-
-	addr = strings.ToLower(strings.TrimSpace(addr))
-	if hasPort(addr) {
-		addr = addr[:strings.LastIndex(addr, ":")]
-	}
-
-	for _, p := range strings.Split(pc.noProxy, ",") {
-		p = strings.ToLower(strings.TrimSpace(p))
-		if len(p) == 0 {
-			continue
-		}
-		if hasPort(p) {
-			p = p[:strings.LastIndex(p, ":")]
-		}
-		if addr == p {
-			return false
-		}
-		if p[0] == '.' && (strings.HasSuffix(addr, p) || addr == p[1:]) {
-			// no_proxy ".foo.com" matches "bar.foo.com" or "foo.com"
-			return false
-		}
-		if p[0] != '.' && strings.HasSuffix(addr, p) && addr[len(addr)-len(p)-1] == '.' {
-			// no_proxy "foo.com" matches "bar.foo.com"
-			return false
-		}
-		if _, net, err := net.ParseCIDR(p); ip != nil && err == nil && net.Contains(ip) {
-			return false
-		}
-	}
-	return true
+	return !pc.noProxy.Matches(host)
 }
 
 // InstallInDefaultTransport sets the proxy resolution used by the
@@ -79,6 +79,14 @@ func (s *Suite) TestSetBadUrl(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `http proxy: invalid proxy address "http://badurl%gg": .*$`)
 }
 
+func (s *Suite) TestSetBadNoProxy(c *gc.C) {
+	pc := proxyconfig.ProxyConfig{}
+	err := pc.Set(proxy.Settings{
+		NoProxy: "10.0.0.0/33",
+	})
+	c.Assert(err, gc.ErrorMatches, `no proxy: CIDR "10.0.0.0/33" not valid`)
+}
+
 func (s *Suite) TestInstallError(c *gc.C) {
 	type fakeRoundTripper struct {
 		http.RoundTripper
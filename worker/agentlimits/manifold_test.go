@@ -0,0 +1,128 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agentlimits_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker/agentlimits"
+	"github.com/juju/juju/worker/dependency"
+	dt "github.com/juju/juju/worker/dependency/testing"
+)
+
+type ManifoldConfigSuite struct {
+	testing.IsolationSuite
+	config agentlimits.ManifoldConfig
+}
+
+var _ = gc.Suite(&ManifoldConfigSuite{})
+
+func (s *ManifoldConfigSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.config = agentlimits.ManifoldConfig{
+		AgentName:     "agent",
+		APICallerName: "api-caller",
+		Clock:         clock.WallClock,
+		NewFacade:     func(base.APICaller) (agentlimits.Facade, error) { return nil, nil },
+		NewWorker:     func(agentlimits.Config) (worker.Worker, error) { return nil, nil },
+	}
+}
+
+func (s *ManifoldConfigSuite) TestValid(c *gc.C) {
+	c.Check(s.config.Validate(), jc.ErrorIsNil)
+}
+
+func (s *ManifoldConfigSuite) TestMissingAgentName(c *gc.C) {
+	s.config.AgentName = ""
+	s.checkNotValid(c, "empty AgentName not valid")
+}
+
+func (s *ManifoldConfigSuite) TestMissingAPICallerName(c *gc.C) {
+	s.config.APICallerName = ""
+	s.checkNotValid(c, "empty APICallerName not valid")
+}
+
+func (s *ManifoldConfigSuite) TestMissingClock(c *gc.C) {
+	s.config.Clock = nil
+	s.checkNotValid(c, "nil Clock not valid")
+}
+
+func (s *ManifoldConfigSuite) TestMissingNewFacade(c *gc.C) {
+	s.config.NewFacade = nil
+	s.checkNotValid(c, "nil NewFacade not valid")
+}
+
+func (s *ManifoldConfigSuite) TestMissingNewWorker(c *gc.C) {
+	s.config.NewWorker = nil
+	s.checkNotValid(c, "nil NewWorker not valid")
+}
+
+func (s *ManifoldConfigSuite) checkNotValid(c *gc.C, expect string) {
+	err := s.config.Validate()
+	c.Check(err, gc.ErrorMatches, expect)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+}
+
+type ManifoldSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&ManifoldSuite{})
+
+func (s *ManifoldSuite) TestInputs(c *gc.C) {
+	manifold := agentlimits.Manifold(agentlimits.ManifoldConfig{
+		AgentName:     "agent",
+		APICallerName: "api-caller",
+	})
+	c.Check(manifold.Inputs, jc.DeepEquals, []string{"agent", "api-caller"})
+}
+
+func (s *ManifoldSuite) TestControllerAgent(c *gc.C) {
+	manifold := agentlimits.Manifold(agentlimits.ManifoldConfig{
+		AgentName:     "agent",
+		APICallerName: "api-caller",
+		Clock:         clock.WallClock,
+		NewFacade: func(base.APICaller) (agentlimits.Facade, error) {
+			c.Fatalf("unexpected facade")
+			return nil, nil
+		},
+		NewWorker: func(agentlimits.Config) (worker.Worker, error) {
+			c.Fatalf("unexpected worker")
+			return nil, nil
+		},
+	})
+	context := dt.StubContext(nil, map[string]interface{}{
+		"agent":      &mockAgent{controller: true},
+		"api-caller": struct{ base.APICaller }{},
+	})
+	w, err := manifold.Start(context)
+	c.Check(w, gc.IsNil)
+	c.Check(err, gc.Equals, dependency.ErrMissing)
+}
+
+type mockAgent struct {
+	agent.Agent
+	controller bool
+}
+
+func (ma *mockAgent) CurrentConfig() agent.Config {
+	return &mockConfig{controller: ma.controller}
+}
+
+type mockConfig struct {
+	agent.Config
+	controller bool
+}
+
+func (mc *mockConfig) StateServingInfo() (params.StateServingInfo, bool) {
+	return params.StateServingInfo{}, mc.controller
+}
@@ -0,0 +1,224 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package agentlimits provides a worker that bounds the resources used
+// by the agent running it, according to the model's agent-max-procs
+// and agent-memory-limit config.
+package agentlimits
+
+import (
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/agent"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/watcher"
+	jworker "github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+var logger = loggo.GetLogger("juju.worker.agentlimits")
+
+// Facade represents the API used by the worker to read the model's
+// agent limits.
+type Facade interface {
+	WatchForModelConfigChanges() (watcher.NotifyWatcher, error)
+	ModelConfig() (*config.Config, error)
+}
+
+// NewFacade returns a Facade backed by the Agent API.
+func NewFacade(caller base.APICaller) (Facade, error) {
+	facade, err := agent.NewState(caller)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return facade, nil
+}
+
+// Config holds the resources and configuration needed to run the worker.
+type Config struct {
+	Facade Facade
+	Clock  clock.Clock
+
+	// CheckInterval is how often the agent's memory use is compared
+	// with the limit.
+	CheckInterval time.Duration
+
+	// SetMaxProcs sets the number of CPUs the agent may use
+	// simultaneously, and returns the previous setting; it does not
+	// change the setting if passed 0. It is usually runtime.GOMAXPROCS.
+	SetMaxProcs func(int) int
+
+	// MemoryUsage returns the number of bytes of memory the agent
+	// holds from the operating system.
+	MemoryUsage func() uint64
+
+	// FreeMemory returns as much memory as possible to the operating
+	// system.
+	FreeMemory func()
+}
+
+// Validate returns an error if the config cannot be used to start
+// a worker.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.CheckInterval <= 0 {
+		return errors.NotValidf("non-positive CheckInterval")
+	}
+	if config.SetMaxProcs == nil {
+		return errors.NotValidf("nil SetMaxProcs")
+	}
+	if config.MemoryUsage == nil {
+		return errors.NotValidf("nil MemoryUsage")
+	}
+	if config.FreeMemory == nil {
+		return errors.NotValidf("nil FreeMemory")
+	}
+	return nil
+}
+
+// MemoryUsage returns the number of bytes of memory the running process
+// holds from the operating system. It's a sensible value for
+// Config.MemoryUsage.
+func MemoryUsage() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.Sys - stats.HeapReleased
+}
+
+// DefaultConfig returns a Config that limits the running process, using
+// the supplied facade and clock.
+func DefaultConfig(facade Facade, clock clock.Clock) Config {
+	return Config{
+		Facade:        facade,
+		Clock:         clock,
+		CheckInterval: time.Minute,
+		SetMaxProcs:   runtime.GOMAXPROCS,
+		MemoryUsage:   MemoryUsage,
+		FreeMemory:    debug.FreeOSMemory,
+	}
+}
+
+// New returns a worker that applies the model's agent-max-procs config
+// to the agent, and restarts the agent should its memory use exceed the
+// model's agent-memory-limit config.
+func New(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &limitsWorker{config: config}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type limitsWorker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *limitsWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *limitsWorker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *limitsWorker) loop() error {
+	modelConfigWatcher, err := w.config.Facade.WatchForModelConfigChanges()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := w.catacomb.Add(modelConfigWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
+	// The agent's own setting is restored whenever the model's limit
+	// is removed.
+	defaultMaxProcs := w.config.SetMaxProcs(0)
+	defer w.config.SetMaxProcs(defaultMaxProcs)
+
+	var maxProcs int
+	var memoryLimit uint64
+	var checkCh <-chan time.Time
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+
+		case _, ok := <-modelConfigWatcher.Changes():
+			if !ok {
+				return errors.New("model configuration watcher closed")
+			}
+			modelConfig, err := w.config.Facade.ModelConfig()
+			if err != nil {
+				return errors.Annotate(err, "cannot load model configuration")
+			}
+			if newMaxProcs := modelConfig.AgentMaxProcs(); newMaxProcs != maxProcs {
+				maxProcs = newMaxProcs
+				if maxProcs == 0 {
+					logger.Infof("removing agent CPU limit")
+					w.config.SetMaxProcs(defaultMaxProcs)
+				} else {
+					logger.Infof("limiting agent to %d CPUs", maxProcs)
+					w.config.SetMaxProcs(maxProcs)
+				}
+			}
+			if newMemoryLimit := modelConfig.AgentMemoryLimitMB(); newMemoryLimit != memoryLimit {
+				memoryLimit = newMemoryLimit
+				if memoryLimit == 0 {
+					logger.Infof("removing agent memory limit")
+					checkCh = nil
+				} else {
+					logger.Infof("limiting agent memory to %dMiB", memoryLimit)
+					if err := w.checkMemory(memoryLimit); err != nil {
+						return errors.Trace(err)
+					}
+					checkCh = w.config.Clock.After(w.config.CheckInterval)
+				}
+			}
+
+		case <-checkCh:
+			if err := w.checkMemory(memoryLimit); err != nil {
+				return errors.Trace(err)
+			}
+			checkCh = w.config.Clock.After(w.config.CheckInterval)
+		}
+	}
+}
+
+// checkMemory returns ErrRestartAgent if the agent's memory use exceeds
+// the supplied limit, in MiB, even after freeing what memory it can.
+func (w *limitsWorker) checkMemory(limitMB uint64) error {
+	limit := limitMB * 1024 * 1024
+	if w.config.MemoryUsage() <= limit {
+		return nil
+	}
+	w.config.FreeMemory()
+	usage := w.config.MemoryUsage()
+	if usage <= limit {
+		return nil
+	}
+	logger.Errorf("agent memory use %dMiB exceeds limit of %dMiB; restarting", usage/(1024*1024), limitMB)
+	return jworker.ErrRestartAgent
+}
@@ -0,0 +1,265 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agentlimits_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/tomb.v1"
+
+	"github.com/juju/juju/environs/config"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/watcher"
+	jworker "github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/agentlimits"
+	"github.com/juju/juju/worker/workertest"
+)
+
+const mib = 1024 * 1024
+
+type WorkerSuite struct {
+	coretesting.BaseSuite
+	facade  *fakeFacade
+	clock   *testing.Clock
+	process *fakeProcess
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.facade = &fakeFacade{
+		changes: make(chan struct{}, 1),
+	}
+	s.setLimits(c, coretesting.Attrs{})
+	s.clock = testing.NewClock(time.Time{})
+	s.process = &fakeProcess{
+		maxProcs: 8,
+		usage:    []uint64{10 * mib},
+		changes:  make(chan int, 10),
+		freed:    make(chan struct{}, 10),
+	}
+}
+
+func (s *WorkerSuite) setLimits(c *gc.C, limits coretesting.Attrs) {
+	cfg, err := config.New(config.UseDefaults, coretesting.FakeConfig().Merge(limits))
+	c.Assert(err, jc.ErrorIsNil)
+	s.facade.setModelConfig(cfg)
+}
+
+func (s *WorkerSuite) config() agentlimits.Config {
+	return agentlimits.Config{
+		Facade:        s.facade,
+		Clock:         s.clock,
+		CheckInterval: time.Minute,
+		SetMaxProcs:   s.process.SetMaxProcs,
+		MemoryUsage:   s.process.MemoryUsage,
+		FreeMemory:    s.process.FreeMemory,
+	}
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) worker.Worker {
+	w, err := agentlimits.New(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) {
+		workertest.DirtyKill(c, w)
+	})
+	s.facade.changes <- struct{}{}
+	return w
+}
+
+func (s *WorkerSuite) assertMaxProcs(c *gc.C, expect int) {
+	select {
+	case n := <-s.process.changes:
+		c.Assert(n, gc.Equals, expect)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for max procs to be set to %d", expect)
+	}
+}
+
+func (s *WorkerSuite) assertFreed(c *gc.C) {
+	select {
+	case <-s.process.freed:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for memory to be freed")
+	}
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	config := s.config()
+	config.Facade = nil
+	_, err := agentlimits.New(config)
+	c.Check(err, gc.ErrorMatches, "nil Facade not valid")
+
+	config = s.config()
+	config.CheckInterval = 0
+	_, err = agentlimits.New(config)
+	c.Check(err, gc.ErrorMatches, "non-positive CheckInterval not valid")
+
+	config = s.config()
+	config.SetMaxProcs = nil
+	_, err = agentlimits.New(config)
+	c.Check(err, gc.ErrorMatches, "nil SetMaxProcs not valid")
+}
+
+func (s *WorkerSuite) TestAppliesMaxProcs(c *gc.C) {
+	s.setLimits(c, coretesting.Attrs{"agent-max-procs": 2})
+	s.startWorker(c)
+	s.assertMaxProcs(c, 2)
+
+	s.setLimits(c, coretesting.Attrs{})
+	s.facade.changes <- struct{}{}
+	s.assertMaxProcs(c, 8)
+}
+
+func (s *WorkerSuite) TestRestoresMaxProcsWhenStopped(c *gc.C) {
+	s.setLimits(c, coretesting.Attrs{"agent-max-procs": 2})
+	w := s.startWorker(c)
+	s.assertMaxProcs(c, 2)
+
+	workertest.CleanKill(c, w)
+	s.assertMaxProcs(c, 8)
+}
+
+func (s *WorkerSuite) TestRestartsAgentWhenMemoryLimitExceeded(c *gc.C) {
+	s.process.setUsage(200*mib, 150*mib)
+	s.setLimits(c, coretesting.Attrs{"agent-memory-limit": "100M"})
+	w := s.startWorker(c)
+
+	s.assertFreed(c)
+	err := workertest.CheckKilled(c, w)
+	c.Assert(errors.Cause(err), gc.Equals, jworker.ErrRestartAgent)
+}
+
+func (s *WorkerSuite) TestFreesMemoryBeforeRestartingAgent(c *gc.C) {
+	s.process.setUsage(200*mib, 50*mib)
+	s.setLimits(c, coretesting.Attrs{"agent-memory-limit": "100M"})
+	w := s.startWorker(c)
+
+	s.assertFreed(c)
+	workertest.CheckAlive(c, w)
+}
+
+func (s *WorkerSuite) TestChecksMemoryPeriodically(c *gc.C) {
+	s.setLimits(c, coretesting.Attrs{"agent-memory-limit": "100M"})
+	w := s.startWorker(c)
+
+	s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	workertest.CheckAlive(c, w)
+
+	s.process.setUsage(200 * mib)
+	s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	s.assertFreed(c)
+	err := workertest.CheckKilled(c, w)
+	c.Assert(errors.Cause(err), gc.Equals, jworker.ErrRestartAgent)
+}
+
+func (s *WorkerSuite) TestNoMemoryLimit(c *gc.C) {
+	s.process.setUsage(200 * mib)
+	w := s.startWorker(c)
+
+	workertest.CheckAlive(c, w)
+	select {
+	case <-s.process.freed:
+		c.Fatal("unexpected memory check")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+type fakeFacade struct {
+	mu          sync.Mutex
+	changes     chan struct{}
+	modelConfig *config.Config
+}
+
+func (f *fakeFacade) setModelConfig(cfg *config.Config) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.modelConfig = cfg
+}
+
+// WatchForModelConfigChanges is part of the agentlimits.Facade interface.
+func (f *fakeFacade) WatchForModelConfigChanges() (watcher.NotifyWatcher, error) {
+	return newMockNotifyWatcher(f.changes), nil
+}
+
+// ModelConfig is part of the agentlimits.Facade interface.
+func (f *fakeFacade) ModelConfig() (*config.Config, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.modelConfig, nil
+}
+
+// fakeProcess stands in for the Go runtime. Successive calls to
+// MemoryUsage return successive values of usage, repeating the last.
+type fakeProcess struct {
+	mu       sync.Mutex
+	maxProcs int
+	usage    []uint64
+	changes  chan int
+	freed    chan struct{}
+}
+
+func (p *fakeProcess) setUsage(usage ...uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.usage = usage
+}
+
+func (p *fakeProcess) SetMaxProcs(n int) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	previous := p.maxProcs
+	if n > 0 {
+		p.maxProcs = n
+		p.changes <- n
+	}
+	return previous
+}
+
+func (p *fakeProcess) MemoryUsage() uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	usage := p.usage[0]
+	if len(p.usage) > 1 {
+		p.usage = p.usage[1:]
+	}
+	return usage
+}
+
+func (p *fakeProcess) FreeMemory() {
+	p.freed <- struct{}{}
+}
+
+type mockNotifyWatcher struct {
+	tomb tomb.Tomb
+	ch   chan struct{}
+}
+
+func newMockNotifyWatcher(ch chan struct{}) *mockNotifyWatcher {
+	w := &mockNotifyWatcher{ch: ch}
+	go func() {
+		defer w.tomb.Done()
+		<-w.tomb.Dying()
+	}()
+	return w
+}
+
+func (w *mockNotifyWatcher) Changes() watcher.NotifyChannel {
+	return w.ch
+}
+
+func (w *mockNotifyWatcher) Kill() {
+	w.tomb.Kill(nil)
+}
+
+func (w *mockNotifyWatcher) Wait() error {
+	return w.tomb.Wait()
+}
@@ -20,6 +20,9 @@ var logger = loggo.GetLogger("juju.worker.apiaddressupdater")
 //
 // In practice, APIAddressUpdater is used by a machine agent to watch
 // API addresses in state and write the changes to the agent's config file.
+// The addresses it is given are those advertised to agents, so they
+// follow the model's api-advertise-addresses and
+// api-advertise-exclude-cidrs settings.
 type APIAddressUpdater struct {
 	addresser APIAddresser
 	setter    APIAddressSetter
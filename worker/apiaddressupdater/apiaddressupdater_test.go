@@ -200,3 +200,43 @@ LXC_BRIDGE="ignored"`[1:])
 		})
 	}
 }
+
+func (s *APIAddressUpdaterSuite) TestAdvertisePolicy(c *gc.C) {
+	err := s.State.SetAPIHostPorts([][]network.HostPort{
+		network.NewHostPorts(1234, "10.0.0.1", "192.168.100.1"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	setter := &apiAddressSetter{servers: make(chan [][]network.HostPort, 1)}
+	st, _ := s.OpenAPIAsNewMachine(c, state.JobHostUnits)
+	worker, err := apiaddressupdater.NewAPIAddressUpdater(apimachiner.NewState(st), setter)
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+	s.BackingState.StartSync()
+	select {
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for SetAPIHostPorts to be called initially")
+	case servers := <-setter.servers:
+		c.Assert(servers, jc.DeepEquals, [][]network.HostPort{
+			network.NewHostPorts(1234, "10.0.0.1", "192.168.100.1"),
+		})
+	}
+
+	// Changing the policy updates the addresses agents are given.
+	err = s.IAASModel.UpdateModelConfig(map[string]interface{}{
+		"api-advertise-addresses":     "203.0.113.7:443",
+		"api-advertise-exclude-cidrs": "192.168.100.0/24",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.BackingState.StartSync()
+	select {
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for SetAPIHostPorts to be called after policy change")
+	case servers := <-setter.servers:
+		c.Assert(servers, jc.DeepEquals, [][]network.HostPort{
+			network.NewHostPorts(443, "203.0.113.7"),
+			network.NewHostPorts(1234, "10.0.0.1"),
+		})
+	}
+}
@@ -5,6 +5,7 @@ package authenticationworker
 
 import (
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/api/keyupdater"
+	"github.com/juju/juju/core/sshkeys"
 	"github.com/juju/juju/watcher"
 	jworker "github.com/juju/juju/worker"
 )
@@ -105,12 +107,14 @@ func (kw *keyupdaterWorker) SetUp() (watcher.NotifyWatcher, error) {
 }
 
 // writeSSHKeys writes out a new ~/.ssh/authorised_keys file, retaining any non Juju keys
-// and adding the specified set of Juju keys.
+// and adding the specified set of Juju keys. Juju keys that have expired are omitted;
+// the controller removes them from the model shortly after they expire.
 func (kw *keyupdaterWorker) writeSSHKeys(jujuKeys []string) error {
 	allKeys := kw.nonJujuKeys
+	jujuKeys, _ = sshkeys.Unexpired(jujuKeys, time.Now())
 	// Ensure any Juju keys have the required prefix in their comment.
 	for i, key := range jujuKeys {
-		jujuKeys[i] = ssh.EnsureJujuComment(key)
+		jujuKeys[i] = ssh.EnsureJujuComment(sshkeys.StripExpiry(key))
 	}
 	allKeys = append(allKeys, jujuKeys...)
 	return ssh.ReplaceKeys(SSHUser, allKeys...)
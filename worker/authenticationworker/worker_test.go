@@ -132,6 +132,19 @@ func (s *workerSuite) TestNewKeysInJujuAreSavedOnStartup(c *gc.C) {
 	s.waitSSHKeys(c, append(s.existingKeys, newKeyWithCommentPrefix))
 }
 
+func (s *workerSuite) TestExpiringKeys(c *gc.C) {
+	authWorker, err := authenticationworker.NewWorker(s.keyupdaterAPI, agentConfig(c, s.machine.Tag().(names.MachineTag)))
+	c.Assert(err, jc.ErrorIsNil)
+	defer stop(c, authWorker)
+
+	s.setAuthorisedKeys(c,
+		`expiry-time="200001010000" `+sshtesting.ValidKeyOne.Key+" expired@host",
+		`expiry-time="299901010000" `+sshtesting.ValidKeyThree.Key+" user@host",
+	)
+	newKeyWithCommentPrefix := sshtesting.ValidKeyThree.Key + " Juju:user@host"
+	s.waitSSHKeys(c, append(s.existingKeys, newKeyWithCommentPrefix))
+}
+
 func (s *workerSuite) TestDeleteKey(c *gc.C) {
 	authWorker, err := authenticationworker.NewWorker(s.keyupdaterAPI, agentConfig(c, s.machine.Tag().(names.MachineTag)))
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,130 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package controllerfirewaller provides a worker that manages the
+// ingress rules of the controller's own instances, so that the API is
+// reachable only from the controller's admin CIDRs, and the database
+// only from the other controller machines.
+package controllerfirewaller
+
+import (
+	"reflect"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/controllerfirewaller"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/catacomb"
+	"github.com/juju/juju/worker/dependency"
+)
+
+var logger = loggo.GetLogger("juju.worker.controllerfirewaller")
+
+// Facade represents the API used by the worker to find the controller
+// ingress rules.
+type Facade interface {
+	ControllerIngressRules() (string, []network.IngressRule, error)
+	WatchControllerIngressRules() (watcher.NotifyWatcher, error)
+}
+
+// NewFacade returns a Facade backed by the ControllerFirewaller API.
+func NewFacade(caller base.APICaller) Facade {
+	return controllerfirewaller.NewClient(caller)
+}
+
+// Config holds the resources and configuration needed to run the worker.
+type Config struct {
+	Facade  Facade
+	Environ environs.ControllerFirewaller
+}
+
+// Validate returns an error if the config cannot be used to start
+// a worker.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.Environ == nil {
+		return errors.NotValidf("nil Environ")
+	}
+	return nil
+}
+
+// New returns a worker that applies the controller ingress rules to
+// the environ whenever they change. The worker uninstalls itself if
+// it is not running for the controller model.
+func New(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &firewallerWorker{config: config}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type firewallerWorker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *firewallerWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *firewallerWorker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *firewallerWorker) loop() error {
+	rulesWatcher, err := w.config.Facade.WatchControllerIngressRules()
+	if params.IsCodeNotSupported(err) {
+		logger.Debugf("not the controller model, uninstalling: %v", err)
+		return dependency.ErrUninstall
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if err := w.catacomb.Add(rulesWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
+	// The rules are recorded as nil until the initial event has been
+	// handled, so that the rules are always set once on startup.
+	var applied []network.IngressRule
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+
+		case _, ok := <-rulesWatcher.Changes():
+			if !ok {
+				return errors.New("controller ingress rules watcher closed")
+			}
+			controllerUUID, rules, err := w.config.Facade.ControllerIngressRules()
+			if err != nil {
+				return errors.Annotate(err, "cannot get controller ingress rules")
+			}
+			if applied != nil && reflect.DeepEqual(applied, rules) {
+				continue
+			}
+			logger.Infof("setting controller ingress rules to %v", rules)
+			if err := w.config.Environ.SetControllerIngressRules(controllerUUID, rules); err != nil {
+				return errors.Annotate(err, "cannot set controller ingress rules")
+			}
+			applied = rules
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controllerfirewaller_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/tomb.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/controllerfirewaller"
+	"github.com/juju/juju/worker/dependency"
+)
+
+type WorkerSuite struct {
+	coretesting.BaseSuite
+	facade  *fakeFacade
+	environ *fakeEnviron
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.facade = &fakeFacade{
+		changes: make(chan struct{}, 1),
+		rules: []network.IngressRule{
+			network.MustNewIngressRule("tcp", 17070, 17070),
+			network.MustNewIngressRule("tcp", 37017, 37017, "10.0.0.1/32"),
+		},
+	}
+	s.environ = &fakeEnviron{set: make(chan []network.IngressRule, 1)}
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) worker.Worker {
+	w, err := controllerfirewaller.New(controllerfirewaller.Config{
+		Facade:  s.facade,
+		Environ: s.environ,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) {
+		c.Check(worker.Stop(w), jc.ErrorIsNil)
+	})
+	return w
+}
+
+func (s *WorkerSuite) assertRulesSet(c *gc.C, expect []network.IngressRule) {
+	select {
+	case rules := <-s.environ.set:
+		c.Assert(rules, jc.DeepEquals, expect)
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for ingress rules to be set")
+	}
+}
+
+func (s *WorkerSuite) assertRulesNotSet(c *gc.C) {
+	select {
+	case rules := <-s.environ.set:
+		c.Fatalf("unexpected ingress rules %v", rules)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	_, err := controllerfirewaller.New(controllerfirewaller.Config{Environ: s.environ})
+	c.Assert(err, gc.ErrorMatches, "nil Facade not valid")
+	_, err = controllerfirewaller.New(controllerfirewaller.Config{Facade: s.facade})
+	c.Assert(err, gc.ErrorMatches, "nil Environ not valid")
+}
+
+func (s *WorkerSuite) TestSetsRulesOnStartup(c *gc.C) {
+	s.startWorker(c)
+	s.facade.changes <- struct{}{}
+	s.assertRulesSet(c, s.facade.rules)
+	c.Assert(s.environ.controllerUUID, gc.Equals, coretesting.ControllerTag.Id())
+}
+
+func (s *WorkerSuite) TestSetsChangedRules(c *gc.C) {
+	s.startWorker(c)
+	s.facade.changes <- struct{}{}
+	s.assertRulesSet(c, s.facade.rules)
+
+	s.facade.rules = []network.IngressRule{
+		network.MustNewIngressRule("tcp", 17070, 17070, "192.0.2.0/24"),
+		network.MustNewIngressRule("tcp", 37017, 37017, "10.0.0.1/32"),
+	}
+	s.facade.changes <- struct{}{}
+	s.assertRulesSet(c, s.facade.rules)
+}
+
+func (s *WorkerSuite) TestIgnoresUnchangedRules(c *gc.C) {
+	s.startWorker(c)
+	s.facade.changes <- struct{}{}
+	s.assertRulesSet(c, s.facade.rules)
+
+	s.facade.changes <- struct{}{}
+	s.assertRulesNotSet(c)
+}
+
+func (s *WorkerSuite) TestUninstallsOutsideControllerModel(c *gc.C) {
+	s.facade.watchErr = &params.Error{Code: params.CodeNotSupported, Message: "not supported"}
+	w, err := controllerfirewaller.New(controllerfirewaller.Config{
+		Facade:  s.facade,
+		Environ: s.environ,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w.Wait(), gc.Equals, dependency.ErrUninstall)
+}
+
+type fakeFacade struct {
+	changes  chan struct{}
+	rules    []network.IngressRule
+	watchErr error
+}
+
+// ControllerIngressRules is part of the controllerfirewaller.Facade interface.
+func (f *fakeFacade) ControllerIngressRules() (string, []network.IngressRule, error) {
+	return coretesting.ControllerTag.Id(), f.rules, nil
+}
+
+// WatchControllerIngressRules is part of the controllerfirewaller.Facade interface.
+func (f *fakeFacade) WatchControllerIngressRules() (watcher.NotifyWatcher, error) {
+	if f.watchErr != nil {
+		return nil, f.watchErr
+	}
+	return newMockNotifyWatcher(f.changes), nil
+}
+
+type fakeEnviron struct {
+	controllerUUID string
+	set            chan []network.IngressRule
+}
+
+// SetControllerIngressRules is part of the environs.ControllerFirewaller
+// interface.
+func (e *fakeEnviron) SetControllerIngressRules(controllerUUID string, rules []network.IngressRule) error {
+	e.controllerUUID = controllerUUID
+	e.set <- rules
+	return nil
+}
+
+type mockNotifyWatcher struct {
+	tomb tomb.Tomb
+	ch   chan struct{}
+}
+
+func newMockNotifyWatcher(ch chan struct{}) *mockNotifyWatcher {
+	w := &mockNotifyWatcher{ch: ch}
+	go func() {
+		defer w.tomb.Done()
+		<-w.tomb.Dying()
+	}()
+	return w
+}
+
+func (w *mockNotifyWatcher) Changes() watcher.NotifyChannel {
+	return w.ch
+}
+
+func (w *mockNotifyWatcher) Kill() {
+	w.tomb.Kill(nil)
+}
+
+func (w *mockNotifyWatcher) Wait() error {
+	return w.tomb.Wait()
+}
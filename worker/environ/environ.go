@@ -109,6 +109,12 @@ func (t *Tracker) loop() error {
 		if err != nil {
 			return errors.Annotate(err, "cannot read environ config")
 		}
+		// Reading the cloud spec fails while a provider fault is
+		// injected into the model; stopping here makes the workers
+		// using the environ restart and fail to open it in turn.
+		if _, err := t.config.Observer.CloudSpec(); err != nil {
+			return errors.Annotate(err, "cannot read environ cloud spec")
+		}
 		if err = t.environ.SetConfig(modelConfig); err != nil {
 			return errors.Annotate(err, "cannot update environ config")
 		}
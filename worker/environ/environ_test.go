@@ -180,6 +180,27 @@ func (s *TrackerSuite) TestWatchedModelConfigFails(c *gc.C) {
 	})
 }
 
+func (s *TrackerSuite) TestWatchedCloudSpecFails(c *gc.C) {
+	fix := &fixture{
+		observerErrs: []error{
+			nil, nil, nil, nil, errors.New("provider API unavailable"),
+		},
+	}
+	fix.Run(c, func(context *runContext) {
+		tracker, err := environ.NewTracker(environ.Config{
+			Observer:       context,
+			NewEnvironFunc: newMockEnviron,
+		})
+		c.Check(err, jc.ErrorIsNil)
+		defer workertest.DirtyKill(c, tracker)
+
+		context.SendModelConfigNotify()
+		err = workertest.CheckKilled(c, tracker)
+		c.Check(err, gc.ErrorMatches, "cannot read environ cloud spec: provider API unavailable")
+		context.CheckCallNames(c, "ModelConfig", "CloudSpec", "WatchForModelConfigChanges", "ModelConfig", "CloudSpec")
+	})
+}
+
 func (s *TrackerSuite) TestWatchedModelConfigIncompatible(c *gc.C) {
 	fix := &fixture{}
 	fix.Run(c, func(context *runContext) {
@@ -197,7 +218,7 @@ func (s *TrackerSuite) TestWatchedModelConfigIncompatible(c *gc.C) {
 		context.SendModelConfigNotify()
 		err = workertest.CheckKilled(c, tracker)
 		c.Check(err, gc.ErrorMatches, "cannot update environ config: SetConfig is broken")
-		context.CheckCallNames(c, "ModelConfig", "CloudSpec", "WatchForModelConfigChanges", "ModelConfig")
+		context.CheckCallNames(c, "ModelConfig", "CloudSpec", "WatchForModelConfigChanges", "ModelConfig", "CloudSpec")
 	})
 }
 
@@ -238,6 +259,6 @@ func (s *TrackerSuite) TestWatchedModelConfigUpdates(c *gc.C) {
 			}
 			break
 		}
-		context.CheckCallNames(c, "ModelConfig", "CloudSpec", "WatchForModelConfigChanges", "ModelConfig")
+		context.CheckCallNames(c, "ModelConfig", "CloudSpec", "WatchForModelConfigChanges", "ModelConfig", "CloudSpec")
 	})
 }
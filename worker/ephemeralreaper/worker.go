@@ -0,0 +1,146 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ephemeralreaper
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/ephemeralreaper"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+var logger = loggo.GetLogger("juju.worker.ephemeralreaper")
+
+// Facade represents the API used by the worker to destroy an expired
+// ephemeral model.
+type Facade interface {
+	DestroyModel() error
+	WatchModel() (watcher.NotifyWatcher, error)
+	ModelExpiry() (time.Time, bool, error)
+}
+
+// NewFacade returns a Facade backed by the EphemeralReaper API.
+func NewFacade(caller base.APICaller) Facade {
+	return ephemeralreaper.NewFacade(caller)
+}
+
+// Config holds the resources and configuration needed to run the worker.
+type Config struct {
+	Facade Facade
+	Clock  clock.Clock
+}
+
+// Validate returns an error if the config cannot be used to start
+// a worker.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	return nil
+}
+
+// New returns a worker that destroys the model once its ephemeral
+// expiry has passed. Models without an expiry are left alone.
+func New(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &reaperWorker{config: config}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type reaperWorker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *reaperWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *reaperWorker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *reaperWorker) loop() error {
+	modelWatcher, err := w.config.Facade.WatchModel()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := w.catacomb.Add(modelWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
+	var expiry time.Time
+	var timer clock.Timer
+	var timerCh <-chan time.Time
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+
+		case _, ok := <-modelWatcher.Changes():
+			if !ok {
+				return errors.New("model watcher closed")
+			}
+			newExpiry, ok, err := w.config.Facade.ModelExpiry()
+			if err != nil {
+				return errors.Annotate(err, "cannot get model expiry")
+			}
+			if !ok {
+				if timerCh != nil {
+					logger.Infof("model is no longer ephemeral")
+					timer.Stop()
+				}
+				expiry = time.Time{}
+				timerCh = nil
+				continue
+			}
+			if timerCh != nil && newExpiry.Equal(expiry) {
+				continue
+			}
+			expiry = newExpiry
+			delay := expiry.Sub(w.config.Clock.Now())
+			if delay < 0 {
+				delay = 0
+			}
+			logger.Infof("ephemeral model will be destroyed at %v", expiry)
+			// A fresh timer avoids picking up a stale expiry from
+			// one that fired while the expiry was being changed.
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = w.config.Clock.NewTimer(delay)
+			timerCh = timer.Chan()
+
+		case <-timerCh:
+			logger.Infof("ephemeral model expired at %v; destroying it", expiry)
+			if err := w.config.Facade.DestroyModel(); err != nil {
+				return errors.Annotate(err, "cannot destroy expired model")
+			}
+			// The model is now dying; there is nothing more to do
+			// unless it is given a new expiry.
+			timerCh = nil
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ephemeralreaper_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/tomb.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/ephemeralreaper"
+)
+
+type WorkerSuite struct {
+	coretesting.BaseSuite
+	facade *fakeFacade
+	clock  *testing.Clock
+	now    time.Time
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.now = time.Date(2018, 4, 1, 12, 0, 0, 0, time.UTC)
+	s.facade = &fakeFacade{
+		changes:   make(chan struct{}, 1),
+		destroyed: make(chan struct{}, 1),
+	}
+	s.setExpiry(c, s.now.Add(2*time.Hour))
+	s.clock = testing.NewClock(s.now)
+}
+
+func (s *WorkerSuite) setExpiry(c *gc.C, expiry time.Time) {
+	s.facade.mu.Lock()
+	defer s.facade.mu.Unlock()
+	s.facade.expiry = expiry
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) worker.Worker {
+	w, err := ephemeralreaper.New(ephemeralreaper.Config{
+		Facade: s.facade,
+		Clock:  s.clock,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) {
+		c.Check(worker.Stop(w), jc.ErrorIsNil)
+	})
+	s.facade.changes <- struct{}{}
+	return w
+}
+
+// waitTimer waits for the worker to set its expiry timer.
+func (s *WorkerSuite) waitTimer(c *gc.C) {
+	select {
+	case <-s.clock.Alarms():
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for expiry timer")
+	}
+}
+
+func (s *WorkerSuite) assertDestroyed(c *gc.C) {
+	select {
+	case <-s.facade.destroyed:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for model to be destroyed")
+	}
+}
+
+func (s *WorkerSuite) assertNotDestroyed(c *gc.C) {
+	select {
+	case <-s.facade.destroyed:
+		c.Fatal("unexpected destroy")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	_, err := ephemeralreaper.New(ephemeralreaper.Config{Clock: s.clock})
+	c.Assert(err, gc.ErrorMatches, "nil Facade not valid")
+	_, err = ephemeralreaper.New(ephemeralreaper.Config{Facade: s.facade})
+	c.Assert(err, gc.ErrorMatches, "nil Clock not valid")
+}
+
+func (s *WorkerSuite) TestDestroysAtExpiry(c *gc.C) {
+	s.startWorker(c)
+	s.waitTimer(c)
+
+	s.clock.Advance(2*time.Hour - time.Nanosecond)
+	s.assertNotDestroyed(c)
+	s.clock.Advance(time.Nanosecond)
+	s.assertDestroyed(c)
+}
+
+func (s *WorkerSuite) TestDestroysImmediatelyWhenExpired(c *gc.C) {
+	s.setExpiry(c, s.now.Add(-time.Minute))
+	s.startWorker(c)
+	s.waitTimer(c)
+
+	s.clock.Advance(0)
+	s.assertDestroyed(c)
+}
+
+func (s *WorkerSuite) TestExpiryExtended(c *gc.C) {
+	s.startWorker(c)
+	s.waitTimer(c)
+	s.clock.Advance(time.Hour)
+
+	s.setExpiry(c, s.now.Add(3*time.Hour))
+	s.facade.changes <- struct{}{}
+	s.waitTimer(c)
+	s.clock.Advance(2*time.Hour - time.Nanosecond)
+	s.assertNotDestroyed(c)
+	s.clock.Advance(time.Nanosecond)
+	s.assertDestroyed(c)
+}
+
+func (s *WorkerSuite) TestNotEphemeral(c *gc.C) {
+	s.setExpiry(c, time.Time{})
+	s.startWorker(c)
+
+	s.clock.Advance(24 * time.Hour)
+	s.assertNotDestroyed(c)
+}
+
+type fakeFacade struct {
+	changes   chan struct{}
+	destroyed chan struct{}
+
+	mu     sync.Mutex
+	expiry time.Time
+}
+
+// DestroyModel is part of the ephemeralreaper.Facade interface.
+func (f *fakeFacade) DestroyModel() error {
+	f.destroyed <- struct{}{}
+	return nil
+}
+
+// WatchModel is part of the ephemeralreaper.Facade interface.
+func (f *fakeFacade) WatchModel() (watcher.NotifyWatcher, error) {
+	return newMockNotifyWatcher(f.changes), nil
+}
+
+// ModelExpiry is part of the ephemeralreaper.Facade interface.
+func (f *fakeFacade) ModelExpiry() (time.Time, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.expiry, !f.expiry.IsZero(), nil
+}
+
+type mockNotifyWatcher struct {
+	tomb tomb.Tomb
+	ch   chan struct{}
+}
+
+func newMockNotifyWatcher(ch chan struct{}) *mockNotifyWatcher {
+	w := &mockNotifyWatcher{ch: ch}
+	go func() {
+		defer w.tomb.Done()
+		<-w.tomb.Dying()
+	}()
+	return w
+}
+
+func (w *mockNotifyWatcher) Changes() watcher.NotifyChannel {
+	return w.ch
+}
+
+func (w *mockNotifyWatcher) Kill() {
+	w.tomb.Kill(nil)
+}
+
+func (w *mockNotifyWatcher) Wait() error {
+	return w.tomb.Wait()
+}
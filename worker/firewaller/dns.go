@@ -0,0 +1,246 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewaller
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/dns"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+)
+
+// setUpDNSRecords starts watching the model's annotations, so that
+// the DNS records of exposed applications follow changes to their
+// hostnames.
+func (fw *Firewaller) setUpDNSRecords() error {
+	if fw.environ == nil {
+		return nil
+	}
+	w, err := fw.firewallerApi.WatchAnnotations()
+	if errors.IsNotSupported(err) {
+		logger.Debugf("not watching application hostnames: %v", err)
+		return nil
+	} else if err != nil {
+		return errors.Annotate(err, "cannot watch annotations")
+	}
+	if err := fw.catacomb.Add(w); err != nil {
+		return errors.Trace(err)
+	}
+	fw.annotationsWatcher = w
+	return nil
+}
+
+// annotationsChanged refreshes the hostnames of the applications
+// known to the firewaller, and updates their DNS records.
+func (fw *Firewaller) annotationsChanged() error {
+	for _, applicationd := range fw.applicationids {
+		hostname, err := applicationd.application.Hostname()
+		if params.IsCodeNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		applicationd.hostname = hostname
+	}
+	return fw.flushDNSRecords()
+}
+
+// openDNSZone returns the DNS zone selected by the model config, or
+// nil if DNS records are not managed for the model. When the config
+// selects a different zone, the records managed in the previous zone
+// are removed from it.
+func (fw *Firewaller) openDNSZone() (dns.Zone, error) {
+	cfg := fw.environ.Config()
+	key := cfg.DNSProvider() + ":" + cfg.DNSZone()
+	if key == fw.dnsZoneKey {
+		return fw.dnsZone, nil
+	}
+	if fw.dnsZone != nil {
+		for name := range fw.dnsRecords {
+			if err := fw.removeDNSRecord(name); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+	}
+	fw.dnsZone = nil
+	fw.dnsRecords = make(map[string]dns.Record)
+	if cfg.DNSProvider() == "" {
+		fw.dnsZoneKey = key
+		return nil, nil
+	}
+	zone, err := dns.Open(fw.environ)
+	if errors.IsNotFound(err) {
+		// Don't keep trying until the config changes.
+		logger.Errorf("not managing DNS records: %v", err)
+		fw.dnsZoneKey = key
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	existing, err := zone.Records()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot list DNS records")
+	}
+	for _, record := range existing {
+		// The zero record never matches a wanted record, so the
+		// record is updated if it is still wanted.
+		fw.dnsRecords[record.Name] = dns.Record{}
+	}
+	fw.dnsZone, fw.dnsZoneKey = zone, key
+	return zone, nil
+}
+
+// flushDNSRecords publishes the hostname of each exposed application
+// in the model's DNS zone, and removes the records of applications
+// that are no longer exposed or have no hostname.
+func (fw *Firewaller) flushDNSRecords() error {
+	if fw.environ == nil {
+		return nil
+	}
+	zone, err := fw.openDNSZone()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if zone == nil {
+		return nil
+	}
+	want, err := fw.gatherDNSRecords()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for name, record := range want {
+		if current, ok := fw.dnsRecords[name]; ok && reflect.DeepEqual(current, record) {
+			continue
+		}
+		if err := fw.retry(func() error {
+			return zone.EnsureRecord(record)
+		}); err != nil {
+			return errors.Annotatef(err, "cannot update DNS record %q", name)
+		}
+		logger.Infof("published %q at %v", name, record.Addresses)
+		fw.dnsRecords[name] = record
+	}
+	for name := range fw.dnsRecords {
+		if _, ok := want[name]; ok {
+			continue
+		}
+		if err := fw.removeDNSRecord(name); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func (fw *Firewaller) removeDNSRecord(name string) error {
+	if err := fw.retry(func() error {
+		return fw.dnsZone.RemoveRecord(name)
+	}); err != nil {
+		return errors.Annotatef(err, "cannot remove DNS record %q", name)
+	}
+	logger.Infof("removed DNS record %q", name)
+	delete(fw.dnsRecords, name)
+	return nil
+}
+
+// gatherDNSRecords returns the DNS records wanted for the exposed
+// applications with a hostname in the model's DNS zone, keyed on
+// hostname.
+func (fw *Firewaller) gatherDNSRecords() (map[string]dns.Record, error) {
+	cfg := fw.environ.Config()
+	want := make(map[string]dns.Record)
+	for _, applicationd := range fw.applicationids {
+		hostname := strings.TrimSuffix(applicationd.hostname, ".")
+		if hostname == "" || len(applicationd.exposedEndpoints) == 0 {
+			continue
+		}
+		if !dns.InZone(hostname, cfg.DNSZone()) {
+			logger.Warningf(
+				"not publishing application %q: hostname %q is not in DNS zone %q",
+				applicationd.application.Name(), hostname, cfg.DNSZone(),
+			)
+			continue
+		}
+		addrs, err := fw.applicationAddresses(applicationd)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(addrs) == 0 {
+			continue
+		}
+		want[hostname] = dns.Record{
+			Name:      hostname,
+			TTL:       cfg.DNSTTL(),
+			Addresses: addrs,
+		}
+	}
+	return want, nil
+}
+
+// applicationAddresses returns the addresses at which the application
+// is published: those of the load balancers provisioned for its
+// endpoints if there are any, or else the public addresses of the
+// instances hosting its units.
+func (fw *Firewaller) applicationAddresses(applicationd *applicationData) ([]network.Address, error) {
+	var addrs []network.Address
+	var ids []instance.Id
+	for _, unitd := range applicationd.unitds {
+		machined := unitd.machined
+		for _, endpoint := range machined.definedPorts[unitd.tag] {
+			if endpoint == "" {
+				continue
+			}
+			name := loadBalancerName(fw.modelUUID, applicationd.application.Tag(), endpoint)
+			for _, addr := range fw.loadBalancerAddresses[name] {
+				addrs = appendAddress(addrs, addr)
+			}
+		}
+		instanceId, err := machined.instanceId()
+		if params.IsCodeNotProvisioned(err) || params.IsCodeNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ids = append(ids, instanceId)
+	}
+	if len(addrs) == 0 && len(ids) > 0 {
+		insts, err := fw.environInstances.Instances(ids)
+		if err == environs.ErrNoInstances {
+			return nil, nil
+		} else if err != nil && err != environs.ErrPartialInstances {
+			return nil, errors.Annotate(err, "cannot get instances")
+		}
+		for _, inst := range insts {
+			if inst == nil {
+				continue
+			}
+			instAddrs, err := inst.Addresses()
+			if err != nil {
+				return nil, errors.Annotatef(err, "cannot get addresses of instance %q", inst.Id())
+			}
+			if addr, ok := network.SelectPublicAddress(instAddrs); ok {
+				addrs = appendAddress(addrs, addr)
+			}
+		}
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].Value < addrs[j].Value
+	})
+	return addrs, nil
+}
+
+func appendAddress(addrs []network.Address, addr network.Address) []network.Address {
+	for _, existing := range addrs {
+		if existing.Value == addr.Value {
+			return addrs
+		}
+	}
+	return append(addrs, addr)
+}
@@ -24,6 +24,8 @@ import (
 	"github.com/juju/juju/core/relation"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/dns"
+	"github.com/juju/juju/environs/providerretry"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/watcher"
@@ -34,6 +36,7 @@ import (
 type FirewallerAPI interface {
 	WatchModelMachines() (watcher.StringsWatcher, error)
 	WatchOpenedPorts() (watcher.StringsWatcher, error)
+	WatchAnnotations() (watcher.NotifyWatcher, error)
 	Machine(tag names.MachineTag) (*firewaller.Machine, error)
 	Unit(tag names.UnitTag) (*firewaller.Unit, error)
 	Relation(tag names.RelationTag) (*firewaller.Relation, error)
@@ -82,8 +85,21 @@ type Config struct {
 	EnvironFirewaller  EnvironFirewaller
 	EnvironInstances   EnvironInstances
 
+	// EnvironLoadBalancers, if not nil, is used to provision load
+	// balancers for the exposed endpoints of applications.
+	EnvironLoadBalancers environs.LoadBalancerEnviron
+
+	// Environ, if not nil, is used to open the DNS zone selected by
+	// the model config, in which the hostnames of exposed applications
+	// are published.
+	Environ environs.Environ
+
 	NewCrossModelFacadeFunc newCrossModelFacadeFunc
 
+	// RetryStrategy describes how failed attempts to open and close
+	// ports in the cloud are retried. The zero value disables retries.
+	RetryStrategy providerretry.Strategy
+
 	Clock clock.Clock
 }
 
@@ -110,7 +126,10 @@ func (cfg Config) Validate() error {
 	return nil
 }
 
-type portRanges map[network.PortRange]bool
+// portRanges maps the port ranges opened by a unit to the endpoint
+// each was opened for, which is empty if it was opened for all the
+// unit's endpoints.
+type portRanges map[network.PortRange]string
 
 // Firewaller watches the state for port ranges opened or closed on
 // machines and reflects those changes onto the backing environment.
@@ -122,6 +141,17 @@ type Firewaller struct {
 	environFirewaller  EnvironFirewaller
 	environInstances   EnvironInstances
 
+	environLoadBalancers     environs.LoadBalancerEnviron
+	loadBalancers            map[string]environs.LoadBalancerSpec
+	loadBalancerAddresses    map[string][]network.Address
+	loadBalancerApplications map[string]names.ApplicationTag
+
+	environ            environs.Environ
+	annotationsWatcher watcher.NotifyWatcher
+	dnsZone            dns.Zone
+	dnsZoneKey         string
+	dnsRecords         map[string]dns.Record
+
 	machinesWatcher      watcher.StringsWatcher
 	portsWatcher         watcher.StringsWatcher
 	machineds            map[names.MachineTag]*machineData
@@ -139,6 +169,7 @@ type Firewaller struct {
 	relationIngress            map[names.RelationTag]*remoteRelationData
 	relationWorkerRunner       *worker.Runner
 	pollClock                  clock.Clock
+	retryStrategy              providerretry.Strategy
 }
 
 // NewFirewaller returns a new Firewaller.
@@ -155,6 +186,12 @@ func NewFirewaller(cfg Config) (worker.Worker, error) {
 		remoteRelationsApi:         cfg.RemoteRelationsApi,
 		environFirewaller:          cfg.EnvironFirewaller,
 		environInstances:           cfg.EnvironInstances,
+		environLoadBalancers:       cfg.EnvironLoadBalancers,
+		loadBalancers:              make(map[string]environs.LoadBalancerSpec),
+		loadBalancerAddresses:      make(map[string][]network.Address),
+		loadBalancerApplications:   make(map[string]names.ApplicationTag),
+		environ:                    cfg.Environ,
+		dnsRecords:                 make(map[string]dns.Record),
 		newRemoteFirewallerAPIFunc: cfg.NewCrossModelFacadeFunc,
 		modelUUID:                  cfg.ModelUUID,
 		machineds:                  make(map[names.MachineTag]*machineData),
@@ -165,6 +202,7 @@ func NewFirewaller(cfg Config) (worker.Worker, error) {
 		relationIngress:            make(map[names.RelationTag]*remoteRelationData),
 		localRelationsChange:       make(chan *remoteRelationNetworkChange),
 		pollClock:                  clk,
+		retryStrategy:              cfg.RetryStrategy,
 		relationWorkerRunner: worker.NewRunner(worker.RunnerParams{
 			Clock: clk,
 
@@ -215,6 +253,14 @@ func (fw *Firewaller) setUp() error {
 		return errors.Trace(err)
 	}
 
+	if err := fw.setUpLoadBalancers(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if err := fw.setUpDNSRecords(); err != nil {
+		return errors.Trace(err)
+	}
+
 	fw.remoteRelationsWatcher, err = fw.remoteRelationsApi.WatchRemoteRelations()
 	if err != nil {
 		return errors.Trace(err)
@@ -233,6 +279,10 @@ func (fw *Firewaller) loop() error {
 	}
 	var reconciled bool
 	portsChange := fw.portsWatcher.Changes()
+	var annotationsChange watcher.NotifyChannel
+	if fw.annotationsWatcher != nil {
+		annotationsChange = fw.annotationsWatcher.Changes()
+	}
 	for {
 		select {
 		case <-fw.catacomb.Dying():
@@ -257,6 +307,12 @@ func (fw *Firewaller) loop() error {
 				if err != nil {
 					return errors.Trace(err)
 				}
+				if err := fw.flushLoadBalancers(nil); err != nil {
+					return errors.Trace(err)
+				}
+				if err := fw.flushDNSRecords(); err != nil {
+					return errors.Trace(err)
+				}
 			}
 		case change, ok := <-portsChange:
 			if !ok {
@@ -271,6 +327,13 @@ func (fw *Firewaller) loop() error {
 					return errors.Trace(err)
 				}
 			}
+		case _, ok := <-annotationsChange:
+			if !ok {
+				return errors.New("annotations watcher closed")
+			}
+			if err := fw.annotationsChanged(); err != nil {
+				return errors.Trace(err)
+			}
 		case change, ok := <-fw.remoteRelationsWatcher.Changes():
 			if !ok {
 				return errors.New("remote relations watcher closed")
@@ -292,7 +355,7 @@ func (fw *Firewaller) loop() error {
 				return errors.Trace(err)
 			}
 		case change := <-fw.exposedChange:
-			change.applicationd.exposed = change.exposed
+			change.applicationd.exposedEndpoints = change.exposedEndpoints
 			unitds := []*unitData{}
 			for _, unitd := range change.applicationd.unitds {
 				unitds = append(unitds, unitd)
@@ -335,6 +398,8 @@ func (fw *Firewaller) startMachine(tag names.MachineTag) error {
 		unitds:       make(map[names.UnitTag]*unitData),
 		ingressRules: make([]network.IngressRule, 0),
 		definedPorts: make(map[names.UnitTag]portRanges),
+
+		loadBalancedApplications: make(map[names.ApplicationTag]bool),
 	}
 	m, err := machined.machine()
 	if params.IsCodeNotFound(err) {
@@ -443,22 +508,29 @@ func (fw *Firewaller) startUnit(unit *firewaller.Unit, machineTag names.MachineT
 // startApplication creates a new data value for tracking details of the
 // application and starts watching the application for exposure changes.
 func (fw *Firewaller) startApplication(app *firewaller.Application) error {
-	exposed, err := app.IsExposed()
+	exposedEndpoints, err := app.ExposedEndpointCIDRs()
 	if err != nil {
 		return err
 	}
+	var hostname string
+	if fw.environ != nil {
+		if hostname, err = app.Hostname(); err != nil {
+			return err
+		}
+	}
 	applicationd := &applicationData{
-		fw:          fw,
-		application: app,
-		exposed:     exposed,
-		unitds:      make(map[names.UnitTag]*unitData),
+		fw:               fw,
+		application:      app,
+		exposedEndpoints: exposedEndpoints,
+		hostname:         hostname,
+		unitds:           make(map[names.UnitTag]*unitData),
 	}
 	fw.applicationids[app.Tag()] = applicationd
 
 	err = catacomb.Invoke(catacomb.Plan{
 		Site: &applicationd.catacomb,
 		Work: func() error {
-			return applicationd.watchLoop(exposed)
+			return applicationd.watchLoop(exposedEndpoints)
 		},
 	})
 	if err != nil {
@@ -548,16 +620,18 @@ func (fw *Firewaller) reconcileInstances() error {
 		if len(toOpen) > 0 {
 			logger.Infof("opening instance port ranges %v for %q",
 				toOpen, machined.tag)
-			if err := fwInstance.OpenPorts(machineId, toOpen); err != nil {
-				// TODO(mue) Add local retry logic.
+			if err := fw.retry(func() error {
+				return fwInstance.OpenPorts(machineId, toOpen)
+			}); err != nil {
 				return err
 			}
 		}
 		if len(toClose) > 0 {
 			logger.Infof("closing instance port ranges %v for %q",
 				toClose, machined.tag)
-			if err := fwInstance.ClosePorts(machineId, toClose); err != nil {
-				// TODO(mue) Add local retry logic.
+			if err := fw.retry(func() error {
+				return fwInstance.ClosePorts(machineId, toClose)
+			}); err != nil {
 				return err
 			}
 		}
@@ -623,19 +697,19 @@ func (fw *Firewaller) openedPortsChanged(machineTag names.MachineTag, subnetTag
 		return err
 	}
 
-	ports, err := m.OpenedPorts(subnetTag)
+	ports, err := m.OpenedPortRanges(subnetTag)
 	if err != nil {
 		return err
 	}
 
 	newPortRanges := make(map[names.UnitTag]portRanges)
-	for portRange, unitTag := range ports {
-		unitd, ok := machined.unitds[unitTag]
+	for portRange, owner := range ports {
+		unitd, ok := machined.unitds[owner.Unit]
 		if !ok {
 			// It is common to receive port change notification before
 			// registering a unit. Skip handling the port change - it will
 			// be handled when the unit is registered.
-			logger.Debugf("failed to lookup %q, skipping port change", unitTag)
+			logger.Debugf("failed to lookup %q, skipping port change", owner.Unit)
 			return nil
 		}
 		ranges, ok := newPortRanges[unitd.tag]
@@ -643,7 +717,7 @@ func (fw *Firewaller) openedPortsChanged(machineTag names.MachineTag, subnetTag
 			ranges = make(portRanges)
 			newPortRanges[unitd.tag] = ranges
 		}
-		ranges[portRange] = true
+		ranges[portRange] = owner.Endpoint
 	}
 
 	if !unitPortsEqual(machined.definedPorts, newPortRanges) {
@@ -708,9 +782,26 @@ func (fw *Firewaller) flushMachine(machined *machineData) error {
 	toOpen, toClose := diffRanges(machined.ingressRules, want)
 	machined.ingressRules = want
 	if fw.globalMode {
-		return fw.flushGlobalPorts(toOpen, toClose)
+		err = fw.flushGlobalPorts(toOpen, toClose)
+	} else {
+		err = fw.flushInstancePorts(machined, toOpen, toClose)
 	}
-	return fw.flushInstancePorts(machined, toOpen, toClose)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Only the load balancers of applications with units on the
+	// machine, now or at the last flush, can have changed.
+	applications := machined.loadBalancedApplications
+	machined.loadBalancedApplications = make(map[names.ApplicationTag]bool)
+	for _, unitd := range machined.unitds {
+		appTag := unitd.applicationd.application.Tag()
+		applications[appTag] = true
+		machined.loadBalancedApplications[appTag] = true
+	}
+	if err := fw.flushLoadBalancers(applications); err != nil {
+		return errors.Trace(err)
+	}
+	return fw.flushDNSRecords()
 }
 
 // gatherIngressRules returns the ingress rules to open and close
@@ -725,26 +816,36 @@ func (fw *Firewaller) gatherIngressRules(machines ...*machineData) ([]network.In
 				continue
 			}
 
-			cidrs := set.NewStrings()
-			// If the unit is exposed, allow access from everywhere.
-			if unitd.applicationd.exposed {
-				cidrs.Add("0.0.0.0/0")
-			} else {
-				// Not exposed, so add any ingress rules required by remote relations.
-				if err := fw.updateForRemoteRelationIngress(unitd.applicationd.application.Tag(), cidrs); err != nil {
-					return nil, errors.Trace(err)
+			// The remote relation CIDRs are only needed for port
+			// ranges whose endpoints are not exposed.
+			var remoteCidrs set.Strings
+			for portRange, endpoint := range portRanges {
+				// If the port range's endpoint is exposed, allow
+				// access from the CIDRs it is exposed to.
+				exposedEndpoints := unitd.applicationd.exposedEndpoints
+				sourceCidrs, exposed := exposedEndpoints[endpoint]
+				if !exposed {
+					sourceCidrs, exposed = exposedEndpoints[""]
 				}
-				logger.Debugf("CIDRS for %v: %v", unitTag, cidrs.Values())
-			}
-			if cidrs.Size() > 0 {
-				for portRange := range portRanges {
-					sourceCidrs := cidrs.SortedValues()
-					rule, err := network.NewIngressRule(portRange.Protocol, portRange.FromPort, portRange.ToPort, sourceCidrs...)
-					if err != nil {
-						return nil, errors.Trace(err)
+				if !exposed {
+					// Not exposed, so add any ingress rules required by remote relations.
+					if remoteCidrs == nil {
+						remoteCidrs = set.NewStrings()
+						if err := fw.updateForRemoteRelationIngress(unitd.applicationd.application.Tag(), remoteCidrs); err != nil {
+							return nil, errors.Trace(err)
+						}
+						logger.Debugf("CIDRS for %v: %v", unitTag, remoteCidrs.Values())
 					}
-					want = append(want, rule)
+					sourceCidrs = remoteCidrs.SortedValues()
+				}
+				if len(sourceCidrs) == 0 {
+					continue
 				}
+				rule, err := network.NewIngressRule(portRange.Protocol, portRange.FromPort, portRange.ToPort, sourceCidrs...)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				want = append(want, rule)
 			}
 		}
 	}
@@ -831,16 +932,18 @@ func (fw *Firewaller) flushGlobalPorts(rawOpen, rawClose []network.IngressRule)
 	}
 	// Open and close the ports.
 	if len(toOpen) > 0 {
-		if err := fw.environFirewaller.OpenPorts(toOpen); err != nil {
-			// TODO(mue) Add local retry logic.
+		if err := fw.retry(func() error {
+			return fw.environFirewaller.OpenPorts(toOpen)
+		}); err != nil {
 			return err
 		}
 		network.SortIngressRules(toOpen)
 		logger.Infof("opened port ranges %v in environment", toOpen)
 	}
 	if len(toClose) > 0 {
-		if err := fw.environFirewaller.ClosePorts(toClose); err != nil {
-			// TODO(mue) Add local retry logic.
+		if err := fw.retry(func() error {
+			return fw.environFirewaller.ClosePorts(toClose)
+		}); err != nil {
 			return err
 		}
 		network.SortIngressRules(toClose)
@@ -887,16 +990,18 @@ func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose
 
 	// Open and close the ports.
 	if len(toOpen) > 0 {
-		if err := fwInstance.OpenPorts(machineId, toOpen); err != nil {
-			// TODO(mue) Add local retry logic.
+		if err := fw.retry(func() error {
+			return fwInstance.OpenPorts(machineId, toOpen)
+		}); err != nil {
 			return err
 		}
 		network.SortIngressRules(toOpen)
 		logger.Infof("opened port ranges %v on %q", toOpen, machined.tag)
 	}
 	if len(toClose) > 0 {
-		if err := fwInstance.ClosePorts(machineId, toClose); err != nil {
-			// TODO(mue) Add local retry logic.
+		if err := fw.retry(func() error {
+			return fwInstance.ClosePorts(machineId, toClose)
+		}); err != nil {
 			return err
 		}
 		network.SortIngressRules(toClose)
@@ -905,6 +1010,28 @@ func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose
 	return nil
 }
 
+// retry calls the given cloud firewall operation until it succeeds or
+// the firewaller's retry strategy gives up on it. The firewaller's loop
+// waits for the retries, so transient failures are limited to the same
+// number of attempts as other failures.
+func (fw *Firewaller) retry(op func() error) error {
+	strategy := fw.retryStrategy
+	strategy.TransientAttempts = strategy.Attempts
+	err := providerretry.Call(providerretry.CallArgs{
+		Strategy: strategy,
+		Clock:    fw.pollClock,
+		Func:     op,
+		NotifyFunc: func(err error, attempts int, delay time.Duration) {
+			logger.Warningf("firewall operation failed (attempt %d), retrying in %v: %v", attempts, delay, err)
+		},
+		Stop: fw.catacomb.Dying(),
+	})
+	if err == providerretry.ErrStopped {
+		return fw.catacomb.ErrDying()
+	}
+	return err
+}
+
 // machineLifeChanged starts watching new machines when the firewaller
 // is starting, or when new machines come to life, and stops watching
 // machines that are dying.
@@ -1002,12 +1129,33 @@ type machineData struct {
 	ingressRules []network.IngressRule
 	// ports defined by units on this machine
 	definedPorts map[names.UnitTag]portRanges
+	// id of the machine's instance, once it has been looked up
+	instId instance.Id
+	// applications with units on this machine at the last flush
+	loadBalancedApplications map[names.ApplicationTag]bool
 }
 
 func (md *machineData) machine() (*firewaller.Machine, error) {
 	return md.fw.firewallerApi.Machine(md.tag)
 }
 
+// instanceId returns the id of the machine's instance.
+func (md *machineData) instanceId() (instance.Id, error) {
+	if md.instId != "" {
+		return md.instId, nil
+	}
+	m, err := md.machine()
+	if err != nil {
+		return "", err
+	}
+	instId, err := m.InstanceId()
+	if err != nil {
+		return "", err
+	}
+	md.instId = instId
+	return instId, nil
+}
+
 // watchLoop watches the machine for units added or removed.
 func (md *machineData) watchLoop(unitw watcher.StringsWatcher) error {
 	if err := md.catacomb.Add(unitw); err != nil {
@@ -1049,10 +1197,11 @@ type unitData struct {
 	machined     *machineData
 }
 
-// exposedChange contains the changed exposed flag for one specific application.
+// exposedChange contains the changed exposed endpoints for one specific
+// application.
 type exposedChange struct {
-	applicationd *applicationData
-	exposed      bool
+	applicationd     *applicationData
+	exposedEndpoints map[string][]string
 }
 
 // applicationData holds application details and watches exposure changes.
@@ -1060,12 +1209,17 @@ type applicationData struct {
 	catacomb    catacomb.Catacomb
 	fw          *Firewaller
 	application *firewaller.Application
-	exposed     bool
-	unitds      map[names.UnitTag]*unitData
+	// exposedEndpoints holds the CIDRs that may access the ports
+	// opened for each exposed endpoint, keyed on the endpoint name.
+	exposedEndpoints map[string][]string
+	// hostname is the hostname at which the application is
+	// published in DNS while it is exposed.
+	hostname string
+	unitds   map[names.UnitTag]*unitData
 }
 
-// watchLoop watches the application's exposed flag for changes.
-func (ad *applicationData) watchLoop(exposed bool) error {
+// watchLoop watches the application's exposed endpoints for changes.
+func (ad *applicationData) watchLoop(exposedEndpoints map[string][]string) error {
 	appWatcher, err := ad.application.Watch()
 	if err != nil {
 		if params.IsCodeNotFound(err) {
@@ -1090,15 +1244,15 @@ func (ad *applicationData) watchLoop(exposed bool) error {
 				}
 				return nil
 			}
-			change, err := ad.application.IsExposed()
+			change, err := ad.application.ExposedEndpointCIDRs()
 			if err != nil {
 				return errors.Trace(err)
 			}
-			if change == exposed {
+			if exposedEndpointsEqual(change, exposedEndpoints) {
 				continue
 			}
 
-			exposed = change
+			exposedEndpoints = change
 			select {
 			case <-ad.catacomb.Dying():
 				return ad.catacomb.ErrDying()
@@ -1108,6 +1262,27 @@ func (ad *applicationData) watchLoop(exposed bool) error {
 	}
 }
 
+// exposedEndpointsEqual reports whether the two sets of exposed
+// endpoints are the same. The CIDRs of each endpoint are expected
+// to be sorted.
+func exposedEndpointsEqual(a, b map[string][]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for endpoint, cidrsA := range a {
+		cidrsB, exists := b[endpoint]
+		if !exists || len(cidrsA) != len(cidrsB) {
+			return false
+		}
+		for i := range cidrsA {
+			if cidrsA[i] != cidrsB[i] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // Kill is part of the worker.Worker interface.
 func (ad *applicationData) Kill() {
 	ad.catacomb.Kill(nil)
@@ -6,6 +6,7 @@ package firewaller_test
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"sync/atomic"
 	"time"
 
@@ -29,6 +30,7 @@ import (
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/dns"
 	"github.com/juju/juju/instance"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
@@ -218,14 +220,18 @@ func (s *InstanceModeSuite) newFirewallerWithClock(c *gc.C, clock clock.Clock) w
 	s.clock = clock
 	fwEnv, ok := s.Environ.(environs.Firewaller)
 	c.Assert(ok, gc.Equals, true)
+	lbEnv, ok := environs.SupportsLoadBalancers(s.Environ)
+	c.Assert(ok, gc.Equals, true)
 
 	cfg := firewaller.Config{
-		ModelUUID:          s.State.ModelUUID(),
-		Mode:               config.FwInstance,
-		EnvironFirewaller:  fwEnv,
-		EnvironInstances:   s.Environ,
-		FirewallerAPI:      s.firewaller,
-		RemoteRelationsApi: s.remoteRelations,
+		ModelUUID:            s.State.ModelUUID(),
+		Mode:                 config.FwInstance,
+		EnvironFirewaller:    fwEnv,
+		EnvironInstances:     s.Environ,
+		EnvironLoadBalancers: lbEnv,
+		Environ:              s.Environ,
+		FirewallerAPI:        s.firewaller,
+		RemoteRelationsApi:   s.remoteRelations,
 		NewCrossModelFacadeFunc: func(*api.Info) (firewaller.CrossModelFirewallerFacadeCloser, error) {
 			return s.crossmodelFirewaller, nil
 		},
@@ -512,6 +518,266 @@ func (s *InstanceModeSuite) TestSetClearExposedApplication(c *gc.C) {
 	s.assertPorts(c, inst, m.Id(), nil)
 }
 
+func (s *InstanceModeSuite) TestExposedEndpoints(c *gc.C) {
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+
+	u, m := s.addUnit(c, app)
+	inst := s.startInstance(c, m)
+	err := u.OpenPort("tcp", 8080)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.OpenPortsForEndpoint("url", "tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Exposing only the url endpoint opens only its ports.
+	err = app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"url": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "10.0.0.0/8"),
+	})
+
+	// Exposing the remaining endpoints leaves the url endpoint's
+	// ports restricted to its own CIDRs.
+	err = app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"": {ExposeToCIDRs: []string{"192.168.0.0/16"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "10.0.0.0/8"),
+		network.MustNewIngressRule("tcp", 8080, 8080, "192.168.0.0/16"),
+	})
+
+	err = app.UnsetExposeSettings([]string{"url"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "192.168.0.0/16"),
+		network.MustNewIngressRule("tcp", 8080, 8080, "192.168.0.0/16"),
+	})
+
+	err = app.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPorts(c, inst, m.Id(), nil)
+}
+
+func (s *InstanceModeSuite) TestLoadBalancers(c *gc.C) {
+	cfg, err := s.Environ.Config().Apply(map[string]interface{}{
+		"load-balancer-type":                  "application",
+		"load-balancer-health-check-path":     "/health",
+		"load-balancer-health-check-interval": "30s",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.Environ.SetConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+	u1, m1 := s.addUnit(c, app)
+	inst1 := s.startInstance(c, m1)
+	u2, m2 := s.addUnit(c, app)
+	inst2 := s.startInstance(c, m2)
+	for _, u := range []*state.Unit{u1, u2} {
+		// Ports not opened for an endpoint are not load balanced.
+		err = u.OpenPort("tcp", 8080)
+		c.Assert(err, jc.ErrorIsNil)
+		err = u.OpenPortsForEndpoint("url", "tcp", 80, 80)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err = app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"url": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	lbName := "juju-" + s.State.ModelUUID() + "-wordpress-url"
+	expected := environs.LoadBalancerSpec{
+		Name:        lbName,
+		Type:        "application",
+		Instances:   []instance.Id{inst1.Id(), inst2.Id()},
+		PortRanges:  []network.PortRange{{FromPort: 80, ToPort: 80, Protocol: "tcp"}},
+		SourceCIDRs: []string{"10.0.0.0/8"},
+		HealthCheck: environs.HealthCheck{
+			Protocol:  "http",
+			Port:      80,
+			Path:      "/health",
+			Interval:  30 * time.Second,
+			Threshold: 3,
+		},
+	}
+	sort.Slice(expected.Instances, func(i, j int) bool {
+		return expected.Instances[i] < expected.Instances[j]
+	})
+	s.assertLoadBalancer(c, lbName, &expected)
+
+	// Removing a unit takes its instance out of the load balancer.
+	err = u2.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = u2.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	expected.Instances = []instance.Id{inst1.Id()}
+	s.assertLoadBalancer(c, lbName, &expected)
+
+	err = app.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertLoadBalancer(c, lbName, nil)
+}
+
+func (s *InstanceModeSuite) TestLoadBalancersOfOtherModelsKept(c *gc.C) {
+	cfg, err := s.Environ.Config().Apply(map[string]interface{}{
+		"load-balancer-type": "network",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.Environ.SetConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	lbEnv, _ := environs.SupportsLoadBalancers(s.Environ)
+	otherSpec := environs.LoadBalancerSpec{
+		Name: "juju-" + utils.MustNewUUID().String() + "-mysql-db",
+		Type: "network",
+	}
+	_, err = lbEnv.EnsureLoadBalancer(otherSpec)
+	c.Assert(err, jc.ErrorIsNil)
+	staleName := "juju-" + s.State.ModelUUID() + "-mysql-db"
+	_, err = lbEnv.EnsureLoadBalancer(environs.LoadBalancerSpec{Name: staleName, Type: "network"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	// The model's stale load balancer is removed once the machines
+	// are known, but the other model's is left alone.
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+	s.addUnit(c, app)
+	s.assertLoadBalancer(c, staleName, nil)
+	s.assertLoadBalancer(c, otherSpec.Name, &otherSpec)
+}
+
+func (s *InstanceModeSuite) TestDNSRecords(c *gc.C) {
+	cfg, err := s.Environ.Config().Apply(map[string]interface{}{
+		"dns-provider": "dummy",
+		"dns-zone":     "example.com",
+		"dns-ttl":      60,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.Environ.SetConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+	err = s.IAASModel.SetAnnotations(app, map[string]string{"hostname": "blog.example.com"})
+	c.Assert(err, jc.ErrorIsNil)
+	u, m := s.addUnit(c, app)
+	inst := s.startInstance(c, m)
+	addr := network.NewScopedAddress("203.0.113.10", network.ScopePublic)
+	dummy.SetInstanceAddresses(inst, []network.Address{addr})
+	err = u.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The hostname is only published while the application is exposed.
+	s.assertDNSRecord(c, "blog.example.com", nil)
+	err = app.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertDNSRecord(c, "blog.example.com", &dns.Record{
+		Name:      "blog.example.com",
+		TTL:       60,
+		Addresses: []network.Address{addr},
+	})
+
+	// Changing the hostname moves the record.
+	err = s.IAASModel.SetAnnotations(app, map[string]string{"hostname": "www.example.com"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertDNSRecord(c, "www.example.com", &dns.Record{
+		Name:      "www.example.com",
+		TTL:       60,
+		Addresses: []network.Address{addr},
+	})
+	s.assertDNSRecord(c, "blog.example.com", nil)
+
+	err = app.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertDNSRecord(c, "www.example.com", nil)
+}
+
+func (s *InstanceModeSuite) TestDNSRecordsLoadBalancer(c *gc.C) {
+	cfg, err := s.Environ.Config().Apply(map[string]interface{}{
+		"load-balancer-type": "network",
+		"dns-provider":       "dummy",
+		"dns-zone":           "example.com",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.Environ.SetConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+	err = s.IAASModel.SetAnnotations(app, map[string]string{"hostname": "blog.example.com"})
+	c.Assert(err, jc.ErrorIsNil)
+	u, m := s.addUnit(c, app)
+	s.startInstance(c, m)
+	err = u.OpenPortsForEndpoint("url", "tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+	err = app.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The record points at the load balancer rather than the instance.
+	s.assertDNSRecord(c, "blog.example.com", &dns.Record{
+		Name:      "blog.example.com",
+		TTL:       300,
+		Addresses: network.NewAddresses("juju-" + s.State.ModelUUID() + "-wordpress-url.lb.dummy"),
+	})
+}
+
+// assertDNSRecord waits for the records of the named host recorded by
+// the dummy DNS provider to match the expected record, or to be
+// removed if expected is nil.
+func (s *InstanceModeSuite) assertDNSRecord(c *gc.C, name string, expected *dns.Record) {
+	dnsEnv := s.Environ.(interface {
+		DNSRecord(string) (dns.Record, error)
+	})
+	s.BackingState.StartSync()
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		record, err := dnsEnv.DNSRecord(name)
+		if expected == nil && errors.IsNotFound(err) {
+			return
+		}
+		if expected != nil && err == nil && reflect.DeepEqual(record, *expected) {
+			return
+		}
+		if !a.HasNext() {
+			c.Fatalf("timed out: expected %+v; got %+v (%v)", expected, record, err)
+		}
+	}
+}
+
+// assertLoadBalancer waits for the named load balancer provisioned in
+// the dummy environ to match the expected spec, or to be removed if
+// expected is nil.
+func (s *InstanceModeSuite) assertLoadBalancer(c *gc.C, name string, expected *environs.LoadBalancerSpec) {
+	lbEnv := s.Environ.(interface {
+		LoadBalancerSpec(string) (environs.LoadBalancerSpec, error)
+	})
+	s.BackingState.StartSync()
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		spec, err := lbEnv.LoadBalancerSpec(name)
+		if expected == nil && errors.IsNotFound(err) {
+			return
+		}
+		if expected != nil && err == nil && reflect.DeepEqual(spec, *expected) {
+			return
+		}
+		if !a.HasNext() {
+			c.Fatalf("timed out: expected %+v; got %+v (%v)", expected, spec, err)
+		}
+	}
+}
+
 func (s *InstanceModeSuite) TestRemoveUnit(c *gc.C) {
 	fw := s.newFirewaller(c)
 	defer statetesting.AssertKillAndWait(c, fw)
@@ -0,0 +1,211 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewaller
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+)
+
+// loadBalancerPrefix returns the prefix of the names of the load
+// balancers provisioned for the model.
+func loadBalancerPrefix(modelUUID string) string {
+	return "juju-" + modelUUID + "-"
+}
+
+// loadBalancerName returns the name of the load balancer for the
+// endpoint of the application. The name includes the model UUID, so
+// that models sharing a cloud account don't claim each other's load
+// balancers.
+func loadBalancerName(modelUUID string, app names.ApplicationTag, endpoint string) string {
+	return loadBalancerPrefix(modelUUID) + app.Id() + "-" + endpoint
+}
+
+// setUpLoadBalancers records the load balancers that the environ has
+// already provisioned for the model, so that those no longer wanted
+// are removed by the first flushLoadBalancers.
+func (fw *Firewaller) setUpLoadBalancers() error {
+	if fw.environLoadBalancers == nil {
+		return nil
+	}
+	existing, err := fw.environLoadBalancers.LoadBalancers()
+	if err != nil {
+		return errors.Annotate(err, "cannot list load balancers")
+	}
+	prefix := loadBalancerPrefix(fw.modelUUID)
+	for _, lb := range existing {
+		if !strings.HasPrefix(lb.Name, prefix) {
+			continue
+		}
+		// The zero spec never matches a wanted spec, so the load
+		// balancer is updated if it is still wanted.
+		fw.loadBalancers[lb.Name] = environs.LoadBalancerSpec{}
+	}
+	return nil
+}
+
+// flushLoadBalancers provisions a load balancer for each exposed
+// endpoint of the given applications that units have opened ports
+// for, and removes the load balancers of their endpoints that are no
+// longer exposed or have no ports opened for them. If applications is
+// nil, the load balancers of all the model's applications are
+// reconciled, including any found by setUpLoadBalancers.
+func (fw *Firewaller) flushLoadBalancers(applications map[names.ApplicationTag]bool) error {
+	if fw.environLoadBalancers == nil {
+		return nil
+	}
+	want, err := fw.gatherLoadBalancers(applications)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for name, spec := range want {
+		if current, ok := fw.loadBalancers[name]; ok && reflect.DeepEqual(current, spec) {
+			continue
+		}
+		var lb *environs.LoadBalancer
+		err := fw.retry(func() error {
+			var err error
+			lb, err = fw.environLoadBalancers.EnsureLoadBalancer(spec)
+			return err
+		})
+		if errors.IsNotSupported(err) {
+			// Record the spec so that we don't keep trying.
+			logger.Warningf("cannot provision load balancer %q: %v", name, err)
+			fw.loadBalancers[name] = spec
+			continue
+		} else if err != nil {
+			return errors.Annotatef(err, "cannot provision load balancer %q", name)
+		}
+		logger.Infof("provisioned load balancer %q at %v", name, lb.Addresses)
+		fw.loadBalancers[name] = spec
+		fw.loadBalancerAddresses[name] = lb.Addresses
+	}
+	for name := range fw.loadBalancers {
+		if _, ok := want[name]; ok {
+			continue
+		}
+		if applications != nil {
+			// Only a full flush removes the load balancers found by
+			// setUpLoadBalancers, as their application is unknown.
+			if app, ok := fw.loadBalancerApplications[name]; !ok || !applications[app] {
+				continue
+			}
+		}
+		if err := fw.retry(func() error {
+			return fw.environLoadBalancers.RemoveLoadBalancer(name)
+		}); err != nil {
+			return errors.Annotatef(err, "cannot remove load balancer %q", name)
+		}
+		logger.Infof("removed load balancer %q", name)
+		delete(fw.loadBalancers, name)
+		delete(fw.loadBalancerAddresses, name)
+		delete(fw.loadBalancerApplications, name)
+	}
+	return nil
+}
+
+// gatherLoadBalancers returns the specs of the load balancers wanted
+// for the exposed endpoints of the given applications, or of all the
+// model's applications if applications is nil, keyed on name. Only
+// port ranges opened for a specific endpoint are load balanced.
+func (fw *Firewaller) gatherLoadBalancers(applications map[names.ApplicationTag]bool) (map[string]environs.LoadBalancerSpec, error) {
+	cfg := fw.environLoadBalancers.Config()
+	lbType := cfg.LoadBalancerType()
+	if lbType == config.LoadBalancerNone {
+		return nil, nil
+	}
+	healthCheck := environs.HealthCheck{
+		Protocol:  "tcp",
+		Interval:  cfg.LoadBalancerHealthCheckInterval(),
+		Threshold: cfg.LoadBalancerHealthCheckThreshold(),
+	}
+	if lbType == config.LoadBalancerApplication {
+		healthCheck.Protocol = "http"
+		healthCheck.Path = cfg.LoadBalancerHealthCheckPath()
+	}
+
+	want := make(map[string]environs.LoadBalancerSpec)
+	for _, machined := range fw.machineds {
+		for unitTag, portRanges := range machined.definedPorts {
+			unitd, known := machined.unitds[unitTag]
+			if !known {
+				continue
+			}
+			appTag := unitd.applicationd.application.Tag()
+			if applications != nil && !applications[appTag] {
+				continue
+			}
+			exposedEndpoints := unitd.applicationd.exposedEndpoints
+			for portRange, endpoint := range portRanges {
+				if endpoint == "" {
+					continue
+				}
+				sourceCidrs, exposed := exposedEndpoints[endpoint]
+				if !exposed {
+					sourceCidrs, exposed = exposedEndpoints[""]
+				}
+				if !exposed || len(sourceCidrs) == 0 {
+					continue
+				}
+				instanceId, err := machined.instanceId()
+				if params.IsCodeNotProvisioned(err) || params.IsCodeNotFound(err) {
+					continue
+				} else if err != nil {
+					return nil, errors.Trace(err)
+				}
+				name := loadBalancerName(fw.modelUUID, appTag, endpoint)
+				fw.loadBalancerApplications[name] = appTag
+				spec, ok := want[name]
+				if !ok {
+					spec = environs.LoadBalancerSpec{
+						Name:        name,
+						Type:        lbType,
+						SourceCIDRs: sourceCidrs,
+						HealthCheck: healthCheck,
+					}
+				}
+				spec.Instances = appendInstanceId(spec.Instances, instanceId)
+				spec.PortRanges = appendPortRange(spec.PortRanges, portRange)
+				want[name] = spec
+			}
+		}
+	}
+	for name, spec := range want {
+		sort.Slice(spec.Instances, func(i, j int) bool {
+			return spec.Instances[i] < spec.Instances[j]
+		})
+		network.SortPortRanges(spec.PortRanges)
+		spec.HealthCheck.Port = spec.PortRanges[0].FromPort
+		want[name] = spec
+	}
+	return want, nil
+}
+
+func appendInstanceId(ids []instance.Id, id instance.Id) []instance.Id {
+	for _, existing := range ids {
+		if existing == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
+
+func appendPortRange(ranges []network.PortRange, portRange network.PortRange) []network.PortRange {
+	for _, existing := range ranges {
+		if existing == portRange {
+			return ranges
+		}
+	}
+	return append(ranges, portRange)
+}
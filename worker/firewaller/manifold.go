@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/api/remoterelations"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/providerretry"
 	"github.com/juju/juju/worker/apicaller"
 	"github.com/juju/juju/worker/dependency"
 )
@@ -103,6 +104,9 @@ func (cfg ManifoldConfig) start(context dependency.Context) (worker.Worker, erro
 		}
 	}
 
+	// The environ may also be able to provision load balancers.
+	lbEnv, _ := environs.SupportsLoadBalancers(environ)
+
 	firewallerAPI, err := cfg.NewFirewallerFacade(apiConn)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -113,12 +117,15 @@ func (cfg ManifoldConfig) start(context dependency.Context) (worker.Worker, erro
 	}
 
 	w, err := cfg.NewFirewallerWorker(Config{
-		ModelUUID:          agent.CurrentConfig().Model().Id(),
-		RemoteRelationsApi: remoteRelationsAPI,
-		FirewallerAPI:      firewallerAPI,
-		EnvironFirewaller:  fwEnv,
-		EnvironInstances:   environ,
-		Mode:               mode,
+		ModelUUID:               agent.CurrentConfig().Model().Id(),
+		RemoteRelationsApi:      remoteRelationsAPI,
+		FirewallerAPI:           firewallerAPI,
+		EnvironFirewaller:       fwEnv,
+		EnvironInstances:        environ,
+		EnvironLoadBalancers:    lbEnv,
+		Environ:                 environ,
+		Mode:                    mode,
+		RetryStrategy:           providerretry.NewStrategy(environ.Config()),
 		NewCrossModelFacadeFunc: crossmodelFirewallerFacadeFunc(cfg.NewControllerConnection),
 	})
 	if err != nil {
@@ -201,6 +201,11 @@ func (s *stubStateTracker) Use() (*state.State, error) {
 	return &s.st, s.NextErr()
 }
 
+func (s *stubStateTracker) Pool() *state.StatePool {
+	s.MethodCall(s, "Pool")
+	return nil
+}
+
 func (s *stubStateTracker) Done() error {
 	s.MethodCall(s, "Done")
 	err := s.NextErr()
@@ -63,14 +63,24 @@ func startFunc(config ManifoldConfig) dependency.StartFunc {
 // exists primarily to be patched out via NewManifoldWorker for ease of testing,
 // and is not itself directly tested. It would almost certainly be better to
 // pass the constructor dependencies in as explicit manifold config.
+//
+// The tracker uses the controller's leadership lease settings; the
+// supplied guarantee is only used with controllers that do not report
+// them.
 var NewManifoldWorker = func(agent agent.Agent, apiCaller base.APICaller, clock clock.Clock, guarantee time.Duration) (worker.Worker, error) {
 	tag := agent.CurrentConfig().Tag()
 	unitTag, ok := tag.(names.UnitTag)
 	if !ok {
 		return nil, fmt.Errorf("expected a unit tag; got %q", tag)
 	}
-	claimer := leadership.NewClient(apiCaller)
-	return NewTracker(unitTag, claimer, clock, guarantee), nil
+	client := leadership.NewClient(apiCaller)
+	duration, renewal, err := client.LeaseSettings()
+	if errors.IsNotSupported(err) {
+		return NewTracker(unitTag, client, clock, guarantee), nil
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot get leadership lease settings")
+	}
+	return NewLeaseTracker(unitTag, client, clock, duration, renewal), nil
 }
 
 // outputFunc extracts the coreleadership.Tracker from a *Tracker passed in as a Worker.
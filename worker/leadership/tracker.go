@@ -24,6 +24,7 @@ type Tracker struct {
 	applicationName string
 	clock           clock.Clock
 	duration        time.Duration
+	leaseDuration   time.Duration
 	isMinion        bool
 
 	claimLease        chan struct{}
@@ -44,6 +45,18 @@ type Tracker struct {
 // calls to the supplied manager (which may very well be on the other side of
 // a network connection).
 func NewTracker(tag names.UnitTag, claimer leadership.Claimer, clock clock.Clock, duration time.Duration) *Tracker {
+	return newTracker(tag, claimer, clock, duration, 2*duration)
+}
+
+// NewLeaseTracker returns a *Tracker that claims leadership for the
+// supplied lease duration, and renews it every renewal interval. Its
+// claims guarantee leadership for the difference between the two,
+// which must be positive.
+func NewLeaseTracker(tag names.UnitTag, claimer leadership.Claimer, clock clock.Clock, leaseDuration, renewal time.Duration) *Tracker {
+	return newTracker(tag, claimer, clock, leaseDuration-renewal, leaseDuration)
+}
+
+func newTracker(tag names.UnitTag, claimer leadership.Claimer, clock clock.Clock, duration, leaseDuration time.Duration) *Tracker {
 	unitName := tag.Id()
 	serviceName, _ := names.UnitApplication(unitName)
 	t := &Tracker{
@@ -52,6 +65,7 @@ func NewTracker(tag names.UnitTag, claimer leadership.Claimer, clock clock.Clock
 		claimer:           claimer,
 		clock:             clock,
 		duration:          duration,
+		leaseDuration:     leaseDuration,
 		claimTickets:      make(chan chan bool),
 		waitLeaderTickets: make(chan chan bool),
 		waitMinionTickets: make(chan chan bool),
@@ -166,9 +180,8 @@ func (t *Tracker) loop() error {
 // latest known reality.
 func (t *Tracker) refresh() error {
 	logger.Tracef("checking %s for %s leadership", t.unitName, t.applicationName)
-	leaseDuration := 2 * t.duration
-	untilTime := t.clock.Now().Add(leaseDuration)
-	err := t.claimer.ClaimLeadership(t.applicationName, t.unitName, leaseDuration)
+	untilTime := t.clock.Now().Add(t.leaseDuration)
+	err := t.claimer.ClaimLeadership(t.applicationName, t.unitName, t.leaseDuration)
 	switch {
 	case err == nil:
 		return t.setLeader(untilTime)
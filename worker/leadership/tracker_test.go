@@ -114,6 +114,34 @@ func (s *TrackerSuite) TestOnLeaderSuccess(c *gc.C) {
 	}})
 }
 
+func (s *TrackerSuite) TestLeaseTrackerRenewsAtInterval(c *gc.C) {
+	tracker := leadership.NewLeaseTracker(s.unitTag, s.claimer, s.clock, 90*time.Second, 20*time.Second)
+	defer workertest.DirtyKill(c, tracker)
+	c.Check(tracker.ClaimDuration(), gc.Equals, 70*time.Second)
+
+	assertClaimLeader(c, tracker, true)
+	err := s.clock.WaitAdvance(20*time.Second, coretesting.LongWait, 1)
+	c.Assert(err, gc.IsNil)
+	for a := coretesting.LongAttempt.Start(); len(s.claimer.Calls()) < 2; {
+		if !a.Next() {
+			c.Fatalf("timed out waiting for lease renewal")
+		}
+	}
+
+	workertest.CleanKill(c, tracker)
+	s.claimer.CheckCalls(c, []testing.StubCall{{
+		FuncName: "ClaimLeadership",
+		Args: []interface{}{
+			"led-service", "led-service/123", 90 * time.Second,
+		},
+	}, {
+		FuncName: "ClaimLeadership",
+		Args: []interface{}{
+			"led-service", "led-service/123", 90 * time.Second,
+		},
+	}})
+}
+
 func (s *TrackerSuite) TestOnLeaderFailure(c *gc.C) {
 	s.claimer.Stub.SetErrors(coreleadership.ErrClaimDenied, nil)
 	tracker := s.newTracker()
@@ -46,6 +46,18 @@ type ManagerConfig struct {
 	// EntityUUID is the entity that we are running this Manager for. Used for
 	// logging purposes.
 	EntityUUID string
+
+	// MinLeaseDuration, if set, is the shortest time for which a lease
+	// will be claimed or extended; requests for less are granted for
+	// this long instead. Granting more than was asked for never breaks
+	// a holder's expectations, it only delays handover on failure.
+	MinLeaseDuration time.Duration
+
+	// ClaimObserver, if set, is called whenever a lease is newly claimed
+	// by a holder; it is not called when a holder extends a lease it
+	// already holds. It is called on its own goroutine, in the order the
+	// claims were made, so it may be slow without holding up the Manager.
+	ClaimObserver func(leaseName, holderName string)
 }
 
 // Validate returns an error if the configuration contains invalid information
@@ -63,5 +75,8 @@ func (config ManagerConfig) Validate() error {
 	if config.MaxSleep <= 0 {
 		return errors.NotValidf("non-positive MaxSleep")
 	}
+	if config.MinLeaseDuration < 0 {
+		return errors.NotValidf("negative MinLeaseDuration")
+	}
 	return nil
 }
@@ -69,6 +69,13 @@ type Fixture struct {
 	// to the extent that it returns an error on Wait(); tests that don't set
 	// this flag will check that the manager's shutdown error is nil.
 	expectDirty bool
+
+	// claimObserver, if set, is passed to the manager as its
+	// ClaimObserver.
+	claimObserver func(leaseName, holderName string)
+
+	// minLeaseDuration is passed to the manager as its MinLeaseDuration.
+	minLeaseDuration time.Duration
 }
 
 // RunTest sets up a Manager and a Clock and passes them into the supplied
@@ -77,10 +84,12 @@ func (fix *Fixture) RunTest(c *gc.C, test func(*lease.Manager, *testing.Clock))
 	clock := testing.NewClock(defaultClockStart)
 	client := NewClient(fix.leases, fix.expectCalls)
 	manager, err := lease.NewManager(lease.ManagerConfig{
-		Clock:     clock,
-		Client:    client,
-		Secretary: Secretary{},
-		MaxSleep:  defaultMaxSleep,
+		Clock:            clock,
+		Client:           client,
+		Secretary:        Secretary{},
+		MaxSleep:         defaultMaxSleep,
+		MinLeaseDuration: fix.minLeaseDuration,
+		ClaimObserver:    fix.claimObserver,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	defer func() {
@@ -11,8 +11,10 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/utils/clock"
+	"gopkg.in/juju/worker.v1"
 
 	"github.com/juju/juju/core/lease"
+	jworker "github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/catacomb"
 )
 
@@ -62,10 +64,15 @@ func NewManager(config ManagerConfig) (*Manager, error) {
 		blocks:     make(chan block),
 		logContext: logContext,
 	}
-	err := catacomb.Invoke(catacomb.Plan{
+	plan := catacomb.Plan{
 		Site: &manager.catacomb,
 		Work: manager.loop,
-	})
+	}
+	if config.ClaimObserver != nil {
+		manager.notifier = newClaimNotifier(config.ClaimObserver)
+		plan.Init = []worker.Worker{jworker.NewSimpleWorker(manager.notifier.run)}
+	}
+	err := catacomb.Invoke(plan)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -92,6 +99,10 @@ type Manager struct {
 
 	// blocks is used to deliver expiry block requests to the loop.
 	blocks chan block
+
+	// notifier, if set, passes new claims to the config's
+	// ClaimObserver.
+	notifier *claimNotifier
 }
 
 // Kill is part of the worker.Worker interface.
@@ -165,8 +176,13 @@ func (manager *Manager) Claim(leaseName, holderName string, duration time.Durati
 // is communicated back to the claim's originator.
 func (manager *Manager) handleClaim(claim claim) error {
 	client := manager.config.Client
-	request := lease.Request{claim.holderName, claim.duration}
+	duration := claim.duration
+	if duration < manager.config.MinLeaseDuration {
+		duration = manager.config.MinLeaseDuration
+	}
+	request := lease.Request{claim.holderName, duration}
 	err := lease.ErrInvalid
+	var extended bool
 	for err == lease.ErrInvalid {
 		select {
 		case <-manager.catacomb.Dying():
@@ -178,11 +194,13 @@ func (manager *Manager) handleClaim(claim claim) error {
 			info, found := client.Leases()[claim.leaseName]
 			switch {
 			case !found:
-				logger.Tracef("[%s] %s asked for lease %s, no lease found, claiming for %s", manager.logContext, claim.holderName, claim.leaseName, claim.duration)
+				logger.Tracef("[%s] %s asked for lease %s, no lease found, claiming for %s", manager.logContext, claim.holderName, claim.leaseName, duration)
 				err = client.ClaimLease(claim.leaseName, request)
+				extended = false
 			case info.Holder == claim.holderName:
-				logger.Tracef("[%s] %s extending lease %s for %s", manager.logContext, claim.holderName, claim.leaseName, claim.duration)
+				logger.Tracef("[%s] %s extending lease %s for %s", manager.logContext, claim.holderName, claim.leaseName, duration)
 				err = client.ExtendLease(claim.leaseName, request)
+				extended = true
 			default:
 				// Note: (jam) 2017-10-31) We don't check here if the lease has
 				// expired for the current holder. Should we?
@@ -197,6 +215,9 @@ func (manager *Manager) handleClaim(claim claim) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if !extended && manager.notifier != nil {
+		manager.notifier.add(claim.leaseName, claim.holderName)
+	}
 	claim.respond(true)
 	return nil
 }
@@ -12,6 +12,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	corelease "github.com/juju/juju/core/lease"
+	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/lease"
 )
 
@@ -57,6 +58,108 @@ func (s *ClaimSuite) TestClaimLease_Success_SameHolder(c *gc.C) {
 	})
 }
 
+func (s *ClaimSuite) TestClaimLease_Success_NotifiesObserver(c *gc.C) {
+	observed := make(chan string, 1)
+	fix := &Fixture{
+		expectCalls: []call{{
+			method: "ClaimLease",
+			args:   []interface{}{"redis", corelease.Request{"redis/0", time.Minute}},
+		}},
+		claimObserver: func(leaseName, holderName string) {
+			observed <- leaseName + ":" + holderName
+		},
+	}
+	fix.RunTest(c, func(manager *lease.Manager, _ *testing.Clock) {
+		err := manager.Claim("redis", "redis/0", time.Minute)
+		c.Check(err, jc.ErrorIsNil)
+		select {
+		case claim := <-observed:
+			c.Check(claim, gc.Equals, "redis:redis/0")
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for claim to be observed")
+		}
+	})
+}
+
+func (s *ClaimSuite) TestClaimLease_SlowObserver_DoesNotBlockClaims(c *gc.C) {
+	release := make(chan struct{})
+	fix := &Fixture{
+		expectCalls: []call{{
+			method: "ClaimLease",
+			args:   []interface{}{"redis", corelease.Request{"redis/0", time.Minute}},
+		}, {
+			method: "ClaimLease",
+			args:   []interface{}{"store", corelease.Request{"store/0", time.Minute}},
+		}},
+		claimObserver: func(leaseName, holderName string) {
+			<-release
+		},
+	}
+	fix.RunTest(c, func(manager *lease.Manager, _ *testing.Clock) {
+		defer close(release)
+		err := manager.Claim("redis", "redis/0", time.Minute)
+		c.Check(err, jc.ErrorIsNil)
+		err = manager.Claim("store", "store/0", time.Minute)
+		c.Check(err, jc.ErrorIsNil)
+	})
+}
+
+func (s *ClaimSuite) TestClaimLease_Extend_DoesNotNotifyObserver(c *gc.C) {
+	observed := make(chan string, 1)
+	fix := &Fixture{
+		leases: map[string]corelease.Info{
+			"redis": {
+				Holder: "redis/0",
+				Expiry: offset(time.Second),
+			},
+		},
+		expectCalls: []call{{
+			method: "ExtendLease",
+			args:   []interface{}{"redis", corelease.Request{"redis/0", time.Minute}},
+		}},
+		claimObserver: func(leaseName, holderName string) {
+			observed <- leaseName + ":" + holderName
+		},
+	}
+	fix.RunTest(c, func(manager *lease.Manager, _ *testing.Clock) {
+		err := manager.Claim("redis", "redis/0", time.Minute)
+		c.Check(err, jc.ErrorIsNil)
+		select {
+		case claim := <-observed:
+			c.Fatalf("unexpected claim observed: %s", claim)
+		case <-time.After(coretesting.ShortWait):
+		}
+	})
+}
+
+func (s *ClaimSuite) TestClaimLease_Success_MinLeaseDuration(c *gc.C) {
+	fix := &Fixture{
+		minLeaseDuration: 2 * time.Minute,
+		expectCalls: []call{{
+			method: "ClaimLease",
+			args:   []interface{}{"redis", corelease.Request{"redis/0", 2 * time.Minute}},
+		}},
+	}
+	fix.RunTest(c, func(manager *lease.Manager, _ *testing.Clock) {
+		err := manager.Claim("redis", "redis/0", time.Minute)
+		c.Check(err, jc.ErrorIsNil)
+	})
+}
+
+func (s *ClaimSuite) TestClaimLease_Success_LongerThanMinLeaseDuration(c *gc.C) {
+	fix := &Fixture{
+		minLeaseDuration: 30 * time.Second,
+		expectCalls: []call{{
+			method: "ClaimLease",
+			args:   []interface{}{"redis", corelease.Request{"redis/0", time.Minute}},
+		}},
+	}
+	fix.RunTest(c, func(manager *lease.Manager, _ *testing.Clock) {
+		err := manager.Claim("redis", "redis/0", time.Minute)
+		c.Check(err, jc.ErrorIsNil)
+	})
+}
+
 func (s *ClaimSuite) TestClaimLease_Failure_OtherHolder(c *gc.C) {
 	fix := &Fixture{
 		expectCalls: []call{{
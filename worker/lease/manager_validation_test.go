@@ -77,6 +77,19 @@ func (s *ValidationSuite) TestNegativeMaxSleep(c *gc.C) {
 	c.Check(manager, gc.IsNil)
 }
 
+func (s *ValidationSuite) TestNegativeMinLeaseDuration(c *gc.C) {
+	manager, err := lease.NewManager(lease.ManagerConfig{
+		Client:           NewClient(nil, nil),
+		Clock:            testing.NewClock(time.Now()),
+		Secretary:        struct{ lease.Secretary }{},
+		MaxSleep:         time.Minute,
+		MinLeaseDuration: -time.Nanosecond,
+	})
+	c.Check(err, gc.ErrorMatches, "negative MinLeaseDuration not valid")
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(manager, gc.IsNil)
+}
+
 func (s *ValidationSuite) TestClaim_LeaseName(c *gc.C) {
 	fix := &Fixture{}
 	fix.RunTest(c, func(manager *lease.Manager, _ *testing.Clock) {
@@ -0,0 +1,62 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lease
+
+import (
+	"sync"
+)
+
+// claimed records a lease newly claimed by a holder.
+type claimed struct {
+	leaseName  string
+	holderName string
+}
+
+// claimNotifier delivers the claims made by a Manager to its
+// ClaimObserver, away from the manager's loop, so that a slow
+// observer cannot hold up the handling of claims and checks.
+type claimNotifier struct {
+	observer func(leaseName, holderName string)
+
+	mu      sync.Mutex
+	pending []claimed
+	wake    chan struct{}
+}
+
+func newClaimNotifier(observer func(leaseName, holderName string)) *claimNotifier {
+	return &claimNotifier{
+		observer: observer,
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// add queues a notification of the claim; it never blocks.
+func (n *claimNotifier) add(leaseName, holderName string) {
+	n.mu.Lock()
+	n.pending = append(n.pending, claimed{leaseName, holderName})
+	n.mu.Unlock()
+	select {
+	case n.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run passes queued claims to the observer, in the order they were
+// made, until stop is closed.
+func (n *claimNotifier) run(stop <-chan struct{}) error {
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-n.wake:
+		}
+		n.mu.Lock()
+		batch := n.pending
+		n.pending = nil
+		n.mu.Unlock()
+		for _, c := range batch {
+			n.observer(c.leaseName, c.holderName)
+		}
+	}
+}
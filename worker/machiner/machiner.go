@@ -36,6 +36,11 @@ type Config struct {
 	// NotifyMachineDead will, if non-nil, be called after the machine
 	// is transitioned to the Dead lifecycle state.
 	NotifyMachineDead func() error
+
+	// OSHardeningProfile, if not empty, names the OS hardening profile
+	// applied to the machine when it was provisioned. It is reported
+	// in the machine's status data.
+	OSHardeningProfile string
 }
 
 // Validate reports whether or not the configuration is valid.
@@ -100,7 +105,13 @@ func (mr *Machiner) SetUp() (watcher.NotifyWatcher, error) {
 	}
 
 	// Mark the machine as started and log it.
-	if err := m.SetStatus(status.Started, "", nil); err != nil {
+	var data map[string]interface{}
+	if mr.config.OSHardeningProfile != "" {
+		data = map[string]interface{}{
+			"os-hardening-profile": mr.config.OSHardeningProfile,
+		}
+	}
+	if err := m.SetStatus(status.Started, "", data); err != nil {
 		return nil, errors.Annotatef(err, "%s failed to set status started", mr.config.Tag)
 	}
 	logger.Infof("%q started", mr.config.Tag)
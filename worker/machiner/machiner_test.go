@@ -305,6 +305,21 @@ func (s *MachinerSuite) TestStartSetsStatus(c *gc.C) {
 	)
 }
 
+func (s *MachinerSuite) TestStartSetsStatusWithHardeningProfile(c *gc.C) {
+	mr, err := machiner.NewMachiner(machiner.Config{
+		MachineAccessor:    s.accessor,
+		Tag:                s.machineTag,
+		OSHardeningProfile: "cis-level1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = stopWorker(mr)
+	c.Assert(err, jc.ErrorIsNil)
+	s.accessor.machine.CheckCall(
+		c, 1, "SetStatus",
+		status.Started, "", map[string]interface{}{"os-hardening-profile": "cis-level1"},
+	)
+}
+
 func (s *MachinerSuite) TestSetDead(c *gc.C) {
 	var machineDead machineDeathTracker
 
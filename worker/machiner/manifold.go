@@ -93,6 +93,7 @@ func newWorker(a agent.Agent, apiCaller base.APICaller) (worker.Worker, error) {
 		NotifyMachineDead: func() error {
 			return agent.SetCanUninstall(a)
 		},
+		OSHardeningProfile: agent.AppliedOSHardeningProfile(currentConfig.DataDir()),
 	})
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot start machiner worker")
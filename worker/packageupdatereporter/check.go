@@ -0,0 +1,49 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package packageupdatereporter
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// aptCheck is the helper, shipped with update-notifier-common on
+// Ubuntu, that counts the pending package updates. It writes them to
+// stderr as "<pending>;<security>".
+var aptCheck = "/usr/lib/update-notifier/apt-check"
+
+// CheckAptUpdates is a CheckUpdatesFunc that uses apt-check to count
+// the pending package updates.
+func CheckAptUpdates() (pending, security int, err error) {
+	if _, err := os.Stat(aptCheck); os.IsNotExist(err) {
+		return 0, 0, errors.NotSupportedf("checking for updates without %s", aptCheck)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command(aptCheck)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return 0, 0, errors.Annotatef(err, "running %s: %s", aptCheck, strings.TrimSpace(stderr.String()))
+	}
+	return parseAptCheck(stderr.String())
+}
+
+// parseAptCheck parses the output of apt-check.
+func parseAptCheck(output string) (pending, security int, err error) {
+	fields := strings.Split(strings.TrimSpace(output), ";")
+	if len(fields) != 2 {
+		return 0, 0, errors.Errorf("unexpected apt-check output %q", output)
+	}
+	if pending, err = strconv.Atoi(fields[0]); err != nil {
+		return 0, 0, errors.Errorf("unexpected apt-check output %q", output)
+	}
+	if security, err = strconv.Atoi(fields[1]); err != nil {
+		return 0, 0, errors.Errorf("unexpected apt-check output %q", output)
+	}
+	return pending, security, nil
+}
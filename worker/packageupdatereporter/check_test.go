@@ -0,0 +1,37 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package packageupdatereporter_test
+
+import (
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/packageupdatereporter"
+)
+
+type CheckSuite struct {
+	jujutesting.IsolationSuite
+}
+
+var _ = gc.Suite(&CheckSuite{})
+
+func (s *CheckSuite) TestParseAptCheck(c *gc.C) {
+	pending, security, err := packageupdatereporter.ParseAptCheck("12;3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(pending, gc.Equals, 12)
+	c.Check(security, gc.Equals, 3)
+
+	pending, security, err = packageupdatereporter.ParseAptCheck("0;0\n")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(pending, gc.Equals, 0)
+	c.Check(security, gc.Equals, 0)
+}
+
+func (s *CheckSuite) TestParseAptCheckInvalid(c *gc.C) {
+	for _, output := range []string{"", "12", "12;x", "E: broken;1", "1;2;3"} {
+		_, _, err := packageupdatereporter.ParseAptCheck(output)
+		c.Check(err, gc.ErrorMatches, `unexpected apt-check output .*`)
+	}
+}
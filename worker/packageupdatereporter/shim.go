@@ -0,0 +1,26 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package packageupdatereporter
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/packageupdatereporter"
+)
+
+// NewFacade returns a Facade backed by the PackageUpdateReporter API.
+func NewFacade(apiCaller base.APICaller) (Facade, error) {
+	return packageupdatereporter.NewFacade(apiCaller), nil
+}
+
+// NewWorker wraps New for use in a ManifoldConfig.
+func NewWorker(config Config) (worker.Worker, error) {
+	worker, err := New(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return worker, nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package packageupdatereporter provides a worker that periodically
+// reports the package updates pending on a machine, and whether the
+// machine needs rebooting for updates already installed to take effect.
+package packageupdatereporter
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/packageupdatereporter"
+	"github.com/juju/juju/worker/catacomb"
+	"github.com/juju/juju/worker/dependency"
+)
+
+var logger = loggo.GetLogger("juju.worker.packageupdatereporter")
+
+// rebootRequiredFile is the file, relative to the root directory, that
+// packages create when they need the machine to be rebooted.
+const rebootRequiredFile = "var/run/reboot-required"
+
+// Facade exposes controller functionality to a Worker.
+type Facade interface {
+	ReportUpdates(machineId string, report packageupdatereporter.Report) error
+}
+
+// CheckUpdatesFunc returns the number of package updates pending on
+// the machine, and how many of those are security updates. It returns
+// an error satisfying errors.IsNotSupported if updates cannot be
+// checked on the machine.
+type CheckUpdatesFunc func() (pending, security int, err error)
+
+// Config defines the parameters of the packageupdatereporter worker.
+type Config struct {
+	Facade       Facade
+	MachineId    string
+	RootDir      string
+	Clock        clock.Clock
+	Interval     time.Duration
+	CheckUpdates CheckUpdatesFunc
+}
+
+// Validate returns an error if Config cannot drive a
+// packageupdatereporter.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.MachineId == "" {
+		return errors.NotValidf("empty MachineId")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.Interval <= 0 {
+		return errors.NotValidf("non-positive Interval")
+	}
+	if config.CheckUpdates == nil {
+		return errors.NotValidf("nil CheckUpdates")
+	}
+	return nil
+}
+
+// New returns a Worker that reports the package updates pending on the
+// machine when started, and then at the configured interval.
+func New(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &reporterWorker{config: config}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type reporterWorker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *reporterWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *reporterWorker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *reporterWorker) loop() error {
+	for {
+		if err := w.report(); err != nil {
+			return errors.Trace(err)
+		}
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case <-w.config.Clock.After(w.config.Interval):
+		}
+	}
+}
+
+func (w *reporterWorker) report() error {
+	pending, security, err := w.config.CheckUpdates()
+	if errors.IsNotSupported(err) {
+		logger.Infof("not reporting package updates: %v", err)
+		return dependency.ErrUninstall
+	} else if err != nil {
+		return errors.Annotate(err, "cannot check package updates")
+	}
+	rebootRequired, err := w.rebootRequired()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	report := packageupdatereporter.Report{
+		Pending:        pending,
+		Security:       security,
+		RebootRequired: rebootRequired,
+		Updated:        w.config.Clock.Now(),
+	}
+	if err := w.config.Facade.ReportUpdates(w.config.MachineId, report); err != nil {
+		return errors.Annotate(err, "cannot report package updates")
+	}
+	logger.Debugf(
+		"machine %s has %d package updates (%d security), reboot required: %v",
+		w.config.MachineId, pending, security, rebootRequired,
+	)
+	return nil
+}
+
+func (w *reporterWorker) rebootRequired() (bool, error) {
+	_, err := os.Stat(filepath.Join(w.config.RootDir, rebootRequiredFile))
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	return true, nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package packageupdatereporter_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/packageupdatereporter"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/dependency"
+	worker "github.com/juju/juju/worker/packageupdatereporter"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type WorkerSuite struct {
+	jujutesting.IsolationSuite
+
+	dir    string
+	now    time.Time
+	clock  *jujutesting.Clock
+	facade *fakeFacade
+	config worker.Config
+
+	pending, security int
+	checkErr          error
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.dir = c.MkDir()
+	s.now = time.Date(2018, 4, 3, 12, 0, 0, 0, time.UTC)
+	s.clock = jujutesting.NewClock(s.now)
+	s.facade = &fakeFacade{reports: make(chan packageupdatereporter.Report, 1)}
+	s.pending, s.security, s.checkErr = 5, 2, nil
+	s.config = worker.Config{
+		Facade:    s.facade,
+		MachineId: "42",
+		RootDir:   s.dir,
+		Clock:     s.clock,
+		Interval:  6 * time.Hour,
+		CheckUpdates: func() (int, int, error) {
+			return s.pending, s.security, s.checkErr
+		},
+	}
+}
+
+func (s *WorkerSuite) TestInvalidConfig(c *gc.C) {
+	s.config.Interval = 0
+	_, err := worker.New(s.config)
+	c.Check(err, gc.ErrorMatches, "non-positive Interval not valid")
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *WorkerSuite) TestReportsPeriodically(c *gc.C) {
+	w, err := worker.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	c.Check(s.nextReport(c), jc.DeepEquals, packageupdatereporter.Report{
+		Pending:  5,
+		Security: 2,
+		Updated:  s.now,
+	})
+	c.Check(s.facade.machineId, gc.Equals, "42")
+
+	s.pending, s.security = 9, 3
+	runDir := filepath.Join(s.dir, "var", "run")
+	c.Assert(os.MkdirAll(runDir, 0755), jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(runDir, "reboot-required"), nil, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.waitAlarm(c)
+	s.clock.Advance(6 * time.Hour)
+	c.Check(s.nextReport(c), jc.DeepEquals, packageupdatereporter.Report{
+		Pending:        9,
+		Security:       3,
+		RebootRequired: true,
+		Updated:        s.now.Add(6 * time.Hour),
+	})
+}
+
+func (s *WorkerSuite) TestCheckNotSupported(c *gc.C) {
+	s.checkErr = errors.NotSupportedf("checking for updates")
+	w, err := worker.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	err = workertest.CheckKilled(c, w)
+	c.Check(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
+}
+
+func (s *WorkerSuite) TestCheckError(c *gc.C) {
+	s.checkErr = errors.New("boom")
+	w, err := worker.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	err = workertest.CheckKilled(c, w)
+	c.Check(err, gc.ErrorMatches, "cannot check package updates: boom")
+}
+
+func (s *WorkerSuite) TestReportError(c *gc.C) {
+	s.facade.err = errors.New("blam")
+	w, err := worker.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	err = workertest.CheckKilled(c, w)
+	c.Check(err, gc.ErrorMatches, "cannot report package updates: blam")
+}
+
+func (s *WorkerSuite) nextReport(c *gc.C) (report packageupdatereporter.Report) {
+	select {
+	case report = <-s.facade.reports:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for package updates report")
+	}
+	return report
+}
+
+func (s *WorkerSuite) waitAlarm(c *gc.C) {
+	select {
+	case <-s.clock.Alarms():
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for report timer")
+	}
+}
+
+type fakeFacade struct {
+	machineId string
+	reports   chan packageupdatereporter.Report
+	err       error
+}
+
+func (f *fakeFacade) ReportUpdates(machineId string, report packageupdatereporter.Report) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.machineId = machineId
+	f.reports <- report
+	return nil
+}
@@ -79,10 +79,11 @@ var (
 	pollInterval = 1 * time.Minute
 )
 
-// Hub defines the only method of the apiserver centralhub that
-// the peer grouper uses.
+// Hub defines the methods of the apiserver centralhub that the peer
+// grouper uses.
 type Hub interface {
 	Publish(topic string, data interface{}) (<-chan struct{}, error)
+	Subscribe(topic string, handler interface{}) (func(), error)
 }
 
 // pgWorker is a worker which watches the controller machines in state
@@ -115,6 +116,10 @@ type pgWorker struct {
 	// hub is the central hub of the apiserver, and is used to publish the
 	// details of the api servers.
 	hub Hub
+
+	// detailsRequests receives a value whenever another worker asks
+	// for the details of the api servers.
+	detailsRequests chan struct{}
 }
 
 // New returns a new worker that maintains the mongo replica set
@@ -140,7 +145,8 @@ func newWorker(st stateInterface, clock clock.Clock, pub publisherInterface, sup
 		machineTrackers:        make(map[string]*machineTracker),
 		publisher:              pub,
 		providerSupportsSpaces: supportsSpaces,
-		hub: hub,
+		hub:                    hub,
+		detailsRequests:        make(chan struct{}),
 	}
 	err := catacomb.Invoke(catacomb.Plan{
 		Site: &w.catacomb,
@@ -165,6 +171,11 @@ func (w *pgWorker) loop() error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	unsubscribe, err := w.hub.Subscribe(apiserver.DetailsRequestTopic, w.onDetailsRequest)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer unsubscribe()
 
 	var updateChan <-chan time.Time
 	retryInterval := initialRetryInterval
@@ -187,6 +198,13 @@ func (w *pgWorker) loop() error {
 				updateChan = w.clock.After(0)
 			}
 
+		case <-w.detailsRequests:
+			// Only answer once the controller machines are known;
+			// they are published anyway when they first are.
+			if len(w.machineTrackers) > 0 {
+				w.publishAPIServerDetails()
+			}
+
 		case <-w.machineChanges:
 			logger.Tracef("<-w.machineChanges")
 			// One of the controller machines changed, update the
@@ -338,32 +356,56 @@ func inStrings(t string, ss []string) bool {
 }
 
 func (w *pgWorker) apiPublishInfo() ([][]network.HostPort, []instance.Id, error) {
+	servers := make([][]network.HostPort, 0, len(w.machineTrackers))
+	instanceIds := make([]instance.Id, 0, len(w.machineTrackers))
+	for _, m := range w.machineTrackers {
+		if len(m.APIHostPorts()) == 0 {
+			continue
+		}
+		instanceId, err := m.stm.InstanceId()
+		if err != nil {
+			return nil, nil, err
+		}
+		instanceIds = append(instanceIds, instanceId)
+		servers = append(servers, m.APIHostPorts())
+	}
+	w.publishAPIServerDetails()
+	return servers, instanceIds, nil
+}
+
+// publishAPIServerDetails publishes the ids and addresses of the
+// controller machines on the hub.
+func (w *pgWorker) publishAPIServerDetails() {
 	details := apiserver.Details{
 		Servers:   make(map[string]apiserver.APIServer),
 		LocalOnly: true,
 	}
-	servers := make([][]network.HostPort, 0, len(w.machineTrackers))
-	instanceIds := make([]instance.Id, 0, len(w.machineTrackers))
 	for _, m := range w.machineTrackers {
 		hostPorts := m.APIHostPorts()
-		server := apiserver.APIServer{ID: m.Id()}
 		if len(hostPorts) == 0 {
 			continue
 		}
+		server := apiserver.APIServer{ID: m.Id()}
 		for _, hp := range network.FilterUnusableHostPorts(hostPorts) {
 			server.Addresses = append(server.Addresses, hp.String())
 		}
-
-		instanceId, err := m.stm.InstanceId()
-		if err != nil {
-			return nil, nil, err
-		}
-		instanceIds = append(instanceIds, instanceId)
-		servers = append(servers, m.APIHostPorts())
 		details.Servers[server.ID] = server
 	}
 	w.hub.Publish(apiserver.DetailsTopic, details)
-	return servers, instanceIds, nil
+}
+
+// onDetailsRequest is called by the hub when another worker asks for
+// the details of the api servers. The details are published from the
+// worker's loop, which owns the machine trackers.
+func (w *pgWorker) onDetailsRequest(topic string, request apiserver.DetailsRequest, err error) {
+	if err != nil {
+		logger.Errorf("cannot read api server details request: %v", err)
+		return
+	}
+	select {
+	case w.detailsRequests <- struct{}{}:
+	case <-w.catacomb.Dying():
+	}
 }
 
 // peerGroupInfo collates current session information about the
@@ -513,6 +513,38 @@ func (s *workerSuite) TestControllersArePublishedOverHub(c *gc.C) {
 
 }
 
+func (s *workerSuite) TestControllersArePublishedOnRequest(c *gc.C) {
+	st := NewFakeState()
+	InitState(c, st, 3, testIPv4)
+	hub := pubsub.NewStructuredHub(nil)
+	event := make(chan apiserver.Details)
+	_, err := hub.Subscribe(apiserver.DetailsTopic, func(topic string, data apiserver.Details, err error) {
+		c.Check(err, jc.ErrorIsNil)
+		event <- data
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	w, err := newNoPublishWorker(st, s.clock, hub)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	// Consume the details published when the worker starts.
+	select {
+	case <-event:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for event")
+	}
+
+	_, err = hub.Publish(apiserver.DetailsRequestTopic, apiserver.DetailsRequest{Requester: "test"})
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case obtained := <-event:
+		c.Assert(obtained.Servers, gc.HasLen, 3)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for event")
+	}
+}
+
 func hostPortInSpace(address, spaceName string) network.HostPort {
 	netAddress := network.Address{
 		Value:     address,
@@ -801,6 +833,10 @@ func (h *noOpHub) Publish(topic string, data interface{}) (<-chan struct{}, erro
 	return nil, nil
 }
 
+func (h *noOpHub) Subscribe(topic string, handler interface{}) (func(), error) {
+	return func() {}, nil
+}
+
 func (s *workerSuite) newNoPublishWorker(c *gc.C, st stateInterface) worker.Worker {
 	// We create a new clock for the worker so we can wait on alarms even when
 	// a single test tests both ipv4 and 6 so is creating two workers.
@@ -10,6 +10,8 @@ import (
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/api/base"
 	apiprovisioner "github.com/juju/juju/api/provisioner"
+	apitracing "github.com/juju/juju/api/tracing"
+	"github.com/juju/juju/core/tracing"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/worker/dependency"
 )
@@ -24,7 +26,7 @@ type ManifoldConfig struct {
 	APICallerName string
 	EnvironName   string
 
-	NewProvisionerFunc func(*apiprovisioner.State, agent.Config, environs.Environ) (Provisioner, error)
+	NewProvisionerFunc func(*apiprovisioner.State, agent.Config, environs.Environ, tracing.Recorder) (Provisioner, error)
 }
 
 // Manifold creates a manifold that runs an environemnt provisioner. See the
@@ -54,7 +56,13 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 
 			api := apiprovisioner.NewState(apiCaller)
 			agentConfig := agent.CurrentConfig()
-			w, err := config.NewProvisionerFunc(api, agentConfig, environ)
+			// Controllers without the Tracing facade cannot
+			// record slow provider calls.
+			var spanRecorder tracing.Recorder
+			if apiCaller.BestFacadeVersion("Tracing") > 0 {
+				spanRecorder = apitracing.NewClient(apiCaller)
+			}
+			w, err := config.NewProvisionerFunc(api, agentConfig, environ, spanRecorder)
 			if err != nil {
 				return nil, errors.Trace(err)
 			}
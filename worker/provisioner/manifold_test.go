@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/api/base"
 	apitesting "github.com/juju/juju/api/base/testing"
 	apiprovisioner "github.com/juju/juju/api/provisioner"
+	"github.com/juju/juju/core/tracing"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/worker/dependency"
 	dt "github.com/juju/juju/worker/dependency/testing"
@@ -31,6 +32,7 @@ func (s *ManifoldSuite) makeManifold() dependency.Manifold {
 		apiSt *apiprovisioner.State,
 		agentConf agent.Config,
 		environ environs.Environ,
+		spanRecorder tracing.Recorder,
 	) (provisioner.Provisioner, error) {
 		s.stub.AddCall("NewProvisionerFunc")
 		return struct{ provisioner.Provisioner }{}, nil
@@ -0,0 +1,98 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/instance"
+)
+
+// ApplicationZoneFinder is implemented by provisioner APIs that can
+// report the availability zones of an application's machines, which
+// are needed to resolve machine-with placement.
+type ApplicationZoneFinder interface {
+	// ApplicationZones returns the availability zones of the
+	// provisioned machines hosting units of the named application.
+	ApplicationZones(appName string) ([]string, error)
+}
+
+// resolvedPlacement is a placement directive after the provisioner has
+// evaluated the parts of it that providers do not understand.
+type resolvedPlacement struct {
+	// Zones, if non-nil, holds the availability zones the instance
+	// may be started in.
+	Zones set.Strings
+
+	// Directive is the single-term placement directive to pass to
+	// the provider, if any.
+	Directive string
+}
+
+// resolvePlacement evaluates the model-scoped placement directive of a
+// machine. Single-term directives are left for the provider to
+// interpret, as they always have been. The zone and machine-with terms
+// of a placement expression restrict the availability zones the
+// instance may be started in; at most one other term may remain, and
+// that is passed to the provider.
+func resolvePlacement(directive string, finder ApplicationZoneFinder) (resolvedPlacement, error) {
+	if !instance.IsPlacementExpr(directive) {
+		return resolvedPlacement{Directive: directive}, nil
+	}
+	expr, err := instance.ParsePlacementExpr(directive)
+	if err != nil {
+		return resolvedPlacement{}, errors.Trace(err)
+	}
+
+	var result resolvedPlacement
+	restrict := func(zones set.Strings) {
+		if result.Zones == nil {
+			result.Zones = zones
+		} else {
+			result.Zones = result.Zones.Intersection(zones)
+		}
+	}
+	var providerTerms instance.PlacementExpr
+	for _, term := range expr {
+		switch {
+		case term.Key == instance.PlacementZone && !term.IsFunction():
+			restrict(set.NewStrings(term.Value))
+		case term.Key == instance.PlacementMachineWith:
+			if finder == nil {
+				return resolvedPlacement{}, errors.NotSupportedf("placement %q", term)
+			}
+			appName := term.Arg("app")
+			zones, err := finder.ApplicationZones(appName)
+			if err != nil {
+				return resolvedPlacement{}, errors.Annotatef(err, "resolving %q", term)
+			}
+			if len(zones) == 0 {
+				return resolvedPlacement{}, errors.NotFoundf("availability zone of application %q", appName)
+			}
+			restrict(set.NewStrings(zones...))
+		default:
+			providerTerms = append(providerTerms, term)
+		}
+	}
+	if result.Zones != nil && result.Zones.IsEmpty() {
+		return resolvedPlacement{}, errors.Errorf("no availability zone satisfies placement %q", directive)
+	}
+	switch len(providerTerms) {
+	case 0:
+		// Providers without automatic zone distribution still
+		// honour a single zone given as a directive.
+		if result.Zones.Size() == 1 {
+			result.Directive = instance.PlacementExpr{{
+				Key:   instance.PlacementZone,
+				Value: result.Zones.Values()[0],
+			}}.String()
+		}
+	case 1:
+		result.Directive = providerTerms.String()
+	default:
+		return resolvedPlacement{}, errors.NotSupportedf("more than one provider placement term in %q", directive)
+	}
+	return result, nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/set"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/provisioner"
+)
+
+type placementSuite struct {
+	testing.IsolationSuite
+	finder *fakeZoneFinder
+}
+
+var _ = gc.Suite(&placementSuite{})
+
+func (s *placementSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.finder = &fakeZoneFinder{zones: map[string][]string{
+		"haproxy":  {"zone-a", "zone-b"},
+		"mysql":    {"zone-c"},
+		"unplaced": nil,
+	}}
+}
+
+func (s *placementSuite) TestSingleTermUnchanged(c *gc.C) {
+	for _, directive := range []string{"", "zone=zone-a", "subnet=10.4.0.0/24", "node1.maas"} {
+		result, err := provisioner.ResolvePlacement(directive, s.finder)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(result.Directive, gc.Equals, directive)
+		c.Check(result.Zones, gc.IsNil)
+	}
+	s.finder.CheckNoCalls(c)
+}
+
+func (s *placementSuite) TestZoneAndSubnet(c *gc.C) {
+	result, err := provisioner.ResolvePlacement("zone=zone-a,subnet=10.4.0.0/24", s.finder)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Directive, gc.Equals, "subnet=10.4.0.0/24")
+	c.Check(result.Zones, jc.DeepEquals, set.NewStrings("zone-a"))
+}
+
+func (s *placementSuite) TestMachineWith(c *gc.C) {
+	result, err := provisioner.ResolvePlacement("machine-with(app=haproxy)", s.finder)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Directive, gc.Equals, "")
+	c.Check(result.Zones, jc.DeepEquals, set.NewStrings("zone-a", "zone-b"))
+	s.finder.CheckCall(c, 0, "ApplicationZones", "haproxy")
+}
+
+func (s *placementSuite) TestMachineWithSingleZone(c *gc.C) {
+	result, err := provisioner.ResolvePlacement("machine-with(app=mysql),subnet=10.4.0.0/24", s.finder)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Directive, gc.Equals, "subnet=10.4.0.0/24")
+	c.Check(result.Zones, jc.DeepEquals, set.NewStrings("zone-c"))
+
+	// With no provider term, the single zone is passed as a directive
+	// for providers that do not distribute across zones themselves.
+	result, err = provisioner.ResolvePlacement("machine-with(app=haproxy),zone=zone-b", s.finder)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Directive, gc.Equals, "zone=zone-b")
+	c.Check(result.Zones, jc.DeepEquals, set.NewStrings("zone-b"))
+}
+
+func (s *placementSuite) TestNoZoneSatisfiesAllTerms(c *gc.C) {
+	_, err := provisioner.ResolvePlacement("machine-with(app=mysql),zone=zone-a", s.finder)
+	c.Assert(err, gc.ErrorMatches, `no availability zone satisfies placement "machine-with\(app=mysql\),zone=zone-a"`)
+}
+
+func (s *placementSuite) TestMachineWithNoZones(c *gc.C) {
+	_, err := provisioner.ResolvePlacement("machine-with(app=unplaced)", s.finder)
+	c.Assert(err, gc.ErrorMatches, `availability zone of application "unplaced" not found`)
+}
+
+func (s *placementSuite) TestMachineWithError(c *gc.C) {
+	s.finder.SetErrors(errors.NotFoundf(`application "foo"`))
+	_, err := provisioner.ResolvePlacement("machine-with(app=foo)", s.finder)
+	c.Assert(err, gc.ErrorMatches, `resolving "machine-with\(app=foo\)": application "foo" not found`)
+}
+
+func (s *placementSuite) TestMachineWithNotSupported(c *gc.C) {
+	_, err := provisioner.ResolvePlacement("machine-with(app=haproxy)", nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *placementSuite) TestTooManyProviderTerms(c *gc.C) {
+	_, err := provisioner.ResolvePlacement("subnet=10.4.0.0/24,node1.maas", s.finder)
+	c.Assert(err, gc.ErrorMatches, `more than one provider placement term in "subnet=10.4.0.0/24,node1.maas" not supported`)
+}
+
+func (s *placementSuite) TestInvalidExpression(c *gc.C) {
+	_, err := provisioner.ResolvePlacement("zone=a,", s.finder)
+	c.Assert(err, gc.ErrorMatches, `parsing placement "zone=a,": missing key in ""`)
+}
+
+type fakeZoneFinder struct {
+	testing.Stub
+	zones map[string][]string
+}
+
+func (f *fakeZoneFinder) ApplicationZones(appName string) ([]string, error) {
+	f.MethodCall(f, "ApplicationZones", appName)
+	return f.zones[appName], f.NextErr()
+}
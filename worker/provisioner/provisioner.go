@@ -9,14 +9,17 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
 	"gopkg.in/juju/names.v2"
 	worker "gopkg.in/juju/worker.v1"
 
 	"github.com/juju/juju/agent"
 	apiprovisioner "github.com/juju/juju/api/provisioner"
 	"github.com/juju/juju/controller/authentication"
+	"github.com/juju/juju/core/tracing"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/providerretry"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/watcher"
 	"github.com/juju/juju/worker/catacomb"
@@ -28,11 +31,6 @@ var logger = loggo.GetLogger("juju.provisioner")
 var _ Provisioner = (*environProvisioner)(nil)
 var _ Provisioner = (*containerProvisioner)(nil)
 
-var (
-	retryStrategyDelay = 10 * time.Second
-	retryStrategyCount = 10
-)
-
 // Provisioner represents a running provisioner worker.
 type Provisioner interface {
 	worker.Worker
@@ -65,24 +63,18 @@ type provisioner struct {
 	broker                  environs.InstanceBroker
 	distributionGroupFinder DistributionGroupFinder
 	toolsFinder             ToolsFinder
+	spanRecorder            tracing.Recorder
 	catacomb                catacomb.Catacomb
 }
 
-// RetryStrategy defines the retry behavior when encountering a retryable
-// error during provisioning.
-//
-// TODO(katco): 2016-08-09: lp:1611427
-type RetryStrategy struct {
-	retryDelay time.Duration
-	retryCount int
-}
-
 // NewRetryStrategy returns a new retry strategy with the specified delay and
-// count for use with retryable provisioning errors.
-func NewRetryStrategy(delay time.Duration, count int) RetryStrategy {
-	return RetryStrategy{
-		retryDelay: delay,
-		retryCount: count,
+// count of retries for use with retryable provisioning errors. The model's
+// provider retry settings are used outside of tests.
+func NewRetryStrategy(delay time.Duration, count int) providerretry.Strategy {
+	return providerretry.Strategy{
+		Attempts: count + 1,
+		Delay:    delay,
+		MaxDelay: delay,
 	}
 }
 
@@ -125,7 +117,7 @@ var getDistributionGroupFinder = func(st *apiprovisioner.State) DistributionGrou
 }
 
 // getStartTask creates a new worker for the provisioner,
-func (p *provisioner) getStartTask(harvestMode config.HarvestMode) (ProvisionerTask, error) {
+func (p *provisioner) getStartTask(harvestPolicy HarvestPolicy) (ProvisionerTask, error) {
 	auth, err := authentication.NewAPIAuthenticator(p.st)
 	if err != nil {
 		return nil, err
@@ -159,7 +151,7 @@ func (p *provisioner) getStartTask(harvestMode config.HarvestMode) (ProvisionerT
 	task, err := NewProvisionerTask(
 		controllerCfg.ControllerUUID(),
 		machineTag,
-		harvestMode,
+		harvestPolicy,
 		p.st,
 		p.distributionGroupFinder,
 		p.toolsFinder,
@@ -168,7 +160,11 @@ func (p *provisioner) getStartTask(harvestMode config.HarvestMode) (ProvisionerT
 		p.broker,
 		auth,
 		modelCfg.ImageStream(),
-		RetryStrategy{retryDelay: retryStrategyDelay, retryCount: retryStrategyCount},
+		providerretry.NewStrategy(modelCfg),
+		tracing.Tracer{
+			Recorder: p.spanRecorder,
+			Clock:    clock.WallClock,
+		},
 	)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -178,14 +174,22 @@ func (p *provisioner) getStartTask(harvestMode config.HarvestMode) (ProvisionerT
 
 // NewEnvironProvisioner returns a new Provisioner for an environment.
 // When new machines are added to the state, it allocates instances
-// from the environment and allocates them to the new machines.
-func NewEnvironProvisioner(st *apiprovisioner.State, agentConfig agent.Config, environ environs.Environ) (Provisioner, error) {
+// from the environment and allocates them to the new machines. Its
+// calls to the provider are traced with the span recorder, if it is
+// not nil.
+func NewEnvironProvisioner(
+	st *apiprovisioner.State,
+	agentConfig agent.Config,
+	environ environs.Environ,
+	spanRecorder tracing.Recorder,
+) (Provisioner, error) {
 	p := &environProvisioner{
 		provisioner: provisioner{
 			st:                      st,
 			agentConfig:             agentConfig,
 			toolsFinder:             getToolsFinder(st),
 			distributionGroupFinder: getDistributionGroupFinder(st),
+			spanRecorder:            spanRecorder,
 		},
 		environ: environ,
 	}
@@ -220,8 +224,7 @@ func (p *environProvisioner) loop() error {
 
 	modelConfig := p.environ.Config()
 	p.configObserver.notify(modelConfig)
-	harvestMode := modelConfig.ProvisionerHarvestMode()
-	task, err := p.getStartTask(harvestMode)
+	task, err := p.getStartTask(NewHarvestPolicy(modelConfig))
 	if err != nil {
 		return loggedErrorStack(errors.Trace(err))
 	}
@@ -244,7 +247,7 @@ func (p *environProvisioner) loop() error {
 			if err := p.setConfig(modelConfig); err != nil {
 				return errors.Annotate(err, "loaded invalid model configuration")
 			}
-			task.SetHarvestMode(modelConfig.ProvisionerHarvestMode())
+			task.SetHarvestPolicy(NewHarvestPolicy(modelConfig))
 		}
 	}
 }
@@ -316,9 +319,8 @@ func (p *containerProvisioner) loop() error {
 		return err
 	}
 	p.configObserver.notify(modelConfig)
-	harvestMode := modelConfig.ProvisionerHarvestMode()
 
-	task, err := p.getStartTask(harvestMode)
+	task, err := p.getStartTask(NewHarvestPolicy(modelConfig))
 	if err != nil {
 		return err
 	}
@@ -339,7 +341,7 @@ func (p *containerProvisioner) loop() error {
 				return errors.Annotate(err, "cannot load model configuration")
 			}
 			p.configObserver.notify(modelConfig)
-			task.SetHarvestMode(modelConfig.ProvisionerHarvestMode())
+			task.SetHarvestPolicy(NewHarvestPolicy(modelConfig))
 		}
 	}
 }
@@ -24,9 +24,11 @@ import (
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/controller/authentication"
+	"github.com/juju/juju/core/tracing"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/providerretry"
 	"github.com/juju/juju/environs/simplestreams"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
@@ -44,10 +46,39 @@ import (
 type ProvisionerTask interface {
 	worker.Worker
 
-	// SetHarvestMode sets a flag to indicate how the provisioner task
-	// should harvest machines. See config.HarvestMode for
+	// SetHarvestPolicy sets the policy that determines how the
+	// provisioner task should harvest machines. See HarvestPolicy for
 	// documentation of behavior.
-	SetHarvestMode(mode config.HarvestMode)
+	SetHarvestPolicy(policy HarvestPolicy)
+}
+
+// HarvestPolicy determines which instances a provisioner task stops
+// when they are no longer required.
+type HarvestPolicy struct {
+	// Mode determines which kinds of instance are harvested. See
+	// config.HarvestMode for documentation of behavior.
+	Mode config.HarvestMode
+
+	// DryRun, if true, causes the instances that would have been
+	// harvested to be logged, rather than stopped.
+	DryRun bool
+
+	// Exempt holds the IDs of unknown instances that must never be
+	// harvested, such as those that were started outside of Juju.
+	Exempt []instance.Id
+}
+
+// NewHarvestPolicy returns the harvest policy described by the
+// supplied model config.
+func NewHarvestPolicy(cfg *config.Config) HarvestPolicy {
+	policy := HarvestPolicy{
+		Mode:   cfg.ProvisionerHarvestMode(),
+		DryRun: cfg.ProvisionerHarvestDryRun(),
+	}
+	for _, id := range cfg.ProvisionerHarvestExemptions() {
+		policy.Exempt = append(policy.Exempt, instance.Id(id))
+	}
+	return policy
 }
 
 type MachineGetter interface {
@@ -71,7 +102,7 @@ type ToolsFinder interface {
 func NewProvisionerTask(
 	controllerUUID string,
 	machineTag names.MachineTag,
-	harvestMode config.HarvestMode,
+	harvestPolicy HarvestPolicy,
 	machineGetter MachineGetter,
 	distributionGroupFinder DistributionGroupFinder,
 	toolsFinder ToolsFinder,
@@ -80,7 +111,8 @@ func NewProvisionerTask(
 	broker environs.InstanceBroker,
 	auth authentication.AuthenticationProvider,
 	imageStream string,
-	retryStartInstanceStrategy RetryStrategy,
+	retryStartInstanceStrategy providerretry.Strategy,
+	tracer tracing.Tracer,
 ) (ProvisionerTask, error) {
 	machineChanges := machineWatcher.Changes()
 	workers := []worker.Worker{machineWatcher}
@@ -99,12 +131,13 @@ func NewProvisionerTask(
 		retryChanges:               retryChanges,
 		broker:                     broker,
 		auth:                       auth,
-		harvestMode:                harvestMode,
-		harvestModeChan:            make(chan config.HarvestMode, 1),
+		harvestPolicy:              harvestPolicy,
+		harvestPolicyChan:          make(chan HarvestPolicy, 1),
 		machines:                   make(map[string]*apiprovisioner.Machine),
 		availabilityZoneMachines:   make([]*AvailabilityZoneMachine, 0),
 		imageStream:                imageStream,
 		retryStartInstanceStrategy: retryStartInstanceStrategy,
+		tracer:                     tracer,
 	}
 	err := catacomb.Invoke(catacomb.Plan{
 		Site: &task.catacomb,
@@ -135,9 +168,10 @@ type provisionerTask struct {
 	catacomb                   catacomb.Catacomb
 	auth                       authentication.AuthenticationProvider
 	imageStream                string
-	harvestMode                config.HarvestMode
-	harvestModeChan            chan config.HarvestMode
-	retryStartInstanceStrategy RetryStrategy
+	harvestPolicy              HarvestPolicy
+	harvestPolicyChan          chan HarvestPolicy
+	retryStartInstanceStrategy providerretry.Strategy
+	tracer                     tracing.Tracer
 	// instance id -> instance
 	instances map[instance.Id]instance.Instance
 	// machine id -> machine
@@ -158,11 +192,11 @@ func (task *provisionerTask) Wait() error {
 
 func (task *provisionerTask) loop() error {
 
-	// Don't allow the harvesting mode to change until we have read at
+	// Don't allow the harvesting policy to change until we have read at
 	// least one set of changes, which will populate the task.machines
 	// map. Otherwise we will potentially see all legitimate instances
 	// as unknown.
-	var harvestModeChan chan config.HarvestMode
+	var harvestPolicyChan chan HarvestPolicy
 
 	// When the watcher is started, it will have the initial changes be all
 	// the machines that are relevant. Also, since this is available straight
@@ -180,15 +214,23 @@ func (task *provisionerTask) loop() error {
 				return errors.Annotate(err, "failed to process updated machines")
 			}
 			// We've seen a set of changes. Enable modification of
-			// harvesting mode.
-			harvestModeChan = task.harvestModeChan
-		case harvestMode := <-harvestModeChan:
-			if harvestMode == task.harvestMode {
+			// harvesting policy.
+			harvestPolicyChan = task.harvestPolicyChan
+		case policy := <-harvestPolicyChan:
+			previous := task.harvestPolicy
+			task.harvestPolicy = policy
+			if policy.Mode != previous.Mode || policy.DryRun != previous.DryRun {
+				logger.Infof("harvesting mode changed to %s (dry run: %v)", policy.Mode, policy.DryRun)
+			} else if lifted := exemptionsLifted(previous, policy); len(lifted) > 0 {
+				// Instances that are no longer exempt may now
+				// be unknown instances to harvest.
+				logger.Infof("harvesting exemptions lifted for %v", lifted)
+			} else {
+				// Adding exemptions can only reduce the set
+				// of instances to harvest.
 				break
 			}
-			logger.Infof("harvesting mode changed to %s", harvestMode)
-			task.harvestMode = harvestMode
-			if harvestMode.HarvestUnknown() {
+			if policy.Mode.HarvestUnknown() {
 				logger.Infof("harvesting unknown machines")
 				if err := task.processMachines(nil); err != nil {
 					return errors.Annotate(err, "failed to process machines after safe mode disabled")
@@ -202,10 +244,27 @@ func (task *provisionerTask) loop() error {
 	}
 }
 
-// SetHarvestMode implements ProvisionerTask.SetHarvestMode().
-func (task *provisionerTask) SetHarvestMode(mode config.HarvestMode) {
+// exemptionsLifted returns the IDs of the instances that were exempt
+// from harvesting under the previous policy, but are not under the
+// current one.
+func exemptionsLifted(previous, current HarvestPolicy) []instance.Id {
+	exempt := make(map[instance.Id]bool, len(current.Exempt))
+	for _, id := range current.Exempt {
+		exempt[id] = true
+	}
+	var lifted []instance.Id
+	for _, id := range previous.Exempt {
+		if !exempt[id] {
+			lifted = append(lifted, id)
+		}
+	}
+	return lifted
+}
+
+// SetHarvestPolicy implements ProvisionerTask.SetHarvestPolicy().
+func (task *provisionerTask) SetHarvestPolicy(policy HarvestPolicy) {
 	select {
-	case task.harvestModeChan <- mode:
+	case task.harvestPolicyChan <- policy:
 	case <-task.catacomb.Dying():
 	}
 }
@@ -259,24 +318,34 @@ func (task *provisionerTask) processMachines(ids []string) error {
 	if err != nil {
 		return err
 	}
-	if !task.harvestMode.HarvestUnknown() {
+	harvestMode := task.harvestPolicy.Mode
+	if !harvestMode.HarvestUnknown() {
 		logger.Infof(
 			"%s is set to %s; unknown instances not stopped %v",
 			config.ProvisionerHarvestModeKey,
-			task.harvestMode.String(),
+			harvestMode.String(),
 			instanceIds(unknown),
 		)
 		unknown = nil
 	}
-	if task.harvestMode.HarvestNone() || !task.harvestMode.HarvestDestroyed() {
+	if harvestMode.HarvestNone() || !harvestMode.HarvestDestroyed() {
 		logger.Infof(
 			`%s is set to "%s"; will not harvest %s`,
 			config.ProvisionerHarvestModeKey,
-			task.harvestMode.String(),
+			harvestMode.String(),
 			instanceIds(stopping),
 		)
 		stopping = nil
 	}
+	if task.harvestPolicy.DryRun && len(stopping)+len(unknown) > 0 {
+		logger.Infof(
+			"%s is set; would stop known instances %v and unknown instances %v",
+			config.ProvisionerHarvestDryRunKey,
+			instanceIds(stopping),
+			instanceIds(unknown),
+		)
+		stopping, unknown = nil, nil
+	}
 
 	if len(stopping) > 0 {
 		logger.Infof("stopping known instances %v", stopping)
@@ -322,7 +391,11 @@ func instanceIds(instances []instance.Instance) []string {
 func (task *provisionerTask) populateMachineMaps(ids []string) error {
 	task.instances = make(map[instance.Id]instance.Instance)
 
-	instances, err := task.broker.AllInstances()
+	var instances []instance.Instance
+	err := task.tracer.Trace(tracing.KindProviderCall, task.machineTag.String(), "AllInstances", func() (err error) {
+		instances, err = task.broker.AllInstances()
+		return err
+	})
 	if err != nil {
 		return errors.Annotate(err, "failed to get all instances from broker")
 	}
@@ -474,6 +547,13 @@ func (task *provisionerTask) findUnknownInstances(stopping []instance.Instance)
 	for _, inst := range stopping {
 		delete(instances, inst.Id())
 	}
+	// Instances exempted from harvesting are never considered unknown.
+	for _, id := range task.harvestPolicy.Exempt {
+		if _, ok := instances[id]; ok {
+			logger.Debugf("instance %v is unknown but exempt from harvesting", id)
+			delete(instances, id)
+		}
+	}
 	var unknown []instance.Instance
 	for _, inst := range instances {
 		unknown = append(unknown, inst)
@@ -518,7 +598,9 @@ func (task *provisionerTask) stopInstances(instances []instance.Instance) error
 	for i, inst := range instances {
 		ids[i] = inst.Id()
 	}
-	if err := task.broker.StopInstances(ids...); err != nil {
+	if err := task.tracer.Trace(tracing.KindProviderCall, task.machineTag.String(), "StopInstances", func() error {
+		return task.broker.StopInstances(ids...)
+	}); err != nil {
 		return errors.Annotate(err, "broker failed to stop instances")
 	}
 	return nil
@@ -704,7 +786,9 @@ func (task *provisionerTask) maintainMachines(machines []*apiprovisioner.Machine
 		startInstanceParams := environs.StartInstanceParams{}
 		startInstanceParams.InstanceConfig = &instancecfg.InstanceConfig{}
 		startInstanceParams.InstanceConfig.MachineId = m.Id()
-		if err := task.broker.MaintainInstance(startInstanceParams); err != nil {
+		if err := task.tracer.Trace(tracing.KindProviderCall, m.Tag().String(), "MaintainInstance", func() error {
+			return task.broker.MaintainInstance(startInstanceParams)
+		}); err != nil {
 			return errors.Annotatef(err, "cannot maintain machine %v", m)
 		}
 	}
@@ -1008,6 +1092,35 @@ func (task *provisionerTask) populateExcludedMachines(machineId string, startIns
 	return nil
 }
 
+// excludeMachineFromOtherZones excludes the machine from being started
+// in any availability zone not in the supplied set. A nil set leaves
+// the machine unrestricted.
+func (task *provisionerTask) excludeMachineFromOtherZones(machineId string, zones set.Strings) {
+	if zones == nil {
+		return
+	}
+	task.azMachinesMutex.Lock()
+	defer task.azMachinesMutex.Unlock()
+	for _, zoneMachines := range task.availabilityZoneMachines {
+		if !zones.Contains(zoneMachines.ZoneName) {
+			zoneMachines.ExcludedMachineIds.Add(machineId)
+		}
+	}
+}
+
+// startInstance asks the broker to start an instance for the machine,
+// tracing the call.
+func (task *provisionerTask) startInstance(
+	machine *apiprovisioner.Machine,
+	args environs.StartInstanceParams,
+) (result *environs.StartInstanceResult, err error) {
+	err = task.tracer.Trace(tracing.KindProviderCall, machine.Tag().String(), "StartInstance", func() error {
+		result, err = task.broker.StartInstance(args)
+		return err
+	})
+	return result, err
+}
+
 func (task *provisionerTask) startMachine(
 	machine *apiprovisioner.Machine,
 	distributionGroupMachineIds []string,
@@ -1021,12 +1134,22 @@ func (task *provisionerTask) startMachine(
 		return task.setErrorStatus("%v", machine, err)
 	}
 
+	// Evaluate any placement expression, leaving the provider a
+	// directive it understands.
+	zoneFinder, _ := task.distributionGroupFinder.(ApplicationZoneFinder)
+	placement, err := resolvePlacement(startInstanceParams.Placement, zoneFinder)
+	if err != nil {
+		return task.setErrorStatus("cannot resolve placement for machine %q: %v", machine, err)
+	}
+	startInstanceParams.Placement = placement.Directive
+
 	// Figure out if the zones available to use for a new instance are
 	// restricted based on placement, and if so exclude those machines
 	// from being started in any other zone.
 	if err := task.populateExcludedMachines(machine.Id(), startInstanceParams); err != nil {
 		return err
 	}
+	task.excludeMachineFromOtherZones(machine.Id(), placement.Zones)
 
 	// TODO (jam): 2017-01-19 Should we be setting this earlier in the cycle?
 	if err := machine.SetInstanceStatus(status.Provisioning, "starting", nil); err != nil {
@@ -1038,12 +1161,15 @@ func (task *provisionerTask) startMachine(
 	// Is rate limiting handled correctly?
 	var result *environs.StartInstanceResult
 
-	// Attempt creating the instance "retryCount" times. If the provider
-	// supports availability zones and we're automatically distributing
-	// across the zones, then we try each zone for every attempt, or until
-	// one of the StartInstance calls returns an error satisfying
-	// environs.IsAvailabilityZoneIndependent.
-	for attemptsLeft := task.retryStartInstanceStrategy.retryCount; attemptsLeft >= 0; {
+	// Attempt creating the instance as many times as the retry strategy
+	// allows. If the provider supports availability zones and we're
+	// automatically distributing across the zones, then we try each zone
+	// for every attempt, or until one of the StartInstance calls returns
+	// an error satisfying environs.IsAvailabilityZoneIndependent.
+	// Transient errors do not use up attempts, and errors caused by an
+	// invalid credential are not retried at all.
+	var retryDelay time.Duration
+	for attemptsLeft := task.retryStartInstanceStrategy.Attempts - 1; attemptsLeft >= 0; {
 		startInstanceParams.AvailabilityZone, err = task.machineAvailabilityZoneDistribution(machine.Id(), distributionGroupMachineIds)
 		if err != nil {
 			return task.setErrorStatus("cannot start instance for machine %q: %v", machine, err)
@@ -1052,17 +1178,19 @@ func (task *provisionerTask) startMachine(
 			logger.Infof("trying machine %s StartInstance in availability zone %s", machine, startInstanceParams.AvailabilityZone)
 		}
 
-		attemptResult, err := task.broker.StartInstance(startInstanceParams)
+		attemptResult, err := task.startInstance(machine, startInstanceParams)
 		if err == nil {
 			result = attemptResult
 			break
-		} else if attemptsLeft <= 0 {
+		} else if !providerretry.ShouldRetry(err) ||
+			(attemptsLeft <= 0 && providerretry.CountsAsAttempt(err)) {
 			// Set the state to error, so the machine will be skipped
 			// next time until the error is resolved.
 			task.removeMachineFromAZMap(machine)
 			return task.setErrorStatus("cannot start instance for machine %q: %v", machine, err)
 		}
 
+		retryDelay = task.retryStartInstanceStrategy.NextDelay(retryDelay)
 		retrying := true
 		retryMsg := ""
 		if startInstanceParams.AvailabilityZone != "" && !environs.IsAvailabilityZoneIndependent(err) {
@@ -1078,8 +1206,7 @@ func (task *provisionerTask) startMachine(
 			if azRemaining {
 				retryMsg = fmt.Sprintf(
 					"failed to start machine %s in zone %q, retrying in %v with new availability zone: %s",
-					machine, startInstanceParams.AvailabilityZone,
-					task.retryStartInstanceStrategy.retryDelay, err,
+					machine, startInstanceParams.AvailabilityZone, retryDelay, err,
 				)
 				logger.Debugf("%s", retryMsg)
 				// There's still more zones to try, so don't decrement "attemptsLeft" yet.
@@ -1094,10 +1221,12 @@ func (task *provisionerTask) startMachine(
 		if retrying {
 			retryMsg = fmt.Sprintf(
 				"failed to start machine %s (%s), retrying in %v (%d more attempts)",
-				machine, err.Error(), task.retryStartInstanceStrategy.retryDelay, attemptsLeft,
+				machine, err.Error(), retryDelay, attemptsLeft,
 			)
 			logger.Warningf("%s", retryMsg)
-			attemptsLeft--
+			if providerretry.CountsAsAttempt(err) {
+				attemptsLeft--
+			}
 		}
 
 		if err3 := machine.SetInstanceStatus(status.Provisioning, retryMsg, nil); err3 != nil {
@@ -1107,7 +1236,7 @@ func (task *provisionerTask) startMachine(
 		select {
 		case <-task.catacomb.Dying():
 			return task.catacomb.ErrDying()
-		case <-time.After(task.retryStartInstanceStrategy.retryDelay):
+		case <-time.After(retryDelay):
 		}
 	}
 
@@ -1127,7 +1256,9 @@ func (task *provisionerTask) startMachine(
 		if err2 := task.setErrorStatus("cannot register instance for machine %v: %v", machine, err); err2 != nil {
 			logger.Errorf("%v", errors.Annotate(err2, "cannot set machine's status"))
 		}
-		if err2 := task.broker.StopInstances(result.Instance.Id()); err2 != nil {
+		if err2 := task.tracer.Trace(tracing.KindProviderCall, machine.Tag().String(), "StopInstances", func() error {
+			return task.broker.StopInstances(result.Instance.Id())
+		}); err2 != nil {
 			logger.Errorf("%v", errors.Annotate(err2, "after failing to set instance info"))
 		}
 		return errors.Annotate(err, "cannot set instance info")
@@ -13,6 +13,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	"github.com/juju/utils/arch"
+	"github.com/juju/utils/clock"
 	"github.com/juju/utils/series"
 	"github.com/juju/utils/set"
 	"github.com/juju/version"
@@ -27,11 +28,13 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/controller/authentication"
+	"github.com/juju/juju/core/tracing"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/filestorage"
 	"github.com/juju/juju/environs/imagemetadata"
 	imagetesting "github.com/juju/juju/environs/imagemetadata/testing"
+	"github.com/juju/juju/environs/providerretry"
 	envtesting "github.com/juju/juju/environs/testing"
 	"github.com/juju/juju/environs/tools"
 	"github.com/juju/juju/instance"
@@ -63,6 +66,17 @@ type CommonProvisionerSuite struct {
 	provisioner *apiprovisioner.State
 }
 
+// setProviderRetryConfig sets the model's provider retry settings, which
+// the environ provisioner uses when starting instances.
+func (s *CommonProvisionerSuite) setProviderRetryConfig(c *gc.C, attempts int, delay time.Duration) {
+	err := s.IAASModel.UpdateModelConfig(map[string]interface{}{
+		config.ProviderRetryAttemptsKey: attempts,
+		config.ProviderRetryDelayKey:    delay.String(),
+		config.ProviderRetryMaxDelayKey: delay.String(),
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *CommonProvisionerSuite) assertProvisionerObservesConfigChanges(c *gc.C, p provisioner.Provisioner) {
 	// Inject our observer into the provisioner
 	cfgObserver := make(chan *config.Config, 1)
@@ -104,10 +118,16 @@ func (s *CommonProvisionerSuite) assertProvisionerObservesConfigChanges(c *gc.C,
 
 type ProvisionerSuite struct {
 	CommonProvisionerSuite
+	tracer tracing.Tracer
 }
 
 var _ = gc.Suite(&ProvisionerSuite{})
 
+func (s *ProvisionerSuite) SetUpTest(c *gc.C) {
+	s.CommonProvisionerSuite.SetUpTest(c)
+	s.tracer = tracing.Tracer{}
+}
+
 func (s *CommonProvisionerSuite) SetUpSuite(c *gc.C) {
 	s.JujuConnSuite.SetUpSuite(c)
 	s.defaultConstraints = constraints.MustParse("arch=amd64 mem=4G cores=1 root-disk=8G")
@@ -459,7 +479,7 @@ func (s *CommonProvisionerSuite) newEnvironProvisioner(c *gc.C) provisioner.Prov
 	machineTag := names.NewMachineTag("0")
 	agentConfig := s.AgentConfigForTag(c, machineTag)
 	apiState := apiprovisioner.NewState(s.st)
-	w, err := provisioner.NewEnvironProvisioner(apiState, agentConfig, s.Environ)
+	w, err := provisioner.NewEnvironProvisioner(apiState, agentConfig, s.Environ, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	return w
 }
@@ -639,9 +659,8 @@ func (s *ProvisionerSuite) waitUntilMachineNotPending(c *gc.C, m *state.Machine)
 }
 
 func (s *ProvisionerSuite) TestProvisionerFailedStartInstanceWithInjectedCreationError(c *gc.C) {
-	// Set the retry delay to 0, and retry count to 2 to keep tests short
-	s.PatchValue(provisioner.RetryStrategyDelay, 0*time.Second)
-	s.PatchValue(provisioner.RetryStrategyCount, 2)
+	// Set the retry delay to 1ms, and retry count to 2 to keep tests short
+	s.setProviderRetryConfig(c, 3, time.Millisecond)
 
 	// create the error injection channel
 	errorInjectionChannel := make(chan error, 3)
@@ -677,9 +696,8 @@ func (s *ProvisionerSuite) TestProvisionerFailedStartInstanceWithInjectedCreatio
 }
 
 func (s *ProvisionerSuite) TestProvisionerSucceedStartInstanceWithInjectedRetryableCreationError(c *gc.C) {
-	// Set the retry delay to 0, and retry count to 2 to keep tests short
-	s.PatchValue(provisioner.RetryStrategyDelay, 0*time.Second)
-	s.PatchValue(provisioner.RetryStrategyCount, 2)
+	// Set the retry delay to 1ms, and retry count to 2 to keep tests short
+	s.setProviderRetryConfig(c, 3, time.Millisecond)
 
 	// create the error injection channel
 	errorInjectionChannel := make(chan error, 1)
@@ -1279,7 +1297,7 @@ func (s *ProvisionerSuite) newProvisionerTaskWithRetryStrategy(
 	machineGetter provisioner.MachineGetter,
 	distributionGroupFinder provisioner.DistributionGroupFinder,
 	toolsFinder provisioner.ToolsFinder,
-	retryStrategy provisioner.RetryStrategy,
+	retryStrategy providerretry.Strategy,
 ) provisioner.ProvisionerTask {
 
 	machineWatcher, err := s.provisioner.WatchModelMachines()
@@ -1292,7 +1310,7 @@ func (s *ProvisionerSuite) newProvisionerTaskWithRetryStrategy(
 	w, err := provisioner.NewProvisionerTask(
 		s.ControllerConfig.ControllerUUID(),
 		names.NewMachineTag("0"),
-		harvestingMethod,
+		provisioner.HarvestPolicy{Mode: harvestingMethod},
 		machineGetter,
 		distributionGroupFinder,
 		toolsFinder,
@@ -1302,16 +1320,87 @@ func (s *ProvisionerSuite) newProvisionerTaskWithRetryStrategy(
 		auth,
 		imagemetadata.ReleasedStream,
 		retryStrategy,
+		s.tracer,
 	)
 	c.Assert(err, jc.ErrorIsNil)
 	return w
 }
 
+func (s *ProvisionerSuite) TestProvisionerTracesStartInstance(c *gc.C) {
+	recorder := &chanSpanRecorder{spans: make(chan tracing.Span, 100)}
+	s.tracer = tracing.Tracer{Recorder: recorder, Clock: clock.WallClock}
+	task := s.newProvisionerTask(c, config.HarvestDestroyed, s.Environ, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{})
+	defer workertest.CleanKill(c, task)
+
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstance(c, m)
+
+	span := recorder.waitForSpan(c, "StartInstance")
+	c.Check(span.Kind, gc.Equals, tracing.KindProviderCall)
+	c.Check(span.Entity, gc.Equals, m.Tag().String())
+	c.Check(span.Error, gc.Equals, "")
+}
+
+func (s *ProvisionerSuite) TestProvisionerTracesAllAndStopInstances(c *gc.C) {
+	recorder := &chanSpanRecorder{spans: make(chan tracing.Span, 100)}
+	s.tracer = tracing.Tracer{Recorder: recorder, Clock: clock.WallClock}
+	task := s.newProvisionerTask(c, config.HarvestDestroyed, s.Environ, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{})
+	defer workertest.CleanKill(c, task)
+
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	i := s.checkStartInstance(c, m)
+
+	span := recorder.waitForSpan(c, "AllInstances")
+	c.Check(span.Kind, gc.Equals, tracing.KindProviderCall)
+	c.Check(span.Entity, gc.Equals, names.NewMachineTag("0").String())
+
+	c.Assert(m.EnsureDead(), gc.IsNil)
+	s.checkStopInstances(c, i)
+
+	span = recorder.waitForSpan(c, "StopInstances")
+	c.Check(span.Kind, gc.Equals, tracing.KindProviderCall)
+	c.Check(span.Entity, gc.Equals, names.NewMachineTag("0").String())
+	c.Check(span.Error, gc.Equals, "")
+}
+
+// chanSpanRecorder sends the spans it records on a channel, dropping
+// them if the channel is full.
+type chanSpanRecorder struct {
+	spans chan tracing.Span
+}
+
+func (r *chanSpanRecorder) RecordSpans(spans []tracing.Span) error {
+	for _, span := range spans {
+		select {
+		case r.spans <- span:
+		default:
+		}
+	}
+	return nil
+}
+
+// waitForSpan returns the first recorded span with the given name.
+func (r *chanSpanRecorder) waitForSpan(c *gc.C, name string) tracing.Span {
+	timeout := time.After(coretesting.LongWait)
+	for {
+		select {
+		case span := <-r.spans:
+			if span.Name == name {
+				return span
+			}
+		case <-timeout:
+			c.Fatalf("timed out waiting for %s span", name)
+		}
+	}
+}
+
 func (s *ProvisionerSuite) TestHarvestNoneReapsNothing(c *gc.C) {
 
 	task := s.newProvisionerTask(c, config.HarvestDestroyed, s.Environ, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{})
 	defer workertest.CleanKill(c, task)
-	task.SetHarvestMode(config.HarvestNone)
+	task.SetHarvestPolicy(provisioner.HarvestPolicy{Mode: config.HarvestNone})
 
 	// Create a machine and an unknown instance.
 	m0, err := s.addMachine()
@@ -1336,7 +1425,7 @@ func (s *ProvisionerSuite) TestHarvestUnknownReapsOnlyUnknown(c *gc.C) {
 		mockToolsFinder{},
 	)
 	defer workertest.CleanKill(c, task)
-	task.SetHarvestMode(config.HarvestUnknown)
+	task.SetHarvestPolicy(provisioner.HarvestPolicy{Mode: config.HarvestUnknown})
 
 	// Create a machine and an unknown instance.
 	m0, err := s.addMachine()
@@ -1390,7 +1479,7 @@ func (s *ProvisionerSuite) TestHarvestAllReapsAllTheThings(c *gc.C) {
 		mockToolsFinder{},
 	)
 	defer workertest.CleanKill(c, task)
-	task.SetHarvestMode(config.HarvestAll)
+	task.SetHarvestPolicy(provisioner.HarvestPolicy{Mode: config.HarvestAll})
 
 	// Create a machine and an unknown instance.
 	m0, err := s.addMachine()
@@ -1406,6 +1495,91 @@ func (s *ProvisionerSuite) TestHarvestAllReapsAllTheThings(c *gc.C) {
 	s.waitForRemovalMark(c, m0)
 }
 
+func (s *ProvisionerSuite) TestHarvestDryRunReapsNothing(c *gc.C) {
+
+	task := s.newProvisionerTask(c, config.HarvestDestroyed, s.Environ, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{})
+	defer workertest.CleanKill(c, task)
+	task.SetHarvestPolicy(provisioner.HarvestPolicy{
+		Mode:   config.HarvestAll,
+		DryRun: true,
+	})
+
+	// Create a machine and an unknown instance.
+	m0, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstance(c, m0)
+	s.startUnknownInstance(c, "999")
+
+	// Mark the first machine as dead.
+	c.Assert(m0.EnsureDead(), gc.IsNil)
+
+	// Ensure we're only reporting what would be harvested.
+	s.checkNoOperations(c)
+}
+
+func (s *ProvisionerSuite) TestHarvestAllSparesExemptInstances(c *gc.C) {
+
+	task := s.newProvisionerTask(c,
+		config.HarvestDestroyed,
+		s.Environ,
+		s.provisioner,
+		&mockDistributionGroupFinder{},
+		mockToolsFinder{},
+	)
+	defer workertest.CleanKill(c, task)
+
+	// Create a machine and two unknown instances.
+	m0, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	i0 := s.checkStartInstance(c, m0)
+	i1 := s.startUnknownInstance(c, "998")
+	i2 := s.startUnknownInstance(c, "999")
+
+	// Exempt one of the unknown instances from harvesting.
+	task.SetHarvestPolicy(provisioner.HarvestPolicy{
+		Mode:   config.HarvestAll,
+		Exempt: []instance.Id{i2.Id()},
+	})
+
+	// Mark the first machine as dead.
+	c.Assert(m0.EnsureDead(), gc.IsNil)
+
+	// Everything but the exempt instance must die.
+	s.checkStopSomeInstances(c, []instance.Instance{i0, i1}, []instance.Instance{i2})
+	s.waitForRemovalMark(c, m0)
+}
+
+func (s *ProvisionerSuite) TestHarvestAllReapsInstancesNoLongerExempt(c *gc.C) {
+
+	task := s.newProvisionerTask(c,
+		config.HarvestDestroyed,
+		s.Environ,
+		s.provisioner,
+		&mockDistributionGroupFinder{},
+		mockToolsFinder{},
+	)
+	defer workertest.CleanKill(c, task)
+
+	// Create a machine so that the task has seen a set of
+	// changes, and an unknown instance that is exempt.
+	m0, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstance(c, m0)
+	i1 := s.startUnknownInstance(c, "999")
+	task.SetHarvestPolicy(provisioner.HarvestPolicy{
+		Mode:   config.HarvestAll,
+		Exempt: []instance.Id{i1.Id()},
+	})
+	s.checkNoOperations(c)
+
+	// Lifting the exemption alone causes the instance to be
+	// harvested.
+	task.SetHarvestPolicy(provisioner.HarvestPolicy{
+		Mode: config.HarvestAll,
+	})
+	s.checkStopInstances(c, i1)
+}
+
 func (s *ProvisionerSuite) TestStopInstancesIgnoresMachinesWithKeep(c *gc.C) {
 
 	task := s.newProvisionerTask(c,
@@ -1861,6 +2035,53 @@ func (s *ProvisionerSuite) TestProvisioningMachinesDerivedAZ(c *gc.C) {
 	}
 }
 
+func (s *ProvisionerSuite) TestProvisioningMachinesCredentialErrorNotRetried(c *gc.C) {
+	s.PatchValue(&apiserverprovisioner.ErrorRetryWaitDelay, 5*time.Millisecond)
+	credentialError := providercommon.CredentialNotValidError(errors.New("denied"))
+	e := &mockBroker{
+		Environ:    s.Environ,
+		retryCount: make(map[string]int),
+		startInstanceFailureInfo: map[string]mockBrokerFailures{
+			"1": {whenSucceed: 3, err: credentialError},
+		},
+	}
+	retryStrategy := provisioner.NewRetryStrategy(5*time.Millisecond, 4)
+	task := s.newProvisionerTaskWithRetryStrategy(c, config.HarvestDestroyed,
+		e, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{}, retryStrategy)
+	defer workertest.CleanKill(c, task)
+
+	machine, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	agentStatus, _ := s.waitUntilMachineNotPending(c, machine)
+	c.Check(agentStatus.Status, gc.Equals, status.Error)
+	c.Check(agentStatus.Message, gc.Equals, credentialError.Error())
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	c.Assert(e.retryCount[machine.Id()], gc.Equals, 1)
+}
+
+func (s *ProvisionerSuite) TestProvisioningMachinesTransientErrorsNotCounted(c *gc.C) {
+	s.PatchValue(&apiserverprovisioner.ErrorRetryWaitDelay, 5*time.Millisecond)
+	e := &mockBroker{
+		Environ:    s.Environ,
+		retryCount: make(map[string]int),
+		startInstanceFailureInfo: map[string]mockBrokerFailures{
+			"1": {whenSucceed: 5, err: providercommon.TransientError(errors.New("throttled"))},
+		},
+	}
+	// No retries are allowed, but transient errors do not count.
+	retryStrategy := provisioner.NewRetryStrategy(5*time.Millisecond, 0)
+	task := s.newProvisionerTaskWithRetryStrategy(c, config.HarvestDestroyed,
+		e, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{}, retryStrategy)
+	defer workertest.CleanKill(c, task)
+
+	machine, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstance(c, machine)
+	c.Assert(e.retryCount[machine.Id()], gc.Equals, 5)
+}
+
 func (s *ProvisionerSuite) TestProvisioningMachinesNoZonedEnviron(c *gc.C) {
 	// Make sure the provisioner still works for providers which do not
 	// implement the ZonedEnviron interface.
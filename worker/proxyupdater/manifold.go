@@ -5,12 +5,15 @@ package proxyupdater
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/utils/exec"
 	"github.com/juju/utils/proxy"
+	"github.com/juju/utils/series"
 	worker "gopkg.in/juju/worker.v1"
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/proxyupdater"
+	"github.com/juju/juju/juju/paths"
 	"github.com/juju/juju/worker/dependency"
 )
 
@@ -52,13 +55,21 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 			if err != nil {
 				return nil, err
 			}
+			// The trust store is left unmanaged on OSes whose trust
+			// store Juju does not know how to update.
+			trustStore, err := paths.SystemTrustStore(series.MustHostSeries())
+			if err != nil && !errors.IsNotSupported(err) {
+				return nil, errors.Trace(err)
+			}
 			w, err := config.WorkerFunc(Config{
-				SystemdFiles:    []string{"/etc/juju-proxy-systemd.conf"},
-				EnvFiles:        []string{"/etc/juju-proxy.conf"},
-				RegistryPath:    `HKCU:\Software\Microsoft\Windows\CurrentVersion\Internet Settings`,
-				API:             proxyAPI,
-				ExternalUpdate:  config.ExternalUpdate,
-				InProcessUpdate: config.InProcessUpdate,
+				SystemdFiles:     []string{"/etc/juju-proxy-systemd.conf"},
+				EnvFiles:         []string{"/etc/juju-proxy.conf"},
+				RegistryPath:     `HKCU:\Software\Microsoft\Windows\CurrentVersion\Internet Settings`,
+				API:              proxyAPI,
+				ExternalUpdate:   config.ExternalUpdate,
+				InProcessUpdate:  config.InProcessUpdate,
+				TrustStore:       trustStore,
+				UpdateTrustStore: updateTrustStore(trustStore.UpdateCommand),
 			})
 			if err != nil {
 				return nil, errors.Trace(err)
@@ -67,3 +78,18 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 		},
 	}
 }
+
+// updateTrustStore returns a function that runs the given command to
+// rebuild the system trust store.
+func updateTrustStore(command string) func() error {
+	return func() error {
+		result, err := exec.RunCommands(exec.RunParams{Commands: command})
+		if err != nil {
+			return errors.Annotatef(err, "running %q", command)
+		}
+		if result.Code != 0 {
+			return errors.Errorf("running %q: exit code %d: %s", command, result.Code, result.Stderr)
+		}
+		return nil
+	}
+}
@@ -126,6 +126,7 @@ func (s *ManifoldSuite) TestStartSuccess(c *gc.C) {
 	// return.
 	c.Check(dummy.config.ExternalUpdate(proxy.Settings{}), gc.ErrorMatches, "external")
 	c.Check(dummy.config.InProcessUpdate(proxy.Settings{}), gc.ErrorMatches, "in-process")
+	c.Check(dummy.config.UpdateTrustStore, gc.NotNil)
 }
 
 type dummyAgent struct {
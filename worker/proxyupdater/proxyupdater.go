@@ -6,17 +6,21 @@ package proxyupdater
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/utils/exec"
-	"github.com/juju/utils/os"
+	jujuos "github.com/juju/utils/os"
 	"github.com/juju/utils/packaging/commands"
 	"github.com/juju/utils/packaging/config"
 	proxyutils "github.com/juju/utils/proxy"
 	"github.com/juju/utils/series"
 	worker "gopkg.in/juju/worker.v1"
 
+	"github.com/juju/juju/juju/paths"
 	"github.com/juju/juju/watcher"
 )
 
@@ -31,12 +35,22 @@ type Config struct {
 	API             API
 	ExternalUpdate  func(proxyutils.Settings) error
 	InProcessUpdate func(proxyutils.Settings) error
+
+	// TrustStore describes the system trust store into which the
+	// model's custom CA certificates are installed. If its Dir is
+	// empty, the trust store is not managed.
+	TrustStore paths.TrustStore
+
+	// UpdateTrustStore is called to rebuild the system trust store
+	// after the custom CA certificates have been written.
+	UpdateTrustStore func() error
 }
 
 // API is an interface that is provided to New
 // which can be used to fetch the API host ports
 type API interface {
 	ProxyConfig() (proxyutils.Settings, proxyutils.Settings, error)
+	CACertificates() ([]string, error)
 	WatchForProxyConfigAndAPIHostPortChanges() (watcher.NotifyWatcher, error)
 }
 
@@ -46,8 +60,9 @@ type API interface {
 // changes are apt proxy configuration and the juju proxies stored in the juju
 // proxy file.
 type proxyWorker struct {
-	aptProxy proxyutils.Settings
-	proxy    proxyutils.Settings
+	aptProxy       proxyutils.Settings
+	proxy          proxyutils.Settings
+	caCertificates []string
 
 	// The whole point of the first value is to make sure that the the files
 	// are written out the first time through, even if they are the same as
@@ -127,8 +142,8 @@ func (w *proxyWorker) saveProxySettingsToRegistry() error {
 }
 
 func (w *proxyWorker) saveProxySettings() error {
-	switch os.HostOS() {
-	case os.Windows:
+	switch jujuos.HostOS() {
+	case jujuos.Windows:
 		return w.saveProxySettingsToRegistry()
 	default:
 		return w.saveProxySettingsToFiles()
@@ -186,6 +201,55 @@ func (w *proxyWorker) handleAptProxyValues(aptSettings proxyutils.Settings) erro
 	return nil
 }
 
+// caCertificateFile returns the path of the file in the trust store
+// directory holding the i'th custom CA certificate.
+func (w *proxyWorker) caCertificateFile(i int) string {
+	store := w.config.TrustStore
+	return filepath.Join(store.Dir, fmt.Sprintf("juju-ca-%d%s", i, store.FileExtension))
+}
+
+func (w *proxyWorker) handleCACertificates(certs []string) error {
+	if w.config.TrustStore.Dir == "" {
+		return nil
+	}
+	if reflect.DeepEqual(certs, w.caCertificates) && !w.first {
+		return nil
+	}
+	logger.Debugf("new custom CA certificates (%d)", len(certs))
+	w.caCertificates = certs
+
+	keep := make(map[string]bool)
+	for i, cert := range certs {
+		file := w.caCertificateFile(i)
+		keep[file] = true
+		if err := ioutil.WriteFile(file, []byte(cert), 0644); err != nil {
+			// It isn't really fatal, but we should record it.
+			logger.Errorf("error writing CA certificate file %s: %v", file, err)
+		}
+	}
+	// Remove the certificates that are no longer in the model config.
+	store := w.config.TrustStore
+	existing, err := filepath.Glob(filepath.Join(store.Dir, "juju-ca-*"+store.FileExtension))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, file := range existing {
+		if keep[file] {
+			continue
+		}
+		if err := os.Remove(file); err != nil {
+			logger.Errorf("error removing CA certificate file %s: %v", file, err)
+		}
+	}
+	if update := w.config.UpdateTrustStore; update != nil {
+		if err := update(); err != nil {
+			// It isn't really fatal, but we should record it.
+			logger.Errorf("error updating trust store: %v", err)
+		}
+	}
+	return nil
+}
+
 func (w *proxyWorker) onChange() error {
 	proxySettings, APTProxySettings, err := w.config.API.ProxyConfig()
 	if err != nil {
@@ -193,7 +257,18 @@ func (w *proxyWorker) onChange() error {
 	}
 
 	w.handleProxyValues(proxySettings)
-	return w.handleAptProxyValues(APTProxySettings)
+	if err := w.handleAptProxyValues(APTProxySettings); err != nil {
+		return err
+	}
+
+	if w.config.TrustStore.Dir == "" {
+		return nil
+	}
+	caCertificates, err := w.config.API.CACertificates()
+	if err != nil {
+		return err
+	}
+	return w.handleCACertificates(caCertificates)
 }
 
 // SetUp is defined on the worker.NotifyWatchHandler interface.
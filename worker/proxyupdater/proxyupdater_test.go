@@ -23,6 +23,7 @@ import (
 	gc "gopkg.in/check.v1"
 	worker "gopkg.in/juju/worker.v1"
 
+	"github.com/juju/juju/juju/paths"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/watcher"
 	"github.com/juju/juju/worker/proxyupdater"
@@ -57,6 +58,7 @@ func (w notAWatcher) Changes() watcher.NotifyChannel {
 type fakeAPI struct {
 	Proxy    proxyutils.Settings
 	APTProxy proxyutils.Settings
+	CACerts  []string
 	Err      error
 	Watcher  *notAWatcher
 }
@@ -71,6 +73,10 @@ func (api fakeAPI) ProxyConfig() (proxyutils.Settings, proxyutils.Settings, erro
 
 }
 
+func (api fakeAPI) CACertificates() ([]string, error) {
+	return api.CACerts, api.Err
+}
+
 func (api fakeAPI) WatchForProxyConfigAndAPIHostPortChanges() (watcher.NotifyWatcher, error) {
 	if api.Watcher == nil {
 		w := newNotAWatcher()
@@ -309,3 +315,40 @@ func (s *ProxyUpdaterSuite) TestErrorSettingInProcessLogs(c *gc.C) {
 	}
 	c.Assert(foundMessage, jc.IsTrue)
 }
+
+func (s *ProxyUpdaterSuite) TestCACertificates(c *gc.C) {
+	dir := c.MkDir()
+	stale := filepath.Join(dir, "juju-ca-5.crt")
+	err := ioutil.WriteFile(stale, []byte("stale"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	other := filepath.Join(dir, "other.crt")
+	err = ioutil.WriteFile(other, []byte("other"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.api.CACerts = []string{coretesting.CACert, coretesting.OtherCACert}
+	updated := make(chan struct{}, 1)
+	s.config.TrustStore = paths.TrustStore{
+		Dir:           dir,
+		FileExtension: ".crt",
+	}
+	s.config.UpdateTrustStore = func() error {
+		updated <- struct{}{}
+		return nil
+	}
+	updater, err := proxyupdater.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer worker.Stop(updater)
+
+	select {
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("trust store not updated")
+	case <-updated:
+	}
+
+	s.waitForFile(c, filepath.Join(dir, "juju-ca-0.crt"), coretesting.CACert)
+	s.waitForFile(c, filepath.Join(dir, "juju-ca-1.crt"), coretesting.OtherCACert)
+	_, err = os.Stat(stale)
+	c.Check(os.IsNotExist(err), jc.IsTrue)
+	_, err = os.Stat(other)
+	c.Check(err, jc.ErrorIsNil)
+}
@@ -24,6 +24,31 @@ type Facade interface {
 	ModelConfig() (*config.Config, error)
 }
 
+// EntityFacade represents an API that can also limit the number of
+// entries kept for each entity when pruning.
+type EntityFacade interface {
+	Facade
+	PruneEntities(maxAge time.Duration, maxCollectionMB, maxPerEntity int) error
+}
+
+// Limits holds the limits that a pruner enforces.
+type Limits struct {
+	// MaxAge is the maximum age of the entries kept.
+	MaxAge time.Duration
+
+	// MaxCollectionMB is the maximum size of the collection.
+	MaxCollectionMB uint
+
+	// MaxPerEntity is the maximum number of entries kept for each
+	// entity, or 0 for no limit. It is only enforced if the facade is
+	// an EntityFacade.
+	MaxPerEntity int
+
+	// PruneInterval is how often to prune, or 0 to prune at the
+	// interval given in the worker's Config.
+	PruneInterval time.Duration
+}
+
 // Worker prunes status history records at regular intervals.
 type PrunerWorker struct {
 	catacomb catacomb.Catacomb
@@ -52,6 +77,15 @@ func (w *PrunerWorker) Config() *Config {
 
 // body of generic pruner loop
 func (w *PrunerWorker) Work(getPrunerConfig func(*config.Config) (time.Duration, uint)) error {
+	return w.WorkWithLimits(func(cfg *config.Config) Limits {
+		maxAge, maxCollectionMB := getPrunerConfig(cfg)
+		return Limits{MaxAge: maxAge, MaxCollectionMB: maxCollectionMB}
+	})
+}
+
+// WorkWithLimits is the body of the generic pruner loop, enforcing the
+// limits read from model config by getLimits.
+func (w *PrunerWorker) WorkWithLimits(getLimits func(*config.Config) Limits) error {
 	modelConfigWatcher, err := w.config.Facade.WatchForModelConfigChanges()
 	if err != nil {
 		return errors.Trace(err)
@@ -62,8 +96,7 @@ func (w *PrunerWorker) Work(getPrunerConfig func(*config.Config) (time.Duration,
 	}
 
 	var (
-		maxAge             time.Duration
-		maxCollectionMB    uint
+		limits             Limits
 		modelConfigChanges = modelConfigWatcher.Changes()
 		// We will also get an initial event, but need to ensure that event is
 		// received before doing any pruning.
@@ -85,25 +118,48 @@ func (w *PrunerWorker) Work(getPrunerConfig func(*config.Config) (time.Duration,
 				return errors.Annotate(err, "cannot load model configuration")
 			}
 
-			newMaxAge, newMaxCollectionMB := getPrunerConfig(modelConfig)
+			newLimits := getLimits(modelConfig)
 
-			if newMaxAge != maxAge || newMaxCollectionMB != maxCollectionMB {
-				logger.Infof("status history config: max age: %v, max collection size %dM for %s (%s)",
-					newMaxAge, newMaxCollectionMB, modelConfig.Name(), modelConfig.UUID())
-				maxAge = newMaxAge
-				maxCollectionMB = newMaxCollectionMB
+			if newLimits != limits {
+				logger.Infof("status history config: max age: %v, max collection size %dM, max per entity %d, interval %v for %s (%s)",
+					newLimits.MaxAge, newLimits.MaxCollectionMB, newLimits.MaxPerEntity, w.interval(newLimits),
+					modelConfig.Name(), modelConfig.UUID())
 			}
 			if timer == nil {
-				timer = w.config.Clock.NewTimer(w.config.PruneInterval)
+				timer = w.config.Clock.NewTimer(w.interval(newLimits))
 				timerCh = timer.Chan()
+			} else if w.interval(newLimits) != w.interval(limits) {
+				// Restart the timer so that the new interval
+				// takes effect without waiting for the old one.
+				timer.Reset(w.interval(newLimits))
 			}
+			limits = newLimits
 
 		case <-timerCh:
-			err := w.config.Facade.Prune(maxAge, int(maxCollectionMB))
+			err := w.prune(limits)
 			if err != nil {
 				return errors.Trace(err)
 			}
-			timer.Reset(w.config.PruneInterval)
+			timer.Reset(w.interval(limits))
+		}
+	}
+}
+
+// interval returns how often to prune with the given limits.
+func (w *PrunerWorker) interval(limits Limits) time.Duration {
+	if limits.PruneInterval > 0 {
+		return limits.PruneInterval
+	}
+	return w.config.PruneInterval
+}
+
+// prune enforces the limits, including the per-entity limit if the
+// facade supports it.
+func (w *PrunerWorker) prune(limits Limits) error {
+	if limits.MaxPerEntity > 0 {
+		if facade, ok := w.config.Facade.(EntityFacade); ok {
+			return facade.PruneEntities(limits.MaxAge, int(limits.MaxCollectionMB), limits.MaxPerEntity)
 		}
 	}
+	return w.config.Facade.Prune(limits.MaxAge, int(limits.MaxCollectionMB))
 }
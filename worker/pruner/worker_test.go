@@ -26,18 +26,28 @@ type PrunerSuite struct {
 
 var _ = gc.Suite(&PrunerSuite{})
 
+// pruneInterval is the status history prune interval configured for
+// the model in these tests.
+const pruneInterval = time.Minute
+
 func (s *PrunerSuite) setupPruner(c *gc.C) (*fakeFacade, *testing.Clock) {
 	facade := newFakeFacade()
+	testClock := s.startPruner(c, facade, facade, nil)
+	return facade, testClock
+}
+
+func (s *PrunerSuite) startPruner(c *gc.C, prunerFacade pruner.Facade, facade *fakeFacade, extraAttrs coretesting.Attrs) *testing.Clock {
 	attrs := coretesting.FakeConfig()
 	attrs["max-status-history-age"] = "1s"
 	attrs["max-status-history-size"] = "3M"
-	cfg, err := config.New(config.UseDefaults, attrs)
+	attrs["status-history-prune-interval"] = pruneInterval.String()
+	cfg, err := config.New(config.UseDefaults, attrs.Merge(extraAttrs))
 	c.Assert(err, jc.ErrorIsNil)
 	facade.modelConfig = cfg
 
 	testClock := testing.NewClock(time.Time{})
 	conf := pruner.Config{
-		Facade:        facade,
+		Facade:        prunerFacade,
 		PruneInterval: coretesting.ShortWait,
 		Clock:         testClock,
 	}
@@ -57,12 +67,12 @@ func (s *PrunerSuite) setupPruner(c *gc.C) (*fakeFacade, *testing.Clock) {
 		c.Fatal("timed out waiting for model configr")
 	}
 
-	return facade, testClock
+	return testClock
 }
 
-func (s *PrunerSuite) assertWorkerCallsPrune(c *gc.C, facade *fakeFacade, testClock *testing.Clock, collectionSize int) {
-	// NewTimer/Reset will have been called with the PruneInterval.
-	testClock.WaitAdvance(coretesting.ShortWait-time.Nanosecond, coretesting.LongWait, 1)
+func (s *PrunerSuite) assertWorkerCallsPrune(c *gc.C, facade *fakeFacade, testClock *testing.Clock, interval time.Duration, collectionSize int) {
+	// NewTimer/Reset will have been called with the configured interval.
+	testClock.WaitAdvance(interval-time.Nanosecond, coretesting.LongWait, 1)
 	select {
 	case <-facade.pruned:
 		c.Fatal("unexpected call to Prune")
@@ -79,7 +89,42 @@ func (s *PrunerSuite) assertWorkerCallsPrune(c *gc.C, facade *fakeFacade, testCl
 
 func (s *PrunerSuite) TestWorkerCallsPrune(c *gc.C) {
 	facade, clock := s.setupPruner(c)
-	s.assertWorkerCallsPrune(c, facade, clock, 3)
+	s.assertWorkerCallsPrune(c, facade, clock, pruneInterval, 3)
+}
+
+func (s *PrunerSuite) TestWorkerCallsPruneEntities(c *gc.C) {
+	facade := newFakeFacade()
+	clock := s.startPruner(c, &fakeEntityFacade{facade}, facade, coretesting.Attrs{
+		"max-status-history-per-entity": 100,
+	})
+	clock.WaitAdvance(pruneInterval, coretesting.LongWait, 1)
+	select {
+	case args := <-facade.pruned:
+		c.Assert(args, jc.DeepEquals, pruneParams{
+			maxAge:       time.Second,
+			maxHistoryMB: 3,
+			maxPerEntity: 100,
+		})
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for call to PruneEntities")
+	}
+}
+
+func (s *PrunerSuite) TestWorkerIgnoresEntityLimitWithoutSupport(c *gc.C) {
+	facade := newFakeFacade()
+	clock := s.startPruner(c, facade, facade, coretesting.Attrs{
+		"max-status-history-per-entity": 100,
+	})
+	clock.WaitAdvance(pruneInterval, coretesting.LongWait, 1)
+	select {
+	case args := <-facade.pruned:
+		c.Assert(args, jc.DeepEquals, pruneParams{
+			maxAge:       time.Second,
+			maxHistoryMB: 3,
+		})
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for call to Prune")
+	}
 }
 
 func (s *PrunerSuite) TestWorkerWontCallPruneBeforeFiringTimer(c *gc.C) {
@@ -94,14 +139,38 @@ func (s *PrunerSuite) TestWorkerWontCallPruneBeforeFiringTimer(c *gc.C) {
 
 func (s *PrunerSuite) TestModelConfigChange(c *gc.C) {
 	facade, clock := s.setupPruner(c)
-	s.assertWorkerCallsPrune(c, facade, clock, 3)
+	s.assertWorkerCallsPrune(c, facade, clock, pruneInterval, 3)
 
 	var err error
 	facade.modelConfig, err = facade.modelConfig.Apply(map[string]interface{}{"max-status-history-size": "4M"})
 	c.Assert(err, jc.ErrorIsNil)
 	facade.changesWatcher.changes <- struct{}{}
 
-	s.assertWorkerCallsPrune(c, facade, clock, 4)
+	s.assertWorkerCallsPrune(c, facade, clock, pruneInterval, 4)
+}
+
+func (s *PrunerSuite) TestPruneIntervalChange(c *gc.C) {
+	facade, clock := s.setupPruner(c)
+	s.assertWorkerCallsPrune(c, facade, clock, pruneInterval, 3)
+
+	var err error
+	facade.modelConfig, err = facade.modelConfig.Apply(map[string]interface{}{"status-history-prune-interval": "2m"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.notifyConfigChanged(c, facade)
+	// Notify again so that we know the first change has been handled
+	// before advancing the clock.
+	s.notifyConfigChanged(c, facade)
+
+	s.assertWorkerCallsPrune(c, facade, clock, 2*time.Minute, 3)
+}
+
+func (s *PrunerSuite) notifyConfigChanged(c *gc.C, facade *fakeFacade) {
+	facade.changesWatcher.changes <- struct{}{}
+	select {
+	case <-facade.gotConfig:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for model config")
+	}
 }
 
 type fakeFacade struct {
@@ -114,6 +183,7 @@ type fakeFacade struct {
 type pruneParams struct {
 	maxAge       time.Duration
 	maxHistoryMB int
+	maxPerEntity int
 }
 
 func newFakeFacade() *fakeFacade {
@@ -127,13 +197,28 @@ func newFakeFacade() *fakeFacade {
 // Prune implements Facade
 func (f *fakeFacade) Prune(maxAge time.Duration, maxHistoryMB int) error {
 	select {
-	case f.pruned <- pruneParams{maxAge, maxHistoryMB}:
+	case f.pruned <- pruneParams{maxAge, maxHistoryMB, 0}:
 	case <-time.After(coretesting.LongWait):
 		return errors.New("timed out waiting for facade call Prune to run")
 	}
 	return nil
 }
 
+// fakeEntityFacade is a fakeFacade that also implements EntityFacade.
+type fakeEntityFacade struct {
+	*fakeFacade
+}
+
+// PruneEntities implements EntityFacade
+func (f *fakeEntityFacade) PruneEntities(maxAge time.Duration, maxHistoryMB, maxPerEntity int) error {
+	select {
+	case f.pruned <- pruneParams{maxAge, maxHistoryMB, maxPerEntity}:
+	case <-time.After(coretesting.LongWait):
+		return errors.New("timed out waiting for facade call PruneEntities to run")
+	}
+	return nil
+}
+
 // WatchForModelConfigChanges implements Facade
 func (f *fakeFacade) WatchForModelConfigChanges() (watcher.NotifyWatcher, error) {
 	return f.changesWatcher, nil
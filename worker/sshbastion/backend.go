@@ -0,0 +1,136 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sshbastion
+
+import (
+	"github.com/juju/errors"
+	utilssh "github.com/juju/utils/ssh"
+	"golang.org/x/crypto/ssh"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state"
+)
+
+// NewStateBackend returns a Backend that reads the controller's models
+// from the supplied pool.
+func NewStateBackend(pool *state.StatePool) Backend {
+	return &stateBackend{pool}
+}
+
+type stateBackend struct {
+	pool *state.StatePool
+}
+
+// ControllerConfig is part of the Backend interface.
+func (b *stateBackend) ControllerConfig() (controller.Config, error) {
+	return b.pool.SystemState().ControllerConfig()
+}
+
+// WatchControllerConfig is part of the Backend interface.
+func (b *stateBackend) WatchControllerConfig() state.NotifyWatcher {
+	return b.pool.SystemState().WatchControllerConfig()
+}
+
+// AuthorisedKeys is part of the Backend interface.
+func (b *stateBackend) AuthorisedKeys(modelUUID string) ([]string, error) {
+	var keys []string
+	err := b.withModel(modelUUID, func(st *state.State) error {
+		model, err := st.Model()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		cfg, err := model.ModelConfig()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		keys = utilssh.SplitAuthorisedKeys(cfg.AuthorizedKeys())
+		return nil
+	})
+	return keys, errors.Trace(err)
+}
+
+// UserCanSSH is part of the Backend interface.
+func (b *stateBackend) UserCanSSH(modelUUID, user string) (bool, error) {
+	ok, err := common.UserCanSSH(b.pool.SystemState(), user, names.NewModelTag(modelUUID))
+	return ok, errors.Trace(err)
+}
+
+// SSHAllow is part of the Backend interface.
+func (b *stateBackend) SSHAllow(modelUUID string) ([]string, error) {
+	var cidrs []string
+	err := b.withModel(modelUUID, func(st *state.State) error {
+		model, err := st.Model()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		cfg, err := model.ModelConfig()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		cidrs = cfg.SSHAllow()
+		return nil
+	})
+	return cidrs, errors.Trace(err)
+}
+
+// Target is part of the Backend interface.
+func (b *stateBackend) Target(modelUUID, name string) (Target, error) {
+	var target Target
+	err := b.withModel(modelUUID, func(st *state.State) error {
+		machineId := name
+		if names.IsValidUnit(name) {
+			unit, err := st.Unit(name)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if machineId, err = unit.AssignedMachineId(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		machine, err := st.Machine(machineId)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		address, err := machine.PrivateAddress()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		hostKeys, err := st.GetSSHHostKeys(machine.MachineTag())
+		if err != nil {
+			return errors.Annotatef(err, "getting host keys for machine %s", machineId)
+		}
+		target = Target{
+			MachineId: machineId,
+			Address:   address.Value,
+			HostKeys:  hostKeys,
+		}
+		return nil
+	})
+	return target, errors.Trace(err)
+}
+
+// ClientKey is part of the Backend interface.
+func (b *stateBackend) ClientKey(modelUUID string) (ssh.Signer, error) {
+	var signer ssh.Signer
+	err := b.withModel(modelUUID, func(st *state.State) error {
+		key, err := st.BastionKey()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		signer, err = ssh.ParsePrivateKey([]byte(key.PrivateKey))
+		return errors.Trace(err)
+	})
+	return signer, errors.Trace(err)
+}
+
+func (b *stateBackend) withModel(modelUUID string, f func(*state.State) error) error {
+	st, release, err := b.pool.Get(modelUUID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer release()
+	return f(st)
+}
@@ -0,0 +1,43 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sshbastion_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	"golang.org/x/crypto/ssh"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/worker/sshbastion"
+)
+
+type BackendSuite struct {
+	statetesting.StateSuite
+}
+
+var _ = gc.Suite(&BackendSuite{})
+
+func (s *BackendSuite) TestClientKeyHostedModel(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	backend := sshbastion.NewStateBackend(s.StatePool)
+
+	signer, err := backend.ClientKey(st.ModelUUID())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(authorizedKey(signer.PublicKey(), "juju-bastion"), gc.Equals, publicBastionKey(c, st))
+
+	controllerSigner, err := backend.ClientKey(s.State.ModelUUID())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(authorizedKey(controllerSigner.PublicKey(), "juju-bastion"), gc.Equals, publicBastionKey(c, s.State))
+	c.Check(controllerSigner.PublicKey().Marshal(), gc.Not(jc.DeepEquals), signer.PublicKey().Marshal())
+}
+
+func publicBastionKey(c *gc.C, st *state.State) string {
+	key, err := st.BastionKey()
+	c.Assert(err, jc.ErrorIsNil)
+	publicKey, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(key.PublicKey))
+	c.Assert(err, jc.ErrorIsNil)
+	return authorizedKey(publicKey, comment)
+}
@@ -0,0 +1,155 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sshbastion
+
+import (
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"strconv"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"golang.org/x/crypto/ssh"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/worker/dependency"
+	workerstate "github.com/juju/juju/worker/state"
+)
+
+// ManifoldConfig holds the information necessary to run an ssh bastion
+// worker in a dependency.Engine.
+type ManifoldConfig struct {
+	AgentName string
+	ClockName string
+	StateName string
+
+	// RootDir is the root of the filesystem in which the machine's
+	// ssh host keys are found.
+	RootDir string
+
+	NewWorker func(Config) (worker.Worker, error)
+}
+
+// Validate returns an error if the config cannot be used to start an
+// ssh bastion worker.
+func (config ManifoldConfig) Validate() error {
+	if config.AgentName == "" {
+		return errors.NotValidf("empty AgentName")
+	}
+	if config.ClockName == "" {
+		return errors.NotValidf("empty ClockName")
+	}
+	if config.StateName == "" {
+		return errors.NotValidf("empty StateName")
+	}
+	if config.NewWorker == nil {
+		return errors.NotValidf("nil NewWorker")
+	}
+	return nil
+}
+
+// Manifold returns a dependency.Manifold that will run an ssh bastion
+// worker.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: []string{
+			config.AgentName,
+			config.ClockName,
+			config.StateName,
+		},
+		Start: config.start,
+	}
+}
+
+// NewWorker returns a new ssh bastion worker, expressed as a
+// worker.Worker.
+func NewWorker(config Config) (worker.Worker, error) {
+	w, err := New(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// start is a method on ManifoldConfig because it's more readable than a closure.
+func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var a agent.Agent
+	if err := context.Get(config.AgentName, &a); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var clock clock.Clock
+	if err := context.Get(config.ClockName, &clock); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var stTracker workerstate.StateTracker
+	if err := context.Get(config.StateName, &stTracker); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	agentConfig := a.CurrentConfig()
+	hostKeys, err := readHostKeys(filepath.Join(config.RootDir, "etc", "ssh"))
+	if err != nil {
+		return nil, errors.Annotate(err, "reading ssh host keys")
+	}
+
+	if _, err := stTracker.Use(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w, err := config.NewWorker(Config{
+		Backend:    NewStateBackend(stTracker.Pool()),
+		HostKeys:   hostKeys,
+		TargetPort: 22,
+		RecordDir:  filepath.Join(agentConfig.LogDir(), "ssh-bastion"),
+		Listen: func(port int) (net.Listener, error) {
+			return net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(port)))
+		},
+		Clock: clock,
+	})
+	if err != nil {
+		stTracker.Done()
+		return nil, errors.Trace(err)
+	}
+	go func() {
+		w.Wait()
+		stTracker.Done()
+	}()
+	return w, nil
+}
+
+func readPrivateKey(path string) (ssh.Signer, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	signer, err := ssh.ParsePrivateKey(data)
+	return signer, errors.Trace(err)
+}
+
+// readHostKeys returns the machine's ssh host keys, so that the bastion
+// presents the same identity as the controller machine's own ssh server.
+func readHostKeys(sshDir string) ([]ssh.Signer, error) {
+	filenames, err := filepath.Glob(filepath.Join(sshDir, "ssh_host_*_key"))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var signers []ssh.Signer
+	for _, filename := range filenames {
+		signer, err := readPrivateKey(filename)
+		if err != nil {
+			logger.Debugf("unable to read ssh host key %s (skipping): %v", filename, err)
+			continue
+		}
+		signers = append(signers, signer)
+	}
+	if len(signers) == 0 {
+		return nil, errors.NotFoundf("ssh host keys in %s", sshDir)
+	}
+	return signers, nil
+}
@@ -0,0 +1,373 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sshbastion
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/juju/juju/core/sshkeys"
+)
+
+// targetUser is the user as which the bastion connects to machines.
+const targetUser = "ubuntu"
+
+// server accepts ssh connections on a listener and proxies them to
+// the requested machines.
+type server struct {
+	config         Config
+	sshConfig      *ssh.ServerConfig
+	listener       net.Listener
+	recordSessions bool
+
+	mu    sync.Mutex
+	conns map[net.Conn]bool
+
+	stopping chan struct{}
+	wg       sync.WaitGroup
+}
+
+// stop closes the listener and all open connections, and waits for
+// the connections' goroutines to finish.
+func (srv *server) stop() {
+	close(srv.stopping)
+	srv.listener.Close()
+	srv.mu.Lock()
+	for conn := range srv.conns {
+		conn.Close()
+	}
+	srv.mu.Unlock()
+	srv.wg.Wait()
+}
+
+func (srv *server) acceptLoop() error {
+	for {
+		conn, err := srv.listener.Accept()
+		if err != nil {
+			select {
+			case <-srv.stopping:
+				return nil
+			default:
+				return errors.Trace(err)
+			}
+		}
+		srv.mu.Lock()
+		select {
+		case <-srv.stopping:
+			srv.mu.Unlock()
+			conn.Close()
+			return nil
+		default:
+		}
+		srv.conns[conn] = true
+		srv.wg.Add(1)
+		srv.mu.Unlock()
+		go func() {
+			defer srv.wg.Done()
+			srv.serve(conn)
+			srv.mu.Lock()
+			delete(srv.conns, conn)
+			srv.mu.Unlock()
+		}()
+	}
+}
+
+// authenticate is the bastion's ssh.PublicKeyCallback. It accepts a
+// key if it belongs to a user who may ssh to the machines of the
+// requested model, and the connection comes from an address permitted
+// by the model's ssh-allow config.
+func (srv *server) authenticate(meta ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+	modelUUID, target, err := parseUser(meta.User())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := srv.checkAllowed(modelUUID, meta.RemoteAddr()); err != nil {
+		logger.Warningf("rejecting ssh connection to %s: %v", meta.User(), err)
+		return nil, errors.Trace(err)
+	}
+	keys, err := srv.config.Backend.AuthorisedKeys(modelUUID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	keys, _ = sshkeys.Unexpired(keys, srv.config.Clock.Now())
+	wanted := key.Marshal()
+	for _, authorisedKey := range keys {
+		owner := sshkeys.Owner(authorisedKey)
+		if owner == "" {
+			// Keys that belong to no user cannot be audited, and
+			// are not accepted by the bastion.
+			continue
+		}
+		parsed, _, _, _, err := ssh.ParseAuthorizedKey([]byte(authorisedKey))
+		if err != nil || !bytes.Equal(parsed.Marshal(), wanted) {
+			continue
+		}
+		ok, err := srv.config.Backend.UserCanSSH(modelUUID, owner)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !ok {
+			continue
+		}
+		return &ssh.Permissions{
+			Extensions: map[string]string{
+				userExtension:   owner,
+				modelExtension:  modelUUID,
+				targetExtension: target,
+			},
+		}, nil
+	}
+	logger.Warningf("rejecting ssh connection to %s from %s: key not authorised", meta.User(), meta.RemoteAddr())
+	return nil, errors.Errorf("key not authorised for %s", meta.User())
+}
+
+// checkAllowed returns an error if ssh connections to the model's
+// machines are not accepted from the supplied address.
+func (srv *server) checkAllowed(modelUUID string, addr net.Addr) error {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return errors.Errorf("cannot parse address %q", host)
+	}
+	cidrs, err := srv.config.Backend.SSHAllow(modelUUID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			logger.Warningf("ignoring invalid ssh-allow CIDR %q", cidr)
+			continue
+		}
+		if ipNet.Contains(ip) {
+			return nil
+		}
+	}
+	return errors.Errorf("address %s not permitted by ssh-allow", ip)
+}
+
+// session holds the details of an authenticated connection.
+type session struct {
+	user       string
+	modelUUID  string
+	target     string
+	remoteAddr string
+}
+
+func (s session) String() string {
+	return fmt.Sprintf("user %q from %s to %s in model %s", s.user, s.remoteAddr, s.target, s.modelUUID)
+}
+
+func (srv *server) serve(conn net.Conn) {
+	defer conn.Close()
+	sshConn, channels, requests, err := ssh.NewServerConn(conn, srv.sshConfig)
+	if err != nil {
+		logger.Debugf("ssh handshake with %s failed: %v", conn.RemoteAddr(), err)
+		return
+	}
+	defer sshConn.Close()
+	go ssh.DiscardRequests(requests)
+
+	s := session{
+		user:       sshConn.Permissions.Extensions[userExtension],
+		modelUUID:  sshConn.Permissions.Extensions[modelExtension],
+		target:     sshConn.Permissions.Extensions[targetExtension],
+		remoteAddr: conn.RemoteAddr().String(),
+	}
+	client, err := srv.dialTarget(s)
+	if err != nil {
+		logger.Errorf("cannot connect %s: %v", s, err)
+		if newChannel, ok := <-channels; ok {
+			newChannel.Reject(ssh.ConnectionFailed, fmt.Sprintf("cannot connect to %s", s.target))
+		}
+		return
+	}
+	defer client.Close()
+	logger.Infof("ssh connection opened: %s", s)
+	defer logger.Infof("ssh connection closed: %s", s)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			logger.Warningf("rejecting %q channel: %s", newChannel.ChannelType(), s)
+			newChannel.Reject(ssh.Prohibited, "only session channels are supported")
+			continue
+		}
+		wg.Add(1)
+		go func(newChannel ssh.NewChannel) {
+			defer wg.Done()
+			srv.proxySession(s, client, newChannel)
+		}(newChannel)
+	}
+}
+
+// dialTarget connects to the session's target machine, verifying the
+// machine's host key.
+func (srv *server) dialTarget(s session) (*ssh.Client, error) {
+	target, err := srv.config.Backend.Target(s.modelUUID, s.target)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var hostKeys [][]byte
+	for _, hostKey := range target.HostKeys {
+		parsed, _, _, _, err := ssh.ParseAuthorizedKey([]byte(hostKey))
+		if err != nil {
+			logger.Debugf("ignoring invalid host key for machine %s: %v", target.MachineId, err)
+			continue
+		}
+		hostKeys = append(hostKeys, parsed.Marshal())
+	}
+	if len(hostKeys) == 0 {
+		return nil, errors.Errorf("no host keys known for machine %s", target.MachineId)
+	}
+	clientKey, err := srv.config.Backend.ClientKey(s.modelUUID)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting bastion key")
+	}
+	config := &ssh.ClientConfig{
+		User: targetUser,
+		Auth: []ssh.AuthMethod{ssh.PublicKeys(clientKey)},
+		HostKeyCallback: func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			for _, hostKey := range hostKeys {
+				if bytes.Equal(hostKey, key.Marshal()) {
+					return nil
+				}
+			}
+			return errors.Errorf("host key mismatch for machine %s", target.MachineId)
+		},
+	}
+	addr := net.JoinHostPort(target.Address, strconv.Itoa(srv.config.TargetPort))
+	client, err := ssh.Dial("tcp", addr, config)
+	return client, errors.Annotatef(err, "connecting to machine %s at %s", target.MachineId, addr)
+}
+
+// proxySession relays a session channel, and the requests made on it,
+// between the user and the target machine.
+func (srv *server) proxySession(s session, client *ssh.Client, newChannel ssh.NewChannel) {
+	targetChannel, targetRequests, err := client.OpenChannel("session", nil)
+	if err != nil {
+		newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	defer targetChannel.Close()
+	channel, requests, err := newChannel.Accept()
+	if err != nil {
+		logger.Debugf("accepting session channel: %v", err)
+		return
+	}
+	defer channel.Close()
+
+	stdout, stderr := io.Writer(channel), channel.Stderr()
+	if srv.recordSessions {
+		recording, err := srv.openRecording(s)
+		if err != nil {
+			logger.Errorf("cannot record session for %s: %v", s, err)
+			return
+		}
+		defer recording.Close()
+		stdout = io.MultiWriter(stdout, recording)
+		stderr = io.MultiWriter(stderr, recording)
+	}
+
+	go func() {
+		for req := range requests {
+			auditRequest(s, req)
+			ok, err := targetChannel.SendRequest(req.Type, req.WantReply, req.Payload)
+			if err != nil {
+				logger.Debugf("forwarding %q request: %v", req.Type, err)
+			}
+			if req.WantReply {
+				req.Reply(ok, nil)
+			}
+		}
+		// The user has closed the channel.
+		targetChannel.Close()
+	}()
+	go func() {
+		io.Copy(targetChannel, channel)
+		targetChannel.CloseWrite()
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		io.Copy(stdout, targetChannel)
+	}()
+	go func() {
+		defer wg.Done()
+		io.Copy(stderr, targetChannel.Stderr())
+	}()
+	go func() {
+		defer wg.Done()
+		// Requests from the machine, such as exit-status, must be
+		// relayed before the user's channel is closed.
+		for req := range targetRequests {
+			ok, _ := channel.SendRequest(req.Type, req.WantReply, req.Payload)
+			if req.WantReply {
+				req.Reply(ok, nil)
+			}
+		}
+	}()
+	wg.Wait()
+}
+
+// auditRequest logs requests that start programs on the target machine.
+func auditRequest(s session, req *ssh.Request) {
+	switch req.Type {
+	case "shell":
+		logger.Infof("shell started: %s", s)
+	case "exec":
+		logger.Infof("command %q started: %s", execCommand(req.Payload), s)
+	}
+}
+
+// execCommand returns the command in the payload of an "exec" request.
+func execCommand(payload []byte) string {
+	if len(payload) < 4 {
+		return ""
+	}
+	length := binary.BigEndian.Uint32(payload)
+	if uint32(len(payload)-4) < length {
+		return ""
+	}
+	return string(payload[4 : 4+length])
+}
+
+// openRecording creates a file in which to record the output of the
+// session.
+func (srv *server) openRecording(s session) (*os.File, error) {
+	if err := os.MkdirAll(srv.config.RecordDir, 0700); err != nil {
+		return nil, errors.Trace(err)
+	}
+	now := srv.config.Clock.Now().UTC()
+	name := fmt.Sprintf("%s-%s-%s-%s.log",
+		now.Format("20060102T150405.000000000Z"),
+		s.user, s.modelUUID, strings.Replace(s.target, "/", "-", -1),
+	)
+	f, err := os.OpenFile(filepath.Join(srv.config.RecordDir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err := fmt.Fprintf(f, "# %s at %s\n", s, now.Format("2006-01-02 15:04:05Z")); err != nil {
+		f.Close()
+		return nil, errors.Trace(err)
+	}
+	return f, nil
+}
@@ -0,0 +1,264 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package sshbastion provides a worker that runs an ssh bastion on a
+// controller machine. The bastion terminates ssh connections from
+// users, checks that the user may access the requested machine, and
+// re-originates the connection to that machine using a key generated
+// for the machine's model, which the machine agents install while the
+// bastion is enabled; workload machines therefore need only be
+// reachable from the controller.
+package sshbastion
+
+import (
+	"net"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils"
+	"github.com/juju/utils/clock"
+	"golang.org/x/crypto/ssh"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+var logger = loggo.GetLogger("juju.worker.sshbastion")
+
+// Target describes a machine to which the bastion connects on behalf
+// of a user.
+type Target struct {
+	// MachineId is the id of the machine.
+	MachineId string
+
+	// Address is the address, without port, at which the bastion
+	// connects to the machine.
+	Address string
+
+	// HostKeys holds the machine's ssh host public keys, in
+	// authorized_keys format.
+	HostKeys []string
+}
+
+// Backend provides the information about the controller and its
+// models needed by the bastion.
+type Backend interface {
+	// ControllerConfig returns the controller's configuration.
+	ControllerConfig() (controller.Config, error)
+
+	// WatchControllerConfig returns a watcher that notifies when the
+	// controller's configuration changes.
+	WatchControllerConfig() state.NotifyWatcher
+
+	// AuthorisedKeys returns the authorised ssh keys of the model
+	// with the specified UUID.
+	AuthorisedKeys(modelUUID string) ([]string, error)
+
+	// UserCanSSH reports whether the named user may ssh to the
+	// machines of the model with the specified UUID.
+	UserCanSSH(modelUUID, user string) (bool, error)
+
+	// SSHAllow returns the CIDRs from which ssh connections to the
+	// machines of the model with the specified UUID are accepted.
+	SSHAllow(modelUUID string) ([]string, error)
+
+	// Target returns the machine to connect to for the named machine
+	// or unit in the model with the specified UUID.
+	Target(modelUUID, name string) (Target, error)
+
+	// ClientKey returns the key with which the bastion authenticates
+	// to the machines of the model with the specified UUID.
+	ClientKey(modelUUID string) (ssh.Signer, error)
+}
+
+// Config holds the configuration and dependencies for a bastion worker.
+type Config struct {
+	// Backend provides information about the controller's models.
+	Backend Backend
+
+	// HostKeys holds the keys with which the bastion identifies
+	// itself to users.
+	HostKeys []ssh.Signer
+
+	// TargetPort is the port on which machines accept ssh connections.
+	TargetPort int
+
+	// RecordDir is the directory in which session recordings are
+	// written when recording is enabled in the controller config.
+	RecordDir string
+
+	// Listen is called to listen on the configured bastion port.
+	Listen func(port int) (net.Listener, error)
+
+	// Clock is used to check key expiry and timestamp recordings.
+	Clock clock.Clock
+}
+
+// Validate returns an error if the config cannot be used to start a
+// bastion worker.
+func (config Config) Validate() error {
+	if config.Backend == nil {
+		return errors.NotValidf("nil Backend")
+	}
+	if len(config.HostKeys) == 0 {
+		return errors.NotValidf("empty HostKeys")
+	}
+	if config.TargetPort <= 0 {
+		return errors.NotValidf("non-positive TargetPort")
+	}
+	if config.RecordDir == "" {
+		return errors.NotValidf("empty RecordDir")
+	}
+	if config.Listen == nil {
+		return errors.NotValidf("nil Listen")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	return nil
+}
+
+// settings holds the controller config values that affect the bastion.
+type settings struct {
+	port           int
+	recordSessions bool
+}
+
+// Worker runs the ssh bastion while it is enabled in the controller
+// config, restarting it whenever its settings change.
+type Worker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+}
+
+// New returns a Worker that runs an ssh bastion according to the
+// controller config.
+func New(config Config) (*Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &Worker{config: config}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *Worker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *Worker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *Worker) loop() error {
+	watcher := w.config.Backend.WatchControllerConfig()
+	if err := w.catacomb.Add(watcher); err != nil {
+		return errors.Trace(err)
+	}
+
+	var current settings
+	var srv *server
+	defer func() {
+		if srv != nil {
+			srv.stop()
+		}
+	}()
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case _, ok := <-watcher.Changes():
+			if !ok {
+				return errors.New("controller config watcher closed")
+			}
+			cfg, err := w.config.Backend.ControllerConfig()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			next := settings{
+				port:           cfg.BastionPort(),
+				recordSessions: cfg.BastionRecordSessions(),
+			}
+			if srv != nil && next == current {
+				continue
+			}
+			if srv != nil {
+				logger.Infof("stopping ssh bastion on port %d", current.port)
+				srv.stop()
+				srv = nil
+			}
+			current = next
+			if current.port == 0 {
+				continue
+			}
+			listener, err := w.config.Listen(current.port)
+			if err != nil {
+				return errors.Annotatef(err, "listening on port %d", current.port)
+			}
+			logger.Infof("starting ssh bastion on port %d", current.port)
+			srv = w.newServer(listener, current.recordSessions)
+		}
+	}
+}
+
+// newServer returns a server that accepts connections on the supplied
+// listener until it is stopped. Should the listener fail, the worker
+// is killed.
+func (w *Worker) newServer(listener net.Listener, recordSessions bool) *server {
+	srv := &server{
+		config:         w.config,
+		listener:       listener,
+		recordSessions: recordSessions,
+		conns:          make(map[net.Conn]bool),
+		stopping:       make(chan struct{}),
+	}
+	srv.sshConfig = &ssh.ServerConfig{
+		PublicKeyCallback: srv.authenticate,
+	}
+	for _, key := range w.config.HostKeys {
+		srv.sshConfig.AddHostKey(key)
+	}
+	srv.wg.Add(1)
+	go func() {
+		defer srv.wg.Done()
+		if err := srv.acceptLoop(); err != nil {
+			w.catacomb.Kill(errors.Annotate(err, "accepting ssh connections"))
+		}
+	}()
+	return srv
+}
+
+// The names of the extensions recorded in the ssh.Permissions of an
+// authenticated connection.
+const (
+	userExtension   = "juju-user"
+	modelExtension  = "juju-model"
+	targetExtension = "juju-target"
+)
+
+// parseUser splits the user name presented to the bastion, which has
+// the form "<model-uuid>+<machine-or-unit>", into its parts. Slashes in
+// the machine or unit may be written as dots, as scp would otherwise
+// treat the target as a local path.
+func parseUser(user string) (modelUUID, target string, err error) {
+	parts := strings.SplitN(user, "+", 2)
+	if len(parts) != 2 || !utils.IsValidUUIDString(parts[0]) {
+		return "", "", errors.NotValidf("user %q: expected <model-uuid>+<machine-or-unit>", user)
+	}
+	target = strings.Replace(parts[1], ".", "/", -1)
+	if !names.IsValidMachine(target) && !names.IsValidUnit(target) {
+		return "", "", errors.NotValidf("target %q", parts[1])
+	}
+	return parts[0], target, nil
+}
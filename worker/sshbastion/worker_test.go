@@ -0,0 +1,435 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sshbastion_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/binary"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
+	"golang.org/x/crypto/ssh"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/tomb.v1"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/sshkeys"
+	sshtesting "github.com/juju/juju/network/ssh/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/sshbastion"
+)
+
+const modelUUID = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+
+type WorkerSuite struct {
+	coretesting.BaseSuite
+	backend    *fakeBackend
+	listener   net.Listener
+	listening  chan int
+	userKey    ssh.Signer
+	bastionKey ssh.Signer
+	clientKey  ssh.Signer
+	recordDir  string
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.userKey = generateKey(c)
+	s.bastionKey = generateKey(c)
+	var err error
+	s.clientKey, err = ssh.ParsePrivateKey([]byte(sshtesting.SSHKey1))
+	c.Assert(err, jc.ErrorIsNil)
+
+	targetAddr := s.startTarget(c)
+	s.backend = &fakeBackend{
+		controllerConfig: controller.Config{
+			controller.BastionPort: 17022,
+		},
+		changes: make(chan struct{}, 1),
+		keys: []string{
+			sshkeys.WithOwner(authorizedKey(s.userKey.PublicKey(), "bob@laptop"), "bob"),
+		},
+		canSSH:    map[string]bool{"bob": true},
+		sshAllow:  []string{"127.0.0.0/8"},
+		clientKey: s.clientKey,
+		target: sshbastion.Target{
+			MachineId: "0",
+			Address:   "127.0.0.1",
+			HostKeys:  []string{sshtesting.SSHPub2},
+		},
+	}
+	s.backend.targetPort = targetAddr.Port
+	s.listening = make(chan int, 1)
+	s.recordDir = c.MkDir()
+}
+
+func (s *WorkerSuite) config() sshbastion.Config {
+	return sshbastion.Config{
+		Backend:    s.backend,
+		HostKeys:   []ssh.Signer{s.bastionKey},
+		TargetPort: s.backend.targetPort,
+		RecordDir:  s.recordDir,
+		Listen: func(port int) (net.Listener, error) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			s.listener = listener
+			s.listening <- port
+			return listener, nil
+		},
+		Clock: clock.WallClock,
+	}
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) worker.Worker {
+	w, err := sshbastion.New(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) {
+		c.Check(worker.Stop(w), jc.ErrorIsNil)
+	})
+	s.backend.changes <- struct{}{}
+	return w
+}
+
+func (s *WorkerSuite) waitListening(c *gc.C) int {
+	select {
+	case port := <-s.listening:
+		return port
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for bastion to listen")
+	}
+	panic("unreachable")
+}
+
+// startTarget starts an ssh server standing in for a machine. It
+// accepts only the controller's key, and answers each exec request by
+// echoing the command.
+func (s *WorkerSuite) startTarget(c *gc.C) *net.TCPAddr {
+	hostKey, err := ssh.ParsePrivateKey([]byte(sshtesting.SSHKey2))
+	c.Assert(err, jc.ErrorIsNil)
+	clientKey := s.clientKey.PublicKey().Marshal()
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(meta ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if meta.User() != "ubuntu" || !bytes.Equal(key.Marshal(), clientKey) {
+				return nil, errors.New("denied")
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(hostKey)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTarget(conn, config)
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr)
+}
+
+func serveTarget(conn net.Conn, config *ssh.ServerConfig) {
+	defer conn.Close()
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	for newChannel := range channels {
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer channel.Close()
+			for req := range requests {
+				if req.Type != "exec" {
+					req.Reply(false, nil)
+					continue
+				}
+				req.Reply(true, nil)
+				command := string(req.Payload[4:])
+				channel.Write([]byte("ran: " + command + "\n"))
+				status := make([]byte, 4)
+				binary.BigEndian.PutUint32(status, 0)
+				channel.SendRequest("exit-status", false, status)
+				return
+			}
+		}()
+	}
+}
+
+func (s *WorkerSuite) dial(user string) (*ssh.Client, error) {
+	bastionKey := s.bastionKey.PublicKey().Marshal()
+	return ssh.Dial("tcp", s.listener.Addr().String(), &ssh.ClientConfig{
+		User: user,
+		Auth: []ssh.AuthMethod{ssh.PublicKeys(s.userKey)},
+		HostKeyCallback: func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			if !bytes.Equal(key.Marshal(), bastionKey) {
+				return errors.New("unexpected bastion host key")
+			}
+			return nil
+		},
+	})
+}
+
+func (s *WorkerSuite) run(c *gc.C, command string) string {
+	client, err := s.dial(modelUUID + "+0")
+	c.Assert(err, jc.ErrorIsNil)
+	defer client.Close()
+	session, err := client.NewSession()
+	c.Assert(err, jc.ErrorIsNil)
+	defer session.Close()
+	output, err := session.Output(command)
+	c.Assert(err, jc.ErrorIsNil)
+	return string(output)
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	config := s.config()
+	config.Backend = nil
+	_, err := sshbastion.New(config)
+	c.Check(err, gc.ErrorMatches, "nil Backend not valid")
+
+	config = s.config()
+	config.HostKeys = nil
+	_, err = sshbastion.New(config)
+	c.Check(err, gc.ErrorMatches, "empty HostKeys not valid")
+
+	config = s.config()
+	config.Listen = nil
+	_, err = sshbastion.New(config)
+	c.Check(err, gc.ErrorMatches, "nil Listen not valid")
+}
+
+func (s *WorkerSuite) TestProxiesSession(c *gc.C) {
+	s.startWorker(c)
+	c.Assert(s.waitListening(c), gc.Equals, 17022)
+
+	c.Assert(s.run(c, "hostname"), gc.Equals, "ran: hostname\n")
+}
+
+func (s *WorkerSuite) TestDisabled(c *gc.C) {
+	s.backend.controllerConfig = controller.Config{}
+	s.startWorker(c)
+
+	select {
+	case <-s.listening:
+		c.Fatal("bastion started while disabled")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *WorkerSuite) TestRestartsWhenPortChanges(c *gc.C) {
+	s.startWorker(c)
+	s.waitListening(c)
+
+	s.backend.setControllerConfig(controller.Config{
+		controller.BastionPort: 17023,
+	})
+	s.backend.changes <- struct{}{}
+	c.Assert(s.waitListening(c), gc.Equals, 17023)
+	c.Assert(s.run(c, "hostname"), gc.Equals, "ran: hostname\n")
+}
+
+func (s *WorkerSuite) TestRecordsSession(c *gc.C) {
+	s.backend.controllerConfig[controller.BastionRecordSessions] = true
+	s.startWorker(c)
+	s.waitListening(c)
+
+	s.run(c, "uptime")
+	files, err := filepath.Glob(filepath.Join(s.recordDir, "*-bob-"+modelUUID+"-0.log"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(files, gc.HasLen, 1)
+	data, err := ioutil.ReadFile(files[0])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), jc.Contains, `# user "bob" from 127.0.0.1:`)
+	c.Assert(strings.HasSuffix(string(data), "ran: uptime\n"), jc.IsTrue)
+}
+
+func (s *WorkerSuite) TestRejectsUnownedKey(c *gc.C) {
+	s.backend.keys = []string{authorizedKey(s.userKey.PublicKey(), "bob@laptop")}
+	s.startWorker(c)
+	s.waitListening(c)
+
+	_, err := s.dial(modelUUID + "+0")
+	c.Assert(err, gc.ErrorMatches, "ssh: handshake failed: ssh: unable to authenticate.*")
+}
+
+func (s *WorkerSuite) TestRejectsUserWithoutAccess(c *gc.C) {
+	s.backend.canSSH["bob"] = false
+	s.startWorker(c)
+	s.waitListening(c)
+
+	_, err := s.dial(modelUUID + "+0")
+	c.Assert(err, gc.ErrorMatches, "ssh: handshake failed: ssh: unable to authenticate.*")
+}
+
+func (s *WorkerSuite) TestRejectsExpiredKey(c *gc.C) {
+	key := s.backend.keys[0]
+	s.backend.keys = []string{sshkeys.WithExpiry(key, time.Now().Add(-time.Minute))}
+	s.startWorker(c)
+	s.waitListening(c)
+
+	_, err := s.dial(modelUUID + "+0")
+	c.Assert(err, gc.ErrorMatches, "ssh: handshake failed: ssh: unable to authenticate.*")
+}
+
+func (s *WorkerSuite) TestRejectsAddressNotAllowed(c *gc.C) {
+	s.backend.sshAllow = []string{"10.0.0.0/8"}
+	s.startWorker(c)
+	s.waitListening(c)
+
+	_, err := s.dial(modelUUID + "+0")
+	c.Assert(err, gc.ErrorMatches, "ssh: handshake failed: ssh: unable to authenticate.*")
+}
+
+func (s *WorkerSuite) TestRejectsInvalidUser(c *gc.C) {
+	s.startWorker(c)
+	s.waitListening(c)
+
+	_, err := s.dial("ubuntu")
+	c.Assert(err, gc.ErrorMatches, "ssh: handshake failed: ssh: unable to authenticate.*")
+}
+
+func (s *WorkerSuite) TestRejectsUnknownTargetHostKey(c *gc.C) {
+	s.backend.target.HostKeys = []string{sshtesting.SSHPub1}
+	s.startWorker(c)
+	s.waitListening(c)
+
+	client, err := s.dial(modelUUID + "+0")
+	c.Assert(err, jc.ErrorIsNil)
+	defer client.Close()
+	_, err = client.NewSession()
+	c.Assert(err, gc.ErrorMatches, "ssh: rejected: connect failed .*")
+}
+
+func generateKey(c *gc.C) ssh.Signer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	signer, err := ssh.NewSignerFromKey(key)
+	c.Assert(err, jc.ErrorIsNil)
+	return signer
+}
+
+func authorizedKey(key ssh.PublicKey, comment string) string {
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key))) + " " + comment
+}
+
+type fakeBackend struct {
+	mu               sync.Mutex
+	controllerConfig controller.Config
+	changes          chan struct{}
+	keys             []string
+	canSSH           map[string]bool
+	sshAllow         []string
+	target           sshbastion.Target
+	targetPort       int
+	clientKey        ssh.Signer
+}
+
+func (b *fakeBackend) setControllerConfig(cfg controller.Config) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.controllerConfig = cfg
+}
+
+// ControllerConfig is part of the sshbastion.Backend interface.
+func (b *fakeBackend) ControllerConfig() (controller.Config, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.controllerConfig, nil
+}
+
+// WatchControllerConfig is part of the sshbastion.Backend interface.
+func (b *fakeBackend) WatchControllerConfig() state.NotifyWatcher {
+	return newMockNotifyWatcher(b.changes)
+}
+
+// AuthorisedKeys is part of the sshbastion.Backend interface.
+func (b *fakeBackend) AuthorisedKeys(uuid string) ([]string, error) {
+	if uuid != modelUUID {
+		return nil, errors.NotFoundf("model %q", uuid)
+	}
+	return b.keys, nil
+}
+
+// UserCanSSH is part of the sshbastion.Backend interface.
+func (b *fakeBackend) UserCanSSH(uuid, user string) (bool, error) {
+	return b.canSSH[user], nil
+}
+
+// SSHAllow is part of the sshbastion.Backend interface.
+func (b *fakeBackend) SSHAllow(uuid string) ([]string, error) {
+	return b.sshAllow, nil
+}
+
+// Target is part of the sshbastion.Backend interface.
+func (b *fakeBackend) Target(uuid, name string) (sshbastion.Target, error) {
+	if name != b.target.MachineId {
+		return sshbastion.Target{}, errors.NotFoundf("machine %q", name)
+	}
+	return b.target, nil
+}
+
+// ClientKey is part of the sshbastion.Backend interface.
+func (b *fakeBackend) ClientKey(uuid string) (ssh.Signer, error) {
+	if uuid != modelUUID {
+		return nil, errors.NotFoundf("model %q", uuid)
+	}
+	return b.clientKey, nil
+}
+
+type mockNotifyWatcher struct {
+	tomb tomb.Tomb
+	ch   chan struct{}
+}
+
+func newMockNotifyWatcher(ch chan struct{}) *mockNotifyWatcher {
+	w := &mockNotifyWatcher{ch: ch}
+	go func() {
+		defer w.tomb.Done()
+		<-w.tomb.Dying()
+	}()
+	return w
+}
+
+func (w *mockNotifyWatcher) Changes() <-chan struct{} {
+	return w.ch
+}
+
+func (w *mockNotifyWatcher) Kill() {
+	w.tomb.Kill(nil)
+}
+
+func (w *mockNotifyWatcher) Wait() error {
+	return w.tomb.Wait()
+}
+
+func (w *mockNotifyWatcher) Stop() error {
+	w.Kill()
+	return w.Wait()
+}
+
+func (w *mockNotifyWatcher) Err() error {
+	return w.tomb.Err()
+}
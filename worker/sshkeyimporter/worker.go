@@ -0,0 +1,153 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sshkeyimporter
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/sshkeyimporter"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+var logger = loggo.GetLogger("juju.worker.sshkeyimporter")
+
+// Facade represents the API used by the worker to refresh a model's
+// authorized keys.
+type Facade interface {
+	RefreshKeys() (time.Duration, error)
+	WatchForModelConfigChanges() (watcher.NotifyWatcher, error)
+	ModelConfig() (*config.Config, error)
+}
+
+// NewFacade returns a Facade backed by the SSHKeyImporter API.
+func NewFacade(caller base.APICaller) Facade {
+	return sshkeyimporter.NewClient(caller)
+}
+
+// Config holds the resources and configuration needed to run the worker.
+type Config struct {
+	Facade Facade
+	Clock  clock.Clock
+}
+
+// Validate returns an error if the config cannot be used to start
+// a worker.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	return nil
+}
+
+// New returns a worker that refreshes the model's authorized keys from
+// their key server sources whenever the sources change, and thereafter
+// whenever the controller asks for them to be refreshed.
+func New(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &importerWorker{config: config}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type importerWorker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *importerWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *importerWorker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *importerWorker) loop() error {
+	modelConfigWatcher, err := w.config.Facade.WatchForModelConfigChanges()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := w.catacomb.Add(modelConfigWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
+	// The sources are recorded as nil until the initial model config
+	// event has been handled, so that the first event always causes
+	// the keys to be refreshed.
+	var sources []string
+	var timer clock.Timer
+	var timerCh <-chan time.Time
+	refresh := func() error {
+		refreshAfter, err := w.config.Facade.RefreshKeys()
+		if err != nil {
+			return errors.Annotate(err, "cannot refresh authorized keys")
+		}
+		logger.Debugf("authorized keys refreshed; next refresh in %v", refreshAfter)
+		if timer == nil {
+			timer = w.config.Clock.NewTimer(refreshAfter)
+			timerCh = timer.Chan()
+		} else {
+			timer.Reset(refreshAfter)
+		}
+		return nil
+	}
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+
+		case _, ok := <-modelConfigWatcher.Changes():
+			if !ok {
+				return errors.New("model configuration watcher closed")
+			}
+			modelConfig, err := w.config.Facade.ModelConfig()
+			if err != nil {
+				return errors.Annotate(err, "cannot load model configuration")
+			}
+			newSources := modelConfig.SSHKeySources()
+			if sources != nil && sameSources(sources, newSources) {
+				continue
+			}
+			if newSources == nil {
+				newSources = []string{}
+			}
+			logger.Infof("ssh key sources changed to %q", newSources)
+			sources = newSources
+			if err := refresh(); err != nil {
+				return errors.Trace(err)
+			}
+
+		case <-timerCh:
+			if err := refresh(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+func sameSources(a, b []string) bool {
+	return strings.Join(a, " ") == strings.Join(b, " ")
+}
@@ -0,0 +1,157 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sshkeyimporter_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/tomb.v1"
+
+	"github.com/juju/juju/environs/config"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/sshkeyimporter"
+)
+
+type WorkerSuite struct {
+	coretesting.BaseSuite
+	facade *fakeFacade
+	clock  *testing.Clock
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.facade = &fakeFacade{
+		changes:      make(chan struct{}, 1),
+		refreshed:    make(chan struct{}, 1),
+		refreshAfter: time.Hour,
+	}
+	s.setSources(c, "lp:fred")
+	s.clock = testing.NewClock(time.Time{})
+}
+
+func (s *WorkerSuite) setSources(c *gc.C, sources string) {
+	attrs := coretesting.FakeConfig().Merge(coretesting.Attrs{
+		"ssh-key-sources": sources,
+	})
+	cfg, err := config.New(config.UseDefaults, attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	s.facade.modelConfig = cfg
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) worker.Worker {
+	w, err := sshkeyimporter.New(sshkeyimporter.Config{
+		Facade: s.facade,
+		Clock:  s.clock,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) {
+		c.Check(worker.Stop(w), jc.ErrorIsNil)
+	})
+	s.facade.changes <- struct{}{}
+	s.assertRefreshed(c)
+	return w
+}
+
+func (s *WorkerSuite) assertRefreshed(c *gc.C) {
+	select {
+	case <-s.facade.refreshed:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for keys to be refreshed")
+	}
+}
+
+func (s *WorkerSuite) assertNotRefreshed(c *gc.C) {
+	select {
+	case <-s.facade.refreshed:
+		c.Fatal("unexpected refresh")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	_, err := sshkeyimporter.New(sshkeyimporter.Config{Clock: s.clock})
+	c.Assert(err, gc.ErrorMatches, "nil Facade not valid")
+	_, err = sshkeyimporter.New(sshkeyimporter.Config{Facade: s.facade})
+	c.Assert(err, gc.ErrorMatches, "nil Clock not valid")
+}
+
+func (s *WorkerSuite) TestRefreshesAfterRequestedDelay(c *gc.C) {
+	s.startWorker(c)
+
+	s.clock.WaitAdvance(time.Hour-time.Nanosecond, coretesting.LongWait, 1)
+	s.assertNotRefreshed(c)
+	s.clock.Advance(time.Nanosecond)
+	s.assertRefreshed(c)
+}
+
+func (s *WorkerSuite) TestRefreshesWhenSourcesChange(c *gc.C) {
+	s.startWorker(c)
+
+	s.setSources(c, "lp:fred gh:mary")
+	s.facade.changes <- struct{}{}
+	s.assertRefreshed(c)
+}
+
+func (s *WorkerSuite) TestIgnoresUnrelatedConfigChanges(c *gc.C) {
+	s.startWorker(c)
+
+	s.facade.changes <- struct{}{}
+	s.assertNotRefreshed(c)
+}
+
+type fakeFacade struct {
+	changes      chan struct{}
+	refreshed    chan struct{}
+	refreshAfter time.Duration
+	modelConfig  *config.Config
+}
+
+// RefreshKeys is part of the sshkeyimporter.Facade interface.
+func (f *fakeFacade) RefreshKeys() (time.Duration, error) {
+	f.refreshed <- struct{}{}
+	return f.refreshAfter, nil
+}
+
+// WatchForModelConfigChanges is part of the sshkeyimporter.Facade interface.
+func (f *fakeFacade) WatchForModelConfigChanges() (watcher.NotifyWatcher, error) {
+	return newMockNotifyWatcher(f.changes), nil
+}
+
+// ModelConfig is part of the sshkeyimporter.Facade interface.
+func (f *fakeFacade) ModelConfig() (*config.Config, error) {
+	return f.modelConfig, nil
+}
+
+type mockNotifyWatcher struct {
+	tomb tomb.Tomb
+	ch   chan struct{}
+}
+
+func newMockNotifyWatcher(ch chan struct{}) *mockNotifyWatcher {
+	w := &mockNotifyWatcher{ch: ch}
+	go func() {
+		defer w.tomb.Done()
+		<-w.tomb.Dying()
+	}()
+	return w
+}
+
+func (w *mockNotifyWatcher) Changes() watcher.NotifyChannel {
+	return w.ch
+}
+
+func (w *mockNotifyWatcher) Kill() {
+	w.tomb.Kill(nil)
+}
+
+func (w *mockNotifyWatcher) Wait() error {
+	return w.tomb.Wait()
+}
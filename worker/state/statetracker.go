@@ -20,6 +20,12 @@ type StateTracker interface {
 	// it. ErrStateClosed is returned if the State is closed.
 	Use() (*state.State, error)
 
+	// Pool returns a StatePool whose system state is the wrapped
+	// State, shared by all users of the tracker. It must only be
+	// used between calls to Use and Done, and is closed along with
+	// the State.
+	Pool() *state.StatePool
+
 	// Done records that there's one less user of the wrapped State,
 	// closing it if there's no more users. ErrStateClosed is returned
 	// if the State has already been closed (indicating that Done has
@@ -27,21 +33,23 @@ type StateTracker interface {
 	Done() error
 }
 
-// stateTracker wraps a *state.State, keeping a reference count and
-// closing the State when there are no longer any references to it. It
-// implements StateTracker.
+// stateTracker wraps a *state.State and a *state.StatePool based on
+// it, keeping a reference count and closing both when there are no
+// longer any references to them. It implements StateTracker.
 //
 // The reference count starts at 1. Done should be called exactly 1 +
 // number of calls to Use.
 type stateTracker struct {
 	mu         sync.Mutex
 	st         *state.State
+	pool       *state.StatePool
 	references int
 }
 
 func newStateTracker(st *state.State) StateTracker {
 	return &stateTracker{
 		st:         st,
+		pool:       state.NewStatePool(st),
 		references: 1,
 	}
 }
@@ -58,6 +66,11 @@ func (c *stateTracker) Use() (*state.State, error) {
 	return c.st, nil
 }
 
+// Pool implements StateTracker.
+func (c *stateTracker) Pool() *state.StatePool {
+	return c.pool
+}
+
 // Done implements StateTracker.
 func (c *stateTracker) Done() error {
 	c.mu.Lock()
@@ -68,6 +81,9 @@ func (c *stateTracker) Done() error {
 	}
 	c.references--
 	if c.references == 0 {
+		if err := c.pool.Close(); err != nil {
+			logger.Errorf("error when closing state pool: %v", err)
+		}
 		err := c.st.Close()
 		if err != nil {
 			logger.Errorf("error when closing state: %v", err)
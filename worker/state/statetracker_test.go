@@ -91,6 +91,21 @@ func (s *StateTrackerSuite) TestUseWhenClosed(c *gc.C) {
 	c.Check(err, gc.Equals, workerstate.ErrStateClosed)
 }
 
+func (s *StateTrackerSuite) TestPool(c *gc.C) {
+	pool := s.stateTracker.Pool()
+	c.Check(pool.SystemState(), gc.Equals, s.State)
+	c.Check(s.stateTracker.Pool(), gc.Equals, pool)
+
+	otherState := s.Factory.MakeModel(c, nil)
+	defer otherState.Close()
+	st, release, err := pool.Get(otherState.ModelUUID())
+	c.Assert(err, jc.ErrorIsNil)
+	release()
+
+	c.Assert(s.stateTracker.Done(), jc.ErrorIsNil)
+	assertStateClosed(c, st)
+}
+
 func assertStateNotClosed(c *gc.C, st *state.State) {
 	err := st.Ping()
 	c.Assert(err, jc.ErrorIsNil)
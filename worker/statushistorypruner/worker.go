@@ -4,8 +4,6 @@
 package statushistorypruner
 
 import (
-	"time"
-
 	"github.com/juju/errors"
 	"gopkg.in/juju/worker.v1"
 
@@ -27,8 +25,13 @@ func NewFacade(caller base.APICaller) pruner.Facade {
 }
 
 func (w *Worker) loop() error {
-	return w.Work(func(config *config.Config) (time.Duration, uint) {
-		return config.MaxStatusHistoryAge(), config.MaxStatusHistorySizeMB()
+	return w.WorkWithLimits(func(config *config.Config) pruner.Limits {
+		return pruner.Limits{
+			MaxAge:          config.MaxStatusHistoryAge(),
+			MaxCollectionMB: config.MaxStatusHistorySizeMB(),
+			MaxPerEntity:    config.MaxStatusHistoryPerEntity(),
+			PruneInterval:   config.StatusHistoryPruneInterval(),
+		}
 	})
 }
 
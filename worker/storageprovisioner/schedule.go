@@ -3,7 +3,11 @@
 
 package storageprovisioner
 
-import "time"
+import (
+	"time"
+
+	"github.com/juju/juju/environs/providerretry"
+)
 
 // minRetryDelay is the minimum delay to apply
 // to operation retries; this does not apply to
@@ -15,6 +19,14 @@ const minRetryDelay = 30 * time.Second
 // up to this ceiling.
 const maxRetryDelay = 30 * time.Minute
 
+// retryStrategy describes the backoff applied to
+// rescheduled operations, which are retried until
+// they succeed.
+var retryStrategy = providerretry.Strategy{
+	Delay:    minRetryDelay,
+	MaxDelay: maxRetryDelay,
+}
+
 // scheduleOperations schedules the given operations
 // by calculating the current time once, and then
 // adding each operation's delay to that time. By
@@ -54,13 +66,14 @@ type exponentialBackoff struct {
 
 func (s *exponentialBackoff) delay() time.Duration {
 	current := s.d
-	if s.d < minRetryDelay {
-		s.d = minRetryDelay
-	} else {
-		s.d *= 2
-		if s.d > maxRetryDelay {
-			s.d = maxRetryDelay
-		}
-	}
+	s.d = retryStrategy.NextDelay(s.d)
 	return current
 }
+
+// delayMax sets the delay before the next execution of the
+// operation to the ceiling. Operations that failed because the
+// cloud credential was rejected are retried this rarely, as they
+// will keep failing until the credential is updated.
+func (s *exponentialBackoff) delayMax() {
+	s.d = maxRetryDelay
+}
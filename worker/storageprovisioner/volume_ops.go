@@ -8,6 +8,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/providerretry"
 	"github.com/juju/juju/status"
 	"github.com/juju/juju/storage"
 )
@@ -61,14 +62,20 @@ func createVolumes(ctx *context, ops map[names.VolumeTag]*createVolumeOp) error
 			})
 			entityStatus := &statuses[len(statuses)-1]
 			if result.Error != nil {
-				// Reschedule the volume creation.
-				reschedule = append(reschedule, ops[volumeParams[i].Tag])
-
-				// Note: we keep the status as "pending" to indicate
-				// that we will retry. When we distinguish between
-				// transient and permanent errors, we will set the
-				// status to "error" for permanent errors.
-				entityStatus.Status = status.Pending.String()
+				// Reschedule the volume creation. Errors caused by
+				// the cloud credential will not pass until it is
+				// updated, so the creation is retried only as often
+				// as the backoff ceiling and the status is set to
+				// "error". Otherwise we keep the status as "pending"
+				// to indicate that we will retry.
+				op := ops[volumeParams[i].Tag]
+				reschedule = append(reschedule, op)
+				if providerretry.ShouldRetry(result.Error) {
+					entityStatus.Status = status.Pending.String()
+				} else {
+					op.delayMax()
+					entityStatus.Status = status.Error.String()
+				}
 				entityStatus.Info = result.Error.Error()
 				logger.Debugf(
 					"failed to create %s: %v",
@@ -162,13 +169,19 @@ func attachVolumes(ctx *context, ops map[params.MachineStorageId]*attachVolumeOp
 					MachineTag:    p.Machine.String(),
 					AttachmentTag: p.Volume.String(),
 				}
-				reschedule = append(reschedule, ops[id])
 
-				// Note: we keep the status as "attaching" to
-				// indicate that we will retry. When we distinguish
-				// between transient and permanent errors, we will
-				// set the status to "error" for permanent errors.
-				entityStatus.Status = status.Attaching.String()
+				// As with creation, errors caused by the cloud
+				// credential are retried only as often as the
+				// backoff ceiling; otherwise we keep the status
+				// as "attaching" to indicate that we will retry.
+				op := ops[id]
+				reschedule = append(reschedule, op)
+				if providerretry.ShouldRetry(result.Error) {
+					entityStatus.Status = status.Attaching.String()
+				} else {
+					op.delayMax()
+					entityStatus.Status = status.Error.String()
+				}
 				entityStatus.Info = result.Error.Error()
 				logger.Debugf(
 					"failed to attach %s to %s: %v",
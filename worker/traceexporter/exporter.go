@@ -0,0 +1,348 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package traceexporter provides a worker that exports the spans
+// recorded on a controller to an OpenTelemetry collector, using the
+// JSON encoding of OTLP over HTTP, for sites that already run tracing
+// infrastructure.
+package traceexporter
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	mathrand "math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+
+	"github.com/juju/juju/core/tracing"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+var logger = loggo.GetLogger("juju.worker.traceexporter")
+
+const (
+	// tracesPath is the path, relative to the endpoint, to which
+	// traces are posted.
+	tracesPath = "/v1/traces"
+
+	// statusCodeError is the OTLP status code of a failed span.
+	statusCodeError = 2
+
+	// spanKindInternal is the OTLP kind of the spans exported.
+	spanKindInternal = 1
+)
+
+// Config holds the configuration and dependencies for an Exporter.
+type Config struct {
+	// Endpoint is the base URL of the collector.
+	Endpoint string
+
+	// Headers holds the HTTP headers sent with each export.
+	Headers map[string]string
+
+	// SamplingRatio is the fraction of spans that are exported.
+	SamplingRatio float64
+
+	// InstanceID identifies the exporting agent to the collector, eg
+	// "machine-0".
+	InstanceID string
+
+	// FlushInterval is how often the queued spans are exported.
+	FlushInterval time.Duration
+
+	// MaxQueueSize is the number of spans that may be queued between
+	// exports. Further spans are dropped.
+	MaxQueueSize int
+
+	// HTTPClient is used to post the spans to the collector.
+	HTTPClient *http.Client
+
+	// Clock is used to schedule exports.
+	Clock clock.Clock
+}
+
+// Validate returns an error if the config cannot be used to start an
+// Exporter.
+func (config Config) Validate() error {
+	if config.Endpoint == "" {
+		return errors.NotValidf("empty Endpoint")
+	}
+	if config.SamplingRatio < 0 || config.SamplingRatio > 1 {
+		return errors.NotValidf("SamplingRatio %v", config.SamplingRatio)
+	}
+	if config.InstanceID == "" {
+		return errors.NotValidf("empty InstanceID")
+	}
+	if config.FlushInterval <= 0 {
+		return errors.NotValidf("non-positive FlushInterval")
+	}
+	if config.MaxQueueSize <= 0 {
+		return errors.NotValidf("non-positive MaxQueueSize")
+	}
+	if config.HTTPClient == nil {
+		return errors.NotValidf("nil HTTPClient")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	return nil
+}
+
+// Exporter is a worker that exports the spans it records to an
+// OpenTelemetry collector. Recording never blocks on the collector:
+// spans are queued and exported periodically, and spans that cannot
+// be exported are dropped.
+type Exporter struct {
+	catacomb catacomb.Catacomb
+	config   Config
+
+	mu      sync.Mutex
+	queue   []tracing.Span
+	dropped int
+}
+
+// New returns an Exporter that exports spans according to the config.
+func New(config Config) (*Exporter, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	e := &Exporter{config: config}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &e.catacomb,
+		Work: e.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return e, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (e *Exporter) Kill() {
+	e.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (e *Exporter) Wait() error {
+	return e.catacomb.Wait()
+}
+
+// RecordSpans is part of the tracing.Recorder interface. It queues a
+// sample of the spans for export.
+func (e *Exporter) RecordSpans(spans []tracing.Span) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, s := range spans {
+		if !e.sampled() {
+			continue
+		}
+		if len(e.queue) >= e.config.MaxQueueSize {
+			e.dropped++
+			continue
+		}
+		e.queue = append(e.queue, s)
+	}
+	return nil
+}
+
+// sampled reports whether a span should be exported.
+func (e *Exporter) sampled() bool {
+	switch ratio := e.config.SamplingRatio; {
+	case ratio >= 1:
+		return true
+	case ratio <= 0:
+		return false
+	default:
+		return mathrand.Float64() < ratio
+	}
+}
+
+func (e *Exporter) loop() error {
+	for {
+		select {
+		case <-e.catacomb.Dying():
+			e.flush()
+			return e.catacomb.ErrDying()
+		case <-e.config.Clock.After(e.config.FlushInterval):
+			e.flush()
+		}
+	}
+}
+
+// flush exports the queued spans. Failures are logged rather than
+// stopping the worker, as the collector being unavailable should not
+// affect the controller.
+func (e *Exporter) flush() {
+	e.mu.Lock()
+	spans, dropped := e.queue, e.dropped
+	e.queue, e.dropped = nil, 0
+	e.mu.Unlock()
+
+	if dropped > 0 {
+		logger.Warningf("dropped %d spans: export queue full", dropped)
+	}
+	if len(spans) == 0 {
+		return
+	}
+	if err := e.export(spans); err != nil {
+		logger.Warningf("cannot export %d spans to %s: %v", len(spans), e.config.Endpoint, err)
+	}
+}
+
+func (e *Exporter) export(spans []tracing.Span) error {
+	body, err := json.Marshal(e.request(spans))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	url := strings.TrimSuffix(e.config.Endpoint, "/") + tracesPath
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.config.Headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := e.config.HTTPClient.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		message, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("collector returned %s: %s", resp.Status, bytes.TrimSpace(message))
+	}
+	return nil
+}
+
+// The following types give the JSON encoding of an OTLP export
+// request, in so far as the exporter uses it.
+
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope  `json:"scope"`
+	Spans []span `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type span struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	Name              string     `json:"name"`
+	Kind              int        `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes"`
+	Status            *status    `json:"status,omitempty"`
+}
+
+type status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+func (e *Exporter) request(spans []tracing.Span) exportRequest {
+	out := make([]span, len(spans))
+	for i, s := range spans {
+		name := string(s.Kind)
+		if s.Name != "" {
+			name = fmt.Sprintf("%s %s", s.Kind, s.Name)
+		}
+		out[i] = span{
+			// Spans are not yet correlated, so each is a trace
+			// of its own.
+			TraceID:           randomID(16),
+			SpanID:            randomID(8),
+			Name:              name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: unixNano(s.Start),
+			EndTimeUnixNano:   unixNano(s.Start.Add(s.Duration)),
+			Attributes: []keyValue{
+				stringAttribute("juju.kind", string(s.Kind)),
+				stringAttribute("juju.entity", s.Entity),
+			},
+		}
+		if s.CorrelationID != "" {
+			out[i].Attributes = append(out[i].Attributes,
+				stringAttribute("juju.correlation-id", s.CorrelationID),
+			)
+		}
+		if s.Error != "" {
+			out[i].Status = &status{
+				Code:    statusCodeError,
+				Message: s.Error,
+			}
+		}
+	}
+	return exportRequest{
+		ResourceSpans: []resourceSpans{{
+			Resource: resource{
+				Attributes: []keyValue{
+					stringAttribute("service.name", "juju"),
+					stringAttribute("service.instance.id", e.config.InstanceID),
+				},
+			},
+			ScopeSpans: []scopeSpans{{
+				Scope: scope{Name: "github.com/juju/juju"},
+				Spans: out,
+			}},
+		}},
+	}
+}
+
+func stringAttribute(key, value string) keyValue {
+	return keyValue{Key: key, Value: anyValue{StringValue: value}}
+}
+
+// unixNano returns the time as a decimal count of nanoseconds since
+// the Unix epoch, as OTLP encodes 64-bit integers in JSON as strings.
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// randomID returns a random hex-encoded identifier of n bytes.
+func randomID(n int) string {
+	id := make([]byte, n)
+	if _, err := rand.Read(id); err != nil {
+		// The identifiers need only be unique enough to tell
+		// spans apart.
+		mathrand.Read(id)
+	}
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,186 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package traceexporter_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/tracing"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/traceexporter"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type ExporterSuite struct {
+	testing.IsolationSuite
+	clock    *testing.Clock
+	server   *httptest.Server
+	requests chan *http.Request
+	bodies   chan map[string]interface{}
+	config   traceexporter.Config
+}
+
+var _ = gc.Suite(&ExporterSuite{})
+
+func (s *ExporterSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testing.NewClock(time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC))
+	s.requests = make(chan *http.Request, 10)
+	s.bodies = make(chan map[string]interface{}, 10)
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		data, err := ioutil.ReadAll(req.Body)
+		c.Check(err, jc.ErrorIsNil)
+		var body map[string]interface{}
+		c.Check(json.Unmarshal(data, &body), jc.ErrorIsNil)
+		s.requests <- req
+		s.bodies <- body
+	}))
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+	s.config = traceexporter.Config{
+		Endpoint:      s.server.URL,
+		Headers:       map[string]string{"Authorization": "Bearer xyz"},
+		SamplingRatio: 1,
+		InstanceID:    "machine-0",
+		FlushInterval: 10 * time.Second,
+		MaxQueueSize:  2,
+		HTTPClient:    http.DefaultClient,
+		Clock:         s.clock,
+	}
+}
+
+func (s *ExporterSuite) TestValidate(c *gc.C) {
+	s.config.SamplingRatio = 2
+	_, err := traceexporter.New(s.config)
+	c.Assert(err, gc.ErrorMatches, "SamplingRatio 2 not valid")
+}
+
+func (s *ExporterSuite) TestExport(c *gc.C) {
+	e, err := traceexporter.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, e)
+
+	start := time.Date(2018, 3, 1, 9, 0, 0, 0, time.UTC)
+	err = e.RecordSpans([]tracing.Span{{
+		Kind:     tracing.KindFacadeCall,
+		Entity:   "user-admin",
+		Name:     "Client.FullStatus",
+		Start:    start,
+		Duration: time.Second,
+
+		CorrelationID: "deadbeef",
+	}, {
+		Kind:     tracing.KindTxn,
+		Entity:   "model-deadbeef",
+		Name:     "units",
+		Start:    start,
+		Duration: time.Millisecond,
+		Error:    "transaction aborted",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.clock.WaitAdvance(10*time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+
+	req, body := s.nextExport(c)
+	c.Assert(req.Method, gc.Equals, "POST")
+	c.Assert(req.URL.Path, gc.Equals, "/v1/traces")
+	c.Assert(req.Header.Get("Content-Type"), gc.Equals, "application/json")
+	c.Assert(req.Header.Get("Authorization"), gc.Equals, "Bearer xyz")
+
+	resourceSpans := body["resourceSpans"].([]interface{})[0].(map[string]interface{})
+	c.Assert(resourceSpans["resource"], jc.DeepEquals, map[string]interface{}{
+		"attributes": []interface{}{
+			attribute("service.name", "juju"),
+			attribute("service.instance.id", "machine-0"),
+		},
+	})
+	spans := resourceSpans["scopeSpans"].([]interface{})[0].(map[string]interface{})["spans"].([]interface{})
+	c.Assert(spans, gc.HasLen, 2)
+
+	span := spans[0].(map[string]interface{})
+	c.Assert(span["traceId"], gc.HasLen, 32)
+	c.Assert(span["spanId"], gc.HasLen, 16)
+	c.Assert(span["name"], gc.Equals, "facade-call Client.FullStatus")
+	c.Assert(span["startTimeUnixNano"], gc.Equals, "1519894800000000000")
+	c.Assert(span["endTimeUnixNano"], gc.Equals, "1519894801000000000")
+	c.Assert(span["attributes"], jc.DeepEquals, []interface{}{
+		attribute("juju.kind", "facade-call"),
+		attribute("juju.entity", "user-admin"),
+		attribute("juju.correlation-id", "deadbeef"),
+	})
+	c.Assert(span["status"], gc.IsNil)
+
+	span = spans[1].(map[string]interface{})
+	c.Assert(span["name"], gc.Equals, "txn units")
+	c.Assert(span["status"], jc.DeepEquals, map[string]interface{}{
+		"code":    float64(2),
+		"message": "transaction aborted",
+	})
+}
+
+func (s *ExporterSuite) TestExportOnStop(c *gc.C) {
+	e, err := traceexporter.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = e.RecordSpans([]tracing.Span{{Kind: tracing.KindHook, Entity: "unit-mysql-0", Name: "install"}})
+	c.Assert(err, jc.ErrorIsNil)
+	workertest.CleanKill(c, e)
+
+	_, body := s.nextExport(c)
+	c.Assert(body["resourceSpans"], gc.HasLen, 1)
+}
+
+func (s *ExporterSuite) TestQueueFull(c *gc.C) {
+	e, err := traceexporter.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+
+	span := tracing.Span{Kind: tracing.KindHook, Entity: "unit-mysql-0", Name: "install"}
+	err = e.RecordSpans([]tracing.Span{span, span, span})
+	c.Assert(err, jc.ErrorIsNil)
+	workertest.CleanKill(c, e)
+
+	_, body := s.nextExport(c)
+	resourceSpans := body["resourceSpans"].([]interface{})[0].(map[string]interface{})
+	spans := resourceSpans["scopeSpans"].([]interface{})[0].(map[string]interface{})["spans"]
+	c.Assert(spans, gc.HasLen, 2)
+}
+
+func (s *ExporterSuite) TestSamplingRatioZero(c *gc.C) {
+	s.config.SamplingRatio = 0
+	e, err := traceexporter.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = e.RecordSpans([]tracing.Span{{Kind: tracing.KindHook, Entity: "unit-mysql-0", Name: "install"}})
+	c.Assert(err, jc.ErrorIsNil)
+	workertest.CleanKill(c, e)
+
+	select {
+	case <-s.requests:
+		c.Fatalf("unexpected export")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *ExporterSuite) nextExport(c *gc.C) (*http.Request, map[string]interface{}) {
+	select {
+	case req := <-s.requests:
+		return req, <-s.bodies
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for export")
+	}
+	panic("unreachable")
+}
+
+func attribute(key, value string) map[string]interface{} {
+	return map[string]interface{}{
+		"key":   key,
+		"value": map[string]interface{}{"stringValue": value},
+	}
+}
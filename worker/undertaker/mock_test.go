@@ -11,7 +11,9 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/watcher"
 	"github.com/juju/juju/worker/undertaker"
 	"github.com/juju/juju/worker/workertest"
@@ -71,6 +73,32 @@ func (mock *mockEnviron) Destroy() error {
 	return mock.stub.NextErr()
 }
 
+// mockTaggedEnviron is a mockEnviron that can also force-destroy
+// tagged resources.
+type mockTaggedEnviron struct {
+	mockEnviron
+	resources []environs.TaggedResource
+	failures  map[string]error
+}
+
+func (mock *mockTaggedEnviron) Config() *config.Config {
+	cfg, err := config.New(config.NoDefaults, coretesting.FakeConfig())
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+func (mock *mockTaggedEnviron) TaggedResources(tags map[string]string) ([]environs.TaggedResource, error) {
+	mock.stub.MethodCall(mock, "TaggedResources", tags)
+	return mock.resources, nil
+}
+
+func (mock *mockTaggedEnviron) DestroyTaggedResource(resource environs.TaggedResource) error {
+	mock.stub.MethodCall(mock, "DestroyTaggedResource", resource.ID)
+	return mock.failures[resource.ID]
+}
+
 type mockWatcher struct {
 	worker.Worker
 	changes chan struct{}
@@ -84,6 +112,11 @@ type fixture struct {
 	info   params.UndertakerModelInfoResult
 	errors []error
 	dirty  bool
+
+	// taggedResources, if non-nil, causes the environ to support
+	// force-destroying the resources it holds.
+	taggedResources []environs.TaggedResource
+	taggedFailures  map[string]error
 }
 
 func (fix fixture) cleanup(c *gc.C, w worker.Worker) {
@@ -96,9 +129,16 @@ func (fix fixture) cleanup(c *gc.C, w worker.Worker) {
 
 func (fix fixture) run(c *gc.C, test func(worker.Worker)) *testing.Stub {
 	stub := &testing.Stub{}
-	environ := &mockEnviron{
+	var environ environs.Environ = &mockEnviron{
 		stub: stub,
 	}
+	if fix.taggedResources != nil {
+		environ = &mockTaggedEnviron{
+			mockEnviron: mockEnviron{stub: stub},
+			resources:   fix.taggedResources,
+			failures:    fix.taggedFailures,
+		}
+	}
 	facade := &mockFacade{
 		stub: stub,
 		info: fix.info,
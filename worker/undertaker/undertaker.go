@@ -5,16 +5,21 @@ package undertaker
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/juju/errors"
+	"github.com/juju/loggo"
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/status"
 	"github.com/juju/juju/watcher"
 	"github.com/juju/juju/worker/catacomb"
 )
 
+var logger = loggo.GetLogger("juju.worker.undertaker")
+
 // Facade covers the parts of the api/undertaker.UndertakerClient that we
 // need for the worker. It's more than a little raw, but we'll survive.
 type Facade interface {
@@ -132,7 +137,15 @@ func (u *Undertaker) run() error {
 		return errors.Trace(err)
 	}
 	if err := u.config.Environ.Destroy(); err != nil {
-		return errors.Trace(err)
+		// If normal teardown has stalled, fall back to removing every
+		// resource tagged as belonging to the model, if we can. Other
+		// failures would recur when removing the resources one by one.
+		if !environs.IsTeardownStalled(err) {
+			return errors.Trace(err)
+		}
+		if err := u.forceDestroyEnviron(err); err != nil {
+			return errors.Trace(err)
+		}
 	}
 
 	// Finally, remove the model.
@@ -142,6 +155,50 @@ func (u *Undertaker) run() error {
 	return nil
 }
 
+// forceDestroyEnviron removes every cloud resource tagged as belonging
+// to the model, after a normal teardown failed with destroyErr. Each
+// removed resource is logged; an error is returned if any resource
+// could not be removed or identified, or if the environ does not
+// support tagged resource removal.
+func (u *Undertaker) forceDestroyEnviron(destroyErr error) error {
+	destroyer, ok := u.config.Environ.(environs.TaggedResourceDestroyer)
+	if !ok {
+		return errors.Trace(destroyErr)
+	}
+	logger.Warningf("cannot destroy cloud environment (%v); removing tagged resources", destroyErr)
+	if err := u.setStatus(
+		status.Destroying, "force-removing tagged cloud resources",
+	); err != nil {
+		return errors.Trace(err)
+	}
+	modelUUID := u.config.Environ.Config().UUID()
+	report, err := environs.ForceDestroyTaggedResources(destroyer, map[string]string{
+		tags.JujuModel: modelUUID,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, resource := range report.Destroyed {
+		logger.Infof("model %s: removed %s", modelUUID, resource)
+	}
+	for _, resource := range report.Unidentified {
+		logger.Warningf("model %s: not removing unidentified %s", modelUUID, resource)
+	}
+	var failed []string
+	for resource, err := range report.Failed {
+		logger.Errorf("model %s: cannot remove %s: %v", modelUUID, resource, err)
+		failed = append(failed, resource)
+	}
+	if report.Complete() {
+		return nil
+	}
+	sort.Strings(failed)
+	return errors.Errorf(
+		"cannot remove all cloud resources: %d removed, %d failed %v, %d unidentified",
+		len(report.Destroyed), len(failed), failed, len(report.Unidentified),
+	)
+}
+
 func (u *Undertaker) setStatus(modelStatus status.Status, message string) error {
 	return u.config.Facade.SetStatus(modelStatus, message, nil)
 }
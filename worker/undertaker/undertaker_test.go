@@ -10,7 +10,9 @@ import (
 	worker "gopkg.in/juju/worker.v1"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/workertest"
 )
 
@@ -177,6 +179,73 @@ func (s *UndertakerSuite) TestDestroyErrorFatal(c *gc.C) {
 	stub.CheckCallNames(c, "ModelInfo", "SetStatus", "Destroy")
 }
 
+func (s *UndertakerSuite) TestDestroyErrorForceRemovesTaggedResources(c *gc.C) {
+	s.fix.errors = []error{nil, nil, errors.Timeoutf("terminating instances")}
+	s.fix.info.Result.Life = "dead"
+	s.fix.taggedResources = []environs.TaggedResource{
+		{Kind: environs.ResourceKindVolume, ID: "vol-1"},
+		{Kind: environs.ResourceKindInstance, ID: "i-1"},
+	}
+	stub := s.fix.run(c, func(w worker.Worker) {
+		workertest.CheckKilled(c, w)
+	})
+	stub.CheckCallNames(c,
+		"ModelInfo",
+		"SetStatus",
+		"Destroy",
+		"SetStatus",
+		"TaggedResources",
+		"DestroyTaggedResource",
+		"DestroyTaggedResource",
+		"RemoveModel",
+	)
+	stub.CheckCall(c, 3, "SetStatus", status.Destroying, "force-removing tagged cloud resources", map[string]interface{}(nil))
+	stub.CheckCall(c, 4, "TaggedResources", map[string]string{
+		"juju-model-uuid": coretesting.ModelTag.Id(),
+	})
+	stub.CheckCall(c, 5, "DestroyTaggedResource", "i-1")
+	stub.CheckCall(c, 6, "DestroyTaggedResource", "vol-1")
+}
+
+func (s *UndertakerSuite) TestDestroyErrorNotStalledNotForced(c *gc.C) {
+	s.fix.errors = []error{nil, nil, errors.New("pow")}
+	s.fix.info.Result.Life = "dead"
+	s.fix.dirty = true
+	s.fix.taggedResources = []environs.TaggedResource{
+		{Kind: environs.ResourceKindInstance, ID: "i-1"},
+	}
+	stub := s.fix.run(c, func(w worker.Worker) {
+		err := workertest.CheckKilled(c, w)
+		c.Check(err, gc.ErrorMatches, "pow")
+	})
+	stub.CheckCallNames(c, "ModelInfo", "SetStatus", "Destroy")
+}
+
+func (s *UndertakerSuite) TestDestroyErrorForceRemoveIncomplete(c *gc.C) {
+	s.fix.errors = []error{nil, nil, errors.Timeoutf("terminating instances")}
+	s.fix.info.Result.Life = "dead"
+	s.fix.dirty = true
+	s.fix.taggedResources = []environs.TaggedResource{
+		{Kind: environs.ResourceKindInstance, ID: "i-1"},
+		{Kind: environs.ResourceKindVolume, ID: "vol-1"},
+		{Kind: "mystery", ID: "m-1"},
+	}
+	s.fix.taggedFailures = map[string]error{"vol-1": errors.New("in use")}
+	stub := s.fix.run(c, func(w worker.Worker) {
+		err := workertest.CheckKilled(c, w)
+		c.Check(err, gc.ErrorMatches, `cannot remove all cloud resources: 1 removed, 1 failed \[volume "vol-1"\], 1 unidentified`)
+	})
+	stub.CheckCallNames(c,
+		"ModelInfo",
+		"SetStatus",
+		"Destroy",
+		"SetStatus",
+		"TaggedResources",
+		"DestroyTaggedResource",
+		"DestroyTaggedResource",
+	)
+}
+
 func (s *UndertakerSuite) TestRemoveModelErrorFatal(c *gc.C) {
 	s.fix.errors = []error{nil, nil, nil, errors.New("pow")}
 	s.fix.info.Result.Life = "dead"
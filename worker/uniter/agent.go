@@ -3,9 +3,15 @@
 
 package uniter
 
-import "github.com/juju/juju/status"
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/worker/uniter/statusbuffer"
+)
 
 // setAgentStatus sets the unit's status if it has changed since last time this method was called.
+// The status is buffered, and sent to the controller once it can be reached.
 func setAgentStatus(u *Uniter, agentStatus status.Status, info string, data map[string]interface{}) error {
 	u.setStatusMutex.Lock()
 	defer u.setStatusMutex.Unlock()
@@ -15,7 +21,60 @@ func setAgentStatus(u *Uniter, agentStatus status.Status, info string, data map[
 	u.lastReportedStatus = agentStatus
 	u.lastReportedMessage = info
 	logger.Debugf("[AGENT-STATUS] %s: %s", agentStatus, info)
-	return u.unit.SetAgentStatus(agentStatus, info, data)
+	return u.agentStatusBuffer.Add(status.StatusInfo{
+		Status:  agentStatus,
+		Message: info,
+		Data:    data,
+	})
+}
+
+// startStatusBuffer starts a buffer that keeps statuses in the spool
+// file until send delivers them, and ties its lifetime to the uniter's.
+func (u *Uniter) startStatusBuffer(send statusbuffer.SendFunc, spoolFile string) (*statusbuffer.Buffer, error) {
+	buffer, err := statusbuffer.New(statusbuffer.Config{
+		Send:       send,
+		SpoolFile:  spoolFile,
+		MaxEntries: maxSpooledStatuses,
+		RetryDelay: statusRetryDelay,
+		Clock:      u.clock,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := u.catacomb.Add(buffer); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return buffer, nil
+}
+
+// sendAgentStatuses sends buffered agent statuses to the controller.
+// Statuses the controller rejects are logged rather than sent again.
+func (u *Uniter) sendAgentStatuses(statuses []status.StatusInfo) error {
+	errs, err := u.unit.SetAgentStatuses(statuses)
+	if err != nil {
+		return err
+	}
+	logRejectedStatuses("agent", statuses, errs)
+	return nil
+}
+
+// sendUnitStatuses sends buffered workload statuses to the controller.
+// Statuses the controller rejects are logged rather than sent again.
+func (u *Uniter) sendUnitStatuses(statuses []status.StatusInfo) error {
+	errs, err := u.unit.SetUnitStatuses(statuses)
+	if err != nil {
+		return err
+	}
+	logRejectedStatuses("workload", statuses, errs)
+	return nil
+}
+
+func logRejectedStatuses(kind string, statuses []status.StatusInfo, errs []error) {
+	for i, err := range errs {
+		if err != nil {
+			logger.Errorf("cannot set %s status %q: %v", kind, statuses[i].Status, err)
+		}
+	}
 }
 
 // reportAgentError reports if there was an error performing an agent operation.
@@ -11,9 +11,11 @@ import (
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/api"
+	apitracing "github.com/juju/juju/api/tracing"
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/leadership"
+	"github.com/juju/juju/core/tracing"
 	"github.com/juju/juju/worker/dependency"
 	"github.com/juju/juju/worker/fortress"
 	"github.com/juju/juju/worker/uniter/operation"
@@ -89,6 +91,12 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 				return nil, errors.Errorf("expected a unit tag, got %v", tag)
 			}
 			uniterFacade := uniter.NewState(apiConn, unitTag)
+			// Controllers without the Tracing facade cannot
+			// record slow hooks.
+			var spanRecorder tracing.Recorder
+			if apiConn.BestFacadeVersion("Tracing") > 0 {
+				spanRecorder = apitracing.NewClient(apiConn)
+			}
 			uniter, err := NewUniter(&UniterParams{
 				UniterFacade:         uniterFacade,
 				UnitTag:              unitTag,
@@ -102,6 +110,7 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 				NewOperationExecutor: operation.NewExecutor,
 				TranslateResolverErr: config.TranslateResolverErr,
 				Clock:                manifoldConfig.Clock,
+				SpanRecorder:         spanRecorder,
 			})
 			if err != nil {
 				return nil, errors.Trace(err)
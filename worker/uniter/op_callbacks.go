@@ -12,6 +12,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/correlation"
 	"github.com/juju/juju/status"
 	"github.com/juju/juju/worker/uniter/charm"
 	"github.com/juju/juju/worker/uniter/hook"
@@ -66,6 +67,12 @@ func (opc *operationCallbacks) CommitHook(hi hook.Info) error {
 	return nil
 }
 
+// hookFailureReporter is implemented by contexts that capture what a
+// hook did, to be recorded should it fail.
+type hookFailureReporter interface {
+	HookFailureData() map[string]interface{}
+}
+
 func notifyHook(hook string, ctx runner.Context, method func(string)) {
 	if r, err := ctx.HookRelation(); err == nil {
 		remote, _ := ctx.RemoteUnitName()
@@ -86,6 +93,9 @@ func (opc *operationCallbacks) NotifyHookCompleted(hook string, ctx runner.Conte
 
 // NotifyHookFailed is part of the operation.Callbacks interface.
 func (opc *operationCallbacks) NotifyHookFailed(hook string, ctx runner.Context) {
+	if reporter, ok := ctx.(hookFailureReporter); ok {
+		opc.u.hookFailureData = reporter.HookFailureData()
+	}
 	if opc.u.observer != nil {
 		notifyHook(hook, ctx, opc.u.observer.HookFailed)
 	}
@@ -122,3 +132,12 @@ func (opc *operationCallbacks) SetCurrentCharm(charmURL *corecharm.URL) error {
 func (opc *operationCallbacks) SetExecutingStatus(message string) error {
 	return setAgentStatus(opc.u, status.Executing, message, nil)
 }
+
+// SetExecutingCorrelatedStatus is part of the operation.Callbacks interface.
+func (opc *operationCallbacks) SetExecutingCorrelatedStatus(message, correlationID string) error {
+	var data map[string]interface{}
+	if correlationID != "" {
+		data = map[string]interface{}{correlation.StatusDataKey: correlationID}
+	}
+	return setAgentStatus(opc.u, status.Executing, message, data)
+}
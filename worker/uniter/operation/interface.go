@@ -136,6 +136,11 @@ type Callbacks interface {
 	// SetExecutingStatus sets the agent state to "Executing" with a message.
 	SetExecutingStatus(string) error
 
+	// SetExecutingCorrelatedStatus is like SetExecutingStatus, but
+	// records the correlation ID of the work being executed, if any, in
+	// the status data. It's only used by RunAction operations.
+	SetExecutingCorrelatedStatus(message, correlationID string) error
+
 	// NotifyHook* exist so that we can defer worrying about how to untangle the
 	// callbacks inserted for uniter_test. They're only used by RunHook operations.
 	NotifyHookCompleted(string, runner.Context)
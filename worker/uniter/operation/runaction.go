@@ -17,8 +17,9 @@ type runAction struct {
 	callbacks     Callbacks
 	runnerFactory runner.Factory
 
-	name   string
-	runner runner.Runner
+	name          string
+	correlationID string
+	runner        runner.Runner
 
 	RequiresMachineLock
 }
@@ -54,6 +55,7 @@ func (ra *runAction) Prepare(state State) (*State, error) {
 		return nil, errors.Trace(err)
 	}
 	ra.name = actionData.Name
+	ra.correlationID = actionData.CorrelationID
 	ra.runner = rnr
 	return stateChange{
 		Kind:     RunAction,
@@ -68,9 +70,12 @@ func (ra *runAction) Prepare(state State) (*State, error) {
 func (ra *runAction) Execute(state State) (*State, error) {
 	message := fmt.Sprintf("running action %s", ra.name)
 
-	if err := ra.callbacks.SetExecutingStatus(message); err != nil {
+	if err := ra.callbacks.SetExecutingCorrelatedStatus(message, ra.correlationID); err != nil {
 		return nil, err
 	}
+	if ra.correlationID != "" {
+		logger.Infof("running action %s %s for correlation-id %s", ra.name, ra.actionId, ra.correlationID)
+	}
 
 	err := ra.runner.RunAction(ra.name)
 	if err != nil {
@@ -223,6 +223,7 @@ func (s *RunActionSuite) TestExecuteSuccess(c *gc.C) {
 		c.Assert(err, jc.ErrorIsNil)
 		c.Assert(newState, jc.DeepEquals, &test.after)
 		c.Assert(callbacks.executingMessage, gc.Equals, "running action some-action-name")
+		c.Assert(callbacks.executingCorrelationID, gc.Equals, "deadbeef")
 		c.Assert(*runnerFactory.MockNewActionRunner.runner.MockRunAction.gotName, gc.Equals, "some-action-name")
 	}
 }
@@ -116,15 +116,17 @@ func (mock *MockFailAction) Call(actionId, message string) error {
 type RunActionCallbacks struct {
 	operation.Callbacks
 	*MockFailAction
-	executingMessage string
+	executingMessage       string
+	executingCorrelationID string
 }
 
 func (cb *RunActionCallbacks) FailAction(actionId, message string) error {
 	return cb.MockFailAction.Call(actionId, message)
 }
 
-func (cb *RunActionCallbacks) SetExecutingStatus(message string) error {
+func (cb *RunActionCallbacks) SetExecutingCorrelatedStatus(message, correlationID string) error {
 	cb.executingMessage = message
+	cb.executingCorrelationID = correlationID
 	return nil
 }
 
@@ -414,7 +416,10 @@ func NewRunActionRunnerFactory(runErr error) *MockRunnerFactory {
 			runner: &MockRunner{
 				MockRunAction: &MockRunAction{err: runErr},
 				context: &MockContext{
-					actionData: &context.ActionData{Name: "some-action-name"},
+					actionData: &context.ActionData{
+						Name:          "some-action-name",
+						CorrelationID: "deadbeef",
+					},
 				},
 			},
 		},
@@ -103,6 +103,14 @@ type StatePaths struct {
 	// MetricsSpoolDir acts as temporary storage for metrics being sent from
 	// the uniter to state.
 	MetricsSpoolDir string
+
+	// StatusSpoolFile holds agent statuses waiting to be sent from the
+	// uniter to state.
+	StatusSpoolFile string
+
+	// UnitStatusSpoolFile holds workload statuses waiting to be sent
+	// from the uniter to state.
+	UnitStatusSpoolFile string
 }
 
 // NewPaths returns the set of filesystem paths that the supplied unit should
@@ -145,14 +153,16 @@ func NewWorkerPaths(dataDir string, unitTag names.UnitTag, worker string) Paths
 			JujucServerSocket: socket("agent", true),
 		},
 		State: StatePaths{
-			BaseDir:         baseDir,
-			CharmDir:        join(baseDir, "charm"),
-			OperationsFile:  join(stateDir, "uniter"),
-			RelationsDir:    join(stateDir, "relations"),
-			BundlesDir:      join(stateDir, "bundles"),
-			DeployerDir:     join(stateDir, "deployer"),
-			StorageDir:      join(stateDir, "storage"),
-			MetricsSpoolDir: join(stateDir, "spool", "metrics"),
+			BaseDir:             baseDir,
+			CharmDir:            join(baseDir, "charm"),
+			OperationsFile:      join(stateDir, "uniter"),
+			RelationsDir:        join(stateDir, "relations"),
+			BundlesDir:          join(stateDir, "bundles"),
+			DeployerDir:         join(stateDir, "deployer"),
+			StorageDir:          join(stateDir, "storage"),
+			MetricsSpoolDir:     join(stateDir, "spool", "metrics"),
+			StatusSpoolFile:     join(stateDir, "spool", "status"),
+			UnitStatusSpoolFile: join(stateDir, "spool", "unit-status"),
 		},
 	}
 }
@@ -44,14 +44,16 @@ func (s *PathsSuite) TestWindows(c *gc.C) {
 			JujucServerSocket: `\\.\pipe\unit-some-application-323-agent`,
 		},
 		State: uniter.StatePaths{
-			BaseDir:         relAgent(),
-			CharmDir:        relAgent("charm"),
-			OperationsFile:  relAgent("state", "uniter"),
-			RelationsDir:    relAgent("state", "relations"),
-			BundlesDir:      relAgent("state", "bundles"),
-			DeployerDir:     relAgent("state", "deployer"),
-			StorageDir:      relAgent("state", "storage"),
-			MetricsSpoolDir: relAgent("state", "spool", "metrics"),
+			BaseDir:             relAgent(),
+			CharmDir:            relAgent("charm"),
+			OperationsFile:      relAgent("state", "uniter"),
+			RelationsDir:        relAgent("state", "relations"),
+			BundlesDir:          relAgent("state", "bundles"),
+			DeployerDir:         relAgent("state", "deployer"),
+			StorageDir:          relAgent("state", "storage"),
+			MetricsSpoolDir:     relAgent("state", "spool", "metrics"),
+			StatusSpoolFile:     relAgent("state", "spool", "status"),
+			UnitStatusSpoolFile: relAgent("state", "spool", "unit-status"),
 		},
 	})
 }
@@ -73,14 +75,16 @@ func (s *PathsSuite) TestWorkerPathsWindows(c *gc.C) {
 			JujucServerSocket: `\\.\pipe\unit-some-application-323-some-worker-agent`,
 		},
 		State: uniter.StatePaths{
-			BaseDir:         relAgent(),
-			CharmDir:        relAgent("charm"),
-			OperationsFile:  relAgent("state", "uniter"),
-			RelationsDir:    relAgent("state", "relations"),
-			BundlesDir:      relAgent("state", "bundles"),
-			DeployerDir:     relAgent("state", "deployer"),
-			StorageDir:      relAgent("state", "storage"),
-			MetricsSpoolDir: relAgent("state", "spool", "metrics"),
+			BaseDir:             relAgent(),
+			CharmDir:            relAgent("charm"),
+			OperationsFile:      relAgent("state", "uniter"),
+			RelationsDir:        relAgent("state", "relations"),
+			BundlesDir:          relAgent("state", "bundles"),
+			DeployerDir:         relAgent("state", "deployer"),
+			StorageDir:          relAgent("state", "storage"),
+			MetricsSpoolDir:     relAgent("state", "spool", "metrics"),
+			StatusSpoolFile:     relAgent("state", "spool", "status"),
+			UnitStatusSpoolFile: relAgent("state", "spool", "unit-status"),
 		},
 	})
 }
@@ -101,14 +105,16 @@ func (s *PathsSuite) TestOther(c *gc.C) {
 			JujucServerSocket: "@" + relAgent("agent.socket"),
 		},
 		State: uniter.StatePaths{
-			BaseDir:         relAgent(),
-			CharmDir:        relAgent("charm"),
-			OperationsFile:  relAgent("state", "uniter"),
-			RelationsDir:    relAgent("state", "relations"),
-			BundlesDir:      relAgent("state", "bundles"),
-			DeployerDir:     relAgent("state", "deployer"),
-			StorageDir:      relAgent("state", "storage"),
-			MetricsSpoolDir: relAgent("state", "spool", "metrics"),
+			BaseDir:             relAgent(),
+			CharmDir:            relAgent("charm"),
+			OperationsFile:      relAgent("state", "uniter"),
+			RelationsDir:        relAgent("state", "relations"),
+			BundlesDir:          relAgent("state", "bundles"),
+			DeployerDir:         relAgent("state", "deployer"),
+			StorageDir:          relAgent("state", "storage"),
+			MetricsSpoolDir:     relAgent("state", "spool", "metrics"),
+			StatusSpoolFile:     relAgent("state", "spool", "status"),
+			UnitStatusSpoolFile: relAgent("state", "spool", "unit-status"),
 		},
 	})
 }
@@ -130,14 +136,16 @@ func (s *PathsSuite) TestWorkerPaths(c *gc.C) {
 			JujucServerSocket: "@" + relAgent(worker+"-agent.socket"),
 		},
 		State: uniter.StatePaths{
-			BaseDir:         relAgent(),
-			CharmDir:        relAgent("charm"),
-			OperationsFile:  relAgent("state", "uniter"),
-			RelationsDir:    relAgent("state", "relations"),
-			BundlesDir:      relAgent("state", "bundles"),
-			DeployerDir:     relAgent("state", "deployer"),
-			StorageDir:      relAgent("state", "storage"),
-			MetricsSpoolDir: relAgent("state", "spool", "metrics"),
+			BaseDir:             relAgent(),
+			CharmDir:            relAgent("charm"),
+			OperationsFile:      relAgent("state", "uniter"),
+			RelationsDir:        relAgent("state", "relations"),
+			BundlesDir:          relAgent("state", "bundles"),
+			DeployerDir:         relAgent("state", "deployer"),
+			StorageDir:          relAgent("state", "storage"),
+			MetricsSpoolDir:     relAgent("state", "spool", "metrics"),
+			StatusSpoolFile:     relAgent("state", "spool", "status"),
+			UnitStatusSpoolFile: relAgent("state", "spool", "unit-status"),
 		},
 	})
 }
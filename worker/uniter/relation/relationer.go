@@ -7,9 +7,11 @@ import (
 	"fmt"
 
 	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6"
 	"gopkg.in/juju/charm.v6/hooks"
 
 	apiuniter "github.com/juju/juju/api/uniter"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/runner/context"
 )
@@ -46,6 +48,22 @@ func (r *Relationer) IsImplicit() bool {
 	return r.ru.Endpoint().IsImplicit()
 }
 
+// DepartureHeld reports whether the relation's departure barrier
+// prevents the unit from breaking the relation. Only units on the
+// provider side of a relation are held by its barrier.
+func (r *Relationer) DepartureHeld() (bool, error) {
+	if r.ru.Endpoint().Role != charm.RoleProvider {
+		return false, nil
+	}
+	released, err := r.ru.DepartureBarrierReleased()
+	if params.IsCodeNotFoundOrCodeUnauthorized(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Annotatef(err, "checking departure barrier of relation %q", r.ru.Relation())
+	}
+	return !released, nil
+}
+
 // Join initializes local state and causes the unit to enter its relation
 // scope, allowing its counterpart units to detect its presence and settings
 // changes. Local state directory is not created until needed.
@@ -237,6 +237,7 @@ func (r *relations) NextHook(
 			continue
 		}
 		var remoteBroken bool
+		relationDying := relationSnapshot.Life == params.Dying
 		if remoteState.Life == params.Dying ||
 			relationSnapshot.Life == params.Dying || relationSnapshot.Suspended {
 			relationSnapshot = remotestate.RelationSnapshot{}
@@ -249,6 +250,20 @@ func (r *relations) NextHook(
 		if err == resolver.ErrNoOperation {
 			continue
 		}
+		if err == nil && hook.Kind == hooks.RelationBroken && relationDying {
+			// The relation is being removed, so its departure
+			// barrier may hold the hook back until the requirer
+			// units have cleaned up. This is checked again when
+			// the remote state next changes.
+			held, err := relationer.DepartureHeld()
+			if err != nil {
+				return hook, errors.Trace(err)
+			}
+			if held {
+				logger.Debugf("relation %d departure barrier not yet released", relationId)
+				continue
+			}
+		}
 		return hook, err
 	}
 	return hook.Info{}, resolver.ErrNoOperation
@@ -83,3 +83,22 @@ func hookCommand(hook string) []string {
 	}
 	return []string{hook}
 }
+
+// powerShellPreamble is prepended to commands run with PowerShell. It
+// makes PowerShell, and the programs it runs, write their output as
+// UTF-8 rather than in the console's code page, so the output can be
+// reported without loss. Agents running as services may have no
+// console, in which case the console's encoding cannot be changed and
+// the default is kept.
+const powerShellPreamble = `$OutputEncoding = New-Object System.Text.UTF8Encoding $false
+try { [Console]::OutputEncoding = $OutputEncoding } catch {}
+`
+
+// ShellCommands returns the supplied commands prepared to be run by
+// the host's shell, which is PowerShell on Windows and bash elsewhere.
+func ShellCommands(commands string) string {
+	if jujuos.HostOS() != jujuos.Windows {
+		return commands
+	}
+	return powerShellPreamble + commands
+}
@@ -47,6 +47,45 @@ func (s *WindowsHookSuite) TestHookCommandNotPowerShellScripts(c *gc.C) {
 	c.Assert(runner.HookCommand(bathook), gc.DeepEquals, []string{bathook})
 }
 
+func (s *WindowsHookSuite) TestShellCommandsWindows(c *gc.C) {
+	restorer := envtesting.PatchValue(&os.HostOS, func() os.OSType { return os.Windows })
+	defer restorer()
+
+	commands := runner.ShellCommands("Write-Output héllo")
+	c.Assert(commands, jc.HasPrefix, "$OutputEncoding = New-Object System.Text.UTF8Encoding $false\n")
+	c.Assert(commands, jc.HasSuffix, "\nWrite-Output héllo")
+}
+
+func (s *WindowsHookSuite) TestShellCommandsUbuntu(c *gc.C) {
+	restorer := envtesting.PatchValue(&os.HostOS, func() os.OSType { return os.Ubuntu })
+	defer restorer()
+
+	c.Assert(runner.ShellCommands("echo héllo"), gc.Equals, "echo héllo")
+}
+
+func (s *WindowsHookSuite) TestDecodeWindowsOutput(c *gc.C) {
+	for i, test := range []struct {
+		output   []byte
+		expected []byte
+	}{{
+		output:   []byte("plain"),
+		expected: []byte("plain"),
+	}, {
+		output:   []byte("\xef\xbb\xbfh\xc3\xa9llo"),
+		expected: []byte("héllo"),
+	}, {
+		output:   []byte("\xff\xfeh\x00\xe9\x00l\x00l\x00o\x00"),
+		expected: []byte("héllo"),
+	}, {
+		// Odd-length output is not UTF-16, and is left alone.
+		output:   []byte("\xff\xfeh"),
+		expected: []byte("\xff\xfeh"),
+	}} {
+		c.Logf("test %d", i)
+		c.Check(runner.DecodeWindowsOutput(test.output), jc.DeepEquals, test.expected)
+	}
+}
+
 func (s *WindowsHookSuite) TestSearchHookUbuntu(c *gc.C) {
 	if runtime.GOOS == "windows" {
 		c.Skip("Cannot search for executables without extension on windows")
@@ -15,6 +15,10 @@ type ActionData struct {
 	Failed         bool
 	ResultsMessage string
 	ResultsMap     map[string]interface{}
+
+	// CorrelationID identifies the operator action that queued the
+	// Action, if known.
+	CorrelationID string
 }
 
 // NewActionData builds a suitable ActionData struct with no nil members.
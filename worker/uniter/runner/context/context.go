@@ -21,6 +21,7 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/correlation"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/status"
 	"github.com/juju/juju/version"
@@ -143,6 +144,10 @@ type HookContext struct {
 	// status is the status of the local unit.
 	status *jujuc.StatusInfo
 
+	// statusBuffer, if set, records the workload statuses set by the
+	// hook, to be sent to the controller without holding it up.
+	statusBuffer StatusBuffer
+
 	// relationId identifies the relation for which a relation hook is
 	// executing. If it is -1, the context is not running a relation hook;
 	// otherwise, its value must be a valid key into the relations map.
@@ -214,6 +219,12 @@ type HookContext struct {
 
 	//  slaLevel contains the current SLA level.
 	slaLevel string
+
+	// hookOutput holds the tail of the output of the hook being run,
+	// and logMessages the last messages it logged with juju-log,
+	// to report should the hook fail.
+	hookOutput  []string
+	logMessages []string
 }
 
 // Component implements jujuc.Context.
@@ -352,11 +363,28 @@ func (ctx *HookContext) ApplicationStatus() (jujuc.ApplicationStatusInfo, error)
 func (ctx *HookContext) SetUnitStatus(unitStatus jujuc.StatusInfo) error {
 	ctx.hasRunStatusSet = true
 	logger.Tracef("[WORKLOAD-STATUS] %s: %s", unitStatus.Status, unitStatus.Info)
-	return ctx.unit.SetUnitStatus(
-		status.Status(unitStatus.Status),
-		unitStatus.Info,
-		unitStatus.Data,
-	)
+	if ctx.statusBuffer == nil {
+		return ctx.unit.SetUnitStatus(
+			status.Status(unitStatus.Status),
+			unitStatus.Info,
+			unitStatus.Data,
+		)
+	}
+	if err := ctx.statusBuffer.Add(status.StatusInfo{
+		Status:  status.Status(unitStatus.Status),
+		Message: unitStatus.Info,
+		Data:    unitStatus.Data,
+	}); err != nil {
+		return errors.Trace(err)
+	}
+	// The controller may not have the status yet, so
+	// later calls to UnitStatus must not ask it.
+	ctx.status = &jujuc.StatusInfo{
+		Status: unitStatus.Status,
+		Info:   unitStatus.Info,
+		Data:   unitStatus.Data,
+	}
+	return nil
 }
 
 // SetApplicationStatus will set the given status to the service to which this
@@ -441,16 +469,24 @@ func (ctx *HookContext) AddUnitStorage(cons map[string]params.StorageConstraints
 }
 
 func (ctx *HookContext) OpenPorts(protocol string, fromPort, toPort int) error {
+	return ctx.OpenPortsForEndpoint("", protocol, fromPort, toPort)
+}
+
+func (ctx *HookContext) ClosePorts(protocol string, fromPort, toPort int) error {
+	return ctx.ClosePortsForEndpoint("", protocol, fromPort, toPort)
+}
+
+func (ctx *HookContext) OpenPortsForEndpoint(endpoint, protocol string, fromPort, toPort int) error {
 	return tryOpenPorts(
-		protocol, fromPort, toPort,
+		endpoint, protocol, fromPort, toPort,
 		ctx.unit.Tag(),
 		ctx.machinePorts, ctx.pendingPorts,
 	)
 }
 
-func (ctx *HookContext) ClosePorts(protocol string, fromPort, toPort int) error {
+func (ctx *HookContext) ClosePortsForEndpoint(endpoint, protocol string, fromPort, toPort int) error {
 	return tryClosePorts(
-		protocol, fromPort, toPort,
+		endpoint, protocol, fromPort, toPort,
 		ctx.unit.Tag(),
 		ctx.machinePorts, ctx.pendingPorts,
 	)
@@ -574,10 +610,11 @@ func (c *HookContext) ActionData() (*ActionData, error) {
 // such that it can know what environment it's operating in, and can call back
 // into context.
 func (context *HookContext) HookVars(paths Paths) ([]string, error) {
+	charmDir := hostPath(paths.GetCharmDir())
 	vars := context.proxySettings.AsEnvironmentValues()
 	vars = append(vars,
-		"CHARM_DIR="+paths.GetCharmDir(), // legacy, embarrassing
-		"JUJU_CHARM_DIR="+paths.GetCharmDir(),
+		"CHARM_DIR="+charmDir, // legacy, embarrassing
+		"JUJU_CHARM_DIR="+charmDir,
 		"JUJU_CONTEXT_ID="+context.id,
 		"JUJU_AGENT_SOCKET="+paths.GetJujucSocket(),
 		"JUJU_UNIT_NAME="+context.unitName,
@@ -607,6 +644,9 @@ func (context *HookContext) HookVars(paths Paths) ([]string, error) {
 			"JUJU_ACTION_UUID="+context.actionData.Tag.Id(),
 			"JUJU_ACTION_TAG="+context.actionData.Tag.String(),
 		)
+		if context.actionData.CorrelationID != "" {
+			vars = append(vars, correlation.EnvVar+"="+context.actionData.CorrelationID)
+		}
 	}
 	return append(vars, OSDependentEnvVars(paths)...), nil
 }
@@ -687,14 +727,16 @@ func (ctx *HookContext) Flush(process string, ctxErr error) (err error) {
 			var e error
 			var op string
 			if rangeInfo.ShouldOpen {
-				e = ctx.unit.OpenPorts(
+				e = ctx.unit.OpenPortsForEndpoint(
+					rangeInfo.Endpoint,
 					rangeKey.Ports.Protocol,
 					rangeKey.Ports.FromPort,
 					rangeKey.Ports.ToPort,
 				)
 				op = "open"
 			} else {
-				e = ctx.unit.ClosePorts(
+				e = ctx.unit.ClosePortsForEndpoint(
+					rangeInfo.Endpoint,
 					rangeKey.Ports.Protocol,
 					rangeKey.Ports.FromPort,
 					rangeKey.Ports.ToPort,
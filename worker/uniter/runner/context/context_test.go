@@ -5,8 +5,10 @@ package context_test
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
+	"github.com/juju/loggo"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -130,6 +132,58 @@ func (s *InterfaceSuite) TestSetUnitStatus(c *gc.C) {
 	c.Check(unitStatus.Data, gc.DeepEquals, map[string]interface{}{})
 }
 
+func (s *InterfaceSuite) TestSetUnitStatusBuffered(c *gc.C) {
+	ctx := s.GetContext(c, -1, "")
+	var buffer fakeStatusBuffer
+	defer context.PatchStatusBuffer(ctx, &buffer)()
+	err := ctx.SetUnitStatus(jujuc.StatusInfo{
+		Status: "maintenance",
+		Info:   "doing work",
+	})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(buffer.statuses, jc.DeepEquals, []status.StatusInfo{{
+		Status:  status.Maintenance,
+		Message: "doing work",
+	}})
+
+	// The status is reported before the controller has it.
+	unitStatus, err := ctx.UnitStatus()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(unitStatus.Status, gc.Equals, "maintenance")
+	c.Check(unitStatus.Info, gc.Equals, "doing work")
+	unitStatusInfo, err := s.unit.Status()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(unitStatusInfo.Status, gc.Not(gc.Equals), status.Maintenance)
+}
+
+type fakeStatusBuffer struct {
+	statuses []status.StatusInfo
+}
+
+func (b *fakeStatusBuffer) Add(info status.StatusInfo) error {
+	b.statuses = append(b.statuses, info)
+	return nil
+}
+
+func (s *InterfaceSuite) TestHookFailureData(c *gc.C) {
+	ctx := s.GetContext(c, -1, "").(*context.HookContext)
+	defer context.PatchCachedStatus(ctx, "blocked", "missing relation", nil)()
+	for i := 0; i < context.MaxFailureLines+5; i++ {
+		ctx.RecordHookOutput(fmt.Sprintf("line %d", i))
+	}
+	ctx.RecordLogMessage(loggo.ERROR, "cannot connect to database")
+
+	data := ctx.HookFailureData()
+	output, ok := data["hook-output"].([]string)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(output, gc.HasLen, context.MaxFailureLines)
+	c.Check(output[0], gc.Equals, "line 5")
+	c.Check(output[context.MaxFailureLines-1], gc.Equals, fmt.Sprintf("line %d", context.MaxFailureLines+4))
+	c.Check(data["hook-log"], jc.DeepEquals, []string{"ERROR cannot connect to database"})
+	c.Check(data["workload-status"], gc.Equals, "blocked")
+	c.Check(data["workload-message"], gc.Equals, "missing relation")
+}
+
 func (s *InterfaceSuite) TestSetUnitStatusUpdatesFlag(c *gc.C) {
 	ctx := s.GetContext(c, -1, "")
 	c.Assert(ctx.(runner.Context).HasExecutionSetUnitStatus(), jc.IsFalse)
@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/leadership"
+	"github.com/juju/juju/status"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
 )
@@ -56,6 +57,12 @@ type StorageContextAccessor interface {
 	Storage(names.StorageTag) (jujuc.ContextStorageAttachment, error)
 }
 
+// StatusBuffer records statuses, to be sent to the controller once it
+// can be reached.
+type StatusBuffer interface {
+	Add(status.StatusInfo) error
+}
+
 // RelationsFunc is used to get snapshots of relation membership at context
 // creation time.
 type RelationsFunc func() map[int]*RelationInfo
@@ -76,6 +83,9 @@ type contextFactory struct {
 	zone       string
 	principal  string
 
+	// statusBuffer, if set, records the workload statuses set by hooks.
+	statusBuffer StatusBuffer
+
 	// Callback to get relation state snapshot.
 	getRelationInfos RelationsFunc
 	relationCaches   map[int]*RelationCache
@@ -94,6 +104,11 @@ type FactoryConfig struct {
 	Storage          StorageContextAccessor
 	Paths            Paths
 	Clock            clock.Clock
+
+	// StatusBuffer, if set, records the workload statuses set by
+	// hooks, to be sent to the controller without holding them up.
+	// If it is not set, statuses are sent as they are set.
+	StatusBuffer StatusBuffer
 }
 
 // NewContextFactory returns a ContextFactory capable of creating execution contexts backed
@@ -139,6 +154,7 @@ func NewContextFactory(config FactoryConfig) (ContextFactory, error) {
 		clock:            config.Clock,
 		zone:             zone,
 		principal:        principal,
+		statusBuffer:     config.StatusBuffer,
 	}
 	return f, nil
 }
@@ -172,6 +188,7 @@ func (f *contextFactory) coreContext() (*HookContext, error) {
 		componentFuncs:     registeredComponentFuncs,
 		availabilityzone:   f.zone,
 		principal:          f.principal,
+		statusBuffer:       f.statusBuffer,
 	}
 	if err := f.updateContext(ctx); err != nil {
 		return nil, err
@@ -308,7 +325,7 @@ func (f *contextFactory) updateContext(ctx *HookContext) (err error) {
 	if err != nil {
 		return err
 	}
-	ctx.proxySettings = modelConfig.ProxySettings()
+	ctx.proxySettings = modelConfig.ApplicationProxySettings(f.unit.ApplicationName())
 
 	// Calling these last, because there's a potential race: they're not guaranteed
 	// to be set in time to be needed for a hook. If they're not, we just leave them
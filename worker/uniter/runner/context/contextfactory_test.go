@@ -12,6 +12,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	"github.com/juju/utils/fs"
+	"github.com/juju/utils/proxy"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6/hooks"
 	"gopkg.in/juju/names.v2"
@@ -128,6 +129,23 @@ func (s *ContextFactorySuite) TestNewHookContextRetrievesSLALevel(c *gc.C) {
 	c.Assert(ctx.SLALevel(), gc.Equals, "essential")
 }
 
+func (s *ContextFactorySuite) TestNewHookContextApplicationProxySettings(c *gc.C) {
+	err := s.Model(c).UpdateModelConfig(map[string]interface{}{
+		"http-proxy":      "http://model:3128",
+		"https-proxy":     "http://model:3128",
+		"proxy-overrides": s.unit.ApplicationName() + "=http=http://app:3128",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx, err := s.factory.HookContext(hook.Info{Kind: hooks.ConfigChanged})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.ProxySettings(), jc.DeepEquals, proxy.Settings{
+		Http:    "http://app:3128",
+		Https:   "http://model:3128",
+		NoProxy: "127.0.0.1,localhost,::1",
+	})
+}
+
 func (s *ContextFactorySuite) TestNewHookContextLeadershipContext(c *gc.C) {
 	s.testLeadershipContextWiring(c, func() *context.HookContext {
 		ctx, err := s.factory.HookContext(hook.Info{Kind: hooks.ConfigChanged})
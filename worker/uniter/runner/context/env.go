@@ -6,6 +6,7 @@ package context
 import (
 	"os"
 	"path/filepath"
+	"strings"
 
 	jujuos "github.com/juju/utils/os"
 )
@@ -26,6 +27,16 @@ func OSDependentEnvVars(paths Paths) []string {
 	return nil
 }
 
+// hostPath returns the supplied path in the form expected by programs
+// on the host. Agent paths on Windows are configured with forward
+// slashes, which PowerShell and many Windows programs do not accept.
+func hostPath(path string) string {
+	if jujuos.HostOS() != jujuos.Windows {
+		return path
+	}
+	return strings.Replace(path, "/", `\`, -1)
+}
+
 func appendPath(paths Paths) []string {
 	return []string{
 		"PATH=" + paths.GetToolsDir() + ":" + os.Getenv("PATH"),
@@ -56,9 +67,9 @@ func opensuseEnv(paths Paths) []string {
 // a semicolon instead of a colon
 func windowsEnv(paths Paths) []string {
 	charmDir := paths.GetCharmDir()
-	charmModules := filepath.Join(charmDir, "lib", "Modules")
+	charmModules := hostPath(filepath.Join(charmDir, "lib", "Modules"))
 	return []string{
-		"Path=" + paths.GetToolsDir() + ";" + os.Getenv("Path"),
+		"Path=" + hostPath(paths.GetToolsDir()) + ";" + os.Getenv("Path"),
 		"PSModulePath=" + os.Getenv("PSModulePath") + ";" + charmModules,
 	}
 }
@@ -5,7 +5,6 @@ package context_test
 
 import (
 	"os"
-	"path/filepath"
 	"runtime"
 	"sort"
 
@@ -119,7 +118,7 @@ func (s *EnvSuite) TestEnvWindows(c *gc.C) {
 	os.Setenv("PSModulePath", "ping;pong")
 	windowsVars := []string{
 		"Path=path-to-tools;foo;bar",
-		"PSModulePath=ping;pong;" + filepath.FromSlash("path-to-charm/lib/Modules"),
+		`PSModulePath=ping;pong;path-to-charm\lib\Modules`,
 	}
 
 	ctx, contextVars := s.getContext()
@@ -134,6 +133,35 @@ func (s *EnvSuite) TestEnvWindows(c *gc.C) {
 	s.assertVars(c, actualVars, contextVars, pathsVars, windowsVars, relationVars)
 }
 
+type windowsEnvPaths struct {
+	MockEnvPaths
+}
+
+func (windowsEnvPaths) GetToolsDir() string {
+	return "C:/Juju/lib/juju/tools/unit-this-unit-123"
+}
+
+func (windowsEnvPaths) GetCharmDir() string {
+	return "C:/Juju/lib/juju/agents/unit-this-unit-123/charm"
+}
+
+func (s *EnvSuite) TestEnvWindowsTranslatesPaths(c *gc.C) {
+	s.PatchValue(&jujuos.HostOS, func() jujuos.OSType { return jujuos.Windows })
+	os.Setenv("Path", "foo;bar")
+	os.Setenv("PSModulePath", "ping;pong")
+
+	ctx, _ := s.getContext()
+	actualVars, err := ctx.HookVars(windowsEnvPaths{})
+	c.Assert(err, jc.ErrorIsNil)
+	vars, err := keyvalues.Parse(actualVars, true)
+	c.Assert(err, jc.ErrorIsNil)
+	charmDir := `C:\Juju\lib\juju\agents\unit-this-unit-123\charm`
+	c.Check(vars["CHARM_DIR"], gc.Equals, charmDir)
+	c.Check(vars["JUJU_CHARM_DIR"], gc.Equals, charmDir)
+	c.Check(vars["Path"], gc.Equals, `C:\Juju\lib\juju\tools\unit-this-unit-123;foo;bar`)
+	c.Check(vars["PSModulePath"], gc.Equals, `ping;pong;`+charmDir+`\lib\Modules`)
+}
+
 func (s *EnvSuite) TestEnvUbuntu(c *gc.C) {
 	s.PatchValue(&jujuos.HostOS, func() jujuos.OSType { return jujuos.Ubuntu })
 	s.PatchValue(&jujuversion.Current, version.MustParse("1.2.3"))
@@ -190,3 +190,16 @@ func CachedSettings(cf0 ContextFactory, relId int, unitName string) (params.Sett
 func (ctx *HookContext) SLALevel() string {
 	return ctx.slaLevel
 }
+
+func (ctx *HookContext) ProxySettings() proxy.Settings {
+	return ctx.proxySettings
+}
+
+func PatchStatusBuffer(ctx jujuc.Context, buffer StatusBuffer) func() {
+	hctx := ctx.(*HookContext)
+	oldBuffer := hctx.statusBuffer
+	hctx.statusBuffer = buffer
+	return func() {
+		hctx.statusBuffer = oldBuffer
+	}
+}
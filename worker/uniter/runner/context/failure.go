@@ -0,0 +1,77 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context
+
+import (
+	"fmt"
+
+	"github.com/juju/loggo"
+)
+
+const (
+	// MaxFailureLines is the largest number of lines of hook output,
+	// and of juju-log messages, kept to report should a hook fail.
+	MaxFailureLines = 20
+
+	// maxFailureLineLength is the length at which kept lines are
+	// truncated.
+	maxFailureLineLength = 256
+)
+
+// appendTail appends line to lines, keeping only the last
+// MaxFailureLines lines.
+func appendTail(lines []string, line string) []string {
+	if len(line) > maxFailureLineLength {
+		line = line[:maxFailureLineLength-3] + "..."
+	}
+	lines = append(lines, line)
+	if len(lines) > MaxFailureLines {
+		lines = lines[len(lines)-MaxFailureLines:]
+	}
+	return lines
+}
+
+// RecordHookOutput records a line of the combined stdout and stderr of
+// the hook being run, to report should the hook fail.
+func (ctx *HookContext) RecordHookOutput(line string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	ctx.hookOutput = appendTail(ctx.hookOutput, line)
+}
+
+// RecordLogMessage records a message logged by the hook with juju-log,
+// to report should the hook fail.
+func (ctx *HookContext) RecordLogMessage(level loggo.Level, message string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	ctx.logMessages = appendTail(ctx.logMessages, fmt.Sprintf("%s %s", level, message))
+}
+
+// HookFailureData returns the context captured while running a hook,
+// for recording alongside the hook's failure: the tail of its output,
+// the messages it logged with juju-log and the unit's workload status
+// at the time it failed.
+func (ctx *HookContext) HookFailureData() map[string]interface{} {
+	mutex.Lock()
+	data := map[string]interface{}{}
+	if len(ctx.hookOutput) > 0 {
+		data["hook-output"] = append([]string(nil), ctx.hookOutput...)
+	}
+	if len(ctx.logMessages) > 0 {
+		data["hook-log"] = append([]string(nil), ctx.logMessages...)
+	}
+	mutex.Unlock()
+
+	// The workload status is cached in the context if the hook has
+	// read or set it, so this rarely needs to go to the controller.
+	if workload, err := ctx.UnitStatus(); err != nil {
+		logger.Warningf("cannot get workload status for hook failure: %v", err)
+	} else {
+		data["workload-status"] = workload.Status
+		if workload.Info != "" {
+			data["workload-message"] = workload.Info
+		}
+	}
+	return data
+}
@@ -169,6 +169,25 @@ func (s *FlushContextSuite) TestRunHookOpensAndClosesPendingPorts(c *gc.C) {
 	c.Assert(unitRanges, jc.DeepEquals, expectUnitRanges)
 }
 
+func (s *FlushContextSuite) TestRunHookOpensPendingPortsForEndpoint(c *gc.C) {
+	ctx := s.context(c)
+	err := ctx.OpenPortsForEndpoint("url", "tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = ctx.Flush("some badge", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ports, err := s.machine.OpenedPorts("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports.AllPortRangeInfo(), jc.DeepEquals, []state.PortRange{{
+		UnitName: "u/0",
+		FromPort: 80,
+		ToPort:   80,
+		Protocol: "tcp",
+		Endpoint: "url",
+	}})
+}
+
 func (s *FlushContextSuite) TestRunHookAddStorageOnFailure(c *gc.C) {
 	ctx := s.context(c)
 	c.Assert(ctx.UnitName(), gc.Equals, "u/0")
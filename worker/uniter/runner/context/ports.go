@@ -19,6 +19,7 @@ import (
 type PortRangeInfo struct {
 	ShouldOpen  bool
 	RelationTag names.RelationTag
+	Endpoint    string
 }
 
 // PortRange contains a port range and a relation id. Used as key to
@@ -42,7 +43,7 @@ func validatePortRange(protocol string, fromPort, toPort int) (network.PortRange
 }
 
 func tryOpenPorts(
-	protocol string,
+	endpoint, protocol string,
 	fromPort, toPort int,
 	unitTag names.UnitTag,
 	machinePorts map[network.PortRange]params.RelationUnit,
@@ -69,6 +70,7 @@ func tryOpenPorts(
 			// If the same range is already pending to be closed, just
 			// mark is pending to be opened.
 			rangeInfo.ShouldOpen = true
+			rangeInfo.Endpoint = endpoint
 			pendingPorts[rangeKey] = rangeInfo
 		}
 		return nil
@@ -109,12 +111,13 @@ func tryOpenPorts(
 
 	rangeInfo = pendingPorts[rangeKey]
 	rangeInfo.ShouldOpen = true
+	rangeInfo.Endpoint = endpoint
 	pendingPorts[rangeKey] = rangeInfo
 	return nil
 }
 
 func tryClosePorts(
-	protocol string,
+	endpoint, protocol string,
 	fromPort, toPort int,
 	unitTag names.UnitTag,
 	machinePorts map[network.PortRange]params.RelationUnit,
@@ -168,6 +171,7 @@ func tryClosePorts(
 
 	rangeInfo = pendingPorts[rangeKey]
 	rangeInfo.ShouldOpen = false
+	rangeInfo.Endpoint = endpoint
 	pendingPorts[rangeKey] = rangeInfo
 	return nil
 }
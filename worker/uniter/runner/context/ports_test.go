@@ -202,6 +202,7 @@ func (s *PortsSuite) TestTryOpenPorts(c *gc.C) {
 
 		test = test.withDefaults("tcp", 10, 20)
 		err := context.TryOpenPorts(
+			"",
 			test.proto,
 			test.ports[0],
 			test.ports[1],
@@ -256,6 +257,7 @@ func (s *PortsSuite) TestTryClosePorts(c *gc.C) {
 
 		test = test.withDefaults("tcp", 10, 20)
 		err := context.TryClosePorts(
+			"",
 			test.proto,
 			test.ports[0],
 			test.ports[1],
@@ -5,6 +5,7 @@ package context
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
@@ -89,3 +90,13 @@ func (ctx *ContextRelation) Suspended() bool {
 func (ctx *ContextRelation) SetStatus(status relation.Status) error {
 	return ctx.ru.Relation().SetStatus(status)
 }
+
+// SetDepartureBarrier sets a departure barrier on the relation.
+func (ctx *ContextRelation) SetDepartureBarrier(timeout time.Duration) error {
+	return ctx.ru.SetDepartureBarrier(timeout)
+}
+
+// ConfirmDeparture confirms the unit's departure from the relation.
+func (ctx *ContextRelation) ConfirmDeparture() error {
+	return ctx.ru.ConfirmDeparture()
+}
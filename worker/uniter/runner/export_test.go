@@ -12,6 +12,7 @@ var (
 	SearchHook              = searchHook
 	HookCommand             = hookCommand
 	LookPath                = lookPath
+	DecodeWindowsOutput     = decodeWindowsOutput
 )
 
 func RunnerPaths(rnr Runner) context.Paths {
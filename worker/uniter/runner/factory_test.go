@@ -252,6 +252,24 @@ func (s *FactorySuite) TestNewActionRunnerGood(c *gc.C) {
 	}
 }
 
+func (s *FactorySuite) TestNewActionRunnerCorrelated(c *gc.C) {
+	s.SetCharm(c, "dummy")
+	action, err := s.model.EnqueueCorrelatedAction(s.unit.Tag(), "deadbeef", "snapshot", map[string]interface{}{
+		"outfile": "/some/file.bz2",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	rnr, err := s.factory.NewActionRunner(action.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := rnr.Context()
+	data, err := ctx.ActionData()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.CorrelationID, gc.Equals, "deadbeef")
+	vars, err := ctx.HookVars(s.paths)
+	c.Assert(err, jc.ErrorIsNil)
+	combined := strings.Join(vars, "|")
+	c.Assert(combined, gc.Matches, `(^|.*\|)JUJU_CORRELATION_ID=deadbeef(\|.*|$)`)
+}
+
 func (s *FactorySuite) TestNewActionRunnerBadCharm(c *gc.C) {
 	rnr, err := s.factory.NewActionRunner("irrelevant")
 	c.Assert(rnr, gc.IsNil)
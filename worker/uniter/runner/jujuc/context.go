@@ -155,6 +155,15 @@ type ContextNetworking interface {
 	// separately by a co- located unit).
 	ClosePorts(protocol string, fromPort, toPort int) error
 
+	// OpenPortsForEndpoint marks the supplied port range for opening
+	// when the named endpoint of the executing unit's application is
+	// exposed.
+	OpenPortsForEndpoint(endpoint, protocol string, fromPort, toPort int) error
+
+	// ClosePortsForEndpoint ensures the supplied port range, opened
+	// for the named endpoint, is closed.
+	ClosePortsForEndpoint(endpoint, protocol string, fromPort, toPort int) error
+
 	// OpenedPorts returns all port ranges currently opened by this
 	// unit on its assigned machine. The result is sorted first by
 	// protocol, then by number.
@@ -271,6 +280,16 @@ type ContextRelation interface {
 
 	// SetStatus sets the relation's status.
 	SetStatus(relation.Status) error
+
+	// SetDepartureBarrier sets a departure barrier on the relation, so
+	// that when it is removed the provider units wait up to the timeout
+	// for the requirer units to confirm their departure before running
+	// relation-broken.
+	SetDepartureBarrier(timeout time.Duration) error
+
+	// ConfirmDeparture records that the executing unit has cleaned up
+	// after the relation, releasing it from the departure barrier.
+	ConfirmDeparture() error
 }
 
 // ContextStorageAttachment expresses the capabilities of a hook with
@@ -76,5 +76,14 @@ func (c *JujuLogCommand) Run(ctx *cmd.Context) error {
 	}
 
 	logger.Logf(logLevel, "%s%s", prefix, c.Message)
+	if recorder, ok := c.ctx.(logRecorder); ok {
+		recorder.RecordLogMessage(logLevel, prefix+c.Message)
+	}
 	return nil
 }
+
+// logRecorder is implemented by contexts that keep the messages logged
+// by a hook, to report should the hook fail.
+type logRecorder interface {
+	RecordLogMessage(level loggo.Level, message string)
+}
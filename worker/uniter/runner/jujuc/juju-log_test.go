@@ -5,8 +5,11 @@
 package jujuc_test
 
 import (
+	"fmt"
+
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -53,3 +56,22 @@ func (s *JujuLogSuite) TestLogDeprecation(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "--format flag deprecated for command \"juju-log\"")
 }
+
+type logRecordingContext struct {
+	jujuc.Context
+	messages []string
+}
+
+func (ctx *logRecordingContext) RecordLogMessage(level loggo.Level, message string) {
+	ctx.messages = append(ctx.messages, fmt.Sprintf("%s %s", level, message))
+}
+
+func (s *JujuLogSuite) TestLogRecordsMessage(c *gc.C) {
+	hctx, _ := s.newHookContext(1, "")
+	ctx := &logRecordingContext{Context: hctx}
+	com, err := jujuc.NewCommand(ctx, cmdString("juju-log"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = cmdtesting.RunCommand(c, com, "-l", "WARNING", "disk", "full")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.messages, jc.DeepEquals, []string{"WARNING peer1:1: disk full"})
+}
@@ -128,6 +128,7 @@ type portCommand struct {
 	Protocol   string
 	FromPort   int
 	ToPort     int
+	Endpoint   string
 	formatFlag string // deprecated
 }
 
@@ -137,6 +138,7 @@ func (c *portCommand) Info() *cmd.Info {
 
 func (c *portCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.formatFlag, "format", "", "deprecated format flag")
+	f.StringVar(&c.Endpoint, "endpoint", "", "the endpoint the port range is opened or closed for")
 }
 
 func (c *portCommand) Init(args []string) error {
@@ -166,13 +168,18 @@ var openPortInfo = &cmd.Info{
 	Name:    "open-port",
 	Args:    portFormat,
 	Purpose: "register a port or range to open",
-	Doc:     "The port range will only be open while the application is exposed.",
+	Doc: "The port range will only be open while the application is exposed.\n" +
+		"If --endpoint is specified, the port range will only be open while\n" +
+		"that endpoint of the application is exposed.",
 }
 
 func NewOpenPortCommand(ctx Context) (cmd.Command, error) {
 	return &portCommand{
 		info: openPortInfo,
 		action: func(c *portCommand) error {
+			if c.Endpoint != "" {
+				return ctx.OpenPortsForEndpoint(c.Endpoint, c.Protocol, c.FromPort, c.ToPort)
+			}
 			return ctx.OpenPorts(c.Protocol, c.FromPort, c.ToPort)
 		},
 	}, nil
@@ -188,6 +195,9 @@ func NewClosePortCommand(ctx Context) (cmd.Command, error) {
 	return &portCommand{
 		info: closePortInfo,
 		action: func(c *portCommand) error {
+			if c.Endpoint != "" {
+				return ctx.ClosePortsForEndpoint(c.Endpoint, c.Protocol, c.FromPort, c.ToPort)
+			}
 			return ctx.ClosePorts(c.Protocol, c.FromPort, c.ToPort)
 		},
 	}, nil
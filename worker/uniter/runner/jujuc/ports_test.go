@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
+	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -91,6 +92,25 @@ func (s *PortsSuite) TestOpenClose(c *gc.C) {
 	}
 }
 
+func (s *PortsSuite) TestOpenCloseForEndpoint(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	for _, name := range []string{"open-port", "close-port"} {
+		com, err := jujuc.NewCommand(hctx, cmdString(name))
+		c.Assert(err, jc.ErrorIsNil)
+		ctx := cmdtesting.Context(c)
+		code := cmd.Main(com, ctx, []string{"--endpoint", "website", "80"})
+		c.Check(code, gc.Equals, 0)
+	}
+	hctx.info.CheckPorts(c, nil)
+	s.Stub.CheckCalls(c, []jujutesting.StubCall{{
+		FuncName: "OpenPortsForEndpoint",
+		Args:     []interface{}{"website", "tcp", 80, 80},
+	}, {
+		FuncName: "ClosePortsForEndpoint",
+		Args:     []interface{}{"website", "tcp", 80, 80},
+	}})
+}
+
 var badPortsTests = []struct {
 	args []string
 	err  string
@@ -136,6 +156,8 @@ register a port or range to open
 
 Details:
 The port range will only be open while the application is exposed.
+If --endpoint is specified, the port range will only be open while
+that endpoint of the application is exposed.
 `[1:])
 
 	close, err := jujuc.NewCommand(hctx, cmdString("close-port"))
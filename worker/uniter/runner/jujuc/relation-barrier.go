@@ -0,0 +1,103 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+)
+
+// defaultDepartureBarrierTimeout is how long a departure barrier holds
+// when no timeout is specified.
+const defaultDepartureBarrierTimeout = 10 * time.Minute
+
+const relationBarrierDoc = `
+"relation-barrier" coordinates the departure of units from a relation
+that is being removed.
+
+Run on the provider side of a relation, it sets a departure barrier:
+when the relation is removed, the provider units do not run their
+relation-broken hooks until every requirer unit has confirmed that it
+has cleaned up, or has left the relation, or until the timeout has
+passed since the relation began to be removed.
+
+Run with --confirm on the requirer side of a relation, typically from
+the relation-departed or relation-broken hook, it confirms that the
+unit has cleaned up, releasing it from the barrier.
+
+If no relation is specified then the current relation is used.
+`
+
+// RelationBarrierCommand implements the relation-barrier command.
+type RelationBarrierCommand struct {
+	cmd.CommandBase
+	ctx             Context
+	RelationId      int
+	relationIdProxy gnuflag.Value
+	Timeout         time.Duration
+	Confirm         bool
+}
+
+// NewRelationBarrierCommand returns a new relation-barrier command.
+func NewRelationBarrierCommand(ctx Context) (cmd.Command, error) {
+	c := &RelationBarrierCommand{ctx: ctx}
+
+	rV, err := newRelationIdValue(ctx, &c.RelationId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	c.relationIdProxy = rV
+
+	return c, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *RelationBarrierCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "relation-barrier",
+		Args:    "[--timeout <duration> | --confirm]",
+		Purpose: "coordinate unit departure from a relation",
+		Doc:     relationBarrierDoc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *RelationBarrierCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.Var(c.relationIdProxy, "r", "specify a relation by id")
+	f.Var(c.relationIdProxy, "relation", "")
+	f.DurationVar(&c.Timeout, "timeout", 0, "how long provider units wait for requirer units (default 10m)")
+	f.BoolVar(&c.Confirm, "confirm", false, "confirm that this requirer unit has cleaned up")
+}
+
+// Init is part of the cmd.Command interface.
+func (c *RelationBarrierCommand) Init(args []string) error {
+	if c.RelationId == -1 {
+		return errors.Errorf("no relation id specified")
+	}
+	if c.Confirm && c.Timeout != 0 {
+		return errors.Errorf("cannot specify both --timeout and --confirm")
+	}
+	if c.Timeout < 0 {
+		return errors.Errorf("timeout must be positive, got %v", c.Timeout)
+	}
+	if !c.Confirm && c.Timeout == 0 {
+		c.Timeout = defaultDepartureBarrierTimeout
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run is part of the cmd.Command interface.
+func (c *RelationBarrierCommand) Run(ctx *cmd.Context) error {
+	r, err := c.ctx.Relation(c.RelationId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if c.Confirm {
+		return errors.Annotate(r.ConfirmDeparture(), "cannot confirm departure")
+	}
+	return errors.Annotate(r.SetDepartureBarrier(c.Timeout), "cannot set departure barrier")
+}
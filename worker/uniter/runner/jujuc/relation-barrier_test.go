@@ -0,0 +1,98 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type RelationBarrierSuite struct {
+	relationSuite
+}
+
+var _ = gc.Suite(&RelationBarrierSuite{})
+
+var relationBarrierInitTests = []struct {
+	summary string
+	relid   int
+	args    []string
+	err     string
+}{{
+	summary: "no default relation",
+	relid:   -1,
+	err:     "no relation id specified",
+}, {
+	summary: "both timeout and confirm",
+	relid:   1,
+	args:    []string{"--timeout", "5m", "--confirm"},
+	err:     "cannot specify both --timeout and --confirm",
+}, {
+	summary: "negative timeout",
+	relid:   1,
+	args:    []string{"--timeout", "-5m"},
+	err:     "timeout must be positive, got -5m0s",
+}, {
+	summary: "extra arguments",
+	relid:   1,
+	args:    []string{"foo"},
+	err:     `unrecognized args: \["foo"\]`,
+}}
+
+func (s *RelationBarrierSuite) TestInitErrors(c *gc.C) {
+	for i, t := range relationBarrierInitTests {
+		c.Logf("test %d: %s", i, t.summary)
+		hctx, _ := s.newHookContext(t.relid, "")
+		com, err := jujuc.NewCommand(hctx, cmdString("relation-barrier"))
+		c.Assert(err, jc.ErrorIsNil)
+		err = cmdtesting.InitCommand(com, t.args)
+		c.Assert(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *RelationBarrierSuite) TestSetBarrier(c *gc.C) {
+	hctx, info := s.newHookContext(1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("relation-barrier"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = cmdtesting.RunCommand(c, com, "--timeout", "30m")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.rels[1].DepartureBarrier, gc.Equals, 30*time.Minute)
+	c.Assert(info.rels[0].DepartureBarrier, gc.Equals, time.Duration(0))
+}
+
+func (s *RelationBarrierSuite) TestSetBarrierDefaultTimeout(c *gc.C) {
+	hctx, info := s.newHookContext(-1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("relation-barrier"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = cmdtesting.RunCommand(c, com, "-r", "peer0:0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.rels[0].DepartureBarrier, gc.Equals, 10*time.Minute)
+}
+
+func (s *RelationBarrierSuite) TestConfirm(c *gc.C) {
+	hctx, info := s.newHookContext(1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("relation-barrier"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = cmdtesting.RunCommand(c, com, "--confirm")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.rels[1].DepartureConfirmed, jc.IsTrue)
+	c.Assert(info.rels[1].DepartureBarrier, gc.Equals, time.Duration(0))
+}
+
+func (s *RelationBarrierSuite) TestConfirmError(c *gc.C) {
+	hctx, _ := s.newHookContext(1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("relation-barrier"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = cmdtesting.InitCommand(com, []string{"--confirm"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.Stub.SetErrors(errors.New("not a requirer"))
+	err = com.Run(cmdtesting.Context(c))
+	c.Assert(err, gc.ErrorMatches, "cannot confirm departure: not a requirer")
+}
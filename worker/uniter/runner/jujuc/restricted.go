@@ -61,6 +61,16 @@ func (*RestrictedContext) ClosePorts(protocol string, fromPort, toPort int) erro
 	return ErrRestrictedContext
 }
 
+// OpenPortsForEndpoint implements jujuc.Context.
+func (*RestrictedContext) OpenPortsForEndpoint(endpoint, protocol string, fromPort, toPort int) error {
+	return ErrRestrictedContext
+}
+
+// ClosePortsForEndpoint implements jujuc.Context.
+func (*RestrictedContext) ClosePortsForEndpoint(endpoint, protocol string, fromPort, toPort int) error {
+	return ErrRestrictedContext
+}
+
 // OpenedPorts implements jujuc.Context.
 func (*RestrictedContext) OpenedPorts() []network.PortRange { return nil }
 
@@ -49,6 +49,7 @@ var baseCommands = map[string]creator{
 	"juju-log" + cmdSuffix:                NewJujuLogCommand,
 	"open-port" + cmdSuffix:               NewOpenPortCommand,
 	"opened-ports" + cmdSuffix:            NewOpenedPortsCommand,
+	"relation-barrier" + cmdSuffix:        NewRelationBarrierCommand,
 	"relation-get" + cmdSuffix:            NewRelationGetCommand,
 	"action-get" + cmdSuffix:              NewActionGetCommand,
 	"action-set" + cmdSuffix:              NewActionSetCommand,
@@ -256,6 +256,7 @@ var newCommandTests = []struct {
 	{"juju-log", ""},
 	{"open-port", ""},
 	{"opened-ports", ""},
+	{"relation-barrier", ""},
 	{"relation-get", ""},
 	{"relation-ids", ""},
 	{"relation-list", ""},
@@ -6,6 +6,7 @@ package testing
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/juju/errors"
 
@@ -24,6 +25,10 @@ type Relation struct {
 	Units map[string]Settings
 	// UnitName is data for jujuc.ContextRelation.
 	UnitName string
+	// DepartureBarrier is data for jujuc.ContextRelation.
+	DepartureBarrier time.Duration
+	// DepartureConfirmed is data for jujuc.ContextRelation.
+	DepartureConfirmed bool
 }
 
 // Reset clears the Relation's settings.
@@ -119,3 +124,25 @@ func (r *ContextRelation) Suspended() bool {
 func (r *ContextRelation) SetStatus(status relation.Status) error {
 	return nil
 }
+
+// SetDepartureBarrier implements jujuc.ContextRelation.
+func (r *ContextRelation) SetDepartureBarrier(timeout time.Duration) error {
+	r.stub.AddCall("SetDepartureBarrier", timeout)
+	if err := r.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	r.info.DepartureBarrier = timeout
+	return nil
+}
+
+// ConfirmDeparture implements jujuc.ContextRelation.
+func (r *ContextRelation) ConfirmDeparture() error {
+	r.stub.AddCall("ConfirmDeparture")
+	if err := r.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	r.info.DepartureConfirmed = true
+	return nil
+}
@@ -18,6 +18,9 @@ type hookLogger struct {
 	mu      sync.Mutex
 	stopped bool
 	logger  loggo.Logger
+
+	// record, if not nil, is called with each line of output.
+	record func(line string)
 }
 
 func (l *hookLogger) run() {
@@ -38,6 +41,9 @@ func (l *hookLogger) run() {
 			return
 		}
 		l.logger.Debugf("%s", line)
+		if l.record != nil {
+			l.record(string(line))
+		}
 		l.mu.Unlock()
 	}
 }
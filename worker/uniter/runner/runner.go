@@ -4,12 +4,15 @@
 package runner
 
 import (
+	"bytes"
 	"encoding/base64"
+	"encoding/binary"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"time"
+	"unicode/utf16"
 	"unicode/utf8"
 
 	"github.com/juju/cmd"
@@ -57,6 +60,12 @@ type Context interface {
 	Flush(badge string, failure error) error
 }
 
+// outputRecorder is implemented by contexts that keep the tail of a
+// hook's output, to report should the hook fail.
+type outputRecorder interface {
+	RecordHookOutput(line string)
+}
+
 // NewRunner returns a Runner backed by the supplied context and paths.
 func NewRunner(context Context, paths context.Paths) Runner {
 	return &runner{context, paths}
@@ -91,8 +100,13 @@ func (runner *runner) runCommandsWithTimeout(commands string, timeout time.Durat
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if jujuos.HostOS() == jujuos.Windows {
+		// utils/exec merges the environment case sensitively, which
+		// would leave both our Path and the system's PATH set.
+		env = mergeWindowsEnvironment(env, os.Environ())
+	}
 	command := utilexec.RunParams{
-		Commands:    commands,
+		Commands:    ShellCommands(commands),
 		WorkingDir:  runner.paths.GetCharmDir(),
 		Environment: env,
 		Clock:       clock,
@@ -114,7 +128,15 @@ func (runner *runner) runCommandsWithTimeout(commands string, timeout time.Durat
 	}
 
 	// Block and wait for process to finish
-	return command.WaitWithCancel(cancel)
+	result, err := command.WaitWithCancel(cancel)
+	if err != nil {
+		return nil, err
+	}
+	if jujuos.HostOS() == jujuos.Windows {
+		result.Stdout = decodeWindowsOutput(result.Stdout)
+		result.Stderr = decodeWindowsOutput(result.Stderr)
+	}
+	return result, nil
 }
 
 // runJujuRunAction is the function that executes when a juju-run action is ran.
@@ -148,6 +170,29 @@ func (runner *runner) runJujuRunAction() (err error) {
 	return runner.context.Flush("juju-run", nil)
 }
 
+var (
+	utf8BOM    = []byte{0xef, 0xbb, 0xbf}
+	utf16LEBOM = []byte{0xff, 0xfe}
+)
+
+// decodeWindowsOutput converts output written by Windows programs to
+// UTF-8. Some programs write UTF-16 regardless of PowerShell's output
+// encoding, and PowerShell may mark UTF-8 output with a byte order mark;
+// output without a recognised byte order mark is returned unchanged.
+func decodeWindowsOutput(output []byte) []byte {
+	switch {
+	case bytes.HasPrefix(output, utf8BOM):
+		return output[len(utf8BOM):]
+	case bytes.HasPrefix(output, utf16LEBOM) && len(output)%2 == 0:
+		units := make([]uint16, (len(output)-len(utf16LEBOM))/2)
+		for i := range units {
+			units[i] = binary.LittleEndian.Uint16(output[len(utf16LEBOM)+2*i:])
+		}
+		return []byte(string(utf16.Decode(units)))
+	}
+	return output
+}
+
 func encodeBytes(input []byte) (value string, encoding string) {
 	if utf8.Valid(input) {
 		value = string(input)
@@ -252,6 +297,9 @@ func (runner *runner) runCharmHook(hookName string, env []string, charmLocation
 		done:   make(chan struct{}),
 		logger: runner.getLogger(hookName),
 	}
+	if recorder, ok := runner.context.(outputRecorder); ok {
+		hookLogger.record = recorder.RecordHookOutput
+	}
 	go hookLogger.run()
 	err = ps.Start()
 	outWriter.Close()
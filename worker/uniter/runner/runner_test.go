@@ -145,6 +145,25 @@ func (s *RunHookSuite) TestRunHook(c *gc.C) {
 	}
 }
 
+func (s *RunHookSuite) TestRunHookRecordsOutput(c *gc.C) {
+	ctx, err := s.contextFactory.HookContext(hook.Info{Kind: hooks.ConfigChanged})
+	c.Assert(err, jc.ErrorIsNil)
+	paths := runnertesting.NewRealPaths(c)
+	makeCharm(c, hookSpec{
+		dir:    "hooks",
+		name:   hookName,
+		perm:   0700,
+		stdout: "stdout",
+		stderr: "stderr",
+		code:   1,
+	}, paths.GetCharmDir())
+
+	err = runner.NewRunner(ctx, paths).RunHook("something-happened")
+	c.Assert(err, gc.ErrorMatches, "exit status 1")
+	data := ctx.(*context.HookContext).HookFailureData()
+	c.Assert(data["hook-output"], jc.SameContents, []string{"stdout", "stderr"})
+}
+
 type MockContext struct {
 	runner.Context
 	actionData      *context.ActionData
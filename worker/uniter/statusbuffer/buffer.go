@@ -0,0 +1,322 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package statusbuffer provides a write-behind buffer for the statuses
+// of a unit agent, so that changes of status are neither lost nor hold
+// up the uniter while the controller cannot be reached.
+package statusbuffer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils"
+	"github.com/juju/utils/clock"
+
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+var logger = loggo.GetLogger("juju.worker.uniter.statusbuffer")
+
+// SendFunc sends a batch of statuses, oldest first, to the controller.
+// It returns an error only if the batch could not be delivered, in
+// which case the whole batch is sent again later.
+type SendFunc func([]status.StatusInfo) error
+
+// Config holds the configuration and dependencies of a Buffer.
+type Config struct {
+	// Send sends buffered statuses to the controller.
+	Send SendFunc
+
+	// SpoolFile is the file in which statuses are kept until they
+	// have been sent, so that they survive restarts of the agent.
+	// Statuses are appended to it as they are added, and it is
+	// rewritten only once statuses have been sent or too many have
+	// been dropped.
+	SpoolFile string
+
+	// MaxEntries bounds the number of statuses that are kept. Once it
+	// is exceeded, the oldest statuses are dropped.
+	MaxEntries int
+
+	// RetryDelay is how long to wait before sending again after a
+	// batch could not be delivered.
+	RetryDelay time.Duration
+
+	// Clock is used to time statuses and retries.
+	Clock clock.Clock
+}
+
+// Validate returns an error if the config cannot be used to start a
+// Buffer.
+func (config Config) Validate() error {
+	if config.Send == nil {
+		return errors.NotValidf("nil Send")
+	}
+	if config.SpoolFile == "" {
+		return errors.NotValidf("empty SpoolFile")
+	}
+	if config.MaxEntries <= 0 {
+		return errors.NotValidf("non-positive MaxEntries")
+	}
+	if config.RetryDelay <= 0 {
+		return errors.NotValidf("non-positive RetryDelay")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	return nil
+}
+
+// Buffer is a worker that records statuses in a local spool as they are
+// added, and sends them to the controller in batches, in the order they
+// were added, whenever it can be reached.
+type Buffer struct {
+	catacomb catacomb.Catacomb
+	config   Config
+	changes  chan struct{}
+
+	mu sync.Mutex
+	// pending holds the statuses not yet sent.
+	pending []status.StatusInfo
+	// first is the sequence number of pending[0], counting every
+	// status added or loaded by the buffer.
+	first uint64
+	// spooled is the number of statuses in the spool, including any
+	// that have since been sent or dropped.
+	spooled int
+}
+
+// spoolEntry is the form in which a status is kept in the spool, one
+// per line.
+type spoolEntry struct {
+	Status  status.Status          `json:"status"`
+	Message string                 `json:"message,omitempty"`
+	Data    map[string]interface{} `json:"data,omitempty"`
+	Since   *time.Time             `json:"since,omitempty"`
+}
+
+// New returns a Buffer that first sends any statuses left in the spool
+// by a previous run.
+func New(config Config) (*Buffer, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(config.SpoolFile), 0755); err != nil {
+		return nil, errors.Trace(err)
+	}
+	pending, spooled, err := readSpool(config.SpoolFile)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read status spool")
+	}
+	if excess := len(pending) - config.MaxEntries; excess > 0 {
+		logger.Warningf("dropping %d unsent statuses", excess)
+		pending = pending[excess:]
+	}
+	b := &Buffer{
+		config:  config,
+		pending: pending,
+		spooled: spooled,
+		// The channel is buffered so that Add never blocks, and
+		// statuses added while a batch is sent are coalesced.
+		changes: make(chan struct{}, 1),
+	}
+	if spooled != len(pending) {
+		// Rewrite the spool without the statuses dropped or
+		// partly written, so nothing is appended to a torn line.
+		if err := b.writeSpool(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if len(pending) > 0 {
+		logger.Infof("sending %d statuses left from a previous run", len(pending))
+		b.changes <- struct{}{}
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &b.catacomb,
+		Work: b.loop,
+	})
+	return b, errors.Trace(err)
+}
+
+// Kill is part of the worker.Worker interface.
+func (b *Buffer) Kill() {
+	b.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (b *Buffer) Wait() error {
+	return b.catacomb.Wait()
+}
+
+// Add records the status, to be sent to the controller once it can be
+// reached. It returns once the status is spooled, whether or not the
+// buffer is still running; statuses spooled after it stops are sent by
+// the next Buffer using the same spool. If the status has no Since time,
+// it is set to the current time.
+func (b *Buffer) Add(info status.StatusInfo) error {
+	if info.Since == nil {
+		now := b.config.Clock.Now()
+		info.Since = &now
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pending = append(b.pending, info)
+	if excess := len(b.pending) - b.config.MaxEntries; excess > 0 {
+		logger.Warningf("dropping %d unsent statuses", excess)
+		b.pending = b.pending[excess:]
+		b.first += uint64(excess)
+	}
+	if err := b.appendSpool(info); err != nil {
+		return errors.Trace(err)
+	}
+	select {
+	case b.changes <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (b *Buffer) loop() error {
+	var retry <-chan time.Time
+	for {
+		select {
+		case <-b.catacomb.Dying():
+			return b.catacomb.ErrDying()
+		case <-b.changes:
+		case <-retry:
+		}
+		retry = nil
+		if err := b.flush(); err != nil {
+			logger.Warningf("cannot send statuses, retrying in %v: %v", b.config.RetryDelay, err)
+			retry = b.config.Clock.After(b.config.RetryDelay)
+		}
+	}
+}
+
+// flush sends the pending statuses and removes them from the spool.
+func (b *Buffer) flush() error {
+	b.mu.Lock()
+	batch := append([]status.StatusInfo(nil), b.pending...)
+	first := b.first
+	b.mu.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+	if err := b.config.Send(batch); err != nil {
+		return errors.Trace(err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	// Statuses may have been added, and the oldest
+	// dropped, while the batch was being sent.
+	sent := first + uint64(len(batch))
+	if sent <= b.first {
+		return nil
+	}
+	n := sent - b.first
+	if n > uint64(len(b.pending)) {
+		n = uint64(len(b.pending))
+	}
+	b.pending = b.pending[n:]
+	b.first += n
+	return errors.Trace(b.writeSpool())
+}
+
+// appendSpool adds the status to the end of the spool. Once the spool
+// holds twice as many statuses as the buffer keeps, it is rewritten to
+// hold only the pending ones. It must be called with the mutex held.
+func (b *Buffer) appendSpool(info status.StatusInfo) error {
+	if b.spooled >= 2*b.config.MaxEntries {
+		return errors.Trace(b.writeSpool())
+	}
+	data, err := encodeEntry(info)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	f, err := os.OpenFile(b.config.SpoolFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return errors.Annotate(err, "cannot write status spool")
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return errors.Annotate(err, "cannot write status spool")
+	}
+	b.spooled++
+	return nil
+}
+
+// writeSpool replaces the spool with the pending statuses. It must be
+// called with the mutex held.
+func (b *Buffer) writeSpool() error {
+	var buf bytes.Buffer
+	for _, info := range b.pending {
+		data, err := encodeEntry(info)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		buf.Write(data)
+	}
+	if err := utils.AtomicWriteFile(b.config.SpoolFile, buf.Bytes(), 0644); err != nil {
+		return errors.Annotate(err, "cannot write status spool")
+	}
+	b.spooled = len(b.pending)
+	return nil
+}
+
+// encodeEntry returns the spool line for the status.
+func encodeEntry(info status.StatusInfo) ([]byte, error) {
+	data, err := json.Marshal(spoolEntry{
+		Status:  info.Status,
+		Message: info.Message,
+		Data:    info.Data,
+		Since:   info.Since,
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot encode status")
+	}
+	return append(data, '\n'), nil
+}
+
+// readSpool returns the statuses in the spool, and the number of lines
+// it holds. A line that cannot be decoded, as left by an agent that
+// stopped while appending it, is skipped.
+func readSpool(path string) ([]status.StatusInfo, int, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	} else if err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	defer f.Close()
+	var statuses []status.StatusInfo
+	lines := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines++
+		var entry spoolEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			logger.Warningf("skipping bad status in spool: %v", err)
+			continue
+		}
+		statuses = append(statuses, status.StatusInfo{
+			Status:  entry.Status,
+			Message: entry.Message,
+			Data:    entry.Data,
+			Since:   entry.Since,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	return statuses, lines, nil
+}
@@ -0,0 +1,233 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package statusbuffer_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/statusbuffer"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type BufferSuite struct {
+	coretesting.BaseSuite
+	clock *jujutesting.Clock
+	sent  chan []status.StatusInfo
+	spool string
+
+	mu   sync.Mutex
+	fail error
+}
+
+var _ = gc.Suite(&BufferSuite{})
+
+func (s *BufferSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.clock = jujutesting.NewClock(time.Time{})
+	s.sent = make(chan []status.StatusInfo, 10)
+	s.setFail(nil)
+	s.spool = filepath.Join(c.MkDir(), "spool", "status")
+}
+
+func (s *BufferSuite) setFail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fail = err
+}
+
+func (s *BufferSuite) config() statusbuffer.Config {
+	return statusbuffer.Config{
+		Send: func(statuses []status.StatusInfo) error {
+			s.sent <- statuses
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			return s.fail
+		},
+		SpoolFile:  s.spool,
+		MaxEntries: 3,
+		RetryDelay: time.Minute,
+		Clock:      s.clock,
+	}
+}
+
+func (s *BufferSuite) nextBatch(c *gc.C) []status.StatusInfo {
+	select {
+	case statuses := <-s.sent:
+		return statuses
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for statuses to be sent")
+	}
+	panic("unreachable")
+}
+
+func (s *BufferSuite) assertNoBatch(c *gc.C) {
+	select {
+	case statuses := <-s.sent:
+		c.Fatalf("unexpected statuses sent: %v", statuses)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func messages(statuses []status.StatusInfo) []string {
+	result := make([]string, len(statuses))
+	for i, info := range statuses {
+		result[i] = info.Message
+	}
+	return result
+}
+
+func (s *BufferSuite) TestValidate(c *gc.C) {
+	config := s.config()
+	config.MaxEntries = 0
+	_, err := statusbuffer.New(config)
+	c.Assert(err, gc.ErrorMatches, "non-positive MaxEntries not valid")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *BufferSuite) TestAddSends(c *gc.C) {
+	b, err := statusbuffer.New(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, b)
+
+	err = b.Add(status.StatusInfo{Status: status.Executing, Message: "running install hook"})
+	c.Assert(err, jc.ErrorIsNil)
+	statuses := s.nextBatch(c)
+	c.Assert(statuses, gc.HasLen, 1)
+	c.Assert(statuses[0].Status, gc.Equals, status.Executing)
+	c.Assert(statuses[0].Since, jc.DeepEquals, &time.Time{})
+	s.assertNoBatch(c)
+}
+
+func (s *BufferSuite) TestRetryAfterFailure(c *gc.C) {
+	s.setFail(errors.New("connection is shut down"))
+	b, err := statusbuffer.New(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, b)
+
+	err = b.Add(status.StatusInfo{Status: status.Executing, Message: "one"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(messages(s.nextBatch(c)), jc.DeepEquals, []string{"one"})
+
+	s.setFail(nil)
+	err = s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(messages(s.nextBatch(c)), jc.DeepEquals, []string{"one"})
+	s.assertNoBatch(c)
+}
+
+func (s *BufferSuite) TestSpoolSurvivesRestart(c *gc.C) {
+	s.setFail(errors.New("connection is shut down"))
+	b, err := statusbuffer.New(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	err = b.Add(status.StatusInfo{Status: status.Executing, Message: "one"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.nextBatch(c)
+	workertest.CleanKill(c, b)
+
+	// Statuses added after the buffer stops are spooled too.
+	err = b.Add(status.StatusInfo{Status: status.Idle, Message: "two"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.setFail(nil)
+	b, err = statusbuffer.New(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, b)
+	statuses := s.nextBatch(c)
+	c.Assert(messages(statuses), jc.DeepEquals, []string{"one", "two"})
+	c.Assert(statuses[1].Status, gc.Equals, status.Idle)
+}
+
+func (s *BufferSuite) TestMaxEntriesDropsOldest(c *gc.C) {
+	// Statuses added to a stopped buffer are only spooled.
+	b, err := statusbuffer.New(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	workertest.CleanKill(c, b)
+	for _, message := range []string{"one", "two", "three", "four"} {
+		err = b.Add(status.StatusInfo{Status: status.Executing, Message: message})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	b, err = statusbuffer.New(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, b)
+	c.Assert(messages(s.nextBatch(c)), jc.DeepEquals, []string{"two", "three", "four"})
+}
+
+func (s *BufferSuite) spoolLines(c *gc.C) []string {
+	data, err := ioutil.ReadFile(s.spool)
+	c.Assert(err, jc.ErrorIsNil)
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func (s *BufferSuite) TestSpoolAppendedUntilSent(c *gc.C) {
+	s.setFail(errors.New("connection is shut down"))
+	b, err := statusbuffer.New(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, b)
+
+	err = b.Add(status.StatusInfo{Status: status.Executing, Message: "one"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.nextBatch(c)
+	err = b.Add(status.StatusInfo{Status: status.Idle, Message: "two"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.spoolLines(c), gc.HasLen, 2)
+
+	// Once the statuses are sent, the spool is emptied.
+	s.setFail(nil)
+	err = s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(messages(s.nextBatch(c)), jc.DeepEquals, []string{"one", "two"})
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		data, err := ioutil.ReadFile(s.spool)
+		c.Assert(err, jc.ErrorIsNil)
+		if len(data) == 0 {
+			return
+		}
+	}
+	c.Fatalf("spool not emptied")
+}
+
+func (s *BufferSuite) TestSpoolCompacted(c *gc.C) {
+	b, err := statusbuffer.New(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	workertest.CleanKill(c, b)
+	for i := 0; i < 7; i++ {
+		err = b.Add(status.StatusInfo{Status: status.Executing, Message: fmt.Sprint(i)})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	// The spool was rewritten to hold only the 3 kept statuses once
+	// it held 6, and has had one appended since.
+	c.Assert(s.spoolLines(c), gc.HasLen, 4)
+}
+
+func (s *BufferSuite) TestSpoolSkipsTornEntry(c *gc.C) {
+	b, err := statusbuffer.New(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	workertest.CleanKill(c, b)
+	err = b.Add(status.StatusInfo{Status: status.Executing, Message: "one"})
+	c.Assert(err, jc.ErrorIsNil)
+	f, err := os.OpenFile(s.spool, os.O_WRONLY|os.O_APPEND, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = f.WriteString(`{"status":"idle","mess`)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(f.Close(), jc.ErrorIsNil)
+
+	b, err = statusbuffer.New(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, b)
+	c.Assert(messages(s.nextBatch(c)), jc.DeepEquals, []string{"one"})
+}
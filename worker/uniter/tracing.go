@@ -0,0 +1,56 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/core/tracing"
+	"github.com/juju/juju/worker/uniter/hook"
+	"github.com/juju/juju/worker/uniter/runner"
+)
+
+// NewTracingRunnerFactory returns a runner.Factory that creates hook
+// runners which trace each hook they run for the unit. Command and
+// action runners are not traced.
+func NewTracingRunnerFactory(factory runner.Factory, tracer tracing.Tracer, unitTag names.UnitTag) runner.Factory {
+	return &tracingRunnerFactory{
+		Factory: factory,
+		tracer:  tracer,
+		entity:  unitTag.String(),
+	}
+}
+
+type tracingRunnerFactory struct {
+	runner.Factory
+	tracer tracing.Tracer
+	entity string
+}
+
+// NewHookRunner is part of the runner.Factory interface.
+func (f *tracingRunnerFactory) NewHookRunner(hookInfo hook.Info) (runner.Runner, error) {
+	r, err := f.Factory.NewHookRunner(hookInfo)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &tracingRunner{
+		Runner: r,
+		tracer: f.tracer,
+		entity: f.entity,
+	}, nil
+}
+
+type tracingRunner struct {
+	runner.Runner
+	tracer tracing.Tracer
+	entity string
+}
+
+// RunHook is part of the runner.Runner interface.
+func (r *tracingRunner) RunHook(name string) error {
+	return r.tracer.Trace(tracing.KindHook, r.entity, name, func() error {
+		return r.Runner.RunHook(name)
+	})
+}
@@ -0,0 +1,86 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6/hooks"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/core/tracing"
+	"github.com/juju/juju/worker/uniter"
+	"github.com/juju/juju/worker/uniter/hook"
+	"github.com/juju/juju/worker/uniter/runner"
+)
+
+type tracingSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&tracingSuite{})
+
+func (s *tracingSuite) TestHooksTraced(c *gc.C) {
+	clock := testing.NewClock(time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC))
+	start := clock.Now()
+	recorder := &fakeSpanRecorder{}
+	hookRunner := &fakeHookRunner{
+		run: func(name string) error {
+			clock.Advance(2 * time.Minute)
+			return errors.New("exit status 1")
+		},
+	}
+	factory := uniter.NewTracingRunnerFactory(
+		fakeRunnerFactory{runner: hookRunner},
+		tracing.Tracer{Recorder: recorder, Clock: clock},
+		names.NewUnitTag("mysql/0"),
+	)
+
+	r, err := factory.NewHookRunner(hook.Info{Kind: hooks.Install})
+	c.Assert(err, jc.ErrorIsNil)
+	err = r.RunHook("install")
+	c.Assert(err, gc.ErrorMatches, "exit status 1")
+	c.Assert(hookRunner.ran, jc.DeepEquals, []string{"install"})
+	c.Assert(recorder.spans, jc.DeepEquals, []tracing.Span{{
+		Kind:     tracing.KindHook,
+		Entity:   "unit-mysql-0",
+		Name:     "install",
+		Start:    start,
+		Duration: 2 * time.Minute,
+		Error:    "exit status 1",
+	}})
+}
+
+type fakeRunnerFactory struct {
+	runner.Factory
+	runner runner.Runner
+}
+
+func (f fakeRunnerFactory) NewHookRunner(hook.Info) (runner.Runner, error) {
+	return f.runner, nil
+}
+
+type fakeHookRunner struct {
+	runner.Runner
+	run func(name string) error
+	ran []string
+}
+
+func (r *fakeHookRunner) RunHook(name string) error {
+	r.ran = append(r.ran, name)
+	return r.run(name)
+}
+
+type fakeSpanRecorder struct {
+	spans []tracing.Span
+}
+
+func (r *fakeSpanRecorder) RecordSpans(spans []tracing.Span) error {
+	r.spans = append(r.spans, spans...)
+	return nil
+}
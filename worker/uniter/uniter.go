@@ -22,6 +22,7 @@ import (
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/leadership"
+	"github.com/juju/juju/core/tracing"
 	"github.com/juju/juju/status"
 	jworker "github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/catacomb"
@@ -38,11 +39,22 @@ import (
 	"github.com/juju/juju/worker/uniter/runner"
 	"github.com/juju/juju/worker/uniter/runner/context"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
+	"github.com/juju/juju/worker/uniter/statusbuffer"
 	"github.com/juju/juju/worker/uniter/storage"
 )
 
 var logger = loggo.GetLogger("juju.worker.uniter")
 
+const (
+	// maxSpooledStatuses is the number of agent statuses, and of
+	// workload statuses, kept while the controller cannot be reached.
+	maxSpooledStatuses = 1000
+
+	// statusRetryDelay is how long to wait before sending statuses
+	// again after they could not be sent.
+	statusRetryDelay = 10 * time.Second
+)
+
 // A UniterExecutionObserver gets the appropriate methods called when a hook
 // is executed and either succeeds or fails.  Missing hooks don't get reported
 // in this way.
@@ -70,6 +82,16 @@ type Uniter struct {
 	lastReportedStatus  status.Status
 	lastReportedMessage string
 
+	// agentStatusBuffer and unitStatusBuffer send agent and
+	// workload statuses to the controller without holding up
+	// the uniter.
+	agentStatusBuffer *statusbuffer.Buffer
+	unitStatusBuffer  *statusbuffer.Buffer
+
+	// hookFailureData holds the context captured when the last hook
+	// failed, to be recorded with the error status reported for it.
+	hookFailureData map[string]interface{}
+
 	operationFactory     operation.Factory
 	operationExecutor    operation.Executor
 	newOperationExecutor NewExecutorFunc
@@ -101,6 +123,9 @@ type Uniter struct {
 	// downloader is the downloader that should be used to get the charm
 	// archive.
 	downloader charm.Downloader
+
+	// spanRecorder, if not nil, records the execution of each hook.
+	spanRecorder tracing.Recorder
 }
 
 // UniterParams hold all the necessary parameters for a new Uniter.
@@ -117,6 +142,9 @@ type UniterParams struct {
 	NewOperationExecutor NewExecutorFunc
 	TranslateResolverErr func(error) error
 	Clock                clock.Clock
+	// SpanRecorder, if not nil, records the execution of each hook
+	// so that slow hooks can be reported.
+	SpanRecorder tracing.Recorder
 	// TODO (mattyw, wallyworld, fwereade) Having the observer here make this approach a bit more legitimate, but it isn't.
 	// the observer is only a stop gap to be used in tests. A better approach would be to have the uniter tests start hooks
 	// that write to files, and have the tests watch the output to know that hooks have finished.
@@ -147,6 +175,7 @@ func NewUniter(uniterParams *UniterParams) (*Uniter, error) {
 		observer:             uniterParams.Observer,
 		clock:                uniterParams.Clock,
 		downloader:           uniterParams.Downloader,
+		spanRecorder:         uniterParams.SpanRecorder,
 	}
 	err := catacomb.Invoke(catacomb.Plan{
 		Site: &u.catacomb,
@@ -408,6 +437,14 @@ func (u *Uniter) init(unitTag names.UnitTag) (err error) {
 		// and inescapable, whereas this one is not.
 		return jworker.ErrTerminateAgent
 	}
+	u.agentStatusBuffer, err = u.startStatusBuffer(u.sendAgentStatuses, u.paths.State.StatusSpoolFile)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	u.unitStatusBuffer, err = u.startStatusBuffer(u.sendUnitStatuses, u.paths.State.UnitStatusSpoolFile)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	// If initialising for the first time after deploying, update the status.
 	currentStatus, err := u.unit.UnitStatus()
 	if err != nil {
@@ -472,6 +509,7 @@ func (u *Uniter) init(unitTag names.UnitTag) (err error) {
 		Storage:          u.storage,
 		Paths:            u.paths,
 		Clock:            u.clock,
+		StatusBuffer:     u.unitStatusBuffer,
 	})
 	if err != nil {
 		return err
@@ -482,6 +520,10 @@ func (u *Uniter) init(unitTag names.UnitTag) (err error) {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	runnerFactory = NewTracingRunnerFactory(runnerFactory, tracing.Tracer{
+		Recorder: u.spanRecorder,
+		Clock:    u.clock,
+	}, unitTag)
 	u.operationFactory = operation.NewFactory(operation.FactoryParams{
 		Deployer:       deployer,
 		RunnerFactory:  runnerFactory,
@@ -583,6 +625,10 @@ func (u *Uniter) reportHookError(hookInfo hook.Info) error {
 		hookName = fmt.Sprintf("%s-%s", relationName, hookInfo.Kind)
 	}
 	statusData["hook"] = hookName
+	for k, v := range u.hookFailureData {
+		statusData[k] = v
+	}
+	u.hookFailureData = nil
 	statusMessage := fmt.Sprintf("hook failed: %q", hookName)
 	return setAgentStatus(u, status.Error, statusMessage, statusData)
 }
@@ -778,14 +778,24 @@ func (s waitUnitAgent) step(c *gc.C, ctx *context) {
 				continue
 			}
 			if s.data != nil {
-				if len(statusInfo.Data) != len(s.data) {
-					c.Logf("want %d status data value(s), got %d; still waiting", len(s.data), len(statusInfo.Data))
+				// The context captured when a hook fails depends on
+				// the hook, so is not compared.
+				data := make(map[string]interface{})
+				for key, value := range statusInfo.Data {
+					switch key {
+					case "hook-output", "hook-log", "workload-status", "workload-message":
+					default:
+						data[key] = value
+					}
+				}
+				if len(data) != len(s.data) {
+					c.Logf("want %d status data value(s), got %d; still waiting", len(s.data), len(data))
 					continue
 				}
 				for key, value := range s.data {
-					if statusInfo.Data[key] != value {
+					if data[key] != value {
 						c.Logf("want status data value %q for key %q, got %q; still waiting",
-							value, key, statusInfo.Data[key])
+							value, key, data[key])
 						continue
 					}
 				}
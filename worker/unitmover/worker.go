@@ -0,0 +1,122 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package unitmover
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/unitmover"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+var logger = loggo.GetLogger("juju.worker.unitmover")
+
+// PollInterval is how often unfinished unit moves are checked. The
+// phases of a move wait on machines, agents and relations, none of
+// which the unit moves watcher reports, so they must be polled.
+const PollInterval = 10 * time.Second
+
+// Facade represents the API used by the worker to drive unit moves.
+type Facade interface {
+	WatchUnitMoves() (watcher.NotifyWatcher, error)
+	AdvanceUnitMoves() (int, error)
+}
+
+// NewFacade returns a Facade backed by the UnitMover API.
+func NewFacade(caller base.APICaller) Facade {
+	return unitmover.NewFacade(caller)
+}
+
+// Config holds the resources and configuration needed to run the worker.
+type Config struct {
+	Facade Facade
+	Clock  clock.Clock
+}
+
+// Validate returns an error if the config cannot be used to start
+// a worker.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	return nil
+}
+
+// New returns a worker that moves unit moves on through their phases,
+// from provisioning the target machine to retiring the original unit,
+// and removes evacuated machines once their units have gone.
+func New(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &moverWorker{config: config}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type moverWorker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *moverWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *moverWorker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *moverWorker) loop() error {
+	movesWatcher, err := w.config.Facade.WatchUnitMoves()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := w.catacomb.Add(movesWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
+	var timerCh <-chan time.Time
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+
+		case _, ok := <-movesWatcher.Changes():
+			if !ok {
+				return errors.New("unit moves watcher closed")
+			}
+		case <-timerCh:
+		}
+
+		active, err := w.config.Facade.AdvanceUnitMoves()
+		if err != nil {
+			return errors.Annotate(err, "cannot advance unit moves")
+		}
+		if active == 0 {
+			timerCh = nil
+			continue
+		}
+		logger.Debugf("%d unit move(s) in progress", active)
+		timerCh = w.config.Clock.After(PollInterval)
+	}
+}
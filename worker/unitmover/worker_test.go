@@ -0,0 +1,171 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package unitmover_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/tomb.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/unitmover"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type WorkerSuite struct {
+	coretesting.BaseSuite
+	facade *fakeFacade
+	clock  *testing.Clock
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.facade = &fakeFacade{
+		changes:  make(chan struct{}, 1),
+		advanced: make(chan struct{}, 10),
+	}
+	s.clock = testing.NewClock(time.Date(2018, 5, 1, 12, 0, 0, 0, time.UTC))
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) worker.Worker {
+	w, err := unitmover.New(unitmover.Config{
+		Facade: s.facade,
+		Clock:  s.clock,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) {
+		workertest.DirtyKill(c, w)
+	})
+	s.facade.changes <- struct{}{}
+	return w
+}
+
+func (s *WorkerSuite) assertAdvanced(c *gc.C) {
+	select {
+	case <-s.facade.advanced:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for unit moves to be advanced")
+	}
+}
+
+func (s *WorkerSuite) assertNotAdvanced(c *gc.C) {
+	select {
+	case <-s.facade.advanced:
+		c.Fatal("unexpected advance")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *WorkerSuite) waitTimer(c *gc.C) {
+	select {
+	case <-s.clock.Alarms():
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for poll timer")
+	}
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	_, err := unitmover.New(unitmover.Config{Clock: s.clock})
+	c.Assert(err, gc.ErrorMatches, "nil Facade not valid")
+	_, err = unitmover.New(unitmover.Config{Facade: s.facade})
+	c.Assert(err, gc.ErrorMatches, "nil Clock not valid")
+}
+
+func (s *WorkerSuite) TestPollsWhileMovesActive(c *gc.C) {
+	s.facade.setActive(2, 1, 0)
+	s.startWorker(c)
+	s.assertAdvanced(c)
+
+	s.waitTimer(c)
+	s.clock.Advance(unitmover.PollInterval - time.Nanosecond)
+	s.assertNotAdvanced(c)
+	s.clock.Advance(time.Nanosecond)
+	s.assertAdvanced(c)
+
+	s.waitTimer(c)
+	s.clock.Advance(unitmover.PollInterval)
+	s.assertAdvanced(c)
+
+	// No moves are active, so the worker waits for a new one.
+	s.clock.Advance(time.Hour)
+	s.assertNotAdvanced(c)
+	s.facade.changes <- struct{}{}
+	s.assertAdvanced(c)
+}
+
+func (s *WorkerSuite) TestAdvanceError(c *gc.C) {
+	s.facade.err = errors.New("boom")
+	w := s.startWorker(c)
+	err := workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "cannot advance unit moves: boom")
+}
+
+type fakeFacade struct {
+	mu       sync.Mutex
+	changes  chan struct{}
+	advanced chan struct{}
+	active   []int
+	err      error
+}
+
+func (f *fakeFacade) setActive(active ...int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.active = active
+}
+
+// WatchUnitMoves is part of the unitmover.Facade interface.
+func (f *fakeFacade) WatchUnitMoves() (watcher.NotifyWatcher, error) {
+	return newMockNotifyWatcher(f.changes), nil
+}
+
+// AdvanceUnitMoves is part of the unitmover.Facade interface.
+func (f *fakeFacade) AdvanceUnitMoves() (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.advanced <- struct{}{}
+	if f.err != nil {
+		return 0, f.err
+	}
+	active := 0
+	if len(f.active) > 0 {
+		active, f.active = f.active[0], f.active[1:]
+	}
+	return active, nil
+}
+
+type mockNotifyWatcher struct {
+	tomb tomb.Tomb
+	ch   chan struct{}
+}
+
+func newMockNotifyWatcher(ch chan struct{}) *mockNotifyWatcher {
+	w := &mockNotifyWatcher{ch: ch}
+	go func() {
+		defer w.tomb.Done()
+		<-w.tomb.Dying()
+	}()
+	return w
+}
+
+func (w *mockNotifyWatcher) Changes() watcher.NotifyChannel {
+	return w.ch
+}
+
+func (w *mockNotifyWatcher) Kill() {
+	w.tomb.Kill(nil)
+}
+
+func (w *mockNotifyWatcher) Wait() error {
+	return w.tomb.Wait()
+}
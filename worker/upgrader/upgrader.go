@@ -4,7 +4,10 @@
 package upgrader
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"time"
 
@@ -19,6 +22,7 @@ import (
 	"github.com/juju/juju/agent"
 	agenttools "github.com/juju/juju/agent/tools"
 	"github.com/juju/juju/api/upgrader"
+	envtools "github.com/juju/juju/environs/tools"
 	coretools "github.com/juju/juju/tools"
 	jujuversion "github.com/juju/juju/version"
 	"github.com/juju/juju/worker/catacomb"
@@ -32,6 +36,14 @@ var retryAfter = func() <-chan time.Time {
 	return time.After(5 * time.Second)
 }
 
+// recheckAfter returns a channel that receives a value when the
+// desired version should be checked again without waiting for a
+// change to the model; upgrades held back by the controller while the
+// model's maintenance windows are closed are noticed this way.
+var recheckAfter = func() <-chan time.Time {
+	return time.After(15 * time.Minute)
+}
+
 var logger = loggo.GetLogger("juju.worker.upgrader")
 
 // Upgrader represents a worker that watches the state for upgrade
@@ -151,12 +163,13 @@ func (u *Upgrader) loop() error {
 		}
 	}
 
-	var retry <-chan time.Time
+	var retry, recheck <-chan time.Time
 	for {
 		select {
-		// NOTE: retry and dying both start out nil, so they can't be chosen
-		// first time round the loop. However...
+		// NOTE: retry, recheck and dying all start out nil, so they can't
+		// be chosen first time round the loop. However...
 		case <-retry:
+		case <-recheck:
 		case <-dying:
 			return u.catacomb.ErrDying()
 		// ...*every* other case *must* allowDying(), before doing anything
@@ -179,6 +192,7 @@ func (u *Upgrader) loop() error {
 
 		if wantVersion == jujuversion.Current {
 			u.initialUpgradeCheckComplete.Unlock()
+			recheck = recheckAfter()
 			continue
 		} else if !allowedTargetVersion(
 			u.origAgentVersion,
@@ -210,13 +224,17 @@ func (u *Upgrader) loop() error {
 			// Not being able to lookup Tools is considered fatal
 			return err
 		}
+		trustedKeys, err := u.st.AgentBinaryTrustedKeys()
+		if err != nil {
+			return errors.Annotate(err, "cannot get agent binary trusted keys")
+		}
 		// The worker cannot be stopped while we're downloading
 		// the tools - this means that even if the API is going down
 		// repeatedly (causing the agent to be stopped), as long
 		// as we have got as far as this, we will still be able to
 		// upgrade the agent.
 		for _, wantTools := range wantToolsList {
-			err = u.ensureTools(wantTools)
+			err = u.ensureTools(wantTools, trustedKeys)
 			if err == nil {
 				return u.newUpgradeReadyError(wantTools.Version)
 			}
@@ -249,7 +267,7 @@ func (u *Upgrader) newUpgradeReadyError(newVersion version.Binary) *UpgradeReady
 	}
 }
 
-func (u *Upgrader) ensureTools(agentTools *coretools.Tools) error {
+func (u *Upgrader) ensureTools(agentTools *coretools.Tools, trustedKeys string) error {
 	logger.Infof("fetching agent binaries from %q", agentTools.URL)
 	// The reader MUST verify the tools' hash, so there is no
 	// need to validate the peer. We cannot anyway: see http://pad.lv/1261780.
@@ -261,7 +279,22 @@ func (u *Upgrader) ensureTools(agentTools *coretools.Tools) error {
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("bad HTTP response: %v", resp.Status)
 	}
-	err = agenttools.UnpackTools(u.dataDir, agentTools, resp.Body)
+	var r io.Reader = resp.Body
+	if trustedKeys != "" {
+		// The controller may hold binaries that were uploaded
+		// rather than fetched from a verified stream, so the
+		// signature is checked here too before anything is
+		// unpacked.
+		data, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return errors.Annotate(err, "cannot read agent binaries")
+		}
+		if err := envtools.VerifySignature(bytes.NewReader(data), agentTools, trustedKeys); err != nil {
+			return errors.Trace(err)
+		}
+		r = bytes.NewReader(data)
+	}
+	err = agenttools.UnpackTools(u.dataDir, agentTools, r)
 	if err != nil {
 		return fmt.Errorf("cannot unpack agent binaries: %v", err)
 	}
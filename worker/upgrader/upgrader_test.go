@@ -4,7 +4,9 @@
 package upgrader_test
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	stdtesting "testing"
@@ -23,10 +25,15 @@ import (
 	"github.com/juju/juju/agent"
 	agenttools "github.com/juju/juju/agent/tools"
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/environs/simplestreams"
+	sstesting "github.com/juju/juju/environs/simplestreams/testing"
+	"github.com/juju/juju/environs/storage"
 	envtesting "github.com/juju/juju/environs/testing"
 	envtools "github.com/juju/juju/environs/tools"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/binarystorage"
 	statetesting "github.com/juju/juju/state/testing"
 	coretesting "github.com/juju/juju/testing"
 	coretools "github.com/juju/juju/tools"
@@ -386,6 +393,96 @@ func (s *UpgraderSuite) TestUpgraderRefusesDowngradeToOrigVersionIfUpgradeNotInP
 	c.Check(err, jc.ErrorIsNil)
 }
 
+type UpgraderTrustedKeysSuite struct {
+	UpgraderSuite
+}
+
+var _ = gc.Suite(&UpgraderTrustedKeysSuite{})
+
+func (s *UpgraderTrustedKeysSuite) SetUpTest(c *gc.C) {
+	s.ControllerConfigAttrs = map[string]interface{}{
+		controller.AgentBinaryTrustedKeys: sstesting.SignedMetadataPublicKey,
+	}
+	s.UpgraderSuite.SetUpTest(c)
+}
+
+// addToolsToState copies the tarball of the given tools into the
+// controller's binary storage, as an upload would, so that the API
+// server serves it without fetching and verifying it first.
+func (s *UpgraderTrustedKeysSuite) addToolsToState(c *gc.C, tools *coretools.Tools, sign bool) {
+	r, err := storage.Get(s.DefaultToolsStorage, envtools.StorageName(tools.Version, s.Environ.Config().AgentStream()))
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+
+	metadata := binarystorage.Metadata{
+		Version: tools.Version.String(),
+		Size:    tools.Size,
+		SHA256:  tools.SHA256,
+	}
+	if sign {
+		metadata.Signature, err = simplestreams.SignDetached(
+			bytes.NewReader(data), sstesting.SignedMetadataPrivateKey, sstesting.PrivateKeyPassphrase,
+		)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	stor, err := s.State.ToolsStorage()
+	c.Assert(err, jc.ErrorIsNil)
+	defer stor.Close()
+	err = stor.Add(bytes.NewReader(data), metadata)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UpgraderTrustedKeysSuite) TestUpgraderVerifiesSignature(c *gc.C) {
+	stor := s.DefaultToolsStorage
+	oldTools := envtesting.PrimeTools(c, stor, s.DataDir(), s.Environ.Config().AgentStream(), version.MustParseBinary("5.4.3-precise-amd64"))
+	s.patchVersion(oldTools.Version)
+	newTools := envtesting.AssertUploadFakeToolsVersions(
+		c, stor, s.Environ.Config().AgentStream(), s.Environ.Config().AgentStream(), version.MustParseBinary("5.4.5-precise-amd64"))[0]
+	s.addToolsToState(c, newTools, true)
+	err := statetesting.SetAgentVersion(s.State, newTools.Version.Number)
+	c.Assert(err, jc.ErrorIsNil)
+
+	u := s.makeUpgrader(c)
+	err = u.Stop()
+	envtesting.CheckUpgraderReadyError(c, err, &upgrader.UpgradeReadyError{
+		AgentName: s.machine.Tag().String(),
+		OldTools:  oldTools.Version,
+		NewTools:  newTools.Version,
+		DataDir:   s.DataDir(),
+	})
+	_, err = agenttools.ReadTools(s.DataDir(), newTools.Version)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UpgraderTrustedKeysSuite) TestUpgraderRejectsUnsignedTools(c *gc.C) {
+	stor := s.DefaultToolsStorage
+	oldTools := envtesting.PrimeTools(c, stor, s.DataDir(), s.Environ.Config().AgentStream(), version.MustParseBinary("5.4.3-precise-amd64"))
+	s.patchVersion(oldTools.Version)
+	newTools := envtesting.AssertUploadFakeToolsVersions(
+		c, stor, s.Environ.Config().AgentStream(), s.Environ.Config().AgentStream(), version.MustParseBinary("5.4.5-precise-amd64"))[0]
+	s.addToolsToState(c, newTools, false)
+	err := statetesting.SetAgentVersion(s.State, newTools.Version.Number)
+	c.Assert(err, jc.ErrorIsNil)
+
+	retryc := make(chan time.Time)
+	*upgrader.RetryAfter = func() <-chan time.Time {
+		return retryc
+	}
+	u := s.makeUpgrader(c)
+	defer u.Stop()
+
+	// The upgrader refuses the unsigned binaries and waits to retry.
+	select {
+	case retryc <- time.Now():
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("upgrader did not retry")
+	}
+	_, err = agenttools.ReadTools(s.DataDir(), newTools.Version)
+	c.Assert(err, gc.NotNil)
+}
+
 type allowedTest struct {
 	original       string
 	current        string